@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/mfa"
+	"github.com/dfodeker/terminus/internal/platform"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/google/uuid"
+)
+
+// The access checks below are shared by the REST middleware and the
+// GraphQL Caller, so both APIs grant exactly the same things. A check that
+// refuses returns a *service.Error; one that couldn't be made returns an
+// *accessFailure.
+
+// errUnauthenticated refuses a request with no signed-in caller
+var errUnauthenticated = &service.Error{Kind: service.ErrForbidden, Message: "Authentication required", Code: problem.CodeUnauthorized}
+
+// accessFailure is an access check that couldn't be made, such as a failed
+// lookup. message is what the client is told.
+type accessFailure struct {
+	message string
+	err     error
+}
+
+func (e *accessFailure) Error() string { return e.message + ": " + e.err.Error() }
+
+func (e *accessFailure) Unwrap() error { return e.err }
+
+// respondWithAccessError writes the response for a check that refused or
+// failed
+func respondWithAccessError(w http.ResponseWriter, err error) {
+	var failure *accessFailure
+	if errors.As(err, &failure) {
+		respondWithError(w, http.StatusInternalServerError, failure.message, failure.err)
+		return
+	}
+	var svcErr *service.Error
+	if errors.As(err, &svcErr) && svcErr.Code == problem.CodeUnauthorized {
+		respondWithErrorCode(w, http.StatusUnauthorized, svcErr.Code, svcErr.Message, nil)
+		return
+	}
+	respondWithServiceError(w, err, "Unable to verify access")
+}
+
+// checkUserToken refuses API keys and impersonation tokens, for endpoints
+// that act on the signed-in user's own account
+func checkUserToken(ctx context.Context) error {
+	if _, ok := apiKeyFromContext(ctx); ok {
+		return &service.Error{Kind: service.ErrForbidden, Message: "API keys can't be used for this endpoint"}
+	}
+	if _, ok := impersonatedTenantFromContext(ctx); ok {
+		return &service.Error{Kind: service.ErrForbidden, Message: "Impersonation tokens can't be used for this endpoint"}
+	}
+	return nil
+}
+
+// checkVerifiedEmail refuses a user who hasn't confirmed their email
+// address yet
+func (cfg *apiConfig) checkVerifiedEmail(ctx context.Context, userID uuid.UUID) error {
+	user, err := cfg.db.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errUnauthenticated
+		}
+		return &accessFailure{message: "Unable to retrieve user", err: err}
+	}
+	if !user.VerifiedAt.Valid {
+		return &service.Error{Kind: service.ErrForbidden, Message: "Please verify your email address first", Code: problem.CodeEmailNotVerified}
+	}
+	return nil
+}
+
+// checkAPIKeyTenant refuses an API key on any tenant but its own. Some
+// tenant routes check membership rather than permissions, and the key's
+// creator may belong to other tenants.
+func checkAPIKeyTenant(ctx context.Context, tenantID uuid.UUID) error {
+	if key, ok := apiKeyFromContext(ctx); ok && key.TenantID != tenantID {
+		return &service.Error{Kind: service.ErrForbidden, Message: "This API key belongs to a different tenant"}
+	}
+	return nil
+}
+
+// checkClaimedTenant refuses a tenant missing from the token's tenant
+// claims before any membership or permission lookups run. Claims only
+// count while their permissions version is current; a stale token passes
+// to the regular checks until it's refreshed.
+func (cfg *apiConfig) checkClaimedTenant(ctx context.Context, tenantID uuid.UUID) error {
+	claims, ok := tenantClaimsFromContext(ctx)
+	if !ok || claims.HasTenant(tenantID) {
+		return nil
+	}
+
+	userID, _ := userFromContext(ctx)
+	version, err := cfg.db.GetUserPermissionsVersion(ctx, userID)
+	if err != nil {
+		slog.WarnContext(ctx, "unable to check permissions version", "user_id", userID, "error", err)
+		return nil
+	}
+	if version != claims.PermissionsVersion {
+		return nil
+	}
+	return &service.Error{Kind: service.ErrForbidden, Message: "You are not a member of this tenant", Code: problem.CodeNotTenantMember}
+}
+
+// tenantMember checks the caller is an active member of tenantID and
+// returns their TenantContext. API keys pass as their creator, so revoking
+// the creator's membership also stops their keys. Suspended tenants are
+// closed to their members; only impersonating staff get in.
+func (cfg *apiConfig) tenantMember(ctx context.Context, tenantID uuid.UUID) (TenantContext, error) {
+	reqID := middleware.GetRequestID(ctx)
+
+	user, ok := userFromContext(ctx)
+	if !ok {
+		return TenantContext{}, errUnauthenticated
+	}
+	if impersonated, ok := impersonatedTenantFromContext(ctx); ok {
+		return cfg.impersonatedMember(ctx, user, tenantID, impersonated)
+	}
+
+	membership, err := cfg.db.GetTenantUser(ctx, database.GetTenantUserParams{
+		TenantID: tenantID,
+		UserID:   user,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.WarnContext(ctx, "tenant request denied: user not a member of tenant",
+				"request_id", reqID,
+				"user_id", user,
+				"tenant_id", tenantID,
+			)
+			return TenantContext{}, &service.Error{Kind: service.ErrForbidden, Message: "You are not a member of this tenant", Code: problem.CodeNotTenantMember}
+		}
+		return TenantContext{}, &accessFailure{message: "Unable to verify tenant membership", err: err}
+	}
+
+	if membership.Status != "active" {
+		slog.WarnContext(ctx, "tenant request denied: user membership not active",
+			"request_id", reqID,
+			"user_id", user,
+			"tenant_id", tenantID,
+			"membership_status", membership.Status,
+		)
+		return TenantContext{}, &service.Error{Kind: service.ErrForbidden, Message: "Your membership in this tenant is not active", Code: problem.CodeMembershipInactive}
+	}
+
+	tenant, err := cfg.db.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return TenantContext{}, &accessFailure{message: "Unable to retrieve tenant", err: err}
+	}
+	if tenant.Status == platform.TenantSuspended {
+		return TenantContext{}, &service.Error{Kind: service.ErrForbidden, Message: "This tenant is suspended, please contact support", Code: problem.CodeTenantSuspended}
+	}
+
+	roles, err := cfg.db.GetUserRolesInTenant(ctx, database.GetUserRolesInTenantParams{
+		TenantID: tenantID,
+		UserID:   user,
+	})
+	if err != nil {
+		return TenantContext{}, &accessFailure{message: "Unable to retrieve roles", err: err}
+	}
+
+	return TenantContext{Tenant: tenant, Membership: membership, Roles: roles}, nil
+}
+
+// impersonatedMember lets platform staff acting in a tenant through, as a
+// member with no roles, once it is the tenant their token names and they
+// are still allowed to impersonate. Suspended tenants are open to them.
+func (cfg *apiConfig) impersonatedMember(ctx context.Context, user, tenantID, impersonated uuid.UUID) (TenantContext, error) {
+	if tenantID != impersonated {
+		return TenantContext{}, &service.Error{Kind: service.ErrForbidden, Message: "This impersonation token is for a different tenant", Code: problem.CodeNotTenantMember}
+	}
+
+	staff, err := cfg.db.GetUserPlatformRole(ctx, user)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return TenantContext{}, &accessFailure{message: "Unable to verify platform role", err: err}
+	}
+	if !platform.Role(staff.PlatformRole.String).Allows(platform.RoleAdmin) {
+		return TenantContext{}, service.Forbidden("Your platform role no longer allows impersonation")
+	}
+
+	tenant, err := cfg.db.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TenantContext{}, service.NotFound("Tenant not found")
+		}
+		return TenantContext{}, &accessFailure{message: "Unable to retrieve tenant", err: err}
+	}
+
+	return TenantContext{
+		Tenant:     tenant,
+		Membership: database.TenantUser{TenantID: tenantID, UserID: user, Status: "active"},
+	}, nil
+}
+
+// checkTenantPermission refuses a member of tc who doesn't hold permission
+// there, or who needs MFA for it under the tenant's policy
+func (cfg *apiConfig) checkTenantPermission(ctx context.Context, tc TenantContext, permission string) error {
+	user, tenantID := tc.Membership.UserID, tc.Tenant.ID
+
+	hasPermission, err := cfg.checkPermission(ctx, database.CheckUserHasPermissionParams{
+		TenantID: tenantID,
+		UserID:   user,
+		Key:      permission,
+	})
+	if err != nil {
+		return &accessFailure{message: "Unable to verify permissions", err: err}
+	}
+	if !hasPermission {
+		slog.WarnContext(ctx, "tenant request denied: insufficient permissions",
+			"request_id", middleware.GetRequestID(ctx),
+			"user_id", user,
+			"tenant_id", tenantID,
+			"permission", permission,
+		)
+		return service.Forbidden("You do not have permission to perform this action")
+	}
+
+	return cfg.checkMFAPolicy(ctx, tenantID, user, permission)
+}
+
+// checkMFAPolicy refuses a sensitive permission to a user who hasn't
+// turned MFA on when the tenant requires it
+func (cfg *apiConfig) checkMFAPolicy(ctx context.Context, tenantID, userID uuid.UUID, permission string) error {
+	// API keys are limited by their scopes instead
+	if _, ok := apiKeyFromContext(ctx); ok || !mfa.IsSensitivePermission(permission) {
+		return nil
+	}
+
+	status, err := cfg.db.GetTenantMFAPolicyStatus(ctx, database.GetTenantMFAPolicyStatusParams{
+		UserID:   userID,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return service.NotFound("Tenant not found")
+		}
+		return &accessFailure{message: "Unable to verify permissions", err: err}
+	}
+
+	if status.RequireMfa && !status.MfaEnabled {
+		return &service.Error{Kind: service.ErrForbidden, Message: "This tenant requires two-factor authentication, please enable it on your account", Code: problem.CodeMFARequired}
+	}
+	return nil
+}
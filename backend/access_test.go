@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// TestAccessChecksAgree refuses the same callers through the REST
+// middleware and the GraphQL Caller, which must tell them the same thing
+func TestAccessChecksAgree(t *testing.T) {
+	cfg := newRouteTestConfig(t)
+	tenantID := uuid.New()
+	user := uuid.New()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want problem.Problem
+	}{
+		{
+			name: "signed out",
+			ctx:  context.Background(),
+			want: problem.Problem{Status: http.StatusUnauthorized, Code: problem.CodeUnauthorized, Detail: "Authentication required"},
+		},
+		{
+			name: "api key of another tenant",
+			ctx: context.WithValue(context.WithValue(context.Background(), userKey, user),
+				apiKeyKey, database.ApiKey{TenantID: uuid.New()}),
+			want: problem.Problem{Status: http.StatusForbidden, Code: problem.CodeFor(http.StatusForbidden), Detail: "This API key belongs to a different tenant"},
+		},
+		{
+			name: "membership lookup failed",
+			ctx:  context.WithValue(context.Background(), userKey, user),
+			want: problem.Problem{Status: http.StatusInternalServerError, Code: problem.CodeFor(http.StatusInternalServerError), Detail: "Unable to verify tenant membership"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("tenantID", tenantID.String())
+			req := httptest.NewRequest(http.MethodGet, "/tenants/"+tenantID.String(), nil)
+			req = req.WithContext(context.WithValue(tt.ctx, chi.RouteCtxKey, rctx))
+			rec := httptest.NewRecorder()
+			chi.Chain(cfg.requireAPIKeyTenant, cfg.requireClaimedTenant, cfg.requireTenantMember).
+				HandlerFunc(func(http.ResponseWriter, *http.Request) { t.Error("request was let through") }).
+				ServeHTTP(rec, req)
+
+			var got problem.Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != tt.want.Status || got.Code != tt.want.Code || got.Detail != tt.want.Detail {
+				t.Errorf("REST refused with %d %q %q, want %d %q %q", got.Status, got.Code, got.Detail, tt.want.Status, tt.want.Code, tt.want.Detail)
+			}
+
+			caller := &graphCaller{cfg: cfg, r: req}
+			_, err := caller.Authorize(tt.ctx, tenantID, "")
+			var svcErr *service.Error
+			switch {
+			case tt.want.Status == http.StatusInternalServerError:
+				if !errors.Is(err, errNoDatabase) {
+					t.Errorf("GraphQL failed with %v, want the database error", err)
+				}
+			case !errors.As(err, &svcErr):
+				t.Errorf("GraphQL refused with %v, want a service error", err)
+			case svcErr.Message != tt.want.Detail:
+				t.Errorf("GraphQL refused with %q, want %q", svcErr.Message, tt.want.Detail)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/dfodeker/terminus/internal/audit"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
+)
+
+// auditEntry is what a handler tells the audit log about its change
+type auditEntry struct {
+	entity gid.GID
+	before any
+	after  any
+	set    bool
+}
+
+type auditEntryKey struct{}
+
+// auditChange gives the audit log the entity a request changed and its
+// state before and after, in the same shape so the two can be diffed.
+// Either state may be nil, as for a create or a delete. Handlers that
+// don't call it are still logged, with their response as the new state.
+func auditChange(r *http.Request, entity gid.GID, before, after any) {
+	entry, ok := r.Context().Value(auditEntryKey{}).(*auditEntry)
+	if !ok {
+		return
+	}
+	entry.entity, entry.before, entry.after, entry.set = entity, before, after, true
+}
+
+// auditGID names an entity by its GID, if it has been given one
+func auditGID(entityType gid.EntityType, id sql.NullInt64) gid.GID {
+	if !id.Valid {
+		return gid.GID{}
+	}
+	return gid.New(entityType, uint64(id.Int64))
+}
+
+// auditRecorder keeps the status and, up to a limit, the body of a
+// response
+type auditRecorder struct {
+	http.ResponseWriter
+	status    int
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (r *auditRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *auditRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	if !r.truncated {
+		if r.body.Len()+len(b) > audit.MaxStateBytes {
+			r.truncated = true
+			r.body.Reset()
+		} else {
+			r.body.Write(b)
+		}
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// auditLog records every successful create, update and delete under a
+// tenant: who made it, from where, to which entity, and what changed. It
+// runs behind requireTenantMember so the actor is known, and inside the
+// idempotency middleware so a replayed response isn't logged twice.
+// Failing to write an entry is logged but doesn't fail the request, which
+// has already been answered.
+func (cfg *apiConfig) auditLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !audit.IsMutation(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		entry := &auditEntry{}
+		rec := &auditRecorder{ResponseWriter: w}
+		ctx := context.WithValue(r.Context(), auditEntryKey{}, entry)
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if status < 200 || status >= 300 {
+			return
+		}
+
+		// The entry must be written even if the client has hung up
+		storeCtx := context.WithoutCancel(r.Context())
+		arg := cfg.auditLogEntry(r, entry, rec, status)
+		if err := cfg.db.CreateAuditLogEntry(storeCtx, arg); err != nil {
+			slog.ErrorContext(storeCtx, "audit log write failed",
+				"request_id", middleware.GetRequestID(r.Context()),
+				"tenant_id", arg.TenantID,
+				"route", arg.Route,
+				"error", err,
+			)
+		}
+	})
+}
+
+func (cfg *apiConfig) auditLogEntry(r *http.Request, entry *auditEntry, rec *auditRecorder, status int) database.CreateAuditLogEntryParams {
+	tc := tenantContextFrom(r)
+
+	var before, after json.RawMessage
+	if entry.set {
+		before, after = auditState(entry.before), auditState(entry.after)
+	} else if r.Method != http.MethodDelete && !rec.truncated {
+		// A delete's response only confirms it
+		after = audit.Redact(rec.body.Bytes())
+	}
+
+	entityID := audit.EntityID(after)
+	if entityID == uuid.Nil {
+		entityID = audit.EntityID(before)
+	}
+
+	// Every URL parameter has been routed by now
+	rctx := chi.RouteContext(r.Context())
+	route := strings.TrimSuffix(rctx.RoutePattern(), "/")
+	var storeID uuid.NullUUID
+	if id, err := uuid.Parse(rctx.URLParam("storeID")); err == nil {
+		storeID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	reqID, ip := middleware.GetRequestID(r.Context()), middleware.ClientIP(r)
+	arg := database.CreateAuditLogEntryParams{
+		TenantID:   tc.Tenant.ID,
+		StoreID:    storeID,
+		Action:     audit.ActionFor(r.Method, before != nil),
+		EntityID:   uuid.NullUUID{UUID: entityID, Valid: entityID != uuid.Nil},
+		Method:     r.Method,
+		Route:      route,
+		Path:       r.URL.Path,
+		StatusCode: int32(status),
+		RequestID:  sql.NullString{String: reqID, Valid: reqID != ""},
+		Ip:         sql.NullString{String: ip, Valid: ip != ""},
+		Before:     pqtype.NullRawMessage{RawMessage: before, Valid: before != nil},
+		After:      pqtype.NullRawMessage{RawMessage: after, Valid: after != nil},
+	}
+	if key, ok := apiKeyFromContext(r.Context()); ok {
+		arg.ActorApiKeyID = uuid.NullUUID{UUID: key.ID, Valid: true}
+	}
+	if tc.Membership.UserID != uuid.Nil {
+		arg.ActorUserID = uuid.NullUUID{UUID: tc.Membership.UserID, Valid: true}
+	}
+	if !entry.entity.IsZero() {
+		arg.EntityType = sql.NullString{String: string(entry.entity.Type), Valid: true}
+		arg.EntityGid = sql.NullString{String: entry.entity.String(), Valid: true}
+	}
+	if changes := audit.Diff(before, after); changes != nil {
+		if data, err := json.Marshal(changes); err == nil {
+			arg.Changes = pqtype.NullRawMessage{RawMessage: data, Valid: true}
+		}
+	}
+	return arg
+}
+
+// auditState encodes a state a handler gave, redacted and within the size
+// limit
+func auditState(v any) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil || len(data) > audit.MaxStateBytes {
+		return nil
+	}
+	return audit.Redact(data)
+}
@@ -0,0 +1,319 @@
+// Package client calls the Terminus API. Its methods are generated from
+// openapi.json by cmd/sdkgen, one per operation, and take and return the
+// documented types. Lists that page with next_cursor also get an All
+// method that follows the cursor.
+//
+// Requests the server can safely see twice are retried: reads, and writes
+// that take an Idempotency-Key. The client sends a key with those writes
+// itself, the same one on every attempt; WithIdempotencyKey picks it.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"math"
+	mathrand "math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client calls the API at one base URL
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+	retry   RetryPolicy
+}
+
+// RetryPolicy says how often and how long to retry. The wait doubles from
+// MinBackoff up to MaxBackoff, with jitter, unless the server asks for
+// longer with Retry-After.
+type RetryPolicy struct {
+	// MaxAttempts counts the first try; 1 never retries
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+}
+
+// DefaultRetryPolicy is the policy of a new client
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 4, MinBackoff: 250 * time.Millisecond, MaxBackoff: 10 * time.Second}
+
+// Option configures a client
+type Option func(*Client)
+
+// WithToken authenticates requests with an access token or API key
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithHTTPClient sends requests through hc instead of http.DefaultClient
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.http = hc }
+}
+
+// WithRetry replaces the retry policy
+func WithRetry(p RetryPolicy) Option {
+	return func(c *Client) { c.retry = p }
+}
+
+// New returns a client of the API at baseURL, such as
+// https://api.example.com
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    http.DefaultClient,
+		retry:   DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+type idempotencyKeyContext struct{}
+
+// WithIdempotencyKey sends key as the Idempotency-Key of the write made
+// with ctx, in place of a random one. Reusing the key of an earlier call,
+// say after a crash, gets that call's response instead of repeating it.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContext{}, key)
+}
+
+// Error is an API error, from the RFC 7807 problem details in its body
+type Error struct {
+	StatusCode int          `json:"-"`
+	Type       string       `json:"type"`
+	Title      string       `json:"title"`
+	Code       string       `json:"code"`
+	Detail     string       `json:"detail"`
+	RequestID  string       `json:"request_id"`
+	Errors     []FieldError `json:"errors"`
+}
+
+// FieldError is one rejected field of a request body
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("terminus: %d %s", e.StatusCode, e.Code)
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	return msg
+}
+
+// Body is a request body sent as it is instead of encoded as JSON, such
+// as the CSV of a product import. Pass it as the body of an operation.
+type Body struct {
+	ContentType string
+	Data        []byte
+}
+
+// request is one call, as the generated methods describe it
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   any
+	// idempotent is set for writes that take an Idempotency-Key
+	idempotent bool
+}
+
+// do sends req, retrying as the policy allows, and decodes a successful
+// response into out unless it is nil
+func (c *Client) do(ctx context.Context, req request, out any) error {
+	var body []byte
+	contentType := "application/json"
+	switch b := req.body.(type) {
+	case nil:
+	case Body:
+		body, contentType = b.Data, b.ContentType
+	default:
+		var err error
+		body, err = json.Marshal(req.body)
+		if err != nil {
+			return err
+		}
+	}
+
+	u := c.baseURL + req.path
+	if len(req.query) > 0 {
+		u += "?" + req.query.Encode()
+	}
+
+	var key string
+	if req.idempotent {
+		key, _ = ctx.Value(idempotencyKeyContext{}).(string)
+		if key == "" {
+			key = newIdempotencyKey()
+		}
+	}
+	retryable := req.idempotent || req.method == http.MethodGet || req.method == http.MethodHead ||
+		req.method == http.MethodPut || req.method == http.MethodDelete
+
+	attempts := max(c.retry.MaxAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		httpReq, err := http.NewRequestWithContext(ctx, req.method, u, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		httpReq.Header.Set("Accept", "application/json")
+		if body != nil {
+			httpReq.Header.Set("Content-Type", contentType)
+		}
+		if c.token != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+c.token)
+		}
+		if key != "" {
+			httpReq.Header.Set("Idempotency-Key", key)
+		}
+
+		resp, err := c.http.Do(httpReq)
+		if err != nil {
+			// The request may have reached the server, so only what is safe
+			// to repeat is
+			if !retryable || attempt >= attempts || ctx.Err() != nil {
+				return err
+			}
+			if err := c.wait(ctx, attempt, 0); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if resp.StatusCode < 300 {
+			return decodeBody(resp, out)
+		}
+
+		apiErr := decodeError(resp)
+		// A rate-limited request was turned away before it was handled, so
+		// any request may be retried
+		again := resp.StatusCode == http.StatusTooManyRequests ||
+			(retryable && (resp.StatusCode == http.StatusBadGateway ||
+				resp.StatusCode == http.StatusServiceUnavailable ||
+				resp.StatusCode == http.StatusGatewayTimeout))
+		if !again || attempt >= attempts {
+			return apiErr
+		}
+		if err := c.wait(ctx, attempt, retryAfter(resp)); err != nil {
+			return err
+		}
+	}
+}
+
+// wait sleeps before the retry after attempt
+func (c *Client) wait(ctx context.Context, attempt int, after time.Duration) error {
+	backoff := time.Duration(float64(c.retry.MinBackoff) * math.Pow(2, float64(attempt-1)))
+	if c.retry.MaxBackoff > 0 && backoff > c.retry.MaxBackoff {
+		backoff = c.retry.MaxBackoff
+	}
+	// Jitter keeps many clients from retrying in step
+	if backoff > 0 {
+		backoff = time.Duration(mathrand.Int64N(int64(backoff))) + backoff/2
+	}
+	backoff = max(backoff, after)
+
+	t := time.NewTimer(backoff)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// decodeBody decodes a successful response into out. Bodies the spec
+// gives no schema for are kept as they are, and may be empty.
+func decodeBody(resp *http.Response, out any) error {
+	defer resp.Body.Close()
+	if out == nil {
+		return nil
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil || len(data) == 0 {
+		return err
+	}
+	if raw, ok := out.(*json.RawMessage); ok {
+		*raw = data
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+func decodeError(resp *http.Response) *Error {
+	defer resp.Body.Close()
+	apiErr := &Error{StatusCode: resp.StatusCode}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err := json.Unmarshal(data, apiErr); err != nil || apiErr.Code == "" {
+		apiErr.Code = strings.ReplaceAll(strings.ToLower(http.StatusText(resp.StatusCode)), " ", "_")
+		apiErr.Detail = strings.TrimSpace(string(data))
+	}
+	return apiErr
+}
+
+// retryAfter reads a Retry-After header given in seconds
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// PageInfo is where a page sits in a list
+type PageInfo struct {
+	Limit      int64  `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// paginate yields every item of a list from the page at cursor on,
+// fetching the next page until there are no more. An error ends the
+// sequence.
+func paginate[T any](cursor string, fetch func(cursor string) ([]T, PageInfo, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			items, page, err := fetch(cursor)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if !page.HasMore || page.NextCursor == "" {
+				return
+			}
+			cursor = page.NextCursor
+		}
+	}
+}
+
+// pathEscape escapes a path parameter
+func pathEscape(s string) string {
+	return url.PathEscape(s)
+}
@@ -0,0 +1,243 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// noWait retries at once
+var noWait = RetryPolicy{MaxAttempts: 3}
+
+func TestDoRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		idempotent   bool
+		statuses     []int
+		wantAttempts int
+		wantStatus   int
+	}{
+		{name: "get retried on 503", method: http.MethodGet, statuses: []int{503, 200}, wantAttempts: 2},
+		{name: "get gives up", method: http.MethodGet, statuses: []int{502, 502, 502}, wantAttempts: 3, wantStatus: 502},
+		{name: "post not retried", method: http.MethodPost, statuses: []int{503, 200}, wantAttempts: 1, wantStatus: 503},
+		{name: "idempotent post retried", method: http.MethodPost, idempotent: true, statuses: []int{503, 200}, wantAttempts: 2},
+		{name: "rate-limited post retried", method: http.MethodPost, statuses: []int{429, 200}, wantAttempts: 2},
+		{name: "client error not retried", method: http.MethodGet, statuses: []int{404, 200}, wantAttempts: 1, wantStatus: 404},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var keys []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				keys = append(keys, r.Header.Get("Idempotency-Key"))
+				status := tt.statuses[len(keys)-1]
+				mu.Unlock()
+				if status >= 300 {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(status)
+					return
+				}
+				fmt.Fprint(w, `{"ok":true}`)
+			}))
+			defer srv.Close()
+
+			c := New(srv.URL, WithRetry(noWait))
+			var out struct{ OK bool }
+			err := c.do(context.Background(), request{method: tt.method, path: "/x", idempotent: tt.idempotent}, &out)
+
+			if len(keys) != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", len(keys), tt.wantAttempts)
+			}
+			var apiErr *Error
+			switch {
+			case tt.wantStatus == 0 && err != nil:
+				t.Fatalf("do() error = %v", err)
+			case tt.wantStatus == 0 && !out.OK:
+				t.Errorf("do() didn't decode the response")
+			case tt.wantStatus != 0 && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantStatus):
+				t.Errorf("do() error = %v, want status %d", err, tt.wantStatus)
+			}
+			for _, key := range keys {
+				if tt.idempotent && (key == "" || key != keys[0]) {
+					t.Errorf("idempotency keys = %q, want one key on every attempt", keys)
+				}
+				if !tt.idempotent && key != "" {
+					t.Errorf("idempotency key %q sent with a request that takes none", key)
+				}
+			}
+		})
+	}
+}
+
+func TestIdempotencyKeyFromContext(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Idempotency-Key")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	ctx := WithIdempotencyKey(context.Background(), "order-42")
+	if err := New(srv.URL).do(ctx, request{method: http.MethodPost, path: "/x", idempotent: true}, nil); err != nil {
+		t.Fatalf("do() error = %v", err)
+	}
+	if got != "order-42" {
+		t.Errorf("Idempotency-Key = %q, want order-42", got)
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantCode   string
+		wantDetail string
+		wantFields int
+	}{
+		{
+			name:       "problem details",
+			body:       `{"type":"about:blank","title":"Invalid request","code":"validation_failed","detail":"name is required","request_id":"req-1","errors":[{"field":"name","code":"required","message":"is required"}]}`,
+			wantCode:   "validation_failed",
+			wantDetail: "name is required",
+			wantFields: 1,
+		},
+		{name: "plain text", body: "upstream down\n", wantCode: "unprocessable_entity", wantDetail: "upstream down"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer srv.Close()
+
+			err := New(srv.URL).do(context.Background(), request{method: http.MethodGet, path: "/x"}, nil)
+			var apiErr *Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("do() error = %v, want *Error", err)
+			}
+			if apiErr.StatusCode != http.StatusUnprocessableEntity || apiErr.Code != tt.wantCode ||
+				apiErr.Detail != tt.wantDetail || len(apiErr.Errors) != tt.wantFields {
+				t.Errorf("error = %+v", apiErr)
+			}
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	pages := map[string]struct {
+		items []int
+		page  PageInfo
+	}{
+		"":   {items: []int{1, 2}, page: PageInfo{HasMore: true, NextCursor: "c1"}},
+		"c1": {items: []int{3, 4}, page: PageInfo{HasMore: true, NextCursor: "c2"}},
+		"c2": {items: []int{5}, page: PageInfo{}},
+	}
+	fetch := func(cursor string) ([]int, PageInfo, error) {
+		p, ok := pages[cursor]
+		if !ok {
+			return nil, PageInfo{}, fmt.Errorf("unknown cursor %q", cursor)
+		}
+		return p.items, p.page, nil
+	}
+
+	tests := []struct {
+		name    string
+		cursor  string
+		stop    int
+		want    []int
+		wantErr bool
+	}{
+		{name: "every page", want: []int{1, 2, 3, 4, 5}},
+		{name: "from a cursor", cursor: "c1", want: []int{3, 4, 5}},
+		{name: "stopped early", stop: 3, want: []int{1, 2, 3}},
+		{name: "error ends it", cursor: "gone", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			var gotErr error
+			for item, err := range paginate(tt.cursor, fetch) {
+				if err != nil {
+					gotErr = err
+					continue
+				}
+				got = append(got, item)
+				if len(got) == tt.stop {
+					break
+				}
+			}
+			if (gotErr != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", gotErr, tt.wantErr)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("items = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListAll(t *testing.T) {
+	var cursors []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cursor := r.URL.Query().Get("cursor")
+		cursors = append(cursors, cursor)
+		page := map[string]any{"data": []map[string]any{{"id": "t-" + cursor}}, "page": PageInfo{Limit: 1}}
+		if cursor == "" {
+			page["page"] = PageInfo{Limit: 1, HasMore: true, NextCursor: "next"}
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, WithRetry(RetryPolicy{MaxAttempts: 1, MaxBackoff: time.Millisecond}))
+	var ids []string
+	for tenant, err := range c.TenantsListAll(context.Background(), &TenantsListParams{Limit: 1}) {
+		if err != nil {
+			t.Fatalf("TenantsListAll() error = %v", err)
+		}
+		ids = append(ids, tenant.ID)
+	}
+	if fmt.Sprint(ids) != "[t- t-next]" || fmt.Sprint(cursors) != "[ next]" {
+		t.Errorf("ids = %q, cursors = %q", ids, cursors)
+	}
+}
+
+func TestDoBody(t *testing.T) {
+	tests := []struct {
+		name            string
+		body            any
+		wantContentType string
+		wantBody        string
+	}{
+		{name: "encoded as JSON", body: map[string]string{"name": "Demo"}, wantContentType: "application/json", wantBody: `{"name":"Demo"}`},
+		{name: "sent as it is", body: Body{ContentType: "text/csv", Data: []byte("name\nShirt\n")}, wantContentType: "text/csv", wantBody: "name\nShirt\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var contentType, body string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, _ := io.ReadAll(r.Body)
+				contentType, body = r.Header.Get("Content-Type"), string(data)
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer srv.Close()
+
+			if err := New(srv.URL).do(context.Background(), request{method: http.MethodPost, path: "/x", body: tt.body}, nil); err != nil {
+				t.Fatalf("do() error = %v", err)
+			}
+			if contentType != tt.wantContentType || body != tt.wantBody {
+				t.Errorf("sent %q as %q, want %q as %q", body, contentType, tt.wantBody, tt.wantContentType)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+// Command querymock writes internal/database/dbmock, a mock of the Querier
+// interface sqlc generates. Run it from backend after regenerating the
+// queries:
+//
+//	sqlc generate && go run ./cmd/querymock
+//
+// With -check it writes nothing and fails if the mock is out of date.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const databaseImport = "github.com/dfodeker/terminus/internal/database"
+
+func main() {
+	in := flag.String("in", "internal/database/querier.go", "file declaring the Querier interface")
+	out := flag.String("out", "internal/database/dbmock/querier.go", "file to write")
+	check := flag.Bool("check", false, "fail if the file isn't what would be written")
+	flag.Parse()
+
+	src, err := generate(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *check {
+		existing, err := os.ReadFile(*out)
+		if err != nil || !bytes.Equal(existing, src) {
+			log.Fatalf("%s is out of date with %s; regenerate it with go run ./cmd/querymock", *out, *in)
+		}
+		return
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// method is one method of the interface, its types qualified with the
+// database package
+type method struct {
+	Name    string
+	Params  []string // "name type"
+	Args    []string // names, to pass on
+	Results string
+}
+
+func generate(path string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	iface := querier(file)
+	if iface == nil {
+		return nil, fmt.Errorf("%s declares no Querier interface", path)
+	}
+
+	var methods []method
+	for _, field := range iface.Methods.List {
+		fn, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) != 1 {
+			return nil, fmt.Errorf("%s: Querier embeds %s; only methods are supported", path, expr(fset, field.Type))
+		}
+		qualify(fn)
+		m := method{Name: field.Names[0].Name}
+		for i, p := range fn.Params.List {
+			names := p.Names
+			if len(names) == 0 {
+				names = []*ast.Ident{ast.NewIdent("arg" + strconv.Itoa(i))}
+			}
+			for _, name := range names {
+				m.Params = append(m.Params, name.Name+" "+expr(fset, p.Type))
+				m.Args = append(m.Args, name.Name)
+			}
+		}
+		if fn.Results != nil {
+			var results []string
+			for _, r := range fn.Results.List {
+				results = append(results, expr(fset, r.Type))
+			}
+			m.Results = strings.Join(results, ", ")
+			if len(results) > 1 {
+				m.Results = "(" + m.Results + ")"
+			}
+		}
+		methods = append(methods, m)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by querymock from %s. DO NOT EDIT.\n\n", path)
+	b.WriteString("package dbmock\n\nimport (\n")
+	std, third := []string{}, []string{strconv.Quote(databaseImport)}
+	for _, imp := range file.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		if first, _, _ := strings.Cut(path, "/"); strings.Contains(first, ".") {
+			third = append(third, imp.Path.Value)
+		} else {
+			std = append(std, imp.Path.Value)
+		}
+	}
+	slices.Sort(std)
+	slices.Sort(third)
+	for _, group := range [][]string{std, third} {
+		for _, path := range group {
+			b.WriteString("\t" + path + "\n")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(")\n\n")
+
+	b.WriteString("// Querier is a database.Querier whose methods call the func field named\n")
+	b.WriteString("// after them. A method whose func is nil panics, so a test fails on a\n")
+	b.WriteString("// query it didn't expect.\n")
+	b.WriteString("type Querier struct {\n")
+	for _, m := range methods {
+		fmt.Fprintf(&b, "\t%sFunc func(%s) %s\n", m.Name, strings.Join(m.Params, ", "), m.Results)
+	}
+	b.WriteString("}\n\nvar _ database.Querier = (*Querier)(nil)\n")
+
+	for _, m := range methods {
+		fmt.Fprintf(&b, "\nfunc (m *Querier) %s(%s) %s {\n", m.Name, strings.Join(m.Params, ", "), m.Results)
+		fmt.Fprintf(&b, "\tif m.%sFunc == nil {\n\t\tpanic(unexpected(%q))\n\t}\n", m.Name, m.Name)
+		ret := "return "
+		if m.Results == "" {
+			ret = ""
+		}
+		fmt.Fprintf(&b, "\t%sm.%sFunc(%s)\n}\n", ret, m.Name, strings.Join(m.Args, ", "))
+	}
+	return format.Source(b.Bytes())
+}
+
+func querier(file *ast.File) *ast.InterfaceType {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if iface, ok := ts.Type.(*ast.InterfaceType); ok && ts.Name.Name == "Querier" {
+				return iface
+			}
+		}
+	}
+	return nil
+}
+
+// qualify prefixes the types of fn declared in the database package with
+// its name, as the mock refers to them from outside it
+func qualify(fn *ast.FuncType) {
+	var fields []*ast.Field
+	fields = append(fields, fn.Params.List...)
+	if fn.Results != nil {
+		fields = append(fields, fn.Results.List...)
+	}
+	for _, field := range fields {
+		ast.Inspect(field.Type, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.SelectorExpr:
+				// Declared elsewhere, such as uuid.UUID
+				return false
+			case *ast.Ident:
+				if n.IsExported() {
+					n.Name = "database." + n.Name
+				}
+			}
+			return true
+		})
+	}
+}
+
+func expr(fset *token.FileSet, e ast.Expr) string {
+	var b bytes.Buffer
+	printer.Fprint(&b, fset, e)
+	return b.String()
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"regexp"
+	"strings"
+
+	"github.com/dfodeker/terminus/internal/openapi"
+)
+
+// pathParam matches a parameter of a document path
+var pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// goImports are the packages generated code may use, with what it uses
+// them for
+var goImports = []struct{ path, use string }{
+	{"context", "context.Context"},
+	{"encoding/json", "json.RawMessage"},
+	{"iter", "iter.Seq2"},
+	{"net/url", "url.Values"},
+	{"strconv", "strconv.Format"},
+	{"time", "time.Time"},
+}
+
+// generateGo writes the schemas and operations of the Go client
+func generateGo(doc *openapi.Document, ops []operation) ([]byte, error) {
+	var b strings.Builder
+	for _, name := range componentNames(doc) {
+		schema := doc.Components.Schemas[name]
+		fmt.Fprintln(&b)
+		fmt.Fprintf(&b, "type %s struct {\n", name)
+		for _, prop := range propertyNames(schema) {
+			tag := prop
+			if !required(schema, prop) {
+				tag += ",omitempty"
+			}
+			fmt.Fprintf(&b, "\t%s %s `json:%q`\n", exported(prop), goType(schema.Properties[prop]), tag)
+		}
+		fmt.Fprintln(&b, "}")
+	}
+
+	for _, op := range ops {
+		writeGoOperation(&b, op)
+	}
+
+	body := b.String()
+	var head strings.Builder
+	fmt.Fprintln(&head, "// Code generated by sdkgen from openapi.json. DO NOT EDIT.")
+	fmt.Fprintln(&head)
+	fmt.Fprintln(&head, "package client")
+	fmt.Fprintln(&head)
+	fmt.Fprintln(&head, "import (")
+	for _, imp := range goImports {
+		// Only what the document's operations use
+		if strings.Contains(body, imp.use) {
+			fmt.Fprintf(&head, "\t%q\n", imp.path)
+		}
+	}
+	fmt.Fprintln(&head, ")")
+
+	src, err := format.Source([]byte(head.String() + body))
+	if err != nil {
+		return nil, fmt.Errorf("generated Go doesn't parse: %w", err)
+	}
+	return src, nil
+}
+
+func writeGoOperation(b *strings.Builder, op operation) {
+	params := op.Name + "Params"
+	if len(op.Query) > 0 {
+		fmt.Fprintln(b)
+		fmt.Fprintf(b, "// %s are the query parameters of %s\n", params, op.Name)
+		fmt.Fprintf(b, "type %s struct {\n", params)
+		for _, q := range op.Query {
+			if q.Description != "" {
+				comment(b, "\t", q.Description)
+			}
+			fmt.Fprintf(b, "\t%s %s\n", exported(q.Name), goQueryType(q))
+		}
+		fmt.Fprintln(b, "}")
+		fmt.Fprintln(b)
+		fmt.Fprintf(b, "func (p *%s) values() url.Values {\n", params)
+		fmt.Fprintln(b, "\tq := url.Values{}")
+		fmt.Fprintln(b, "\tif p == nil {")
+		fmt.Fprintln(b, "\t\treturn q")
+		fmt.Fprintln(b, "\t}")
+		for _, q := range op.Query {
+			field := "p." + exported(q.Name)
+			switch goQueryType(q) {
+			case "int64":
+				fmt.Fprintf(b, "\tif %s != 0 {\n\t\tq.Set(%q, strconv.FormatInt(%s, 10))\n\t}\n", field, q.Name, field)
+			case "*bool":
+				fmt.Fprintf(b, "\tif %s != nil {\n\t\tq.Set(%q, strconv.FormatBool(*%s))\n\t}\n", field, q.Name, field)
+			default:
+				fmt.Fprintf(b, "\tif %s != \"\" {\n\t\tq.Set(%q, %s)\n\t}\n", field, q.Name, field)
+			}
+		}
+		fmt.Fprintln(b, "\treturn q")
+		fmt.Fprintln(b, "}")
+	}
+
+	args := []string{"ctx context.Context"}
+	for _, p := range op.PathParams {
+		args = append(args, p+" string")
+	}
+	if len(op.Query) > 0 {
+		args = append(args, "params *"+params)
+	}
+	switch {
+	case op.Body != nil:
+		args = append(args, "body "+goType(op.Body))
+	case op.Method == "POST" || op.Method == "PUT" || op.Method == "PATCH":
+		args = append(args, "body any")
+	}
+
+	req := fmt.Sprintf("request{method: %q, path: %s", op.Method, goPath(op.Path))
+	if len(op.Query) > 0 {
+		req += ", query: params.values()"
+	}
+	if op.Body != nil || op.Method == "POST" || op.Method == "PUT" || op.Method == "PATCH" {
+		req += ", body: body"
+	}
+	if op.Idempotent {
+		req += ", idempotent: true"
+	}
+	req += "}"
+
+	fmt.Fprintln(b)
+	fmt.Fprintf(b, "// %s calls %s %s.\n", op.Name, op.Method, op.Path)
+	if text := sentence(op.Summary); text != "" {
+		fmt.Fprintln(b, "//")
+		comment(b, "", text)
+	}
+	if text := sentence(op.Description); text != "" {
+		comment(b, "", text)
+	}
+	switch {
+	case op.NoContent:
+		fmt.Fprintf(b, "func (c *Client) %s(%s) error {\n", op.Name, strings.Join(args, ", "))
+		fmt.Fprintf(b, "\treturn c.do(ctx, %s, nil)\n", req)
+	case op.Response != nil && op.Response.Ref != "":
+		out := goType(op.Response)
+		fmt.Fprintf(b, "func (c *Client) %s(%s) (*%s, error) {\n", op.Name, strings.Join(args, ", "), out)
+		fmt.Fprintf(b, "\tvar out %s\n", out)
+		fmt.Fprintf(b, "\tif err := c.do(ctx, %s, &out); err != nil {\n\t\treturn nil, err\n\t}\n", req)
+		fmt.Fprintln(b, "\treturn &out, nil")
+	default:
+		out := "json.RawMessage"
+		if op.Response != nil {
+			out = goType(op.Response)
+		}
+		fmt.Fprintf(b, "func (c *Client) %s(%s) (%s, error) {\n", op.Name, strings.Join(args, ", "), out)
+		fmt.Fprintf(b, "\tvar out %s\n", out)
+		fmt.Fprintf(b, "\terr := c.do(ctx, %s, &out)\n", req)
+		fmt.Fprintln(b, "\treturn out, err")
+	}
+	fmt.Fprintln(b, "}")
+
+	if op.Item == nil {
+		return
+	}
+	item := goType(op.Item)
+	callArgs := []string{"ctx"}
+	callArgs = append(callArgs, op.PathParams...)
+	callArgs = append(callArgs, "&p")
+	fmt.Fprintln(b)
+	fmt.Fprintf(b, "// %sAll calls %s for each page in turn, from params.Cursor on, and\n", op.Name, op.Name)
+	fmt.Fprintln(b, "// yields the items. An error ends the sequence.")
+	fmt.Fprintf(b, "func (c *Client) %sAll(%s) iter.Seq2[%s, error] {\n", op.Name, strings.Join(args, ", "), item)
+	fmt.Fprintf(b, "\tvar p %s\n", params)
+	fmt.Fprintln(b, "\tif params != nil {\n\t\tp = *params\n\t}")
+	fmt.Fprintf(b, "\treturn paginate(p.Cursor, func(cursor string) ([]%s, PageInfo, error) {\n", item)
+	fmt.Fprintln(b, "\t\tp.Cursor = cursor")
+	fmt.Fprintf(b, "\t\tpage, err := c.%s(%s)\n", op.Name, strings.Join(callArgs, ", "))
+	fmt.Fprintln(b, "\t\tif err != nil {\n\t\t\treturn nil, PageInfo{}, err\n\t\t}")
+	fmt.Fprintln(b, "\t\treturn page.Data, page.Page, nil")
+	fmt.Fprintln(b, "\t})")
+	fmt.Fprintln(b, "}")
+}
+
+// goPath is a Go expression building path from its parameters
+func goPath(path string) string {
+	var parts []string
+	last := 0
+	for _, m := range pathParam.FindAllStringSubmatchIndex(path, -1) {
+		if m[0] > last {
+			parts = append(parts, fmt.Sprintf("%q", path[last:m[0]]))
+		}
+		parts = append(parts, "pathEscape("+path[m[2]:m[3]]+")")
+		last = m[1]
+	}
+	if last < len(path) {
+		parts = append(parts, fmt.Sprintf("%q", path[last:]))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// goType is the Go type of values of s
+func goType(s *openapi.Schema) string {
+	if s.Ref != "" {
+		return refName(s.Ref)
+	}
+	if inner := nullableOf(s); inner != nil {
+		return "*" + goType(inner)
+	}
+	typ, nullable := schemaType(s)
+	var t string
+	switch typ {
+	case "string":
+		t = "string"
+		if s.Format == "date-time" {
+			t = "time.Time"
+		}
+	case "integer":
+		t = "int64"
+		if s.Format == "int32" {
+			t = "int32"
+		}
+	case "number":
+		t = "float64"
+	case "boolean":
+		t = "bool"
+	case "array":
+		t = "[]" + goType(s.Items)
+	case "object":
+		if s.AdditionalProperties == nil {
+			return "json.RawMessage"
+		}
+		t = "map[string]" + goType(s.AdditionalProperties)
+	default:
+		return "json.RawMessage"
+	}
+	if nullable {
+		return "*" + t
+	}
+	return t
+}
+
+// goQueryType is the Go type of a query parameter: booleans are pointers
+// so false can be sent
+func goQueryType(q openapi.Parameter) string {
+	typ, _ := schemaType(q.Schema)
+	switch typ {
+	case "integer":
+		return "int64"
+	case "boolean":
+		return "*bool"
+	}
+	return "string"
+}
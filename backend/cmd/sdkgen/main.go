@@ -0,0 +1,277 @@
+// Command sdkgen writes the Go and TypeScript clients of the API from
+// openapi.json. Run it from backend after regenerating the document:
+//
+//	go run . openapi && go run ./cmd/sdkgen
+//
+// With -check it writes nothing and fails if either client is out of date.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dfodeker/terminus/internal/openapi"
+)
+
+// handwritten are the schemas both clients define themselves
+var handwritten = map[string]bool{
+	"PageInfo":   true,
+	"Problem":    true,
+	"FieldError": true,
+}
+
+// methodOrder lists an operation's methods in a fixed order
+var methodOrder = []string{"get", "post", "put", "patch", "delete"}
+
+func main() {
+	spec := flag.String("spec", "openapi.json", "OpenAPI document to generate from")
+	goOut := flag.String("go", "client/operations.go", "Go file to write")
+	tsOut := flag.String("ts", "../clients/typescript/src/operations.ts", "TypeScript file to write")
+	check := flag.Bool("check", false, "fail if the files aren't what would be written")
+	flag.Parse()
+
+	data, err := os.ReadFile(*spec)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var doc openapi.Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		log.Fatalf("Unable to read %s: %s", *spec, err)
+	}
+
+	ops := operations(&doc)
+	goSrc, err := generateGo(&doc, ops)
+	if err != nil {
+		log.Fatal(err)
+	}
+	tsSrc := generateTS(&doc, ops)
+
+	failed := false
+	for path, src := range map[string][]byte{*goOut: goSrc, *tsOut: tsSrc} {
+		if *check {
+			existing, err := os.ReadFile(path)
+			if err != nil || !bytes.Equal(existing, src) {
+				log.Printf("%s is out of date with %s; regenerate it with go run ./cmd/sdkgen", path, *spec)
+				failed = true
+			}
+			continue
+		}
+		if err := os.WriteFile(path, src, 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// operation is what both clients need to know of one operation
+type operation struct {
+	Name        string
+	Method      string
+	Path        string
+	Summary     string
+	Description string
+	PathParams  []string
+	Query       []openapi.Parameter
+	Body        *openapi.Schema
+	// Response is nil when the operation documents no body; NoContent
+	// says it never has one
+	Response  *openapi.Schema
+	NoContent bool
+	// Idempotent operations take an Idempotency-Key, so may be retried
+	Idempotent bool
+	// Item is the schema of the items of a list that pages with a cursor
+	Item *openapi.Schema
+}
+
+func operations(doc *openapi.Document) []operation {
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var ops []operation
+	for _, path := range paths {
+		for _, method := range methodOrder {
+			docOp := doc.Paths[path][method]
+			if docOp == nil {
+				continue
+			}
+			op := operation{
+				Name:        exported(docOp.OperationID),
+				Method:      strings.ToUpper(method),
+				Path:        path,
+				Summary:     docOp.Summary,
+				Description: docOp.Description,
+			}
+			for _, p := range docOp.Parameters {
+				switch {
+				case p.In == "path":
+					op.PathParams = append(op.PathParams, p.Name)
+				case p.In == "query":
+					op.Query = append(op.Query, p)
+				case p.In == "header" && p.Name == openapi.IdempotencyKeyHeader:
+					op.Idempotent = true
+				}
+			}
+			if docOp.RequestBody != nil {
+				op.Body = docOp.RequestBody.Content["application/json"].Schema
+			}
+			for status, resp := range docOp.Responses {
+				if status == "default" {
+					continue
+				}
+				op.NoContent = status == strconv.Itoa(http.StatusNoContent)
+				if mt, ok := resp.Content["application/json"]; ok {
+					op.Response = mt.Schema
+				}
+			}
+			op.Item = pageItem(doc, op)
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// pageItem returns the item schema of an operation returning a page of
+// a list, one with data and a PageInfo, that takes a cursor
+func pageItem(doc *openapi.Document, op operation) *openapi.Schema {
+	if op.Response == nil || op.Response.Ref == "" {
+		return nil
+	}
+	cursor := false
+	for _, q := range op.Query {
+		cursor = cursor || q.Name == "cursor"
+	}
+	page := doc.Components.Schemas[refName(op.Response.Ref)]
+	if !cursor || page == nil || page.Properties["data"] == nil || page.Properties["page"] == nil {
+		return nil
+	}
+	if refName(page.Properties["page"].Ref) != "PageInfo" || page.Properties["data"].Items == nil {
+		return nil
+	}
+	return page.Properties["data"].Items
+}
+
+// schemaType splits a schema's type into the type and whether it may be
+// null, as a document read back from JSON holds it
+func schemaType(s *openapi.Schema) (string, bool) {
+	switch t := s.Type.(type) {
+	case string:
+		return t, false
+	case []any:
+		typ, nullable := "", false
+		for _, v := range t {
+			if v == "null" {
+				nullable = true
+			} else if str, ok := v.(string); ok {
+				typ = str
+			}
+		}
+		return typ, nullable
+	}
+	return "", false
+}
+
+// nullableOf returns the schema s allows null besides, if it is an anyOf
+// of one schema and null
+func nullableOf(s *openapi.Schema) *openapi.Schema {
+	if len(s.AnyOf) != 2 {
+		return nil
+	}
+	for i, alt := range s.AnyOf {
+		if t, _ := schemaType(alt); t == "null" {
+			return s.AnyOf[1-i]
+		}
+	}
+	return nil
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// componentNames lists the generated components in order
+func componentNames(doc *openapi.Document) []string {
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		if !handwritten[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// propertyNames lists a schema's properties in order
+func propertyNames(s *openapi.Schema) []string {
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func required(s *openapi.Schema, name string) bool {
+	for _, r := range s.Required {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// initialisms are written in capitals in Go names
+var initialisms = map[string]bool{
+	"api": true, "gid": true, "http": true, "id": true, "ids": true, "ip": true, "json": true,
+	"mfa": true, "saml": true, "sku": true, "sso": true, "uri": true, "url": true, "uuid": true,
+}
+
+// exported turns a JSON name such as tenant_id, or an operation ID, into
+// an exported Go name
+func exported(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' || r == '.' })
+	var b strings.Builder
+	for _, w := range words {
+		switch {
+		case initialisms[strings.ToLower(w)]:
+			b.WriteString(strings.ToUpper(w))
+		default:
+			b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+		}
+	}
+	return b.String()
+}
+
+// comment wraps text as a doc comment
+func comment(b *strings.Builder, indent, text string) {
+	line := indent + "//"
+	for _, word := range strings.Fields(text) {
+		if len(line)+1+len(word) > 76 && line != indent+"//" {
+			fmt.Fprintln(b, line)
+			line = indent + "//"
+		}
+		line += " " + word
+	}
+	fmt.Fprintln(b, line)
+}
+
+// sentence ends text with a full stop
+func sentence(text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" || strings.HasSuffix(text, ".") {
+		return text
+	}
+	return text + "."
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dfodeker/terminus/internal/openapi"
+)
+
+// tsIdentifier matches property names that need no quotes
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// generateTS writes the schemas and operations of the TypeScript client
+func generateTS(doc *openapi.Document, ops []operation) []byte {
+	var b strings.Builder
+	fmt.Fprintln(&b, "// Code generated by sdkgen from openapi.json. DO NOT EDIT.")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, `import { BaseClient, type PageInfo, type RequestOptions } from "./core";`)
+
+	for _, name := range componentNames(doc) {
+		schema := doc.Components.Schemas[name]
+		fmt.Fprintln(&b)
+		fmt.Fprintf(&b, "export interface %s {\n", name)
+		for _, prop := range propertyNames(schema) {
+			key := prop
+			if !tsIdentifier.MatchString(key) {
+				key = fmt.Sprintf("%q", key)
+			}
+			if !required(schema, prop) {
+				key += "?"
+			}
+			fmt.Fprintf(&b, "  %s: %s;\n", key, tsType(schema.Properties[prop]))
+		}
+		fmt.Fprintln(&b, "}")
+	}
+
+	for _, op := range ops {
+		if len(op.Query) == 0 {
+			continue
+		}
+		fmt.Fprintln(&b)
+		fmt.Fprintf(&b, "export interface %sParams {\n", op.Name)
+		for _, q := range op.Query {
+			if q.Description != "" {
+				fmt.Fprintf(&b, "  /** %s */\n", q.Description)
+			}
+			fmt.Fprintf(&b, "  %s?: %s;\n", q.Name, tsType(q.Schema))
+		}
+		fmt.Fprintln(&b, "}")
+	}
+
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "export class Client extends BaseClient {")
+	for i, op := range ops {
+		if i > 0 {
+			fmt.Fprintln(&b)
+		}
+		writeTSOperation(&b, op)
+	}
+	fmt.Fprintln(&b, "}")
+	return []byte(b.String())
+}
+
+func writeTSOperation(b *strings.Builder, op operation) {
+	method := strings.ToLower(op.Name[:1]) + op.Name[1:]
+
+	var args, callArgs []string
+	for _, p := range op.PathParams {
+		args = append(args, p+": string")
+		callArgs = append(callArgs, p)
+	}
+	if len(op.Query) > 0 {
+		args = append(args, fmt.Sprintf("params?: %sParams", op.Name))
+	}
+	hasBody := op.Body != nil || op.Method == "POST" || op.Method == "PUT" || op.Method == "PATCH"
+	if hasBody {
+		body := "unknown"
+		if op.Body != nil {
+			body = tsType(op.Body)
+		}
+		args = append(args, "body?: "+body)
+	}
+	args = append(args, "options?: RequestOptions")
+
+	out := "unknown"
+	switch {
+	case op.NoContent:
+		out = "void"
+	case op.Response != nil:
+		out = tsType(op.Response)
+	}
+
+	req := fmt.Sprintf("method: %q, path: %s", op.Method, tsPath(op.Path))
+	if len(op.Query) > 0 {
+		req += ", query: params"
+	}
+	if hasBody {
+		req += ", body"
+	}
+	if op.Idempotent {
+		req += ", idempotent: true"
+	}
+
+	fmt.Fprintln(b, "  /**")
+	if op.Summary != "" {
+		fmt.Fprintf(b, "   * %s\n", sentence(op.Summary))
+	}
+	if op.Description != "" {
+		fmt.Fprintf(b, "   * %s\n", sentence(op.Description))
+	}
+	fmt.Fprintf(b, "   * `%s %s`\n", op.Method, op.Path)
+	fmt.Fprintln(b, "   */")
+	fmt.Fprintf(b, "  %s(%s): Promise<%s> {\n", method, strings.Join(args, ", "), out)
+	fmt.Fprintf(b, "    return this.request<%s>({ %s }, options);\n", out, req)
+	fmt.Fprintln(b, "  }")
+
+	if op.Item == nil {
+		return
+	}
+	item := tsType(op.Item)
+	pageArgs := append(append([]string{}, callArgs...), "{ ...params, cursor }", "options")
+	fmt.Fprintln(b)
+	fmt.Fprintln(b, "  /**")
+	fmt.Fprintf(b, "   * Every item of %s, from `params.cursor` on, fetching page after page.\n", method)
+	fmt.Fprintln(b, "   */")
+	fmt.Fprintf(b, "  %sAll(%s): AsyncGenerator<%s> {\n", method, strings.Join(args, ", "), item)
+	fmt.Fprintf(b, "    return this.paginate(params?.cursor, (cursor) => this.%s(%s));\n", method, strings.Join(pageArgs, ", "))
+	fmt.Fprintln(b, "  }")
+}
+
+// tsPath is a template literal building path from its parameters
+func tsPath(path string) string {
+	return "`" + pathParam.ReplaceAllString(path, "$${encodeURIComponent($1)}") + "`"
+}
+
+// tsType is the TypeScript type of values of s
+func tsType(s *openapi.Schema) string {
+	if s == nil {
+		return "unknown"
+	}
+	if s.Ref != "" {
+		return refName(s.Ref)
+	}
+	if inner := nullableOf(s); inner != nil {
+		return tsType(inner) + " | null"
+	}
+	typ, nullable := schemaType(s)
+	var t string
+	switch typ {
+	case "string":
+		t = "string"
+	case "integer", "number":
+		t = "number"
+	case "boolean":
+		t = "boolean"
+	case "array":
+		item := tsType(s.Items)
+		if strings.Contains(item, " ") {
+			item = "(" + item + ")"
+		}
+		t = item + "[]"
+	case "object":
+		if s.AdditionalProperties == nil {
+			return "Record<string, unknown>"
+		}
+		t = "Record<string, " + tsType(s.AdditionalProperties) + ">"
+	default:
+		return "unknown"
+	}
+	if nullable {
+		return t + " | null"
+	}
+	return t
+}
@@ -0,0 +1,379 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dfodeker/terminus/client"
+)
+
+func runLogin(args []string) error {
+	fs := flag.NewFlagSet("login", flag.ContinueOnError)
+	url := fs.String("url", "", "API to sign in to (default TERMINUS_URL or "+defaultURL+")")
+	email := fs.String("email", "", "account email")
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from stdin without prompting")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	s, err := loadSession()
+	if err != nil {
+		return err
+	}
+	if *url != "" {
+		s.URL = *url
+	}
+	if *email == "" {
+		if *email, err = prompt("Email"); err != nil {
+			return err
+		}
+	}
+	label := "Password"
+	if *passwordStdin {
+		label = ""
+	}
+	password, err := prompt(label)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	c := client.New(s.URL)
+	result, err := c.LoginUsers(ctx, map[string]string{"email": *email, "password": password})
+	if err != nil {
+		return err
+	}
+	token, refresh := result.Token, result.RefreshToken
+	if result.MFARequired {
+		code, err := prompt("Authentication code")
+		if err != nil {
+			return err
+		}
+		tokens, err := c.LoginMFA(ctx, map[string]string{"mfa_token": result.MFAToken, "code": code})
+		if err != nil {
+			return err
+		}
+		token, refresh = tokens.Token, tokens.RefreshToken
+	}
+
+	s.Email, s.Token, s.RefreshToken = *email, token, refresh
+	if err := saveSession(s); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Signed in to %s as %s\n", s.URL, *email)
+	return nil
+}
+
+func runLogout(args []string) error {
+	fs := flag.NewFlagSet("logout", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	s, err := loadSession()
+	if err != nil {
+		return err
+	}
+	if s.Token != "" {
+		// The local session goes either way; a token that no longer works
+		// has nothing to revoke
+		c := client.New(s.URL, client.WithToken(s.Token))
+		if err := c.Logout(context.Background(), map[string]string{"refresh_token": s.RefreshToken}); err != nil {
+			fmt.Fprintln(os.Stderr, "terminus: revoking the session:", err)
+		}
+	}
+	path, err := sessionPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func runTenants(args []string) error {
+	name, args, err := subcommand(args, "list", "create")
+	if err != nil {
+		return err
+	}
+	fs := flag.NewFlagSet("tenants "+name, flag.ContinueOnError)
+	tenantName := fs.String("name", "", "tenant name")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	c, err := apiClient()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch name {
+	case "list":
+		var all []client.TenantResponse
+		for tenant, err := range c.TenantsListAll(ctx, nil) {
+			if err != nil {
+				return err
+			}
+			all = append(all, tenant)
+		}
+		return printJSON(all)
+	default:
+		if err := required(fs, "name"); err != nil {
+			return err
+		}
+		return printResult(c.TenantsCreate(ctx, map[string]string{"name": *tenantName}))
+	}
+}
+
+func runStores(args []string) error {
+	name, args, err := subcommand(args, "list", "create", "update", "delete")
+	if err != nil {
+		return err
+	}
+	fs := flag.NewFlagSet("stores "+name, flag.ContinueOnError)
+	tenant, store := tenantFlag(fs), storeFlag(fs)
+	storeName := fs.String("name", "", "store name")
+	handle := fs.String("handle", "", "store handle, made from the name when empty")
+	plan := fs.String("plan", "", "plan, when staff set it")
+	body := fs.String("f", "", "JSON file of the fields to update, or - for stdin")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := required(fs, "tenant"); err != nil {
+		return err
+	}
+	c, err := apiClient()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch name {
+	case "list":
+		var all []client.TenantStoreResponse
+		for store, err := range c.TenantStoresListAll(ctx, *tenant, nil) {
+			if err != nil {
+				return err
+			}
+			all = append(all, store)
+		}
+		return printJSON(all)
+	case "create":
+		if err := required(fs, "name"); err != nil {
+			return err
+		}
+		fields := map[string]string{"name": *storeName, "handle": *handle, "plan": *plan}
+		return printResult(c.TenantStoresCreate(ctx, *tenant, fields))
+	case "update":
+		if err := required(fs, "store", "f"); err != nil {
+			return err
+		}
+		fields, err := readJSON(*body)
+		if err != nil {
+			return err
+		}
+		return printResult(c.TenantStoreUpdate(ctx, *tenant, *store, fields))
+	default:
+		if err := required(fs, "store"); err != nil {
+			return err
+		}
+		return printResult(c.TenantStoreDelete(ctx, *tenant, *store))
+	}
+}
+
+func runProducts(args []string) error {
+	name, args, err := subcommand(args, "list", "get", "create", "update", "delete")
+	if err != nil {
+		return err
+	}
+	fs := flag.NewFlagSet("products "+name, flag.ContinueOnError)
+	tenant, store := tenantFlag(fs), storeFlag(fs)
+	product := fs.String("product", "", "product ID")
+	productName := fs.String("name", "", "product name")
+	handle := fs.String("handle", "", "product handle, made from the name when empty")
+	status := fs.String("status", "", "active or draft")
+	sku := fs.String("sku", "", "SKU")
+	body := fs.String("f", "", "JSON file of the product's fields, or - for stdin")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := required(fs, "tenant", "store"); err != nil {
+		return err
+	}
+	c, err := apiClient()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch name {
+	case "list":
+		return printResult(c.TenantProductsList(ctx, *tenant, *store))
+	case "create":
+		fields := map[string]any{}
+		if *body != "" {
+			if fields, err = readJSON(*body); err != nil {
+				return err
+			}
+		}
+		// Flags win over the file
+		for key, value := range map[string]string{"name": *productName, "handle": *handle, "status": *status, "sku": *sku} {
+			if value != "" {
+				fields[key] = value
+			}
+		}
+		if fields["name"] == nil {
+			return errors.New("products create: -name or a name in -f is required")
+		}
+		return printResult(c.TenantProductCreate(ctx, *tenant, *store, fields))
+	}
+
+	if err := required(fs, "product"); err != nil {
+		return err
+	}
+	switch name {
+	case "get":
+		return printResult(c.TenantProductGet(ctx, *tenant, *store, *product))
+	case "update":
+		if err := required(fs, "f"); err != nil {
+			return err
+		}
+		fields, err := readJSON(*body)
+		if err != nil {
+			return err
+		}
+		return printResult(c.TenantProductUpdate(ctx, *tenant, *store, *product, fields))
+	default:
+		return printResult(c.TenantProductDelete(ctx, *tenant, *store, *product))
+	}
+}
+
+func runImport(args []string) error {
+	name, args, err := subcommand(args, "products", "get")
+	if err != nil {
+		return err
+	}
+	fs := flag.NewFlagSet("import "+name, flag.ContinueOnError)
+	tenant, store := tenantFlag(fs), storeFlag(fs)
+	file := fs.String("file", "", "product CSV to import")
+	id := fs.String("id", "", "import ID")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := required(fs, "tenant", "store"); err != nil {
+		return err
+	}
+	c, err := apiClient()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	if name == "get" {
+		if err := required(fs, "id"); err != nil {
+			return err
+		}
+		return printResult(c.TenantProductImportGet(ctx, *tenant, *store, *id))
+	}
+	if err := required(fs, "file"); err != nil {
+		return err
+	}
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+	csv := client.Body{ContentType: "text/csv", Data: data}
+	return printResult(c.TenantProductImportCreate(ctx, *tenant, *store, csv))
+}
+
+// exportOps are what can be exported, with the operations that queue an
+// export and look one up
+var exportOps = map[string]struct {
+	create func(c *client.Client, ctx context.Context, tenant, store string, body any) (json.RawMessage, error)
+	get    func(c *client.Client, ctx context.Context, tenant, store, id string) (json.RawMessage, error)
+}{
+	"products":  {(*client.Client).TenantExportCreateProducts, (*client.Client).TenantExportGetProducts},
+	"customers": {(*client.Client).TenantExportCreateCustomers, (*client.Client).TenantExportGetCustomers},
+	"orders":    {(*client.Client).TenantExportCreateOrders, (*client.Client).TenantExportGetOrders},
+}
+
+func runExport(args []string) error {
+	name, args, err := subcommand(args, "products", "customers", "orders", "get")
+	if err != nil {
+		return err
+	}
+	fs := flag.NewFlagSet("export "+name, flag.ContinueOnError)
+	tenant, store := tenantFlag(fs), storeFlag(fs)
+	format := fs.String("format", "csv", "csv or json")
+	columns := fs.String("columns", "", "comma-separated columns, all when empty")
+	resource := fs.String("resource", "products", "products, customers or orders")
+	id := fs.String("id", "", "export ID")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := required(fs, "tenant", "store"); err != nil {
+		return err
+	}
+	c, err := apiClient()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	if name == "get" {
+		ops, ok := exportOps[*resource]
+		if !ok {
+			return fmt.Errorf("export get: unknown resource %q", *resource)
+		}
+		if err := required(fs, "id"); err != nil {
+			return err
+		}
+		return printResult(ops.get(c, ctx, *tenant, *store, *id))
+	}
+	body := map[string]any{"format": *format}
+	if *columns != "" {
+		body["columns"] = strings.Split(*columns, ",")
+	}
+	return printResult(exportOps[name].create(c, ctx, *tenant, *store, body))
+}
+
+func tenantFlag(fs *flag.FlagSet) *string {
+	return fs.String("tenant", os.Getenv("TERMINUS_TENANT"), "tenant ID (default TERMINUS_TENANT)")
+}
+
+func storeFlag(fs *flag.FlagSet) *string {
+	return fs.String("store", os.Getenv("TERMINUS_STORE"), "store ID (default TERMINUS_STORE)")
+}
+
+// printResult prints the response of an operation, or returns its error
+func printResult(resp json.RawMessage, err error) error {
+	if err != nil {
+		return err
+	}
+	return printJSON(resp)
+}
+
+// readJSON reads a JSON object from a file, or stdin for -
+func readJSON(path string) (map[string]any, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("%s is not a JSON object: %w", path, err)
+	}
+	return fields, nil
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/config"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/dbpool"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/migrate"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/service/products"
+	"github.com/dfodeker/terminus/internal/service/stores"
+	"github.com/dfodeker/terminus/internal/service/tenants"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// seedMachineID is the GID machine ID seed generates IDs as, so they can't
+// collide with those of a server running against the same database
+const seedMachineID = 1023
+
+// openDatabase connects to the database at DB_URL, read from the
+// environment or .env as the server reads it. Migrations and seeding work
+// across tenants, so its connections bypass tenant isolation.
+func openDatabase() (*pgxpool.Pool, error) {
+	lookup, err := config.Env()
+	if err != nil {
+		return nil, err
+	}
+	dsn, ok := lookup("DB_URL")
+	if !ok || dsn == "" {
+		return nil, errors.New("DB_URL is required")
+	}
+	return dbpool.Open(context.Background(), dsn, dbpool.Config{Bypass: true})
+}
+
+// runMigrate applies or rolls back the migrations embedded in the binary
+func runMigrate(args []string) error {
+	name, args, err := subcommand(args, "up", "down", "status")
+	if err != nil {
+		return err
+	}
+	fs := flag.NewFlagSet("migrate "+name, flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	switch name {
+	case "up":
+		return migrate.Up(ctx, db)
+	case "down":
+		return migrate.Down(ctx, db)
+	default:
+		return migrate.Status(ctx, db)
+	}
+}
+
+// seedProducts are the products of the demo store
+var seedProducts = []products.CreateInput{
+	{Name: "Linen shirt", SKU: ptr("LIN-SHIRT"), Tags: ptr("apparel,summer"), InventoryTracked: true},
+	{Name: "Canvas tote", SKU: ptr("CAN-TOTE"), Tags: ptr("bags")},
+	{Name: "Enamel mug", SKU: ptr("ENA-MUG"), Tags: ptr("kitchen"), InventoryTracked: true},
+	{Name: "Wool beanie", SKU: ptr("WOL-BEANIE"), Tags: ptr("apparel,winter"), Status: products.StatusDraft},
+}
+
+// runSeed creates a verified user who owns a demo tenant with one store
+// of products. The user is created directly, since accounts made through
+// the API can't create tenants until they verify their email.
+func runSeed(args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	email := fs.String("email", "admin@example.com", "email of the demo user")
+	password := fs.String("password", "terminus-dev", "password of the demo user")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	q := database.New(db)
+	ctx := context.Background()
+
+	if _, err := q.GetUserByEmail(ctx, *email); err == nil {
+		return fmt.Errorf("%s already exists; the database has been seeded", *email)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	gids, err := gid.NewGenerator(seedMachineID)
+	if err != nil {
+		return err
+	}
+	hash, err := auth.HashPassword(*password)
+	if err != nil {
+		return err
+	}
+	user, err := q.CreateUser(ctx, database.CreateUserParams{Email: *email, HashedPassword: hash})
+	if err != nil {
+		return err
+	}
+	if _, err := q.MarkUserVerified(ctx, user.ID); err != nil {
+		return err
+	}
+
+	created, err := tenants.New(q, service.SQLTx[tenants.Queries](db, q), gids).Create(ctx, tenants.CreateInput{
+		Name:    "Demo",
+		OwnerID: user.ID,
+	})
+	if err != nil {
+		return err
+	}
+	store, err := stores.New(q, gids).Create(ctx, stores.CreateInput{
+		TenantID: created.Tenant.ID,
+		Name:     "Demo Store",
+	})
+	if err != nil {
+		return err
+	}
+	productSvc := products.New(q, gids)
+	for _, in := range seedProducts {
+		in.StoreID = store.ID
+		if _, err := productSvc.Create(ctx, in); err != nil {
+			return fmt.Errorf("create %s: %w", in.Name, err)
+		}
+	}
+
+	fmt.Printf("Seeded tenant %s with store %s (%d products)\n", created.Tenant.ID, store.ID, len(seedProducts))
+	fmt.Printf("Sign in with: terminus login -email %s (password %s)\n", *email, *password)
+	fmt.Printf("export TERMINUS_TENANT=%s TERMINUS_STORE=%s\n", created.Tenant.ID, store.ID)
+	return nil
+}
+
+// runServe runs the API server: the binary given with -bin, the api binary
+// installed beside terminus, or else go run . in the current directory,
+// which is what a checkout's backend directory needs
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	bin := fs.String("bin", "", "API server binary")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var name string
+	var cmdArgs []string
+	switch {
+	case *bin != "":
+		name = *bin
+	default:
+		if self, err := os.Executable(); err == nil {
+			beside := filepath.Join(filepath.Dir(self), "api")
+			if _, err := os.Stat(beside); err == nil {
+				name = beside
+			}
+		}
+		if name == "" {
+			name, cmdArgs = "go", []string{"run", "."}
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	server := exec.CommandContext(ctx, name, cmdArgs...)
+	server.Stdout, server.Stderr = os.Stdout, os.Stderr
+	// Pass the signal on so the server drains in-flight requests rather
+	// than being killed
+	server.Cancel = func() error { return server.Process.Signal(syscall.SIGTERM) }
+	server.WaitDelay = time.Minute
+	if err := server.Run(); err != nil && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
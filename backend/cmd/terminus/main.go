@@ -0,0 +1,220 @@
+// Command terminus administers a Terminus deployment and helps with local
+// development. API commands go through the client package as the user who
+// signed in with terminus login; migrate and seed work on the database at
+// DB_URL, and serve runs the API.
+//
+//	terminus login -email admin@example.com
+//	terminus tenants list
+//	terminus products create -tenant ID -store ID -name "Linen shirt"
+//	terminus import products -tenant ID -store ID -file products.csv
+//
+// -tenant and -store default to TERMINUS_TENANT and TERMINUS_STORE, so a
+// script can set them once.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dfodeker/terminus/client"
+)
+
+// defaultURL is the API of a server started with terminus serve
+const defaultURL = "http://localhost:8080"
+
+// command runs one subcommand with the arguments after its name
+type command struct {
+	run     func(args []string) error
+	summary string
+}
+
+var commands = map[string]command{
+	"login":    {runLogin, "sign in and save the session"},
+	"logout":   {runLogout, "forget the saved session"},
+	"tenants":  {runTenants, "list or create tenants"},
+	"stores":   {runStores, "list, create, update or delete a tenant's stores"},
+	"products": {runProducts, "list, show, create, update or delete a store's products"},
+	"import":   {runImport, "queue a product CSV import, or show one"},
+	"export":   {runExport, "queue a CSV export, or show one"},
+	"migrate":  {runMigrate, "apply or roll back database migrations"},
+	"seed":     {runSeed, "fill a development database with demo data"},
+	"serve":    {runServe, "run the API server"},
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "-h" || os.Args[1] == "help" {
+		usage()
+		return
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "terminus: unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd.run(os.Args[2:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "terminus:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(os.Stderr, "Usage: terminus <command> [arguments]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-9s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, `Run "terminus <command> -h" for a command's flags.`)
+}
+
+// session is what terminus login saves
+type session struct {
+	URL          string `json:"url"`
+	Email        string `json:"email"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// sessionPath is where the session is saved, readable only by the user
+func sessionPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "terminus", "session.json"), nil
+}
+
+// loadSession returns the saved session, or an empty one when there is
+// none. TERMINUS_URL and TERMINUS_TOKEN override what was saved.
+func loadSession() (session, error) {
+	var s session
+	path, err := sessionPath()
+	if err != nil {
+		return s, err
+	}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return s, err
+	default:
+		if err := json.Unmarshal(data, &s); err != nil {
+			return s, fmt.Errorf("read %s: %w", path, err)
+		}
+	}
+	if url := os.Getenv("TERMINUS_URL"); url != "" {
+		s.URL = url
+	}
+	if token := os.Getenv("TERMINUS_TOKEN"); token != "" {
+		s.Token = token
+	}
+	if s.URL == "" {
+		s.URL = defaultURL
+	}
+	return s, nil
+}
+
+func saveSession(s session) error {
+	path, err := sessionPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// apiClient returns a client signed in as the saved session
+func apiClient() (*client.Client, error) {
+	s, err := loadSession()
+	if err != nil {
+		return nil, err
+	}
+	if s.Token == "" {
+		return nil, errors.New("not signed in; run terminus login or set TERMINUS_TOKEN")
+	}
+	return client.New(s.URL, client.WithToken(s.Token)), nil
+}
+
+// printJSON writes v to stdout as indented JSON
+func printJSON(v any) error {
+	if raw, ok := v.(json.RawMessage); ok {
+		if len(raw) == 0 {
+			return nil
+		}
+		var decoded any
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			return err
+		}
+		v = decoded
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// stdin is shared so input read ahead for one prompt isn't lost to the
+// next
+var stdin = bufio.NewReader(os.Stdin)
+
+// prompt reads a line from stdin, asking for it by label unless label is
+// empty
+func prompt(label string) (string, error) {
+	if label != "" {
+		fmt.Fprint(os.Stderr, label+": ")
+	}
+	line, err := stdin.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// subcommand splits args into the name of a subcommand and its arguments
+func subcommand(args []string, names ...string) (string, []string, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return "", nil, fmt.Errorf("expected one of %s", strings.Join(names, ", "))
+	}
+	for _, name := range names {
+		if args[0] == name {
+			return name, args[1:], nil
+		}
+	}
+	return "", nil, fmt.Errorf("unknown subcommand %q; expected one of %s", args[0], strings.Join(names, ", "))
+}
+
+// required fails unless every named flag was given a value
+func required(fs *flag.FlagSet, names ...string) error {
+	var missing []string
+	for _, name := range names {
+		if fs.Lookup(name).Value.String() == "" {
+			missing = append(missing, "-"+name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s: %s is required", fs.Name(), strings.Join(missing, " and "))
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"github.com/dfodeker/terminus/internal/bulk"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/jobs"
+	"github.com/dfodeker/terminus/internal/orders"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/service/products"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/google/uuid"
+)
+
+// runBulkOperation applies a batch one mutation at a time. Like a Shopify
+// import, each mutation and the record of its outcome are written in one
+// transaction, so a retried job picks up where the last attempt stopped.
+func (d *handlerDeps) runBulkOperation(ctx context.Context, job jobs.Job, args bulk.Args) error {
+	op, err := d.db.GetBulkOperationForProcessing(ctx, args.OperationID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Tenant deleted before the job ran; nothing to do
+			return nil
+		}
+		return err
+	}
+	if op.Status == bulk.StatusCompleted || op.Status == bulk.StatusFailed {
+		return nil
+	}
+	if err := d.db.StartBulkOperation(ctx, op.ID); err != nil {
+		return err
+	}
+
+	var mutations []bulk.Mutation
+	if err := json.Unmarshal(op.Mutations, &mutations); err != nil {
+		// Checked when the batch was submitted, so this is unexpected, but
+		// retrying won't change it
+		return d.finishBulkOperation(ctx, op.ID, bulk.StatusFailed, err.Error())
+	}
+
+	done, err := d.db.ListBulkOperationResultPositions(ctx, op.ID)
+	if err != nil {
+		return err
+	}
+	recorded := make(map[int32]bool, len(done))
+	for _, n := range done {
+		recorded[n] = true
+	}
+
+	for i, m := range mutations {
+		if recorded[int32(i)] {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := d.applyBulkMutation(ctx, op.ID, int32(i), m); err != nil {
+			return err
+		}
+	}
+
+	if err := d.finishBulkOperation(ctx, op.ID, bulk.StatusCompleted, ""); err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "bulk operation completed",
+		"job_id", job.ID,
+		"operation_id", op.ID,
+		"tenant_id", op.TenantID,
+		"mutations", len(mutations),
+	)
+	return nil
+}
+
+func (d *handlerDeps) applyBulkMutation(ctx context.Context, operationID uuid.UUID, position int32, m bulk.Mutation) error {
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	qtx := d.db.WithTx(tx)
+
+	if err := d.applyMutation(ctx, qtx, m); err != nil {
+		fields, ok := mutationErrors(err)
+		if !ok {
+			return err
+		}
+		tx.Rollback(ctx)
+		return recordBulkResult(ctx, d.db, operationID, position, m, bulk.ResultFailed, fields)
+	}
+	if err := recordBulkResult(ctx, qtx, operationID, position, m, bulk.ResultSucceeded, nil); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// applyMutation makes one change through the same service or query its
+// own endpoint uses
+func (d *handlerDeps) applyMutation(ctx context.Context, q *database.Queries, m bulk.Mutation) error {
+	catalog := products.New(q, d.gids)
+	switch m.Op {
+	case bulk.OpProductSetStatus:
+		in, err := decodeMutation[bulk.ProductStatusInput](m)
+		if err != nil {
+			return err
+		}
+		_, err = catalog.SetStatus(ctx, m.StoreID, m.ID, in.Status)
+		return err
+	case bulk.OpProductUpdate:
+		in, err := decodeMutation[bulk.ProductUpdateInput](m)
+		if err != nil {
+			return err
+		}
+		_, err = catalog.Update(ctx, products.UpdateInput{
+			ID:               m.ID,
+			StoreID:          m.StoreID,
+			Name:             in.Name,
+			Description:      in.Description,
+			Tags:             in.Tags,
+			InventoryTracked: in.InventoryTracked,
+		})
+		return err
+	case bulk.OpProductDelete:
+		_, err := catalog.Delete(ctx, m.StoreID, m.ID)
+		return err
+	case bulk.OpVariantSetPrice:
+		in, err := decodeMutation[bulk.VariantPriceInput](m)
+		if err != nil {
+			return err
+		}
+		return setVariantPrice(ctx, q, m, in)
+	case bulk.OpOrderSetTags:
+		in, err := decodeMutation[bulk.OrderTagsInput](m)
+		if err != nil {
+			return err
+		}
+		tags, err := orders.NormalizeTags(in.Tags)
+		if err != nil {
+			return failItem("input.tags", validate.CodeInvalid, err.Error())
+		}
+		_, err = q.UpdateOrderTags(ctx, database.UpdateOrderTagsParams{
+			ID:      m.ID,
+			StoreID: m.StoreID,
+			Tags:    tags,
+		})
+		return service.NotFoundAs(err, "Order not found")
+	}
+	return failItem("op", validate.CodeOneOf, "is not a known mutation")
+}
+
+// decodeMutation reads a mutation's input. The batch was checked when it
+// was submitted, but an input that no longer decodes is still the
+// mutation's failure rather than the job's.
+func decodeMutation[T any](m bulk.Mutation) (T, error) {
+	in, err := bulk.Decode[T](m)
+	if err != nil {
+		return in, failItem("input", validate.CodeInvalid, err.Error())
+	}
+	return in, nil
+}
+
+func setVariantPrice(ctx context.Context, q *database.Queries, m bulk.Mutation, in bulk.VariantPriceInput) error {
+	variant, err := q.GetProductVariantByID(ctx, m.ID)
+	if err != nil {
+		return service.NotFoundAs(err, "Variant not found")
+	}
+	// Variants are looked up by ID alone, so check the store here
+	if variant.StoreID != m.StoreID {
+		return service.NotFound("Variant not found")
+	}
+
+	// Only the prices are set, so a concurrent edit to the rest stands
+	arg := database.UpdateProductVariantParams{
+		ID:         variant.ID,
+		ProductID:  variant.ProductID,
+		StoreID:    variant.StoreID,
+		PriceCents: sql.NullInt32{Int32: *in.PriceCents, Valid: true},
+	}
+	if in.CompareAtCents != nil {
+		arg.CompareAtCents = sql.NullInt32{Int32: *in.CompareAtCents, Valid: true}
+	}
+	_, err = q.UpdateProductVariant(ctx, arg)
+	return service.NotFoundAs(err, "Variant not found")
+}
+
+func recordBulkResult(ctx context.Context, q *database.Queries, operationID uuid.UUID, position int32, m bulk.Mutation, status string, errs []problem.FieldError) error {
+	if errs == nil {
+		errs = []problem.FieldError{}
+	}
+	data, err := json.Marshal(errs)
+	if err != nil {
+		return err
+	}
+	return q.CreateBulkOperationResult(ctx, database.CreateBulkOperationResultParams{
+		OperationID: operationID,
+		Position:    position,
+		Op:          m.Op,
+		StoreID:     m.StoreID,
+		TargetID:    m.ID,
+		Status:      status,
+		Errors:      data,
+	})
+}
+
+func (d *handlerDeps) finishBulkOperation(ctx context.Context, operationID uuid.UUID, status, message string) error {
+	return d.db.FinishBulkOperation(ctx, database.FinishBulkOperationParams{
+		ID:     operationID,
+		Status: status,
+		Error:  sql.NullString{String: message, Valid: message != ""},
+	})
+}
+
+// mutationErrors turns a rejection of a mutation into field errors named
+// as in the submitted batch, or reports false for failures worth retrying
+func mutationErrors(err error) ([]problem.FieldError, bool) {
+	var failure *itemFailure
+	if errors.As(err, &failure) {
+		return failure.fields, true
+	}
+	var invalid *validate.Error
+	if errors.As(err, &invalid) {
+		fields := make([]problem.FieldError, len(invalid.Fields))
+		for i, f := range invalid.Fields {
+			f.Field = "input." + f.Field
+			fields[i] = f
+		}
+		return fields, true
+	}
+	var svcErr *service.Error
+	if !errors.As(err, &svcErr) {
+		return nil, false
+	}
+	field, code := "input", svcErr.Code
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		field, code = "id", problem.CodeNotFound
+	case code == problem.CodeInvalidStatusTransition:
+		field = "input.status"
+	case code == problem.CodeHandleTaken:
+		field = "id"
+	case code == "" && errors.Is(err, service.ErrConflict):
+		code = problem.CodeConflict
+	case code == "":
+		code = validate.CodeInvalid
+	}
+	return []problem.FieldError{{Field: field, Code: code, Message: svcErr.Message}}, true
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/dfodeker/terminus/internal/jobs"
+	"github.com/dfodeker/terminus/internal/segments"
+)
+
+// recalculateCustomerGroup replaces the members of a rule group with the
+// customers matching its rules. A group deleted or made manual since the
+// job was queued is left alone.
+func (d *handlerDeps) recalculateCustomerGroup(ctx context.Context, job jobs.Job, args segments.RecalculateArgs) error {
+	n, err := d.db.RecalculateCustomerGroup(ctx, args.GroupID)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		slog.InfoContext(ctx, "customer group recalculated",
+			"job_id", job.ID,
+			"group_id", args.GroupID,
+		)
+	}
+	return nil
+}
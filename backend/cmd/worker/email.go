@@ -0,0 +1,24 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dfodeker/terminus/internal/jobs"
+	"github.com/dfodeker/terminus/internal/mailer"
+)
+
+// sendEmail delivers a queued message. Provider rejections are dead-lettered
+// straight away; outages and rate limits are retried with backoff.
+func (d *handlerDeps) sendEmail(ctx context.Context, job jobs.Job, args mailer.SendArgs) error {
+	if err := args.Message.Validate(); err != nil {
+		return jobs.Permanent(fmt.Errorf("invalid message: %w", err))
+	}
+
+	err := d.mailer.Send(ctx, args.Message)
+	if errors.Is(err, mailer.ErrRejected) {
+		return jobs.Permanent(err)
+	}
+	return err
+}
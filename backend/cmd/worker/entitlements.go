@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/service/entitlements"
+	"github.com/dfodeker/terminus/internal/service/products"
+)
+
+// limitedCatalog is the products service checking the store's product
+// limit before each product an import creates. Updates aren't limited, so
+// an import into a full store still updates the products it has.
+type limitedCatalog struct {
+	*products.Service
+	entitlements *entitlements.Service
+}
+
+func newLimitedCatalog(q *database.Queries, gids *gid.Generator) limitedCatalog {
+	return limitedCatalog{
+		Service:      products.New(q, gids),
+		entitlements: entitlements.New(q, entitlements.NewCatalog(nil)),
+	}
+}
+
+func (c limitedCatalog) Create(ctx context.Context, in products.CreateInput) (database.Product, error) {
+	if err := c.entitlements.CheckProducts(ctx, in.StoreID); err != nil {
+		return database.Product{}, err
+	}
+	return c.Service.Create(ctx, in)
+}
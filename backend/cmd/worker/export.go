@@ -0,0 +1,277 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+
+	"github.com/dfodeker/terminus/internal/catalog"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/export"
+	"github.com/dfodeker/terminus/internal/jobs"
+	"github.com/dfodeker/terminus/internal/orders"
+	"github.com/dfodeker/terminus/internal/segments"
+	"github.com/dfodeker/terminus/internal/storage"
+	"github.com/google/uuid"
+)
+
+// exportBatchSize is how many records are read from the database at once
+const exportBatchSize = 500
+
+// runExport writes an export to a temporary file a page at a time, then
+// stores it under a private key. A retried job starts over and overwrites
+// the same key.
+func (d *handlerDeps) runExport(ctx context.Context, job jobs.Job, args export.Args) error {
+	exp, err := d.db.GetExportForProcessing(ctx, args.ExportID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Store deleted before the job ran; nothing to do
+			return nil
+		}
+		return err
+	}
+	if exp.Status == export.StatusCompleted || exp.Status == export.StatusFailed {
+		return nil
+	}
+	if err := d.db.StartExport(ctx, exp.ID); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp("", "export-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	var rows int
+	switch exp.Resource {
+	case export.ResourceProducts:
+		rows, err = d.exportProducts(ctx, file, exp)
+	case export.ResourceOrders:
+		rows, err = d.exportOrders(ctx, file, exp)
+	case export.ResourceCustomers:
+		rows, err = d.exportCustomers(ctx, file, exp)
+	default:
+		err = fmt.Errorf("%w: unknown resource %q", export.ErrInvalid, exp.Resource)
+	}
+	if err != nil {
+		// The request was checked when the export was made, so a bad
+		// filter or column here won't get better with retries
+		if errors.Is(err, export.ErrInvalid) || errors.Is(err, catalog.ErrInvalidFilter) || errors.Is(err, orders.ErrInvalidFilter) || errors.Is(err, segments.ErrInvalidFilter) {
+			return d.db.FailExport(ctx, database.FailExportParams{ID: exp.ID, Error: sql.NullString{String: err.Error(), Valid: true}})
+		}
+		return err
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	key := exportKey(exp)
+	if err := d.storage.Put(ctx, key, export.ContentType(exp.Format), file, size); err != nil {
+		return fmt.Errorf("store export: %w", err)
+	}
+
+	err = d.db.CompleteExport(ctx, database.CompleteExportParams{
+		ID:         exp.ID,
+		StorageKey: sql.NullString{String: key, Valid: true},
+		RowCount:   sql.NullInt32{Int32: int32(rows), Valid: true},
+		SizeBytes:  sql.NullInt64{Int64: size, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "export completed",
+		"job_id", job.ID,
+		"export_id", exp.ID,
+		"store_id", exp.StoreID,
+		"resource", exp.Resource,
+		"rows", rows,
+		"size_bytes", size,
+	)
+	return nil
+}
+
+func exportKey(exp database.Export) string {
+	return fmt.Sprintf("%sstores/%s/exports/%s.%s", storage.PrivatePrefix, exp.StoreID, exp.ID, exp.Format)
+}
+
+// exportProducts writes the products matching the export's filters,
+// newest first like the list endpoint
+func (d *handlerDeps) exportProducts(ctx context.Context, w io.Writer, exp database.Export) (int, error) {
+	query, err := url.ParseQuery(exp.Filters)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", export.ErrInvalid, err)
+	}
+	filter, err := catalog.ParseFilter(query)
+	if err != nil {
+		return 0, err
+	}
+	columns, err := export.Select(export.ProductColumns, exp.Columns)
+	if err != nil {
+		return 0, err
+	}
+	ew, err := export.NewWriter(w, exp.Format, columns)
+	if err != nil {
+		return 0, err
+	}
+
+	params := database.ListFilteredProductsParams{
+		StoreID:  exp.StoreID,
+		Statuses: filter.Statuses,
+		Tags:     filter.Tags,
+		RowLimit: exportBatchSize,
+	}
+	if filter.MinPriceCents != nil {
+		params.MinPriceCents = sql.NullInt64{Int64: *filter.MinPriceCents, Valid: true}
+	}
+	if filter.MaxPriceCents != nil {
+		params.MaxPriceCents = sql.NullInt64{Int64: *filter.MaxPriceCents, Valid: true}
+	}
+	if filter.InStock != nil {
+		params.InStock = sql.NullBool{Bool: *filter.InStock, Valid: true}
+	}
+	if filter.CreatedAfter != nil {
+		params.CreatedAfter = sql.NullTime{Time: *filter.CreatedAfter, Valid: true}
+	}
+	if filter.CreatedBefore != nil {
+		params.CreatedBefore = sql.NullTime{Time: *filter.CreatedBefore, Valid: true}
+	}
+	if filter.ChannelID != nil {
+		params.ChannelID = uuid.NullUUID{UUID: *filter.ChannelID, Valid: true}
+	}
+
+	count := 0
+	for {
+		batch, err := d.db.ListFilteredProducts(ctx, params)
+		if err != nil {
+			return 0, err
+		}
+		for _, p := range batch {
+			if err := ew.Write(p); err != nil {
+				return 0, err
+			}
+		}
+		count += len(batch)
+		if len(batch) < exportBatchSize {
+			break
+		}
+		last := batch[len(batch)-1]
+		params.HasCursor, params.CursorCreatedAt, params.CursorID = true, last.CreatedAt, last.ID
+	}
+	return count, ew.Flush()
+}
+
+// exportOrders writes the orders matching the export's filters, newest
+// first like the list endpoint
+func (d *handlerDeps) exportOrders(ctx context.Context, w io.Writer, exp database.Export) (int, error) {
+	query, err := url.ParseQuery(exp.Filters)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", export.ErrInvalid, err)
+	}
+	filter, err := orders.ParseFilter(query)
+	if err != nil {
+		return 0, err
+	}
+	columns, err := export.Select(export.OrderColumns, exp.Columns)
+	if err != nil {
+		return 0, err
+	}
+	ew, err := export.NewWriter(w, exp.Format, columns)
+	if err != nil {
+		return 0, err
+	}
+
+	params := database.ListFilteredOrdersParams{
+		StoreID:             exp.StoreID,
+		Tags:                filter.Tags,
+		Statuses:            filter.Statuses,
+		FinancialStatuses:   filter.FinancialStatuses,
+		FulfillmentStatuses: filter.FulfillmentStatuses,
+		RowLimit:            exportBatchSize,
+	}
+
+	count := 0
+	for {
+		batch, err := d.db.ListFilteredOrders(ctx, params)
+		if err != nil {
+			return 0, err
+		}
+		for _, o := range batch {
+			if err := ew.Write(o); err != nil {
+				return 0, err
+			}
+		}
+		count += len(batch)
+		if len(batch) < exportBatchSize {
+			break
+		}
+		last := batch[len(batch)-1]
+		params.HasCursor, params.CursorPlacedAt, params.CursorID = true, last.PlacedAt, last.ID
+	}
+	return count, ew.Flush()
+}
+
+// exportCustomers writes the customers matching the export's filters,
+// newest first like the list endpoint. Filtering by group and marketing
+// consent gives a mailing list.
+func (d *handlerDeps) exportCustomers(ctx context.Context, w io.Writer, exp database.Export) (int, error) {
+	query, err := url.ParseQuery(exp.Filters)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", export.ErrInvalid, err)
+	}
+	filter, err := segments.ParseFilter(query)
+	if err != nil {
+		return 0, err
+	}
+	columns, err := export.Select(export.CustomerColumns, exp.Columns)
+	if err != nil {
+		return 0, err
+	}
+	ew, err := export.NewWriter(w, exp.Format, columns)
+	if err != nil {
+		return 0, err
+	}
+
+	params := database.ListFilteredCustomersParams{
+		StoreID:  exp.StoreID,
+		Tags:     filter.Tags,
+		RowLimit: exportBatchSize,
+	}
+	if filter.AcceptsMarketing != nil {
+		params.AcceptsMarketing = sql.NullBool{Bool: *filter.AcceptsMarketing, Valid: true}
+	}
+	if filter.GroupID != nil {
+		params.GroupID = uuid.NullUUID{UUID: *filter.GroupID, Valid: true}
+	}
+
+	count := 0
+	for {
+		batch, err := d.db.ListFilteredCustomers(ctx, params)
+		if err != nil {
+			return 0, err
+		}
+		for _, c := range batch {
+			if err := ew.Write(c); err != nil {
+				return 0, err
+			}
+		}
+		count += len(batch)
+		if len(batch) < exportBatchSize {
+			break
+		}
+		last := batch[len(batch)-1]
+		params.HasCursor, params.CursorCreatedAt, params.CursorID = true, last.CreatedAt, last.ID
+	}
+	return count, ew.Flush()
+}
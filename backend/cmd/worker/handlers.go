@@ -0,0 +1,41 @@
+package main
+
+import (
+	"github.com/dfodeker/terminus/internal/crypto"
+	"github.com/dfodeker/terminus/internal/crypto/keystore"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/jobs"
+	"github.com/dfodeker/terminus/internal/mailer"
+	"github.com/dfodeker/terminus/internal/storage"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// handlerDeps are the shared dependencies job handlers close over
+type handlerDeps struct {
+	db              *database.Queries
+	pool            *pgxpool.Pool
+	jobs            *jobs.Client
+	storage         storage.Storage
+	thumbnailWidths []int
+	mailer          mailer.Sender
+	gids            *gid.Generator
+	keyring         *crypto.Keyring
+	resealer        *keystore.Resealer
+}
+
+// registerHandlers wires every job kind the worker knows how to run.
+// New background work is added here alongside its jobs.Args type.
+func registerHandlers(w *jobs.Worker, deps *handlerDeps) {
+	jobs.Register(w, deps.generateThumbnails)
+	jobs.Register(w, deps.sendEmail)
+	jobs.Register(w, deps.sendOrderEmail)
+	jobs.Register(w, deps.importProducts)
+	jobs.Register(w, deps.runExport)
+	jobs.Register(w, deps.importShopify)
+	jobs.Register(w, deps.fetchMedia)
+	jobs.Register(w, deps.runBulkOperation)
+	jobs.Register(w, deps.recalculateCustomerGroup)
+	jobs.Register(w, deps.runPrivacyRequest)
+	jobs.Register(w, deps.reseal)
+}
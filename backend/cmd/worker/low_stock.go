@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dfodeker/terminus/internal/inventory"
+	"github.com/dfodeker/terminus/internal/mailer"
+	"github.com/dfodeker/terminus/internal/notifications"
+	"github.com/dfodeker/terminus/internal/service/stores"
+	"github.com/google/uuid"
+)
+
+// notifyLowStock tells a store's staff about variants that went low: in the
+// notification center, and by email when the store has low stock alerts
+// turned on
+func (d *handlerDeps) notifyLowStock(ctx context.Context, tenantID, storeID uuid.UUID, items []inventory.LowStock) error {
+	store, err := d.db.GetStoreByID(ctx, storeID)
+	if err != nil {
+		return err
+	}
+
+	variantIDs := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		variantIDs = append(variantIDs, item.Alert.VariantID)
+	}
+	title := fmt.Sprintf("%d variants are low on stock", len(items))
+	if len(items) == 1 {
+		title = fmt.Sprintf("%s is low on stock", lowStockName(items[0]))
+	}
+	_, err = notifications.Record(ctx, d.db, notifications.Notification{
+		TenantID: tenantID,
+		StoreID:  storeID,
+		Kind:     notifications.KindLowStock,
+		Title:    title,
+		Body:     fmt.Sprintf("At or below their reorder point in %s", store.Name),
+		Data:     map[string]any{"variant_ids": variantIDs},
+	})
+	if err != nil {
+		return err
+	}
+
+	settings, err := stores.New(d.db, d.gids).Settings(ctx, tenantID, storeID)
+	if err != nil {
+		return err
+	}
+	if !settings.Notifications.LowStockAlerts || settings.Notifications.StaffEmail == "" {
+		return nil
+	}
+
+	data := mailer.LowStockData{StoreName: store.Name}
+	for _, item := range items {
+		data.Items = append(data.Items, mailer.LowStockItem{
+			Title:        item.ProductName,
+			VariantTitle: item.VariantTitle,
+			SKU:          item.SKU,
+			LocationName: item.LocationName,
+			OnHand:       item.Alert.OnHand,
+			Reorder:      item.Reorder,
+		})
+	}
+	msg, err := mailer.Render(mailer.TemplateLowStock, data)
+	if err != nil {
+		return err
+	}
+	msg.To = []string{settings.Notifications.StaffEmail}
+	return mailer.Enqueue(ctx, d.jobs, d.db, msg)
+}
+
+func lowStockName(item inventory.LowStock) string {
+	if item.VariantTitle == "" {
+		return item.ProductName
+	}
+	return item.ProductName + " (" + item.VariantTitle + ")"
+}
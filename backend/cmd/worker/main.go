@@ -1,14 +1,393 @@
 package main
 
 import (
+	"context"
 	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
+
+	"github.com/dfodeker/terminus/internal/analytics"
+	"github.com/dfodeker/terminus/internal/audit"
+	"github.com/dfodeker/terminus/internal/billing"
+	"github.com/dfodeker/terminus/internal/config"
+	"github.com/dfodeker/terminus/internal/crypto"
+	"github.com/dfodeker/terminus/internal/crypto/keystore"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/dbpool"
+	"github.com/dfodeker/terminus/internal/feeds"
+	"github.com/dfodeker/terminus/internal/fx"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/inventory"
+	"github.com/dfodeker/terminus/internal/jobs"
+	"github.com/dfodeker/terminus/internal/lockout"
+	"github.com/dfodeker/terminus/internal/mailer"
+	"github.com/dfodeker/terminus/internal/metrics"
+	"github.com/dfodeker/terminus/internal/notifications"
+	"github.com/dfodeker/terminus/internal/payments"
+	"github.com/dfodeker/terminus/internal/search"
+	"github.com/dfodeker/terminus/internal/segments"
+	"github.com/dfodeker/terminus/internal/service/products"
+	"github.com/dfodeker/terminus/internal/service/stores"
+	"github.com/dfodeker/terminus/internal/storage"
+	"github.com/dfodeker/terminus/internal/subscriptions"
+	"github.com/dfodeker/terminus/internal/tracing"
+	"github.com/dfodeker/terminus/internal/usage"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 func main() {
-	log.Println("worker started")
-	for {
-		log.Println("worker tick (placeholder)")
-		time.Sleep(5 * time.Second)
+	lookup, err := config.Env()
+	if err != nil {
+		log.Fatal(err)
+	}
+	cfg, err := config.LoadWorker(lookup)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		log.Fatalf("Failed to configure tracing: %s", err)
+	}
+
+	// Jobs run across tenants, so the worker's connections bypass tenant
+	// isolation
+	cfg.DB.Bypass = true
+	db, err := dbpool.Open(context.Background(), cfg.DatabaseURL, cfg.DB)
+	if err != nil {
+		log.Fatalf("Error Loading DB, %s", err)
+	}
+	defer db.Close()
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+	}))
+	slog.SetDefault(logger)
+
+	// Jobs read and write sealed personal data, so the keyring is loaded
+	// before any of them run
+	_, keyring, err := keystore.Open(context.Background(), database.New(db), cfg.Encryption)
+	if err != nil {
+		log.Fatalf("Failed to load encryption keys: %s", err)
+	}
+	crypto.SetDefault(keyring)
+	resealer := keystore.NewResealer(database.New(db), keyring, keystore.ResealerConfig{Logger: logger})
+
+	mediaStorage, err := storage.New(cfg.Storage)
+	if err != nil {
+		log.Fatalf("Failed to configure storage: %s", err)
+	}
+
+	mailSender, err := mailer.New(cfg.Mail, logger)
+	if err != nil {
+		log.Fatalf("Failed to configure mail: %s", err)
+	}
+
+	gidGen, err := gid.NewGenerator(cfg.MachineID)
+	if err != nil {
+		log.Fatalf("Failed to create GID generator: %s", err)
+	}
+
+	worker := jobs.NewWorker(database.New(db), jobs.WorkerConfig{
+		Queue:        cfg.Worker.Queue,
+		Concurrency:  cfg.Worker.Concurrency,
+		DrainTimeout: cfg.ShutdownTimeout,
+		Logger:       logger,
+	})
+	deps := &handlerDeps{
+		db:              database.New(db),
+		pool:            db,
+		jobs:            jobs.NewClient(database.New(db)),
+		storage:         mediaStorage,
+		thumbnailWidths: cfg.Worker.ThumbnailWidths,
+		mailer:          mailSender,
+		gids:            gidGen,
+		keyring:         keyring,
+		resealer:        resealer,
+	}
+	registerHandlers(worker, deps)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Business metrics counted here, such as renewal orders and declined
+	// charges, are scraped from the worker itself
+	metrics.Configure(cfg.Metrics)
+	metrics.Register(prometheus.DefaultRegisterer)
+	dbpool.Register(prometheus.DefaultRegisterer, "terminus", db)
+	if cfg.Worker.MetricsPort != "" {
+		metricsSrv := &http.Server{
+			Addr:              ":" + cfg.Worker.MetricsPort,
+			Handler:           promhttp.Handler(),
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		go func() {
+			logger.Info("metrics listening", "addr", metricsSrv.Addr)
+			if err := metricsSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("metrics listener failed", "error", err)
+			}
+		}()
+		defer metricsSrv.Close()
+	}
+
+	// The search indexer relays product outbox events alongside the job loop
+	searchEngine, err := search.New(database.New(db), cfg.Search)
+	if err != nil {
+		log.Fatalf("Failed to configure search: %s", err)
+	}
+	indexer := search.NewIndexer(db, searchEngine, search.IndexerConfig{Logger: logger})
+	indexerDone := make(chan struct{})
+	go func() {
+		defer close(indexerDone)
+		if err := indexer.Run(ctx); err != nil {
+			logger.Error("search indexer failed", "error", err)
+		}
+	}()
+
+	// Old audit log entries are pruned on the same schedule everywhere a
+	// worker runs
+	pruner := audit.NewPruner(database.New(db), audit.PrunerConfig{
+		Retention: cfg.Worker.AuditRetention,
+		Logger:    logger,
+	})
+	prunerDone := make(chan struct{})
+	go func() {
+		defer close(prunerDone)
+		if err := pruner.Run(ctx); err != nil {
+			logger.Error("audit log pruner failed", "error", err)
+		}
+	}()
+
+	// Old staff notifications are pruned the same way
+	notificationPruner := notifications.NewPruner(database.New(db), notifications.PrunerConfig{
+		Retention: cfg.Worker.NotificationRetention,
+		Logger:    logger,
+	})
+	notificationPrunerDone := make(chan struct{})
+	go func() {
+		defer close(notificationPrunerDone)
+		if err := notificationPruner.Run(ctx); err != nil {
+			logger.Error("notification pruner failed", "error", err)
+		}
+	}()
+
+	// Failed sign-in counts are dropped once they have gone quiet
+	throttlePruner := lockout.NewPruner(database.New(db), lockout.PrunerConfig{Logger: logger})
+	throttlePrunerDone := make(chan struct{})
+	go func() {
+		defer close(throttlePrunerDone)
+		if err := throttlePruner.Run(ctx); err != nil {
+			logger.Error("login throttle pruner failed", "error", err)
+		}
+	}()
+
+	// Stored idempotency keys are dropped once they can't be replayed
+	idempotencyPruner := middleware.NewIdempotencyPruner(database.New(db), middleware.IdempotencyPrunerConfig{Logger: logger})
+	idempotencyPrunerDone := make(chan struct{})
+	go func() {
+		defer close(idempotencyPrunerDone)
+		if err := idempotencyPruner.Run(ctx); err != nil {
+			logger.Error("idempotency key pruner failed", "error", err)
+		}
+	}()
+
+	// Deleted products and variants are purged once they can no longer be
+	// restored
+	purger := products.NewPurger(database.New(db), products.PurgerConfig{
+		Retention: cfg.Worker.DeletedProductRetention,
+		Logger:    logger,
+	})
+	purgerDone := make(chan struct{})
+	go func() {
+		defer close(purgerDone)
+		if err := purger.Run(ctx); err != nil {
+			logger.Error("deleted product purger failed", "error", err)
+		}
+	}()
+
+	// Deleted stores likewise, with everything in them
+	storePurger := stores.NewPurger(database.New(db), stores.PurgerConfig{
+		Retention: cfg.Worker.DeletedStoreRetention,
+		Logger:    logger,
+	})
+	storePurgerDone := make(chan struct{})
+	go func() {
+		defer close(storePurgerDone)
+		if err := storePurger.Run(ctx); err != nil {
+			logger.Error("deleted store purger failed", "error", err)
+		}
+	}()
+
+	// Subscriptions due a renewal are charged and their orders placed
+	biller := subscriptions.NewBiller(db, payments.Default(), gidGen, subscriptions.BillerConfig{
+		RetryDelay:  cfg.Worker.SubscriptionRetryDelay,
+		MaxAttempts: cfg.Worker.SubscriptionMaxAttempts,
+		OnOrder:     deps.renewalPlaced,
+		Logger:      logger,
+	})
+	billerDone := make(chan struct{})
+	go func() {
+		defer close(billerDone)
+		if err := biller.Run(ctx); err != nil {
+			logger.Error("subscription biller failed", "error", err)
+		}
+	}()
+
+	// Marketplace product feeds are regenerated as they come due
+	feedGenerator := feeds.NewGenerator(database.New(db), mediaStorage, feeds.GeneratorConfig{Logger: logger})
+	feedsDone := make(chan struct{})
+	go func() {
+		defer close(feedsDone)
+		if err := feedGenerator.Run(ctx); err != nil {
+			logger.Error("product feed generator failed", "error", err)
+		}
+	}()
+
+	// Rule customer groups are recalculated as they go stale, picking up
+	// new orders and tags between recalculations staff ask for
+	refresher := segments.NewRefresher(database.New(db), segments.RefresherConfig{
+		StaleAfter: cfg.Worker.CustomerGroupRefreshInterval,
+		Logger:     logger,
+	})
+	segmentsDone := make(chan struct{})
+	go func() {
+		defer close(segmentsDone)
+		if err := refresher.Run(ctx); err != nil {
+			logger.Error("customer group refresher failed", "error", err)
+		}
+	}()
+
+	// Reservations of abandoned checkouts give their stock back
+	expirer := inventory.NewExpirer(db, inventory.ExpirerConfig{Logger: logger})
+	expirerDone := make(chan struct{})
+	go func() {
+		defer close(expirerDone)
+		if err := expirer.Run(ctx); err != nil {
+			logger.Error("inventory reservation expirer failed", "error", err)
+		}
+	}()
+
+	// Stock levels are checked against their reorder points, and staff
+	// told about the variants that went low
+	stockMonitor := inventory.NewMonitor(db, inventory.MonitorConfig{
+		Interval: cfg.Worker.LowStockCheckInterval,
+		Notify:   deps.notifyLowStock,
+		Logger:   logger,
+	})
+	stockMonitorDone := make(chan struct{})
+	go func() {
+		defer close(stockMonitorDone)
+		if err := stockMonitor.Run(ctx); err != nil {
+			logger.Error("low stock monitor failed", "error", err)
+		}
+	}()
+
+	// Analytics rollups are rebuilt nightly in each store's timezone
+	roller := analytics.NewRoller(db, analytics.RollerConfig{Logger: logger})
+	rollerDone := make(chan struct{})
+	go func() {
+		defer close(rollerDone)
+		if err := roller.Run(ctx); err != nil {
+			logger.Error("analytics roller failed", "error", err)
+		}
+	}()
+
+	// Tenants whose subscription stayed unpaid past its grace period drop
+	// to the free plan
+	lapser := billing.NewLapser(db, billing.LapserConfig{Logger: logger})
+	lapserDone := make(chan struct{})
+	go func() {
+		defer close(lapserDone)
+		if err := lapser.Run(ctx); err != nil {
+			logger.Error("billing lapser failed", "error", err)
+		}
+	}()
+
+	// Orders and stored media are counted into each tenant's usage
+	tallier := usage.NewTallier(database.New(db), usage.TallierConfig{Logger: logger})
+	tallierDone := make(chan struct{})
+	go func() {
+		defer close(tallierDone)
+		if err := tallier.Run(ctx); err != nil {
+			logger.Error("usage tally failed", "error", err)
+		}
+	}()
+
+	// Use over each plan's allowance is billed once a month ends, when
+	// Stripe is configured
+	reporterDone := make(chan struct{})
+	if cfg.Billing.SecretKey != "" {
+		reporter := usage.NewReporter(db, billing.New(cfg.Billing), usage.ReporterConfig{Logger: logger})
+		go func() {
+			defer close(reporterDone)
+			if err := reporter.Run(ctx); err != nil {
+				logger.Error("usage overage report failed", "error", err)
+			}
+		}()
+	} else {
+		close(reporterDone)
+	}
+
+	// Values under a retired data key, or written before encryption was
+	// turned on, are resealed under the active one
+	resealerDone := make(chan struct{})
+	go func() {
+		defer close(resealerDone)
+		if err := resealer.Run(ctx); err != nil {
+			logger.Error("encryption resealer failed", "error", err)
+		}
+	}()
+
+	// Exchange rates for presentment prices, when a feed is configured
+	ratesDone := make(chan struct{})
+	if cfg.Worker.FX.RatesURL != "" {
+		rates := fx.NewSyncer(database.New(db), cfg.Worker.FX, logger)
+		go func() {
+			defer close(ratesDone)
+			if err := rates.Run(ctx); err != nil {
+				logger.Error("exchange rate sync failed", "error", err)
+			}
+		}()
+	} else {
+		close(ratesDone)
+	}
+
+	// Run returns once in-flight jobs have drained; the indexer, pruners,
+	// purgers, biller, feed generator, group refresher, reservation expirer,
+	// stock monitor, analytics roller, billing lapser, usage tallier,
+	// overage reporter, resealer and rate sync are waited for too so none
+	// is cut off by the deferred db.Close
+	if err := worker.Run(ctx); err != nil {
+		log.Fatalf("worker: %s", err)
+	}
+	<-indexerDone
+	<-prunerDone
+	<-notificationPrunerDone
+	<-throttlePrunerDone
+	<-idempotencyPrunerDone
+	<-purgerDone
+	<-storePurgerDone
+	<-billerDone
+	<-feedsDone
+	<-segmentsDone
+	<-expirerDone
+	<-stockMonitorDone
+	<-rollerDone
+	<-lapserDone
+	<-tallierDone
+	<-reporterDone
+	<-resealerDone
+	<-ratesDone
+
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFlush()
+	if err := shutdownTracing(flushCtx); err != nil {
+		logger.Error("failed to flush traces", "error", err)
 	}
 }
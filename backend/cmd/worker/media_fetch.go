@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/jobs"
+	"github.com/dfodeker/terminus/internal/media"
+)
+
+// fetchClient downloads images named in uploaded files. Those URLs come
+// from merchants, so it only dials public addresses; a file can't point
+// the worker at the database or a cloud metadata endpoint.
+var fetchClient = &http.Client{
+	Timeout: 60 * time.Second,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 10 * time.Second,
+			Control: publicAddressesOnly,
+		}).DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+	},
+}
+
+func publicAddressesOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return fmt.Errorf("refusing to fetch from %s", host)
+	}
+	return nil
+}
+
+// fetchMedia copies an image from the web into storage for a pending media
+// row, marks it ready and queues its thumbnails, as confirming an upload
+// does
+func (d *handlerDeps) fetchMedia(ctx context.Context, job jobs.Job, args media.FetchArgs) error {
+	m, err := d.db.GetProductMediaForProcessing(ctx, args.MediaID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Deleted before the job ran; nothing to do
+			return nil
+		}
+		return err
+	}
+	if m.Status == "ready" {
+		return nil
+	}
+
+	src, err := url.Parse(args.SourceURL)
+	if err != nil || src.Scheme != "https" {
+		return jobs.Permanent(fmt.Errorf("media %s: source must be an https URL", m.ID))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src.String(), nil)
+	if err != nil {
+		return jobs.Permanent(err)
+	}
+	resp, err := fetchClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		return fmt.Errorf("media %s: source returned %s", m.ID, resp.Status)
+	default:
+		return jobs.Permanent(fmt.Errorf("media %s: source returned %s", m.ID, resp.Status))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, media.MaxBytes+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > media.MaxBytes {
+		return jobs.Permanent(fmt.Errorf("media %s is larger than %d bytes", m.ID, media.MaxBytes))
+	}
+	if detected := http.DetectContentType(data); detected != m.ContentType {
+		return jobs.Permanent(fmt.Errorf("media %s: expected %s, got %s", m.ID, m.ContentType, detected))
+	}
+
+	if err := d.storage.Put(ctx, m.StorageKey, m.ContentType, bytes.NewReader(data), int64(len(data))); err != nil {
+		return fmt.Errorf("store media: %w", err)
+	}
+
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	qtx := d.db.WithTx(tx)
+
+	_, err = qtx.MarkProductMediaReady(ctx, database.MarkProductMediaReadyParams{
+		ID:        m.ID,
+		ProductID: m.ProductID,
+		SizeBytes: sql.NullInt64{Int64: int64(len(data)), Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+	// Thumbnails are rendered in the background; until then clients fall
+	// back to the original
+	if media.CanThumbnail(m.ContentType) {
+		if _, err := d.jobs.EnqueueTx(ctx, qtx, media.ThumbnailArgs{MediaID: m.ID}); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "media fetched",
+		"job_id", job.ID,
+		"media_id", m.ID,
+		"product_id", m.ProductID,
+		"size_bytes", len(data),
+	)
+	return nil
+}
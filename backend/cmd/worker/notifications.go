@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/notifications"
+)
+
+// renewalPlaced tells the customer and the store's staff about a
+// subscription renewal's order
+func (d *handlerDeps) renewalPlaced(ctx context.Context, q *database.Queries, order database.Order) error {
+	if err := d.queueRenewalEmails(ctx, q, order); err != nil {
+		return err
+	}
+	_, err := notifications.Record(ctx, q, notifications.Notification{
+		TenantID: order.TenantID,
+		StoreID:  order.StoreID,
+		Kind:     notifications.KindNewOrder,
+		Title:    fmt.Sprintf("New order #%d", order.OrderNumber),
+		Body:     fmt.Sprintf("Subscription renewal for %d.%02d %s", order.TotalCents/100, order.TotalCents%100, order.Currency),
+		Data:     map[string]any{"order_id": order.ID},
+	})
+	return err
+}
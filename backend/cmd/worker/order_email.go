@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/jobs"
+	"github.com/dfodeker/terminus/internal/mailer"
+	"github.com/dfodeker/terminus/internal/service/stores"
+)
+
+// sendOrderEmail renders an order email in the store's wording and queues
+// it for delivery. Orders without an email address, and emails the store
+// has turned off, are skipped.
+func (d *handlerDeps) sendOrderEmail(ctx context.Context, job jobs.Job, args mailer.OrderEmailArgs) error {
+	order, err := d.db.GetOrderByID(ctx, database.GetOrderByIDParams{ID: args.OrderID, StoreID: args.StoreID})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !order.Email.Valid || order.Email.String == "" {
+		return nil
+	}
+
+	settings, err := stores.New(d.db, d.gids).Settings(ctx, order.TenantID, order.StoreID)
+	if err != nil {
+		return err
+	}
+	var enabled bool
+	switch args.Template {
+	case mailer.TemplateOrderConfirmation:
+		enabled = settings.Notifications.OrderConfirmation
+	case mailer.TemplateOrderPaid:
+		enabled = settings.Notifications.PaymentReceipts
+	case mailer.TemplateOrderShipped:
+		enabled = settings.Notifications.ShippingUpdates
+	case mailer.TemplateOrderRefunded:
+		enabled = settings.Notifications.RefundReceipts
+	default:
+		return jobs.Permanent(fmt.Errorf("%q is not an order email", args.Template))
+	}
+	if !enabled {
+		return nil
+	}
+
+	data, err := d.orderEmailData(ctx, order, args)
+	if err != nil {
+		return err
+	}
+
+	var custom mailer.Custom
+	row, err := d.db.GetStoreEmailTemplate(ctx, database.GetStoreEmailTemplateParams{
+		StoreID:  order.StoreID,
+		Template: string(args.Template),
+	})
+	if err == nil {
+		custom = mailer.Custom{Subject: row.Subject, Text: row.BodyText, HTML: row.BodyHtml}
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	msg, err := mailer.RenderOrder(args.Template, data, custom)
+	if err != nil {
+		return jobs.Permanent(err)
+	}
+	msg.To = []string{order.Email.String}
+	msg.ReplyTo = settings.Notifications.StaffEmail
+	return mailer.Enqueue(ctx, d.jobs, d.db, msg)
+}
+
+// queueRenewalEmails queues the confirmation of a subscription renewal's
+// order, and its payment receipt once the charge has gone through
+func (d *handlerDeps) queueRenewalEmails(ctx context.Context, q *database.Queries, order database.Order) error {
+	_, err := d.jobs.EnqueueTx(ctx, q, mailer.OrderEmailArgs{
+		Template: mailer.TemplateOrderConfirmation,
+		OrderID:  order.ID,
+		StoreID:  order.StoreID,
+	})
+	if err != nil || order.FinancialStatus != "paid" {
+		return err
+	}
+	_, err = d.jobs.EnqueueTx(ctx, q, mailer.OrderEmailArgs{
+		Template:    mailer.TemplateOrderPaid,
+		OrderID:     order.ID,
+		StoreID:     order.StoreID,
+		AmountCents: order.TotalCents,
+	})
+	return err
+}
+
+// orderEmailData fills an order email from the order and the shipment or
+// refund it is about
+func (d *handlerDeps) orderEmailData(ctx context.Context, order database.Order, args mailer.OrderEmailArgs) (mailer.OrderData, error) {
+	store, err := d.db.GetStoreByID(ctx, order.StoreID)
+	if err != nil {
+		return mailer.OrderData{}, err
+	}
+	data := mailer.OrderData{
+		StoreName:     store.Name,
+		OrderNumber:   order.OrderNumber,
+		Currency:      order.Currency,
+		SubtotalCents: order.SubtotalCents,
+		DiscountCents: order.DiscountCents,
+		ShippingCents: order.ShippingCents,
+		TaxCents:      order.TaxCents,
+		TotalCents:    order.TotalCents,
+		AmountCents:   args.AmountCents,
+	}
+
+	if order.CustomerID.Valid {
+		customer, err := d.db.GetCustomerByID(ctx, database.GetCustomerByIDParams{ID: order.CustomerID.UUID, StoreID: order.StoreID})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return mailer.OrderData{}, err
+		}
+		data.CustomerName = strings.TrimSpace(customer.FirstName.String + " " + customer.LastName.String)
+	}
+
+	lines, err := d.db.GetOrderLineItems(ctx, order.ID)
+	if err != nil {
+		return mailer.OrderData{}, err
+	}
+	for _, line := range lines {
+		// Bundle components are listed under their bundle's line
+		if line.ParentLineItemID.Valid {
+			continue
+		}
+		data.Items = append(data.Items, mailer.OrderItem{
+			Title:        line.Title,
+			VariantTitle: line.VariantTitle.String,
+			Quantity:     line.Quantity,
+			TotalCents:   line.TotalCents,
+		})
+	}
+
+	switch args.Template {
+	case mailer.TemplateOrderShipped:
+		f, err := d.db.GetFulfillmentByID(ctx, database.GetFulfillmentByIDParams{ID: args.FulfillmentID, OrderID: order.ID})
+		if err != nil {
+			return mailer.OrderData{}, fmt.Errorf("fulfillment %s: %w", args.FulfillmentID, err)
+		}
+		data.Carrier = f.Carrier.String
+		data.TrackingNumber = f.TrackingNumber.String
+		data.TrackingURL = f.TrackingUrl.String
+	case mailer.TemplateOrderRefunded:
+		rf, err := d.db.GetRefundByID(ctx, database.GetRefundByIDParams{ID: args.RefundID, OrderID: order.ID})
+		if err != nil {
+			return mailer.OrderData{}, fmt.Errorf("refund %s: %w", args.RefundID, err)
+		}
+		data.AmountCents = rf.AmountCents
+		data.RefundReason = rf.Reason.String
+	}
+	return data, nil
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/jobs"
+	"github.com/dfodeker/terminus/internal/privacy"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/storage"
+)
+
+// runPrivacyRequest writes a subject's data export or erases them. An
+// erasure that became impossible after it was asked for, such as a
+// customer who has since placed an order, fails with the reason rather
+// than retrying.
+func (d *handlerDeps) runPrivacyRequest(ctx context.Context, job jobs.Job, args privacy.Args) error {
+	req, err := d.db.GetPrivacyRequestForProcessing(ctx, args.RequestID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Subject or store deleted before the job ran; nothing to do
+			return nil
+		}
+		return err
+	}
+	if req.Status == privacy.StatusCompleted || req.Status == privacy.StatusFailed {
+		return nil
+	}
+	if err := d.db.StartPrivacyRequest(ctx, req.ID); err != nil {
+		return err
+	}
+
+	if req.Kind == privacy.KindExport {
+		err = d.exportPrivacyData(ctx, req)
+	} else {
+		err = d.erasePrivacySubject(ctx, req)
+	}
+	if err != nil {
+		if errors.Is(err, service.ErrConflict) {
+			return d.db.FailPrivacyRequest(ctx, database.FailPrivacyRequestParams{
+				ID:    req.ID,
+				Error: sql.NullString{String: err.Error(), Valid: true},
+			})
+		}
+		return err
+	}
+
+	slog.InfoContext(ctx, "privacy request completed",
+		"job_id", job.ID,
+		"privacy_request_id", req.ID,
+		"kind", req.Kind,
+		"customer_id", req.CustomerID.UUID,
+		"user_id", req.UserID.UUID,
+	)
+	return nil
+}
+
+// exportPrivacyData writes the subject's archive to a temporary file, then
+// stores it under a private key. A retried job overwrites the same key.
+func (d *handlerDeps) exportPrivacyData(ctx context.Context, req database.PrivacyRequest) error {
+	file, err := os.CreateTemp("", "privacy-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	archive := privacy.NewArchive(file)
+	if req.CustomerID.Valid {
+		var customer database.Customer
+		customer, err = d.db.GetCustomerByID(ctx, database.GetCustomerByIDParams{
+			ID:      req.CustomerID.UUID,
+			StoreID: req.StoreID.UUID,
+		})
+		if err == nil {
+			err = privacy.ExportCustomer(ctx, d.db, archive, customer)
+		}
+	} else {
+		var user database.User
+		user, err = d.db.GetUserByID(ctx, req.UserID.UUID)
+		if err == nil {
+			err = privacy.ExportUser(ctx, d.db, archive, user)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	key := privacyExportKey(req)
+	if err := d.storage.Put(ctx, key, privacy.ContentType, file, size); err != nil {
+		return fmt.Errorf("store privacy export: %w", err)
+	}
+
+	return d.db.CompletePrivacyRequest(ctx, database.CompletePrivacyRequestParams{
+		ID:         req.ID,
+		StorageKey: sql.NullString{String: key, Valid: true},
+		SizeBytes:  sql.NullInt64{Int64: size, Valid: true},
+	})
+}
+
+// erasePrivacySubject anonymizes the subject, deletes the exports already
+// written about them and completes the request in one transaction. Should
+// it roll back, the retry deletes the same exports again, which is harmless.
+func (d *handlerDeps) erasePrivacySubject(ctx context.Context, req database.PrivacyRequest) error {
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	qtx := d.db.WithTx(tx)
+
+	if req.CustomerID.Valid {
+		var customer database.Customer
+		customer, err = qtx.GetCustomerByID(ctx, database.GetCustomerByIDParams{
+			ID:      req.CustomerID.UUID,
+			StoreID: req.StoreID.UUID,
+		})
+		if err == nil {
+			err = privacy.EraseCustomer(ctx, qtx, customer)
+		}
+	} else {
+		var user database.User
+		user, err = qtx.GetUserByID(ctx, req.UserID.UUID)
+		if err == nil {
+			err = privacy.EraseUser(ctx, qtx, user)
+		}
+		if err == nil && user.AvatarKey.Valid {
+			if err := d.storage.Delete(ctx, user.AvatarKey.String); err != nil {
+				return fmt.Errorf("delete avatar: %w", err)
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	exports, err := qtx.ListPrivacyExportKeys(ctx, database.ListPrivacyExportKeysParams{
+		CustomerID: req.CustomerID,
+		UserID:     req.UserID,
+	})
+	if err != nil {
+		return err
+	}
+	for _, exp := range exports {
+		if err := d.storage.Delete(ctx, exp.StorageKey); err != nil {
+			return fmt.Errorf("delete privacy export: %w", err)
+		}
+		if err := qtx.ClearPrivacyExportKey(ctx, exp.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := qtx.CompletePrivacyRequest(ctx, database.CompletePrivacyRequestParams{ID: req.ID}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func privacyExportKey(req database.PrivacyRequest) string {
+	if req.CustomerID.Valid {
+		return fmt.Sprintf("%sstores/%s/privacy/%s.zip", storage.PrivatePrefix, req.StoreID.UUID, req.ID)
+	}
+	return fmt.Sprintf("%susers/%s/privacy/%s.zip", storage.PrivatePrefix, req.UserID.UUID, req.ID)
+}
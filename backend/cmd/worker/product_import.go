@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/jobs"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/productimport"
+	"github.com/google/uuid"
+)
+
+// importProducts applies an uploaded product CSV row by row, recording the
+// outcome of each. Rows already recorded are skipped, so a retried job
+// picks up where the last attempt stopped.
+func (d *handlerDeps) importProducts(ctx context.Context, job jobs.Job, args productimport.Args) error {
+	imp, err := d.db.GetProductImportForProcessing(ctx, args.ImportID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Store deleted before the job ran; nothing to do
+			return nil
+		}
+		return err
+	}
+	if imp.Status == productimport.StatusCompleted || imp.Status == productimport.StatusFailed {
+		return nil
+	}
+	if err := d.db.StartProductImport(ctx, imp.ID); err != nil {
+		return err
+	}
+
+	rows, err := productimport.Parse(bytes.NewReader(imp.Data))
+	if err != nil {
+		// The upload was checked when it was made, so this is unexpected,
+		// but retrying won't change the file
+		return d.finishImport(ctx, imp.ID, productimport.StatusFailed, err.Error())
+	}
+
+	done, err := d.db.ListProductImportRowNumbers(ctx, imp.ID)
+	if err != nil {
+		return err
+	}
+	recorded := make(map[int32]bool, len(done))
+	for _, n := range done {
+		recorded[n] = true
+	}
+
+	importer := productimport.NewImporter(newLimitedCatalog(d.db, d.gids), d.db, imp.StoreID)
+	for _, row := range rows {
+		if recorded[int32(row.Line)] {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		result, err := importer.Apply(ctx, row)
+		if err != nil {
+			return err
+		}
+		if err := d.recordImportRow(ctx, imp.ID, result); err != nil {
+			return err
+		}
+	}
+
+	if err := d.finishImport(ctx, imp.ID, productimport.StatusCompleted, ""); err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "product import completed",
+		"job_id", job.ID,
+		"import_id", imp.ID,
+		"store_id", imp.StoreID,
+		"rows", len(rows),
+	)
+	return nil
+}
+
+func (d *handlerDeps) recordImportRow(ctx context.Context, importID uuid.UUID, result productimport.Result) error {
+	errs := result.Errors
+	if errs == nil {
+		errs = []problem.FieldError{}
+	}
+	data, err := json.Marshal(errs)
+	if err != nil {
+		return err
+	}
+	return d.db.CreateProductImportRow(ctx, database.CreateProductImportRowParams{
+		ImportID:  importID,
+		RowNumber: int32(result.Line),
+		Status:    result.Status,
+		Handle:    sql.NullString{String: result.Handle, Valid: result.Handle != ""},
+		Sku:       sql.NullString{String: result.SKU, Valid: result.SKU != ""},
+		ProductID: uuid.NullUUID{UUID: result.ProductID, Valid: result.ProductID != uuid.Nil},
+		Errors:    data,
+	})
+}
+
+func (d *handlerDeps) finishImport(ctx context.Context, importID uuid.UUID, status, message string) error {
+	return d.db.FinishProductImport(ctx, database.FinishProductImportParams{
+		ID:     importID,
+		Status: status,
+		Error:  sql.NullString{String: message, Valid: message != ""},
+	})
+}
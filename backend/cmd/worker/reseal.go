@@ -0,0 +1,20 @@
+package main
+
+import (
+	"context"
+
+	"github.com/dfodeker/terminus/internal/crypto/keystore"
+	"github.com/dfodeker/terminus/internal/jobs"
+)
+
+// reseal reseals stored values right after a data key rotation, so the
+// retired key stops guarding anything without waiting for the resealer's
+// next pass. The keyring is reloaded first, as this worker may not have
+// picked up the new key yet.
+func (d *handlerDeps) reseal(ctx context.Context, job jobs.Job, _ keystore.ResealArgs) error {
+	if err := d.keyring.Reload(ctx); err != nil {
+		return err
+	}
+	_, err := d.resealer.Reseal(ctx)
+	return err
+}
@@ -0,0 +1,678 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/dfodeker/terminus/internal/crypto"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/inventory"
+	"github.com/dfodeker/terminus/internal/jobs"
+	"github.com/dfodeker/terminus/internal/media"
+	"github.com/dfodeker/terminus/internal/options"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/service/products"
+	"github.com/dfodeker/terminus/internal/shopify"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/google/uuid"
+)
+
+// shopifyItem is one product, customer or order of an import, ready to
+// apply
+type shopifyItem struct {
+	position  int
+	reference string
+	errors    []problem.FieldError
+	apply     func(ctx context.Context, q *database.Queries) (status string, targetID uuid.UUID, err error)
+}
+
+// itemFailure is a problem with one item that retrying won't fix
+type itemFailure struct {
+	fields []problem.FieldError
+}
+
+func (f *itemFailure) Error() string {
+	return fmt.Sprintf("item rejected: %s %s", f.fields[0].Field, f.fields[0].Message)
+}
+
+func failItem(field, code, message string) error {
+	return &itemFailure{fields: []problem.FieldError{{Field: field, Code: code, Message: message}}}
+}
+
+// importShopify applies an uploaded Shopify export one item at a time.
+// Each item and the record of its outcome are written in one transaction,
+// so a retried job picks up exactly where the last attempt stopped.
+func (d *handlerDeps) importShopify(ctx context.Context, job jobs.Job, args shopify.Args) error {
+	imp, err := d.db.GetShopifyImportForProcessing(ctx, args.ImportID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Store deleted before the job ran; nothing to do
+			return nil
+		}
+		return err
+	}
+	if imp.Status == shopify.StatusCompleted || imp.Status == shopify.StatusFailed {
+		return nil
+	}
+	if err := d.db.StartShopifyImport(ctx, imp.ID); err != nil {
+		return err
+	}
+
+	items, err := d.shopifyItems(imp)
+	if err != nil {
+		// The upload was checked when it was made, so this is unexpected,
+		// but retrying won't change the file
+		return d.finishShopifyImport(ctx, imp.ID, shopify.StatusFailed, err.Error())
+	}
+
+	done, err := d.db.ListShopifyImportItemPositions(ctx, imp.ID)
+	if err != nil {
+		return err
+	}
+	recorded := make(map[int32]bool, len(done))
+	for _, n := range done {
+		recorded[n] = true
+	}
+
+	for _, item := range items {
+		if recorded[int32(item.position)] {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := d.applyShopifyItem(ctx, imp.ID, item); err != nil {
+			return err
+		}
+	}
+
+	if err := d.finishShopifyImport(ctx, imp.ID, shopify.StatusCompleted, ""); err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "shopify import completed",
+		"job_id", job.ID,
+		"import_id", imp.ID,
+		"store_id", imp.StoreID,
+		"entity", imp.Entity,
+		"items", len(items),
+	)
+	return nil
+}
+
+// shopifyItems parses the import's file into items bound to its store
+func (d *handlerDeps) shopifyItems(imp database.ShopifyImport) ([]shopifyItem, error) {
+	var items []shopifyItem
+	switch imp.Entity {
+	case shopify.EntityProducts:
+		parsed, err := shopify.ParseProducts(imp.Format, imp.Data)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range parsed {
+			items = append(items, shopifyItem{
+				position:  p.Position,
+				reference: p.Handle,
+				errors:    p.Errors,
+				apply: func(ctx context.Context, q *database.Queries) (string, uuid.UUID, error) {
+					return d.applyShopifyProduct(ctx, q, imp, p)
+				},
+			})
+		}
+	case shopify.EntityCustomers:
+		parsed, err := shopify.ParseCustomers(imp.Format, imp.Data)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range parsed {
+			items = append(items, shopifyItem{
+				position:  c.Position,
+				reference: c.Email,
+				errors:    c.Errors,
+				apply: func(ctx context.Context, q *database.Queries) (string, uuid.UUID, error) {
+					return d.applyShopifyCustomer(ctx, q, imp, c)
+				},
+			})
+		}
+	case shopify.EntityOrders:
+		parsed, err := shopify.ParseOrders(imp.Format, imp.Data)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range parsed {
+			items = append(items, shopifyItem{
+				position:  o.Position,
+				reference: o.Name,
+				errors:    o.Errors,
+				apply: func(ctx context.Context, q *database.Queries) (string, uuid.UUID, error) {
+					return d.applyShopifyOrder(ctx, q, imp, o)
+				},
+			})
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown entity %q", shopify.ErrInvalidFile, imp.Entity)
+	}
+	return items, nil
+}
+
+// applyShopifyItem writes one item, or records why it couldn't be
+func (d *handlerDeps) applyShopifyItem(ctx context.Context, importID uuid.UUID, item shopifyItem) error {
+	if len(item.errors) > 0 {
+		return d.recordShopifyItem(ctx, d.db, importID, item, shopify.ItemFailed, uuid.Nil, item.errors)
+	}
+
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	qtx := d.db.WithTx(tx)
+
+	status, targetID, err := item.apply(ctx, qtx)
+	if err != nil {
+		fields, ok := shopifyItemErrors(err)
+		if !ok {
+			return err
+		}
+		tx.Rollback(ctx)
+		return d.recordShopifyItem(ctx, d.db, importID, item, shopify.ItemFailed, uuid.Nil, fields)
+	}
+	if err := d.recordShopifyItem(ctx, qtx, importID, item, status, targetID, nil); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (d *handlerDeps) recordShopifyItem(ctx context.Context, q *database.Queries, importID uuid.UUID, item shopifyItem, status string, targetID uuid.UUID, errs []problem.FieldError) error {
+	if errs == nil {
+		errs = []problem.FieldError{}
+	}
+	data, err := json.Marshal(errs)
+	if err != nil {
+		return err
+	}
+	return q.CreateShopifyImportItem(ctx, database.CreateShopifyImportItemParams{
+		ImportID:  importID,
+		Position:  int32(item.position),
+		Status:    status,
+		Reference: sql.NullString{String: item.reference, Valid: item.reference != ""},
+		TargetID:  uuid.NullUUID{UUID: targetID, Valid: targetID != uuid.Nil},
+		Errors:    data,
+	})
+}
+
+func (d *handlerDeps) finishShopifyImport(ctx context.Context, importID uuid.UUID, status, message string) error {
+	return d.db.FinishShopifyImport(ctx, database.FinishShopifyImportParams{
+		ID:     importID,
+		Status: status,
+		Error:  sql.NullString{String: message, Valid: message != ""},
+	})
+}
+
+// shopifyItemErrors turns a rejection of an item into field errors, or
+// reports false for failures worth retrying
+func shopifyItemErrors(err error) ([]problem.FieldError, bool) {
+	var failure *itemFailure
+	if errors.As(err, &failure) {
+		return failure.fields, true
+	}
+	var invalid *validate.Error
+	if errors.As(err, &invalid) {
+		return invalid.Fields, true
+	}
+	var svcErr *service.Error
+	if !errors.As(err, &svcErr) {
+		return nil, false
+	}
+	field, code := "item", svcErr.Code
+	switch {
+	case code == problem.CodeHandleTaken:
+		field = "handle"
+	case code == problem.CodeInvalidStatusTransition:
+		field = "status"
+	case code == "":
+		code = validate.CodeInvalid
+	}
+	return []problem.FieldError{{Field: field, Code: code, Message: svcErr.Message}}, true
+}
+
+// applyShopifyProduct creates or updates the product with the same handle,
+// its options and variants. Variants are matched by SKU, then by option
+// values; ones not in the file are left alone. Images are brought over
+// only for new products, since an existing product's media may have been
+// curated since.
+func (d *handlerDeps) applyShopifyProduct(ctx context.Context, q *database.Queries, imp database.ShopifyImport, p shopify.Product) (string, uuid.UUID, error) {
+	catalog := newLimitedCatalog(q, d.gids)
+	existing, err := q.GetProductByHandle(ctx, database.GetProductByHandleParams{StoreID: imp.StoreID, Handle: p.Handle})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", uuid.Nil, err
+	}
+	found := err == nil
+
+	var product database.Product
+	status := shopify.ItemUpdated
+	if found {
+		product, err = catalog.Update(ctx, products.UpdateInput{
+			StoreID:          imp.StoreID,
+			ID:               existing.ID,
+			Name:             &p.Title,
+			Description:      optional(p.Description),
+			InventoryTracked: &p.InventoryTracked,
+			Tags:             optional(p.Tags),
+			Status:           &p.Status,
+		})
+	} else {
+		status = shopify.ItemCreated
+		// Products can't be created archived, so archived ones start as
+		// drafts and move on once they exist
+		initial := p.Status
+		if initial == products.StatusArchived {
+			initial = products.StatusDraft
+		}
+		product, err = catalog.Create(ctx, products.CreateInput{
+			StoreID:          imp.StoreID,
+			Name:             p.Title,
+			Handle:           p.Handle,
+			Description:      optional(p.Description),
+			InventoryTracked: p.InventoryTracked,
+			Tags:             optional(p.Tags),
+			Status:           initial,
+		})
+		if err == nil && initial != p.Status {
+			product, err = catalog.Update(ctx, products.UpdateInput{StoreID: imp.StoreID, ID: product.ID, Status: &p.Status})
+		}
+	}
+	if err != nil {
+		return "", uuid.Nil, err
+	}
+
+	if err := options.Validate(p.Options); err != nil {
+		return "", uuid.Nil, err
+	}
+	if err := q.DeleteProductOptions(ctx, product.ID); err != nil {
+		return "", uuid.Nil, err
+	}
+	for i, opt := range p.Options {
+		_, err := q.CreateProductOption(ctx, database.CreateProductOptionParams{
+			ProductID: product.ID,
+			Name:      opt.Name,
+			Position:  int32(i),
+			Choices:   opt.Values,
+		})
+		if err != nil {
+			return "", uuid.Nil, err
+		}
+	}
+
+	variantIDs, err := d.upsertShopifyVariants(ctx, q, imp, product, p)
+	if err != nil {
+		return "", uuid.Nil, err
+	}
+
+	if !found {
+		if err := d.queueShopifyImages(ctx, q, imp, product, p, variantIDs); err != nil {
+			return "", uuid.Nil, err
+		}
+	}
+	return status, product.ID, nil
+}
+
+// upsertShopifyVariants writes the product's variants, returning the ID
+// each image URL a variant shows should be attached to
+func (d *handlerDeps) upsertShopifyVariants(ctx context.Context, q *database.Queries, imp database.ShopifyImport, product database.Product, p shopify.Product) (map[string]uuid.UUID, error) {
+	existing, err := q.GetProductVariantsByProductID(ctx, product.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	imageVariants := map[string]uuid.UUID{}
+	for i, v := range p.Variants {
+		combo := options.Combination(v.Values)
+		values, err := json.Marshal(combo.Values(p.Options))
+		if err != nil {
+			return nil, err
+		}
+		title := combo.Title()
+		if title == "" {
+			title = "Default Title"
+		}
+		sku := sql.NullString{String: v.SKU, Valid: v.SKU != ""}
+		barcode := sql.NullString{String: v.Barcode, Valid: v.Barcode != ""}
+		compareAt := sql.NullInt32{}
+		if v.CompareAtCents != nil {
+			compareAt = sql.NullInt32{Int32: *v.CompareAtCents, Valid: true}
+		}
+		weight := sql.NullInt32{}
+		if v.WeightGrams != nil {
+			weight = sql.NullInt32{Int32: *v.WeightGrams, Valid: true}
+		}
+
+		var variant database.ProductVariant
+		if match, ok := matchVariant(existing, p.Options, v); ok {
+			variant, err = q.ReplaceProductVariant(ctx, database.ReplaceProductVariantParams{
+				ID:             match.ID,
+				ProductID:      product.ID,
+				Sku:            sku,
+				Barcode:        barcode,
+				Title:          title,
+				PriceCents:     v.PriceCents,
+				CompareAtCents: compareAt,
+				OptionValues:   values,
+				Status:         match.Status,
+				WeightGrams:    weight,
+			})
+		} else {
+			variant, err = q.CreateProductVariant(ctx, database.CreateProductVariantParams{
+				Gid:            service.NewGID(d.gids),
+				TenantID:       imp.TenantID,
+				StoreID:        imp.StoreID,
+				ProductID:      product.ID,
+				Sku:            sku,
+				Barcode:        barcode,
+				Title:          title,
+				PriceCents:     v.PriceCents,
+				CompareAtCents: compareAt,
+				OptionValues:   values,
+				Status:         "active",
+				WeightGrams:    weight,
+			})
+			if err == nil && product.InventoryTracked && v.InventoryQuantity > 0 {
+				err = receiveStock(ctx, q, imp, variant.ID, v.InventoryQuantity)
+			}
+		}
+		if service.UniqueViolation(err, "") {
+			return nil, failItem(fmt.Sprintf("variants.%d.sku", i), problem.CodeConflict,
+				fmt.Sprintf("A variant with SKU %s already exists in this store", v.SKU))
+		}
+		if err != nil {
+			return nil, err
+		}
+		if v.ImageSrc != "" {
+			if _, ok := imageVariants[v.ImageSrc]; !ok {
+				imageVariants[v.ImageSrc] = variant.ID
+			}
+		}
+	}
+	return imageVariants, nil
+}
+
+// matchVariant finds the existing variant a Shopify one is, by SKU when it
+// has one and otherwise by its option values
+func matchVariant(existing []database.ProductVariant, opts []options.Option, v shopify.Variant) (database.ProductVariant, bool) {
+	key := options.Combination(v.Values).Key()
+	for _, ev := range existing {
+		if v.SKU != "" {
+			if ev.Sku.Valid && ev.Sku.String == v.SKU {
+				return ev, true
+			}
+			continue
+		}
+		if combo, ok := options.Match(opts, ev.OptionValues); ok && combo.Key() == key {
+			return ev, true
+		}
+	}
+	return database.ProductVariant{}, false
+}
+
+// receiveStock records a new variant's starting stock at the tenant's
+// default location
+func receiveStock(ctx context.Context, q *database.Queries, imp database.ShopifyImport, variantID uuid.UUID, quantity int32) error {
+	location, err := q.EnsureDefaultInventoryLocation(ctx, imp.TenantID)
+	if err != nil {
+		return err
+	}
+	item, err := q.EnsureInventoryItem(ctx, database.EnsureInventoryItemParams{
+		TenantID:  imp.TenantID,
+		StoreID:   imp.StoreID,
+		VariantID: variantID,
+	})
+	if err != nil {
+		return err
+	}
+	level, err := q.EnsureInventoryLevel(ctx, database.EnsureInventoryLevelParams{
+		TenantID:        imp.TenantID,
+		InventoryItemID: item.ID,
+		LocationID:      location.ID,
+	})
+	if err != nil {
+		return err
+	}
+	level, err = q.AdjustInventoryLevel(ctx, database.AdjustInventoryLevelParams{Delta: quantity, ID: level.ID})
+	if err != nil {
+		return err
+	}
+	if _, err := q.AdjustInventoryItem(ctx, database.AdjustInventoryItemParams{Delta: quantity, ID: item.ID}); err != nil {
+		return err
+	}
+	_, err = q.CreateInventoryMovement(ctx, database.CreateInventoryMovementParams{
+		TenantID:        imp.TenantID,
+		InventoryItemID: item.ID,
+		LocationID:      uuid.NullUUID{UUID: location.ID, Valid: true},
+		Delta:           quantity,
+		QuantityAfter:   level.OnHand,
+		Reason:          string(inventory.ReasonReceived),
+		Note:            sql.NullString{String: "Imported from Shopify", Valid: true},
+		CreatedBy:       imp.CreatedBy,
+	})
+	return err
+}
+
+// queueShopifyImages adds a pending media row for each image and a job to
+// copy it from Shopify's CDN. Images in formats product media doesn't take
+// are left behind.
+func (d *handlerDeps) queueShopifyImages(ctx context.Context, q *database.Queries, imp database.ShopifyImport, product database.Product, p shopify.Product, variantIDs map[string]uuid.UUID) error {
+	for _, img := range p.Images {
+		contentType, ok := img.ContentType()
+		if !ok {
+			slog.WarnContext(ctx, "shopify image skipped",
+				"import_id", imp.ID,
+				"product_id", product.ID,
+				"src", img.Src,
+			)
+			continue
+		}
+		mediaID := uuid.New()
+		variantID, hasVariant := variantIDs[img.Src]
+		_, err := q.CreateProductMedia(ctx, database.CreateProductMediaParams{
+			ID:          mediaID,
+			Gid:         service.NewGID(d.gids),
+			TenantID:    imp.TenantID,
+			StoreID:     imp.StoreID,
+			ProductID:   product.ID,
+			VariantID:   uuid.NullUUID{UUID: variantID, Valid: hasVariant},
+			StorageKey:  media.OriginalKey(imp.StoreID, product.ID, mediaID, contentType),
+			ContentType: contentType,
+			AltText:     sql.NullString{String: img.Alt, Valid: img.Alt != ""},
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := d.jobs.EnqueueTx(ctx, q, media.FetchArgs{MediaID: mediaID, SourceURL: img.Src}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyShopifyCustomer creates the customer with the same email or updates
+// their profile. A default address is added when they have none yet.
+func (d *handlerDeps) applyShopifyCustomer(ctx context.Context, q *database.Queries, imp database.ShopifyImport, c shopify.Customer) (string, uuid.UUID, error) {
+	var v validate.Validator
+	v.Email("email", c.Email)
+	if err := v.Err(); err != nil {
+		return "", uuid.Nil, err
+	}
+
+	customer, err := q.GetCustomerByEmail(ctx, database.GetCustomerByEmailParams{
+		StoreID:     imp.StoreID,
+		EmailDigest: crypto.Digest(c.Email),
+		Email:       c.Email,
+	})
+	status := shopify.ItemUpdated
+	switch {
+	case err == nil:
+		customer, err = q.UpdateCustomerProfile(ctx, database.UpdateCustomerProfileParams{
+			ID:               customer.ID,
+			StoreID:          imp.StoreID,
+			FirstName:        orNull(c.FirstName, customer.FirstName),
+			LastName:         orNull(c.LastName, customer.LastName),
+			Phone:            crypto.NullText(orNull(c.Phone, sql.NullString(customer.Phone))),
+			AcceptsMarketing: c.AcceptsMarketing,
+		})
+	case errors.Is(err, sql.ErrNoRows):
+		status = shopify.ItemCreated
+		customer, err = q.CreateCustomer(ctx, database.CreateCustomerParams{
+			Gid:              service.NewGID(d.gids),
+			TenantID:         imp.TenantID,
+			StoreID:          imp.StoreID,
+			Email:            crypto.Text(c.Email),
+			EmailDigest:      crypto.Digest(c.Email),
+			FirstName:        orNull(c.FirstName, sql.NullString{}),
+			LastName:         orNull(c.LastName, sql.NullString{}),
+			Phone:            crypto.NullText(orNull(c.Phone, sql.NullString{})),
+			AcceptsMarketing: c.AcceptsMarketing,
+			PlainEmail:       c.Email,
+		})
+	}
+	if err != nil {
+		return "", uuid.Nil, err
+	}
+
+	if c.Address != nil {
+		count, err := q.CountCustomerAddresses(ctx, customer.ID)
+		if err != nil {
+			return "", uuid.Nil, err
+		}
+		if count == 0 {
+			a := c.Address
+			_, err := q.CreateCustomerAddress(ctx, database.CreateCustomerAddressParams{
+				CustomerID:        customer.ID,
+				FirstName:         orNull(a.FirstName, sql.NullString{}),
+				LastName:          orNull(a.LastName, sql.NullString{}),
+				Company:           orNull(a.Company, sql.NullString{}),
+				Address1:          crypto.Text(a.Address1),
+				Address2:          crypto.NullText(orNull(a.Address2, sql.NullString{})),
+				City:              a.City,
+				RegionCode:        orNull(a.RegionCode, sql.NullString{}),
+				PostalCode:        orNull(a.PostalCode, sql.NullString{}),
+				CountryCode:       a.CountryCode,
+				Phone:             crypto.NullText(orNull(a.Phone, sql.NullString{})),
+				IsDefaultShipping: true,
+				IsDefaultBilling:  true,
+			})
+			if err != nil {
+				return "", uuid.Nil, err
+			}
+		}
+	}
+	return status, customer.ID, nil
+}
+
+// applyShopifyOrder records an order under its Shopify number, linked to
+// the customer with its email and the variants with its SKUs where the
+// store has them. An order whose number is taken is skipped, so importing
+// the same export twice is harmless.
+func (d *handlerDeps) applyShopifyOrder(ctx context.Context, q *database.Queries, imp database.ShopifyImport, o shopify.Order) (string, uuid.UUID, error) {
+	existing, err := q.GetOrderByNumber(ctx, database.GetOrderByNumberParams{StoreID: imp.StoreID, OrderNumber: o.Number})
+	if err == nil {
+		return shopify.ItemSkipped, existing.ID, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", uuid.Nil, err
+	}
+
+	customerID := uuid.NullUUID{}
+	if o.Email != "" {
+		customer, err := q.GetCustomerByEmail(ctx, database.GetCustomerByEmailParams{
+			StoreID:     imp.StoreID,
+			EmailDigest: crypto.Digest(o.Email),
+			Email:       o.Email,
+		})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return "", uuid.Nil, err
+		}
+		customerID = uuid.NullUUID{UUID: customer.ID, Valid: err == nil}
+	}
+
+	order, err := q.CreateOrder(ctx, database.CreateOrderParams{
+		Gid:               service.NewGID(d.gids),
+		TenantID:          imp.TenantID,
+		StoreID:           imp.StoreID,
+		CustomerID:        customerID,
+		OrderNumber:       o.Number,
+		Email:             crypto.NullText(orNull(o.Email, sql.NullString{})),
+		EmailDigest:       crypto.Digest(o.Email),
+		Status:            o.Status,
+		FinancialStatus:   o.FinancialStatus,
+		FulfillmentStatus: o.FulfillmentStatus,
+		Currency:          o.Currency,
+		SubtotalCents:     o.SubtotalCents,
+		ShippingCents:     o.ShippingCents,
+		TaxCents:          o.TaxCents,
+		DiscountCents:     o.DiscountCents,
+		TotalCents:        o.TotalCents,
+		Tags:              o.Tags,
+		PlacedAt:          o.PlacedAt,
+	})
+	if err != nil {
+		return "", uuid.Nil, err
+	}
+
+	for _, line := range o.LineItems {
+		productID, variantID := uuid.NullUUID{}, uuid.NullUUID{}
+		if line.SKU != "" {
+			variant, err := q.GetProductVariantBySKU(ctx, database.GetProductVariantBySKUParams{
+				StoreID: imp.StoreID,
+				Sku:     sql.NullString{String: line.SKU, Valid: true},
+			})
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return "", uuid.Nil, err
+			}
+			if err == nil {
+				productID = uuid.NullUUID{UUID: variant.ProductID, Valid: true}
+				variantID = uuid.NullUUID{UUID: variant.ID, Valid: true}
+			}
+		}
+		item, err := q.CreateOrderLineItem(ctx, database.CreateOrderLineItemParams{
+			OrderID:      order.ID,
+			ProductID:    productID,
+			VariantID:    variantID,
+			Title:        line.Title,
+			VariantTitle: orNull(line.VariantTitle, sql.NullString{}),
+			Sku:          orNull(line.SKU, sql.NullString{}),
+			Quantity:     line.Quantity,
+			PriceCents:   line.PriceCents,
+			TotalCents:   line.PriceCents * int64(line.Quantity),
+		})
+		if err != nil {
+			return "", uuid.Nil, err
+		}
+		// A bundle's components get lines of their own for fulfillment
+		if variantID.Valid {
+			if _, err := q.CreateBundleComponentLineItems(ctx, item.ID); err != nil {
+				return "", uuid.Nil, err
+			}
+		}
+	}
+	return shopify.ItemCreated, order.ID, nil
+}
+
+// optional is nil for an empty string, which leaves a field unset
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// orNull is s as a column value, or fallback when s is empty
+func orNull(s string, fallback sql.NullString) sql.NullString {
+	if s == "" {
+		return fallback
+	}
+	return sql.NullString{String: s, Valid: true}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/jobs"
+	"github.com/dfodeker/terminus/internal/media"
+	"github.com/dfodeker/terminus/internal/storage"
+)
+
+// maxOriginalBytes caps how much of an original is read into memory
+const maxOriginalBytes = 50 << 20
+
+// generateThumbnails renders the configured widths of an uploaded product
+// image and records which ones exist. Reruns overwrite the same keys, so a
+// retried or duplicated job is harmless.
+func (d *handlerDeps) generateThumbnails(ctx context.Context, job jobs.Job, args media.ThumbnailArgs) error {
+	m, err := d.db.GetProductMediaForProcessing(ctx, args.MediaID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Deleted before the job ran; nothing to do
+			return nil
+		}
+		return err
+	}
+	if m.Status != "ready" {
+		return jobs.Permanent(fmt.Errorf("media %s is %s, not ready", m.ID, m.Status))
+	}
+
+	outType, _, err := media.ThumbnailFormat(m.ContentType)
+	if err != nil {
+		return jobs.Permanent(fmt.Errorf("media %s: %w", m.ID, err))
+	}
+
+	body, err := d.storage.Get(ctx, m.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return jobs.Permanent(err)
+		}
+		return err
+	}
+	data, err := io.ReadAll(io.LimitReader(body, maxOriginalBytes+1))
+	body.Close()
+	if err != nil {
+		return err
+	}
+	if len(data) > maxOriginalBytes {
+		return jobs.Permanent(fmt.Errorf("media %s is larger than %d bytes", m.ID, maxOriginalBytes))
+	}
+
+	result, err := media.Generate(data, m.ContentType, d.thumbnailWidths)
+	if err != nil {
+		return jobs.Permanent(fmt.Errorf("media %s: %w", m.ID, err))
+	}
+
+	widths := make([]int32, 0, len(result.Thumbnails))
+	for _, thumb := range result.Thumbnails {
+		key := media.ThumbnailKey(m.StorageKey, m.ContentType, thumb.Width)
+		err := d.storage.Put(ctx, key, outType, bytes.NewReader(thumb.Data), int64(len(thumb.Data)))
+		if err != nil {
+			return fmt.Errorf("store %dw thumbnail: %w", thumb.Width, err)
+		}
+		widths = append(widths, int32(thumb.Width))
+	}
+
+	err = d.db.SetProductMediaThumbnails(ctx, database.SetProductMediaThumbnailsParams{
+		ID:              m.ID,
+		Width:           sql.NullInt32{Int32: int32(result.Width), Valid: true},
+		Height:          sql.NullInt32{Int32: int32(result.Height), Valid: true},
+		ThumbnailWidths: widths,
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "product media thumbnails generated",
+		"job_id", job.ID,
+		"media_id", m.ID,
+		"widths", widths,
+	)
+	return nil
+}
@@ -0,0 +1,45 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/google/uuid"
+)
+
+// Caller is who a request acts as, checked the way the REST API checks
+// them. Errors are *service.Error values, so their message and problem
+// code reach the client.
+type Caller interface {
+	// User is the signed-in user, for fields on their own account. API
+	// keys and impersonation tokens are refused, as on the matching REST
+	// routes; with verified, so are users who haven't confirmed their
+	// email address.
+	User(ctx context.Context, verified bool) (uuid.UUID, error)
+	// Authorize checks the caller is a member of tenantID holding
+	// permission, or just a member when permission is empty. It returns
+	// ctx with its queries scoped to the tenant's rows, which is what the
+	// resolver reads and writes through from then on.
+	Authorize(ctx context.Context, tenantID uuid.UUID, permission string) (context.Context, error)
+	// Audit records a change in the tenant's audit log. before is nil for
+	// a create and after for a delete; storeID is uuid.Nil outside a store.
+	Audit(ctx context.Context, tenantID, storeID uuid.UUID, entity gid.GID, before, after any)
+}
+
+type callerKey struct{}
+
+// WithCaller returns ctx carrying the request's Caller
+func WithCaller(ctx context.Context, c Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, c)
+}
+
+// callerFrom returns the Caller the API host stored. A request without one
+// was routed around the host's handler, so it panics rather than let a
+// resolver run unchecked.
+func callerFrom(ctx context.Context) Caller {
+	c, ok := ctx.Value(callerKey{}).(Caller)
+	if !ok {
+		panic("graph: request has no Caller")
+	}
+	return c
+}
@@ -0,0 +1,108 @@
+package graph
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultFirst = 50
+	maxFirst     = 100
+)
+
+// pageArgs are the arguments of every connection field
+type pageArgs struct {
+	First *int32
+	After *string
+}
+
+// cursor is the REST API's list cursor, so either API can continue a page
+// the other started
+type cursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+}
+
+func encodeCursor(createdAt time.Time, id uuid.UUID) string {
+	b, _ := json.Marshal(cursor{CreatedAt: createdAt, ID: id})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// page turns connection arguments into a service page request
+func (a pageArgs) page() (service.PageRequest, error) {
+	page := service.PageRequest{Limit: defaultFirst}
+	if a.First != nil {
+		if *a.First < 1 {
+			return service.PageRequest{}, service.Invalid("first must be at least 1")
+		}
+		page.Limit = min(int(*a.First), maxFirst)
+	}
+	if a.After == nil || *a.After == "" {
+		return page, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(*a.After)
+	if err != nil {
+		return service.PageRequest{}, service.Invalid("Invalid cursor")
+	}
+	var c cursor
+	if err := json.Unmarshal(b, &c); err != nil || c.CreatedAt.IsZero() || c.ID == uuid.Nil {
+		return service.PageRequest{}, service.Invalid("Invalid cursor")
+	}
+	page.After = &service.Cursor{CreatedAt: c.CreatedAt, ID: c.ID}
+	return page, nil
+}
+
+type pageInfo struct {
+	hasNextPage bool
+	endCursor   *string
+}
+
+func (p pageInfo) HasNextPage() bool { return p.hasNextPage }
+
+func (p pageInfo) EndCursor() *string { return p.endCursor }
+
+// edge is one item of a connection with the cursor that follows it
+type edge[T any] struct {
+	cursor string
+	node   T
+}
+
+func (e edge[T]) Cursor() string { return e.cursor }
+
+func (e edge[T]) Node() T { return e.node }
+
+// connection is a page of a list. Every connection in the schema has the
+// same shape, so this one type serves them all.
+type connection[T any] struct {
+	edges    []edge[T]
+	pageInfo pageInfo
+}
+
+func (c *connection[T]) Edges() []edge[T] { return c.edges }
+
+func (c *connection[T]) PageInfo() pageInfo { return c.pageInfo }
+
+// newConnection builds a connection from one page of a list, keyed for
+// cursors by each item's creation time and ID
+func newConnection[R, T any](page service.Page[R], key func(R) (time.Time, uuid.UUID), node func(R) T) *connection[T] {
+	c := &connection[T]{edges: make([]edge[T], 0, len(page.Items))}
+	for _, item := range page.Items {
+		createdAt, id := key(item)
+		c.edges = append(c.edges, edge[T]{cursor: encodeCursor(createdAt, id), node: node(item)})
+	}
+	c.pageInfo.hasNextPage = page.HasMore
+	if n := len(c.edges); n > 0 {
+		c.pageInfo.endCursor = &c.edges[n-1].cursor
+	}
+	return c
+}
+
+// formatTime writes timestamps the way the REST API's JSON does
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
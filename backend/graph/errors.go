@@ -0,0 +1,68 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+)
+
+// queryError is a field's failure as the client sees it. The problem code
+// and anything else the REST error would carry go in its extensions.
+type queryError struct {
+	message    string
+	extensions map[string]any
+}
+
+func (e *queryError) Error() string { return e.message }
+
+func (e *queryError) Extensions() map[string]any { return e.extensions }
+
+// resolverError maps an error from a service or the Caller to what the
+// client is told, in the way respondWithServiceError does for REST.
+// Anything the client can't act on is logged and reported as fallback.
+func resolverError(ctx context.Context, err error, fallback string) error {
+	var invalid *validate.Error
+	if errors.As(err, &invalid) {
+		return &queryError{
+			message:    "The request has invalid fields",
+			extensions: map[string]any{"code": problem.CodeValidationFailed, "errors": invalid.Fields},
+		}
+	}
+
+	var svcErr *service.Error
+	if !errors.As(err, &svcErr) {
+		slog.ErrorContext(ctx, "graphql resolver failed",
+			"request_id", middleware.GetRequestID(ctx),
+			"error", err,
+		)
+		return &queryError{
+			message:    fallback,
+			extensions: map[string]any{"code": problem.CodeFor(http.StatusInternalServerError)},
+		}
+	}
+
+	code := svcErr.Code
+	if code == "" {
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, service.ErrNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, service.ErrConflict):
+			status = http.StatusConflict
+		case errors.Is(err, service.ErrForbidden):
+			status = http.StatusForbidden
+		}
+		code = problem.CodeFor(status)
+	}
+	extensions := map[string]any{"code": code}
+	if svcErr.Suggestion != "" {
+		extensions["suggestion"] = svcErr.Suggestion
+	}
+	return &queryError{message: svcErr.Message, extensions: extensions}
+}
@@ -0,0 +1,64 @@
+// Package graph serves the GraphQL Admin API. The schema lives in
+// schema.graphqls; the resolvers call the same services as the REST
+// handlers, and hold callers to the same checks through the Caller the API
+// host puts on each request's context.
+package graph
+
+import (
+	"context"
+	_ "embed"
+	"net/http"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/service/entitlements"
+	"github.com/dfodeker/terminus/internal/service/nodes"
+	"github.com/dfodeker/terminus/internal/service/products"
+	"github.com/dfodeker/terminus/internal/service/stores"
+	"github.com/dfodeker/terminus/internal/service/tenants"
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+)
+
+//go:embed schema.graphqls
+var schemaSDL string
+
+// maxDepth is how deeply a query may nest. Variants of the products of
+// the stores of the caller's tenants, the deepest useful query, is 13.
+const maxDepth = 15
+
+// Config is what the resolvers work with
+type Config struct {
+	// DB runs the list queries the services don't have
+	DB           database.Querier
+	Products     *products.Service
+	Stores       *stores.Service
+	Tenants      *tenants.Service
+	Nodes        *nodes.Service
+	Entitlements *entitlements.Service
+	// NodePermissions is what reading each node type takes beyond
+	// membership of its tenant, as for GET /api/v1/nodes/{gid}
+	NodePermissions map[gid.EntityType]string
+	// StorePlan picks the plan a new store goes on, given the one asked
+	// for, which may be empty
+	StorePlan func(ctx context.Context, tenantID uuid.UUID, requested string) (string, error)
+	// ProductWritten runs after a product is created or updated, for the
+	// follow-up work the REST handlers do, such as automated collections
+	ProductWritten func(ctx context.Context, product database.Product)
+}
+
+// NewHandler returns the handler for POST /admin/graphql. It panics if the
+// schema and resolvers disagree, which is a bug caught at startup.
+//
+// Resolvers run one at a time: the contexts Caller.Authorize returns share
+// a connection per request, which can't run queries concurrently.
+func NewHandler(cfg Config) http.Handler {
+	schema := graphql.MustParseSchema(schemaSDL, &resolver{cfg: cfg}, graphql.MaxDepth(maxDepth), graphql.MaxParallelism(1))
+	return &relay.Handler{Schema: schema}
+}
+
+// resolver is the root of Query and Mutation
+type resolver struct {
+	cfg Config
+}
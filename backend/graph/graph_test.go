@@ -0,0 +1,303 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/database/dbmock"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/service/nodes"
+	"github.com/dfodeker/terminus/internal/service/products"
+	"github.com/dfodeker/terminus/internal/service/stores"
+	"github.com/dfodeker/terminus/internal/service/tenants"
+	"github.com/google/uuid"
+)
+
+type fakeGIDs struct{ next uint64 }
+
+func (g *fakeGIDs) Generate() uint64 {
+	g.next++
+	return g.next
+}
+
+// fakeCaller grants every permission but those in denied
+type fakeCaller struct {
+	user   uuid.UUID
+	denied map[string]bool
+
+	mu      sync.Mutex
+	checked []string
+	audited []gid.GID
+}
+
+func (c *fakeCaller) User(context.Context, bool) (uuid.UUID, error) { return c.user, nil }
+
+// scopedTo is the tenant a fakeCaller scoped a context to
+type scopedTo struct{}
+
+func (c *fakeCaller) Authorize(ctx context.Context, tenantID uuid.UUID, permission string) (context.Context, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checked = append(c.checked, permission)
+	if c.denied[permission] {
+		return nil, service.Forbidden("You do not have permission to perform this action")
+	}
+	return context.WithValue(ctx, scopedTo{}, tenantID), nil
+}
+
+// inScope fails t unless ctx was scoped to tenantID
+func inScope(t *testing.T, ctx context.Context, tenantID uuid.UUID) {
+	t.Helper()
+	if got, _ := ctx.Value(scopedTo{}).(uuid.UUID); got != tenantID {
+		t.Errorf("query ran scoped to tenant %s, want %s", got, tenantID)
+	}
+}
+
+func (c *fakeCaller) Audit(_ context.Context, _, _ uuid.UUID, entity gid.GID, _, _ any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.audited = append(c.audited, entity)
+}
+
+func newTestHandler(q *dbmock.Querier) http.Handler {
+	gids := &fakeGIDs{}
+	return NewHandler(Config{
+		DB:              q,
+		Products:        products.New(q, gids),
+		Stores:          stores.New(q, gids),
+		Tenants:         tenants.New(q, nil, gids),
+		Nodes:           nodes.New(q),
+		NodePermissions: map[gid.EntityType]string{gid.EntityProduct: "products:view", gid.EntityOrder: "orders:view"},
+		ProductWritten:  func(context.Context, database.Product) {},
+	})
+}
+
+type response struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func exec(t *testing.T, h http.Handler, caller Caller, query string) response {
+	t.Helper()
+	body, _ := json.Marshal(map[string]string{"query": query})
+	req := httptest.NewRequest(http.MethodPost, "/admin/graphql", strings.NewReader(string(body)))
+	req = req.WithContext(WithCaller(req.Context(), caller))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var resp response
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response %q: %v", rec.Body.String(), err)
+	}
+	return resp
+}
+
+func TestTenantsConnection(t *testing.T) {
+	user, tenantID := uuid.New(), uuid.New()
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	q := &dbmock.Querier{
+		GetTenantsByUserIDPaginatedFunc: func(_ context.Context, arg database.GetTenantsByUserIDPaginatedParams) ([]database.GetTenantsByUserIDPaginatedRow, error) {
+			if arg.UserID != user || arg.RowLimit != 2 {
+				t.Errorf("tenants listed with %+v", arg)
+			}
+			// One more than asked for, so there is a next page
+			return []database.GetTenantsByUserIDPaginatedRow{
+				{ID: tenantID, Gid: sql.NullInt64{Int64: 7, Valid: true}, Name: "Acme", Status: "active", CreatedAt: created},
+				{ID: uuid.New(), Name: "Other", CreatedAt: created.Add(-time.Hour)},
+			}, nil
+		},
+		GetStoresByTenantIDPaginatedFunc: func(ctx context.Context, arg database.GetStoresByTenantIDPaginatedParams) ([]database.GetStoresByTenantIDPaginatedRow, error) {
+			inScope(t, ctx, tenantID)
+			if arg.TenantID.UUID != tenantID {
+				t.Errorf("stores listed for tenant %s, want %s", arg.TenantID.UUID, tenantID)
+			}
+			return []database.GetStoresByTenantIDPaginatedRow{
+				{ID: uuid.New(), Gid: sql.NullInt64{Int64: 9, Valid: true}, Handle: "main", TenantID: arg.TenantID},
+			}, nil
+		},
+	}
+	caller := &fakeCaller{user: user}
+
+	resp := exec(t, newTestHandler(q), caller, `{
+		tenants(first: 1) {
+			edges { cursor node { id name stores { edges { node { id handle } } } } }
+			pageInfo { hasNextPage endCursor }
+		}
+	}`)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("errors: %+v", resp.Errors)
+	}
+
+	var got struct {
+		Edges []struct {
+			Cursor string
+			Node   struct {
+				ID     string
+				Name   string
+				Stores struct {
+					Edges []struct{ Node struct{ ID, Handle string } }
+				}
+			}
+		}
+		PageInfo struct {
+			HasNextPage bool
+			EndCursor   string
+		}
+	}
+	if err := json.Unmarshal(resp.Data["tenants"], &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Edges) != 1 || got.Edges[0].Node.ID != "gid://mystoreos/Tenant/7" || got.Edges[0].Node.Name != "Acme" {
+		t.Fatalf("edges = %+v, want only Acme", got.Edges)
+	}
+	if stores := got.Edges[0].Node.Stores.Edges; len(stores) != 1 || stores[0].Node.ID != "gid://mystoreos/Store/9" {
+		t.Errorf("stores = %+v, want the main store", stores)
+	}
+	if !got.PageInfo.HasNextPage || got.PageInfo.EndCursor != got.Edges[0].Cursor {
+		t.Errorf("pageInfo = %+v, want a next page after the only edge", got.PageInfo)
+	}
+
+	// The cursor is the REST API's, so it resumes after the tenant
+	after, err := pageArgs{After: &got.PageInfo.EndCursor}.page()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.After == nil || after.After.ID != tenantID || !after.After.CreatedAt.Equal(created) {
+		t.Errorf("cursor resumes at %+v, want after tenant %s", after.After, tenantID)
+	}
+	if len(caller.checked) != 1 || caller.checked[0] != "stores:view" {
+		t.Errorf("checked %v, want stores:view", caller.checked)
+	}
+}
+
+func TestNodeChecksPermission(t *testing.T) {
+	storeID, tenantID := uuid.New(), uuid.New()
+	q := &dbmock.Querier{
+		GetProductByGIDFunc: func(_ context.Context, g sql.NullInt64) (database.Product, error) {
+			return database.Product{ID: uuid.New(), Gid: g, StoreID: storeID, Name: "Mug"}, nil
+		},
+		GetStoreByIDFunc: func(_ context.Context, id uuid.UUID) (database.Store, error) {
+			return database.Store{ID: id, TenantID: uuid.NullUUID{UUID: tenantID, Valid: true}}, nil
+		},
+	}
+	h := newTestHandler(q)
+	query := `{ node(id: "gid://mystoreos/Product/3") { id ... on Product { name } } }`
+
+	resp := exec(t, h, &fakeCaller{}, query)
+	if len(resp.Errors) > 0 || !strings.Contains(string(resp.Data["node"]), `"name":"Mug"`) {
+		t.Fatalf("allowed: data %s, errors %+v", resp.Data["node"], resp.Errors)
+	}
+
+	resp = exec(t, h, &fakeCaller{denied: map[string]bool{"products:view": true}}, query)
+	if string(resp.Data["node"]) != "null" {
+		t.Errorf("denied: node = %s, want null", resp.Data["node"])
+	}
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != problem.CodePermissionDenied {
+		t.Errorf("denied: errors = %+v, want one %s", resp.Errors, problem.CodePermissionDenied)
+	}
+}
+
+// TestNodeReadInScope hands out the node as the tenant's scope reads it,
+// not as the lookup that found its tenant did
+func TestNodeReadInScope(t *testing.T) {
+	tenantID := uuid.New()
+	var scopedReads int
+	q := &dbmock.Querier{
+		GetOrderByGIDFunc: func(ctx context.Context, g sql.NullInt64) (database.Order, error) {
+			if _, ok := ctx.Value(scopedTo{}).(uuid.UUID); !ok {
+				return database.Order{ID: uuid.New(), Gid: g, TenantID: tenantID}, nil
+			}
+			inScope(t, ctx, tenantID)
+			scopedReads++
+			// Isolation hides it from the tenant's connection
+			return database.Order{}, sql.ErrNoRows
+		},
+	}
+
+	resp := exec(t, newTestHandler(q), &fakeCaller{}, `{ node(id: "gid://mystoreos/Order/5") { ... on Order { orderNumber } } }`)
+	if len(resp.Errors) > 0 || string(resp.Data["node"]) != "null" {
+		t.Errorf("data %s, errors %+v, want a null node", resp.Data["node"], resp.Errors)
+	}
+	if scopedReads != 1 {
+		t.Errorf("read the order %d times in the tenant scope, want once", scopedReads)
+	}
+}
+
+func TestOrderNode(t *testing.T) {
+	tenantID := uuid.New()
+	q := &dbmock.Querier{
+		GetOrderByGIDFunc: func(_ context.Context, g sql.NullInt64) (database.Order, error) {
+			return database.Order{ID: uuid.New(), Gid: g, TenantID: tenantID, OrderNumber: 1001, TotalCents: 1 << 40}, nil
+		},
+	}
+	caller := &fakeCaller{}
+
+	resp := exec(t, newTestHandler(q), caller, `{ node(id: "gid://mystoreos/Order/5") { ... on Order { orderNumber totalCents tags } } }`)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("errors: %+v", resp.Errors)
+	}
+	if got, want := string(resp.Data["node"]), `{"orderNumber":"1001","totalCents":1099511627776,"tags":[]}`; got != want {
+		t.Errorf("node = %s, want %s", got, want)
+	}
+	if len(caller.checked) != 1 || caller.checked[0] != "orders:view" {
+		t.Errorf("checked %v, want orders:view", caller.checked)
+	}
+}
+
+func TestNodeMissingIsNull(t *testing.T) {
+	q := &dbmock.Querier{
+		GetTenantByGIDFunc: func(context.Context, sql.NullInt64) (database.Tenant, error) {
+			return database.Tenant{}, sql.ErrNoRows
+		},
+	}
+	resp := exec(t, newTestHandler(q), &fakeCaller{}, `{ node(id: "gid://mystoreos/Tenant/1") { id } }`)
+	if len(resp.Errors) > 0 || string(resp.Data["node"]) != "null" {
+		t.Errorf("data %s, errors %+v, want a null node", resp.Data["node"], resp.Errors)
+	}
+}
+
+func TestProductDeleteIsAudited(t *testing.T) {
+	storeID, tenantID, productID := uuid.New(), uuid.New(), uuid.New()
+	product := database.Product{ID: productID, Gid: sql.NullInt64{Int64: 3, Valid: true}, StoreID: storeID}
+	q := &dbmock.Querier{
+		GetProductByGIDFunc: func(context.Context, sql.NullInt64) (database.Product, error) { return product, nil },
+		GetStoreByIDFunc: func(_ context.Context, id uuid.UUID) (database.Store, error) {
+			return database.Store{ID: id, TenantID: uuid.NullUUID{UUID: tenantID, Valid: true}}, nil
+		},
+		SoftDeleteProductFunc: func(ctx context.Context, arg database.SoftDeleteProductParams) (database.Product, error) {
+			inScope(t, ctx, tenantID)
+			if arg.ID != productID || arg.StoreID != storeID {
+				t.Errorf("deleted %+v, want product %s in store %s", arg, productID, storeID)
+			}
+			return product, nil
+		},
+	}
+	caller := &fakeCaller{}
+
+	resp := exec(t, newTestHandler(q), caller, `mutation { productDelete(id: "gid://mystoreos/Product/3") }`)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("errors: %+v", resp.Errors)
+	}
+	if string(resp.Data["productDelete"]) != `"gid://mystoreos/Product/3"` {
+		t.Errorf("productDelete = %s", resp.Data["productDelete"])
+	}
+	if len(caller.checked) != 1 || caller.checked[0] != "products:delete" {
+		t.Errorf("checked %v, want products:delete", caller.checked)
+	}
+	if len(caller.audited) != 1 || caller.audited[0] != gid.ProductGID(3) {
+		t.Errorf("audited %v, want the product", caller.audited)
+	}
+}
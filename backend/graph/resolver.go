@@ -0,0 +1,306 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/service/nodes"
+	"github.com/dfodeker/terminus/internal/service/products"
+	"github.com/dfodeker/terminus/internal/service/stores"
+	"github.com/dfodeker/terminus/internal/service/tenants"
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// parseID reads a GID in canonical or base64 form, as GET
+// /api/v1/nodes/{gid} does
+func parseID(id graphql.ID) (gid.GID, error) {
+	g, err := gid.Parse(string(id))
+	if err != nil {
+		g, err = gid.ParseBase64(string(id))
+	}
+	if err != nil {
+		return gid.GID{}, service.Invalid("Invalid GID")
+	}
+	return g, nil
+}
+
+// lookup finds the record id names, which must be of type want
+func (r *resolver) lookup(ctx context.Context, id graphql.ID, want gid.EntityType) (nodes.Node, error) {
+	g, err := parseID(id)
+	if err != nil {
+		return nodes.Node{}, err
+	}
+	if g.Type != want {
+		return nodes.Node{}, service.Invalid("Expected the GID of a " + string(want))
+	}
+	return r.cfg.Nodes.Lookup(ctx, g)
+}
+
+// authorize checks the caller may use permission on node's tenant and
+// reads the node again there. The first lookup had to run past tenant
+// isolation to find the owner; only what the tenant's scope returns is
+// handed out. It returns the scoped context for the rest of the resolver.
+func (r *resolver) authorize(ctx context.Context, node nodes.Node, permission string) (context.Context, nodes.Node, error) {
+	scoped, err := callerFrom(ctx).Authorize(ctx, node.TenantID, permission)
+	if err != nil {
+		return nil, nodes.Node{}, err
+	}
+	node, err = r.cfg.Nodes.Lookup(scoped, node.GID)
+	if err != nil {
+		return nil, nodes.Node{}, err
+	}
+	return scoped, node, nil
+}
+
+// recordGID names a record for the audit log, if it has been given a GID
+func recordGID(t gid.EntityType, g sql.NullInt64) gid.GID {
+	if !g.Valid {
+		return gid.GID{}
+	}
+	return gid.New(t, uint64(g.Int64))
+}
+
+// Node looks up any record by its GID. Records that don't exist are null;
+// ones the caller can't read are an error, as they are over REST.
+func (r *resolver) Node(ctx context.Context, args struct{ ID graphql.ID }) (*nodeResolver, error) {
+	g, err := parseID(args.ID)
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to look up node")
+	}
+	node, err := r.cfg.Nodes.Lookup(ctx, g)
+	if err == nil {
+		_, node, err = r.authorize(ctx, node, r.cfg.NodePermissions[g.Type])
+	}
+	if errors.Is(err, service.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to look up node")
+	}
+
+	switch v := node.Value.(type) {
+	case database.Tenant:
+		return &nodeResolver{&tenantResolver{r: r, tenant: v}}, nil
+	case database.Store:
+		return &nodeResolver{&storeResolver{r: r, store: v}}, nil
+	case database.Product:
+		return &nodeResolver{&productResolver{r: r, tenantID: node.TenantID, product: v}}, nil
+	case database.ProductVariant:
+		return &nodeResolver{&variantResolver{variant: v}}, nil
+	case database.Collection:
+		return &nodeResolver{&collectionResolver{collection: v}}, nil
+	case database.Customer:
+		return &nodeResolver{&customerResolver{customer: v}}, nil
+	case database.Order:
+		return &nodeResolver{&orderResolver{order: v}}, nil
+	}
+	return nil, resolverError(ctx, service.Invalid("Nodes of type "+string(g.Type)+" aren't in the GraphQL API"), "Unable to look up node")
+}
+
+// Tenants pages through the signed-in user's tenants
+func (r *resolver) Tenants(ctx context.Context, args pageArgs) (*connection[*tenantResolver], error) {
+	user, err := callerFrom(ctx).User(ctx, false)
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to retrieve tenants")
+	}
+	page, err := args.page()
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to retrieve tenants")
+	}
+	list, err := r.cfg.Tenants.ListForUser(ctx, user, page)
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to retrieve tenants")
+	}
+	return newConnection(list,
+		func(row database.GetTenantsByUserIDPaginatedRow) (time.Time, uuid.UUID) { return row.CreatedAt, row.ID },
+		func(row database.GetTenantsByUserIDPaginatedRow) *tenantResolver {
+			return &tenantResolver{r: r, tenant: database.Tenant{
+				ID:        row.ID,
+				Gid:       row.Gid,
+				Name:      row.Name,
+				Status:    row.Status,
+				CreatedAt: row.CreatedAt,
+				UpdatedAt: row.UpdatedAt,
+			}}
+		},
+	), nil
+}
+
+// TenantCreate creates a tenant owned by the signed-in user, who must have
+// verified their email address
+func (r *resolver) TenantCreate(ctx context.Context, args struct{ Name string }) (*tenantResolver, error) {
+	user, err := callerFrom(ctx).User(ctx, true)
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to create tenant")
+	}
+	created, err := r.cfg.Tenants.Create(ctx, tenants.CreateInput{Name: args.Name, OwnerID: user})
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to create tenant")
+	}
+	return &tenantResolver{r: r, tenant: created.Tenant}, nil
+}
+
+type storeCreateInput struct {
+	TenantID graphql.ID
+	Name     string
+	Handle   string
+	Plan     *string
+}
+
+// StoreCreate needs stores:create and room in the tenant's plan
+func (r *resolver) StoreCreate(ctx context.Context, args struct{ Input storeCreateInput }) (*storeResolver, error) {
+	in := args.Input
+	tenant, err := r.lookup(ctx, in.TenantID, gid.EntityTenant)
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to create store")
+	}
+	scoped, tenant, err := r.authorize(ctx, tenant, "stores:create")
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to create store")
+	}
+
+	if err := r.cfg.Entitlements.CheckStores(scoped, tenant.TenantID); err != nil {
+		return nil, resolverError(ctx, err, "Unable to create store")
+	}
+	var requested string
+	if in.Plan != nil {
+		requested = *in.Plan
+	}
+	plan, err := r.cfg.StorePlan(scoped, tenant.TenantID, requested)
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to create store")
+	}
+
+	store, err := r.cfg.Stores.Create(scoped, stores.CreateInput{
+		TenantID: tenant.TenantID,
+		Name:     in.Name,
+		Handle:   in.Handle,
+		Plan:     plan,
+	})
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to create store")
+	}
+	callerFrom(ctx).Audit(scoped, tenant.TenantID, store.ID, recordGID(gid.EntityStore, store.Gid), nil, store)
+	return &storeResolver{r: r, store: store}, nil
+}
+
+type productCreateInput struct {
+	StoreID          graphql.ID
+	Name             string
+	Handle           string
+	Description      *string
+	InventoryTracked *bool
+	Sku              *string
+	Tags             *string
+	Status           *string
+}
+
+// ProductCreate needs products:create and room in the store's plan
+func (r *resolver) ProductCreate(ctx context.Context, args struct{ Input productCreateInput }) (*productResolver, error) {
+	in := args.Input
+	node, err := r.lookup(ctx, in.StoreID, gid.EntityStore)
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to create product")
+	}
+	scoped, node, err := r.authorize(ctx, node, "products:create")
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to create product")
+	}
+	store := node.Value.(database.Store)
+
+	if err := r.cfg.Entitlements.CheckProducts(scoped, store.ID); err != nil {
+		return nil, resolverError(ctx, err, "Unable to create product")
+	}
+	create := products.CreateInput{
+		StoreID:     store.ID,
+		Name:        in.Name,
+		Handle:      in.Handle,
+		Description: in.Description,
+		SKU:         in.Sku,
+		Tags:        in.Tags,
+	}
+	if in.InventoryTracked != nil {
+		create.InventoryTracked = *in.InventoryTracked
+	}
+	if in.Status != nil {
+		create.Status = *in.Status
+	}
+	product, err := r.cfg.Products.Create(scoped, create)
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to create product")
+	}
+
+	r.cfg.ProductWritten(scoped, product)
+	callerFrom(ctx).Audit(scoped, node.TenantID, store.ID, recordGID(gid.EntityProduct, product.Gid), nil, product)
+	return &productResolver{r: r, tenantID: node.TenantID, product: product}, nil
+}
+
+type productUpdateInput struct {
+	ID               graphql.ID
+	Name             *string
+	Handle           *string
+	Description      *string
+	InventoryTracked *bool
+	Sku              *string
+	Tags             *string
+	Status           *string
+}
+
+// ProductUpdate needs products:edit. Fields left out are unchanged.
+func (r *resolver) ProductUpdate(ctx context.Context, args struct{ Input productUpdateInput }) (*productResolver, error) {
+	in := args.Input
+	node, err := r.lookup(ctx, in.ID, gid.EntityProduct)
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to update product")
+	}
+	scoped, node, err := r.authorize(ctx, node, "products:edit")
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to update product")
+	}
+	existing := node.Value.(database.Product)
+
+	product, err := r.cfg.Products.Update(scoped, products.UpdateInput{
+		StoreID:          existing.StoreID,
+		ID:               existing.ID,
+		Name:             in.Name,
+		Handle:           in.Handle,
+		Description:      in.Description,
+		InventoryTracked: in.InventoryTracked,
+		SKU:              in.Sku,
+		Tags:             in.Tags,
+		Status:           in.Status,
+	})
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to update product")
+	}
+
+	r.cfg.ProductWritten(scoped, product)
+	callerFrom(ctx).Audit(scoped, node.TenantID, product.StoreID, recordGID(gid.EntityProduct, product.Gid), existing, product)
+	return &productResolver{r: r, tenantID: node.TenantID, product: product}, nil
+}
+
+// ProductDelete moves a product to the trash and needs products:delete
+func (r *resolver) ProductDelete(ctx context.Context, args struct{ ID graphql.ID }) (graphql.ID, error) {
+	node, err := r.lookup(ctx, args.ID, gid.EntityProduct)
+	if err != nil {
+		return "", resolverError(ctx, err, "Unable to delete product")
+	}
+	scoped, node, err := r.authorize(ctx, node, "products:delete")
+	if err != nil {
+		return "", resolverError(ctx, err, "Unable to delete product")
+	}
+	existing := node.Value.(database.Product)
+
+	deleted, err := r.cfg.Products.Delete(scoped, existing.StoreID, existing.ID)
+	if err != nil {
+		return "", resolverError(ctx, err, "Unable to delete product")
+	}
+	callerFrom(ctx).Audit(scoped, node.TenantID, deleted.StoreID, recordGID(gid.EntityProduct, deleted.Gid), deleted, nil)
+	return nodeID(gid.EntityProduct, deleted.Gid), nil
+}
@@ -0,0 +1,441 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// nodeID is the id of a record: its GID, as the REST API reports it
+func nodeID(t gid.EntityType, g sql.NullInt64) graphql.ID {
+	return graphql.ID(gid.New(t, uint64(g.Int64)).String())
+}
+
+func nullString(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
+
+// nonNil is s, or an empty list in its place, for non-null list fields
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// nodeResolver is the Node interface, holding one of the object resolvers
+type nodeResolver struct {
+	node interface{ ID() graphql.ID }
+}
+
+func (n *nodeResolver) ID() graphql.ID { return n.node.ID() }
+
+func (n *nodeResolver) ToTenant() (*tenantResolver, bool) {
+	t, ok := n.node.(*tenantResolver)
+	return t, ok
+}
+
+func (n *nodeResolver) ToStore() (*storeResolver, bool) {
+	s, ok := n.node.(*storeResolver)
+	return s, ok
+}
+
+func (n *nodeResolver) ToProduct() (*productResolver, bool) {
+	p, ok := n.node.(*productResolver)
+	return p, ok
+}
+
+func (n *nodeResolver) ToProductVariant() (*variantResolver, bool) {
+	v, ok := n.node.(*variantResolver)
+	return v, ok
+}
+
+func (n *nodeResolver) ToCollection() (*collectionResolver, bool) {
+	c, ok := n.node.(*collectionResolver)
+	return c, ok
+}
+
+func (n *nodeResolver) ToCustomer() (*customerResolver, bool) {
+	c, ok := n.node.(*customerResolver)
+	return c, ok
+}
+
+func (n *nodeResolver) ToOrder() (*orderResolver, bool) {
+	o, ok := n.node.(*orderResolver)
+	return o, ok
+}
+
+type tenantResolver struct {
+	r      *resolver
+	tenant database.Tenant
+}
+
+func (t *tenantResolver) ID() graphql.ID { return nodeID(gid.EntityTenant, t.tenant.Gid) }
+
+func (t *tenantResolver) Name() string { return t.tenant.Name }
+
+func (t *tenantResolver) Status() string { return t.tenant.Status }
+
+func (t *tenantResolver) CreatedAt() string { return formatTime(t.tenant.CreatedAt) }
+
+func (t *tenantResolver) UpdatedAt() string { return formatTime(t.tenant.UpdatedAt) }
+
+// Stores needs stores:view, as GET /tenants/{tenantID}/stores does
+func (t *tenantResolver) Stores(ctx context.Context, args pageArgs) (*connection[*storeResolver], error) {
+	scoped, err := callerFrom(ctx).Authorize(ctx, t.tenant.ID, "stores:view")
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to retrieve stores")
+	}
+	page, err := args.page()
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to retrieve stores")
+	}
+	stores, err := t.r.cfg.Stores.List(scoped, t.tenant.ID, page)
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to retrieve stores")
+	}
+	return newConnection(stores,
+		func(row database.GetStoresByTenantIDPaginatedRow) (time.Time, uuid.UUID) {
+			return row.CreatedAt, row.ID
+		},
+		func(row database.GetStoresByTenantIDPaginatedRow) *storeResolver {
+			return &storeResolver{r: t.r, store: database.Store{
+				ID:              row.ID,
+				Gid:             row.Gid,
+				Name:            row.Name,
+				Handle:          row.Handle,
+				Address:         row.Address,
+				Status:          row.Status,
+				DefaultCurrency: row.DefaultCurrency,
+				DefaultLocale:   row.DefaultLocale,
+				Timezone:        row.Timezone,
+				Plan:            row.Plan,
+				TenantID:        row.TenantID,
+				CreatedAt:       row.CreatedAt,
+				UpdatedAt:       row.UpdatedAt,
+			}}
+		},
+	), nil
+}
+
+// Members needs only membership, as GET /tenants/{tenantID}/members does
+func (t *tenantResolver) Members(ctx context.Context, args pageArgs) (*connection[*memberResolver], error) {
+	scoped, err := callerFrom(ctx).Authorize(ctx, t.tenant.ID, "")
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to retrieve members")
+	}
+	page, err := args.page()
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to retrieve members")
+	}
+	hasCursor, createdAt, id, limit := page.QueryArgs()
+	rows, err := t.r.cfg.DB.GetTenantUsersWithDetailsPaginated(scoped, database.GetTenantUsersWithDetailsPaginatedParams{
+		TenantID:        t.tenant.ID,
+		HasCursor:       hasCursor,
+		CursorCreatedAt: createdAt,
+		CursorID:        id,
+		RowLimit:        limit,
+	})
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to retrieve members")
+	}
+	members := service.NewPage(rows, page.Limit)
+
+	// Roles for the whole page in one query
+	memberIDs := make([]uuid.UUID, 0, len(members.Items))
+	for _, member := range members.Items {
+		memberIDs = append(memberIDs, member.ID)
+	}
+	assigned, err := t.r.cfg.DB.GetRoleNamesByTenantUserIDs(scoped, memberIDs)
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to retrieve member roles")
+	}
+	roles := make(map[uuid.UUID][]string, len(assigned))
+	for _, row := range assigned {
+		roles[row.TenantUserID] = row.RoleNames
+	}
+
+	return newConnection(members,
+		func(row database.GetTenantUsersWithDetailsPaginatedRow) (time.Time, uuid.UUID) {
+			return row.CreatedAt, row.ID
+		},
+		func(row database.GetTenantUsersWithDetailsPaginatedRow) *memberResolver {
+			return &memberResolver{member: row, roles: roles[row.ID]}
+		},
+	), nil
+}
+
+type storeResolver struct {
+	r     *resolver
+	store database.Store
+}
+
+func (s *storeResolver) ID() graphql.ID { return nodeID(gid.EntityStore, s.store.Gid) }
+
+func (s *storeResolver) Name() string { return s.store.Name }
+
+func (s *storeResolver) Handle() string { return s.store.Handle }
+
+func (s *storeResolver) Status() string { return s.store.Status }
+
+func (s *storeResolver) Plan() string { return s.store.Plan }
+
+func (s *storeResolver) DefaultCurrency() string { return s.store.DefaultCurrency }
+
+func (s *storeResolver) Timezone() string { return s.store.Timezone }
+
+func (s *storeResolver) CreatedAt() string { return formatTime(s.store.CreatedAt) }
+
+func (s *storeResolver) UpdatedAt() string { return formatTime(s.store.UpdatedAt) }
+
+// Products needs products:view in the store's tenant
+func (s *storeResolver) Products(ctx context.Context, args pageArgs) (*connection[*productResolver], error) {
+	tenantID := s.store.TenantID.UUID
+	scoped, err := callerFrom(ctx).Authorize(ctx, tenantID, "products:view")
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to retrieve products")
+	}
+	page, err := args.page()
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to retrieve products")
+	}
+	hasCursor, createdAt, id, limit := page.QueryArgs()
+	rows, err := s.r.cfg.DB.GetProductsByStorePaginated(scoped, database.GetProductsByStorePaginatedParams{
+		StoreID:         s.store.ID,
+		HasCursor:       hasCursor,
+		CursorCreatedAt: createdAt,
+		CursorID:        id,
+		RowLimit:        limit,
+	})
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to retrieve products")
+	}
+	return newConnection(service.NewPage(rows, page.Limit),
+		func(row database.GetProductsByStorePaginatedRow) (time.Time, uuid.UUID) { return row.CreatedAt, row.ID },
+		func(row database.GetProductsByStorePaginatedRow) *productResolver {
+			return &productResolver{r: s.r, tenantID: tenantID, product: database.Product{
+				ID:               row.ID,
+				Gid:              row.Gid,
+				StoreID:          row.StoreID,
+				Handle:           row.Handle,
+				Name:             row.Name,
+				Description:      row.Description,
+				InventoryTracked: row.InventoryTracked,
+				Sku:              row.Sku,
+				Tags:             row.Tags,
+				Status:           row.Status,
+				CreatedAt:        row.CreatedAt,
+				UpdatedAt:        row.UpdatedAt,
+			}}
+		},
+	), nil
+}
+
+type productResolver struct {
+	r *resolver
+	// tenantID owns the product's store, for checking its variants
+	tenantID uuid.UUID
+	product  database.Product
+}
+
+func (p *productResolver) ID() graphql.ID { return nodeID(gid.EntityProduct, p.product.Gid) }
+
+func (p *productResolver) Handle() string { return p.product.Handle }
+
+func (p *productResolver) Name() string { return p.product.Name }
+
+func (p *productResolver) Description() *string { return nullString(p.product.Description) }
+
+func (p *productResolver) InventoryTracked() bool { return p.product.InventoryTracked }
+
+func (p *productResolver) Sku() *string { return nullString(p.product.Sku) }
+
+func (p *productResolver) Tags() *string { return nullString(p.product.Tags) }
+
+func (p *productResolver) Status() string { return p.product.Status }
+
+func (p *productResolver) CreatedAt() string { return formatTime(p.product.CreatedAt) }
+
+func (p *productResolver) UpdatedAt() string { return formatTime(p.product.UpdatedAt) }
+
+// Variants needs products:view, as the REST variant list does
+func (p *productResolver) Variants(ctx context.Context, args pageArgs) (*connection[*variantResolver], error) {
+	scoped, err := callerFrom(ctx).Authorize(ctx, p.tenantID, "products:view")
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to retrieve variants")
+	}
+	page, err := args.page()
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to retrieve variants")
+	}
+	hasCursor, createdAt, id, limit := page.QueryArgs()
+	rows, err := p.r.cfg.DB.GetProductVariantsByProductIDPaginated(scoped, database.GetProductVariantsByProductIDPaginatedParams{
+		ProductID:       p.product.ID,
+		HasCursor:       hasCursor,
+		CursorCreatedAt: createdAt,
+		CursorID:        id,
+		RowLimit:        limit,
+	})
+	if err != nil {
+		return nil, resolverError(ctx, err, "Unable to retrieve variants")
+	}
+	return newConnection(service.NewPage(rows, page.Limit),
+		func(row database.GetProductVariantsByProductIDPaginatedRow) (time.Time, uuid.UUID) {
+			return row.CreatedAt, row.ID
+		},
+		func(row database.GetProductVariantsByProductIDPaginatedRow) *variantResolver {
+			return &variantResolver{variant: database.ProductVariant{
+				ID:         row.ID,
+				Gid:        row.Gid,
+				TenantID:   row.TenantID,
+				StoreID:    row.StoreID,
+				ProductID:  row.ProductID,
+				Sku:        row.Sku,
+				Title:      row.Title,
+				PriceCents: row.PriceCents,
+				Status:     row.Status,
+				CreatedAt:  row.CreatedAt,
+				UpdatedAt:  row.UpdatedAt,
+			}}
+		},
+	), nil
+}
+
+type variantResolver struct {
+	variant database.ProductVariant
+}
+
+func (v *variantResolver) ID() graphql.ID { return nodeID(gid.EntityProductVariant, v.variant.Gid) }
+
+func (v *variantResolver) Title() string { return v.variant.Title }
+
+func (v *variantResolver) Sku() *string { return nullString(v.variant.Sku) }
+
+func (v *variantResolver) PriceCents() int32 { return v.variant.PriceCents }
+
+func (v *variantResolver) Status() string { return v.variant.Status }
+
+func (v *variantResolver) CreatedAt() string { return formatTime(v.variant.CreatedAt) }
+
+func (v *variantResolver) UpdatedAt() string { return formatTime(v.variant.UpdatedAt) }
+
+type collectionResolver struct {
+	collection database.Collection
+}
+
+func (c *collectionResolver) ID() graphql.ID { return nodeID(gid.EntityCollection, c.collection.Gid) }
+
+func (c *collectionResolver) Handle() string { return c.collection.Handle }
+
+func (c *collectionResolver) Title() string { return c.collection.Title }
+
+func (c *collectionResolver) Description() *string { return nullString(c.collection.Description) }
+
+func (c *collectionResolver) Kind() string { return c.collection.Kind }
+
+func (c *collectionResolver) CreatedAt() string { return formatTime(c.collection.CreatedAt) }
+
+func (c *collectionResolver) UpdatedAt() string { return formatTime(c.collection.UpdatedAt) }
+
+type customerResolver struct {
+	customer database.Customer
+}
+
+func (c *customerResolver) ID() graphql.ID { return nodeID(gid.EntityCustomer, c.customer.Gid) }
+
+func (c *customerResolver) Email() string { return string(c.customer.Email) }
+
+func (c *customerResolver) FirstName() *string { return nullString(c.customer.FirstName) }
+
+func (c *customerResolver) LastName() *string { return nullString(c.customer.LastName) }
+
+func (c *customerResolver) Phone() *string {
+	if !c.customer.Phone.Valid {
+		return nil
+	}
+	return &c.customer.Phone.String
+}
+
+func (c *customerResolver) AcceptsMarketing() bool { return c.customer.AcceptsMarketing }
+
+func (c *customerResolver) Status() string { return c.customer.Status }
+
+func (c *customerResolver) Tags() []string { return nonNil(c.customer.Tags) }
+
+func (c *customerResolver) ErasedAt() *string {
+	if !c.customer.ErasedAt.Valid {
+		return nil
+	}
+	erased := formatTime(c.customer.ErasedAt.Time)
+	return &erased
+}
+
+func (c *customerResolver) CreatedAt() string { return formatTime(c.customer.CreatedAt) }
+
+func (c *customerResolver) UpdatedAt() string { return formatTime(c.customer.UpdatedAt) }
+
+type orderResolver struct {
+	order database.Order
+}
+
+func (o *orderResolver) ID() graphql.ID { return nodeID(gid.EntityOrder, o.order.Gid) }
+
+func (o *orderResolver) OrderNumber() string { return strconv.FormatInt(o.order.OrderNumber, 10) }
+
+func (o *orderResolver) Email() *string {
+	if !o.order.Email.Valid {
+		return nil
+	}
+	return &o.order.Email.String
+}
+
+func (o *orderResolver) Status() string { return o.order.Status }
+
+func (o *orderResolver) FinancialStatus() string { return o.order.FinancialStatus }
+
+func (o *orderResolver) FulfillmentStatus() string { return o.order.FulfillmentStatus }
+
+func (o *orderResolver) Currency() string { return o.order.Currency }
+
+func (o *orderResolver) SubtotalCents() float64 { return float64(o.order.SubtotalCents) }
+
+func (o *orderResolver) ShippingCents() float64 { return float64(o.order.ShippingCents) }
+
+func (o *orderResolver) TaxCents() float64 { return float64(o.order.TaxCents) }
+
+func (o *orderResolver) DiscountCents() float64 { return float64(o.order.DiscountCents) }
+
+func (o *orderResolver) TotalCents() float64 { return float64(o.order.TotalCents) }
+
+func (o *orderResolver) Tags() []string { return nonNil(o.order.Tags) }
+
+func (o *orderResolver) PlacedAt() string { return formatTime(o.order.PlacedAt) }
+
+func (o *orderResolver) CreatedAt() string { return formatTime(o.order.CreatedAt) }
+
+func (o *orderResolver) UpdatedAt() string { return formatTime(o.order.UpdatedAt) }
+
+type memberResolver struct {
+	member database.GetTenantUsersWithDetailsPaginatedRow
+	roles  []string
+}
+
+func (m *memberResolver) UserID() graphql.ID { return graphql.ID(m.member.UserID.String()) }
+
+func (m *memberResolver) Email() string { return m.member.Email }
+
+func (m *memberResolver) Status() string { return m.member.Status }
+
+func (m *memberResolver) Roles() []string { return nonNil(m.roles) }
+
+func (m *memberResolver) JoinedAt() string { return formatTime(m.member.CreatedAt) }
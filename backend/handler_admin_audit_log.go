@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/platform"
+	"github.com/google/uuid"
+)
+
+type adminAuditLogEntryResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	ActorUserID *uuid.UUID `json:"actor_user_id"`
+	ActorEmail  string     `json:"actor_email"`
+	Action      string     `json:"action"`
+	TenantID    *uuid.UUID `json:"tenant_id"`
+	Reason      string     `json:"reason"`
+	RequestID   string     `json:"request_id,omitempty"`
+	IP          string     `json:"ip,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+var adminAuditLogCursorCodec = CursorCodec[AuditLogCursor]{
+	Validate: func(c AuditLogCursor) error {
+		if c.CreatedAt.IsZero() || c.ID == uuid.Nil {
+			return errors.New("invalid cursor: missing required fields")
+		}
+		return nil
+	},
+}
+
+// handlerAdminAuditLogList returns what platform staff did to tenants,
+// newest first, of one tenant with ?tenant_id=
+func (cfg *apiConfig) handlerAdminAuditLogList(w http.ResponseWriter, r *http.Request) {
+	filter, err := platform.ParseFilter(r.URL.Query(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+	cursor, hasCursor, err := adminAuditLogCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.db.ListPlatformAuditLog(r.Context(), database.ListPlatformAuditLogParams{
+		TenantID:        uuid.NullUUID{UUID: filter.TenantID, Valid: filter.TenantID != uuid.Nil},
+		HasCursor:       hasCursor,
+		CursorCreatedAt: cursor.CreatedAt,
+		CursorID:        cursor.ID,
+		RowLimit:        int32(pageParams.Limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve audit log", err)
+		return
+	}
+
+	hasMore := len(rows) > pageParams.Limit
+	if hasMore {
+		rows = rows[:pageParams.Limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = adminAuditLogCursorCodec.Encode(AuditLogCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]adminAuditLogEntryResponse, 0, len(rows))
+	for _, e := range rows {
+		resp := adminAuditLogEntryResponse{
+			ID:         e.ID,
+			ActorEmail: e.ActorEmail,
+			Action:     e.Action,
+			Reason:     e.Reason,
+			RequestID:  e.RequestID.String,
+			IP:         e.Ip.String,
+			CreatedAt:  e.CreatedAt,
+		}
+		if e.ActorUserID.Valid {
+			resp.ActorUserID = &e.ActorUserID.UUID
+		}
+		if e.TenantID.Valid {
+			resp.TenantID = &e.TenantID.UUID
+		}
+		response = append(response, resp)
+	}
+	respondWithJSON(w, http.StatusOK, PageResponse[adminAuditLogEntryResponse]{
+		Data: response,
+		Page: PageInfo{Limit: pageParams.Limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/crypto/keystore"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/platform"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/google/uuid"
+)
+
+// adminEncryptionKeyResponse describes a key without its material
+type adminEncryptionKeyResponse struct {
+	ID        string     `json:"id"`
+	Purpose   string     `json:"purpose"`
+	KEKID     string     `json:"kek_id"`
+	Active    bool       `json:"active"`
+	CreatedAt time.Time  `json:"created_at"`
+	RetiredAt *time.Time `json:"retired_at"`
+}
+
+func encryptionKeyToResponse(k database.EncryptionKey) adminEncryptionKeyResponse {
+	resp := adminEncryptionKeyResponse{
+		ID:        k.ID,
+		Purpose:   k.Purpose,
+		KEKID:     k.KekID,
+		Active:    !k.RetiredAt.Valid,
+		CreatedAt: k.CreatedAt,
+	}
+	if k.RetiredAt.Valid {
+		resp.RetiredAt = &k.RetiredAt.Time
+	}
+	return resp
+}
+
+// handlerAdminEncryptionKeysList returns the data and index keys, oldest
+// first, with the KEK wrapping each
+func (cfg *apiConfig) handlerAdminEncryptionKeysList(w http.ResponseWriter, r *http.Request) {
+	keys, err := cfg.db.ListEncryptionKeys(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve encryption keys", err)
+		return
+	}
+	resp := make([]adminEncryptionKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		resp = append(resp, encryptionKeyToResponse(k))
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerAdminEncryptionKeyRotate retires the active data key for a new
+// one. New values are sealed under it at once; stored ones are resealed by
+// a job queued with the rotation.
+func (cfg *apiConfig) handlerAdminEncryptionKeyRotate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	staff := platformStaffFrom(r)
+
+	reason, ok := decodeAdminReason(w, r)
+	if !ok {
+		return
+	}
+
+	var key database.EncryptionKey
+	err := cfg.withTx(r.Context(), func(q *database.Queries) error {
+		var err error
+		key, err = cfg.keys.Rotate(r.Context(), q)
+		if err != nil {
+			return err
+		}
+		if err := cfg.recordPlatformAction(r, q, platform.ActionRotateKey, uuid.Nil, reason); err != nil {
+			return err
+		}
+		_, err = cfg.jobs.EnqueueTx(r.Context(), q, keystore.ResealArgs{})
+		return err
+	})
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to rotate the data key")
+		return
+	}
+
+	// Other instances pick the key up within a minute
+	if err := cfg.keyring.Reload(r.Context()); err != nil {
+		slog.ErrorContext(r.Context(), "reloading encryption keys after rotation failed",
+			"request_id", reqID,
+			"error", err,
+		)
+	}
+
+	slog.WarnContext(r.Context(), "data key rotated",
+		"request_id", reqID,
+		"staff_user_id", staff.UserID,
+		"key_id", key.ID,
+	)
+	respondWithJSON(w, http.StatusCreated, encryptionKeyToResponse(key))
+}
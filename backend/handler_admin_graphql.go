@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/dfodeker/terminus/graph"
+	"github.com/dfodeker/terminus/internal/audit"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/dbpool"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
+)
+
+// maxGraphQLBodyBytes caps a GraphQL request, query and variables together
+const maxGraphQLBodyBytes = 1 << 20
+
+// graphConfig wires the GraphQL Admin API to the services and follow-up
+// work the REST handlers use
+func (cfg *apiConfig) graphConfig() graph.Config {
+	return graph.Config{
+		DB:              cfg.reads,
+		Products:        cfg.services.products,
+		Stores:          cfg.services.stores,
+		Tenants:         cfg.services.tenants,
+		Nodes:           cfg.services.nodes,
+		Entitlements:    cfg.services.entitlements,
+		NodePermissions: nodePermissions,
+		StorePlan:       cfg.storePlan,
+		ProductWritten: func(ctx context.Context, product database.Product) {
+			cfg.syncProductCollectionsAfterWrite(ctx, product.StoreID, product.ID)
+		},
+	}
+}
+
+// handlerAdminGraphQL serves the GraphQL Admin API. Its resolvers check
+// the caller through graphCaller, so a field needs what its REST route
+// does.
+func (cfg *apiConfig) handlerAdminGraphQL(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxGraphQLBodyBytes)
+	caller := &graphCaller{cfg: cfg, r: r}
+	defer caller.release()
+	ctx := graph.WithCaller(r.Context(), caller)
+	cfg.graphQL.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// graphCaller is a GraphQL request's graph.Caller. It makes the same
+// access checks the REST middleware does, so both APIs grant exactly the
+// same things.
+type graphCaller struct {
+	cfg *apiConfig
+	r   *http.Request
+
+	mu sync.Mutex
+	// granted holds the checks already passed, as tenant and permission,
+	// since one query can ask the same thing of many records
+	granted map[string]bool
+	// scope holds the connection of the tenant last authorized for, until
+	// one for another tenant replaces it. Resolvers run one at a time, so
+	// the one that read through it is done by then.
+	scope        context.Context
+	scopeTenant  uuid.UUID
+	releaseScope func()
+}
+
+func (c *graphCaller) User(ctx context.Context, verified bool) (uuid.UUID, error) {
+	user, ok := userFromContext(ctx)
+	if !ok {
+		return uuid.Nil, errUnauthenticated
+	}
+	if err := checkUserToken(ctx); err != nil {
+		return uuid.Nil, err
+	}
+	if verified {
+		if err := c.cfg.checkVerifiedEmail(ctx, user); err != nil {
+			return uuid.Nil, err
+		}
+	}
+	return user, nil
+}
+
+func (c *graphCaller) Authorize(ctx context.Context, tenantID uuid.UUID, permission string) (context.Context, error) {
+	if err := c.check(ctx, tenantID, permission); err != nil {
+		return nil, err
+	}
+	scoped, err := c.scopeTo(tenantID)
+	if err != nil {
+		return nil, &accessFailure{message: "Unable to open a tenant connection", err: err}
+	}
+	return dbpool.WithScope(ctx, scoped), nil
+}
+
+// check makes the checks requireTenantMember and requirePermission would
+// for the tenant, once per tenant and permission
+func (c *graphCaller) check(ctx context.Context, tenantID uuid.UUID, permission string) error {
+	key := tenantID.String() + " " + permission
+	c.mu.Lock()
+	done := c.granted[key]
+	c.mu.Unlock()
+	if done {
+		return nil
+	}
+
+	if err := checkAPIKeyTenant(ctx, tenantID); err != nil {
+		return err
+	}
+	if err := c.cfg.checkClaimedTenant(ctx, tenantID); err != nil {
+		return err
+	}
+	tc, err := c.cfg.tenantMember(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if permission != "" {
+		if err := c.cfg.checkTenantPermission(ctx, tc, permission); err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	if c.granted == nil {
+		c.granted = map[string]bool{}
+	}
+	c.granted[key] = true
+	c.mu.Unlock()
+	return nil
+}
+
+// scopeTo returns a context whose queries see only tenantID's rows, as a
+// REST request's do behind requireTenantMember
+func (c *graphCaller) scopeTo(tenantID uuid.UUID) (context.Context, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.scope != nil && c.scopeTenant == tenantID {
+		return c.scope, nil
+	}
+	c.releaseLocked()
+	scoped, release, err := dbpool.WithTenant(c.r.Context(), c.cfg.pool, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	c.scope, c.scopeTenant, c.releaseScope = scoped, tenantID, release
+	return scoped, nil
+}
+
+// release returns the tenant connection to its pool once the request is
+// served
+func (c *graphCaller) release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.releaseLocked()
+}
+
+func (c *graphCaller) releaseLocked() {
+	if c.releaseScope != nil {
+		c.releaseScope()
+	}
+	c.scope, c.releaseScope = nil, nil
+}
+
+// Audit writes the entry the auditLog middleware would for the change
+func (c *graphCaller) Audit(ctx context.Context, tenantID, storeID uuid.UUID, entity gid.GID, before, after any) {
+	beforeState, afterState := auditState(graphAuditState(before)), auditState(graphAuditState(after))
+
+	action := audit.ActionUpdate
+	switch {
+	case before == nil:
+		action = audit.ActionCreate
+	case after == nil:
+		action = audit.ActionDelete
+	}
+	entityID := audit.EntityID(afterState)
+	if entityID == uuid.Nil {
+		entityID = audit.EntityID(beforeState)
+	}
+
+	reqID, ip := middleware.GetRequestID(ctx), middleware.ClientIP(c.r)
+	arg := database.CreateAuditLogEntryParams{
+		TenantID:   tenantID,
+		StoreID:    uuid.NullUUID{UUID: storeID, Valid: storeID != uuid.Nil},
+		Action:     action,
+		EntityID:   uuid.NullUUID{UUID: entityID, Valid: entityID != uuid.Nil},
+		Method:     c.r.Method,
+		Route:      "/admin/graphql",
+		Path:       c.r.URL.Path,
+		StatusCode: http.StatusOK,
+		RequestID:  sql.NullString{String: reqID, Valid: reqID != ""},
+		Ip:         sql.NullString{String: ip, Valid: ip != ""},
+		Before:     pqtype.NullRawMessage{RawMessage: beforeState, Valid: beforeState != nil},
+		After:      pqtype.NullRawMessage{RawMessage: afterState, Valid: afterState != nil},
+	}
+	if key, ok := apiKeyFromContext(ctx); ok {
+		arg.ActorApiKeyID = uuid.NullUUID{UUID: key.ID, Valid: true}
+	}
+	if user, ok := userFromContext(ctx); ok {
+		arg.ActorUserID = uuid.NullUUID{UUID: user, Valid: true}
+	}
+	if !entity.IsZero() {
+		arg.EntityType = sql.NullString{String: string(entity.Type), Valid: true}
+		arg.EntityGid = sql.NullString{String: entity.String(), Valid: true}
+	}
+	if changes := audit.Diff(beforeState, afterState); changes != nil {
+		if data, err := json.Marshal(changes); err == nil {
+			arg.Changes = pqtype.NullRawMessage{RawMessage: data, Valid: true}
+		}
+	}
+
+	// Like the middleware, a failed write is logged, not reported
+	storeCtx := context.WithoutCancel(ctx)
+	if err := c.cfg.db.CreateAuditLogEntry(storeCtx, arg); err != nil {
+		slog.ErrorContext(storeCtx, "audit log write failed",
+			"request_id", reqID,
+			"tenant_id", tenantID,
+			"route", arg.Route,
+			"error", err,
+		)
+	}
+}
+
+// graphAuditState puts a record in the shape its REST endpoint returns, so
+// entries diff the same whichever API made the change
+func graphAuditState(v any) any {
+	switch v := v.(type) {
+	case database.Store:
+		return tenantStoreToResponse(v)
+	case database.Product:
+		return tenantProductToResponse(v)
+	}
+	return v
+}
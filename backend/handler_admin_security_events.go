@@ -0,0 +1,101 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/google/uuid"
+)
+
+type adminSecurityEventResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Kind       string     `json:"kind"`
+	UserID     *uuid.UUID `json:"user_id"`
+	CustomerID *uuid.UUID `json:"customer_id"`
+	StoreID    *uuid.UUID `json:"store_id"`
+	Email      string     `json:"email,omitempty"`
+	IP         string     `json:"ip,omitempty"`
+	UserAgent  string     `json:"user_agent,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// handlerAdminSecurityEventsList returns failed sign-ins and the lockouts
+// they caused, newest first, of one user with ?user_id=
+func (cfg *apiConfig) handlerAdminSecurityEventsList(w http.ResponseWriter, r *http.Request) {
+	var userID uuid.NullUUID
+	if raw := strings.TrimSpace(r.URL.Query().Get("user_id")); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "user_id is not an ID", err)
+			return
+		}
+		userID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+	// Events page the same way as the audit log, by time and ID
+	cursor, hasCursor, err := adminAuditLogCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.db.ListSecurityEvents(r.Context(), database.ListSecurityEventsParams{
+		UserID:          userID,
+		HasCursor:       hasCursor,
+		CursorCreatedAt: cursor.CreatedAt,
+		CursorID:        cursor.ID,
+		RowLimit:        int32(pageParams.Limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve security events", err)
+		return
+	}
+
+	hasMore := len(rows) > pageParams.Limit
+	if hasMore {
+		rows = rows[:pageParams.Limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = adminAuditLogCursorCodec.Encode(AuditLogCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]adminSecurityEventResponse, 0, len(rows))
+	for _, e := range rows {
+		resp := adminSecurityEventResponse{
+			ID:        e.ID,
+			Kind:      e.Kind,
+			Email:     e.Email.String,
+			IP:        e.Ip.String,
+			UserAgent: e.UserAgent.String,
+			CreatedAt: e.CreatedAt,
+		}
+		if e.UserID.Valid {
+			resp.UserID = &e.UserID.UUID
+		}
+		if e.CustomerID.Valid {
+			resp.CustomerID = &e.CustomerID.UUID
+		}
+		if e.StoreID.Valid {
+			resp.StoreID = &e.StoreID.UUID
+		}
+		response = append(response, resp)
+	}
+	respondWithJSON(w, http.StatusOK, PageResponse[adminSecurityEventResponse]{
+		Data: response,
+		Page: PageInfo{Limit: pageParams.Limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
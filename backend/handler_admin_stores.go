@@ -0,0 +1,112 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/platform"
+	"github.com/dfodeker/terminus/internal/service/stores"
+	"github.com/google/uuid"
+)
+
+type adminStoreResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	TenantID     *uuid.UUID `json:"tenant_id"`
+	TenantName   string     `json:"tenant_name,omitempty"`
+	TenantStatus string     `json:"tenant_status,omitempty"`
+	Name         string     `json:"name"`
+	Handle       string     `json:"handle"`
+	Status       string     `json:"status"`
+	Plan         string     `json:"plan"`
+	DeletedAt    *time.Time `json:"deleted_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type adminStoreCursor struct {
+	Handle string `json:"handle"`
+}
+
+var adminStoreCursorCodec = CursorCodec[adminStoreCursor]{
+	Validate: func(c adminStoreCursor) error {
+		if c.Handle == "" {
+			return errors.New("invalid cursor: missing required fields")
+		}
+		return nil
+	},
+}
+
+// handlerAdminStoresList lists every store by handle, deleted ones too,
+// narrowed by the filters platform.ParseFilter reads
+func (cfg *apiConfig) handlerAdminStoresList(w http.ResponseWriter, r *http.Request) {
+	filter, err := platform.ParseFilter(r.URL.Query(), stores.Statuses)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+	cursor, hasCursor, err := adminStoreCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.db.ListPlatformStores(r.Context(), database.ListPlatformStoresParams{
+		TenantID:     uuid.NullUUID{UUID: filter.TenantID, Valid: filter.TenantID != uuid.Nil},
+		Status:       sql.NullString{String: filter.Status, Valid: filter.Status != ""},
+		Query:        sql.NullString{String: filter.Query, Valid: filter.Query != ""},
+		HasCursor:    hasCursor,
+		CursorHandle: cursor.Handle,
+		RowLimit:     int32(pageParams.Limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve stores", err)
+		return
+	}
+
+	hasMore := len(rows) > pageParams.Limit
+	if hasMore {
+		rows = rows[:pageParams.Limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		nextCursor, err = adminStoreCursorCodec.Encode(adminStoreCursor{Handle: rows[len(rows)-1].Handle})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]adminStoreResponse, 0, len(rows))
+	for _, row := range rows {
+		resp := adminStoreResponse{
+			ID:           row.ID,
+			TenantName:   row.TenantName.String,
+			TenantStatus: row.TenantStatus.String,
+			Name:         row.Name,
+			Handle:       row.Handle,
+			Status:       row.Status,
+			Plan:         row.Plan,
+			CreatedAt:    row.CreatedAt,
+		}
+		if row.TenantID.Valid {
+			resp.TenantID = &row.TenantID.UUID
+		}
+		if row.DeletedAt.Valid {
+			resp.DeletedAt = &row.DeletedAt.Time
+		}
+		response = append(response, resp)
+	}
+	respondWithJSON(w, http.StatusOK, PageResponse[adminStoreResponse]{
+		Data: response,
+		Page: PageInfo{Limit: pageParams.Limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/platform"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxReasonLength bounds the reason staff give for what they do to a
+// tenant
+const maxReasonLength = 1000
+
+type adminTenantResponse struct {
+	ID               uuid.UUID  `json:"id"`
+	Name             string     `json:"name"`
+	Status           string     `json:"status"`
+	SuspendedAt      *time.Time `json:"suspended_at"`
+	SuspensionReason string     `json:"suspension_reason,omitempty"`
+	StoreCount       int64      `json:"store_count"`
+	MemberCount      int64      `json:"member_count"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+type adminTenantDetailResponse struct {
+	adminTenantResponse
+	Billing billingResponse `json:"billing"`
+}
+
+type adminImpersonationResponse struct {
+	// Token is a bearer token for the tenant's /api/v1/tenants/{tenantID}
+	// routes, and no others
+	Token     string    `json:"token"`
+	TenantID  uuid.UUID `json:"tenant_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+var adminTenantCursorCodec = CursorCodec[TenantCursor]{
+	Validate: func(c TenantCursor) error {
+		if c.CreatedAt.IsZero() || c.ID == uuid.Nil {
+			return errors.New("invalid cursor: missing required fields")
+		}
+		return nil
+	},
+}
+
+func adminTenantToResponse(row database.GetPlatformTenantRow) adminTenantResponse {
+	resp := adminTenantResponse{
+		ID:               row.ID,
+		Name:             row.Name,
+		Status:           row.Status,
+		SuspensionReason: row.SuspensionReason.String,
+		StoreCount:       row.StoreCount,
+		MemberCount:      row.MemberCount,
+		CreatedAt:        row.CreatedAt,
+	}
+	if row.SuspendedAt.Valid {
+		resp.SuspendedAt = &row.SuspendedAt.Time
+	}
+	return resp
+}
+
+// handlerAdminTenantsList lists every tenant, newest first, narrowed by
+// the filters platform.ParseFilter reads
+func (cfg *apiConfig) handlerAdminTenantsList(w http.ResponseWriter, r *http.Request) {
+	filter, err := platform.ParseFilter(r.URL.Query(), platform.TenantStatuses)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+	cursor, hasCursor, err := adminTenantCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.db.ListPlatformTenants(r.Context(), database.ListPlatformTenantsParams{
+		Status:          sql.NullString{String: filter.Status, Valid: filter.Status != ""},
+		Query:           sql.NullString{String: filter.Query, Valid: filter.Query != ""},
+		HasCursor:       hasCursor,
+		CursorCreatedAt: cursor.CreatedAt,
+		CursorID:        cursor.ID,
+		RowLimit:        int32(pageParams.Limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve tenants", err)
+		return
+	}
+
+	hasMore := len(rows) > pageParams.Limit
+	if hasMore {
+		rows = rows[:pageParams.Limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = adminTenantCursorCodec.Encode(TenantCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]adminTenantResponse, 0, len(rows))
+	for _, row := range rows {
+		response = append(response, adminTenantToResponse(database.GetPlatformTenantRow(row)))
+	}
+	respondWithJSON(w, http.StatusOK, PageResponse[adminTenantResponse]{
+		Data: response,
+		Page: PageInfo{Limit: pageParams.Limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
+
+// handlerAdminTenantGet reports a tenant with its plan and subscription
+func (cfg *apiConfig) handlerAdminTenantGet(w http.ResponseWriter, r *http.Request) {
+	tenant, ok := cfg.adminTenant(w, r)
+	if !ok {
+		return
+	}
+	billing, err := cfg.tenantBilling(r.Context(), tenant.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve billing", err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, adminTenantDetailResponse{
+		adminTenantResponse: adminTenantToResponse(tenant),
+		Billing:             billing,
+	})
+}
+
+// handlerAdminTenantUsage reports what a tenant used in a month, as the
+// tenant sees it
+func (cfg *apiConfig) handlerAdminTenantUsage(w http.ResponseWriter, r *http.Request) {
+	tenant, ok := cfg.adminTenant(w, r)
+	if !ok {
+		return
+	}
+	cfg.respondWithUsage(w, r, tenant.ID)
+}
+
+// handlerAdminTenantSuspend closes a tenant to its members, API keys and
+// shoppers until it is unsuspended. Nothing is deleted. Suspending a
+// suspended tenant updates the reason.
+func (cfg *apiConfig) handlerAdminTenantSuspend(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	staff := platformStaffFrom(r)
+
+	tenantID, err := uuid.Parse(chi.URLParam(r, "tenantID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return
+	}
+	reason, ok := decodeAdminReason(w, r)
+	if !ok {
+		return
+	}
+
+	var tenant database.Tenant
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		var err error
+		tenant, err = q.SuspendTenant(r.Context(), database.SuspendTenantParams{
+			Reason: sql.NullString{String: reason, Valid: true},
+			ID:     tenantID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return service.NotFound("Tenant not found")
+		}
+		if err != nil {
+			return err
+		}
+		return cfg.recordPlatformAction(r, q, platform.ActionSuspend, tenantID, reason)
+	})
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to suspend tenant")
+		return
+	}
+
+	slog.WarnContext(r.Context(), "tenant suspended",
+		"request_id", reqID,
+		"staff_user_id", staff.UserID,
+		"tenant_id", tenantID,
+	)
+	cfg.respondWithAdminTenant(w, r, tenant.ID)
+}
+
+// handlerAdminTenantUnsuspend reopens a suspended tenant
+func (cfg *apiConfig) handlerAdminTenantUnsuspend(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	staff := platformStaffFrom(r)
+
+	tenantID, err := uuid.Parse(chi.URLParam(r, "tenantID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return
+	}
+	reason, ok := decodeAdminReason(w, r)
+	if !ok {
+		return
+	}
+
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		_, err := q.UnsuspendTenant(r.Context(), tenantID)
+		if errors.Is(err, sql.ErrNoRows) {
+			if _, err := q.GetTenantByID(r.Context(), tenantID); errors.Is(err, sql.ErrNoRows) {
+				return service.NotFound("Tenant not found")
+			}
+			return service.Conflict("The tenant is not suspended")
+		}
+		if err != nil {
+			return err
+		}
+		return cfg.recordPlatformAction(r, q, platform.ActionUnsuspend, tenantID, reason)
+	})
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to unsuspend tenant")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant unsuspended",
+		"request_id", reqID,
+		"staff_user_id", staff.UserID,
+		"tenant_id", tenantID,
+	)
+	cfg.respondWithAdminTenant(w, r, tenantID)
+}
+
+// handlerAdminTenantImpersonate issues a short-lived token for acting in a
+// tenant with every permission but ownership, such as to reproduce what a
+// merchant reports. What is done with it is in the tenant's audit log
+// under the staff member, and starting it is in the platform audit log.
+func (cfg *apiConfig) handlerAdminTenantImpersonate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	staff := platformStaffFrom(r)
+
+	tenant, ok := cfg.adminTenant(w, r)
+	if !ok {
+		return
+	}
+	reason, ok := decodeAdminReason(w, r)
+	if !ok {
+		return
+	}
+	if tenant.Status == platform.TenantDeleted {
+		respondWithServiceError(w, service.Conflict("The tenant is deleted"), "Unable to impersonate tenant")
+		return
+	}
+
+	if err := cfg.recordPlatformAction(r, cfg.db, platform.ActionImpersonate, tenant.ID, reason); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to impersonate tenant", err)
+		return
+	}
+	expiresAt := time.Now().Add(platform.ImpersonationTTL)
+	token, err := auth.MakeImpersonationJWT(staff.UserID, tenant.ID, cfg.jwtKeys, platform.ImpersonationTTL)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to impersonate tenant", err)
+		return
+	}
+
+	slog.WarnContext(r.Context(), "tenant impersonation started",
+		"request_id", reqID,
+		"staff_user_id", staff.UserID,
+		"tenant_id", tenant.ID,
+	)
+	respondWithJSON(w, http.StatusCreated, adminImpersonationResponse{
+		Token:     token,
+		TenantID:  tenant.ID,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// adminTenant loads the tenant in the URL, writing the error response and
+// returning ok=false when it can't
+func (cfg *apiConfig) adminTenant(w http.ResponseWriter, r *http.Request) (database.GetPlatformTenantRow, bool) {
+	tenantID, err := uuid.Parse(chi.URLParam(r, "tenantID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid tenant ID format", err)
+		return database.GetPlatformTenantRow{}, false
+	}
+	tenant, err := cfg.db.GetPlatformTenant(r.Context(), tenantID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Tenant not found", nil)
+			return database.GetPlatformTenantRow{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve tenant", err)
+		return database.GetPlatformTenantRow{}, false
+	}
+	return tenant, true
+}
+
+func (cfg *apiConfig) respondWithAdminTenant(w http.ResponseWriter, r *http.Request, tenantID uuid.UUID) {
+	tenant, err := cfg.db.GetPlatformTenant(r.Context(), tenantID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve tenant", err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, adminTenantToResponse(tenant))
+}
+
+// decodeAdminReason reads the reason staff must give for what they do to a
+// tenant, writing the error response and returning ok=false when it's
+// missing
+func decodeAdminReason(w http.ResponseWriter, r *http.Request) (string, bool) {
+	type parameters struct {
+		Reason string `json:"reason"`
+	}
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return "", false
+	}
+	var v validate.Validator
+	if v.Required("reason", params.Reason) {
+		v.MaxLength("reason", params.Reason, maxReasonLength)
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return "", false
+	}
+	return params.Reason, true
+}
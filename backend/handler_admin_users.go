@@ -0,0 +1,117 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/platform"
+	"github.com/google/uuid"
+)
+
+type adminUserResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	Email        string     `json:"email"`
+	DisplayName  *string    `json:"display_name"`
+	VerifiedAt   *time.Time `json:"verified_at"`
+	PlatformRole string     `json:"platform_role,omitempty"`
+	ErasedAt     *time.Time `json:"erased_at"`
+	// TenantCount is how many tenants the user is an active member of
+	TenantCount int64     `json:"tenant_count"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type adminUserCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+}
+
+var adminUserCursorCodec = CursorCodec[adminUserCursor]{
+	Validate: func(c adminUserCursor) error {
+		if c.CreatedAt.IsZero() || c.ID == uuid.Nil {
+			return errors.New("invalid cursor: missing required fields")
+		}
+		return nil
+	},
+}
+
+func adminUserToResponse(row database.ListPlatformUsersRow) adminUserResponse {
+	resp := adminUserResponse{
+		ID:           row.ID,
+		Email:        row.Email,
+		PlatformRole: row.PlatformRole.String,
+		TenantCount:  row.TenantCount,
+		CreatedAt:    row.CreatedAt,
+	}
+	if row.DisplayName.Valid {
+		resp.DisplayName = &row.DisplayName.String
+	}
+	if row.VerifiedAt.Valid {
+		resp.VerifiedAt = &row.VerifiedAt.Time
+	}
+	if row.ErasedAt.Valid {
+		resp.ErasedAt = &row.ErasedAt.Time
+	}
+	return resp
+}
+
+// handlerAdminUsersList lists every user, newest first, narrowed by the
+// filters platform.ParseFilter reads
+func (cfg *apiConfig) handlerAdminUsersList(w http.ResponseWriter, r *http.Request) {
+	filter, err := platform.ParseFilter(r.URL.Query(), platform.UserStatuses)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+	cursor, hasCursor, err := adminUserCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.db.ListPlatformUsers(r.Context(), database.ListPlatformUsersParams{
+		Status:          sql.NullString{String: filter.Status, Valid: filter.Status != ""},
+		Query:           sql.NullString{String: filter.Query, Valid: filter.Query != ""},
+		TenantID:        uuid.NullUUID{UUID: filter.TenantID, Valid: filter.TenantID != uuid.Nil},
+		HasCursor:       hasCursor,
+		CursorCreatedAt: cursor.CreatedAt,
+		CursorID:        cursor.ID,
+		RowLimit:        int32(pageParams.Limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve users", err)
+		return
+	}
+
+	hasMore := len(rows) > pageParams.Limit
+	if hasMore {
+		rows = rows[:pageParams.Limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = adminUserCursorCodec.Encode(adminUserCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]adminUserResponse, 0, len(rows))
+	for _, row := range rows {
+		response = append(response, adminUserToResponse(row))
+	}
+	respondWithJSON(w, http.StatusOK, PageResponse[adminUserResponse]{
+		Data: response,
+		Page: PageInfo{Limit: pageParams.Limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
@@ -2,24 +2,42 @@ package main
 
 import (
 	"encoding/json"
-	"fmt"
 	"log"
 	"log/slog"
 	"net/http"
-	"net/mail"
 	"time"
 
 	"github.com/dfodeker/terminus/internal/auth"
 	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/validate"
 	"github.com/dfodeker/terminus/middleware"
 	"github.com/google/uuid"
 )
 
 type User struct {
-	ID        uuid.UUID `json:"id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Email     string    `json:"email"`
+	ID          uuid.UUID  `json:"id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Email       string     `json:"email"`
+	DisplayName *string    `json:"display_name"`
+	VerifiedAt  *time.Time `json:"verified_at"`
+}
+
+func userToResponse(u database.User) User {
+	resp := User{
+		ID:        u.ID,
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+		Email:     u.Email,
+	}
+	if u.DisplayName.Valid {
+		resp.DisplayName = &u.DisplayName.String
+	}
+	if u.VerifiedAt.Valid {
+		resp.VerifiedAt = &u.VerifiedAt.Time
+	}
+	return resp
 }
 
 func (cfg *apiConfig) CreateUserHandler(w http.ResponseWriter, r *http.Request) {
@@ -38,9 +56,13 @@ func (cfg *apiConfig) CreateUserHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	email := params.Email
-	_, err = mail.ParseAddress(params.Email)
-	if err != nil {
-		respondWithError(w, 400, "Please Provide a Valid Email", err)
+	var v validate.Validator
+	if v.Required("email", email) {
+		v.Email("email", email)
+	}
+	v.Required("password", params.Password)
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
 		return
 	}
 	pass := params.Password
@@ -50,25 +72,27 @@ func (cfg *apiConfig) CreateUserHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	user, err := cfg.db.CreateUser(r.Context(), database.CreateUserParams{
-		Email:          email,
-		HashedPassword: hash,
-	})
+	var user database.User
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		var err error
+		user, err = q.CreateUser(r.Context(), database.CreateUserParams{
+			Email:          email,
+			HashedPassword: hash,
+		})
+		if err != nil {
+			log.Println(err)
+			return service.Invalid("Error Creating User")
+		}
 
+		// New accounts can sign in right away but can't create tenants or
+		// invite anyone until they follow the link
+		return cfg.sendVerificationEmail(r.Context(), q, user)
+	})
 	if err != nil {
-		msg := fmt.Sprintf("%s", err)
-		log.Println(msg)
-		respondWithError(w, http.StatusBadRequest, "Error Creating User", err)
+		respondWithServiceError(w, err, "unable to send verification email")
 		return
-
 	}
 
-	userResponse := User{
-		ID:        user.ID,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-		Email:     user.Email,
-	}
-	respondWithJSON(w, 201, userResponse)
+	respondWithJSON(w, 201, userToResponse(user))
 
 }
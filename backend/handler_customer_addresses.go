@@ -0,0 +1,458 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/address"
+	"github.com/dfodeker/terminus/internal/crypto"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type CustomerAddressResponse struct {
+	ID                uuid.UUID `json:"id"`
+	CustomerID        uuid.UUID `json:"customer_id"`
+	FirstName         *string   `json:"first_name,omitempty"`
+	LastName          *string   `json:"last_name,omitempty"`
+	Company           *string   `json:"company,omitempty"`
+	Address1          string    `json:"address1"`
+	Address2          *string   `json:"address2,omitempty"`
+	City              string    `json:"city"`
+	RegionCode        *string   `json:"region_code,omitempty"`
+	PostalCode        *string   `json:"postal_code,omitempty"`
+	CountryCode       string    `json:"country_code"`
+	Phone             *string   `json:"phone,omitempty"`
+	IsDefaultShipping bool      `json:"is_default_shipping"`
+	IsDefaultBilling  bool      `json:"is_default_billing"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// customerAddressParameters is the request body for creating and updating
+// addresses. Omitted fields keep their current value on update.
+type customerAddressParameters struct {
+	FirstName         *string `json:"first_name"`
+	LastName          *string `json:"last_name"`
+	Company           *string `json:"company"`
+	Address1          *string `json:"address1"`
+	Address2          *string `json:"address2"`
+	City              *string `json:"city"`
+	RegionCode        *string `json:"region_code"`
+	PostalCode        *string `json:"postal_code"`
+	CountryCode       *string `json:"country_code"`
+	Phone             *string `json:"phone"`
+	IsDefaultShipping *bool   `json:"is_default_shipping"`
+	IsDefaultBilling  *bool   `json:"is_default_billing"`
+}
+
+// apply merges the parameters onto addr and validates the result
+func (p customerAddressParameters) apply(addr database.CustomerAddress) (database.CustomerAddress, error) {
+	if p.FirstName != nil {
+		addr.FirstName = nullStringFromPtr(p.FirstName)
+	}
+	if p.LastName != nil {
+		addr.LastName = nullStringFromPtr(p.LastName)
+	}
+	if p.Company != nil {
+		addr.Company = nullStringFromPtr(p.Company)
+	}
+	if p.Address1 != nil {
+		addr.Address1 = crypto.Text(strings.TrimSpace(*p.Address1))
+	}
+	if p.Address2 != nil {
+		addr.Address2 = crypto.NullText(nullStringFromPtr(p.Address2))
+	}
+	if p.City != nil {
+		addr.City = strings.TrimSpace(*p.City)
+	}
+	if p.RegionCode != nil {
+		addr.RegionCode = sql.NullString{String: *p.RegionCode, Valid: true}
+	}
+	if p.PostalCode != nil {
+		addr.PostalCode = nullStringFromPtr(p.PostalCode)
+	}
+	if p.CountryCode != nil {
+		addr.CountryCode = *p.CountryCode
+	}
+	if p.Phone != nil {
+		addr.Phone = crypto.NullText(nullStringFromPtr(p.Phone))
+	}
+	if p.IsDefaultShipping != nil {
+		addr.IsDefaultShipping = *p.IsDefaultShipping
+	}
+	if p.IsDefaultBilling != nil {
+		addr.IsDefaultBilling = *p.IsDefaultBilling
+	}
+
+	if addr.Address1 == "" {
+		return addr, errors.New("address1 is required")
+	}
+	if addr.City == "" {
+		return addr, errors.New("city is required")
+	}
+
+	country, err := address.NormalizeCountry(addr.CountryCode)
+	if err != nil {
+		return addr, err
+	}
+	addr.CountryCode = country
+
+	region, err := address.NormalizeRegion(country, addr.RegionCode.String)
+	if err != nil {
+		return addr, err
+	}
+	addr.RegionCode = sql.NullString{String: region, Valid: region != ""}
+
+	return addr, nil
+}
+
+// handlerStorefrontCustomerAddressesList lists the signed-in shopper's addresses
+func (cfg *apiConfig) handlerStorefrontCustomerAddressesList(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := customerFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+	cfg.respondWithCustomerAddresses(w, r, customerID)
+}
+
+// handlerStorefrontCustomerAddressCreate adds an address to the signed-in shopper's address book
+func (cfg *apiConfig) handlerStorefrontCustomerAddressCreate(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := customerFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+	cfg.createCustomerAddress(w, r, customerID)
+}
+
+// handlerStorefrontCustomerAddressGet returns one of the signed-in shopper's addresses
+func (cfg *apiConfig) handlerStorefrontCustomerAddressGet(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := customerFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	addr, ok := cfg.loadCustomerAddress(w, r, customerID)
+	if !ok {
+		return
+	}
+	respondWithJSON(w, http.StatusOK, customerAddressToResponse(addr))
+}
+
+// handlerStorefrontCustomerAddressUpdate edits one of the signed-in shopper's addresses
+func (cfg *apiConfig) handlerStorefrontCustomerAddressUpdate(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := customerFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+	cfg.updateCustomerAddress(w, r, customerID)
+}
+
+// handlerStorefrontCustomerAddressDelete removes one of the signed-in shopper's addresses
+func (cfg *apiConfig) handlerStorefrontCustomerAddressDelete(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := customerFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+	cfg.deleteCustomerAddress(w, r, customerID)
+}
+
+// handlerTenantCustomerAddressesList lists a customer's addresses for staff
+func (cfg *apiConfig) handlerTenantCustomerAddressesList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	customer, ok := cfg.loadStoreCustomer(w, r, storeID)
+	if !ok {
+		return
+	}
+	cfg.respondWithCustomerAddresses(w, r, customer.ID)
+}
+
+// handlerTenantCustomerAddressCreate adds an address on the customer's behalf
+func (cfg *apiConfig) handlerTenantCustomerAddressCreate(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	customer, ok := cfg.loadStoreCustomer(w, r, storeID)
+	if !ok {
+		return
+	}
+	cfg.createCustomerAddress(w, r, customer.ID)
+}
+
+// handlerTenantCustomerAddressUpdate edits an address on the customer's behalf
+func (cfg *apiConfig) handlerTenantCustomerAddressUpdate(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	customer, ok := cfg.loadStoreCustomer(w, r, storeID)
+	if !ok {
+		return
+	}
+	cfg.updateCustomerAddress(w, r, customer.ID)
+}
+
+// handlerTenantCustomerAddressDelete removes an address on the customer's behalf
+func (cfg *apiConfig) handlerTenantCustomerAddressDelete(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	customer, ok := cfg.loadStoreCustomer(w, r, storeID)
+	if !ok {
+		return
+	}
+	cfg.deleteCustomerAddress(w, r, customer.ID)
+}
+
+func (cfg *apiConfig) respondWithCustomerAddresses(w http.ResponseWriter, r *http.Request, customerID uuid.UUID) {
+	addresses, err := cfg.db.GetCustomerAddresses(r.Context(), customerID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve addresses", err)
+		return
+	}
+
+	response := make([]CustomerAddressResponse, 0, len(addresses))
+	for _, addr := range addresses {
+		response = append(response, customerAddressToResponse(addr))
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{
+		"data": response,
+	})
+}
+
+func (cfg *apiConfig) createCustomerAddress(w http.ResponseWriter, r *http.Request, customerID uuid.UUID) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	decoder := json.NewDecoder(r.Body)
+	params := customerAddressParameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	addr, err := params.apply(database.CustomerAddress{CustomerID: customerID})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	var created database.CustomerAddress
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		// The first address becomes the default for both shipping and billing
+		count, err := q.CountCustomerAddresses(r.Context(), customerID)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			addr.IsDefaultShipping = true
+			addr.IsDefaultBilling = true
+		}
+
+		if err := clearCustomerAddressDefaults(r, q, customerID, addr.IsDefaultShipping, addr.IsDefaultBilling); err != nil {
+			return err
+		}
+
+		created, err = q.CreateCustomerAddress(r.Context(), database.CreateCustomerAddressParams{
+			CustomerID:        customerID,
+			FirstName:         addr.FirstName,
+			LastName:          addr.LastName,
+			Company:           addr.Company,
+			Address1:          addr.Address1,
+			Address2:          addr.Address2,
+			City:              addr.City,
+			RegionCode:        addr.RegionCode,
+			PostalCode:        addr.PostalCode,
+			CountryCode:       addr.CountryCode,
+			Phone:             addr.Phone,
+			IsDefaultShipping: addr.IsDefaultShipping,
+			IsDefaultBilling:  addr.IsDefaultBilling,
+		})
+		return err
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create address", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "customer address created successfully",
+		"request_id", reqID,
+		"customer_id", customerID,
+		"address_id", created.ID,
+	)
+
+	respondWithJSON(w, http.StatusCreated, customerAddressToResponse(created))
+}
+
+func (cfg *apiConfig) updateCustomerAddress(w http.ResponseWriter, r *http.Request, customerID uuid.UUID) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	existing, ok := cfg.loadCustomerAddress(w, r, customerID)
+	if !ok {
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := customerAddressParameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	addr, err := params.apply(existing)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	var updated database.CustomerAddress
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		err := clearCustomerAddressDefaults(r, q, customerID,
+			addr.IsDefaultShipping && !existing.IsDefaultShipping,
+			addr.IsDefaultBilling && !existing.IsDefaultBilling,
+		)
+		if err != nil {
+			return err
+		}
+
+		updated, err = q.UpdateCustomerAddress(r.Context(), database.UpdateCustomerAddressParams{
+			ID:                existing.ID,
+			CustomerID:        customerID,
+			FirstName:         addr.FirstName,
+			LastName:          addr.LastName,
+			Company:           addr.Company,
+			Address1:          addr.Address1,
+			Address2:          addr.Address2,
+			City:              addr.City,
+			RegionCode:        addr.RegionCode,
+			PostalCode:        addr.PostalCode,
+			CountryCode:       addr.CountryCode,
+			Phone:             addr.Phone,
+			IsDefaultShipping: addr.IsDefaultShipping,
+			IsDefaultBilling:  addr.IsDefaultBilling,
+		})
+		return err
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update address", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "customer address updated successfully",
+		"request_id", reqID,
+		"customer_id", customerID,
+		"address_id", updated.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, customerAddressToResponse(updated))
+}
+
+func (cfg *apiConfig) deleteCustomerAddress(w http.ResponseWriter, r *http.Request, customerID uuid.UUID) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	existing, ok := cfg.loadCustomerAddress(w, r, customerID)
+	if !ok {
+		return
+	}
+
+	err := cfg.db.DeleteCustomerAddress(r.Context(), database.DeleteCustomerAddressParams{
+		ID:         existing.ID,
+		CustomerID: customerID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to delete address", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "customer address deleted successfully",
+		"request_id", reqID,
+		"customer_id", customerID,
+		"address_id", existing.ID,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cfg *apiConfig) loadCustomerAddress(w http.ResponseWriter, r *http.Request, customerID uuid.UUID) (database.CustomerAddress, bool) {
+	addressID, err := uuid.Parse(chi.URLParam(r, "addressID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid address ID format", err)
+		return database.CustomerAddress{}, false
+	}
+
+	addr, err := cfg.db.GetCustomerAddressByID(r.Context(), database.GetCustomerAddressByIDParams{
+		ID:         addressID,
+		CustomerID: customerID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Address not found", nil)
+			return database.CustomerAddress{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve address", err)
+		return database.CustomerAddress{}, false
+	}
+	return addr, true
+}
+
+// clearCustomerAddressDefaults unsets the current default(s) so a new one can take their place
+func clearCustomerAddressDefaults(r *http.Request, qtx *database.Queries, customerID uuid.UUID, shipping, billing bool) error {
+	if shipping {
+		if err := qtx.ClearDefaultShippingAddress(r.Context(), customerID); err != nil {
+			return err
+		}
+	}
+	if billing {
+		if err := qtx.ClearDefaultBillingAddress(r.Context(), customerID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func customerAddressToResponse(addr database.CustomerAddress) CustomerAddressResponse {
+	resp := CustomerAddressResponse{
+		ID:                addr.ID,
+		CustomerID:        addr.CustomerID,
+		Address1:          string(addr.Address1),
+		City:              addr.City,
+		CountryCode:       addr.CountryCode,
+		IsDefaultShipping: addr.IsDefaultShipping,
+		IsDefaultBilling:  addr.IsDefaultBilling,
+		CreatedAt:         addr.CreatedAt,
+		UpdatedAt:         addr.UpdatedAt,
+	}
+	if addr.FirstName.Valid {
+		resp.FirstName = &addr.FirstName.String
+	}
+	if addr.LastName.Valid {
+		resp.LastName = &addr.LastName.String
+	}
+	if addr.Company.Valid {
+		resp.Company = &addr.Company.String
+	}
+	if addr.Address2.Valid {
+		resp.Address2 = &addr.Address2.String
+	}
+	if addr.RegionCode.Valid {
+		resp.RegionCode = &addr.RegionCode.String
+	}
+	if addr.PostalCode.Valid {
+		resp.PostalCode = &addr.PostalCode.String
+	}
+	if addr.Phone.Valid {
+		resp.Phone = &addr.Phone.String
+	}
+	return resp
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/mailer"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/google/uuid"
+)
+
+// emailVerificationTTL is how long a verification link stays valid
+const emailVerificationTTL = 48 * time.Hour
+
+// sendVerificationEmail replaces any outstanding verification links for the
+// user with a new one and queues the email on q.
+func (cfg *apiConfig) sendVerificationEmail(ctx context.Context, q *database.Queries, user database.User) error {
+	token, err := auth.MakeOneTimeToken()
+	if err != nil {
+		return err
+	}
+
+	if err := q.InvalidateEmailVerificationTokens(ctx, user.ID); err != nil {
+		return err
+	}
+
+	_, err = q.CreateEmailVerificationToken(ctx, database.CreateEmailVerificationTokenParams{
+		UserID:    user.ID,
+		TokenHash: auth.HashToken(token),
+		ExpiresAt: time.Now().Add(emailVerificationTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	return cfg.enqueueEmail(ctx, q, user.Email, mailer.TemplateVerifyEmail, mailer.VerifyEmailData{
+		VerifyURL:      cfg.config.AppURL + "/verify-email?token=" + url.QueryEscape(token),
+		ExpiresInHours: int(emailVerificationTTL.Hours()),
+	})
+}
+
+// sendEmailChangeEmail replaces any outstanding verification links for the
+// user with one that moves the account to newEmail, and queues it to that
+// address on q
+func (cfg *apiConfig) sendEmailChangeEmail(ctx context.Context, q *database.Queries, user database.User, newEmail string) error {
+	token, err := auth.MakeOneTimeToken()
+	if err != nil {
+		return err
+	}
+
+	if err := q.InvalidateEmailVerificationTokens(ctx, user.ID); err != nil {
+		return err
+	}
+
+	_, err = q.CreateEmailChangeToken(ctx, database.CreateEmailChangeTokenParams{
+		UserID:    user.ID,
+		TokenHash: auth.HashToken(token),
+		NewEmail:  sql.NullString{String: newEmail, Valid: true},
+		ExpiresAt: time.Now().Add(emailVerificationTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	return cfg.enqueueEmail(ctx, q, newEmail, mailer.TemplateVerifyEmail, mailer.VerifyEmailData{
+		VerifyURL:      cfg.config.AppURL + "/verify-email?token=" + url.QueryEscape(token),
+		ExpiresInHours: int(emailVerificationTTL.Hours()),
+	})
+}
+
+// requireVerifiedEmail writes a 403 and returns false when the user hasn't
+// confirmed their email address yet
+func (cfg *apiConfig) requireVerifiedEmail(w http.ResponseWriter, r *http.Request, userID uuid.UUID) bool {
+	if err := cfg.checkVerifiedEmail(r.Context(), userID); err != nil {
+		respondWithAccessError(w, err)
+		return false
+	}
+	return true
+}
+
+// handlerEmailVerify confirms a user's email address with the token from
+// their verification link. A link sent for an email change moves the
+// account to the new address.
+func (cfg *apiConfig) handlerEmailVerify(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	type parameters struct {
+		Token string `json:"token"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	if params.Token == "" {
+		respondWithError(w, http.StatusBadRequest, "Token is required", nil)
+		return
+	}
+
+	var user database.User
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		verification, err := q.GetActiveEmailVerificationToken(r.Context(), auth.HashToken(params.Token))
+		if errors.Is(err, sql.ErrNoRows) {
+			return service.Invalid("Verification link is invalid or has expired")
+		}
+		if err != nil {
+			return err
+		}
+
+		if verification.NewEmail.Valid {
+			user, err = q.ChangeUserEmail(r.Context(), database.ChangeUserEmailParams{
+				ID:    verification.UserID,
+				Email: verification.NewEmail.String,
+			})
+			err = service.ConflictCodeAs(err, problem.CodeEmailTaken, "An account with this email already exists")
+		} else {
+			user, err = q.MarkUserVerified(r.Context(), verification.UserID)
+		}
+		if err != nil {
+			return err
+		}
+
+		return q.InvalidateEmailVerificationTokens(r.Context(), user.ID)
+	})
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to verify email")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "email verified",
+		"request_id", reqID,
+		"user_id", user.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, userToResponse(user))
+}
+
+// handlerEmailVerifyResend sends the signed-in user a fresh verification
+// link. Earlier links stop working.
+func (cfg *apiConfig) handlerEmailVerifyResend(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	userID, ok := userFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	user, err := cfg.db.GetUserByID(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve user", err)
+		return
+	}
+
+	if user.VerifiedAt.Valid {
+		respondWithError(w, http.StatusConflict, "Email is already verified", nil)
+		return
+	}
+
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		return cfg.sendVerificationEmail(r.Context(), q, user)
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to send verification email", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "email verification resent",
+		"request_id", reqID,
+		"user_id", user.ID,
+	)
+
+	w.WriteHeader(http.StatusAccepted)
+}
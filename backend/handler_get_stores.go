@@ -1,61 +1,105 @@
 package main
 
 import (
-	"log"
 	"log/slog"
 	"net/http"
-	"time"
 
 	"github.com/dfodeker/terminus/middleware"
 	"github.com/google/uuid"
 )
 
-type Store struct {
-	ID              uuid.UUID `json:"id"`
-	Name            string    `json:"name"`
-	Handle          string    `json:"handle"`
-	Address         string    `json:"address"`
-	Status          string    `json:"status"`
-	DefaultCurrency string    `json:"default_currency"`
-	Timezone        string    `json:"timezone"`
-	Plan            string    `json:"plan"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
-}
-
-//we can extract this auth function we're about to write somewhere else
-
+// handlerGetStores lists the stores of every tenant where the caller holds
+// stores:view, newest first. ?tenant_id= narrows it to one tenant.
 func (cfg *apiConfig) handlerGetStores(w http.ResponseWriter, r *http.Request) {
 	reqID := middleware.GetRequestID(r.Context())
-	slog.InfoContext(r.Context(), "requesting resource : stores", "request_id", reqID)
+	slog.InfoContext(r.Context(), "store list request received", "request_id", reqID)
+
 	user, ok := userFromContext(r.Context())
 	if !ok {
-
 		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
 		return
 	}
-	log.Println(user)
-	response := []Store{}
 
-	stores, err := cfg.db.GetStores(r.Context())
+	var tenantID uuid.NullUUID
+	if param := r.URL.Query().Get("tenant_id"); param != "" {
+		id, err := uuid.Parse(param)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid tenant ID format", err)
+			return
+		}
+		tenantID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	pageParams, err := ParsePageParams(r, defaultStoreLimit, maxStoreLimit)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+	limit := pageParams.Limit
+
+	cursorCreatedAt, cursorID, hasCursor, err := decodeStoreCursor(pageParams.Cursor)
 	if err != nil {
-		respondWithError(w, http.StatusNotFound, "unable to get stores", err)
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
 		return
 	}
-	for _, store := range stores {
-		response = append(response, Store{
+
+	page, err := cfg.services.stores.ListForUser(r.Context(), user, "stores:view", tenantID, pageRequest(limit, cursorCreatedAt, cursorID, hasCursor))
+	if err != nil {
+		slog.ErrorContext(r.Context(), "store list failed: database query error",
+			"request_id", reqID,
+			"user_id", user,
+			"error", err,
+		)
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve stores", err)
+		return
+	}
+
+	rows, hasMore := page.Items, page.HasMore
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = storeCursorCodec.Encode(StoreCursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]TenantStoreResponse, 0, len(rows))
+	for _, store := range rows {
+		var tenantIDPtr *uuid.UUID
+		if store.TenantID.Valid {
+			tenantIDPtr = &store.TenantID.UUID
+		}
+		response = append(response, TenantStoreResponse{
 			ID:              store.ID,
+			TenantID:        tenantIDPtr,
 			Name:            store.Name,
 			Handle:          store.Handle,
 			Address:         store.Address,
 			Status:          store.Status,
 			DefaultCurrency: store.DefaultCurrency,
+			DefaultLocale:   store.DefaultLocale,
 			Timezone:        store.Timezone,
 			Plan:            store.Plan,
 			CreatedAt:       store.CreatedAt,
 			UpdatedAt:       store.UpdatedAt,
 		})
 	}
-	respondWithJSON(w, http.StatusOK, response)
 
+	slog.InfoContext(r.Context(), "store list successful",
+		"request_id", reqID,
+		"user_id", user,
+		"store_count", len(response),
+		"has_more", hasMore,
+	)
+
+	respondWithJSON(w, http.StatusOK, PageResponse[TenantStoreResponse]{
+		Data: response,
+		Page: PageInfo{Limit: limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
 }
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"cmp"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/fixtures"
+	"github.com/dfodeker/terminus/internal/platform"
+	"github.com/dfodeker/terminus/internal/problem"
+)
+
+// tenantPath is the path of the tenant and store every fixture belongs to
+const tenantPath = "/api/v1/tenants/00000000-0000-4000-8000-000000000001"
+
+// storePath is tenantPath's store
+const storePath = tenantPath + "/stores/00000000-0000-4000-8000-000000000001"
+
+// seedMember makes the caller an active member of the tenant with every
+// permission, and the store one of the tenant's
+func seedMember(db *fixtures.DB) {
+	member := fixtures.Fill[database.TenantUser]()
+	member.Status = "active"
+	db.Set("GetTenantUser", member)
+	db.Set("GetTenantByID", fixtures.Fill[database.Tenant]())
+	db.Set("GetUserRolesInTenant", fixtures.Fill[database.Role]())
+	db.Set("CheckUserHasPermission", true)
+	db.Set("GetStoreByTenantAndID", fixtures.Fill[database.Store]())
+}
+
+// seedProduct sets what the product handlers read alongside a product:
+// its stock, media and ratings
+func seedProduct(db *fixtures.DB) {
+	db.Set("GetProductAvailabilityByLocation", fixtures.Fill[database.GetProductAvailabilityByLocationRow]())
+	db.Set("GetReservedStockByProducts", fixtures.Fill[database.GetReservedStockByProductsRow]())
+	db.Set("GetReadyMediaByProducts", fixtures.Fill[database.ProductMedium]())
+	db.Set("GetProductRatings", fixtures.Fill[database.GetProductRatingsRow]())
+}
+
+// TestHandlerGolden serves requests through the server's handler, over a
+// database holding only the fixture rows of each case, and compares the
+// bodies with golden files, so what handlers build from the rows they read
+// is pinned along with the response types
+func TestHandlerGolden(t *testing.T) {
+	tests := []struct {
+		name string
+		// host is the host the request is for, by default the API's
+		host       string
+		path       string
+		seed       func(db *fixtures.DB)
+		wantStatus int
+	}{
+		{
+			name: "tenant_stores_list",
+			path: tenantPath + "/stores?limit=1",
+			seed: func(db *fixtures.DB) {
+				seedMember(db)
+				// One more than the limit, so the page has a next cursor
+				first := fixtures.Fill[database.GetStoresByTenantIDPaginatedRow]()
+				second := first
+				second.Name = "second"
+				db.Set("GetStoresByTenantIDPaginated", first, second)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "tenant_store_settings",
+			path: storePath + "/settings",
+			seed: func(db *fixtures.DB) {
+				seedMember(db)
+				db.Set("GetStoreSettings", json.RawMessage(`{"weight_unit":"lb","branding":{"primary_color":"#112233"}}`))
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "tenant_products_list",
+			path: storePath + "/products",
+			seed: func(db *fixtures.DB) {
+				seedMember(db)
+				db.Set("ListFilteredProducts", fixtures.Fill[database.Product]())
+				seedProduct(db)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "tenant_product_get",
+			path: storePath + "/products/00000000-0000-4000-8000-000000000001",
+			seed: func(db *fixtures.DB) {
+				seedMember(db)
+				db.Set("GetProductByID", fixtures.Fill[database.Product]())
+				seedProduct(db)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "tenant_product_not_found",
+			path: storePath + "/products/00000000-0000-4000-8000-000000000002",
+			seed: func(db *fixtures.DB) {
+				seedMember(db)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "tenant_customers_list",
+			path: storePath + "/customers",
+			seed: func(db *fixtures.DB) {
+				seedMember(db)
+				db.Set("ListFilteredCustomers", fixtures.Fill[database.Customer]())
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "tenant_order_get",
+			path: storePath + "/orders/00000000-0000-4000-8000-000000000001",
+			seed: func(db *fixtures.DB) {
+				seedMember(db)
+				db.Set("GetOrderByID", fixtures.Fill[database.Order]())
+				db.Set("GetOrderLineItems", fixtures.Fill[database.OrderLineItem]())
+				db.Set("GetFulfillmentsByOrder", fixtures.Fill[database.Fulfillment]())
+				db.Set("GetFulfillmentLineItemsByOrder", fixtures.Fill[database.FulfillmentLineItem]())
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "tenants_list",
+			path: "/api/v1/tenants",
+			seed: func(db *fixtures.DB) {
+				db.Set("GetTenantsByUserIDPaginated", fixtures.Fill[database.GetTenantsByUserIDPaginatedRow]())
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "me",
+			path: "/api/v1/me",
+			seed: func(db *fixtures.DB) {
+				user := fixtures.Fill[database.User]()
+				user.TokenVersion = 0
+				db.Set("GetUserByID", user)
+				db.Set("GetPendingEmailChange", sql.NullString{String: "new@example.com", Valid: true})
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "admin_tenants_list",
+			host: "admin.storeos.org",
+			path: "/api/v1/admin/tenants",
+			seed: func(db *fixtures.DB) {
+				staff := fixtures.Fill[database.GetUserPlatformRoleRow]()
+				staff.PlatformRole = sql.NullString{String: string(platform.RoleSupport), Valid: true}
+				db.Set("GetUserPlatformRole", staff)
+				db.Set("ListPlatformTenants", fixtures.Fill[database.ListPlatformTenantsRow]())
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "storefront_products_list",
+			host: "demo.storeos.org",
+			path: "/api/v1/storefront/products",
+			seed: func(db *fixtures.DB) {
+				store := fixtures.Fill[database.Store]()
+				store.Handle = "demo"
+				store.Status = "active"
+				db.Set("GetStoreByHandle", store)
+				token := fixtures.Fill[database.StorefrontToken]()
+				token.Scopes = []string{auth.ScopeReadProducts}
+				db.Set("GetActiveStorefrontTokenByHash", token)
+				db.Set("GetOnlineStoreChannel", fixtures.Fill[database.SalesChannel]())
+				db.Set("ListFilteredProducts", fixtures.Fill[database.Product]())
+				db.Set("GetStorefrontVariantPrices", fixtures.Fill[database.GetStorefrontVariantPricesRow]())
+				seedProduct(db)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "not_member",
+			path:       tenantPath + "/stores",
+			seed:       func(db *fixtures.DB) {},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := fixtures.NewDB(t)
+			tt.seed(db)
+			cfg := newTestConfig(t, db.Dial)
+			token, err := auth.MakeJWT(fixtures.ID, cfg.jwtKeys, time.Hour)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Host = cmp.Or(tt.host, "api.storeos.org")
+			if strings.HasPrefix(tt.path, "/api/v1/storefront/") {
+				req.Header.Set(storefrontTokenHeader, "sf_token")
+			} else {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			w := httptest.NewRecorder()
+			cfg.hostHandler().ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d\n%s", w.Code, tt.wantStatus, w.Body)
+			}
+			wantType := "application/json"
+			if w.Code >= 400 {
+				wantType = problem.ContentType
+			}
+			if ct := w.Header().Get("Content-Type"); ct != wantType {
+				t.Errorf("Content-Type = %q, want %q", ct, wantType)
+			}
+			fixtures.Golden(t, "handlers/"+tt.name+".json", w.Body.Bytes())
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/dfodeker/terminus/internal/config"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/health"
+	"github.com/dfodeker/terminus/internal/migrate"
+	"github.com/dfodeker/terminus/sql/schema"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// newReadinessChecker registers the dependencies /health/ready checks.
+// Migrations are checked against those the binary embeds, so an instance
+// isn't ready while the database is behind the schema it was built for.
+// Redis is only checked when REDIS_URL is set.
+func newReadinessChecker(cfg *config.Config, pool *pgxpool.Pool, db *database.Queries) (*health.Checker, error) {
+	latest, err := health.LatestMigration(schema.FS, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	checker := health.New(health.DefaultTimeout)
+	checker.Add("database", health.Database(pool))
+	checker.Add("migrations", health.Migrations(pool, latest))
+	checker.Add("job_queue", health.JobQueue(db.CountDueJobs))
+	if cfg.RedisURL != "" {
+		checker.Add("redis", health.Redis(cfg.RedisURL))
+	}
+	return checker, nil
+}
+
+// handlerHealthLive answers the liveness probe. It checks nothing beyond the
+// process serving requests, so a database outage doesn't get pods restarted.
+func (cfg *apiConfig) handlerHealthLive(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, map[string]string{"status": health.StatusUp})
+}
+
+// handlerHealthReady answers the readiness probe with the result of every
+// dependency check, and 503 if any is down
+func (cfg *apiConfig) handlerHealthReady(w http.ResponseWriter, r *http.Request) {
+	report := cfg.health.Run(r.Context())
+	status := http.StatusOK
+	if report.Status != health.StatusUp {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	respondWithJSON(w, status, report)
+}
+
+// migrateOnStart applies the pending migrations the binary embeds
+func migrateOnStart(ctx context.Context, db *pgxpool.Pool) error {
+	return migrate.Up(ctx, db)
+}
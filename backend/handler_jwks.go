@@ -0,0 +1,12 @@
+package main
+
+import "net/http"
+
+// handlerJWKS publishes the public keys access tokens are signed with, so
+// other services can verify tokens without calling this API
+func (cfg *apiConfig) handlerJWKS(w http.ResponseWriter, r *http.Request) {
+	// Verifiers should refetch when they see an unknown kid, so a short
+	// cache is enough
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	respondWithJSON(w, http.StatusOK, cfg.jwtKeys.JWKS())
+}
@@ -1,13 +1,18 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/mail"
 	"time"
 
 	"github.com/dfodeker/terminus/internal/auth"
 	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/lockout"
+	"github.com/dfodeker/terminus/internal/problem"
 	"github.com/google/uuid"
 )
 
@@ -17,16 +22,20 @@ type RefershTokenParams struct {
 	ExpiresAt time.Time
 }
 
+// LoginResponse is returned once a user has fully signed in
+type LoginResponse struct {
+	User
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
 func (cfg *apiConfig) handlerLoginUsers(w http.ResponseWriter, r *http.Request) {
 
-	type response struct {
-		User
-		Token        string `json:"token"`
-		RefreshToken string `json:"refresh_token"`
-	}
 	type parameters struct {
 		Email    string `json:"email"`
 		Password string `json:"password"`
+		// CaptchaToken is needed once the account has been failing
+		CaptchaToken string `json:"captcha_token"`
 	}
 	decoder := json.NewDecoder(r.Body)
 	params := parameters{}
@@ -43,51 +52,112 @@ func (cfg *apiConfig) handlerLoginUsers(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// Guessing is slowed the same whether or not the account exists, so
+	// lockouts don't reveal which emails have one
+	attempt := userLoginAttempt(r, email)
+	who := loginSubject{Email: email}
+	status, ok := cfg.checkLoginLock(w, r, attempt)
+	if !ok {
+		return
+	}
+	if !cfg.checkLoginCaptcha(w, r, status, params.CaptchaToken, who) {
+		return
+	}
+
 	user, err := cfg.db.GetUserByEmail(r.Context(), email)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.failLogin(r, attempt, lockout.EventLoginFailed, who)
+		}
 		respondWithError(w, http.StatusUnauthorized, "incorrect email or password", err)
 		return
 	}
+	who.UserID = user.ID
 
 	//compare password
 	err = auth.CheckPasswordHash(params.Password, user.HashedPassword)
 	if err != nil {
+		cfg.failLogin(r, attempt, lockout.EventLoginFailed, who)
 		respondWithError(w, http.StatusUnauthorized, "incorrect email of password", err)
 		return
 
 	}
-	refreshTime := time.Now().Add(60 * 24 * time.Hour)
 
-	refreshToken, err := auth.MakeRefreshToken()
+	// Checked only once the password is right, so it says nothing about
+	// the account to someone guessing
+	ssoOnly, err := cfg.db.UserRequiresSSO(r.Context(), user.ID)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "internal server error", err)
 		return
 	}
+	if ssoOnly {
+		respondWithErrorCode(w, http.StatusForbidden, problem.CodeSSORequired, "Your organization requires signing in with single sign-on", nil)
+		return
+	}
 
-	accessToken, err := auth.MakeJWT(user.ID, cfg.signingKey, time.Hour)
+	if cfg.finishLogin(w, r, user) {
+		cfg.succeedLogin(r, attempt)
+	}
+}
+
+// finishLogin answers a user who has proven who they are with their
+// tokens, or with an MFA challenge when they have MFA on. It reports
+// whether they are fully signed in.
+func (cfg *apiConfig) finishLogin(w http.ResponseWriter, r *http.Request, user database.User) bool {
+	// With MFA on, the first step only earns a challenge; tokens come from
+	// POST /login/mfa with a code
+	challenge, required, err := cfg.startMFAChallenge(r.Context(), user.ID)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "internal server error", err)
-		return
+		return false
+	}
+	if required {
+		respondWithJSON(w, http.StatusOK, MFAChallengeResponse{
+			MFARequired: true,
+			MFAToken:    challenge,
+		})
+		return false
 	}
 
-	_, err = cfg.db.CreateRefreshToken(r.Context(), database.CreateRefreshTokenParams{
-		Token:     refreshToken,
+	var resp LoginResponse
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		resp, err = cfg.issueLoginTokens(r.Context(), q, user, sessionMetaFromRequest(r))
+		return err
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "unable to generate token", err)
+		return false
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+	return true
+}
+
+// issueLoginTokens starts a session for a user who has passed every
+// sign-in check and returns its first access and refresh tokens
+func (cfg *apiConfig) issueLoginTokens(ctx context.Context, q *database.Queries, user database.User, meta sessionMeta) (LoginResponse, error) {
+	session, err := q.CreateSession(ctx, database.CreateSessionParams{
 		UserID:    user.ID,
-		ExpiresAt: refreshTime,
+		UserAgent: meta.UserAgent,
+		IpAddress: meta.IPAddress,
+		ExpiresAt: time.Now().Add(sessionTTL),
 	})
+	if err != nil {
+		return LoginResponse{}, err
+	}
 
+	refreshToken, err := createRefreshToken(ctx, q, user.ID, session.ID, session.ExpiresAt)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "unable to generate token", err)
-		return
+		return LoginResponse{}, err
 	}
-	respondWithJSON(w, http.StatusOK, response{
-		User: User{
-			ID:        user.ID,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
-			Email:     user.Email,
-		},
+
+	accessToken, err := cfg.makeAccessToken(ctx, q, user.ID)
+	if err != nil {
+		return LoginResponse{}, err
+	}
+
+	return LoginResponse{
+		User:         userToResponse(user),
 		Token:        accessToken,
 		RefreshToken: refreshToken,
-	})
+	}, nil
 }
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/mailer"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/ratelimit"
+	"github.com/dfodeker/terminus/middleware"
+)
+
+// magicLinkTTL is how long a sign-in link stays valid
+const magicLinkTTL = 15 * time.Minute
+
+// magicLinkQuota caps the links sent to one address, on top of the limit
+// per client IP, so an inbox can't be flooded from many addresses
+var magicLinkQuota = ratelimit.Quota{Requests: 3, Window: 15 * time.Minute}
+
+// MagicLinkResponse answers a request for a sign-in link
+type MagicLinkResponse struct {
+	Message string `json:"message"`
+	// DeviceToken has to be sent back with the link's token, so the link
+	// only works in the browser that asked for it
+	DeviceToken string `json:"device_token"`
+}
+
+// handlerMagicLinkRequest emails a single-use sign-in link. It answers the
+// same way whether or not the account exists so it can't be used to probe
+// for registered addresses.
+func (cfg *apiConfig) handlerMagicLinkRequest(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	type parameters struct {
+		Email string `json:"email"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	if _, err := mail.ParseAddress(params.Email); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid email", err)
+		return
+	}
+
+	if !cfg.limit(w, r, "magic_link:"+auth.HashToken(strings.ToLower(params.Email)), magicLinkQuota) {
+		return
+	}
+
+	deviceToken, err := auth.MakeOneTimeToken()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to send sign-in link", err)
+		return
+	}
+	accepted := MagicLinkResponse{
+		Message:     "If an account exists for that email, a sign-in link has been sent",
+		DeviceToken: deviceToken,
+	}
+
+	user, err := cfg.db.GetUserByEmail(r.Context(), params.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithJSON(w, http.StatusAccepted, accepted)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to send sign-in link", err)
+		return
+	}
+
+	// Members of a tenant that requires SSO sign in there; they get no
+	// link, and the answer doesn't say so
+	ssoOnly, err := cfg.db.UserRequiresSSO(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to send sign-in link", err)
+		return
+	}
+	if ssoOnly {
+		respondWithJSON(w, http.StatusAccepted, accepted)
+		return
+	}
+
+	token, err := auth.MakeOneTimeToken()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to send sign-in link", err)
+		return
+	}
+
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		// Only the newest link works
+		if err := q.InvalidateMagicLinkTokens(r.Context(), user.ID); err != nil {
+			return err
+		}
+		_, err := q.CreateMagicLinkToken(r.Context(), database.CreateMagicLinkTokenParams{
+			UserID:     user.ID,
+			TokenHash:  auth.HashToken(token),
+			DeviceHash: auth.HashToken(deviceToken),
+			ExpiresAt:  time.Now().Add(magicLinkTTL),
+		})
+		if err != nil {
+			return err
+		}
+		return cfg.enqueueEmail(r.Context(), q, user.Email, mailer.TemplateMagicLink, mailer.MagicLinkData{
+			LoginURL:         cfg.config.AppURL + "/magic-link?token=" + url.QueryEscape(token),
+			ExpiresInMinutes: int(magicLinkTTL.Minutes()),
+		})
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to send sign-in link", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "magic link requested",
+		"request_id", reqID,
+		"user_id", user.ID,
+	)
+
+	respondWithJSON(w, http.StatusAccepted, accepted)
+}
+
+// handlerMagicLinkConsume signs a user in with the token from a sign-in
+// link and the device token from the request that sent it. The link is
+// spent; following it proves the user owns the email, so the account is
+// marked verified. Users with MFA on still get a challenge.
+func (cfg *apiConfig) handlerMagicLinkConsume(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	type parameters struct {
+		Token       string `json:"token"`
+		DeviceToken string `json:"device_token"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	if params.Token == "" || params.DeviceToken == "" {
+		respondWithError(w, http.StatusBadRequest, "Token and device token are required", nil)
+		return
+	}
+
+	errInvalidLink := errors.New("invalid magic link")
+	errSSORequired := errors.New("sso required")
+	var user database.User
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		link, err := q.GetActiveMagicLinkToken(r.Context(), auth.HashToken(params.Token))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errInvalidLink
+			}
+			return err
+		}
+		// A link opened elsewhere is left for the browser that asked for it
+		if subtle.ConstantTimeCompare([]byte(link.DeviceHash), []byte(auth.HashToken(params.DeviceToken))) != 1 {
+			return errInvalidLink
+		}
+
+		ssoOnly, err := q.UserRequiresSSO(r.Context(), link.UserID)
+		if err != nil {
+			return err
+		}
+		if ssoOnly {
+			return errSSORequired
+		}
+
+		if err := q.InvalidateMagicLinkTokens(r.Context(), link.UserID); err != nil {
+			return err
+		}
+		user, err = q.MarkUserVerified(r.Context(), link.UserID)
+		return err
+	})
+	switch {
+	case errors.Is(err, errInvalidLink):
+		respondWithError(w, http.StatusBadRequest, "Sign-in link is invalid or has expired", nil)
+		return
+	case errors.Is(err, errSSORequired):
+		respondWithErrorCode(w, http.StatusForbidden, problem.CodeSSORequired, "Your organization requires signing in with single sign-on", nil)
+		return
+	case err != nil:
+		respondWithError(w, http.StatusInternalServerError, "Unable to sign in", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "magic link used",
+		"request_id", reqID,
+		"user_id", user.ID,
+	)
+
+	cfg.finishLogin(w, r, user)
+}
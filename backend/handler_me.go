@@ -0,0 +1,318 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/database"
+	mediapkg "github.com/dfodeker/terminus/internal/media"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/ratelimit"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/google/uuid"
+)
+
+const (
+	// avatarMaxBytes caps an uploaded profile picture
+	avatarMaxBytes = 5 << 20
+
+	maxDisplayNameLength = 100
+	// maxEmailLength is the width of users.email
+	maxEmailLength = 100
+)
+
+// currentPasswordQuota caps the current-password checks of one user, so a
+// stolen access token can't be used to guess the password
+var currentPasswordQuota = ratelimit.Quota{Requests: 5, Window: 15 * time.Minute}
+
+// MeResponse is the signed-in user's own account
+type MeResponse struct {
+	User
+	AvatarURL *string `json:"avatar_url"`
+	// PendingEmail is the address the account moves to once the link sent
+	// there is followed
+	PendingEmail *string `json:"pending_email"`
+}
+
+// handlerMeGet returns the signed-in user's account
+func (cfg *apiConfig) handlerMeGet(w http.ResponseWriter, r *http.Request) {
+	user, ok := cfg.loadMe(w, r)
+	if !ok {
+		return
+	}
+
+	resp, err := cfg.meToResponse(r, user)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve account", err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerMeUpdate changes the signed-in user's display name, email or
+// password. A new email only takes over once the link sent to it is
+// followed. Changing the email or password takes the current password;
+// accounts without one, such as those created through single sign-on, set
+// one through a password reset first. Other sessions stay signed in;
+// POST /me/revoke-all ends them.
+func (cfg *apiConfig) handlerMeUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	type parameters struct {
+		DisplayName     *string `json:"display_name"`
+		Email           *string `json:"email"`
+		Password        *string `json:"password"`
+		CurrentPassword string  `json:"current_password"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	user, ok := cfg.loadMe(w, r)
+	if !ok {
+		return
+	}
+
+	var v validate.Validator
+	if params.DisplayName != nil {
+		name := strings.TrimSpace(*params.DisplayName)
+		params.DisplayName = &name
+		v.MaxLength("display_name", name, maxDisplayNameLength)
+	}
+	if params.Email != nil {
+		email := strings.TrimSpace(*params.Email)
+		if email == user.Email {
+			params.Email = nil
+		} else if v.Required("email", email) && v.Email("email", email) {
+			v.MaxLength("email", email, maxEmailLength)
+			params.Email = &email
+		}
+	}
+	if params.Password != nil {
+		v.Check(len(*params.Password) >= 8, "password", validate.CodeTooShort, "must be at least 8 characters")
+	}
+	if params.Email != nil || params.Password != nil {
+		v.Required("current_password", params.CurrentPassword)
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	if params.Email != nil || params.Password != nil {
+		if !cfg.limit(w, r, "current_password:"+user.ID.String(), currentPasswordQuota) {
+			return
+		}
+		if err := auth.CheckPasswordHash(params.CurrentPassword, user.HashedPassword); err != nil {
+			respondWithErrorCode(w, http.StatusForbidden, problem.CodeForbidden, "Current password is incorrect", nil)
+			return
+		}
+	}
+
+	var hash string
+	if params.Password != nil {
+		hash, err = auth.HashPassword(*params.Password)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Unable to use that password", err)
+			return
+		}
+	}
+
+	if params.Email != nil {
+		_, err := cfg.db.GetUserByEmail(r.Context(), *params.Email)
+		if err == nil {
+			respondWithErrorCode(w, http.StatusConflict, problem.CodeEmailTaken, "An account with this email already exists", nil)
+			return
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusInternalServerError, "Unable to update account", err)
+			return
+		}
+	}
+
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		if params.DisplayName != nil {
+			user, err = q.UpdateUserProfile(r.Context(), database.UpdateUserProfileParams{
+				ID:          user.ID,
+				DisplayName: sql.NullString{String: *params.DisplayName, Valid: *params.DisplayName != ""},
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if params.Password != nil {
+			user, err = q.UpdateUserPassword(r.Context(), database.UpdateUserPasswordParams{
+				ID:             user.ID,
+				HashedPassword: hash,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if params.Email != nil {
+			return cfg.sendEmailChangeEmail(r.Context(), q, user, *params.Email)
+		}
+		return nil
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update account", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "account updated",
+		"request_id", reqID,
+		"user_id", user.ID,
+		"email_change", params.Email != nil,
+		"password_change", params.Password != nil,
+	)
+
+	resp, err := cfg.meToResponse(r, user)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve account", err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerMeAvatarUpload replaces the signed-in user's profile picture with
+// the image in the request body
+func (cfg *apiConfig) handlerMeAvatarUpload(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	user, ok := cfg.loadMe(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, avatarMaxBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondWithError(w, http.StatusRequestEntityTooLarge, "Profile pictures must be 5MB or smaller", nil)
+			return
+		}
+		respondWithError(w, http.StatusBadRequest, "Please upload an image", err)
+		return
+	}
+
+	// The bytes decide the type, not the header the client sent
+	contentType := http.DetectContentType(data)
+	ext, ok := mediapkg.Extensions[contentType]
+	if !ok {
+		respondWithError(w, http.StatusBadRequest, "Profile pictures must be JPEG, PNG, WebP or GIF images", nil)
+		return
+	}
+
+	key := "avatars/" + user.ID.String() + "/" + uuid.NewString() + ext
+	if err := cfg.storage.Put(r.Context(), key, contentType, bytes.NewReader(data), int64(len(data))); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to store profile picture", err)
+		return
+	}
+
+	previous := user.AvatarKey
+	user, err = cfg.db.UpdateUserAvatar(r.Context(), database.UpdateUserAvatarParams{
+		ID:        user.ID,
+		AvatarKey: sql.NullString{String: key, Valid: true},
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update profile picture", err)
+		return
+	}
+	cfg.deleteAvatar(r, previous)
+
+	slog.InfoContext(r.Context(), "avatar updated",
+		"request_id", reqID,
+		"user_id", user.ID,
+	)
+
+	resp, err := cfg.meToResponse(r, user)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve account", err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerMeAvatarDelete removes the signed-in user's profile picture
+func (cfg *apiConfig) handlerMeAvatarDelete(w http.ResponseWriter, r *http.Request) {
+	user, ok := cfg.loadMe(w, r)
+	if !ok {
+		return
+	}
+	if !user.AvatarKey.Valid {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	_, err := cfg.db.UpdateUserAvatar(r.Context(), database.UpdateUserAvatarParams{ID: user.ID})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to remove profile picture", err)
+		return
+	}
+	cfg.deleteAvatar(r, user.AvatarKey)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// deleteAvatar removes a replaced profile picture from storage. The
+// account no longer points at it, so a failure only leaves an orphan.
+func (cfg *apiConfig) deleteAvatar(r *http.Request, key sql.NullString) {
+	if !key.Valid {
+		return
+	}
+	if err := cfg.storage.Delete(r.Context(), key.String); err != nil {
+		slog.WarnContext(r.Context(), "avatar cleanup failed", "key", key.String, "error", err)
+	}
+}
+
+// loadMe fetches the signed-in user, writing an error response if that
+// fails
+func (cfg *apiConfig) loadMe(w http.ResponseWriter, r *http.Request) (database.User, bool) {
+	userID, ok := userFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return database.User{}, false
+	}
+
+	user, err := cfg.db.GetUserByID(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+			return database.User{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve account", err)
+		return database.User{}, false
+	}
+	return user, true
+}
+
+func (cfg *apiConfig) meToResponse(r *http.Request, u database.User) (MeResponse, error) {
+	resp := MeResponse{User: userToResponse(u)}
+	if u.AvatarKey.Valid {
+		url := cfg.storage.URL(u.AvatarKey.String)
+		resp.AvatarURL = &url
+	}
+
+	pending, err := cfg.db.GetPendingEmailChange(r.Context(), u.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return MeResponse{}, err
+	}
+	if pending.Valid {
+		resp.PendingEmail = &pending.String
+	}
+	return resp, nil
+}
@@ -0,0 +1,564 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/crypto"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/lockout"
+	"github.com/dfodeker/terminus/internal/mfa"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/google/uuid"
+)
+
+const (
+	// mfaChallengeTTL is how long a user has to enter a code after their
+	// password
+	mfaChallengeTTL = 5 * time.Minute
+	// mfaChallengeMaxAttempts wrong codes burn the challenge, and the user
+	// has to enter their password again
+	mfaChallengeMaxAttempts = 5
+	mfaIssuer               = "Terminus"
+)
+
+// MFAChallengeResponse is returned by POST /login instead of tokens when
+// the user has MFA on
+type MFAChallengeResponse struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+}
+
+type MFAStatusResponse struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+}
+
+type MFAEnrollResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// MFARecoveryCodesResponse carries recovery codes in plain text. They are
+// only ever shown once.
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// startMFAChallenge issues a challenge token when the user has MFA on.
+// required is false, and no challenge is made, when they don't.
+func (cfg *apiConfig) startMFAChallenge(ctx context.Context, userID uuid.UUID) (token string, required bool, err error) {
+	row, err := cfg.db.GetUserMFA(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	if !row.EnabledAt.Valid {
+		return "", false, nil
+	}
+
+	token, err = auth.MakeOneTimeToken()
+	if err != nil {
+		return "", false, err
+	}
+
+	_, err = cfg.db.CreateMFAChallenge(ctx, database.CreateMFAChallengeParams{
+		UserID:    userID,
+		TokenHash: auth.HashToken(token),
+		ExpiresAt: time.Now().Add(mfaChallengeTTL),
+	})
+	if err != nil {
+		return "", false, err
+	}
+	return token, true, nil
+}
+
+// checkMFACode accepts either a code from the authenticator app or an
+// unused recovery code. Accepted codes are spent.
+func checkMFACode(ctx context.Context, q *database.Queries, row database.UserMfa, code, recoveryCode string) (bool, error) {
+	if code != "" {
+		step, err := mfa.Validate(string(row.Secret), code, time.Now(), row.LastUsedStep)
+		if err != nil {
+			if errors.Is(err, mfa.ErrInvalidCode) {
+				return false, nil
+			}
+			return false, err
+		}
+		err = q.UpdateUserMFALastStep(ctx, database.UpdateUserMFALastStepParams{
+			UserID:       row.UserID,
+			LastUsedStep: step,
+		})
+		return err == nil, err
+	}
+
+	if recoveryCode != "" {
+		n, err := q.UseMFARecoveryCode(ctx, database.UseMFARecoveryCodeParams{
+			UserID:   row.UserID,
+			CodeHash: auth.HashToken(mfa.NormalizeRecoveryCode(recoveryCode)),
+		})
+		return n == 1, err
+	}
+
+	return false, nil
+}
+
+// replaceRecoveryCodes drops a user's recovery codes and returns a fresh set
+func replaceRecoveryCodes(ctx context.Context, q *database.Queries, userID uuid.UUID) ([]string, error) {
+	if err := q.DeleteMFARecoveryCodes(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	codes, err := mfa.GenerateRecoveryCodes(mfa.RecoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+	for _, code := range codes {
+		err := q.CreateMFARecoveryCode(ctx, database.CreateMFARecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: auth.HashToken(mfa.NormalizeRecoveryCode(code)),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return codes, nil
+}
+
+// handlerLoginMFA finishes a sign-in started by POST /login, trading the
+// challenge token and a code for access and refresh tokens
+func (cfg *apiConfig) handlerLoginMFA(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	type parameters struct {
+		MFAToken     string `json:"mfa_token"`
+		Code         string `json:"code"`
+		RecoveryCode string `json:"recovery_code"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	if params.MFAToken == "" || (params.Code == "" && params.RecoveryCode == "") {
+		respondWithError(w, http.StatusBadRequest, "mfa_token and a code or recovery_code are required", nil)
+		return
+	}
+
+	challenge, err := cfg.db.GetActiveMFAChallenge(r.Context(), auth.HashToken(params.MFAToken))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusUnauthorized, "Sign-in has expired, please log in again", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to verify code", err)
+		return
+	}
+
+	user, err := cfg.db.GetUserByID(r.Context(), challenge.UserID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve user", err)
+		return
+	}
+
+	// Wrong codes count against the account like wrong passwords, so a
+	// stolen password doesn't buy unlimited guesses at the code
+	attempt := userLoginAttempt(r, user.Email)
+	who := loginSubject{UserID: user.ID, Email: user.Email}
+	if _, ok := cfg.checkLoginLock(w, r, attempt); !ok {
+		return
+	}
+
+	var resp LoginResponse
+	rejected := false
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		row, err := q.GetUserMFAForUpdate(r.Context(), challenge.UserID)
+		if err != nil {
+			return err
+		}
+
+		valid, err := checkMFACode(r.Context(), q, row, params.Code, params.RecoveryCode)
+		if err != nil {
+			return err
+		}
+
+		if !valid {
+			// Keep the attempt count even though sign-in fails
+			rejected = true
+			challenge, err = q.IncrementMFAChallengeAttempts(r.Context(), challenge.ID)
+			if err != nil {
+				return err
+			}
+			if challenge.Attempts >= mfaChallengeMaxAttempts {
+				return q.MarkMFAChallengeUsed(r.Context(), challenge.ID)
+			}
+			return nil
+		}
+
+		if err := q.MarkMFAChallengeUsed(r.Context(), challenge.ID); err != nil {
+			return err
+		}
+		resp, err = cfg.issueLoginTokens(r.Context(), q, user, sessionMetaFromRequest(r))
+		return err
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to verify code", err)
+		return
+	}
+
+	if rejected {
+		slog.WarnContext(r.Context(), "mfa login code rejected",
+			"request_id", reqID,
+			"user_id", challenge.UserID,
+			"attempts", challenge.Attempts,
+		)
+		cfg.failLogin(r, attempt, lockout.EventMFAFailed, who)
+		respondWithError(w, http.StatusUnauthorized, "Invalid authentication code", nil)
+		return
+	}
+
+	cfg.succeedLogin(r, attempt)
+
+	slog.InfoContext(r.Context(), "mfa login completed",
+		"request_id", reqID,
+		"user_id", user.ID,
+		"recovery_code", params.Code == "",
+	)
+
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerMFAStatus reports whether the signed-in user has MFA on
+func (cfg *apiConfig) handlerMFAStatus(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	resp := MFAStatusResponse{}
+
+	row, err := cfg.db.GetUserMFA(r.Context(), userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve MFA status", err)
+		return
+	}
+	if err == nil && row.EnabledAt.Valid {
+		resp.Enabled = true
+		resp.EnabledAt = &row.EnabledAt.Time
+
+		resp.RecoveryCodesRemaining, err = cfg.db.CountUnusedMFARecoveryCodes(r.Context(), userID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve MFA status", err)
+			return
+		}
+	}
+
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerMFAEnroll starts MFA enrollment with a new secret. MFA isn't on
+// until the user confirms a code from their app.
+func (cfg *apiConfig) handlerMFAEnroll(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	userID, ok := userFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	user, err := cfg.db.GetUserByID(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve user", err)
+		return
+	}
+
+	secret, err := mfa.GenerateSecret()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to start MFA enrollment", err)
+		return
+	}
+
+	_, err = cfg.db.UpsertPendingUserMFA(r.Context(), database.UpsertPendingUserMFAParams{
+		UserID: userID,
+		Secret: crypto.Text(secret),
+	})
+	if err != nil {
+		// The upsert skips users who already have MFA on
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusConflict, "Two-factor authentication is already enabled", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to start MFA enrollment", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "mfa enrollment started",
+		"request_id", reqID,
+		"user_id", userID,
+	)
+
+	respondWithJSON(w, http.StatusOK, MFAEnrollResponse{
+		Secret:          secret,
+		ProvisioningURI: mfa.ProvisioningURI(mfaIssuer, user.Email, secret),
+	})
+}
+
+// handlerMFAConfirm turns MFA on once the user enters a code from their
+// app, and returns their first set of recovery codes
+func (cfg *apiConfig) handlerMFAConfirm(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	userID, ok := userFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	type parameters struct {
+		Code string `json:"code"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	var codes []string
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		row, err := q.GetUserMFAForUpdate(r.Context(), userID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return service.Invalid("Start MFA enrollment first")
+		}
+		if err != nil {
+			return err
+		}
+		if row.EnabledAt.Valid {
+			return service.Conflict("Two-factor authentication is already enabled")
+		}
+
+		step, err := mfa.Validate(string(row.Secret), params.Code, time.Now(), row.LastUsedStep)
+		if err != nil {
+			return service.Invalid("Invalid authentication code")
+		}
+
+		_, err = q.EnableUserMFA(r.Context(), database.EnableUserMFAParams{
+			UserID:       userID,
+			LastUsedStep: step,
+		})
+		if err != nil {
+			return err
+		}
+
+		codes, err = replaceRecoveryCodes(r.Context(), q, userID)
+		return err
+	})
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to enable MFA")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "mfa enabled",
+		"request_id", reqID,
+		"user_id", userID,
+	)
+
+	respondWithJSON(w, http.StatusOK, MFARecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// handlerMFARecoveryCodesRegenerate replaces the user's recovery codes.
+// It takes a current code so a stolen access token alone can't do it.
+func (cfg *apiConfig) handlerMFARecoveryCodesRegenerate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	userID, ok := userFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	type parameters struct {
+		Code string `json:"code"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	var codes []string
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		row, err := enabledMFA(r.Context(), q, userID)
+		if err != nil {
+			return err
+		}
+
+		valid, err := checkMFACode(r.Context(), q, row, params.Code, "")
+		if err != nil {
+			return err
+		}
+		if !valid {
+			return service.Invalid("Invalid authentication code")
+		}
+
+		codes, err = replaceRecoveryCodes(r.Context(), q, userID)
+		return err
+	})
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to create recovery codes")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "mfa recovery codes regenerated",
+		"request_id", reqID,
+		"user_id", userID,
+	)
+
+	respondWithJSON(w, http.StatusOK, MFARecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// handlerMFADisable turns MFA off with a current code or a recovery code
+func (cfg *apiConfig) handlerMFADisable(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	userID, ok := userFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	type parameters struct {
+		Code         string `json:"code"`
+		RecoveryCode string `json:"recovery_code"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		row, err := enabledMFA(r.Context(), q, userID)
+		if err != nil {
+			return err
+		}
+
+		valid, err := checkMFACode(r.Context(), q, row, params.Code, params.RecoveryCode)
+		if err != nil {
+			return err
+		}
+		if !valid {
+			return service.Invalid("Invalid authentication code")
+		}
+
+		if err := q.DeleteMFARecoveryCodes(r.Context(), userID); err != nil {
+			return err
+		}
+		return q.DeleteUserMFA(r.Context(), userID)
+	})
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to disable MFA")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "mfa disabled",
+		"request_id", reqID,
+		"user_id", userID,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// enabledMFA locks the user's authenticator, failing as invalid when MFA
+// isn't on
+func enabledMFA(ctx context.Context, q *database.Queries, userID uuid.UUID) (database.UserMfa, error) {
+	row, err := q.GetUserMFAForUpdate(ctx, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return database.UserMfa{}, err
+	}
+	if err != nil || !row.EnabledAt.Valid {
+		return database.UserMfa{}, service.Invalid("Two-factor authentication is not enabled")
+	}
+	return row, nil
+}
+
+// handlerTenantMFAPolicyUpdate turns the tenant's MFA requirement on or off.
+// Members without MFA lose sensitive permissions until they enable it.
+func (cfg *apiConfig) handlerTenantMFAPolicyUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID := access.UserID, access.TenantID
+
+	type parameters struct {
+		RequireMFA *bool `json:"require_mfa"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil || params.RequireMFA == nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	if *params.RequireMFA {
+		// Don't let an admin lock themselves out
+		status, err := cfg.db.GetTenantMFAPolicyStatus(r.Context(), database.GetTenantMFAPolicyStatusParams{
+			UserID:   user,
+			TenantID: tenantID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to update MFA policy", err)
+			return
+		}
+		if !status.MfaEnabled {
+			respondWithError(w, http.StatusBadRequest, "Enable two-factor authentication on your own account first", nil)
+			return
+		}
+	}
+
+	tenant, err := cfg.db.UpdateTenantRequireMFA(r.Context(), database.UpdateTenantRequireMFAParams{
+		ID:         tenantID,
+		RequireMfa: *params.RequireMFA,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update MFA policy", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant mfa policy updated",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"require_mfa", tenant.RequireMfa,
+	)
+
+	respondWithJSON(w, http.StatusOK, map[string]any{
+		"tenant_id":   tenant.ID,
+		"require_mfa": tenant.RequireMfa,
+	})
+}
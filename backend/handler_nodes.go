@@ -0,0 +1,166 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/service/nodes"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+// nodePermissions is what reading each node type takes beyond membership
+// of its tenant, matching the REST routes that list them
+var nodePermissions = map[gid.EntityType]string{
+	gid.EntityProduct:        "products:view",
+	gid.EntityProductVariant: "products:view",
+	gid.EntityCollection:     "products:view",
+	gid.EntityCustomer:       "customers:view",
+	gid.EntityOrder:          "orders:view",
+}
+
+type NodeResponse struct {
+	ID   string         `json:"id"`
+	Type gid.EntityType `json:"type"`
+	Data any            `json:"data"`
+}
+
+// handlerNodeGet looks up any record by its GID, in canonical or base64
+// form, and returns it if the caller could read it through its tenant
+func (cfg *apiConfig) handlerNodeGet(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	gidParam := chi.URLParam(r, "gid")
+
+	slog.InfoContext(r.Context(), "node lookup request received",
+		"request_id", reqID,
+		"gid_param", gidParam,
+	)
+
+	// Canonical GIDs contain slashes, so they arrive escaped
+	raw, err := url.PathUnescape(gidParam)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid GID", err)
+		return
+	}
+	g, err := gid.Parse(raw)
+	if err != nil {
+		g, err = gid.ParseBase64(raw)
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid GID", err)
+		return
+	}
+
+	node, err := cfg.services.nodes.Lookup(r.Context(), g)
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to look up node")
+		return
+	}
+
+	// Hold the node to the same checks as the tenant's own routes
+	chi.RouteContext(r.Context()).URLParams.Add("tenantID", node.TenantID.String())
+	checks := chi.Chain(cfg.requireAPIKeyTenant, cfg.requireClaimedTenant, cfg.requireTenantMember)
+	if permission, ok := nodePermissions[g.Type]; ok {
+		checks = append(checks, cfg.requirePermission(permission))
+	}
+	checks.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The lookup above ran past tenant isolation to find the owner;
+		// only what the tenant's own connection reads is handed out
+		node, err := cfg.services.nodes.Lookup(r.Context(), g)
+		if err != nil {
+			respondWithServiceError(w, err, "Unable to look up node")
+			return
+		}
+		cfg.respondWithNode(w, r, node)
+	}).ServeHTTP(w, r)
+}
+
+// respondWithNode writes node in the shape its own REST endpoint uses
+func (cfg *apiConfig) respondWithNode(w http.ResponseWriter, r *http.Request, node nodes.Node) {
+	var data any
+	switch v := node.Value.(type) {
+	case database.Tenant:
+		data = TenantResponse{
+			ID:        v.ID,
+			GID:       node.GID.String(),
+			Name:      v.Name,
+			Status:    v.Status,
+			CreatedAt: v.CreatedAt,
+			UpdatedAt: v.UpdatedAt,
+		}
+
+	case database.Store:
+		data = TenantStoreResponse{
+			ID:              v.ID,
+			TenantID:        &v.TenantID.UUID,
+			Name:            v.Name,
+			Handle:          v.Handle,
+			Address:         v.Address,
+			Status:          v.Status,
+			DefaultCurrency: v.DefaultCurrency,
+			DefaultLocale:   v.DefaultLocale,
+			Timezone:        v.Timezone,
+			Plan:            v.Plan,
+			CreatedAt:       v.CreatedAt,
+			UpdatedAt:       v.UpdatedAt,
+		}
+
+	case database.Product:
+		products, err := cfg.tenantProductsToResponse(r.Context(), []database.Product{v})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product", err)
+			return
+		}
+		data = products[0]
+
+	case database.ProductVariant:
+		data = variantToResponse(v)
+
+	case database.Role:
+		permissions, err := cfg.db.GetPermissionsByRoleID(r.Context(), v.ID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve role permissions", err)
+			return
+		}
+		keys := make([]string, 0, len(permissions))
+		for _, perm := range permissions {
+			keys = append(keys, perm.Key)
+		}
+		var desc *string
+		if v.Description.Valid {
+			desc = &v.Description.String
+		}
+		data = RoleResponse{
+			ID:          v.ID,
+			TenantID:    v.TenantID,
+			Name:        v.Name,
+			Description: desc,
+			Permissions: keys,
+			CreatedAt:   v.CreatedAt,
+			UpdatedAt:   v.UpdatedAt,
+		}
+
+	case database.Customer:
+		data = tenantCustomerToResponse(v)
+
+	case database.Order:
+		order, err := cfg.tenantOrderDetail(r.Context(), v)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve order", err)
+			return
+		}
+		data = order
+
+	case database.Collection:
+		data = collectionToResponse(v)
+	}
+
+	respondWithJSON(w, http.StatusOK, NodeResponse{
+		ID:   node.GID.String(),
+		Type: node.GID.Type,
+		Data: data,
+	})
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/oauth"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/sso"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// oauthStateTTL is how long a user has to sign in at the provider
+const oauthStateTTL = 10 * time.Minute
+
+// errIdentityUnverified turns away a provider account whose email can't be
+// trusted to link or create an account by
+var errIdentityUnverified = errors.New("identity email not verified")
+
+type UserIdentity struct {
+	ID          uuid.UUID  `json:"id"`
+	Provider    string     `json:"provider"`
+	Email       string     `json:"email"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+}
+
+func userIdentityToResponse(i database.UserIdentity) UserIdentity {
+	resp := UserIdentity{
+		ID:        i.ID,
+		Provider:  i.Provider,
+		Email:     i.Email,
+		CreatedAt: i.CreatedAt,
+	}
+	if i.LastLoginAt.Valid {
+		resp.LastLoginAt = &i.LastLoginAt.Time
+	}
+	return resp
+}
+
+// handlerOAuthProviders lists the providers users can sign in through
+func (cfg *apiConfig) handlerOAuthProviders(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, map[string][]string{"providers": cfg.oauth.Names()})
+}
+
+// handlerOAuthStart begins a sign-in at a provider and returns the URL to
+// send the user to. The provider sends them back to OAUTH_REDIRECT_URL
+// with a code and the state, which the app posts to /login/oauth/callback.
+// The app should check the state is the one it was given here, so no one
+// can sign a user in to someone else's account.
+func (cfg *apiConfig) handlerOAuthStart(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "provider")
+	provider, ok := cfg.oauth.Lookup(name)
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "Sign-in provider not found", nil)
+		return
+	}
+
+	req, err := oauth.NewAuthRequest()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to start sign-in", err)
+		return
+	}
+	authURL, err := provider.AuthURL(r.Context(), req)
+	if err != nil {
+		respondWithError(w, http.StatusBadGateway, "Unable to reach the sign-in provider", err)
+		return
+	}
+
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		if err := q.DeleteExpiredOAuthStates(r.Context()); err != nil {
+			return err
+		}
+		return q.CreateOAuthState(r.Context(), database.CreateOAuthStateParams{
+			StateHash:    auth.HashToken(req.State),
+			Provider:     name,
+			Nonce:        req.Nonce,
+			CodeVerifier: req.Verifier,
+			ExpiresAt:    time.Now().Add(oauthStateTTL),
+		})
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to start sign-in", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{
+		"authorization_url": authURL,
+		"state":             req.State,
+	})
+}
+
+// handlerOAuthCallback finishes a sign-in at a provider. The provider
+// account signs in as the user it is linked to. An account not linked yet
+// is linked to the user with its email, if both the provider and the user
+// have verified it, or else signs up a new user.
+func (cfg *apiConfig) handlerOAuthCallback(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	type parameters struct {
+		State string `json:"state"`
+		Code  string `json:"code"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil || params.State == "" || params.Code == "" {
+		respondWithError(w, http.StatusBadRequest, "state and code are required", err)
+		return
+	}
+
+	state, err := cfg.db.ConsumeOAuthState(r.Context(), auth.HashToken(params.State))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusUnauthorized, "Sign-in has expired, please try again", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to sign in", err)
+		return
+	}
+	if strings.HasPrefix(state.Provider, sso.ProviderPrefix) {
+		cfg.finishSSOCallback(w, r, state, params.State, params.Code)
+		return
+	}
+	provider, ok := cfg.oauth.Lookup(state.Provider)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Sign-in provider is no longer available", nil)
+		return
+	}
+
+	identity, err := provider.Exchange(r.Context(), params.Code, oauth.AuthRequest{
+		State:    params.State,
+		Nonce:    state.Nonce,
+		Verifier: state.CodeVerifier,
+	})
+	if err != nil {
+		if errors.Is(err, oauth.ErrRejected) {
+			respondWithError(w, http.StatusUnauthorized, "The sign-in provider rejected the sign-in", err)
+			return
+		}
+		respondWithError(w, http.StatusBadGateway, "Unable to reach the sign-in provider", err)
+		return
+	}
+
+	var (
+		user   database.User
+		linked bool
+	)
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		user, linked, err = cfg.resolveIdentityUser(r, q, state.Provider, identity)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, errIdentityUnverified) {
+			respondWithErrorCode(w, http.StatusForbidden, problem.CodeEmailNotVerified,
+				fmt.Sprintf("Your %s account's email has to be verified, both there and here, before it can be used to sign in", state.Provider), nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to sign in", err)
+		return
+	}
+
+	// A tenant that has its own identity provider and requires SSO wants
+	// its members signing in through that, not a personal account
+	required, err := cfg.db.UserRequiresTenantSSO(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to sign in", err)
+		return
+	}
+	if required {
+		respondWithErrorCode(w, http.StatusForbidden, problem.CodeSSORequired, "Your organization requires signing in through its single sign-on", nil)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "oauth login",
+		"request_id", reqID,
+		"user_id", user.ID,
+		"provider", state.Provider,
+		"linked", linked,
+	)
+
+	cfg.finishLogin(w, r, user)
+}
+
+// resolveIdentityUser returns the user a provider account signs in as,
+// linking or creating one the first time. linked reports whether the
+// account was linked just now.
+func (cfg *apiConfig) resolveIdentityUser(r *http.Request, q *database.Queries, provider string, id oauth.Identity) (database.User, bool, error) {
+	ctx := r.Context()
+	existing, err := q.GetUserIdentity(ctx, database.GetUserIdentityParams{
+		Provider: provider,
+		Subject:  id.Subject,
+	})
+	if err == nil {
+		if err := q.TouchUserIdentity(ctx, existing.ID); err != nil {
+			return database.User{}, false, err
+		}
+		user, err := q.GetUserByID(ctx, existing.UserID)
+		return user, false, err
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return database.User{}, false, err
+	}
+
+	// Linking by email is only safe when both sides have proven they own
+	// it; otherwise whoever registered the address first could take over
+	// the account
+	if id.Email == "" || !id.EmailVerified {
+		return database.User{}, false, errIdentityUnverified
+	}
+	user, err := q.GetUserByEmail(ctx, id.Email)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		user, err = q.CreateSSOUser(ctx, database.CreateSSOUserParams{
+			Gid:   sql.NullInt64{Int64: int64(cfg.gidGen.Generate()), Valid: true},
+			Email: id.Email,
+		})
+		if err != nil {
+			return database.User{}, false, err
+		}
+	case err != nil:
+		return database.User{}, false, err
+	case !user.VerifiedAt.Valid:
+		return database.User{}, false, errIdentityUnverified
+	}
+
+	if _, err := q.CreateUserIdentity(ctx, database.CreateUserIdentityParams{
+		UserID:   user.ID,
+		Provider: provider,
+		Subject:  id.Subject,
+		Email:    id.Email,
+	}); err != nil {
+		return database.User{}, false, err
+	}
+	return user, true, nil
+}
+
+// handlerUserIdentitiesList lists the provider accounts linked to the
+// signed-in user
+func (cfg *apiConfig) handlerUserIdentitiesList(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	rows, err := cfg.db.ListUserIdentities(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve linked accounts", err)
+		return
+	}
+	resp := make([]UserIdentity, 0, len(rows))
+	for _, row := range rows {
+		resp = append(resp, userIdentityToResponse(row))
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerTenantSSOPolicyUpdate turns on or off the tenant's requirement
+// that members sign in through a provider rather than with a password
+func (cfg *apiConfig) handlerTenantSSOPolicyUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID := access.UserID, access.TenantID
+
+	type parameters struct {
+		RequireSSO *bool `json:"require_sso"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil || params.RequireSSO == nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	if *params.RequireSSO {
+		conn, err := cfg.db.GetSSOConnectionByTenant(r.Context(), tenantID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusInternalServerError, "Unable to update SSO policy", err)
+			return
+		}
+		if len(cfg.oauth) == 0 && (err != nil || !conn.Enabled) {
+			respondWithError(w, http.StatusBadRequest, "No sign-in providers are configured", nil)
+			return
+		}
+		// Don't let an admin lock themselves out
+		identities, err := cfg.db.ListUserIdentities(r.Context(), user)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to update SSO policy", err)
+			return
+		}
+		if len(identities) == 0 {
+			respondWithError(w, http.StatusBadRequest, "Sign in through a provider on your own account first", nil)
+			return
+		}
+	}
+
+	tenant, err := cfg.db.UpdateTenantRequireSSO(r.Context(), database.UpdateTenantRequireSSOParams{
+		ID:         tenantID,
+		RequireSso: *params.RequireSSO,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update SSO policy", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant sso policy updated",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"require_sso", tenant.RequireSso,
+	)
+
+	respondWithJSON(w, http.StatusOK, map[string]any{
+		"tenant_id":   tenant.ID,
+		"require_sso": tenant.RequireSso,
+	})
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"net/http"
+)
+
+// handlerOpenAPI serves the OpenAPI document built at startup
+func (cfg *apiConfig) handlerOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(cfg.openAPI)
+}
+
+// apiDocsPage loads Swagger UI from a CDN and points it at the document
+const apiDocsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Terminus API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/api/v1/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+// handlerAPIDocs serves Swagger UI for the OpenAPI document. It is only
+// routed in development.
+func (cfg *apiConfig) handlerAPIDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(apiDocsPage))
+}
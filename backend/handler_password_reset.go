@@ -0,0 +1,167 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/mailer"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/middleware"
+)
+
+// passwordResetTTL is how long a reset link stays valid
+const passwordResetTTL = time.Hour
+
+// handlerPasswordForgot emails a password reset link. It answers the same
+// way whether or not the account exists so it can't be used to probe for
+// registered addresses.
+func (cfg *apiConfig) handlerPasswordForgot(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	type parameters struct {
+		Email string `json:"email"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	if _, err := mail.ParseAddress(params.Email); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid email", err)
+		return
+	}
+
+	accepted := map[string]string{
+		"message": "If an account exists for that email, a password reset link has been sent",
+	}
+
+	user, err := cfg.db.GetUserByEmail(r.Context(), params.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithJSON(w, http.StatusAccepted, accepted)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to start password reset", err)
+		return
+	}
+
+	token, err := auth.MakeOneTimeToken()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to start password reset", err)
+		return
+	}
+
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		// Only the newest link works
+		if err := q.InvalidatePasswordResetTokens(r.Context(), user.ID); err != nil {
+			return err
+		}
+
+		_, err := q.CreatePasswordResetToken(r.Context(), database.CreatePasswordResetTokenParams{
+			UserID:    user.ID,
+			TokenHash: auth.HashToken(token),
+			ExpiresAt: time.Now().Add(passwordResetTTL),
+		})
+		if err != nil {
+			return err
+		}
+
+		return cfg.enqueueEmail(r.Context(), q, user.Email, mailer.TemplatePasswordReset, mailer.PasswordResetData{
+			ResetURL:         cfg.config.AppURL + "/reset-password?token=" + url.QueryEscape(token),
+			ExpiresInMinutes: int(passwordResetTTL.Minutes()),
+		})
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to start password reset", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "password reset requested",
+		"request_id", reqID,
+		"user_id", user.ID,
+	)
+
+	respondWithJSON(w, http.StatusAccepted, accepted)
+}
+
+// handlerPasswordReset sets a new password with a reset token. The token is
+// spent, and every refresh token of the account is revoked so other
+// sessions have to sign in again.
+func (cfg *apiConfig) handlerPasswordReset(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	type parameters struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	if params.Token == "" {
+		respondWithError(w, http.StatusBadRequest, "Token is required", nil)
+		return
+	}
+	if len(params.Password) < 8 {
+		respondWithError(w, http.StatusBadRequest, "Password must be at least 8 characters", nil)
+		return
+	}
+
+	hash, err := auth.HashPassword(params.Password)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Unable to use that password", err)
+		return
+	}
+
+	var user database.User
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		resetToken, err := q.GetActivePasswordResetToken(r.Context(), auth.HashToken(params.Token))
+		if errors.Is(err, sql.ErrNoRows) {
+			return service.Invalid("Reset link is invalid or has expired")
+		}
+		if err != nil {
+			return err
+		}
+
+		user, err = q.UpdateUserPassword(r.Context(), database.UpdateUserPasswordParams{
+			ID:             resetToken.UserID,
+			HashedPassword: hash,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := q.InvalidatePasswordResetTokens(r.Context(), user.ID); err != nil {
+			return err
+		}
+		return revokeAllUserSessions(r.Context(), q, user.ID)
+	})
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to reset password")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "password reset",
+		"request_id", reqID,
+		"user_id", user.ID,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/privacy"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type PrivacyRequestResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Kind       string     `json:"kind"`
+	CustomerID *uuid.UUID `json:"customer_id,omitempty"`
+	UserID     *uuid.UUID `json:"user_id,omitempty"`
+	Status     string     `json:"status"`
+	SizeBytes  *int64     `json:"size_bytes,omitempty"`
+	Error      *string    `json:"error,omitempty"`
+	// DownloadURL is set once an export has completed, until the subject
+	// is erased
+	DownloadURL       *string    `json:"download_url,omitempty"`
+	DownloadExpiresAt *time.Time `json:"download_expires_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	StartedAt         *time.Time `json:"started_at,omitempty"`
+	FinishedAt        *time.Time `json:"finished_at,omitempty"`
+}
+
+// handlerTenantCustomerPrivacyRequestCreate queues an export of everything
+// the store holds about a customer, or its erasure, on the customer's
+// behalf. Erasure is refused while the customer has orders to fulfill.
+func (cfg *apiConfig) handlerTenantCustomerPrivacyRequestCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	customer, ok := cfg.loadStoreCustomer(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		Kind string `json:"kind"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	var v validate.Validator
+	if v.Required("kind", params.Kind) {
+		v.OneOf("kind", params.Kind, privacy.KindExport, privacy.KindErasure)
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+	if params.Kind == privacy.KindErasure {
+		if err := privacy.CheckCustomerErasable(r.Context(), cfg.db, customer); err != nil {
+			respondWithServiceError(w, err, "Unable to check customer")
+			return
+		}
+	}
+
+	req, ok := cfg.createPrivacyRequest(w, r, database.CreatePrivacyRequestParams{
+		Kind:        params.Kind,
+		StoreID:     uuid.NullUUID{UUID: storeID, Valid: true},
+		CustomerID:  uuid.NullUUID{UUID: customer.ID, Valid: true},
+		RequestedBy: uuid.NullUUID{UUID: user, Valid: true},
+	})
+	if !ok {
+		return
+	}
+
+	slog.InfoContext(r.Context(), "customer privacy request queued",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"customer_id", customer.ID,
+		"privacy_request_id", req.ID,
+		"kind", req.Kind,
+	)
+
+	respondWithJSON(w, http.StatusAccepted, privacyRequestToResponse(req))
+}
+
+// handlerTenantCustomerPrivacyRequestGet reports a customer privacy
+// request's progress, with a short-lived download link once an export has
+// completed
+func (cfg *apiConfig) handlerTenantCustomerPrivacyRequestGet(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	customerID, err := uuid.Parse(chi.URLParam(r, "customerID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID format", err)
+		return
+	}
+	requestID, err := uuid.Parse(chi.URLParam(r, "requestID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid privacy request ID", err)
+		return
+	}
+
+	req, err := cfg.db.GetCustomerPrivacyRequest(r.Context(), database.GetCustomerPrivacyRequestParams{
+		ID:         requestID,
+		StoreID:    uuid.NullUUID{UUID: storeID, Valid: true},
+		CustomerID: uuid.NullUUID{UUID: customerID, Valid: true},
+	})
+	cfg.respondWithPrivacyRequest(w, r, req, err)
+}
+
+// handlerPrivacyRequestCreate queues an export of everything held about the
+// signed-in user, or the erasure of their account. Erasure asks for the
+// password again, and is refused while the user is the only owner of a
+// tenant.
+func (cfg *apiConfig) handlerPrivacyRequestCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	userID, ok := userFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	type parameters struct {
+		Kind     string `json:"kind"`
+		Password string `json:"password"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	var v validate.Validator
+	if v.Required("kind", params.Kind) {
+		v.OneOf("kind", params.Kind, privacy.KindExport, privacy.KindErasure)
+	}
+	if params.Kind == privacy.KindErasure {
+		v.Required("password", params.Password)
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	if params.Kind == privacy.KindErasure {
+		user, err := cfg.db.GetUserByID(r.Context(), userID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve user", err)
+			return
+		}
+		if err := auth.CheckPasswordHash(params.Password, user.HashedPassword); err != nil {
+			respondWithError(w, http.StatusUnauthorized, "Incorrect password", nil)
+			return
+		}
+		if err := privacy.CheckUserErasable(r.Context(), cfg.db, user); err != nil {
+			respondWithServiceError(w, err, "Unable to check account")
+			return
+		}
+	}
+
+	req, ok := cfg.createPrivacyRequest(w, r, database.CreatePrivacyRequestParams{
+		Kind:        params.Kind,
+		UserID:      uuid.NullUUID{UUID: userID, Valid: true},
+		RequestedBy: uuid.NullUUID{UUID: userID, Valid: true},
+	})
+	if !ok {
+		return
+	}
+
+	slog.InfoContext(r.Context(), "user privacy request queued",
+		"request_id", reqID,
+		"user_id", userID,
+		"privacy_request_id", req.ID,
+		"kind", req.Kind,
+	)
+
+	respondWithJSON(w, http.StatusAccepted, privacyRequestToResponse(req))
+}
+
+// handlerPrivacyRequestGet reports one of the signed-in user's privacy
+// requests
+func (cfg *apiConfig) handlerPrivacyRequestGet(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	requestID, err := uuid.Parse(chi.URLParam(r, "requestID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid privacy request ID", err)
+		return
+	}
+
+	req, err := cfg.db.GetUserPrivacyRequest(r.Context(), database.GetUserPrivacyRequestParams{
+		ID:     requestID,
+		UserID: uuid.NullUUID{UUID: userID, Valid: true},
+	})
+	cfg.respondWithPrivacyRequest(w, r, req, err)
+}
+
+// createPrivacyRequest records a request and queues the job carrying it
+// out, together. A subject has at most one open request of each kind. It
+// writes the error response itself and reports whether the request was
+// made.
+func (cfg *apiConfig) createPrivacyRequest(w http.ResponseWriter, r *http.Request, arg database.CreatePrivacyRequestParams) (database.PrivacyRequest, bool) {
+	var req database.PrivacyRequest
+	err := cfg.withTx(r.Context(), func(q *database.Queries) error {
+		var err error
+		req, err = q.CreatePrivacyRequest(r.Context(), arg)
+		if err != nil {
+			return err
+		}
+		_, err = cfg.jobs.EnqueueTx(r.Context(), q, privacy.Args{RequestID: req.ID})
+		return err
+	})
+	if service.UniqueViolation(err, "") {
+		respondWithError(w, http.StatusConflict, "A privacy request of this kind is already in progress", nil)
+		return database.PrivacyRequest{}, false
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create privacy request", err)
+		return database.PrivacyRequest{}, false
+	}
+	return req, true
+}
+
+// respondWithPrivacyRequest answers with req as loaded, err being the
+// error loading it
+func (cfg *apiConfig) respondWithPrivacyRequest(w http.ResponseWriter, r *http.Request, req database.PrivacyRequest, err error) {
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Privacy request not found", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve privacy request", err)
+		return
+	}
+
+	resp := privacyRequestToResponse(req)
+	if req.Status == privacy.StatusCompleted && req.StorageKey.Valid {
+		download, err := cfg.storage.PresignGet(r.Context(), req.StorageKey.String, exportDownloadURLTTL)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to create download URL", err)
+			return
+		}
+		resp.DownloadURL = &download.URL
+		resp.DownloadExpiresAt = &download.ExpiresAt
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+func privacyRequestToResponse(req database.PrivacyRequest) PrivacyRequestResponse {
+	resp := PrivacyRequestResponse{
+		ID:        req.ID,
+		Kind:      req.Kind,
+		Status:    req.Status,
+		CreatedAt: req.CreatedAt,
+	}
+	if req.CustomerID.Valid {
+		resp.CustomerID = &req.CustomerID.UUID
+	}
+	if req.UserID.Valid {
+		resp.UserID = &req.UserID.UUID
+	}
+	if req.SizeBytes.Valid {
+		resp.SizeBytes = &req.SizeBytes.Int64
+	}
+	if req.Error.Valid {
+		resp.Error = &req.Error.String
+	}
+	if req.StartedAt.Valid {
+		resp.StartedAt = &req.StartedAt.Time
+	}
+	if req.FinishedAt.Valid {
+		resp.FinishedAt = &req.FinishedAt.Time
+	}
+	return resp
+}
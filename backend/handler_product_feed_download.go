@@ -0,0 +1,42 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// feedDownloadURLTTL is how long the storage link a feed URL redirects to
+// works. Marketplaces follow it straight away.
+const feedDownloadURLTTL = 15 * time.Minute
+
+// handlerProductFeedDownload redirects a marketplace from a feed's stable
+// URL to a short-lived download of its latest file
+func (cfg *apiConfig) handlerProductFeedDownload(w http.ResponseWriter, r *http.Request) {
+	feed, err := cfg.db.GetProductFeedByToken(r.Context(), chi.URLParam(r, "token"))
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Product feed not found", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product feed", err)
+		return
+	}
+	if !feed.StorageKey.Valid {
+		respondWithError(w, http.StatusNotFound, "Product feed has not been generated yet", nil)
+		return
+	}
+
+	download, err := cfg.storage.PresignGet(r.Context(), feed.StorageKey.String, feedDownloadURLTTL)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to sign feed download", err)
+		return
+	}
+
+	// The target changes with every run, so nothing may cache the redirect
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, r, download.URL, http.StatusFound)
+}
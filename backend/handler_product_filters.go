@@ -0,0 +1,352 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/catalog"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/service/products"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/google/uuid"
+)
+
+// maxFacetTags bounds the tag facet to the most common tags
+const maxFacetTags = 50
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type AvailabilityFacet struct {
+	InStock    int64 `json:"in_stock"`
+	OutOfStock int64 `json:"out_of_stock"`
+}
+
+type PriceRangeFacet struct {
+	MinCents int64 `json:"min_cents"`
+	MaxCents int64 `json:"max_cents"`
+}
+
+// ProductFacetsResponse holds counts for building filter UIs. Each facet is
+// counted with every filter applied except its own, so selecting one value
+// doesn't hide the alternatives.
+type ProductFacetsResponse struct {
+	Total        int64             `json:"total"`
+	Status       []FacetCount      `json:"status,omitempty"`
+	Tags         []FacetCount      `json:"tags"`
+	Availability AvailabilityFacet `json:"availability"`
+	// Price is nil when no matching product has an active variant
+	Price *PriceRangeFacet `json:"price"`
+}
+
+// parseProductFilter reads listing filters from the query string and
+// responds with 400 when they are malformed
+func parseProductFilter(w http.ResponseWriter, r *http.Request) (catalog.Filter, bool) {
+	filter, err := catalog.ParseFilter(r.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return catalog.Filter{}, false
+	}
+	return filter, true
+}
+
+// storefrontProductFilter parses filters for shopper-facing listings, which
+// only ever include active products published to the store's online store
+func (cfg *apiConfig) storefrontProductFilter(w http.ResponseWriter, r *http.Request, storeID uuid.UUID) (catalog.Filter, bool) {
+	filter, ok := parseProductFilter(w, r)
+	if !ok {
+		return catalog.Filter{}, false
+	}
+	channel, err := cfg.reads.GetOnlineStoreChannel(r.Context(), storeID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve online store channel", err)
+		return catalog.Filter{}, false
+	}
+	filter.Statuses = []string{products.StatusActive}
+	filter.ChannelID = &channel.ID
+	return filter, true
+}
+
+// storefrontProduct loads a product shoppers can see: active and published
+// to the store's online store. Any other product is sql.ErrNoRows.
+func (cfg *apiConfig) storefrontProduct(ctx context.Context, storeID, productID uuid.UUID) (database.Product, error) {
+	product, err := cfg.db.GetProductByID(ctx, database.GetProductByIDParams{
+		ID:      productID,
+		StoreID: storeID,
+	})
+	if err != nil {
+		return database.Product{}, err
+	}
+	if product.Status != products.StatusActive {
+		return database.Product{}, sql.ErrNoRows
+	}
+	published, err := cfg.db.IsProductOnOnlineStore(ctx, product.ID)
+	if err != nil {
+		return database.Product{}, err
+	}
+	if !published {
+		return database.Product{}, sql.ErrNoRows
+	}
+	return product, nil
+}
+
+// productFilterParams maps a filter onto the shared parameters of the facet
+// queries, which all take the same filter columns
+func productFilterParams(storeID uuid.UUID, f catalog.Filter) database.CountFilteredProductsByStatusParams {
+	params := database.CountFilteredProductsByStatusParams{
+		StoreID:  storeID,
+		Statuses: f.Statuses,
+		Tags:     f.Tags,
+	}
+	if f.MinPriceCents != nil {
+		params.MinPriceCents = sql.NullInt64{Int64: *f.MinPriceCents, Valid: true}
+	}
+	if f.MaxPriceCents != nil {
+		params.MaxPriceCents = sql.NullInt64{Int64: *f.MaxPriceCents, Valid: true}
+	}
+	if f.InStock != nil {
+		params.InStock = sql.NullBool{Bool: *f.InStock, Valid: true}
+	}
+	if f.CreatedAfter != nil {
+		params.CreatedAfter = sql.NullTime{Time: *f.CreatedAfter, Valid: true}
+	}
+	if f.CreatedBefore != nil {
+		params.CreatedBefore = sql.NullTime{Time: *f.CreatedBefore, Valid: true}
+	}
+	if f.ChannelID != nil {
+		params.ChannelID = uuid.NullUUID{UUID: *f.ChannelID, Valid: true}
+	}
+	return params
+}
+
+// listFilteredProducts returns one page of filtered products, newest first
+func (cfg *apiConfig) listFilteredProducts(w http.ResponseWriter, r *http.Request, storeID uuid.UUID, filter catalog.Filter, pageParams PageParams) ([]database.Product, PageInfo, bool) {
+	cursorCreatedAt, cursorID, hasCursor, err := cursorInfo(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return nil, PageInfo{}, false
+	}
+
+	base := productFilterParams(storeID, filter)
+	rows, err := cfg.reads.ListFilteredProducts(r.Context(), database.ListFilteredProductsParams{
+		StoreID:         base.StoreID,
+		Statuses:        base.Statuses,
+		Tags:            base.Tags,
+		MinPriceCents:   base.MinPriceCents,
+		MaxPriceCents:   base.MaxPriceCents,
+		InStock:         base.InStock,
+		CreatedAfter:    base.CreatedAfter,
+		CreatedBefore:   base.CreatedBefore,
+		ChannelID:       base.ChannelID,
+		HasCursor:       hasCursor,
+		CursorCreatedAt: cursorCreatedAt,
+		CursorID:        cursorID,
+		RowLimit:        int32(pageParams.Limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve products", err)
+		return nil, PageInfo{}, false
+	}
+
+	hasMore := len(rows) > pageParams.Limit
+	if hasMore {
+		rows = rows[:pageParams.Limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = productCursorCodec.Encode(ProductCursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return nil, PageInfo{}, false
+		}
+	}
+
+	return rows, PageInfo{Limit: pageParams.Limit, NextCursor: nextCursor, HasMore: hasMore}, true
+}
+
+// respondWithProductFacets counts products per facet value. withStatus adds
+// the status facet, which storefronts don't get since they only see active products.
+func (cfg *apiConfig) respondWithProductFacets(w http.ResponseWriter, r *http.Request, storeID uuid.UUID, filter catalog.Filter, withStatus bool) {
+	reqID := middleware.GetRequestID(r.Context())
+	start := time.Now()
+	base := productFilterParams(storeID, filter)
+	resp := ProductFacetsResponse{Tags: []FacetCount{}}
+
+	// Status counts double as the total: with the status filter applied
+	// (storefront) or summed over the selected statuses (tenant)
+	statusParams := base
+	if withStatus {
+		statusParams.Statuses = nil
+	}
+	statusRows, err := cfg.reads.CountFilteredProductsByStatus(r.Context(), statusParams)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to count products", err)
+		return
+	}
+	for _, row := range statusRows {
+		if len(filter.Statuses) == 0 || slices.Contains(filter.Statuses, row.Status) {
+			resp.Total += row.Count
+		}
+		if withStatus {
+			resp.Status = append(resp.Status, FacetCount{Value: row.Status, Count: row.Count})
+		}
+	}
+	if withStatus && resp.Status == nil {
+		resp.Status = []FacetCount{}
+	}
+
+	tagParams := base
+	tagParams.Tags = nil
+	tagRows, err := cfg.reads.CountFilteredProductsByTag(r.Context(), database.CountFilteredProductsByTagParams{
+		StoreID:       tagParams.StoreID,
+		Statuses:      tagParams.Statuses,
+		Tags:          tagParams.Tags,
+		MinPriceCents: tagParams.MinPriceCents,
+		MaxPriceCents: tagParams.MaxPriceCents,
+		InStock:       tagParams.InStock,
+		CreatedAfter:  tagParams.CreatedAfter,
+		CreatedBefore: tagParams.CreatedBefore,
+		ChannelID:     tagParams.ChannelID,
+		TagLimit:      maxFacetTags,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to count product tags", err)
+		return
+	}
+	for _, row := range tagRows {
+		resp.Tags = append(resp.Tags, FacetCount{Value: row.Tag, Count: row.Count})
+	}
+
+	stockParams := base
+	stockParams.InStock = sql.NullBool{}
+	stock, err := cfg.reads.CountFilteredProductsByAvailability(r.Context(), database.CountFilteredProductsByAvailabilityParams(stockParams))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to count product availability", err)
+		return
+	}
+	resp.Availability = AvailabilityFacet{InStock: stock.InStock, OutOfStock: stock.OutOfStock}
+
+	priceParams := base
+	priceParams.MinPriceCents = sql.NullInt64{}
+	priceParams.MaxPriceCents = sql.NullInt64{}
+	price, err := cfg.reads.GetFilteredProductsPriceRange(r.Context(), database.GetFilteredProductsPriceRangeParams(priceParams))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusInternalServerError, "Unable to compute product price range", err)
+		return
+	}
+	if price.VariantCount > 0 {
+		resp.Price = &PriceRangeFacet{MinCents: price.MinPriceCents, MaxCents: price.MaxPriceCents}
+	}
+
+	slog.InfoContext(r.Context(), "product facets computed",
+		"request_id", reqID,
+		"store_id", storeID,
+		"product_count", resp.Total,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerTenantProductFacets returns facet counts for the tenant product list
+func (cfg *apiConfig) handlerTenantProductFacets(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	filter, ok := parseProductFilter(w, r)
+	if !ok {
+		return
+	}
+
+	cfg.respondWithProductFacets(w, r, storeID, filter, true)
+}
+
+// handlerStorefrontProductsList lists the resolved store's active products,
+// newest first, narrowed by the listing filters
+func (cfg *apiConfig) handlerStorefrontProductsList(w http.ResponseWriter, r *http.Request) {
+	store, _ := middleware.GetResolvedStore(r.Context())
+
+	filter, ok := cfg.storefrontProductFilter(w, r, store.ID)
+	if !ok {
+		return
+	}
+
+	currency, ok := cfg.storefrontCurrency(w, r, store)
+	if !ok {
+		return
+	}
+	loc, ok := cfg.storefrontLocale(w, r, store)
+	if !ok {
+		return
+	}
+
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+
+	rows, page, ok := cfg.listFilteredProducts(w, r, store.ID, filter, pageParams)
+	if !ok {
+		return
+	}
+
+	products := storefrontProductsToResponse(rows)
+	if err := cfg.localizeStorefrontProducts(r.Context(), store, loc, products); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product translations", err)
+		return
+	}
+	if err := cfg.priceStorefrontProducts(r.Context(), store, currency, products); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product prices", err)
+		return
+	}
+	if err := cfg.rateStorefrontProducts(r.Context(), products); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product ratings", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, PageResponse[StorefrontProductResponse]{Data: products, Page: page})
+}
+
+// handlerStorefrontProductFacets returns facet counts over the resolved
+// store's active products
+func (cfg *apiConfig) handlerStorefrontProductFacets(w http.ResponseWriter, r *http.Request) {
+	store, _ := middleware.GetResolvedStore(r.Context())
+
+	filter, ok := cfg.storefrontProductFilter(w, r, store.ID)
+	if !ok {
+		return
+	}
+
+	cfg.respondWithProductFacets(w, r, store.ID, filter, false)
+}
+
+func storefrontProductsToResponse(rows []database.Product) []StorefrontProductResponse {
+	products := make([]StorefrontProductResponse, 0, len(rows))
+	for _, product := range rows {
+		item := StorefrontProductResponse{
+			ID:     product.ID,
+			Handle: product.Handle,
+			Name:   product.Name,
+		}
+		if product.Description.Valid {
+			item.Description = &product.Description.String
+		}
+		if product.Tags.Valid {
+			item.Tags = &product.Tags.String
+		}
+		products = append(products, item)
+	}
+	return products
+}
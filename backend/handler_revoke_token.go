@@ -1,16 +1,33 @@
 package main
 
 import (
-	"log"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
 	"net/http"
 	"time"
 
 	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/middleware"
 )
 
+// errRefreshTokenInvalid is a refresh token that is unknown, revoked or
+// expired
+var errRefreshTokenInvalid = errors.New("refresh token invalid")
+
+// handlerRefresh exchanges a refresh token for a new access token and a new
+// refresh token. The old refresh token stops working; if it is presented
+// again someone else has a copy, so the whole session is revoked.
 func (cfg *apiConfig) handlerRefresh(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
 	type response struct {
-		Token string `json:"token"`
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
 	}
 
 	refreshToken, err := auth.GetBearerToken(r.Header)
@@ -19,27 +36,87 @@ func (cfg *apiConfig) handlerRefresh(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := cfg.db.GetUserFromRefreshToken(r.Context(), refreshToken)
+	var (
+		session                      database.Session
+		accessToken, newRefreshToken string
+		// reused is set when the token was already rotated away, so the
+		// session is revoked instead
+		reused bool
+	)
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		token, err := q.GetRefreshTokenForUpdate(r.Context(), auth.HashToken(refreshToken))
+		if errors.Is(err, sql.ErrNoRows) {
+			return errRefreshTokenInvalid
+		}
+		if err != nil {
+			return err
+		}
+
+		session, err = q.GetSessionForUpdate(r.Context(), token.SessionID)
+		if err != nil {
+			return err
+		}
+
+		if token.RotatedAt.Valid {
+			reused = true
+			return revokeSession(r.Context(), q, session)
+		}
+
+		now := time.Now()
+		if token.RevokedAt.Valid || !token.ExpiresAt.After(now) || session.RevokedAt.Valid || !session.ExpiresAt.After(now) {
+			return errRefreshTokenInvalid
+		}
+
+		if err := q.RotateRefreshToken(r.Context(), token.TokenHash); err != nil {
+			return err
+		}
+
+		meta := sessionMetaFromRequest(r)
+		expiresAt := now.Add(sessionTTL)
+		err = q.TouchSession(r.Context(), database.TouchSessionParams{
+			ID:        session.ID,
+			UserAgent: meta.UserAgent,
+			IpAddress: meta.IPAddress,
+			ExpiresAt: expiresAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		newRefreshToken, err = createRefreshToken(r.Context(), q, token.UserID, session.ID, expiresAt)
+		if err != nil {
+			return err
+		}
+
+		accessToken, err = cfg.makeAccessToken(r.Context(), q, token.UserID)
+		return err
+	})
+	if errors.Is(err, errRefreshTokenInvalid) {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't get user for refresh token", nil)
+		return
+	}
 	if err != nil {
-		respondWithError(w, http.StatusUnauthorized, "Couldn't get user for refresh token", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't refresh session", err)
 		return
 	}
 
-	accessToken, err := auth.MakeJWT(
-		user.ID,
-		cfg.signingKey,
-		time.Hour,
-	)
-	if err != nil {
-		respondWithError(w, http.StatusUnauthorized, "Couldn't validate token", err)
+	if reused {
+		slog.WarnContext(r.Context(), "refresh token reuse detected, session revoked",
+			"request_id", reqID,
+			"user_id", session.UserID,
+			"session_id", session.ID,
+		)
+		respondWithError(w, http.StatusUnauthorized, "Session has been revoked, please log in again", nil)
 		return
 	}
 
 	respondWithJSON(w, http.StatusOK, response{
-		Token: accessToken,
+		Token:        accessToken,
+		RefreshToken: newRefreshToken,
 	})
 }
 
+// handlerRevoke ends the session the presented refresh token belongs to
 func (cfg *apiConfig) handlerRevoke(w http.ResponseWriter, r *http.Request) {
 	refreshToken, err := auth.GetBearerToken(r.Header)
 	if err != nil {
@@ -47,13 +124,71 @@ func (cfg *apiConfig) handlerRevoke(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = cfg.db.RevokeRefreshToken(r.Context(), refreshToken)
-	log.Printf("err: %d", err)
-	log.Printf("token : %s", refreshToken)
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		return endRefreshTokenSession(r.Context(), q, refreshToken)
+	})
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusUnauthorized, "Couldn't find session", nil)
+			return
+		}
 		respondWithError(w, http.StatusInternalServerError, "Couldn't revoke session", err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// handlerLogout signs out the session of the refresh token in the body, or
+// in the Authorization header. Signing out twice, or with a token that no
+// longer exists, still succeeds, so clients can always clear their state.
+func (cfg *apiConfig) handlerLogout(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	type parameters struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	// The body is optional when the token is in the header
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil && !errors.Is(err, io.EOF) {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+	refreshToken := params.RefreshToken
+	if refreshToken == "" {
+		token, err := auth.GetBearerToken(r.Header)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Refresh token is required", err)
+			return
+		}
+		refreshToken = token
+	}
+
+	err := cfg.withTx(r.Context(), func(q *database.Queries) error {
+		return endRefreshTokenSession(r.Context(), q, refreshToken)
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't sign out", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "logged out", "request_id", reqID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// endRefreshTokenSession revokes the session a refresh token belongs to,
+// with every refresh token in it. It returns sql.ErrNoRows for an unknown
+// token.
+func endRefreshTokenSession(ctx context.Context, q *database.Queries, refreshToken string) error {
+	token, err := q.GetRefreshTokenForUpdate(ctx, auth.HashToken(refreshToken))
+	if err != nil {
+		return err
+	}
+	session, err := q.GetSessionForUpdate(ctx, token.SessionID)
+	if err != nil {
+		return err
+	}
+	return revokeSession(ctx, q, session)
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	// sessionTTL is how long a session lasts without being used. Each
+	// refresh extends it.
+	sessionTTL = 60 * 24 * time.Hour
+
+	maxUserAgentLength = 512
+)
+
+type SessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// sessionMeta describes the device a session is used from
+type sessionMeta struct {
+	UserAgent string
+	IPAddress string
+}
+
+func sessionMetaFromRequest(r *http.Request) sessionMeta {
+	ua := r.UserAgent()
+	if len(ua) > maxUserAgentLength {
+		ua = ua[:maxUserAgentLength]
+	}
+	return sessionMeta{
+		UserAgent: ua,
+		IPAddress: middleware.ClientIP(r),
+	}
+}
+
+// createRefreshToken adds a new refresh token to a session. Only its hash
+// is stored.
+func createRefreshToken(ctx context.Context, q *database.Queries, userID, sessionID uuid.UUID, expiresAt time.Time) (string, error) {
+	token, err := auth.MakeRefreshToken()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = q.CreateRefreshToken(ctx, database.CreateRefreshTokenParams{
+		TokenHash: auth.HashToken(token),
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// revokeSession ends a session and every refresh token in it
+func revokeSession(ctx context.Context, q *database.Queries, session database.Session) error {
+	if _, err := q.RevokeUserSession(ctx, database.RevokeUserSessionParams{
+		ID:     session.ID,
+		UserID: session.UserID,
+	}); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	return q.RevokeSessionRefreshTokens(ctx, session.ID)
+}
+
+// revokeAllUserSessions signs a user out everywhere. Bumping the token
+// version also ends their access tokens when JWT_TOKEN_VERSION is on.
+func revokeAllUserSessions(ctx context.Context, q *database.Queries, userID uuid.UUID) error {
+	if err := q.RevokeAllUserSessions(ctx, userID); err != nil {
+		return err
+	}
+	if err := q.RevokeAllUserRefreshTokens(ctx, userID); err != nil {
+		return err
+	}
+	return q.BumpUserTokenVersion(ctx, userID)
+}
+
+// handlerSessionsList lists the signed-in user's active sessions, most
+// recently used first
+func (cfg *apiConfig) handlerSessionsList(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	sessions, err := cfg.db.ListActiveUserSessions(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve sessions", err)
+		return
+	}
+
+	response := make([]SessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		response = append(response, sessionToResponse(s))
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{"data": response})
+}
+
+// handlerSessionRevoke signs one of the user's sessions out. Access tokens
+// already issued to it stay valid until they expire.
+func (cfg *apiConfig) handlerSessionRevoke(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	userID, ok := userFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	sessionID, err := uuid.Parse(chi.URLParam(r, "sessionID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid session ID format", err)
+		return
+	}
+
+	var session database.Session
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		var err error
+		session, err = q.RevokeUserSession(r.Context(), database.RevokeUserSessionParams{
+			ID:     sessionID,
+			UserID: userID,
+		})
+		if err != nil {
+			return service.NotFoundAs(err, "Session not found")
+		}
+		return q.RevokeSessionRefreshTokens(r.Context(), session.ID)
+	})
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to revoke session")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "session revoked",
+		"request_id", reqID,
+		"user_id", userID,
+		"session_id", session.ID,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerSessionsRevokeAll signs the user out of every session, including
+// the one making the request. It serves DELETE /me/sessions and POST
+// /me/revoke-all.
+func (cfg *apiConfig) handlerSessionsRevokeAll(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	userID, ok := userFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	err := cfg.withTx(r.Context(), func(q *database.Queries) error {
+		return revokeAllUserSessions(r.Context(), q, userID)
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to revoke sessions", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "all sessions revoked",
+		"request_id", reqID,
+		"user_id", userID,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func sessionToResponse(s database.Session) SessionResponse {
+	return SessionResponse{
+		ID:         s.ID,
+		UserAgent:  s.UserAgent,
+		IPAddress:  s.IpAddress,
+		CreatedAt:  s.CreatedAt,
+		LastUsedAt: s.LastUsedAt,
+		ExpiresAt:  s.ExpiresAt,
+	}
+}
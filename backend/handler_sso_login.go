@@ -0,0 +1,500 @@
+package main
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/oauth"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/saml"
+	"github.com/dfodeker/terminus/internal/service/roles"
+	"github.com/dfodeker/terminus/internal/sso"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+var (
+	// errSSODomain turns away an identity provider vouching for an email
+	// outside the tenant's verified domains
+	errSSODomain = errors.New("email is not in a verified domain")
+	// errSSONotProvisioned turns away someone new to the tenant when it
+	// doesn't provision members on first sign-in
+	errSSONotProvisioned = errors.New("not provisioned")
+	// errSSORemoved turns away a member the tenant removed
+	errSSORemoved = errors.New("membership removed")
+	// errSSOStateUsed is a SAML response posted for a sign-in that has
+	// already had one
+	errSSOStateUsed = errors.New("sign-in already completed")
+)
+
+// samlServiceProvider is this API as a service provider to the
+// connection's identity provider. The entity ID is the URL the metadata is
+// served at.
+func (cfg *apiConfig) samlServiceProvider(c database.SsoConnection, idp saml.IdP) *saml.SP {
+	base := cfg.config.APIURL + "/sso/saml/" + c.ID.String()
+	return &saml.SP{
+		EntityID: base + "/metadata",
+		ACSURL:   base + "/acs",
+		IdP:      idp,
+	}
+}
+
+// ssoProvider returns what a connection signs users in through: a SAML
+// service provider or an OpenID Connect one
+func (cfg *apiConfig) ssoProvider(c database.SsoConnection) (*saml.SP, oauth.Provider, error) {
+	if c.Protocol == sso.ProtocolSAML {
+		idp, err := saml.ParseMetadata([]byte(c.SamlMetadata.String))
+		if err != nil {
+			return nil, nil, err
+		}
+		return cfg.samlServiceProvider(c, idp), nil, nil
+	}
+	return nil, oauth.NewOIDC(oauth.OIDCConfig{
+		Name:   sso.ProviderPrefix + c.ID.String(),
+		Issuer: c.OidcIssuer.String,
+		ClientConfig: oauth.ClientConfig{
+			ClientID:     c.OidcClientID.String,
+			ClientSecret: c.OidcClientSecret.String,
+		},
+	}, cfg.config.OAuth.RedirectURL), nil
+}
+
+// handlerSSOStart begins a sign-in through the identity provider of the
+// tenant that verified the email's domain. Like /login/oauth/{provider} it
+// returns the URL to send the user to and a state, and the sign-in is
+// finished at /login/oauth/callback.
+func (cfg *apiConfig) handlerSSOStart(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Email string `json:"email"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil || params.Email == "" {
+		respondWithError(w, http.StatusBadRequest, "email is required", err)
+		return
+	}
+
+	conn, err := cfg.db.GetSSOConnectionByDomain(r.Context(), sso.EmailDomain(strings.TrimSpace(params.Email)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Single sign-on is not set up for this email's domain", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to start sign-in", err)
+		return
+	}
+	sp, provider, err := cfg.ssoProvider(conn)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to start sign-in", err)
+		return
+	}
+
+	req, err := oauth.NewAuthRequest()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to start sign-in", err)
+		return
+	}
+	var authURL string
+	if sp != nil {
+		// The response has to answer the request's ID, which is kept where
+		// OpenID Connect keeps its nonce; there is no PKCE verifier
+		authURL, req.Nonce, err = sp.AuthnRequest(req.State)
+		req.Verifier = ""
+	} else {
+		authURL, err = provider.AuthURL(r.Context(), req)
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadGateway, "Unable to reach the sign-in provider", err)
+		return
+	}
+
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		if err := q.DeleteExpiredOAuthStates(r.Context()); err != nil {
+			return err
+		}
+		return q.CreateOAuthState(r.Context(), database.CreateOAuthStateParams{
+			StateHash:    auth.HashToken(req.State),
+			Provider:     sso.ProviderPrefix + conn.ID.String(),
+			Nonce:        req.Nonce,
+			CodeVerifier: req.Verifier,
+			ExpiresAt:    time.Now().Add(oauthStateTTL),
+		})
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to start sign-in", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{
+		"authorization_url": authURL,
+		"state":             req.State,
+	})
+}
+
+// handlerSAMLMetadata serves the service provider metadata a connection's
+// identity provider is set up with
+func (cfg *apiConfig) handlerSAMLMetadata(w http.ResponseWriter, r *http.Request) {
+	conn, ok := cfg.samlConnectionFromRequest(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/samlmetadata+xml")
+	w.WriteHeader(http.StatusOK)
+	w.Write(cfg.samlServiceProvider(conn, saml.IdP{}).Metadata())
+}
+
+// handlerSAMLACS is the assertion consumer service the identity provider
+// posts its response to, through the user's browser. The user is signed in
+// as far as the API is concerned, and sent on to OAUTH_REDIRECT_URL with a
+// one-time code and the state, for the app to finish the sign-in with at
+// /login/oauth/callback like any other.
+func (cfg *apiConfig) handlerSAMLACS(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	conn, ok := cfg.samlConnectionFromRequest(w, r)
+	if !ok {
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, saml.MaxPostBytes)
+	if err := r.ParseForm(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Unable to read the SAML response", err)
+		return
+	}
+	encoded, relayState := r.PostForm.Get("SAMLResponse"), r.PostForm.Get("RelayState")
+	if encoded == "" || relayState == "" {
+		respondWithError(w, http.StatusBadRequest, "SAMLResponse and RelayState are required", nil)
+		return
+	}
+
+	stateHash := auth.HashToken(relayState)
+	state, err := cfg.db.GetPendingOAuthState(r.Context(), stateHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusUnauthorized, "Sign-in has expired, please try again", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to sign in", err)
+		return
+	}
+	if state.Provider != sso.ProviderPrefix+conn.ID.String() {
+		respondWithError(w, http.StatusUnauthorized, "Sign-in was started for another provider", nil)
+		return
+	}
+
+	sp, _, err := cfg.ssoProvider(conn)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to sign in", err)
+		return
+	}
+	assertion, err := sp.ParseResponse(encoded, state.Nonce)
+	if err != nil {
+		if errors.Is(err, saml.ErrInvalid) {
+			respondWithError(w, http.StatusUnauthorized, "The sign-in provider's response was not accepted", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to sign in", err)
+		return
+	}
+
+	code, err := auth.MakeOneTimeToken()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to sign in", err)
+		return
+	}
+	var user database.User
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		user, err = cfg.provisionSSOUser(r, q, conn, sso.FromSAML(assertion, ssoMapping(conn)))
+		if err != nil {
+			return err
+		}
+		n, err := q.CompleteOAuthState(r.Context(), database.CompleteOAuthStateParams{
+			StateHash: stateHash,
+			UserID:    uuid.NullUUID{UUID: user.ID, Valid: true},
+			CodeHash:  sql.NullString{String: auth.HashToken(code), Valid: true},
+		})
+		if err == nil && n == 0 {
+			return errSSOStateUsed
+		}
+		return err
+	})
+	if err != nil {
+		respondWithSSOError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "saml response accepted",
+		"request_id", reqID,
+		"user_id", user.ID,
+		"tenant_id", conn.TenantID,
+		"connection_id", conn.ID,
+	)
+
+	q := url.Values{"code": {code}, "state": {relayState}}
+	http.Redirect(w, r, cfg.config.OAuth.RedirectURL+"?"+q.Encode(), http.StatusSeeOther)
+}
+
+// samlConnectionFromRequest loads the enabled SAML connection named by
+// {connectionID}
+func (cfg *apiConfig) samlConnectionFromRequest(w http.ResponseWriter, r *http.Request) (database.SsoConnection, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "connectionID"))
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "SSO connection not found", nil)
+		return database.SsoConnection{}, false
+	}
+	conn, err := cfg.db.GetSSOConnectionByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "SSO connection not found", nil)
+			return database.SsoConnection{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve SSO connection", err)
+		return database.SsoConnection{}, false
+	}
+	if conn.Protocol != sso.ProtocolSAML || !conn.Enabled {
+		respondWithError(w, http.StatusNotFound, "SSO connection not found", nil)
+		return database.SsoConnection{}, false
+	}
+	return conn, true
+}
+
+// finishSSOCallback finishes a sign-in through a tenant's identity
+// provider at /login/oauth/callback. An OpenID Connect sign-in exchanges
+// the code here; a SAML one was checked at the assertion consumer service,
+// which handed out the code.
+func (cfg *apiConfig) finishSSOCallback(w http.ResponseWriter, r *http.Request, state database.OauthState, stateToken, code string) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	id, err := uuid.Parse(strings.TrimPrefix(state.Provider, sso.ProviderPrefix))
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Sign-in provider is no longer available", nil)
+		return
+	}
+	conn, err := cfg.db.GetSSOConnectionByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusUnauthorized, "Sign-in provider is no longer available", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to sign in", err)
+		return
+	}
+	if !conn.Enabled {
+		respondWithError(w, http.StatusUnauthorized, "Sign-in provider is no longer available", nil)
+		return
+	}
+
+	var user database.User
+	switch conn.Protocol {
+	case sso.ProtocolSAML:
+		if !state.UserID.Valid || !state.CodeHash.Valid ||
+			subtle.ConstantTimeCompare([]byte(auth.HashToken(code)), []byte(state.CodeHash.String)) != 1 {
+			respondWithError(w, http.StatusUnauthorized, "The sign-in code is not valid", nil)
+			return
+		}
+		user, err = cfg.db.GetUserByID(r.Context(), state.UserID.UUID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to sign in", err)
+			return
+		}
+	default:
+		_, provider, _ := cfg.ssoProvider(conn)
+		identity, err := provider.Exchange(r.Context(), code, oauth.AuthRequest{
+			State:    stateToken,
+			Nonce:    state.Nonce,
+			Verifier: state.CodeVerifier,
+		})
+		if err != nil {
+			if errors.Is(err, oauth.ErrRejected) {
+				respondWithError(w, http.StatusUnauthorized, "The sign-in provider rejected the sign-in", err)
+				return
+			}
+			respondWithError(w, http.StatusBadGateway, "Unable to reach the sign-in provider", err)
+			return
+		}
+		err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+			user, err = cfg.provisionSSOUser(r, q, conn, sso.FromClaims(identity.Subject, identity.Claims, ssoMapping(conn)))
+			return err
+		})
+		if err != nil {
+			respondWithSSOError(w, err)
+			return
+		}
+	}
+
+	slog.InfoContext(r.Context(), "sso login",
+		"request_id", reqID,
+		"user_id", user.ID,
+		"tenant_id", conn.TenantID,
+		"connection_id", conn.ID,
+	)
+
+	cfg.finishLogin(w, r, user)
+}
+
+func ssoMapping(c database.SsoConnection) sso.Mapping {
+	var m sso.Mapping
+	json.Unmarshal(c.AttributeMapping, &m)
+	return m
+}
+
+// provisionSSOUser returns the user a tenant's identity provider signed
+// in, making sure they are an active member of the tenant. The provider is
+// only trusted for emails in the tenant's verified domains, so a tenant
+// can't sign in as anyone else's users. Someone new is given an account
+// and a membership, with the role their attributes name or the default
+// role, when the connection provisions on first sign-in.
+func (cfg *apiConfig) provisionSSOUser(r *http.Request, q *database.Queries, conn database.SsoConnection, p sso.Profile) (database.User, error) {
+	ctx := r.Context()
+	if p.Subject == "" || p.Email == "" {
+		return database.User{}, errSSODomain
+	}
+	verified, err := q.IsSSODomainVerified(ctx, database.IsSSODomainVerifiedParams{
+		TenantID: conn.TenantID,
+		Domain:   sso.EmailDomain(p.Email),
+	})
+	if err != nil {
+		return database.User{}, err
+	}
+	if !verified {
+		return database.User{}, errSSODomain
+	}
+
+	provider := sso.ProviderPrefix + conn.ID.String()
+	var user database.User
+	identity, err := q.GetUserIdentity(ctx, database.GetUserIdentityParams{Provider: provider, Subject: p.Subject})
+	switch {
+	case err == nil:
+		if err := q.TouchUserIdentity(ctx, identity.ID); err != nil {
+			return database.User{}, err
+		}
+		if user, err = q.GetUserByID(ctx, identity.UserID); err != nil {
+			return database.User{}, err
+		}
+	case errors.Is(err, sql.ErrNoRows):
+		user, err = q.GetUserByEmail(ctx, p.Email)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			if !conn.JitProvisioning {
+				return database.User{}, errSSONotProvisioned
+			}
+			user, err = q.CreateSSOUser(ctx, database.CreateSSOUserParams{
+				Gid:   sql.NullInt64{Int64: int64(cfg.gidGen.Generate()), Valid: true},
+				Email: p.Email,
+			})
+			if err != nil {
+				return database.User{}, err
+			}
+		case err != nil:
+			return database.User{}, err
+		case !user.VerifiedAt.Valid:
+			// Whoever signed up with the address unverified may know the
+			// account's password
+			return database.User{}, errIdentityUnverified
+		}
+		if _, err := q.CreateUserIdentity(ctx, database.CreateUserIdentityParams{
+			UserID:   user.ID,
+			Provider: provider,
+			Subject:  p.Subject,
+			Email:    p.Email,
+		}); err != nil {
+			return database.User{}, err
+		}
+	default:
+		return database.User{}, err
+	}
+
+	member, err := q.GetTenantUser(ctx, database.GetTenantUserParams{TenantID: conn.TenantID, UserID: user.ID})
+	switch {
+	case err == nil && member.Status == "active":
+		return user, nil
+	case err == nil && member.Status == "removed":
+		return database.User{}, errSSORemoved
+	case err != nil && !errors.Is(err, sql.ErrNoRows):
+		return database.User{}, err
+	}
+	if !conn.JitProvisioning {
+		return database.User{}, errSSONotProvisioned
+	}
+
+	if err := cfg.services.entitlements.CheckSeats(ctx, conn.TenantID, user.Email); err != nil {
+		return database.User{}, err
+	}
+	member, err = q.UpsertTenantUser(ctx, database.UpsertTenantUserParams{
+		TenantID: conn.TenantID,
+		UserID:   user.ID,
+		Status:   "active",
+	})
+	if err != nil {
+		return database.User{}, err
+	}
+	roleID, err := ssoRole(r, q, conn, p.Roles)
+	if err != nil {
+		return database.User{}, err
+	}
+	if roleID.Valid {
+		err = q.AssignRoleToTenantUser(ctx, database.AssignRoleToTenantUserParams{
+			TenantUserID: member.ID,
+			RoleID:       roleID.UUID,
+		})
+		if err != nil {
+			return database.User{}, err
+		}
+	}
+	slog.InfoContext(ctx, "sso member provisioned",
+		"request_id", middleware.GetRequestID(ctx),
+		"user_id", user.ID,
+		"tenant_id", conn.TenantID,
+		"role_id", roleID.UUID,
+	)
+	return user, nil
+}
+
+// ssoRole picks the role a new member is given: the first of the tenant's
+// roles named in their role attribute, or else the connection's default.
+// The owner role is never given.
+func ssoRole(r *http.Request, q *database.Queries, conn database.SsoConnection, names []string) (uuid.NullUUID, error) {
+	if len(names) > 0 {
+		tenantRoles, err := q.GetRolesByTenantID(r.Context(), conn.TenantID)
+		if err != nil {
+			return uuid.NullUUID{}, err
+		}
+		for _, name := range names {
+			for _, role := range tenantRoles {
+				if strings.EqualFold(role.Name, strings.TrimSpace(name)) && role.Name != roles.OwnerTemplate.Name {
+					return uuid.NullUUID{UUID: role.ID, Valid: true}, nil
+				}
+			}
+		}
+	}
+	return conn.DefaultRoleID, nil
+}
+
+// respondWithSSOError answers a sign-in the tenant's identity provider
+// vouched for but the tenant doesn't let in
+func respondWithSSOError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errSSODomain):
+		respondWithError(w, http.StatusForbidden, "The sign-in provider did not give an email in a domain the tenant has verified", nil)
+	case errors.Is(err, errIdentityUnverified):
+		respondWithErrorCode(w, http.StatusForbidden, problem.CodeEmailNotVerified,
+			"An unverified account already uses this email; verify it before signing in through single sign-on", nil)
+	case errors.Is(err, errSSONotProvisioned):
+		respondWithErrorCode(w, http.StatusForbidden, problem.CodeNotTenantMember, "Ask your administrator to add you to the tenant before signing in", nil)
+	case errors.Is(err, errSSORemoved):
+		respondWithErrorCode(w, http.StatusForbidden, problem.CodeMembershipInactive, "You have been removed from the tenant", nil)
+	case errors.Is(err, errSSOStateUsed):
+		respondWithError(w, http.StatusUnauthorized, "Sign-in has expired, please try again", nil)
+	default:
+		respondWithServiceError(w, err, "Unable to sign in")
+	}
+}
@@ -10,6 +10,8 @@ import (
 
 	"github.com/dfodeker/terminus/internal/database"
 	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service/products"
 	"github.com/dfodeker/terminus/middleware"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -43,7 +45,7 @@ func (cfg *apiConfig) getStoreAndVerifyAccess(r *http.Request, storeHandle strin
 	}
 
 	// Check user has permission in this tenant
-	hasPermission, err := cfg.db.CheckUserHasPermission(r.Context(), database.CheckUserHasPermissionParams{
+	hasPermission, err := cfg.checkPermission(r.Context(), database.CheckUserHasPermissionParams{
 		TenantID: store.TenantID.UUID,
 		UserID:   userID,
 		Key:      permissionKey,
@@ -87,7 +89,7 @@ func (cfg *apiConfig) handlerStoreProductCreate(w http.ResponseWriter, r *http.R
 				"user_id", user,
 				"store_handle", storeHandle,
 			)
-			respondWithError(w, http.StatusForbidden, "You do not have permission to create products in this store", nil)
+			respondWithErrorCode(w, http.StatusForbidden, problem.CodePermissionDenied, "You do not have permission to create products in this store", nil)
 			return
 		}
 		slog.ErrorContext(r.Context(), "product creation failed: error verifying access",
@@ -126,7 +128,11 @@ func (cfg *apiConfig) handlerStoreProductCreate(w http.ResponseWriter, r *http.R
 
 	status := params.Status
 	if status == "" {
-		status = "active"
+		status = products.DefaultStatus
+	}
+	if status != products.StatusActive && status != products.StatusDraft {
+		respondWithError(w, http.StatusBadRequest, "Status must be 'active' or 'draft'", nil)
+		return
 	}
 
 	description := sql.NullString{}
@@ -144,6 +150,11 @@ func (cfg *apiConfig) handlerStoreProductCreate(w http.ResponseWriter, r *http.R
 		tags = sql.NullString{String: *params.Tags, Valid: true}
 	}
 
+	if err := cfg.services.entitlements.CheckProducts(r.Context(), store.ID); err != nil {
+		respondWithServiceError(w, err, "Unable to create product")
+		return
+	}
+
 	product, err := cfg.db.CreateProduct(r.Context(), database.CreateProductParams{
 		StoreID:          store.ID,
 		Handle:           params.Handle,
@@ -197,7 +208,7 @@ func (cfg *apiConfig) handlerStoreProductsList(w http.ResponseWriter, r *http.Re
 			return
 		}
 		if err.Error() == "permission denied" {
-			respondWithError(w, http.StatusForbidden, "You do not have permission to view products in this store", nil)
+			respondWithErrorCode(w, http.StatusForbidden, problem.CodePermissionDenied, "You do not have permission to view products in this store", nil)
 			return
 		}
 		respondWithError(w, http.StatusInternalServerError, "Unable to verify access", err)
@@ -219,12 +230,12 @@ func (cfg *apiConfig) handlerStoreProductsList(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	rows, err := cfg.db.GetProductsByStorePaginated(r.Context(), database.GetProductsByStorePaginatedParams{
-		StoreID: store.ID,
-		Column2: hasCursor,
-		Column3: cursorCreatedAt,
-		Column4: cursorID,
-		Limit:   limitPlusOne,
+	rows, err := cfg.reads.GetProductsByStorePaginated(r.Context(), database.GetProductsByStorePaginatedParams{
+		StoreID:         store.ID,
+		HasCursor:       hasCursor,
+		CursorCreatedAt: cursorCreatedAt,
+		CursorID:        cursorID,
+		RowLimit:        limitPlusOne,
 	})
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve products", err)
@@ -256,13 +267,9 @@ func (cfg *apiConfig) handlerStoreProductsList(w http.ResponseWriter, r *http.Re
 		"product_count", len(response),
 	)
 
-	respondWithJSON(w, http.StatusOK, map[string]any{
-		"data": response,
-		"page": map[string]any{
-			"limit":       limit,
-			"has_more":    hasMore,
-			"next_cursor": nextCursor,
-		},
+	respondWithJSON(w, http.StatusOK, PageResponse[ProductResponse]{
+		Data: response,
+		Page: PageInfo{Limit: limit, NextCursor: nextCursor, HasMore: hasMore},
 	})
 }
 
@@ -291,7 +298,7 @@ func (cfg *apiConfig) handlerStoreProductGet(w http.ResponseWriter, r *http.Requ
 			return
 		}
 		if err.Error() == "permission denied" {
-			respondWithError(w, http.StatusForbidden, "You do not have permission to view products in this store", nil)
+			respondWithErrorCode(w, http.StatusForbidden, problem.CodePermissionDenied, "You do not have permission to view products in this store", nil)
 			return
 		}
 		respondWithError(w, http.StatusInternalServerError, "Unable to verify access", err)
@@ -345,27 +352,13 @@ func (cfg *apiConfig) handlerStoreProductUpdate(w http.ResponseWriter, r *http.R
 			return
 		}
 		if err.Error() == "permission denied" {
-			respondWithError(w, http.StatusForbidden, "You do not have permission to edit products in this store", nil)
+			respondWithErrorCode(w, http.StatusForbidden, problem.CodePermissionDenied, "You do not have permission to edit products in this store", nil)
 			return
 		}
 		respondWithError(w, http.StatusInternalServerError, "Unable to verify access", err)
 		return
 	}
 
-	// Get existing product
-	existing, err := cfg.db.GetProductByID(r.Context(), database.GetProductByIDParams{
-		ID:      productID,
-		StoreID: store.ID,
-	})
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			respondWithError(w, http.StatusNotFound, "Product not found", nil)
-			return
-		}
-		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product", err)
-		return
-	}
-
 	type parameters struct {
 		Name             *string `json:"name"`
 		Handle           *string `json:"handle"`
@@ -383,59 +376,23 @@ func (cfg *apiConfig) handlerStoreProductUpdate(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	// Merge with existing values
-	name := existing.Name
-	if params.Name != nil {
-		name = *params.Name
-	}
-
-	handle := existing.Handle
-	if params.Handle != nil {
-		handle = *params.Handle
-	}
-
-	description := existing.Description
-	if params.Description != nil {
-		description = sql.NullString{String: *params.Description, Valid: true}
-	}
-
-	inventoryTracked := existing.InventoryTracked
-	if params.InventoryTracked != nil {
-		inventoryTracked = *params.InventoryTracked
-	}
-
-	sku := existing.Sku
-	if params.SKU != nil {
-		sku = sql.NullString{String: *params.SKU, Valid: true}
-	}
-
-	tags := existing.Tags
-	if params.Tags != nil {
-		tags = sql.NullString{String: *params.Tags, Valid: true}
-	}
-
-	status := existing.Status
-	if params.Status != nil {
-		status = *params.Status
-	}
-
-	product, err := cfg.db.UpdateProduct(r.Context(), database.UpdateProductParams{
-		ID:               productID,
+	product, err := cfg.services.products.Update(r.Context(), products.UpdateInput{
 		StoreID:          store.ID,
-		Handle:           handle,
-		Name:             name,
-		Description:      description,
-		InventoryTracked: inventoryTracked,
-		Sku:              sku,
-		Tags:             tags,
-		Status:           status,
+		ID:               productID,
+		Name:             params.Name,
+		Handle:           params.Handle,
+		Description:      params.Description,
+		InventoryTracked: params.InventoryTracked,
+		SKU:              params.SKU,
+		Tags:             params.Tags,
+		Status:           params.Status,
 	})
 	if err != nil {
 		slog.ErrorContext(r.Context(), "product update failed",
 			"request_id", reqID,
 			"error", err,
 		)
-		respondWithError(w, http.StatusInternalServerError, "Unable to update product", err)
+		respondWithServiceError(w, err, "Unable to update product")
 		return
 	}
 
@@ -473,14 +430,14 @@ func (cfg *apiConfig) handlerStoreProductDelete(w http.ResponseWriter, r *http.R
 			return
 		}
 		if err.Error() == "permission denied" {
-			respondWithError(w, http.StatusForbidden, "You do not have permission to delete products in this store", nil)
+			respondWithErrorCode(w, http.StatusForbidden, problem.CodePermissionDenied, "You do not have permission to delete products in this store", nil)
 			return
 		}
 		respondWithError(w, http.StatusInternalServerError, "Unable to verify access", err)
 		return
 	}
 
-	deleted, err := cfg.db.DeleteProduct(r.Context(), database.DeleteProductParams{
+	deleted, err := cfg.db.SoftDeleteProduct(r.Context(), database.SoftDeleteProductParams{
 		ID:      productID,
 		StoreID: store.ID,
 	})
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/reviews"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type StorefrontProductResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Handle      string    `json:"handle"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description,omitempty"`
+	Tags        *string   `json:"tags,omitempty"`
+	// Locale is what Name and Description are in: the one negotiated if
+	// the product has a translation in it, else the store's default
+	Locale string `json:"locale"`
+	// PriceCents is the cheapest active variant's price in Currency, the
+	// store's default. For a signed-in customer it is after their price
+	// lists, and RetailPriceCents is what a guest pays when that is more.
+	PriceCents       *int64 `json:"price_cents,omitempty"`
+	RetailPriceCents *int64 `json:"retail_price_cents,omitempty"`
+	Currency         string `json:"currency"`
+	// PresentmentPrice is PriceCents in the ?currency= asked for
+	PresentmentPrice *StorefrontPrice `json:"presentment_price,omitempty"`
+	// Rating is over the product's approved reviews
+	Rating *reviews.Summary `json:"rating,omitempty"`
+}
+
+// StorefrontCollectionProductsResponse is a collection with a page of its
+// products
+type StorefrontCollectionProductsResponse struct {
+	Collection CollectionResponse          `json:"collection"`
+	Data       []StorefrontProductResponse `json:"data"`
+	Page       PageInfo                    `json:"page"`
+}
+
+// handlerStorefrontCollectionsList lists the resolved store's collections
+func (cfg *apiConfig) handlerStorefrontCollectionsList(w http.ResponseWriter, r *http.Request) {
+	store, _ := middleware.GetResolvedStore(r.Context())
+	cfg.respondWithCollections(w, r, store.ID)
+}
+
+// handlerStorefrontCollectionProductsList lists a collection's active products in display order
+func (cfg *apiConfig) handlerStorefrontCollectionProductsList(w http.ResponseWriter, r *http.Request) {
+	store, _ := middleware.GetResolvedStore(r.Context())
+
+	currency, ok := cfg.storefrontCurrency(w, r, store)
+	if !ok {
+		return
+	}
+	loc, ok := cfg.storefrontLocale(w, r, store)
+	if !ok {
+		return
+	}
+
+	collection, err := cfg.reads.GetCollectionByHandle(r.Context(), database.GetCollectionByHandleParams{
+		StoreID: store.ID,
+		Handle:  chi.URLParam(r, "handle"),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Collection not found", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve collection", err)
+		return
+	}
+
+	channel, err := cfg.reads.GetOnlineStoreChannel(r.Context(), store.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve online store channel", err)
+		return
+	}
+
+	rows, page, ok := cfg.collectionProductsPage(w, r, collection.ID, true, uuid.NullUUID{UUID: channel.ID, Valid: true})
+	if !ok {
+		return
+	}
+
+	products := make([]StorefrontProductResponse, 0, len(rows))
+	for _, product := range rows {
+		item := StorefrontProductResponse{
+			ID:     product.ID,
+			Handle: product.Handle,
+			Name:   product.Name,
+		}
+		if product.Description.Valid {
+			item.Description = &product.Description.String
+		}
+		if product.Tags.Valid {
+			item.Tags = &product.Tags.String
+		}
+		products = append(products, item)
+	}
+	if err := cfg.localizeStorefrontProducts(r.Context(), store, loc, products); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product translations", err)
+		return
+	}
+	if err := cfg.priceStorefrontProducts(r.Context(), store, currency, products); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product prices", err)
+		return
+	}
+	if err := cfg.rateStorefrontProducts(r.Context(), products); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product ratings", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, StorefrontCollectionProductsResponse{
+		Collection: collectionToResponse(collection),
+		Data:       products,
+		Page:       page,
+	})
+}
@@ -0,0 +1,435 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/crypto"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/lockout"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/google/uuid"
+)
+
+const (
+	customerAccessTokenTTL  = time.Hour
+	customerRefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+type CustomerResponse struct {
+	ID               uuid.UUID `json:"id"`
+	StoreID          uuid.UUID `json:"store_id"`
+	Email            string    `json:"email"`
+	FirstName        *string   `json:"first_name,omitempty"`
+	LastName         *string   `json:"last_name,omitempty"`
+	Phone            *string   `json:"phone,omitempty"`
+	AcceptsMarketing bool      `json:"accepts_marketing"`
+	Status           string    `json:"status"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+type customerAuthResponse struct {
+	Customer     CustomerResponse `json:"customer"`
+	Token        string           `json:"token"`
+	RefreshToken string           `json:"refresh_token"`
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// handlerStorefrontCustomerRegister creates a shopper account on the resolved store
+func (cfg *apiConfig) handlerStorefrontCustomerRegister(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	store, _ := middleware.GetResolvedStore(r.Context())
+
+	slog.InfoContext(r.Context(), "storefront customer registration request received",
+		"request_id", reqID,
+		"store_id", store.ID,
+	)
+
+	type parameters struct {
+		Email            string  `json:"email"`
+		Password         string  `json:"password"`
+		FirstName        *string `json:"first_name"`
+		LastName         *string `json:"last_name"`
+		Phone            *string `json:"phone"`
+		AcceptsMarketing bool    `json:"accepts_marketing"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	email := normalizeEmail(params.Email)
+	var v validate.Validator
+	if v.Required("email", email) {
+		v.Email("email", email)
+	}
+	v.Check(len(params.Password) >= 8, "password", validate.CodeTooShort, "must be at least 8 characters")
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	hash, err := auth.HashPassword(params.Password)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Unable to use this password", err)
+		return
+	}
+
+	customerGID := cfg.gidGen.Generate()
+	customer, err := cfg.db.CreateCustomer(r.Context(), database.CreateCustomerParams{
+		Gid:              sql.NullInt64{Int64: int64(customerGID), Valid: true},
+		TenantID:         store.TenantID.UUID,
+		StoreID:          store.ID,
+		Email:            crypto.Text(email),
+		EmailDigest:      crypto.Digest(email),
+		HashedPassword:   sql.NullString{String: hash, Valid: true},
+		FirstName:        nullStringFromPtr(params.FirstName),
+		LastName:         nullStringFromPtr(params.LastName),
+		Phone:            crypto.NullText(nullStringFromPtr(params.Phone)),
+		AcceptsMarketing: params.AcceptsMarketing,
+		PlainEmail:       email,
+	})
+	if err != nil {
+		slog.WarnContext(r.Context(), "storefront customer registration failed",
+			"request_id", reqID,
+			"store_id", store.ID,
+			"error", err,
+		)
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeEmailTaken, "An account with this email already exists", err)
+		return
+	}
+
+	resp, err := cfg.issueCustomerTokens(r, customer)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Account created but unable to sign in", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "storefront customer registered",
+		"request_id", reqID,
+		"store_id", store.ID,
+		"customer_id", customer.ID,
+	)
+
+	respondWithJSON(w, http.StatusCreated, resp)
+}
+
+// handlerStorefrontCustomerLogin exchanges shopper credentials for tokens
+func (cfg *apiConfig) handlerStorefrontCustomerLogin(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	store, _ := middleware.GetResolvedStore(r.Context())
+
+	type parameters struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+		// CaptchaToken is needed once the account has been failing
+		CaptchaToken string `json:"captcha_token"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	attempt := customerLoginAttempt(r, store.ID, params.Email)
+	who := loginSubject{StoreID: store.ID, Email: normalizeEmail(params.Email)}
+	status, ok := cfg.checkLoginLock(w, r, attempt)
+	if !ok {
+		return
+	}
+	if !cfg.checkLoginCaptcha(w, r, status, params.CaptchaToken, who) {
+		return
+	}
+
+	customer, err := cfg.db.GetCustomerByEmail(r.Context(), database.GetCustomerByEmailParams{
+		StoreID:     store.ID,
+		EmailDigest: crypto.Digest(normalizeEmail(params.Email)),
+		Email:       normalizeEmail(params.Email),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			cfg.failLogin(r, attempt, lockout.EventLoginFailed, who)
+		}
+		respondWithError(w, http.StatusUnauthorized, "Incorrect email or password", err)
+		return
+	}
+	who.CustomerID = customer.ID
+
+	if !customer.HashedPassword.Valid {
+		cfg.failLogin(r, attempt, lockout.EventLoginFailed, who)
+		respondWithError(w, http.StatusUnauthorized, "Incorrect email or password", nil)
+		return
+	}
+
+	if err := auth.CheckPasswordHash(params.Password, customer.HashedPassword.String); err != nil {
+		cfg.failLogin(r, attempt, lockout.EventLoginFailed, who)
+		respondWithError(w, http.StatusUnauthorized, "Incorrect email or password", err)
+		return
+	}
+
+	if customer.Status != "enabled" {
+		respondWithErrorCode(w, http.StatusForbidden, problem.CodeAccountDisabled, "This account has been disabled", nil)
+		return
+	}
+
+	resp, err := cfg.issueCustomerTokens(r, customer)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to sign in", err)
+		return
+	}
+
+	cfg.succeedLogin(r, attempt)
+
+	slog.InfoContext(r.Context(), "storefront customer logged in",
+		"request_id", reqID,
+		"store_id", store.ID,
+		"customer_id", customer.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerStorefrontCustomerRefresh issues a new access token from a refresh token
+func (cfg *apiConfig) handlerStorefrontCustomerRefresh(w http.ResponseWriter, r *http.Request) {
+	type response struct {
+		Token string `json:"token"`
+	}
+
+	store, _ := middleware.GetResolvedStore(r.Context())
+
+	refreshToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't find token", err)
+		return
+	}
+
+	customer, err := cfg.db.GetCustomerFromRefreshToken(r.Context(), database.GetCustomerFromRefreshTokenParams{
+		TokenHash: auth.HashToken(refreshToken),
+		StoreID:   store.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't get customer for refresh token", err)
+		return
+	}
+
+	if customer.Status != "enabled" {
+		respondWithErrorCode(w, http.StatusForbidden, problem.CodeAccountDisabled, "This account has been disabled", nil)
+		return
+	}
+
+	accessToken, err := auth.MakeCustomerJWT(customer.ID, store.ID, cfg.jwtKeys, customerAccessTokenTTL)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create token", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response{
+		Token: accessToken,
+	})
+}
+
+// handlerStorefrontCustomerRevoke revokes a shopper refresh token (logout)
+func (cfg *apiConfig) handlerStorefrontCustomerRevoke(w http.ResponseWriter, r *http.Request) {
+	refreshToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't find token", err)
+		return
+	}
+
+	if err := cfg.db.RevokeCustomerRefreshToken(r.Context(), auth.HashToken(refreshToken)); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't revoke session", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerStorefrontCustomerMe returns the signed-in shopper's profile
+func (cfg *apiConfig) handlerStorefrontCustomerMe(w http.ResponseWriter, r *http.Request) {
+	store, _ := middleware.GetResolvedStore(r.Context())
+	customerID, ok := customerFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	customer, err := cfg.db.GetCustomerByID(r.Context(), database.GetCustomerByIDParams{
+		ID:      customerID,
+		StoreID: store.ID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Customer not found", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve customer", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, customerToResponse(customer))
+}
+
+// handlerStorefrontCustomerUpdate lets a shopper edit their own profile
+func (cfg *apiConfig) handlerStorefrontCustomerUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	store, _ := middleware.GetResolvedStore(r.Context())
+	customerID, ok := customerFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	existing, err := cfg.db.GetCustomerByID(r.Context(), database.GetCustomerByIDParams{
+		ID:      customerID,
+		StoreID: store.ID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Customer not found", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve customer", err)
+		return
+	}
+
+	type parameters struct {
+		FirstName        *string `json:"first_name"`
+		LastName         *string `json:"last_name"`
+		Phone            *string `json:"phone"`
+		AcceptsMarketing *bool   `json:"accepts_marketing"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	firstName := existing.FirstName
+	if params.FirstName != nil {
+		firstName = sql.NullString{String: *params.FirstName, Valid: true}
+	}
+
+	lastName := existing.LastName
+	if params.LastName != nil {
+		lastName = sql.NullString{String: *params.LastName, Valid: true}
+	}
+
+	phone := existing.Phone
+	if params.Phone != nil {
+		phone = crypto.NullText{String: *params.Phone, Valid: true}
+	}
+
+	acceptsMarketing := existing.AcceptsMarketing
+	if params.AcceptsMarketing != nil {
+		acceptsMarketing = *params.AcceptsMarketing
+	}
+
+	customer, err := cfg.db.UpdateCustomerProfile(r.Context(), database.UpdateCustomerProfileParams{
+		ID:               existing.ID,
+		StoreID:          existing.StoreID,
+		FirstName:        firstName,
+		LastName:         lastName,
+		Phone:            phone,
+		AcceptsMarketing: acceptsMarketing,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update customer", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "storefront customer profile updated",
+		"request_id", reqID,
+		"store_id", store.ID,
+		"customer_id", customer.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, customerToResponse(customer))
+}
+
+func (cfg *apiConfig) issueCustomerTokens(r *http.Request, customer database.Customer) (customerAuthResponse, error) {
+	accessToken, err := auth.MakeCustomerJWT(customer.ID, customer.StoreID, cfg.jwtKeys, customerAccessTokenTTL)
+	if err != nil {
+		return customerAuthResponse{}, err
+	}
+
+	refreshToken, err := auth.MakeRefreshToken()
+	if err != nil {
+		return customerAuthResponse{}, err
+	}
+
+	_, err = cfg.db.CreateCustomerRefreshToken(r.Context(), database.CreateCustomerRefreshTokenParams{
+		TokenHash:  auth.HashToken(refreshToken),
+		CustomerID: customer.ID,
+		ExpiresAt:  time.Now().Add(customerRefreshTokenTTL),
+	})
+	if err != nil {
+		return customerAuthResponse{}, err
+	}
+
+	return customerAuthResponse{
+		Customer:     customerToResponse(customer),
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func customerToResponse(customer database.Customer) CustomerResponse {
+	var firstName, lastName, phone *string
+	if customer.FirstName.Valid {
+		firstName = &customer.FirstName.String
+	}
+	if customer.LastName.Valid {
+		lastName = &customer.LastName.String
+	}
+	if customer.Phone.Valid {
+		phone = &customer.Phone.String
+	}
+	return CustomerResponse{
+		ID:               customer.ID,
+		StoreID:          customer.StoreID,
+		Email:            string(customer.Email),
+		FirstName:        firstName,
+		LastName:         lastName,
+		Phone:            phone,
+		AcceptsMarketing: customer.AcceptsMarketing,
+		Status:           customer.Status,
+		CreatedAt:        customer.CreatedAt,
+		UpdatedAt:        customer.UpdatedAt,
+	}
+}
+
+func nullStringFromPtr(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+func nullInt32FromPtr(n *int32) sql.NullInt32 {
+	if n == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: *n, Valid: true}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/giftcard"
+	"github.com/dfodeker/terminus/internal/mailer"
+	"github.com/dfodeker/terminus/internal/orders"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type GiftCardBalanceResponse struct {
+	LastCharacters string     `json:"last_characters"`
+	Currency       string     `json:"currency"`
+	BalanceCents   int64      `json:"balance_cents"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	Redeemable     bool       `json:"redeemable"`
+}
+
+// handlerStorefrontGiftCardBalance looks up a card's balance by code. Codes
+// are the only credential, so unknown codes get a plain 404.
+func (cfg *apiConfig) handlerStorefrontGiftCardBalance(w http.ResponseWriter, r *http.Request) {
+	store, _ := middleware.GetResolvedStore(r.Context())
+
+	type parameters struct {
+		Code string `json:"code"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	card, ok := cfg.loadGiftCardByCode(w, r, store.ID, params.Code)
+	if !ok {
+		return
+	}
+
+	resp := GiftCardBalanceResponse{
+		LastCharacters: card.LastCharacters,
+		Currency:       card.Currency,
+		BalanceCents:   card.BalanceCents,
+		Redeemable:     giftCardRedeemable(card, time.Now()) == "" && card.BalanceCents > 0,
+	}
+	if card.ExpiresAt.Valid {
+		resp.ExpiresAt = &card.ExpiresAt.Time
+	}
+
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerStorefrontOrderGiftCardRedeem pays part or all of one of the
+// signed-in shopper's pending orders with a gift card. Several cards can be
+// applied to one order; it is marked paid once they cover the total.
+func (cfg *apiConfig) handlerStorefrontOrderGiftCardRedeem(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	store, _ := middleware.GetResolvedStore(r.Context())
+	customerID, ok := customerFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "orderID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid order ID format", err)
+		return
+	}
+
+	type parameters struct {
+		Code string `json:"code"`
+		// AmountCents limits the redemption; omit to draw as much as possible
+		AmountCents int64 `json:"amount_cents"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	card, ok := cfg.loadGiftCardByCode(w, r, store.ID, params.Code)
+	if !ok {
+		return
+	}
+	if reason := giftCardRedeemable(card, time.Now()); reason != "" {
+		respondWithError(w, http.StatusUnprocessableEntity, reason, nil)
+		return
+	}
+
+	var order database.Order
+	var due, amount int64
+	err = cfg.withTenantTx(r.Context(), store.TenantID.UUID, func(q *database.Queries) error {
+		// Lock the order so concurrent redemptions can't overpay it
+		var err error
+		order, err = q.LockOrderForUpdate(r.Context(), database.LockOrderForUpdateParams{
+			ID:      orderID,
+			StoreID: store.ID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return abortTx(http.StatusNotFound, "Order not found", nil)
+			}
+			return err
+		}
+
+		// Shoppers only ever see their own orders; don't reveal that others exist
+		if !order.CustomerID.Valid || order.CustomerID.UUID != customerID {
+			return abortTx(http.StatusNotFound, "Order not found", nil)
+		}
+		if order.Status != "open" || order.FinancialStatus != "pending" {
+			return abortTx(http.StatusConflict, "Order is not awaiting payment", nil)
+		}
+		if order.Currency != card.Currency {
+			return abortTx(http.StatusUnprocessableEntity, "Gift card currency does not match the order", nil)
+		}
+
+		redeemed, err := q.SumOrderGiftCardRedemptions(r.Context(), uuid.NullUUID{UUID: order.ID, Valid: true})
+		if err != nil {
+			return err
+		}
+		due = order.TotalCents - redeemed
+
+		amount, err = giftcard.RedeemAmount(card.BalanceCents, due, params.AmountCents)
+		if err != nil {
+			return abortTx(http.StatusUnprocessableEntity, err.Error(), err)
+		}
+
+		card, err = q.ChangeGiftCardBalance(r.Context(), database.ChangeGiftCardBalanceParams{
+			AmountCents: -amount,
+			ID:          card.ID,
+			StoreID:     store.ID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return abortTx(http.StatusConflict, "Gift card balance changed, please try again", nil)
+			}
+			return err
+		}
+
+		_, err = q.CreateGiftCardTransaction(r.Context(), database.CreateGiftCardTransactionParams{
+			GiftCardID:        card.ID,
+			StoreID:           store.ID,
+			Kind:              "redeem",
+			AmountCents:       -amount,
+			BalanceAfterCents: card.BalanceCents,
+			OrderID:           uuid.NullUUID{UUID: order.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = recordOrderEvent(r.Context(), q, order, orders.EventPayment,
+			fmt.Sprintf("Paid %d %s with gift card ending %s", amount, card.Currency, card.LastCharacters),
+			map[string]any{"gift_card_id": card.ID, "amount_cents": amount}, uuid.Nil)
+		if err != nil {
+			return err
+		}
+
+		due -= amount
+		if due == 0 {
+			order, err = q.UpdateOrderFinancialStatus(r.Context(), database.UpdateOrderFinancialStatusParams{
+				ID:              order.ID,
+				StoreID:         store.ID,
+				FinancialStatus: "paid",
+			})
+			if err != nil {
+				return err
+			}
+			err = cfg.queueOrderEmail(r.Context(), q, mailer.OrderEmailArgs{
+				Template:    mailer.TemplateOrderPaid,
+				OrderID:     order.ID,
+				StoreID:     store.ID,
+				AmountCents: order.TotalCents,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		respondWithTxError(w, err, "Unable to redeem gift card")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "storefront gift card redeemed",
+		"request_id", reqID,
+		"store_id", store.ID,
+		"customer_id", customerID,
+		"order_id", order.ID,
+		"gift_card_id", card.ID,
+		"amount_cents", amount,
+		"amount_due_cents", due,
+	)
+
+	respondWithJSON(w, http.StatusCreated, map[string]any{
+		"order":            orderToResponse(order, nil),
+		"redeemed_cents":   amount,
+		"amount_due_cents": due,
+		"gift_card": GiftCardBalanceResponse{
+			LastCharacters: card.LastCharacters,
+			Currency:       card.Currency,
+			BalanceCents:   card.BalanceCents,
+			Redeemable:     card.BalanceCents > 0,
+		},
+	})
+}
+
+// loadGiftCardByCode finds a card by its code within the store
+func (cfg *apiConfig) loadGiftCardByCode(w http.ResponseWriter, r *http.Request, storeID uuid.UUID, code string) (database.GiftCard, bool) {
+	if giftcard.Normalize(code) == "" {
+		respondWithError(w, http.StatusBadRequest, "Gift card code is required", nil)
+		return database.GiftCard{}, false
+	}
+
+	card, err := cfg.db.GetGiftCardByCodeHash(r.Context(), database.GetGiftCardByCodeHashParams{
+		StoreID:  storeID,
+		CodeHash: giftcard.Hash(code),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Gift card not found", nil)
+			return database.GiftCard{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve gift card", err)
+		return database.GiftCard{}, false
+	}
+	return card, true
+}
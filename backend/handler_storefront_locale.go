@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/locale"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/google/uuid"
+)
+
+// storefrontLocale picks the locale of the response from ?locale= or else
+// Accept-Language, among the store's default locale and those it has
+// translations in, and names it in Content-Language. It has responded
+// when it returns false.
+func (cfg *apiConfig) storefrontLocale(w http.ResponseWriter, r *http.Request, store middleware.ResolvedStore) (string, bool) {
+	explicit := r.URL.Query().Get("locale")
+	if explicit != "" {
+		if _, err := locale.Parse(explicit); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid locale", err)
+			return "", false
+		}
+	}
+	w.Header().Add("Vary", "Accept-Language")
+
+	available, err := cfg.db.ListStoreTranslationLocales(r.Context(), store.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve store locales", err)
+		return "", false
+	}
+
+	loc := locale.Negotiate(explicit, r.Header.Get("Accept-Language"), store.DefaultLocale, available)
+	w.Header().Set("Content-Language", loc)
+	return loc, true
+}
+
+// localizeStorefrontProducts swaps in each product's translation in loc.
+// A product without one, and any field the translation leaves out, keeps
+// the store's own content.
+func (cfg *apiConfig) localizeStorefrontProducts(ctx context.Context, store middleware.ResolvedStore, loc string, products []StorefrontProductResponse) error {
+	for i := range products {
+		products[i].Locale = store.DefaultLocale
+	}
+	if loc == store.DefaultLocale || len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+	translations, err := cfg.db.GetProductTranslationsForLocale(ctx, database.GetProductTranslationsForLocaleParams{
+		StoreID:    store.ID,
+		Locale:     loc,
+		ProductIds: ids,
+	})
+	if err != nil {
+		return err
+	}
+
+	byProduct := make(map[uuid.UUID]database.ProductTranslation, len(translations))
+	for _, t := range translations {
+		byProduct[t.ProductID] = t
+	}
+	for i := range products {
+		t, ok := byProduct[products[i].ID]
+		if !ok {
+			continue
+		}
+		p := &products[i]
+		p.Locale = loc
+		p.Name = t.Name
+		if t.Description.Valid {
+			p.Description = &t.Description.String
+		}
+		if t.Handle.Valid {
+			p.Handle = t.Handle.String
+		}
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/fulfillment"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type OrderResponse struct {
+	ID                uuid.UUID               `json:"id"`
+	StoreID           uuid.UUID               `json:"store_id"`
+	CustomerID        *uuid.UUID              `json:"customer_id,omitempty"`
+	OrderNumber       int64                   `json:"order_number"`
+	Email             *string                 `json:"email,omitempty"`
+	Status            string                  `json:"status"`
+	FinancialStatus   string                  `json:"financial_status"`
+	FulfillmentStatus string                  `json:"fulfillment_status"`
+	Currency          string                  `json:"currency"`
+	SubtotalCents     int64                   `json:"subtotal_cents"`
+	ShippingCents     int64                   `json:"shipping_cents"`
+	TaxCents          int64                   `json:"tax_cents"`
+	DiscountCents     int64                   `json:"discount_cents"`
+	TotalCents        int64                   `json:"total_cents"`
+	ShippingAddress   json.RawMessage         `json:"shipping_address,omitempty"`
+	BillingAddress    json.RawMessage         `json:"billing_address,omitempty"`
+	LineItems         []OrderLineItemResponse `json:"line_items,omitempty"`
+	Fulfillments      []FulfillmentResponse   `json:"fulfillments,omitempty"`
+	PlacedAt          time.Time               `json:"placed_at"`
+	CreatedAt         time.Time               `json:"created_at"`
+	UpdatedAt         time.Time               `json:"updated_at"`
+}
+
+type OrderLineItemResponse struct {
+	ID uuid.UUID `json:"id"`
+	// ParentLineItemID is set on the component lines of a bundle line
+	ParentLineItemID *uuid.UUID `json:"parent_line_item_id,omitempty"`
+	ProductID        *uuid.UUID `json:"product_id,omitempty"`
+	VariantID        *uuid.UUID `json:"variant_id,omitempty"`
+	Title            string     `json:"title"`
+	VariantTitle     *string    `json:"variant_title,omitempty"`
+	SKU              *string    `json:"sku,omitempty"`
+	Quantity         int32      `json:"quantity"`
+	PriceCents       int64      `json:"price_cents"`
+	TotalCents       int64      `json:"total_cents"`
+}
+
+type OrderCursor struct {
+	PlacedAt time.Time `json:"placed_at"`
+	ID       uuid.UUID `json:"id"`
+}
+
+var orderCursorCodec = CursorCodec[OrderCursor]{
+	Validate: func(c OrderCursor) error {
+		if c.PlacedAt.IsZero() || c.ID == uuid.Nil {
+			return errors.New("invalid cursor: missing required fields")
+		}
+		return nil
+	},
+}
+
+// handlerStorefrontCustomerOrdersList returns the signed-in shopper's order history, newest first
+func (cfg *apiConfig) handlerStorefrontCustomerOrdersList(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := customerFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	cfg.respondWithCustomerOrders(w, r, customerID)
+}
+
+// handlerStorefrontCustomerOrderGet returns one of the signed-in shopper's orders with line items
+func (cfg *apiConfig) handlerStorefrontCustomerOrderGet(w http.ResponseWriter, r *http.Request) {
+	store, _ := middleware.GetResolvedStore(r.Context())
+	customerID, ok := customerFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "orderID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid order ID format", err)
+		return
+	}
+
+	order, err := cfg.db.GetOrderByID(r.Context(), database.GetOrderByIDParams{
+		ID:      orderID,
+		StoreID: store.ID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Order not found", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve order", err)
+		return
+	}
+
+	// Shoppers only ever see their own orders; don't reveal that others exist
+	if !order.CustomerID.Valid || order.CustomerID.UUID != customerID {
+		respondWithError(w, http.StatusNotFound, "Order not found", nil)
+		return
+	}
+
+	lineItems, err := cfg.db.GetOrderLineItems(r.Context(), order.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve order line items", err)
+		return
+	}
+
+	fulfillments, err := cfg.db.GetFulfillmentsByOrder(r.Context(), order.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve order fulfillments", err)
+		return
+	}
+
+	items, err := cfg.orderFulfillmentItems(r.Context(), order.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve order fulfillments", err)
+		return
+	}
+
+	resp := orderToResponse(order, lineItems)
+	for _, f := range fulfillments {
+		if f.Status == string(fulfillment.StatusCancelled) {
+			continue
+		}
+		resp.Fulfillments = append(resp.Fulfillments, fulfillmentToResponse(f, items[f.ID]))
+	}
+
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// respondWithCustomerOrders writes one page of a customer's orders, newest first
+func (cfg *apiConfig) respondWithCustomerOrders(w http.ResponseWriter, r *http.Request, customerID uuid.UUID) {
+	pageParams, err := ParsePageParams(r, 20, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+
+	limit := pageParams.Limit
+	limitPlusOne := int32(pageParams.Limit + 1)
+
+	cursor, hasCursor, err := orderCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.db.GetOrdersByCustomerPaginated(r.Context(), database.GetOrdersByCustomerPaginatedParams{
+		CustomerID:     uuid.NullUUID{UUID: customerID, Valid: true},
+		HasCursor:      hasCursor,
+		CursorPlacedAt: cursor.PlacedAt,
+		CursorID:       cursor.ID,
+		RowLimit:       limitPlusOne,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve orders", err)
+		return
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = orderCursorCodec.Encode(OrderCursor{
+			PlacedAt: last.PlacedAt,
+			ID:       last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]OrderResponse, 0, len(rows))
+	for _, order := range rows {
+		response = append(response, orderToResponse(order, nil))
+	}
+
+	respondWithJSON(w, http.StatusOK, PageResponse[OrderResponse]{
+		Data: response,
+		Page: PageInfo{Limit: limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
+
+func orderToResponse(order database.Order, lineItems []database.OrderLineItem) OrderResponse {
+	var customerID *uuid.UUID
+	if order.CustomerID.Valid {
+		customerID = &order.CustomerID.UUID
+	}
+	var email *string
+	if order.Email.Valid {
+		email = &order.Email.String
+	}
+
+	resp := OrderResponse{
+		ID:                order.ID,
+		StoreID:           order.StoreID,
+		CustomerID:        customerID,
+		OrderNumber:       order.OrderNumber,
+		Email:             email,
+		Status:            order.Status,
+		FinancialStatus:   order.FinancialStatus,
+		FulfillmentStatus: order.FulfillmentStatus,
+		Currency:          order.Currency,
+		SubtotalCents:     order.SubtotalCents,
+		ShippingCents:     order.ShippingCents,
+		TaxCents:          order.TaxCents,
+		DiscountCents:     order.DiscountCents,
+		TotalCents:        order.TotalCents,
+		PlacedAt:          order.PlacedAt,
+		CreatedAt:         order.CreatedAt,
+		UpdatedAt:         order.UpdatedAt,
+	}
+
+	if order.ShippingAddress.Valid {
+		resp.ShippingAddress = order.ShippingAddress.RawMessage
+	}
+	if order.BillingAddress.Valid {
+		resp.BillingAddress = order.BillingAddress.RawMessage
+	}
+
+	if lineItems != nil {
+		resp.LineItems = make([]OrderLineItemResponse, 0, len(lineItems))
+		for _, li := range lineItems {
+			item := OrderLineItemResponse{
+				ID:         li.ID,
+				Title:      li.Title,
+				Quantity:   li.Quantity,
+				PriceCents: li.PriceCents,
+				TotalCents: li.TotalCents,
+			}
+			if li.ParentLineItemID.Valid {
+				item.ParentLineItemID = &li.ParentLineItemID.UUID
+			}
+			if li.ProductID.Valid {
+				item.ProductID = &li.ProductID.UUID
+			}
+			if li.VariantID.Valid {
+				item.VariantID = &li.VariantID.UUID
+			}
+			if li.VariantTitle.Valid {
+				item.VariantTitle = &li.VariantTitle.String
+			}
+			if li.Sku.Valid {
+				item.SKU = &li.Sku.String
+			}
+			resp.LineItems = append(resp.LineItems, item)
+		}
+	}
+
+	return resp
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/fx"
+	"github.com/dfodeker/terminus/internal/pricelists"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/google/uuid"
+)
+
+// StorefrontPrice is an amount in the minor units of Currency
+type StorefrontPrice struct {
+	Currency   string `json:"currency"`
+	PriceCents int64  `json:"price_cents"`
+}
+
+// storefrontCurrency reads ?currency=, which must be the store's default
+// currency or one of its presentment currencies. It is "" when not given,
+// and has responded when it returns false.
+func (cfg *apiConfig) storefrontCurrency(w http.ResponseWriter, r *http.Request, store middleware.ResolvedStore) (string, bool) {
+	currency := r.URL.Query().Get("currency")
+	if currency == "" || currency == store.DefaultCurrency {
+		return currency, true
+	}
+
+	enabled, err := cfg.db.ListStoreCurrencies(r.Context(), store.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve store currencies", err)
+		return "", false
+	}
+	if !slices.Contains(enabled, currency) {
+		respondWithError(w, http.StatusBadRequest, "Currency "+currency+" is not available in this store", nil)
+		return "", false
+	}
+	return currency, true
+}
+
+// priceStorefrontProducts sets each product's price to its cheapest active
+// variant, after the price lists of the signed-in customer if there is one.
+// With a currency it also sets the presentment price, from the variants'
+// overrides in that currency or else their converted base price; a
+// customer's list price is converted too when it comes out lower. A
+// product with no override and no exchange rate is left without one.
+func (cfg *apiConfig) priceStorefrontProducts(ctx context.Context, store middleware.ResolvedStore, currency string, products []StorefrontProductResponse) error {
+	if len(products) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+
+	variants, err := cfg.db.GetStorefrontVariantPrices(ctx, database.GetStorefrontVariantPricesParams{
+		StoreID:    store.ID,
+		ProductIds: ids,
+		Currency:   currency,
+	})
+	if err != nil {
+		return err
+	}
+
+	retail := make(map[uuid.UUID]int64, len(variants))
+	for _, v := range variants {
+		retail[v.ID] = int64(v.PriceCents)
+	}
+	customer, err := cfg.customerPrices(ctx, store.ID, retail)
+	if err != nil {
+		return err
+	}
+
+	var rates fx.Rates
+	if currency != "" && currency != store.DefaultCurrency {
+		rows, err := cfg.db.ListExchangeRates(ctx, []string{store.DefaultCurrency, currency})
+		if err != nil {
+			return err
+		}
+		rates = make(fx.Rates, len(rows))
+		for _, row := range rows {
+			rates[row.Currency] = row.Rate
+		}
+	}
+
+	base := make(map[uuid.UUID]int64, len(products))
+	guest := make(map[uuid.UUID]int64, len(products))
+	presentment := make(map[uuid.UUID]int64, len(products))
+	for _, v := range variants {
+		price := customer.Price(v.ID, int64(v.PriceCents))
+		if lowest, ok := base[v.ProductID]; !ok || price < lowest {
+			base[v.ProductID] = price
+		}
+		if lowest, ok := guest[v.ProductID]; !ok || int64(v.PriceCents) < lowest {
+			guest[v.ProductID] = int64(v.PriceCents)
+		}
+		if currency == "" {
+			continue
+		}
+		converted, ok := int64(v.OverrideCents.Int32), v.OverrideCents.Valid
+		if !ok {
+			converted, ok = rates.Convert(int64(v.PriceCents), store.DefaultCurrency, currency)
+		}
+		if price < int64(v.PriceCents) {
+			if c, cok := rates.Convert(price, store.DefaultCurrency, currency); cok && (!ok || c < converted) {
+				converted, ok = c, true
+			}
+		}
+		if lowest, seen := presentment[v.ProductID]; ok && (!seen || converted < lowest) {
+			presentment[v.ProductID] = converted
+		}
+	}
+
+	for i := range products {
+		p := &products[i]
+		p.Currency = store.DefaultCurrency
+		if price, ok := base[p.ID]; ok {
+			p.PriceCents = &price
+			if retail := guest[p.ID]; retail > price {
+				p.RetailPriceCents = &retail
+			}
+		}
+		if price, ok := presentment[p.ID]; ok {
+			p.PresentmentPrice = &StorefrontPrice{Currency: currency, PriceCents: price}
+		}
+	}
+	return nil
+}
+
+// customerPrices works out what the signed-in customer, if any, pays for
+// the variants in retail from the price lists assigned to their groups. It
+// is nil for guests, which prices every variant at retail.
+func (cfg *apiConfig) customerPrices(ctx context.Context, storeID uuid.UUID, retail map[uuid.UUID]int64) (pricelists.Prices, error) {
+	customerID, ok := customerFromContext(ctx)
+	if !ok || len(retail) == 0 {
+		return nil, nil
+	}
+
+	variantIDs := make([]uuid.UUID, 0, len(retail))
+	for id := range retail {
+		variantIDs = append(variantIDs, id)
+	}
+	rows, err := cfg.db.GetCustomerPriceListEntries(ctx, database.GetCustomerPriceListEntriesParams{
+		StoreID:    storeID,
+		CustomerID: customerID,
+		VariantIds: variantIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]pricelists.Entry, 0, len(rows))
+	for _, row := range rows {
+		entry := pricelists.Entry{VariantID: row.VariantID, PercentOff: int64(row.PercentOff.Int32)}
+		if row.PriceCents.Valid {
+			price := int64(row.PriceCents.Int32)
+			entry.PriceCents = &price
+		}
+		entries = append(entries, entry)
+	}
+	return pricelists.Resolve(retail, entries), nil
+}
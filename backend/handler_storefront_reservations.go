@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/bundles"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/inventory"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// reservedStock is the stock one variant of a reservation needs, after
+// bundles are broken down into their components
+type reservedStock struct {
+	VariantID uuid.UUID
+	Quantity  int32
+	Tracked   bool
+	Policy    inventory.OversellPolicy
+}
+
+// handlerStorefrontReservationCreate holds stock for a checkout until it is
+// committed, released or expires. Each variant is locked while it is
+// reserved, so two checkouts cannot both take the last unit; a variant
+// short of stock is refused, sold through or backordered as its product's
+// oversell policy says.
+func (cfg *apiConfig) handlerStorefrontReservationCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	store, _ := middleware.GetResolvedStore(r.Context())
+	customerID, signedIn := customerFromContext(r.Context())
+
+	type parameters struct {
+		Items []inventory.Line `json:"items"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	lines, err := inventory.MergeLines(params.Items)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	stock, err := cfg.reservedStock(r.Context(), store.ID, lines)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusUnprocessableEntity, "One or more items are not available", nil)
+		return
+	}
+	if errors.Is(err, bundles.ErrOverflow) {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve variants", err)
+		return
+	}
+
+	var reservation database.InventoryReservation
+	var items []database.InventoryReservationItem
+	err = cfg.withTenantTx(r.Context(), store.TenantID.UUID, func(q *database.Queries) error {
+		var err error
+		reservation, err = q.CreateInventoryReservation(r.Context(), database.CreateInventoryReservationParams{
+			Gid:        sql.NullInt64{Int64: int64(cfg.gidGen.Generate()), Valid: true},
+			TenantID:   store.TenantID.UUID,
+			StoreID:    store.ID,
+			CustomerID: uuid.NullUUID{UUID: customerID, Valid: signedIn},
+			ExpiresAt:  time.Now().Add(cfg.config.ReservationTTL),
+		})
+		if err != nil {
+			return err
+		}
+
+		// Always lock in the same order so concurrent checkouts of the
+		// same variants cannot deadlock
+		for _, s := range stock {
+			item, err := reserveStock(r.Context(), q, reservation, s)
+			if err != nil {
+				return err
+			}
+			items = append(items, item)
+		}
+		return nil
+	})
+	if errors.Is(err, inventory.ErrInsufficientStock) {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeInsufficientStock, err.Error(), err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to reserve stock", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "inventory reserved",
+		"request_id", reqID,
+		"store_id", store.ID,
+		"reservation_id", reservation.ID,
+		"items", len(items),
+	)
+
+	respondWithJSON(w, http.StatusCreated, reservationToResponse(reservation, items))
+}
+
+// handlerStorefrontReservationGet returns a reservation of the store. One
+// made by a signed-in customer is only shown to them.
+func (cfg *apiConfig) handlerStorefrontReservationGet(w http.ResponseWriter, r *http.Request) {
+	reservation, ok := cfg.loadStorefrontReservation(w, r)
+	if !ok {
+		return
+	}
+	items, err := cfg.db.GetInventoryReservationItems(r.Context(), reservation.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve reservation items", err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, reservationToResponse(reservation, items))
+}
+
+// handlerStorefrontReservationRelease gives back the stock of a checkout
+// the shopper left
+func (cfg *apiConfig) handlerStorefrontReservationRelease(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	existing, ok := cfg.loadStorefrontReservation(w, r)
+	if !ok {
+		return
+	}
+	reservation, _, ok := cfg.releaseReservation(w, r, existing)
+	if !ok {
+		return
+	}
+
+	slog.InfoContext(r.Context(), "inventory reservation released",
+		"request_id", reqID,
+		"store_id", reservation.StoreID,
+		"reservation_id", reservation.ID,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// reservedStock breaks lines down into the stock they need, bundles into
+// their components, sorted by variant. It fails with sql.ErrNoRows when a
+// variant is not on sale.
+func (cfg *apiConfig) reservedStock(ctx context.Context, storeID uuid.UUID, lines []inventory.Line) ([]reservedStock, error) {
+	ids := make([]uuid.UUID, len(lines))
+	for i, l := range lines {
+		ids[i] = l.VariantID
+	}
+	variants, err := cfg.db.GetReservableVariants(ctx, database.GetReservableVariantsParams{
+		StoreID:    storeID,
+		VariantIds: ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(variants) != len(ids) {
+		return nil, sql.ErrNoRows
+	}
+	byID := make(map[uuid.UUID]database.GetReservableVariantsRow, len(variants))
+	for _, v := range variants {
+		byID[v.ID] = v
+	}
+
+	needed := make(map[uuid.UUID]*reservedStock)
+	add := func(s reservedStock) error {
+		existing, ok := needed[s.VariantID]
+		if !ok {
+			needed[s.VariantID] = &s
+			return nil
+		}
+		if int64(existing.Quantity)+int64(s.Quantity) > 1<<31-1 {
+			return bundles.ErrOverflow
+		}
+		existing.Quantity += s.Quantity
+		return nil
+	}
+
+	for _, l := range lines {
+		v := byID[l.VariantID]
+
+		// A bundle's stock is its components'
+		components, err := cfg.db.ListBundleComponents(ctx, l.VariantID)
+		if err != nil {
+			return nil, err
+		}
+		if len(components) == 0 {
+			if err := add(reservedStock{
+				VariantID: v.ID,
+				Quantity:  l.Quantity,
+				Tracked:   v.InventoryTracked,
+				Policy:    inventory.OversellPolicy(v.OversellPolicy),
+			}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		parts := make([]bundles.Component, len(components))
+		for i, c := range components {
+			parts[i] = bundles.Component{VariantID: c.ComponentVariantID, Quantity: c.Quantity}
+		}
+		quantities, err := bundles.Scale(l.Quantity, parts)
+		if err != nil {
+			return nil, err
+		}
+		for i, c := range components {
+			if err := add(reservedStock{
+				VariantID: c.ComponentVariantID,
+				Quantity:  quantities[i],
+				Tracked:   c.InventoryTracked,
+				Policy:    inventory.OversellPolicy(c.OversellPolicy),
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	stock := make([]reservedStock, 0, len(needed))
+	for _, s := range needed {
+		stock = append(stock, *s)
+	}
+	slices.SortFunc(stock, func(a, b reservedStock) int {
+		return bytes.Compare(a.VariantID[:], b.VariantID[:])
+	})
+	return stock, nil
+}
+
+// reserveStock locks the stock of s and adds it to reservation. Untracked
+// variants are listed without holding anything.
+func reserveStock(ctx context.Context, q *database.Queries, reservation database.InventoryReservation, s reservedStock) (database.InventoryReservationItem, error) {
+	item, err := q.EnsureInventoryItem(ctx, database.EnsureInventoryItemParams{
+		TenantID:  reservation.TenantID,
+		StoreID:   reservation.StoreID,
+		VariantID: s.VariantID,
+	})
+	if err != nil {
+		return database.InventoryReservationItem{}, err
+	}
+
+	var held, backordered int32
+	if s.Tracked {
+		item, err = q.LockInventoryItem(ctx, item.ID)
+		if err != nil {
+			return database.InventoryReservationItem{}, err
+		}
+		held, backordered, err = inventory.Hold(s.Policy, item.OnHand-item.Reserved, s.Quantity)
+		if err != nil {
+			return database.InventoryReservationItem{}, fmt.Errorf("only %d of variant %s available: %w", max(item.OnHand-item.Reserved, 0), s.VariantID, err)
+		}
+		if held > 0 {
+			_, err = q.ReserveInventoryItem(ctx, database.ReserveInventoryItemParams{
+				Held: held,
+				ID:   item.ID,
+			})
+			if errors.Is(err, sql.ErrNoRows) {
+				return database.InventoryReservationItem{}, inventory.ErrInsufficientStock
+			}
+			if err != nil {
+				return database.InventoryReservationItem{}, err
+			}
+		}
+	}
+
+	return q.CreateInventoryReservationItem(ctx, database.CreateInventoryReservationItemParams{
+		ReservationID:   reservation.ID,
+		InventoryItemID: item.ID,
+		VariantID:       s.VariantID,
+		Quantity:        s.Quantity,
+		Held:            held,
+		Backordered:     backordered,
+	})
+}
+
+// loadStorefrontReservation loads the {reservationID} of the route from the
+// resolved store, hiding a signed-in customer's reservation from anyone
+// else. It has responded when it returns false.
+func (cfg *apiConfig) loadStorefrontReservation(w http.ResponseWriter, r *http.Request) (database.InventoryReservation, bool) {
+	store, _ := middleware.GetResolvedStore(r.Context())
+	reservation, ok := cfg.loadReservation(w, r, store.ID, chi.URLParam(r, "reservationID"))
+	if !ok {
+		return database.InventoryReservation{}, false
+	}
+	if reservation.CustomerID.Valid {
+		customerID, signedIn := customerFromContext(r.Context())
+		if !signedIn || customerID != reservation.CustomerID.UUID {
+			respondWithError(w, http.StatusNotFound, "Reservation not found", nil)
+			return database.InventoryReservation{}, false
+		}
+	}
+	return reservation, true
+}
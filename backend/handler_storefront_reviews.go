@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/notifications"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/reviews"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// StorefrontReviewResponse is an approved review as shoppers see it
+type StorefrontReviewResponse struct {
+	ID         uuid.UUID `json:"id"`
+	AuthorName string    `json:"author_name"`
+	Rating     int32     `json:"rating"`
+	Title      *string   `json:"title,omitempty"`
+	Body       string    `json:"body"`
+	// VerifiedPurchase is true when the author had a paid order with the
+	// product when they wrote the review
+	VerifiedPurchase bool      `json:"verified_purchase"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// StorefrontReviewPageResponse is a page of a product's reviews with its
+// rating over all of them
+type StorefrontReviewPageResponse struct {
+	Rating reviews.Summary            `json:"rating"`
+	Data   []StorefrontReviewResponse `json:"data"`
+	Page   PageInfo                   `json:"page"`
+}
+
+type ReviewCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+}
+
+var reviewCursorCodec = CursorCodec[ReviewCursor]{
+	Validate: func(c ReviewCursor) error {
+		if c.CreatedAt.IsZero() || c.ID == uuid.Nil {
+			return errors.New("invalid cursor: missing required fields")
+		}
+		return nil
+	},
+}
+
+// handlerStorefrontProductReviewsList lists the approved reviews of an
+// active product on the online store, newest first, with its rating
+func (cfg *apiConfig) handlerStorefrontProductReviewsList(w http.ResponseWriter, r *http.Request) {
+	store, _ := middleware.GetResolvedStore(r.Context())
+
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid product ID format", err)
+		return
+	}
+
+	product, err := cfg.storefrontProduct(r.Context(), store.ID, productID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Product not found", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product", err)
+		return
+	}
+
+	pageParams, err := ParsePageParams(r, 20, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+	limit := pageParams.Limit
+
+	cursor, hasCursor, err := reviewCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.reads.ListApprovedProductReviewsPaginated(r.Context(), database.ListApprovedProductReviewsPaginatedParams{
+		ProductID:       product.ID,
+		HasCursor:       hasCursor,
+		CursorCreatedAt: cursor.CreatedAt,
+		CursorID:        cursor.ID,
+		RowLimit:        int32(limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve reviews", err)
+		return
+	}
+	ratings, err := cfg.productRatings(r.Context(), []uuid.UUID{product.ID})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product rating", err)
+		return
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = reviewCursorCodec.Encode(ReviewCursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]StorefrontReviewResponse, 0, len(rows))
+	for _, review := range rows {
+		response = append(response, storefrontReviewToResponse(review))
+	}
+
+	respondWithJSON(w, http.StatusOK, StorefrontReviewPageResponse{
+		Rating: ratings[product.ID],
+		Data:   response,
+		Page:   PageInfo{Limit: limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
+
+// handlerStorefrontProductReviewCreate lets the signed-in customer review
+// an active product once. The review waits for staff to approve it before
+// it is shown or counts towards the product's rating.
+func (cfg *apiConfig) handlerStorefrontProductReviewCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	store, _ := middleware.GetResolvedStore(r.Context())
+	customerID, ok := customerFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid product ID format", err)
+		return
+	}
+
+	type parameters struct {
+		Rating int32  `json:"rating"`
+		Title  string `json:"title"`
+		Body   string `json:"body"`
+		// AuthorName is how the customer wants to be shown, instead of
+		// their first name and last initial
+		AuthorName string `json:"author_name"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	params.Title = strings.TrimSpace(params.Title)
+	params.Body = strings.TrimSpace(params.Body)
+	params.AuthorName = strings.TrimSpace(params.AuthorName)
+	var v validate.Validator
+	v.Between("rating", int64(params.Rating), reviews.MinRating, reviews.MaxRating)
+	v.MaxLength("title", params.Title, reviews.MaxTitleLength)
+	if v.Required("body", params.Body) {
+		v.MaxLength("body", params.Body, reviews.MaxBodyLength)
+	}
+	v.MaxLength("author_name", params.AuthorName, reviews.MaxAuthorNameLength)
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	product, err := cfg.storefrontProduct(r.Context(), store.ID, productID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Product not found", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product", err)
+		return
+	}
+
+	customer, err := cfg.db.GetCustomerByID(r.Context(), database.GetCustomerByIDParams{
+		ID:      customerID,
+		StoreID: store.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve customer", err)
+		return
+	}
+	if params.AuthorName == "" {
+		params.AuthorName = reviews.AuthorName(customer.FirstName.String, customer.LastName.String)
+	}
+
+	purchased, err := cfg.db.CustomerPurchasedProduct(r.Context(), database.CustomerPurchasedProductParams{
+		CustomerID: customer.ID,
+		ProductID:  product.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to check purchase history", err)
+		return
+	}
+
+	review, err := cfg.db.CreateProductReview(r.Context(), database.CreateProductReviewParams{
+		Gid:              sql.NullInt64{Int64: int64(cfg.gidGen.Generate()), Valid: true},
+		StoreID:          store.ID,
+		ProductID:        product.ID,
+		CustomerID:       uuid.NullUUID{UUID: customer.ID, Valid: true},
+		AuthorName:       params.AuthorName,
+		Rating:           params.Rating,
+		Title:            sql.NullString{String: params.Title, Valid: params.Title != ""},
+		Body:             params.Body,
+		VerifiedPurchase: purchased,
+	})
+	if service.UniqueViolation(err, "") {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeAlreadyExists, "You have already reviewed this product", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create review", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "product review submitted",
+		"request_id", reqID,
+		"store_id", store.ID,
+		"customer_id", customer.ID,
+		"product_id", product.ID,
+		"review_id", review.ID,
+		"verified_purchase", review.VerifiedPurchase,
+	)
+
+	if store.TenantID.Valid {
+		cfg.notify(r.Context(), notifications.Notification{
+			TenantID: store.TenantID.UUID,
+			StoreID:  store.ID,
+			Kind:     notifications.KindNewReview,
+			Title:    fmt.Sprintf("New %d-star review of %s", review.Rating, product.Name),
+			Body:     "It waits for approval before it is shown.",
+			Data:     map[string]any{"review_id": review.ID, "product_id": product.ID},
+		})
+	}
+
+	respondWithJSON(w, http.StatusCreated, productReviewToResponse(review))
+}
+
+// rateStorefrontProducts sets the rating of each product
+func (cfg *apiConfig) rateStorefrontProducts(ctx context.Context, products []StorefrontProductResponse) error {
+	ids := make([]uuid.UUID, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+	ratings, err := cfg.productRatings(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range products {
+		rating := ratings[products[i].ID]
+		products[i].Rating = &rating
+	}
+	return nil
+}
+
+// productRatings batch-loads the rating of each product over its approved
+// reviews. Products without any have a zero summary.
+func (cfg *apiConfig) productRatings(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID]reviews.Summary, error) {
+	ratings := make(map[uuid.UUID]reviews.Summary, len(productIDs))
+	if len(productIDs) == 0 {
+		return ratings, nil
+	}
+
+	rows, err := cfg.reads.GetProductRatings(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range productIDs {
+		ratings[id] = reviews.Summary{}
+	}
+	for _, row := range rows {
+		ratings[row.ProductID] = reviews.Summarize(row.ReviewCount, row.RatingTotal)
+	}
+	return ratings, nil
+}
+
+func storefrontReviewToResponse(review database.ProductReview) StorefrontReviewResponse {
+	resp := StorefrontReviewResponse{
+		ID:               review.ID,
+		AuthorName:       review.AuthorName,
+		Rating:           review.Rating,
+		Body:             review.Body,
+		VerifiedPurchase: review.VerifiedPurchase,
+		CreatedAt:        review.CreatedAt,
+	}
+	if review.Title.Valid {
+		resp.Title = &review.Title.String
+	}
+	return resp
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/dfodeker/terminus/internal/address"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/shipping"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/google/uuid"
+)
+
+// maxQuoteItems bounds the cart size a rate quote accepts
+const maxQuoteItems = 250
+
+type ShippingOptionResponse struct {
+	RateID     uuid.UUID `json:"rate_id"`
+	Name       string    `json:"name"`
+	PriceCents int64     `json:"price_cents"`
+}
+
+type ShippingQuoteResponse struct {
+	// ZoneID is nil when the store doesn't ship to the destination
+	ZoneID        *uuid.UUID               `json:"zone_id"`
+	SubtotalCents int64                    `json:"subtotal_cents"`
+	WeightGrams   int64                    `json:"weight_grams"`
+	Options       []ShippingOptionResponse `json:"options"`
+}
+
+// handlerStorefrontShippingQuote prices shipping for a cart. Prices and
+// weights come from the catalog, not the request, so checkout can trust them.
+func (cfg *apiConfig) handlerStorefrontShippingQuote(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	store, _ := middleware.GetResolvedStore(r.Context())
+
+	type item struct {
+		VariantID uuid.UUID `json:"variant_id"`
+		Quantity  int64     `json:"quantity"`
+	}
+	type parameters struct {
+		Destination struct {
+			Country string `json:"country"`
+			Region  string `json:"region"`
+		} `json:"destination"`
+		Items []item `json:"items"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	country, err := address.NormalizeCountry(params.Destination.Country)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	if len(params.Items) == 0 {
+		respondWithError(w, http.StatusBadRequest, "At least one item is required", nil)
+		return
+	}
+	if len(params.Items) > maxQuoteItems {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("At most %d items are allowed", maxQuoteItems), nil)
+		return
+	}
+
+	quantities := make(map[uuid.UUID]int64, len(params.Items))
+	variantIDs := make([]uuid.UUID, 0, len(params.Items))
+	for _, it := range params.Items {
+		if it.Quantity <= 0 {
+			respondWithError(w, http.StatusBadRequest, "Item quantities must be positive", nil)
+			return
+		}
+		if _, seen := quantities[it.VariantID]; !seen {
+			variantIDs = append(variantIDs, it.VariantID)
+		}
+		quantities[it.VariantID] += it.Quantity
+	}
+
+	variants, err := cfg.db.GetActiveVariantsForQuote(r.Context(), database.GetActiveVariantsForQuoteParams{
+		StoreID:    store.ID,
+		VariantIds: variantIDs,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve variants", err)
+		return
+	}
+	if len(variants) != len(variantIDs) {
+		respondWithError(w, http.StatusUnprocessableEntity, "One or more items are not available", nil)
+		return
+	}
+
+	// A signed-in customer's cart is at their price list prices
+	retail := make(map[uuid.UUID]int64, len(variants))
+	for _, v := range variants {
+		retail[v.ID] = int64(v.PriceCents)
+	}
+	prices, err := cfg.customerPrices(r.Context(), store.ID, retail)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve customer prices", err)
+		return
+	}
+
+	var cart shipping.Cart
+	for _, v := range variants {
+		qty := quantities[v.ID]
+		cart.SubtotalCents += prices.Price(v.ID, int64(v.PriceCents)) * qty
+		cart.WeightGrams += int64(v.WeightGrams) * qty
+	}
+
+	resp := ShippingQuoteResponse{
+		SubtotalCents: cart.SubtotalCents,
+		WeightGrams:   cart.WeightGrams,
+		Options:       []ShippingOptionResponse{},
+	}
+
+	zones, err := cfg.db.GetShippingZonesByStore(r.Context(), store.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve shipping zones", err)
+		return
+	}
+
+	candidates := make([]shipping.Zone, 0, len(zones))
+	for _, zone := range zones {
+		candidates = append(candidates, shipping.Zone{ID: zone.ID, Name: zone.Name, Regions: zone.Regions})
+	}
+
+	zone, ok := shipping.MatchZone(candidates, shipping.Destination{
+		Country: country,
+		Region:  params.Destination.Region,
+	})
+	if ok {
+		rows, err := cfg.db.GetShippingRatesByZone(r.Context(), zone.ID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve shipping rates", err)
+			return
+		}
+
+		rates := make([]shipping.Rate, 0, len(rows))
+		for _, row := range rows {
+			rates = append(rates, shippingRateFromRow(row))
+		}
+
+		resp.ZoneID = &zone.ID
+		for _, option := range shipping.Quote(rates, cart) {
+			resp.Options = append(resp.Options, ShippingOptionResponse{
+				RateID:     option.RateID,
+				Name:       option.Name,
+				PriceCents: option.PriceCents,
+			})
+		}
+	}
+
+	slog.InfoContext(r.Context(), "storefront shipping quote",
+		"request_id", reqID,
+		"store_id", store.ID,
+		"country", country,
+		"weight_grams", cart.WeightGrams,
+		"option_count", len(resp.Options),
+	)
+
+	respondWithJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/subscriptions"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxPaymentMethodRefLength bounds the provider reference of a saved
+// payment method
+const maxPaymentMethodRefLength = 255
+
+// StorefrontSellingPlanResponse is a way a shopper can subscribe to a
+// product
+type StorefrontSellingPlanResponse struct {
+	ID            uuid.UUID `json:"id"`
+	Name          string    `json:"name"`
+	IntervalUnit  string    `json:"interval_unit"`
+	IntervalCount int32     `json:"interval_count"`
+	PercentOff    int32     `json:"percent_off"`
+}
+
+// handlerStorefrontProductSellingPlans lists the selling plans an active
+// product on the online store can be subscribed to
+func (cfg *apiConfig) handlerStorefrontProductSellingPlans(w http.ResponseWriter, r *http.Request) {
+	store, _ := middleware.GetResolvedStore(r.Context())
+
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid product ID format", err)
+		return
+	}
+
+	product, err := cfg.storefrontProduct(r.Context(), store.ID, productID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Product not found", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product", err)
+		return
+	}
+
+	plans, err := cfg.reads.ListProductSellingPlans(r.Context(), product.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve selling plans", err)
+		return
+	}
+
+	response := make([]StorefrontSellingPlanResponse, 0, len(plans))
+	for _, plan := range plans {
+		response = append(response, StorefrontSellingPlanResponse{
+			ID:            plan.ID,
+			Name:          plan.Name,
+			IntervalUnit:  plan.IntervalUnit,
+			IntervalCount: plan.IntervalCount,
+			PercentOff:    plan.PercentOff,
+		})
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{"data": response})
+}
+
+// handlerStorefrontSubscriptionsList lists the signed-in customer's
+// subscriptions, newest first
+func (cfg *apiConfig) handlerStorefrontSubscriptionsList(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := customerFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	rows, err := cfg.db.ListCustomerSubscriptionContracts(r.Context(), customerID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve subscriptions", err)
+		return
+	}
+
+	response := make([]SubscriptionResponse, 0, len(rows))
+	for _, c := range rows {
+		response = append(response, subscriptionToResponse(c))
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{"data": response})
+}
+
+// handlerStorefrontSubscriptionCreate subscribes the signed-in customer to a
+// variant on one of its product's selling plans, charged to a payment
+// method saved with the provider. The first order is placed by the next
+// billing run, and the rest every interval after it.
+func (cfg *apiConfig) handlerStorefrontSubscriptionCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	store, _ := middleware.GetResolvedStore(r.Context())
+	customerID, ok := customerFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	type parameters struct {
+		VariantID     uuid.UUID `json:"variant_id"`
+		SellingPlanID uuid.UUID `json:"selling_plan_id"`
+		Quantity      *int32    `json:"quantity"`
+		PaymentMethod struct {
+			Provider  string `json:"provider"`
+			Reference string `json:"reference"`
+		} `json:"payment_method"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	quantity := int32(1)
+	if params.Quantity != nil {
+		quantity = *params.Quantity
+	}
+	provider := strings.ToLower(strings.TrimSpace(params.PaymentMethod.Provider))
+	reference := strings.TrimSpace(params.PaymentMethod.Reference)
+
+	var v validate.Validator
+	v.Check(params.VariantID != uuid.Nil, "variant_id", validate.CodeRequired, "is required")
+	v.Check(params.SellingPlanID != uuid.Nil, "selling_plan_id", validate.CodeRequired, "is required")
+	v.Between("quantity", int64(quantity), 1, subscriptions.MaxQuantity)
+	if v.Required("payment_method.provider", provider) {
+		v.OneOf("payment_method.provider", provider, cfg.payments.Providers()...)
+	}
+	if v.Required("payment_method.reference", reference) {
+		v.MaxLength("payment_method.reference", reference, maxPaymentMethodRefLength)
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	variant, err := cfg.db.GetProductVariantByID(r.Context(), params.VariantID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && (variant.StoreID != store.ID || variant.Status != "active")) {
+		v.Fail("variant_id", validate.CodeInvalid, "does not exist")
+		respondWithValidationError(w, v.Err())
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve variant", err)
+		return
+	}
+	product, err := cfg.storefrontProduct(r.Context(), store.ID, variant.ProductID)
+	if errors.Is(err, sql.ErrNoRows) {
+		v.Fail("variant_id", validate.CodeInvalid, "does not exist")
+		respondWithValidationError(w, v.Err())
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product", err)
+		return
+	}
+	plans, err := cfg.db.ListProductSellingPlans(r.Context(), product.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve selling plans", err)
+		return
+	}
+	var plan *database.SellingPlan
+	for i := range plans {
+		if plans[i].ID == params.SellingPlanID {
+			plan = &plans[i]
+		}
+	}
+	if plan == nil {
+		v.Fail("selling_plan_id", validate.CodeInvalid, "is not offered on this product")
+		respondWithValidationError(w, v.Err())
+		return
+	}
+
+	// The plan discount comes off what this customer pays today, price
+	// lists included, and is locked in for every renewal
+	base := int64(variant.PriceCents)
+	prices, err := cfg.customerPrices(r.Context(), store.ID, map[uuid.UUID]int64{variant.ID: base})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to price subscription", err)
+		return
+	}
+	price := subscriptions.Price(prices.Price(variant.ID, base), int(plan.PercentOff))
+
+	contract, err := cfg.db.CreateSubscriptionContract(r.Context(), database.CreateSubscriptionContractParams{
+		Gid:              service.NewGID(cfg.gidGen),
+		TenantID:         store.TenantID.UUID,
+		StoreID:          store.ID,
+		CustomerID:       customerID,
+		SellingPlanID:    uuid.NullUUID{UUID: plan.ID, Valid: true},
+		VariantID:        uuid.NullUUID{UUID: variant.ID, Valid: true},
+		Quantity:         quantity,
+		PriceCents:       price,
+		Currency:         store.DefaultCurrency,
+		IntervalUnit:     plan.IntervalUnit,
+		IntervalCount:    plan.IntervalCount,
+		PaymentProvider:  provider,
+		PaymentMethodRef: reference,
+		BillingAnchorAt:  time.Now(),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create subscription", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "subscription created",
+		"request_id", reqID,
+		"store_id", store.ID,
+		"customer_id", customerID,
+		"subscription_id", contract.ID,
+		"selling_plan_id", plan.ID,
+	)
+
+	respondWithJSON(w, http.StatusCreated, subscriptionToResponse(contract))
+}
+
+// handlerStorefrontSubscriptionGet returns one of the signed-in customer's
+// subscriptions
+func (cfg *apiConfig) handlerStorefrontSubscriptionGet(w http.ResponseWriter, r *http.Request) {
+	contract, ok := cfg.loadCustomerSubscription(w, r)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, subscriptionToResponse(contract))
+}
+
+// handlerStorefrontSubscriptionPause stops one of the signed-in customer's
+// subscriptions renewing until they resume it
+func (cfg *apiConfig) handlerStorefrontSubscriptionPause(w http.ResponseWriter, r *http.Request) {
+	cfg.storefrontSubscriptionTransition(w, r, subscriptions.StatusPaused)
+}
+
+// handlerStorefrontSubscriptionResume starts a paused or failed
+// subscription renewing again
+func (cfg *apiConfig) handlerStorefrontSubscriptionResume(w http.ResponseWriter, r *http.Request) {
+	cfg.storefrontSubscriptionTransition(w, r, subscriptions.StatusActive)
+}
+
+// handlerStorefrontSubscriptionCancel ends one of the signed-in customer's
+// subscriptions
+func (cfg *apiConfig) handlerStorefrontSubscriptionCancel(w http.ResponseWriter, r *http.Request) {
+	cfg.storefrontSubscriptionTransition(w, r, subscriptions.StatusCancelled)
+}
+
+func (cfg *apiConfig) storefrontSubscriptionTransition(w http.ResponseWriter, r *http.Request, to subscriptions.Status) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	existing, ok := cfg.loadCustomerSubscription(w, r)
+	if !ok {
+		return
+	}
+
+	contract, err := cfg.transitionSubscription(r.Context(), existing, to)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeInvalidStatusTransition, "Subscription cannot be "+subscriptionTransitionVerb(to)+" while "+existing.Status, nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update subscription", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "subscription "+subscriptionTransitionVerb(to),
+		"request_id", reqID,
+		"store_id", contract.StoreID,
+		"customer_id", contract.CustomerID,
+		"subscription_id", contract.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, subscriptionToResponse(contract))
+}
+
+// loadCustomerSubscription loads the {subscriptionID} of the route if it
+// belongs to the signed-in customer. It has responded when it returns
+// false.
+func (cfg *apiConfig) loadCustomerSubscription(w http.ResponseWriter, r *http.Request) (database.SubscriptionContract, bool) {
+	customerID, ok := customerFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return database.SubscriptionContract{}, false
+	}
+
+	contractID, err := uuid.Parse(chi.URLParam(r, "subscriptionID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid subscription ID format", err)
+		return database.SubscriptionContract{}, false
+	}
+
+	// Shoppers only ever see their own subscriptions
+	contract, err := cfg.db.GetCustomerSubscriptionContract(r.Context(), database.GetCustomerSubscriptionContractParams{
+		ID:         contractID,
+		CustomerID: customerID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Subscription not found", nil)
+		return database.SubscriptionContract{}, false
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve subscription", err)
+		return database.SubscriptionContract{}, false
+	}
+	return contract, true
+}
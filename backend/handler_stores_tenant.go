@@ -1,7 +1,6 @@
 package main
 
 import (
-	"database/sql"
 	"encoding/json"
 	"errors"
 	"log/slog"
@@ -9,6 +8,8 @@ import (
 	"time"
 
 	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/service/stores"
 	"github.com/dfodeker/terminus/middleware"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -22,10 +23,13 @@ type TenantStoreResponse struct {
 	Address         string     `json:"address"`
 	Status          string     `json:"status"`
 	DefaultCurrency string     `json:"default_currency"`
+	DefaultLocale   string     `json:"default_locale"`
 	Timezone        string     `json:"timezone"`
 	Plan            string     `json:"plan"`
 	CreatedAt       time.Time  `json:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at"`
+	// DeletedAt is set on deleted stores waiting to be purged
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 type StoreCursor struct {
@@ -56,62 +60,8 @@ func (cfg *apiConfig) handlerTenantStoresCreate(w http.ResponseWriter, r *http.R
 		"tenant_param", tenantParam,
 	)
 
-	user, ok := userFromContext(r.Context())
-	if !ok {
-		slog.WarnContext(r.Context(), "store creation failed: no authenticated user",
-			"request_id", reqID,
-		)
-		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
-		return
-	}
-
-	tenantID, err := uuid.Parse(tenantParam)
-	if err != nil {
-		slog.WarnContext(r.Context(), "store creation failed: invalid tenant ID format",
-			"request_id", reqID,
-			"user_id", user,
-			"tenant_param", tenantParam,
-			"error", err,
-		)
-		respondWithError(w, http.StatusBadRequest, "Invalid tenant ID format", err)
-		return
-	}
-
-	// Verify user is a member of the tenant
-	tenantUser, err := cfg.db.GetTenantUser(r.Context(), database.GetTenantUserParams{
-		TenantID: tenantID,
-		UserID:   user,
-	})
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			slog.WarnContext(r.Context(), "store creation denied: user not a member of tenant",
-				"request_id", reqID,
-				"user_id", user,
-				"tenant_id", tenantID,
-			)
-			respondWithError(w, http.StatusForbidden, "You are not a member of this tenant", nil)
-			return
-		}
-		slog.ErrorContext(r.Context(), "store creation failed: error checking tenant membership",
-			"request_id", reqID,
-			"user_id", user,
-			"tenant_id", tenantID,
-			"error", err,
-		)
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify tenant membership", err)
-		return
-	}
-
-	if tenantUser.Status != "active" {
-		slog.WarnContext(r.Context(), "store creation denied: user membership not active",
-			"request_id", reqID,
-			"user_id", user,
-			"tenant_id", tenantID,
-			"membership_status", tenantUser.Status,
-		)
-		respondWithError(w, http.StatusForbidden, "Your membership in this tenant is not active", nil)
-		return
-	}
+	tc := tenantContextFrom(r)
+	user, tenantID := tc.Membership.UserID, tc.Tenant.ID
 
 	type parameters struct {
 		Name   string `json:"name"`
@@ -121,7 +71,7 @@ func (cfg *apiConfig) handlerTenantStoresCreate(w http.ResponseWriter, r *http.R
 
 	decoder := json.NewDecoder(r.Body)
 	params := parameters{}
-	err = decoder.Decode(&params)
+	err := decoder.Decode(&params)
 	if err != nil {
 		slog.WarnContext(r.Context(), "store creation failed: invalid request body",
 			"request_id", reqID,
@@ -133,20 +83,6 @@ func (cfg *apiConfig) handlerTenantStoresCreate(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	if params.Name == "" {
-		respondWithError(w, http.StatusBadRequest, "Store name is required", nil)
-		return
-	}
-	if params.Handle == "" {
-		respondWithError(w, http.StatusBadRequest, "Store handle is required", nil)
-		return
-	}
-
-	plan := params.Plan
-	if plan == "" {
-		plan = "free"
-	}
-
 	slog.DebugContext(r.Context(), "store creation: creating store in database",
 		"request_id", reqID,
 		"user_id", user,
@@ -155,20 +91,30 @@ func (cfg *apiConfig) handlerTenantStoresCreate(w http.ResponseWriter, r *http.R
 		"store_handle", params.Handle,
 	)
 
-	store, err := cfg.db.CreateStoreForTenant(r.Context(), database.CreateStoreForTenantParams{
+	if err := cfg.services.entitlements.CheckStores(r.Context(), tenantID); err != nil {
+		respondWithServiceError(w, err, "Unable to create store")
+		return
+	}
+	plan, err := cfg.storePlan(r.Context(), tenantID, params.Plan)
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to create store")
+		return
+	}
+
+	store, err := cfg.services.stores.Create(r.Context(), stores.CreateInput{
+		TenantID: tenantID,
 		Name:     params.Name,
 		Handle:   params.Handle,
 		Plan:     plan,
-		TenantID: uuid.NullUUID{UUID: tenantID, Valid: true},
 	})
 	if err != nil {
-		slog.ErrorContext(r.Context(), "store creation failed: database error",
+		slog.ErrorContext(r.Context(), "store creation failed",
 			"request_id", reqID,
 			"user_id", user,
 			"tenant_id", tenantID,
 			"error", err,
 		)
-		respondWithError(w, http.StatusBadRequest, "Unable to create store", err)
+		respondWithServiceError(w, err, "Unable to create store")
 		return
 	}
 
@@ -180,12 +126,130 @@ func (cfg *apiConfig) handlerTenantStoresCreate(w http.ResponseWriter, r *http.R
 		"store_handle", store.Handle,
 	)
 
+	respondWithJSON(w, http.StatusCreated, tenantStoreToResponse(store))
+}
+
+// handlerTenantStoreUpdate changes a store's settings. Fields left out of
+// the body keep their value.
+func (cfg *apiConfig) handlerTenantStoreUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	type parameters struct {
+		Name            *string `json:"name"`
+		Address         *string `json:"address"`
+		DefaultCurrency *string `json:"default_currency"`
+		DefaultLocale   *string `json:"default_locale"`
+		Timezone        *string `json:"timezone"`
+		Plan            *string `json:"plan"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	// The audit log records what the update changed
+	existing, err := cfg.db.GetStoreByTenantAndID(r.Context(), database.GetStoreByTenantAndIDParams{
+		TenantID: uuid.NullUUID{UUID: access.TenantID, Valid: true},
+		ID:       access.StoreID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update store", err)
+		return
+	}
+	if params.Plan != nil {
+		plan, err := cfg.storePlan(r.Context(), access.TenantID, *params.Plan)
+		if err != nil {
+			respondWithServiceError(w, err, "Unable to update store")
+			return
+		}
+		params.Plan = &plan
+	}
+
+	store, err := cfg.services.stores.Update(r.Context(), stores.UpdateInput{
+		TenantID:        access.TenantID,
+		ID:              access.StoreID,
+		Name:            params.Name,
+		Address:         params.Address,
+		DefaultCurrency: params.DefaultCurrency,
+		DefaultLocale:   params.DefaultLocale,
+		Timezone:        params.Timezone,
+		Plan:            params.Plan,
+	})
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to update store")
+		return
+	}
+	auditChange(r, auditGID(gid.EntityStore, store.Gid), tenantStoreToResponse(existing), tenantStoreToResponse(store))
+
+	slog.InfoContext(r.Context(), "store updated",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"store_id", store.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, tenantStoreToResponse(store))
+}
+
+// handlerTenantStoreStatus pauses, closes or reopens a store. Only active
+// stores are served on their storefront.
+func (cfg *apiConfig) handlerTenantStoreStatus(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	type parameters struct {
+		Status string `json:"status"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	existing, err := cfg.db.GetStoreByTenantAndID(r.Context(), database.GetStoreByTenantAndIDParams{
+		TenantID: uuid.NullUUID{UUID: access.TenantID, Valid: true},
+		ID:       access.StoreID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to change store status", err)
+		return
+	}
+
+	store, err := cfg.services.stores.SetStatus(r.Context(), access.TenantID, access.StoreID, params.Status)
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to change store status")
+		return
+	}
+	auditChange(r, auditGID(gid.EntityStore, store.Gid), tenantStoreToResponse(existing), tenantStoreToResponse(store))
+
+	slog.InfoContext(r.Context(), "store status changed",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"store_id", store.ID,
+		"from", existing.Status,
+		"to", store.Status,
+	)
+
+	respondWithJSON(w, http.StatusOK, tenantStoreToResponse(store))
+}
+
+func tenantStoreToResponse(store database.Store) TenantStoreResponse {
 	var tenantIDPtr *uuid.UUID
 	if store.TenantID.Valid {
 		tenantIDPtr = &store.TenantID.UUID
 	}
-
-	respondWithJSON(w, http.StatusCreated, TenantStoreResponse{
+	var deletedAt *time.Time
+	if store.DeletedAt.Valid {
+		deletedAt = &store.DeletedAt.Time
+	}
+	return TenantStoreResponse{
 		ID:              store.ID,
 		TenantID:        tenantIDPtr,
 		Name:            store.Name,
@@ -193,11 +257,13 @@ func (cfg *apiConfig) handlerTenantStoresCreate(w http.ResponseWriter, r *http.R
 		Address:         store.Address,
 		Status:          store.Status,
 		DefaultCurrency: store.DefaultCurrency,
+		DefaultLocale:   store.DefaultLocale,
 		Timezone:        store.Timezone,
 		Plan:            store.Plan,
 		CreatedAt:       store.CreatedAt,
 		UpdatedAt:       store.UpdatedAt,
-	})
+		DeletedAt:       deletedAt,
+	}
 }
 
 func (cfg *apiConfig) handlerTenantStoresList(w http.ResponseWriter, r *http.Request) {
@@ -209,62 +275,8 @@ func (cfg *apiConfig) handlerTenantStoresList(w http.ResponseWriter, r *http.Req
 		"tenant_param", tenantParam,
 	)
 
-	user, ok := userFromContext(r.Context())
-	if !ok {
-		slog.WarnContext(r.Context(), "tenant stores list failed: no authenticated user",
-			"request_id", reqID,
-		)
-		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
-		return
-	}
-
-	tenantID, err := uuid.Parse(tenantParam)
-	if err != nil {
-		slog.WarnContext(r.Context(), "tenant stores list failed: invalid tenant ID format",
-			"request_id", reqID,
-			"user_id", user,
-			"tenant_param", tenantParam,
-			"error", err,
-		)
-		respondWithError(w, http.StatusBadRequest, "Invalid tenant ID format", err)
-		return
-	}
-
-	// Verify user is a member of the tenant
-	tenantUser, err := cfg.db.GetTenantUser(r.Context(), database.GetTenantUserParams{
-		TenantID: tenantID,
-		UserID:   user,
-	})
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			slog.WarnContext(r.Context(), "tenant stores list denied: user not a member of tenant",
-				"request_id", reqID,
-				"user_id", user,
-				"tenant_id", tenantID,
-			)
-			respondWithError(w, http.StatusForbidden, "You are not a member of this tenant", nil)
-			return
-		}
-		slog.ErrorContext(r.Context(), "tenant stores list failed: error checking tenant membership",
-			"request_id", reqID,
-			"user_id", user,
-			"tenant_id", tenantID,
-			"error", err,
-		)
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify tenant membership", err)
-		return
-	}
-
-	if tenantUser.Status != "active" {
-		slog.WarnContext(r.Context(), "tenant stores list denied: user membership not active",
-			"request_id", reqID,
-			"user_id", user,
-			"tenant_id", tenantID,
-			"membership_status", tenantUser.Status,
-		)
-		respondWithError(w, http.StatusForbidden, "Your membership in this tenant is not active", nil)
-		return
-	}
+	tc := tenantContextFrom(r)
+	user, tenantID := tc.Membership.UserID, tc.Tenant.ID
 
 	pageParams, err := ParsePageParams(r, defaultStoreLimit, maxStoreLimit)
 	if err != nil {
@@ -279,7 +291,6 @@ func (cfg *apiConfig) handlerTenantStoresList(w http.ResponseWriter, r *http.Req
 	}
 
 	limit := pageParams.Limit
-	limitPlusOne := int32(pageParams.Limit + 1)
 
 	cursorCreatedAt, cursorID, hasCursor, err := decodeStoreCursor(pageParams.Cursor)
 	if err != nil {
@@ -301,13 +312,7 @@ func (cfg *apiConfig) handlerTenantStoresList(w http.ResponseWriter, r *http.Req
 		"has_cursor", hasCursor,
 	)
 
-	rows, err := cfg.db.GetStoresByTenantIDPaginated(r.Context(), database.GetStoresByTenantIDPaginatedParams{
-		TenantID: uuid.NullUUID{UUID: tenantID, Valid: true},
-		Column2:  hasCursor,
-		Column3:  cursorCreatedAt,
-		Column4:  cursorID,
-		Limit:    limitPlusOne,
-	})
+	page, err := cfg.services.stores.List(r.Context(), tenantID, pageRequest(limit, cursorCreatedAt, cursorID, hasCursor))
 	if err != nil {
 		slog.ErrorContext(r.Context(), "tenant stores list failed: database query error",
 			"request_id", reqID,
@@ -319,10 +324,7 @@ func (cfg *apiConfig) handlerTenantStoresList(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	hasMore := len(rows) > limit
-	if hasMore {
-		rows = rows[:limit]
-	}
+	rows, hasMore := page.Items, page.HasMore
 
 	var nextCursor string
 	if hasMore && len(rows) > 0 {
@@ -357,6 +359,7 @@ func (cfg *apiConfig) handlerTenantStoresList(w http.ResponseWriter, r *http.Req
 			Address:         store.Address,
 			Status:          store.Status,
 			DefaultCurrency: store.DefaultCurrency,
+			DefaultLocale:   store.DefaultLocale,
 			Timezone:        store.Timezone,
 			Plan:            store.Plan,
 			CreatedAt:       store.CreatedAt,
@@ -372,16 +375,27 @@ func (cfg *apiConfig) handlerTenantStoresList(w http.ResponseWriter, r *http.Req
 		"has_more", hasMore,
 	)
 
-	respondWithJSON(w, http.StatusOK, map[string]any{
-		"data": response,
-		"page": map[string]any{
-			"limit":       limit,
-			"has_more":    hasMore,
-			"next_cursor": nextCursor,
-		},
+	respondWithJSON(w, http.StatusOK, PageResponse[TenantStoreResponse]{
+		Data: response,
+		Page: PageInfo{Limit: limit, NextCursor: nextCursor, HasMore: hasMore},
 	})
 }
 
+// handlerTenantStoreHandleAvailability checks whether ?handle= is free in
+// the tenant, or with ?name= the handle a store of that name would get
+func (cfg *apiConfig) handlerTenantStoreHandleAvailability(w http.ResponseWriter, r *http.Request) {
+	tenantID := tenantContextFrom(r).Tenant.ID
+	query := r.URL.Query()
+
+	availability, err := cfg.services.stores.CheckHandle(r.Context(), tenantID, query.Get("handle"), query.Get("name"))
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to check handle")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, HandleAvailabilityResponse(availability))
+}
+
 func decodeStoreCursor(cursor string) (time.Time, uuid.UUID, bool, error) {
 	cur, ok, err := storeCursorCodec.Decode(cursor)
 	if err != nil {
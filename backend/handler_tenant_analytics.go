@@ -0,0 +1,299 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/analytics"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/google/uuid"
+)
+
+// SalesReportPeriod is the sales of one period of a report
+type SalesReportPeriod struct {
+	PeriodStart   string `json:"period_start,omitempty"`
+	Orders        int64  `json:"orders"`
+	Units         int64  `json:"units"`
+	SubtotalCents int64  `json:"subtotal_cents"`
+	DiscountCents int64  `json:"discount_cents"`
+	ShippingCents int64  `json:"shipping_cents"`
+	TaxCents      int64  `json:"tax_cents"`
+	TotalCents    int64  `json:"total_cents"`
+	// RefundedCents counts refunds on the day they were made
+	RefundedCents          int64 `json:"refunded_cents"`
+	NetCents               int64 `json:"net_cents"`
+	AverageOrderValueCents int64 `json:"average_order_value_cents"`
+}
+
+// TopProductResponse is a product's sales over a report's range
+type TopProductResponse struct {
+	ProductID    uuid.UUID `json:"product_id"`
+	ProductName  string    `json:"product_name"`
+	Units        int64     `json:"units"`
+	Orders       int64     `json:"orders"`
+	RevenueCents int64     `json:"revenue_cents"`
+}
+
+// ChannelConversionPeriod is a channel's conversion over one period
+type ChannelConversionPeriod struct {
+	PeriodStart    string  `json:"period_start,omitempty"`
+	Checkouts      int64   `json:"checkouts"`
+	Converted      int64   `json:"converted"`
+	ConversionRate float64 `json:"conversion_rate"`
+	Orders         int64   `json:"orders"`
+}
+
+// ChannelConversionResponse is a sales channel's conversion over a
+// report's range
+type ChannelConversionResponse struct {
+	ChannelID uuid.UUID                 `json:"channel_id"`
+	Name      string                    `json:"name"`
+	Kind      string                    `json:"kind"`
+	Totals    ChannelConversionPeriod   `json:"totals"`
+	Periods   []ChannelConversionPeriod `json:"periods"`
+}
+
+// analyticsQuery is what every report takes: the days, how to group them
+// and, for money, the currency
+type analyticsQuery struct {
+	store       database.Store
+	rng         analytics.Range
+	granularity analytics.Granularity
+	currency    string
+	// rolledThrough is the last day with rollups, empty before the first
+	rolledThrough string
+}
+
+// parseAnalyticsQuery reads ?from= and ?to= as dates in the store
+// timezone, ?granularity= (day, week or month, day by default) and
+// ?currency=, the store default currency unless given
+func (cfg *apiConfig) parseAnalyticsQuery(w http.ResponseWriter, r *http.Request) (analyticsQuery, bool) {
+	store, err := cfg.reads.GetStoreByID(r.Context(), tenantAccessFrom(r).StoreID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve store", err)
+		return analyticsQuery{}, false
+	}
+	loc, err := time.LoadLocation(store.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	query := r.URL.Query()
+	q := analyticsQuery{
+		store:       store,
+		granularity: analytics.Granularity(query.Get("granularity")),
+		currency:    strings.ToUpper(strings.TrimSpace(query.Get("currency"))),
+	}
+	if q.granularity == "" {
+		q.granularity = analytics.Day
+	}
+	if q.currency == "" {
+		q.currency = store.DefaultCurrency
+	}
+
+	var v validate.Validator
+	v.OneOf("granularity", string(q.granularity), analytics.Granularities...)
+	q.rng, err = analytics.ParseRange(query.Get("from"), query.Get("to"), analytics.Date(time.Now(), loc))
+	if err != nil {
+		v.Fail("range", validate.CodeInvalid, err.Error())
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return analyticsQuery{}, false
+	}
+
+	rollup, err := cfg.reads.GetAnalyticsRollup(r.Context(), store.ID)
+	if err == nil && rollup.RolledThrough.Valid {
+		q.rolledThrough = rollup.RolledThrough.Time.Format(analytics.DateLayout)
+	}
+	return q, true
+}
+
+// meta is what every report response says about how it was built
+func (q analyticsQuery) meta() map[string]any {
+	return map[string]any{
+		"from":           q.rng.From.Format(analytics.DateLayout),
+		"to":             q.rng.To.Format(analytics.DateLayout),
+		"granularity":    q.granularity,
+		"timezone":       q.store.Timezone,
+		"rolled_through": q.rolledThrough,
+	}
+}
+
+// handlerTenantAnalyticsSales reports orders, units, takings, refunds and
+// average order value over time in one currency. The currencies the store
+// sold in over the range are listed, so other ones can be asked for.
+func (cfg *apiConfig) handlerTenantAnalyticsSales(w http.ResponseWriter, r *http.Request) {
+	q, ok := cfg.parseAnalyticsQuery(w, r)
+	if !ok {
+		return
+	}
+
+	rows, err := cfg.reads.GetAnalyticsDailySales(r.Context(), database.GetAnalyticsDailySalesParams{
+		StoreID:  q.store.ID,
+		Currency: q.currency,
+		FromDay:  q.rng.From,
+		ToDay:    q.rng.To,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve sales", err)
+		return
+	}
+	currencies, err := cfg.reads.GetAnalyticsSalesCurrencies(r.Context(), database.GetAnalyticsSalesCurrenciesParams{
+		StoreID: q.store.ID,
+		FromDay: q.rng.From,
+		ToDay:   q.rng.To,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve sales", err)
+		return
+	}
+
+	periods, totals := analytics.BucketSales(q.granularity, q.rng, rows)
+	data := make([]SalesReportPeriod, 0, len(periods))
+	for _, p := range periods {
+		data = append(data, salesReportPeriod(p.Start, p.Sales))
+	}
+
+	resp := q.meta()
+	resp["currency"] = q.currency
+	resp["currencies"] = currencies
+	resp["totals"] = salesReportPeriod(time.Time{}, totals)
+	resp["data"] = data
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerTenantAnalyticsProducts reports the best selling products over the
+// range in one currency, by revenue or with ?sort=units by units sold.
+// ?limit= takes up to 100, 10 by default.
+func (cfg *apiConfig) handlerTenantAnalyticsProducts(w http.ResponseWriter, r *http.Request) {
+	q, ok := cfg.parseAnalyticsQuery(w, r)
+	if !ok {
+		return
+	}
+
+	sort := r.URL.Query().Get("sort")
+	if sort == "" {
+		sort = "revenue"
+	}
+	limit := 10
+	var v validate.Validator
+	v.OneOf("sort", sort, "revenue", "units")
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if v.Check(err == nil, "limit", validate.CodeFormat, "must be a number") {
+			v.Between("limit", int64(n), 1, 100)
+			limit = n
+		}
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	rows, err := cfg.reads.GetAnalyticsTopProducts(r.Context(), database.GetAnalyticsTopProductsParams{
+		StoreID:  q.store.ID,
+		Currency: q.currency,
+		FromDay:  q.rng.From,
+		ToDay:    q.rng.To,
+		ByUnits:  sort == "units",
+		RowLimit: int32(limit),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product sales", err)
+		return
+	}
+
+	data := make([]TopProductResponse, 0, len(rows))
+	for _, row := range rows {
+		data = append(data, TopProductResponse{
+			ProductID:    row.ProductID,
+			ProductName:  row.ProductName,
+			Units:        row.Units,
+			Orders:       row.Orders,
+			RevenueCents: row.RevenueCents,
+		})
+	}
+
+	resp := q.meta()
+	delete(resp, "granularity")
+	resp["currency"] = q.currency
+	resp["sort"] = sort
+	resp["data"] = data
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerTenantAnalyticsChannels reports, per sales channel, how many
+// checkouts were started and how many of them became orders, with the
+// orders placed through the channel
+func (cfg *apiConfig) handlerTenantAnalyticsChannels(w http.ResponseWriter, r *http.Request) {
+	q, ok := cfg.parseAnalyticsQuery(w, r)
+	if !ok {
+		return
+	}
+
+	rows, err := cfg.reads.GetAnalyticsDailyChannels(r.Context(), database.GetAnalyticsDailyChannelsParams{
+		StoreID: q.store.ID,
+		FromDay: q.rng.From,
+		ToDay:   q.rng.To,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve channel conversion", err)
+		return
+	}
+
+	channels := analytics.BucketChannels(q.granularity, q.rng, rows)
+	data := make([]ChannelConversionResponse, 0, len(channels))
+	for _, c := range channels {
+		resp := ChannelConversionResponse{
+			ChannelID: c.ChannelID,
+			Name:      c.Name,
+			Kind:      c.Kind,
+			Totals:    channelConversionPeriod(time.Time{}, c.Total),
+			Periods:   make([]ChannelConversionPeriod, 0, len(c.Periods)),
+		}
+		for _, p := range c.Periods {
+			resp.Periods = append(resp.Periods, channelConversionPeriod(p.Start, p.Conversion))
+		}
+		data = append(data, resp)
+	}
+
+	resp := q.meta()
+	resp["data"] = data
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+func salesReportPeriod(start time.Time, s analytics.Sales) SalesReportPeriod {
+	p := SalesReportPeriod{
+		Orders:                 s.Orders,
+		Units:                  s.Units,
+		SubtotalCents:          s.SubtotalCents,
+		DiscountCents:          s.DiscountCents,
+		ShippingCents:          s.ShippingCents,
+		TaxCents:               s.TaxCents,
+		TotalCents:             s.TotalCents,
+		RefundedCents:          s.RefundedCents,
+		NetCents:               s.NetCents(),
+		AverageOrderValueCents: s.AverageOrderValueCents(),
+	}
+	if !start.IsZero() {
+		p.PeriodStart = start.Format(analytics.DateLayout)
+	}
+	return p
+}
+
+func channelConversionPeriod(start time.Time, c analytics.Conversion) ChannelConversionPeriod {
+	p := ChannelConversionPeriod{
+		Checkouts:      c.Checkouts,
+		Converted:      c.Converted,
+		ConversionRate: c.Rate(),
+		Orders:         c.Orders,
+	}
+	if !start.IsZero() {
+		p.PeriodStart = start.Format(analytics.DateLayout)
+	}
+	return p
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const maxAPIKeyNameLength = 100
+
+type APIKeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	TenantID   uuid.UUID  `json:"tenant_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedBy  uuid.UUID  `json:"created_by"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateAPIKeyResponse carries the full key. It is never shown again.
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// handlerTenantAPIKeysCreate issues an API key for the tenant. Its scopes
+// must be permission keys the creator holds, so a key can't do more than
+// the person who made it.
+func (cfg *apiConfig) handlerTenantAPIKeysCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID := access.UserID, access.TenantID
+
+	if _, ok := apiKeyFromContext(r.Context()); ok {
+		respondWithError(w, http.StatusForbidden, "API keys can't create other API keys", nil)
+		return
+	}
+
+	type parameters struct {
+		Name      string     `json:"name"`
+		Scopes    []string   `json:"scopes"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	name := strings.TrimSpace(params.Name)
+	if name == "" || len(name) > maxAPIKeyNameLength {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Name is required and must be at most %d characters", maxAPIKeyNameLength), nil)
+		return
+	}
+
+	scopes := make([]string, 0, len(params.Scopes))
+	for _, s := range params.Scopes {
+		s = strings.TrimSpace(s)
+		if s != "" && !slices.Contains(scopes, s) {
+			scopes = append(scopes, s)
+		}
+	}
+	slices.Sort(scopes)
+	if len(scopes) == 0 {
+		respondWithError(w, http.StatusBadRequest, "At least one scope is required", nil)
+		return
+	}
+
+	var expiresAt sql.NullTime
+	if params.ExpiresAt != nil {
+		if !params.ExpiresAt.After(time.Now()) {
+			respondWithError(w, http.StatusBadRequest, "expires_at must be in the future", nil)
+			return
+		}
+		expiresAt = sql.NullTime{Time: *params.ExpiresAt, Valid: true}
+	}
+
+	permissions, err := cfg.db.GetPermissionsByKeys(r.Context(), scopes)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to verify scopes", err)
+		return
+	}
+	if len(permissions) != len(scopes) {
+		known := make([]string, 0, len(permissions))
+		for _, p := range permissions {
+			known = append(known, p.Key)
+		}
+		for _, s := range scopes {
+			if !slices.Contains(known, s) {
+				respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Unknown scope %q", s), nil)
+				return
+			}
+		}
+	}
+
+	for _, s := range scopes {
+		has, err := cfg.db.CheckUserHasPermission(r.Context(), database.CheckUserHasPermissionParams{
+			TenantID: tenantID,
+			UserID:   user,
+			Key:      s,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to verify scopes", err)
+			return
+		}
+		if !has {
+			respondWithErrorCode(w, http.StatusForbidden, problem.CodePermissionDenied, fmt.Sprintf("You can't grant the %q scope because you don't have it", s), nil)
+			return
+		}
+	}
+
+	key, prefix, err := auth.MakeAPIKey()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create API key", err)
+		return
+	}
+
+	apiKey, err := cfg.db.CreateAPIKey(r.Context(), database.CreateAPIKeyParams{
+		Gid:       sql.NullInt64{Int64: int64(cfg.gidGen.Generate()), Valid: true},
+		TenantID:  tenantID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   auth.HashToken(key),
+		Scopes:    scopes,
+		CreatedBy: user,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create API key", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant api key created",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"api_key_id", apiKey.ID,
+		"scopes", scopes,
+	)
+
+	respondWithJSON(w, http.StatusCreated, CreateAPIKeyResponse{
+		APIKeyResponse: apiKeyToResponse(apiKey),
+		Key:            key,
+	})
+}
+
+// handlerTenantAPIKeysList lists the tenant's active API keys
+func (cfg *apiConfig) handlerTenantAPIKeysList(w http.ResponseWriter, r *http.Request) {
+	tenantID := tenantAccessFrom(r).TenantID
+
+	keys, err := cfg.db.ListTenantAPIKeys(r.Context(), tenantID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve API keys", err)
+		return
+	}
+
+	response := make([]APIKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		response = append(response, apiKeyToResponse(k))
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{"data": response})
+}
+
+// handlerTenantAPIKeyRevoke revokes an API key. Requests using it fail from
+// then on.
+func (cfg *apiConfig) handlerTenantAPIKeyRevoke(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID := access.UserID, access.TenantID
+
+	keyID, err := uuid.Parse(chi.URLParam(r, "keyID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid API key ID format", err)
+		return
+	}
+
+	apiKey, err := cfg.db.RevokeAPIKey(r.Context(), database.RevokeAPIKeyParams{
+		ID:       keyID,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "API key not found", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to revoke API key", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant api key revoked",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"api_key_id", apiKey.ID,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func apiKeyToResponse(k database.ApiKey) APIKeyResponse {
+	resp := APIKeyResponse{
+		ID:        k.ID,
+		TenantID:  k.TenantID,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Scopes:    k.Scopes,
+		CreatedBy: k.CreatedBy,
+		CreatedAt: k.CreatedAt,
+	}
+	if resp.Scopes == nil {
+		resp.Scopes = []string{}
+	}
+	if k.LastUsedAt.Valid {
+		resp.LastUsedAt = &k.LastUsedAt.Time
+	}
+	if k.ExpiresAt.Valid {
+		resp.ExpiresAt = &k.ExpiresAt.Time
+	}
+	return resp
+}
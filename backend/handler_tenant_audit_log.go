@@ -0,0 +1,158 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/audit"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/google/uuid"
+)
+
+type AuditLogEntryResponse struct {
+	ID            uuid.UUID               `json:"id"`
+	Action        string                  `json:"action"`
+	EntityType    string                  `json:"entity_type,omitempty"`
+	EntityID      *uuid.UUID              `json:"entity_id,omitempty"`
+	EntityGID     string                  `json:"entity_gid,omitempty"`
+	StoreID       *uuid.UUID              `json:"store_id,omitempty"`
+	ActorUserID   *uuid.UUID              `json:"actor_user_id,omitempty"`
+	ActorAPIKeyID *uuid.UUID              `json:"actor_api_key_id,omitempty"`
+	Method        string                  `json:"method"`
+	Route         string                  `json:"route"`
+	Path          string                  `json:"path"`
+	StatusCode    int32                   `json:"status_code"`
+	RequestID     string                  `json:"request_id,omitempty"`
+	IP            string                  `json:"ip,omitempty"`
+	Before        json.RawMessage         `json:"before,omitempty"`
+	After         json.RawMessage         `json:"after,omitempty"`
+	Changes       map[string]audit.Change `json:"changes,omitempty"`
+	CreatedAt     time.Time               `json:"created_at"`
+}
+
+type AuditLogCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+}
+
+var auditLogCursorCodec = CursorCodec[AuditLogCursor]{
+	Validate: func(c AuditLogCursor) error {
+		if c.CreatedAt.IsZero() || c.ID == uuid.Nil {
+			return errors.New("invalid cursor: missing required fields")
+		}
+		return nil
+	},
+}
+
+// handlerTenantAuditLogList returns a tenant's audit log, newest first,
+// narrowed by the filters audit.ParseFilter reads
+func (cfg *apiConfig) handlerTenantAuditLogList(w http.ResponseWriter, r *http.Request) {
+	tenantID := tenantAccessFrom(r).TenantID
+
+	filter, err := audit.ParseFilter(r.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+
+	cursor, hasCursor, err := auditLogCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.db.ListAuditLog(r.Context(), database.ListAuditLogParams{
+		TenantID:        tenantID,
+		ActorUserID:     uuid.NullUUID{UUID: filter.ActorID, Valid: filter.ActorID != uuid.Nil},
+		Action:          sql.NullString{String: filter.Action, Valid: filter.Action != ""},
+		EntityType:      sql.NullString{String: filter.EntityType, Valid: filter.EntityType != ""},
+		EntityID:        uuid.NullUUID{UUID: filter.EntityID, Valid: filter.EntityID != uuid.Nil},
+		EntityGid:       sql.NullString{String: filter.EntityGID, Valid: filter.EntityGID != ""},
+		StoreID:         uuid.NullUUID{UUID: filter.StoreID, Valid: filter.StoreID != uuid.Nil},
+		Since:           sql.NullTime{Time: filter.Since, Valid: !filter.Since.IsZero()},
+		Until:           sql.NullTime{Time: filter.Until, Valid: !filter.Until.IsZero()},
+		HasCursor:       hasCursor,
+		CursorCreatedAt: cursor.CreatedAt,
+		CursorID:        cursor.ID,
+		RowLimit:        int32(pageParams.Limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve audit log", err)
+		return
+	}
+
+	hasMore := len(rows) > pageParams.Limit
+	if hasMore {
+		rows = rows[:pageParams.Limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = auditLogCursorCodec.Encode(AuditLogCursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]AuditLogEntryResponse, 0, len(rows))
+	for _, e := range rows {
+		response = append(response, auditLogEntryToResponse(e))
+	}
+
+	respondWithJSON(w, http.StatusOK, PageResponse[AuditLogEntryResponse]{
+		Data: response,
+		Page: PageInfo{Limit: pageParams.Limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
+
+func auditLogEntryToResponse(e database.AuditLog) AuditLogEntryResponse {
+	resp := AuditLogEntryResponse{
+		ID:         e.ID,
+		Action:     e.Action,
+		EntityType: e.EntityType.String,
+		EntityGID:  e.EntityGid.String,
+		Method:     e.Method,
+		Route:      e.Route,
+		Path:       e.Path,
+		StatusCode: e.StatusCode,
+		RequestID:  e.RequestID.String,
+		IP:         e.Ip.String,
+		CreatedAt:  e.CreatedAt,
+	}
+	if e.EntityID.Valid {
+		resp.EntityID = &e.EntityID.UUID
+	}
+	if e.StoreID.Valid {
+		resp.StoreID = &e.StoreID.UUID
+	}
+	if e.ActorUserID.Valid {
+		resp.ActorUserID = &e.ActorUserID.UUID
+	}
+	if e.ActorApiKeyID.Valid {
+		resp.ActorAPIKeyID = &e.ActorApiKeyID.UUID
+	}
+	if e.Before.Valid {
+		resp.Before = e.Before.RawMessage
+	}
+	if e.After.Valid {
+		resp.After = e.After.RawMessage
+	}
+	if e.Changes.Valid {
+		_ = json.Unmarshal(e.Changes.RawMessage, &resp.Changes)
+	}
+	return resp
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/billing"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/metrics"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/google/uuid"
+)
+
+type billingSubscriptionResponse struct {
+	Plan              string     `json:"plan"`
+	Status            string     `json:"status"`
+	CurrentPeriodEnd  *time.Time `json:"current_period_end"`
+	CancelAtPeriodEnd bool       `json:"cancel_at_period_end"`
+	// GraceUntil is when an unpaid subscription loses its plan
+	GraceUntil *time.Time `json:"grace_until"`
+}
+
+type billingResponse struct {
+	// Enabled is false when plans aren't sold, and staff set each store's
+	// plan themselves
+	Enabled bool `json:"enabled"`
+	// Plan is the plan the tenant's stores are on
+	Plan string `json:"plan"`
+	// Plans are the plans that can be subscribed to
+	Plans        []string                     `json:"plans"`
+	Subscription *billingSubscriptionResponse `json:"subscription"`
+}
+
+type billingSessionResponse struct {
+	URL string `json:"url"`
+}
+
+// handlerTenantBillingGet reports the plan the tenant pays for and the
+// state of its subscription
+func (cfg *apiConfig) handlerTenantBillingGet(w http.ResponseWriter, r *http.Request) {
+	resp, err := cfg.tenantBilling(r.Context(), tenantAccessFrom(r).TenantID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve billing", err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// tenantBilling builds what handlerTenantBillingGet reports of a tenant
+func (cfg *apiConfig) tenantBilling(ctx context.Context, tenantID uuid.UUID) (billingResponse, error) {
+	resp := billingResponse{Plans: []string{}}
+	if cfg.billing == nil {
+		plan, err := cfg.services.entitlements.TenantPlan(ctx, tenantID)
+		if err != nil {
+			return billingResponse{}, err
+		}
+		resp.Plan = plan.Name
+		return resp, nil
+	}
+
+	resp.Enabled = true
+	resp.Plan = billing.FreePlan
+	resp.Plans = cfg.billing.Plans()
+	account, err := cfg.db.GetTenantBilling(ctx, tenantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return resp, nil
+	}
+	if err != nil {
+		return billingResponse{}, err
+	}
+	resp.Plan = account.AppliedPlan
+	if account.StripeSubscriptionID.Valid {
+		sub := &billingSubscriptionResponse{
+			Plan:              account.Plan,
+			Status:            account.Status,
+			CancelAtPeriodEnd: account.CancelAtPeriodEnd,
+		}
+		if account.CurrentPeriodEnd.Valid {
+			sub.CurrentPeriodEnd = &account.CurrentPeriodEnd.Time
+		}
+		if account.GraceUntil.Valid {
+			sub.GraceUntil = &account.GraceUntil.Time
+		}
+		resp.Subscription = sub
+	}
+	return resp, nil
+}
+
+// handlerTenantBillingCheckout starts a Stripe Checkout session
+// subscribing the tenant to a plan. The tenant's stores move to the plan
+// once Stripe reports the subscription paid. A tenant already subscribed
+// changes plan in the billing portal instead.
+func (cfg *apiConfig) handlerTenantBillingCheckout(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+	if cfg.billing == nil {
+		respondWithError(w, http.StatusNotFound, "Billing is not enabled", nil)
+		return
+	}
+
+	type parameters struct {
+		Plan string `json:"plan"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+	var v validate.Validator
+	if v.Required("plan", params.Plan) {
+		v.OneOf("plan", params.Plan, cfg.billing.Plans()...)
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	account, err := cfg.billingAccount(r.Context(), access)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to start checkout", err)
+		return
+	}
+	if billing.Status(account.Status).Paid() || billing.Status(account.Status).Overdue() {
+		respondWithServiceError(w, service.Conflict("The tenant is already subscribed. Change plans in the billing portal."), "Unable to start checkout")
+		return
+	}
+
+	returnURL := cfg.billingReturnURL(access.TenantID)
+	url, err := cfg.billing.CreateCheckoutSession(r.Context(), billing.Checkout{
+		CustomerID: account.StripeCustomerID,
+		TenantID:   access.TenantID,
+		Plan:       params.Plan,
+		SuccessURL: returnURL + "?checkout=success",
+		CancelURL:  returnURL + "?checkout=cancelled",
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadGateway, "Unable to start checkout", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "billing checkout started",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"plan", params.Plan,
+	)
+	respondWithJSON(w, http.StatusCreated, billingSessionResponse{URL: url})
+}
+
+// handlerTenantBillingPortal starts a Stripe billing portal session, where
+// the tenant changes plan, updates its card, reads invoices or cancels
+func (cfg *apiConfig) handlerTenantBillingPortal(w http.ResponseWriter, r *http.Request) {
+	access := tenantAccessFrom(r)
+	if cfg.billing == nil {
+		respondWithError(w, http.StatusNotFound, "Billing is not enabled", nil)
+		return
+	}
+
+	account, err := cfg.db.GetTenantBilling(r.Context(), access.TenantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "The tenant has not subscribed yet", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to open the billing portal", err)
+		return
+	}
+
+	url, err := cfg.billing.CreatePortalSession(r.Context(), account.StripeCustomerID, cfg.billingReturnURL(access.TenantID))
+	if err != nil {
+		respondWithError(w, http.StatusBadGateway, "Unable to open the billing portal", err)
+		return
+	}
+	respondWithJSON(w, http.StatusCreated, billingSessionResponse{URL: url})
+}
+
+// billingAccount returns the tenant's billing record, creating its Stripe
+// customer the first time
+func (cfg *apiConfig) billingAccount(ctx context.Context, access tenantAccess) (database.TenantBilling, error) {
+	account, err := cfg.db.GetTenantBilling(ctx, access.TenantID)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return account, err
+	}
+
+	tenant, err := cfg.db.GetTenantByID(ctx, access.TenantID)
+	if err != nil {
+		return database.TenantBilling{}, err
+	}
+	customer := billing.Customer{TenantID: tenant.ID, Name: tenant.Name}
+	if user, err := cfg.db.GetUserByID(ctx, access.UserID); err == nil {
+		customer.Email = user.Email
+	}
+	customerID, err := cfg.billing.CreateCustomer(ctx, customer)
+	if err != nil {
+		return database.TenantBilling{}, err
+	}
+	return cfg.db.CreateTenantBilling(ctx, database.CreateTenantBillingParams{
+		TenantID:         tenant.ID,
+		StripeCustomerID: customerID,
+	})
+}
+
+// billingReturnURL is the admin app page Stripe sends staff back to
+func (cfg *apiConfig) billingReturnURL(tenantID uuid.UUID) string {
+	return fmt.Sprintf("%s/tenants/%s/billing", cfg.config.AppURL, tenantID)
+}
+
+// handlerBillingWebhook takes the subscription changes Stripe sends. The
+// signature is the only credential. Each change moves the tenant's stores
+// to the plan the subscription now leaves them on.
+func (cfg *apiConfig) handlerBillingWebhook(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	if cfg.billing == nil {
+		respondWithError(w, http.StatusNotFound, "Billing is not enabled", nil)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, billing.MaxWebhookBytes))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Unable to read webhook", err)
+		return
+	}
+	event, ok, err := cfg.billing.ParseWebhook(r, body, time.Now())
+	if errors.Is(err, billing.ErrInvalidSignature) {
+		metrics.WebhookFailed(uuid.Nil, "stripe")
+		respondWithError(w, http.StatusUnauthorized, "Invalid webhook signature", err)
+		return
+	}
+	if err != nil {
+		metrics.WebhookFailed(uuid.Nil, "stripe")
+		respondWithError(w, http.StatusBadRequest, "Unable to parse webhook", err)
+		return
+	}
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var account database.TenantBilling
+	var applied bool
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		account, applied, err = cfg.billing.Apply(r.Context(), q, event, time.Now())
+		return err
+	})
+	if err != nil {
+		metrics.WebhookFailed(uuid.Nil, "stripe")
+		respondWithError(w, http.StatusInternalServerError, "Unable to apply subscription change", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "billing subscription change received",
+		"request_id", reqID,
+		"event_id", event.ID,
+		"event_type", event.Type,
+		"subscription_id", event.Subscription.ID,
+		"subscription_status", event.Subscription.Status,
+		"applied", applied,
+		"tenant_id", account.TenantID,
+		"plan", account.AppliedPlan,
+	)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// storePlan is the plan a store of the tenant may be given when staff ask
+// for requested. Without billing, staff set plans themselves. With it,
+// every store is on the plan the tenant pays for; asking for another is an
+// upgrade-required error and asking for none gets that plan.
+func (cfg *apiConfig) storePlan(ctx context.Context, tenantID uuid.UUID, requested string) (string, error) {
+	if cfg.billing == nil {
+		return requested, nil
+	}
+	plan := billing.FreePlan
+	account, err := cfg.db.GetTenantBilling(ctx, tenantID)
+	if err == nil {
+		plan = account.AppliedPlan
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+	if requested != "" && requested != plan {
+		return "", service.UpgradeRequired(fmt.Sprintf("Stores are on the %s plan the tenant pays for. Change plans through billing.", plan))
+	}
+	return plan, nil
+}
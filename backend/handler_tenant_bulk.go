@@ -0,0 +1,318 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/bulk"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxBulkBodyBytes leaves room for a full batch of product updates
+const maxBulkBodyBytes = 4 << 20
+
+type BulkOperationResponse struct {
+	ID         uuid.UUID           `json:"id"`
+	Status     string              `json:"status"`
+	Counts     BulkOperationCounts `json:"counts"`
+	Error      *string             `json:"error,omitempty"`
+	CreatedAt  time.Time           `json:"created_at"`
+	StartedAt  *time.Time          `json:"started_at,omitempty"`
+	FinishedAt *time.Time          `json:"finished_at,omitempty"`
+}
+
+// BulkOperationCounts tallies mutations by outcome; Total less the others
+// is what is still to be applied
+type BulkOperationCounts struct {
+	Total     int32 `json:"total"`
+	Succeeded int32 `json:"succeeded"`
+	Failed    int32 `json:"failed"`
+}
+
+type BulkOperationResultResponse struct {
+	// Index is the mutation's place in the submitted batch, from 0
+	Index   int32                `json:"index"`
+	Op      string               `json:"op"`
+	StoreID uuid.UUID            `json:"store_id"`
+	ID      uuid.UUID            `json:"id"`
+	Status  string               `json:"status"`
+	Errors  []problem.FieldError `json:"errors"`
+}
+
+// BulkOperationReportResponse is an operation with a page of its results
+type BulkOperationReportResponse struct {
+	Operation BulkOperationResponse         `json:"operation"`
+	Results   []BulkOperationResultResponse `json:"results"`
+	Page      PageInfo                      `json:"page"`
+}
+
+type BulkOperationResultCursor struct {
+	Index int32 `json:"index"`
+}
+
+var bulkOperationResultCursorCodec = CursorCodec[BulkOperationResultCursor]{
+	Validate: func(c BulkOperationResultCursor) error {
+		if c.Index < 0 {
+			return errors.New("invalid cursor: index must not be negative")
+		}
+		return nil
+	},
+}
+
+// handlerTenantBulkCreate queues a batch of product, variant and order
+// changes across the tenant's stores for the worker. The batch is checked
+// as a whole first: every mutation must be well formed, name a store in
+// this tenant, and be one the caller has permission to make.
+func (cfg *apiConfig) handlerTenantBulkCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	tc := tenantContextFrom(r)
+	user, tenantID := tc.Membership.UserID, tc.Tenant.ID
+
+	type parameters struct {
+		Mutations []bulk.Mutation `json:"mutations"`
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBulkBodyBytes)
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondWithError(w, http.StatusRequestEntityTooLarge, "Bulk requests must be 4MB or smaller", nil)
+			return
+		}
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	if err := bulk.Validate(params.Mutations); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	permissions := bulk.Permissions(params.Mutations)
+	for _, permission := range permissions {
+		if _, ok := cfg.resolveTenantAccess(w, r, permission); !ok {
+			return
+		}
+	}
+
+	if err := cfg.checkBulkStores(r, tenantID, params.Mutations); err != nil {
+		if errors.Is(err, validate.ErrInvalid) {
+			respondWithValidationError(w, err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to verify stores", err)
+		return
+	}
+
+	mutations, err := json.Marshal(params.Mutations)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create bulk operation", err)
+		return
+	}
+
+	var created database.CreateBulkOperationRow
+	err = cfg.withTenantTx(r.Context(), tenantID, func(q *database.Queries) error {
+		var err error
+		created, err = q.CreateBulkOperation(r.Context(), database.CreateBulkOperationParams{
+			TenantID:       tenantID,
+			CreatedBy:      uuid.NullUUID{UUID: user, Valid: true},
+			Mutations:      mutations,
+			Permissions:    permissions,
+			TotalMutations: int32(len(params.Mutations)),
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = cfg.jobs.EnqueueTx(r.Context(), q, bulk.Args{OperationID: created.ID})
+		return err
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create bulk operation", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant bulk operation queued",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"operation_id", created.ID,
+		"mutations", len(params.Mutations),
+	)
+
+	respondWithJSON(w, http.StatusAccepted, bulkOperationToResponse(database.GetBulkOperationRow(created), database.CountBulkOperationResultsRow{}))
+}
+
+// checkBulkStores confirms every store a batch names belongs to the tenant,
+// reporting the mutations that name one that doesn't
+func (cfg *apiConfig) checkBulkStores(r *http.Request, tenantID uuid.UUID, mutations []bulk.Mutation) error {
+	var v validate.Validator
+	known := map[uuid.UUID]bool{}
+	for i, m := range mutations {
+		inTenant, checked := known[m.StoreID]
+		if !checked {
+			_, err := cfg.db.GetStoreByTenantAndID(r.Context(), database.GetStoreByTenantAndIDParams{
+				TenantID: uuid.NullUUID{UUID: tenantID, Valid: true},
+				ID:       m.StoreID,
+			})
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+			inTenant = err == nil
+			known[m.StoreID] = inTenant
+		}
+		v.Check(inTenant, fmt.Sprintf("mutations.%d.store_id", i), validate.CodeInvalid, "is not a store in this tenant")
+	}
+	return v.Err()
+}
+
+// handlerTenantBulkGet reports a bulk operation's progress and a page of
+// its results in batch order. ?status=failed lists only the mutations that
+// were rejected. Reading the results takes the same permissions as
+// submitting the batch did.
+func (cfg *apiConfig) handlerTenantBulkGet(w http.ResponseWriter, r *http.Request) {
+	tenantID := tenantContextFrom(r).Tenant.ID
+
+	operationID, err := uuid.Parse(chi.URLParam(r, "operationID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid operation ID", err)
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", bulk.ResultSucceeded, bulk.ResultFailed:
+	default:
+		respondWithError(w, http.StatusBadRequest, "status must be succeeded or failed", nil)
+		return
+	}
+
+	pageParams, err := ParsePageParams(r, 100, 500)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+
+	cursor, hasCursor, err := bulkOperationResultCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+	after := int32(-1)
+	if hasCursor {
+		after = cursor.Index
+	}
+
+	op, err := cfg.db.GetBulkOperation(r.Context(), database.GetBulkOperationParams{
+		ID:       operationID,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Bulk operation not found", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve bulk operation", err)
+		return
+	}
+
+	for _, permission := range op.Permissions {
+		if _, ok := cfg.resolveTenantAccess(w, r, permission); !ok {
+			return
+		}
+	}
+
+	counts, err := cfg.db.CountBulkOperationResults(r.Context(), op.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve bulk operation", err)
+		return
+	}
+
+	results, err := cfg.db.ListBulkOperationResults(r.Context(), database.ListBulkOperationResultsParams{
+		OperationID:   op.ID,
+		Status:        sql.NullString{String: status, Valid: status != ""},
+		AfterPosition: after,
+		RowLimit:      int32(pageParams.Limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve bulk operation results", err)
+		return
+	}
+
+	hasMore := len(results) > pageParams.Limit
+	if hasMore {
+		results = results[:pageParams.Limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(results) > 0 {
+		nextCursor, err = bulkOperationResultCursorCodec.Encode(BulkOperationResultCursor{Index: results[len(results)-1].Position})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	report := make([]BulkOperationResultResponse, 0, len(results))
+	for _, result := range results {
+		report = append(report, bulkResultToResponse(result))
+	}
+
+	respondWithJSON(w, http.StatusOK, BulkOperationReportResponse{
+		Operation: bulkOperationToResponse(op, counts),
+		Results:   report,
+		Page:      PageInfo{Limit: pageParams.Limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
+
+func bulkOperationToResponse(op database.GetBulkOperationRow, counts database.CountBulkOperationResultsRow) BulkOperationResponse {
+	resp := BulkOperationResponse{
+		ID:     op.ID,
+		Status: op.Status,
+		Counts: BulkOperationCounts{
+			Total:     op.TotalMutations,
+			Succeeded: counts.Succeeded,
+			Failed:    counts.Failed,
+		},
+		CreatedAt: op.CreatedAt,
+	}
+	if op.Error.Valid {
+		resp.Error = &op.Error.String
+	}
+	if op.StartedAt.Valid {
+		resp.StartedAt = &op.StartedAt.Time
+	}
+	if op.FinishedAt.Valid {
+		resp.FinishedAt = &op.FinishedAt.Time
+	}
+	return resp
+}
+
+func bulkResultToResponse(result database.BulkOperationResult) BulkOperationResultResponse {
+	resp := BulkOperationResultResponse{
+		Index:   result.Position,
+		Op:      result.Op,
+		StoreID: result.StoreID,
+		ID:      result.TargetID,
+		Status:  result.Status,
+		Errors:  []problem.FieldError{},
+	}
+	// Written by the worker from the same type, so a decode failure would
+	// only lose the detail, not the mutation's status
+	_ = json.Unmarshal(result.Errors, &resp.Errors)
+	return resp
+}
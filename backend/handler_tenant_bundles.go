@@ -0,0 +1,178 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/dfodeker/terminus/internal/bundles"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/google/uuid"
+)
+
+// BundleComponentResponse is a variant a bundle is made of
+type BundleComponentResponse struct {
+	VariantID        uuid.UUID `json:"variant_id"`
+	ProductID        uuid.UUID `json:"product_id"`
+	Title            string    `json:"title"`
+	SKU              *string   `json:"sku,omitempty"`
+	Quantity         int32     `json:"quantity"`
+	InventoryTracked bool      `json:"inventory_tracked"`
+}
+
+// handlerTenantBundleComponentsGet lists the variants a bundle variant is
+// made of. An ordinary variant has none.
+func (cfg *apiConfig) handlerTenantBundleComponentsGet(w http.ResponseWriter, r *http.Request) {
+	variant, ok := cfg.tenantVariant(w, r)
+	if !ok {
+		return
+	}
+
+	components, err := cfg.bundleComponents(r, variant.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve bundle components", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{"data": components})
+}
+
+// handlerTenantBundleComponentsPut replaces a variant's components, making
+// it a bundle, or an ordinary variant again when the list is empty
+func (cfg *apiConfig) handlerTenantBundleComponentsPut(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	variant, ok := cfg.tenantVariant(w, r)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		Components []bundles.Component `json:"components"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	var v validate.Validator
+	if err := bundles.Validate(variant.ID, params.Components); err != nil {
+		v.Fail("components", validate.CodeInvalid, err.Error())
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	if len(params.Components) > 0 {
+		// Bundles are one level deep, so stock never has to be unpacked twice
+		isComponent, err := cfg.db.IsBundleComponent(r.Context(), variant.ID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to update bundle components", err)
+			return
+		}
+		v.Check(!isComponent, "components", validate.CodeInvalid, "cannot be set on a variant that is itself in a bundle")
+
+		ids := make([]uuid.UUID, 0, len(params.Components))
+		for _, c := range params.Components {
+			component, err := cfg.db.GetProductVariantByID(r.Context(), c.VariantID)
+			if errors.Is(err, sql.ErrNoRows) || (err == nil && component.StoreID != access.StoreID) {
+				v.Fail("components", validate.CodeInvalid, "includes a variant that does not exist")
+				break
+			}
+			if err != nil {
+				respondWithError(w, http.StatusInternalServerError, "Unable to retrieve variant", err)
+				return
+			}
+			ids = append(ids, c.VariantID)
+		}
+		nested, err := cfg.db.GetBundleVariantIDs(r.Context(), ids)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to update bundle components", err)
+			return
+		}
+		v.Check(len(nested) == 0, "components", validate.CodeInvalid, "cannot include another bundle")
+		if err := v.Err(); err != nil {
+			respondWithValidationError(w, err)
+			return
+		}
+	}
+
+	// Kept for the audit log
+	before, err := cfg.bundleComponents(r, variant.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update bundle components", err)
+		return
+	}
+
+	err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		if err := q.ClearBundleComponents(r.Context(), variant.ID); err != nil {
+			return err
+		}
+		for _, c := range params.Components {
+			if err := q.AddBundleComponent(r.Context(), database.AddBundleComponentParams{
+				BundleVariantID:    variant.ID,
+				ComponentVariantID: c.VariantID,
+				StoreID:            variant.StoreID,
+				Quantity:           c.Quantity,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update bundle components", err)
+		return
+	}
+
+	components, err := cfg.bundleComponents(r, variant.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve bundle components", err)
+		return
+	}
+	auditChange(r, auditGID(gid.EntityProductVariant, variant.Gid), before, components)
+
+	slog.InfoContext(r.Context(), "bundle components updated",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"variant_id", variant.ID,
+		"component_count", len(components),
+	)
+
+	respondWithJSON(w, http.StatusOK, map[string]any{"data": components})
+}
+
+// bundleComponents returns a variant's components, empty unless it is a
+// bundle
+func (cfg *apiConfig) bundleComponents(r *http.Request, variantID uuid.UUID) ([]BundleComponentResponse, error) {
+	rows, err := cfg.db.ListBundleComponents(r.Context(), variantID)
+	if err != nil {
+		return nil, err
+	}
+	components := make([]BundleComponentResponse, 0, len(rows))
+	for _, row := range rows {
+		c := BundleComponentResponse{
+			VariantID:        row.ComponentVariantID,
+			ProductID:        row.ProductID,
+			Title:            row.Title,
+			Quantity:         row.Quantity,
+			InventoryTracked: row.InventoryTracked,
+		}
+		if row.Sku.Valid {
+			c.SKU = &row.Sku.String
+		}
+		components = append(components, c)
+	}
+	return components, nil
+}
@@ -0,0 +1,887 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/collections"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type CollectionResponse struct {
+	ID          uuid.UUID          `json:"id"`
+	StoreID     uuid.UUID          `json:"store_id"`
+	Handle      string             `json:"handle"`
+	Title       string             `json:"title"`
+	Description *string            `json:"description,omitempty"`
+	Kind        string             `json:"kind"`
+	Rules       []collections.Rule `json:"rules,omitempty"`
+	MatchAny    bool               `json:"match_any"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+type CollectionCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+}
+
+var collectionCursorCodec = CursorCodec[CollectionCursor]{
+	Validate: func(c CollectionCursor) error {
+		if c.CreatedAt.IsZero() || c.ID == uuid.Nil {
+			return errors.New("invalid cursor: missing required fields")
+		}
+		return nil
+	},
+}
+
+type CollectionProductCursor struct {
+	Position int32     `json:"position"`
+	ID       uuid.UUID `json:"id"`
+}
+
+var collectionProductCursorCodec = CursorCodec[CollectionProductCursor]{
+	Validate: func(c CollectionProductCursor) error {
+		if c.ID == uuid.Nil {
+			return errors.New("invalid cursor: missing required fields")
+		}
+		return nil
+	},
+}
+
+// maxCollectionTitleLength bounds collection titles
+const maxCollectionTitleLength = 255
+
+// validateCollectionRules records why rules are invalid, if they are
+func validateCollectionRules(v *validate.Validator, rules []collections.Rule) {
+	if err := collections.ValidateRules(rules); err != nil {
+		v.Fail("rules", validate.CodeInvalid, err.Error())
+	}
+}
+
+// handlerTenantCollectionsCreate creates a manual or automated collection in a store
+func (cfg *apiConfig) handlerTenantCollectionsCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	type parameters struct {
+		Handle      string             `json:"handle"`
+		Title       string             `json:"title"`
+		Description *string            `json:"description"`
+		Kind        collections.Kind   `json:"kind"`
+		Rules       []collections.Rule `json:"rules"`
+		MatchAny    bool               `json:"match_any"`
+		// ProductIDs seeds a manual collection
+		ProductIDs []uuid.UUID `json:"product_ids"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	if params.Kind == "" {
+		params.Kind = collections.KindManual
+	}
+
+	var v validate.Validator
+	if v.Required("title", params.Title) {
+		v.MaxLength("title", params.Title, maxCollectionTitleLength)
+	}
+	if v.Required("handle", params.Handle) {
+		v.Handle("handle", params.Handle)
+	}
+	if v.OneOf("kind", string(params.Kind), string(collections.KindManual), string(collections.KindAutomated)) {
+		switch params.Kind {
+		case collections.KindAutomated:
+			validateCollectionRules(&v, params.Rules)
+			v.Check(len(params.ProductIDs) == 0, "product_ids", validate.CodeInvalid, "cannot be set on automated collections")
+		case collections.KindManual:
+			v.Check(len(params.Rules) == 0, "rules", validate.CodeInvalid, "are only allowed on automated collections")
+		}
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	_, err = cfg.db.GetCollectionByHandle(r.Context(), database.GetCollectionByHandleParams{
+		StoreID: storeID,
+		Handle:  params.Handle,
+	})
+	if err == nil {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeHandleTaken, "A collection with this handle already exists", nil)
+		return
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusInternalServerError, "Unable to verify collection handle", err)
+		return
+	}
+
+	rules, err := json.Marshal(params.Rules)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid collection rules", err)
+		return
+	}
+	if params.Rules == nil {
+		rules = []byte("[]")
+	}
+
+	var collection database.Collection
+	err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		var err error
+		collection, err = q.CreateCollection(r.Context(), database.CreateCollectionParams{
+			Gid:         sql.NullInt64{Int64: int64(cfg.gidGen.Generate()), Valid: true},
+			TenantID:    tenantID,
+			StoreID:     storeID,
+			Handle:      params.Handle,
+			Title:       params.Title,
+			Description: nullStringFromPtr(params.Description),
+			Kind:        string(params.Kind),
+			Rules:       rules,
+			MatchAny:    params.MatchAny,
+		})
+		if err != nil {
+			return err
+		}
+
+		if collection.Kind == string(collections.KindAutomated) {
+			return refreshAutomatedCollection(r.Context(), q, collection)
+		}
+		return addCollectionProducts(r.Context(), q, collection, params.ProductIDs)
+	})
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to create collection")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant collection created successfully",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"collection_id", collection.ID,
+		"kind", collection.Kind,
+	)
+
+	respondWithJSON(w, http.StatusCreated, collectionToResponse(collection))
+}
+
+// handlerTenantCollectionsList lists a store's collections
+func (cfg *apiConfig) handlerTenantCollectionsList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	cfg.respondWithCollections(w, r, storeID)
+}
+
+// handlerTenantCollectionGet returns a single collection
+func (cfg *apiConfig) handlerTenantCollectionGet(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	collection, ok := cfg.loadStoreCollection(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, collectionToResponse(collection))
+}
+
+// handlerTenantCollectionUpdate edits a collection; changing an automated
+// collection's rules re-evaluates its membership
+func (cfg *apiConfig) handlerTenantCollectionUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	existing, ok := cfg.loadStoreCollection(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		Handle      *string             `json:"handle"`
+		Title       *string             `json:"title"`
+		Description *string             `json:"description"`
+		Rules       *[]collections.Rule `json:"rules"`
+		MatchAny    *bool               `json:"match_any"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	automated := existing.Kind == string(collections.KindAutomated)
+
+	var v validate.Validator
+	if params.Handle != nil && v.Required("handle", *params.Handle) {
+		v.Handle("handle", *params.Handle)
+	}
+	if params.Title != nil && v.Required("title", *params.Title) {
+		v.MaxLength("title", *params.Title, maxCollectionTitleLength)
+	}
+	if params.Rules != nil && v.Check(automated, "rules", validate.CodeInvalid, "are only allowed on automated collections") {
+		validateCollectionRules(&v, *params.Rules)
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	handle := existing.Handle
+	if params.Handle != nil {
+		handle = *params.Handle
+	}
+
+	title := existing.Title
+	if params.Title != nil {
+		title = *params.Title
+	}
+
+	description := existing.Description
+	if params.Description != nil {
+		description = sql.NullString{String: *params.Description, Valid: true}
+	}
+
+	rules := existing.Rules
+	if params.Rules != nil {
+		rules, err = json.Marshal(*params.Rules)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid collection rules", err)
+			return
+		}
+	}
+
+	matchAny := existing.MatchAny
+	if params.MatchAny != nil {
+		matchAny = *params.MatchAny
+	}
+
+	if handle != existing.Handle {
+		_, err = cfg.db.GetCollectionByHandle(r.Context(), database.GetCollectionByHandleParams{
+			StoreID: storeID,
+			Handle:  handle,
+		})
+		if err == nil {
+			respondWithErrorCode(w, http.StatusConflict, problem.CodeHandleTaken, "A collection with this handle already exists", nil)
+			return
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusInternalServerError, "Unable to verify collection handle", err)
+			return
+		}
+	}
+
+	var collection database.Collection
+	err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		var err error
+		collection, err = q.UpdateCollection(r.Context(), database.UpdateCollectionParams{
+			ID:          existing.ID,
+			StoreID:     storeID,
+			Handle:      handle,
+			Title:       title,
+			Description: description,
+			Rules:       rules,
+			MatchAny:    matchAny,
+		})
+		if err != nil {
+			return err
+		}
+
+		if automated && (params.Rules != nil || params.MatchAny != nil) {
+			return refreshAutomatedCollection(r.Context(), q, collection)
+		}
+		return nil
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update collection", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant collection updated successfully",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"collection_id", collection.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, collectionToResponse(collection))
+}
+
+// handlerTenantCollectionDelete deletes a collection; its products are untouched
+func (cfg *apiConfig) handlerTenantCollectionDelete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	collection, ok := cfg.loadStoreCollection(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	err := cfg.db.DeleteCollection(r.Context(), database.DeleteCollectionParams{
+		ID:      collection.ID,
+		StoreID: storeID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to delete collection", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant collection deleted successfully",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"collection_id", collection.ID,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerTenantCollectionProductsAdd assigns products to a manual collection
+func (cfg *apiConfig) handlerTenantCollectionProductsAdd(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	collection, ok := cfg.loadStoreCollection(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	if collection.Kind != string(collections.KindManual) {
+		respondWithError(w, http.StatusConflict, "Products of automated collections are managed by its rules", nil)
+		return
+	}
+
+	type parameters struct {
+		ProductIDs []uuid.UUID `json:"product_ids"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	if len(params.ProductIDs) == 0 {
+		respondWithError(w, http.StatusBadRequest, "At least one product ID is required", nil)
+		return
+	}
+
+	err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		return addCollectionProducts(r.Context(), q, collection, params.ProductIDs)
+	})
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to add products to collection")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant collection products added",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"collection_id", collection.ID,
+		"product_count", len(params.ProductIDs),
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerTenantCollectionProductRemove takes a product out of a manual collection
+func (cfg *apiConfig) handlerTenantCollectionProductRemove(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	collection, ok := cfg.loadStoreCollection(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	if collection.Kind != string(collections.KindManual) {
+		respondWithError(w, http.StatusConflict, "Products of automated collections are managed by its rules", nil)
+		return
+	}
+
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid product ID format", err)
+		return
+	}
+
+	removed, err := cfg.db.RemoveProductFromCollection(r.Context(), database.RemoveProductFromCollectionParams{
+		CollectionID: collection.ID,
+		ProductID:    productID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to remove product from collection", err)
+		return
+	}
+	if removed == 0 {
+		respondWithError(w, http.StatusNotFound, "Product is not in this collection", nil)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant collection product removed",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"collection_id", collection.ID,
+		"product_id", productID,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerTenantCollectionProductsReorder moves the listed products to the top
+// of the collection in the given order; unlisted products keep their relative order
+func (cfg *apiConfig) handlerTenantCollectionProductsReorder(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	collection, ok := cfg.loadStoreCollection(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		ProductIDs []uuid.UUID `json:"product_ids"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	var ordered []uuid.UUID
+	err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		current, err := q.GetCollectionProductIDs(r.Context(), collection.ID)
+		if err != nil {
+			return err
+		}
+
+		ordered, err = collections.Reorder(current, params.ProductIDs)
+		if err != nil {
+			return service.Invalid(err.Error())
+		}
+
+		for i, productID := range ordered {
+			err = q.SetCollectionProductPosition(r.Context(), database.SetCollectionProductPositionParams{
+				CollectionID: collection.ID,
+				ProductID:    productID,
+				Position:     int32(i + 1),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to reorder collection products")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant collection products reordered",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"collection_id", collection.ID,
+		"product_count", len(ordered),
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerTenantCollectionProductsList lists a collection's products in display order
+func (cfg *apiConfig) handlerTenantCollectionProductsList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	collection, ok := cfg.loadStoreCollection(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	rows, page, ok := cfg.collectionProductsPage(w, r, collection.ID, false, uuid.NullUUID{})
+	if !ok {
+		return
+	}
+
+	productIDs := make([]uuid.UUID, 0, len(rows))
+	for _, product := range rows {
+		productIDs = append(productIDs, product.ID)
+	}
+
+	media, err := cfg.productMedia(r.Context(), productIDs)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product media", err)
+		return
+	}
+
+	response := make([]TenantProductResponse, 0, len(rows))
+	for _, product := range rows {
+		var desc, skuPtr, tagsPtr *string
+		if product.Description.Valid {
+			desc = &product.Description.String
+		}
+		if product.Sku.Valid {
+			skuPtr = &product.Sku.String
+		}
+		if product.Tags.Valid {
+			tagsPtr = &product.Tags.String
+		}
+
+		response = append(response, TenantProductResponse{
+			ID:               product.ID,
+			StoreID:          product.StoreID,
+			Handle:           product.Handle,
+			Name:             product.Name,
+			Description:      desc,
+			InventoryTracked: product.InventoryTracked,
+			OversellPolicy:   product.OversellPolicy,
+			SKU:              skuPtr,
+			Tags:             tagsPtr,
+			Status:           product.Status,
+			Media:            media[product.ID],
+			CreatedAt:        product.CreatedAt,
+			UpdatedAt:        product.UpdatedAt,
+		})
+	}
+
+	respondWithJSON(w, http.StatusOK, PageResponse[TenantProductResponse]{Data: response, Page: page})
+}
+
+// respondWithCollections writes one page of a store's collections, newest first
+func (cfg *apiConfig) respondWithCollections(w http.ResponseWriter, r *http.Request, storeID uuid.UUID) {
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+
+	limit := pageParams.Limit
+	limitPlusOne := int32(pageParams.Limit + 1)
+
+	cursor, hasCursor, err := collectionCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.reads.GetCollectionsByStorePaginated(r.Context(), database.GetCollectionsByStorePaginatedParams{
+		StoreID:         storeID,
+		HasCursor:       hasCursor,
+		CursorCreatedAt: cursor.CreatedAt,
+		CursorID:        cursor.ID,
+		RowLimit:        limitPlusOne,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve collections", err)
+		return
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = collectionCursorCodec.Encode(CollectionCursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]CollectionResponse, 0, len(rows))
+	for _, collection := range rows {
+		response = append(response, collectionToResponse(collection))
+	}
+
+	respondWithJSON(w, http.StatusOK, PageResponse[CollectionResponse]{
+		Data: response,
+		Page: PageInfo{Limit: limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
+
+// collectionProductsPage fetches one page of a collection's products in
+// display order, returning the rows and the "page" object for the response.
+// A valid channelID keeps only products published to that channel.
+func (cfg *apiConfig) collectionProductsPage(w http.ResponseWriter, r *http.Request, collectionID uuid.UUID, activeOnly bool, channelID uuid.NullUUID) ([]database.GetCollectionProductsPaginatedRow, PageInfo, bool) {
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return nil, PageInfo{}, false
+	}
+
+	limit := pageParams.Limit
+	limitPlusOne := int32(pageParams.Limit + 1)
+
+	cursor, hasCursor, err := collectionProductCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return nil, PageInfo{}, false
+	}
+
+	rows, err := cfg.reads.GetCollectionProductsPaginated(r.Context(), database.GetCollectionProductsPaginatedParams{
+		CollectionID:   collectionID,
+		ActiveOnly:     activeOnly,
+		ChannelID:      channelID,
+		HasCursor:      hasCursor,
+		CursorPosition: cursor.Position,
+		CursorID:       cursor.ID,
+		RowLimit:       limitPlusOne,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve collection products", err)
+		return nil, PageInfo{}, false
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = collectionProductCursorCodec.Encode(CollectionProductCursor{
+			Position: last.Position,
+			ID:       last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return nil, PageInfo{}, false
+		}
+	}
+
+	return rows, PageInfo{Limit: limit, NextCursor: nextCursor, HasMore: hasMore}, true
+}
+
+// addCollectionProducts appends products to a manual collection after checking
+// they belong to its store
+func addCollectionProducts(ctx context.Context, q *database.Queries, collection database.Collection, productIDs []uuid.UUID) error {
+	for _, productID := range productIDs {
+		_, err := q.GetProductByID(ctx, database.GetProductByIDParams{
+			ID:      productID,
+			StoreID: collection.StoreID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return service.Invalid("Product " + productID.String() + " not found in this store")
+		}
+		if err != nil {
+			return err
+		}
+
+		err = q.AddProductToCollection(ctx, database.AddProductToCollectionParams{
+			CollectionID: collection.ID,
+			ProductID:    productID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cfg *apiConfig) loadStoreCollection(w http.ResponseWriter, r *http.Request, storeID uuid.UUID) (database.Collection, bool) {
+	collectionID, err := uuid.Parse(chi.URLParam(r, "collectionID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid collection ID format", err)
+		return database.Collection{}, false
+	}
+
+	collection, err := cfg.db.GetCollectionByID(r.Context(), database.GetCollectionByIDParams{
+		ID:      collectionID,
+		StoreID: storeID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Collection not found", nil)
+			return database.Collection{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve collection", err)
+		return database.Collection{}, false
+	}
+	return collection, true
+}
+
+// refreshAutomatedCollection re-evaluates the collection's rules against every
+// product in its store. Products that still match keep their position; new
+// matches are appended.
+func refreshAutomatedCollection(ctx context.Context, q *database.Queries, collection database.Collection) error {
+	var rules []collections.Rule
+	if err := json.Unmarshal(collection.Rules, &rules); err != nil {
+		return err
+	}
+
+	candidates, err := q.GetCollectionMatchCandidates(ctx, database.GetCollectionMatchCandidatesParams{
+		StoreID: collection.StoreID,
+	})
+	if err != nil {
+		return err
+	}
+
+	current, err := q.GetCollectionProductIDs(ctx, collection.ID)
+	if err != nil {
+		return err
+	}
+
+	matching := make(map[uuid.UUID]bool, len(candidates))
+	for _, candidate := range candidates {
+		if collections.Matches(rules, collection.MatchAny, matchCandidate(candidate)) {
+			matching[candidate.ID] = true
+		}
+	}
+
+	for _, productID := range current {
+		if matching[productID] {
+			continue
+		}
+		_, err := q.RemoveProductFromCollection(ctx, database.RemoveProductFromCollectionParams{
+			CollectionID: collection.ID,
+			ProductID:    productID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, candidate := range candidates {
+		if !matching[candidate.ID] {
+			continue
+		}
+		err := q.AddProductToCollection(ctx, database.AddProductToCollectionParams{
+			CollectionID: collection.ID,
+			ProductID:    candidate.ID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncProductCollections adds or removes a single product from the store's
+// automated collections after the product or its variants change
+func syncProductCollections(ctx context.Context, q *database.Queries, storeID, productID uuid.UUID) error {
+	automated, err := q.GetAutomatedCollectionsByStore(ctx, storeID)
+	if err != nil || len(automated) == 0 {
+		return err
+	}
+
+	candidates, err := q.GetCollectionMatchCandidates(ctx, database.GetCollectionMatchCandidatesParams{
+		StoreID:   storeID,
+		ProductID: uuid.NullUUID{UUID: productID, Valid: true},
+	})
+	if err != nil || len(candidates) == 0 {
+		return err
+	}
+	product := matchCandidate(candidates[0])
+
+	for _, collection := range automated {
+		var rules []collections.Rule
+		if err := json.Unmarshal(collection.Rules, &rules); err != nil {
+			return err
+		}
+
+		if collections.Matches(rules, collection.MatchAny, product) {
+			err = q.AddProductToCollection(ctx, database.AddProductToCollectionParams{
+				CollectionID: collection.ID,
+				ProductID:    productID,
+			})
+		} else {
+			_, err = q.RemoveProductFromCollection(ctx, database.RemoveProductFromCollectionParams{
+				CollectionID: collection.ID,
+				ProductID:    productID,
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncProductCollectionsAfterWrite runs syncProductCollections for a product
+// that was just saved. Failures are logged rather than failing the request;
+// the next rule change on the collection repairs its membership.
+func (cfg *apiConfig) syncProductCollectionsAfterWrite(ctx context.Context, storeID, productID uuid.UUID) {
+	if err := syncProductCollections(ctx, cfg.db, storeID, productID); err != nil {
+		slog.WarnContext(ctx, "automated collection sync failed",
+			"request_id", middleware.GetRequestID(ctx),
+			"store_id", storeID,
+			"product_id", productID,
+			"error", err,
+		)
+	}
+}
+
+func matchCandidate(row database.GetCollectionMatchCandidatesRow) collections.Product {
+	p := collections.Product{
+		Tags:   collections.ParseTags(row.Tags.String),
+		Status: row.Status,
+	}
+	if row.ActiveVariantCount > 0 {
+		price := row.MinPriceCents
+		p.MinPriceCents = &price
+	}
+	return p
+}
+
+func collectionToResponse(collection database.Collection) CollectionResponse {
+	resp := CollectionResponse{
+		ID:        collection.ID,
+		StoreID:   collection.StoreID,
+		Handle:    collection.Handle,
+		Title:     collection.Title,
+		Kind:      collection.Kind,
+		MatchAny:  collection.MatchAny,
+		CreatedAt: collection.CreatedAt,
+		UpdatedAt: collection.UpdatedAt,
+	}
+	if collection.Description.Valid {
+		resp.Description = &collection.Description.String
+	}
+	// Stored rules were validated on write; a decode failure just omits them
+	_ = json.Unmarshal(collection.Rules, &resp.Rules)
+	return resp
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// VariantPriceResponse is a variant's price in one presentment currency
+type VariantPriceResponse struct {
+	Currency       string    `json:"currency"`
+	PriceCents     int32     `json:"price_cents"`
+	CompareAtCents *int32    `json:"compare_at_cents,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// handlerTenantStoreCurrenciesGet returns the store's default currency and
+// the presentment currencies shoppers can pick
+func (cfg *apiConfig) handlerTenantStoreCurrenciesGet(w http.ResponseWriter, r *http.Request) {
+	access := tenantAccessFrom(r)
+
+	currencies, err := cfg.services.stores.Currencies(r.Context(), access.TenantID, access.StoreID)
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to retrieve store currencies")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, currencies)
+}
+
+// handlerTenantStoreCurrenciesUpdate replaces the store's presentment
+// currencies
+func (cfg *apiConfig) handlerTenantStoreCurrenciesUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	type parameters struct {
+		Currencies []string `json:"currencies"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	// The audit log records what the update changed
+	store, err := cfg.db.GetStoreByTenantAndID(r.Context(), database.GetStoreByTenantAndIDParams{
+		TenantID: uuid.NullUUID{UUID: access.TenantID, Valid: true},
+		ID:       access.StoreID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update store currencies", err)
+		return
+	}
+	before, err := cfg.services.stores.Currencies(r.Context(), access.TenantID, access.StoreID)
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to update store currencies")
+		return
+	}
+
+	currencies, err := cfg.services.stores.SetCurrencies(r.Context(), access.TenantID, access.StoreID, params.Currencies)
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to update store currencies")
+		return
+	}
+	auditChange(r, auditGID(gid.EntityStore, store.Gid), before, currencies)
+
+	slog.InfoContext(r.Context(), "store currencies updated",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"store_id", access.StoreID,
+		"currencies", currencies.Enabled,
+	)
+
+	respondWithJSON(w, http.StatusOK, currencies)
+}
+
+// handlerTenantVariantPricesList lists a variant's presentment price
+// overrides. Currencies without one are converted from the base price.
+func (cfg *apiConfig) handlerTenantVariantPricesList(w http.ResponseWriter, r *http.Request) {
+	variant, ok := cfg.tenantVariant(w, r)
+	if !ok {
+		return
+	}
+
+	prices, err := cfg.db.ListVariantPrices(r.Context(), variant.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve variant prices", err)
+		return
+	}
+
+	response := make([]VariantPriceResponse, 0, len(prices))
+	for _, price := range prices {
+		response = append(response, variantPriceToResponse(price))
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{"data": response})
+}
+
+// handlerTenantVariantPriceSet sets a variant's price in one of the store's
+// presentment currencies, in that currency's minor units
+func (cfg *apiConfig) handlerTenantVariantPriceSet(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	variant, ok := cfg.tenantVariant(w, r)
+	if !ok {
+		return
+	}
+	currency, ok := cfg.presentmentCurrency(w, r)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		PriceCents     *int32 `json:"price_cents"`
+		CompareAtCents *int32 `json:"compare_at_cents"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	var v validate.Validator
+	if v.Check(params.PriceCents != nil, "price_cents", validate.CodeRequired, "is required") {
+		v.Between("price_cents", int64(*params.PriceCents), 0, maxPriceCents)
+	}
+	if params.CompareAtCents != nil {
+		v.Between("compare_at_cents", int64(*params.CompareAtCents), 0, maxPriceCents)
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	var compareAt sql.NullInt32
+	if params.CompareAtCents != nil {
+		compareAt = sql.NullInt32{Int32: *params.CompareAtCents, Valid: true}
+	}
+
+	// Kept for the audit log
+	var before any
+	existing, err := cfg.db.ListVariantPrices(r.Context(), variant.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to set variant price", err)
+		return
+	}
+	if i := slices.IndexFunc(existing, func(p database.VariantPrice) bool { return p.Currency == currency }); i >= 0 {
+		before = variantPriceToResponse(existing[i])
+	}
+
+	price, err := cfg.db.UpsertVariantPrice(r.Context(), database.UpsertVariantPriceParams{
+		VariantID:      variant.ID,
+		StoreID:        variant.StoreID,
+		Currency:       currency,
+		PriceCents:     *params.PriceCents,
+		CompareAtCents: compareAt,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to set variant price", err)
+		return
+	}
+	auditChange(r, auditGID(gid.EntityProductVariant, variant.Gid), before, variantPriceToResponse(price))
+
+	slog.InfoContext(r.Context(), "variant price set",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"variant_id", variant.ID,
+		"currency", currency,
+	)
+
+	respondWithJSON(w, http.StatusOK, variantPriceToResponse(price))
+}
+
+// handlerTenantVariantPriceDelete removes a variant's price override, so
+// the currency goes back to converting the base price
+func (cfg *apiConfig) handlerTenantVariantPriceDelete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	variant, ok := cfg.tenantVariant(w, r)
+	if !ok {
+		return
+	}
+	currency := chi.URLParam(r, "currency")
+
+	// Kept for the audit log
+	existing, err := cfg.db.ListVariantPrices(r.Context(), variant.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to delete variant price", err)
+		return
+	}
+	i := slices.IndexFunc(existing, func(p database.VariantPrice) bool { return p.Currency == currency })
+	if i < 0 {
+		respondWithError(w, http.StatusNotFound, "Variant price not found", nil)
+		return
+	}
+
+	if _, err := cfg.db.DeleteVariantPrice(r.Context(), database.DeleteVariantPriceParams{
+		VariantID: variant.ID,
+		Currency:  currency,
+	}); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to delete variant price", err)
+		return
+	}
+	auditChange(r, auditGID(gid.EntityProductVariant, variant.Gid), variantPriceToResponse(existing[i]), nil)
+
+	slog.InfoContext(r.Context(), "variant price deleted",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"variant_id", variant.ID,
+		"currency", currency,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// tenantVariant loads the {variantID} of the route, making sure it belongs
+// to {productID} in the store the caller was let into. It has responded
+// when it returns false.
+func (cfg *apiConfig) tenantVariant(w http.ResponseWriter, r *http.Request) (database.ProductVariant, bool) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid product ID format", err)
+		return database.ProductVariant{}, false
+	}
+	variantID, err := uuid.Parse(chi.URLParam(r, "variantID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid variant ID format", err)
+		return database.ProductVariant{}, false
+	}
+
+	variant, err := cfg.db.GetProductVariantByID(r.Context(), variantID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && (variant.ProductID != productID || variant.StoreID != tenantAccessFrom(r).StoreID)) {
+		respondWithError(w, http.StatusNotFound, "Variant not found", nil)
+		return database.ProductVariant{}, false
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve variant", err)
+		return database.ProductVariant{}, false
+	}
+	return variant, true
+}
+
+// presentmentCurrency reads the {currency} of the route, which must be one
+// of the store's presentment currencies. It has responded when it returns
+// false.
+func (cfg *apiConfig) presentmentCurrency(w http.ResponseWriter, r *http.Request) (string, bool) {
+	access := tenantAccessFrom(r)
+	currency := chi.URLParam(r, "currency")
+
+	currencies, err := cfg.services.stores.Currencies(r.Context(), access.TenantID, access.StoreID)
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to retrieve store currencies")
+		return "", false
+	}
+	if !slices.Contains(currencies.Enabled, currency) {
+		respondWithError(w, http.StatusUnprocessableEntity, "Currency "+currency+" is not enabled for this store", nil)
+		return "", false
+	}
+	return currency, true
+}
+
+func variantPriceToResponse(price database.VariantPrice) VariantPriceResponse {
+	resp := VariantPriceResponse{
+		Currency:   price.Currency,
+		PriceCents: price.PriceCents,
+		UpdatedAt:  price.UpdatedAt,
+	}
+	if price.CompareAtCents.Valid {
+		resp.CompareAtCents = &price.CompareAtCents.Int32
+	}
+	return resp
+}
@@ -0,0 +1,545 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/pricelists"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/segments"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// CustomerGroupResponse is a group of customers price lists are assigned to
+// and customer exports are filtered by
+type CustomerGroupResponse struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Kind string    `json:"kind"`
+	// Rules and RecalculatedAt are only set on rule groups. A rule group
+	// not yet recalculated since its rules changed has no RecalculatedAt.
+	Rules          *segments.Rules `json:"rules,omitempty"`
+	RecalculatedAt *time.Time      `json:"recalculated_at,omitempty"`
+	CustomerCount  *int64          `json:"customer_count,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// handlerTenantCustomerGroupsList lists a store's customer groups
+func (cfg *apiConfig) handlerTenantCustomerGroupsList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	groups, err := cfg.db.ListCustomerGroups(r.Context(), storeID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve customer groups", err)
+		return
+	}
+
+	response := make([]CustomerGroupResponse, 0, len(groups))
+	for _, g := range groups {
+		resp := customerGroupToResponse(database.CustomerGroup{
+			ID:                 g.ID,
+			Name:               g.Name,
+			Kind:               g.Kind,
+			MinTotalSpentCents: g.MinTotalSpentCents,
+			MaxTotalSpentCents: g.MaxTotalSpentCents,
+			MinOrderCount:      g.MinOrderCount,
+			MaxOrderCount:      g.MaxOrderCount,
+			Tags:               g.Tags,
+			RecalculatedAt:     g.RecalculatedAt,
+			CreatedAt:          g.CreatedAt,
+			UpdatedAt:          g.UpdatedAt,
+		})
+		resp.CustomerCount = &g.CustomerCount
+		response = append(response, resp)
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{"data": response})
+}
+
+// handlerTenantCustomerGroupsCreate adds a customer group to a store. The
+// members of a rule group are worked out in the background.
+func (cfg *apiConfig) handlerTenantCustomerGroupsCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	type parameters struct {
+		Name  string          `json:"name"`
+		Kind  string          `json:"kind"`
+		Rules *segments.Rules `json:"rules"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+	if params.Kind == "" {
+		params.Kind = string(segments.KindManual)
+	}
+
+	params.Name = strings.TrimSpace(params.Name)
+	var v validate.Validator
+	if v.Required("name", params.Name) {
+		v.MaxLength("name", params.Name, pricelists.MaxNameLength)
+	}
+	var rules segments.Rules
+	if v.OneOf("kind", params.Kind, string(segments.KindManual), string(segments.KindRule)) {
+		rules = validateCustomerGroupRules(&v, segments.Kind(params.Kind), params.Rules)
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	createParams := database.CreateCustomerGroupParams{
+		Gid:      sql.NullInt64{Int64: int64(cfg.gidGen.Generate()), Valid: true},
+		TenantID: access.TenantID,
+		StoreID:  access.StoreID,
+		Name:     params.Name,
+		Kind:     params.Kind,
+		Tags:     []string{},
+	}
+	if segments.Kind(params.Kind) == segments.KindRule {
+		createParams.MinTotalSpentCents, createParams.MaxTotalSpentCents, createParams.MinOrderCount, createParams.MaxOrderCount = customerGroupRuleColumns(rules)
+		createParams.Tags = rules.Tags
+	}
+
+	// A rule group is only recalculated once it exists
+	var group database.CustomerGroup
+	err := cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		var err error
+		group, err = q.CreateCustomerGroup(r.Context(), createParams)
+		if err != nil || segments.Kind(group.Kind) != segments.KindRule {
+			return err
+		}
+		_, err = cfg.jobs.EnqueueTx(r.Context(), q, segments.RecalculateArgs{GroupID: group.ID})
+		return err
+	})
+	if service.UniqueViolation(err, "") {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeAlreadyExists, "A customer group with this name already exists", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create customer group", err)
+		return
+	}
+	auditChange(r, auditGID(gid.EntityCustomerGroup, group.Gid), nil, customerGroupToResponse(group))
+
+	slog.InfoContext(r.Context(), "customer group created",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"store_id", access.StoreID,
+		"group_id", group.ID,
+		"kind", group.Kind,
+	)
+
+	respondWithJSON(w, http.StatusCreated, customerGroupToResponse(group))
+}
+
+// handlerTenantCustomerGroupGet returns a customer group
+func (cfg *apiConfig) handlerTenantCustomerGroupGet(w http.ResponseWriter, r *http.Request) {
+	group, ok := cfg.loadCustomerGroup(w, r)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, customerGroupToResponse(group))
+}
+
+// handlerTenantCustomerGroupUpdate renames a customer group and, for a rule
+// group, replaces its rules when they are given. New rules are applied in
+// the background.
+func (cfg *apiConfig) handlerTenantCustomerGroupUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	existing, ok := cfg.loadCustomerGroup(w, r)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		Name  string          `json:"name"`
+		Rules *segments.Rules `json:"rules"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	params.Name = strings.TrimSpace(params.Name)
+	var v validate.Validator
+	if v.Required("name", params.Name) {
+		v.MaxLength("name", params.Name, pricelists.MaxNameLength)
+	}
+	var rules segments.Rules
+	if params.Rules != nil {
+		rules = validateCustomerGroupRules(&v, segments.Kind(existing.Kind), params.Rules)
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	var group database.CustomerGroup
+	err := cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		var err error
+		group, err = q.UpdateCustomerGroup(r.Context(), database.UpdateCustomerGroupParams{
+			ID:      existing.ID,
+			StoreID: access.StoreID,
+			Name:    params.Name,
+		})
+		if err != nil || params.Rules == nil {
+			return err
+		}
+		ruleParams := database.UpdateCustomerGroupRulesParams{
+			ID:      existing.ID,
+			StoreID: access.StoreID,
+			Tags:    rules.Tags,
+		}
+		ruleParams.MinTotalSpentCents, ruleParams.MaxTotalSpentCents, ruleParams.MinOrderCount, ruleParams.MaxOrderCount = customerGroupRuleColumns(rules)
+		if group, err = q.UpdateCustomerGroupRules(r.Context(), ruleParams); err != nil {
+			return err
+		}
+		_, err = cfg.jobs.EnqueueTx(r.Context(), q, segments.RecalculateArgs{GroupID: group.ID})
+		return err
+	})
+	if service.UniqueViolation(err, "") {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeAlreadyExists, "A customer group with this name already exists", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update customer group", err)
+		return
+	}
+	auditChange(r, auditGID(gid.EntityCustomerGroup, group.Gid), customerGroupToResponse(existing), customerGroupToResponse(group))
+
+	slog.InfoContext(r.Context(), "customer group updated",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"group_id", group.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, customerGroupToResponse(group))
+}
+
+// handlerTenantCustomerGroupRecalculate queues a recalculation of the
+// members of a rule group, such as after customers were tagged
+func (cfg *apiConfig) handlerTenantCustomerGroupRecalculate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	group, ok := cfg.loadCustomerGroup(w, r)
+	if !ok {
+		return
+	}
+	if segments.Kind(group.Kind) != segments.KindRule {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeConflict, "Only rule groups are recalculated", nil)
+		return
+	}
+
+	job, err := cfg.jobs.Enqueue(r.Context(), segments.RecalculateArgs{GroupID: group.ID})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to schedule recalculation", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "customer group recalculation queued",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"group_id", group.ID,
+		"job_id", job.ID,
+	)
+
+	respondWithJSON(w, http.StatusAccepted, customerGroupToResponse(group))
+}
+
+// handlerTenantCustomerGroupDelete deletes a customer group. Its customers
+// stop getting the prices of the lists assigned to it.
+func (cfg *apiConfig) handlerTenantCustomerGroupDelete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	group, ok := cfg.loadCustomerGroup(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := cfg.db.DeleteCustomerGroup(r.Context(), database.DeleteCustomerGroupParams{
+		ID:      group.ID,
+		StoreID: access.StoreID,
+	}); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to delete customer group", err)
+		return
+	}
+	auditChange(r, auditGID(gid.EntityCustomerGroup, group.Gid), customerGroupToResponse(group), nil)
+
+	slog.InfoContext(r.Context(), "customer group deleted",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"group_id", group.ID,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerTenantCustomerGroupMembersList lists the customers in a group,
+// newest first
+func (cfg *apiConfig) handlerTenantCustomerGroupMembersList(w http.ResponseWriter, r *http.Request) {
+	group, ok := cfg.loadCustomerGroup(w, r)
+	if !ok {
+		return
+	}
+
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+	limit := pageParams.Limit
+
+	cursor, hasCursor, err := customerCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.db.GetCustomerGroupMembersPaginated(r.Context(), database.GetCustomerGroupMembersPaginatedParams{
+		GroupID:         group.ID,
+		HasCursor:       hasCursor,
+		CursorCreatedAt: cursor.CreatedAt,
+		CursorID:        cursor.ID,
+		RowLimit:        int32(limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve customers", err)
+		return
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = customerCursorCodec.Encode(CustomerCursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]TenantCustomerResponse, 0, len(rows))
+	for _, customer := range rows {
+		response = append(response, tenantCustomerToResponse(customer))
+	}
+
+	respondWithJSON(w, http.StatusOK, PageResponse[TenantCustomerResponse]{
+		Data: response,
+		Page: PageInfo{Limit: limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
+
+// handlerTenantCustomerGroupMemberAdd puts a customer in a manual group.
+// Adding a customer already in it does nothing.
+func (cfg *apiConfig) handlerTenantCustomerGroupMemberAdd(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	group, ok := cfg.loadManualCustomerGroup(w, r)
+	if !ok {
+		return
+	}
+	customer, ok := cfg.loadStoreCustomer(w, r, access.StoreID)
+	if !ok {
+		return
+	}
+
+	if err := cfg.db.AddCustomerToGroup(r.Context(), database.AddCustomerToGroupParams{
+		GroupID:    group.ID,
+		CustomerID: customer.ID,
+	}); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to add customer to group", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "customer added to group",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"group_id", group.ID,
+		"customer_id", customer.ID,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerTenantCustomerGroupMemberRemove takes a customer out of a manual
+// group
+func (cfg *apiConfig) handlerTenantCustomerGroupMemberRemove(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	group, ok := cfg.loadManualCustomerGroup(w, r)
+	if !ok {
+		return
+	}
+	customerID, err := uuid.Parse(chi.URLParam(r, "customerID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID format", err)
+		return
+	}
+
+	removed, err := cfg.db.RemoveCustomerFromGroup(r.Context(), database.RemoveCustomerFromGroupParams{
+		GroupID:    group.ID,
+		CustomerID: customerID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to remove customer from group", err)
+		return
+	}
+	if removed == 0 {
+		respondWithError(w, http.StatusNotFound, "Customer is not in this group", nil)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "customer removed from group",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"group_id", group.ID,
+		"customer_id", customerID,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadCustomerGroup loads the {groupID} of the route from the store the
+// caller was let into. It has responded when it returns false.
+func (cfg *apiConfig) loadCustomerGroup(w http.ResponseWriter, r *http.Request) (database.CustomerGroup, bool) {
+	groupID, err := uuid.Parse(chi.URLParam(r, "groupID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer group ID format", err)
+		return database.CustomerGroup{}, false
+	}
+
+	group, err := cfg.db.GetCustomerGroupByID(r.Context(), database.GetCustomerGroupByIDParams{
+		ID:      groupID,
+		StoreID: tenantAccessFrom(r).StoreID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Customer group not found", nil)
+		return database.CustomerGroup{}, false
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve customer group", err)
+		return database.CustomerGroup{}, false
+	}
+	return group, true
+}
+
+// loadManualCustomerGroup is loadCustomerGroup for changes to the members,
+// which the rules of a rule group set
+func (cfg *apiConfig) loadManualCustomerGroup(w http.ResponseWriter, r *http.Request) (database.CustomerGroup, bool) {
+	group, ok := cfg.loadCustomerGroup(w, r)
+	if !ok {
+		return database.CustomerGroup{}, false
+	}
+	if segments.Kind(group.Kind) != segments.KindManual {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeConflict, "The customers of a rule group are set by its rules", nil)
+		return database.CustomerGroup{}, false
+	}
+	return group, true
+}
+
+// validateCustomerGroupRules checks the rules given for a group of kind and
+// returns them normalized. Only rule groups have rules, and they need them.
+func validateCustomerGroupRules(v *validate.Validator, kind segments.Kind, rules *segments.Rules) segments.Rules {
+	if kind != segments.KindRule {
+		v.Check(rules == nil, "rules", validate.CodeInvalid, "is only allowed on rule groups")
+		return segments.Rules{}
+	}
+	if rules == nil {
+		v.Fail("rules", validate.CodeRequired, "is required for rule groups")
+		return segments.Rules{}
+	}
+	normalized, err := rules.Normalize()
+	if err != nil {
+		v.Fail("rules", validate.CodeInvalid, err.Error())
+	}
+	return normalized
+}
+
+// customerGroupRuleColumns splits rules into the nullable columns they are
+// stored in
+func customerGroupRuleColumns(rules segments.Rules) (minSpent, maxSpent sql.NullInt64, minOrders, maxOrders sql.NullInt32) {
+	if rules.MinTotalSpentCents != nil {
+		minSpent = sql.NullInt64{Int64: *rules.MinTotalSpentCents, Valid: true}
+	}
+	if rules.MaxTotalSpentCents != nil {
+		maxSpent = sql.NullInt64{Int64: *rules.MaxTotalSpentCents, Valid: true}
+	}
+	if rules.MinOrderCount != nil {
+		minOrders = sql.NullInt32{Int32: *rules.MinOrderCount, Valid: true}
+	}
+	if rules.MaxOrderCount != nil {
+		maxOrders = sql.NullInt32{Int32: *rules.MaxOrderCount, Valid: true}
+	}
+	return minSpent, maxSpent, minOrders, maxOrders
+}
+
+func customerGroupToResponse(g database.CustomerGroup) CustomerGroupResponse {
+	resp := CustomerGroupResponse{
+		ID:        g.ID,
+		Name:      g.Name,
+		Kind:      g.Kind,
+		CreatedAt: g.CreatedAt,
+		UpdatedAt: g.UpdatedAt,
+	}
+	if segments.Kind(g.Kind) != segments.KindRule {
+		return resp
+	}
+	rules := segments.Rules{Tags: g.Tags}
+	if g.MinTotalSpentCents.Valid {
+		rules.MinTotalSpentCents = &g.MinTotalSpentCents.Int64
+	}
+	if g.MaxTotalSpentCents.Valid {
+		rules.MaxTotalSpentCents = &g.MaxTotalSpentCents.Int64
+	}
+	if g.MinOrderCount.Valid {
+		rules.MinOrderCount = &g.MinOrderCount.Int32
+	}
+	if g.MaxOrderCount.Valid {
+		rules.MaxOrderCount = &g.MaxOrderCount.Int32
+	}
+	resp.Rules = &rules
+	if g.RecalculatedAt.Valid {
+		resp.RecalculatedAt = &g.RecalculatedAt.Time
+	}
+	return resp
+}
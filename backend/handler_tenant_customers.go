@@ -0,0 +1,357 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/crypto"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/segments"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// TenantCustomerResponse is a customer as merchants see it, with the
+// internal fields shoppers never get
+type TenantCustomerResponse struct {
+	CustomerResponse
+	Tags []string `json:"tags"`
+	// ErasedAt is set once the customer's personal data has been erased
+	ErasedAt *time.Time `json:"erased_at,omitempty"`
+}
+
+type CustomerTagCount struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+type CustomerCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+}
+
+var customerCursorCodec = CursorCodec[CustomerCursor]{
+	Validate: func(c CustomerCursor) error {
+		if c.CreatedAt.IsZero() || c.ID == uuid.Nil {
+			return errors.New("invalid cursor: missing required fields")
+		}
+		return nil
+	},
+}
+
+// handlerTenantCustomersList lists a store's customers, newest first,
+// filtered by tag, marketing consent and group
+func (cfg *apiConfig) handlerTenantCustomersList(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	filter, err := segments.ParseFilter(r.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+
+	limit := pageParams.Limit
+
+	cursor, hasCursor, err := customerCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	params := database.ListFilteredCustomersParams{
+		StoreID:         storeID,
+		Tags:            filter.Tags,
+		HasCursor:       hasCursor,
+		CursorCreatedAt: cursor.CreatedAt,
+		CursorID:        cursor.ID,
+		RowLimit:        int32(limit + 1),
+	}
+	if filter.AcceptsMarketing != nil {
+		params.AcceptsMarketing = sql.NullBool{Bool: *filter.AcceptsMarketing, Valid: true}
+	}
+	if filter.GroupID != nil {
+		params.GroupID = uuid.NullUUID{UUID: *filter.GroupID, Valid: true}
+	}
+	rows, err := cfg.db.ListFilteredCustomers(r.Context(), params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve customers", err)
+		return
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = customerCursorCodec.Encode(CustomerCursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]TenantCustomerResponse, 0, len(rows))
+	for _, customer := range rows {
+		response = append(response, tenantCustomerToResponse(customer))
+	}
+
+	slog.InfoContext(r.Context(), "tenant customers list successful",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"customer_count", len(response),
+		"has_more", hasMore,
+	)
+
+	respondWithJSON(w, http.StatusOK, PageResponse[TenantCustomerResponse]{
+		Data: response,
+		Page: PageInfo{Limit: limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
+
+// handlerTenantCustomerGet returns a single customer
+func (cfg *apiConfig) handlerTenantCustomerGet(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	customer, ok := cfg.loadStoreCustomer(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, tenantCustomerToResponse(customer))
+}
+
+// handlerTenantCustomerUpdate lets staff edit a customer's profile or disable the account
+func (cfg *apiConfig) handlerTenantCustomerUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	existing, ok := cfg.loadStoreCustomer(w, r, storeID)
+	if !ok {
+		return
+	}
+	if existing.ErasedAt.Valid {
+		respondWithError(w, http.StatusConflict, "The customer has been erased and can't be edited", nil)
+		return
+	}
+
+	type parameters struct {
+		FirstName        *string `json:"first_name"`
+		LastName         *string `json:"last_name"`
+		Phone            *string `json:"phone"`
+		AcceptsMarketing *bool   `json:"accepts_marketing"`
+		Status           *string `json:"status"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	if params.Status != nil && *params.Status != "enabled" && *params.Status != "disabled" {
+		respondWithError(w, http.StatusBadRequest, "Status must be 'enabled' or 'disabled'", nil)
+		return
+	}
+
+	firstName := existing.FirstName
+	if params.FirstName != nil {
+		firstName = sql.NullString{String: *params.FirstName, Valid: true}
+	}
+
+	lastName := existing.LastName
+	if params.LastName != nil {
+		lastName = sql.NullString{String: *params.LastName, Valid: true}
+	}
+
+	phone := existing.Phone
+	if params.Phone != nil {
+		phone = crypto.NullText{String: *params.Phone, Valid: true}
+	}
+
+	acceptsMarketing := existing.AcceptsMarketing
+	if params.AcceptsMarketing != nil {
+		acceptsMarketing = *params.AcceptsMarketing
+	}
+
+	// The profile and status change together or not at all
+	var customer database.Customer
+	err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		customer, err = q.UpdateCustomerProfile(r.Context(), database.UpdateCustomerProfileParams{
+			ID:               existing.ID,
+			StoreID:          storeID,
+			FirstName:        firstName,
+			LastName:         lastName,
+			Phone:            phone,
+			AcceptsMarketing: acceptsMarketing,
+		})
+		if err != nil || params.Status == nil || *params.Status == customer.Status {
+			return err
+		}
+		customer, err = q.UpdateCustomerStatus(r.Context(), database.UpdateCustomerStatusParams{
+			ID:      existing.ID,
+			StoreID: storeID,
+			Status:  *params.Status,
+		})
+		return err
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update customer", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant customer updated successfully",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"customer_id", customer.ID,
+		"status", customer.Status,
+	)
+
+	respondWithJSON(w, http.StatusOK, tenantCustomerToResponse(customer))
+}
+
+// handlerTenantCustomerTagsUpdate replaces a customer's tags. Rule groups
+// matching on tags pick the change up when they are next recalculated.
+func (cfg *apiConfig) handlerTenantCustomerTagsUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	customer, ok := cfg.loadStoreCustomer(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		Tags []string `json:"tags"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	tags, err := segments.NormalizeTags(params.Tags)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	customer, err = cfg.db.UpdateCustomerTags(r.Context(), database.UpdateCustomerTagsParams{
+		ID:      customer.ID,
+		StoreID: storeID,
+		Tags:    tags,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update customer tags", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant customer tags updated",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"customer_id", customer.ID,
+		"tag_count", len(tags),
+	)
+
+	respondWithJSON(w, http.StatusOK, tenantCustomerToResponse(customer))
+}
+
+// handlerTenantCustomerTagsList lists the tags in use across a store's
+// customers, most used first
+func (cfg *apiConfig) handlerTenantCustomerTagsList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	rows, err := cfg.db.GetCustomerTagsByStore(r.Context(), storeID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve customer tags", err)
+		return
+	}
+
+	response := make([]CustomerTagCount, 0, len(rows))
+	for _, row := range rows {
+		response = append(response, CustomerTagCount{Tag: row.Tag, Count: row.Count})
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{"data": response})
+}
+
+// handlerTenantCustomerOrdersList returns a customer's order history for staff
+func (cfg *apiConfig) handlerTenantCustomerOrdersList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	customer, ok := cfg.loadStoreCustomer(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	cfg.respondWithCustomerOrders(w, r, customer.ID)
+}
+
+func (cfg *apiConfig) loadStoreCustomer(w http.ResponseWriter, r *http.Request, storeID uuid.UUID) (database.Customer, bool) {
+	customerID, err := uuid.Parse(chi.URLParam(r, "customerID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid customer ID format", err)
+		return database.Customer{}, false
+	}
+
+	customer, err := cfg.db.GetCustomerByID(r.Context(), database.GetCustomerByIDParams{
+		ID:      customerID,
+		StoreID: storeID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Customer not found", nil)
+			return database.Customer{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve customer", err)
+		return database.Customer{}, false
+	}
+	return customer, true
+}
+
+func tenantCustomerToResponse(customer database.Customer) TenantCustomerResponse {
+	resp := TenantCustomerResponse{
+		CustomerResponse: customerToResponse(customer),
+		Tags:             customer.Tags,
+	}
+	if resp.Tags == nil {
+		resp.Tags = []string{}
+	}
+	if customer.ErasedAt.Valid {
+		resp.ErasedAt = &customer.ErasedAt.Time
+	}
+	return resp
+}
@@ -0,0 +1,389 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/mailer"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// EmailTemplateResponse is a store's wording for one of the customer order
+// emails. Empty parts use the built-in template.
+type EmailTemplateResponse struct {
+	Template   string                `json:"template"`
+	Customized bool                  `json:"customized"`
+	Subject    string                `json:"subject"`
+	Text       string                `json:"text"`
+	HTML       string                `json:"html"`
+	Variables  []string              `json:"variables"`
+	UpdatedAt  *time.Time            `json:"updated_at,omitempty"`
+	Preview    *EmailPreviewResponse `json:"preview,omitempty"`
+}
+
+// EmailPreviewResponse is an email rendered with a sample order
+type EmailPreviewResponse struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
+
+// handlerTenantEmailTemplatesList lists every order email with the store's
+// wording, if it has any
+func (cfg *apiConfig) handlerTenantEmailTemplatesList(w http.ResponseWriter, r *http.Request) {
+	access := tenantAccessFrom(r)
+
+	rows, err := cfg.db.GetStoreEmailTemplates(r.Context(), access.StoreID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve email templates", err)
+		return
+	}
+	custom := make(map[string]database.StoreEmailTemplate, len(rows))
+	for _, row := range rows {
+		custom[row.Template] = row
+	}
+
+	resp := make([]EmailTemplateResponse, 0, len(mailer.OrderTemplates))
+	for _, tmpl := range mailer.OrderTemplates {
+		row, ok := custom[string(tmpl)]
+		resp = append(resp, emailTemplateToResponse(tmpl, row, ok))
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerTenantEmailTemplateGet returns one order email's wording and a
+// preview of it rendered with a sample order
+func (cfg *apiConfig) handlerTenantEmailTemplateGet(w http.ResponseWriter, r *http.Request) {
+	access := tenantAccessFrom(r)
+
+	tmpl, ok := emailTemplateParam(w, r)
+	if !ok {
+		return
+	}
+	store, ok := cfg.loadEmailTemplateStore(w, r, access.TenantID, access.StoreID)
+	if !ok {
+		return
+	}
+	row, customized, err := cfg.storeEmailTemplate(r, store.ID, tmpl)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve email template", err)
+		return
+	}
+
+	resp := emailTemplateToResponse(tmpl, row, customized)
+	msg, err := mailer.RenderOrder(tmpl, mailer.SampleOrderData(store.Name, store.DefaultCurrency), customFromRow(row))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to render email template", err)
+		return
+	}
+	resp.Preview = &EmailPreviewResponse{Subject: msg.Subject, Text: msg.Text, HTML: msg.HTML}
+
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerTenantEmailTemplateUpdate sets the store's wording for an order
+// email. Sending every part empty goes back to the built-in template.
+func (cfg *apiConfig) handlerTenantEmailTemplateUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	tmpl, ok := emailTemplateParam(w, r)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		Subject string `json:"subject"`
+		Text    string `json:"text"`
+		HTML    string `json:"html"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+	custom := mailer.Custom{
+		Subject: strings.TrimSpace(params.Subject),
+		Text:    strings.TrimSpace(params.Text),
+		HTML:    strings.TrimSpace(params.HTML),
+	}
+	if err := validateCustomEmail(custom); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	store, ok := cfg.loadEmailTemplateStore(w, r, access.TenantID, access.StoreID)
+	if !ok {
+		return
+	}
+	before, customized, err := cfg.storeEmailTemplate(r, store.ID, tmpl)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update email template", err)
+		return
+	}
+
+	var row database.StoreEmailTemplate
+	if custom.IsZero() {
+		_, err = cfg.db.DeleteStoreEmailTemplate(r.Context(), database.DeleteStoreEmailTemplateParams{
+			StoreID:  store.ID,
+			Template: string(tmpl),
+		})
+	} else {
+		row, err = cfg.db.UpsertStoreEmailTemplate(r.Context(), database.UpsertStoreEmailTemplateParams{
+			StoreID:  store.ID,
+			Template: string(tmpl),
+			Subject:  custom.Subject,
+			BodyText: custom.Text,
+			BodyHtml: custom.HTML,
+		})
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update email template", err)
+		return
+	}
+	resp := emailTemplateToResponse(tmpl, row, !custom.IsZero())
+	auditChange(r, auditGID(gid.EntityStore, store.Gid), emailTemplateToResponse(tmpl, before, customized), resp)
+
+	slog.InfoContext(r.Context(), "tenant email template updated",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"store_id", access.StoreID,
+		"template", tmpl,
+		"customized", resp.Customized,
+	)
+
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerTenantEmailTemplateDelete puts an order email back to the
+// built-in template
+func (cfg *apiConfig) handlerTenantEmailTemplateDelete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	tmpl, ok := emailTemplateParam(w, r)
+	if !ok {
+		return
+	}
+	store, ok := cfg.loadEmailTemplateStore(w, r, access.TenantID, access.StoreID)
+	if !ok {
+		return
+	}
+	before, customized, err := cfg.storeEmailTemplate(r, store.ID, tmpl)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to reset email template", err)
+		return
+	}
+	if !customized {
+		respondWithError(w, http.StatusNotFound, "Email template has not been customized", nil)
+		return
+	}
+
+	_, err = cfg.db.DeleteStoreEmailTemplate(r.Context(), database.DeleteStoreEmailTemplateParams{
+		StoreID:  store.ID,
+		Template: string(tmpl),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to reset email template", err)
+		return
+	}
+	auditChange(r, auditGID(gid.EntityStore, store.Gid), emailTemplateToResponse(tmpl, before, true), nil)
+
+	slog.InfoContext(r.Context(), "tenant email template reset",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"store_id", access.StoreID,
+		"template", tmpl,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerTenantEmailTemplateTest sends an order email, rendered with a
+// sample order, to the caller or to the address given. Unsaved wording can
+// be tried by sending it in the body; otherwise the saved wording is used.
+func (cfg *apiConfig) handlerTenantEmailTemplateTest(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	tmpl, ok := emailTemplateParam(w, r)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		To      string  `json:"to"`
+		Subject *string `json:"subject"`
+		Text    *string `json:"text"`
+		HTML    *string `json:"html"`
+	}
+
+	params := parameters{}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+			return
+		}
+	}
+
+	store, ok := cfg.loadEmailTemplateStore(w, r, access.TenantID, access.StoreID)
+	if !ok {
+		return
+	}
+
+	to := strings.TrimSpace(params.To)
+	if to == "" {
+		user, err := cfg.db.GetUserByID(r.Context(), access.UserID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve user", err)
+			return
+		}
+		to = user.Email
+	}
+
+	row, _, err := cfg.storeEmailTemplate(r, store.ID, tmpl)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve email template", err)
+		return
+	}
+	custom := customFromRow(row)
+	if params.Subject != nil || params.Text != nil || params.HTML != nil {
+		custom = mailer.Custom{}
+		for _, part := range []struct {
+			dst *string
+			src *string
+		}{{&custom.Subject, params.Subject}, {&custom.Text, params.Text}, {&custom.HTML, params.HTML}} {
+			if part.src != nil {
+				*part.dst = strings.TrimSpace(*part.src)
+			}
+		}
+	}
+
+	var v validate.Validator
+	v.Email("to", to)
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+	if err := validateCustomEmail(custom); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	msg, err := mailer.RenderOrder(tmpl, mailer.SampleOrderData(store.Name, store.DefaultCurrency), custom)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to render email template", err)
+		return
+	}
+	msg.Subject = "[Test] " + msg.Subject
+	msg.To = []string{to}
+	if err := mailer.Enqueue(r.Context(), cfg.jobs, cfg.db, msg); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to queue test email", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant email template test sent",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"store_id", access.StoreID,
+		"template", tmpl,
+	)
+
+	respondWithJSON(w, http.StatusAccepted, map[string]any{
+		"to":      to,
+		"preview": EmailPreviewResponse{Subject: msg.Subject, Text: msg.Text, HTML: msg.HTML},
+	})
+}
+
+// emailTemplateParam reads the {template} URL parameter, which must name
+// one of the order emails
+func emailTemplateParam(w http.ResponseWriter, r *http.Request) (mailer.Template, bool) {
+	tmpl := mailer.Template(chi.URLParam(r, "template"))
+	if !slices.Contains(mailer.OrderTemplates, tmpl) {
+		respondWithError(w, http.StatusNotFound, "Email template not found", nil)
+		return "", false
+	}
+	return tmpl, true
+}
+
+func (cfg *apiConfig) loadEmailTemplateStore(w http.ResponseWriter, r *http.Request, tenantID, storeID uuid.UUID) (database.Store, bool) {
+	store, err := cfg.db.GetStoreByTenantAndID(r.Context(), database.GetStoreByTenantAndIDParams{
+		TenantID: uuid.NullUUID{UUID: tenantID, Valid: true},
+		ID:       storeID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Store not found", nil)
+			return database.Store{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve store", err)
+		return database.Store{}, false
+	}
+	return store, true
+}
+
+// storeEmailTemplate returns the store's wording for tmpl, reporting false
+// when it has none
+func (cfg *apiConfig) storeEmailTemplate(r *http.Request, storeID uuid.UUID, tmpl mailer.Template) (database.StoreEmailTemplate, bool, error) {
+	row, err := cfg.db.GetStoreEmailTemplate(r.Context(), database.GetStoreEmailTemplateParams{
+		StoreID:  storeID,
+		Template: string(tmpl),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.StoreEmailTemplate{}, false, nil
+	}
+	if err != nil {
+		return database.StoreEmailTemplate{}, false, err
+	}
+	return row, true, nil
+}
+
+// validateCustomEmail checks the lengths of c's parts and that they only
+// use known variables
+func validateCustomEmail(c mailer.Custom) error {
+	var v validate.Validator
+	v.MaxLength("subject", c.Subject, mailer.MaxCustomSubjectLength)
+	v.MaxLength("text", c.Text, mailer.MaxCustomBodyLength)
+	v.MaxLength("html", c.HTML, mailer.MaxCustomBodyLength)
+	for _, part := range []struct{ field, s string }{{"subject", c.Subject}, {"text", c.Text}, {"html", c.HTML}} {
+		if unknown := mailer.UnknownVariables(part.s); len(unknown) > 0 {
+			v.Fail(part.field, validate.CodeInvalid, "uses unknown variables "+strings.Join(unknown, ", ")+"; see variables")
+		}
+	}
+	return v.Err()
+}
+
+func customFromRow(row database.StoreEmailTemplate) mailer.Custom {
+	return mailer.Custom{Subject: row.Subject, Text: row.BodyText, HTML: row.BodyHtml}
+}
+
+func emailTemplateToResponse(tmpl mailer.Template, row database.StoreEmailTemplate, customized bool) EmailTemplateResponse {
+	resp := EmailTemplateResponse{
+		Template:   string(tmpl),
+		Customized: customized,
+		Subject:    row.Subject,
+		Text:       row.BodyText,
+		HTML:       row.BodyHtml,
+		Variables:  mailer.OrderVariables,
+	}
+	if customized {
+		resp.UpdatedAt = &row.UpdatedAt
+	}
+	return resp
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/dfodeker/terminus/internal/service/entitlements"
+)
+
+type apiRateResponse struct {
+	Requests      int `json:"requests"`
+	WindowSeconds int `json:"window_seconds"`
+}
+
+type entitlementsUsageResponse struct {
+	Stores     int64 `json:"stores"`
+	StaffSeats int64 `json:"staff_seats"`
+}
+
+type entitlementsResponse struct {
+	// Plan is the best plan among the tenant's stores. Product limits
+	// follow each store's own plan.
+	Plan    string                    `json:"plan"`
+	Limits  entitlements.Limits       `json:"limits"`
+	APIRate apiRateResponse           `json:"api_rate"`
+	Usage   entitlementsUsageResponse `json:"usage"`
+}
+
+// handlerTenantEntitlements reports the tenant's plan, what it allows and
+// how much of that is in use. Limits of zero are unlimited.
+func (cfg *apiConfig) handlerTenantEntitlements(w http.ResponseWriter, r *http.Request) {
+	access := tenantAccessFrom(r)
+
+	usage, err := cfg.services.entitlements.Usage(r.Context(), access.TenantID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve entitlements", err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, entitlementsResponse{
+		Plan:   usage.Plan.Name,
+		Limits: usage.Plan.Limits,
+		APIRate: apiRateResponse{
+			Requests:      usage.Plan.APIRate.Requests,
+			WindowSeconds: int(usage.Plan.APIRate.Window.Seconds()),
+		},
+		Usage: entitlementsUsageResponse{Stores: usage.Stores, StaffSeats: usage.StaffSeats},
+	})
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/catalog"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/export"
+	"github.com/dfodeker/terminus/internal/orders"
+	"github.com/dfodeker/terminus/internal/segments"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// exportDownloadURLTTL is how long a download link handed out for a
+// finished export works; polling again gives a fresh one
+const exportDownloadURLTTL = 15 * time.Minute
+
+type ExportResponse struct {
+	ID       uuid.UUID `json:"id"`
+	StoreID  uuid.UUID `json:"store_id"`
+	Resource string    `json:"resource"`
+	Format   string    `json:"format"`
+	Columns  []string  `json:"columns"`
+	// Filters is the list endpoint query string the export was made with
+	Filters   string  `json:"filters"`
+	Status    string  `json:"status"`
+	RowCount  *int32  `json:"row_count,omitempty"`
+	SizeBytes *int64  `json:"size_bytes,omitempty"`
+	Error     *string `json:"error,omitempty"`
+	// DownloadURL is set once the export has completed
+	DownloadURL       *string    `json:"download_url,omitempty"`
+	DownloadExpiresAt *time.Time `json:"download_expires_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	StartedAt         *time.Time `json:"started_at,omitempty"`
+	FinishedAt        *time.Time `json:"finished_at,omitempty"`
+}
+
+// handlerTenantExportCreate queues an export of the store's products,
+// orders or customers. The query string takes the same filters as the resource's list
+// endpoint; the body picks the format and, optionally, the columns.
+func (cfg *apiConfig) handlerTenantExportCreate(resource string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqID := middleware.GetRequestID(r.Context())
+
+		access := tenantAccessFrom(r)
+		user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+		type parameters struct {
+			Format  string   `json:"format"`
+			Columns []string `json:"columns"`
+		}
+
+		decoder := json.NewDecoder(r.Body)
+		params := parameters{}
+		err := decoder.Decode(&params)
+		if err != nil && !errors.Is(err, io.EOF) {
+			respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+			return
+		}
+		if params.Format == "" {
+			params.Format = export.FormatCSV
+		}
+
+		// Pagination doesn't apply to an export, and search isn't a filter
+		query := r.URL.Query()
+		query.Del("limit")
+		query.Del("cursor")
+		if query.Has("q") {
+			respondWithError(w, http.StatusBadRequest, "Search results cannot be exported; use filters instead", nil)
+			return
+		}
+
+		var v validate.Validator
+		v.OneOf("format", params.Format, export.FormatCSV, export.FormatJSONL)
+		switch resource {
+		case export.ResourceProducts:
+			if _, err := catalog.ParseFilter(query); err != nil {
+				respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+				return
+			}
+			_, err = export.Select(export.ProductColumns, params.Columns)
+		case export.ResourceOrders:
+			if _, err := orders.ParseFilter(query); err != nil {
+				respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+				return
+			}
+			_, err = export.Select(export.OrderColumns, params.Columns)
+		case export.ResourceCustomers:
+			if _, err := segments.ParseFilter(query); err != nil {
+				respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+				return
+			}
+			_, err = export.Select(export.CustomerColumns, params.Columns)
+		}
+		if err != nil {
+			v.Fail("columns", validate.CodeInvalid, err.Error())
+		}
+		if err := v.Err(); err != nil {
+			respondWithValidationError(w, err)
+			return
+		}
+		if params.Columns == nil {
+			params.Columns = []string{}
+		}
+
+		var exp database.Export
+		err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+			var err error
+			exp, err = q.CreateExport(r.Context(), database.CreateExportParams{
+				StoreID:   storeID,
+				CreatedBy: uuid.NullUUID{UUID: user, Valid: true},
+				Resource:  resource,
+				Format:    params.Format,
+				Columns:   params.Columns,
+				Filters:   query.Encode(),
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = cfg.jobs.EnqueueTx(r.Context(), q, export.Args{ExportID: exp.ID})
+			return err
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to create export", err)
+			return
+		}
+
+		slog.InfoContext(r.Context(), "tenant export queued",
+			"request_id", reqID,
+			"user_id", user,
+			"tenant_id", tenantID,
+			"store_id", storeID,
+			"export_id", exp.ID,
+			"resource", resource,
+			"format", exp.Format,
+		)
+
+		respondWithJSON(w, http.StatusAccepted, exportToResponse(exp))
+	}
+}
+
+// handlerTenantExportGet reports an export's progress, with a short-lived
+// download link once it has completed
+func (cfg *apiConfig) handlerTenantExportGet(resource string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		storeID := tenantAccessFrom(r).StoreID
+
+		exportID, err := uuid.Parse(chi.URLParam(r, "exportID"))
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid export ID", err)
+			return
+		}
+
+		exp, err := cfg.db.GetExport(r.Context(), database.GetExportParams{
+			ID:       exportID,
+			StoreID:  storeID,
+			Resource: resource,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				respondWithError(w, http.StatusNotFound, "Export not found", nil)
+				return
+			}
+			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve export", err)
+			return
+		}
+
+		resp := exportToResponse(exp)
+		if exp.Status == export.StatusCompleted && exp.StorageKey.Valid {
+			download, err := cfg.storage.PresignGet(r.Context(), exp.StorageKey.String, exportDownloadURLTTL)
+			if err != nil {
+				respondWithError(w, http.StatusInternalServerError, "Unable to create download URL", err)
+				return
+			}
+			resp.DownloadURL = &download.URL
+			resp.DownloadExpiresAt = &download.ExpiresAt
+		}
+
+		respondWithJSON(w, http.StatusOK, resp)
+	}
+}
+
+func exportToResponse(exp database.Export) ExportResponse {
+	resp := ExportResponse{
+		ID:        exp.ID,
+		StoreID:   exp.StoreID,
+		Resource:  exp.Resource,
+		Format:    exp.Format,
+		Columns:   exp.Columns,
+		Filters:   exp.Filters,
+		Status:    exp.Status,
+		CreatedAt: exp.CreatedAt,
+	}
+	if len(resp.Columns) == 0 {
+		// No selection means every column, in the default order
+		if exp.Resource == export.ResourceOrders {
+			resp.Columns = export.ColumnNames(export.OrderColumns)
+		} else {
+			resp.Columns = export.ColumnNames(export.ProductColumns)
+		}
+	}
+	if exp.RowCount.Valid {
+		resp.RowCount = &exp.RowCount.Int32
+	}
+	if exp.SizeBytes.Valid {
+		resp.SizeBytes = &exp.SizeBytes.Int64
+	}
+	if exp.Error.Valid {
+		resp.Error = &exp.Error.String
+	}
+	if exp.StartedAt.Valid {
+		resp.StartedAt = &exp.StartedAt.Time
+	}
+	if exp.FinishedAt.Valid {
+		resp.FinishedAt = &exp.FinishedAt.Time
+	}
+	return resp
+}
@@ -0,0 +1,606 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/fulfillment"
+	"github.com/dfodeker/terminus/internal/mailer"
+	"github.com/dfodeker/terminus/internal/orders"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type FulfillmentResponse struct {
+	ID             uuid.UUID                     `json:"id"`
+	OrderID        uuid.UUID                     `json:"order_id"`
+	Status         string                        `json:"status"`
+	Carrier        *string                       `json:"carrier,omitempty"`
+	TrackingNumber *string                       `json:"tracking_number,omitempty"`
+	TrackingURL    *string                       `json:"tracking_url,omitempty"`
+	LineItems      []FulfillmentLineItemResponse `json:"line_items"`
+	ShippedAt      *time.Time                    `json:"shipped_at,omitempty"`
+	DeliveredAt    *time.Time                    `json:"delivered_at,omitempty"`
+	CancelledAt    *time.Time                    `json:"cancelled_at,omitempty"`
+	CreatedAt      time.Time                     `json:"created_at"`
+	UpdatedAt      time.Time                     `json:"updated_at"`
+	// Label is set once a shipping label was bought, and TrackingStatus is
+	// the latest the carrier reported for it
+	Label             *FulfillmentLabelResponse `json:"label,omitempty"`
+	TrackingStatus    *string                   `json:"tracking_status,omitempty"`
+	TrackingUpdatedAt *time.Time                `json:"tracking_updated_at,omitempty"`
+}
+
+// FulfillmentLabelResponse is a shipping label bought for a fulfillment
+type FulfillmentLabelResponse struct {
+	Provider    string    `json:"provider"`
+	URL         string    `json:"url"`
+	CostCents   int64     `json:"cost_cents"`
+	Currency    string    `json:"currency"`
+	PurchasedAt time.Time `json:"purchased_at"`
+}
+
+type FulfillmentLineItemResponse struct {
+	LineItemID uuid.UUID `json:"line_item_id"`
+	Quantity   int32     `json:"quantity"`
+}
+
+// handlerTenantFulfillmentsCreate fulfills some or all of an order's
+// remaining items. Omitting items fulfills everything left.
+func (cfg *apiConfig) handlerTenantFulfillmentsCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "orderID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid order ID format", err)
+		return
+	}
+
+	type item struct {
+		LineItemID uuid.UUID `json:"line_item_id"`
+		Quantity   int64     `json:"quantity"`
+	}
+	type parameters struct {
+		Items          []item             `json:"items"`
+		Status         fulfillment.Status `json:"status"`
+		Carrier        *string            `json:"carrier"`
+		TrackingNumber *string            `json:"tracking_number"`
+		TrackingURL    *string            `json:"tracking_url"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	if params.Status == "" {
+		params.Status = fulfillment.StatusPending
+	}
+	if params.Status != fulfillment.StatusPending && params.Status != fulfillment.StatusShipped {
+		respondWithError(w, http.StatusBadRequest, "Status must be 'pending' or 'shipped'", nil)
+		return
+	}
+
+	var order database.Order
+	var f database.Fulfillment
+	var lineItems []FulfillmentLineItemResponse
+	err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		var err error
+		// Lock the order so concurrent fulfillments can't over-fulfill it
+		order, err = q.LockOrderForUpdate(r.Context(), database.LockOrderForUpdateParams{
+			ID:      orderID,
+			StoreID: storeID,
+		})
+		if err != nil {
+			return service.NotFoundAs(err, "Order not found")
+		}
+		if order.Status == "cancelled" {
+			return service.Conflict("Cancelled orders cannot be fulfilled")
+		}
+
+		lines, err := orderFulfillmentLines(r.Context(), q, order.ID)
+		if err != nil {
+			return err
+		}
+
+		requested := make([]fulfillment.Item, 0, len(params.Items))
+		for _, it := range params.Items {
+			requested = append(requested, fulfillment.Item{LineItemID: it.LineItemID, Quantity: it.Quantity})
+		}
+		items, err := fulfillment.Allocate(lines, requested)
+		if err != nil {
+			return abortTx(http.StatusUnprocessableEntity, err.Error(), err)
+		}
+
+		trackingURL := nullStringFromPtr(params.TrackingURL)
+		if !trackingURL.Valid && params.Carrier != nil && params.TrackingNumber != nil {
+			if u := fulfillment.TrackingURL(*params.Carrier, *params.TrackingNumber); u != "" {
+				trackingURL = sql.NullString{String: u, Valid: true}
+			}
+		}
+
+		f, err = q.CreateFulfillment(r.Context(), database.CreateFulfillmentParams{
+			Gid:            sql.NullInt64{Int64: int64(cfg.gidGen.Generate()), Valid: true},
+			StoreID:        storeID,
+			OrderID:        order.ID,
+			Status:         string(params.Status),
+			Carrier:        nullStringFromPtr(params.Carrier),
+			TrackingNumber: nullStringFromPtr(params.TrackingNumber),
+			TrackingUrl:    trackingURL,
+		})
+		if err != nil {
+			return err
+		}
+
+		lineItems = make([]FulfillmentLineItemResponse, 0, len(items))
+		for _, it := range items {
+			err = q.CreateFulfillmentLineItem(r.Context(), database.CreateFulfillmentLineItemParams{
+				FulfillmentID:   f.ID,
+				OrderLineItemID: it.LineItemID,
+				Quantity:        int32(it.Quantity),
+			})
+			if err != nil {
+				return err
+			}
+			lineItems = append(lineItems, FulfillmentLineItemResponse{LineItemID: it.LineItemID, Quantity: int32(it.Quantity)})
+		}
+
+		_, err = recordOrderEvent(r.Context(), q, order, orders.EventFulfillment,
+			fmt.Sprintf("Fulfillment created as %s", f.Status),
+			map[string]any{"fulfillment_id": f.ID, "status": f.Status, "line_items": lineItems}, user)
+		if err != nil {
+			return err
+		}
+
+		if params.Status == fulfillment.StatusShipped {
+			err = cfg.queueOrderEmail(r.Context(), q, mailer.OrderEmailArgs{
+				Template:      mailer.TemplateOrderShipped,
+				OrderID:       order.ID,
+				StoreID:       storeID,
+				FulfillmentID: f.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		order, err = syncOrderFulfillmentStatus(r.Context(), q, order)
+		return err
+	})
+	if err != nil {
+		respondWithTxError(w, err, "Unable to create fulfillment")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant fulfillment created",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"order_id", order.ID,
+		"fulfillment_id", f.ID,
+		"order_fulfillment_status", order.FulfillmentStatus,
+	)
+
+	respondWithJSON(w, http.StatusCreated, map[string]any{
+		"fulfillment":        fulfillmentToResponse(f, lineItems),
+		"fulfillment_status": order.FulfillmentStatus,
+	})
+}
+
+// handlerTenantFulfillmentsList lists an order's fulfillments, oldest first
+func (cfg *apiConfig) handlerTenantFulfillmentsList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	order, ok := cfg.loadStoreOrder(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	fulfillments, err := cfg.db.GetFulfillmentsByOrder(r.Context(), order.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve fulfillments", err)
+		return
+	}
+
+	items, err := cfg.orderFulfillmentItems(r.Context(), order.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve fulfillment line items", err)
+		return
+	}
+
+	response := make([]FulfillmentResponse, 0, len(fulfillments))
+	for _, f := range fulfillments {
+		response = append(response, fulfillmentToResponse(f, items[f.ID]))
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{
+		"data":               response,
+		"fulfillment_status": order.FulfillmentStatus,
+	})
+}
+
+// handlerTenantFulfillmentGet returns a single fulfillment
+func (cfg *apiConfig) handlerTenantFulfillmentGet(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	order, ok := cfg.loadStoreOrder(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	f, ok := cfg.loadOrderFulfillment(w, r, cfg.db, order.ID)
+	if !ok {
+		return
+	}
+
+	items, err := cfg.orderFulfillmentItems(r.Context(), order.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve fulfillment line items", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, fulfillmentToResponse(f, items[f.ID]))
+}
+
+// handlerTenantFulfillmentUpdate changes a fulfillment's tracking details
+func (cfg *apiConfig) handlerTenantFulfillmentUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	order, ok := cfg.loadStoreOrder(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	f, ok := cfg.loadOrderFulfillment(w, r, cfg.db, order.ID)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		Carrier        *string `json:"carrier"`
+		TrackingNumber *string `json:"tracking_number"`
+		TrackingURL    *string `json:"tracking_url"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	if f.Status == string(fulfillment.StatusCancelled) {
+		respondWithError(w, http.StatusConflict, "Cancelled fulfillments cannot be changed", nil)
+		return
+	}
+
+	carrier := f.Carrier
+	if params.Carrier != nil {
+		carrier = nullStringFromPtr(params.Carrier)
+	}
+	trackingNumber := f.TrackingNumber
+	if params.TrackingNumber != nil {
+		trackingNumber = nullStringFromPtr(params.TrackingNumber)
+	}
+	trackingURL := f.TrackingUrl
+	if params.TrackingURL != nil {
+		trackingURL = nullStringFromPtr(params.TrackingURL)
+	} else if params.Carrier != nil || params.TrackingNumber != nil {
+		// The old link points at the old number; rebuild it when we can
+		trackingURL = sql.NullString{}
+		if u := fulfillment.TrackingURL(carrier.String, trackingNumber.String); u != "" {
+			trackingURL = sql.NullString{String: u, Valid: true}
+		}
+	}
+
+	f, err = cfg.db.UpdateFulfillmentTracking(r.Context(), database.UpdateFulfillmentTrackingParams{
+		ID:             f.ID,
+		OrderID:        order.ID,
+		Carrier:        carrier,
+		TrackingNumber: trackingNumber,
+		TrackingUrl:    trackingURL,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update fulfillment", err)
+		return
+	}
+
+	items, err := cfg.orderFulfillmentItems(r.Context(), order.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve fulfillment line items", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant fulfillment tracking updated",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"order_id", order.ID,
+		"fulfillment_id", f.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, fulfillmentToResponse(f, items[f.ID]))
+}
+
+// handlerTenantFulfillmentStatusUpdate moves a fulfillment along
+// pending → shipped → delivered, or cancels it. Cancelling releases its
+// items and rolls the order's fulfillment status back.
+func (cfg *apiConfig) handlerTenantFulfillmentStatusUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "orderID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid order ID format", err)
+		return
+	}
+
+	fulfillmentID, err := uuid.Parse(chi.URLParam(r, "fulfillmentID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid fulfillment ID format", err)
+		return
+	}
+
+	type parameters struct {
+		Status fulfillment.Status `json:"status"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	var order database.Order
+	var f database.Fulfillment
+	err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		var err error
+		order, err = q.LockOrderForUpdate(r.Context(), database.LockOrderForUpdateParams{
+			ID:      orderID,
+			StoreID: storeID,
+		})
+		if err != nil {
+			return service.NotFoundAs(err, "Order not found")
+		}
+
+		f, err = q.GetFulfillmentByID(r.Context(), database.GetFulfillmentByIDParams{
+			ID:      fulfillmentID,
+			OrderID: order.ID,
+		})
+		if err != nil {
+			return service.NotFoundAs(err, "Fulfillment not found")
+		}
+
+		if err := fulfillment.CanTransition(fulfillment.Status(f.Status), params.Status); err != nil {
+			return service.Conflict(err.Error())
+		}
+
+		f, err = q.UpdateFulfillmentStatus(r.Context(), database.UpdateFulfillmentStatusParams{
+			Status:  string(params.Status),
+			ID:      f.ID,
+			OrderID: order.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = recordOrderEvent(r.Context(), q, order, orders.EventFulfillment,
+			fmt.Sprintf("Fulfillment marked as %s", f.Status),
+			map[string]any{"fulfillment_id": f.ID, "status": f.Status}, user)
+		if err != nil {
+			return err
+		}
+
+		if params.Status == fulfillment.StatusShipped {
+			err = cfg.queueOrderEmail(r.Context(), q, mailer.OrderEmailArgs{
+				Template:      mailer.TemplateOrderShipped,
+				OrderID:       order.ID,
+				StoreID:       storeID,
+				FulfillmentID: f.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		order, err = syncOrderFulfillmentStatus(r.Context(), q, order)
+		return err
+	})
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to update fulfillment")
+		return
+	}
+
+	items, err := cfg.orderFulfillmentItems(r.Context(), order.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve fulfillment line items", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant fulfillment status updated",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"order_id", order.ID,
+		"fulfillment_id", f.ID,
+		"status", f.Status,
+		"order_fulfillment_status", order.FulfillmentStatus,
+	)
+
+	respondWithJSON(w, http.StatusOK, map[string]any{
+		"fulfillment":        fulfillmentToResponse(f, items[f.ID]),
+		"fulfillment_status": order.FulfillmentStatus,
+	})
+}
+
+// loadStoreOrder parses {orderID} and loads it, scoped to the store
+func (cfg *apiConfig) loadStoreOrder(w http.ResponseWriter, r *http.Request, storeID uuid.UUID) (database.Order, bool) {
+	orderID, err := uuid.Parse(chi.URLParam(r, "orderID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid order ID format", err)
+		return database.Order{}, false
+	}
+
+	order, err := cfg.db.GetOrderByID(r.Context(), database.GetOrderByIDParams{
+		ID:      orderID,
+		StoreID: storeID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Order not found", nil)
+			return database.Order{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve order", err)
+		return database.Order{}, false
+	}
+	return order, true
+}
+
+// loadOrderFulfillment parses {fulfillmentID} and loads it, scoped to the order
+func (cfg *apiConfig) loadOrderFulfillment(w http.ResponseWriter, r *http.Request, q *database.Queries, orderID uuid.UUID) (database.Fulfillment, bool) {
+	fulfillmentID, err := uuid.Parse(chi.URLParam(r, "fulfillmentID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid fulfillment ID format", err)
+		return database.Fulfillment{}, false
+	}
+
+	f, err := q.GetFulfillmentByID(r.Context(), database.GetFulfillmentByIDParams{
+		ID:      fulfillmentID,
+		OrderID: orderID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Fulfillment not found", nil)
+			return database.Fulfillment{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve fulfillment", err)
+		return database.Fulfillment{}, false
+	}
+	return f, true
+}
+
+// orderFulfillmentItems loads the line items of every fulfillment of an order
+func (cfg *apiConfig) orderFulfillmentItems(ctx context.Context, orderID uuid.UUID) (map[uuid.UUID][]FulfillmentLineItemResponse, error) {
+	rows, err := cfg.db.GetFulfillmentLineItemsByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	items := make(map[uuid.UUID][]FulfillmentLineItemResponse)
+	for _, row := range rows {
+		items[row.FulfillmentID] = append(items[row.FulfillmentID], FulfillmentLineItemResponse{
+			LineItemID: row.OrderLineItemID,
+			Quantity:   row.Quantity,
+		})
+	}
+	return items, nil
+}
+
+func orderFulfillmentLines(ctx context.Context, q *database.Queries, orderID uuid.UUID) ([]fulfillment.Line, error) {
+	rows, err := q.GetOrderLineItemFulfillment(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]fulfillment.Line, 0, len(rows))
+	for _, row := range rows {
+		lines = append(lines, fulfillment.Line{
+			ID:        row.ID,
+			Ordered:   int64(row.Quantity),
+			Fulfilled: row.FulfilledQuantity,
+		})
+	}
+	return lines, nil
+}
+
+// syncOrderFulfillmentStatus recomputes the order's fulfillment_status from
+// its non-cancelled fulfillments
+func syncOrderFulfillmentStatus(ctx context.Context, q *database.Queries, order database.Order) (database.Order, error) {
+	lines, err := orderFulfillmentLines(ctx, q, order.ID)
+	if err != nil {
+		return order, fmt.Errorf("order line items: %w", err)
+	}
+
+	status := fulfillment.RollUp(lines)
+	if status == order.FulfillmentStatus {
+		return order, nil
+	}
+
+	return q.UpdateOrderFulfillmentStatus(ctx, database.UpdateOrderFulfillmentStatusParams{
+		ID:                order.ID,
+		StoreID:           order.StoreID,
+		FulfillmentStatus: status,
+	})
+}
+
+func fulfillmentToResponse(f database.Fulfillment, items []FulfillmentLineItemResponse) FulfillmentResponse {
+	resp := FulfillmentResponse{
+		ID:        f.ID,
+		OrderID:   f.OrderID,
+		Status:    f.Status,
+		LineItems: items,
+		CreatedAt: f.CreatedAt,
+		UpdatedAt: f.UpdatedAt,
+	}
+	if resp.LineItems == nil {
+		resp.LineItems = []FulfillmentLineItemResponse{}
+	}
+	if f.Carrier.Valid {
+		resp.Carrier = &f.Carrier.String
+	}
+	if f.TrackingNumber.Valid {
+		resp.TrackingNumber = &f.TrackingNumber.String
+	}
+	if f.TrackingUrl.Valid {
+		resp.TrackingURL = &f.TrackingUrl.String
+	}
+	if f.ShippedAt.Valid {
+		resp.ShippedAt = &f.ShippedAt.Time
+	}
+	if f.DeliveredAt.Valid {
+		resp.DeliveredAt = &f.DeliveredAt.Time
+	}
+	if f.CancelledAt.Valid {
+		resp.CancelledAt = &f.CancelledAt.Time
+	}
+	if f.LabelProvider.Valid {
+		resp.Label = &FulfillmentLabelResponse{
+			Provider:    f.LabelProvider.String,
+			URL:         f.LabelUrl.String,
+			CostCents:   f.LabelCostCents.Int64,
+			Currency:    f.LabelCurrency.String,
+			PurchasedAt: f.LabelPurchasedAt.Time,
+		}
+	}
+	if f.TrackingStatus.Valid {
+		resp.TrackingStatus = &f.TrackingStatus.String
+	}
+	if f.TrackingUpdatedAt.Valid {
+		resp.TrackingUpdatedAt = &f.TrackingUpdatedAt.Time
+	}
+	return resp
+}
@@ -0,0 +1,552 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/giftcard"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type GiftCardResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	StoreID    uuid.UUID  `json:"store_id"`
+	CustomerID *uuid.UUID `json:"customer_id,omitempty"`
+	// Code is only returned when the card is issued
+	Code                string     `json:"code,omitempty"`
+	LastCharacters      string     `json:"last_characters"`
+	Currency            string     `json:"currency"`
+	InitialBalanceCents int64      `json:"initial_balance_cents"`
+	BalanceCents        int64      `json:"balance_cents"`
+	Status              string     `json:"status"`
+	Note                *string    `json:"note,omitempty"`
+	ExpiresAt           *time.Time `json:"expires_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+type GiftCardTransactionResponse struct {
+	ID                uuid.UUID  `json:"id"`
+	GiftCardID        uuid.UUID  `json:"gift_card_id"`
+	Kind              string     `json:"kind"`
+	AmountCents       int64      `json:"amount_cents"`
+	BalanceAfterCents int64      `json:"balance_after_cents"`
+	OrderID           *uuid.UUID `json:"order_id,omitempty"`
+	UserID            *uuid.UUID `json:"user_id,omitempty"`
+	Note              *string    `json:"note,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+type GiftCardCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+}
+
+var giftCardCursorCodec = CursorCodec[GiftCardCursor]{
+	Validate: func(c GiftCardCursor) error {
+		if c.CreatedAt.IsZero() || c.ID == uuid.Nil {
+			return errors.New("invalid cursor: missing required fields")
+		}
+		return nil
+	},
+}
+
+// handlerTenantGiftCardsCreate issues a gift card. The code is generated
+// unless the merchant supplies one, and is only ever returned here.
+func (cfg *apiConfig) handlerTenantGiftCardsCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	type parameters struct {
+		InitialBalanceCents int64      `json:"initial_balance_cents"`
+		Code                *string    `json:"code"`
+		Currency            *string    `json:"currency"`
+		CustomerID          *uuid.UUID `json:"customer_id"`
+		Note                *string    `json:"note"`
+		ExpiresAt           *time.Time `json:"expires_at"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	if params.InitialBalanceCents <= 0 {
+		respondWithError(w, http.StatusBadRequest, "Initial balance must be greater than zero", nil)
+		return
+	}
+	if params.ExpiresAt != nil && !params.ExpiresAt.After(time.Now()) {
+		respondWithError(w, http.StatusBadRequest, "Expiry must be in the future", nil)
+		return
+	}
+
+	store, err := cfg.db.GetStoreByTenantAndID(r.Context(), database.GetStoreByTenantAndIDParams{
+		TenantID: uuid.NullUUID{UUID: tenantID, Valid: true},
+		ID:       storeID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve store", err)
+		return
+	}
+	currency := store.DefaultCurrency
+	if params.Currency != nil && *params.Currency != "" {
+		currency = *params.Currency
+	}
+
+	var customerID uuid.NullUUID
+	if params.CustomerID != nil {
+		_, err := cfg.db.GetCustomerByID(r.Context(), database.GetCustomerByIDParams{
+			ID:      *params.CustomerID,
+			StoreID: storeID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				respondWithError(w, http.StatusBadRequest, "Customer not found in this store", nil)
+				return
+			}
+			respondWithError(w, http.StatusInternalServerError, "Unable to verify customer", err)
+			return
+		}
+		customerID = uuid.NullUUID{UUID: *params.CustomerID, Valid: true}
+	}
+
+	var code string
+	if params.Code != nil {
+		if err := giftcard.Validate(*params.Code); err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+		code = giftcard.Normalize(*params.Code)
+
+		_, err = cfg.db.GetGiftCardByCodeHash(r.Context(), database.GetGiftCardByCodeHashParams{
+			StoreID:  storeID,
+			CodeHash: giftcard.Hash(code),
+		})
+		if err == nil {
+			respondWithErrorCode(w, http.StatusConflict, problem.CodeAlreadyExists, "A gift card with this code already exists", nil)
+			return
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusInternalServerError, "Unable to verify gift card code", err)
+			return
+		}
+	} else {
+		code, err = giftcard.GenerateCode()
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to generate gift card code", err)
+			return
+		}
+	}
+
+	var expiresAt sql.NullTime
+	if params.ExpiresAt != nil {
+		expiresAt = sql.NullTime{Time: *params.ExpiresAt, Valid: true}
+	}
+
+	var card database.GiftCard
+	err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		var err error
+		card, err = q.CreateGiftCard(r.Context(), database.CreateGiftCardParams{
+			Gid:                 sql.NullInt64{Int64: int64(cfg.gidGen.Generate()), Valid: true},
+			TenantID:            tenantID,
+			StoreID:             storeID,
+			CustomerID:          customerID,
+			CodeHash:            giftcard.Hash(code),
+			LastCharacters:      giftcard.LastCharacters(code),
+			Currency:            currency,
+			InitialBalanceCents: params.InitialBalanceCents,
+			Note:                nullStringFromPtr(params.Note),
+			ExpiresAt:           expiresAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateGiftCardTransaction(r.Context(), database.CreateGiftCardTransactionParams{
+			GiftCardID:        card.ID,
+			StoreID:           storeID,
+			Kind:              "issue",
+			AmountCents:       card.InitialBalanceCents,
+			BalanceAfterCents: card.BalanceCents,
+			UserID:            uuid.NullUUID{UUID: user, Valid: true},
+		})
+		return err
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to issue gift card", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant gift card issued",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"gift_card_id", card.ID,
+		"balance_cents", card.BalanceCents,
+	)
+
+	resp := giftCardToResponse(card)
+	resp.Code = code
+	respondWithJSON(w, http.StatusCreated, resp)
+}
+
+// handlerTenantGiftCardsList lists a store's gift cards, newest first
+func (cfg *apiConfig) handlerTenantGiftCardsList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+
+	limit := pageParams.Limit
+	limitPlusOne := int32(pageParams.Limit + 1)
+
+	cursor, hasCursor, err := giftCardCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.db.GetGiftCardsByStorePaginated(r.Context(), database.GetGiftCardsByStorePaginatedParams{
+		StoreID:         storeID,
+		HasCursor:       hasCursor,
+		CursorCreatedAt: cursor.CreatedAt,
+		CursorID:        cursor.ID,
+		RowLimit:        limitPlusOne,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve gift cards", err)
+		return
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = giftCardCursorCodec.Encode(GiftCardCursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]GiftCardResponse, 0, len(rows))
+	for _, card := range rows {
+		response = append(response, giftCardToResponse(card))
+	}
+
+	respondWithJSON(w, http.StatusOK, PageResponse[GiftCardResponse]{
+		Data: response,
+		Page: PageInfo{Limit: limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
+
+// handlerTenantGiftCardGet returns a single gift card
+func (cfg *apiConfig) handlerTenantGiftCardGet(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	card, ok := cfg.loadStoreGiftCard(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, giftCardToResponse(card))
+}
+
+// handlerTenantGiftCardUpdate enables or disables a gift card. Disabled cards
+// keep their balance but can't be redeemed.
+func (cfg *apiConfig) handlerTenantGiftCardUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	type parameters struct {
+		Status giftcard.Status `json:"status"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	if !params.Status.IsValid() {
+		respondWithError(w, http.StatusBadRequest, "Status must be 'active' or 'disabled'", nil)
+		return
+	}
+
+	card, ok := cfg.loadStoreGiftCard(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	card, err = cfg.db.UpdateGiftCardStatus(r.Context(), database.UpdateGiftCardStatusParams{
+		ID:      card.ID,
+		StoreID: storeID,
+		Status:  string(params.Status),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update gift card", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant gift card updated",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"gift_card_id", card.ID,
+		"status", card.Status,
+	)
+
+	respondWithJSON(w, http.StatusOK, giftCardToResponse(card))
+}
+
+// handlerTenantGiftCardAdjust credits or debits a card outside of checkout,
+// e.g. to correct a mistake or top up a balance
+func (cfg *apiConfig) handlerTenantGiftCardAdjust(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	type parameters struct {
+		AmountCents int64   `json:"amount_cents"`
+		Note        *string `json:"note"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	if params.AmountCents == 0 {
+		respondWithError(w, http.StatusBadRequest, "Amount must not be zero", nil)
+		return
+	}
+
+	card, ok := cfg.loadStoreGiftCard(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	var txn database.GiftCardTransaction
+	err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		var err error
+		card, err = q.ChangeGiftCardBalance(r.Context(), database.ChangeGiftCardBalanceParams{
+			AmountCents: params.AmountCents,
+			ID:          card.ID,
+			StoreID:     storeID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return service.Conflict("Adjustment would make the balance negative")
+			}
+			return err
+		}
+
+		txn, err = q.CreateGiftCardTransaction(r.Context(), database.CreateGiftCardTransactionParams{
+			GiftCardID:        card.ID,
+			StoreID:           storeID,
+			Kind:              "adjust",
+			AmountCents:       params.AmountCents,
+			BalanceAfterCents: card.BalanceCents,
+			UserID:            uuid.NullUUID{UUID: user, Valid: true},
+			Note:              nullStringFromPtr(params.Note),
+		})
+		return err
+	})
+	if err != nil {
+		respondWithTxError(w, err, "Unable to adjust gift card balance")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant gift card adjusted",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"gift_card_id", card.ID,
+		"amount_cents", params.AmountCents,
+		"balance_cents", card.BalanceCents,
+	)
+
+	respondWithJSON(w, http.StatusCreated, map[string]any{
+		"gift_card":   giftCardToResponse(card),
+		"transaction": giftCardTransactionToResponse(txn),
+	})
+}
+
+// handlerTenantGiftCardTransactionsList returns a card's ledger, newest first
+func (cfg *apiConfig) handlerTenantGiftCardTransactionsList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	card, ok := cfg.loadStoreGiftCard(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+
+	limit := pageParams.Limit
+	limitPlusOne := int32(pageParams.Limit + 1)
+
+	cursor, hasCursor, err := giftCardCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.db.GetGiftCardTransactionsPaginated(r.Context(), database.GetGiftCardTransactionsPaginatedParams{
+		GiftCardID:      card.ID,
+		HasCursor:       hasCursor,
+		CursorCreatedAt: cursor.CreatedAt,
+		CursorID:        cursor.ID,
+		RowLimit:        limitPlusOne,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve gift card transactions", err)
+		return
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = giftCardCursorCodec.Encode(GiftCardCursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]GiftCardTransactionResponse, 0, len(rows))
+	for _, txn := range rows {
+		response = append(response, giftCardTransactionToResponse(txn))
+	}
+
+	respondWithJSON(w, http.StatusOK, PageResponse[GiftCardTransactionResponse]{
+		Data: response,
+		Page: PageInfo{Limit: limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
+
+// loadStoreGiftCard parses {giftCardID} and loads it, scoped to the store
+func (cfg *apiConfig) loadStoreGiftCard(w http.ResponseWriter, r *http.Request, storeID uuid.UUID) (database.GiftCard, bool) {
+	giftCardID, err := uuid.Parse(chi.URLParam(r, "giftCardID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid gift card ID format", err)
+		return database.GiftCard{}, false
+	}
+
+	card, err := cfg.db.GetGiftCardByID(r.Context(), database.GetGiftCardByIDParams{
+		ID:      giftCardID,
+		StoreID: storeID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Gift card not found", nil)
+			return database.GiftCard{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve gift card", err)
+		return database.GiftCard{}, false
+	}
+	return card, true
+}
+
+func giftCardToResponse(card database.GiftCard) GiftCardResponse {
+	resp := GiftCardResponse{
+		ID:                  card.ID,
+		StoreID:             card.StoreID,
+		LastCharacters:      card.LastCharacters,
+		Currency:            card.Currency,
+		InitialBalanceCents: card.InitialBalanceCents,
+		BalanceCents:        card.BalanceCents,
+		Status:              card.Status,
+		CreatedAt:           card.CreatedAt,
+		UpdatedAt:           card.UpdatedAt,
+	}
+	if card.CustomerID.Valid {
+		resp.CustomerID = &card.CustomerID.UUID
+	}
+	if card.Note.Valid {
+		resp.Note = &card.Note.String
+	}
+	if card.ExpiresAt.Valid {
+		resp.ExpiresAt = &card.ExpiresAt.Time
+	}
+	return resp
+}
+
+func giftCardTransactionToResponse(txn database.GiftCardTransaction) GiftCardTransactionResponse {
+	resp := GiftCardTransactionResponse{
+		ID:                txn.ID,
+		GiftCardID:        txn.GiftCardID,
+		Kind:              txn.Kind,
+		AmountCents:       txn.AmountCents,
+		BalanceAfterCents: txn.BalanceAfterCents,
+		CreatedAt:         txn.CreatedAt,
+	}
+	if txn.OrderID.Valid {
+		resp.OrderID = &txn.OrderID.UUID
+	}
+	if txn.UserID.Valid {
+		resp.UserID = &txn.UserID.UUID
+	}
+	if txn.Note.Valid {
+		resp.Note = &txn.Note.String
+	}
+	return resp
+}
+
+// giftCardRedeemable reports why a card can't be used, or "" if it can
+func giftCardRedeemable(card database.GiftCard, now time.Time) string {
+	if card.Status != string(giftcard.StatusActive) {
+		return "Gift card is disabled"
+	}
+	if card.ExpiresAt.Valid && !card.ExpiresAt.Time.After(now) {
+		return "Gift card has expired"
+	}
+	return ""
+}
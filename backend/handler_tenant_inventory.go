@@ -0,0 +1,872 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/bundles"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/inventory"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type InventoryItemResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	TenantID  uuid.UUID  `json:"tenant_id"`
+	StoreID   uuid.UUID  `json:"store_id"`
+	VariantID uuid.UUID  `json:"variant_id"`
+	ProductID *uuid.UUID `json:"product_id,omitempty"`
+	SKU       *string    `json:"sku,omitempty"`
+	Title     string     `json:"title,omitempty"`
+	// OnHand is the total across all locations
+	OnHand int32 `json:"on_hand"`
+	// Reserved is held by active checkout reservations, and Available is
+	// what is left to sell
+	Reserved  int32                    `json:"reserved"`
+	Available int32                    `json:"available"`
+	Levels    []InventoryLevelResponse `json:"levels,omitempty"`
+	CreatedAt time.Time                `json:"created_at"`
+	UpdatedAt time.Time                `json:"updated_at"`
+}
+
+type InventoryLevelResponse struct {
+	LocationID   uuid.UUID `json:"location_id"`
+	LocationName string    `json:"location_name,omitempty"`
+	OnHand       int32     `json:"on_hand"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	// ReorderPoint is the stock at or below which the variant is low at
+	// the location, and ReorderQuantity how many to order when it is
+	ReorderPoint    *int32 `json:"reorder_point,omitempty"`
+	ReorderQuantity *int32 `json:"reorder_quantity,omitempty"`
+}
+
+// ProductInventoryResponse aggregates stock for all of a product's variants.
+// Available is net of what checkout reservations hold, while the stock at
+// each location is what is on hand there.
+type ProductInventoryResponse struct {
+	Available int64                         `json:"available"`
+	Reserved  int64                         `json:"reserved"`
+	Locations []ProductLocationAvailability `json:"locations"`
+}
+
+type ProductLocationAvailability struct {
+	LocationID   uuid.UUID `json:"location_id"`
+	LocationName string    `json:"location_name"`
+	Available    int64     `json:"available"`
+}
+
+type InventoryMovementResponse struct {
+	ID              uuid.UUID  `json:"id"`
+	InventoryItemID uuid.UUID  `json:"inventory_item_id"`
+	LocationID      *uuid.UUID `json:"location_id,omitempty"`
+	TransferID      *uuid.UUID `json:"transfer_id,omitempty"`
+	Delta           int32      `json:"delta"`
+	QuantityAfter   int32      `json:"quantity_after"`
+	Reason          string     `json:"reason"`
+	Note            *string    `json:"note,omitempty"`
+	CreatedBy       *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+type InventoryCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+}
+
+var inventoryCursorCodec = CursorCodec[InventoryCursor]{
+	Validate: func(c InventoryCursor) error {
+		if c.CreatedAt.IsZero() || c.ID == uuid.Nil {
+			return errors.New("invalid cursor: missing required fields")
+		}
+		return nil
+	},
+}
+
+// handlerTenantInventoryList lists stock levels for every tracked variant in a store
+func (cfg *apiConfig) handlerTenantInventoryList(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	tenantParam := chi.URLParam(r, "tenantID")
+	storeParam := chi.URLParam(r, "storeID")
+
+	slog.InfoContext(r.Context(), "tenant inventory list request received",
+		"request_id", reqID,
+		"tenant_param", tenantParam,
+		"store_param", storeParam,
+	)
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+
+	limit := pageParams.Limit
+	limitPlusOne := int32(pageParams.Limit + 1)
+
+	cursor, hasCursor, err := inventoryCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.db.GetInventoryItemsByStorePaginated(r.Context(), database.GetInventoryItemsByStorePaginatedParams{
+		StoreID:         storeID,
+		HasCursor:       hasCursor,
+		CursorCreatedAt: cursor.CreatedAt,
+		CursorID:        cursor.ID,
+		RowLimit:        limitPlusOne,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve inventory", err)
+		return
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = inventoryCursorCodec.Encode(InventoryCursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]InventoryItemResponse, 0, len(rows))
+	for _, row := range rows {
+		var skuPtr *string
+		if row.Sku.Valid {
+			skuPtr = &row.Sku.String
+		}
+		response = append(response, InventoryItemResponse{
+			ID:        row.ID,
+			TenantID:  row.TenantID,
+			StoreID:   row.StoreID,
+			VariantID: row.VariantID,
+			ProductID: &row.ProductID,
+			SKU:       skuPtr,
+			Title:     row.Title,
+			OnHand:    row.OnHand,
+			Reserved:  row.Reserved,
+			Available: max(row.OnHand-row.Reserved, 0),
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+		})
+	}
+
+	slog.InfoContext(r.Context(), "tenant inventory list successful",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"item_count", len(response),
+		"has_more", hasMore,
+	)
+
+	respondWithJSON(w, http.StatusOK, PageResponse[InventoryItemResponse]{
+		Data: response,
+		Page: PageInfo{Limit: limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
+
+// handlerTenantInventoryGet returns the stock level for a single variant
+func (cfg *apiConfig) handlerTenantInventoryGet(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	tenantParam := chi.URLParam(r, "tenantID")
+	storeParam := chi.URLParam(r, "storeID")
+	variantParam := chi.URLParam(r, "variantID")
+
+	slog.InfoContext(r.Context(), "tenant inventory get request received",
+		"request_id", reqID,
+		"tenant_param", tenantParam,
+		"store_param", storeParam,
+		"variant_param", variantParam,
+	)
+
+	storeID := tenantAccessFrom(r).StoreID
+
+	variantID, err := uuid.Parse(variantParam)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid variant ID format", err)
+		return
+	}
+
+	item, err := cfg.db.GetInventoryItemByVariantID(r.Context(), database.GetInventoryItemByVariantIDParams{
+		VariantID: variantID,
+		StoreID:   storeID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "No inventory recorded for this variant", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve inventory", err)
+		return
+	}
+
+	levels, err := cfg.db.GetInventoryLevelsByItem(r.Context(), item.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve inventory levels", err)
+		return
+	}
+
+	response := inventoryItemToResponse(item)
+	response.Levels = make([]InventoryLevelResponse, 0, len(levels))
+	for _, level := range levels {
+		resp := InventoryLevelResponse{
+			LocationID:   level.LocationID,
+			LocationName: level.LocationName,
+			OnHand:       level.OnHand,
+			UpdatedAt:    level.UpdatedAt,
+		}
+		if level.ReorderPoint.Valid {
+			resp.ReorderPoint = &level.ReorderPoint.Int32
+		}
+		if level.ReorderQuantity.Valid {
+			resp.ReorderQuantity = &level.ReorderQuantity.Int32
+		}
+		response.Levels = append(response.Levels, resp)
+	}
+
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// handlerTenantInventoryAdjust applies a stock adjustment and records it in the movement ledger
+func (cfg *apiConfig) handlerTenantInventoryAdjust(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	tenantParam := chi.URLParam(r, "tenantID")
+	storeParam := chi.URLParam(r, "storeID")
+	variantParam := chi.URLParam(r, "variantID")
+
+	slog.InfoContext(r.Context(), "tenant inventory adjustment request received",
+		"request_id", reqID,
+		"tenant_param", tenantParam,
+		"store_param", storeParam,
+		"variant_param", variantParam,
+	)
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	variantID, err := uuid.Parse(variantParam)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid variant ID format", err)
+		return
+	}
+
+	variant, err := cfg.db.GetProductVariantByID(r.Context(), variantID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Variant not found", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve variant", err)
+		return
+	}
+
+	if variant.StoreID != storeID {
+		respondWithError(w, http.StatusNotFound, "Variant not found in this store", nil)
+		return
+	}
+
+	product, err := cfg.db.GetProductByID(r.Context(), database.GetProductByIDParams{
+		ID:      variant.ProductID,
+		StoreID: storeID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product", err)
+		return
+	}
+
+	// A bundle's stock is its components', whatever the bundle product says
+	components, err := cfg.db.ListBundleComponents(r.Context(), variantID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve bundle components", err)
+		return
+	}
+
+	if !product.InventoryTracked && len(components) == 0 {
+		respondWithError(w, http.StatusConflict, "Inventory tracking is disabled for this product", nil)
+		return
+	}
+
+	type parameters struct {
+		Delta  int32   `json:"delta"`
+		Reason string  `json:"reason"`
+		Note   *string `json:"note"`
+		// LocationID defaults to the tenant's default location
+		LocationID *uuid.UUID `json:"location_id"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	reason := inventory.Reason(params.Reason)
+	if err := inventory.ValidateAdjustment(reason, params.Delta); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	note := sql.NullString{}
+	if params.Note != nil {
+		note = sql.NullString{String: *params.Note, Valid: true}
+	}
+
+	if len(components) > 0 {
+		cfg.adjustBundleInventory(w, r, variant, components, reason, params.Delta, note.String, params.LocationID)
+		return
+	}
+
+	// The stock change and its ledger entry must land together
+	var item database.InventoryItem
+	var location database.InventoryLocation
+	var level database.InventoryLevel
+	var movement database.InventoryMovement
+	err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		var err error
+		item, err = q.EnsureInventoryItem(r.Context(), database.EnsureInventoryItemParams{
+			TenantID:  tenantID,
+			StoreID:   storeID,
+			VariantID: variantID,
+		})
+		if err != nil {
+			return err
+		}
+
+		location, err = resolveRestockLocation(r.Context(), q, tenantID, params.LocationID)
+		if err != nil {
+			return err
+		}
+
+		level, err = q.EnsureInventoryLevel(r.Context(), database.EnsureInventoryLevelParams{
+			TenantID:        tenantID,
+			InventoryItemID: item.ID,
+			LocationID:      location.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		level, err = q.AdjustInventoryLevel(r.Context(), database.AdjustInventoryLevelParams{
+			Delta: params.Delta,
+			ID:    level.ID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return abortTxCode(http.StatusConflict, problem.CodeInsufficientStock, "Adjustment would take stock at this location below zero", inventory.ErrInsufficientStock)
+			}
+			return err
+		}
+
+		// Keep the item's aggregate in step with the per-location level
+		item, err = q.AdjustInventoryItem(r.Context(), database.AdjustInventoryItemParams{
+			Delta: params.Delta,
+			ID:    item.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		movement, err = q.CreateInventoryMovement(r.Context(), database.CreateInventoryMovementParams{
+			TenantID:        tenantID,
+			InventoryItemID: item.ID,
+			LocationID:      uuid.NullUUID{UUID: location.ID, Valid: true},
+			Delta:           params.Delta,
+			QuantityAfter:   level.OnHand,
+			Reason:          string(reason),
+			Note:            note,
+			CreatedBy:       uuid.NullUUID{UUID: user, Valid: true},
+		})
+		return err
+	})
+	if err != nil {
+		respondWithTxError(w, err, "Unable to adjust inventory")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant inventory adjusted successfully",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"variant_id", variantID,
+		"delta", params.Delta,
+		"reason", reason,
+		"location_id", location.ID,
+		"on_hand", item.OnHand,
+	)
+
+	respondWithJSON(w, http.StatusCreated, map[string]any{
+		"inventory_item": inventoryItemToResponse(item),
+		"inventory_level": InventoryLevelResponse{
+			LocationID:   location.ID,
+			LocationName: location.Name,
+			OnHand:       level.OnHand,
+			UpdatedAt:    level.UpdatedAt,
+		},
+		"movement": inventoryMovementToResponse(movement),
+	})
+}
+
+// adjustBundleInventory records a bundle sold or returned by moving the
+// stock of its tracked components at one location, all or nothing
+func (cfg *apiConfig) adjustBundleInventory(w http.ResponseWriter, r *http.Request, variant database.ProductVariant, components []database.ListBundleComponentsRow, reason inventory.Reason, delta int32, note string, locationID *uuid.UUID) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	if reason != inventory.ReasonSold && reason != inventory.ReasonReturned {
+		respondWithError(w, http.StatusConflict, "Bundles have no stock of their own, adjust their components instead", nil)
+		return
+	}
+
+	parts := make([]bundles.Component, len(components))
+	for i, c := range components {
+		parts[i] = bundles.Component{VariantID: c.ComponentVariantID, Quantity: c.Quantity}
+	}
+	deltas, err := bundles.Scale(delta, parts)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	if note == "" {
+		note = "Bundle " + variant.Title
+		if variant.Sku.Valid {
+			note = "Bundle " + variant.Sku.String
+		}
+	}
+
+	var location database.InventoryLocation
+	var movements []InventoryMovementResponse
+	err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		var err error
+		location, err = resolveRestockLocation(r.Context(), q, access.TenantID, locationID)
+		if err != nil {
+			return err
+		}
+
+		movements = make([]InventoryMovementResponse, 0, len(components))
+		for i, c := range components {
+			if !c.InventoryTracked {
+				continue
+			}
+			_, movement, err := changeStock(r.Context(), q, stockChange{
+				TenantID:   access.TenantID,
+				StoreID:    access.StoreID,
+				VariantID:  c.ComponentVariantID,
+				LocationID: location.ID,
+				Delta:      deltas[i],
+				Reason:     reason,
+				Note:       note,
+				UserID:     access.UserID,
+			})
+			if errors.Is(err, inventory.ErrInsufficientStock) {
+				return abortTxCode(http.StatusConflict, problem.CodeInsufficientStock, "Not enough stock of a bundle component at this location", err)
+			}
+			if err != nil {
+				return err
+			}
+			movements = append(movements, inventoryMovementToResponse(movement))
+		}
+		return nil
+	})
+	if err != nil {
+		respondWithTxError(w, err, "Unable to adjust inventory")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant bundle inventory adjusted",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"variant_id", variant.ID,
+		"delta", delta,
+		"reason", reason,
+		"location_id", location.ID,
+		"component_count", len(movements),
+	)
+
+	respondWithJSON(w, http.StatusCreated, map[string]any{"movements": movements})
+}
+
+// handlerTenantInventoryMovementsList returns the stock ledger for a variant, newest first
+func (cfg *apiConfig) handlerTenantInventoryMovementsList(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	tenantParam := chi.URLParam(r, "tenantID")
+	storeParam := chi.URLParam(r, "storeID")
+	variantParam := chi.URLParam(r, "variantID")
+
+	slog.InfoContext(r.Context(), "tenant inventory movements request received",
+		"request_id", reqID,
+		"tenant_param", tenantParam,
+		"store_param", storeParam,
+		"variant_param", variantParam,
+	)
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	variantID, err := uuid.Parse(variantParam)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid variant ID format", err)
+		return
+	}
+
+	item, err := cfg.db.GetInventoryItemByVariantID(r.Context(), database.GetInventoryItemByVariantIDParams{
+		VariantID: variantID,
+		StoreID:   storeID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "No inventory recorded for this variant", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve inventory", err)
+		return
+	}
+
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+
+	limit := pageParams.Limit
+	limitPlusOne := int32(pageParams.Limit + 1)
+
+	cursor, hasCursor, err := inventoryCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.db.GetInventoryMovementsByItemPaginated(r.Context(), database.GetInventoryMovementsByItemPaginatedParams{
+		InventoryItemID: item.ID,
+		HasCursor:       hasCursor,
+		CursorCreatedAt: cursor.CreatedAt,
+		CursorID:        cursor.ID,
+		RowLimit:        limitPlusOne,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve inventory movements", err)
+		return
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = inventoryCursorCodec.Encode(InventoryCursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]InventoryMovementResponse, 0, len(rows))
+	for _, movement := range rows {
+		response = append(response, inventoryMovementToResponse(movement))
+	}
+
+	slog.InfoContext(r.Context(), "tenant inventory movements list successful",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"variant_id", variantID,
+		"movement_count", len(response),
+		"has_more", hasMore,
+	)
+
+	respondWithJSON(w, http.StatusOK, PageResponse[InventoryMovementResponse]{
+		Data: response,
+		Page: PageInfo{Limit: limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
+
+// handlerTenantInventoryTransfer moves stock for a variant between two locations
+func (cfg *apiConfig) handlerTenantInventoryTransfer(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	tenantParam := chi.URLParam(r, "tenantID")
+	storeParam := chi.URLParam(r, "storeID")
+	variantParam := chi.URLParam(r, "variantID")
+
+	slog.InfoContext(r.Context(), "tenant inventory transfer request received",
+		"request_id", reqID,
+		"tenant_param", tenantParam,
+		"store_param", storeParam,
+		"variant_param", variantParam,
+	)
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	variantID, err := uuid.Parse(variantParam)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid variant ID format", err)
+		return
+	}
+
+	type parameters struct {
+		FromLocationID uuid.UUID `json:"from_location_id"`
+		ToLocationID   uuid.UUID `json:"to_location_id"`
+		Quantity       int32     `json:"quantity"`
+		Note           *string   `json:"note"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	if err := inventory.ValidateTransfer(params.FromLocationID, params.ToLocationID, params.Quantity); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	note := sql.NullString{}
+	if params.Note != nil {
+		note = sql.NullString{String: *params.Note, Valid: true}
+	}
+
+	var from, to database.InventoryLocation
+	var fromLevel, toLevel database.InventoryLevel
+	var transferID uuid.UUID
+	var movements []InventoryMovementResponse
+	err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		item, err := q.GetInventoryItemByVariantID(r.Context(), database.GetInventoryItemByVariantIDParams{
+			VariantID: variantID,
+			StoreID:   storeID,
+		})
+		if err != nil {
+			return service.NotFoundAs(err, "No inventory recorded for this variant")
+		}
+
+		from, err = q.GetInventoryLocationByID(r.Context(), database.GetInventoryLocationByIDParams{
+			ID:       params.FromLocationID,
+			TenantID: tenantID,
+		})
+		if err != nil {
+			return service.NotFoundAs(err, "Source location not found")
+		}
+
+		to, err = q.GetInventoryLocationByID(r.Context(), database.GetInventoryLocationByIDParams{
+			ID:       params.ToLocationID,
+			TenantID: tenantID,
+		})
+		if err != nil {
+			return service.NotFoundAs(err, "Destination location not found")
+		}
+
+		if !to.Active {
+			return service.Conflict("Destination location is inactive")
+		}
+
+		fromLevel, err = q.EnsureInventoryLevel(r.Context(), database.EnsureInventoryLevelParams{
+			TenantID:        tenantID,
+			InventoryItemID: item.ID,
+			LocationID:      from.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		toLevel, err = q.EnsureInventoryLevel(r.Context(), database.EnsureInventoryLevelParams{
+			TenantID:        tenantID,
+			InventoryItemID: item.ID,
+			LocationID:      to.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		type transferLeg struct {
+			level *database.InventoryLevel
+			delta int32
+		}
+		legs := []transferLeg{
+			{&fromLevel, -params.Quantity},
+			{&toLevel, params.Quantity},
+		}
+
+		// Lock both level rows in a stable order so opposing transfers cannot deadlock
+		lockOrder := []transferLeg{legs[0], legs[1]}
+		if toLevel.ID.String() < fromLevel.ID.String() {
+			lockOrder[0], lockOrder[1] = lockOrder[1], lockOrder[0]
+		}
+		for _, leg := range lockOrder {
+			updated, err := q.AdjustInventoryLevel(r.Context(), database.AdjustInventoryLevelParams{
+				Delta: leg.delta,
+				ID:    leg.level.ID,
+			})
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return abortTxCode(http.StatusConflict, problem.CodeInsufficientStock, "Not enough stock at the source location", inventory.ErrInsufficientStock)
+				}
+				return err
+			}
+			*leg.level = updated
+		}
+
+		transferID = uuid.New()
+		movements = make([]InventoryMovementResponse, 0, len(legs))
+		for _, leg := range legs {
+			movement, err := q.CreateInventoryMovement(r.Context(), database.CreateInventoryMovementParams{
+				TenantID:        tenantID,
+				InventoryItemID: item.ID,
+				LocationID:      uuid.NullUUID{UUID: leg.level.LocationID, Valid: true},
+				TransferID:      uuid.NullUUID{UUID: transferID, Valid: true},
+				Delta:           leg.delta,
+				QuantityAfter:   leg.level.OnHand,
+				Reason:          string(inventory.ReasonTransfer),
+				Note:            note,
+				CreatedBy:       uuid.NullUUID{UUID: user, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+			movements = append(movements, inventoryMovementToResponse(movement))
+		}
+		return nil
+	})
+	if err != nil {
+		respondWithTxError(w, err, "Unable to transfer inventory")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant inventory transferred successfully",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"variant_id", variantID,
+		"transfer_id", transferID,
+		"from_location_id", from.ID,
+		"to_location_id", to.ID,
+		"quantity", params.Quantity,
+	)
+
+	respondWithJSON(w, http.StatusCreated, map[string]any{
+		"transfer_id": transferID,
+		"levels": []InventoryLevelResponse{
+			{LocationID: from.ID, LocationName: from.Name, OnHand: fromLevel.OnHand, UpdatedAt: fromLevel.UpdatedAt},
+			{LocationID: to.ID, LocationName: to.Name, OnHand: toLevel.OnHand, UpdatedAt: toLevel.UpdatedAt},
+		},
+		"movements": movements,
+	})
+}
+
+// productAvailability sums stock per location for each product, less what
+// reservations hold
+func (cfg *apiConfig) productAvailability(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID]*ProductInventoryResponse, error) {
+	availability := make(map[uuid.UUID]*ProductInventoryResponse, len(productIDs))
+	if len(productIDs) == 0 {
+		return availability, nil
+	}
+
+	rows, err := cfg.db.GetProductAvailabilityByLocation(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range productIDs {
+		availability[id] = &ProductInventoryResponse{Locations: []ProductLocationAvailability{}}
+	}
+	for _, row := range rows {
+		agg, ok := availability[row.ProductID]
+		if !ok {
+			continue
+		}
+		agg.Available += row.OnHand
+		agg.Locations = append(agg.Locations, ProductLocationAvailability{
+			LocationID:   row.LocationID,
+			LocationName: row.LocationName,
+			Available:    row.OnHand,
+		})
+	}
+
+	reserved, err := cfg.db.GetReservedStockByProducts(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range reserved {
+		if agg, ok := availability[row.ProductID]; ok {
+			agg.Reserved = row.Reserved
+			agg.Available = max(agg.Available-row.Reserved, 0)
+		}
+	}
+	return availability, nil
+}
+
+func inventoryItemToResponse(item database.InventoryItem) InventoryItemResponse {
+	return InventoryItemResponse{
+		ID:        item.ID,
+		TenantID:  item.TenantID,
+		StoreID:   item.StoreID,
+		VariantID: item.VariantID,
+		OnHand:    item.OnHand,
+		Reserved:  item.Reserved,
+		Available: max(item.OnHand-item.Reserved, 0),
+		CreatedAt: item.CreatedAt,
+		UpdatedAt: item.UpdatedAt,
+	}
+}
+
+func inventoryMovementToResponse(m database.InventoryMovement) InventoryMovementResponse {
+	var notePtr *string
+	if m.Note.Valid {
+		notePtr = &m.Note.String
+	}
+	var createdBy, locationID, transferID *uuid.UUID
+	if m.CreatedBy.Valid {
+		createdBy = &m.CreatedBy.UUID
+	}
+	if m.LocationID.Valid {
+		locationID = &m.LocationID.UUID
+	}
+	if m.TransferID.Valid {
+		transferID = &m.TransferID.UUID
+	}
+	return InventoryMovementResponse{
+		ID:              m.ID,
+		InventoryItemID: m.InventoryItemID,
+		LocationID:      locationID,
+		TransferID:      transferID,
+		Delta:           m.Delta,
+		QuantityAfter:   m.QuantityAfter,
+		Reason:          m.Reason,
+		Note:            notePtr,
+		CreatedBy:       createdBy,
+		CreatedAt:       m.CreatedAt,
+	}
+}
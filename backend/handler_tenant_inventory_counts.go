@@ -0,0 +1,637 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/inventory"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// InventoryCountResponse is a stock count of a location
+type InventoryCountResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	LocationID  uuid.UUID  `json:"location_id"`
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	CreatedBy   *uuid.UUID `json:"created_by,omitempty"`
+	CompletedBy *uuid.UUID `json:"completed_by,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	// Summary and Lines are only returned for a single count
+	Summary *inventory.CountSummary      `json:"summary,omitempty"`
+	Lines   []InventoryCountLineResponse `json:"lines,omitempty"`
+}
+
+// InventoryCountLineResponse is a variant recorded in a count
+type InventoryCountLineResponse struct {
+	VariantID    uuid.UUID `json:"variant_id"`
+	ProductName  string    `json:"product_name"`
+	VariantTitle string    `json:"variant_title"`
+	SKU          *string   `json:"sku,omitempty"`
+	// Expected is the stock at the location when the variant was counted,
+	// and Discrepancy is what completing the count adjusts it by
+	Expected    int32      `json:"expected"`
+	Counted     int32      `json:"counted"`
+	Discrepancy int32      `json:"discrepancy"`
+	OnHand      int32      `json:"on_hand"`
+	CountedBy   *uuid.UUID `json:"counted_by,omitempty"`
+	CountedAt   time.Time  `json:"counted_at"`
+}
+
+type InventoryCountCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+}
+
+var inventoryCountCursorCodec = CursorCodec[InventoryCountCursor]{
+	Validate: func(c InventoryCountCursor) error {
+		if c.CreatedAt.IsZero() || c.ID == uuid.Nil {
+			return errors.New("invalid cursor: missing required fields")
+		}
+		return nil
+	},
+}
+
+// handlerTenantInventoryCountCreate opens a count of a location, the
+// default one unless location_id is given. A location has one open count
+// at a time.
+func (cfg *apiConfig) handlerTenantInventoryCountCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	type parameters struct {
+		LocationID *uuid.UUID `json:"location_id"`
+		Name       string     `json:"name"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	name := strings.TrimSpace(params.Name)
+	var v validate.Validator
+	v.MaxLength("name", name, inventory.MaxCountNameLength)
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	location, err := resolveRestockLocation(r.Context(), cfg.db, access.TenantID, params.LocationID)
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to retrieve location")
+		return
+	}
+	if name == "" {
+		name = location.Name + " " + time.Now().UTC().Format(time.DateOnly)
+	}
+
+	count, err := cfg.db.CreateInventoryCount(r.Context(), database.CreateInventoryCountParams{
+		Gid:        sql.NullInt64{Int64: int64(cfg.gidGen.Generate()), Valid: true},
+		TenantID:   access.TenantID,
+		StoreID:    access.StoreID,
+		LocationID: location.ID,
+		Name:       name,
+		CreatedBy:  uuid.NullUUID{UUID: access.UserID, Valid: true},
+	})
+	if service.UniqueViolation(err, "") {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeConflict, "Location already has an open count", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create inventory count", err)
+		return
+	}
+
+	resp := inventoryCountToResponse(count)
+	auditChange(r, auditGID(gid.EntityInventoryCount, count.Gid), nil, resp)
+	slog.InfoContext(r.Context(), "inventory count opened",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"store_id", access.StoreID,
+		"count_id", count.ID,
+		"location_id", location.ID,
+	)
+
+	respondWithJSON(w, http.StatusCreated, resp)
+}
+
+// handlerTenantInventoryCountsList lists a store's counts, newest first,
+// filtered by ?status=
+func (cfg *apiConfig) handlerTenantInventoryCountsList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	status := r.URL.Query().Get("status")
+	var v validate.Validator
+	if status != "" {
+		v.OneOf("status", status, inventory.CountOpen, inventory.CountCompleted, inventory.CountCancelled)
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+	limit := pageParams.Limit
+
+	cursor, hasCursor, err := inventoryCountCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.db.ListInventoryCountsPaginated(r.Context(), database.ListInventoryCountsPaginatedParams{
+		StoreID:         storeID,
+		Status:          sql.NullString{String: status, Valid: status != ""},
+		HasCursor:       hasCursor,
+		CursorCreatedAt: cursor.CreatedAt,
+		CursorID:        cursor.ID,
+		RowLimit:        int32(limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve inventory counts", err)
+		return
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = inventoryCountCursorCodec.Encode(InventoryCountCursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]InventoryCountResponse, 0, len(rows))
+	for _, count := range rows {
+		response = append(response, inventoryCountToResponse(count))
+	}
+
+	respondWithJSON(w, http.StatusOK, PageResponse[InventoryCountResponse]{
+		Data: response,
+		Page: PageInfo{Limit: limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
+
+// handlerTenantInventoryCountGet returns a count with its lines and a
+// summary of the discrepancies, to review before completing it. With
+// ?discrepancies_only=true only lines that differ are listed.
+func (cfg *apiConfig) handlerTenantInventoryCountGet(w http.ResponseWriter, r *http.Request) {
+	count, ok := cfg.loadInventoryCount(w, r)
+	if !ok {
+		return
+	}
+	lines, err := cfg.db.GetInventoryCountLines(r.Context(), count.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve inventory count lines", err)
+		return
+	}
+
+	resp := inventoryCountWithLines(count, lines)
+	if r.URL.Query().Get("discrepancies_only") == "true" {
+		differing := make([]InventoryCountLineResponse, 0, len(resp.Lines))
+		for _, line := range resp.Lines {
+			if line.Discrepancy != 0 {
+				differing = append(differing, line)
+			}
+		}
+		resp.Lines = differing
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerTenantInventoryCountLinesRecord records counted quantities of an
+// open count. Each entry names a variant by variant_id or by a SKU or
+// barcode in code. Counting a variant again replaces its earlier count.
+func (cfg *apiConfig) handlerTenantInventoryCountLinesRecord(w http.ResponseWriter, r *http.Request) {
+	count, ok := cfg.loadInventoryCount(w, r)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		Entries []inventory.CountEntry `json:"entries"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	var v validate.Validator
+	v.Check(len(params.Entries) > 0, "entries", validate.CodeRequired, "is required")
+	v.Check(len(params.Entries) <= inventory.MaxCountEntries, "entries", validate.CodeInvalid, "has too many entries")
+	for i, e := range params.Entries {
+		params.Entries[i].Code = strings.TrimSpace(e.Code)
+		if !v.Check(e.VariantID != uuid.Nil || params.Entries[i].Code != "", "entries", validate.CodeRequired, "each entry needs a variant_id or code") ||
+			!v.Check(e.Counted >= 0, "entries", validate.CodeInvalid, "counted must be at least 0") {
+			break
+		}
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	cfg.recordCountEntries(w, r, count, params.Entries)
+}
+
+// handlerTenantInventoryCountLinesImport records counted quantities of an
+// open count from a CSV, as the request body or the file field of a
+// multipart form. See inventory.ParseCountFile for its columns.
+func (cfg *apiConfig) handlerTenantInventoryCountLinesImport(w http.ResponseWriter, r *http.Request) {
+	count, ok := cfg.loadInventoryCount(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, inventory.MaxCountFileBytes)
+	data, _, err := readImportFile(r)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondWithError(w, http.StatusRequestEntityTooLarge, "Count files must be 2MB or smaller", nil)
+			return
+		}
+		respondWithError(w, http.StatusBadRequest, "Please upload a CSV file", err)
+		return
+	}
+
+	entries, err := inventory.ParseCountFile(bytes.NewReader(data))
+	if err != nil {
+		respondWithError(w, http.StatusUnprocessableEntity, err.Error(), nil)
+		return
+	}
+
+	cfg.recordCountEntries(w, r, count, entries)
+}
+
+// handlerTenantInventoryCountLineDelete takes a variant back out of an
+// open count, such as one counted by mistake
+func (cfg *apiConfig) handlerTenantInventoryCountLineDelete(w http.ResponseWriter, r *http.Request) {
+	count, ok := cfg.loadInventoryCount(w, r)
+	if !ok {
+		return
+	}
+	variantID, err := uuid.Parse(chi.URLParam(r, "variantID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid variant ID format", err)
+		return
+	}
+
+	var deleted int64
+	err = cfg.withTenantTx(r.Context(), tenantAccessFrom(r).TenantID, func(q *database.Queries) error {
+		open, err := q.TouchInventoryCount(r.Context(), count.ID)
+		if err != nil {
+			return err
+		}
+		if open == 0 {
+			return inventory.ErrCountNotOpen
+		}
+		deleted, err = q.DeleteInventoryCountLine(r.Context(), database.DeleteInventoryCountLineParams{
+			CountID:   count.ID,
+			VariantID: variantID,
+		})
+		return err
+	})
+	if errors.Is(err, inventory.ErrCountNotOpen) {
+		respondWithError(w, http.StatusConflict, "Inventory count is no longer open", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to delete inventory count line", err)
+		return
+	}
+	if deleted == 0 {
+		respondWithError(w, http.StatusNotFound, "Variant has not been counted", nil)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerTenantInventoryCountComplete closes an open count and adjusts the
+// location's stock by each line's discrepancy. Because a line is measured
+// against the stock when it was counted, stock sold or received since is
+// kept.
+func (cfg *apiConfig) handlerTenantInventoryCountComplete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	existing, ok := cfg.loadInventoryCount(w, r)
+	if !ok {
+		return
+	}
+
+	var count database.InventoryCount
+	var lines []database.GetInventoryCountLinesRow
+	var adjusted int
+	err := cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		var err error
+		count, err = q.CloseInventoryCount(r.Context(), database.CloseInventoryCountParams{
+			Status:      inventory.CountCompleted,
+			CompletedBy: uuid.NullUUID{UUID: access.UserID, Valid: true},
+			ID:          existing.ID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return inventory.ErrCountNotOpen
+		}
+		if err != nil {
+			return err
+		}
+
+		lines, err = q.GetInventoryCountLines(r.Context(), count.ID)
+		if err != nil {
+			return err
+		}
+		for _, line := range lines {
+			delta := line.Counted - line.Expected
+			if delta == 0 {
+				continue
+			}
+			if _, _, err := changeStock(r.Context(), q, stockChange{
+				TenantID:   count.TenantID,
+				StoreID:    count.StoreID,
+				VariantID:  line.VariantID,
+				LocationID: count.LocationID,
+				Delta:      delta,
+				Reason:     inventory.ReasonCount,
+				Note:       "Count " + count.Name,
+				UserID:     access.UserID,
+			}); err != nil {
+				return err
+			}
+			adjusted++
+		}
+
+		// The lines now report the stock after the adjustments
+		lines, err = q.GetInventoryCountLines(r.Context(), count.ID)
+		return err
+	})
+	if errors.Is(err, inventory.ErrCountNotOpen) {
+		respondWithError(w, http.StatusConflict, "Inventory count is no longer open", err)
+		return
+	}
+	if errors.Is(err, inventory.ErrInsufficientStock) {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeInsufficientStock, "Stock has fallen below what a shortage would take off; recount the affected variants", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to complete inventory count", err)
+		return
+	}
+
+	resp := inventoryCountWithLines(count, lines)
+	auditChange(r, auditGID(gid.EntityInventoryCount, count.Gid), inventoryCountToResponse(existing), inventoryCountToResponse(count))
+	slog.InfoContext(r.Context(), "inventory count completed",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"store_id", access.StoreID,
+		"count_id", count.ID,
+		"lines", len(lines),
+		"adjusted", adjusted,
+	)
+
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerTenantInventoryCountCancel closes an open count without touching
+// stock
+func (cfg *apiConfig) handlerTenantInventoryCountCancel(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	existing, ok := cfg.loadInventoryCount(w, r)
+	if !ok {
+		return
+	}
+
+	count, err := cfg.db.CloseInventoryCount(r.Context(), database.CloseInventoryCountParams{
+		Status: inventory.CountCancelled,
+		ID:     existing.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusConflict, "Inventory count is no longer open", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to cancel inventory count", err)
+		return
+	}
+
+	resp := inventoryCountToResponse(count)
+	auditChange(r, auditGID(gid.EntityInventoryCount, count.Gid), inventoryCountToResponse(existing), resp)
+	slog.InfoContext(r.Context(), "inventory count cancelled",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"store_id", access.StoreID,
+		"count_id", count.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// recordCountEntries resolves entries to variants and records them as
+// lines of count, each expecting the location's stock at this moment, and
+// responds with the count
+func (cfg *apiConfig) recordCountEntries(w http.ResponseWriter, r *http.Request, count database.InventoryCount, entries []inventory.CountEntry) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	var ids []uuid.UUID
+	var codes, skus []string
+	for _, e := range entries {
+		if e.VariantID != uuid.Nil {
+			ids = append(ids, e.VariantID)
+		} else {
+			codes = append(codes, e.Code)
+			skus = append(skus, strings.ToLower(e.Code))
+		}
+	}
+	variants, err := cfg.db.GetCountableVariants(r.Context(), database.GetCountableVariantsParams{
+		StoreID: count.StoreID,
+		Ids:     ids,
+		Codes:   codes,
+		Skus:    skus,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve variants", err)
+		return
+	}
+	counted, err := inventory.MatchCountEntries(entries, variants)
+	if err != nil {
+		respondWithError(w, http.StatusUnprocessableEntity, err.Error(), nil)
+		return
+	}
+
+	var lines []database.GetInventoryCountLinesRow
+	err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		// Holding the count open stops it completing part way through
+		open, err := q.TouchInventoryCount(r.Context(), count.ID)
+		if err != nil {
+			return err
+		}
+		if open == 0 {
+			return inventory.ErrCountNotOpen
+		}
+
+		variantIDs := make([]uuid.UUID, 0, len(counted))
+		for id := range counted {
+			variantIDs = append(variantIDs, id)
+		}
+		stock, err := q.GetStockAtLocation(r.Context(), database.GetStockAtLocationParams{
+			VariantIds: variantIDs,
+			LocationID: count.LocationID,
+		})
+		if err != nil {
+			return err
+		}
+		onHand := make(map[uuid.UUID]int32, len(stock))
+		for _, s := range stock {
+			onHand[s.VariantID] = s.OnHand
+		}
+
+		for id, n := range counted {
+			if err := q.UpsertInventoryCountLine(r.Context(), database.UpsertInventoryCountLineParams{
+				CountID:   count.ID,
+				VariantID: id,
+				Expected:  onHand[id],
+				Counted:   n,
+				CountedBy: uuid.NullUUID{UUID: access.UserID, Valid: true},
+			}); err != nil {
+				return err
+			}
+		}
+
+		lines, err = q.GetInventoryCountLines(r.Context(), count.ID)
+		return err
+	})
+	if errors.Is(err, inventory.ErrCountNotOpen) {
+		respondWithError(w, http.StatusConflict, "Inventory count is no longer open", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to record inventory count", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "inventory count recorded",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"store_id", access.StoreID,
+		"count_id", count.ID,
+		"variants", len(counted),
+	)
+
+	respondWithJSON(w, http.StatusOK, inventoryCountWithLines(count, lines))
+}
+
+// loadInventoryCount loads the {countID} of the route from the store. It
+// has responded when it returns false.
+func (cfg *apiConfig) loadInventoryCount(w http.ResponseWriter, r *http.Request) (database.InventoryCount, bool) {
+	countID, err := uuid.Parse(chi.URLParam(r, "countID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid count ID format", err)
+		return database.InventoryCount{}, false
+	}
+	count, err := cfg.db.GetInventoryCountByID(r.Context(), database.GetInventoryCountByIDParams{
+		ID:      countID,
+		StoreID: tenantAccessFrom(r).StoreID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Inventory count not found", nil)
+		return database.InventoryCount{}, false
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve inventory count", err)
+		return database.InventoryCount{}, false
+	}
+	return count, true
+}
+
+func inventoryCountToResponse(count database.InventoryCount) InventoryCountResponse {
+	resp := InventoryCountResponse{
+		ID:         count.ID,
+		LocationID: count.LocationID,
+		Name:       count.Name,
+		Status:     count.Status,
+		CreatedAt:  count.CreatedAt,
+		UpdatedAt:  count.UpdatedAt,
+	}
+	if count.CreatedBy.Valid {
+		resp.CreatedBy = &count.CreatedBy.UUID
+	}
+	if count.CompletedBy.Valid {
+		resp.CompletedBy = &count.CompletedBy.UUID
+	}
+	if count.CompletedAt.Valid {
+		resp.CompletedAt = &count.CompletedAt.Time
+	}
+	return resp
+}
+
+// inventoryCountWithLines converts a count with its lines and a summary of
+// their discrepancies
+func inventoryCountWithLines(count database.InventoryCount, lines []database.GetInventoryCountLinesRow) InventoryCountResponse {
+	resp := inventoryCountToResponse(count)
+	summary := inventory.CountSummary{}
+	resp.Lines = make([]InventoryCountLineResponse, 0, len(lines))
+	for _, line := range lines {
+		summary.Add(line.Expected, line.Counted)
+		lr := InventoryCountLineResponse{
+			VariantID:    line.VariantID,
+			ProductName:  line.ProductName,
+			VariantTitle: line.VariantTitle,
+			Expected:     line.Expected,
+			Counted:      line.Counted,
+			Discrepancy:  line.Counted - line.Expected,
+			OnHand:       line.OnHand,
+			CountedAt:    line.CountedAt,
+		}
+		if line.Sku.Valid {
+			lr.SKU = &line.Sku.String
+		}
+		if line.CountedBy.Valid {
+			lr.CountedBy = &line.CountedBy.UUID
+		}
+		resp.Lines = append(resp.Lines, lr)
+	}
+	resp.Summary = &summary
+	return resp
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/google/uuid"
+)
+
+// invitationTTL is how long an emailed tenant invitation stays valid
+const invitationTTL = 7 * 24 * time.Hour
+
+type TenantInvitationResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	TenantID  uuid.UUID  `json:"tenant_id"`
+	Email     string     `json:"email"`
+	RoleID    *uuid.UUID `json:"role_id,omitempty"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// InvitationPreviewResponse is what the accept page shows before the
+// invitee signs in or signs up
+type InvitationPreviewResponse struct {
+	TenantName    string    `json:"tenant_name"`
+	Email         string    `json:"email"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	AccountExists bool      `json:"account_exists"`
+}
+
+// handlerInvitationPreview describes the invitation behind ?token= so the
+// app can offer to sign in or to create an account
+func (cfg *apiConfig) handlerInvitationPreview(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		respondWithError(w, http.StatusBadRequest, "Token is required", nil)
+		return
+	}
+
+	invitation, err := cfg.db.GetActiveTenantInvitation(r.Context(), auth.HashToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Invitation is invalid or has expired", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve invitation", err)
+		return
+	}
+
+	tenant, err := cfg.db.GetTenantByID(r.Context(), invitation.TenantID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve tenant", err)
+		return
+	}
+
+	_, err = cfg.db.GetUserByEmail(r.Context(), invitation.Email)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve invitation", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, InvitationPreviewResponse{
+		TenantName:    tenant.Name,
+		Email:         invitation.Email,
+		ExpiresAt:     invitation.ExpiresAt,
+		AccountExists: err == nil,
+	})
+}
+
+// handlerInvitationAccept accepts an invitation for the signed-in user, whose
+// email must match the invited address
+func (cfg *apiConfig) handlerInvitationAccept(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	userID, ok := userFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	type parameters struct {
+		Token string `json:"token"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	if params.Token == "" {
+		respondWithError(w, http.StatusBadRequest, "Token is required", nil)
+		return
+	}
+
+	var invitation database.TenantInvitation
+	var user database.User
+	var member TenantMemberResponse
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		var err error
+		invitation, err = q.GetActiveTenantInvitation(r.Context(), auth.HashToken(params.Token))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return service.Invalid("Invitation is invalid or has expired")
+			}
+			return err
+		}
+
+		user, err = q.GetUserByID(r.Context(), userID)
+		if err != nil {
+			return err
+		}
+
+		if !strings.EqualFold(user.Email, invitation.Email) {
+			return abortTx(http.StatusForbidden, "This invitation was sent to a different email address", nil)
+		}
+
+		member, err = acceptInvitation(r.Context(), q, invitation, user)
+		return err
+	})
+	if err != nil {
+		respondWithTxError(w, err, "Unable to accept invitation")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant invitation accepted",
+		"request_id", reqID,
+		"user_id", user.ID,
+		"tenant_id", invitation.TenantID,
+		"invitation_id", invitation.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, member)
+}
+
+// handlerInvitationSignup creates an account for the invited address and
+// accepts the invitation in one step
+func (cfg *apiConfig) handlerInvitationSignup(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	type parameters struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+
+	type response struct {
+		User   User                 `json:"user"`
+		Member TenantMemberResponse `json:"member"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	if params.Token == "" {
+		respondWithError(w, http.StatusBadRequest, "Token is required", nil)
+		return
+	}
+	if len(params.Password) < 8 {
+		respondWithError(w, http.StatusBadRequest, "Password must be at least 8 characters", nil)
+		return
+	}
+
+	hash, err := auth.HashPassword(params.Password)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Unable to use that password", err)
+		return
+	}
+
+	var invitation database.TenantInvitation
+	var user database.User
+	var member TenantMemberResponse
+	err = cfg.withTx(r.Context(), func(q *database.Queries) error {
+		var err error
+		invitation, err = q.GetActiveTenantInvitation(r.Context(), auth.HashToken(params.Token))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return service.Invalid("Invitation is invalid or has expired")
+			}
+			return err
+		}
+
+		_, err = q.GetUserByEmail(r.Context(), invitation.Email)
+		if err == nil {
+			return &service.Error{Kind: service.ErrConflict, Code: problem.CodeEmailTaken, Message: "An account with this email already exists, please log in to accept the invitation"}
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		user, err = q.CreateUser(r.Context(), database.CreateUserParams{
+			Gid:            sql.NullInt64{Int64: int64(cfg.gidGen.Generate()), Valid: true},
+			Email:          invitation.Email,
+			HashedPassword: hash,
+		})
+		if err != nil {
+			return err
+		}
+
+		member, err = acceptInvitation(r.Context(), q, invitation, user)
+		if err != nil {
+			return err
+		}
+
+		// Reading the invitation email proves the address
+		user, err = q.MarkUserVerified(r.Context(), user.ID)
+		return err
+	})
+	if err != nil {
+		respondWithTxError(w, err, "Unable to create account")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant invitation accepted with new account",
+		"request_id", reqID,
+		"user_id", user.ID,
+		"tenant_id", invitation.TenantID,
+		"invitation_id", invitation.ID,
+	)
+
+	respondWithJSON(w, http.StatusCreated, response{
+		User:   userToResponse(user),
+		Member: member,
+	})
+}
+
+// acceptInvitation activates the user's membership, grants the invitation's
+// role if it still exists and closes the invitation
+func acceptInvitation(ctx context.Context, q *database.Queries, invitation database.TenantInvitation, user database.User) (TenantMemberResponse, error) {
+	tenantUser, err := q.UpsertTenantUser(ctx, database.UpsertTenantUserParams{
+		TenantID: invitation.TenantID,
+		UserID:   user.ID,
+		Status:   "active",
+	})
+	if err != nil {
+		return TenantMemberResponse{}, err
+	}
+
+	if invitation.RoleID.Valid {
+		err = q.AssignRoleToTenantUser(ctx, database.AssignRoleToTenantUserParams{
+			TenantUserID: tenantUser.ID,
+			RoleID:       invitation.RoleID.UUID,
+		})
+		if err != nil {
+			return TenantMemberResponse{}, err
+		}
+	}
+
+	_, err = q.MarkTenantInvitationAccepted(ctx, database.MarkTenantInvitationAcceptedParams{
+		ID:         invitation.ID,
+		AcceptedBy: uuid.NullUUID{UUID: user.ID, Valid: true},
+	})
+	if err != nil {
+		return TenantMemberResponse{}, err
+	}
+
+	roles, err := q.GetRolesByTenantUserID(ctx, tenantUser.ID)
+	if err != nil {
+		return TenantMemberResponse{}, err
+	}
+	roleNames := make([]string, 0, len(roles))
+	for _, role := range roles {
+		roleNames = append(roleNames, role.Name)
+	}
+
+	resp := TenantMemberResponse{
+		ID:        tenantUser.ID,
+		TenantID:  tenantUser.TenantID,
+		UserID:    tenantUser.UserID,
+		Email:     user.Email,
+		Status:    tenantUser.Status,
+		Roles:     roleNames,
+		CreatedAt: tenantUser.CreatedAt,
+		UpdatedAt: tenantUser.UpdatedAt,
+	}
+	if user.DisplayName.Valid {
+		resp.DisplayName = &user.DisplayName.String
+	}
+	return resp, nil
+}
+
+func invitationToResponse(i database.TenantInvitation) TenantInvitationResponse {
+	resp := TenantInvitationResponse{
+		ID:        i.ID,
+		TenantID:  i.TenantID,
+		Email:     i.Email,
+		ExpiresAt: i.ExpiresAt,
+		CreatedAt: i.CreatedAt,
+	}
+	if i.RoleID.Valid {
+		resp.RoleID = &i.RoleID.UUID
+	}
+	return resp
+}
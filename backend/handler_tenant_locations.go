@@ -0,0 +1,341 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type LocationResponse struct {
+	ID        uuid.UUID `json:"id"`
+	TenantID  uuid.UUID `json:"tenant_id"`
+	Name      string    `json:"name"`
+	Kind      string    `json:"kind"`
+	Address   *string   `json:"address,omitempty"`
+	IsDefault bool      `json:"is_default"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func isValidLocationKind(kind string) bool {
+	return kind == "warehouse" || kind == "retail"
+}
+
+// handlerTenantLocationsCreate creates an inventory location (warehouse or retail store)
+func (cfg *apiConfig) handlerTenantLocationsCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	tenantParam := chi.URLParam(r, "tenantID")
+
+	slog.InfoContext(r.Context(), "tenant location creation request received",
+		"request_id", reqID,
+		"tenant_param", tenantParam,
+	)
+
+	access := tenantAccessFrom(r)
+	user, tenantID := access.UserID, access.TenantID
+
+	type parameters struct {
+		Name    string  `json:"name"`
+		Kind    string  `json:"kind"`
+		Address *string `json:"address"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	if params.Name == "" {
+		respondWithError(w, http.StatusBadRequest, "Location name is required", nil)
+		return
+	}
+
+	kind := params.Kind
+	if kind == "" {
+		kind = "warehouse"
+	}
+	if !isValidLocationKind(kind) {
+		respondWithError(w, http.StatusBadRequest, "Location kind must be 'warehouse' or 'retail'", nil)
+		return
+	}
+
+	address := sql.NullString{}
+	if params.Address != nil {
+		address = sql.NullString{String: *params.Address, Valid: true}
+	}
+
+	// The tenant's first location becomes its default
+	existing, err := cfg.db.GetInventoryLocationsByTenant(r.Context(), tenantID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create location", err)
+		return
+	}
+
+	location, err := cfg.db.CreateInventoryLocation(r.Context(), database.CreateInventoryLocationParams{
+		TenantID:  tenantID,
+		Name:      params.Name,
+		Kind:      kind,
+		Address:   address,
+		IsDefault: len(existing) == 0,
+	})
+	if err != nil {
+		slog.ErrorContext(r.Context(), "tenant location creation failed: database error",
+			"request_id", reqID,
+			"error", err,
+		)
+		respondWithError(w, http.StatusBadRequest, "Unable to create location, the name may already be in use", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant location created successfully",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"location_id", location.ID,
+	)
+
+	respondWithJSON(w, http.StatusCreated, locationToResponse(location))
+}
+
+// handlerTenantLocationsList lists a tenant's inventory locations, default first
+func (cfg *apiConfig) handlerTenantLocationsList(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	tenantParam := chi.URLParam(r, "tenantID")
+
+	slog.InfoContext(r.Context(), "tenant locations list request received",
+		"request_id", reqID,
+		"tenant_param", tenantParam,
+	)
+
+	tenantID := tenantAccessFrom(r).TenantID
+
+	locations, err := cfg.db.GetInventoryLocationsByTenant(r.Context(), tenantID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve locations", err)
+		return
+	}
+
+	response := make([]LocationResponse, 0, len(locations))
+	for _, location := range locations {
+		response = append(response, locationToResponse(location))
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{
+		"data": response,
+	})
+}
+
+// handlerTenantLocationsUpdate updates a location's details or deactivates it
+func (cfg *apiConfig) handlerTenantLocationsUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	tenantParam := chi.URLParam(r, "tenantID")
+	locationParam := chi.URLParam(r, "locationID")
+
+	slog.InfoContext(r.Context(), "tenant location update request received",
+		"request_id", reqID,
+		"tenant_param", tenantParam,
+		"location_param", locationParam,
+	)
+
+	access := tenantAccessFrom(r)
+	user, tenantID := access.UserID, access.TenantID
+
+	locationID, err := uuid.Parse(locationParam)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid location ID format", err)
+		return
+	}
+
+	existing, err := cfg.db.GetInventoryLocationByID(r.Context(), database.GetInventoryLocationByIDParams{
+		ID:       locationID,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Location not found", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve location", err)
+		return
+	}
+
+	type parameters struct {
+		Name    *string `json:"name"`
+		Kind    *string `json:"kind"`
+		Address *string `json:"address"`
+		Active  *bool   `json:"active"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	name := existing.Name
+	if params.Name != nil {
+		if *params.Name == "" {
+			respondWithError(w, http.StatusBadRequest, "Location name cannot be empty", nil)
+			return
+		}
+		name = *params.Name
+	}
+
+	kind := existing.Kind
+	if params.Kind != nil {
+		if !isValidLocationKind(*params.Kind) {
+			respondWithError(w, http.StatusBadRequest, "Location kind must be 'warehouse' or 'retail'", nil)
+			return
+		}
+		kind = *params.Kind
+	}
+
+	address := existing.Address
+	if params.Address != nil {
+		address = sql.NullString{String: *params.Address, Valid: true}
+	}
+
+	active := existing.Active
+	if params.Active != nil {
+		active = *params.Active
+	}
+
+	if existing.IsDefault && !active {
+		respondWithError(w, http.StatusConflict, "The default location cannot be deactivated", nil)
+		return
+	}
+
+	location, err := cfg.db.UpdateInventoryLocation(r.Context(), database.UpdateInventoryLocationParams{
+		ID:       locationID,
+		TenantID: tenantID,
+		Name:     name,
+		Kind:     kind,
+		Address:  address,
+		Active:   active,
+	})
+	if err != nil {
+		slog.ErrorContext(r.Context(), "tenant location update failed: database error",
+			"request_id", reqID,
+			"error", err,
+		)
+		respondWithError(w, http.StatusInternalServerError, "Unable to update location", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant location updated successfully",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"location_id", location.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, locationToResponse(location))
+}
+
+// handlerTenantLocationsDelete removes a location that no longer holds stock
+func (cfg *apiConfig) handlerTenantLocationsDelete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	tenantParam := chi.URLParam(r, "tenantID")
+	locationParam := chi.URLParam(r, "locationID")
+
+	slog.InfoContext(r.Context(), "tenant location delete request received",
+		"request_id", reqID,
+		"tenant_param", tenantParam,
+		"location_param", locationParam,
+	)
+
+	access := tenantAccessFrom(r)
+	user, tenantID := access.UserID, access.TenantID
+
+	locationID, err := uuid.Parse(locationParam)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid location ID format", err)
+		return
+	}
+
+	location, err := cfg.db.GetInventoryLocationByID(r.Context(), database.GetInventoryLocationByIDParams{
+		ID:       locationID,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Location not found", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve location", err)
+		return
+	}
+
+	if location.IsDefault {
+		respondWithError(w, http.StatusConflict, "The default location cannot be deleted", nil)
+		return
+	}
+
+	stocked, err := cfg.db.CountStockAtLocation(r.Context(), locationID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to verify location stock", err)
+		return
+	}
+
+	if stocked > 0 {
+		respondWithError(w, http.StatusConflict, "Location still holds stock; transfer it out first", nil)
+		return
+	}
+
+	err = cfg.db.DeleteInventoryLocation(r.Context(), database.DeleteInventoryLocationParams{
+		ID:       locationID,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		slog.ErrorContext(r.Context(), "tenant location delete failed: database error",
+			"request_id", reqID,
+			"error", err,
+		)
+		respondWithError(w, http.StatusInternalServerError, "Unable to delete location", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant location deleted successfully",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"location_id", locationID,
+	)
+
+	respondWithJSON(w, http.StatusOK, map[string]any{
+		"message":     "Location deleted successfully",
+		"location_id": locationID,
+	})
+}
+
+func locationToResponse(location database.InventoryLocation) LocationResponse {
+	var addressPtr *string
+	if location.Address.Valid {
+		addressPtr = &location.Address.String
+	}
+	return LocationResponse{
+		ID:        location.ID,
+		TenantID:  location.TenantID,
+		Name:      location.Name,
+		Kind:      location.Kind,
+		Address:   addressPtr,
+		IsDefault: location.IsDefault,
+		Active:    location.Active,
+		CreatedAt: location.CreatedAt,
+		UpdatedAt: location.UpdatedAt,
+	}
+}
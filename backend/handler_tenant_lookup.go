@@ -0,0 +1,111 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service/products"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/google/uuid"
+)
+
+// VariantLookupResponse is the variant a scanned code resolved to, with
+// what a point of sale needs to ring it up
+type VariantLookupResponse struct {
+	VariantID    uuid.UUID `json:"variant_id"`
+	ProductID    uuid.UUID `json:"product_id"`
+	ProductName  string    `json:"product_name"`
+	VariantTitle string    `json:"variant_title"`
+	SKU          *string   `json:"sku,omitempty"`
+	Barcode      *string   `json:"barcode,omitempty"`
+	// MatchedBy is barcode or sku
+	MatchedBy      string `json:"matched_by"`
+	Status         string `json:"status"`
+	ProductStatus  string `json:"product_status"`
+	PriceCents     int32  `json:"price_cents"`
+	CompareAtCents *int32 `json:"compare_at_cents,omitempty"`
+	Currency       string `json:"currency"`
+	// OnHand is the total across locations and Available is what is left
+	// once checkout reservations are taken off
+	InventoryTracked bool  `json:"inventory_tracked"`
+	OnHand           int32 `json:"on_hand"`
+	Available        int32 `json:"available"`
+	// LocationOnHand is the stock at ?location_id=, when given
+	LocationOnHand *int32 `json:"location_on_hand,omitempty"`
+}
+
+// handlerTenantVariantLookup resolves a scanned ?code= to a variant of the
+// store. Barcodes match exactly and win over SKUs, which match in any case.
+func (cfg *apiConfig) handlerTenantVariantLookup(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	code := strings.TrimSpace(r.URL.Query().Get("code"))
+	var v validate.Validator
+	if v.Required("code", code) {
+		v.MaxLength("code", code, products.MaxSKULength)
+	}
+	var locationID uuid.NullUUID
+	if raw := r.URL.Query().Get("location_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		v.Check(err == nil, "location_id", validate.CodeInvalid, "must be a UUID")
+		locationID = uuid.NullUUID{UUID: id, Valid: err == nil}
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	rows, err := cfg.db.LookupVariantByCode(r.Context(), database.LookupVariantByCodeParams{
+		Code:       code,
+		LocationID: locationID,
+		StoreID:    storeID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to look up code", err)
+		return
+	}
+	if len(rows) == 0 {
+		respondWithError(w, http.StatusNotFound, "No variant has this barcode or SKU", nil)
+		return
+	}
+	// Barcodes are not unique, and SKUs differing only in case match alike
+	if len(rows) > 1 && rows[0].BarcodeMatch == rows[1].BarcodeMatch {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeConflict, "More than one variant has this code", nil)
+		return
+	}
+
+	row := rows[0]
+	resp := VariantLookupResponse{
+		VariantID:        row.ID,
+		ProductID:        row.ProductID,
+		ProductName:      row.ProductName,
+		VariantTitle:     row.Title,
+		MatchedBy:        "sku",
+		Status:           row.Status,
+		ProductStatus:    row.ProductStatus,
+		PriceCents:       row.PriceCents,
+		Currency:         row.Currency,
+		InventoryTracked: row.InventoryTracked,
+		OnHand:           row.OnHand,
+		Available:        max(row.OnHand-row.Reserved, 0),
+	}
+	if row.BarcodeMatch {
+		resp.MatchedBy = "barcode"
+	}
+	if row.Sku.Valid {
+		resp.SKU = &row.Sku.String
+	}
+	if row.Barcode.Valid {
+		resp.Barcode = &row.Barcode.String
+	}
+	if row.CompareAtCents.Valid {
+		resp.CompareAtCents = &row.CompareAtCents.Int32
+	}
+	if locationID.Valid {
+		resp.LocationOnHand = &row.LocationOnHand.Int32
+	}
+
+	respondWithJSON(w, http.StatusOK, resp)
+}
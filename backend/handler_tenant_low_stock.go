@@ -0,0 +1,353 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/inventory"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// LowStockResponse is a variant at or below its reorder point at a location
+type LowStockResponse struct {
+	VariantID    uuid.UUID `json:"variant_id"`
+	ProductID    uuid.UUID `json:"product_id"`
+	ProductName  string    `json:"product_name"`
+	VariantTitle string    `json:"variant_title"`
+	SKU          *string   `json:"sku,omitempty"`
+	LocationID   uuid.UUID `json:"location_id"`
+	LocationName string    `json:"location_name"`
+	OnHand       int32     `json:"on_hand"`
+	ReorderPoint int32     `json:"reorder_point"`
+	// SuggestedReorder is the reorder quantity when one is set, otherwise
+	// enough to bring the level back above its reorder point
+	SuggestedReorder int32 `json:"suggested_reorder"`
+	// LowStockSince is when the low stock was alerted on, unset until the
+	// worker next checks
+	LowStockSince *time.Time `json:"low_stock_since,omitempty"`
+}
+
+// InventoryAlertResponse is a level going to or below its reorder point,
+// or back above it
+type InventoryAlertResponse struct {
+	ID           uuid.UUID `json:"id"`
+	Kind         string    `json:"kind"`
+	VariantID    uuid.UUID `json:"variant_id"`
+	LocationID   uuid.UUID `json:"location_id"`
+	OnHand       int32     `json:"on_hand"`
+	ReorderPoint *int32    `json:"reorder_point,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type LowStockCursor struct {
+	ProductName string    `json:"product_name"`
+	ID          uuid.UUID `json:"id"`
+}
+
+var lowStockCursorCodec = CursorCodec[LowStockCursor]{
+	Validate: func(c LowStockCursor) error {
+		if c.ID == uuid.Nil {
+			return errors.New("invalid cursor: missing required fields")
+		}
+		return nil
+	},
+}
+
+type InventoryAlertCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+}
+
+var inventoryAlertCursorCodec = CursorCodec[InventoryAlertCursor]{
+	Validate: func(c InventoryAlertCursor) error {
+		if c.CreatedAt.IsZero() || c.ID == uuid.Nil {
+			return errors.New("invalid cursor: missing required fields")
+		}
+		return nil
+	},
+}
+
+// handlerTenantInventoryReorderPointSet sets the reorder point of a variant
+// at a location, the default one unless location_id is given. A null
+// reorder_point stops watching the level.
+func (cfg *apiConfig) handlerTenantInventoryReorderPointSet(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	variantID, err := uuid.Parse(chi.URLParam(r, "variantID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid variant ID format", err)
+		return
+	}
+	variant, err := cfg.db.GetProductVariantByID(r.Context(), variantID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && variant.StoreID != access.StoreID) {
+		respondWithError(w, http.StatusNotFound, "Variant not found", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve variant", err)
+		return
+	}
+
+	type parameters struct {
+		LocationID      *uuid.UUID `json:"location_id"`
+		ReorderPoint    *int32     `json:"reorder_point"`
+		ReorderQuantity *int32     `json:"reorder_quantity"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	var v validate.Validator
+	if params.ReorderPoint != nil {
+		v.Between("reorder_point", int64(*params.ReorderPoint), 0, inventory.MaxReorderPoint)
+	}
+	if params.ReorderQuantity != nil {
+		v.Check(params.ReorderPoint != nil, "reorder_quantity", validate.CodeInvalid, "requires a reorder_point")
+		v.Between("reorder_quantity", int64(*params.ReorderQuantity), 1, inventory.MaxReorderPoint)
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	var location database.InventoryLocation
+	var level database.InventoryLevel
+	err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		var err error
+		location, err = resolveRestockLocation(r.Context(), q, access.TenantID, params.LocationID)
+		if err != nil {
+			return err
+		}
+		item, err := q.EnsureInventoryItem(r.Context(), database.EnsureInventoryItemParams{
+			TenantID:  access.TenantID,
+			StoreID:   access.StoreID,
+			VariantID: variant.ID,
+		})
+		if err != nil {
+			return err
+		}
+		level, err = q.EnsureInventoryLevel(r.Context(), database.EnsureInventoryLevelParams{
+			TenantID:        access.TenantID,
+			InventoryItemID: item.ID,
+			LocationID:      location.ID,
+		})
+		if err != nil {
+			return err
+		}
+		level, err = q.SetInventoryLevelReorderPoint(r.Context(), database.SetInventoryLevelReorderPointParams{
+			ReorderPoint:    nullInt32FromPtr(params.ReorderPoint),
+			ReorderQuantity: nullInt32FromPtr(params.ReorderQuantity),
+			ID:              level.ID,
+		})
+		return err
+	})
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to set reorder point")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "inventory reorder point set",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"store_id", access.StoreID,
+		"variant_id", variant.ID,
+		"location_id", location.ID,
+	)
+
+	resp := InventoryLevelResponse{
+		LocationID:   location.ID,
+		LocationName: location.Name,
+		OnHand:       level.OnHand,
+		UpdatedAt:    level.UpdatedAt,
+	}
+	if level.ReorderPoint.Valid {
+		resp.ReorderPoint = &level.ReorderPoint.Int32
+	}
+	if level.ReorderQuantity.Valid {
+		resp.ReorderQuantity = &level.ReorderQuantity.Int32
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerTenantInventoryLowStockReport lists the variants at or below their
+// reorder point right now, by product name, optionally at one ?location_id=
+func (cfg *apiConfig) handlerTenantInventoryLowStockReport(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	var locationID uuid.NullUUID
+	if raw := r.URL.Query().Get("location_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid location ID format", err)
+			return
+		}
+		locationID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+	limit := pageParams.Limit
+
+	cursor, hasCursor, err := lowStockCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.db.ListLowStockLevelsPaginated(r.Context(), database.ListLowStockLevelsPaginatedParams{
+		StoreID:    storeID,
+		LocationID: locationID,
+		HasCursor:  hasCursor,
+		CursorName: cursor.ProductName,
+		CursorID:   cursor.ID,
+		RowLimit:   int32(limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve low stock", err)
+		return
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = lowStockCursorCodec.Encode(LowStockCursor{
+			ProductName: last.ProductName,
+			ID:          last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]LowStockResponse, 0, len(rows))
+	for _, row := range rows {
+		resp := LowStockResponse{
+			VariantID:        row.VariantID,
+			ProductID:        row.ProductID,
+			ProductName:      row.ProductName,
+			VariantTitle:     row.VariantTitle,
+			LocationID:       row.LocationID,
+			LocationName:     row.LocationName,
+			OnHand:           row.OnHand,
+			ReorderPoint:     row.ReorderPoint.Int32,
+			SuggestedReorder: inventory.SuggestedReorder(row.OnHand, row.ReorderPoint.Int32, row.ReorderQuantity),
+		}
+		if row.Sku.Valid {
+			resp.SKU = &row.Sku.String
+		}
+		if row.LowStockSince.Valid {
+			resp.LowStockSince = &row.LowStockSince.Time
+		}
+		response = append(response, resp)
+	}
+
+	respondWithJSON(w, http.StatusOK, PageResponse[LowStockResponse]{
+		Data: response,
+		Page: PageInfo{Limit: limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
+
+// handlerTenantInventoryAlertsList lists the store's low stock and restocked
+// alerts, newest first, filtered by ?kind=. Integrations poll it to follow
+// stock crossing reorder points.
+func (cfg *apiConfig) handlerTenantInventoryAlertsList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	kind := r.URL.Query().Get("kind")
+	var v validate.Validator
+	if kind != "" {
+		v.OneOf("kind", kind, inventory.AlertLowStock, inventory.AlertRestocked)
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+	limit := pageParams.Limit
+
+	cursor, hasCursor, err := inventoryAlertCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.db.ListInventoryAlertsPaginated(r.Context(), database.ListInventoryAlertsPaginatedParams{
+		StoreID:         storeID,
+		Kind:            sql.NullString{String: kind, Valid: kind != ""},
+		HasCursor:       hasCursor,
+		CursorCreatedAt: cursor.CreatedAt,
+		CursorID:        cursor.ID,
+		RowLimit:        int32(limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve inventory alerts", err)
+		return
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = inventoryAlertCursorCodec.Encode(InventoryAlertCursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]InventoryAlertResponse, 0, len(rows))
+	for _, alert := range rows {
+		resp := InventoryAlertResponse{
+			ID:         alert.ID,
+			Kind:       alert.Kind,
+			VariantID:  alert.VariantID,
+			LocationID: alert.LocationID,
+			OnHand:     alert.OnHand,
+			CreatedAt:  alert.CreatedAt,
+		}
+		if alert.ReorderPoint.Valid {
+			resp.ReorderPoint = &alert.ReorderPoint.Int32
+		}
+		response = append(response, resp)
+	}
+
+	respondWithJSON(w, http.StatusOK, PageResponse[InventoryAlertResponse]{
+		Data: response,
+		Page: PageInfo{Limit: limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
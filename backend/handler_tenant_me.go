@@ -0,0 +1,102 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/platform"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/google/uuid"
+)
+
+// TenantMeResponse is what the caller may do in a tenant, so a frontend can
+// hide controls rather than wait for a 403
+type TenantMeResponse struct {
+	TenantID     uuid.UUID      `json:"tenant_id"`
+	UserID       uuid.UUID      `json:"user_id"`
+	MembershipID uuid.UUID      `json:"membership_id"`
+	Status       string         `json:"status"`
+	Roles        []TenantMeRole `json:"roles"`
+	Permissions  []string       `json:"permissions"`
+}
+
+type TenantMeRole struct {
+	ID     uuid.UUID `json:"id"`
+	Name   string    `json:"name"`
+	System bool      `json:"system"`
+}
+
+// handlerTenantMe returns the caller's membership, roles and the permission
+// keys they hold across them. An API key holds exactly its scopes.
+func (cfg *apiConfig) handlerTenantMe(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	tc := tenantContextFrom(r)
+
+	permissions, err := cfg.callerPermissions(r)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve permissions", err)
+		return
+	}
+
+	roles := make([]TenantMeRole, 0, len(tc.Roles))
+	for _, role := range tc.Roles {
+		roles = append(roles, TenantMeRole{ID: role.ID, Name: role.Name, System: role.IsSystem})
+	}
+
+	slog.InfoContext(r.Context(), "tenant permissions retrieved",
+		"request_id", reqID,
+		"user_id", tc.Membership.UserID,
+		"tenant_id", tc.Tenant.ID,
+		"permission_count", len(permissions),
+	)
+
+	respondWithJSON(w, http.StatusOK, TenantMeResponse{
+		TenantID:     tc.Tenant.ID,
+		UserID:       tc.Membership.UserID,
+		MembershipID: tc.Membership.ID,
+		Status:       tc.Membership.Status,
+		Roles:        roles,
+		Permissions:  permissions,
+	})
+}
+
+// callerPermissions lists the permission keys the caller holds in the
+// tenant. An API key holds exactly its scopes, and impersonating staff
+// every permission impersonation allows.
+func (cfg *apiConfig) callerPermissions(r *http.Request) ([]string, error) {
+	if key, ok := apiKeyFromContext(r.Context()); ok {
+		permissions := slices.Clone(key.Scopes)
+		slices.Sort(permissions)
+		return permissions, nil
+	}
+	if _, ok := impersonatedTenantFromContext(r.Context()); ok {
+		all, err := cfg.db.GetAllPermissions(r.Context())
+		if err != nil {
+			return nil, err
+		}
+		permissions := make([]string, 0, len(all))
+		for _, p := range all {
+			if platform.ImpersonationAllows(p.Key) {
+				permissions = append(permissions, p.Key)
+			}
+		}
+		slices.Sort(permissions)
+		return permissions, nil
+	}
+
+	tc := tenantContextFrom(r)
+	granted, err := cfg.db.GetUserPermissionsInTenant(r.Context(), database.GetUserPermissionsInTenantParams{
+		TenantID: tc.Tenant.ID,
+		UserID:   tc.Membership.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	permissions := make([]string, 0, len(granted))
+	for _, p := range granted {
+		permissions = append(permissions, p.Key)
+	}
+	return permissions, nil
+}
@@ -1,28 +1,40 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
+	"github.com/dfodeker/terminus/internal/auth"
 	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/mailer"
+	"github.com/dfodeker/terminus/internal/platform"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service/tenants"
+	"github.com/dfodeker/terminus/internal/validate"
 	"github.com/dfodeker/terminus/middleware"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
 type TenantMemberResponse struct {
-	ID        uuid.UUID `json:"id"`
-	TenantID  uuid.UUID `json:"tenant_id"`
-	UserID    uuid.UUID `json:"user_id"`
-	Email     string    `json:"email"`
-	Status    string    `json:"status"`
-	Roles     []string  `json:"roles"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          uuid.UUID `json:"id"`
+	TenantID    uuid.UUID `json:"tenant_id"`
+	UserID      uuid.UUID `json:"user_id"`
+	Email       string    `json:"email"`
+	DisplayName *string   `json:"display_name"`
+	Status      string    `json:"status"`
+	Roles       []string  `json:"roles"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
 
 type TenantMemberCursor struct {
@@ -44,7 +56,8 @@ const (
 	maxMemberLimit     = 100
 )
 
-// handlerTenantMembersInvite invites a user to a tenant
+// handlerTenantMembersInvite emails an invitation to join a tenant. The
+// address doesn't need an account; the invitee can sign up when accepting.
 func (cfg *apiConfig) handlerTenantMembersInvite(w http.ResponseWriter, r *http.Request) {
 	reqID := middleware.GetRequestID(r.Context())
 	tenantParam := chi.URLParam(r, "tenantID")
@@ -54,89 +67,82 @@ func (cfg *apiConfig) handlerTenantMembersInvite(w http.ResponseWriter, r *http.
 		"tenant_param", tenantParam,
 	)
 
-	user, ok := userFromContext(r.Context())
-	if !ok {
-		slog.WarnContext(r.Context(), "tenant member invite failed: no authenticated user",
-			"request_id", reqID,
-		)
-		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+	access := tenantAccessFrom(r)
+	user, tenantID := access.UserID, access.TenantID
+
+	if !cfg.requireVerifiedEmail(w, r, user) {
 		return
 	}
 
-	tenantID, err := uuid.Parse(tenantParam)
-	if err != nil {
-		slog.WarnContext(r.Context(), "tenant member invite failed: invalid tenant ID",
-			"request_id", reqID,
-			"user_id", user,
-			"error", err,
-		)
-		respondWithError(w, http.StatusBadRequest, "Invalid tenant ID format", err)
-		return
+	type parameters struct {
+		Email  string `json:"email"`
+		RoleID string `json:"role_id"`
 	}
 
-	// Verify requesting user has permission to invite
-	hasPermission, err := cfg.db.CheckUserHasPermission(r.Context(), database.CheckUserHasPermissionParams{
-		TenantID: tenantID,
-		UserID:   user,
-		Key:      "tenant:invite_users",
-	})
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
 	if err != nil {
-		slog.ErrorContext(r.Context(), "tenant member invite failed: error checking permissions",
+		slog.WarnContext(r.Context(), "tenant member invite failed: invalid request body",
 			"request_id", reqID,
 			"user_id", user,
 			"tenant_id", tenantID,
 			"error", err,
 		)
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify permissions", err)
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
 		return
 	}
 
-	if !hasPermission {
-		slog.WarnContext(r.Context(), "tenant member invite denied: insufficient permissions",
-			"request_id", reqID,
-			"user_id", user,
-			"tenant_id", tenantID,
-		)
-		respondWithError(w, http.StatusForbidden, "You do not have permission to invite users to this tenant", nil)
+	var v validate.Validator
+	if v.Required("email", params.Email) {
+		v.Email("email", strings.TrimSpace(params.Email))
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
 		return
 	}
+	email := strings.ToLower(strings.TrimSpace(params.Email))
 
-	type parameters struct {
-		Email string `json:"email"`
+	// An optional role is granted when the invitation is accepted
+	var roleID uuid.NullUUID
+	if params.RoleID != "" {
+		id, err := uuid.Parse(params.RoleID)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid role ID format", err)
+			return
+		}
+		_, err = cfg.db.GetRoleByTenantAndID(r.Context(), database.GetRoleByTenantAndIDParams{
+			TenantID: tenantID,
+			ID:       id,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				respondWithError(w, http.StatusNotFound, "Role not found in this tenant", nil)
+				return
+			}
+			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve role", err)
+			return
+		}
+		roleID = uuid.NullUUID{UUID: id, Valid: true}
 	}
 
-	decoder := json.NewDecoder(r.Body)
-	params := parameters{}
-	err = decoder.Decode(&params)
+	tenant, err := cfg.db.GetTenantByID(r.Context(), tenantID)
 	if err != nil {
-		slog.WarnContext(r.Context(), "tenant member invite failed: invalid request body",
-			"request_id", reqID,
-			"user_id", user,
-			"tenant_id", tenantID,
-			"error", err,
-		)
-		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve tenant", err)
 		return
 	}
 
-	if params.Email == "" {
-		respondWithError(w, http.StatusBadRequest, "Email is required", nil)
+	inviter, err := cfg.db.GetUserByID(r.Context(), user)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve user", err)
 		return
 	}
 
-	// Find user by email
-	invitedUser, err := cfg.db.GetUserByEmail(r.Context(), params.Email)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			slog.WarnContext(r.Context(), "tenant member invite failed: user not found",
-				"request_id", reqID,
-				"user_id", user,
-				"tenant_id", tenantID,
-				"invited_email", params.Email,
-			)
-			respondWithError(w, http.StatusNotFound, "User with this email not found", nil)
-			return
-		}
+	// The invitee may not have an account yet. If they do, they show up in
+	// the member list as invited until they accept.
+	invitedUser, err := cfg.db.GetUserByEmail(r.Context(), email)
+	hasAccount := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		slog.ErrorContext(r.Context(), "tenant member invite failed: error finding user",
 			"request_id", reqID,
 			"user_id", user,
@@ -147,44 +153,87 @@ func (cfg *apiConfig) handlerTenantMembersInvite(w http.ResponseWriter, r *http.
 		return
 	}
 
-	// Check if user is already a member
-	existingMember, err := cfg.db.GetTenantUser(r.Context(), database.GetTenantUserParams{
-		TenantID: tenantID,
-		UserID:   invitedUser.ID,
-	})
-	if err == nil {
-		if existingMember.Status == "active" {
-			respondWithError(w, http.StatusConflict, "User is already an active member of this tenant", nil)
+	if hasAccount {
+		existingMember, err := cfg.db.GetTenantUser(r.Context(), database.GetTenantUserParams{
+			TenantID: tenantID,
+			UserID:   invitedUser.ID,
+		})
+		if err == nil && existingMember.Status == "active" {
+			respondWithErrorCode(w, http.StatusConflict, problem.CodeAlreadyExists, "User is already an active member of this tenant", nil)
 			return
-		}
-		if existingMember.Status == "invited" {
-			respondWithError(w, http.StatusConflict, "User has already been invited to this tenant", nil)
+		} else if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			slog.ErrorContext(r.Context(), "tenant member invite failed: error checking existing membership",
+				"request_id", reqID,
+				"error", err,
+			)
+			respondWithError(w, http.StatusInternalServerError, "Unable to check existing membership", err)
 			return
 		}
-		// If status is 'removed', we can re-invite
-	} else if !errors.Is(err, sql.ErrNoRows) {
-		slog.ErrorContext(r.Context(), "tenant member invite failed: error checking existing membership",
-			"request_id", reqID,
-			"error", err,
-		)
-		respondWithError(w, http.StatusInternalServerError, "Unable to check existing membership", err)
+		// Invited and removed members can be invited again; the new link
+		// replaces the old one
+	}
+
+	if err := cfg.services.entitlements.CheckSeats(r.Context(), tenantID, email); err != nil {
+		respondWithServiceError(w, err, "Unable to create invitation")
 		return
 	}
 
-	// Create tenant user with 'invited' status
-	tenantUser, err := cfg.db.CreateTenantUser(r.Context(), database.CreateTenantUserParams{
-		TenantID: tenantID,
-		UserID:   invitedUser.ID,
-		Status:   "invited",
+	token, err := auth.MakeOneTimeToken()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create invitation", err)
+		return
+	}
+
+	var invitation database.TenantInvitation
+	err = cfg.withTenantTx(r.Context(), tenantID, func(q *database.Queries) error {
+		var err error
+		if hasAccount {
+			_, err = q.UpsertTenantUser(r.Context(), database.UpsertTenantUserParams{
+				TenantID: tenantID,
+				UserID:   invitedUser.ID,
+				Status:   "invited",
+			})
+			if err != nil {
+				slog.ErrorContext(r.Context(), "tenant member invite failed: error creating tenant user",
+					"request_id", reqID,
+					"user_id", user,
+					"tenant_id", tenantID,
+					"invited_user_id", invitedUser.ID,
+					"error", err,
+				)
+				return err
+			}
+		}
+
+		err = q.RevokeOpenTenantInvitations(r.Context(), database.RevokeOpenTenantInvitationsParams{
+			TenantID: tenantID,
+			Email:    email,
+		})
+		if err != nil {
+			return err
+		}
+
+		invitation, err = q.CreateTenantInvitation(r.Context(), database.CreateTenantInvitationParams{
+			TenantID:  tenantID,
+			Email:     email,
+			RoleID:    roleID,
+			TokenHash: auth.HashToken(token),
+			InvitedBy: uuid.NullUUID{UUID: user, Valid: true},
+			ExpiresAt: time.Now().Add(invitationTTL),
+		})
+		if err != nil {
+			return err
+		}
+
+		err = cfg.enqueueEmail(r.Context(), q, email, mailer.TemplateInvite, mailer.InviteData{
+			InviterName: inviter.Email,
+			TenantName:  tenant.Name,
+			AcceptURL:   cfg.config.AppURL + "/invitations/accept?token=" + url.QueryEscape(token),
+			ExpiresAt:   invitation.ExpiresAt,
+		})
+		return err
 	})
 	if err != nil {
-		slog.ErrorContext(r.Context(), "tenant member invite failed: error creating tenant user",
-			"request_id", reqID,
-			"user_id", user,
-			"tenant_id", tenantID,
-			"invited_user_id", invitedUser.ID,
-			"error", err,
-		)
 		respondWithError(w, http.StatusInternalServerError, "Unable to create invitation", err)
 		return
 	}
@@ -193,23 +242,15 @@ func (cfg *apiConfig) handlerTenantMembersInvite(w http.ResponseWriter, r *http.
 		"request_id", reqID,
 		"user_id", user,
 		"tenant_id", tenantID,
-		"invited_user_id", invitedUser.ID,
-		"tenant_user_id", tenantUser.ID,
+		"invitation_id", invitation.ID,
+		"has_account", hasAccount,
 	)
 
-	respondWithJSON(w, http.StatusCreated, TenantMemberResponse{
-		ID:        tenantUser.ID,
-		TenantID:  tenantUser.TenantID,
-		UserID:    tenantUser.UserID,
-		Email:     invitedUser.Email,
-		Status:    tenantUser.Status,
-		Roles:     []string{},
-		CreatedAt: tenantUser.CreatedAt,
-		UpdatedAt: tenantUser.UpdatedAt,
-	})
+	respondWithJSON(w, http.StatusCreated, invitationToResponse(invitation))
 }
 
-// handlerTenantMembersList lists all members of a tenant with pagination
+// handlerTenantMembersList lists the members of a tenant with pagination.
+// q narrows them to those whose email or display name contains it.
 func (cfg *apiConfig) handlerTenantMembersList(w http.ResponseWriter, r *http.Request) {
 	reqID := middleware.GetRequestID(r.Context())
 	tenantParam := chi.URLParam(r, "tenantID")
@@ -219,53 +260,8 @@ func (cfg *apiConfig) handlerTenantMembersList(w http.ResponseWriter, r *http.Re
 		"tenant_param", tenantParam,
 	)
 
-	user, ok := userFromContext(r.Context())
-	if !ok {
-		slog.WarnContext(r.Context(), "tenant members list failed: no authenticated user",
-			"request_id", reqID,
-		)
-		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
-		return
-	}
-
-	tenantID, err := uuid.Parse(tenantParam)
-	if err != nil {
-		slog.WarnContext(r.Context(), "tenant members list failed: invalid tenant ID",
-			"request_id", reqID,
-			"user_id", user,
-			"error", err,
-		)
-		respondWithError(w, http.StatusBadRequest, "Invalid tenant ID format", err)
-		return
-	}
-
-	// Verify user is a member of the tenant
-	tenantUser, err := cfg.db.GetTenantUser(r.Context(), database.GetTenantUserParams{
-		TenantID: tenantID,
-		UserID:   user,
-	})
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			slog.WarnContext(r.Context(), "tenant members list denied: user not a member",
-				"request_id", reqID,
-				"user_id", user,
-				"tenant_id", tenantID,
-			)
-			respondWithError(w, http.StatusForbidden, "You are not a member of this tenant", nil)
-			return
-		}
-		slog.ErrorContext(r.Context(), "tenant members list failed: error checking membership",
-			"request_id", reqID,
-			"error", err,
-		)
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify membership", err)
-		return
-	}
-
-	if tenantUser.Status != "active" {
-		respondWithError(w, http.StatusForbidden, "Your membership is not active", nil)
-		return
-	}
+	tc := tenantContextFrom(r)
+	user, tenantID := tc.Membership.UserID, tc.Tenant.ID
 
 	pageParams, err := ParsePageParams(r, defaultMemberLimit, maxMemberLimit)
 	if err != nil {
@@ -276,6 +272,12 @@ func (cfg *apiConfig) handlerTenantMembersList(w http.ResponseWriter, r *http.Re
 	limit := pageParams.Limit
 	limitPlusOne := int32(pageParams.Limit + 1)
 
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if len(query) > platform.MaxQueryLength {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("q is longer than %d characters", platform.MaxQueryLength), nil)
+		return
+	}
+
 	cursorCreatedAt, cursorID, hasCursor, err := decodeTenantMemberCursor(pageParams.Cursor)
 	if err != nil {
 		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
@@ -291,11 +293,12 @@ func (cfg *apiConfig) handlerTenantMembersList(w http.ResponseWriter, r *http.Re
 	)
 
 	rows, err := cfg.db.GetTenantUsersWithDetailsPaginated(r.Context(), database.GetTenantUsersWithDetailsPaginatedParams{
-		TenantID: tenantID,
-		Column2:  hasCursor,
-		Column3:  cursorCreatedAt,
-		Column4:  cursorID,
-		Limit:    limitPlusOne,
+		TenantID:        tenantID,
+		Query:           sql.NullString{String: query, Valid: query != ""},
+		HasCursor:       hasCursor,
+		CursorCreatedAt: cursorCreatedAt,
+		CursorID:        cursorID,
+		RowLimit:        limitPlusOne,
 	})
 	if err != nil {
 		slog.ErrorContext(r.Context(), "tenant members list failed: database query error",
@@ -326,25 +329,34 @@ func (cfg *apiConfig) handlerTenantMembersList(w http.ResponseWriter, r *http.Re
 		}
 	}
 
-	// Fetch roles for each member
-	response := make([]TenantMemberResponse, 0, len(rows))
+	// Fetch roles for the whole page in one query
+	memberIDs := make([]uuid.UUID, 0, len(rows))
 	for _, member := range rows {
-		roles, err := cfg.db.GetRolesByTenantUserID(r.Context(), member.ID)
-		if err != nil {
-			slog.ErrorContext(r.Context(), "tenant members list: error fetching roles for member",
-				"request_id", reqID,
-				"tenant_user_id", member.ID,
-				"error", err,
-			)
-			roles = []database.Role{}
-		}
+		memberIDs = append(memberIDs, member.ID)
+	}
+	assigned, err := cfg.db.GetRoleNamesByTenantUserIDs(r.Context(), memberIDs)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "tenant members list failed: error fetching roles",
+			"request_id", reqID,
+			"tenant_id", tenantID,
+			"error", err,
+		)
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve member roles", err)
+		return
+	}
+	roleNamesByMember := make(map[uuid.UUID][]string, len(assigned))
+	for _, a := range assigned {
+		roleNamesByMember[a.TenantUserID] = a.RoleNames
+	}
 
-		roleNames := make([]string, 0, len(roles))
-		for _, role := range roles {
-			roleNames = append(roleNames, role.Name)
+	response := make([]TenantMemberResponse, 0, len(rows))
+	for _, member := range rows {
+		roleNames := roleNamesByMember[member.ID]
+		if roleNames == nil {
+			roleNames = []string{}
 		}
 
-		response = append(response, TenantMemberResponse{
+		resp := TenantMemberResponse{
 			ID:        member.ID,
 			TenantID:  member.TenantID,
 			UserID:    member.UserID,
@@ -353,7 +365,11 @@ func (cfg *apiConfig) handlerTenantMembersList(w http.ResponseWriter, r *http.Re
 			Roles:     roleNames,
 			CreatedAt: member.CreatedAt,
 			UpdatedAt: member.UpdatedAt,
-		})
+		}
+		if member.DisplayName.Valid {
+			resp.DisplayName = &member.DisplayName.String
+		}
+		response = append(response, resp)
 	}
 
 	slog.InfoContext(r.Context(), "tenant members list successful",
@@ -364,13 +380,9 @@ func (cfg *apiConfig) handlerTenantMembersList(w http.ResponseWriter, r *http.Re
 		"has_more", hasMore,
 	)
 
-	respondWithJSON(w, http.StatusOK, map[string]any{
-		"data": response,
-		"page": map[string]any{
-			"limit":       limit,
-			"has_more":    hasMore,
-			"next_cursor": nextCursor,
-		},
+	respondWithJSON(w, http.StatusOK, PageResponse[TenantMemberResponse]{
+		Data: response,
+		Page: PageInfo{Limit: limit, NextCursor: nextCursor, HasMore: hasMore},
 	})
 }
 
@@ -386,17 +398,8 @@ func (cfg *apiConfig) handlerTenantMemberAssignRole(w http.ResponseWriter, r *ht
 		"member_param", memberParam,
 	)
 
-	user, ok := userFromContext(r.Context())
-	if !ok {
-		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
-		return
-	}
-
-	tenantID, err := uuid.Parse(tenantParam)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid tenant ID format", err)
-		return
-	}
+	access := tenantAccessFrom(r)
+	user, tenantID := access.UserID, access.TenantID
 
 	memberID, err := uuid.Parse(memberParam)
 	if err != nil {
@@ -404,31 +407,6 @@ func (cfg *apiConfig) handlerTenantMemberAssignRole(w http.ResponseWriter, r *ht
 		return
 	}
 
-	// Verify requesting user has permission to manage users
-	hasPermission, err := cfg.db.CheckUserHasPermission(r.Context(), database.CheckUserHasPermissionParams{
-		TenantID: tenantID,
-		UserID:   user,
-		Key:      "tenant:manage_users",
-	})
-	if err != nil {
-		slog.ErrorContext(r.Context(), "tenant member role assignment failed: error checking permissions",
-			"request_id", reqID,
-			"error", err,
-		)
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify permissions", err)
-		return
-	}
-
-	if !hasPermission {
-		slog.WarnContext(r.Context(), "tenant member role assignment denied: insufficient permissions",
-			"request_id", reqID,
-			"user_id", user,
-			"tenant_id", tenantID,
-		)
-		respondWithError(w, http.StatusForbidden, "You do not have permission to manage users", nil)
-		return
-	}
-
 	// Verify the member exists and belongs to this tenant
 	tenantUser, err := cfg.db.GetTenantUserByID(r.Context(), memberID)
 	if err != nil {
@@ -524,17 +502,8 @@ func (cfg *apiConfig) handlerTenantMemberRemoveRole(w http.ResponseWriter, r *ht
 		"role_param", roleParam,
 	)
 
-	user, ok := userFromContext(r.Context())
-	if !ok {
-		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
-		return
-	}
-
-	tenantID, err := uuid.Parse(tenantParam)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid tenant ID format", err)
-		return
-	}
+	access := tenantAccessFrom(r)
+	user, tenantID := access.UserID, access.TenantID
 
 	memberID, err := uuid.Parse(memberParam)
 	if err != nil {
@@ -548,22 +517,6 @@ func (cfg *apiConfig) handlerTenantMemberRemoveRole(w http.ResponseWriter, r *ht
 		return
 	}
 
-	// Verify requesting user has permission to manage users
-	hasPermission, err := cfg.db.CheckUserHasPermission(r.Context(), database.CheckUserHasPermissionParams{
-		TenantID: tenantID,
-		UserID:   user,
-		Key:      "tenant:manage_users",
-	})
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify permissions", err)
-		return
-	}
-
-	if !hasPermission {
-		respondWithError(w, http.StatusForbidden, "You do not have permission to manage users", nil)
-		return
-	}
-
 	// Remove the role
 	err = cfg.db.RemoveRoleFromTenantUser(r.Context(), database.RemoveRoleFromTenantUserParams{
 		TenantUserID: memberID,
@@ -593,6 +546,149 @@ func (cfg *apiConfig) handlerTenantMemberRemoveRole(w http.ResponseWriter, r *ht
 	})
 }
 
+// handlerTenantMemberRemove takes a member out of the tenant along with
+// their roles
+func (cfg *apiConfig) handlerTenantMemberRemove(w http.ResponseWriter, r *http.Request) {
+	_, ok := cfg.changeTenantMember(w, r, "remove", cfg.services.tenants.RemoveMember)
+	if ok {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// handlerTenantMemberSuspend stops a member reaching the tenant until they
+// are reactivated. Their roles are kept.
+func (cfg *apiConfig) handlerTenantMemberSuspend(w http.ResponseWriter, r *http.Request) {
+	member, ok := cfg.changeTenantMember(w, r, "suspend", cfg.services.tenants.SuspendMember)
+	if ok {
+		respondWithJSON(w, http.StatusOK, member)
+	}
+}
+
+// handlerTenantMemberReactivate restores a suspended member's access
+func (cfg *apiConfig) handlerTenantMemberReactivate(w http.ResponseWriter, r *http.Request) {
+	member, ok := cfg.changeTenantMember(w, r, "reactivate", cfg.services.tenants.ReactivateMember)
+	if ok {
+		respondWithJSON(w, http.StatusOK, member)
+	}
+}
+
+// handlerTenantLeave takes the caller out of the tenant
+func (cfg *apiConfig) handlerTenantLeave(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	tc := tenantContextFrom(r)
+
+	before, err := cfg.tenantMemberToResponse(r.Context(), tc.Membership)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve membership", err)
+		return
+	}
+
+	if _, err := cfg.services.tenants.Leave(r.Context(), tc.Tenant.ID, tc.Membership.UserID); err != nil {
+		respondWithServiceError(w, err, "Unable to leave tenant")
+		return
+	}
+	auditChange(r, gid.GID{}, before, nil)
+
+	slog.InfoContext(r.Context(), "tenant left",
+		"request_id", reqID,
+		"user_id", tc.Membership.UserID,
+		"tenant_id", tc.Tenant.ID,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// changeTenantMember applies change to the member named in the URL and
+// records it in the audit log. It writes the error response itself and
+// reports whether the change was made.
+func (cfg *apiConfig) changeTenantMember(
+	w http.ResponseWriter,
+	r *http.Request,
+	action string,
+	change func(context.Context, tenants.MemberChange) (database.TenantUser, error),
+) (TenantMemberResponse, bool) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	memberID, err := uuid.Parse(chi.URLParam(r, "memberID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid member ID format", err)
+		return TenantMemberResponse{}, false
+	}
+
+	existing, err := cfg.db.GetTenantUserByID(r.Context(), memberID)
+	if err != nil || existing.TenantID != access.TenantID {
+		if err == nil || errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Member not found in this tenant", nil)
+			return TenantMemberResponse{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to find member", err)
+		return TenantMemberResponse{}, false
+	}
+	before, err := cfg.tenantMemberToResponse(r.Context(), existing)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to find member", err)
+		return TenantMemberResponse{}, false
+	}
+
+	changed, err := change(r.Context(), tenants.MemberChange{
+		TenantID: access.TenantID,
+		MemberID: memberID,
+		ActorID:  access.UserID,
+	})
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to "+action+" member")
+		return TenantMemberResponse{}, false
+	}
+
+	var after any
+	member := before
+	if action != "remove" {
+		member.Status, member.UpdatedAt = changed.Status, changed.UpdatedAt
+		after = member
+	}
+	auditChange(r, gid.GID{}, before, after)
+
+	slog.InfoContext(r.Context(), "tenant member changed",
+		"request_id", reqID,
+		"action", action,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"tenant_user_id", memberID,
+	)
+	return member, true
+}
+
+// tenantMemberToResponse fills in the member's email and role names
+func (cfg *apiConfig) tenantMemberToResponse(ctx context.Context, m database.TenantUser) (TenantMemberResponse, error) {
+	user, err := cfg.db.GetUserByID(ctx, m.UserID)
+	if err != nil {
+		return TenantMemberResponse{}, err
+	}
+	roles, err := cfg.db.GetRolesByTenantUserID(ctx, m.ID)
+	if err != nil {
+		return TenantMemberResponse{}, err
+	}
+	roleNames := make([]string, 0, len(roles))
+	for _, role := range roles {
+		roleNames = append(roleNames, role.Name)
+	}
+	resp := TenantMemberResponse{
+		ID:        m.ID,
+		TenantID:  m.TenantID,
+		UserID:    m.UserID,
+		Email:     user.Email,
+		Status:    m.Status,
+		Roles:     roleNames,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+	if user.DisplayName.Valid {
+		resp.DisplayName = &user.DisplayName.String
+	}
+	return resp, nil
+}
+
 func decodeTenantMemberCursor(cursor string) (time.Time, uuid.UUID, bool, error) {
 	cur, ok, err := tenantMemberCursorCodec.Decode(cursor)
 	if err != nil {
@@ -0,0 +1,376 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/notifications"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type NotificationResponse struct {
+	ID        uuid.UUID       `json:"id"`
+	Kind      string          `json:"kind"`
+	StoreID   *uuid.UUID      `json:"store_id,omitempty"`
+	Title     string          `json:"title"`
+	Body      string          `json:"body,omitempty"`
+	Data      json.RawMessage `json:"data"`
+	Read      bool            `json:"read"`
+	ReadAt    *time.Time      `json:"read_at,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// NotificationPreferenceResponse is whether a tenant records one kind
+type NotificationPreferenceResponse struct {
+	Kind       string `json:"kind"`
+	Permission string `json:"permission"`
+	Enabled    bool   `json:"enabled"`
+}
+
+type NotificationCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+}
+
+var notificationCursorCodec = CursorCodec[NotificationCursor]{
+	Validate: func(c NotificationCursor) error {
+		if c.CreatedAt.IsZero() || c.ID == uuid.Nil {
+			return errors.New("invalid cursor: missing required fields")
+		}
+		return nil
+	},
+}
+
+// handlerTenantNotificationsList returns the caller's notifications,
+// newest first. Members only see the kinds their permissions cover.
+// ?unread=true leaves out what they have read and ?store_id= narrows to a
+// store.
+func (cfg *apiConfig) handlerTenantNotificationsList(w http.ResponseWriter, r *http.Request) {
+	tc := tenantContextFrom(r)
+
+	kinds, ok := cfg.visibleNotificationKinds(w, r)
+	if !ok {
+		return
+	}
+
+	var storeID uuid.NullUUID
+	if s := r.URL.Query().Get("store_id"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid store ID format", err)
+			return
+		}
+		storeID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+	cursor, hasCursor, err := notificationCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.db.ListNotifications(r.Context(), database.ListNotificationsParams{
+		UserID:          tc.Membership.UserID,
+		TenantID:        tc.Tenant.ID,
+		Kinds:           kinds,
+		UnreadOnly:      r.URL.Query().Get("unread") == "true",
+		StoreID:         storeID,
+		HasCursor:       hasCursor,
+		CursorCreatedAt: cursor.CreatedAt,
+		CursorID:        cursor.ID,
+		RowLimit:        int32(pageParams.Limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve notifications", err)
+		return
+	}
+
+	hasMore := len(rows) > pageParams.Limit
+	if hasMore {
+		rows = rows[:pageParams.Limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = notificationCursorCodec.Encode(NotificationCursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]NotificationResponse, 0, len(rows))
+	for _, n := range rows {
+		response = append(response, notificationToResponse(database.GetNotificationRow(n)))
+	}
+
+	respondWithJSON(w, http.StatusOK, PageResponse[NotificationResponse]{
+		Data: response,
+		Page: PageInfo{Limit: pageParams.Limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
+
+// handlerTenantNotificationsUnreadCount counts the caller's unread
+// notifications, for a badge
+func (cfg *apiConfig) handlerTenantNotificationsUnreadCount(w http.ResponseWriter, r *http.Request) {
+	tc := tenantContextFrom(r)
+
+	kinds, ok := cfg.visibleNotificationKinds(w, r)
+	if !ok {
+		return
+	}
+	count, err := cfg.db.CountUnreadNotifications(r.Context(), database.CountUnreadNotificationsParams{
+		TenantID: tc.Tenant.ID,
+		Kinds:    kinds,
+		UserID:   tc.Membership.UserID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to count notifications", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]int64{"unread": count})
+}
+
+// handlerTenantNotificationRead marks a notification read for the caller
+func (cfg *apiConfig) handlerTenantNotificationRead(w http.ResponseWriter, r *http.Request) {
+	cfg.setNotificationRead(w, r, true)
+}
+
+// handlerTenantNotificationUnread marks a notification unread for the
+// caller
+func (cfg *apiConfig) handlerTenantNotificationUnread(w http.ResponseWriter, r *http.Request) {
+	cfg.setNotificationRead(w, r, false)
+}
+
+func (cfg *apiConfig) setNotificationRead(w http.ResponseWriter, r *http.Request, read bool) {
+	tc := tenantContextFrom(r)
+
+	id, err := uuid.Parse(chi.URLParam(r, "notificationID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid notification ID format", err)
+		return
+	}
+	kinds, ok := cfg.visibleNotificationKinds(w, r)
+	if !ok {
+		return
+	}
+
+	params := database.GetNotificationParams{UserID: tc.Membership.UserID, ID: id, TenantID: tc.Tenant.ID}
+	n, err := cfg.db.GetNotification(r.Context(), params)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !slices.Contains(kinds, n.Kind)) {
+		respondWithError(w, http.StatusNotFound, "Notification not found", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve notification", err)
+		return
+	}
+
+	if read {
+		err = cfg.db.MarkNotificationRead(r.Context(), database.MarkNotificationReadParams{NotificationID: n.ID, UserID: tc.Membership.UserID})
+	} else {
+		err = cfg.db.MarkNotificationUnread(r.Context(), database.MarkNotificationUnreadParams{NotificationID: n.ID, UserID: tc.Membership.UserID})
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update notification", err)
+		return
+	}
+
+	n, err = cfg.db.GetNotification(r.Context(), params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve notification", err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, notificationToResponse(n))
+}
+
+// handlerTenantNotificationsReadAll marks every notification the caller
+// can see read
+func (cfg *apiConfig) handlerTenantNotificationsReadAll(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	tc := tenantContextFrom(r)
+
+	kinds, ok := cfg.visibleNotificationKinds(w, r)
+	if !ok {
+		return
+	}
+	marked, err := cfg.db.MarkAllNotificationsRead(r.Context(), database.MarkAllNotificationsReadParams{
+		UserID:   tc.Membership.UserID,
+		TenantID: tc.Tenant.ID,
+		Kinds:    kinds,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update notifications", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant notifications marked read",
+		"request_id", reqID,
+		"user_id", tc.Membership.UserID,
+		"tenant_id", tc.Tenant.ID,
+		"marked", marked,
+	)
+
+	respondWithJSON(w, http.StatusOK, map[string]int64{"marked": marked})
+}
+
+// handlerTenantNotificationPreferencesGet lists every kind and whether the
+// tenant records it
+func (cfg *apiConfig) handlerTenantNotificationPreferencesGet(w http.ResponseWriter, r *http.Request) {
+	access := tenantAccessFrom(r)
+
+	prefs, err := cfg.notificationPreferences(r.Context(), access.TenantID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve notification preferences", err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, prefs)
+}
+
+// handlerTenantNotificationPreferencesUpdate turns kinds on or off for
+// the whole tenant. The body maps kinds to whether they are on; kinds left
+// out keep their setting.
+func (cfg *apiConfig) handlerTenantNotificationPreferencesUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	var params map[string]bool
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	var v validate.Validator
+	valid := make([]string, 0, len(notifications.Kinds))
+	for _, k := range notifications.Kinds {
+		valid = append(valid, string(k))
+	}
+	for kind := range params {
+		v.OneOf(kind, kind, valid...)
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	before, err := cfg.notificationPreferences(r.Context(), access.TenantID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update notification preferences", err)
+		return
+	}
+	err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		for _, k := range notifications.Kinds {
+			enabled, ok := params[string(k)]
+			if !ok {
+				continue
+			}
+			err := q.UpsertNotificationPreference(r.Context(), database.UpsertNotificationPreferenceParams{
+				TenantID: access.TenantID,
+				Kind:     string(k),
+				Enabled:  enabled,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update notification preferences", err)
+		return
+	}
+	prefs, err := cfg.notificationPreferences(r.Context(), access.TenantID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve notification preferences", err)
+		return
+	}
+	auditChange(r, auditGID(gid.EntityTenant, tenantContextFrom(r).Tenant.Gid), before, prefs)
+
+	slog.InfoContext(r.Context(), "tenant notification preferences updated",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+	)
+
+	respondWithJSON(w, http.StatusOK, prefs)
+}
+
+// visibleNotificationKinds lists the kinds the caller may see
+func (cfg *apiConfig) visibleNotificationKinds(w http.ResponseWriter, r *http.Request) ([]string, bool) {
+	permissions, err := cfg.callerPermissions(r)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve permissions", err)
+		return nil, false
+	}
+	return notifications.Visible(permissions), true
+}
+
+func (cfg *apiConfig) notificationPreferences(ctx context.Context, tenantID uuid.UUID) ([]NotificationPreferenceResponse, error) {
+	rows, err := cfg.db.GetNotificationPreferences(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	prefs := notifications.Preferences(rows)
+	resp := make([]NotificationPreferenceResponse, 0, len(notifications.Kinds))
+	for _, k := range notifications.Kinds {
+		resp = append(resp, NotificationPreferenceResponse{
+			Kind:       string(k),
+			Permission: k.Permission(),
+			Enabled:    prefs[k],
+		})
+	}
+	return resp, nil
+}
+
+// notify records a notification for staff. Notifications are a courtesy,
+// so a failure is logged rather than failing whatever prompted it.
+func (cfg *apiConfig) notify(ctx context.Context, n notifications.Notification) {
+	if _, err := notifications.Record(ctx, cfg.db, n); err != nil {
+		slog.ErrorContext(ctx, "notification not recorded",
+			"request_id", middleware.GetRequestID(ctx),
+			"tenant_id", n.TenantID,
+			"kind", n.Kind,
+			"error", err,
+		)
+	}
+}
+
+func notificationToResponse(n database.GetNotificationRow) NotificationResponse {
+	resp := NotificationResponse{
+		ID:        n.ID,
+		Kind:      n.Kind,
+		Title:     n.Title,
+		Body:      n.Body,
+		Data:      n.Data,
+		Read:      n.ReadAt.Valid,
+		CreatedAt: n.CreatedAt,
+	}
+	if n.StoreID.Valid {
+		resp.StoreID = &n.StoreID.UUID
+	}
+	if n.ReadAt.Valid {
+		resp.ReadAt = &n.ReadAt.Time
+	}
+	return resp
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/orders"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/google/uuid"
+)
+
+type OrderEventResponse struct {
+	ID        uuid.UUID       `json:"id"`
+	OrderID   uuid.UUID       `json:"order_id"`
+	Kind      string          `json:"kind"`
+	Message   string          `json:"message"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	AuthorID  *uuid.UUID      `json:"author_id,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type OrderEventCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+}
+
+var orderEventCursorCodec = CursorCodec[OrderEventCursor]{
+	Validate: func(c OrderEventCursor) error {
+		if c.CreatedAt.IsZero() || c.ID == uuid.Nil {
+			return errors.New("invalid cursor: missing required fields")
+		}
+		return nil
+	},
+}
+
+// handlerTenantOrderEventsList returns an order's timeline, newest first.
+// ?kind=note,payment narrows it to some kinds of event.
+func (cfg *apiConfig) handlerTenantOrderEventsList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	order, ok := cfg.loadStoreOrder(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	kinds, err := orders.ParseEventKinds(r.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+
+	cursor, hasCursor, err := orderEventCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.db.GetOrderEventsPaginated(r.Context(), database.GetOrderEventsPaginatedParams{
+		OrderID:         order.ID,
+		Kinds:           kinds,
+		HasCursor:       hasCursor,
+		CursorCreatedAt: cursor.CreatedAt,
+		CursorID:        cursor.ID,
+		RowLimit:        int32(pageParams.Limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve order events", err)
+		return
+	}
+
+	hasMore := len(rows) > pageParams.Limit
+	if hasMore {
+		rows = rows[:pageParams.Limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = orderEventCursorCodec.Encode(OrderEventCursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]OrderEventResponse, 0, len(rows))
+	for _, e := range rows {
+		response = append(response, orderEventToResponse(e))
+	}
+
+	respondWithJSON(w, http.StatusOK, PageResponse[OrderEventResponse]{
+		Data: response,
+		Page: PageInfo{Limit: pageParams.Limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
+
+// handlerTenantOrderNoteCreate adds a staff note to an order's timeline
+func (cfg *apiConfig) handlerTenantOrderNoteCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	order, ok := cfg.loadStoreOrder(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		Note string `json:"note"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	note, err := orders.NormalizeNote(params.Note)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	event, err := recordOrderEvent(r.Context(), cfg.db, order, orders.EventNote, note, nil, user)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to add note", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant order note added",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"order_id", order.ID,
+		"event_id", event.ID,
+	)
+
+	respondWithJSON(w, http.StatusCreated, orderEventToResponse(event))
+}
+
+// recordOrderEvent appends an entry to an order's timeline. author is
+// uuid.Nil for events not made by staff, such as shopper payments.
+func recordOrderEvent(ctx context.Context, q *database.Queries, order database.Order, kind orders.EventKind, message string, data map[string]any, author uuid.UUID) (database.OrderEvent, error) {
+	raw := json.RawMessage(`{}`)
+	if data != nil {
+		b, err := json.Marshal(data)
+		if err != nil {
+			return database.OrderEvent{}, err
+		}
+		raw = b
+	}
+
+	return q.CreateOrderEvent(ctx, database.CreateOrderEventParams{
+		OrderID:  order.ID,
+		StoreID:  order.StoreID,
+		Kind:     string(kind),
+		Message:  message,
+		Data:     raw,
+		AuthorID: uuid.NullUUID{UUID: author, Valid: author != uuid.Nil},
+	})
+}
+
+func orderEventToResponse(e database.OrderEvent) OrderEventResponse {
+	resp := OrderEventResponse{
+		ID:        e.ID,
+		OrderID:   e.OrderID,
+		Kind:      e.Kind,
+		Message:   e.Message,
+		CreatedAt: e.CreatedAt,
+	}
+	if len(e.Data) > 0 && string(e.Data) != "{}" {
+		resp.Data = e.Data
+	}
+	if e.AuthorID.Valid {
+		resp.AuthorID = &e.AuthorID.UUID
+	}
+	return resp
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/orders"
+	"github.com/dfodeker/terminus/middleware"
+)
+
+// TenantOrderResponse is an order as merchants see it, with the internal
+// fields shoppers never get
+type TenantOrderResponse struct {
+	OrderResponse
+	Tags []string `json:"tags"`
+}
+
+type OrderTagCount struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+// handlerTenantOrdersList lists a store's orders, newest first, filtered by
+// tag and status
+func (cfg *apiConfig) handlerTenantOrdersList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	filter, err := orders.ParseFilter(r.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+
+	cursor, hasCursor, err := orderCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.db.ListFilteredOrders(r.Context(), database.ListFilteredOrdersParams{
+		StoreID:             storeID,
+		Tags:                filter.Tags,
+		Statuses:            filter.Statuses,
+		FinancialStatuses:   filter.FinancialStatuses,
+		FulfillmentStatuses: filter.FulfillmentStatuses,
+		HasCursor:           hasCursor,
+		CursorPlacedAt:      cursor.PlacedAt,
+		CursorID:            cursor.ID,
+		RowLimit:            int32(pageParams.Limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve orders", err)
+		return
+	}
+
+	hasMore := len(rows) > pageParams.Limit
+	if hasMore {
+		rows = rows[:pageParams.Limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = orderCursorCodec.Encode(OrderCursor{
+			PlacedAt: last.PlacedAt,
+			ID:       last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]TenantOrderResponse, 0, len(rows))
+	for _, order := range rows {
+		response = append(response, tenantOrderToResponse(order, nil))
+	}
+
+	respondWithJSON(w, http.StatusOK, PageResponse[TenantOrderResponse]{
+		Data: response,
+		Page: PageInfo{Limit: pageParams.Limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
+
+// handlerTenantOrderGet returns an order with its line items and every
+// fulfillment, cancelled ones included
+func (cfg *apiConfig) handlerTenantOrderGet(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	order, ok := cfg.loadStoreOrder(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	resp, err := cfg.tenantOrderDetail(r.Context(), order)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve order", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// tenantOrderDetail is an order with its line items and fulfillments, as
+// GET /orders/{orderID} returns it
+func (cfg *apiConfig) tenantOrderDetail(ctx context.Context, order database.Order) (TenantOrderResponse, error) {
+	lineItems, err := cfg.db.GetOrderLineItems(ctx, order.ID)
+	if err != nil {
+		return TenantOrderResponse{}, err
+	}
+
+	fulfillments, err := cfg.db.GetFulfillmentsByOrder(ctx, order.ID)
+	if err != nil {
+		return TenantOrderResponse{}, err
+	}
+
+	items, err := cfg.orderFulfillmentItems(ctx, order.ID)
+	if err != nil {
+		return TenantOrderResponse{}, err
+	}
+
+	resp := tenantOrderToResponse(order, lineItems)
+	for _, f := range fulfillments {
+		resp.Fulfillments = append(resp.Fulfillments, fulfillmentToResponse(f, items[f.ID]))
+	}
+	return resp, nil
+}
+
+// handlerTenantOrderTagsUpdate replaces an order's tags
+func (cfg *apiConfig) handlerTenantOrderTagsUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	order, ok := cfg.loadStoreOrder(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		Tags []string `json:"tags"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	tags, err := orders.NormalizeTags(params.Tags)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	order, err = cfg.db.UpdateOrderTags(r.Context(), database.UpdateOrderTagsParams{
+		ID:      order.ID,
+		StoreID: storeID,
+		Tags:    tags,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update order tags", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant order tags updated",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"order_id", order.ID,
+		"tag_count", len(tags),
+	)
+
+	respondWithJSON(w, http.StatusOK, tenantOrderToResponse(order, nil))
+}
+
+// handlerTenantOrderTagsList lists the tags in use across a store's orders,
+// most used first
+func (cfg *apiConfig) handlerTenantOrderTagsList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	rows, err := cfg.db.GetOrderTagsByStore(r.Context(), storeID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve order tags", err)
+		return
+	}
+
+	response := make([]OrderTagCount, 0, len(rows))
+	for _, row := range rows {
+		response = append(response, OrderTagCount{Tag: row.Tag, Count: row.Count})
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{"data": response})
+}
+
+func tenantOrderToResponse(order database.Order, lineItems []database.OrderLineItem) TenantOrderResponse {
+	resp := TenantOrderResponse{
+		OrderResponse: orderToResponse(order, lineItems),
+		Tags:          order.Tags,
+	}
+	if resp.Tags == nil {
+		resp.Tags = []string{}
+	}
+	return resp
+}
@@ -0,0 +1,525 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/pricelists"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxPriceListGroups bounds the customer groups one price list is assigned to
+const maxPriceListGroups = 50
+
+// PriceListResponse is a price list and the customer groups that get it
+type PriceListResponse struct {
+	ID               uuid.UUID   `json:"id"`
+	Name             string      `json:"name"`
+	CustomerGroupIDs []uuid.UUID `json:"customer_group_ids"`
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+}
+
+// PriceListEntryResponse is a variant's price on a price list, either a
+// fixed price in the store currency or a percentage off its base price
+type PriceListEntryResponse struct {
+	VariantID  uuid.UUID `json:"variant_id"`
+	PriceCents *int32    `json:"price_cents,omitempty"`
+	PercentOff *int32    `json:"percent_off,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// handlerTenantPriceListsList lists a store's price lists
+func (cfg *apiConfig) handlerTenantPriceListsList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	lists, err := cfg.db.ListPriceLists(r.Context(), storeID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve price lists", err)
+		return
+	}
+	assignments, err := cfg.db.ListPriceListGroupsByStore(r.Context(), storeID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve price lists", err)
+		return
+	}
+	groups := make(map[uuid.UUID][]uuid.UUID, len(lists))
+	for _, a := range assignments {
+		groups[a.PriceListID] = append(groups[a.PriceListID], a.GroupID)
+	}
+
+	response := make([]PriceListResponse, 0, len(lists))
+	for _, list := range lists {
+		response = append(response, priceListToResponse(list, groups[list.ID]))
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{"data": response})
+}
+
+// handlerTenantPriceListsCreate adds a price list to a store, assigned to
+// the given customer groups
+func (cfg *apiConfig) handlerTenantPriceListsCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	type parameters struct {
+		Name             string      `json:"name"`
+		CustomerGroupIDs []uuid.UUID `json:"customer_group_ids"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	params.Name = strings.TrimSpace(params.Name)
+	if !cfg.validPriceList(w, r, params.Name, params.CustomerGroupIDs) {
+		return
+	}
+
+	var list database.PriceList
+	err := cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		var err error
+		list, err = q.CreatePriceList(r.Context(), database.CreatePriceListParams{
+			Gid:      sql.NullInt64{Int64: int64(cfg.gidGen.Generate()), Valid: true},
+			TenantID: access.TenantID,
+			StoreID:  access.StoreID,
+			Name:     params.Name,
+		})
+		if err != nil {
+			return err
+		}
+		return assignPriceListGroups(r, q, list.ID, params.CustomerGroupIDs)
+	})
+	if service.UniqueViolation(err, "") {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeAlreadyExists, "A price list with this name already exists", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create price list", err)
+		return
+	}
+	resp := priceListToResponse(list, params.CustomerGroupIDs)
+	auditChange(r, auditGID(gid.EntityPriceList, list.Gid), nil, resp)
+
+	slog.InfoContext(r.Context(), "price list created",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"store_id", access.StoreID,
+		"price_list_id", list.ID,
+	)
+
+	respondWithJSON(w, http.StatusCreated, resp)
+}
+
+// handlerTenantPriceListGet returns a price list
+func (cfg *apiConfig) handlerTenantPriceListGet(w http.ResponseWriter, r *http.Request) {
+	list, groups, ok := cfg.loadPriceList(w, r)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, priceListToResponse(list, groups))
+}
+
+// handlerTenantPriceListUpdate renames a price list or, when
+// customer_group_ids is given, replaces the groups it is assigned to
+func (cfg *apiConfig) handlerTenantPriceListUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	existing, existingGroups, ok := cfg.loadPriceList(w, r)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		Name             *string      `json:"name"`
+		CustomerGroupIDs *[]uuid.UUID `json:"customer_group_ids"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	name := existing.Name
+	if params.Name != nil {
+		name = strings.TrimSpace(*params.Name)
+	}
+	groups := existingGroups
+	if params.CustomerGroupIDs != nil {
+		groups = *params.CustomerGroupIDs
+	}
+	if !cfg.validPriceList(w, r, name, groups) {
+		return
+	}
+
+	var list database.PriceList
+	err := cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		var err error
+		list, err = q.UpdatePriceList(r.Context(), database.UpdatePriceListParams{
+			ID:      existing.ID,
+			StoreID: access.StoreID,
+			Name:    name,
+		})
+		if err != nil || params.CustomerGroupIDs == nil {
+			return err
+		}
+		if err := q.ClearPriceListGroups(r.Context(), list.ID); err != nil {
+			return err
+		}
+		return assignPriceListGroups(r, q, list.ID, groups)
+	})
+	if service.UniqueViolation(err, "") {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeAlreadyExists, "A price list with this name already exists", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update price list", err)
+		return
+	}
+	resp := priceListToResponse(list, groups)
+	auditChange(r, auditGID(gid.EntityPriceList, list.Gid), priceListToResponse(existing, existingGroups), resp)
+
+	slog.InfoContext(r.Context(), "price list updated",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"price_list_id", list.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerTenantPriceListDelete deletes a price list and its entries
+func (cfg *apiConfig) handlerTenantPriceListDelete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	list, groups, ok := cfg.loadPriceList(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := cfg.db.DeletePriceList(r.Context(), database.DeletePriceListParams{
+		ID:      list.ID,
+		StoreID: access.StoreID,
+	}); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to delete price list", err)
+		return
+	}
+	auditChange(r, auditGID(gid.EntityPriceList, list.Gid), priceListToResponse(list, groups), nil)
+
+	slog.InfoContext(r.Context(), "price list deleted",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"price_list_id", list.ID,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerTenantPriceListEntriesList lists the variants a price list prices
+func (cfg *apiConfig) handlerTenantPriceListEntriesList(w http.ResponseWriter, r *http.Request) {
+	list, _, ok := cfg.loadPriceList(w, r)
+	if !ok {
+		return
+	}
+
+	entries, err := cfg.db.ListPriceListEntries(r.Context(), list.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve price list entries", err)
+		return
+	}
+
+	response := make([]PriceListEntryResponse, 0, len(entries))
+	for _, e := range entries {
+		response = append(response, priceListEntryToResponse(e))
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{"data": response})
+}
+
+// handlerTenantPriceListEntrySet sets the price of the {variantID} of the
+// route on a price list, as exactly one of price_cents or percent_off
+func (cfg *apiConfig) handlerTenantPriceListEntrySet(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	list, _, ok := cfg.loadPriceList(w, r)
+	if !ok {
+		return
+	}
+	variant, ok := cfg.priceListVariant(w, r)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		PriceCents *int32 `json:"price_cents"`
+		PercentOff *int32 `json:"percent_off"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	var v validate.Validator
+	if v.Check((params.PriceCents == nil) != (params.PercentOff == nil), "price_cents", validate.CodeInvalid, "set exactly one of price_cents or percent_off") {
+		if params.PriceCents != nil {
+			v.Between("price_cents", int64(*params.PriceCents), 0, maxPriceCents)
+		} else {
+			v.Between("percent_off", int64(*params.PercentOff), 1, 100)
+		}
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	var priceCents, percentOff sql.NullInt32
+	if params.PriceCents != nil {
+		priceCents = sql.NullInt32{Int32: *params.PriceCents, Valid: true}
+	} else {
+		percentOff = sql.NullInt32{Int32: *params.PercentOff, Valid: true}
+	}
+
+	// Kept for the audit log
+	var before any
+	existing, err := cfg.db.ListPriceListEntries(r.Context(), list.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to set price list entry", err)
+		return
+	}
+	if i := slices.IndexFunc(existing, func(e database.PriceListEntry) bool { return e.VariantID == variant.ID }); i >= 0 {
+		before = priceListEntryToResponse(existing[i])
+	}
+
+	entry, err := cfg.db.UpsertPriceListEntry(r.Context(), database.UpsertPriceListEntryParams{
+		PriceListID: list.ID,
+		VariantID:   variant.ID,
+		PriceCents:  priceCents,
+		PercentOff:  percentOff,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to set price list entry", err)
+		return
+	}
+	auditChange(r, auditGID(gid.EntityPriceList, list.Gid), before, priceListEntryToResponse(entry))
+
+	slog.InfoContext(r.Context(), "price list entry set",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"price_list_id", list.ID,
+		"variant_id", variant.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, priceListEntryToResponse(entry))
+}
+
+// handlerTenantPriceListEntryDelete takes a variant off a price list, so
+// the list's customers pay its base price again
+func (cfg *apiConfig) handlerTenantPriceListEntryDelete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	list, _, ok := cfg.loadPriceList(w, r)
+	if !ok {
+		return
+	}
+	variantID, err := uuid.Parse(chi.URLParam(r, "variantID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid variant ID format", err)
+		return
+	}
+
+	entry, err := cfg.db.DeletePriceListEntry(r.Context(), database.DeletePriceListEntryParams{
+		PriceListID: list.ID,
+		VariantID:   variantID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Price list entry not found", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to delete price list entry", err)
+		return
+	}
+	auditChange(r, auditGID(gid.EntityPriceList, list.Gid), priceListEntryToResponse(entry), nil)
+
+	slog.InfoContext(r.Context(), "price list entry deleted",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"price_list_id", list.ID,
+		"variant_id", variantID,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validPriceList checks a price list's name and that its customer groups
+// belong to the store. It has responded when it returns false.
+func (cfg *apiConfig) validPriceList(w http.ResponseWriter, r *http.Request, name string, groupIDs []uuid.UUID) bool {
+	var v validate.Validator
+	if v.Required("name", name) {
+		v.MaxLength("name", name, pricelists.MaxNameLength)
+	}
+	if v.Check(len(groupIDs) <= maxPriceListGroups, "customer_group_ids", validate.CodeOutOfRange, "has too many groups") {
+		seen := make(map[uuid.UUID]bool, len(groupIDs))
+		for _, id := range groupIDs {
+			if !v.Check(!seen[id], "customer_group_ids", validate.CodeInvalid, "includes a customer group more than once") {
+				break
+			}
+			seen[id] = true
+		}
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return false
+	}
+	if len(groupIDs) == 0 {
+		return true
+	}
+
+	found, err := cfg.db.GetCustomerGroupIDsInStore(r.Context(), database.GetCustomerGroupIDsInStoreParams{
+		StoreID: tenantAccessFrom(r).StoreID,
+		Ids:     groupIDs,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to verify customer groups", err)
+		return false
+	}
+	for _, id := range groupIDs {
+		if !slices.Contains(found, id) {
+			v.Fail("customer_group_ids", validate.CodeInvalid, "includes a customer group that does not exist")
+			respondWithValidationError(w, v.Err())
+			return false
+		}
+	}
+	return true
+}
+
+// assignPriceListGroups assigns a price list to customer groups
+func assignPriceListGroups(r *http.Request, q *database.Queries, listID uuid.UUID, groupIDs []uuid.UUID) error {
+	for _, groupID := range groupIDs {
+		if err := q.AddPriceListGroup(r.Context(), database.AddPriceListGroupParams{
+			PriceListID: listID,
+			GroupID:     groupID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadPriceList loads the {priceListID} of the route from the store the
+// caller was let into, with the customer groups it is assigned to. It has
+// responded when it returns false.
+func (cfg *apiConfig) loadPriceList(w http.ResponseWriter, r *http.Request) (database.PriceList, []uuid.UUID, bool) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	listID, err := uuid.Parse(chi.URLParam(r, "priceListID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid price list ID format", err)
+		return database.PriceList{}, nil, false
+	}
+
+	list, err := cfg.db.GetPriceListByID(r.Context(), database.GetPriceListByIDParams{
+		ID:      listID,
+		StoreID: storeID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Price list not found", nil)
+		return database.PriceList{}, nil, false
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve price list", err)
+		return database.PriceList{}, nil, false
+	}
+
+	assignments, err := cfg.db.ListPriceListGroupsByStore(r.Context(), storeID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve price list", err)
+		return database.PriceList{}, nil, false
+	}
+	var groups []uuid.UUID
+	for _, a := range assignments {
+		if a.PriceListID == list.ID {
+			groups = append(groups, a.GroupID)
+		}
+	}
+	return list, groups, true
+}
+
+// priceListVariant loads the {variantID} of the route from the store the
+// caller was let into. It has responded when it returns false.
+func (cfg *apiConfig) priceListVariant(w http.ResponseWriter, r *http.Request) (database.ProductVariant, bool) {
+	variantID, err := uuid.Parse(chi.URLParam(r, "variantID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid variant ID format", err)
+		return database.ProductVariant{}, false
+	}
+
+	variant, err := cfg.db.GetProductVariantByID(r.Context(), variantID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && (variant.StoreID != tenantAccessFrom(r).StoreID || variant.DeletedAt.Valid)) {
+		respondWithError(w, http.StatusNotFound, "Variant not found", nil)
+		return database.ProductVariant{}, false
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve variant", err)
+		return database.ProductVariant{}, false
+	}
+	return variant, true
+}
+
+func priceListToResponse(list database.PriceList, groups []uuid.UUID) PriceListResponse {
+	if groups == nil {
+		groups = []uuid.UUID{}
+	}
+	return PriceListResponse{
+		ID:               list.ID,
+		Name:             list.Name,
+		CustomerGroupIDs: groups,
+		CreatedAt:        list.CreatedAt,
+		UpdatedAt:        list.UpdatedAt,
+	}
+}
+
+func priceListEntryToResponse(e database.PriceListEntry) PriceListEntryResponse {
+	resp := PriceListEntryResponse{
+		VariantID: e.VariantID,
+		UpdatedAt: e.UpdatedAt,
+	}
+	if e.PriceCents.Valid {
+		resp.PriceCents = &e.PriceCents.Int32
+	}
+	if e.PercentOff.Valid {
+		resp.PercentOff = &e.PercentOff.Int32
+	}
+	return resp
+}
@@ -0,0 +1,447 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/catalog"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/feeds"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// ProductFeedResponse is a marketplace product feed. URL is the stable
+// address to give the marketplace; it changes only when the token is
+// rotated.
+type ProductFeedResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	ChannelID      uuid.UUID  `json:"channel_id"`
+	Name           string     `json:"name"`
+	Format         string     `json:"format"`
+	LinkTemplate   string     `json:"link_template"`
+	RefreshMinutes int32      `json:"refresh_minutes"`
+	URL            string     `json:"url"`
+	ItemCount      *int32     `json:"item_count,omitempty"`
+	SizeBytes      *int64     `json:"size_bytes,omitempty"`
+	GeneratedAt    *time.Time `json:"generated_at,omitempty"`
+	NextRunAt      time.Time  `json:"next_run_at"`
+	LastError      *string    `json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// handlerTenantProductFeedsList lists a store's product feeds
+func (cfg *apiConfig) handlerTenantProductFeedsList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	rows, err := cfg.db.ListProductFeeds(r.Context(), storeID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product feeds", err)
+		return
+	}
+
+	response := make([]ProductFeedResponse, 0, len(rows))
+	for _, feed := range rows {
+		response = append(response, cfg.productFeedToResponse(feed))
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{"data": response})
+}
+
+// handlerTenantProductFeedsCreate adds a feed of the products published to
+// a marketplace feed channel. Without a link_template, product links go to
+// the store's primary custom domain. The first file is generated on the
+// next worker pass.
+func (cfg *apiConfig) handlerTenantProductFeedsCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	type parameters struct {
+		ChannelID      uuid.UUID `json:"channel_id"`
+		Name           string    `json:"name"`
+		Format         string    `json:"format"`
+		LinkTemplate   string    `json:"link_template"`
+		RefreshMinutes *int32    `json:"refresh_minutes"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+	params.Name = strings.TrimSpace(params.Name)
+	params.Format = strings.ToLower(strings.TrimSpace(params.Format))
+	params.LinkTemplate = strings.TrimSpace(params.LinkTemplate)
+	refreshMinutes := int32(feeds.DefaultRefreshMinutes)
+	if params.RefreshMinutes != nil {
+		refreshMinutes = *params.RefreshMinutes
+	}
+
+	if params.LinkTemplate == "" {
+		template, err := cfg.defaultFeedLinkTemplate(r.Context(), access.StoreID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve store domains", err)
+			return
+		}
+		params.LinkTemplate = template
+	}
+
+	var v validate.Validator
+	if v.Check(params.ChannelID != uuid.Nil, "channel_id", validate.CodeRequired, "is required") {
+		channel, err := cfg.db.GetSalesChannelByID(r.Context(), database.GetSalesChannelByIDParams{
+			ID:      params.ChannelID,
+			StoreID: access.StoreID,
+		})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve sales channel", err)
+			return
+		}
+		if v.Check(err == nil, "channel_id", validate.CodeInvalid, "does not exist") {
+			v.Check(channel.Kind == string(catalog.ChannelMarketplaceFeed), "channel_id", validate.CodeInvalid, "must be a marketplace feed channel")
+		}
+	}
+	if v.Required("name", params.Name) {
+		v.MaxLength("name", params.Name, feeds.MaxNameLength)
+	}
+	v.OneOf("format", params.Format, feeds.FormatGoogleMerchantXML, feeds.FormatFacebookCSV)
+	validateFeedLinkTemplate(&v, params.LinkTemplate, true)
+	v.Between("refresh_minutes", int64(refreshMinutes), feeds.MinRefreshMinutes, feeds.MaxRefreshMinutes)
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	token, err := auth.MakeOneTimeToken()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to generate feed token", err)
+		return
+	}
+
+	feed, err := cfg.db.CreateProductFeed(r.Context(), database.CreateProductFeedParams{
+		Gid:            sql.NullInt64{Int64: int64(cfg.gidGen.Generate()), Valid: true},
+		TenantID:       access.TenantID,
+		StoreID:        access.StoreID,
+		ChannelID:      params.ChannelID,
+		Name:           params.Name,
+		Format:         params.Format,
+		LinkTemplate:   params.LinkTemplate,
+		RefreshMinutes: refreshMinutes,
+		Token:          token,
+	})
+	if service.UniqueViolation(err, "") {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeAlreadyExists, "A product feed with this name already exists", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create product feed", err)
+		return
+	}
+	resp := cfg.productFeedToResponse(feed)
+	auditChange(r, auditGID(gid.EntityProductFeed, feed.Gid), nil, productFeedAudit(resp))
+
+	slog.InfoContext(r.Context(), "product feed created",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"store_id", access.StoreID,
+		"feed_id", feed.ID,
+		"format", feed.Format,
+	)
+
+	respondWithJSON(w, http.StatusCreated, resp)
+}
+
+// handlerTenantProductFeedGet returns a product feed
+func (cfg *apiConfig) handlerTenantProductFeedGet(w http.ResponseWriter, r *http.Request) {
+	feed, ok := cfg.loadProductFeed(w, r)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, cfg.productFeedToResponse(feed))
+}
+
+// handlerTenantProductFeedUpdate renames a feed or changes its product
+// links or schedule. The new settings apply from the next run.
+func (cfg *apiConfig) handlerTenantProductFeedUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	existing, ok := cfg.loadProductFeed(w, r)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		Name           *string `json:"name"`
+		LinkTemplate   *string `json:"link_template"`
+		RefreshMinutes *int32  `json:"refresh_minutes"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	name, linkTemplate, refreshMinutes := existing.Name, existing.LinkTemplate, existing.RefreshMinutes
+	if params.Name != nil {
+		name = strings.TrimSpace(*params.Name)
+	}
+	if params.LinkTemplate != nil {
+		linkTemplate = strings.TrimSpace(*params.LinkTemplate)
+	}
+	if params.RefreshMinutes != nil {
+		refreshMinutes = *params.RefreshMinutes
+	}
+
+	var v validate.Validator
+	if v.Required("name", name) {
+		v.MaxLength("name", name, feeds.MaxNameLength)
+	}
+	validateFeedLinkTemplate(&v, linkTemplate, false)
+	v.Between("refresh_minutes", int64(refreshMinutes), feeds.MinRefreshMinutes, feeds.MaxRefreshMinutes)
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	feed, err := cfg.db.UpdateProductFeed(r.Context(), database.UpdateProductFeedParams{
+		ID:             existing.ID,
+		StoreID:        access.StoreID,
+		Name:           name,
+		LinkTemplate:   linkTemplate,
+		RefreshMinutes: refreshMinutes,
+	})
+	if service.UniqueViolation(err, "") {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeAlreadyExists, "A product feed with this name already exists", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update product feed", err)
+		return
+	}
+	resp := cfg.productFeedToResponse(feed)
+	auditChange(r, auditGID(gid.EntityProductFeed, feed.Gid), productFeedAudit(cfg.productFeedToResponse(existing)), productFeedAudit(resp))
+
+	slog.InfoContext(r.Context(), "product feed updated",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"feed_id", feed.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerTenantProductFeedDelete deletes a product feed and its file. The
+// feed URL stops working at once.
+func (cfg *apiConfig) handlerTenantProductFeedDelete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	feed, ok := cfg.loadProductFeed(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := cfg.db.DeleteProductFeed(r.Context(), database.DeleteProductFeedParams{
+		ID:      feed.ID,
+		StoreID: access.StoreID,
+	}); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to delete product feed", err)
+		return
+	}
+	if feed.StorageKey.Valid {
+		// The row is gone, so a file left behind is only wasted space
+		if err := cfg.storage.Delete(r.Context(), feed.StorageKey.String); err != nil {
+			slog.WarnContext(r.Context(), "unable to delete product feed file",
+				"request_id", reqID,
+				"feed_id", feed.ID,
+				"error", err,
+			)
+		}
+	}
+	auditChange(r, auditGID(gid.EntityProductFeed, feed.Gid), productFeedAudit(cfg.productFeedToResponse(feed)), nil)
+
+	slog.InfoContext(r.Context(), "product feed deleted",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"feed_id", feed.ID,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerTenantProductFeedRefresh regenerates a feed on the next worker
+// pass instead of waiting for its schedule
+func (cfg *apiConfig) handlerTenantProductFeedRefresh(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	existing, ok := cfg.loadProductFeed(w, r)
+	if !ok {
+		return
+	}
+
+	feed, err := cfg.db.RefreshProductFeed(r.Context(), database.RefreshProductFeedParams{
+		ID:      existing.ID,
+		StoreID: access.StoreID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to schedule product feed", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "product feed refresh requested",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"feed_id", feed.ID,
+	)
+
+	respondWithJSON(w, http.StatusAccepted, cfg.productFeedToResponse(feed))
+}
+
+// handlerTenantProductFeedRotateToken gives a feed a new URL. The old one
+// stops working at once, so the marketplace must be given the new one.
+func (cfg *apiConfig) handlerTenantProductFeedRotateToken(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	existing, ok := cfg.loadProductFeed(w, r)
+	if !ok {
+		return
+	}
+
+	token, err := auth.MakeOneTimeToken()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to generate feed token", err)
+		return
+	}
+
+	feed, err := cfg.db.RotateProductFeedToken(r.Context(), database.RotateProductFeedTokenParams{
+		ID:      existing.ID,
+		StoreID: access.StoreID,
+		Token:   token,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to rotate feed token", err)
+		return
+	}
+	auditChange(r, auditGID(gid.EntityProductFeed, feed.Gid), nil, map[string]any{"token_rotated": true})
+
+	slog.InfoContext(r.Context(), "product feed token rotated",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"feed_id", feed.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, cfg.productFeedToResponse(feed))
+}
+
+// defaultFeedLinkTemplate links products to the store's primary verified
+// custom domain, or returns "" when it has none
+func (cfg *apiConfig) defaultFeedLinkTemplate(ctx context.Context, storeID uuid.UUID) (string, error) {
+	domains, err := cfg.db.GetCustomDomainsByStoreID(ctx, storeID)
+	if err != nil {
+		return "", err
+	}
+	for _, d := range domains {
+		if d.IsPrimary && d.VerificationStatus == "verified" {
+			return "https://" + d.Domain + "/products/" + feeds.HandlePlaceholder, nil
+		}
+	}
+	return "", nil
+}
+
+// validateFeedLinkTemplate checks a product link template. On create an
+// empty template means the store has no domain to default to.
+func validateFeedLinkTemplate(v *validate.Validator, template string, creating bool) {
+	if template == "" && creating {
+		v.Fail("link_template", validate.CodeRequired, "is required when the store has no primary custom domain")
+		return
+	}
+	if !v.MaxLength("link_template", template, feeds.MaxLinkTemplateLength) {
+		return
+	}
+	if err := feeds.ValidateLinkTemplate(template); err != nil {
+		v.Fail("link_template", validate.CodeInvalid, strings.TrimPrefix(err.Error(), feeds.ErrInvalidLinkTemplate.Error()+": "))
+	}
+}
+
+// loadProductFeed loads the {feedID} of the route from the store the
+// caller was let into. It has responded when it returns false.
+func (cfg *apiConfig) loadProductFeed(w http.ResponseWriter, r *http.Request) (database.ProductFeed, bool) {
+	feedID, err := uuid.Parse(chi.URLParam(r, "feedID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid product feed ID format", err)
+		return database.ProductFeed{}, false
+	}
+
+	feed, err := cfg.db.GetProductFeedByID(r.Context(), database.GetProductFeedByIDParams{
+		ID:      feedID,
+		StoreID: tenantAccessFrom(r).StoreID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Product feed not found", nil)
+		return database.ProductFeed{}, false
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product feed", err)
+		return database.ProductFeed{}, false
+	}
+	return feed, true
+}
+
+func (cfg *apiConfig) productFeedToResponse(feed database.ProductFeed) ProductFeedResponse {
+	resp := ProductFeedResponse{
+		ID:             feed.ID,
+		ChannelID:      feed.ChannelID,
+		Name:           feed.Name,
+		Format:         feed.Format,
+		LinkTemplate:   feed.LinkTemplate,
+		RefreshMinutes: feed.RefreshMinutes,
+		URL:            cfg.config.APIURL + "/feeds/" + feed.Token,
+		NextRunAt:      feed.NextRunAt,
+		CreatedAt:      feed.CreatedAt,
+		UpdatedAt:      feed.UpdatedAt,
+	}
+	if feed.ItemCount.Valid {
+		resp.ItemCount = &feed.ItemCount.Int32
+	}
+	if feed.SizeBytes.Valid {
+		resp.SizeBytes = &feed.SizeBytes.Int64
+	}
+	if feed.GeneratedAt.Valid {
+		resp.GeneratedAt = &feed.GeneratedAt.Time
+	}
+	if feed.LastError.Valid {
+		resp.LastError = &feed.LastError.String
+	}
+	return resp
+}
+
+// productFeedAudit keeps the feed URL, and the token in it, out of the
+// audit log
+func productFeedAudit(resp ProductFeedResponse) ProductFeedResponse {
+	resp.URL = ""
+	return resp
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/productimport"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type ProductImportResponse struct {
+	ID         uuid.UUID           `json:"id"`
+	StoreID    uuid.UUID           `json:"store_id"`
+	Filename   *string             `json:"filename,omitempty"`
+	Status     string              `json:"status"`
+	Counts     ProductImportCounts `json:"counts"`
+	Error      *string             `json:"error,omitempty"`
+	CreatedAt  time.Time           `json:"created_at"`
+	StartedAt  *time.Time          `json:"started_at,omitempty"`
+	FinishedAt *time.Time          `json:"finished_at,omitempty"`
+}
+
+// ProductImportCounts tallies rows by outcome; Total less the others is
+// what is still to be processed
+type ProductImportCounts struct {
+	Total   int32 `json:"total"`
+	Created int32 `json:"created"`
+	Updated int32 `json:"updated"`
+	Failed  int32 `json:"failed"`
+}
+
+type ProductImportRowResponse struct {
+	Line      int32                `json:"line"`
+	Status    string               `json:"status"`
+	Handle    *string              `json:"handle,omitempty"`
+	SKU       *string              `json:"sku,omitempty"`
+	ProductID *uuid.UUID           `json:"product_id,omitempty"`
+	Errors    []problem.FieldError `json:"errors"`
+}
+
+// ProductImportReportResponse is an import with a page of its rows
+type ProductImportReportResponse struct {
+	Import ProductImportResponse      `json:"import"`
+	Rows   []ProductImportRowResponse `json:"rows"`
+	Page   PageInfo                   `json:"page"`
+}
+
+type ProductImportRowCursor struct {
+	Line int32 `json:"line"`
+}
+
+var productImportRowCursorCodec = CursorCodec[ProductImportRowCursor]{
+	Validate: func(c ProductImportRowCursor) error {
+		if c.Line < 1 {
+			return errors.New("invalid cursor: missing required fields")
+		}
+		return nil
+	},
+}
+
+// handlerTenantProductImportCreate accepts a product CSV, either as the
+// request body or as the file field of a multipart form, and queues it for
+// the worker. The file is checked up front, so a response of 202 means
+// every row will be tried.
+func (cfg *apiConfig) handlerTenantProductImportCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	r.Body = http.MaxBytesReader(w, r.Body, productimport.MaxBytes)
+	data, filename, err := readImportFile(r)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondWithError(w, http.StatusRequestEntityTooLarge, "Import files must be 10MB or smaller", nil)
+			return
+		}
+		respondWithError(w, http.StatusBadRequest, "Please upload a CSV file", err)
+		return
+	}
+
+	rows, err := productimport.Parse(bytes.NewReader(data))
+	if err != nil {
+		respondWithError(w, http.StatusUnprocessableEntity, err.Error(), nil)
+		return
+	}
+
+	var created database.CreateProductImportRow
+	err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		var err error
+		created, err = q.CreateProductImport(r.Context(), database.CreateProductImportParams{
+			StoreID:   storeID,
+			CreatedBy: uuid.NullUUID{UUID: user, Valid: true},
+			Filename:  sql.NullString{String: filename, Valid: filename != ""},
+			Data:      data,
+			TotalRows: int32(len(rows)),
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = cfg.jobs.EnqueueTx(r.Context(), q, productimport.Args{ImportID: created.ID})
+		return err
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create import", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant product import queued",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"import_id", created.ID,
+		"rows", len(rows),
+	)
+
+	respondWithJSON(w, http.StatusAccepted, productImportToResponse(database.GetProductImportRow(created), database.CountProductImportRowsRow{}))
+}
+
+// readImportFile returns the uploaded file and its name, if it came with
+// one
+func readImportFile(r *http.Request) ([]byte, string, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		data, err := io.ReadAll(r.Body)
+		return data, "", err
+	}
+
+	if err := r.ParseMultipartForm(productimport.MaxBytes); err != nil {
+		return nil, "", err
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return nil, "", fmt.Errorf("file field: %w", err)
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	return data, header.Filename, err
+}
+
+// handlerTenantProductImportGet reports an import's progress and a page of
+// its row results in file order. ?status=failed lists only the rows that
+// need fixing.
+func (cfg *apiConfig) handlerTenantProductImportGet(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	importID, err := uuid.Parse(chi.URLParam(r, "importID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid import ID", err)
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", productimport.RowCreated, productimport.RowUpdated, productimport.RowFailed:
+	default:
+		respondWithError(w, http.StatusBadRequest, "status must be created, updated or failed", nil)
+		return
+	}
+
+	pageParams, err := ParsePageParams(r, 100, 500)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+
+	cursor, _, err := productImportRowCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	imp, err := cfg.db.GetProductImport(r.Context(), database.GetProductImportParams{
+		ID:      importID,
+		StoreID: storeID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Import not found", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve import", err)
+		return
+	}
+
+	counts, err := cfg.db.CountProductImportRows(r.Context(), imp.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve import", err)
+		return
+	}
+
+	rows, err := cfg.db.ListProductImportRows(r.Context(), database.ListProductImportRowsParams{
+		ImportID: imp.ID,
+		Status:   sql.NullString{String: status, Valid: status != ""},
+		AfterRow: cursor.Line,
+		RowLimit: int32(pageParams.Limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve import rows", err)
+		return
+	}
+
+	hasMore := len(rows) > pageParams.Limit
+	if hasMore {
+		rows = rows[:pageParams.Limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		nextCursor, err = productImportRowCursorCodec.Encode(ProductImportRowCursor{Line: rows[len(rows)-1].RowNumber})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	report := make([]ProductImportRowResponse, 0, len(rows))
+	for _, row := range rows {
+		report = append(report, productImportRowToResponse(row))
+	}
+
+	respondWithJSON(w, http.StatusOK, ProductImportReportResponse{
+		Import: productImportToResponse(imp, counts),
+		Rows:   report,
+		Page:   PageInfo{Limit: pageParams.Limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
+
+func productImportToResponse(imp database.GetProductImportRow, counts database.CountProductImportRowsRow) ProductImportResponse {
+	resp := ProductImportResponse{
+		ID:      imp.ID,
+		StoreID: imp.StoreID,
+		Status:  imp.Status,
+		Counts: ProductImportCounts{
+			Total:   imp.TotalRows,
+			Created: counts.Created,
+			Updated: counts.Updated,
+			Failed:  counts.Failed,
+		},
+		CreatedAt: imp.CreatedAt,
+	}
+	if imp.Filename.Valid {
+		resp.Filename = &imp.Filename.String
+	}
+	if imp.Error.Valid {
+		resp.Error = &imp.Error.String
+	}
+	if imp.StartedAt.Valid {
+		resp.StartedAt = &imp.StartedAt.Time
+	}
+	if imp.FinishedAt.Valid {
+		resp.FinishedAt = &imp.FinishedAt.Time
+	}
+	return resp
+}
+
+func productImportRowToResponse(row database.ProductImportRow) ProductImportRowResponse {
+	resp := ProductImportRowResponse{
+		Line:   row.RowNumber,
+		Status: row.Status,
+		Errors: []problem.FieldError{},
+	}
+	if row.Handle.Valid {
+		resp.Handle = &row.Handle.String
+	}
+	if row.Sku.Valid {
+		resp.SKU = &row.Sku.String
+	}
+	if row.ProductID.Valid {
+		resp.ProductID = &row.ProductID.UUID
+	}
+	// Written by the worker from the same type, so a decode failure would
+	// only lose the detail, not the row's status
+	_ = json.Unmarshal(row.Errors, &resp.Errors)
+	return resp
+}
@@ -0,0 +1,550 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	mediapkg "github.com/dfodeker/terminus/internal/media"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/storage"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const mediaUploadURLTTL = 15 * time.Minute
+
+type ProductMediaResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	ProductID   uuid.UUID  `json:"product_id"`
+	VariantID   *uuid.UUID `json:"variant_id,omitempty"`
+	URL         string     `json:"url"`
+	ContentType string     `json:"content_type"`
+	SizeBytes   *int64     `json:"size_bytes,omitempty"`
+	Width       *int32     `json:"width,omitempty"`
+	Height      *int32     `json:"height,omitempty"`
+	AltText     *string    `json:"alt_text,omitempty"`
+	Position    int32      `json:"position"`
+	Status      string     `json:"status"`
+	// Thumbnails are smaller renditions, narrowest first. Empty until the
+	// thumbnail job has run, and for formats that cannot be resized.
+	Thumbnails []MediaThumbnail `json:"thumbnails"`
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
+
+type MediaThumbnail struct {
+	Width  int32  `json:"width"`
+	Height *int32 `json:"height,omitempty"`
+	URL    string `json:"url"`
+}
+
+// handlerTenantProductMediaCreateUpload registers a pending image and returns
+// a presigned URL the client uploads the file to
+func (cfg *apiConfig) handlerTenantProductMediaCreateUpload(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	product, ok := cfg.loadStoreProduct(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		ContentType string     `json:"content_type"`
+		SizeBytes   int64      `json:"size_bytes"`
+		VariantID   *uuid.UUID `json:"variant_id"`
+		AltText     *string    `json:"alt_text"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	if _, ok := mediapkg.Extensions[params.ContentType]; !ok {
+		respondWithError(w, http.StatusBadRequest, "Content type must be image/jpeg, image/png, image/webp or image/gif", nil)
+		return
+	}
+
+	if params.SizeBytes < 0 || params.SizeBytes > mediapkg.MaxBytes {
+		respondWithError(w, http.StatusRequestEntityTooLarge, "Images must be 20MB or smaller", nil)
+		return
+	}
+
+	variantID, ok := cfg.mediaVariantID(w, r, product.ID, params.VariantID)
+	if !ok {
+		return
+	}
+
+	mediaID := uuid.New()
+	key := mediapkg.OriginalKey(storeID, product.ID, mediaID, params.ContentType)
+
+	upload, err := cfg.storage.PresignPut(r.Context(), key, params.ContentType, mediaUploadURLTTL)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create upload URL", err)
+		return
+	}
+
+	media, err := cfg.db.CreateProductMedia(r.Context(), database.CreateProductMediaParams{
+		ID:          mediaID,
+		Gid:         sql.NullInt64{Int64: int64(cfg.gidGen.Generate()), Valid: true},
+		TenantID:    tenantID,
+		StoreID:     storeID,
+		ProductID:   product.ID,
+		VariantID:   variantID,
+		StorageKey:  key,
+		ContentType: params.ContentType,
+		AltText:     nullStringFromPtr(params.AltText),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create media", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant product media upload created",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"product_id", product.ID,
+		"media_id", media.ID,
+	)
+
+	respondWithJSON(w, http.StatusCreated, map[string]any{
+		"media":  cfg.productMediaToResponse(media),
+		"upload": upload,
+	})
+}
+
+// handlerTenantProductMediaComplete confirms a presigned upload landed and marks the media ready
+func (cfg *apiConfig) handlerTenantProductMediaComplete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	product, ok := cfg.loadStoreProduct(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	media, ok := cfg.loadProductMedia(w, r, product.ID)
+	if !ok {
+		return
+	}
+
+	info, err := cfg.storage.Stat(r.Context(), media.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			respondWithError(w, http.StatusConflict, "The file has not been uploaded yet", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to verify upload", err)
+		return
+	}
+
+	if info.Size > mediapkg.MaxBytes {
+		if err := cfg.storage.Delete(r.Context(), media.StorageKey); err != nil {
+			slog.WarnContext(r.Context(), "oversized media cleanup failed",
+				"request_id", reqID,
+				"media_id", media.ID,
+				"error", err,
+			)
+		}
+		respondWithError(w, http.StatusRequestEntityTooLarge, "Images must be 20MB or smaller", nil)
+		return
+	}
+
+	err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		media, err = q.MarkProductMediaReady(r.Context(), database.MarkProductMediaReadyParams{
+			ID:        media.ID,
+			ProductID: product.ID,
+			SizeBytes: sql.NullInt64{Int64: info.Size, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		// Thumbnails are rendered in the background; until then clients fall back to the original
+		if mediapkg.CanThumbnail(media.ContentType) {
+			_, err = cfg.jobs.EnqueueTx(r.Context(), q, mediapkg.ThumbnailArgs{MediaID: media.ID})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update media", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant product media upload completed",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"product_id", product.ID,
+		"media_id", media.ID,
+		"size_bytes", info.Size,
+	)
+
+	respondWithJSON(w, http.StatusOK, cfg.productMediaToResponse(media))
+}
+
+// handlerTenantProductMediaList lists all of a product's media, including pending uploads
+func (cfg *apiConfig) handlerTenantProductMediaList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	product, ok := cfg.loadStoreProduct(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	rows, err := cfg.db.GetProductMediaByProduct(r.Context(), product.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve media", err)
+		return
+	}
+
+	response := make([]ProductMediaResponse, 0, len(rows))
+	for _, media := range rows {
+		response = append(response, cfg.productMediaToResponse(media))
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{
+		"data": response,
+	})
+}
+
+// handlerTenantProductMediaUpdate edits alt text or the variant an image is pinned to
+func (cfg *apiConfig) handlerTenantProductMediaUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	product, ok := cfg.loadStoreProduct(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	existing, ok := cfg.loadProductMedia(w, r, product.ID)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		AltText *string `json:"alt_text"`
+		// VariantID pins the image to a variant; uuid.Nil unpins it
+		VariantID *uuid.UUID `json:"variant_id"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	altText := existing.AltText
+	if params.AltText != nil {
+		altText = sql.NullString{String: *params.AltText, Valid: true}
+	}
+
+	variantID := existing.VariantID
+	if params.VariantID != nil {
+		if *params.VariantID == uuid.Nil {
+			variantID = uuid.NullUUID{}
+		} else {
+			variantID, ok = cfg.mediaVariantID(w, r, product.ID, params.VariantID)
+			if !ok {
+				return
+			}
+		}
+	}
+
+	media, err := cfg.db.UpdateProductMedia(r.Context(), database.UpdateProductMediaParams{
+		ID:        existing.ID,
+		ProductID: product.ID,
+		AltText:   altText,
+		VariantID: variantID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update media", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant product media updated successfully",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"product_id", product.ID,
+		"media_id", media.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, cfg.productMediaToResponse(media))
+}
+
+// handlerTenantProductMediaReorder sets the display order of a product's media.
+// Every media ID of the product must be listed exactly once.
+func (cfg *apiConfig) handlerTenantProductMediaReorder(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	product, ok := cfg.loadStoreProduct(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		MediaIDs []uuid.UUID `json:"media_ids"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		existing, err := q.GetProductMediaByProduct(r.Context(), product.ID)
+		if err != nil {
+			return err
+		}
+
+		remaining := make(map[uuid.UUID]bool, len(existing))
+		for _, media := range existing {
+			remaining[media.ID] = true
+		}
+		for _, id := range params.MediaIDs {
+			if !remaining[id] {
+				return service.Invalid("Media " + id.String() + " is unknown or listed twice")
+			}
+			delete(remaining, id)
+		}
+		if len(remaining) > 0 {
+			return service.Invalid("Every media item of the product must be listed")
+		}
+
+		for i, id := range params.MediaIDs {
+			err = q.SetProductMediaPosition(r.Context(), database.SetProductMediaPositionParams{
+				ID:        id,
+				ProductID: product.ID,
+				Position:  int32(i + 1),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to reorder media")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant product media reordered",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"product_id", product.ID,
+		"media_count", len(params.MediaIDs),
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerTenantProductMediaDelete removes an image and its stored file
+func (cfg *apiConfig) handlerTenantProductMediaDelete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	product, ok := cfg.loadStoreProduct(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	media, ok := cfg.loadProductMedia(w, r, product.ID)
+	if !ok {
+		return
+	}
+
+	err := cfg.db.DeleteProductMedia(r.Context(), database.DeleteProductMediaParams{
+		ID:        media.ID,
+		ProductID: product.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to delete media", err)
+		return
+	}
+
+	// The row is gone either way; an orphaned file is only wasted space
+	keys := []string{media.StorageKey}
+	for _, width := range media.ThumbnailWidths {
+		keys = append(keys, mediapkg.ThumbnailKey(media.StorageKey, media.ContentType, int(width)))
+	}
+	for _, key := range keys {
+		if err := cfg.storage.Delete(r.Context(), key); err != nil {
+			slog.WarnContext(r.Context(), "product media file delete failed",
+				"request_id", reqID,
+				"media_id", media.ID,
+				"storage_key", key,
+				"error", err,
+			)
+		}
+	}
+
+	slog.InfoContext(r.Context(), "tenant product media deleted successfully",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"product_id", product.ID,
+		"media_id", media.ID,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// productMedia returns the ready media of each product, keyed by product ID.
+// Every requested product gets a (possibly empty) slice.
+func (cfg *apiConfig) productMedia(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID][]ProductMediaResponse, error) {
+	media := make(map[uuid.UUID][]ProductMediaResponse, len(productIDs))
+	if len(productIDs) == 0 {
+		return media, nil
+	}
+
+	rows, err := cfg.db.GetReadyMediaByProducts(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range productIDs {
+		media[id] = []ProductMediaResponse{}
+	}
+	for _, row := range rows {
+		media[row.ProductID] = append(media[row.ProductID], cfg.productMediaToResponse(row))
+	}
+	return media, nil
+}
+
+// mediaVariantID checks an optional variant belongs to the product
+func (cfg *apiConfig) mediaVariantID(w http.ResponseWriter, r *http.Request, productID uuid.UUID, variantID *uuid.UUID) (uuid.NullUUID, bool) {
+	if variantID == nil {
+		return uuid.NullUUID{}, true
+	}
+
+	variant, err := cfg.db.GetProductVariantByID(r.Context(), *variantID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusInternalServerError, "Unable to verify variant", err)
+		return uuid.NullUUID{}, false
+	}
+	if err != nil || variant.ProductID != productID {
+		respondWithError(w, http.StatusBadRequest, "Variant not found on this product", nil)
+		return uuid.NullUUID{}, false
+	}
+	return uuid.NullUUID{UUID: variant.ID, Valid: true}, true
+}
+
+func (cfg *apiConfig) loadStoreProduct(w http.ResponseWriter, r *http.Request, storeID uuid.UUID) (database.Product, bool) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid product ID format", err)
+		return database.Product{}, false
+	}
+
+	product, err := cfg.db.GetProductByID(r.Context(), database.GetProductByIDParams{
+		ID:      productID,
+		StoreID: storeID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Product not found", nil)
+			return database.Product{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product", err)
+		return database.Product{}, false
+	}
+	return product, true
+}
+
+func (cfg *apiConfig) loadProductMedia(w http.ResponseWriter, r *http.Request, productID uuid.UUID) (database.ProductMedium, bool) {
+	mediaID, err := uuid.Parse(chi.URLParam(r, "mediaID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid media ID format", err)
+		return database.ProductMedium{}, false
+	}
+
+	media, err := cfg.db.GetProductMediaByID(r.Context(), database.GetProductMediaByIDParams{
+		ID:        mediaID,
+		ProductID: productID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Media not found", nil)
+			return database.ProductMedium{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve media", err)
+		return database.ProductMedium{}, false
+	}
+	return media, true
+}
+
+func (cfg *apiConfig) productMediaToResponse(media database.ProductMedium) ProductMediaResponse {
+	resp := ProductMediaResponse{
+		ID:          media.ID,
+		ProductID:   media.ProductID,
+		URL:         cfg.storage.URL(media.StorageKey),
+		ContentType: media.ContentType,
+		Position:    media.Position,
+		Status:      media.Status,
+		CreatedAt:   media.CreatedAt,
+		UpdatedAt:   media.UpdatedAt,
+	}
+	if media.VariantID.Valid {
+		resp.VariantID = &media.VariantID.UUID
+	}
+	if media.SizeBytes.Valid {
+		resp.SizeBytes = &media.SizeBytes.Int64
+	}
+	if media.Width.Valid {
+		resp.Width = &media.Width.Int32
+	}
+	if media.Height.Valid {
+		resp.Height = &media.Height.Int32
+	}
+	if media.AltText.Valid {
+		resp.AltText = &media.AltText.String
+	}
+
+	resp.Thumbnails = make([]MediaThumbnail, 0, len(media.ThumbnailWidths))
+	for _, width := range media.ThumbnailWidths {
+		thumb := MediaThumbnail{
+			Width: width,
+			URL:   cfg.storage.URL(mediapkg.ThumbnailKey(media.StorageKey, media.ContentType, int(width))),
+		}
+		if media.Width.Valid && media.Height.Valid {
+			height := int32(mediapkg.ScaledHeight(int(media.Width.Int32), int(media.Height.Int32), int(width)))
+			thumb.Height = &height
+		}
+		resp.Thumbnails = append(resp.Thumbnails, thumb)
+	}
+	return resp
+}
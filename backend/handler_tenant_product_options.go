@@ -0,0 +1,343 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/options"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/slug"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxSKUPartLength bounds each option value's part of a generated SKU
+const maxSKUPartLength = 20
+
+// handlerTenantProductOptionsGet lists a product's options in display order
+func (cfg *apiConfig) handlerTenantProductOptionsGet(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	productID, ok := cfg.productInStore(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	opts, err := productOptions(r.Context(), cfg.db, productID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product options", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{"data": opts})
+}
+
+// handlerTenantProductOptionsPut replaces a product's options. Existing
+// variants are left as they are; generate variants to fill in new
+// combinations.
+func (cfg *apiConfig) handlerTenantProductOptionsPut(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	productID, ok := cfg.productInStore(w, r, access.StoreID)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		Options []options.Option `json:"options"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{Options: []options.Option{}}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+	if err := options.Validate(params.Options); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	err := cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		return replaceProductOptions(r.Context(), q, productID, params.Options)
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to save product options", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant product options replaced",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"store_id", access.StoreID,
+		"product_id", productID,
+		"option_count", len(params.Options),
+	)
+
+	respondWithJSON(w, http.StatusOK, map[string]any{"data": params.Options})
+}
+
+// variantOverride sets fields of the variant generated for one
+// combination, picked by its option_values
+type variantOverride struct {
+	OptionValues   map[string]string `json:"option_values"`
+	SKU            *string           `json:"sku"`
+	Barcode        *string           `json:"barcode"`
+	PriceCents     *int32            `json:"price_cents"`
+	CompareAtCents *int32            `json:"compare_at_cents"`
+	Status         *string           `json:"status"`
+	WeightGrams    *int32            `json:"weight_grams"`
+}
+
+// handlerTenantVariantsGenerate creates a variant for every combination of
+// the product's options that doesn't have one yet. Options given in the
+// body replace the product's first. Each variant takes the shared fields
+// unless an override for its combination says otherwise; with a
+// sku_prefix, variants without an SKU get one built from their values,
+// such as TEE-M-BLACK.
+func (cfg *apiConfig) handlerTenantVariantsGenerate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	productID, ok := cfg.productInStore(w, r, access.StoreID)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		Options        []options.Option  `json:"options"`
+		SKUPrefix      string            `json:"sku_prefix"`
+		PriceCents     int32             `json:"price_cents"`
+		CompareAtCents *int32            `json:"compare_at_cents"`
+		Status         string            `json:"status"`
+		WeightGrams    *int32            `json:"weight_grams"`
+		Overrides      []variantOverride `json:"overrides"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+	if params.Status == "" {
+		params.Status = "active"
+	}
+
+	var created []VariantResponse
+	var skipped int
+	err := cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		var err error
+		opts := params.Options
+		if opts != nil {
+			if err := options.Validate(opts); err != nil {
+				return err
+			}
+			if err := replaceProductOptions(r.Context(), q, productID, opts); err != nil {
+				return err
+			}
+		} else if opts, err = productOptions(r.Context(), q, productID); err != nil {
+			return err
+		}
+
+		var v validate.Validator
+		v.Check(len(params.SKUPrefix) <= maxVariantSKULength/2, "sku_prefix", validate.CodeTooLong,
+			fmt.Sprintf("must be at most %d characters", maxVariantSKULength/2))
+		variantFields{
+			PriceCents:     &params.PriceCents,
+			CompareAtCents: params.CompareAtCents,
+			Status:         &params.Status,
+			WeightGrams:    params.WeightGrams,
+		}.check(&v, "")
+		overrides := map[string]variantOverride{}
+		for i, o := range params.Overrides {
+			prefix := fmt.Sprintf("overrides.%d.", i)
+			values, _ := json.Marshal(o.OptionValues)
+			combo, ok := options.Match(opts, values)
+			if v.Check(ok, prefix+"option_values", validate.CodeInvalid, "must name one value of each of the product's options") {
+				_, dup := overrides[combo.Key()]
+				v.Check(!dup, prefix+"option_values", validate.CodeInvalid, "is already overridden")
+				overrides[combo.Key()] = o
+			}
+			variantFields{
+				SKU:            o.SKU,
+				Barcode:        o.Barcode,
+				PriceCents:     o.PriceCents,
+				CompareAtCents: o.CompareAtCents,
+				Status:         o.Status,
+				WeightGrams:    o.WeightGrams,
+			}.check(&v, prefix)
+		}
+		if err := v.Err(); err != nil {
+			return err
+		}
+
+		existing, err := q.GetProductVariantsByProductID(r.Context(), productID)
+		if err != nil {
+			return err
+		}
+		have := map[string]bool{}
+		for _, variant := range existing {
+			if combo, ok := options.Match(opts, variant.OptionValues); ok {
+				have[combo.Key()] = true
+			}
+		}
+
+		created = []VariantResponse{}
+		for _, combo := range options.Combinations(opts) {
+			if have[combo.Key()] {
+				skipped++
+				continue
+			}
+			arg := generatedVariant(params.PriceCents, params.CompareAtCents, params.Status, params.WeightGrams, overrides[combo.Key()])
+			arg.Gid = sql.NullInt64{Int64: int64(cfg.gidGen.Generate()), Valid: true}
+			arg.TenantID, arg.StoreID, arg.ProductID = access.TenantID, access.StoreID, productID
+			arg.Title = combo.Title()
+			if len(opts) == 0 {
+				arg.Title = "Default Title"
+			}
+			arg.OptionValues, _ = json.Marshal(combo.Values(opts))
+			if !arg.Sku.Valid && params.SKUPrefix != "" {
+				arg.Sku = sql.NullString{String: generatedSKU(params.SKUPrefix, combo), Valid: true}
+			}
+
+			variant, err := q.CreateProductVariant(r.Context(), arg)
+			if err != nil {
+				return service.ConflictCodeAs(err, problem.CodeAlreadyExists,
+					fmt.Sprintf("A variant with SKU %s already exists in this store", arg.Sku.String))
+			}
+			created = append(created, variantToResponse(variant))
+		}
+		return nil
+	})
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to generate variants")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant variants generated",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"store_id", access.StoreID,
+		"product_id", productID,
+		"created", len(created),
+		"skipped", skipped,
+	)
+
+	cfg.syncProductCollectionsAfterWrite(r.Context(), access.StoreID, productID)
+
+	respondWithJSON(w, http.StatusCreated, map[string]any{
+		"data":    created,
+		"skipped": skipped,
+	})
+}
+
+// generatedVariant builds the fields of one generated variant: the shared
+// ones, overridden where o says
+func generatedVariant(priceCents int32, compareAtCents *int32, status string, weightGrams *int32, o variantOverride) database.CreateProductVariantParams {
+	if o.PriceCents != nil {
+		priceCents = *o.PriceCents
+	}
+	if o.CompareAtCents != nil {
+		compareAtCents = o.CompareAtCents
+	}
+	if o.Status != nil {
+		status = *o.Status
+	}
+	if o.WeightGrams != nil {
+		weightGrams = o.WeightGrams
+	}
+
+	arg := database.CreateProductVariantParams{PriceCents: priceCents, Status: status}
+	if compareAtCents != nil {
+		arg.CompareAtCents = sql.NullInt32{Int32: *compareAtCents, Valid: true}
+	}
+	if weightGrams != nil {
+		arg.WeightGrams = sql.NullInt32{Int32: *weightGrams, Valid: true}
+	}
+	if o.SKU != nil {
+		arg.Sku = sql.NullString{String: *o.SKU, Valid: true}
+	}
+	if o.Barcode != nil {
+		arg.Barcode = sql.NullString{String: *o.Barcode, Valid: true}
+	}
+	return arg
+}
+
+// generatedSKU joins prefix and the combination's values, such as
+// TEE-M-BLACK
+func generatedSKU(prefix string, combo options.Combination) string {
+	parts := []string{prefix}
+	for _, value := range combo {
+		if part := slug.Make(value, maxSKUPartLength); part != "" {
+			parts = append(parts, strings.ToUpper(part))
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// productInStore parses the productID URL parameter and checks the product
+// is in the store, responding with 400 or 404 when not
+func (cfg *apiConfig) productInStore(w http.ResponseWriter, r *http.Request, storeID uuid.UUID) (uuid.UUID, bool) {
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid product ID format", err)
+		return uuid.Nil, false
+	}
+	_, err = cfg.db.GetProductByID(r.Context(), database.GetProductByIDParams{
+		ID:      productID,
+		StoreID: storeID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Product not found", nil)
+			return uuid.Nil, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to verify product", err)
+		return uuid.Nil, false
+	}
+	return productID, true
+}
+
+// productOptions loads a product's options in display order
+func productOptions(ctx context.Context, q *database.Queries, productID uuid.UUID) ([]options.Option, error) {
+	rows, err := q.ListProductOptions(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	opts := make([]options.Option, 0, len(rows))
+	for _, row := range rows {
+		opts = append(opts, options.Option{Name: row.Name, Values: row.Choices})
+	}
+	return opts, nil
+}
+
+// replaceProductOptions swaps a product's options for opts
+func replaceProductOptions(ctx context.Context, q *database.Queries, productID uuid.UUID, opts []options.Option) error {
+	if err := q.DeleteProductOptions(ctx, productID); err != nil {
+		return err
+	}
+	for i, opt := range opts {
+		_, err := q.CreateProductOption(ctx, database.CreateProductOptionParams{
+			ProductID: productID,
+			Name:      opt.Name,
+			Position:  int32(i),
+			Choices:   opt.Values,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/reviews"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// ProductReviewResponse is a review as staff moderating it see it
+type ProductReviewResponse struct {
+	StorefrontReviewResponse
+	ProductID uuid.UUID `json:"product_id"`
+	// CustomerID is unset once the customer is deleted
+	CustomerID  *uuid.UUID `json:"customer_id,omitempty"`
+	Status      string     `json:"status"`
+	ModeratedAt *time.Time `json:"moderated_at,omitempty"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// handlerTenantProductReviewsList is the moderation queue: a store's
+// reviews, newest first, filtered by ?status= (pending unless given, or
+// "all") and ?product_id=
+func (cfg *apiConfig) handlerTenantProductReviewsList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = reviews.StatusPending
+	}
+	var v validate.Validator
+	if status != "all" {
+		v.Check(reviews.IsValidStatus(status), "status", validate.CodeInvalid, "must be one of pending, approved, rejected, spam, all")
+	}
+	var productID uuid.NullUUID
+	if raw := r.URL.Query().Get("product_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		v.Check(err == nil, "product_id", validate.CodeInvalid, "must be a UUID")
+		productID = uuid.NullUUID{UUID: id, Valid: err == nil}
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+	limit := pageParams.Limit
+
+	cursor, hasCursor, err := reviewCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.db.ListProductReviewsPaginated(r.Context(), database.ListProductReviewsPaginatedParams{
+		StoreID:         storeID,
+		Status:          sql.NullString{String: status, Valid: status != "all"},
+		ProductID:       productID,
+		HasCursor:       hasCursor,
+		CursorCreatedAt: cursor.CreatedAt,
+		CursorID:        cursor.ID,
+		RowLimit:        int32(limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve reviews", err)
+		return
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = reviewCursorCodec.Encode(ReviewCursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]ProductReviewResponse, 0, len(rows))
+	for _, review := range rows {
+		response = append(response, productReviewToResponse(review))
+	}
+
+	respondWithJSON(w, http.StatusOK, PageResponse[ProductReviewResponse]{
+		Data: response,
+		Page: PageInfo{Limit: limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
+
+// handlerTenantProductReviewGet returns a review
+func (cfg *apiConfig) handlerTenantProductReviewGet(w http.ResponseWriter, r *http.Request) {
+	review, ok := cfg.loadProductReview(w, r)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, productReviewToResponse(review))
+}
+
+// handlerTenantProductReviewModerate returns a handler that moves a review
+// to status. Only approved reviews are shown to shoppers, so approving
+// publishes a review and rejecting or marking it as spam takes it down.
+func (cfg *apiConfig) handlerTenantProductReviewModerate(status string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqID := middleware.GetRequestID(r.Context())
+		access := tenantAccessFrom(r)
+
+		existing, ok := cfg.loadProductReview(w, r)
+		if !ok {
+			return
+		}
+
+		review, err := cfg.db.ModerateProductReview(r.Context(), database.ModerateProductReviewParams{
+			ID:          existing.ID,
+			StoreID:     access.StoreID,
+			Status:      status,
+			ModeratedBy: uuid.NullUUID{UUID: access.UserID, Valid: true},
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Review not found", nil)
+			return
+		}
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to moderate review", err)
+			return
+		}
+		auditChange(r, auditGID(gid.EntityProductReview, review.Gid), productReviewToResponse(existing), productReviewToResponse(review))
+
+		slog.InfoContext(r.Context(), "product review moderated",
+			"request_id", reqID,
+			"user_id", access.UserID,
+			"tenant_id", access.TenantID,
+			"store_id", access.StoreID,
+			"review_id", review.ID,
+			"status", review.Status,
+		)
+
+		respondWithJSON(w, http.StatusOK, productReviewToResponse(review))
+	}
+}
+
+// handlerTenantProductReviewDelete deletes a review for good
+func (cfg *apiConfig) handlerTenantProductReviewDelete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	review, ok := cfg.loadProductReview(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := cfg.db.DeleteProductReview(r.Context(), database.DeleteProductReviewParams{
+		ID:      review.ID,
+		StoreID: access.StoreID,
+	}); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to delete review", err)
+		return
+	}
+	auditChange(r, auditGID(gid.EntityProductReview, review.Gid), productReviewToResponse(review), nil)
+
+	slog.InfoContext(r.Context(), "product review deleted",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"review_id", review.ID,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadProductReview loads the {reviewID} of the route from the store the
+// caller was let into. It has responded when it returns false.
+func (cfg *apiConfig) loadProductReview(w http.ResponseWriter, r *http.Request) (database.ProductReview, bool) {
+	reviewID, err := uuid.Parse(chi.URLParam(r, "reviewID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid review ID format", err)
+		return database.ProductReview{}, false
+	}
+
+	review, err := cfg.db.GetProductReviewByID(r.Context(), database.GetProductReviewByIDParams{
+		ID:      reviewID,
+		StoreID: tenantAccessFrom(r).StoreID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Review not found", nil)
+		return database.ProductReview{}, false
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve review", err)
+		return database.ProductReview{}, false
+	}
+	return review, true
+}
+
+func productReviewToResponse(review database.ProductReview) ProductReviewResponse {
+	resp := ProductReviewResponse{
+		StorefrontReviewResponse: storefrontReviewToResponse(review),
+		ProductID:                review.ProductID,
+		Status:                   review.Status,
+		UpdatedAt:                review.UpdatedAt,
+	}
+	if review.CustomerID.Valid {
+		resp.CustomerID = &review.CustomerID.UUID
+	}
+	if review.ModeratedAt.Valid {
+		resp.ModeratedAt = &review.ModeratedAt.Time
+	}
+	return resp
+}
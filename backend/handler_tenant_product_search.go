@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/search"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/google/uuid"
+)
+
+// maxSearchQueryLength bounds the q parameter; longer queries are almost
+// certainly not typed by a person and are expensive to parse
+const maxSearchQueryLength = 200
+
+// ProductSearchMatch explains why a product matched a search query.
+// Highlights wrap matched terms in <mark> tags and are not HTML-escaped.
+type ProductSearchMatch struct {
+	Rank               float64 `json:"rank"`
+	NameHighlight      string  `json:"name_highlight"`
+	DescriptionSnippet *string `json:"description_snippet,omitempty"`
+}
+
+// respondWithProductSearch serves the tenant product list when a q search
+// query is present. Results are ordered by relevance instead of recency and
+// come from the configured search engine.
+func (cfg *apiConfig) respondWithProductSearch(w http.ResponseWriter, r *http.Request, storeID uuid.UUID, query string, pageParams PageParams) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	if len(query) > maxSearchQueryLength {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Search query must be %d characters or fewer", maxSearchQueryLength), nil)
+		return
+	}
+
+	result, err := cfg.search.Search(r.Context(), search.Query{
+		StoreID: storeID,
+		Text:    query,
+		Limit:   pageParams.Limit,
+		Cursor:  pageParams.Cursor,
+	})
+	if err != nil {
+		if errors.Is(err, search.ErrInvalidCursor) {
+			respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to search products", err)
+		return
+	}
+
+	ids := make([]uuid.UUID, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		ids = append(ids, hit.ProductID)
+	}
+
+	rows, err := cfg.db.GetProductsByIDs(r.Context(), database.GetProductsByIDsParams{
+		StoreID: storeID,
+		Ids:     ids,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve products", err)
+		return
+	}
+
+	byID := make(map[uuid.UUID]database.Product, len(rows))
+	for _, row := range rows {
+		byID[row.ID] = row
+	}
+
+	// External indexes lag behind writes; skip hits deleted in the meantime
+	products := make([]database.Product, 0, len(result.Hits))
+	hits := make([]search.Hit, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		if product, ok := byID[hit.ProductID]; ok {
+			products = append(products, product)
+			hits = append(hits, hit)
+		}
+	}
+
+	response, err := cfg.tenantProductsToResponse(r.Context(), products)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve products", err)
+		return
+	}
+
+	for i, hit := range hits {
+		match := &ProductSearchMatch{
+			Rank:          hit.Score,
+			NameHighlight: hit.NameHighlight,
+		}
+		if match.NameHighlight == "" {
+			match.NameHighlight = products[i].Name
+		}
+		if hit.DescriptionSnippet != "" {
+			match.DescriptionSnippet = &hit.DescriptionSnippet
+		}
+		response[i].Search = match
+	}
+
+	slog.InfoContext(r.Context(), "tenant products search successful",
+		"request_id", reqID,
+		"store_id", storeID,
+		"engine", cfg.search.Name(),
+		"product_count", len(response),
+		"has_more", result.NextCursor != "",
+	)
+
+	respondWithJSON(w, http.StatusOK, PageResponse[TenantProductResponse]{
+		Data: response,
+		Page: PageInfo{Limit: pageParams.Limit, NextCursor: result.NextCursor, HasMore: result.NextCursor != ""},
+	})
+}
+
+// handlerTenantSearchReindex queues every product of a store for the search
+// indexer, e.g. after switching search engines
+func (cfg *apiConfig) handlerTenantSearchReindex(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	queued, err := cfg.db.EnqueueStoreProductReindex(r.Context(), storeID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to queue reindex", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant store search reindex queued",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"engine", cfg.search.Name(),
+		"product_count", queued,
+	)
+
+	respondWithJSON(w, http.StatusAccepted, map[string]any{
+		"engine":          cfg.search.Name(),
+		"queued_products": queued,
+	})
+}
+
+// tenantProductsToResponse builds list responses with inventory, media and
+// ratings loaded for the whole page at once
+func (cfg *apiConfig) tenantProductsToResponse(ctx context.Context, products []database.Product) ([]TenantProductResponse, error) {
+	trackedIDs := make([]uuid.UUID, 0, len(products))
+	productIDs := make([]uuid.UUID, 0, len(products))
+	for _, product := range products {
+		if product.InventoryTracked {
+			trackedIDs = append(trackedIDs, product.ID)
+		}
+		productIDs = append(productIDs, product.ID)
+	}
+
+	availability, err := cfg.productAvailability(ctx, trackedIDs)
+	if err != nil {
+		return nil, fmt.Errorf("product inventory: %w", err)
+	}
+
+	media, err := cfg.productMedia(ctx, productIDs)
+	if err != nil {
+		return nil, fmt.Errorf("product media: %w", err)
+	}
+
+	ratings, err := cfg.productRatings(ctx, productIDs)
+	if err != nil {
+		return nil, fmt.Errorf("product ratings: %w", err)
+	}
+
+	response := make([]TenantProductResponse, 0, len(products))
+	for _, product := range products {
+		var desc, skuPtr, tagsPtr *string
+		if product.Description.Valid {
+			desc = &product.Description.String
+		}
+		if product.Sku.Valid {
+			skuPtr = &product.Sku.String
+		}
+		if product.Tags.Valid {
+			tagsPtr = &product.Tags.String
+		}
+		rating := ratings[product.ID]
+
+		response = append(response, TenantProductResponse{
+			ID:               product.ID,
+			StoreID:          product.StoreID,
+			Handle:           product.Handle,
+			Name:             product.Name,
+			Description:      desc,
+			InventoryTracked: product.InventoryTracked,
+			OversellPolicy:   product.OversellPolicy,
+			SKU:              skuPtr,
+			Tags:             tagsPtr,
+			Status:           product.Status,
+			Inventory:        availability[product.ID],
+			Media:            media[product.ID],
+			Rating:           &rating,
+			CreatedAt:        product.CreatedAt,
+			UpdatedAt:        product.UpdatedAt,
+		})
+	}
+	return response, nil
+}
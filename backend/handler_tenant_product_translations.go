@@ -0,0 +1,184 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/locale"
+	"github.com/dfodeker/terminus/internal/service/products"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// ProductTranslationResponse is a product's content in one locale
+type ProductTranslationResponse struct {
+	Locale      string    `json:"locale"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description,omitempty"`
+	Handle      *string   `json:"handle,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// handlerTenantProductTranslationsList lists a product's translations
+func (cfg *apiConfig) handlerTenantProductTranslationsList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid product ID format", err)
+		return
+	}
+
+	translations, err := cfg.services.products.Translations(r.Context(), storeID, productID)
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to retrieve translations")
+		return
+	}
+
+	response := make([]ProductTranslationResponse, 0, len(translations))
+	for _, t := range translations {
+		response = append(response, productTranslationToResponse(t))
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{"data": response})
+}
+
+// handlerTenantProductTranslationSet creates or replaces a product's
+// translation in the {locale} of the route
+func (cfg *apiConfig) handlerTenantProductTranslationSet(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid product ID format", err)
+		return
+	}
+
+	type parameters struct {
+		Name        string  `json:"name"`
+		Description *string `json:"description"`
+		Handle      *string `json:"handle"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	// The audit log records what the translation replaced
+	product, err := cfg.services.products.Get(r.Context(), access.StoreID, productID)
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to save translation")
+		return
+	}
+	before, err := cfg.productTranslation(r, product.ID, chi.URLParam(r, "locale"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to save translation", err)
+		return
+	}
+
+	translation, err := cfg.services.products.SetTranslation(r.Context(), products.TranslationInput{
+		StoreID:     access.StoreID,
+		ProductID:   product.ID,
+		Locale:      chi.URLParam(r, "locale"),
+		Name:        params.Name,
+		Description: params.Description,
+		Handle:      params.Handle,
+	})
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to save translation")
+		return
+	}
+	auditChange(r, auditGID(gid.EntityProduct, product.Gid), before, productTranslationToResponse(translation))
+
+	slog.InfoContext(r.Context(), "product translation saved",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"product_id", product.ID,
+		"locale", translation.Locale,
+	)
+
+	respondWithJSON(w, http.StatusOK, productTranslationToResponse(translation))
+}
+
+// handlerTenantProductTranslationDelete removes a product's translation,
+// so shoppers asking for that locale get the product's own content
+func (cfg *apiConfig) handlerTenantProductTranslationDelete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid product ID format", err)
+		return
+	}
+
+	product, err := cfg.services.products.Get(r.Context(), access.StoreID, productID)
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to delete translation")
+		return
+	}
+
+	translation, err := cfg.services.products.DeleteTranslation(r.Context(), access.StoreID, product.ID, chi.URLParam(r, "locale"))
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to delete translation")
+		return
+	}
+	auditChange(r, auditGID(gid.EntityProduct, product.Gid), productTranslationToResponse(translation), nil)
+
+	slog.InfoContext(r.Context(), "product translation deleted",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"product_id", product.ID,
+		"locale", translation.Locale,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// productTranslation returns the product's translation in loc for the
+// audit log, or nil when there is none
+func (cfg *apiConfig) productTranslation(r *http.Request, productID uuid.UUID, loc string) (any, error) {
+	canonical, err := locale.Parse(loc)
+	if err != nil {
+		// SetTranslation rejects it
+		return nil, nil
+	}
+	translations, err := cfg.db.ListProductTranslations(r.Context(), productID)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range translations {
+		if t.Locale == canonical {
+			return productTranslationToResponse(t), nil
+		}
+	}
+	return nil, nil
+}
+
+func productTranslationToResponse(t database.ProductTranslation) ProductTranslationResponse {
+	resp := ProductTranslationResponse{
+		Locale:    t.Locale,
+		Name:      t.Name,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+	}
+	if t.Description.Valid {
+		resp.Description = &t.Description.String
+	}
+	if t.Handle.Valid {
+		resp.Handle = &t.Handle.String
+	}
+	return resp
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// DeletedCursor pages through the trash, most recently deleted first
+type DeletedCursor struct {
+	DeletedAt time.Time `json:"deleted_at"`
+	ID        uuid.UUID `json:"id"`
+}
+
+var deletedCursorCodec = CursorCodec[DeletedCursor]{
+	Validate: func(c DeletedCursor) error {
+		if c.DeletedAt.IsZero() || c.ID == uuid.Nil {
+			return errors.New("invalid cursor: missing required fields")
+		}
+		return nil
+	},
+}
+
+// handlerTenantProductsDeletedList lists a store's deleted products that
+// have not been purged yet
+func (cfg *apiConfig) handlerTenantProductsDeletedList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+
+	cursor, hasCursor, err := deletedCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.db.ListDeletedProducts(r.Context(), database.ListDeletedProductsParams{
+		StoreID:         storeID,
+		HasCursor:       hasCursor,
+		CursorDeletedAt: cursor.DeletedAt,
+		CursorID:        cursor.ID,
+		RowLimit:        int32(pageParams.Limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve deleted products", err)
+		return
+	}
+
+	hasMore := len(rows) > pageParams.Limit
+	if hasMore {
+		rows = rows[:pageParams.Limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = deletedCursorCodec.Encode(DeletedCursor{
+			DeletedAt: last.DeletedAt.Time,
+			ID:        last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]TenantProductResponse, 0, len(rows))
+	for _, p := range rows {
+		resp := tenantProductToResponse(p)
+		resp.Media = []ProductMediaResponse{}
+		response = append(response, resp)
+	}
+
+	respondWithJSON(w, http.StatusOK, PageResponse[TenantProductResponse]{
+		Data: response,
+		Page: PageInfo{Limit: pageParams.Limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
+
+// handlerTenantProductRestore brings a deleted product back, with the
+// variants that were deleted along with it
+func (cfg *apiConfig) handlerTenantProductRestore(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid product ID format", err)
+		return
+	}
+
+	// Kept for the audit log
+	trashed, err := cfg.db.GetDeletedProduct(r.Context(), database.GetDeletedProductParams{
+		ID:      productID,
+		StoreID: access.StoreID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Deleted product not found", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to restore product", err)
+		return
+	}
+
+	if err := cfg.services.entitlements.CheckProducts(r.Context(), access.StoreID); err != nil {
+		respondWithServiceError(w, err, "Unable to restore product")
+		return
+	}
+
+	product, err := cfg.services.products.Restore(r.Context(), access.StoreID, productID)
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to restore product")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant product restored",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"store_id", access.StoreID,
+		"product_id", product.ID,
+	)
+
+	cfg.syncProductCollectionsAfterWrite(r.Context(), product.StoreID, product.ID)
+	auditChange(r, auditGID(gid.EntityProduct, product.Gid), tenantProductToResponse(trashed), tenantProductToResponse(product))
+
+	media, err := cfg.productMedia(r.Context(), []uuid.UUID{product.ID})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product media", err)
+		return
+	}
+
+	resp := tenantProductToResponse(product)
+	resp.Media = media[product.ID]
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerTenantVariantsDeletedList lists a product's deleted variants that
+// have not been purged yet
+func (cfg *apiConfig) handlerTenantVariantsDeletedList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid product ID format", err)
+		return
+	}
+	if _, err := cfg.services.products.Get(r.Context(), storeID, productID); err != nil {
+		respondWithServiceError(w, err, "Unable to retrieve product")
+		return
+	}
+
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+
+	cursor, hasCursor, err := deletedCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.db.ListDeletedProductVariants(r.Context(), database.ListDeletedProductVariantsParams{
+		ProductID:       productID,
+		HasCursor:       hasCursor,
+		CursorDeletedAt: cursor.DeletedAt,
+		CursorID:        cursor.ID,
+		RowLimit:        int32(pageParams.Limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve deleted variants", err)
+		return
+	}
+
+	hasMore := len(rows) > pageParams.Limit
+	if hasMore {
+		rows = rows[:pageParams.Limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = deletedCursorCodec.Encode(DeletedCursor{
+			DeletedAt: last.DeletedAt.Time,
+			ID:        last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]VariantResponse, 0, len(rows))
+	for _, v := range rows {
+		response = append(response, variantToResponse(v))
+	}
+
+	respondWithJSON(w, http.StatusOK, PageResponse[VariantResponse]{
+		Data: response,
+		Page: PageInfo{Limit: pageParams.Limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
+
+// handlerTenantVariantRestore brings back a variant deleted on its own. A
+// variant deleted with its product comes back when the product does.
+func (cfg *apiConfig) handlerTenantVariantRestore(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid product ID format", err)
+		return
+	}
+	variantID, err := uuid.Parse(chi.URLParam(r, "variantID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid variant ID format", err)
+		return
+	}
+	if _, err := cfg.services.products.Get(r.Context(), access.StoreID, productID); err != nil {
+		respondWithServiceError(w, err, "Unable to restore variant")
+		return
+	}
+
+	// Kept for the audit log
+	trashed, err := cfg.db.GetDeletedProductVariant(r.Context(), database.GetDeletedProductVariantParams{
+		ID:        variantID,
+		ProductID: productID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Deleted variant not found", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to restore variant", err)
+		return
+	}
+
+	variant, err := cfg.db.RestoreProductVariant(r.Context(), database.RestoreProductVariantParams{
+		ID:        variantID,
+		ProductID: productID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Deleted variant not found", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to restore variant", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant variant restored",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"store_id", access.StoreID,
+		"variant_id", variant.ID,
+	)
+
+	cfg.syncProductCollectionsAfterWrite(r.Context(), variant.StoreID, variant.ProductID)
+	auditChange(r, auditGID(gid.EntityProductVariant, variant.Gid), variantToResponse(trashed), variantToResponse(variant))
+
+	respondWithJSON(w, http.StatusOK, variantToResponse(variant))
+}
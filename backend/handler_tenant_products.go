@@ -1,19 +1,49 @@
 package main
 
 import (
-	"database/sql"
 	"encoding/json"
 	"errors"
 	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/reviews"
+	"github.com/dfodeker/terminus/internal/service/products"
 	"github.com/dfodeker/terminus/middleware"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
+// ProductCursor is where a page of products ordered newest first ends
+type ProductCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+}
+
+var productCursorCodec = CursorCodec[ProductCursor]{
+	Validate: func(c ProductCursor) error {
+		if c.CreatedAt.IsZero() || c.ID == uuid.Nil {
+			return errors.New("missing fields")
+		}
+		return nil
+	},
+}
+
+// cursorInfo decodes a product cursor into the query's cursor arguments
+func cursorInfo(cursor string) (time.Time, uuid.UUID, bool, error) {
+	cur, ok, err := productCursorCodec.Decode(cursor)
+	if err != nil {
+		return time.Time{}, uuid.UUID{}, false, err
+	}
+	if !ok {
+		return time.Time{}, uuid.UUID{}, false, nil
+	}
+	return cur.CreatedAt, cur.ID, true, nil
+}
+
 type TenantProductResponse struct {
 	ID               uuid.UUID `json:"id"`
 	StoreID          uuid.UUID `json:"store_id"`
@@ -24,8 +54,25 @@ type TenantProductResponse struct {
 	SKU              *string   `json:"sku,omitempty"`
 	Tags             *string   `json:"tags,omitempty"`
 	Status           string    `json:"status"`
-	CreatedAt        time.Time `json:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at"`
+	// OversellPolicy is deny, allow or backorder: what a checkout wanting
+	// more than is available gets
+	OversellPolicy string `json:"oversell_policy"`
+	// Inventory is only populated on reads of products with inventory tracking
+	Inventory *ProductInventoryResponse `json:"inventory,omitempty"`
+	// Media holds the product's uploaded images in display order
+	Media []ProductMediaResponse `json:"media"`
+	// Rating is over the product's approved reviews, and only populated
+	// on reads and lists
+	Rating *reviews.Summary `json:"rating,omitempty"`
+	// Search is only set when listing with a q search query
+	Search    *ProductSearchMatch `json:"search,omitempty"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+	// Version is bumped by every write; send it back to pin an update to
+	// what was read
+	Version int64 `json:"version"`
+	// DeletedAt is only set on products in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 // handlerTenantProductCreate creates a product within a tenant's store
@@ -40,62 +87,8 @@ func (cfg *apiConfig) handlerTenantProductCreate(w http.ResponseWriter, r *http.
 		"store_param", storeParam,
 	)
 
-	user, ok := userFromContext(r.Context())
-	if !ok {
-		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
-		return
-	}
-
-	tenantID, err := uuid.Parse(tenantParam)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid tenant ID format", err)
-		return
-	}
-
-	storeID, err := uuid.Parse(storeParam)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid store ID format", err)
-		return
-	}
-
-	// Verify user has permission to create products
-	hasPermission, err := cfg.db.CheckUserHasPermission(r.Context(), database.CheckUserHasPermissionParams{
-		TenantID: tenantID,
-		UserID:   user,
-		Key:      "products:create",
-	})
-	if err != nil {
-		slog.ErrorContext(r.Context(), "tenant product creation failed: error checking permissions",
-			"request_id", reqID,
-			"error", err,
-		)
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify permissions", err)
-		return
-	}
-
-	if !hasPermission {
-		slog.WarnContext(r.Context(), "tenant product creation denied: insufficient permissions",
-			"request_id", reqID,
-			"user_id", user,
-			"tenant_id", tenantID,
-		)
-		respondWithError(w, http.StatusForbidden, "You do not have permission to create products", nil)
-		return
-	}
-
-	// Verify store belongs to tenant
-	store, err := cfg.db.GetStoreByTenantAndID(r.Context(), database.GetStoreByTenantAndIDParams{
-		TenantID: uuid.NullUUID{UUID: tenantID, Valid: true},
-		ID:       storeID,
-	})
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			respondWithError(w, http.StatusNotFound, "Store not found in this tenant", nil)
-			return
-		}
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify store", err)
-		return
-	}
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
 
 	type parameters struct {
 		Name             string  `json:"name"`
@@ -109,60 +102,36 @@ func (cfg *apiConfig) handlerTenantProductCreate(w http.ResponseWriter, r *http.
 
 	decoder := json.NewDecoder(r.Body)
 	params := parameters{}
-	err = decoder.Decode(&params)
+	err := decoder.Decode(&params)
 	if err != nil {
 		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
 		return
 	}
 
-	if params.Name == "" {
-		respondWithError(w, http.StatusBadRequest, "Product name is required", nil)
-		return
-	}
-	if params.Handle == "" {
-		respondWithError(w, http.StatusBadRequest, "Product handle is required", nil)
+	if err := cfg.services.entitlements.CheckProducts(r.Context(), storeID); err != nil {
+		respondWithServiceError(w, err, "Unable to create product")
 		return
 	}
 
-	status := params.Status
-	if status == "" {
-		status = "active"
-	}
-
-	description := sql.NullString{}
-	if params.Description != nil {
-		description = sql.NullString{String: *params.Description, Valid: true}
-	}
-
-	sku := sql.NullString{}
-	if params.SKU != nil {
-		sku = sql.NullString{String: *params.SKU, Valid: true}
-	}
-
-	tags := sql.NullString{}
-	if params.Tags != nil {
-		tags = sql.NullString{String: *params.Tags, Valid: true}
-	}
-
-	product, err := cfg.db.CreateProduct(r.Context(), database.CreateProductParams{
-		StoreID:          store.ID,
-		Handle:           params.Handle,
+	product, err := cfg.services.products.Create(r.Context(), products.CreateInput{
+		StoreID:          storeID,
 		Name:             params.Name,
-		Description:      description,
+		Handle:           params.Handle,
+		Description:      params.Description,
 		InventoryTracked: params.InventoryTracked,
-		Sku:              sku,
-		Tags:             tags,
-		Status:           status,
+		SKU:              params.SKU,
+		Tags:             params.Tags,
+		Status:           params.Status,
 	})
 	if err != nil {
-		slog.ErrorContext(r.Context(), "tenant product creation failed: database error",
+		slog.ErrorContext(r.Context(), "tenant product creation failed",
 			"request_id", reqID,
 			"user_id", user,
 			"tenant_id", tenantID,
 			"store_id", storeID,
 			"error", err,
 		)
-		respondWithError(w, http.StatusBadRequest, "Unable to create product", err)
+		respondWithServiceError(w, err, "Unable to create product")
 		return
 	}
 
@@ -174,6 +143,10 @@ func (cfg *apiConfig) handlerTenantProductCreate(w http.ResponseWriter, r *http.
 		"product_id", product.ID,
 	)
 
+	cfg.syncProductCollectionsAfterWrite(r.Context(), product.StoreID, product.ID)
+	auditChange(r, auditGID(gid.EntityProduct, product.Gid), nil, tenantProductToResponse(product))
+	setVersionETag(w, product.Version)
+
 	var desc, skuPtr, tagsPtr *string
 	if product.Description.Valid {
 		desc = &product.Description.String
@@ -192,11 +165,14 @@ func (cfg *apiConfig) handlerTenantProductCreate(w http.ResponseWriter, r *http.
 		Name:             product.Name,
 		Description:      desc,
 		InventoryTracked: product.InventoryTracked,
+		OversellPolicy:   product.OversellPolicy,
 		SKU:              skuPtr,
 		Tags:             tagsPtr,
 		Status:           product.Status,
+		Media:            []ProductMediaResponse{},
 		CreatedAt:        product.CreatedAt,
 		UpdatedAt:        product.UpdatedAt,
+		Version:          product.Version,
 	})
 }
 
@@ -214,23 +190,7 @@ func (cfg *apiConfig) handlerTenantProductGet(w http.ResponseWriter, r *http.Req
 		"product_param", productParam,
 	)
 
-	user, ok := userFromContext(r.Context())
-	if !ok {
-		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
-		return
-	}
-
-	tenantID, err := uuid.Parse(tenantParam)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid tenant ID format", err)
-		return
-	}
-
-	storeID, err := uuid.Parse(storeParam)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid store ID format", err)
-		return
-	}
+	storeID := tenantAccessFrom(r).StoreID
 
 	productID, err := uuid.Parse(productParam)
 	if err != nil {
@@ -238,46 +198,9 @@ func (cfg *apiConfig) handlerTenantProductGet(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// Verify user has permission to view products
-	hasPermission, err := cfg.db.CheckUserHasPermission(r.Context(), database.CheckUserHasPermissionParams{
-		TenantID: tenantID,
-		UserID:   user,
-		Key:      "products:view",
-	})
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify permissions", err)
-		return
-	}
-
-	if !hasPermission {
-		respondWithError(w, http.StatusForbidden, "You do not have permission to view products", nil)
-		return
-	}
-
-	// Verify store belongs to tenant
-	_, err = cfg.db.GetStoreByTenantAndID(r.Context(), database.GetStoreByTenantAndIDParams{
-		TenantID: uuid.NullUUID{UUID: tenantID, Valid: true},
-		ID:       storeID,
-	})
+	product, err := cfg.services.products.Get(r.Context(), storeID, productID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			respondWithError(w, http.StatusNotFound, "Store not found in this tenant", nil)
-			return
-		}
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify store", err)
-		return
-	}
-
-	product, err := cfg.db.GetProductByID(r.Context(), database.GetProductByIDParams{
-		ID:      productID,
-		StoreID: storeID,
-	})
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			respondWithError(w, http.StatusNotFound, "Product not found", nil)
-			return
-		}
-		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product", err)
+		respondWithServiceError(w, err, "Unable to retrieve product")
 		return
 	}
 
@@ -292,6 +215,29 @@ func (cfg *apiConfig) handlerTenantProductGet(w http.ResponseWriter, r *http.Req
 		tagsPtr = &product.Tags.String
 	}
 
+	var inventory *ProductInventoryResponse
+	if product.InventoryTracked {
+		availability, err := cfg.productAvailability(r.Context(), []uuid.UUID{product.ID})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product inventory", err)
+			return
+		}
+		inventory = availability[product.ID]
+	}
+
+	media, err := cfg.productMedia(r.Context(), []uuid.UUID{product.ID})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product media", err)
+		return
+	}
+	ratings, err := cfg.productRatings(r.Context(), []uuid.UUID{product.ID})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product rating", err)
+		return
+	}
+	rating := ratings[product.ID]
+
+	setVersionETag(w, product.Version)
 	respondWithJSON(w, http.StatusOK, TenantProductResponse{
 		ID:               product.ID,
 		StoreID:          product.StoreID,
@@ -299,9 +245,14 @@ func (cfg *apiConfig) handlerTenantProductGet(w http.ResponseWriter, r *http.Req
 		Name:             product.Name,
 		Description:      desc,
 		InventoryTracked: product.InventoryTracked,
+		OversellPolicy:   product.OversellPolicy,
 		SKU:              skuPtr,
 		Tags:             tagsPtr,
 		Status:           product.Status,
+		Inventory:        inventory,
+		Media:            media[product.ID],
+		Rating:           &rating,
+		Version:          product.Version,
 		CreatedAt:        product.CreatedAt,
 		UpdatedAt:        product.UpdatedAt,
 	})
@@ -321,23 +272,8 @@ func (cfg *apiConfig) handlerTenantProductUpdate(w http.ResponseWriter, r *http.
 		"product_param", productParam,
 	)
 
-	user, ok := userFromContext(r.Context())
-	if !ok {
-		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
-		return
-	}
-
-	tenantID, err := uuid.Parse(tenantParam)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid tenant ID format", err)
-		return
-	}
-
-	storeID, err := uuid.Parse(storeParam)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid store ID format", err)
-		return
-	}
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
 
 	productID, err := uuid.Parse(productParam)
 	if err != nil {
@@ -345,50 +281,6 @@ func (cfg *apiConfig) handlerTenantProductUpdate(w http.ResponseWriter, r *http.
 		return
 	}
 
-	// Verify user has permission to edit products
-	hasPermission, err := cfg.db.CheckUserHasPermission(r.Context(), database.CheckUserHasPermissionParams{
-		TenantID: tenantID,
-		UserID:   user,
-		Key:      "products:edit",
-	})
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify permissions", err)
-		return
-	}
-
-	if !hasPermission {
-		respondWithError(w, http.StatusForbidden, "You do not have permission to edit products", nil)
-		return
-	}
-
-	// Verify store belongs to tenant
-	_, err = cfg.db.GetStoreByTenantAndID(r.Context(), database.GetStoreByTenantAndIDParams{
-		TenantID: uuid.NullUUID{UUID: tenantID, Valid: true},
-		ID:       storeID,
-	})
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			respondWithError(w, http.StatusNotFound, "Store not found in this tenant", nil)
-			return
-		}
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify store", err)
-		return
-	}
-
-	// Get existing product first
-	existingProduct, err := cfg.db.GetProductByID(r.Context(), database.GetProductByIDParams{
-		ID:      productID,
-		StoreID: storeID,
-	})
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			respondWithError(w, http.StatusNotFound, "Product not found", nil)
-			return
-		}
-		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product", err)
-		return
-	}
-
 	type parameters struct {
 		Name             *string `json:"name"`
 		Handle           *string `json:"handle"`
@@ -397,6 +289,8 @@ func (cfg *apiConfig) handlerTenantProductUpdate(w http.ResponseWriter, r *http.
 		SKU              *string `json:"sku"`
 		Tags             *string `json:"tags"`
 		Status           *string `json:"status"`
+		OversellPolicy   *string `json:"oversell_policy"`
+		Version          *int64  `json:"version"`
 	}
 
 	decoder := json.NewDecoder(r.Body)
@@ -407,59 +301,38 @@ func (cfg *apiConfig) handlerTenantProductUpdate(w http.ResponseWriter, r *http.
 		return
 	}
 
-	// Use existing values if not provided
-	name := existingProduct.Name
-	if params.Name != nil {
-		name = *params.Name
-	}
-
-	handle := existingProduct.Handle
-	if params.Handle != nil {
-		handle = *params.Handle
-	}
-
-	description := existingProduct.Description
-	if params.Description != nil {
-		description = sql.NullString{String: *params.Description, Valid: true}
-	}
-
-	inventoryTracked := existingProduct.InventoryTracked
-	if params.InventoryTracked != nil {
-		inventoryTracked = *params.InventoryTracked
-	}
-
-	sku := existingProduct.Sku
-	if params.SKU != nil {
-		sku = sql.NullString{String: *params.SKU, Valid: true}
-	}
-
-	tags := existingProduct.Tags
-	if params.Tags != nil {
-		tags = sql.NullString{String: *params.Tags, Valid: true}
+	version, err := requestVersion(r, params.Version)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
 	}
 
-	status := existingProduct.Status
-	if params.Status != nil {
-		status = *params.Status
+	// The audit log records what the update changed
+	existing, err := cfg.services.products.Get(r.Context(), storeID, productID)
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to update product")
+		return
 	}
 
-	product, err := cfg.db.UpdateProduct(r.Context(), database.UpdateProductParams{
-		ID:               productID,
+	product, err := cfg.services.products.Update(r.Context(), products.UpdateInput{
 		StoreID:          storeID,
-		Handle:           handle,
-		Name:             name,
-		Description:      description,
-		InventoryTracked: inventoryTracked,
-		Sku:              sku,
-		Tags:             tags,
-		Status:           status,
+		ID:               productID,
+		Name:             params.Name,
+		Handle:           params.Handle,
+		Description:      params.Description,
+		InventoryTracked: params.InventoryTracked,
+		SKU:              params.SKU,
+		Tags:             params.Tags,
+		Status:           params.Status,
+		OversellPolicy:   params.OversellPolicy,
+		Version:          version,
 	})
 	if err != nil {
-		slog.ErrorContext(r.Context(), "tenant product update failed: database error",
+		slog.ErrorContext(r.Context(), "tenant product update failed",
 			"request_id", reqID,
 			"error", err,
 		)
-		respondWithError(w, http.StatusInternalServerError, "Unable to update product", err)
+		respondWithServiceError(w, err, "Unable to update product")
 		return
 	}
 
@@ -471,30 +344,130 @@ func (cfg *apiConfig) handlerTenantProductUpdate(w http.ResponseWriter, r *http.
 		"product_id", product.ID,
 	)
 
-	var desc, skuPtr, tagsPtr *string
-	if product.Description.Valid {
-		desc = &product.Description.String
+	cfg.syncProductCollectionsAfterWrite(r.Context(), product.StoreID, product.ID)
+	auditChange(r, auditGID(gid.EntityProduct, product.Gid), tenantProductToResponse(existing), tenantProductToResponse(product))
+
+	media, err := cfg.productMedia(r.Context(), []uuid.UUID{product.ID})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product media", err)
+		return
 	}
-	if product.Sku.Valid {
-		skuPtr = &product.Sku.String
+
+	resp := tenantProductToResponse(product)
+	resp.Media = media[product.ID]
+	setVersionETag(w, product.Version)
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerTenantProductSetStatus returns a handler that moves a product to
+// status: publish, unpublish and archive are each one of these
+func (cfg *apiConfig) handlerTenantProductSetStatus(status string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqID := middleware.GetRequestID(r.Context())
+		access := tenantAccessFrom(r)
+
+		productID, err := uuid.Parse(chi.URLParam(r, "productID"))
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid product ID format", err)
+			return
+		}
+
+		existing, err := cfg.services.products.Get(r.Context(), access.StoreID, productID)
+		if err != nil {
+			respondWithServiceError(w, err, "Unable to change product status")
+			return
+		}
+
+		product, err := cfg.services.products.SetStatus(r.Context(), access.StoreID, productID, status)
+		if err != nil {
+			slog.WarnContext(r.Context(), "tenant product status change failed",
+				"request_id", reqID,
+				"store_id", access.StoreID,
+				"product_id", productID,
+				"status", status,
+				"error", err,
+			)
+			respondWithServiceError(w, err, "Unable to change product status")
+			return
+		}
+
+		slog.InfoContext(r.Context(), "tenant product status changed",
+			"request_id", reqID,
+			"user_id", access.UserID,
+			"tenant_id", access.TenantID,
+			"store_id", access.StoreID,
+			"product_id", product.ID,
+			"status", product.Status,
+		)
+
+		// Automated collections can match on status
+		cfg.syncProductCollectionsAfterWrite(r.Context(), product.StoreID, product.ID)
+		auditChange(r, auditGID(gid.EntityProduct, product.Gid), tenantProductToResponse(existing), tenantProductToResponse(product))
+
+		media, err := cfg.productMedia(r.Context(), []uuid.UUID{product.ID})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product media", err)
+			return
+		}
+
+		resp := tenantProductToResponse(product)
+		resp.Media = media[product.ID]
+		setVersionETag(w, product.Version)
+		respondWithJSON(w, http.StatusOK, resp)
 	}
-	if product.Tags.Valid {
-		tagsPtr = &product.Tags.String
+}
+
+// HandleAvailabilityResponse answers a handle availability check.
+// Suggestion is a free alternative when the handle is taken.
+type HandleAvailabilityResponse struct {
+	Handle     string `json:"handle"`
+	Available  bool   `json:"available"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// handlerTenantProductHandleAvailability checks whether ?handle= is free in
+// the store, or with ?name= the handle a product of that name would get
+func (cfg *apiConfig) handlerTenantProductHandleAvailability(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+	query := r.URL.Query()
+
+	availability, err := cfg.services.products.CheckHandle(r.Context(), storeID, query.Get("handle"), query.Get("name"))
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to check handle")
+		return
 	}
 
-	respondWithJSON(w, http.StatusOK, TenantProductResponse{
+	respondWithJSON(w, http.StatusOK, HandleAvailabilityResponse(availability))
+}
+
+// tenantProductToResponse maps the product's own columns; inventory and
+// media are left for the caller
+func tenantProductToResponse(product database.Product) TenantProductResponse {
+	resp := TenantProductResponse{
 		ID:               product.ID,
 		StoreID:          product.StoreID,
 		Handle:           product.Handle,
 		Name:             product.Name,
-		Description:      desc,
 		InventoryTracked: product.InventoryTracked,
-		SKU:              skuPtr,
-		Tags:             tagsPtr,
+		OversellPolicy:   product.OversellPolicy,
 		Status:           product.Status,
 		CreatedAt:        product.CreatedAt,
 		UpdatedAt:        product.UpdatedAt,
-	})
+		Version:          product.Version,
+	}
+	if product.Description.Valid {
+		resp.Description = &product.Description.String
+	}
+	if product.Sku.Valid {
+		resp.SKU = &product.Sku.String
+	}
+	if product.Tags.Valid {
+		resp.Tags = &product.Tags.String
+	}
+	if product.DeletedAt.Valid {
+		resp.DeletedAt = &product.DeletedAt.Time
+	}
+	return resp
 }
 
 // handlerTenantProductDelete deletes a product
@@ -511,23 +484,8 @@ func (cfg *apiConfig) handlerTenantProductDelete(w http.ResponseWriter, r *http.
 		"product_param", productParam,
 	)
 
-	user, ok := userFromContext(r.Context())
-	if !ok {
-		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
-		return
-	}
-
-	tenantID, err := uuid.Parse(tenantParam)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid tenant ID format", err)
-		return
-	}
-
-	storeID, err := uuid.Parse(storeParam)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid store ID format", err)
-		return
-	}
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
 
 	productID, err := uuid.Parse(productParam)
 	if err != nil {
@@ -535,51 +493,14 @@ func (cfg *apiConfig) handlerTenantProductDelete(w http.ResponseWriter, r *http.
 		return
 	}
 
-	// Verify user has permission to delete products
-	hasPermission, err := cfg.db.CheckUserHasPermission(r.Context(), database.CheckUserHasPermissionParams{
-		TenantID: tenantID,
-		UserID:   user,
-		Key:      "products:delete",
-	})
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify permissions", err)
-		return
-	}
-
-	if !hasPermission {
-		respondWithError(w, http.StatusForbidden, "You do not have permission to delete products", nil)
-		return
-	}
-
-	// Verify store belongs to tenant
-	_, err = cfg.db.GetStoreByTenantAndID(r.Context(), database.GetStoreByTenantAndIDParams{
-		TenantID: uuid.NullUUID{UUID: tenantID, Valid: true},
-		ID:       storeID,
-	})
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			respondWithError(w, http.StatusNotFound, "Store not found in this tenant", nil)
-			return
-		}
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify store", err)
-		return
-	}
-
 	// Delete the product (variants will be deleted via CASCADE)
-	deletedProduct, err := cfg.db.DeleteProduct(r.Context(), database.DeleteProductParams{
-		ID:      productID,
-		StoreID: storeID,
-	})
+	deletedProduct, err := cfg.services.products.Delete(r.Context(), storeID, productID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			respondWithError(w, http.StatusNotFound, "Product not found", nil)
-			return
-		}
-		slog.ErrorContext(r.Context(), "tenant product delete failed: database error",
+		slog.ErrorContext(r.Context(), "tenant product delete failed",
 			"request_id", reqID,
 			"error", err,
 		)
-		respondWithError(w, http.StatusInternalServerError, "Unable to delete product", err)
+		respondWithServiceError(w, err, "Unable to delete product")
 		return
 	}
 
@@ -591,6 +512,8 @@ func (cfg *apiConfig) handlerTenantProductDelete(w http.ResponseWriter, r *http.
 		"product_id", deletedProduct.ID,
 	)
 
+	auditChange(r, auditGID(gid.EntityProduct, deletedProduct.Gid), tenantProductToResponse(deletedProduct), nil)
+
 	respondWithJSON(w, http.StatusOK, map[string]any{
 		"message":    "Product deleted successfully",
 		"product_id": deletedProduct.ID,
@@ -609,142 +532,50 @@ func (cfg *apiConfig) handlerTenantProductsList(w http.ResponseWriter, r *http.R
 		"store_param", storeParam,
 	)
 
-	user, ok := userFromContext(r.Context())
-	if !ok {
-		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
-		return
-	}
-
-	tenantID, err := uuid.Parse(tenantParam)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid tenant ID format", err)
-		return
-	}
-
-	storeID, err := uuid.Parse(storeParam)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid store ID format", err)
-		return
-	}
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
 
-	// Verify user has permission to view products
-	hasPermission, err := cfg.db.CheckUserHasPermission(r.Context(), database.CheckUserHasPermissionParams{
-		TenantID: tenantID,
-		UserID:   user,
-		Key:      "products:view",
-	})
+	pageParams, err := ParsePageParams(r, 50, 100)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify permissions", err)
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
 		return
 	}
 
-	if !hasPermission {
-		respondWithError(w, http.StatusForbidden, "You do not have permission to view products", nil)
+	filter, ok := parseProductFilter(w, r)
+	if !ok {
 		return
 	}
 
-	// Verify store belongs to tenant
-	_, err = cfg.db.GetStoreByTenantAndID(r.Context(), database.GetStoreByTenantAndIDParams{
-		TenantID: uuid.NullUUID{UUID: tenantID, Valid: true},
-		ID:       storeID,
-	})
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			respondWithError(w, http.StatusNotFound, "Store not found in this tenant", nil)
+	// A search query switches to relevance ordering with its own cursor
+	if query := strings.TrimSpace(r.URL.Query().Get("q")); query != "" {
+		if !filter.IsZero() {
+			respondWithError(w, http.StatusBadRequest, "Filters cannot be combined with a search query", nil)
 			return
 		}
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify store", err)
+		cfg.respondWithProductSearch(w, r, storeID, query, pageParams)
 		return
 	}
 
-	pageParams, err := ParsePageParams(r, 50, 100)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
-		return
-	}
-
-	limit := pageParams.Limit
-	limitPlusOne := int32(pageParams.Limit + 1)
-
-	cursorCreatedAt, cursorID, hasCursor, err := cursorInfo(pageParams.Cursor)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+	products, page, ok := cfg.listFilteredProducts(w, r, storeID, filter, pageParams)
+	if !ok {
 		return
 	}
 
-	rows, err := cfg.db.GetProductsByStorePaginated(r.Context(), database.GetProductsByStorePaginatedParams{
-		StoreID: storeID,
-		Column2: hasCursor,
-		Column3: cursorCreatedAt,
-		Column4: cursorID,
-		Limit:   limitPlusOne,
-	})
+	response, err := cfg.tenantProductsToResponse(r.Context(), products)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve products", err)
 		return
 	}
 
-	hasMore := len(rows) > limit
-	if hasMore {
-		rows = rows[:limit]
-	}
-
-	var nextCursor string
-	if hasMore && len(rows) > 0 {
-		last := rows[len(rows)-1]
-		nextCursor, err = productCursorCodec.Encode(ProductCursor{
-			CreatedAt: last.CreatedAt,
-			ID:        last.ID,
-		})
-		if err != nil {
-			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
-			return
-		}
-	}
-
-	response := make([]TenantProductResponse, 0, len(rows))
-	for _, product := range rows {
-		var desc, skuPtr, tagsPtr *string
-		if product.Description.Valid {
-			desc = &product.Description.String
-		}
-		if product.Sku.Valid {
-			skuPtr = &product.Sku.String
-		}
-		if product.Tags.Valid {
-			tagsPtr = &product.Tags.String
-		}
-
-		response = append(response, TenantProductResponse{
-			ID:               product.ID,
-			StoreID:          product.StoreID,
-			Handle:           product.Handle,
-			Name:             product.Name,
-			Description:      desc,
-			InventoryTracked: product.InventoryTracked,
-			SKU:              skuPtr,
-			Tags:             tagsPtr,
-			Status:           product.Status,
-			CreatedAt:        product.CreatedAt,
-			UpdatedAt:        product.UpdatedAt,
-		})
-	}
-
 	slog.InfoContext(r.Context(), "tenant products list successful",
 		"request_id", reqID,
 		"user_id", user,
 		"tenant_id", tenantID,
 		"store_id", storeID,
 		"product_count", len(response),
-		"has_more", hasMore,
+		"filtered", !filter.IsZero(),
+		"has_more", page.HasMore,
 	)
 
-	respondWithJSON(w, http.StatusOK, map[string]any{
-		"data": response,
-		"page": map[string]any{
-			"limit":       limit,
-			"has_more":    hasMore,
-			"next_cursor": nextCursor,
-		},
-	})
+	respondWithJSON(w, http.StatusOK, PageResponse[TenantProductResponse]{Data: response, Page: page})
 }
@@ -0,0 +1,523 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/bundles"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/inventory"
+	"github.com/dfodeker/terminus/internal/mailer"
+	"github.com/dfodeker/terminus/internal/orders"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/refund"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type RefundResponse struct {
+	ID                    uuid.UUID                `json:"id"`
+	OrderID               uuid.UUID                `json:"order_id"`
+	AmountCents           int64                    `json:"amount_cents"`
+	Currency              string                   `json:"currency"`
+	Reason                *string                  `json:"reason,omitempty"`
+	Note                  *string                  `json:"note,omitempty"`
+	Provider              string                   `json:"provider"`
+	ProviderTransactionID *string                  `json:"provider_transaction_id,omitempty"`
+	LineItems             []RefundLineItemResponse `json:"line_items"`
+	CreatedBy             *uuid.UUID               `json:"created_by,omitempty"`
+	CreatedAt             time.Time                `json:"created_at"`
+}
+
+type RefundLineItemResponse struct {
+	LineItemID  uuid.UUID  `json:"line_item_id"`
+	Quantity    int32      `json:"quantity"`
+	AmountCents int64      `json:"amount_cents"`
+	Restocked   bool       `json:"restocked"`
+	LocationID  *uuid.UUID `json:"location_id,omitempty"`
+}
+
+// handlerTenantRefundsCreate refunds all or part of a paid order. Items can
+// be restocked as they are returned; the provider fields record the
+// payment provider's refund so payouts can be reconciled.
+func (cfg *apiConfig) handlerTenantRefundsCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "orderID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid order ID format", err)
+		return
+	}
+
+	type item struct {
+		LineItemID uuid.UUID `json:"line_item_id"`
+		Quantity   int64     `json:"quantity"`
+		Restock    bool      `json:"restock"`
+	}
+	type parameters struct {
+		Items []item `json:"items"`
+		// AmountCents overrides the amount worked out from items; omit both
+		// items and amount for a full refund
+		AmountCents *int64 `json:"amount_cents"`
+		// Restock applies to every item of a full refund
+		Restock               bool    `json:"restock"`
+		Reason                *string `json:"reason"`
+		Note                  *string `json:"note"`
+		Provider              string  `json:"provider"`
+		ProviderTransactionID *string `json:"provider_transaction_id"`
+		// LocationID receives restocked items; defaults to the tenant's default location
+		LocationID *uuid.UUID `json:"location_id"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	provider := strings.ToLower(strings.TrimSpace(params.Provider))
+	if provider == "" {
+		provider = "manual"
+	}
+	transactionID := nullStringFromPtr(params.ProviderTransactionID)
+	if transactionID.Valid {
+		transactionID.String = strings.TrimSpace(transactionID.String)
+		if transactionID.String == "" {
+			respondWithError(w, http.StatusBadRequest, "Provider transaction ID must not be blank", nil)
+			return
+		}
+	}
+
+	var order database.Order
+	var rf database.Refund
+	var lineItems []RefundLineItemResponse
+	var state refund.Order
+	err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		var err error
+
+		// Lock the order so concurrent refunds can't refund more than was paid
+		order, err = q.LockOrderForUpdate(r.Context(), database.LockOrderForUpdateParams{
+			ID:      orderID,
+			StoreID: storeID,
+		})
+		if err != nil {
+			return service.NotFoundAs(err, "Order not found")
+		}
+		if !refund.Refundable(order.FinancialStatus) {
+			return service.Conflict("Only paid orders can be refunded")
+		}
+
+		if transactionID.Valid {
+			_, err = q.GetRefundByProviderTransaction(r.Context(), database.GetRefundByProviderTransactionParams{
+				StoreID:               storeID,
+				Provider:              provider,
+				ProviderTransactionID: transactionID,
+			})
+			if err == nil {
+				return &service.Error{Kind: service.ErrConflict, Code: problem.CodeAlreadyExists, Message: "A refund for this provider transaction has already been recorded"}
+			}
+			if !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+		}
+
+		rows, err := q.GetOrderLineItemsForRefund(r.Context(), order.ID)
+		if err != nil {
+			return err
+		}
+		refunded, err := q.SumOrderRefunds(r.Context(), order.ID)
+		if err != nil {
+			return err
+		}
+
+		// Component lines carry no money; a bundle is refunded by its own line
+		// and its components are restocked with it
+		lines := make([]refund.Line, 0, len(rows))
+		byID := make(map[uuid.UUID]database.GetOrderLineItemsForRefundRow, len(rows))
+		components := make(map[uuid.UUID][]database.GetOrderLineItemsForRefundRow)
+		for _, row := range rows {
+			if row.ParentLineItemID.Valid {
+				components[row.ParentLineItemID.UUID] = append(components[row.ParentLineItemID.UUID], row)
+				continue
+			}
+			lines = append(lines, refund.Line{
+				ID:               row.ID,
+				Quantity:         int64(row.Quantity),
+				TotalCents:       row.TotalCents,
+				RefundedQuantity: row.RefundedQuantity,
+				RefundedCents:    row.RefundedCents,
+			})
+			byID[row.ID] = row
+		}
+
+		requested := make([]refund.Item, 0, len(params.Items))
+		for _, it := range params.Items {
+			requested = append(requested, refund.Item{LineItemID: it.LineItemID, Quantity: it.Quantity, Restock: it.Restock})
+		}
+
+		state = refund.Order{TotalCents: order.TotalCents, RefundedCents: refunded}
+		plan, err := refund.Build(state, lines, requested, params.AmountCents, params.Restock)
+		if err != nil {
+			return abortTx(http.StatusUnprocessableEntity, err.Error(), err)
+		}
+
+		// Untracked products and deleted variants have no stock to return to
+		stocked := func(row database.GetOrderLineItemsForRefundRow) bool {
+			return row.VariantID.Valid && row.InventoryTracked
+		}
+		restockable := func(row database.GetOrderLineItemsForRefundRow) bool {
+			if parts, ok := components[row.ID]; ok {
+				return slices.ContainsFunc(parts, stocked)
+			}
+			return stocked(row)
+		}
+
+		// Resolve where returned stock goes only if something is restocked
+		var location database.InventoryLocation
+		for _, it := range plan.Items {
+			if !it.Restock || !restockable(byID[it.LineItemID]) {
+				continue
+			}
+			location, err = resolveRestockLocation(r.Context(), q, tenantID, params.LocationID)
+			if err != nil {
+				return err
+			}
+			break
+		}
+
+		rf, err = q.CreateRefund(r.Context(), database.CreateRefundParams{
+			Gid:                   sql.NullInt64{Int64: int64(cfg.gidGen.Generate()), Valid: true},
+			StoreID:               storeID,
+			OrderID:               order.ID,
+			AmountCents:           plan.AmountCents,
+			Currency:              order.Currency,
+			Reason:                nullStringFromPtr(params.Reason),
+			Note:                  nullStringFromPtr(params.Note),
+			Provider:              provider,
+			ProviderTransactionID: transactionID,
+			CreatedBy:             uuid.NullUUID{UUID: user, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		lineItems = make([]RefundLineItemResponse, 0, len(plan.Items))
+		for _, it := range plan.Items {
+			row := byID[it.LineItemID]
+
+			restocked := it.Restock && restockable(row)
+			locationID := uuid.NullUUID{}
+			if restocked {
+				restock := []restockParams{{VariantID: row.VariantID.UUID, Quantity: int32(it.Quantity)}}
+				if parts, ok := components[row.ID]; ok {
+					restock = restock[:0]
+					for _, part := range parts {
+						if stocked(part) {
+							perBundle := bundles.PerBundle(part.Quantity, row.Quantity)
+							restock = append(restock, restockParams{VariantID: part.VariantID.UUID, Quantity: perBundle * int32(it.Quantity)})
+						}
+					}
+				}
+				for _, p := range restock {
+					p.TenantID, p.StoreID, p.LocationID, p.UserID = tenantID, storeID, location.ID, user
+					p.Note = "Refund " + rf.ID.String()
+					if err := restockVariant(r.Context(), q, p); err != nil {
+						return err
+					}
+				}
+				locationID = uuid.NullUUID{UUID: location.ID, Valid: true}
+			}
+
+			err = q.CreateRefundLineItem(r.Context(), database.CreateRefundLineItemParams{
+				RefundID:        rf.ID,
+				OrderLineItemID: it.LineItemID,
+				Quantity:        int32(it.Quantity),
+				AmountCents:     it.AmountCents,
+				Restocked:       restocked,
+				LocationID:      locationID,
+			})
+			if err != nil {
+				return err
+			}
+			lineItems = append(lineItems, refundLineItemToResponse(database.RefundLineItem{
+				RefundID:        rf.ID,
+				OrderLineItemID: it.LineItemID,
+				Quantity:        int32(it.Quantity),
+				AmountCents:     it.AmountCents,
+				Restocked:       restocked,
+				LocationID:      locationID,
+			}))
+		}
+
+		message := fmt.Sprintf("Refunded %d %s", rf.AmountCents, rf.Currency)
+		if rf.AmountCents == 0 {
+			message = "Items returned without a refund"
+		}
+		data := map[string]any{
+			"refund_id":    rf.ID,
+			"amount_cents": rf.AmountCents,
+			"provider":     rf.Provider,
+			"line_items":   lineItems,
+		}
+		if rf.ProviderTransactionID.Valid {
+			data["provider_transaction_id"] = rf.ProviderTransactionID.String
+		}
+		_, err = recordOrderEvent(r.Context(), q, order, orders.EventRefund, message, data, user)
+		if err != nil {
+			return err
+		}
+
+		// A return without money back leaves the financial status alone, and
+		// the customer gets no refund email
+		if plan.AmountCents > 0 {
+			err = cfg.queueOrderEmail(r.Context(), q, mailer.OrderEmailArgs{
+				Template: mailer.TemplateOrderRefunded,
+				OrderID:  order.ID,
+				StoreID:  storeID,
+				RefundID: rf.ID,
+			})
+			if err != nil {
+				return err
+			}
+
+			state.RefundedCents += plan.AmountCents
+			order, err = q.UpdateOrderFinancialStatus(r.Context(), database.UpdateOrderFinancialStatusParams{
+				ID:              order.ID,
+				StoreID:         storeID,
+				FinancialStatus: refund.FinancialStatus(state),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		respondWithTxError(w, err, "Unable to create refund")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant refund created",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"order_id", order.ID,
+		"refund_id", rf.ID,
+		"amount_cents", rf.AmountCents,
+		"provider", provider,
+		"financial_status", order.FinancialStatus,
+	)
+
+	respondWithJSON(w, http.StatusCreated, map[string]any{
+		"refund":           refundToResponse(rf, lineItems),
+		"financial_status": order.FinancialStatus,
+		"refunded_cents":   state.RefundedCents,
+	})
+}
+
+// handlerTenantRefundsList lists an order's refunds, oldest first
+func (cfg *apiConfig) handlerTenantRefundsList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	order, ok := cfg.loadStoreOrder(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	refunds, err := cfg.db.GetRefundsByOrder(r.Context(), order.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve refunds", err)
+		return
+	}
+
+	items, err := cfg.db.GetRefundLineItemsByOrder(r.Context(), order.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve refund line items", err)
+		return
+	}
+	byRefund := make(map[uuid.UUID][]RefundLineItemResponse, len(refunds))
+	for _, it := range items {
+		byRefund[it.RefundID] = append(byRefund[it.RefundID], refundLineItemToResponse(it))
+	}
+
+	var total int64
+	resp := make([]RefundResponse, 0, len(refunds))
+	for _, rf := range refunds {
+		total += rf.AmountCents
+		resp = append(resp, refundToResponse(rf, byRefund[rf.ID]))
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{
+		"data":           resp,
+		"refunded_cents": total,
+	})
+}
+
+// resolveRestockLocation loads the requested location, or the tenant's
+// default one, for returned stock
+func resolveRestockLocation(ctx context.Context, q *database.Queries, tenantID uuid.UUID, locationID *uuid.UUID) (database.InventoryLocation, error) {
+	if locationID == nil {
+		return q.EnsureDefaultInventoryLocation(ctx, tenantID)
+	}
+
+	location, err := q.GetInventoryLocationByID(ctx, database.GetInventoryLocationByIDParams{
+		ID:       *locationID,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		return database.InventoryLocation{}, service.NotFoundAs(err, "Location not found")
+	}
+	if !location.Active {
+		return database.InventoryLocation{}, service.Conflict("Location is inactive")
+	}
+	return location, nil
+}
+
+type restockParams struct {
+	TenantID   uuid.UUID
+	StoreID    uuid.UUID
+	VariantID  uuid.UUID
+	LocationID uuid.UUID
+	Quantity   int32
+	Note       string
+	UserID     uuid.UUID
+}
+
+// restockVariant puts returned units back at a location and records the
+// movement, the same way a manual adjustment does
+func restockVariant(ctx context.Context, q *database.Queries, p restockParams) error {
+	_, _, err := changeStock(ctx, q, stockChange{
+		TenantID:   p.TenantID,
+		StoreID:    p.StoreID,
+		VariantID:  p.VariantID,
+		LocationID: p.LocationID,
+		Delta:      p.Quantity,
+		Reason:     inventory.ReasonReturned,
+		Note:       p.Note,
+		UserID:     p.UserID,
+	})
+	return err
+}
+
+// stockChange is a change to a variant's stock at one location
+type stockChange struct {
+	TenantID   uuid.UUID
+	StoreID    uuid.UUID
+	VariantID  uuid.UUID
+	LocationID uuid.UUID
+	Delta      int32
+	Reason     inventory.Reason
+	Note       string
+	UserID     uuid.UUID
+}
+
+// changeStock applies c to the location's level and the item's total and
+// records the movement. It fails with inventory.ErrInsufficientStock when
+// the location would go below zero.
+func changeStock(ctx context.Context, q *database.Queries, c stockChange) (database.InventoryLevel, database.InventoryMovement, error) {
+	item, err := q.EnsureInventoryItem(ctx, database.EnsureInventoryItemParams{
+		TenantID:  c.TenantID,
+		StoreID:   c.StoreID,
+		VariantID: c.VariantID,
+	})
+	if err != nil {
+		return database.InventoryLevel{}, database.InventoryMovement{}, err
+	}
+
+	level, err := q.EnsureInventoryLevel(ctx, database.EnsureInventoryLevelParams{
+		TenantID:        c.TenantID,
+		InventoryItemID: item.ID,
+		LocationID:      c.LocationID,
+	})
+	if err != nil {
+		return database.InventoryLevel{}, database.InventoryMovement{}, err
+	}
+
+	level, err = q.AdjustInventoryLevel(ctx, database.AdjustInventoryLevelParams{
+		Delta: c.Delta,
+		ID:    level.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.InventoryLevel{}, database.InventoryMovement{}, inventory.ErrInsufficientStock
+	}
+	if err != nil {
+		return database.InventoryLevel{}, database.InventoryMovement{}, err
+	}
+
+	_, err = q.AdjustInventoryItem(ctx, database.AdjustInventoryItemParams{
+		Delta: c.Delta,
+		ID:    item.ID,
+	})
+	if err != nil {
+		return database.InventoryLevel{}, database.InventoryMovement{}, err
+	}
+
+	movement, err := q.CreateInventoryMovement(ctx, database.CreateInventoryMovementParams{
+		TenantID:        c.TenantID,
+		InventoryItemID: item.ID,
+		LocationID:      uuid.NullUUID{UUID: c.LocationID, Valid: true},
+		Delta:           c.Delta,
+		QuantityAfter:   level.OnHand,
+		Reason:          string(c.Reason),
+		Note:            sql.NullString{String: c.Note, Valid: c.Note != ""},
+		CreatedBy:       uuid.NullUUID{UUID: c.UserID, Valid: true},
+	})
+	return level, movement, err
+}
+
+func refundToResponse(rf database.Refund, items []RefundLineItemResponse) RefundResponse {
+	resp := RefundResponse{
+		ID:          rf.ID,
+		OrderID:     rf.OrderID,
+		AmountCents: rf.AmountCents,
+		Currency:    rf.Currency,
+		Provider:    rf.Provider,
+		LineItems:   items,
+		CreatedAt:   rf.CreatedAt,
+	}
+	if resp.LineItems == nil {
+		resp.LineItems = []RefundLineItemResponse{}
+	}
+	if rf.Reason.Valid {
+		resp.Reason = &rf.Reason.String
+	}
+	if rf.Note.Valid {
+		resp.Note = &rf.Note.String
+	}
+	if rf.ProviderTransactionID.Valid {
+		resp.ProviderTransactionID = &rf.ProviderTransactionID.String
+	}
+	if rf.CreatedBy.Valid {
+		resp.CreatedBy = &rf.CreatedBy.UUID
+	}
+	return resp
+}
+
+func refundLineItemToResponse(it database.RefundLineItem) RefundLineItemResponse {
+	resp := RefundLineItemResponse{
+		LineItemID:  it.OrderLineItemID,
+		Quantity:    it.Quantity,
+		AmountCents: it.AmountCents,
+		Restocked:   it.Restocked,
+	}
+	if it.LocationID.Valid {
+		resp.LocationID = &it.LocationID.UUID
+	}
+	return resp
+}
@@ -0,0 +1,391 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/inventory"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// ReservationResponse is stock held for a checkout
+type ReservationResponse struct {
+	ID     uuid.UUID `json:"id"`
+	Status string    `json:"status"`
+	// CustomerID is unset for guest checkouts
+	CustomerID  *uuid.UUID                `json:"customer_id,omitempty"`
+	OrderID     *uuid.UUID                `json:"order_id,omitempty"`
+	Items       []ReservationItemResponse `json:"items"`
+	ExpiresAt   time.Time                 `json:"expires_at"`
+	CommittedAt *time.Time                `json:"committed_at,omitempty"`
+	CreatedAt   time.Time                 `json:"created_at"`
+	UpdatedAt   time.Time                 `json:"updated_at"`
+}
+
+// ReservationItemResponse is a variant in a reservation. Bundles are held
+// as their components, so each component is listed instead.
+type ReservationItemResponse struct {
+	VariantID uuid.UUID `json:"variant_id"`
+	Quantity  int32     `json:"quantity"`
+	// Held is what the item takes from stock, and Backordered is the
+	// shortfall recorded under a backorder policy
+	Held        int32 `json:"held"`
+	Backordered int32 `json:"backordered"`
+}
+
+type ReservationCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+}
+
+var reservationCursorCodec = CursorCodec[ReservationCursor]{
+	Validate: func(c ReservationCursor) error {
+		if c.CreatedAt.IsZero() || c.ID == uuid.Nil {
+			return errors.New("invalid cursor: missing required fields")
+		}
+		return nil
+	},
+}
+
+// handlerTenantReservationsList lists a store's reservations, newest
+// first, filtered by ?status=
+func (cfg *apiConfig) handlerTenantReservationsList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	status := r.URL.Query().Get("status")
+	var v validate.Validator
+	if status != "" {
+		v.OneOf("status", status, inventory.ReservationActive, inventory.ReservationCommitted, inventory.ReservationReleased, inventory.ReservationExpired)
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+	limit := pageParams.Limit
+
+	cursor, hasCursor, err := reservationCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.db.ListInventoryReservationsPaginated(r.Context(), database.ListInventoryReservationsPaginatedParams{
+		StoreID:         storeID,
+		Status:          sql.NullString{String: status, Valid: status != ""},
+		HasCursor:       hasCursor,
+		CursorCreatedAt: cursor.CreatedAt,
+		CursorID:        cursor.ID,
+		RowLimit:        int32(limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve reservations", err)
+		return
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = reservationCursorCodec.Encode(ReservationCursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]ReservationResponse, 0, len(rows))
+	for _, reservation := range rows {
+		items, err := cfg.db.GetInventoryReservationItems(r.Context(), reservation.ID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve reservation items", err)
+			return
+		}
+		response = append(response, reservationToResponse(reservation, items))
+	}
+
+	respondWithJSON(w, http.StatusOK, PageResponse[ReservationResponse]{
+		Data: response,
+		Page: PageInfo{Limit: limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
+
+// handlerTenantReservationGet returns a reservation with its items
+func (cfg *apiConfig) handlerTenantReservationGet(w http.ResponseWriter, r *http.Request) {
+	reservation, ok := cfg.loadReservation(w, r, tenantAccessFrom(r).StoreID, chi.URLParam(r, "reservationID"))
+	if !ok {
+		return
+	}
+	items, err := cfg.db.GetInventoryReservationItems(r.Context(), reservation.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve reservation items", err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, reservationToResponse(reservation, items))
+}
+
+// handlerTenantReservationCommit records the held stock of an active
+// reservation as sold, once the checkout it was made for is paid. The
+// stock comes off the default location first, then the fullest ones.
+func (cfg *apiConfig) handlerTenantReservationCommit(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	existing, ok := cfg.loadReservation(w, r, access.StoreID, chi.URLParam(r, "reservationID"))
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		// OrderID is the order the reservation was sold through
+		OrderID *uuid.UUID `json:"order_id"`
+	}
+
+	// The body is optional
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil && !errors.Is(err, io.EOF) {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	orderID := uuid.NullUUID{}
+	if params.OrderID != nil {
+		order, err := cfg.db.GetOrderByID(r.Context(), database.GetOrderByIDParams{
+			ID:      *params.OrderID,
+			StoreID: access.StoreID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Order not found", nil)
+			return
+		}
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve order", err)
+			return
+		}
+		orderID = uuid.NullUUID{UUID: order.ID, Valid: true}
+	}
+
+	var reservation database.InventoryReservation
+	var items []database.InventoryReservationItem
+	err := cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		var err error
+		reservation, err = q.CloseInventoryReservation(r.Context(), database.CloseInventoryReservationParams{
+			Status:  inventory.ReservationCommitted,
+			OrderID: orderID,
+			ID:      existing.ID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return inventory.ErrReservationNotActive
+		}
+		if err != nil {
+			return err
+		}
+
+		items, err = q.GetInventoryReservationItems(r.Context(), reservation.ID)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := sellReservedStock(r.Context(), q, reservation, item, access.UserID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, inventory.ErrReservationNotActive) {
+		respondWithError(w, http.StatusConflict, "Reservation is no longer active", err)
+		return
+	}
+	if errors.Is(err, inventory.ErrInsufficientStock) {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeInsufficientStock, "Stock was adjusted below what the reservation holds", err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to commit reservation", err)
+		return
+	}
+
+	auditChange(r, auditGID(gid.EntityReservation, reservation.Gid), reservationToResponse(existing, items), reservationToResponse(reservation, items))
+	slog.InfoContext(r.Context(), "inventory reservation committed",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"store_id", access.StoreID,
+		"reservation_id", reservation.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, reservationToResponse(reservation, items))
+}
+
+// handlerTenantReservationRelease gives back the stock an active
+// reservation holds, such as when its checkout was abandoned
+func (cfg *apiConfig) handlerTenantReservationRelease(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	existing, ok := cfg.loadReservation(w, r, access.StoreID, chi.URLParam(r, "reservationID"))
+	if !ok {
+		return
+	}
+
+	reservation, items, ok := cfg.releaseReservation(w, r, existing)
+	if !ok {
+		return
+	}
+
+	auditChange(r, auditGID(gid.EntityReservation, reservation.Gid), reservationToResponse(existing, items), reservationToResponse(reservation, items))
+	slog.InfoContext(r.Context(), "inventory reservation released",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"store_id", access.StoreID,
+		"reservation_id", reservation.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, reservationToResponse(reservation, items))
+}
+
+// sellReservedStock takes what item holds off the shelves as sold and
+// stops holding it
+func sellReservedStock(ctx context.Context, q *database.Queries, reservation database.InventoryReservation, item database.InventoryReservationItem, userID uuid.UUID) error {
+	if item.Held == 0 {
+		return nil
+	}
+
+	levels, err := q.GetSellableInventoryLevels(ctx, item.InventoryItemID)
+	if err != nil {
+		return err
+	}
+	stock := make([]int32, len(levels))
+	for i, level := range levels {
+		stock[i] = level.OnHand
+	}
+	taken, err := inventory.Allocate(stock, item.Held)
+	if err != nil {
+		return err
+	}
+
+	for i, n := range taken {
+		if n == 0 {
+			continue
+		}
+		if _, _, err := changeStock(ctx, q, stockChange{
+			TenantID:   reservation.TenantID,
+			StoreID:    reservation.StoreID,
+			VariantID:  item.VariantID,
+			LocationID: levels[i].LocationID,
+			Delta:      -n,
+			Reason:     inventory.ReasonSold,
+			Note:       "Reservation " + reservation.ID.String(),
+			UserID:     userID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return q.UnreserveInventoryItem(ctx, database.UnreserveInventoryItemParams{
+		Held: item.Held,
+		ID:   item.InventoryItemID,
+	})
+}
+
+// releaseReservation releases an active reservation. It has responded
+// when it returns false.
+func (cfg *apiConfig) releaseReservation(w http.ResponseWriter, r *http.Request, existing database.InventoryReservation) (database.InventoryReservation, []database.InventoryReservationItem, bool) {
+	var reservation database.InventoryReservation
+	var items []database.InventoryReservationItem
+	err := cfg.withTenantTx(r.Context(), existing.TenantID, func(q *database.Queries) error {
+		var err error
+		reservation, err = inventory.Release(r.Context(), q, existing.ID, inventory.ReservationReleased)
+		if err != nil {
+			return err
+		}
+		items, err = q.GetInventoryReservationItems(r.Context(), reservation.ID)
+		return err
+	})
+	if errors.Is(err, inventory.ErrReservationNotActive) {
+		respondWithError(w, http.StatusConflict, "Reservation is no longer active", err)
+		return database.InventoryReservation{}, nil, false
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to release reservation", err)
+		return database.InventoryReservation{}, nil, false
+	}
+	return reservation, items, true
+}
+
+// loadReservation loads reservation rawID of storeID. It has responded
+// when it returns false.
+func (cfg *apiConfig) loadReservation(w http.ResponseWriter, r *http.Request, storeID uuid.UUID, rawID string) (database.InventoryReservation, bool) {
+	reservationID, err := uuid.Parse(rawID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid reservation ID format", err)
+		return database.InventoryReservation{}, false
+	}
+	reservation, err := cfg.db.GetInventoryReservationByID(r.Context(), database.GetInventoryReservationByIDParams{
+		ID:      reservationID,
+		StoreID: storeID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Reservation not found", nil)
+		return database.InventoryReservation{}, false
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve reservation", err)
+		return database.InventoryReservation{}, false
+	}
+	return reservation, true
+}
+
+func reservationToResponse(reservation database.InventoryReservation, items []database.InventoryReservationItem) ReservationResponse {
+	resp := ReservationResponse{
+		ID:        reservation.ID,
+		Status:    reservation.Status,
+		Items:     make([]ReservationItemResponse, 0, len(items)),
+		ExpiresAt: reservation.ExpiresAt,
+		CreatedAt: reservation.CreatedAt,
+		UpdatedAt: reservation.UpdatedAt,
+	}
+	if reservation.CustomerID.Valid {
+		resp.CustomerID = &reservation.CustomerID.UUID
+	}
+	if reservation.OrderID.Valid {
+		resp.OrderID = &reservation.OrderID.UUID
+	}
+	if reservation.CommittedAt.Valid {
+		resp.CommittedAt = &reservation.CommittedAt.Time
+	}
+	for _, item := range items {
+		resp.Items = append(resp.Items, ReservationItemResponse{
+			VariantID:   item.VariantID,
+			Quantity:    item.Quantity,
+			Held:        item.Held,
+			Backordered: item.Backordered,
+		})
+	}
+	return resp
+}
@@ -1,14 +1,13 @@
 package main
 
 import (
-	"database/sql"
 	"encoding/json"
 	"errors"
 	"log/slog"
 	"net/http"
 	"time"
 
-	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/service/roles"
 	"github.com/dfodeker/terminus/middleware"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -20,6 +19,8 @@ type RoleResponse struct {
 	Name        string     `json:"name"`
 	Description *string    `json:"description,omitempty"`
 	Permissions []string   `json:"permissions"`
+	// System roles are built in; they can be cloned but not changed
+	System      bool       `json:"system"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
 }
@@ -61,145 +62,113 @@ func (cfg *apiConfig) handlerTenantRolesCreate(w http.ResponseWriter, r *http.Re
 		"tenant_param", tenantParam,
 	)
 
-	user, ok := userFromContext(r.Context())
-	if !ok {
-		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
-		return
+	access := tenantAccessFrom(r)
+	user, tenantID := access.UserID, access.TenantID
+
+	type parameters struct {
+		Name        string   `json:"name"`
+		Description string   `json:"description"`
+		Permissions []string `json:"permissions"` // Permission keys to assign
 	}
 
-	tenantID, err := uuid.Parse(tenantParam)
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
 	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid tenant ID format", err)
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
 		return
 	}
 
-	// Verify requesting user has permission to manage users (which includes role management)
-	hasPermission, err := cfg.db.CheckUserHasPermission(r.Context(), database.CheckUserHasPermissionParams{
-		TenantID: tenantID,
-		UserID:   user,
-		Key:      "tenant:manage_users",
+	role, err := cfg.services.roles.Create(r.Context(), roles.CreateInput{
+		TenantID:    tenantID,
+		Name:        params.Name,
+		Description: params.Description,
+		Permissions: params.Permissions,
 	})
 	if err != nil {
-		slog.ErrorContext(r.Context(), "tenant role creation failed: error checking permissions",
+		slog.ErrorContext(r.Context(), "tenant role creation failed",
 			"request_id", reqID,
+			"user_id", user,
+			"tenant_id", tenantID,
 			"error", err,
 		)
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify permissions", err)
+		respondWithServiceError(w, err, "Unable to create role")
 		return
 	}
 
-	if !hasPermission {
-		slog.WarnContext(r.Context(), "tenant role creation denied: insufficient permissions",
-			"request_id", reqID,
-			"user_id", user,
-			"tenant_id", tenantID,
-		)
-		respondWithError(w, http.StatusForbidden, "You do not have permission to create roles", nil)
+	slog.InfoContext(r.Context(), "tenant role created successfully",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"role_id", role.ID,
+		"role_name", role.Name,
+		"permissions_count", len(role.Permissions),
+	)
+
+	respondWithJSON(w, http.StatusCreated, roleToResponse(role))
+}
+
+// handlerTenantRoleClone copies a role, system or not, into a new role
+// with the same permissions that the tenant can then change
+func (cfg *apiConfig) handlerTenantRoleClone(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	roleID, err := uuid.Parse(chi.URLParam(r, "roleID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid role ID format", err)
 		return
 	}
 
 	type parameters struct {
-		Name        string   `json:"name"`
-		Description string   `json:"description"`
-		Permissions []string `json:"permissions"` // Permission keys to assign
+		Name        string `json:"name"`
+		Description string `json:"description"`
 	}
 
 	decoder := json.NewDecoder(r.Body)
 	params := parameters{}
-	err = decoder.Decode(&params)
-	if err != nil {
+	if err := decoder.Decode(&params); err != nil {
 		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
 		return
 	}
 
-	if params.Name == "" {
-		respondWithError(w, http.StatusBadRequest, "Role name is required", nil)
-		return
-	}
-
-	// Check if role name already exists in this tenant
-	_, err = cfg.db.GetRoleByTenantAndName(r.Context(), database.GetRoleByTenantAndNameParams{
-		TenantID: tenantID,
-		Name:     params.Name,
-	})
-	if err == nil {
-		respondWithError(w, http.StatusConflict, "A role with this name already exists", nil)
-		return
-	} else if !errors.Is(err, sql.ErrNoRows) {
-		respondWithError(w, http.StatusInternalServerError, "Unable to check existing roles", err)
-		return
-	}
-
-	// Create the role
-	description := sql.NullString{String: params.Description, Valid: params.Description != ""}
-	role, err := cfg.db.CreateRole(r.Context(), database.CreateRoleParams{
-		TenantID:    tenantID,
+	role, err := cfg.services.roles.Clone(r.Context(), roles.CloneInput{
+		TenantID:    access.TenantID,
+		RoleID:      roleID,
 		Name:        params.Name,
-		Description: description,
+		Description: params.Description,
 	})
 	if err != nil {
-		slog.ErrorContext(r.Context(), "tenant role creation failed: database error",
-			"request_id", reqID,
-			"user_id", user,
-			"tenant_id", tenantID,
-			"error", err,
-		)
-		respondWithError(w, http.StatusInternalServerError, "Unable to create role", err)
+		respondWithServiceError(w, err, "Unable to clone role")
 		return
 	}
 
-	// Assign permissions if provided
-	assignedPermissions := []string{}
-	if len(params.Permissions) > 0 {
-		permissions, err := cfg.db.GetPermissionsByKeys(r.Context(), params.Permissions)
-		if err != nil {
-			slog.WarnContext(r.Context(), "tenant role creation: error fetching permissions",
-				"request_id", reqID,
-				"error", err,
-			)
-		} else {
-			for _, perm := range permissions {
-				err = cfg.db.AssignPermissionToRole(r.Context(), database.AssignPermissionToRoleParams{
-					RoleID:       role.ID,
-					PermissionID: perm.ID,
-				})
-				if err != nil {
-					slog.WarnContext(r.Context(), "tenant role creation: error assigning permission",
-						"request_id", reqID,
-						"role_id", role.ID,
-						"permission_key", perm.Key,
-						"error", err,
-					)
-				} else {
-					assignedPermissions = append(assignedPermissions, perm.Key)
-				}
-			}
-		}
-	}
-
-	slog.InfoContext(r.Context(), "tenant role created successfully",
+	slog.InfoContext(r.Context(), "tenant role cloned",
 		"request_id", reqID,
-		"user_id", user,
-		"tenant_id", tenantID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"source_role_id", roleID,
 		"role_id", role.ID,
-		"role_name", role.Name,
-		"permissions_count", len(assignedPermissions),
 	)
 
+	respondWithJSON(w, http.StatusCreated, roleToResponse(role))
+}
+
+func roleToResponse(role roles.Role) RoleResponse {
 	var desc *string
 	if role.Description.Valid {
 		desc = &role.Description.String
 	}
-
-	respondWithJSON(w, http.StatusCreated, RoleResponse{
+	return RoleResponse{
 		ID:          role.ID,
 		TenantID:    role.TenantID,
 		Name:        role.Name,
 		Description: desc,
-		Permissions: assignedPermissions,
+		Permissions: role.Permissions,
+		System:      role.IsSystem,
 		CreatedAt:   role.CreatedAt,
 		UpdatedAt:   role.UpdatedAt,
-	})
+	}
 }
 
 // handlerTenantRolesList lists all roles in a tenant with pagination
@@ -212,36 +181,8 @@ func (cfg *apiConfig) handlerTenantRolesList(w http.ResponseWriter, r *http.Requ
 		"tenant_param", tenantParam,
 	)
 
-	user, ok := userFromContext(r.Context())
-	if !ok {
-		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
-		return
-	}
-
-	tenantID, err := uuid.Parse(tenantParam)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid tenant ID format", err)
-		return
-	}
-
-	// Verify user is a member of the tenant
-	tenantUser, err := cfg.db.GetTenantUser(r.Context(), database.GetTenantUserParams{
-		TenantID: tenantID,
-		UserID:   user,
-	})
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			respondWithError(w, http.StatusForbidden, "You are not a member of this tenant", nil)
-			return
-		}
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify membership", err)
-		return
-	}
-
-	if tenantUser.Status != "active" {
-		respondWithError(w, http.StatusForbidden, "Your membership is not active", nil)
-		return
-	}
+	tc := tenantContextFrom(r)
+	user, tenantID := tc.Membership.UserID, tc.Tenant.ID
 
 	pageParams, err := ParsePageParams(r, defaultRoleLimit, maxRoleLimit)
 	if err != nil {
@@ -250,7 +191,6 @@ func (cfg *apiConfig) handlerTenantRolesList(w http.ResponseWriter, r *http.Requ
 	}
 
 	limit := pageParams.Limit
-	limitPlusOne := int32(pageParams.Limit + 1)
 
 	cursorCreatedAt, cursorID, hasCursor, err := decodeRoleCursor(pageParams.Cursor)
 	if err != nil {
@@ -258,13 +198,7 @@ func (cfg *apiConfig) handlerTenantRolesList(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	rows, err := cfg.db.GetRolesByTenantIDPaginated(r.Context(), database.GetRolesByTenantIDPaginatedParams{
-		TenantID: tenantID,
-		Column2:  hasCursor,
-		Column3:  cursorCreatedAt,
-		Column4:  cursorID,
-		Limit:    limitPlusOne,
-	})
+	page, err := cfg.services.roles.List(r.Context(), tenantID, pageRequest(limit, cursorCreatedAt, cursorID, hasCursor))
 	if err != nil {
 		slog.ErrorContext(r.Context(), "tenant roles list failed: database query error",
 			"request_id", reqID,
@@ -274,10 +208,7 @@ func (cfg *apiConfig) handlerTenantRolesList(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	hasMore := len(rows) > limit
-	if hasMore {
-		rows = rows[:limit]
-	}
+	rows, hasMore := page.Items, page.HasMore
 
 	var nextCursor string
 	if hasMore && len(rows) > 0 {
@@ -292,38 +223,9 @@ func (cfg *apiConfig) handlerTenantRolesList(w http.ResponseWriter, r *http.Requ
 		}
 	}
 
-	// Fetch permissions for each role
 	response := make([]RoleResponse, 0, len(rows))
 	for _, role := range rows {
-		permissions, err := cfg.db.GetPermissionsByRoleID(r.Context(), role.ID)
-		if err != nil {
-			slog.WarnContext(r.Context(), "tenant roles list: error fetching permissions for role",
-				"request_id", reqID,
-				"role_id", role.ID,
-				"error", err,
-			)
-			permissions = []database.Permission{}
-		}
-
-		permissionKeys := make([]string, 0, len(permissions))
-		for _, perm := range permissions {
-			permissionKeys = append(permissionKeys, perm.Key)
-		}
-
-		var desc *string
-		if role.Description.Valid {
-			desc = &role.Description.String
-		}
-
-		response = append(response, RoleResponse{
-			ID:          role.ID,
-			TenantID:    role.TenantID,
-			Name:        role.Name,
-			Description: desc,
-			Permissions: permissionKeys,
-			CreatedAt:   role.CreatedAt,
-			UpdatedAt:   role.UpdatedAt,
-		})
+		response = append(response, roleToResponse(role))
 	}
 
 	slog.InfoContext(r.Context(), "tenant roles list successful",
@@ -334,13 +236,9 @@ func (cfg *apiConfig) handlerTenantRolesList(w http.ResponseWriter, r *http.Requ
 		"has_more", hasMore,
 	)
 
-	respondWithJSON(w, http.StatusOK, map[string]any{
-		"data": response,
-		"page": map[string]any{
-			"limit":       limit,
-			"has_more":    hasMore,
-			"next_cursor": nextCursor,
-		},
+	respondWithJSON(w, http.StatusOK, PageResponse[RoleResponse]{
+		Data: response,
+		Page: PageInfo{Limit: limit, NextCursor: nextCursor, HasMore: hasMore},
 	})
 }
 
@@ -356,17 +254,8 @@ func (cfg *apiConfig) handlerTenantRoleAddPermission(w http.ResponseWriter, r *h
 		"role_param", roleParam,
 	)
 
-	user, ok := userFromContext(r.Context())
-	if !ok {
-		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
-		return
-	}
-
-	tenantID, err := uuid.Parse(tenantParam)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid tenant ID format", err)
-		return
-	}
+	access := tenantAccessFrom(r)
+	user, tenantID := access.UserID, access.TenantID
 
 	roleID, err := uuid.Parse(roleParam)
 	if err != nil {
@@ -374,36 +263,6 @@ func (cfg *apiConfig) handlerTenantRoleAddPermission(w http.ResponseWriter, r *h
 		return
 	}
 
-	// Verify requesting user has permission to manage users
-	hasPermission, err := cfg.db.CheckUserHasPermission(r.Context(), database.CheckUserHasPermissionParams{
-		TenantID: tenantID,
-		UserID:   user,
-		Key:      "tenant:manage_users",
-	})
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify permissions", err)
-		return
-	}
-
-	if !hasPermission {
-		respondWithError(w, http.StatusForbidden, "You do not have permission to manage roles", nil)
-		return
-	}
-
-	// Verify role exists and belongs to this tenant
-	role, err := cfg.db.GetRoleByTenantAndID(r.Context(), database.GetRoleByTenantAndIDParams{
-		TenantID: tenantID,
-		ID:       roleID,
-	})
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			respondWithError(w, http.StatusNotFound, "Role not found in this tenant", nil)
-			return
-		}
-		respondWithError(w, http.StatusInternalServerError, "Unable to find role", err)
-		return
-	}
-
 	type parameters struct {
 		PermissionKey string `json:"permission_key"`
 	}
@@ -416,35 +275,15 @@ func (cfg *apiConfig) handlerTenantRoleAddPermission(w http.ResponseWriter, r *h
 		return
 	}
 
-	if params.PermissionKey == "" {
-		respondWithError(w, http.StatusBadRequest, "Permission key is required", nil)
-		return
-	}
-
-	// Find the permission by key
-	permission, err := cfg.db.GetPermissionByKey(r.Context(), params.PermissionKey)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			respondWithError(w, http.StatusNotFound, "Permission not found", nil)
-			return
-		}
-		respondWithError(w, http.StatusInternalServerError, "Unable to find permission", err)
-		return
-	}
-
-	// Assign permission to role
-	err = cfg.db.AssignPermissionToRole(r.Context(), database.AssignPermissionToRoleParams{
-		RoleID:       roleID,
-		PermissionID: permission.ID,
-	})
+	role, permission, err := cfg.services.roles.AddPermission(r.Context(), tenantID, roleID, params.PermissionKey)
 	if err != nil {
-		slog.ErrorContext(r.Context(), "tenant role add permission failed: database error",
+		slog.ErrorContext(r.Context(), "tenant role add permission failed",
 			"request_id", reqID,
 			"role_id", roleID,
-			"permission_id", permission.ID,
+			"permission_key", params.PermissionKey,
 			"error", err,
 		)
-		respondWithError(w, http.StatusInternalServerError, "Unable to add permission to role", err)
+		respondWithServiceError(w, err, "Unable to add permission to role")
 		return
 	}
 
@@ -479,17 +318,8 @@ func (cfg *apiConfig) handlerTenantRoleRemovePermission(w http.ResponseWriter, r
 		"permission_param", permissionParam,
 	)
 
-	user, ok := userFromContext(r.Context())
-	if !ok {
-		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
-		return
-	}
-
-	tenantID, err := uuid.Parse(tenantParam)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid tenant ID format", err)
-		return
-	}
+	access := tenantAccessFrom(r)
+	user, tenantID := access.UserID, access.TenantID
 
 	roleID, err := uuid.Parse(roleParam)
 	if err != nil {
@@ -497,44 +327,13 @@ func (cfg *apiConfig) handlerTenantRoleRemovePermission(w http.ResponseWriter, r
 		return
 	}
 
-	// Verify requesting user has permission to manage users
-	hasPermission, err := cfg.db.CheckUserHasPermission(r.Context(), database.CheckUserHasPermissionParams{
-		TenantID: tenantID,
-		UserID:   user,
-		Key:      "tenant:manage_users",
-	})
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify permissions", err)
-		return
-	}
-
-	if !hasPermission {
-		respondWithError(w, http.StatusForbidden, "You do not have permission to manage roles", nil)
-		return
-	}
-
-	// Find the permission by key
-	permission, err := cfg.db.GetPermissionByKey(r.Context(), permissionParam)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			respondWithError(w, http.StatusNotFound, "Permission not found", nil)
-			return
-		}
-		respondWithError(w, http.StatusInternalServerError, "Unable to find permission", err)
-		return
-	}
-
-	// Remove permission from role
-	err = cfg.db.RemovePermissionFromRole(r.Context(), database.RemovePermissionFromRoleParams{
-		RoleID:       roleID,
-		PermissionID: permission.ID,
-	})
-	if err != nil {
-		slog.ErrorContext(r.Context(), "tenant role remove permission failed: database error",
+	if _, _, err := cfg.services.roles.RemovePermission(r.Context(), tenantID, roleID, permissionParam); err != nil {
+		slog.ErrorContext(r.Context(), "tenant role remove permission failed",
 			"request_id", reqID,
+			"role_id", roleID,
 			"error", err,
 		)
-		respondWithError(w, http.StatusInternalServerError, "Unable to remove permission from role", err)
+		respondWithServiceError(w, err, "Unable to remove permission from role")
 		return
 	}
 
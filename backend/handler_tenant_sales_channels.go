@@ -0,0 +1,402 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/catalog"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxProductPublications bounds the channels one request publishes a
+// product to
+const maxProductPublications = 50
+
+// SalesChannelResponse is a place a store sells through
+type SalesChannelResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Kind        string    `json:"kind"`
+	Name        string    `json:"name"`
+	AutoPublish bool      `json:"auto_publish"`
+	// ProductCount is only filled in on listings
+	ProductCount *int64    `json:"product_count,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ProductPublicationResponse is a channel a product is published to
+type ProductPublicationResponse struct {
+	ChannelID   uuid.UUID `json:"channel_id"`
+	Kind        string    `json:"kind"`
+	Name        string    `json:"name"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// handlerTenantSalesChannelsList lists a store's sales channels, the online
+// store first
+func (cfg *apiConfig) handlerTenantSalesChannelsList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	rows, err := cfg.db.ListSalesChannels(r.Context(), storeID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve sales channels", err)
+		return
+	}
+
+	response := make([]SalesChannelResponse, 0, len(rows))
+	for _, row := range rows {
+		resp := salesChannelToResponse(database.SalesChannel{
+			ID:          row.ID,
+			Kind:        row.Kind,
+			Name:        row.Name,
+			AutoPublish: row.AutoPublish,
+			CreatedAt:   row.CreatedAt,
+			UpdatedAt:   row.UpdatedAt,
+		})
+		resp.ProductCount = &row.ProductCount
+		response = append(response, resp)
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{"data": response})
+}
+
+// handlerTenantSalesChannelsCreate adds a point of sale or marketplace feed
+// to a store. The online store comes with the store.
+func (cfg *apiConfig) handlerTenantSalesChannelsCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	type parameters struct {
+		Kind        string `json:"kind"`
+		Name        string `json:"name"`
+		AutoPublish bool   `json:"auto_publish"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+	params.Kind = strings.ToLower(strings.TrimSpace(params.Kind))
+	params.Name = strings.TrimSpace(params.Name)
+
+	var v validate.Validator
+	v.OneOf("kind", params.Kind, string(catalog.ChannelPOS), string(catalog.ChannelMarketplaceFeed))
+	if v.Required("name", params.Name) {
+		v.MaxLength("name", params.Name, catalog.MaxChannelNameLength)
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	channel, err := cfg.db.CreateSalesChannel(r.Context(), database.CreateSalesChannelParams{
+		Gid:         sql.NullInt64{Int64: int64(cfg.gidGen.Generate()), Valid: true},
+		TenantID:    access.TenantID,
+		StoreID:     access.StoreID,
+		Kind:        params.Kind,
+		Name:        params.Name,
+		AutoPublish: params.AutoPublish,
+	})
+	if service.UniqueViolation(err, "") {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeAlreadyExists, "A sales channel with this name already exists", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create sales channel", err)
+		return
+	}
+	resp := salesChannelToResponse(channel)
+	auditChange(r, auditGID(gid.EntitySalesChannel, channel.Gid), nil, resp)
+
+	slog.InfoContext(r.Context(), "sales channel created",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"store_id", access.StoreID,
+		"channel_id", channel.ID,
+		"kind", channel.Kind,
+	)
+
+	respondWithJSON(w, http.StatusCreated, resp)
+}
+
+// handlerTenantSalesChannelGet returns a sales channel
+func (cfg *apiConfig) handlerTenantSalesChannelGet(w http.ResponseWriter, r *http.Request) {
+	channel, ok := cfg.loadSalesChannel(w, r)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, salesChannelToResponse(channel))
+}
+
+// handlerTenantSalesChannelUpdate renames a sales channel or changes whether
+// new products are published to it. A channel's kind never changes.
+func (cfg *apiConfig) handlerTenantSalesChannelUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	existing, ok := cfg.loadSalesChannel(w, r)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		Name        *string `json:"name"`
+		AutoPublish *bool   `json:"auto_publish"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	name := existing.Name
+	if params.Name != nil {
+		name = strings.TrimSpace(*params.Name)
+	}
+	autoPublish := existing.AutoPublish
+	if params.AutoPublish != nil {
+		autoPublish = *params.AutoPublish
+	}
+
+	var v validate.Validator
+	if v.Required("name", name) {
+		v.MaxLength("name", name, catalog.MaxChannelNameLength)
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	channel, err := cfg.db.UpdateSalesChannel(r.Context(), database.UpdateSalesChannelParams{
+		ID:          existing.ID,
+		StoreID:     access.StoreID,
+		Name:        name,
+		AutoPublish: autoPublish,
+	})
+	if service.UniqueViolation(err, "") {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeAlreadyExists, "A sales channel with this name already exists", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update sales channel", err)
+		return
+	}
+	resp := salesChannelToResponse(channel)
+	auditChange(r, auditGID(gid.EntitySalesChannel, channel.Gid), salesChannelToResponse(existing), resp)
+
+	slog.InfoContext(r.Context(), "sales channel updated",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"channel_id", channel.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerTenantSalesChannelDelete deletes a sales channel and unpublishes
+// every product from it. The online store cannot be deleted.
+func (cfg *apiConfig) handlerTenantSalesChannelDelete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	channel, ok := cfg.loadSalesChannel(w, r)
+	if !ok {
+		return
+	}
+
+	deleted, err := cfg.db.DeleteSalesChannel(r.Context(), database.DeleteSalesChannelParams{
+		ID:      channel.ID,
+		StoreID: access.StoreID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to delete sales channel", err)
+		return
+	}
+	if deleted == 0 {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeConflict, "The online store channel cannot be deleted", nil)
+		return
+	}
+	auditChange(r, auditGID(gid.EntitySalesChannel, channel.Gid), salesChannelToResponse(channel), nil)
+
+	slog.InfoContext(r.Context(), "sales channel deleted",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"channel_id", channel.ID,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerTenantProductPublicationsList lists the channels a product is
+// published to
+func (cfg *apiConfig) handlerTenantProductPublicationsList(w http.ResponseWriter, r *http.Request) {
+	product, ok := cfg.loadStoreProduct(w, r, tenantAccessFrom(r).StoreID)
+	if !ok {
+		return
+	}
+
+	cfg.respondWithProductPublications(w, r, product.ID)
+}
+
+// handlerTenantProductPublicationsSet publishes a product to exactly the
+// given channels. Channels it stays on keep their original published_at.
+func (cfg *apiConfig) handlerTenantProductPublicationsSet(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	product, ok := cfg.loadStoreProduct(w, r, access.StoreID)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		ChannelIDs []uuid.UUID `json:"channel_ids"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	var v validate.Validator
+	if v.Check(params.ChannelIDs != nil, "channel_ids", validate.CodeRequired, "is required") &&
+		v.Check(len(params.ChannelIDs) <= maxProductPublications, "channel_ids", validate.CodeOutOfRange, "has too many channels") {
+		seen := make(map[uuid.UUID]bool, len(params.ChannelIDs))
+		for _, id := range params.ChannelIDs {
+			if !v.Check(!seen[id], "channel_ids", validate.CodeInvalid, "includes a channel more than once") {
+				break
+			}
+			seen[id] = true
+		}
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	channels, err := cfg.db.ListSalesChannels(r.Context(), access.StoreID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve sales channels", err)
+		return
+	}
+	known := make(map[uuid.UUID]bool, len(channels))
+	for _, c := range channels {
+		known[c.ID] = true
+	}
+	for _, id := range params.ChannelIDs {
+		if !known[id] {
+			v.Fail("channel_ids", validate.CodeInvalid, "includes a channel that does not exist")
+			respondWithValidationError(w, v.Err())
+			return
+		}
+	}
+
+	err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		if err := q.UnpublishProductExcept(r.Context(), database.UnpublishProductExceptParams{
+			ProductID:  product.ID,
+			ChannelIds: params.ChannelIDs,
+		}); err != nil {
+			return err
+		}
+		for _, channelID := range params.ChannelIDs {
+			if err := q.PublishProduct(r.Context(), database.PublishProductParams{
+				ChannelID: channelID,
+				ProductID: product.ID,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update product publications", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "product publications updated",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"product_id", product.ID,
+		"channel_count", len(params.ChannelIDs),
+	)
+
+	cfg.respondWithProductPublications(w, r, product.ID)
+}
+
+func (cfg *apiConfig) respondWithProductPublications(w http.ResponseWriter, r *http.Request, productID uuid.UUID) {
+	rows, err := cfg.db.ListProductPublications(r.Context(), productID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve product publications", err)
+		return
+	}
+
+	response := make([]ProductPublicationResponse, 0, len(rows))
+	for _, row := range rows {
+		response = append(response, ProductPublicationResponse{
+			ChannelID:   row.ChannelID,
+			Kind:        row.Kind,
+			Name:        row.Name,
+			PublishedAt: row.PublishedAt,
+		})
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{"data": response})
+}
+
+// loadSalesChannel loads the {channelID} of the route from the store the
+// caller was let into. It has responded when it returns false.
+func (cfg *apiConfig) loadSalesChannel(w http.ResponseWriter, r *http.Request) (database.SalesChannel, bool) {
+	channelID, err := uuid.Parse(chi.URLParam(r, "channelID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid sales channel ID format", err)
+		return database.SalesChannel{}, false
+	}
+
+	channel, err := cfg.db.GetSalesChannelByID(r.Context(), database.GetSalesChannelByIDParams{
+		ID:      channelID,
+		StoreID: tenantAccessFrom(r).StoreID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Sales channel not found", nil)
+		return database.SalesChannel{}, false
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve sales channel", err)
+		return database.SalesChannel{}, false
+	}
+	return channel, true
+}
+
+func salesChannelToResponse(c database.SalesChannel) SalesChannelResponse {
+	return SalesChannelResponse{
+		ID:          c.ID,
+		Kind:        c.Kind,
+		Name:        c.Name,
+		AutoPublish: c.AutoPublish,
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+	}
+}
@@ -0,0 +1,366 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/subscriptions"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxSellingPlanProducts bounds the products one selling plan is offered on
+const maxSellingPlanProducts = 250
+
+// SellingPlanResponse is a way to subscribe to products: how often they
+// renew and what each renewal saves
+type SellingPlanResponse struct {
+	ID            uuid.UUID   `json:"id"`
+	Name          string      `json:"name"`
+	IntervalUnit  string      `json:"interval_unit"`
+	IntervalCount int32       `json:"interval_count"`
+	PercentOff    int32       `json:"percent_off"`
+	ProductIDs    []uuid.UUID `json:"product_ids"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
+
+// sellingPlanParams is the body of a selling plan create or update. Fields
+// left out of an update keep their value.
+type sellingPlanParams struct {
+	Name          *string      `json:"name"`
+	IntervalUnit  *string      `json:"interval_unit"`
+	IntervalCount *int32       `json:"interval_count"`
+	PercentOff    *int32       `json:"percent_off"`
+	ProductIDs    *[]uuid.UUID `json:"product_ids"`
+}
+
+// handlerTenantSellingPlansList lists a store's selling plans
+func (cfg *apiConfig) handlerTenantSellingPlansList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	plans, err := cfg.db.ListSellingPlans(r.Context(), storeID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve selling plans", err)
+		return
+	}
+	products, err := cfg.sellingPlanProducts(r, storeID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve selling plans", err)
+		return
+	}
+
+	response := make([]SellingPlanResponse, 0, len(plans))
+	for _, plan := range plans {
+		response = append(response, sellingPlanToResponse(plan, products[plan.ID]))
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{"data": response})
+}
+
+// handlerTenantSellingPlansCreate adds a selling plan to a store, offered on
+// the given products
+func (cfg *apiConfig) handlerTenantSellingPlansCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	decoder := json.NewDecoder(r.Body)
+	params := sellingPlanParams{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	plan := database.SellingPlan{}
+	var productIDs []uuid.UUID
+	if !cfg.applySellingPlanParams(w, r, &plan, &productIDs, params) {
+		return
+	}
+
+	err := cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		var err error
+		plan, err = q.CreateSellingPlan(r.Context(), database.CreateSellingPlanParams{
+			Gid:           sql.NullInt64{Int64: int64(cfg.gidGen.Generate()), Valid: true},
+			TenantID:      access.TenantID,
+			StoreID:       access.StoreID,
+			Name:          plan.Name,
+			IntervalUnit:  plan.IntervalUnit,
+			IntervalCount: plan.IntervalCount,
+			PercentOff:    plan.PercentOff,
+		})
+		if err != nil {
+			return err
+		}
+		return assignSellingPlanProducts(r, q, plan.ID, productIDs)
+	})
+	if service.UniqueViolation(err, "") {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeAlreadyExists, "A selling plan with this name already exists", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create selling plan", err)
+		return
+	}
+	resp := sellingPlanToResponse(plan, productIDs)
+	auditChange(r, auditGID(gid.EntitySellingPlan, plan.Gid), nil, resp)
+
+	slog.InfoContext(r.Context(), "selling plan created",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"store_id", access.StoreID,
+		"selling_plan_id", plan.ID,
+	)
+
+	respondWithJSON(w, http.StatusCreated, resp)
+}
+
+// handlerTenantSellingPlanGet returns a selling plan
+func (cfg *apiConfig) handlerTenantSellingPlanGet(w http.ResponseWriter, r *http.Request) {
+	plan, productIDs, ok := cfg.loadSellingPlan(w, r)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, sellingPlanToResponse(plan, productIDs))
+}
+
+// handlerTenantSellingPlanUpdate changes a selling plan or, when product_ids
+// is given, replaces the products it is offered on. Existing subscriptions
+// keep the interval and price they were taken out at.
+func (cfg *apiConfig) handlerTenantSellingPlanUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	existing, existingProducts, ok := cfg.loadSellingPlan(w, r)
+	if !ok {
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := sellingPlanParams{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	plan := existing
+	productIDs := existingProducts
+	if !cfg.applySellingPlanParams(w, r, &plan, &productIDs, params) {
+		return
+	}
+
+	err := cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		var err error
+		plan, err = q.UpdateSellingPlan(r.Context(), database.UpdateSellingPlanParams{
+			ID:            existing.ID,
+			StoreID:       access.StoreID,
+			Name:          plan.Name,
+			IntervalUnit:  plan.IntervalUnit,
+			IntervalCount: plan.IntervalCount,
+			PercentOff:    plan.PercentOff,
+		})
+		if err != nil || params.ProductIDs == nil {
+			return err
+		}
+		if err := q.ClearSellingPlanProducts(r.Context(), plan.ID); err != nil {
+			return err
+		}
+		return assignSellingPlanProducts(r, q, plan.ID, productIDs)
+	})
+	if service.UniqueViolation(err, "") {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeAlreadyExists, "A selling plan with this name already exists", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update selling plan", err)
+		return
+	}
+	resp := sellingPlanToResponse(plan, productIDs)
+	auditChange(r, auditGID(gid.EntitySellingPlan, plan.Gid), sellingPlanToResponse(existing, existingProducts), resp)
+
+	slog.InfoContext(r.Context(), "selling plan updated",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"selling_plan_id", plan.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerTenantSellingPlanDelete deletes a selling plan. Subscriptions taken
+// out on it carry on renewing on its old terms.
+func (cfg *apiConfig) handlerTenantSellingPlanDelete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	plan, productIDs, ok := cfg.loadSellingPlan(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := cfg.db.DeleteSellingPlan(r.Context(), database.DeleteSellingPlanParams{
+		ID:      plan.ID,
+		StoreID: access.StoreID,
+	}); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to delete selling plan", err)
+		return
+	}
+	auditChange(r, auditGID(gid.EntitySellingPlan, plan.Gid), sellingPlanToResponse(plan, productIDs), nil)
+
+	slog.InfoContext(r.Context(), "selling plan deleted",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"selling_plan_id", plan.ID,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// applySellingPlanParams validates params onto plan and productIDs. It has
+// responded when it returns false.
+func (cfg *apiConfig) applySellingPlanParams(w http.ResponseWriter, r *http.Request, plan *database.SellingPlan, productIDs *[]uuid.UUID, params sellingPlanParams) bool {
+	if params.Name != nil {
+		plan.Name = strings.TrimSpace(*params.Name)
+	}
+	if params.IntervalUnit != nil {
+		plan.IntervalUnit = strings.ToLower(strings.TrimSpace(*params.IntervalUnit))
+	}
+	if params.IntervalCount != nil {
+		plan.IntervalCount = *params.IntervalCount
+	}
+	if params.PercentOff != nil {
+		plan.PercentOff = *params.PercentOff
+	}
+	if params.ProductIDs != nil {
+		*productIDs = *params.ProductIDs
+	}
+
+	var v validate.Validator
+	if v.Required("name", plan.Name) {
+		v.MaxLength("name", plan.Name, subscriptions.MaxNameLength)
+	}
+	v.OneOf("interval_unit", plan.IntervalUnit, string(subscriptions.UnitDay), string(subscriptions.UnitWeek), string(subscriptions.UnitMonth), string(subscriptions.UnitYear))
+	v.Between("interval_count", int64(plan.IntervalCount), 1, subscriptions.MaxIntervalCount)
+	v.Between("percent_off", int64(plan.PercentOff), 0, 100)
+	if v.Check(len(*productIDs) <= maxSellingPlanProducts, "product_ids", validate.CodeOutOfRange, "has too many products") {
+		seen := make(map[uuid.UUID]bool, len(*productIDs))
+		for _, id := range *productIDs {
+			if !v.Check(!seen[id], "product_ids", validate.CodeInvalid, "includes a product more than once") {
+				break
+			}
+			seen[id] = true
+		}
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return false
+	}
+	if len(*productIDs) == 0 {
+		return true
+	}
+
+	found, err := cfg.db.GetProductsByIDs(r.Context(), database.GetProductsByIDsParams{
+		StoreID: tenantAccessFrom(r).StoreID,
+		Ids:     *productIDs,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to verify products", err)
+		return false
+	}
+	if len(found) != len(*productIDs) {
+		v.Fail("product_ids", validate.CodeInvalid, "includes a product that does not exist")
+		respondWithValidationError(w, v.Err())
+		return false
+	}
+	return true
+}
+
+// assignSellingPlanProducts offers a selling plan on products
+func assignSellingPlanProducts(r *http.Request, q *database.Queries, planID uuid.UUID, productIDs []uuid.UUID) error {
+	for _, productID := range productIDs {
+		if err := q.AddSellingPlanProduct(r.Context(), database.AddSellingPlanProductParams{
+			SellingPlanID: planID,
+			ProductID:     productID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sellingPlanProducts maps each of a store's selling plans to the products
+// it is offered on
+func (cfg *apiConfig) sellingPlanProducts(r *http.Request, storeID uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	rows, err := cfg.db.ListSellingPlanProductsByStore(r.Context(), storeID)
+	if err != nil {
+		return nil, err
+	}
+	products := make(map[uuid.UUID][]uuid.UUID)
+	for _, row := range rows {
+		products[row.SellingPlanID] = append(products[row.SellingPlanID], row.ProductID)
+	}
+	return products, nil
+}
+
+// loadSellingPlan loads the {sellingPlanID} of the route from the store the
+// caller was let into, with the products it is offered on. It has responded
+// when it returns false.
+func (cfg *apiConfig) loadSellingPlan(w http.ResponseWriter, r *http.Request) (database.SellingPlan, []uuid.UUID, bool) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	planID, err := uuid.Parse(chi.URLParam(r, "sellingPlanID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid selling plan ID format", err)
+		return database.SellingPlan{}, nil, false
+	}
+
+	plan, err := cfg.db.GetSellingPlanByID(r.Context(), database.GetSellingPlanByIDParams{
+		ID:      planID,
+		StoreID: storeID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Selling plan not found", nil)
+		return database.SellingPlan{}, nil, false
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve selling plan", err)
+		return database.SellingPlan{}, nil, false
+	}
+
+	products, err := cfg.sellingPlanProducts(r, storeID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve selling plan", err)
+		return database.SellingPlan{}, nil, false
+	}
+	return plan, products[plan.ID], true
+}
+
+func sellingPlanToResponse(plan database.SellingPlan, productIDs []uuid.UUID) SellingPlanResponse {
+	if productIDs == nil {
+		productIDs = []uuid.UUID{}
+	}
+	return SellingPlanResponse{
+		ID:            plan.ID,
+		Name:          plan.Name,
+		IntervalUnit:  plan.IntervalUnit,
+		IntervalCount: plan.IntervalCount,
+		PercentOff:    plan.PercentOff,
+		ProductIDs:    productIDs,
+		CreatedAt:     plan.CreatedAt,
+		UpdatedAt:     plan.UpdatedAt,
+	}
+}
@@ -0,0 +1,580 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/shipping"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type ShippingZoneResponse struct {
+	ID        uuid.UUID              `json:"id"`
+	StoreID   uuid.UUID              `json:"store_id"`
+	Name      string                 `json:"name"`
+	Regions   []string               `json:"regions"`
+	Rates     []ShippingRateResponse `json:"rates"`
+	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
+}
+
+type ShippingRateResponse struct {
+	ID         uuid.UUID `json:"id"`
+	ZoneID     uuid.UUID `json:"zone_id"`
+	Name       string    `json:"name"`
+	Kind       string    `json:"kind"`
+	PriceCents int64     `json:"price_cents"`
+	MinValue   *int64    `json:"min_value,omitempty"`
+	MaxValue   *int64    `json:"max_value,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// handlerTenantShippingZonesCreate creates a shipping zone
+func (cfg *apiConfig) handlerTenantShippingZonesCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	type parameters struct {
+		Name    string   `json:"name"`
+		Regions []string `json:"regions"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	name, regions, ok := cfg.validateShippingZone(w, r, storeID, uuid.Nil, params.Name, params.Regions)
+	if !ok {
+		return
+	}
+
+	zone, err := cfg.db.CreateShippingZone(r.Context(), database.CreateShippingZoneParams{
+		Gid:      sql.NullInt64{Int64: int64(cfg.gidGen.Generate()), Valid: true},
+		TenantID: tenantID,
+		StoreID:  storeID,
+		Name:     name,
+		Regions:  regions,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create shipping zone", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant shipping zone created",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"zone_id", zone.ID,
+		"region_count", len(zone.Regions),
+	)
+
+	respondWithJSON(w, http.StatusCreated, shippingZoneToResponse(zone, nil))
+}
+
+// handlerTenantShippingZonesList lists a store's shipping zones with their rates
+func (cfg *apiConfig) handlerTenantShippingZonesList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	zones, err := cfg.db.GetShippingZonesByStore(r.Context(), storeID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve shipping zones", err)
+		return
+	}
+
+	rates, err := cfg.db.GetShippingRatesByStore(r.Context(), storeID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve shipping rates", err)
+		return
+	}
+
+	byZone := make(map[uuid.UUID][]database.ShippingRate, len(zones))
+	for _, rate := range rates {
+		byZone[rate.ZoneID] = append(byZone[rate.ZoneID], rate)
+	}
+
+	response := make([]ShippingZoneResponse, 0, len(zones))
+	for _, zone := range zones {
+		response = append(response, shippingZoneToResponse(zone, byZone[zone.ID]))
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{
+		"data": response,
+	})
+}
+
+// handlerTenantShippingZoneGet returns a shipping zone with its rates
+func (cfg *apiConfig) handlerTenantShippingZoneGet(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	zone, ok := cfg.loadStoreShippingZone(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	rates, err := cfg.db.GetShippingRatesByZone(r.Context(), zone.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve shipping rates", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, shippingZoneToResponse(zone, rates))
+}
+
+// handlerTenantShippingZoneUpdate renames a zone or replaces its regions
+func (cfg *apiConfig) handlerTenantShippingZoneUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	zone, ok := cfg.loadStoreShippingZone(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		Name    *string  `json:"name"`
+		Regions []string `json:"regions"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	name := zone.Name
+	if params.Name != nil {
+		name = *params.Name
+	}
+	regions := zone.Regions
+	if params.Regions != nil {
+		regions = params.Regions
+	}
+
+	name, regions, ok = cfg.validateShippingZone(w, r, storeID, zone.ID, name, regions)
+	if !ok {
+		return
+	}
+
+	zone, err = cfg.db.UpdateShippingZone(r.Context(), database.UpdateShippingZoneParams{
+		ID:      zone.ID,
+		StoreID: storeID,
+		Name:    name,
+		Regions: regions,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update shipping zone", err)
+		return
+	}
+
+	rates, err := cfg.db.GetShippingRatesByZone(r.Context(), zone.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve shipping rates", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant shipping zone updated",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"zone_id", zone.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, shippingZoneToResponse(zone, rates))
+}
+
+// handlerTenantShippingZoneDelete deletes a zone and its rates
+func (cfg *apiConfig) handlerTenantShippingZoneDelete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	zone, ok := cfg.loadStoreShippingZone(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	_, err := cfg.db.DeleteShippingZone(r.Context(), database.DeleteShippingZoneParams{
+		ID:      zone.ID,
+		StoreID: storeID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to delete shipping zone", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant shipping zone deleted",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"zone_id", zone.ID,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type shippingRateParameters struct {
+	Name       string        `json:"name"`
+	Kind       shipping.Kind `json:"kind"`
+	PriceCents int64         `json:"price_cents"`
+	MinValue   *int64        `json:"min_value"`
+	MaxValue   *int64        `json:"max_value"`
+}
+
+// handlerTenantShippingRatesCreate adds a rate to a zone
+func (cfg *apiConfig) handlerTenantShippingRatesCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	zone, ok := cfg.loadStoreShippingZone(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := shippingRateParameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	params.Name = strings.TrimSpace(params.Name)
+	if err := shipping.ValidateRate(shippingRateFromParams(params)); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	rate, err := cfg.db.CreateShippingRate(r.Context(), database.CreateShippingRateParams{
+		ZoneID:     zone.ID,
+		StoreID:    storeID,
+		Name:       params.Name,
+		Kind:       string(params.Kind),
+		PriceCents: params.PriceCents,
+		MinValue:   nullInt64FromPtr(params.MinValue),
+		MaxValue:   nullInt64FromPtr(params.MaxValue),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create shipping rate", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant shipping rate created",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"zone_id", zone.ID,
+		"rate_id", rate.ID,
+	)
+
+	respondWithJSON(w, http.StatusCreated, shippingRateToResponse(rate))
+}
+
+// handlerTenantShippingRatesList lists a zone's rates, cheapest first
+func (cfg *apiConfig) handlerTenantShippingRatesList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	zone, ok := cfg.loadStoreShippingZone(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	rates, err := cfg.db.GetShippingRatesByZone(r.Context(), zone.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve shipping rates", err)
+		return
+	}
+
+	response := make([]ShippingRateResponse, 0, len(rates))
+	for _, rate := range rates {
+		response = append(response, shippingRateToResponse(rate))
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{
+		"data": response,
+	})
+}
+
+// handlerTenantShippingRateUpdate replaces a rate's pricing. The whole rate
+// is sent since kind and range only make sense together.
+func (cfg *apiConfig) handlerTenantShippingRateUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	zone, ok := cfg.loadStoreShippingZone(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	rate, ok := cfg.loadZoneShippingRate(w, r, zone.ID)
+	if !ok {
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := shippingRateParameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	params.Name = strings.TrimSpace(params.Name)
+	if err := shipping.ValidateRate(shippingRateFromParams(params)); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	rate, err = cfg.db.UpdateShippingRate(r.Context(), database.UpdateShippingRateParams{
+		ID:         rate.ID,
+		ZoneID:     zone.ID,
+		Name:       params.Name,
+		Kind:       string(params.Kind),
+		PriceCents: params.PriceCents,
+		MinValue:   nullInt64FromPtr(params.MinValue),
+		MaxValue:   nullInt64FromPtr(params.MaxValue),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update shipping rate", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant shipping rate updated",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"zone_id", zone.ID,
+		"rate_id", rate.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, shippingRateToResponse(rate))
+}
+
+// handlerTenantShippingRateDelete removes a rate from a zone
+func (cfg *apiConfig) handlerTenantShippingRateDelete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	zone, ok := cfg.loadStoreShippingZone(w, r, storeID)
+	if !ok {
+		return
+	}
+
+	rate, ok := cfg.loadZoneShippingRate(w, r, zone.ID)
+	if !ok {
+		return
+	}
+
+	_, err := cfg.db.DeleteShippingRate(r.Context(), database.DeleteShippingRateParams{
+		ID:     rate.ID,
+		ZoneID: zone.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to delete shipping rate", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant shipping rate deleted",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"zone_id", zone.ID,
+		"rate_id", rate.ID,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateShippingZone normalizes a zone's name and regions and rejects
+// regions another zone of the store already covers, so every destination
+// resolves to exactly one zone
+func (cfg *apiConfig) validateShippingZone(w http.ResponseWriter, r *http.Request, storeID, zoneID uuid.UUID, name string, regions []string) (string, []string, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		respondWithError(w, http.StatusBadRequest, "Zone name is required", nil)
+		return "", nil, false
+	}
+
+	regions, err := shipping.NormalizeRegions(regions)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return "", nil, false
+	}
+
+	zones, err := cfg.db.GetShippingZonesByStore(r.Context(), storeID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve shipping zones", err)
+		return "", nil, false
+	}
+
+	for _, other := range zones {
+		if other.ID == zoneID {
+			continue
+		}
+		if other.Name == name {
+			respondWithErrorCode(w, http.StatusConflict, problem.CodeAlreadyExists, "A shipping zone with this name already exists", nil)
+			return "", nil, false
+		}
+		for _, code := range regions {
+			for _, taken := range other.Regions {
+				if code == taken {
+					respondWithError(w, http.StatusConflict, fmt.Sprintf("Region %s is already in zone %q", code, other.Name), nil)
+					return "", nil, false
+				}
+			}
+		}
+	}
+
+	return name, regions, true
+}
+
+// loadStoreShippingZone parses {zoneID} and loads it, scoped to the store
+func (cfg *apiConfig) loadStoreShippingZone(w http.ResponseWriter, r *http.Request, storeID uuid.UUID) (database.ShippingZone, bool) {
+	zoneID, err := uuid.Parse(chi.URLParam(r, "zoneID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid shipping zone ID format", err)
+		return database.ShippingZone{}, false
+	}
+
+	zone, err := cfg.db.GetShippingZoneByID(r.Context(), database.GetShippingZoneByIDParams{
+		ID:      zoneID,
+		StoreID: storeID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Shipping zone not found", nil)
+			return database.ShippingZone{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve shipping zone", err)
+		return database.ShippingZone{}, false
+	}
+	return zone, true
+}
+
+// loadZoneShippingRate parses {rateID} and loads it, scoped to the zone
+func (cfg *apiConfig) loadZoneShippingRate(w http.ResponseWriter, r *http.Request, zoneID uuid.UUID) (database.ShippingRate, bool) {
+	rateID, err := uuid.Parse(chi.URLParam(r, "rateID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid shipping rate ID format", err)
+		return database.ShippingRate{}, false
+	}
+
+	rate, err := cfg.db.GetShippingRateByID(r.Context(), database.GetShippingRateByIDParams{
+		ID:     rateID,
+		ZoneID: zoneID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Shipping rate not found", nil)
+			return database.ShippingRate{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve shipping rate", err)
+		return database.ShippingRate{}, false
+	}
+	return rate, true
+}
+
+func shippingRateFromParams(params shippingRateParameters) shipping.Rate {
+	return shipping.Rate{
+		Name:       params.Name,
+		Kind:       params.Kind,
+		PriceCents: params.PriceCents,
+		Min:        params.MinValue,
+		Max:        params.MaxValue,
+	}
+}
+
+func shippingRateFromRow(rate database.ShippingRate) shipping.Rate {
+	out := shipping.Rate{
+		ID:         rate.ID,
+		ZoneID:     rate.ZoneID,
+		Name:       rate.Name,
+		Kind:       shipping.Kind(rate.Kind),
+		PriceCents: rate.PriceCents,
+	}
+	if rate.MinValue.Valid {
+		out.Min = &rate.MinValue.Int64
+	}
+	if rate.MaxValue.Valid {
+		out.Max = &rate.MaxValue.Int64
+	}
+	return out
+}
+
+func shippingZoneToResponse(zone database.ShippingZone, rates []database.ShippingRate) ShippingZoneResponse {
+	resp := ShippingZoneResponse{
+		ID:        zone.ID,
+		StoreID:   zone.StoreID,
+		Name:      zone.Name,
+		Regions:   zone.Regions,
+		Rates:     make([]ShippingRateResponse, 0, len(rates)),
+		CreatedAt: zone.CreatedAt,
+		UpdatedAt: zone.UpdatedAt,
+	}
+	if resp.Regions == nil {
+		resp.Regions = []string{}
+	}
+	for _, rate := range rates {
+		resp.Rates = append(resp.Rates, shippingRateToResponse(rate))
+	}
+	return resp
+}
+
+func shippingRateToResponse(rate database.ShippingRate) ShippingRateResponse {
+	resp := ShippingRateResponse{
+		ID:         rate.ID,
+		ZoneID:     rate.ZoneID,
+		Name:       rate.Name,
+		Kind:       rate.Kind,
+		PriceCents: rate.PriceCents,
+		CreatedAt:  rate.CreatedAt,
+		UpdatedAt:  rate.UpdatedAt,
+	}
+	if rate.MinValue.Valid {
+		resp.MinValue = &rate.MinValue.Int64
+	}
+	if rate.MaxValue.Valid {
+		resp.MaxValue = &rate.MaxValue.Int64
+	}
+	return resp
+}
+
+func nullInt64FromPtr(v *int64) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *v, Valid: true}
+}
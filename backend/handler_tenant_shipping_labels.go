@@ -0,0 +1,389 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/address"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/fulfillment"
+	"github.com/dfodeker/terminus/internal/labels"
+	"github.com/dfodeker/terminus/internal/mailer"
+	"github.com/dfodeker/terminus/internal/metrics"
+	"github.com/dfodeker/terminus/internal/notifications"
+	"github.com/dfodeker/terminus/internal/orders"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// Parcels larger than these are freight, which label providers do not sell
+const (
+	maxParcelGrams = 70000
+	maxParcelCM    = 300
+)
+
+// handlerTenantFulfillmentLabelCreate buys a shipping label for a
+// fulfillment from one of the configured providers and records its
+// carrier, tracking number and label URL on the fulfillment. The parcel
+// ships to the order's shipping address unless to is given. The
+// provider's tracking webhooks then move the fulfillment along.
+func (cfg *apiConfig) handlerTenantFulfillmentLabelCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	order, ok := cfg.loadStoreOrder(w, r, storeID)
+	if !ok {
+		return
+	}
+	f, ok := cfg.loadOrderFulfillment(w, r, cfg.db, order.ID)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		Provider string          `json:"provider"`
+		Carrier  string          `json:"carrier"`
+		Service  string          `json:"service"`
+		From     labels.Address  `json:"from"`
+		To       *labels.Address `json:"to"`
+		Parcel   labels.Parcel   `json:"parcel"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	if len(cfg.labels) == 0 {
+		respondWithError(w, http.StatusUnprocessableEntity, "No shipping label provider is configured", nil)
+		return
+	}
+	if f.Status == string(fulfillment.StatusCancelled) {
+		respondWithError(w, http.StatusConflict, "Cancelled fulfillments cannot be shipped", nil)
+		return
+	}
+	if f.LabelProvider.Valid {
+		respondWithError(w, http.StatusConflict, "Fulfillment already has a shipping label", nil)
+		return
+	}
+
+	var v validate.Validator
+	v.OneOf("provider", params.Provider, cfg.labels.Names()...)
+	params.From = normalizeLabelAddress(&v, "from", params.From)
+	if params.To != nil {
+		*params.To = normalizeLabelAddress(&v, "to", *params.To)
+	} else if to, ok := orderShipTo(order); ok {
+		params.To = &to
+	} else {
+		v.Fail("to", validate.CodeRequired, "is required when the order has no shipping address")
+	}
+	v.Between("parcel.weight_grams", int64(params.Parcel.WeightGrams), 1, maxParcelGrams)
+	for _, dim := range []struct {
+		field string
+		cm    float64
+	}{
+		{"parcel.length_cm", params.Parcel.LengthCM},
+		{"parcel.width_cm", params.Parcel.WidthCM},
+		{"parcel.height_cm", params.Parcel.HeightCM},
+	} {
+		v.Check(dim.cm > 0 && dim.cm <= maxParcelCM, dim.field, validate.CodeInvalid, fmt.Sprintf("must be more than 0 and at most %d", maxParcelCM))
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+	provider, _ := cfg.labels.Lookup(params.Provider)
+
+	label, err := provider.Buy(r.Context(), labels.Request{
+		From:      params.From,
+		To:        *params.To,
+		Parcel:    params.Parcel,
+		Carrier:   strings.TrimSpace(params.Carrier),
+		Service:   strings.TrimSpace(params.Service),
+		Reference: fmt.Sprintf("#%d", order.OrderNumber),
+	})
+	if errors.Is(err, labels.ErrRejected) {
+		respondWithError(w, http.StatusUnprocessableEntity, err.Error(), err)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadGateway, "Unable to buy a label from the provider", err)
+		return
+	}
+
+	trackingURL := sql.NullString{String: label.TrackingURL, Valid: label.TrackingURL != ""}
+	if !trackingURL.Valid {
+		if u := fulfillment.TrackingURL(label.Carrier, label.TrackingNumber); u != "" {
+			trackingURL = sql.NullString{String: u, Valid: true}
+		}
+	}
+
+	err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		locked, err := q.LockOrderForUpdate(r.Context(), database.LockOrderForUpdateParams{
+			ID:      order.ID,
+			StoreID: storeID,
+		})
+		if err != nil {
+			return err
+		}
+		f, err = q.SetFulfillmentLabel(r.Context(), database.SetFulfillmentLabelParams{
+			LabelProvider:    sql.NullString{String: params.Provider, Valid: true},
+			LabelShipmentRef: sql.NullString{String: label.ShipmentRef, Valid: label.ShipmentRef != ""},
+			LabelUrl:         sql.NullString{String: label.URL, Valid: true},
+			LabelCostCents:   sql.NullInt64{Int64: label.CostCents, Valid: true},
+			LabelCurrency:    sql.NullString{String: label.Currency, Valid: label.Currency != ""},
+			Carrier:          sql.NullString{String: label.Carrier, Valid: label.Carrier != ""},
+			TrackingNumber:   sql.NullString{String: label.TrackingNumber, Valid: label.TrackingNumber != ""},
+			TrackingUrl:      trackingURL,
+			ID:               f.ID,
+			OrderID:          order.ID,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = recordOrderEvent(r.Context(), q, locked, orders.EventFulfillment,
+			fmt.Sprintf("Shipping label bought from %s", params.Provider),
+			map[string]any{"fulfillment_id": f.ID, "carrier": label.Carrier, "tracking_number": label.TrackingNumber}, user)
+		return err
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// Another request bought one first; this label is left to void
+		slog.WarnContext(r.Context(), "duplicate shipping label bought",
+			"request_id", reqID,
+			"fulfillment_id", f.ID,
+			"provider", params.Provider,
+			"shipment_ref", label.ShipmentRef,
+		)
+		respondWithError(w, http.StatusConflict, "Fulfillment already has a shipping label", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to record shipping label", err)
+		return
+	}
+
+	items, err := cfg.orderFulfillmentItems(r.Context(), order.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve fulfillment line items", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant shipping label bought",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"order_id", order.ID,
+		"fulfillment_id", f.ID,
+		"provider", params.Provider,
+		"carrier", label.Carrier,
+	)
+
+	respondWithJSON(w, http.StatusCreated, fulfillmentToResponse(f, items[f.ID]))
+}
+
+// handlerShippingTrackingWebhook takes the tracking updates a label
+// provider sends for the labels bought from it. The provider's signature
+// is the only credential. A parcel in transit marks its fulfillment
+// shipped and a delivered one marks it delivered.
+func (cfg *apiConfig) handlerShippingTrackingWebhook(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	name := chi.URLParam(r, "provider")
+	provider, ok := cfg.labels.Lookup(name)
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "Unknown shipping label provider", nil)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, labels.MaxWebhookBytes))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Unable to read webhook", err)
+		return
+	}
+	event, ok, err := provider.ParseWebhook(r, body)
+	if errors.Is(err, labels.ErrInvalidSignature) {
+		metrics.WebhookFailed(uuid.Nil, name)
+		respondWithError(w, http.StatusUnauthorized, "Invalid webhook signature", err)
+		return
+	}
+	if err != nil {
+		metrics.WebhookFailed(uuid.Nil, name)
+		respondWithError(w, http.StatusBadRequest, "Unable to parse webhook", err)
+		return
+	}
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	fulfillments, err := cfg.db.GetFulfillmentsByLabelTracking(r.Context(), database.GetFulfillmentsByLabelTrackingParams{
+		LabelProvider:  sql.NullString{String: name, Valid: true},
+		TrackingNumber: sql.NullString{String: event.TrackingNumber, Valid: true},
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve fulfillments", err)
+		return
+	}
+	for _, f := range fulfillments {
+		if err := cfg.applyTrackingEvent(r.Context(), f, event); err != nil {
+			metrics.WebhookFailed(f.StoreID, name)
+			cfg.notifyTrackingFailed(r.Context(), name, f, event)
+			respondWithError(w, http.StatusInternalServerError, "Unable to apply tracking update", err)
+			return
+		}
+	}
+
+	slog.InfoContext(r.Context(), "shipping tracking update received",
+		"request_id", reqID,
+		"provider", name,
+		"tracking_number", event.TrackingNumber,
+		"tracking_status", event.Status,
+		"fulfillments", len(fulfillments),
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// notifyTrackingFailed tells the staff of f's store that a tracking
+// update for it could not be applied
+func (cfg *apiConfig) notifyTrackingFailed(ctx context.Context, provider string, f database.Fulfillment, event labels.TrackingEvent) {
+	store, err := cfg.db.GetStoreByID(ctx, f.StoreID)
+	if err != nil || !store.TenantID.Valid {
+		return
+	}
+	cfg.notify(ctx, notifications.Notification{
+		TenantID: store.TenantID.UUID,
+		StoreID:  store.ID,
+		Kind:     notifications.KindWebhookFailed,
+		Title:    fmt.Sprintf("Tracking update from %s failed", provider),
+		Body:     fmt.Sprintf("The %s update for %s could not be applied to its fulfillment.", event.Status, event.TrackingNumber),
+		Data: map[string]any{
+			"provider":       provider,
+			"order_id":       f.OrderID,
+			"fulfillment_id": f.ID,
+		},
+	})
+}
+
+// applyTrackingEvent records a carrier update on f and moves f to the
+// status the update implies, when it may. Updates older than the last one
+// recorded are ignored.
+func (cfg *apiConfig) applyTrackingEvent(ctx context.Context, f database.Fulfillment, event labels.TrackingEvent) error {
+	return cfg.withTx(ctx, func(q *database.Queries) error {
+		order, err := q.LockOrderForUpdate(ctx, database.LockOrderForUpdateParams{
+			ID:      f.OrderID,
+			StoreID: f.StoreID,
+		})
+		if err != nil {
+			return err
+		}
+
+		f, err = q.SetFulfillmentTrackingStatus(ctx, database.SetFulfillmentTrackingStatusParams{
+			TrackingStatus:    sql.NullString{String: string(event.Status), Valid: true},
+			TrackingUpdatedAt: event.OccurredAt,
+			ID:                f.ID,
+			OrderID:           f.OrderID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		next := event.Status.FulfillmentStatus()
+		if next == "" || fulfillment.CanTransition(fulfillment.Status(f.Status), next) != nil {
+			return nil
+		}
+		f, err = q.UpdateFulfillmentStatus(ctx, database.UpdateFulfillmentStatusParams{
+			Status:  string(next),
+			ID:      f.ID,
+			OrderID: f.OrderID,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = recordOrderEvent(ctx, q, order, orders.EventFulfillment,
+			fmt.Sprintf("Fulfillment marked as %s by carrier tracking", f.Status),
+			map[string]any{"fulfillment_id": f.ID, "status": f.Status, "tracking_status": event.Status, "detail": event.Detail}, uuid.Nil)
+		if err != nil {
+			return err
+		}
+		if next == fulfillment.StatusShipped {
+			err = cfg.queueOrderEmail(ctx, q, mailer.OrderEmailArgs{
+				Template:      mailer.TemplateOrderShipped,
+				OrderID:       order.ID,
+				StoreID:       order.StoreID,
+				FulfillmentID: f.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		_, err = syncOrderFulfillmentStatus(ctx, q, order)
+		return err
+	})
+}
+
+// normalizeLabelAddress trims a and checks it can be shipped to, recording
+// problems under field
+func normalizeLabelAddress(v *validate.Validator, field string, a labels.Address) labels.Address {
+	for _, s := range []*string{&a.Name, &a.Company, &a.Address1, &a.Address2, &a.City, &a.RegionCode, &a.PostalCode, &a.CountryCode, &a.Phone, &a.Email} {
+		*s = strings.TrimSpace(*s)
+	}
+	v.Required(field+".name", a.Name)
+	v.Required(field+".address1", a.Address1)
+	v.Required(field+".city", a.City)
+
+	country, err := address.NormalizeCountry(a.CountryCode)
+	if !v.Check(err == nil, field+".country_code", validate.CodeInvalid, "must be an ISO 3166-1 alpha-2 code") {
+		return a
+	}
+	a.CountryCode = country
+	region, err := address.NormalizeRegion(country, a.RegionCode)
+	if v.Check(err == nil, field+".region_code", validate.CodeInvalid, "is not a region of the country") {
+		a.RegionCode = region
+	}
+	return a
+}
+
+// orderShipTo reads the order's shipping address, which is kept in the
+// shape of a customer address
+func orderShipTo(order database.Order) (labels.Address, bool) {
+	if !order.ShippingAddress.Valid {
+		return labels.Address{}, false
+	}
+	var addr struct {
+		labels.Address
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+	}
+	if err := json.Unmarshal(order.ShippingAddress.RawMessage, &addr); err != nil {
+		return labels.Address{}, false
+	}
+	if addr.Name == "" {
+		addr.Name = strings.TrimSpace(addr.FirstName + " " + addr.LastName)
+	}
+
+	var v validate.Validator
+	to := normalizeLabelAddress(&v, "to", addr.Address)
+	return to, v.Valid()
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/shopify"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type ShopifyImportResponse struct {
+	ID         uuid.UUID           `json:"id"`
+	StoreID    uuid.UUID           `json:"store_id"`
+	Entity     string              `json:"entity"`
+	Format     string              `json:"format"`
+	Filename   *string             `json:"filename,omitempty"`
+	Status     string              `json:"status"`
+	Counts     ShopifyImportCounts `json:"counts"`
+	Error      *string             `json:"error,omitempty"`
+	CreatedAt  time.Time           `json:"created_at"`
+	StartedAt  *time.Time          `json:"started_at,omitempty"`
+	FinishedAt *time.Time          `json:"finished_at,omitempty"`
+}
+
+// ShopifyImportCounts tallies items by outcome; Total less the others is
+// what is still to be processed
+type ShopifyImportCounts struct {
+	Total   int32 `json:"total"`
+	Created int32 `json:"created"`
+	Updated int32 `json:"updated"`
+	Skipped int32 `json:"skipped"`
+	Failed  int32 `json:"failed"`
+}
+
+type ShopifyImportItemResponse struct {
+	// Position is the line an item starts on in a CSV, or its place in a
+	// JSON list counting from 1
+	Position int32  `json:"position"`
+	Status   string `json:"status"`
+	// Reference is the item's handle, email or order name in Shopify
+	Reference *string              `json:"reference,omitempty"`
+	TargetID  *uuid.UUID           `json:"target_id,omitempty"`
+	Errors    []problem.FieldError `json:"errors"`
+}
+
+// ShopifyImportReportResponse is an import with a page of its items
+type ShopifyImportReportResponse struct {
+	Import ShopifyImportResponse       `json:"import"`
+	Items  []ShopifyImportItemResponse `json:"items"`
+	Page   PageInfo                    `json:"page"`
+}
+
+type ShopifyImportItemCursor struct {
+	Position int32 `json:"position"`
+}
+
+var shopifyImportItemCursorCodec = CursorCodec[ShopifyImportItemCursor]{
+	Validate: func(c ShopifyImportItemCursor) error {
+		if c.Position < 1 {
+			return errors.New("invalid cursor: missing required fields")
+		}
+		return nil
+	},
+}
+
+// handlerTenantShopifyImportCreate accepts a Shopify export of products,
+// customers or orders, as CSV or Admin API JSON, and queues it for the
+// worker. Like a product import, the file is checked up front and may be
+// the request body or the file field of a multipart form.
+func (cfg *apiConfig) handlerTenantShopifyImportCreate(entity string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqID := middleware.GetRequestID(r.Context())
+
+		access := tenantAccessFrom(r)
+		user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+		r.Body = http.MaxBytesReader(w, r.Body, shopify.MaxBytes)
+		data, filename, err := readImportFile(r)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				respondWithError(w, http.StatusRequestEntityTooLarge, "Shopify files must be 25MB or smaller", nil)
+				return
+			}
+			respondWithError(w, http.StatusBadRequest, "Please upload a Shopify CSV or JSON export", err)
+			return
+		}
+
+		format := shopify.DetectFormat(filename, r.Header.Get("Content-Type"), data)
+		total, err := shopify.Count(entity, format, data)
+		if err != nil {
+			respondWithError(w, http.StatusUnprocessableEntity, err.Error(), nil)
+			return
+		}
+
+		var created database.CreateShopifyImportRow
+		err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+			var err error
+			created, err = q.CreateShopifyImport(r.Context(), database.CreateShopifyImportParams{
+				TenantID:   tenantID,
+				StoreID:    storeID,
+				CreatedBy:  uuid.NullUUID{UUID: user, Valid: true},
+				Entity:     entity,
+				Format:     format,
+				Filename:   sql.NullString{String: filename, Valid: filename != ""},
+				Data:       data,
+				TotalItems: int32(total),
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = cfg.jobs.EnqueueTx(r.Context(), q, shopify.Args{ImportID: created.ID})
+			return err
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to create import", err)
+			return
+		}
+
+		slog.InfoContext(r.Context(), "tenant shopify import queued",
+			"request_id", reqID,
+			"user_id", user,
+			"tenant_id", tenantID,
+			"store_id", storeID,
+			"import_id", created.ID,
+			"entity", entity,
+			"format", format,
+			"items", total,
+		)
+
+		respondWithJSON(w, http.StatusAccepted, shopifyImportToResponse(database.GetShopifyImportRow(created), database.CountShopifyImportItemsRow{}))
+	}
+}
+
+// handlerTenantShopifyImportGet reports an import's progress and a page of
+// its item results in file order. ?status=failed lists only the items that
+// need fixing.
+func (cfg *apiConfig) handlerTenantShopifyImportGet(entity string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		storeID := tenantAccessFrom(r).StoreID
+
+		importID, err := uuid.Parse(chi.URLParam(r, "importID"))
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid import ID", err)
+			return
+		}
+
+		status := r.URL.Query().Get("status")
+		switch status {
+		case "", shopify.ItemCreated, shopify.ItemUpdated, shopify.ItemSkipped, shopify.ItemFailed:
+		default:
+			respondWithError(w, http.StatusBadRequest, "status must be created, updated, skipped or failed", nil)
+			return
+		}
+
+		pageParams, err := ParsePageParams(r, 100, 500)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+			return
+		}
+
+		cursor, _, err := shopifyImportItemCursorCodec.Decode(pageParams.Cursor)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+			return
+		}
+
+		imp, err := cfg.db.GetShopifyImport(r.Context(), database.GetShopifyImportParams{
+			ID:      importID,
+			StoreID: storeID,
+			Entity:  entity,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				respondWithError(w, http.StatusNotFound, "Import not found", nil)
+				return
+			}
+			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve import", err)
+			return
+		}
+
+		counts, err := cfg.db.CountShopifyImportItems(r.Context(), imp.ID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve import", err)
+			return
+		}
+
+		items, err := cfg.db.ListShopifyImportItems(r.Context(), database.ListShopifyImportItemsParams{
+			ImportID:      imp.ID,
+			Status:        sql.NullString{String: status, Valid: status != ""},
+			AfterPosition: cursor.Position,
+			RowLimit:      int32(pageParams.Limit + 1),
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve import items", err)
+			return
+		}
+
+		hasMore := len(items) > pageParams.Limit
+		if hasMore {
+			items = items[:pageParams.Limit]
+		}
+
+		var nextCursor string
+		if hasMore && len(items) > 0 {
+			nextCursor, err = shopifyImportItemCursorCodec.Encode(ShopifyImportItemCursor{Position: items[len(items)-1].Position})
+			if err != nil {
+				respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+				return
+			}
+		}
+
+		report := make([]ShopifyImportItemResponse, 0, len(items))
+		for _, item := range items {
+			report = append(report, shopifyImportItemToResponse(item))
+		}
+
+		respondWithJSON(w, http.StatusOK, ShopifyImportReportResponse{
+			Import: shopifyImportToResponse(imp, counts),
+			Items:  report,
+			Page:   PageInfo{Limit: pageParams.Limit, NextCursor: nextCursor, HasMore: hasMore},
+		})
+	}
+}
+
+func shopifyImportToResponse(imp database.GetShopifyImportRow, counts database.CountShopifyImportItemsRow) ShopifyImportResponse {
+	resp := ShopifyImportResponse{
+		ID:      imp.ID,
+		StoreID: imp.StoreID,
+		Entity:  imp.Entity,
+		Format:  imp.Format,
+		Status:  imp.Status,
+		Counts: ShopifyImportCounts{
+			Total:   imp.TotalItems,
+			Created: counts.Created,
+			Updated: counts.Updated,
+			Skipped: counts.Skipped,
+			Failed:  counts.Failed,
+		},
+		CreatedAt: imp.CreatedAt,
+	}
+	if imp.Filename.Valid {
+		resp.Filename = &imp.Filename.String
+	}
+	if imp.Error.Valid {
+		resp.Error = &imp.Error.String
+	}
+	if imp.StartedAt.Valid {
+		resp.StartedAt = &imp.StartedAt.Time
+	}
+	if imp.FinishedAt.Valid {
+		resp.FinishedAt = &imp.FinishedAt.Time
+	}
+	return resp
+}
+
+func shopifyImportItemToResponse(item database.ShopifyImportItem) ShopifyImportItemResponse {
+	resp := ShopifyImportItemResponse{
+		Position: item.Position,
+		Status:   item.Status,
+		Errors:   []problem.FieldError{},
+	}
+	if item.Reference.Valid {
+		resp.Reference = &item.Reference.String
+	}
+	if item.TargetID.Valid {
+		resp.TargetID = &item.TargetID.UUID
+	}
+	// Written by the worker from the same type, so a decode failure would
+	// only lose the detail, not the item's status
+	_ = json.Unmarshal(item.Errors, &resp.Errors)
+	return resp
+}
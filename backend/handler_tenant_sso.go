@@ -0,0 +1,487 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/crypto"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/saml"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/service/roles"
+	"github.com/dfodeker/terminus/internal/sso"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type SSOConnectionResponse struct {
+	ID               uuid.UUID        `json:"id"`
+	TenantID         uuid.UUID        `json:"tenant_id"`
+	Protocol         string           `json:"protocol"`
+	Enabled          bool             `json:"enabled"`
+	SAML             *SSOSAMLSettings `json:"saml,omitempty"`
+	OIDC             *SSOOIDCSettings `json:"oidc,omitempty"`
+	AttributeMapping sso.Mapping      `json:"attribute_mapping"`
+	DefaultRoleID    *uuid.UUID       `json:"default_role_id"`
+	JITProvisioning  bool             `json:"jit_provisioning"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+}
+
+// SSOSAMLSettings are what the identity provider's metadata says about it,
+// and what its administrator needs to set the tenant up there
+type SSOSAMLSettings struct {
+	IdPEntityID   string `json:"idp_entity_id"`
+	IdPSSOURL     string `json:"idp_sso_url"`
+	SPEntityID    string `json:"sp_entity_id"`
+	ACSURL        string `json:"acs_url"`
+	SPMetadataURL string `json:"sp_metadata_url"`
+}
+
+// SSOOIDCSettings are the connection's OpenID Connect client; the secret
+// is never sent back
+type SSOOIDCSettings struct {
+	Issuer      string `json:"issuer"`
+	ClientID    string `json:"client_id"`
+	RedirectURL string `json:"redirect_url"`
+}
+
+type SSODomainResponse struct {
+	ID     uuid.UUID `json:"id"`
+	Domain string    `json:"domain"`
+	// TXTRecord is what to publish in a TXT record on the domain to
+	// verify it
+	TXTRecord  string     `json:"txt_record"`
+	VerifiedAt *time.Time `json:"verified_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (cfg *apiConfig) ssoConnectionToResponse(c database.SsoConnection) SSOConnectionResponse {
+	resp := SSOConnectionResponse{
+		ID:              c.ID,
+		TenantID:        c.TenantID,
+		Protocol:        c.Protocol,
+		Enabled:         c.Enabled,
+		JITProvisioning: c.JitProvisioning,
+		CreatedAt:       c.CreatedAt,
+		UpdatedAt:       c.UpdatedAt,
+	}
+	json.Unmarshal(c.AttributeMapping, &resp.AttributeMapping)
+	if c.DefaultRoleID.Valid {
+		resp.DefaultRoleID = &c.DefaultRoleID.UUID
+	}
+	switch c.Protocol {
+	case sso.ProtocolSAML:
+		sp := cfg.samlServiceProvider(c, saml.IdP{})
+		resp.SAML = &SSOSAMLSettings{
+			IdPEntityID:   c.SamlEntityID.String,
+			IdPSSOURL:     c.SamlSsoUrl.String,
+			SPEntityID:    sp.EntityID,
+			ACSURL:        sp.ACSURL,
+			SPMetadataURL: sp.EntityID,
+		}
+	case sso.ProtocolOIDC:
+		resp.OIDC = &SSOOIDCSettings{
+			Issuer:      c.OidcIssuer.String,
+			ClientID:    c.OidcClientID.String,
+			RedirectURL: cfg.config.OAuth.RedirectURL,
+		}
+	}
+	return resp
+}
+
+func ssoDomainToResponse(d database.SsoDomain) SSODomainResponse {
+	resp := SSODomainResponse{
+		ID:        d.ID,
+		Domain:    d.Domain,
+		TXTRecord: sso.TXTRecord(d.VerificationToken),
+		CreatedAt: d.CreatedAt,
+	}
+	if d.VerifiedAt.Valid {
+		resp.VerifiedAt = &d.VerifiedAt.Time
+	}
+	return resp
+}
+
+// handlerTenantSSOGet returns the tenant's identity provider connection
+func (cfg *apiConfig) handlerTenantSSOGet(w http.ResponseWriter, r *http.Request) {
+	tenantID := tenantAccessFrom(r).TenantID
+
+	conn, err := cfg.db.GetSSOConnectionByTenant(r.Context(), tenantID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Single sign-on is not set up for this tenant", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve SSO connection", err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, cfg.ssoConnectionToResponse(conn))
+}
+
+// handlerTenantSSOUpdate sets up or replaces the tenant's identity provider
+// connection. A SAML provider is described by its metadata; an OpenID
+// Connect one by its issuer and a client registered there. The metadata
+// and client secret are kept when left out, so settings can be changed
+// without uploading them again.
+func (cfg *apiConfig) handlerTenantSSOUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID := access.UserID, access.TenantID
+
+	type parameters struct {
+		Protocol         string      `json:"protocol"`
+		Enabled          *bool       `json:"enabled"`
+		MetadataXML      string      `json:"metadata_xml"`
+		OIDCIssuer       string      `json:"oidc_issuer"`
+		OIDCClientID     string      `json:"oidc_client_id"`
+		OIDCClientSecret string      `json:"oidc_client_secret"`
+		AttributeMapping sso.Mapping `json:"attribute_mapping"`
+		DefaultRoleID    *uuid.UUID  `json:"default_role_id"`
+		JITProvisioning  bool        `json:"jit_provisioning"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	// Both protocols finish signing in through the app's OAuth callback
+	if cfg.config.OAuth.RedirectURL == "" {
+		respondWithErrorCode(w, http.StatusServiceUnavailable, problem.CodeUnavailable, "Single sign-on is not available on this server", nil)
+		return
+	}
+
+	existing, err := cfg.db.GetSSOConnectionByTenant(r.Context(), tenantID)
+	hasExisting := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve SSO connection", err)
+		return
+	}
+
+	arg := database.UpsertSSOConnectionParams{
+		TenantID:        tenantID,
+		Protocol:        params.Protocol,
+		Enabled:         params.Enabled == nil || *params.Enabled,
+		JitProvisioning: params.JITProvisioning,
+	}
+
+	var v validate.Validator
+	if v.OneOf("protocol", params.Protocol, sso.ProtocolSAML, sso.ProtocolOIDC) {
+		switch params.Protocol {
+		case sso.ProtocolSAML:
+			metadata := params.MetadataXML
+			if metadata == "" && hasExisting && existing.Protocol == sso.ProtocolSAML {
+				metadata = existing.SamlMetadata.String
+			}
+			if v.Required("metadata_xml", metadata) {
+				idp, err := saml.ParseMetadata([]byte(metadata))
+				if v.Check(err == nil, "metadata_xml", validate.CodeInvalid, "must be identity provider metadata with a signing certificate and an https HTTP-Redirect sign-on service") {
+					arg.SamlMetadata = sql.NullString{String: metadata, Valid: true}
+					arg.SamlEntityID = sql.NullString{String: idp.EntityID, Valid: true}
+					arg.SamlSsoUrl = sql.NullString{String: idp.SSOURL, Valid: true}
+				}
+			}
+		case sso.ProtocolOIDC:
+			issuer := strings.TrimSuffix(strings.TrimSpace(params.OIDCIssuer), "/")
+			if v.Required("oidc_issuer", issuer) {
+				u, err := url.Parse(issuer)
+				v.Check(err == nil && u.Scheme == "https" && u.Host != "", "oidc_issuer", validate.CodeFormat, "must be an https URL")
+			}
+			v.Required("oidc_client_id", params.OIDCClientID)
+			secret := params.OIDCClientSecret
+			if secret == "" && hasExisting && existing.Protocol == sso.ProtocolOIDC {
+				secret = existing.OidcClientSecret.String
+			}
+			v.Required("oidc_client_secret", secret)
+			arg.OidcIssuer = sql.NullString{String: issuer, Valid: true}
+			arg.OidcClientID = sql.NullString{String: strings.TrimSpace(params.OIDCClientID), Valid: true}
+			arg.OidcClientSecret = crypto.NullText{String: secret, Valid: true}
+		}
+	}
+
+	// The owner role is never handed out by an identity provider
+	if params.DefaultRoleID != nil {
+		role, err := cfg.db.GetRoleByTenantAndID(r.Context(), database.GetRoleByTenantAndIDParams{
+			TenantID: tenantID,
+			ID:       *params.DefaultRoleID,
+		})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusInternalServerError, "Unable to retrieve role", err)
+			return
+		}
+		if v.Check(err == nil, "default_role_id", validate.CodeInvalid, "must be a role in this tenant") &&
+			v.Check(role.Name != roles.OwnerTemplate.Name, "default_role_id", validate.CodeInvalid, "can't be the owner role") {
+			arg.DefaultRoleID = uuid.NullUUID{UUID: role.ID, Valid: true}
+		}
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	if !arg.Enabled && cfg.ssoLockout(w, r, tenantID) {
+		return
+	}
+
+	arg.AttributeMapping, err = json.Marshal(params.AttributeMapping)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to save SSO connection", err)
+		return
+	}
+	conn, err := cfg.db.UpsertSSOConnection(r.Context(), arg)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to save SSO connection", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant sso connection saved",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"connection_id", conn.ID,
+		"protocol", conn.Protocol,
+		"enabled", conn.Enabled,
+	)
+
+	respondWithJSON(w, http.StatusOK, cfg.ssoConnectionToResponse(conn))
+}
+
+// handlerTenantSSODelete removes the tenant's identity provider connection
+// and the sign-ins linked to it
+func (cfg *apiConfig) handlerTenantSSODelete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID := access.UserID, access.TenantID
+
+	conn, err := cfg.db.GetSSOConnectionByTenant(r.Context(), tenantID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Single sign-on is not set up for this tenant", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve SSO connection", err)
+		return
+	}
+	if cfg.ssoLockout(w, r, tenantID) {
+		return
+	}
+
+	err = cfg.withTenantTx(r.Context(), tenantID, func(q *database.Queries) error {
+		if err := q.DeleteUserIdentitiesByProvider(r.Context(), sso.ProviderPrefix+conn.ID.String()); err != nil {
+			return err
+		}
+		_, err := q.DeleteSSOConnection(r.Context(), tenantID)
+		return err
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to delete SSO connection", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant sso connection deleted",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"connection_id", conn.ID,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ssoLockout refuses to take away the tenant's identity provider while
+// the tenant requires SSO and there is no other provider for its members
+// to sign in through. It reports whether it responded.
+func (cfg *apiConfig) ssoLockout(w http.ResponseWriter, r *http.Request, tenantID uuid.UUID) bool {
+	tenant, err := cfg.db.GetTenantByID(r.Context(), tenantID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve tenant", err)
+		return true
+	}
+	if tenant.RequireSso && len(cfg.oauth) == 0 {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeConflict, "Turn off the tenant's SSO requirement first, or members will have no way to sign in", nil)
+		return true
+	}
+	return false
+}
+
+// handlerTenantSSODomainsList lists the email domains the tenant claims
+func (cfg *apiConfig) handlerTenantSSODomainsList(w http.ResponseWriter, r *http.Request) {
+	tenantID := tenantAccessFrom(r).TenantID
+
+	rows, err := cfg.db.ListSSODomains(r.Context(), tenantID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve SSO domains", err)
+		return
+	}
+	resp := make([]SSODomainResponse, 0, len(rows))
+	for _, row := range rows {
+		resp = append(resp, ssoDomainToResponse(row))
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handlerTenantSSODomainCreate claims an email domain for the tenant's
+// identity provider. It counts once verified through DNS.
+func (cfg *apiConfig) handlerTenantSSODomainCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID := access.UserID, access.TenantID
+
+	type parameters struct {
+		Domain string `json:"domain"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	var v validate.Validator
+	domain, err := sso.NormalizeDomain(params.Domain)
+	if v.Required("domain", params.Domain) {
+		v.Check(err == nil, "domain", validate.CodeFormat, "must be a domain name, such as example.com")
+	}
+	if err := v.Err(); err != nil {
+		respondWithValidationError(w, err)
+		return
+	}
+
+	token, err := sso.NewVerificationToken()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to add SSO domain", err)
+		return
+	}
+	row, err := cfg.db.CreateSSODomain(r.Context(), database.CreateSSODomainParams{
+		TenantID:          tenantID,
+		Domain:            domain,
+		VerificationToken: token,
+	})
+	if service.UniqueViolation(err, "") {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeAlreadyExists, "The tenant has already added this domain", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to add SSO domain", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant sso domain added",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"domain", domain,
+	)
+
+	respondWithJSON(w, http.StatusCreated, ssoDomainToResponse(row))
+}
+
+// handlerTenantSSODomainVerify checks the domain publishes its
+// verification TXT record
+func (cfg *apiConfig) handlerTenantSSODomainVerify(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID := access.UserID, access.TenantID
+
+	row, ok := cfg.ssoDomainFromRequest(w, r, tenantID)
+	if !ok {
+		return
+	}
+	if row.VerifiedAt.Valid {
+		respondWithJSON(w, http.StatusOK, ssoDomainToResponse(row))
+		return
+	}
+
+	err := sso.VerifyDomain(r.Context(), cfg.resolver, row.Domain, row.VerificationToken)
+	if errors.Is(err, sso.ErrNotVerified) {
+		respondWithErrorCode(w, http.StatusUnprocessableEntity, problem.CodeUnprocessable,
+			"The TXT record "+sso.TXTRecord(row.VerificationToken)+" was not found on "+row.Domain+"; DNS changes can take a while to show up", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadGateway, "Unable to look up the domain", err)
+		return
+	}
+
+	row, err = cfg.db.MarkSSODomainVerified(r.Context(), row.ID)
+	if service.UniqueViolation(err, "") {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeConflict, "Another tenant has already verified this domain", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to verify SSO domain", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant sso domain verified",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"domain", row.Domain,
+	)
+
+	respondWithJSON(w, http.StatusOK, ssoDomainToResponse(row))
+}
+
+// handlerTenantSSODomainDelete gives up a domain. Its users can no longer
+// sign in through the tenant's identity provider.
+func (cfg *apiConfig) handlerTenantSSODomainDelete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID := access.UserID, access.TenantID
+
+	row, ok := cfg.ssoDomainFromRequest(w, r, tenantID)
+	if !ok {
+		return
+	}
+	if _, err := cfg.db.DeleteSSODomain(r.Context(), database.DeleteSSODomainParams{TenantID: tenantID, ID: row.ID}); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to delete SSO domain", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "tenant sso domain deleted",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"domain", row.Domain,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ssoDomainFromRequest loads the tenant's domain named by {domainID}
+func (cfg *apiConfig) ssoDomainFromRequest(w http.ResponseWriter, r *http.Request, tenantID uuid.UUID) (database.SsoDomain, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "domainID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid domain ID format", err)
+		return database.SsoDomain{}, false
+	}
+	row, err := cfg.db.GetSSODomain(r.Context(), database.GetSSODomainParams{TenantID: tenantID, ID: id})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "SSO domain not found", nil)
+			return database.SsoDomain{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve SSO domain", err)
+		return database.SsoDomain{}, false
+	}
+	return row, true
+}
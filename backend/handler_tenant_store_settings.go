@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/google/uuid"
+)
+
+// handlerTenantStoreSettingsGet returns a store's settings with every
+// default filled in
+func (cfg *apiConfig) handlerTenantStoreSettingsGet(w http.ResponseWriter, r *http.Request) {
+	access := tenantAccessFrom(r)
+
+	settings, err := cfg.services.stores.Settings(r.Context(), access.TenantID, access.StoreID)
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to retrieve store settings")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, settings)
+}
+
+// handlerTenantStoreSettingsUpdate takes a JSON merge patch: only the fields
+// in the body change, and null puts a field back to its default
+func (cfg *apiConfig) handlerTenantStoreSettingsUpdate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	var patch json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	// The audit log records what the patch changed
+	store, err := cfg.db.GetStoreByTenantAndID(r.Context(), database.GetStoreByTenantAndIDParams{
+		TenantID: uuid.NullUUID{UUID: access.TenantID, Valid: true},
+		ID:       access.StoreID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update store settings", err)
+		return
+	}
+	before, err := cfg.services.stores.Settings(r.Context(), access.TenantID, access.StoreID)
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to update store settings")
+		return
+	}
+
+	settings, err := cfg.services.stores.UpdateSettings(r.Context(), access.TenantID, access.StoreID, patch)
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to update store settings")
+		return
+	}
+	auditChange(r, auditGID(gid.EntityStore, store.Gid), before, settings)
+
+	slog.InfoContext(r.Context(), "store settings updated",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"store_id", access.StoreID,
+	)
+
+	respondWithJSON(w, http.StatusOK, settings)
+}
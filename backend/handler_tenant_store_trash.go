@@ -0,0 +1,144 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// handlerTenantStoreDelete hides a store and everything in it, including
+// its storefront. It can be restored until the worker purges it.
+func (cfg *apiConfig) handlerTenantStoreDelete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	store, err := cfg.services.stores.Delete(r.Context(), access.TenantID, access.StoreID)
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to delete store")
+		return
+	}
+	auditChange(r, auditGID(gid.EntityStore, store.Gid), tenantStoreToResponse(store), nil)
+
+	slog.InfoContext(r.Context(), "store deleted",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"store_id", store.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, tenantStoreToResponse(store))
+}
+
+// handlerTenantStoresDeletedList lists a tenant's deleted stores that have
+// not been purged yet
+func (cfg *apiConfig) handlerTenantStoresDeletedList(w http.ResponseWriter, r *http.Request) {
+	tenantID := tenantAccessFrom(r).TenantID
+
+	pageParams, err := ParsePageParams(r, defaultStoreLimit, maxStoreLimit)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+
+	cursor, hasCursor, err := deletedCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.db.ListDeletedStores(r.Context(), database.ListDeletedStoresParams{
+		TenantID:        uuid.NullUUID{UUID: tenantID, Valid: true},
+		HasCursor:       hasCursor,
+		CursorDeletedAt: cursor.DeletedAt,
+		CursorID:        cursor.ID,
+		RowLimit:        int32(pageParams.Limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve deleted stores", err)
+		return
+	}
+
+	hasMore := len(rows) > pageParams.Limit
+	if hasMore {
+		rows = rows[:pageParams.Limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = deletedCursorCodec.Encode(DeletedCursor{
+			DeletedAt: last.DeletedAt.Time,
+			ID:        last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]TenantStoreResponse, 0, len(rows))
+	for _, store := range rows {
+		response = append(response, tenantStoreToResponse(store))
+	}
+
+	respondWithJSON(w, http.StatusOK, PageResponse[TenantStoreResponse]{
+		Data: response,
+		Page: PageInfo{Limit: pageParams.Limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
+
+// handlerTenantStoreRestore brings a deleted store back as it was. The
+// route names the store deletedStoreID, since requirePermission turns away
+// a storeID that isn't live.
+func (cfg *apiConfig) handlerTenantStoreRestore(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	storeID, err := uuid.Parse(chi.URLParam(r, "deletedStoreID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid store ID format", err)
+		return
+	}
+
+	// Kept for the audit log
+	trashed, err := cfg.db.GetDeletedStore(r.Context(), database.GetDeletedStoreParams{
+		ID:       storeID,
+		TenantID: uuid.NullUUID{UUID: access.TenantID, Valid: true},
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Deleted store not found", nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to restore store", err)
+		return
+	}
+
+	if err := cfg.services.entitlements.CheckStores(r.Context(), access.TenantID); err != nil {
+		respondWithServiceError(w, err, "Unable to restore store")
+		return
+	}
+
+	store, err := cfg.services.stores.Restore(r.Context(), access.TenantID, storeID)
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to restore store")
+		return
+	}
+	auditChange(r, auditGID(gid.EntityStore, store.Gid), tenantStoreToResponse(trashed), tenantStoreToResponse(store))
+
+	slog.InfoContext(r.Context(), "store restored",
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"store_id", store.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, tenantStoreToResponse(store))
+}
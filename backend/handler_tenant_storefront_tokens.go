@@ -0,0 +1,311 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	maxStorefrontTokenNameLength = 100
+	// maxStorefrontTokenGracePeriod bounds how long a rotated token keeps
+	// working alongside its replacement
+	maxStorefrontTokenGracePeriod = 7 * 24 * time.Hour
+)
+
+type StorefrontTokenResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	StoreID     uuid.UUID  `json:"store_id"`
+	Kind        string     `json:"kind"`
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	Scopes      []string   `json:"scopes"`
+	CreatedBy   uuid.UUID  `json:"created_by"`
+	RotatedFrom *uuid.UUID `json:"rotated_from"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	LastUsedIP  *string    `json:"last_used_ip"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// CreateStorefrontTokenResponse carries the full token. It is never shown
+// again.
+type CreateStorefrontTokenResponse struct {
+	StorefrontTokenResponse
+	Token string `json:"token"`
+}
+
+// handlerTenantStorefrontTokensCreate issues a storefront access token for
+// the store
+func (cfg *apiConfig) handlerTenantStorefrontTokensCreate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	type parameters struct {
+		Kind   string   `json:"kind"`
+		Name   string   `json:"name"`
+		Scopes []string `json:"scopes"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+
+	name := strings.TrimSpace(params.Name)
+	if name == "" || len(name) > maxStorefrontTokenNameLength {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Name is required and must be at most %d characters", maxStorefrontTokenNameLength), nil)
+		return
+	}
+
+	kind := params.Kind
+	if kind == "" {
+		kind = auth.StorefrontTokenPublishable
+	}
+
+	scopes := make([]string, 0, len(params.Scopes))
+	for _, s := range params.Scopes {
+		s = strings.TrimSpace(s)
+		if s != "" && !slices.Contains(scopes, s) {
+			scopes = append(scopes, s)
+		}
+	}
+	slices.Sort(scopes)
+	if len(scopes) == 0 {
+		respondWithError(w, http.StatusBadRequest, "At least one scope is required", nil)
+		return
+	}
+	if err := auth.ValidateStorefrontScopes(kind, scopes); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	raw, token, err := cfg.createStorefrontToken(r, cfg.db, database.CreateStorefrontTokenParams{
+		StoreID:   storeID,
+		Kind:      kind,
+		Name:      name,
+		Scopes:    scopes,
+		CreatedBy: user,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to create storefront token", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "storefront token created",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"storefront_token_id", token.ID,
+		"kind", kind,
+		"scopes", scopes,
+	)
+
+	respondWithJSON(w, http.StatusCreated, CreateStorefrontTokenResponse{
+		StorefrontTokenResponse: storefrontTokenToResponse(token),
+		Token:                   raw,
+	})
+}
+
+// handlerTenantStorefrontTokensList lists the store's tokens that still work
+func (cfg *apiConfig) handlerTenantStorefrontTokensList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	tokens, err := cfg.db.ListStoreStorefrontTokens(r.Context(), storeID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve storefront tokens", err)
+		return
+	}
+
+	response := make([]StorefrontTokenResponse, 0, len(tokens))
+	for _, t := range tokens {
+		response = append(response, storefrontTokenToResponse(t))
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{"data": response})
+}
+
+// handlerTenantStorefrontTokenRotate replaces a token with a new one of the
+// same kind, name and scopes. The old token stops working after an
+// optional grace period, so deployed storefronts can switch over.
+func (cfg *apiConfig) handlerTenantStorefrontTokenRotate(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	tokenID, err := uuid.Parse(chi.URLParam(r, "tokenID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid storefront token ID format", err)
+		return
+	}
+
+	type parameters struct {
+		GracePeriodSeconds int `json:"grace_period_seconds"`
+	}
+
+	// The body is optional
+	params := parameters{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil && !errors.Is(err, io.EOF) {
+		respondWithError(w, http.StatusBadRequest, "Please provide a valid request body", err)
+		return
+	}
+	grace := time.Duration(params.GracePeriodSeconds) * time.Second
+	if grace < 0 || grace > maxStorefrontTokenGracePeriod {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("grace_period_seconds must be between 0 and %d", int(maxStorefrontTokenGracePeriod.Seconds())), nil)
+		return
+	}
+
+	var old, token database.StorefrontToken
+	var raw string
+	err = cfg.withTenantTx(r.Context(), access.TenantID, func(q *database.Queries) error {
+		if grace == 0 {
+			old, err = q.RevokeStorefrontToken(r.Context(), database.RevokeStorefrontTokenParams{
+				ID:      tokenID,
+				StoreID: storeID,
+			})
+		} else {
+			old, err = q.ExpireStorefrontToken(r.Context(), database.ExpireStorefrontTokenParams{
+				ExpiresAt: sql.NullTime{Time: time.Now().Add(grace), Valid: true},
+				ID:        tokenID,
+				StoreID:   storeID,
+			})
+		}
+		if err != nil {
+			return service.NotFoundAs(err, "Storefront token not found")
+		}
+
+		raw, token, err = cfg.createStorefrontToken(r, q, database.CreateStorefrontTokenParams{
+			StoreID:     storeID,
+			Kind:        old.Kind,
+			Name:        old.Name,
+			Scopes:      old.Scopes,
+			CreatedBy:   user,
+			RotatedFrom: uuid.NullUUID{UUID: old.ID, Valid: true},
+		})
+		return err
+	})
+	if err != nil {
+		respondWithServiceError(w, err, "Unable to rotate storefront token")
+		return
+	}
+
+	slog.InfoContext(r.Context(), "storefront token rotated",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"storefront_token_id", token.ID,
+		"rotated_from", old.ID,
+		"grace_period", grace,
+	)
+
+	respondWithJSON(w, http.StatusCreated, CreateStorefrontTokenResponse{
+		StorefrontTokenResponse: storefrontTokenToResponse(token),
+		Token:                   raw,
+	})
+}
+
+// handlerTenantStorefrontTokenRevoke revokes a storefront token at once
+func (cfg *apiConfig) handlerTenantStorefrontTokenRevoke(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
+
+	tokenID, err := uuid.Parse(chi.URLParam(r, "tokenID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid storefront token ID format", err)
+		return
+	}
+
+	token, err := cfg.db.RevokeStorefrontToken(r.Context(), database.RevokeStorefrontTokenParams{
+		ID:      tokenID,
+		StoreID: storeID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Storefront token not found", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to revoke storefront token", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "storefront token revoked",
+		"request_id", reqID,
+		"user_id", user,
+		"tenant_id", tenantID,
+		"store_id", storeID,
+		"storefront_token_id", token.ID,
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// createStorefrontToken generates a token, fills in its GID, prefix and
+// hash, and stores it. It returns the raw token alongside the record.
+func (cfg *apiConfig) createStorefrontToken(r *http.Request, q *database.Queries, arg database.CreateStorefrontTokenParams) (string, database.StorefrontToken, error) {
+	raw, prefix, err := auth.MakeStorefrontToken(arg.Kind)
+	if err != nil {
+		return "", database.StorefrontToken{}, err
+	}
+	arg.Gid = sql.NullInt64{Int64: int64(cfg.gidGen.Generate()), Valid: true}
+	arg.Prefix = prefix
+	arg.TokenHash = auth.HashToken(raw)
+
+	token, err := q.CreateStorefrontToken(r.Context(), arg)
+	if err != nil {
+		return "", database.StorefrontToken{}, err
+	}
+	return raw, token, nil
+}
+
+func storefrontTokenToResponse(t database.StorefrontToken) StorefrontTokenResponse {
+	resp := StorefrontTokenResponse{
+		ID:        t.ID,
+		StoreID:   t.StoreID,
+		Kind:      t.Kind,
+		Name:      t.Name,
+		Prefix:    t.Prefix,
+		Scopes:    t.Scopes,
+		CreatedBy: t.CreatedBy,
+		CreatedAt: t.CreatedAt,
+	}
+	if resp.Scopes == nil {
+		resp.Scopes = []string{}
+	}
+	if t.RotatedFrom.Valid {
+		resp.RotatedFrom = &t.RotatedFrom.UUID
+	}
+	if t.LastUsedAt.Valid {
+		resp.LastUsedAt = &t.LastUsedAt.Time
+	}
+	if t.LastUsedIp.Valid {
+		resp.LastUsedIP = &t.LastUsedIp.String
+	}
+	if t.ExpiresAt.Valid {
+		resp.ExpiresAt = &t.ExpiresAt.Time
+	}
+	return resp
+}
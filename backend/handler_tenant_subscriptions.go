@@ -0,0 +1,293 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/subscriptions"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// SubscriptionResponse is a customer's subscription to a variant. The
+// saved payment method itself is never returned, only its provider.
+type SubscriptionResponse struct {
+	ID              uuid.UUID  `json:"id"`
+	CustomerID      uuid.UUID  `json:"customer_id"`
+	SellingPlanID   *uuid.UUID `json:"selling_plan_id,omitempty"`
+	VariantID       *uuid.UUID `json:"variant_id,omitempty"`
+	Quantity        int32      `json:"quantity"`
+	PriceCents      int64      `json:"price_cents"`
+	Currency        string     `json:"currency"`
+	IntervalUnit    string     `json:"interval_unit"`
+	IntervalCount   int32      `json:"interval_count"`
+	PaymentProvider string     `json:"payment_provider"`
+	Status          string     `json:"status"`
+	NextBillingAt   *time.Time `json:"next_billing_at,omitempty"`
+	FailedAttempts  int32      `json:"failed_attempts"`
+	LastFailure     *string    `json:"last_failure,omitempty"`
+	PausedAt        *time.Time `json:"paused_at,omitempty"`
+	CancelledAt     *time.Time `json:"cancelled_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// SubscriptionCursor is the keyset position for subscription pagination
+type SubscriptionCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+}
+
+var subscriptionCursorCodec = CursorCodec[SubscriptionCursor]{
+	Validate: func(c SubscriptionCursor) error {
+		if c.CreatedAt.IsZero() || c.ID == uuid.Nil {
+			return errors.New("incomplete cursor")
+		}
+		return nil
+	},
+}
+
+// handlerTenantSubscriptionsList lists a store's subscriptions newest
+// first, optionally only those with ?status=
+func (cfg *apiConfig) handlerTenantSubscriptionsList(w http.ResponseWriter, r *http.Request) {
+	storeID := tenantAccessFrom(r).StoreID
+
+	pageParams, err := ParsePageParams(r, 50, 100)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
+		return
+	}
+	limit := pageParams.Limit
+
+	status := r.URL.Query().Get("status")
+	if status != "" && !subscriptions.Status(status).IsValid() {
+		respondWithError(w, http.StatusBadRequest, "Invalid status filter", nil)
+		return
+	}
+
+	cursor, hasCursor, err := subscriptionCursorCodec.Decode(pageParams.Cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid cursor", err)
+		return
+	}
+
+	rows, err := cfg.db.GetSubscriptionContractsPaginated(r.Context(), database.GetSubscriptionContractsPaginatedParams{
+		StoreID:         storeID,
+		Status:          status,
+		HasCursor:       hasCursor,
+		CursorCreatedAt: cursor.CreatedAt,
+		CursorID:        cursor.ID,
+		RowLimit:        int32(limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve subscriptions", err)
+		return
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor, err = subscriptionCursorCodec.Encode(SubscriptionCursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Unable to build pagination cursor", err)
+			return
+		}
+	}
+
+	response := make([]SubscriptionResponse, 0, len(rows))
+	for _, c := range rows {
+		response = append(response, subscriptionToResponse(c))
+	}
+
+	respondWithJSON(w, http.StatusOK, PageResponse[SubscriptionResponse]{
+		Data: response,
+		Page: PageInfo{Limit: limit, NextCursor: nextCursor, HasMore: hasMore},
+	})
+}
+
+// handlerTenantSubscriptionGet returns a subscription
+func (cfg *apiConfig) handlerTenantSubscriptionGet(w http.ResponseWriter, r *http.Request) {
+	contract, ok := cfg.loadSubscription(w, r)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, subscriptionToResponse(contract))
+}
+
+// handlerTenantSubscriptionOrdersList lists the orders a subscription's
+// renewals placed, latest first
+func (cfg *apiConfig) handlerTenantSubscriptionOrdersList(w http.ResponseWriter, r *http.Request) {
+	contract, ok := cfg.loadSubscription(w, r)
+	if !ok {
+		return
+	}
+
+	rows, err := cfg.db.ListSubscriptionOrders(r.Context(), contract.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve subscription orders", err)
+		return
+	}
+
+	response := make([]OrderResponse, 0, len(rows))
+	for _, order := range rows {
+		response = append(response, orderToResponse(order, nil))
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]any{"data": response})
+}
+
+// handlerTenantSubscriptionPause stops an active subscription renewing
+// until it is resumed
+func (cfg *apiConfig) handlerTenantSubscriptionPause(w http.ResponseWriter, r *http.Request) {
+	cfg.tenantSubscriptionTransition(w, r, subscriptions.StatusPaused)
+}
+
+// handlerTenantSubscriptionResume starts a paused or failed subscription
+// renewing again from its next renewal date still ahead
+func (cfg *apiConfig) handlerTenantSubscriptionResume(w http.ResponseWriter, r *http.Request) {
+	cfg.tenantSubscriptionTransition(w, r, subscriptions.StatusActive)
+}
+
+// handlerTenantSubscriptionCancel ends a subscription for good
+func (cfg *apiConfig) handlerTenantSubscriptionCancel(w http.ResponseWriter, r *http.Request) {
+	cfg.tenantSubscriptionTransition(w, r, subscriptions.StatusCancelled)
+}
+
+func (cfg *apiConfig) tenantSubscriptionTransition(w http.ResponseWriter, r *http.Request, to subscriptions.Status) {
+	reqID := middleware.GetRequestID(r.Context())
+	access := tenantAccessFrom(r)
+
+	existing, ok := cfg.loadSubscription(w, r)
+	if !ok {
+		return
+	}
+
+	contract, err := cfg.transitionSubscription(r.Context(), existing, to)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithErrorCode(w, http.StatusConflict, problem.CodeInvalidStatusTransition, "Subscription cannot be "+subscriptionTransitionVerb(to)+" while "+existing.Status, nil)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to update subscription", err)
+		return
+	}
+	resp := subscriptionToResponse(contract)
+	auditChange(r, auditGID(gid.EntitySubscription, contract.Gid), subscriptionToResponse(existing), resp)
+
+	slog.InfoContext(r.Context(), "subscription "+subscriptionTransitionVerb(to),
+		"request_id", reqID,
+		"user_id", access.UserID,
+		"tenant_id", access.TenantID,
+		"subscription_id", contract.ID,
+	)
+
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// transitionSubscription moves a contract to status to. It returns
+// sql.ErrNoRows when the contract cannot get there from where it is.
+func (cfg *apiConfig) transitionSubscription(ctx context.Context, c database.SubscriptionContract, to subscriptions.Status) (database.SubscriptionContract, error) {
+	switch to {
+	case subscriptions.StatusPaused:
+		return cfg.db.PauseSubscriptionContract(ctx, c.ID)
+	case subscriptions.StatusCancelled:
+		return cfg.db.CancelSubscriptionContract(ctx, c.ID)
+	default:
+		interval := subscriptions.Interval{Unit: subscriptions.Unit(c.IntervalUnit), Count: int(c.IntervalCount)}
+		next := interval.NextCycle(c.BillingAnchorAt, int(c.NextCycle), time.Now())
+		return cfg.db.ResumeSubscriptionContract(ctx, database.ResumeSubscriptionContractParams{
+			ID:            c.ID,
+			NextCycle:     int32(next),
+			NextBillingAt: interval.At(c.BillingAnchorAt, next),
+		})
+	}
+}
+
+func subscriptionTransitionVerb(to subscriptions.Status) string {
+	switch to {
+	case subscriptions.StatusPaused:
+		return "paused"
+	case subscriptions.StatusCancelled:
+		return "cancelled"
+	default:
+		return "resumed"
+	}
+}
+
+// loadSubscription loads the {subscriptionID} of the route from the store
+// the caller was let into. It has responded when it returns false.
+func (cfg *apiConfig) loadSubscription(w http.ResponseWriter, r *http.Request) (database.SubscriptionContract, bool) {
+	contractID, err := uuid.Parse(chi.URLParam(r, "subscriptionID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid subscription ID format", err)
+		return database.SubscriptionContract{}, false
+	}
+
+	contract, err := cfg.db.GetSubscriptionContractByID(r.Context(), database.GetSubscriptionContractByIDParams{
+		ID:      contractID,
+		StoreID: tenantAccessFrom(r).StoreID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Subscription not found", nil)
+		return database.SubscriptionContract{}, false
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve subscription", err)
+		return database.SubscriptionContract{}, false
+	}
+	return contract, true
+}
+
+func subscriptionToResponse(c database.SubscriptionContract) SubscriptionResponse {
+	resp := SubscriptionResponse{
+		ID:              c.ID,
+		CustomerID:      c.CustomerID,
+		Quantity:        c.Quantity,
+		PriceCents:      c.PriceCents,
+		Currency:        c.Currency,
+		IntervalUnit:    c.IntervalUnit,
+		IntervalCount:   c.IntervalCount,
+		PaymentProvider: c.PaymentProvider,
+		Status:          c.Status,
+		FailedAttempts:  c.FailedAttempts,
+		CreatedAt:       c.CreatedAt,
+		UpdatedAt:       c.UpdatedAt,
+	}
+	if c.SellingPlanID.Valid {
+		resp.SellingPlanID = &c.SellingPlanID.UUID
+	}
+	if c.VariantID.Valid {
+		resp.VariantID = &c.VariantID.UUID
+	}
+	// Only an active subscription has a renewal coming
+	if c.Status == string(subscriptions.StatusActive) {
+		resp.NextBillingAt = &c.NextBillingAt
+	}
+	if c.LastFailure.Valid {
+		resp.LastFailure = &c.LastFailure.String
+	}
+	if c.PausedAt.Valid {
+		resp.PausedAt = &c.PausedAt.Time
+	}
+	if c.CancelledAt.Valid {
+		resp.CancelledAt = &c.CancelledAt.Time
+	}
+	return resp
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/usage"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/google/uuid"
+)
+
+// usageMonthLayout is how ?month= is written
+const usageMonthLayout = "2006-01"
+
+type usageTotalResponse struct {
+	Metric   usage.Metric `json:"metric"`
+	Used     int64        `json:"used"`
+	Included int64        `json:"included"`
+	// Unit is how much of the metric one unit of overage is
+	Unit         int64 `json:"unit"`
+	OverageUnits int64 `json:"overage_units"`
+}
+
+type usageDayResponse struct {
+	Metric   usage.Metric `json:"metric"`
+	Day      string       `json:"day"`
+	Quantity int64        `json:"quantity"`
+}
+
+type usageResponse struct {
+	Month   string               `json:"month"`
+	Plan    string               `json:"plan"`
+	Metrics []usageTotalResponse `json:"metrics"`
+	Days    []usageDayResponse   `json:"days"`
+}
+
+// handlerTenantUsage reports what the tenant used in a month, ?month=
+// (YYYY-MM, this month by default), against its plan's allowance. API calls
+// are a few seconds behind and orders and storage up to a quarter hour.
+func (cfg *apiConfig) handlerTenantUsage(w http.ResponseWriter, r *http.Request) {
+	cfg.respondWithUsage(w, r, tenantAccessFrom(r).TenantID)
+}
+
+// respondWithUsage answers with the tenant's use in the month ?month= names
+func (cfg *apiConfig) respondWithUsage(w http.ResponseWriter, r *http.Request, tenantID uuid.UUID) {
+	month := time.Now()
+	if s := r.URL.Query().Get("month"); s != "" {
+		var err error
+		month, err = time.Parse(usageMonthLayout, s)
+		if err != nil {
+			var v validate.Validator
+			v.Fail("month", validate.CodeInvalid, "must be a month such as 2026-01")
+			respondWithValidationError(w, v.Err())
+			return
+		}
+	}
+	from, to := usage.Month(month)
+
+	plan, err := cfg.services.entitlements.TenantPlan(r.Context(), tenantID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve plan", err)
+		return
+	}
+	rows, err := cfg.db.ListTenantUsage(r.Context(), database.ListTenantUsageParams{
+		TenantID: tenantID,
+		FromDay:  from,
+		ToDay:    to,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve usage", err)
+		return
+	}
+
+	resp := usageResponse{
+		Month:   from.Format(usageMonthLayout),
+		Plan:    plan.Name,
+		Metrics: []usageTotalResponse{},
+		Days:    make([]usageDayResponse, 0, len(rows)),
+	}
+	for _, t := range usage.Totals(rows, plan.Name) {
+		resp.Metrics = append(resp.Metrics, usageTotalResponse{
+			Metric:       t.Metric,
+			Used:         t.Used,
+			Included:     t.Included,
+			Unit:         t.Metric.Unit(),
+			OverageUnits: t.OverageUnits,
+		})
+	}
+	for _, row := range rows {
+		resp.Days = append(resp.Days, usageDayResponse{
+			Metric:   usage.Metric(row.Metric),
+			Day:      row.Day.Format(time.DateOnly),
+			Quantity: row.Quantity,
+		})
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+}
@@ -9,9 +9,13 @@ import (
 	"time"
 
 	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/validate"
 	"github.com/dfodeker/terminus/middleware"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
 )
 
 type VariantResponse struct {
@@ -26,10 +30,18 @@ type VariantResponse struct {
 	CompareAtCents *int32          `json:"compare_at_cents,omitempty"`
 	OptionValues   json.RawMessage `json:"option_values"`
 	Status         string          `json:"status"`
+	WeightGrams    *int32          `json:"weight_grams,omitempty"`
 	CreatedAt      time.Time       `json:"created_at"`
 	UpdatedAt      time.Time       `json:"updated_at"`
+	// Version is bumped by every write; send it back to pin an update to
+	// what was read
+	Version int64 `json:"version"`
+	// DeletedAt is only set on variants in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
+const variantVersionConflict = "The variant was changed by another request, please reload it and retry"
+
 type VariantCursor struct {
 	CreatedAt time.Time `json:"created_at"`
 	ID        uuid.UUID `json:"id"`
@@ -44,6 +56,93 @@ var variantCursorCodec = CursorCodec[VariantCursor]{
 	},
 }
 
+// variantStatuses are the states a variant can be in
+var variantStatuses = []string{"active", "disabled"}
+
+// Variant field limits
+const (
+	maxVariantSKULength     = 100
+	maxVariantBarcodeLength = 64
+	maxVariantTitleLength   = 255
+	// maxPriceCents keeps prices well inside the int32 columns
+	maxPriceCents = 100_000_000
+)
+
+// variantFields are the fields a variant create or update sets, nil
+// meaning not given
+type variantFields struct {
+	SKU            *string
+	Barcode        *string
+	Title          *string
+	PriceCents     *int32
+	CompareAtCents *int32
+	OptionValues   *json.RawMessage
+	Status         *string
+	WeightGrams    *int32
+}
+
+// validate checks every given field, reporting all that are invalid
+func (f variantFields) validate() error {
+	var v validate.Validator
+	f.check(&v, "")
+	return v.Err()
+}
+
+// check records the given fields that are invalid, their names prefixed
+// with prefix, such as overrides.2.
+func (f variantFields) check(v *validate.Validator, prefix string) {
+	if f.SKU != nil {
+		v.MaxLength(prefix+"sku", *f.SKU, maxVariantSKULength)
+	}
+	if f.Barcode != nil {
+		v.MaxLength(prefix+"barcode", *f.Barcode, maxVariantBarcodeLength)
+	}
+	if f.Title != nil && v.Required(prefix+"title", *f.Title) {
+		v.MaxLength(prefix+"title", *f.Title, maxVariantTitleLength)
+	}
+	if f.PriceCents != nil {
+		v.Between(prefix+"price_cents", int64(*f.PriceCents), 0, maxPriceCents)
+	}
+	if f.CompareAtCents != nil {
+		v.Between(prefix+"compare_at_cents", int64(*f.CompareAtCents), 0, maxPriceCents)
+	}
+	if f.OptionValues != nil {
+		var values map[string]any
+		v.Check(json.Unmarshal(*f.OptionValues, &values) == nil, prefix+"option_values", validate.CodeInvalid,
+			"must be an object of option names to values")
+	}
+	if f.Status != nil {
+		v.OneOf(prefix+"status", *f.Status, variantStatuses...)
+	}
+	if f.WeightGrams != nil {
+		v.Min(prefix+"weight_grams", int64(*f.WeightGrams), 0)
+	}
+}
+
+// updateParams sets the given fields of a variant and keeps the rest. With
+// a version, the update only applies while the variant still has it.
+func (f variantFields) updateParams(id, productID, storeID uuid.UUID, version *int64) database.UpdateProductVariantParams {
+	arg := database.UpdateProductVariantParams{
+		ID:             id,
+		ProductID:      productID,
+		StoreID:        storeID,
+		Sku:            nullStringFromPtr(f.SKU),
+		Barcode:        nullStringFromPtr(f.Barcode),
+		Title:          nullStringFromPtr(f.Title),
+		PriceCents:     nullInt32FromPtr(f.PriceCents),
+		CompareAtCents: nullInt32FromPtr(f.CompareAtCents),
+		Status:         nullStringFromPtr(f.Status),
+		WeightGrams:    nullInt32FromPtr(f.WeightGrams),
+	}
+	if f.OptionValues != nil {
+		arg.OptionValues = pqtype.NullRawMessage{RawMessage: *f.OptionValues, Valid: true}
+	}
+	if version != nil {
+		arg.Version = sql.NullInt64{Int64: *version, Valid: true}
+	}
+	return arg
+}
+
 // handlerTenantVariantCreate creates a variant for a product
 func (cfg *apiConfig) handlerTenantVariantCreate(w http.ResponseWriter, r *http.Request) {
 	reqID := middleware.GetRequestID(r.Context())
@@ -58,23 +157,8 @@ func (cfg *apiConfig) handlerTenantVariantCreate(w http.ResponseWriter, r *http.
 		"product_param", productParam,
 	)
 
-	user, ok := userFromContext(r.Context())
-	if !ok {
-		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
-		return
-	}
-
-	tenantID, err := uuid.Parse(tenantParam)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid tenant ID format", err)
-		return
-	}
-
-	storeID, err := uuid.Parse(storeParam)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid store ID format", err)
-		return
-	}
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
 
 	productID, err := uuid.Parse(productParam)
 	if err != nil {
@@ -82,36 +166,6 @@ func (cfg *apiConfig) handlerTenantVariantCreate(w http.ResponseWriter, r *http.
 		return
 	}
 
-	// Verify user has permission to create products (variants are part of products)
-	hasPermission, err := cfg.db.CheckUserHasPermission(r.Context(), database.CheckUserHasPermissionParams{
-		TenantID: tenantID,
-		UserID:   user,
-		Key:      "products:create",
-	})
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify permissions", err)
-		return
-	}
-
-	if !hasPermission {
-		respondWithError(w, http.StatusForbidden, "You do not have permission to create variants", nil)
-		return
-	}
-
-	// Verify store belongs to tenant
-	_, err = cfg.db.GetStoreByTenantAndID(r.Context(), database.GetStoreByTenantAndIDParams{
-		TenantID: uuid.NullUUID{UUID: tenantID, Valid: true},
-		ID:       storeID,
-	})
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			respondWithError(w, http.StatusNotFound, "Store not found in this tenant", nil)
-			return
-		}
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify store", err)
-		return
-	}
-
 	// Verify product exists and belongs to store
 	_, err = cfg.db.GetProductByID(r.Context(), database.GetProductByIDParams{
 		ID:      productID,
@@ -134,6 +188,7 @@ func (cfg *apiConfig) handlerTenantVariantCreate(w http.ResponseWriter, r *http.
 		CompareAtCents *int32          `json:"compare_at_cents"`
 		OptionValues   json.RawMessage `json:"option_values"`
 		Status         string          `json:"status"`
+		WeightGrams    *int32          `json:"weight_grams"`
 	}
 
 	decoder := json.NewDecoder(r.Body)
@@ -144,8 +199,24 @@ func (cfg *apiConfig) handlerTenantVariantCreate(w http.ResponseWriter, r *http.
 		return
 	}
 
-	if params.PriceCents < 0 {
-		respondWithError(w, http.StatusBadRequest, "Price must be non-negative", nil)
+	fields := variantFields{
+		SKU:            params.SKU,
+		Barcode:        params.Barcode,
+		PriceCents:     &params.PriceCents,
+		CompareAtCents: params.CompareAtCents,
+		WeightGrams:    params.WeightGrams,
+	}
+	if params.Title != "" {
+		fields.Title = &params.Title
+	}
+	if params.Status != "" {
+		fields.Status = &params.Status
+	}
+	if params.OptionValues != nil {
+		fields.OptionValues = &params.OptionValues
+	}
+	if err := fields.validate(); err != nil {
+		respondWithValidationError(w, err)
 		return
 	}
 
@@ -179,6 +250,11 @@ func (cfg *apiConfig) handlerTenantVariantCreate(w http.ResponseWriter, r *http.
 		compareAtCents = sql.NullInt32{Int32: *params.CompareAtCents, Valid: true}
 	}
 
+	weightGrams := sql.NullInt32{}
+	if params.WeightGrams != nil {
+		weightGrams = sql.NullInt32{Int32: *params.WeightGrams, Valid: true}
+	}
+
 	variant, err := cfg.db.CreateProductVariant(r.Context(), database.CreateProductVariantParams{
 		TenantID:       tenantID,
 		StoreID:        storeID,
@@ -190,6 +266,7 @@ func (cfg *apiConfig) handlerTenantVariantCreate(w http.ResponseWriter, r *http.
 		CompareAtCents: compareAtCents,
 		OptionValues:   optionValues,
 		Status:         status,
+		WeightGrams:    weightGrams,
 	})
 	if err != nil {
 		slog.ErrorContext(r.Context(), "tenant variant creation failed: database error",
@@ -209,8 +286,12 @@ func (cfg *apiConfig) handlerTenantVariantCreate(w http.ResponseWriter, r *http.
 		"variant_id", variant.ID,
 	)
 
+	// Price-based rules depend on the product's active variants
+	cfg.syncProductCollectionsAfterWrite(r.Context(), variant.StoreID, variant.ProductID)
+
 	var skuPtr, barcodePtr *string
 	var compareAtPtr *int32
+	var weightPtr *int32
 	if variant.Sku.Valid {
 		skuPtr = &variant.Sku.String
 	}
@@ -220,7 +301,11 @@ func (cfg *apiConfig) handlerTenantVariantCreate(w http.ResponseWriter, r *http.
 	if variant.CompareAtCents.Valid {
 		compareAtPtr = &variant.CompareAtCents.Int32
 	}
+	if variant.WeightGrams.Valid {
+		weightPtr = &variant.WeightGrams.Int32
+	}
 
+	setVersionETag(w, variant.Version)
 	respondWithJSON(w, http.StatusCreated, VariantResponse{
 		ID:             variant.ID,
 		TenantID:       variant.TenantID,
@@ -233,8 +318,10 @@ func (cfg *apiConfig) handlerTenantVariantCreate(w http.ResponseWriter, r *http.
 		CompareAtCents: compareAtPtr,
 		OptionValues:   variant.OptionValues,
 		Status:         variant.Status,
+		WeightGrams:    weightPtr,
 		CreatedAt:      variant.CreatedAt,
 		UpdatedAt:      variant.UpdatedAt,
+		Version:        variant.Version,
 	})
 }
 
@@ -252,23 +339,8 @@ func (cfg *apiConfig) handlerTenantVariantsList(w http.ResponseWriter, r *http.R
 		"product_param", productParam,
 	)
 
-	user, ok := userFromContext(r.Context())
-	if !ok {
-		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
-		return
-	}
-
-	tenantID, err := uuid.Parse(tenantParam)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid tenant ID format", err)
-		return
-	}
-
-	storeID, err := uuid.Parse(storeParam)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid store ID format", err)
-		return
-	}
+	access := tenantAccessFrom(r)
+	user, tenantID := access.UserID, access.TenantID
 
 	productID, err := uuid.Parse(productParam)
 	if err != nil {
@@ -276,36 +348,6 @@ func (cfg *apiConfig) handlerTenantVariantsList(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	// Verify user has permission to view products
-	hasPermission, err := cfg.db.CheckUserHasPermission(r.Context(), database.CheckUserHasPermissionParams{
-		TenantID: tenantID,
-		UserID:   user,
-		Key:      "products:view",
-	})
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify permissions", err)
-		return
-	}
-
-	if !hasPermission {
-		respondWithError(w, http.StatusForbidden, "You do not have permission to view variants", nil)
-		return
-	}
-
-	// Verify store belongs to tenant
-	_, err = cfg.db.GetStoreByTenantAndID(r.Context(), database.GetStoreByTenantAndIDParams{
-		TenantID: uuid.NullUUID{UUID: tenantID, Valid: true},
-		ID:       storeID,
-	})
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			respondWithError(w, http.StatusNotFound, "Store not found in this tenant", nil)
-			return
-		}
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify store", err)
-		return
-	}
-
 	pageParams, err := ParsePageParams(r, 50, 100)
 	if err != nil {
 		respondWithError(w, http.StatusBadRequest, "Invalid pagination parameters", err)
@@ -322,11 +364,11 @@ func (cfg *apiConfig) handlerTenantVariantsList(w http.ResponseWriter, r *http.R
 	}
 
 	rows, err := cfg.db.GetProductVariantsByProductIDPaginated(r.Context(), database.GetProductVariantsByProductIDPaginatedParams{
-		ProductID: productID,
-		Column2:   hasCursor,
-		Column3:   cursorCreatedAt,
-		Column4:   cursorID,
-		Limit:     limitPlusOne,
+		ProductID:       productID,
+		HasCursor:       hasCursor,
+		CursorCreatedAt: cursorCreatedAt,
+		CursorID:        cursorID,
+		RowLimit:        limitPlusOne,
 	})
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Unable to retrieve variants", err)
@@ -355,6 +397,7 @@ func (cfg *apiConfig) handlerTenantVariantsList(w http.ResponseWriter, r *http.R
 	for _, variant := range rows {
 		var skuPtr, barcodePtr *string
 		var compareAtPtr *int32
+		var weightPtr *int32
 		if variant.Sku.Valid {
 			skuPtr = &variant.Sku.String
 		}
@@ -364,6 +407,9 @@ func (cfg *apiConfig) handlerTenantVariantsList(w http.ResponseWriter, r *http.R
 		if variant.CompareAtCents.Valid {
 			compareAtPtr = &variant.CompareAtCents.Int32
 		}
+		if variant.WeightGrams.Valid {
+			weightPtr = &variant.WeightGrams.Int32
+		}
 
 		response = append(response, VariantResponse{
 			ID:             variant.ID,
@@ -377,8 +423,10 @@ func (cfg *apiConfig) handlerTenantVariantsList(w http.ResponseWriter, r *http.R
 			CompareAtCents: compareAtPtr,
 			OptionValues:   variant.OptionValues,
 			Status:         variant.Status,
+			WeightGrams:    weightPtr,
 			CreatedAt:      variant.CreatedAt,
 			UpdatedAt:      variant.UpdatedAt,
+			Version:        variant.Version,
 		})
 	}
 
@@ -391,13 +439,9 @@ func (cfg *apiConfig) handlerTenantVariantsList(w http.ResponseWriter, r *http.R
 		"has_more", hasMore,
 	)
 
-	respondWithJSON(w, http.StatusOK, map[string]any{
-		"data": response,
-		"page": map[string]any{
-			"limit":       limit,
-			"has_more":    hasMore,
-			"next_cursor": nextCursor,
-		},
+	respondWithJSON(w, http.StatusOK, PageResponse[VariantResponse]{
+		Data: response,
+		Page: PageInfo{Limit: limit, NextCursor: nextCursor, HasMore: hasMore},
 	})
 }
 
@@ -417,23 +461,8 @@ func (cfg *apiConfig) handlerTenantVariantUpdate(w http.ResponseWriter, r *http.
 		"variant_param", variantParam,
 	)
 
-	user, ok := userFromContext(r.Context())
-	if !ok {
-		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
-		return
-	}
-
-	tenantID, err := uuid.Parse(tenantParam)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid tenant ID format", err)
-		return
-	}
-
-	storeID, err := uuid.Parse(storeParam)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid store ID format", err)
-		return
-	}
+	access := tenantAccessFrom(r)
+	user, tenantID := access.UserID, access.TenantID
 
 	productID, err := uuid.Parse(productParam)
 	if err != nil {
@@ -447,37 +476,7 @@ func (cfg *apiConfig) handlerTenantVariantUpdate(w http.ResponseWriter, r *http.
 		return
 	}
 
-	// Verify user has permission to edit products
-	hasPermission, err := cfg.db.CheckUserHasPermission(r.Context(), database.CheckUserHasPermissionParams{
-		TenantID: tenantID,
-		UserID:   user,
-		Key:      "products:edit",
-	})
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify permissions", err)
-		return
-	}
-
-	if !hasPermission {
-		respondWithError(w, http.StatusForbidden, "You do not have permission to edit variants", nil)
-		return
-	}
-
-	// Verify store belongs to tenant
-	_, err = cfg.db.GetStoreByTenantAndID(r.Context(), database.GetStoreByTenantAndIDParams{
-		TenantID: uuid.NullUUID{UUID: tenantID, Valid: true},
-		ID:       storeID,
-	})
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			respondWithError(w, http.StatusNotFound, "Store not found in this tenant", nil)
-			return
-		}
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify store", err)
-		return
-	}
-
-	// Get existing variant
+	// Kept for the audit log; the update itself reads nothing first
 	existingVariant, err := cfg.db.GetProductVariantByID(r.Context(), variantID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -502,6 +501,8 @@ func (cfg *apiConfig) handlerTenantVariantUpdate(w http.ResponseWriter, r *http.
 		CompareAtCents *int32           `json:"compare_at_cents"`
 		OptionValues   *json.RawMessage `json:"option_values"`
 		Status         *string          `json:"status"`
+		WeightGrams    *int32           `json:"weight_grams"`
+		Version        *int64           `json:"version"`
 	}
 
 	decoder := json.NewDecoder(r.Body)
@@ -512,53 +513,38 @@ func (cfg *apiConfig) handlerTenantVariantUpdate(w http.ResponseWriter, r *http.
 		return
 	}
 
-	// Use existing values if not provided
-	sku := existingVariant.Sku
-	if params.SKU != nil {
-		sku = sql.NullString{String: *params.SKU, Valid: true}
-	}
-
-	barcode := existingVariant.Barcode
-	if params.Barcode != nil {
-		barcode = sql.NullString{String: *params.Barcode, Valid: true}
-	}
-
-	title := existingVariant.Title
-	if params.Title != nil {
-		title = *params.Title
-	}
-
-	priceCents := existingVariant.PriceCents
-	if params.PriceCents != nil {
-		priceCents = *params.PriceCents
+	version, err := requestVersion(r, params.Version)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), nil)
+		return
 	}
 
-	compareAtCents := existingVariant.CompareAtCents
-	if params.CompareAtCents != nil {
-		compareAtCents = sql.NullInt32{Int32: *params.CompareAtCents, Valid: true}
+	fields := variantFields{
+		SKU:            params.SKU,
+		Barcode:        params.Barcode,
+		Title:          params.Title,
+		PriceCents:     params.PriceCents,
+		CompareAtCents: params.CompareAtCents,
+		OptionValues:   params.OptionValues,
+		Status:         params.Status,
+		WeightGrams:    params.WeightGrams,
 	}
-
-	optionValues := existingVariant.OptionValues
-	if params.OptionValues != nil {
-		optionValues = *params.OptionValues
+	if err := fields.validate(); err != nil {
+		respondWithValidationError(w, err)
+		return
 	}
 
-	status := existingVariant.Status
-	if params.Status != nil {
-		status = *params.Status
+	variant, err := cfg.db.UpdateProductVariant(r.Context(), fields.updateParams(variantID, productID, access.StoreID, version))
+	if errors.Is(err, sql.ErrNoRows) {
+		// It was there when read, so it has moved past the pinned version
+		// or been deleted since
+		if version != nil {
+			respondWithErrorCode(w, http.StatusConflict, problem.CodeVersionConflict, variantVersionConflict, nil)
+			return
+		}
+		respondWithError(w, http.StatusNotFound, "Variant not found", nil)
+		return
 	}
-
-	variant, err := cfg.db.UpdateProductVariant(r.Context(), database.UpdateProductVariantParams{
-		ID:             variantID,
-		ProductID:      productID,
-		Sku:            sku,
-		Barcode:        barcode,
-		Title:          title,
-		PriceCents:     priceCents,
-		CompareAtCents: compareAtCents,
-		OptionValues:   optionValues,
-		Status:         status,
-	})
 	if err != nil {
 		slog.ErrorContext(r.Context(), "tenant variant update failed: database error",
 			"request_id", reqID,
@@ -575,8 +561,12 @@ func (cfg *apiConfig) handlerTenantVariantUpdate(w http.ResponseWriter, r *http.
 		"variant_id", variant.ID,
 	)
 
+	cfg.syncProductCollectionsAfterWrite(r.Context(), variant.StoreID, variant.ProductID)
+	auditChange(r, auditGID(gid.EntityProductVariant, variant.Gid), variantToResponse(existingVariant), variantToResponse(variant))
+
 	var skuPtr, barcodePtr *string
 	var compareAtPtr *int32
+	var weightPtr *int32
 	if variant.Sku.Valid {
 		skuPtr = &variant.Sku.String
 	}
@@ -586,7 +576,11 @@ func (cfg *apiConfig) handlerTenantVariantUpdate(w http.ResponseWriter, r *http.
 	if variant.CompareAtCents.Valid {
 		compareAtPtr = &variant.CompareAtCents.Int32
 	}
+	if variant.WeightGrams.Valid {
+		weightPtr = &variant.WeightGrams.Int32
+	}
 
+	setVersionETag(w, variant.Version)
 	respondWithJSON(w, http.StatusOK, VariantResponse{
 		ID:             variant.ID,
 		TenantID:       variant.TenantID,
@@ -599,8 +593,10 @@ func (cfg *apiConfig) handlerTenantVariantUpdate(w http.ResponseWriter, r *http.
 		CompareAtCents: compareAtPtr,
 		OptionValues:   variant.OptionValues,
 		Status:         variant.Status,
+		WeightGrams:    weightPtr,
 		CreatedAt:      variant.CreatedAt,
 		UpdatedAt:      variant.UpdatedAt,
+		Version:        variant.Version,
 	})
 }
 
@@ -620,23 +616,8 @@ func (cfg *apiConfig) handlerTenantVariantDelete(w http.ResponseWriter, r *http.
 		"variant_param", variantParam,
 	)
 
-	user, ok := userFromContext(r.Context())
-	if !ok {
-		respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
-		return
-	}
-
-	tenantID, err := uuid.Parse(tenantParam)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid tenant ID format", err)
-		return
-	}
-
-	storeID, err := uuid.Parse(storeParam)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid store ID format", err)
-		return
-	}
+	access := tenantAccessFrom(r)
+	user, tenantID, storeID := access.UserID, access.TenantID, access.StoreID
 
 	productID, err := uuid.Parse(productParam)
 	if err != nil {
@@ -650,40 +631,14 @@ func (cfg *apiConfig) handlerTenantVariantDelete(w http.ResponseWriter, r *http.
 		return
 	}
 
-	// Verify user has permission to delete products
-	hasPermission, err := cfg.db.CheckUserHasPermission(r.Context(), database.CheckUserHasPermissionParams{
-		TenantID: tenantID,
-		UserID:   user,
-		Key:      "products:delete",
-	})
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify permissions", err)
-		return
-	}
-
-	if !hasPermission {
-		respondWithError(w, http.StatusForbidden, "You do not have permission to delete variants", nil)
-		return
-	}
-
-	// Verify store belongs to tenant
-	_, err = cfg.db.GetStoreByTenantAndID(r.Context(), database.GetStoreByTenantAndIDParams{
-		TenantID: uuid.NullUUID{UUID: tenantID, Valid: true},
-		ID:       storeID,
-	})
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			respondWithError(w, http.StatusNotFound, "Store not found in this tenant", nil)
-			return
-		}
-		respondWithError(w, http.StatusInternalServerError, "Unable to verify store", err)
-		return
-	}
-
-	err = cfg.db.DeleteProductVariant(r.Context(), database.DeleteProductVariantParams{
+	deleted, err := cfg.db.SoftDeleteProductVariant(r.Context(), database.SoftDeleteProductVariantParams{
 		ID:        variantID,
 		ProductID: productID,
 	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Variant not found", nil)
+		return
+	}
 	if err != nil {
 		slog.ErrorContext(r.Context(), "tenant variant delete failed: database error",
 			"request_id", reqID,
@@ -700,6 +655,9 @@ func (cfg *apiConfig) handlerTenantVariantDelete(w http.ResponseWriter, r *http.
 		"variant_id", variantID,
 	)
 
+	cfg.syncProductCollectionsAfterWrite(r.Context(), storeID, productID)
+	auditChange(r, auditGID(gid.EntityProductVariant, deleted.Gid), variantToResponse(deleted), nil)
+
 	respondWithJSON(w, http.StatusOK, map[string]any{
 		"message":    "Variant deleted successfully",
 		"variant_id": variantID,
@@ -716,3 +674,42 @@ func decodeVariantCursor(cursor string) (time.Time, uuid.UUID, bool, error) {
 	}
 	return cur.CreatedAt, cur.ID, true, nil
 }
+
+func variantToResponse(variant database.ProductVariant) VariantResponse {
+	var skuPtr, barcodePtr *string
+	var compareAtPtr, weightPtr *int32
+	if variant.Sku.Valid {
+		skuPtr = &variant.Sku.String
+	}
+	if variant.Barcode.Valid {
+		barcodePtr = &variant.Barcode.String
+	}
+	if variant.CompareAtCents.Valid {
+		compareAtPtr = &variant.CompareAtCents.Int32
+	}
+	if variant.WeightGrams.Valid {
+		weightPtr = &variant.WeightGrams.Int32
+	}
+
+	resp := VariantResponse{
+		ID:             variant.ID,
+		TenantID:       variant.TenantID,
+		StoreID:        variant.StoreID,
+		ProductID:      variant.ProductID,
+		SKU:            skuPtr,
+		Barcode:        barcodePtr,
+		Title:          variant.Title,
+		PriceCents:     variant.PriceCents,
+		CompareAtCents: compareAtPtr,
+		OptionValues:   variant.OptionValues,
+		Status:         variant.Status,
+		WeightGrams:    weightPtr,
+		CreatedAt:      variant.CreatedAt,
+		UpdatedAt:      variant.UpdatedAt,
+		Version:        variant.Version,
+	}
+	if variant.DeletedAt.Valid {
+		resp.DeletedAt = &variant.DeletedAt.Time
+	}
+	return resp
+}
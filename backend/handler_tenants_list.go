@@ -6,7 +6,6 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/dfodeker/terminus/internal/database"
 	"github.com/dfodeker/terminus/middleware"
 	"github.com/google/uuid"
 )
@@ -65,7 +64,6 @@ func (cfg *apiConfig) handlerTenantsList(w http.ResponseWriter, r *http.Request)
 	}
 
 	limit := pageParams.Limit
-	limitPlusOne := int32(pageParams.Limit + 1)
 
 	cursorCreatedAt, cursorID, hasCursor, err := decodeTenantCursor(pageParams.Cursor)
 	if err != nil {
@@ -86,13 +84,7 @@ func (cfg *apiConfig) handlerTenantsList(w http.ResponseWriter, r *http.Request)
 		"has_cursor", hasCursor,
 	)
 
-	rows, err := cfg.db.GetTenantsByUserIDPaginated(r.Context(), database.GetTenantsByUserIDPaginatedParams{
-		UserID:  user,
-		Column2: hasCursor,
-		Column3: cursorCreatedAt,
-		Column4: cursorID,
-		Limit:   limitPlusOne,
-	})
+	page, err := cfg.services.tenants.ListForUser(r.Context(), user, pageRequest(limit, cursorCreatedAt, cursorID, hasCursor))
 	if err != nil {
 		slog.ErrorContext(r.Context(), "tenant list failed: database query error",
 			"request_id", reqID,
@@ -103,10 +95,7 @@ func (cfg *apiConfig) handlerTenantsList(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	hasMore := len(rows) > limit
-	if hasMore {
-		rows = rows[:limit]
-	}
+	rows, hasMore := page.Items, page.HasMore
 
 	var nextCursor string
 	if hasMore && len(rows) > 0 {
@@ -144,13 +133,9 @@ func (cfg *apiConfig) handlerTenantsList(w http.ResponseWriter, r *http.Request)
 		"has_more", hasMore,
 	)
 
-	respondWithJSON(w, http.StatusOK, map[string]any{
-		"data": response,
-		"page": map[string]any{
-			"limit":       limit,
-			"has_more":    hasMore,
-			"next_cursor": nextCursor,
-		},
+	respondWithJSON(w, http.StatusOK, PageResponse[TenantResponse]{
+		Data: response,
+		Page: PageInfo{Limit: limit, NextCursor: nextCursor, HasMore: hasMore},
 	})
 }
 
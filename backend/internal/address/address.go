@@ -0,0 +1,98 @@
+package address
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalidCountry is returned for codes that are not ISO 3166-1 alpha-2
+	ErrInvalidCountry = errors.New("invalid country code")
+	// ErrInvalidRegion is returned for region codes unknown to the country
+	ErrInvalidRegion = errors.New("invalid region code")
+	// ErrRegionRequired is returned when the country needs a region and none was given
+	ErrRegionRequired = errors.New("region code is required for this country")
+)
+
+// NormalizeCountry upper-cases and validates an ISO 3166-1 alpha-2 country code
+func NormalizeCountry(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if _, ok := countries[code]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCountry, code)
+	}
+	return code, nil
+}
+
+// NormalizeRegion validates the subdivision part of an ISO 3166-2 code
+// (e.g. "CA" for US-CA) against a normalized country. Countries we hold a
+// subdivision list for require a known region; elsewhere the region is
+// optional and only checked for shape.
+func NormalizeRegion(country, region string) (string, error) {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	region = strings.TrimPrefix(region, country+"-")
+
+	known, hasList := regions[country]
+	if region == "" {
+		if hasList {
+			return "", fmt.Errorf("%w: %s", ErrRegionRequired, country)
+		}
+		return "", nil
+	}
+
+	if hasList {
+		if _, ok := known[region]; !ok {
+			return "", fmt.Errorf("%w: %q for %s", ErrInvalidRegion, region, country)
+		}
+		return region, nil
+	}
+
+	if len(region) > 3 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidRegion, region)
+	}
+	for _, c := range region {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return "", fmt.Errorf("%w: %q", ErrInvalidRegion, region)
+		}
+	}
+	return region, nil
+}
+
+func set(codes ...string) map[string]struct{} {
+	m := make(map[string]struct{}, len(codes))
+	for _, c := range codes {
+		m[c] = struct{}{}
+	}
+	return m
+}
+
+var countries = set(
+	"AD", "AE", "AF", "AG", "AI", "AL", "AM", "AO", "AQ", "AR", "AS", "AT", "AU", "AW", "AX", "AZ",
+	"BA", "BB", "BD", "BE", "BF", "BG", "BH", "BI", "BJ", "BL", "BM", "BN", "BO", "BQ", "BR", "BS",
+	"BT", "BV", "BW", "BY", "BZ", "CA", "CC", "CD", "CF", "CG", "CH", "CI", "CK", "CL", "CM", "CN",
+	"CO", "CR", "CU", "CV", "CW", "CX", "CY", "CZ", "DE", "DJ", "DK", "DM", "DO", "DZ", "EC", "EE",
+	"EG", "EH", "ER", "ES", "ET", "FI", "FJ", "FK", "FM", "FO", "FR", "GA", "GB", "GD", "GE", "GF",
+	"GG", "GH", "GI", "GL", "GM", "GN", "GP", "GQ", "GR", "GS", "GT", "GU", "GW", "GY", "HK", "HM",
+	"HN", "HR", "HT", "HU", "ID", "IE", "IL", "IM", "IN", "IO", "IQ", "IR", "IS", "IT", "JE", "JM",
+	"JO", "JP", "KE", "KG", "KH", "KI", "KM", "KN", "KP", "KR", "KW", "KY", "KZ", "LA", "LB", "LC",
+	"LI", "LK", "LR", "LS", "LT", "LU", "LV", "LY", "MA", "MC", "MD", "ME", "MF", "MG", "MH", "MK",
+	"ML", "MM", "MN", "MO", "MP", "MQ", "MR", "MS", "MT", "MU", "MV", "MW", "MX", "MY", "MZ", "NA",
+	"NC", "NE", "NF", "NG", "NI", "NL", "NO", "NP", "NR", "NU", "NZ", "OM", "PA", "PE", "PF", "PG",
+	"PH", "PK", "PL", "PM", "PN", "PR", "PS", "PT", "PW", "PY", "QA", "RE", "RO", "RS", "RU", "RW",
+	"SA", "SB", "SC", "SD", "SE", "SG", "SH", "SI", "SJ", "SK", "SL", "SM", "SN", "SO", "SR", "SS",
+	"ST", "SV", "SX", "SY", "SZ", "TC", "TD", "TF", "TG", "TH", "TJ", "TK", "TL", "TM", "TN", "TO",
+	"TR", "TT", "TV", "TW", "TZ", "UA", "UG", "UM", "US", "UY", "UZ", "VA", "VC", "VE", "VG", "VI",
+	"VN", "VU", "WF", "WS", "YE", "YT", "ZA", "ZM", "ZW",
+)
+
+// regions lists subdivisions for countries where carriers and tax need one
+var regions = map[string]map[string]struct{}{
+	"US": set(
+		"AL", "AK", "AZ", "AR", "CA", "CO", "CT", "DE", "DC", "FL", "GA", "HI", "ID", "IL", "IN", "IA",
+		"KS", "KY", "LA", "ME", "MD", "MA", "MI", "MN", "MS", "MO", "MT", "NE", "NV", "NH", "NJ", "NM",
+		"NY", "NC", "ND", "OH", "OK", "OR", "PA", "RI", "SC", "SD", "TN", "TX", "UT", "VT", "VA", "WA",
+		"WV", "WI", "WY", "AS", "GU", "MP", "PR", "VI", "UM", "AA", "AE", "AP",
+	),
+	"CA": set("AB", "BC", "MB", "NB", "NL", "NS", "NT", "NU", "ON", "PE", "QC", "SK", "YT"),
+	"AU": set("ACT", "NSW", "NT", "QLD", "SA", "TAS", "VIC", "WA"),
+}
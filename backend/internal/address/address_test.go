@@ -0,0 +1,69 @@
+package address
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeCountry(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		want    string
+		wantErr bool
+	}{
+		{"upper", "US", "US", false},
+		{"lower with spaces", " gb ", "GB", false},
+		{"alpha-3", "USA", "", true},
+		{"unknown", "XX", "", true},
+		{"empty", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeCountry(tt.code)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeCountry(%q) error = %v, wantErr %v", tt.code, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidCountry) {
+				t.Errorf("NormalizeCountry(%q) error = %v, want ErrInvalidCountry", tt.code, err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeCountry(%q) = %q, want %q", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeRegion(t *testing.T) {
+	tests := []struct {
+		name    string
+		country string
+		region  string
+		want    string
+		wantErr error
+	}{
+		{"us state", "US", "ca", "CA", nil},
+		{"iso 3166-2 form", "US", "US-NY", "NY", nil},
+		{"us unknown state", "US", "ZZ", "", ErrInvalidRegion},
+		{"us missing state", "US", "", "", ErrRegionRequired},
+		{"canada province", "CA", "QC", "QC", nil},
+		{"australia state", "AU", "nsw", "NSW", nil},
+		{"optional region omitted", "GB", "", "", nil},
+		{"free-form region", "FR", "75", "75", nil},
+		{"region too long", "FR", "PARIS", "", ErrInvalidRegion},
+		{"region bad characters", "DE", "B-E", "", ErrInvalidRegion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeRegion(tt.country, tt.region)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NormalizeRegion(%q, %q) error = %v, want %v", tt.country, tt.region, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeRegion(%q, %q) = %q, want %q", tt.country, tt.region, got, tt.want)
+			}
+		})
+	}
+}
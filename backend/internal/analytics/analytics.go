@@ -0,0 +1,264 @@
+// Package analytics builds the sales, product and channel reports staff
+// see from daily rollups. The rollups are kept by a Roller in the worker,
+// one day per store in the store timezone, so a report only covers days
+// that have ended.
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/google/uuid"
+)
+
+// DateLayout is how days are written in report parameters and responses
+const DateLayout = "2006-01-02"
+
+const (
+	// DefaultRangeDays is how many days a report covers without from
+	DefaultRangeDays = 30
+	// MaxRangeDays bounds the days one report covers
+	MaxRangeDays = 731
+)
+
+// Granularity is how a report groups days
+type Granularity string
+
+const (
+	Day   Granularity = "day"
+	Week  Granularity = "week"
+	Month Granularity = "month"
+)
+
+// Granularities lists every granularity
+var Granularities = []string{string(Day), string(Week), string(Month)}
+
+// Valid reports whether g is a known granularity
+func (g Granularity) Valid() bool {
+	return g == Day || g == Week || g == Month
+}
+
+// PeriodStart is the first day of the period of g holding day. Weeks
+// start on Monday.
+func (g Granularity) PeriodStart(day time.Time) time.Time {
+	switch g {
+	case Week:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case Month:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+func (g Granularity) next(start time.Time) time.Time {
+	switch g {
+	case Week:
+		return start.AddDate(0, 0, 7)
+	case Month:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// Date is the day t falls on in loc, at midnight UTC as date columns are
+// read
+func Date(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// Range is the days a report covers, both ends included
+type Range struct {
+	From time.Time
+	To   time.Time
+}
+
+// ParseRange reads from and to as dates. Without to the range ends
+// yesterday, the last day rolled up, and without from it covers
+// DefaultRangeDays.
+func ParseRange(from, to string, today time.Time) (Range, error) {
+	r := Range{To: today.AddDate(0, 0, -1)}
+	if to != "" {
+		t, err := time.Parse(DateLayout, to)
+		if err != nil {
+			return Range{}, fmt.Errorf("to must be a date such as %s", DateLayout)
+		}
+		r.To = t
+	}
+	r.From = r.To.AddDate(0, 0, -(DefaultRangeDays - 1))
+	if from != "" {
+		f, err := time.Parse(DateLayout, from)
+		if err != nil {
+			return Range{}, fmt.Errorf("from must be a date such as %s", DateLayout)
+		}
+		r.From = f
+	}
+
+	if r.From.After(r.To) {
+		return Range{}, errors.New("from must not be after to")
+	}
+	if r.Days() > MaxRangeDays {
+		return Range{}, fmt.Errorf("a report covers at most %d days", MaxRangeDays)
+	}
+	return r, nil
+}
+
+// Days is how many days r covers
+func (r Range) Days() int {
+	return int(r.To.Sub(r.From).Hours()/24) + 1
+}
+
+// Periods lists the starts of the periods of g that r touches. The first
+// may start before r.From.
+func (r Range) Periods(g Granularity) []time.Time {
+	var periods []time.Time
+	for start := g.PeriodStart(r.From); !start.After(r.To); start = g.next(start) {
+		periods = append(periods, start)
+	}
+	return periods
+}
+
+// Sales are the order totals over some days. Amounts are in one currency.
+type Sales struct {
+	Orders        int64
+	Units         int64
+	SubtotalCents int64
+	DiscountCents int64
+	ShippingCents int64
+	TaxCents      int64
+	TotalCents    int64
+	RefundedCents int64
+}
+
+// NetCents is what was taken less what was refunded
+func (s Sales) NetCents() int64 {
+	return s.TotalCents - s.RefundedCents
+}
+
+// AverageOrderValueCents is the mean order total, rounded to the cent
+func (s Sales) AverageOrderValueCents() int64 {
+	if s.Orders == 0 {
+		return 0
+	}
+	return int64(math.Round(float64(s.TotalCents) / float64(s.Orders)))
+}
+
+func (s *Sales) add(row database.AnalyticsDailySale) {
+	s.Orders += int64(row.Orders)
+	s.Units += row.Units
+	s.SubtotalCents += row.SubtotalCents
+	s.DiscountCents += row.DiscountCents
+	s.ShippingCents += row.ShippingCents
+	s.TaxCents += row.TaxCents
+	s.TotalCents += row.TotalCents
+	s.RefundedCents += row.RefundedCents
+}
+
+// SalesPeriod is the sales of one period
+type SalesPeriod struct {
+	Start time.Time
+	Sales
+}
+
+// BucketSales groups the daily sales of one currency into the periods of
+// g over r, periods without sales included, and totals them
+func BucketSales(g Granularity, r Range, rows []database.AnalyticsDailySale) ([]SalesPeriod, Sales) {
+	periods := r.Periods(g)
+	out := make([]SalesPeriod, len(periods))
+	index := make(map[time.Time]int, len(periods))
+	for i, start := range periods {
+		out[i].Start = start
+		index[start] = i
+	}
+
+	var total Sales
+	for _, row := range rows {
+		i, ok := index[g.PeriodStart(row.Day.UTC())]
+		if !ok {
+			continue
+		}
+		out[i].add(row)
+		total.add(row)
+	}
+	return out, total
+}
+
+// Conversion is how many checkouts a channel started and converted, and
+// the orders placed through it
+type Conversion struct {
+	Checkouts int64
+	Converted int64
+	Orders    int64
+}
+
+// Rate is the share of checkouts converted, to four places
+func (c Conversion) Rate() float64 {
+	if c.Checkouts == 0 {
+		return 0
+	}
+	return math.Round(float64(c.Converted)/float64(c.Checkouts)*10000) / 10000
+}
+
+// ConversionPeriod is the conversion of one period
+type ConversionPeriod struct {
+	Start time.Time
+	Conversion
+}
+
+// ChannelConversion is the conversion of one sales channel over a range
+type ChannelConversion struct {
+	ChannelID uuid.UUID
+	Name      string
+	Kind      string
+	Total     Conversion
+	Periods   []ConversionPeriod
+}
+
+// BucketChannels groups daily channel rows into the periods of g over r,
+// one entry per channel in the order the rows first name them
+func BucketChannels(g Granularity, r Range, rows []database.GetAnalyticsDailyChannelsRow) []ChannelConversion {
+	periods := r.Periods(g)
+	index := make(map[time.Time]int, len(periods))
+	for i, start := range periods {
+		index[start] = i
+	}
+
+	var out []ChannelConversion
+	channels := map[uuid.UUID]int{}
+	for _, row := range rows {
+		c, ok := channels[row.ChannelID]
+		if !ok {
+			c = len(out)
+			channels[row.ChannelID] = c
+			cc := ChannelConversion{
+				ChannelID: row.ChannelID,
+				Name:      row.ChannelName,
+				Kind:      row.ChannelKind,
+				Periods:   make([]ConversionPeriod, len(periods)),
+			}
+			for i, start := range periods {
+				cc.Periods[i].Start = start
+			}
+			out = append(out, cc)
+		}
+		i, ok := index[g.PeriodStart(row.Day.UTC())]
+		if !ok {
+			continue
+		}
+		day := Conversion{Checkouts: int64(row.Checkouts), Converted: int64(row.Converted), Orders: int64(row.Orders)}
+		out[c].Periods[i].add(day)
+		out[c].Total.add(day)
+	}
+	return out
+}
+
+func (c *Conversion) add(o Conversion) {
+	c.Checkouts += o.Checkouts
+	c.Converted += o.Converted
+	c.Orders += o.Orders
+}
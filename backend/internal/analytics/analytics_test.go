@@ -0,0 +1,153 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/google/uuid"
+)
+
+func day(s string) time.Time {
+	t, err := time.Parse(DateLayout, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestPeriodStart(t *testing.T) {
+	tests := []struct {
+		g    Granularity
+		day  string
+		want string
+	}{
+		{g: Day, day: "2026-10-14", want: "2026-10-14"},
+		{g: Week, day: "2026-10-14", want: "2026-10-12"},
+		{g: Week, day: "2026-10-12", want: "2026-10-12"},
+		{g: Week, day: "2026-10-18", want: "2026-10-12"},
+		{g: Week, day: "2026-01-01", want: "2025-12-29"},
+		{g: Month, day: "2026-10-31", want: "2026-10-01"},
+		{g: Month, day: "2026-02-01", want: "2026-02-01"},
+	}
+	for _, tt := range tests {
+		if got := tt.g.PeriodStart(day(tt.day)).Format(DateLayout); got != tt.want {
+			t.Errorf("%s PeriodStart(%s) = %s, want %s", tt.g, tt.day, got, tt.want)
+		}
+	}
+}
+
+func TestDate(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("no timezone database")
+	}
+	at := time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC)
+	if got := Date(at, tokyo).Format(DateLayout); got != "2026-10-18" {
+		t.Errorf("Date() in Tokyo = %s, want 2026-10-18", got)
+	}
+	if got := Date(at, time.UTC).Format(DateLayout); got != "2026-10-17" {
+		t.Errorf("Date() in UTC = %s, want 2026-10-17", got)
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	today := day("2026-10-18")
+	tests := []struct {
+		name     string
+		from, to string
+		want     Range
+		wantErr  bool
+	}{
+		{name: "defaults", want: Range{From: day("2026-09-18"), To: day("2026-10-17")}},
+		{name: "from only", from: "2026-10-01", want: Range{From: day("2026-10-01"), To: day("2026-10-17")}},
+		{name: "to only", to: "2026-06-30", want: Range{From: day("2026-06-01"), To: day("2026-06-30")}},
+		{name: "one day", from: "2026-10-05", to: "2026-10-05", want: Range{From: day("2026-10-05"), To: day("2026-10-05")}},
+		{name: "reversed", from: "2026-10-05", to: "2026-10-04", wantErr: true},
+		{name: "bad from", from: "10/05/2026", wantErr: true},
+		{name: "bad to", to: "yesterday", wantErr: true},
+		{name: "too long", from: "2020-01-01", to: "2026-10-17", wantErr: true},
+		{name: "longest", from: "2024-10-17", to: "2026-10-17", want: Range{From: day("2024-10-17"), To: day("2026-10-17")}},
+	}
+	for _, tt := range tests {
+		got, err := ParseRange(tt.from, tt.to, today)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: ParseRange() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (!got.From.Equal(tt.want.From) || !got.To.Equal(tt.want.To)) {
+			t.Errorf("%s: ParseRange() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBucketSales(t *testing.T) {
+	rows := []database.AnalyticsDailySale{
+		{Day: day("2026-10-12"), Orders: 2, Units: 3, TotalCents: 5000, RefundedCents: 500},
+		{Day: day("2026-10-14"), Orders: 1, Units: 1, TotalCents: 1001},
+		{Day: day("2026-10-20"), Orders: 4, Units: 4, TotalCents: 8000},
+		// Outside the range
+		{Day: day("2026-11-02"), Orders: 9, TotalCents: 9000},
+	}
+
+	periods, total := BucketSales(Week, Range{From: day("2026-10-13"), To: day("2026-10-26")}, rows)
+	if len(periods) != 3 {
+		t.Fatalf("BucketSales() gave %d periods, want 3", len(periods))
+	}
+	wantStarts := []string{"2026-10-12", "2026-10-19", "2026-10-26"}
+	wantOrders := []int64{3, 4, 0}
+	for i, p := range periods {
+		if p.Start.Format(DateLayout) != wantStarts[i] || p.Orders != wantOrders[i] {
+			t.Errorf("period %d = %s with %d orders, want %s with %d", i, p.Start.Format(DateLayout), p.Orders, wantStarts[i], wantOrders[i])
+		}
+	}
+	if total.Orders != 7 || total.Units != 8 || total.TotalCents != 14001 || total.NetCents() != 13501 {
+		t.Errorf("BucketSales() total = %+v", total)
+	}
+	if got := periods[0].AverageOrderValueCents(); got != 2000 {
+		t.Errorf("AverageOrderValueCents() = %d, want 2000", got)
+	}
+	if got := periods[2].AverageOrderValueCents(); got != 0 {
+		t.Errorf("AverageOrderValueCents() without orders = %d, want 0", got)
+	}
+}
+
+func TestConversionRate(t *testing.T) {
+	tests := []struct {
+		c    Conversion
+		want float64
+	}{
+		{c: Conversion{}, want: 0},
+		{c: Conversion{Checkouts: 4, Converted: 1}, want: 0.25},
+		{c: Conversion{Checkouts: 3, Converted: 1}, want: 0.3333},
+		{c: Conversion{Checkouts: 2, Converted: 2}, want: 1},
+	}
+	for _, tt := range tests {
+		if got := tt.c.Rate(); got != tt.want {
+			t.Errorf("%+v.Rate() = %v, want %v", tt.c, got, tt.want)
+		}
+	}
+}
+
+func TestBucketChannels(t *testing.T) {
+	online, pos := uuid.New(), uuid.New()
+	rows := []database.GetAnalyticsDailyChannelsRow{
+		{Day: day("2026-10-01"), ChannelID: online, ChannelName: "Online Store", ChannelKind: "online_store", Checkouts: 10, Converted: 3, Orders: 4},
+		{Day: day("2026-10-03"), ChannelID: online, ChannelName: "Online Store", ChannelKind: "online_store", Checkouts: 10, Converted: 2, Orders: 2},
+		{Day: day("2026-10-02"), ChannelID: pos, ChannelName: "Counter", ChannelKind: "pos", Orders: 5},
+	}
+
+	got := BucketChannels(Day, Range{From: day("2026-10-01"), To: day("2026-10-03")}, rows)
+	if len(got) != 2 || got[0].ChannelID != online || got[1].ChannelID != pos {
+		t.Fatalf("BucketChannels() = %+v", got)
+	}
+	if got[0].Total != (Conversion{Checkouts: 20, Converted: 5, Orders: 6}) || got[0].Total.Rate() != 0.25 {
+		t.Errorf("online total = %+v", got[0].Total)
+	}
+	if len(got[0].Periods) != 3 || got[0].Periods[1].Checkouts != 0 || got[0].Periods[2].Converted != 2 {
+		t.Errorf("online periods = %+v", got[0].Periods)
+	}
+	if got[1].Total.Orders != 5 || got[1].Total.Rate() != 0 {
+		t.Errorf("pos total = %+v", got[1].Total)
+	}
+}
@@ -0,0 +1,179 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DefaultLookbackDays is how many days already rolled up are rebuilt with
+// each new day, so refunds and cancellations made after an order's day
+// reach its report
+const DefaultLookbackDays = 7
+
+// RollerConfig configures a Roller
+type RollerConfig struct {
+	// Interval is how often stores are checked for a day that has ended
+	Interval time.Duration
+	// LookbackDays is how many rolled up days are rebuilt with a new one
+	LookbackDays int
+	// RetryAfter is how long a store whose rollup failed waits before
+	// another try
+	RetryAfter time.Duration
+	// BatchSize bounds the stores rolled up each interval
+	BatchSize int
+	Logger    *slog.Logger
+}
+
+// Roller keeps the daily analytics rollups. Each store is rolled up once
+// its day has ended in its timezone, so nightly wherever it is. A store's
+// first rollup covers every day since its first order. Stores are claimed
+// before they are rolled up, so running several at once is harmless.
+type Roller struct {
+	pool *pgxpool.Pool
+	db   *database.Queries
+	cfg  RollerConfig
+}
+
+// NewRoller creates a roller over pool
+func NewRoller(pool *pgxpool.Pool, cfg RollerConfig) *Roller {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 15 * time.Minute
+	}
+	if cfg.LookbackDays < 1 {
+		cfg.LookbackDays = DefaultLookbackDays
+	}
+	if cfg.RetryAfter <= 0 {
+		cfg.RetryAfter = time.Hour
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 50
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &Roller{pool: pool, db: database.New(pool), cfg: cfg}
+}
+
+// Run rolls up the stores with a day ended once an interval until ctx is
+// cancelled
+func (r *Roller) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := r.RollDue(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			r.cfg.Logger.Error("analytics rollup failed", "error", err)
+		} else if n > 0 {
+			r.cfg.Logger.Info("analytics rolled up", "stores", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RollDue rolls up to a batch of stores whose last rolled up day is
+// before yesterday, as of now, and returns how many it rolled up
+func (r *Roller) RollDue(ctx context.Context, now time.Time) (int, error) {
+	if err := r.db.EnsureAnalyticsRollups(ctx); err != nil {
+		return 0, err
+	}
+
+	var n int
+	for n < r.cfg.BatchSize && ctx.Err() == nil {
+		state, err := r.db.ClaimAnalyticsRollup(ctx, database.ClaimAnalyticsRollupParams{
+			Now:         now,
+			RetryBefore: now.Add(-r.cfg.RetryAfter),
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		if err := r.roll(ctx, state, now); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// roll rebuilds the days of one store from the lookback before its last
+// rolled up day through yesterday
+func (r *Roller) roll(ctx context.Context, state database.AnalyticsRollup, now time.Time) error {
+	store, err := r.db.GetStoreByID(ctx, state.StoreID)
+	if err != nil {
+		return err
+	}
+	loc, err := time.LoadLocation(store.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	through := Date(now, loc).AddDate(0, 0, -1)
+	var from time.Time
+	if state.RolledThrough.Valid {
+		from = state.RolledThrough.Time.UTC().AddDate(0, 0, -(r.cfg.LookbackDays - 1))
+	} else {
+		first, err := r.db.GetFirstOrderPlacedAt(ctx, store.ID)
+		if err != nil {
+			return err
+		}
+		from = Date(first, loc)
+	}
+	if from.After(through) {
+		from = through
+	}
+	startAt := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	endAt := time.Date(through.Year(), through.Month(), through.Day()+1, 0, 0, 0, 0, loc)
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	q := r.db.WithTx(tx)
+
+	if err := q.DeleteAnalyticsDailySales(ctx, database.DeleteAnalyticsDailySalesParams{StoreID: store.ID, FromDay: from, ToDay: through}); err != nil {
+		return err
+	}
+	if err := q.DeleteAnalyticsDailyProducts(ctx, database.DeleteAnalyticsDailyProductsParams{StoreID: store.ID, FromDay: from, ToDay: through}); err != nil {
+		return err
+	}
+	if err := q.DeleteAnalyticsDailyChannels(ctx, database.DeleteAnalyticsDailyChannelsParams{StoreID: store.ID, FromDay: from, ToDay: through}); err != nil {
+		return err
+	}
+
+	tz := loc.String()
+	if err := q.RollupAnalyticsDailySales(ctx, database.RollupAnalyticsDailySalesParams{Timezone: tz, StoreID: store.ID, StartAt: startAt, EndAt: endAt}); err != nil {
+		return err
+	}
+	if err := q.RollupAnalyticsDailyRefunds(ctx, database.RollupAnalyticsDailyRefundsParams{Timezone: tz, StoreID: store.ID, StartAt: startAt, EndAt: endAt}); err != nil {
+		return err
+	}
+	if err := q.RollupAnalyticsDailyProducts(ctx, database.RollupAnalyticsDailyProductsParams{Timezone: tz, StoreID: store.ID, StartAt: startAt, EndAt: endAt}); err != nil {
+		return err
+	}
+	if err := q.RollupAnalyticsDailyCheckouts(ctx, database.RollupAnalyticsDailyCheckoutsParams{Timezone: tz, StoreID: store.ID, StartAt: startAt, EndAt: endAt}); err != nil {
+		return err
+	}
+	if err := q.RollupAnalyticsDailyChannelOrders(ctx, database.RollupAnalyticsDailyChannelOrdersParams{Timezone: tz, StoreID: store.ID, StartAt: startAt, EndAt: endAt}); err != nil {
+		return err
+	}
+
+	if err := q.SetAnalyticsRolledThrough(ctx, database.SetAnalyticsRolledThroughParams{RolledThrough: through, StoreID: store.ID}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,161 @@
+// Package audit keeps the record of who changed what in a tenant. The API
+// writes an entry for every successful create, update and delete under a
+// tenant; this package decides what goes into one, the entity's state
+// before and after with secrets removed and the fields that changed, and
+// prunes entries once they pass the retention window.
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Actions
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Actions are every action an entry can have
+var Actions = []string{ActionCreate, ActionUpdate, ActionDelete}
+
+// MaxStateBytes bounds a recorded state. Larger responses, such as a
+// product with hundreds of images, are logged without it.
+const MaxStateBytes = 64 << 10
+
+// Redacted replaces the value of a sensitive field
+const Redacted = "[redacted]"
+
+// sensitiveFields are never written to the log, at any depth. Responses
+// carry freshly issued API keys, storefront tokens and gift card codes
+// once; the log must not keep them.
+var sensitiveFields = []string{"key", "token", "password", "secret", "code", "access_token", "refresh_token"}
+
+// ignoredFields change on every write and would only add noise to a diff
+var ignoredFields = []string{"updated_at", "version"}
+
+// ActionFor names what a request did from its method. A POST that
+// recorded a prior state changed something that existed, as publishing a
+// product does, so it is an update.
+func ActionFor(method string, hasBefore bool) string {
+	switch method {
+	case http.MethodPut, http.MethodPatch:
+		return ActionUpdate
+	case http.MethodDelete:
+		return ActionDelete
+	}
+	if hasBefore {
+		return ActionUpdate
+	}
+	return ActionCreate
+}
+
+// IsMutation reports whether requests with method change anything
+func IsMutation(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// Redact returns state with every sensitive field's value replaced. State
+// that isn't JSON is dropped rather than stored unchecked.
+func Redact(state json.RawMessage) json.RawMessage {
+	if len(state) == 0 {
+		return nil
+	}
+	var v any
+	if err := json.Unmarshal(state, &v); err != nil {
+		return nil
+	}
+	out, err := json.Marshal(redact(v))
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+func redact(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, field := range v {
+			if slices.Contains(sensitiveFields, k) && field != nil {
+				v[k] = Redacted
+				continue
+			}
+			v[k] = redact(field)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redact(item)
+		}
+	}
+	return v
+}
+
+// Change is one field's value before and after
+type Change struct {
+	From json.RawMessage `json:"from"`
+	To   json.RawMessage `json:"to"`
+}
+
+// Diff lists the top-level fields that differ between two states of an
+// entity, each a JSON object. A field missing from one side counts as
+// null. It returns nil unless both states are objects, as for a create
+// or a delete, where the one state says it all.
+func Diff(before, after json.RawMessage) map[string]Change {
+	var from, to map[string]json.RawMessage
+	if json.Unmarshal(before, &from) != nil || json.Unmarshal(after, &to) != nil || from == nil || to == nil {
+		return nil
+	}
+
+	changes := map[string]Change{}
+	for field := range from {
+		if _, ok := to[field]; !ok {
+			to[field] = nil
+		}
+	}
+	for field, value := range to {
+		if slices.Contains(ignoredFields, field) {
+			continue
+		}
+		old := normalize(from[field])
+		value = normalize(value)
+		if bytes.Equal(old, value) {
+			continue
+		}
+		changes[field] = Change{From: old, To: value}
+	}
+	return changes
+}
+
+// normalize compacts a value so formatting doesn't count as a change, and
+// spells a missing value as null
+func normalize(v json.RawMessage) json.RawMessage {
+	if len(v) == 0 {
+		return json.RawMessage("null")
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, v); err != nil {
+		return v
+	}
+	return buf.Bytes()
+}
+
+// EntityID reads the id field of a state, which is how every response
+// names the entity it returns
+func EntityID(state json.RawMessage) uuid.UUID {
+	var v struct {
+		ID uuid.UUID `json:"id"`
+	}
+	if json.Unmarshal(state, &v) != nil {
+		return uuid.Nil
+	}
+	return v.ID
+}
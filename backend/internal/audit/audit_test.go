@@ -0,0 +1,191 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestActionFor(t *testing.T) {
+	tests := []struct {
+		method    string
+		hasBefore bool
+		want      string
+	}{
+		{http.MethodPost, false, ActionCreate},
+		{http.MethodPost, true, ActionUpdate},
+		{http.MethodPut, false, ActionUpdate},
+		{http.MethodPatch, true, ActionUpdate},
+		{http.MethodDelete, true, ActionDelete},
+		{http.MethodDelete, false, ActionDelete},
+	}
+	for _, tt := range tests {
+		if got := ActionFor(tt.method, tt.hasBefore); got != tt.want {
+			t.Errorf("ActionFor(%s, %v) = %q, want %q", tt.method, tt.hasBefore, got, tt.want)
+		}
+	}
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name  string
+		state string
+		want  string
+	}{
+		{
+			name:  "top level secret",
+			state: `{"id":"k1","name":"CI","key":"tk_live_abc"}`,
+			want:  `{"id":"k1","key":"[redacted]","name":"CI"}`,
+		},
+		{
+			name:  "nested and in lists",
+			state: `{"cards":[{"code":"GIFT-1234","balance":500}],"invite":{"token":"t"}}`,
+			want:  `{"cards":[{"balance":500,"code":"[redacted]"}],"invite":{"token":"[redacted]"}}`,
+		},
+		{
+			name:  "null secret left alone",
+			state: `{"code":null}`,
+			want:  `{"code":null}`,
+		},
+		{
+			name:  "not json",
+			state: `deleted`,
+			want:  ``,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(Redact(json.RawMessage(tt.state))); got != tt.want {
+				t.Errorf("Redact() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   map[string]Change
+	}{
+		{
+			name:   "changed and cleared fields",
+			before: `{"name":"Tee","status":"draft","sku":"TEE","updated_at":"2026-01-01T00:00:00Z","tags":["a", "b"]}`,
+			after:  `{"name":"Tee","status":"active","updated_at":"2026-02-01T00:00:00Z","tags":["a","b"]}`,
+			want: map[string]Change{
+				"status": {From: json.RawMessage(`"draft"`), To: json.RawMessage(`"active"`)},
+				"sku":    {From: json.RawMessage(`"TEE"`), To: json.RawMessage(`null`)},
+			},
+		},
+		{
+			name:   "added field",
+			before: `{"name":"Tee"}`,
+			after:  `{"name":"Tee","description":"Soft"}`,
+			want: map[string]Change{
+				"description": {From: json.RawMessage(`null`), To: json.RawMessage(`"Soft"`)},
+			},
+		},
+		{
+			name:   "nothing changed",
+			before: `{"name":"Tee"}`,
+			after:  `{"name":"Tee"}`,
+			want:   map[string]Change{},
+		},
+		{
+			name:  "create",
+			after: `{"name":"Tee"}`,
+		},
+		{
+			name:   "delete",
+			before: `{"name":"Tee"}`,
+		},
+		{
+			name:   "not objects",
+			before: `[1]`,
+			after:  `[2]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff(json.RawMessage(tt.before), json.RawMessage(tt.after))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %s, want %s", marshal(got), marshal(tt.want))
+			}
+		})
+	}
+}
+
+func marshal(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func TestEntityID(t *testing.T) {
+	id := uuid.New()
+	if got := EntityID(json.RawMessage(`{"id":"` + id.String() + `","name":"Tee"}`)); got != id {
+		t.Errorf("EntityID() = %s, want %s", got, id)
+	}
+	for _, state := range []string{``, `{"message":"deleted"}`, `{"id":7}`, `[]`} {
+		if got := EntityID(json.RawMessage(state)); got != uuid.Nil {
+			t.Errorf("EntityID(%s) = %s, want nil", state, got)
+		}
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	actor := uuid.New()
+	tests := []struct {
+		name    string
+		query   string
+		want    Filter
+		wantErr bool
+	}{
+		{name: "empty"},
+		{
+			name:  "every filter",
+			query: "actor_id=" + actor.String() + "&action=Update&entity_type=Product&entity_gid=gid://mystoreos/Product/42&since=2026-01-01T00:00:00Z&until=2026-02-01T00:00:00Z",
+			want: Filter{
+				ActorID:    actor,
+				Action:     ActionUpdate,
+				EntityType: "Product",
+				EntityGID:  "gid://mystoreos/Product/42",
+				Since:      time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+				Until:      time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{name: "bad actor", query: "actor_id=me", wantErr: true},
+		{name: "unknown action", query: "action=publish", wantErr: true},
+		{name: "unknown entity type", query: "entity_type=Widget", wantErr: true},
+		{name: "bad gid", query: "entity_gid=Product/42", wantErr: true},
+		{name: "bad time", query: "since=yesterday", wantErr: true},
+		{name: "empty range", query: "since=2026-02-01T00:00:00Z&until=2026-01-01T00:00:00Z", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ParseFilter(q)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidFilter) {
+					t.Fatalf("ParseFilter() error = %v, want ErrInvalidFilter", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFilter() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFilter() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidFilter wraps every rejected filter parameter
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// Filter narrows the audit log listing. Zero values match everything.
+type Filter struct {
+	ActorID    uuid.UUID
+	Action     string
+	EntityType string
+	EntityID   uuid.UUID
+	EntityGID  string
+	StoreID    uuid.UUID
+	// Since and Until bound when entries were made, Until exclusive
+	Since time.Time
+	Until time.Time
+}
+
+// ParseFilter reads filters from query parameters:
+//
+//	actor_id=<user id>
+//	action=update                      create, update or delete
+//	entity_type=Product                as in the entity's GID
+//	entity_id=<id>
+//	entity_gid=gid://mystoreos/Product/123
+//	store_id=<store id>
+//	since=2026-01-01T00:00:00Z         RFC 3339
+//	until=2026-02-01T00:00:00Z
+func ParseFilter(q url.Values) (Filter, error) {
+	var f Filter
+	var err error
+
+	if f.ActorID, err = parseID(q, "actor_id"); err != nil {
+		return Filter{}, err
+	}
+	if f.EntityID, err = parseID(q, "entity_id"); err != nil {
+		return Filter{}, err
+	}
+	if f.StoreID, err = parseID(q, "store_id"); err != nil {
+		return Filter{}, err
+	}
+
+	f.Action = strings.ToLower(strings.TrimSpace(q.Get("action")))
+	if f.Action != "" && !slices.Contains(Actions, f.Action) {
+		return Filter{}, fmt.Errorf("%w: unknown action %q", ErrInvalidFilter, f.Action)
+	}
+	f.EntityType = strings.TrimSpace(q.Get("entity_type"))
+	if f.EntityType != "" && !gid.EntityType(f.EntityType).IsValid() {
+		return Filter{}, fmt.Errorf("%w: unknown entity_type %q", ErrInvalidFilter, f.EntityType)
+	}
+	if raw := strings.TrimSpace(q.Get("entity_gid")); raw != "" {
+		g, err := gid.Parse(raw)
+		if err != nil {
+			return Filter{}, fmt.Errorf("%w: entity_gid: %v", ErrInvalidFilter, err)
+		}
+		f.EntityGID = g.String()
+	}
+
+	if f.Since, err = parseTime(q, "since"); err != nil {
+		return Filter{}, err
+	}
+	if f.Until, err = parseTime(q, "until"); err != nil {
+		return Filter{}, err
+	}
+	if !f.Since.IsZero() && !f.Until.IsZero() && !f.Until.After(f.Since) {
+		return Filter{}, fmt.Errorf("%w: until must be after since", ErrInvalidFilter)
+	}
+	return f, nil
+}
+
+func parseID(q url.Values, key string) (uuid.UUID, error) {
+	raw := strings.TrimSpace(q.Get(key))
+	if raw == "" {
+		return uuid.Nil, nil
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %s must be a UUID", ErrInvalidFilter, key)
+	}
+	return id, nil
+}
+
+func parseTime(q url.Values, key string) (time.Time, error) {
+	raw := strings.TrimSpace(q.Get(key))
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s must be an RFC 3339 time", ErrInvalidFilter, key)
+	}
+	return t, nil
+}
@@ -0,0 +1,95 @@
+package audit
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+)
+
+// DefaultRetention is how long entries are kept when AUDIT_LOG_RETENTION
+// is unset
+const DefaultRetention = 365 * 24 * time.Hour
+
+// PrunerConfig configures a Pruner
+type PrunerConfig struct {
+	Retention time.Duration
+	Interval  time.Duration
+	// BatchSize bounds each delete, so pruning a large backlog doesn't
+	// hold locks on the table for long
+	BatchSize int
+	Logger    *slog.Logger
+}
+
+// Pruner deletes entries older than the retention window. Running several
+// at once is harmless.
+type Pruner struct {
+	db  *database.Queries
+	cfg PrunerConfig
+}
+
+// NewPruner creates a pruner over db
+func NewPruner(db *database.Queries, cfg PrunerConfig) *Pruner {
+	if cfg.Retention <= 0 {
+		cfg.Retention = DefaultRetention
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 1000
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &Pruner{db: db, cfg: cfg}
+}
+
+// Run prunes once an interval until ctx is cancelled
+func (p *Pruner) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := p.Prune(ctx, time.Now().Add(-p.cfg.Retention))
+		if err != nil && ctx.Err() == nil {
+			p.cfg.Logger.Error("audit log prune failed", "error", err)
+		} else if n > 0 {
+			p.cfg.Logger.Info("audit log pruned", "deleted", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Prune deletes every entry created before cutoff, a batch at a time, and
+// returns how many were deleted. Security events around sign-in are kept
+// as long as the audit log and pruned with it.
+func (p *Pruner) Prune(ctx context.Context, cutoff time.Time) (int64, error) {
+	var total int64
+	for _, prune := range []func(context.Context, time.Time, int32) (int64, error){
+		func(ctx context.Context, before time.Time, limit int32) (int64, error) {
+			return p.db.PruneAuditLog(ctx, database.PruneAuditLogParams{Before: before, RowLimit: limit})
+		},
+		func(ctx context.Context, before time.Time, limit int32) (int64, error) {
+			return p.db.PruneSecurityEvents(ctx, database.PruneSecurityEventsParams{Before: before, RowLimit: limit})
+		},
+	} {
+		for {
+			n, err := prune(ctx, cutoff, int32(p.cfg.BatchSize))
+			total += n
+			if err != nil {
+				return total, err
+			}
+			if n < int64(p.cfg.BatchSize) {
+				break
+			}
+		}
+	}
+	return total, nil
+}
@@ -2,10 +2,13 @@ package auth
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -40,52 +43,213 @@ type Token string
 
 const (
 	TokenTypeAccess Token = "terminus-access"
+	// TokenTypeCustomer is issued to storefront shoppers, never to staff
+	TokenTypeCustomer Token = "terminus-customer"
+	// TokenTypeImpersonation lets platform staff act in one tenant for
+	// support. It is not a staff access token, so account routes refuse it.
+	TokenTypeImpersonation Token = "terminus-impersonation"
 )
 
+// TenantClaims optionally ride along in a staff access token so middleware
+// can turn away requests for tenants the user doesn't belong to without a
+// membership lookup. PermissionsVersion is the user's permissions_version
+// when the token was issued; once it moves on, the tenant list is stale.
+type TenantClaims struct {
+	TenantIDs          []uuid.UUID
+	PermissionsVersion int64
+}
+
+// HasTenant reports whether tenantID is among the claimed memberships
+func (tc *TenantClaims) HasTenant(tenantID uuid.UUID) bool {
+	return slices.Contains(tc.TenantIDs, tenantID)
+}
+
+// accessClaims is the JWT body of a staff access token
+type accessClaims struct {
+	jwt.RegisteredClaims
+	Tenants            []string `json:"tenants,omitempty"`
+	PermissionsVersion int64    `json:"pv,omitempty"`
+	TokenVersion       int64    `json:"tv,omitempty"`
+}
+
+// AccessToken is what a staff access token says about its user
+type AccessToken struct {
+	UserID uuid.UUID
+	// Tenants are the optional tenant claims
+	Tenants *TenantClaims
+	// Version is the user's token_version when the token was issued, or 0
+	// when it doesn't carry one. Once it moves on, the token is revoked.
+	Version int64
+}
+
+// MakeJWT issues a staff access token signed with the key set's current key
 func MakeJWT(
 	userID uuid.UUID,
-	tokenSecret string,
+	keys *KeySet,
+	expiresIn time.Duration,
+) (string, error) {
+	return MakeAccessJWT(userID, keys, expiresIn, nil)
+}
+
+// MakeAccessJWT issues a staff access token, embedding tenant claims when
+// tenants is non-nil
+func MakeAccessJWT(
+	userID uuid.UUID,
+	keys *KeySet,
+	expiresIn time.Duration,
+	tenants *TenantClaims,
+) (string, error) {
+	return SignAccessToken(AccessToken{UserID: userID, Tenants: tenants}, keys, expiresIn)
+}
+
+// SignAccessToken issues a staff access token carrying tok
+func SignAccessToken(tok AccessToken, keys *KeySet, expiresIn time.Duration) (string, error) {
+	if tok.Version < 0 {
+		return "", errors.New("token version can't be negative")
+	}
+	claims := accessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    string(TokenTypeAccess),
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(expiresIn)),
+			Subject:   tok.UserID.String(),
+		},
+		TokenVersion: tok.Version,
+	}
+	if tenants := tok.Tenants; tenants != nil {
+		if tenants.PermissionsVersion <= 0 {
+			return "", errors.New("tenant claims need a permissions version")
+		}
+		// An empty list is still meaningful: the user belongs to no tenants
+		claims.Tenants = make([]string, 0, len(tenants.TenantIDs))
+		for _, id := range tenants.TenantIDs {
+			claims.Tenants = append(claims.Tenants, id.String())
+		}
+		claims.PermissionsVersion = tenants.PermissionsVersion
+	}
+	return keys.sign(claims)
+}
+
+func ValidateJWT(tokenString string, keys *KeySet) (uuid.UUID, error) {
+	id, _, err := ParseAccessJWT(tokenString, keys)
+	return id, err
+}
+
+// ParseAccessJWT validates a staff access token and returns its user and,
+// if the token carries them, its tenant claims
+func ParseAccessJWT(tokenString string, keys *KeySet) (uuid.UUID, *TenantClaims, error) {
+	tok, err := ParseAccessToken(tokenString, keys)
+	return tok.UserID, tok.Tenants, err
+}
+
+// ParseAccessToken validates a staff access token and returns what it says
+func ParseAccessToken(tokenString string, keys *KeySet) (AccessToken, error) {
+	claims := accessClaims{}
+	_, err := keys.parse(tokenString, &claims, jwt.WithIssuer(string(TokenTypeAccess)))
+	if err != nil {
+		return AccessToken{}, err
+	}
+
+	id, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return AccessToken{}, fmt.Errorf("invalid user ID: %w", err)
+	}
+	tok := AccessToken{UserID: id, Version: claims.TokenVersion}
+
+	if claims.PermissionsVersion == 0 {
+		return tok, nil
+	}
+	tok.Tenants = &TenantClaims{
+		TenantIDs:          make([]uuid.UUID, 0, len(claims.Tenants)),
+		PermissionsVersion: claims.PermissionsVersion,
+	}
+	for _, raw := range claims.Tenants {
+		tenantID, err := uuid.Parse(raw)
+		if err != nil {
+			return AccessToken{}, fmt.Errorf("invalid tenant ID: %w", err)
+		}
+		tok.Tenants.TenantIDs = append(tok.Tenants.TenantIDs, tenantID)
+	}
+	return tok, nil
+}
+
+// MakeImpersonationJWT issues a token for platform staff userID to act in
+// tenantID, bound to the tenant via the audience claim
+func MakeImpersonationJWT(
+	userID uuid.UUID,
+	tenantID uuid.UUID,
+	keys *KeySet,
 	expiresIn time.Duration,
 ) (string, error) {
-	signingKey := []byte(tokenSecret)
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
-		Issuer:    string(TokenTypeAccess),
+	return keys.sign(jwt.RegisteredClaims{
+		Issuer:    string(TokenTypeImpersonation),
 		IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
 		ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(expiresIn)),
 		Subject:   userID.String(),
+		Audience:  jwt.ClaimStrings{tenantID.String()},
 	})
-	return token.SignedString(signingKey)
 }
 
-//func CheckPasswordHash(password, hash string) error
+// ParseImpersonationJWT validates an impersonation token and returns the
+// staff user and the tenant it acts in
+func ParseImpersonationJWT(tokenString string, keys *KeySet) (uuid.UUID, uuid.UUID, error) {
+	claims := jwt.RegisteredClaims{}
+	_, err := keys.parse(tokenString, &claims, jwt.WithIssuer(string(TokenTypeImpersonation)))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
 
-func ValidateJWT(tokenString, tokenSecret string) (uuid.UUID, error) {
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	if len(claims.Audience) != 1 {
+		return uuid.Nil, uuid.Nil, errors.New("impersonation token must name one tenant")
+	}
+	tenantID, err := uuid.Parse(claims.Audience[0])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid tenant ID: %w", err)
+	}
+	return userID, tenantID, nil
+}
+
+// MakeCustomerJWT issues a shopper access token bound to a single store via the audience claim
+func MakeCustomerJWT(
+	customerID uuid.UUID,
+	storeID uuid.UUID,
+	keys *KeySet,
+	expiresIn time.Duration,
+) (string, error) {
+	return keys.sign(jwt.RegisteredClaims{
+		Issuer:    string(TokenTypeCustomer),
+		IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+		ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(expiresIn)),
+		Subject:   customerID.String(),
+		Audience:  jwt.ClaimStrings{storeID.String()},
+	})
+}
+
+// ValidateCustomerJWT checks a shopper token and that it was issued for storeID
+func ValidateCustomerJWT(tokenString string, keys *KeySet, storeID uuid.UUID) (uuid.UUID, error) {
 	claimsStruct := jwt.RegisteredClaims{}
-	token, err := jwt.ParseWithClaims(
+	token, err := keys.parse(
 		tokenString,
 		&claimsStruct,
-		func(token *jwt.Token) (interface{}, error) { return []byte(tokenSecret), nil },
+		jwt.WithIssuer(string(TokenTypeCustomer)),
+		jwt.WithAudience(storeID.String()),
 	)
 	if err != nil {
 		return uuid.Nil, err
 	}
 
-	userIDString, err := token.Claims.GetSubject()
+	customerIDString, err := token.Claims.GetSubject()
 	if err != nil {
 		return uuid.Nil, err
 	}
 
-	issuer, err := token.Claims.GetIssuer()
+	id, err := uuid.Parse(customerIDString)
 	if err != nil {
-		return uuid.Nil, err
-	}
-	if issuer != string(TokenTypeAccess) {
-		return uuid.Nil, errors.New("invalid issuer")
-	}
-
-	id, err := uuid.Parse(userIDString)
-	if err != nil {
-		return uuid.Nil, fmt.Errorf("invalid user ID: %w", err)
+		return uuid.Nil, fmt.Errorf("invalid customer ID: %w", err)
 	}
 	return id, nil
 }
@@ -121,6 +285,94 @@ func MakeRefreshToken() (string, error) {
 	return encodedString, nil
 }
 
+// MakeOneTimeToken returns a random URL-safe token for links sent by email.
+// Only its HashToken digest is stored, so a database leak can't be replayed.
+func MakeOneTimeToken() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(key), nil
+}
+
+// APIKeyPrefix starts every API key so leaked keys are easy to spot
+const APIKeyPrefix = "trm_"
+
+// MakeAPIKey returns a new API key and its display prefix, the part that can
+// be shown again after the key itself. Store the key as HashToken(key).
+func MakeAPIKey() (key string, prefix string, err error) {
+	return makePrefixedKey(APIKeyPrefix)
+}
+
+// Storefront token kinds. Publishable tokens can sit in a web page, so they
+// only get read access; secret tokens are kept server side.
+const (
+	StorefrontTokenPublishable = "publishable"
+	StorefrontTokenSecret      = "secret"
+)
+
+// Storefront token scopes
+const (
+	ScopeReadProducts   = "read_products"
+	ScopeManageCheckout = "manage_checkout"
+)
+
+// storefrontTokenScopes lists the scopes each kind of token may carry
+var storefrontTokenScopes = map[string][]string{
+	StorefrontTokenPublishable: {ScopeReadProducts},
+	StorefrontTokenSecret:      {ScopeReadProducts, ScopeManageCheckout},
+}
+
+var storefrontTokenPrefixes = map[string]string{
+	StorefrontTokenPublishable: "sfp_",
+	StorefrontTokenSecret:      "sfs_",
+}
+
+// ValidateStorefrontScopes checks kind is a storefront token kind and that
+// it may carry every scope
+func ValidateStorefrontScopes(kind string, scopes []string) error {
+	allowed, ok := storefrontTokenScopes[kind]
+	if !ok {
+		return fmt.Errorf("unknown token kind %q", kind)
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(allowed, scope) {
+			return fmt.Errorf("%s tokens can't carry the %q scope", kind, scope)
+		}
+	}
+	return nil
+}
+
+// MakeStorefrontToken returns a new storefront token of kind and its display
+// prefix. Like API keys, store the token as HashToken(token).
+func MakeStorefrontToken(kind string) (token string, prefix string, err error) {
+	p, ok := storefrontTokenPrefixes[kind]
+	if !ok {
+		return "", "", fmt.Errorf("unknown token kind %q", kind)
+	}
+	return makePrefixedKey(p)
+}
+
+func makePrefixedKey(p string) (key string, prefix string, err error) {
+	id := make([]byte, 5)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+
+	prefix = p + hex.EncodeToString(id)
+	return prefix + "_" + base64.RawURLEncoding.EncodeToString(secret), prefix, nil
+}
+
+// HashToken returns the hex SHA-256 digest a one-time token is stored under
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 func GetAPIKey(headers http.Header) (string, error) {
 	// shape Authorization: ApiKey THE_KEY_HERE
 	authorization := headers.Get("Authorization")
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -64,34 +65,36 @@ func TestCheckPasswordHash(t *testing.T) {
 }
 
 func TestValidateJWT(t *testing.T) {
+	keys := NewKeySet(DeriveSigningKey("secret"), nil, "")
+	wrongKeys := NewKeySet(DeriveSigningKey("wrong_secret"), nil, "")
 	userID := uuid.New()
-	validToken, _ := MakeJWT(userID, "secret", time.Hour)
+	validToken, _ := MakeJWT(userID, keys, time.Hour)
 
 	tests := []struct {
 		name        string
 		tokenString string
-		tokenSecret string
+		keys        *KeySet
 		wantUserID  uuid.UUID
 		wantErr     bool
 	}{
 		{
 			name:        "Valid token",
 			tokenString: validToken,
-			tokenSecret: "secret",
+			keys:        keys,
 			wantUserID:  userID,
 			wantErr:     false,
 		},
 		{
 			name:        "Invalid token",
 			tokenString: "invalid.token.string",
-			tokenSecret: "secret",
+			keys:        keys,
 			wantUserID:  uuid.Nil,
 			wantErr:     true,
 		},
 		{
 			name:        "Wrong secret",
 			tokenString: validToken,
-			tokenSecret: "wrong_secret",
+			keys:        wrongKeys,
 			wantUserID:  uuid.Nil,
 			wantErr:     true,
 		},
@@ -99,7 +102,7 @@ func TestValidateJWT(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotUserID, err := ValidateJWT(tt.tokenString, tt.tokenSecret)
+			gotUserID, err := ValidateJWT(tt.tokenString, tt.keys)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ValidateJWT() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -111,6 +114,223 @@ func TestValidateJWT(t *testing.T) {
 	}
 }
 
+func TestValidateCustomerJWT(t *testing.T) {
+	keys := NewKeySet(DeriveSigningKey("secret"), nil, "")
+	wrongKeys := NewKeySet(DeriveSigningKey("wrong_secret"), nil, "")
+	customerID := uuid.New()
+	storeID := uuid.New()
+	validToken, _ := MakeCustomerJWT(customerID, storeID, keys, time.Hour)
+	staffToken, _ := MakeJWT(customerID, keys, time.Hour)
+
+	tests := []struct {
+		name           string
+		tokenString    string
+		keys           *KeySet
+		storeID        uuid.UUID
+		wantCustomerID uuid.UUID
+		wantErr        bool
+	}{
+		{
+			name:           "Valid token",
+			tokenString:    validToken,
+			keys:           keys,
+			storeID:        storeID,
+			wantCustomerID: customerID,
+			wantErr:        false,
+		},
+		{
+			name:           "Token for another store",
+			tokenString:    validToken,
+			keys:           keys,
+			storeID:        uuid.New(),
+			wantCustomerID: uuid.Nil,
+			wantErr:        true,
+		},
+		{
+			name:           "Staff token rejected",
+			tokenString:    staffToken,
+			keys:           keys,
+			storeID:        storeID,
+			wantCustomerID: uuid.Nil,
+			wantErr:        true,
+		},
+		{
+			name:           "Wrong secret",
+			tokenString:    validToken,
+			keys:           wrongKeys,
+			storeID:        storeID,
+			wantCustomerID: uuid.Nil,
+			wantErr:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotCustomerID, err := ValidateCustomerJWT(tt.tokenString, tt.keys, tt.storeID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCustomerJWT() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotCustomerID != tt.wantCustomerID {
+				t.Errorf("ValidateCustomerJWT() gotCustomerID = %v, want %v", gotCustomerID, tt.wantCustomerID)
+			}
+		})
+	}
+}
+
+func TestCustomerTokenRejectedAsStaff(t *testing.T) {
+	keys := NewKeySet(DeriveSigningKey("secret"), nil, "")
+	token, _ := MakeCustomerJWT(uuid.New(), uuid.New(), keys, time.Hour)
+	if _, err := ValidateJWT(token, keys); err == nil {
+		t.Error("ValidateJWT() accepted a customer token")
+	}
+}
+
+func TestParseImpersonationJWT(t *testing.T) {
+	keys := NewKeySet(DeriveSigningKey("secret"), nil, "")
+	userID, tenantID := uuid.New(), uuid.New()
+	validToken, _ := MakeImpersonationJWT(userID, tenantID, keys, time.Hour)
+	expiredToken, _ := MakeImpersonationJWT(userID, tenantID, keys, -time.Minute)
+	staffToken, _ := MakeJWT(userID, keys, time.Hour)
+	customerToken, _ := MakeCustomerJWT(userID, tenantID, keys, time.Hour)
+
+	tests := []struct {
+		name        string
+		tokenString string
+		wantErr     bool
+	}{
+		{name: "Valid token", tokenString: validToken},
+		{name: "Expired token", tokenString: expiredToken, wantErr: true},
+		{name: "Staff token rejected", tokenString: staffToken, wantErr: true},
+		{name: "Customer token rejected", tokenString: customerToken, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotUserID, gotTenantID, err := ParseImpersonationJWT(tt.tokenString, keys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseImpersonationJWT() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (gotUserID != userID || gotTenantID != tenantID) {
+				t.Errorf("ParseImpersonationJWT() = %v, %v, want %v, %v", gotUserID, gotTenantID, userID, tenantID)
+			}
+		})
+	}
+
+	if _, err := ValidateJWT(validToken, keys); err == nil {
+		t.Error("ValidateJWT() accepted an impersonation token")
+	}
+}
+
+func TestAccessJWTTenantClaims(t *testing.T) {
+	keys := NewKeySet(DeriveSigningKey("secret"), nil, "")
+	userID := uuid.New()
+	member, other := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name        string
+		tenants     *TenantClaims
+		wantClaims  bool
+		wantMember  bool
+		wantVersion int64
+		wantErr     bool
+	}{
+		{name: "No claims", tenants: nil},
+		{
+			name:        "Member of one tenant",
+			tenants:     &TenantClaims{TenantIDs: []uuid.UUID{member}, PermissionsVersion: 7},
+			wantClaims:  true,
+			wantMember:  true,
+			wantVersion: 7,
+		},
+		{
+			name:        "Member of no tenants",
+			tenants:     &TenantClaims{PermissionsVersion: 1},
+			wantClaims:  true,
+			wantVersion: 1,
+		},
+		{
+			name:    "Missing permissions version",
+			tenants: &TenantClaims{TenantIDs: []uuid.UUID{member}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := MakeAccessJWT(userID, keys, time.Hour, tt.tenants)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MakeAccessJWT() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			gotUserID, claims, err := ParseAccessJWT(token, keys)
+			if err != nil {
+				t.Fatalf("ParseAccessJWT() error = %v", err)
+			}
+			if gotUserID != userID {
+				t.Errorf("ParseAccessJWT() user = %v, want %v", gotUserID, userID)
+			}
+			if (claims != nil) != tt.wantClaims {
+				t.Fatalf("ParseAccessJWT() claims = %+v, wantClaims %v", claims, tt.wantClaims)
+			}
+			if claims == nil {
+				return
+			}
+			if claims.PermissionsVersion != tt.wantVersion {
+				t.Errorf("PermissionsVersion = %d, want %d", claims.PermissionsVersion, tt.wantVersion)
+			}
+			if claims.HasTenant(member) != tt.wantMember || claims.HasTenant(other) {
+				t.Errorf("HasTenant() mismatch for claims %+v", claims)
+			}
+		})
+	}
+}
+
+func TestAccessTokenVersion(t *testing.T) {
+	keys := NewKeySet(DeriveSigningKey("secret"), nil, "")
+	userID := uuid.New()
+
+	tests := []struct {
+		name    string
+		tok     AccessToken
+		wantErr bool
+	}{
+		{name: "No version", tok: AccessToken{UserID: userID}},
+		{name: "Version", tok: AccessToken{UserID: userID, Version: 3}},
+		{
+			name: "Version with tenant claims",
+			tok:  AccessToken{UserID: userID, Version: 2, Tenants: &TenantClaims{PermissionsVersion: 5}},
+		},
+		{name: "Negative version", tok: AccessToken{UserID: userID, Version: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := SignAccessToken(tt.tok, keys, time.Hour)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SignAccessToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got, err := ParseAccessToken(token, keys)
+			if err != nil {
+				t.Fatalf("ParseAccessToken() error = %v", err)
+			}
+			if got.UserID != tt.tok.UserID || got.Version != tt.tok.Version {
+				t.Errorf("ParseAccessToken() = %+v, want %+v", got, tt.tok)
+			}
+			if (got.Tenants != nil) != (tt.tok.Tenants != nil) {
+				t.Errorf("ParseAccessToken() tenants = %+v, want %+v", got.Tenants, tt.tok.Tenants)
+			}
+		})
+	}
+}
+
 func TestMakeRefreshToken(t *testing.T) {
 	//function performs a single action
 	tests := []struct {
@@ -139,9 +359,103 @@ func TestMakeRefreshToken(t *testing.T) {
 	}
 }
 
+func TestOneTimeToken(t *testing.T) {
+	a, err := MakeOneTimeToken()
+	if err != nil {
+		t.Fatalf("MakeOneTimeToken() error = %v", err)
+	}
+	b, _ := MakeOneTimeToken()
+	if a == "" || a == b {
+		t.Fatalf("tokens should be random and non-empty, got %q and %q", a, b)
+	}
+	if strings.ContainsAny(a, "+/=") {
+		t.Errorf("token %q is not URL-safe", a)
+	}
+
+	if HashToken(a) != HashToken(a) {
+		t.Error("HashToken() should be deterministic")
+	}
+	if HashToken(a) == HashToken(b) || HashToken(a) == a {
+		t.Error("HashToken() should differ per token and from the token itself")
+	}
+}
+
+func TestMakeAPIKey(t *testing.T) {
+	key, prefix, err := MakeAPIKey()
+	if err != nil {
+		t.Fatalf("MakeAPIKey() error = %v", err)
+	}
+	if !strings.HasPrefix(prefix, APIKeyPrefix) || len(prefix) != len(APIKeyPrefix)+10 {
+		t.Errorf("unexpected prefix %q", prefix)
+	}
+	if !strings.HasPrefix(key, prefix+"_") || len(key) < len(prefix)+40 {
+		t.Errorf("key %q should start with its prefix and carry a long secret", key)
+	}
+
+	other, otherPrefix, _ := MakeAPIKey()
+	if other == key || otherPrefix == prefix {
+		t.Error("keys should be random")
+	}
+
+	got, err := GetAPIKey(http.Header{"Authorization": []string{"ApiKey " + key}})
+	if err != nil || got != key {
+		t.Errorf("GetAPIKey() = %q, %v", got, err)
+	}
+}
+
+func TestMakeStorefrontToken(t *testing.T) {
+	tests := []struct {
+		kind       string
+		wantPrefix string
+		wantErr    bool
+	}{
+		{kind: StorefrontTokenPublishable, wantPrefix: "sfp_"},
+		{kind: StorefrontTokenSecret, wantPrefix: "sfs_"},
+		{kind: "private", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			token, prefix, err := MakeStorefrontToken(tt.kind)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MakeStorefrontToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !strings.HasPrefix(prefix, tt.wantPrefix) || !strings.HasPrefix(token, prefix+"_") {
+				t.Errorf("token %q, prefix %q should start with %q", token, prefix, tt.wantPrefix)
+			}
+		})
+	}
+}
+
+func TestValidateStorefrontScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    string
+		scopes  []string
+		wantErr bool
+	}{
+		{name: "publishable read", kind: StorefrontTokenPublishable, scopes: []string{ScopeReadProducts}},
+		{name: "publishable checkout", kind: StorefrontTokenPublishable, scopes: []string{ScopeManageCheckout}, wantErr: true},
+		{name: "secret both", kind: StorefrontTokenSecret, scopes: []string{ScopeReadProducts, ScopeManageCheckout}},
+		{name: "unknown scope", kind: StorefrontTokenSecret, scopes: []string{"write_orders"}, wantErr: true},
+		{name: "unknown kind", kind: "private", scopes: []string{ScopeReadProducts}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateStorefrontScopes(tt.kind, tt.scopes); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateStorefrontScopes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestGetBearerToken(t *testing.T) {
 	userID := uuid.New()
-	validToken, _ := MakeJWT(userID, "secret", time.Hour)
+	validToken, _ := MakeJWT(userID, NewKeySet(DeriveSigningKey("secret"), nil, ""), time.Hour)
 	invalidToken := "Invalid.Token"
 
 	// var validHeader http.Header
@@ -0,0 +1,297 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrUnknownKey     = errors.New("token signed with an unknown key")
+	ErrUnsupportedKey = errors.New("unsupported key type, use Ed25519 or RSA of at least 2048 bits")
+)
+
+// VerificationKey checks signatures made by one signing key. kid is its
+// RFC 7638 thumbprint, so the same key always gets the same ID.
+type VerificationKey struct {
+	ID     string
+	Method jwt.SigningMethod
+	Public crypto.PublicKey
+}
+
+// SigningKey is a private key tokens are signed with
+type SigningKey struct {
+	VerificationKey
+	Private crypto.Signer
+}
+
+// KeySet signs tokens with its current key and accepts tokens from any of
+// its keys, so keys can be rotated without signing everyone out: the old
+// key stays in the set, verify-only, until its tokens have expired.
+type KeySet struct {
+	current SigningKey
+	keys    map[string]VerificationKey
+
+	// legacySecret verifies HS256 tokens from before keys were asymmetric.
+	// They carry no kid.
+	legacySecret []byte
+}
+
+// NewKeySet builds a key set. legacySecret may be empty.
+func NewKeySet(current SigningKey, retired []VerificationKey, legacySecret string) *KeySet {
+	ks := &KeySet{
+		current: current,
+		keys:    map[string]VerificationKey{current.ID: current.VerificationKey},
+	}
+	for _, k := range retired {
+		ks.keys[k.ID] = k
+	}
+	if legacySecret != "" {
+		ks.legacySecret = []byte(legacySecret)
+	}
+	return ks
+}
+
+// KeyConfig locates the keys a KeySet is loaded from
+type KeyConfig struct {
+	// PrivateKeyFile is a PEM file holding the current signing key
+	PrivateKeyFile string
+	// RetiredKeyFiles are PEM public keys still accepted for verification
+	RetiredKeyFiles []string
+	// SigningKey, if set, keeps old HS256 tokens valid. Without a key file
+	// the signing key is derived from it, which is only meant for
+	// development.
+	SigningKey string
+}
+
+// LoadKeySet reads the signing key and retired keys named by cfg
+func LoadKeySet(cfg KeyConfig) (*KeySet, error) {
+	secret := cfg.SigningKey
+
+	var current SigningKey
+	if path := cfg.PrivateKeyFile; path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read JWT_PRIVATE_KEY_FILE: %w", err)
+		}
+		current, err = ParsePrivateKeyPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("JWT_PRIVATE_KEY_FILE: %w", err)
+		}
+	} else if secret != "" {
+		current = DeriveSigningKey(secret)
+	} else {
+		return nil, errors.New("JWT_PRIVATE_KEY_FILE or SIGNING_KEY must be set")
+	}
+
+	var retired []VerificationKey
+	for _, path := range cfg.RetiredKeyFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read retired key %s: %w", path, err)
+		}
+		k, err := ParsePublicKeyPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("retired key %s: %w", path, err)
+		}
+		retired = append(retired, k)
+	}
+
+	return NewKeySet(current, retired, secret), nil
+}
+
+// DeriveSigningKey turns a shared secret into an Ed25519 key. Every
+// instance with the same secret gets the same key.
+func DeriveSigningKey(secret string) SigningKey {
+	seed := sha256.Sum256([]byte("terminus-jwt:" + secret))
+	k, _ := NewSigningKey(ed25519.NewKeyFromSeed(seed[:]))
+	return k
+}
+
+// NewSigningKey wraps an Ed25519 or RSA private key
+func NewSigningKey(private crypto.Signer) (SigningKey, error) {
+	vk, err := NewVerificationKey(private.Public())
+	if err != nil {
+		return SigningKey{}, err
+	}
+	return SigningKey{VerificationKey: vk, Private: private}, nil
+}
+
+// NewVerificationKey wraps an Ed25519 or RSA public key
+func NewVerificationKey(public crypto.PublicKey) (VerificationKey, error) {
+	var method jwt.SigningMethod
+	switch k := public.(type) {
+	case ed25519.PublicKey:
+		method = jwt.SigningMethodEdDSA
+	case *rsa.PublicKey:
+		if k.N.BitLen() < 2048 {
+			return VerificationKey{}, ErrUnsupportedKey
+		}
+		method = jwt.SigningMethodRS256
+	default:
+		return VerificationKey{}, ErrUnsupportedKey
+	}
+
+	vk := VerificationKey{Method: method, Public: public}
+	thumbprint, err := json.Marshal(vk.thumbprintMembers())
+	if err != nil {
+		return VerificationKey{}, err
+	}
+	sum := sha256.Sum256(thumbprint)
+	vk.ID = base64.RawURLEncoding.EncodeToString(sum[:])
+	return vk, nil
+}
+
+// ParsePrivateKeyPEM reads a PKCS #8 private key, or a PKCS #1 RSA key
+func ParsePrivateKeyPEM(data []byte) (SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return SigningKey{}, errors.New("no PEM block found")
+	}
+
+	var key any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return SigningKey{}, err
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return SigningKey{}, ErrUnsupportedKey
+	}
+	return NewSigningKey(signer)
+}
+
+// ParsePublicKeyPEM reads a PKIX public key. A private key is accepted too,
+// so a retired key file can be reused as it is.
+func ParsePublicKeyPEM(data []byte) (VerificationKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return VerificationKey{}, errors.New("no PEM block found")
+	}
+	if strings.Contains(block.Type, "PRIVATE KEY") {
+		k, err := ParsePrivateKeyPEM(data)
+		return k.VerificationKey, err
+	}
+
+	public, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return VerificationKey{}, err
+	}
+	return NewVerificationKey(public)
+}
+
+// sign signs claims with the current key, naming it in the kid header
+func (ks *KeySet) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.current.Method, claims)
+	token.Header["kid"] = ks.current.ID
+	return token.SignedString(ks.current.Private)
+}
+
+// parse verifies a token's signature against the key its kid names
+func (ks *KeySet) parse(tokenString string, claims jwt.Claims, opts ...jwt.ParserOption) (*jwt.Token, error) {
+	opts = append(opts, jwt.WithValidMethods([]string{
+		jwt.SigningMethodEdDSA.Alg(),
+		jwt.SigningMethodRS256.Alg(),
+		jwt.SigningMethodHS256.Alg(),
+	}))
+	return jwt.ParseWithClaims(tokenString, claims, ks.keyFunc, opts...)
+}
+
+func (ks *KeySet) keyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if ks.legacySecret != nil && token.Method.Alg() == jwt.SigningMethodHS256.Alg() {
+			return ks.legacySecret, nil
+		}
+		return nil, ErrUnknownKey
+	}
+
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	// The algorithm must be the key's own, never one picked by the token
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("token algorithm %s does not match key %s", token.Method.Alg(), kid)
+	}
+	return key.Public, nil
+}
+
+// JWK is a public key in JSON Web Key form (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+// JWKS lists the public keys other services can verify tokens with. The
+// legacy HS256 secret is never published.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the current key first, then the retired ones
+func (ks *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{ks.current.jwk()}}
+	for id, k := range ks.keys {
+		if id != ks.current.ID {
+			set.Keys = append(set.Keys, k.jwk())
+		}
+	}
+	return set
+}
+
+func (k VerificationKey) jwk() JWK {
+	m := k.thumbprintMembers()
+	return JWK{
+		Kty: m["kty"],
+		Kid: k.ID,
+		Use: "sig",
+		Alg: k.Method.Alg(),
+		Crv: m["crv"],
+		X:   m["x"],
+		N:   m["n"],
+		E:   m["e"],
+	}
+}
+
+// thumbprintMembers are the required JWK members of the key. Marshalled as
+// a map their keys come out sorted, as RFC 7638 requires.
+func (k VerificationKey) thumbprintMembers() map[string]string {
+	b64 := base64.RawURLEncoding.EncodeToString
+	switch pub := k.Public.(type) {
+	case ed25519.PublicKey:
+		return map[string]string{"crv": "Ed25519", "kty": "OKP", "x": b64(pub)}
+	case *rsa.PublicKey:
+		return map[string]string{
+			"e":   b64(big.NewInt(int64(pub.E)).Bytes()),
+			"kty": "RSA",
+			"n":   b64(pub.N.Bytes()),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func TestKeyRotation(t *testing.T) {
+	userID := uuid.New()
+
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	oldKey, err := NewSigningKey(edKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	newKey, err := NewSigningKey(rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := NewKeySet(oldKey, nil, "")
+	oldToken, err := MakeJWT(userID, before, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// After rotation, tokens from the old key still validate and new ones
+	// are signed with RS256
+	after := NewKeySet(newKey, []VerificationKey{oldKey.VerificationKey}, "")
+	if got, err := ValidateJWT(oldToken, after); err != nil || got != userID {
+		t.Errorf("old token after rotation: got %v, %v", got, err)
+	}
+
+	newToken, _ := MakeJWT(userID, after, time.Hour)
+	parsed, _, _ := jwt.NewParser().ParseUnverified(newToken, &jwt.RegisteredClaims{})
+	if parsed.Method.Alg() != "RS256" || parsed.Header["kid"] != newKey.ID {
+		t.Errorf("new token header = %v", parsed.Header)
+	}
+
+	// Once the old key is dropped its tokens stop working
+	if _, err := ValidateJWT(oldToken, NewKeySet(newKey, nil, "")); err == nil {
+		t.Error("token from a removed key should be rejected")
+	}
+}
+
+func TestLegacyHS256Tokens(t *testing.T) {
+	userID := uuid.New()
+	legacy, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Issuer:    string(TokenTypeAccess),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		Subject:   userID.String(),
+	}).SignedString([]byte("secret"))
+
+	tests := []struct {
+		name    string
+		keys    *KeySet
+		wantErr bool
+	}{
+		{"accepted with the legacy secret", NewKeySet(DeriveSigningKey("secret"), nil, "secret"), false},
+		{"rejected without it", NewKeySet(DeriveSigningKey("secret"), nil, ""), true},
+		{"rejected with another secret", NewKeySet(DeriveSigningKey("secret"), nil, "other"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ValidateJWT(legacy, tt.keys)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateJWT() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAlgorithmMustMatchKey(t *testing.T) {
+	keys := NewKeySet(DeriveSigningKey("secret"), nil, "secret")
+
+	// An HS256 token naming the Ed25519 key must not be checked as HMAC
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Issuer:    string(TokenTypeAccess),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		Subject:   uuid.New().String(),
+	})
+	forged.Header["kid"] = keys.current.ID
+	token, _ := forged.SignedString([]byte("secret"))
+
+	if _, err := ValidateJWT(token, keys); err == nil {
+		t.Error("token with a mismatched algorithm should be rejected")
+	}
+}
+
+func TestParseKeysAndJWKS(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	der, _ := x509.MarshalPKCS8PrivateKey(rsaKey)
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	pubDER, _ := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+
+	signing, err := ParsePrivateKeyPEM(privatePEM)
+	if err != nil {
+		t.Fatalf("ParsePrivateKeyPEM() error = %v", err)
+	}
+	public, err := ParsePublicKeyPEM(publicPEM)
+	if err != nil {
+		t.Fatalf("ParsePublicKeyPEM() error = %v", err)
+	}
+	if signing.ID != public.ID {
+		t.Errorf("private and public key IDs differ: %s, %s", signing.ID, public.ID)
+	}
+
+	small, _ := rsa.GenerateKey(rand.Reader, 1024)
+	if _, err := NewSigningKey(small); err != ErrUnsupportedKey {
+		t.Errorf("expected ErrUnsupportedKey for a 1024-bit key, got %v", err)
+	}
+
+	dev := DeriveSigningKey("secret")
+	if DeriveSigningKey("secret").ID != dev.ID {
+		t.Error("derived keys should be stable")
+	}
+
+	jwks := NewKeySet(signing, []VerificationKey{dev.VerificationKey}, "secret").JWKS()
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("JWKS has %d keys, want 2", len(jwks.Keys))
+	}
+	if k := jwks.Keys[0]; k.Kid != signing.ID || k.Kty != "RSA" || k.Alg != "RS256" || k.N == "" || k.E != "AQAB" {
+		t.Errorf("unexpected RSA JWK %+v", k)
+	}
+	if k := jwks.Keys[1]; k.Kty != "OKP" || k.Crv != "Ed25519" || k.Alg != "EdDSA" || k.X == "" {
+		t.Errorf("unexpected Ed25519 JWK %+v", k)
+	}
+}
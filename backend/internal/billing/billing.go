@@ -0,0 +1,215 @@
+// Package billing charges tenants for their plan through Stripe Billing. A
+// tenant subscribes in a Stripe Checkout session and manages its card,
+// invoices and cancellation in the Stripe billing portal. Stripe's
+// subscription webhooks keep the tenant's plan in step: every store of the
+// tenant is on the plan while its subscription is paid for, keeps it for a
+// grace period once a renewal fails, and lapses to the free plan after.
+package billing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+)
+
+// FreePlan is the plan of tenants without a paid subscription
+const FreePlan = "free"
+
+// DefaultGracePeriod is how long a tenant keeps its plan after a failed
+// renewal when BILLING_GRACE_PERIOD is unset
+const DefaultGracePeriod = 7 * 24 * time.Hour
+
+// MaxWebhookBytes bounds a Stripe webhook body
+const MaxWebhookBytes = 1 << 20
+
+var (
+	// ErrRejected is returned when Stripe refused a request, such as for a
+	// customer that was deleted. Anything else is a failure to reach it.
+	ErrRejected = errors.New("request rejected by stripe")
+	// ErrInvalidSignature is returned for webhooks that did not come from
+	// Stripe
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+)
+
+// Status is a Stripe subscription status
+type Status string
+
+const (
+	StatusNone              Status = "none"
+	StatusIncomplete        Status = "incomplete"
+	StatusIncompleteExpired Status = "incomplete_expired"
+	StatusTrialing          Status = "trialing"
+	StatusActive            Status = "active"
+	StatusPastDue           Status = "past_due"
+	StatusUnpaid            Status = "unpaid"
+	StatusCanceled          Status = "canceled"
+	StatusPaused            Status = "paused"
+)
+
+// Paid reports whether a subscription in s is paid up
+func (s Status) Paid() bool {
+	return s == StatusActive || s == StatusTrialing
+}
+
+// Overdue reports whether a subscription in s failed to renew and Stripe is
+// still retrying the charge
+func (s Status) Overdue() bool {
+	return s == StatusPastDue || s == StatusUnpaid
+}
+
+// Ended reports whether a subscription in s is over for good
+func (s Status) Ended() bool {
+	return s == StatusCanceled || s == StatusIncompleteExpired
+}
+
+// Standing is the plan a subscription to plan in status leaves its tenant
+// on at now, and until when an overdue one keeps it. graceUntil is the
+// deadline an earlier failed renewal started, if any; the first failure
+// starts one grace long.
+func Standing(plan string, status Status, graceUntil, now time.Time, grace time.Duration) (string, time.Time) {
+	switch {
+	case status.Paid():
+		return plan, time.Time{}
+	case status.Overdue():
+		if graceUntil.IsZero() {
+			graceUntil = now.Add(grace)
+		}
+		if now.Before(graceUntil) {
+			return plan, graceUntil
+		}
+		return FreePlan, graceUntil
+	}
+	return FreePlan, time.Time{}
+}
+
+// Config holds the Stripe credentials. Billing is off without a secret
+// key.
+type Config struct {
+	SecretKey string
+	// WebhookSecret signs the webhooks Stripe sends
+	WebhookSecret string
+	// Prices maps each paid plan to the Stripe price it is billed at
+	Prices map[string]string
+	// OveragePrices maps usage metrics to the one-time Stripe price each
+	// unit over a plan's allowance is billed at. Overage of a metric
+	// without a price is reported but not billed.
+	OveragePrices map[string]string
+	// GracePeriod is how long a tenant keeps its plan after a failed
+	// renewal
+	GracePeriod time.Duration
+	// BaseURL is overridden in tests
+	BaseURL string
+}
+
+// ParsePrices reads a list of name=price pairs separated by commas, such
+// as "pro=price_1PqXyZ"
+func ParsePrices(s string) (map[string]string, error) {
+	prices := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, price, ok := strings.Cut(pair, "=")
+		name, price = strings.TrimSpace(name), strings.TrimSpace(price)
+		if !ok || name == "" || price == "" {
+			return nil, fmt.Errorf("%q is not name=price", pair)
+		}
+		if name == FreePlan {
+			return nil, fmt.Errorf("the %s plan is not billed", FreePlan)
+		}
+		if _, dup := prices[name]; dup {
+			return nil, fmt.Errorf("%q is priced twice", name)
+		}
+		prices[name] = price
+	}
+	return prices, nil
+}
+
+// Plans lists the plans with a price, in order
+func (c *Client) Plans() []string {
+	plans := make([]string, 0, len(c.cfg.Prices))
+	for plan := range c.cfg.Prices {
+		plans = append(plans, plan)
+	}
+	sort.Strings(plans)
+	return plans
+}
+
+// PlanFor returns the plan billed at price
+func (c *Client) PlanFor(price string) (string, bool) {
+	for plan, p := range c.cfg.Prices {
+		if p == price {
+			return plan, true
+		}
+	}
+	return "", false
+}
+
+// OveragePrice returns the price overage of metric is billed at
+func (c *Client) OveragePrice(metric string) (string, bool) {
+	price, ok := c.cfg.OveragePrices[metric]
+	return price, ok
+}
+
+// Apply records what event says of a subscription on the tenant whose
+// Stripe customer it belongs to, and moves the tenant's stores to the plan
+// it leaves them on. It reports false for customers that are no tenant's,
+// for events older than the last one applied, and for the end of a
+// subscription the tenant has since replaced. q should be in a transaction
+// so the stores move with the record.
+func (c *Client) Apply(ctx context.Context, q *database.Queries, event Event, now time.Time) (database.TenantBilling, bool, error) {
+	sub := event.Subscription
+	row, err := q.LockTenantBillingByCustomer(ctx, sub.CustomerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.TenantBilling{}, false, nil
+	}
+	if err != nil {
+		return database.TenantBilling{}, false, err
+	}
+	if row.SyncedAt.Valid && event.Created.Before(row.SyncedAt.Time) {
+		return row, false, nil
+	}
+	if row.StripeSubscriptionID.Valid && row.StripeSubscriptionID.String != sub.ID && sub.Status.Ended() {
+		return row, false, nil
+	}
+
+	plan, ok := c.PlanFor(sub.PriceID)
+	if !ok {
+		plan = FreePlan
+	}
+	var graceUntil time.Time
+	if row.GraceUntil.Valid && row.StripeSubscriptionID.String == sub.ID {
+		graceUntil = row.GraceUntil.Time
+	}
+	applied, graceUntil := Standing(plan, sub.Status, graceUntil, now, c.cfg.GracePeriod)
+
+	row, err = q.UpdateTenantBillingSubscription(ctx, database.UpdateTenantBillingSubscriptionParams{
+		StripeSubscriptionID: sql.NullString{String: sub.ID, Valid: true},
+		Plan:                 plan,
+		Status:               string(sub.Status),
+		CurrentPeriodEnd:     nullTime(sub.CurrentPeriodEnd),
+		CancelAtPeriodEnd:    sub.CancelAtPeriodEnd,
+		GraceUntil:           nullTime(graceUntil),
+		AppliedPlan:          applied,
+		SyncedAt:             nullTime(event.Created),
+		TenantID:             row.TenantID,
+	})
+	if err != nil {
+		return database.TenantBilling{}, false, err
+	}
+	if _, err := q.SetTenantStorePlans(ctx, database.SetTenantStorePlansParams{Plan: applied, TenantID: row.TenantID}); err != nil {
+		return database.TenantBilling{}, false, err
+	}
+	return row, true, nil
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -0,0 +1,245 @@
+package billing
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestStanding(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	grace := 7 * 24 * time.Hour
+	running := now.Add(48 * time.Hour)
+
+	tests := []struct {
+		name       string
+		status     Status
+		graceUntil time.Time
+		wantPlan   string
+		wantUntil  time.Time
+	}{
+		{name: "active", status: StatusActive, wantPlan: "pro"},
+		{name: "trialing", status: StatusTrialing, wantPlan: "pro"},
+		{name: "paid again clears grace", status: StatusActive, graceUntil: running, wantPlan: "pro"},
+		{name: "first failed renewal", status: StatusPastDue, wantPlan: "pro", wantUntil: now.Add(grace)},
+		{name: "grace running", status: StatusUnpaid, graceUntil: running, wantPlan: "pro", wantUntil: running},
+		{name: "grace over", status: StatusPastDue, graceUntil: now, wantPlan: FreePlan, wantUntil: now},
+		{name: "canceled", status: StatusCanceled, graceUntil: running, wantPlan: FreePlan},
+		{name: "first payment pending", status: StatusIncomplete, wantPlan: FreePlan},
+		{name: "paused", status: StatusPaused, wantPlan: FreePlan},
+	}
+	for _, tt := range tests {
+		plan, until := Standing("pro", tt.status, tt.graceUntil, now, grace)
+		if plan != tt.wantPlan || !until.Equal(tt.wantUntil) {
+			t.Errorf("%s: Standing() = %s, %v, want %s, %v", tt.name, plan, until, tt.wantPlan, tt.wantUntil)
+		}
+	}
+}
+
+func TestParsePrices(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    map[string]string
+		wantErr bool
+	}{
+		{in: "", want: map[string]string{}},
+		{in: "pro=price_1", want: map[string]string{"pro": "price_1"}},
+		{in: " pro = price_1 , team=price_2,", want: map[string]string{"pro": "price_1", "team": "price_2"}},
+		{in: "free=price_0", wantErr: true},
+		{in: "pro", wantErr: true},
+		{in: "pro=", wantErr: true},
+		{in: "pro=price_1,pro=price_2", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePrices(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePrices(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) && !tt.wantErr {
+			t.Errorf("ParsePrices(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	c := New(Config{Prices: map[string]string{"team": "price_2", "pro": "price_1"}})
+	if got := c.Plans(); len(got) != 2 || got[0] != "pro" || got[1] != "team" {
+		t.Errorf("Plans() = %v", got)
+	}
+	if plan, ok := c.PlanFor("price_2"); !ok || plan != "team" {
+		t.Errorf("PlanFor(price_2) = %q, %v", plan, ok)
+	}
+	if _, ok := c.PlanFor("price_9"); ok {
+		t.Error("PlanFor() found an unknown price")
+	}
+}
+
+func TestCreateCheckoutSession(t *testing.T) {
+	tenantID := uuid.New()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer sk_test" || r.Header.Get("Stripe-Version") == "" {
+			t.Errorf("headers = %v", r.Header)
+		}
+		if r.URL.Path != "/v1/checkout/sessions" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		r.ParseForm()
+		want := map[string]string{
+			"mode":                                   "subscription",
+			"customer":                               "cus_1",
+			"line_items[0][price]":                   "price_1",
+			"subscription_data[metadata][tenant_id]": tenantID.String(),
+			"success_url":                            "https://app.example/billing?checkout=success",
+		}
+		for k, v := range want {
+			if r.PostForm.Get(k) != v {
+				t.Errorf("%s = %q, want %q", k, r.PostForm.Get(k), v)
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id": "cs_1", "url": "https://checkout.stripe.example/cs_1"})
+	}))
+	defer srv.Close()
+
+	c := New(Config{SecretKey: "sk_test", Prices: map[string]string{"pro": "price_1"}, BaseURL: srv.URL})
+	got, err := c.CreateCheckoutSession(context.Background(), Checkout{
+		CustomerID: "cus_1",
+		TenantID:   tenantID,
+		Plan:       "pro",
+		SuccessURL: "https://app.example/billing?checkout=success",
+		CancelURL:  "https://app.example/billing",
+	})
+	if err != nil || got != "https://checkout.stripe.example/cs_1" {
+		t.Errorf("CreateCheckoutSession() = %q, %v", got, err)
+	}
+
+	if _, err := c.CreateCheckoutSession(context.Background(), Checkout{CustomerID: "cus_1", Plan: "team"}); !errors.Is(err, ErrRejected) {
+		t.Errorf("CreateCheckoutSession() for an unpriced plan error = %v, want ErrRejected", err)
+	}
+}
+
+func TestCreateInvoiceItem(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/invoiceitems" || r.Header.Get("Idempotency-Key") != "overage-1" {
+			t.Errorf("unexpected request %s, Idempotency-Key %q", r.URL.Path, r.Header.Get("Idempotency-Key"))
+		}
+		r.ParseForm()
+		if r.PostForm.Get("customer") != "cus_1" || r.PostForm.Get("price") != "price_2" || r.PostForm.Get("quantity") != "3" {
+			t.Errorf("form = %v", r.PostForm)
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id": "ii_1"})
+	}))
+	defer srv.Close()
+
+	c := New(Config{SecretKey: "sk_test", OveragePrices: map[string]string{"orders": "price_2"}, BaseURL: srv.URL})
+	price, ok := c.OveragePrice("orders")
+	if !ok {
+		t.Fatal("OveragePrice(orders) is unset")
+	}
+	if _, ok := c.OveragePrice("api_calls"); ok {
+		t.Error("OveragePrice(api_calls) is set")
+	}
+	got, err := c.CreateInvoiceItem(context.Background(), InvoiceItem{
+		CustomerID:     "cus_1",
+		PriceID:        price,
+		Quantity:       3,
+		Description:    "orders overage",
+		IdempotencyKey: "overage-1",
+	})
+	if err != nil || got != "ii_1" {
+		t.Errorf("CreateInvoiceItem() = %q, %v", got, err)
+	}
+}
+
+func TestStripeErrors(t *testing.T) {
+	tests := []struct {
+		status       int
+		wantRejected bool
+	}{
+		{status: http.StatusBadRequest, wantRejected: true},
+		{status: http.StatusNotFound, wantRejected: true},
+		{status: http.StatusTooManyRequests},
+		{status: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			fmt.Fprint(w, `{"error":{"message":"No such customer: 'cus_9'","type":"invalid_request_error"}}`)
+		}))
+		_, err := New(Config{SecretKey: "sk_test", BaseURL: srv.URL}).CreatePortalSession(context.Background(), "cus_9", "https://app.example")
+		srv.Close()
+		if err == nil || errors.Is(err, ErrRejected) != tt.wantRejected {
+			t.Errorf("status %d: error = %v, want rejected %v", tt.status, err, tt.wantRejected)
+		}
+		if err != nil && tt.wantRejected && err.Error() != "stripe billing portal session: request rejected by stripe: status "+strconv.Itoa(tt.status)+": No such customer: 'cus_9'" {
+			t.Errorf("status %d: error = %q, want Stripe's message", tt.status, err)
+		}
+	}
+}
+
+func TestParseWebhook(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	c := New(Config{SecretKey: "sk_test", WebhookSecret: "whsec"})
+	sign := func(at time.Time, body string) string {
+		ts := strconv.FormatInt(at.Unix(), 10)
+		mac := hmac.New(sha256.New, []byte("whsec"))
+		mac.Write([]byte(ts + "." + body))
+		return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+	}
+	updated := `{"id":"evt_1","type":"customer.subscription.updated","created":1792324800,"data":{"object":{"id":"sub_1","customer":"cus_1","status":"past_due","cancel_at_period_end":true,"items":{"data":[{"price":{"id":"price_1"},"current_period_end":1794916800}]}}}}`
+	invoice := `{"id":"evt_2","type":"invoice.paid","created":1792324800,"data":{"object":{"id":"in_1"}}}`
+
+	tests := []struct {
+		name      string
+		body      string
+		signature string
+		wantOK    bool
+		wantErr   error
+	}{
+		{name: "subscription update", body: updated, signature: sign(now, updated), wantOK: true},
+		{name: "rolled secret", body: updated, signature: sign(now, updated) + ",v1=00ff", wantOK: true},
+		{name: "other event", body: invoice, signature: sign(now, invoice)},
+		{name: "bad signature", body: updated, signature: sign(now, invoice), wantErr: ErrInvalidSignature},
+		{name: "replayed", body: updated, signature: sign(now.Add(-time.Hour), updated), wantErr: ErrInvalidSignature},
+		{name: "no signature", body: updated, wantErr: ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/webhooks/billing/stripe", nil)
+			if tt.signature != "" {
+				r.Header.Set("Stripe-Signature", tt.signature)
+			}
+			event, ok, err := c.ParseWebhook(r, []byte(tt.body), now)
+			if !errors.Is(err, tt.wantErr) || ok != tt.wantOK {
+				t.Fatalf("ParseWebhook() = %v, %v, want %v, %v", ok, err, tt.wantOK, tt.wantErr)
+			}
+			if !ok {
+				return
+			}
+			want := Subscription{
+				ID:                "sub_1",
+				CustomerID:        "cus_1",
+				Status:            StatusPastDue,
+				PriceID:           "price_1",
+				CurrentPeriodEnd:  time.Unix(1794916800, 0).UTC(),
+				CancelAtPeriodEnd: true,
+			}
+			if event.Subscription != want || event.ID != "evt_1" || event.Created.Unix() != 1792324800 {
+				t.Errorf("event = %+v", event)
+			}
+		})
+	}
+
+	if _, _, err := New(Config{}).ParseWebhook(httptest.NewRequest(http.MethodPost, "/", nil), []byte(updated), now); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("ParseWebhook() without a secret error = %v, want ErrInvalidSignature", err)
+	}
+}
@@ -0,0 +1,101 @@
+package billing
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// LapserConfig configures a Lapser
+type LapserConfig struct {
+	Interval time.Duration
+	// BatchSize bounds the tenants lapsed in each transaction
+	BatchSize int
+	Logger    *slog.Logger
+}
+
+// Lapser moves the stores of tenants whose grace period ran out to the free
+// plan. Webhooks apply every other change as it happens, but nothing is
+// sent when a grace period ends. Each tenant is locked while it lapses, so
+// running several at once is harmless.
+type Lapser struct {
+	pool *pgxpool.Pool
+	db   *database.Queries
+	cfg  LapserConfig
+}
+
+// NewLapser creates a lapser over pool
+func NewLapser(pool *pgxpool.Pool, cfg LapserConfig) *Lapser {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 10 * time.Minute
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 100
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &Lapser{pool: pool, db: database.New(pool), cfg: cfg}
+}
+
+// Run lapses due tenants once an interval until ctx is cancelled
+func (l *Lapser) Run(ctx context.Context) error {
+	ticker := time.NewTicker(l.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := l.LapseDue(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			l.cfg.Logger.Error("billing grace period lapse failed", "error", err)
+		} else if n > 0 {
+			l.cfg.Logger.Info("tenants lapsed to the free plan", "tenants", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// LapseDue lapses every tenant whose grace period ended by now, a batch at
+// a time, and returns how many it lapsed
+func (l *Lapser) LapseDue(ctx context.Context, now time.Time) (int, error) {
+	var total int
+	for ctx.Err() == nil {
+		n, err := l.lapseBatch(ctx, now)
+		total += n
+		if err != nil || n < l.cfg.BatchSize {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+func (l *Lapser) lapseBatch(ctx context.Context, now time.Time) (int, error) {
+	tx, err := l.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+	q := l.db.WithTx(tx)
+
+	tenants, err := q.LapseTenantBilling(ctx, database.LapseTenantBillingParams{
+		FreePlan: FreePlan,
+		Now:      now,
+		RowLimit: int32(l.cfg.BatchSize),
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, tenantID := range tenants {
+		if _, err := q.SetTenantStorePlans(ctx, database.SetTenantStorePlansParams{Plan: FreePlan, TenantID: tenantID}); err != nil {
+			return 0, err
+		}
+	}
+	return len(tenants), tx.Commit(ctx)
+}
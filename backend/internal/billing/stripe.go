@@ -0,0 +1,319 @@
+package billing
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/tracing"
+	"github.com/google/uuid"
+)
+
+// stripeVersion pins the shape of Stripe's responses
+const stripeVersion = "2024-06-20"
+
+// webhookTolerance is how far a webhook's signed timestamp may be from
+// now, so a captured webhook can't be replayed later
+const webhookTolerance = 5 * time.Minute
+
+// Client calls the Stripe API and reads its webhooks
+type Client struct {
+	cfg    Config
+	client *http.Client
+}
+
+// New returns a client for cfg
+func New(cfg Config) *Client {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api.stripe.com"
+	}
+	if cfg.GracePeriod <= 0 {
+		cfg.GracePeriod = DefaultGracePeriod
+	}
+	return &Client{cfg: cfg, client: &http.Client{Timeout: 60 * time.Second, Transport: tracing.Transport(nil)}}
+}
+
+// Customer is who a tenant is billed as
+type Customer struct {
+	TenantID uuid.UUID
+	Name     string
+	Email    string
+}
+
+// CreateCustomer creates a Stripe customer and returns its ID
+func (c *Client) CreateCustomer(ctx context.Context, customer Customer) (string, error) {
+	form := url.Values{
+		"name":                {customer.Name},
+		"metadata[tenant_id]": {customer.TenantID.String()},
+	}
+	if customer.Email != "" {
+		form.Set("email", customer.Email)
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := c.post(ctx, "/v1/customers", "", form, &created); err != nil {
+		return "", fmt.Errorf("stripe customer: %w", err)
+	}
+	return created.ID, nil
+}
+
+// Checkout asks for a Checkout session subscribing a customer to a plan
+type Checkout struct {
+	CustomerID string
+	TenantID   uuid.UUID
+	Plan       string
+	// SuccessURL and CancelURL are where Stripe sends the customer back to
+	SuccessURL string
+	CancelURL  string
+}
+
+// CreateCheckoutSession starts a Checkout session and returns the URL the
+// customer pays at
+func (c *Client) CreateCheckoutSession(ctx context.Context, checkout Checkout) (string, error) {
+	price, ok := c.cfg.Prices[checkout.Plan]
+	if !ok {
+		return "", fmt.Errorf("%w: the %s plan has no price", ErrRejected, checkout.Plan)
+	}
+	form := url.Values{
+		"mode":                                   {"subscription"},
+		"customer":                               {checkout.CustomerID},
+		"client_reference_id":                    {checkout.TenantID.String()},
+		"line_items[0][price]":                   {price},
+		"line_items[0][quantity]":                {"1"},
+		"subscription_data[metadata][tenant_id]": {checkout.TenantID.String()},
+		"success_url":                            {checkout.SuccessURL},
+		"cancel_url":                             {checkout.CancelURL},
+	}
+	var session struct {
+		URL string `json:"url"`
+	}
+	if err := c.post(ctx, "/v1/checkout/sessions", "", form, &session); err != nil {
+		return "", fmt.Errorf("stripe checkout session: %w", err)
+	}
+	return session.URL, nil
+}
+
+// CreatePortalSession starts a billing portal session for a customer and
+// returns its URL. The customer comes back to returnURL.
+func (c *Client) CreatePortalSession(ctx context.Context, customerID, returnURL string) (string, error) {
+	form := url.Values{
+		"customer":   {customerID},
+		"return_url": {returnURL},
+	}
+	var session struct {
+		URL string `json:"url"`
+	}
+	if err := c.post(ctx, "/v1/billing_portal/sessions", "", form, &session); err != nil {
+		return "", fmt.Errorf("stripe billing portal session: %w", err)
+	}
+	return session.URL, nil
+}
+
+// InvoiceItem is a charge added to a customer's next invoice
+type InvoiceItem struct {
+	CustomerID string
+	// PriceID is a one-time price charged Quantity times
+	PriceID     string
+	Quantity    int64
+	Description string
+	// IdempotencyKey makes retrying the same charge safe
+	IdempotencyKey string
+}
+
+// CreateInvoiceItem adds an item to the customer's next invoice and
+// returns its ID
+func (c *Client) CreateInvoiceItem(ctx context.Context, item InvoiceItem) (string, error) {
+	form := url.Values{
+		"customer":    {item.CustomerID},
+		"price":       {item.PriceID},
+		"quantity":    {strconv.FormatInt(item.Quantity, 10)},
+		"description": {item.Description},
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := c.post(ctx, "/v1/invoiceitems", item.IdempotencyKey, form, &created); err != nil {
+		return "", fmt.Errorf("stripe invoice item: %w", err)
+	}
+	return created.ID, nil
+}
+
+// post sends a form-encoded Stripe API request and decodes the response
+// into out. 4xx responses other than 429 are rejections.
+func (c *Client) post(ctx context.Context, path, idempotencyKey string, form url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.BaseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.cfg.SecretKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Stripe-Version", stripeVersion)
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		var stripeErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		msg := strings.TrimSpace(string(body))
+		if json.Unmarshal(body, &stripeErr) == nil && stripeErr.Error.Message != "" {
+			msg = stripeErr.Error.Message
+		}
+		err = fmt.Errorf("status %d: %s", resp.StatusCode, msg)
+		if resp.StatusCode/100 == 4 && resp.StatusCode != http.StatusTooManyRequests {
+			return fmt.Errorf("%w: %v", ErrRejected, err)
+		}
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Subscription is the state of a Stripe subscription
+type Subscription struct {
+	ID         string
+	CustomerID string
+	Status     Status
+	// PriceID is the price of its first item
+	PriceID           string
+	CurrentPeriodEnd  time.Time
+	CancelAtPeriodEnd bool
+}
+
+// Event is a subscription change Stripe sent
+type Event struct {
+	ID           string
+	Type         string
+	Created      time.Time
+	Subscription Subscription
+}
+
+// subscriptionEvents are the webhook events read. Each carries the
+// subscription as it is after the change.
+var subscriptionEvents = map[string]bool{
+	"customer.subscription.created": true,
+	"customer.subscription.updated": true,
+	"customer.subscription.deleted": true,
+	"customer.subscription.paused":  true,
+	"customer.subscription.resumed": true,
+}
+
+// ParseWebhook checks the Stripe-Signature header, an HMAC-SHA256 of the
+// signed timestamp and body keyed with the webhook secret, and reads
+// subscription events. It reports false for other kinds of event.
+func (c *Client) ParseWebhook(r *http.Request, body []byte, now time.Time) (Event, bool, error) {
+	if c.cfg.WebhookSecret == "" {
+		return Event{}, false, fmt.Errorf("%w: no webhook secret is configured", ErrInvalidSignature)
+	}
+	if err := c.verify(r.Header.Get("Stripe-Signature"), body, now); err != nil {
+		return Event{}, false, err
+	}
+
+	var event struct {
+		ID      string `json:"id"`
+		Type    string `json:"type"`
+		Created int64  `json:"created"`
+		Data    struct {
+			Object struct {
+				ID                string `json:"id"`
+				Customer          string `json:"customer"`
+				Status            Status `json:"status"`
+				CurrentPeriodEnd  int64  `json:"current_period_end"`
+				CancelAtPeriodEnd bool   `json:"cancel_at_period_end"`
+				Items             struct {
+					Data []struct {
+						Price struct {
+							ID string `json:"id"`
+						} `json:"price"`
+						// Newer API versions keep the period on each item
+						CurrentPeriodEnd int64 `json:"current_period_end"`
+					} `json:"data"`
+				} `json:"items"`
+			} `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return Event{}, false, fmt.Errorf("stripe webhook: %w", err)
+	}
+	if !subscriptionEvents[event.Type] {
+		return Event{}, false, nil
+	}
+
+	obj := event.Data.Object
+	sub := Subscription{
+		ID:                obj.ID,
+		CustomerID:        obj.Customer,
+		Status:            obj.Status,
+		CancelAtPeriodEnd: obj.CancelAtPeriodEnd,
+	}
+	periodEnd := obj.CurrentPeriodEnd
+	if len(obj.Items.Data) > 0 {
+		sub.PriceID = obj.Items.Data[0].Price.ID
+		if periodEnd == 0 {
+			periodEnd = obj.Items.Data[0].CurrentPeriodEnd
+		}
+	}
+	if periodEnd > 0 {
+		sub.CurrentPeriodEnd = time.Unix(periodEnd, 0).UTC()
+	}
+	if sub.ID == "" || sub.CustomerID == "" || sub.Status == "" {
+		return Event{}, false, fmt.Errorf("stripe webhook: %s event has no subscription", event.Type)
+	}
+	return Event{ID: event.ID, Type: event.Type, Created: time.Unix(event.Created, 0).UTC(), Subscription: sub}, true, nil
+}
+
+// verify checks a Stripe-Signature header such as "t=1492774577,v1=5257a8…".
+// Any of several v1 signatures may match, as Stripe sends one per secret
+// while a secret is being rolled.
+func (c *Client) verify(header string, body []byte, now time.Time) error {
+	var timestamp string
+	var signatures [][]byte
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			timestamp = v
+		case "v1":
+			if sig, err := hex.DecodeString(v); err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+	sent, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(sent, 0)); age > webhookTolerance || age < -webhookTolerance {
+		return fmt.Errorf("%w: timestamp is outside the tolerance", ErrInvalidSignature)
+	}
+
+	mac := hmac.New(sha256.New, []byte(c.cfg.WebhookSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	want := mac.Sum(nil)
+	for _, sig := range signatures {
+		if hmac.Equal(sig, want) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
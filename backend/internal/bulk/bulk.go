@@ -0,0 +1,210 @@
+// Package bulk describes batches of changes made across a tenant's stores
+// in one request, such as publishing five hundred products. A batch is
+// checked as a whole when it is submitted, then the worker applies its
+// mutations one at a time, so one that fails leaves the rest in place and
+// is reported on its own.
+package bulk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dfodeker/terminus/internal/orders"
+	"github.com/dfodeker/terminus/internal/service/products"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/google/uuid"
+)
+
+// MaxMutations bounds a batch
+const MaxMutations = 1000
+
+// maxPriceCents matches what the variant endpoints accept
+const maxPriceCents = 100_000_000
+
+// Operation statuses
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+// Mutation outcomes
+const (
+	ResultSucceeded = "succeeded"
+	ResultFailed    = "failed"
+)
+
+// Mutation kinds
+const (
+	OpProductSetStatus = "product.set_status"
+	OpProductUpdate    = "product.update"
+	OpProductDelete    = "product.delete"
+	OpVariantSetPrice  = "variant.set_price"
+	OpOrderSetTags     = "order.set_tags"
+)
+
+// permissions is what a member needs to submit each kind of mutation, the
+// same as making the change through its own endpoint
+var permissions = map[string]string{
+	OpProductSetStatus: "products:edit",
+	OpProductUpdate:    "products:edit",
+	OpProductDelete:    "products:delete",
+	OpVariantSetPrice:  "products:edit",
+	OpOrderSetTags:     "orders:manage",
+}
+
+// Ops are the mutation kinds a batch can hold
+var Ops = []string{OpProductSetStatus, OpProductUpdate, OpProductDelete, OpVariantSetPrice, OpOrderSetTags}
+
+// Args is the job that applies one submitted batch
+type Args struct {
+	OperationID uuid.UUID `json:"operation_id"`
+}
+
+func (Args) Kind() string { return "bulk.run" }
+
+// Mutation is one change in a batch: what to do, to which product, variant
+// or order in which store, and with what. Input's shape depends on Op.
+type Mutation struct {
+	Op      string          `json:"op"`
+	StoreID uuid.UUID       `json:"store_id"`
+	ID      uuid.UUID       `json:"id"`
+	Input   json.RawMessage `json:"input,omitempty"`
+}
+
+// ProductStatusInput moves a product to another status, as publishing and
+// archiving do
+type ProductStatusInput struct {
+	Status string `json:"status"`
+}
+
+// ProductUpdateInput changes the given fields of a product
+type ProductUpdateInput struct {
+	Name             *string `json:"name"`
+	Description      *string `json:"description"`
+	Tags             *string `json:"tags"`
+	InventoryTracked *bool   `json:"inventory_tracked"`
+}
+
+// VariantPriceInput reprices a variant. Without compare_at_cents the
+// variant keeps the one it has.
+type VariantPriceInput struct {
+	PriceCents     *int32 `json:"price_cents"`
+	CompareAtCents *int32 `json:"compare_at_cents"`
+}
+
+// OrderTagsInput replaces an order's tags
+type OrderTagsInput struct {
+	Tags []string `json:"tags"`
+}
+
+// Decode reads a mutation's input. Unknown fields are rejected, so a typo
+// isn't silently ignored across a whole batch.
+func Decode[T any](m Mutation) (T, error) {
+	var in T
+	if len(m.Input) == 0 || bytes.Equal(m.Input, []byte("null")) {
+		return in, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(m.Input))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&in); err != nil {
+		return in, err
+	}
+	return in, nil
+}
+
+// Validate checks a batch before it is queued. Field names are prefixed
+// with the mutation's place in the batch, such as mutations.3.input.status.
+// Whether the targets exist, and whether a change is allowed from their
+// current state, is only known when the worker applies them.
+func Validate(mutations []Mutation) error {
+	var v validate.Validator
+	if len(mutations) == 0 {
+		v.Fail("mutations", validate.CodeRequired, "must have at least one mutation")
+	}
+	if len(mutations) > MaxMutations {
+		v.Fail("mutations", validate.CodeTooLong, fmt.Sprintf("must have at most %d mutations", MaxMutations))
+	}
+	if !v.Valid() {
+		return v.Err()
+	}
+
+	for i, m := range mutations {
+		prefix := fmt.Sprintf("mutations.%d.", i)
+		if !v.OneOf(prefix+"op", m.Op, Ops...) {
+			continue
+		}
+		v.Check(m.StoreID != uuid.Nil, prefix+"store_id", validate.CodeRequired, "is required")
+		v.Check(m.ID != uuid.Nil, prefix+"id", validate.CodeRequired, "is required")
+		checkInput(&v, prefix+"input", m)
+	}
+	return v.Err()
+}
+
+func checkInput(v *validate.Validator, field string, m Mutation) {
+	fail := func(err error) {
+		v.Fail(field, validate.CodeInvalid, strings.TrimPrefix(err.Error(), "json: "))
+	}
+	switch m.Op {
+	case OpProductSetStatus:
+		in, err := Decode[ProductStatusInput](m)
+		if err != nil {
+			fail(err)
+			return
+		}
+		if v.Required(field+".status", in.Status) {
+			v.OneOf(field+".status", in.Status, products.Statuses...)
+		}
+	case OpProductUpdate:
+		in, err := Decode[ProductUpdateInput](m)
+		if err != nil {
+			fail(err)
+			return
+		}
+		if in.Name == nil && in.Description == nil && in.Tags == nil && in.InventoryTracked == nil {
+			v.Fail(field, validate.CodeRequired, "must change at least one field")
+		}
+		if in.Name != nil && v.Required(field+".name", *in.Name) {
+			v.MaxLength(field+".name", *in.Name, products.MaxNameLength)
+		}
+	case OpProductDelete:
+		v.Check(len(m.Input) == 0 || bytes.Equal(m.Input, []byte("null")), field, validate.CodeInvalid, "must be empty")
+	case OpVariantSetPrice:
+		in, err := Decode[VariantPriceInput](m)
+		if err != nil {
+			fail(err)
+			return
+		}
+		if v.Check(in.PriceCents != nil, field+".price_cents", validate.CodeRequired, "is required") {
+			v.Between(field+".price_cents", int64(*in.PriceCents), 0, maxPriceCents)
+		}
+		if in.CompareAtCents != nil {
+			v.Between(field+".compare_at_cents", int64(*in.CompareAtCents), 0, maxPriceCents)
+		}
+	case OpOrderSetTags:
+		in, err := Decode[OrderTagsInput](m)
+		if err != nil {
+			fail(err)
+			return
+		}
+		if _, err := orders.NormalizeTags(in.Tags); err != nil {
+			v.Fail(field+".tags", validate.CodeInvalid, strings.TrimPrefix(err.Error(), orders.ErrInvalidTags.Error()+": "))
+		}
+	}
+}
+
+// Permissions lists, sorted, the permissions needed to submit a batch
+func Permissions(mutations []Mutation) []string {
+	var out []string
+	for _, m := range mutations {
+		if p, ok := permissions[m.Op]; ok && !slices.Contains(out, p) {
+			out = append(out, p)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
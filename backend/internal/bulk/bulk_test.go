@@ -0,0 +1,149 @@
+package bulk
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/google/uuid"
+)
+
+func mutation(op, input string) Mutation {
+	m := Mutation{Op: op, StoreID: uuid.New(), ID: uuid.New()}
+	if input != "" {
+		m.Input = json.RawMessage(input)
+	}
+	return m
+}
+
+func invalidFields(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	invalid, ok := err.(*validate.Error)
+	if !ok {
+		t.Fatalf("error = %T %v, want *validate.Error", err, err)
+	}
+	var out []string
+	for _, f := range invalid.Fields {
+		out = append(out, f.Field)
+	}
+	return out
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		mutations []Mutation
+		want      []string
+	}{
+		{
+			name: "valid batch",
+			mutations: []Mutation{
+				mutation(OpProductSetStatus, `{"status":"archived"}`),
+				mutation(OpProductUpdate, `{"name":"Tee","inventory_tracked":true}`),
+				mutation(OpProductDelete, ""),
+				mutation(OpVariantSetPrice, `{"price_cents":1999,"compare_at_cents":2500}`),
+				mutation(OpOrderSetTags, `{"tags":["vip","Gift "]}`),
+			},
+		},
+		{
+			name: "empty batch",
+			want: []string{"mutations"},
+		},
+		{
+			name:      "too many mutations",
+			mutations: make([]Mutation, MaxMutations+1),
+			want:      []string{"mutations"},
+		},
+		{
+			name:      "unknown op",
+			mutations: []Mutation{mutation("product.publish", "")},
+			want:      []string{"mutations.0.op"},
+		},
+		{
+			name:      "missing targets",
+			mutations: []Mutation{{Op: OpProductDelete}},
+			want:      []string{"mutations.0.store_id", "mutations.0.id"},
+		},
+		{
+			name: "bad status",
+			mutations: []Mutation{
+				mutation(OpProductSetStatus, `{"status":"active"}`),
+				mutation(OpProductSetStatus, `{"status":"sold"}`),
+				mutation(OpProductSetStatus, ""),
+			},
+			want: []string{"mutations.1.input.status", "mutations.2.input.status"},
+		},
+		{
+			name:      "unknown input field",
+			mutations: []Mutation{mutation(OpProductSetStatus, `{"state":"draft"}`)},
+			want:      []string{"mutations.0.input"},
+		},
+		{
+			name: "update changes nothing",
+			mutations: []Mutation{
+				mutation(OpProductUpdate, `{}`),
+				mutation(OpProductUpdate, `{"name":""}`),
+			},
+			want: []string{"mutations.0.input", "mutations.1.input.name"},
+		},
+		{
+			name:      "delete with input",
+			mutations: []Mutation{mutation(OpProductDelete, `{"force":true}`)},
+			want:      []string{"mutations.0.input"},
+		},
+		{
+			name: "bad prices",
+			mutations: []Mutation{
+				mutation(OpVariantSetPrice, `{}`),
+				mutation(OpVariantSetPrice, `{"price_cents":-1,"compare_at_cents":200000000}`),
+			},
+			want: []string{"mutations.0.input.price_cents", "mutations.1.input.price_cents", "mutations.1.input.compare_at_cents"},
+		},
+		{
+			name:      "bad tags",
+			mutations: []Mutation{mutation(OpOrderSetTags, `{"tags":["a,b"]}`)},
+			want:      []string{"mutations.0.input.tags"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := invalidFields(t, Validate(tt.mutations))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	in, err := Decode[VariantPriceInput](mutation(OpVariantSetPrice, `{"price_cents":500}`))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if in.PriceCents == nil || *in.PriceCents != 500 || in.CompareAtCents != nil {
+		t.Errorf("Decode() = %+v, want price 500 and no compare-at price", in)
+	}
+
+	if _, err := Decode[OrderTagsInput](mutation(OpOrderSetTags, `{"tags":"vip"}`)); err == nil {
+		t.Error("Decode() accepted tags that are not a list")
+	}
+}
+
+func TestPermissions(t *testing.T) {
+	got := Permissions([]Mutation{
+		{Op: OpOrderSetTags},
+		{Op: OpProductSetStatus},
+		{Op: OpVariantSetPrice},
+		{Op: OpProductDelete},
+		{Op: "unknown"},
+	})
+	want := []string{"orders:manage", "products:delete", "products:edit"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Permissions() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,73 @@
+// Package bundles holds the rules for variants sold as a kit of other
+// variants: what a bundle may contain and how stock moves through it.
+package bundles
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxComponents caps the variants in one bundle
+	MaxComponents = 20
+	// MaxQuantity caps how many of one component a bundle holds
+	MaxQuantity = 1000
+)
+
+// Component is a variant in a bundle and how many of it one bundle holds
+type Component struct {
+	VariantID uuid.UUID `json:"variant_id"`
+	Quantity  int32     `json:"quantity"`
+}
+
+// Validate checks the components of bundle. An empty list is valid; it
+// turns the bundle back into an ordinary variant.
+func Validate(bundle uuid.UUID, components []Component) error {
+	if len(components) > MaxComponents {
+		return fmt.Errorf("at most %d components are allowed", MaxComponents)
+	}
+	seen := make(map[uuid.UUID]bool, len(components))
+	for i, c := range components {
+		switch {
+		case c.VariantID == uuid.Nil:
+			return fmt.Errorf("component %d: variant_id is required", i)
+		case c.VariantID == bundle:
+			return fmt.Errorf("component %d: a bundle cannot contain itself", i)
+		case seen[c.VariantID]:
+			return fmt.Errorf("component %d: variant is listed more than once", i)
+		case c.Quantity < 1 || c.Quantity > MaxQuantity:
+			return fmt.Errorf("component %d: quantity must be between 1 and %d", i, MaxQuantity)
+		}
+		seen[c.VariantID] = true
+	}
+	return nil
+}
+
+// ErrOverflow is returned when a stock change is too large once spread over
+// a bundle's components
+var ErrOverflow = errors.New("quantity is too large for this bundle")
+
+// Scale spreads a change of delta bundles over the components, returning
+// the change for each of them in order
+func Scale(delta int32, components []Component) ([]int32, error) {
+	deltas := make([]int32, len(components))
+	for i, c := range components {
+		d := int64(delta) * int64(c.Quantity)
+		if d > 1<<31-1 || d < -(1<<31) {
+			return nil, ErrOverflow
+		}
+		deltas[i] = int32(d)
+	}
+	return deltas, nil
+}
+
+// PerBundle is how many of a component line's units go with one unit of
+// its bundle line, from the quantities the order recorded
+func PerBundle(componentQuantity, bundleQuantity int32) int32 {
+	if bundleQuantity <= 0 {
+		return 0
+	}
+	return componentQuantity / bundleQuantity
+}
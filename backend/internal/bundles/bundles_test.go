@@ -0,0 +1,88 @@
+package bundles
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestValidate(t *testing.T) {
+	bundle, a, b := uuid.New(), uuid.New(), uuid.New()
+	tooMany := make([]Component, MaxComponents+1)
+	for i := range tooMany {
+		tooMany[i] = Component{VariantID: uuid.New(), Quantity: 1}
+	}
+
+	tests := []struct {
+		name       string
+		components []Component
+		wantErr    string
+	}{
+		{name: "empty", components: nil},
+		{name: "valid", components: []Component{{VariantID: a, Quantity: 2}, {VariantID: b, Quantity: 1}}},
+		{name: "too many", components: tooMany, wantErr: "at most"},
+		{name: "missing variant", components: []Component{{Quantity: 1}}, wantErr: "variant_id is required"},
+		{name: "contains itself", components: []Component{{VariantID: bundle, Quantity: 1}}, wantErr: "cannot contain itself"},
+		{name: "duplicate", components: []Component{{VariantID: a, Quantity: 1}, {VariantID: a, Quantity: 2}}, wantErr: "more than once"},
+		{name: "zero quantity", components: []Component{{VariantID: a}}, wantErr: "quantity must be"},
+		{name: "quantity too large", components: []Component{{VariantID: a, Quantity: MaxQuantity + 1}}, wantErr: "quantity must be"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(bundle, tt.components)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScale(t *testing.T) {
+	components := []Component{{VariantID: uuid.New(), Quantity: 2}, {VariantID: uuid.New(), Quantity: 1}}
+
+	tests := []struct {
+		name    string
+		delta   int32
+		want    []int32
+		wantErr error
+	}{
+		{name: "sold", delta: -3, want: []int32{-6, -3}},
+		{name: "returned", delta: 2, want: []int32{4, 2}},
+		{name: "overflow", delta: 1 << 30, wantErr: ErrOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Scale(tt.delta, components)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Scale() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Scale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPerBundle(t *testing.T) {
+	tests := []struct {
+		component, bundle, want int32
+	}{
+		{component: 6, bundle: 3, want: 2},
+		{component: 3, bundle: 3, want: 1},
+		{component: 4, bundle: 0, want: 0},
+	}
+	for _, tt := range tests {
+		if got := PerBundle(tt.component, tt.bundle); got != tt.want {
+			t.Errorf("PerBundle(%d, %d) = %d, want %d", tt.component, tt.bundle, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Package captcha checks CAPTCHA responses before a sign-in that has been
+// failing is let through. hCaptcha, reCAPTCHA and Cloudflare Turnstile
+// all verify responses with the same siteverify request, so any of them
+// can be used by pointing VerifyURL at it.
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/tracing"
+)
+
+// DefaultAfter is how many failed sign-ins in a row make an account need
+// a CAPTCHA when LOGIN_CAPTCHA_AFTER is unset
+const DefaultAfter = 3
+
+// Config configures the verifier. CAPTCHAs are off while Secret is empty.
+type Config struct {
+	VerifyURL string
+	Secret    string
+	// After is how many failed sign-ins in a row make an account need a
+	// CAPTCHA
+	After int
+}
+
+// Verifier checks the response a client got from solving a CAPTCHA
+type Verifier interface {
+	Verify(ctx context.Context, response, remoteIP string) (bool, error)
+}
+
+// SiteVerify is a Verifier for providers with a siteverify endpoint
+type SiteVerify struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// New returns a verifier for cfg
+func New(cfg Config) *SiteVerify {
+	return &SiteVerify{
+		url:    cfg.VerifyURL,
+		secret: cfg.Secret,
+		client: &http.Client{Timeout: 10 * time.Second, Transport: tracing.Transport(nil)},
+	}
+}
+
+// Verify asks the provider whether response is a solved CAPTCHA. An empty
+// response fails without asking.
+func (s *SiteVerify) Verify(ctx context.Context, response, remoteIP string) (bool, error) {
+	if response == "" {
+		return false, nil
+	}
+	form := url.Values{"secret": {s.secret}, "response": {response}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("captcha: verify: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("captcha: verify: %s", resp.Status)
+	}
+	var out struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return false, fmt.Errorf("captcha: verify: %w", err)
+	}
+	return out.Success, nil
+}
@@ -0,0 +1,53 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		if r.Form.Get("secret") != "shh" {
+			t.Errorf("secret = %q, want shh", r.Form.Get("secret"))
+		}
+		switch r.Form.Get("response") {
+		case "broken":
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			json.NewEncoder(w).Encode(map[string]any{
+				"success": r.Form.Get("response") == "solved" && r.Form.Get("remoteip") == "203.0.113.7",
+			})
+		}
+	}))
+	defer srv.Close()
+	v := New(Config{VerifyURL: srv.URL, Secret: "shh"})
+
+	tests := []struct {
+		name     string
+		response string
+		want     bool
+		wantErr  bool
+	}{
+		{name: "solved", response: "solved", want: true},
+		{name: "wrong", response: "guessed"},
+		{name: "missing", response: ""},
+		{name: "provider down", response: "broken", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := v.Verify(context.Background(), tt.response, "203.0.113.7")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package catalog
+
+// ChannelKind is where a sales channel sells a store's products
+type ChannelKind string
+
+const (
+	// ChannelOnlineStore is the store's own storefront. Every store has
+	// exactly one and it cannot be removed.
+	ChannelOnlineStore ChannelKind = "online_store"
+	// ChannelPOS is an in-person point of sale
+	ChannelPOS ChannelKind = "pos"
+	// ChannelMarketplaceFeed is a product feed read by a marketplace such
+	// as Google Shopping
+	ChannelMarketplaceFeed ChannelKind = "marketplace_feed"
+)
+
+// MaxChannelNameLength bounds a sales channel name
+const MaxChannelNameLength = 100
+
+// IsValid reports whether k is a known channel kind
+func (k ChannelKind) IsValid() bool {
+	switch k {
+	case ChannelOnlineStore, ChannelPOS, ChannelMarketplaceFeed:
+		return true
+	}
+	return false
+}
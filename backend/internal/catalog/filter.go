@@ -0,0 +1,149 @@
+// Package catalog parses the product listing filters shared by the tenant
+// and storefront APIs and names the sales channels products are sold on.
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidFilter wraps every filter parsing error
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// MaxFilterValues bounds how many statuses or tags one request may filter on
+const MaxFilterValues = 20
+
+// Filter narrows a product listing. Zero values match everything.
+type Filter struct {
+	Statuses []string
+	// Tags must all be present on a product; they are lowercased to match
+	// how tags are indexed
+	Tags []string
+	// MinPriceCents and MaxPriceCents match products with at least one active
+	// variant priced inside the (inclusive) range
+	MinPriceCents *int64
+	MaxPriceCents *int64
+	// InStock matches products with stock on an active variant (true) or
+	// without (false). Products that don't track inventory are always in stock.
+	InStock       *bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// ChannelID matches products published to the sales channel
+	ChannelID *uuid.UUID
+}
+
+// IsZero reports whether the filter matches every product
+func (f Filter) IsZero() bool {
+	return len(f.Statuses) == 0 && len(f.Tags) == 0 &&
+		f.MinPriceCents == nil && f.MaxPriceCents == nil &&
+		f.InStock == nil && f.CreatedAfter == nil && f.CreatedBefore == nil &&
+		f.ChannelID == nil
+}
+
+// ParseFilter reads filters from query parameters:
+//
+//	status=active,draft        any of the statuses
+//	tag=summer&tag=linen       all of the tags (comma-separated also works)
+//	min_price=1000             price range in cents, inclusive
+//	max_price=5000
+//	in_stock=true
+//	created_after=2025-01-01   RFC 3339 timestamp or date, inclusive
+//	created_before=2025-02-01  RFC 3339 timestamp or date, exclusive
+//	channel_id=<uuid>          published to the sales channel
+func ParseFilter(q url.Values) (Filter, error) {
+	var f Filter
+	var err error
+
+	if f.Statuses, err = parseList(q, "status", false); err != nil {
+		return Filter{}, err
+	}
+	if f.Tags, err = parseList(q, "tag", true); err != nil {
+		return Filter{}, err
+	}
+	if f.MinPriceCents, err = parseCents(q, "min_price"); err != nil {
+		return Filter{}, err
+	}
+	if f.MaxPriceCents, err = parseCents(q, "max_price"); err != nil {
+		return Filter{}, err
+	}
+	if f.MinPriceCents != nil && f.MaxPriceCents != nil && *f.MinPriceCents > *f.MaxPriceCents {
+		return Filter{}, fmt.Errorf("%w: min_price must not exceed max_price", ErrInvalidFilter)
+	}
+
+	if s := q.Get("in_stock"); s != "" {
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return Filter{}, fmt.Errorf("%w: in_stock must be true or false", ErrInvalidFilter)
+		}
+		f.InStock = &v
+	}
+
+	if f.CreatedAfter, err = parseTime(q, "created_after"); err != nil {
+		return Filter{}, err
+	}
+	if f.CreatedBefore, err = parseTime(q, "created_before"); err != nil {
+		return Filter{}, err
+	}
+
+	if s := q.Get("channel_id"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return Filter{}, fmt.Errorf("%w: channel_id must be a UUID", ErrInvalidFilter)
+		}
+		f.ChannelID = &id
+	}
+	return f, nil
+}
+
+func parseList(q url.Values, key string, lower bool) ([]string, error) {
+	var out []string
+	seen := map[string]bool{}
+	for _, raw := range q[key] {
+		for _, v := range strings.Split(raw, ",") {
+			v = strings.TrimSpace(v)
+			if lower {
+				v = strings.ToLower(v)
+			}
+			if v == "" || seen[v] {
+				continue
+			}
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	if len(out) > MaxFilterValues {
+		return nil, fmt.Errorf("%w: at most %d %s values are allowed", ErrInvalidFilter, MaxFilterValues, key)
+	}
+	return out, nil
+}
+
+func parseCents(q url.Values, key string) (*int64, error) {
+	s := q.Get(key)
+	if s == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || v < 0 {
+		return nil, fmt.Errorf("%w: %s must be a non-negative amount in cents", ErrInvalidFilter, key)
+	}
+	return &v, nil
+}
+
+func parseTime(q url.Values, key string) (*time.Time, error) {
+	s := q.Get(key)
+	if s == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s must be an RFC 3339 timestamp or a YYYY-MM-DD date", ErrInvalidFilter, key)
+}
@@ -0,0 +1,106 @@
+package catalog
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		check   func(t *testing.T, f Filter)
+		wantErr bool
+	}{
+		{
+			name:  "empty",
+			query: "",
+			check: func(t *testing.T, f Filter) {
+				if !f.IsZero() {
+					t.Errorf("filter = %+v, want zero", f)
+				}
+			},
+		},
+		{
+			name:  "lists are split, trimmed and deduplicated",
+			query: "status=active,%20draft&tag=Summer&tag=linen,summer",
+			check: func(t *testing.T, f Filter) {
+				if !reflect.DeepEqual(f.Statuses, []string{"active", "draft"}) {
+					t.Errorf("statuses = %v", f.Statuses)
+				}
+				if !reflect.DeepEqual(f.Tags, []string{"summer", "linen"}) {
+					t.Errorf("tags = %v", f.Tags)
+				}
+			},
+		},
+		{
+			name:  "price, stock and dates",
+			query: "min_price=1000&max_price=5000&in_stock=false&created_after=2025-01-01&created_before=2025-02-01T12:00:00Z",
+			check: func(t *testing.T, f Filter) {
+				if *f.MinPriceCents != 1000 || *f.MaxPriceCents != 5000 {
+					t.Errorf("price range = %d-%d", *f.MinPriceCents, *f.MaxPriceCents)
+				}
+				if *f.InStock {
+					t.Error("in_stock = true, want false")
+				}
+				if !f.CreatedAfter.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+					t.Errorf("created_after = %v", f.CreatedAfter)
+				}
+				if !f.CreatedBefore.Equal(time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)) {
+					t.Errorf("created_before = %v", f.CreatedBefore)
+				}
+			},
+		},
+		{
+			name:  "channel",
+			query: "channel_id=7f1d9a52-3c4e-4b8a-9e61-2d5f0c8b1a34",
+			check: func(t *testing.T, f Filter) {
+				if f.ChannelID == nil || f.ChannelID.String() != "7f1d9a52-3c4e-4b8a-9e61-2d5f0c8b1a34" {
+					t.Errorf("channel_id = %v", f.ChannelID)
+				}
+				if f.IsZero() {
+					t.Error("IsZero() = true with a channel")
+				}
+			},
+		},
+		{name: "inverted price range", query: "min_price=500&max_price=100", wantErr: true},
+		{name: "negative price", query: "min_price=-1", wantErr: true},
+		{name: "bad boolean", query: "in_stock=maybe", wantErr: true},
+		{name: "bad date", query: "created_after=yesterday", wantErr: true},
+		{name: "bad channel", query: "channel_id=online", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f, err := ParseFilter(q)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFilter(%q) error = %v, wantErr %v", tt.query, err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrInvalidFilter) {
+					t.Errorf("error = %v, want ErrInvalidFilter", err)
+				}
+				return
+			}
+			tt.check(t, f)
+		})
+	}
+}
+
+func TestChannelKindIsValid(t *testing.T) {
+	for _, k := range []ChannelKind{ChannelOnlineStore, ChannelPOS, ChannelMarketplaceFeed} {
+		if !k.IsValid() {
+			t.Errorf("%q.IsValid() = false", k)
+		}
+	}
+	if ChannelKind("wholesale").IsValid() {
+		t.Error(`"wholesale".IsValid() = true`)
+	}
+}
@@ -0,0 +1,186 @@
+// Package certs provisions TLS certificates for verified custom domains over
+// ACME and keeps them, sealed, in the database so every API instance serves
+// the same certificates.
+package certs
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"database/sql"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/crypto"
+	"github.com/dfodeker/terminus/internal/database"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// SSLStatusActive is recorded on a custom domain once its certificate is issued
+const SSLStatusActive = "active"
+
+// RenewBefore is how long before expiry a certificate is renewed
+const RenewBefore = 30 * 24 * time.Hour
+
+// Queries is the slice of the database the package uses
+type Queries interface {
+	GetACMECacheEntry(ctx context.Context, key string) (string, error)
+	PutACMECacheEntry(ctx context.Context, arg database.PutACMECacheEntryParams) error
+	DeleteACMECacheEntry(ctx context.Context, key string) error
+	GetVerifiedCustomDomainByDomain(ctx context.Context, domain string) (database.CustomDomain, error)
+	UpdateCustomDomainSSLStatus(ctx context.Context, arg database.UpdateCustomDomainSSLStatusParams) (database.CustomDomain, error)
+}
+
+// Config configures the ACME client
+type Config struct {
+	// Email is given to the CA for expiry notices. Optional.
+	Email string
+	// DirectoryURL is the CA's directory. Empty means Let's Encrypt.
+	DirectoryURL string
+}
+
+// NewManager returns an autocert manager that issues certificates for
+// verified custom domains only. Its TLSConfig answers TLS-ALPN-01 challenges
+// and picks certificates by SNI; its HTTPHandler answers HTTP-01 challenges.
+// Certificates are renewed in the background while the server runs, and
+// cached sealed under keyring's active data key.
+func NewManager(q Queries, keyring *crypto.Keyring, cfg Config) *autocert.Manager {
+	directoryURL := cfg.DirectoryURL
+	if directoryURL == "" {
+		directoryURL = autocert.DefaultACMEDirectory
+	}
+	return &autocert.Manager{
+		Prompt:      autocert.AcceptTOS,
+		Cache:       NewCache(q, keyring),
+		HostPolicy:  HostPolicy(q),
+		RenewBefore: RenewBefore,
+		Email:       cfg.Email,
+		Client:      &acme.Client{DirectoryURL: directoryURL},
+	}
+}
+
+// HostPolicy only lets certificates be requested for verified custom
+// domains, so arbitrary SNI names can't make us spend CA rate limits
+func HostPolicy(q Queries) autocert.HostPolicy {
+	return func(ctx context.Context, host string) error {
+		_, err := q.GetVerifiedCustomDomainByDomain(ctx, strings.ToLower(host))
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("certs: %q is not a verified custom domain", host)
+		}
+		return err
+	}
+}
+
+// Cache is an autocert.Cache backed by the acme_cache table. Entries are
+// sealed with the keyring, as other secrets are, along with their cache key
+// so an entry can't be moved to another key.
+type Cache struct {
+	q       Queries
+	keyring *crypto.Keyring
+}
+
+func NewCache(q Queries, keyring *crypto.Keyring) *Cache {
+	return &Cache{q: q, keyring: keyring}
+}
+
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+	sealed, err := c.q.GetACMECacheEntry(ctx, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, autocert.ErrCacheMiss
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c.open(key, sealed)
+}
+
+// Put stores an entry. When the entry is a domain's certificate, the custom
+// domain's SSL status and expiry are updated to match.
+func (c *Cache) Put(ctx context.Context, key string, data []byte) error {
+	sealed, err := c.seal(key, data)
+	if err != nil {
+		return err
+	}
+	if err := c.q.PutACMECacheEntry(ctx, database.PutACMECacheEntryParams{Key: key, Data: sealed}); err != nil {
+		return err
+	}
+
+	domain, ok := certDomain(key)
+	if !ok {
+		return nil
+	}
+	expiresAt, ok := leafExpiry(data)
+	if !ok {
+		return nil
+	}
+	cd, err := c.q.GetVerifiedCustomDomainByDomain(ctx, domain)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = c.q.UpdateCustomDomainSSLStatus(ctx, database.UpdateCustomDomainSSLStatusParams{
+		ID:           cd.ID,
+		SslStatus:    SSLStatusActive,
+		SslExpiresAt: sql.NullTime{Time: expiresAt, Valid: true},
+	})
+	return err
+}
+
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	return c.q.DeleteACMECacheEntry(ctx, key)
+}
+
+// seal seals data behind its cache key and a NUL, which no key contains
+func (c *Cache) seal(key string, data []byte) (string, error) {
+	return c.keyring.Seal(append([]byte(key+"\x00"), data...))
+}
+
+func (c *Cache) open(key string, sealed string) ([]byte, error) {
+	plaintext, err := c.keyring.Open(sealed)
+	if err != nil {
+		return nil, fmt.Errorf("certs: cache entry %q: %w", key, err)
+	}
+	data, ok := bytes.CutPrefix(plaintext, []byte(key+"\x00"))
+	if !ok {
+		return nil, fmt.Errorf("certs: cache entry %q was sealed for another key", key)
+	}
+	return data, nil
+}
+
+// certDomain reports the domain a cache key holds a certificate for.
+// autocert keys certificates by domain, with a +rsa suffix for the RSA
+// fallback; every other entry (account key, challenge tokens) has some
+// other + suffix.
+func certDomain(key string) (string, bool) {
+	domain := strings.TrimSuffix(key, "+rsa")
+	if domain == "" || strings.Contains(domain, "+") {
+		return "", false
+	}
+	return domain, true
+}
+
+// leafExpiry finds the first certificate in an autocert entry, which holds
+// the private key followed by the chain, leaf first
+func leafExpiry(data []byte) (time.Time, bool) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return time.Time{}, false
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return cert.NotAfter, true
+	}
+}
@@ -0,0 +1,196 @@
+package collections
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Kind distinguishes hand-curated collections from rule-based ones
+type Kind string
+
+const (
+	KindManual    Kind = "manual"
+	KindAutomated Kind = "automated"
+)
+
+// IsValid returns true if the kind is known
+func (k Kind) IsValid() bool {
+	return k == KindManual || k == KindAutomated
+}
+
+// Field is the product attribute a rule tests
+type Field string
+
+const (
+	FieldTag    Field = "tag"
+	FieldPrice  Field = "price"
+	FieldStatus Field = "status"
+)
+
+// Relation is the comparison a rule applies
+type Relation string
+
+const (
+	RelationEquals      Relation = "equals"
+	RelationNotEquals   Relation = "not_equals"
+	RelationGreaterThan Relation = "greater_than"
+	RelationLessThan    Relation = "less_than"
+)
+
+// MaxRules caps the number of rules on an automated collection
+const MaxRules = 20
+
+// Rule is a single condition of an automated collection. Price values are
+// in cents and compare against the product's lowest active variant price.
+type Rule struct {
+	Field    Field    `json:"field"`
+	Relation Relation `json:"relation"`
+	Value    string   `json:"value"`
+}
+
+// Product holds the attributes rules are evaluated against
+type Product struct {
+	Tags   []string
+	Status string
+	// MinPriceCents is nil when the product has no active variants
+	MinPriceCents *int64
+}
+
+// ValidateRules checks an automated collection's rule set
+func ValidateRules(rules []Rule) error {
+	if len(rules) == 0 {
+		return errors.New("automated collections need at least one rule")
+	}
+	if len(rules) > MaxRules {
+		return fmt.Errorf("at most %d rules are allowed", MaxRules)
+	}
+	for i, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("rule %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (r Rule) validate() error {
+	if strings.TrimSpace(r.Value) == "" {
+		return errors.New("value is required")
+	}
+	switch r.Field {
+	case FieldTag, FieldStatus:
+		if r.Relation != RelationEquals && r.Relation != RelationNotEquals {
+			return fmt.Errorf("relation %q is not supported for %s", r.Relation, r.Field)
+		}
+	case FieldPrice:
+		switch r.Relation {
+		case RelationEquals, RelationNotEquals, RelationGreaterThan, RelationLessThan:
+		default:
+			return fmt.Errorf("unknown relation %q", r.Relation)
+		}
+		if _, err := strconv.ParseInt(r.Value, 10, 64); err != nil {
+			return errors.New("price value must be a whole number of cents")
+		}
+	default:
+		return fmt.Errorf("unknown field %q", r.Field)
+	}
+	return nil
+}
+
+// Matches reports whether the product satisfies the rules: all of them, or
+// any of them when matchAny is set. Rules are assumed to be validated.
+func Matches(rules []Rule, matchAny bool, p Product) bool {
+	if len(rules) == 0 {
+		return false
+	}
+	for _, rule := range rules {
+		ok := rule.matches(p)
+		if matchAny && ok {
+			return true
+		}
+		if !matchAny && !ok {
+			return false
+		}
+	}
+	return !matchAny
+}
+
+func (r Rule) matches(p Product) bool {
+	switch r.Field {
+	case FieldTag:
+		has := false
+		for _, tag := range p.Tags {
+			if strings.EqualFold(tag, strings.TrimSpace(r.Value)) {
+				has = true
+				break
+			}
+		}
+		return has == (r.Relation == RelationEquals)
+	case FieldStatus:
+		same := strings.EqualFold(p.Status, strings.TrimSpace(r.Value))
+		return same == (r.Relation == RelationEquals)
+	case FieldPrice:
+		if p.MinPriceCents == nil {
+			return false
+		}
+		want, err := strconv.ParseInt(r.Value, 10, 64)
+		if err != nil {
+			return false
+		}
+		price := *p.MinPriceCents
+		switch r.Relation {
+		case RelationEquals:
+			return price == want
+		case RelationNotEquals:
+			return price != want
+		case RelationGreaterThan:
+			return price > want
+		case RelationLessThan:
+			return price < want
+		}
+	}
+	return false
+}
+
+// ParseTags splits the comma-separated tags stored on products
+func ParseTags(tags string) []string {
+	var out []string
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+// Reorder moves the given products to the front of current, in the order
+// given, keeping the relative order of everything else. Every moved product
+// must already be in current and appear only once.
+func Reorder(current, moved []uuid.UUID) ([]uuid.UUID, error) {
+	members := make(map[uuid.UUID]bool, len(current))
+	for _, id := range current {
+		members[id] = true
+	}
+
+	seen := make(map[uuid.UUID]bool, len(moved))
+	out := make([]uuid.UUID, 0, len(current))
+	for _, id := range moved {
+		if !members[id] {
+			return nil, fmt.Errorf("product %s is not in the collection", id)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("product %s is listed more than once", id)
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	for _, id := range current {
+		if !seen[id] {
+			out = append(out, id)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,115 @@
+package collections
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestValidateRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []Rule
+		wantErr bool
+	}{
+		{"tag equals", []Rule{{FieldTag, RelationEquals, "summer"}}, false},
+		{"price greater than", []Rule{{FieldPrice, RelationGreaterThan, "1000"}}, false},
+		{"status not equals", []Rule{{FieldStatus, RelationNotEquals, "archived"}}, false},
+		{"empty", nil, true},
+		{"unknown field", []Rule{{Field("vendor"), RelationEquals, "acme"}}, true},
+		{"tag greater than", []Rule{{FieldTag, RelationGreaterThan, "a"}}, true},
+		{"price not a number", []Rule{{FieldPrice, RelationLessThan, "ten"}}, true},
+		{"blank value", []Rule{{FieldTag, RelationEquals, " "}}, true},
+		{"unknown relation", []Rule{{FieldPrice, Relation("between"), "1"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRules(tt.rules)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	price := int64(2500)
+	shirt := Product{Tags: []string{"Summer", "shirts"}, Status: "active", MinPriceCents: &price}
+	noVariants := Product{Tags: []string{"summer"}, Status: "draft"}
+
+	tests := []struct {
+		name     string
+		rules    []Rule
+		matchAny bool
+		product  Product
+		want     bool
+	}{
+		{"tag case-insensitive", []Rule{{FieldTag, RelationEquals, "summer"}}, false, shirt, true},
+		{"tag missing", []Rule{{FieldTag, RelationEquals, "winter"}}, false, shirt, false},
+		{"tag not equals", []Rule{{FieldTag, RelationNotEquals, "winter"}}, false, shirt, true},
+		{"price above", []Rule{{FieldPrice, RelationGreaterThan, "2000"}}, false, shirt, true},
+		{"price below", []Rule{{FieldPrice, RelationLessThan, "2000"}}, false, shirt, false},
+		{"no price never matches", []Rule{{FieldPrice, RelationNotEquals, "1"}}, false, noVariants, false},
+		{"all must match", []Rule{
+			{FieldTag, RelationEquals, "summer"},
+			{FieldStatus, RelationEquals, "draft"},
+		}, false, shirt, false},
+		{"any may match", []Rule{
+			{FieldTag, RelationEquals, "winter"},
+			{FieldStatus, RelationEquals, "active"},
+		}, true, shirt, true},
+		{"any with none matching", []Rule{
+			{FieldTag, RelationEquals, "winter"},
+			{FieldStatus, RelationEquals, "draft"},
+		}, true, shirt, false},
+		{"no rules", nil, false, shirt, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Matches(tt.rules, tt.matchAny, tt.product); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	got := ParseTags(" summer, shirts ,,sale")
+	want := []string{"summer", "shirts", "sale"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseTags() = %v, want %v", got, want)
+	}
+}
+
+func TestReorder(t *testing.T) {
+	a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	current := []uuid.UUID{a, b, c, d}
+
+	tests := []struct {
+		name    string
+		moved   []uuid.UUID
+		want    []uuid.UUID
+		wantErr bool
+	}{
+		{"full order", []uuid.UUID{d, c, b, a}, []uuid.UUID{d, c, b, a}, false},
+		{"partial order", []uuid.UUID{c}, []uuid.UUID{c, a, b, d}, false},
+		{"empty", nil, []uuid.UUID{a, b, c, d}, false},
+		{"not a member", []uuid.UUID{uuid.New()}, nil, true},
+		{"duplicate", []uuid.UUID{b, b}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Reorder(current, tt.moved)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reorder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Reorder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,800 @@
+// Package config loads and validates the settings of the api and worker
+// binaries. Every problem is collected before failing, so a misconfigured
+// deploy can be fixed in one pass instead of one variable per restart.
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/audit"
+	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/billing"
+	"github.com/dfodeker/terminus/internal/captcha"
+	"github.com/dfodeker/terminus/internal/certs"
+	"github.com/dfodeker/terminus/internal/crypto"
+	"github.com/dfodeker/terminus/internal/dbpool"
+	"github.com/dfodeker/terminus/internal/fx"
+	"github.com/dfodeker/terminus/internal/inventory"
+	"github.com/dfodeker/terminus/internal/jobs"
+	"github.com/dfodeker/terminus/internal/labels"
+	"github.com/dfodeker/terminus/internal/mailer"
+	"github.com/dfodeker/terminus/internal/media"
+	"github.com/dfodeker/terminus/internal/metrics"
+	"github.com/dfodeker/terminus/internal/notifications"
+	"github.com/dfodeker/terminus/internal/oauth"
+	"github.com/dfodeker/terminus/internal/ratelimit"
+	"github.com/dfodeker/terminus/internal/search"
+	"github.com/dfodeker/terminus/internal/segments"
+	"github.com/dfodeker/terminus/internal/service/entitlements"
+	"github.com/dfodeker/terminus/internal/service/products"
+	"github.com/dfodeker/terminus/internal/service/stores"
+	"github.com/dfodeker/terminus/internal/storage"
+	"github.com/dfodeker/terminus/internal/subscriptions"
+	"github.com/dfodeker/terminus/internal/tracing"
+	"github.com/dfodeker/terminus/internal/usage"
+	"github.com/joho/godotenv"
+)
+
+// Lookup returns the value of a setting and whether it is set.
+// os.LookupEnv is one.
+type Lookup func(key string) (string, bool)
+
+// Config holds every setting. LoadAPI and LoadWorker fill the sections
+// their binary uses.
+type Config struct {
+	// Platform is dev for local development, which switches to
+	// human-readable request logs
+	Platform    string
+	Port        string
+	DatabaseURL string
+	// DB sizes the pool of connections to DatabaseURL
+	DB dbpool.Config
+	// DatabaseReplicaURL is a read replica that listings and reports read
+	// from while it answers; empty sends every read to DatabaseURL. Its
+	// pool is sized as DB.
+	DatabaseReplicaURL string
+	// ReplicaCheckInterval is how often the replica is checked
+	ReplicaCheckInterval time.Duration
+	// ShutdownTimeout is how long in-flight requests and jobs get to finish
+	// after SIGTERM or SIGINT
+	ShutdownTimeout time.Duration
+	// MigrateOnStart applies pending migrations before the API serves.
+	// Without it they are applied with terminus migrate up, and the API
+	// reports itself not ready until they are.
+	MigrateOnStart bool
+	// RedisURL, if set, adds Redis to the readiness check
+	RedisURL string
+	// MachineID keeps GIDs from different instances apart, so each API
+	// and worker process needs its own
+	MachineID  uint16
+	BaseDomain string
+	// AppURL is the admin app origin used in emailed links
+	AppURL string
+	// APIURL is the origin the API is reached at, used in links handed to
+	// other services such as product feed URLs
+	APIURL    string
+	RateLimit RateLimit
+	JWTKeys   auth.KeyConfig
+	// TenantClaims embeds active tenant memberships in access tokens
+	TenantClaims bool
+	// TokenVersions stamps access tokens with the user's token version and
+	// checks it on every request, so signing out everywhere ends access
+	// tokens too instead of leaving them until they expire
+	TokenVersions bool
+	// ReservationTTL is how long a checkout reservation holds stock
+	ReservationTTL time.Duration
+	// StorefrontTokensRequired turns away storefront requests without a
+	// storefront access token
+	StorefrontTokensRequired bool
+	// Encryption configures the key encryption keys personal data and
+	// secrets are sealed under
+	Encryption crypto.Config
+	// Captcha, once configured, is asked of accounts whose sign-ins keep
+	// failing
+	Captcha captcha.Config
+	// OAuth lists the providers users can sign in through
+	OAuth   oauth.Config
+	TLS     TLS
+	Storage storage.Config
+	Search  search.Config
+	Mail    mailer.Config
+	Labels  labels.Config
+	Billing billing.Config
+	Tracing tracing.Config
+	Metrics metrics.Config
+	Worker  Worker
+}
+
+// RateLimit caps requests. Anonymous ones are limited per client IP and
+// endpoint by Requests and Window. Authenticated ones are limited per API
+// key, user and tenant by the quota of the best plan among their stores.
+type RateLimit struct {
+	Requests int
+	Window   time.Duration
+	Plans    ratelimit.Plans
+	// Store is memory, counting in each instance, or redis, sharing the
+	// counts between instances through REDIS_URL
+	Store string
+}
+
+// TLS configures the HTTPS listener for custom domains
+type TLS struct {
+	// ACME turns on the listener and certificate provisioning
+	ACME  bool
+	Port  string
+	Certs certs.Config
+}
+
+// Worker configures the background job runner
+type Worker struct {
+	Queue           string
+	Concurrency     int
+	ThumbnailWidths []int
+	// AuditRetention is how long audit log entries are kept
+	AuditRetention time.Duration
+	// NotificationRetention is how long staff notifications are kept
+	NotificationRetention time.Duration
+	// DeletedProductRetention is how long deleted products and variants
+	// can be restored before they are purged
+	DeletedProductRetention time.Duration
+	// DeletedStoreRetention is how long deleted stores can be restored
+	// before they are purged with everything in them
+	DeletedStoreRetention time.Duration
+	// SubscriptionRetryDelay is how long a declined subscription renewal
+	// waits before it is charged again
+	SubscriptionRetryDelay time.Duration
+	// SubscriptionMaxAttempts is how many declined renewals in a row stop a
+	// subscription billing until it is resumed
+	SubscriptionMaxAttempts int
+	// CustomerGroupRefreshInterval is how long the members of a rule
+	// customer group may go without a recalculation
+	CustomerGroupRefreshInterval time.Duration
+	// LowStockCheckInterval is how often stock levels are checked against
+	// their reorder points
+	LowStockCheckInterval time.Duration
+	// FX syncs the exchange rates presentment prices are converted with
+	FX fx.Config
+	// MetricsPort, if set, serves the worker's Prometheus metrics
+	MetricsPort string
+}
+
+// Error lists every problem found while loading
+type Error struct {
+	Problems []string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid configuration (%d problems):\n  %s", len(e.Problems), strings.Join(e.Problems, "\n  "))
+}
+
+// Env reads settings from the process environment. The env file named by
+// CONFIG_FILE is loaded first and must exist; without it .env is loaded if
+// present. Variables already in the environment win over either file.
+func Env() (Lookup, error) {
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := godotenv.Load(path); err != nil {
+			return nil, fmt.Errorf("load CONFIG_FILE: %w", err)
+		}
+	} else {
+		godotenv.Load()
+	}
+	return os.LookupEnv, nil
+}
+
+// LoadAPI loads the settings of the api binary
+func LoadAPI(lookup Lookup) (*Config, error) {
+	l := &loader{lookup: lookup}
+	cfg := &Config{
+		Platform:                 l.required("PLATFORM"),
+		Port:                     l.str("API_PORT", "8080"),
+		DatabaseURL:              l.required("DB_URL"),
+		DB:                       l.dbPool(),
+		DatabaseReplicaURL:       l.get("DB_REPLICA_URL"),
+		ReplicaCheckInterval:     l.duration("DB_REPLICA_CHECK_INTERVAL", dbpool.DefaultReplicaCheckInterval),
+		ShutdownTimeout:          l.duration("SHUTDOWN_TIMEOUT", 30*time.Second),
+		MigrateOnStart:           l.boolean("MIGRATE_ON_START", false),
+		RedisURL:                 l.get("REDIS_URL"),
+		MachineID:                l.machineID("MACHINE_ID"),
+		BaseDomain:               l.str("BASE_DOMAIN", "storeos.org"),
+		AppURL:                   strings.TrimSuffix(l.str("APP_URL", "http://localhost:3000"), "/"),
+		APIURL:                   strings.TrimSuffix(l.str("API_URL", "http://localhost:8080"), "/"),
+		RateLimit:                l.rateLimit(),
+		JWTKeys:                  l.jwtKeys(),
+		TenantClaims:             l.boolean("JWT_TENANT_CLAIMS", false),
+		TokenVersions:            l.boolean("JWT_TOKEN_VERSION", false),
+		StorefrontTokensRequired: l.boolean("STOREFRONT_TOKENS_REQUIRED", false),
+		ReservationTTL:           l.duration("INVENTORY_RESERVATION_TTL", inventory.DefaultReservationTTL),
+		Encryption:               l.encryption(),
+		Captcha:                  l.captcha(),
+		OAuth:                    l.oauth(),
+		TLS:                      l.tls(),
+		Storage:                  l.storage(),
+		Search:                   l.search(),
+		Labels:                   l.labels(),
+		Billing:                  l.billing(),
+		Tracing:                  l.tracing("terminus-api"),
+		Metrics:                  l.metrics(),
+	}
+	return cfg, l.err()
+}
+
+// LoadWorker loads the settings of the worker binary
+func LoadWorker(lookup Lookup) (*Config, error) {
+	l := &loader{lookup: lookup}
+	cfg := &Config{
+		DatabaseURL:     l.required("DB_URL"),
+		DB:              l.dbPool(),
+		ShutdownTimeout: l.duration("SHUTDOWN_TIMEOUT", 30*time.Second),
+		MachineID:       l.machineID("MACHINE_ID"),
+		Encryption:      l.encryption(),
+		Storage:         l.storage(),
+		Search:          l.search(),
+		Mail:            l.mail(),
+		Billing:         l.billing(),
+		Tracing:         l.tracing("terminus-worker"),
+		Metrics:         l.metrics(),
+		Worker: Worker{
+			Queue:                        l.str("WORKER_QUEUE", jobs.DefaultQueue),
+			Concurrency:                  l.positiveInt("WORKER_CONCURRENCY", 4),
+			ThumbnailWidths:              l.thumbnailWidths("MEDIA_THUMBNAIL_WIDTHS"),
+			AuditRetention:               l.duration("AUDIT_LOG_RETENTION", audit.DefaultRetention),
+			NotificationRetention:        l.duration("NOTIFICATION_RETENTION", notifications.DefaultRetention),
+			DeletedProductRetention:      l.duration("DELETED_PRODUCT_RETENTION", products.DefaultRetention),
+			DeletedStoreRetention:        l.duration("DELETED_STORE_RETENTION", stores.DefaultRetention),
+			SubscriptionRetryDelay:       l.duration("SUBSCRIPTION_RETRY_DELAY", subscriptions.DefaultRetryDelay),
+			SubscriptionMaxAttempts:      l.positiveInt("SUBSCRIPTION_MAX_ATTEMPTS", subscriptions.DefaultMaxAttempts),
+			CustomerGroupRefreshInterval: l.duration("CUSTOMER_GROUP_REFRESH_INTERVAL", segments.DefaultRefreshInterval),
+			LowStockCheckInterval:        l.duration("LOW_STOCK_CHECK_INTERVAL", inventory.DefaultLowStockCheckInterval),
+			FX:                           l.fx(),
+			MetricsPort:                  l.get("WORKER_METRICS_PORT"),
+		},
+	}
+	return cfg, l.err()
+}
+
+// loader reads settings and records what's wrong with them. Empty values
+// count as unset.
+type loader struct {
+	lookup   Lookup
+	problems []string
+}
+
+func (l *loader) err() error {
+	if len(l.problems) == 0 {
+		return nil
+	}
+	return &Error{Problems: l.problems}
+}
+
+func (l *loader) problem(format string, args ...any) {
+	l.problems = append(l.problems, fmt.Sprintf(format, args...))
+}
+
+func (l *loader) get(key string) string {
+	v, _ := l.lookup(key)
+	return v
+}
+
+func (l *loader) str(key, def string) string {
+	if v := l.get(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func (l *loader) required(key string) string {
+	v := l.get(key)
+	if v == "" {
+		l.problem("%s is required", key)
+	}
+	return v
+}
+
+// requiredFor checks the settings a driver needs, naming the driver
+func (l *loader) requiredFor(what string, keys ...string) {
+	for _, key := range keys {
+		if l.get(key) == "" {
+			l.problem("%s is required for %s", key, what)
+		}
+	}
+}
+
+func (l *loader) boolean(key string, def bool) bool {
+	v := l.get(key)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		l.problem("%s must be true or false, got %q", key, v)
+		return def
+	}
+	return b
+}
+
+func (l *loader) positiveInt(key string, def int) int {
+	v := l.get(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		l.problem("%s must be a positive integer, got %q", key, v)
+		return def
+	}
+	return n
+}
+
+func (l *loader) nonNegativeInt(key string, def int) int {
+	v := l.get(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		l.problem("%s must be a whole number, got %q", key, v)
+		return def
+	}
+	return n
+}
+
+func (l *loader) duration(key string, def time.Duration) time.Duration {
+	v := l.get(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		l.problem("%s must be a positive duration such as 1s, got %q", key, v)
+		return def
+	}
+	return d
+}
+
+func (l *loader) ratio(key string, def float64) float64 {
+	v := l.get(key)
+	if v == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < 0 || f > 1 {
+		l.problem("%s must be a number between 0 and 1, got %q", key, v)
+		return def
+	}
+	return f
+}
+
+func (l *loader) machineID(key string) uint16 {
+	v := l.get(key)
+	if v == "" {
+		return 0
+	}
+	id, err := strconv.ParseUint(v, 10, 16)
+	if err != nil || id > 1023 {
+		l.problem("%s must be between 0 and 1023, got %q", key, v)
+		return 0
+	}
+	return uint16(id)
+}
+
+func (l *loader) thumbnailWidths(key string) []int {
+	widths, err := media.ParseWidths(l.get(key))
+	if err != nil {
+		l.problem("%s: %s", key, err)
+	}
+	return widths
+}
+
+func (l *loader) jwtKeys() auth.KeyConfig {
+	cfg := auth.KeyConfig{
+		PrivateKeyFile: l.get("JWT_PRIVATE_KEY_FILE"),
+		SigningKey:     l.get("SIGNING_KEY"),
+	}
+	if cfg.PrivateKeyFile == "" && cfg.SigningKey == "" {
+		l.problem("JWT_PRIVATE_KEY_FILE or SIGNING_KEY is required")
+	}
+	for _, path := range strings.Split(l.get("JWT_RETIRED_KEY_FILES"), ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			cfg.RetiredKeyFiles = append(cfg.RetiredKeyFiles, path)
+		}
+	}
+	return cfg
+}
+
+// encryption reads the KEKs. With both a KMS key and ENCRYPTION_KEY set,
+// KMS wraps new keys and ENCRYPTION_KEY only unwraps old ones, which is how
+// a deploy moves from one to the other.
+func (l *loader) encryption() crypto.Config {
+	cfg := crypto.Config{
+		KMS: crypto.KMSConfig{
+			KeyID:           l.get("ENCRYPTION_KMS_KEY_ID"),
+			Region:          l.get("KMS_REGION"),
+			Endpoint:        l.get("KMS_ENDPOINT"),
+			AccessKeyID:     l.get("KMS_ACCESS_KEY_ID"),
+			SecretAccessKey: l.get("KMS_SECRET_ACCESS_KEY"),
+		},
+		Key: l.encryptionKey("ENCRYPTION_KEY", l.get("ENCRYPTION_KEY")),
+	}
+	for _, raw := range strings.Split(l.get("ENCRYPTION_RETIRED_KEYS"), ",") {
+		if raw = strings.TrimSpace(raw); raw != "" {
+			cfg.RetiredKeys = append(cfg.RetiredKeys, l.encryptionKey("ENCRYPTION_RETIRED_KEYS", raw))
+		}
+	}
+	for _, id := range strings.Split(l.get("ENCRYPTION_RETIRED_KMS_KEY_IDS"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			cfg.RetiredKMSKeyIDs = append(cfg.RetiredKMSKeyIDs, id)
+		}
+	}
+
+	switch {
+	case cfg.KMS.KeyID != "":
+		l.requiredFor("ENCRYPTION_KMS_KEY_ID", "KMS_REGION", "KMS_ACCESS_KEY_ID", "KMS_SECRET_ACCESS_KEY")
+	case cfg.Key == nil && l.get("ENCRYPTION_KEY") == "":
+		l.problem("ENCRYPTION_KEY or ENCRYPTION_KMS_KEY_ID is required")
+	}
+	return cfg
+}
+
+func (l *loader) encryptionKey(key, raw string) []byte {
+	if raw == "" {
+		return nil
+	}
+	b, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil || len(b) != crypto.KeySize {
+		l.problem("%s must be %d bytes, base64 encoded", key, crypto.KeySize)
+		return nil
+	}
+	return b
+}
+
+func (l *loader) captcha() captcha.Config {
+	cfg := captcha.Config{
+		VerifyURL: l.get("CAPTCHA_VERIFY_URL"),
+		Secret:    l.get("CAPTCHA_SECRET"),
+		After:     l.positiveInt("LOGIN_CAPTCHA_AFTER", captcha.DefaultAfter),
+	}
+	if cfg.Secret == "" {
+		return cfg
+	}
+	l.requiredFor("CAPTCHA_SECRET", "CAPTCHA_VERIFY_URL")
+	if cfg.VerifyURL != "" {
+		if u, err := url.Parse(cfg.VerifyURL); err != nil || u.Scheme != "https" || u.Host == "" {
+			l.problem("CAPTCHA_VERIFY_URL must be an https URL, got %q", cfg.VerifyURL)
+		}
+	}
+	return cfg
+}
+
+// oauth reads the client of each sign-in provider. Each needs its secret,
+// and any of them needs the page providers send users back to.
+func (l *loader) oauth() oauth.Config {
+	cfg := oauth.Config{
+		RedirectURL: l.get("OAUTH_REDIRECT_URL"),
+		Google: oauth.ClientConfig{
+			ClientID:     l.get("GOOGLE_CLIENT_ID"),
+			ClientSecret: l.get("GOOGLE_CLIENT_SECRET"),
+		},
+		GitHub: oauth.ClientConfig{
+			ClientID:     l.get("GITHUB_CLIENT_ID"),
+			ClientSecret: l.get("GITHUB_CLIENT_SECRET"),
+		},
+		OIDC: oauth.OIDCConfig{
+			Name:   l.str("OIDC_PROVIDER_NAME", "oidc"),
+			Issuer: l.get("OIDC_ISSUER"),
+			ClientConfig: oauth.ClientConfig{
+				ClientID:     l.get("OIDC_CLIENT_ID"),
+				ClientSecret: l.get("OIDC_CLIENT_SECRET"),
+			},
+		},
+	}
+	if cfg.Google.ClientID != "" {
+		l.requiredFor("GOOGLE_CLIENT_ID", "GOOGLE_CLIENT_SECRET", "OAUTH_REDIRECT_URL")
+	}
+	if cfg.GitHub.ClientID != "" {
+		l.requiredFor("GITHUB_CLIENT_ID", "GITHUB_CLIENT_SECRET", "OAUTH_REDIRECT_URL")
+	}
+	if cfg.OIDC.ClientID != "" {
+		l.requiredFor("OIDC_CLIENT_ID", "OIDC_CLIENT_SECRET", "OIDC_ISSUER", "OAUTH_REDIRECT_URL")
+		// callback shares the path of the provider names
+		if cfg.OIDC.Name == oauth.ProviderGoogle || cfg.OIDC.Name == oauth.ProviderGitHub || cfg.OIDC.Name == "callback" {
+			l.problem("OIDC_PROVIDER_NAME can't be %q, which is taken", cfg.OIDC.Name)
+		}
+		if u, err := url.Parse(cfg.OIDC.Issuer); cfg.OIDC.Issuer != "" && (err != nil || u.Scheme != "https" || u.Host == "") {
+			l.problem("OIDC_ISSUER must be an https URL, got %q", cfg.OIDC.Issuer)
+		}
+	}
+	if cfg.RedirectURL != "" {
+		if u, err := url.Parse(cfg.RedirectURL); err != nil || !u.IsAbs() {
+			l.problem("OAUTH_REDIRECT_URL must be an absolute URL, got %q", cfg.RedirectURL)
+		}
+	}
+	return cfg
+}
+
+func (l *loader) tls() TLS {
+	cfg := TLS{
+		ACME: l.boolean("TLS_ACME", false),
+		Port: l.str("TLS_PORT", "443"),
+		Certs: certs.Config{
+			Email:        l.get("ACME_EMAIL"),
+			DirectoryURL: l.get("ACME_DIRECTORY_URL"),
+		},
+	}
+	return cfg
+}
+
+func (l *loader) storage() storage.Config {
+	publicURL := l.get("STORAGE_PUBLIC_URL")
+	cfg := storage.Config{Driver: l.get("STORAGE_DRIVER")}
+
+	switch cfg.Driver {
+	case "", "local":
+		cfg.LocalDir = l.str("STORAGE_LOCAL_DIR", "./uploads")
+		cfg.LocalPublicURL = publicURL
+		if cfg.LocalPublicURL == "" {
+			cfg.LocalPublicURL = "http://localhost:8080/media"
+		}
+		cfg.LocalSecret = l.str("STORAGE_LOCAL_SECRET", l.get("SIGNING_KEY"))
+		if cfg.LocalSecret == "" {
+			l.problem("STORAGE_LOCAL_SECRET or SIGNING_KEY is required for local storage")
+		}
+	case "s3":
+		l.requiredFor("STORAGE_DRIVER=s3", "S3_BUCKET", "S3_REGION", "S3_ACCESS_KEY_ID", "S3_SECRET_ACCESS_KEY")
+		cfg.S3 = storage.S3Config{
+			Bucket:          l.get("S3_BUCKET"),
+			Region:          l.get("S3_REGION"),
+			Endpoint:        l.get("S3_ENDPOINT"),
+			AccessKeyID:     l.get("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: l.get("S3_SECRET_ACCESS_KEY"),
+			PathStyle:       l.boolean("S3_PATH_STYLE", false),
+			PublicURL:       publicURL,
+		}
+	case "gcs":
+		l.requiredFor("STORAGE_DRIVER=gcs", "GCS_BUCKET", "GCS_HMAC_KEY_ID", "GCS_HMAC_SECRET")
+		cfg.GCS = storage.GCSConfig{
+			Bucket:     l.get("GCS_BUCKET"),
+			HMACKeyID:  l.get("GCS_HMAC_KEY_ID"),
+			HMACSecret: l.get("GCS_HMAC_SECRET"),
+			PublicURL:  publicURL,
+		}
+	default:
+		l.problem("STORAGE_DRIVER must be local, s3 or gcs, got %q", cfg.Driver)
+	}
+	return cfg
+}
+
+func (l *loader) search() search.Config {
+	cfg := search.Config{
+		Engine: l.get("SEARCH_ENGINE"),
+		Index:  l.str("SEARCH_INDEX", search.DefaultIndex),
+	}
+
+	switch cfg.Engine {
+	case "", "postgres":
+	case "meilisearch":
+		l.requiredFor("SEARCH_ENGINE=meilisearch", "MEILISEARCH_URL")
+		cfg.Meilisearch = search.MeilisearchConfig{
+			URL:    l.get("MEILISEARCH_URL"),
+			APIKey: l.get("MEILISEARCH_API_KEY"),
+		}
+	case "elasticsearch":
+		l.requiredFor("SEARCH_ENGINE=elasticsearch", "ELASTICSEARCH_URL")
+		cfg.Elasticsearch = search.ElasticsearchConfig{
+			URL:      l.get("ELASTICSEARCH_URL"),
+			APIKey:   l.get("ELASTICSEARCH_API_KEY"),
+			Username: l.get("ELASTICSEARCH_USERNAME"),
+			Password: l.get("ELASTICSEARCH_PASSWORD"),
+		}
+	default:
+		l.problem("SEARCH_ENGINE must be postgres, meilisearch or elasticsearch, got %q", cfg.Engine)
+	}
+	return cfg
+}
+
+func (l *loader) fx() fx.Config {
+	cfg := fx.Config{
+		RatesURL: l.get("FX_RATES_URL"),
+		Base:     l.str("FX_BASE_CURRENCY", fx.DefaultBase),
+		Interval: l.duration("FX_SYNC_INTERVAL", fx.DefaultInterval),
+	}
+	if !fx.ValidCode(cfg.Base) {
+		l.problem("FX_BASE_CURRENCY must be a three letter ISO 4217 code such as USD, got %q", cfg.Base)
+	}
+	if cfg.RatesURL != "" {
+		if u, err := url.Parse(cfg.RatesURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			l.problem("FX_RATES_URL must be an http or https URL, got %q", cfg.RatesURL)
+		}
+	}
+	return cfg
+}
+
+// labels reads the credentials of each shipping label provider. A
+// provider's webhook secret is required with its key, so the tracking
+// updates it sends can be trusted.
+func (l *loader) labels() labels.Config {
+	cfg := labels.Config{
+		EasyPost: labels.EasyPostConfig{
+			APIKey:        l.get("EASYPOST_API_KEY"),
+			WebhookSecret: l.get("EASYPOST_WEBHOOK_SECRET"),
+		},
+		Shippo: labels.ShippoConfig{
+			APIToken:     l.get("SHIPPO_API_TOKEN"),
+			WebhookToken: l.get("SHIPPO_WEBHOOK_TOKEN"),
+		},
+	}
+	if cfg.EasyPost.APIKey != "" {
+		l.requiredFor("EASYPOST_API_KEY", "EASYPOST_WEBHOOK_SECRET")
+	}
+	if cfg.Shippo.APIToken != "" {
+		l.requiredFor("SHIPPO_API_TOKEN", "SHIPPO_WEBHOOK_TOKEN")
+	}
+	return cfg
+}
+
+// billing reads the Stripe credentials, the price of each paid plan and
+// the overage price of each usage metric. The webhook secret and plan
+// prices are required with the key, as plans can't be sold or kept in step
+// without them.
+func (l *loader) billing() billing.Config {
+	cfg := billing.Config{
+		SecretKey:     l.get("STRIPE_SECRET_KEY"),
+		WebhookSecret: l.get("STRIPE_WEBHOOK_SECRET"),
+		GracePeriod:   l.duration("BILLING_GRACE_PERIOD", billing.DefaultGracePeriod),
+	}
+	if cfg.SecretKey == "" {
+		return cfg
+	}
+	l.requiredFor("STRIPE_SECRET_KEY", "STRIPE_WEBHOOK_SECRET", "STRIPE_PRICES")
+	prices, err := billing.ParsePrices(l.get("STRIPE_PRICES"))
+	if err != nil {
+		l.problem("STRIPE_PRICES: %s", err)
+	}
+	for plan := range prices {
+		if !slices.Contains(entitlements.Plans, plan) {
+			l.problem("STRIPE_PRICES: unknown plan %q, plans are %s", plan, strings.Join(entitlements.Plans, ", "))
+		}
+	}
+	cfg.Prices = prices
+
+	overage, err := billing.ParsePrices(l.get("STRIPE_OVERAGE_PRICES"))
+	if err != nil {
+		l.problem("STRIPE_OVERAGE_PRICES: %s", err)
+	}
+	for metric := range overage {
+		if !usage.Metric(metric).Valid() {
+			l.problem("STRIPE_OVERAGE_PRICES: unknown metric %q, metrics are %s", metric, metricNames())
+		}
+	}
+	cfg.OveragePrices = overage
+	return cfg
+}
+
+func metricNames() string {
+	names := make([]string, len(usage.Metrics))
+	for i, m := range usage.Metrics {
+		names[i] = string(m)
+	}
+	return strings.Join(names, ", ")
+}
+
+func (l *loader) mail() mailer.Config {
+	cfg := mailer.Config{
+		Driver: l.get("MAIL_DRIVER"),
+		From:   l.str("MAIL_FROM", mailer.DefaultFrom),
+	}
+
+	switch cfg.Driver {
+	case "", "log":
+	case "smtp":
+		l.requiredFor("MAIL_DRIVER=smtp", "SMTP_HOST")
+		cfg.SMTP = mailer.SMTPConfig{
+			Host:     l.get("SMTP_HOST"),
+			Port:     l.positiveInt("SMTP_PORT", 587),
+			Username: l.get("SMTP_USERNAME"),
+			Password: l.get("SMTP_PASSWORD"),
+		}
+	case "ses":
+		l.requiredFor("MAIL_DRIVER=ses", "SES_REGION", "SES_SMTP_USERNAME", "SES_SMTP_PASSWORD")
+		cfg.SES = mailer.SESConfig{
+			Region:   l.get("SES_REGION"),
+			Username: l.get("SES_SMTP_USERNAME"),
+			Password: l.get("SES_SMTP_PASSWORD"),
+		}
+	case "sendgrid":
+		l.requiredFor("MAIL_DRIVER=sendgrid", "SENDGRID_API_KEY")
+		cfg.SendGrid = mailer.SendGridConfig{APIKey: l.get("SENDGRID_API_KEY")}
+	case "postmark":
+		l.requiredFor("MAIL_DRIVER=postmark", "POSTMARK_SERVER_TOKEN")
+		cfg.Postmark = mailer.PostmarkConfig{
+			ServerToken:   l.get("POSTMARK_SERVER_TOKEN"),
+			MessageStream: l.get("POSTMARK_MESSAGE_STREAM"),
+		}
+	default:
+		l.problem("MAIL_DRIVER must be log, smtp, ses, sendgrid or postmark, got %q", cfg.Driver)
+	}
+	return cfg
+}
+
+// rateLimit reads the anonymous limit, the quota of each plan and where
+// the counts are kept
+func (l *loader) rateLimit() RateLimit {
+	cfg := RateLimit{
+		Requests: l.positiveInt("RATE_LIMIT_REQUESTS", 5),
+		Window:   l.duration("RATE_LIMIT_WINDOW", time.Second),
+		Store:    l.str("RATE_LIMIT_STORE", "memory"),
+	}
+	plans, err := ratelimit.ParsePlans(l.str("RATE_LIMIT_PLANS", ratelimit.DefaultPlans))
+	if err != nil {
+		l.problem("RATE_LIMIT_PLANS: %s", err)
+	}
+	cfg.Plans = plans
+
+	switch cfg.Store {
+	case "memory":
+	case "redis":
+		l.requiredFor("RATE_LIMIT_STORE=redis", "REDIS_URL")
+	default:
+		l.problem("RATE_LIMIT_STORE must be memory or redis, got %q", cfg.Store)
+	}
+	return cfg
+}
+
+func (l *loader) metrics() metrics.Config {
+	return metrics.Config{
+		MaxStoreLabels: l.positiveInt("METRICS_MAX_STORES", metrics.DefaultMaxStoreLabels),
+	}
+}
+
+// dbPool sizes a binary's pool of database connections
+func (l *loader) dbPool() dbpool.Config {
+	cfg := dbpool.Config{
+		MaxConns:          l.positiveInt("DB_MAX_CONNS", dbpool.DefaultMaxConns),
+		MaxConnLifetime:   l.duration("DB_MAX_CONN_LIFETIME", dbpool.DefaultMaxConnLifetime),
+		MaxConnIdleTime:   l.duration("DB_MAX_CONN_IDLE_TIME", dbpool.DefaultMaxConnIdleTime),
+		HealthCheckPeriod: l.duration("DB_HEALTH_CHECK_PERIOD", dbpool.DefaultHealthCheckPeriod),
+		ConnectTimeout:    l.duration("DB_CONNECT_TIMEOUT", dbpool.DefaultConnectTimeout),
+	}
+	cfg.MinConns = l.nonNegativeInt("DB_MIN_CONNS", 0)
+	if cfg.MinConns > cfg.MaxConns {
+		l.problem("DB_MIN_CONNS must be at most DB_MAX_CONNS (%d), got %d", cfg.MaxConns, cfg.MinConns)
+	}
+	return cfg
+}
+
+// tracing reads the standard OpenTelemetry exporter variables. Tracing
+// stays off until OTEL_EXPORTER_OTLP_ENDPOINT is set.
+func (l *loader) tracing(serviceName string) tracing.Config {
+	cfg := tracing.Config{
+		Endpoint:    strings.TrimSuffix(l.get("OTEL_EXPORTER_OTLP_ENDPOINT"), "/"),
+		ServiceName: l.str("OTEL_SERVICE_NAME", serviceName),
+		SampleRatio: l.ratio("OTEL_TRACES_SAMPLER_ARG", 1),
+	}
+	if cfg.Endpoint != "" {
+		if u, err := url.Parse(cfg.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			l.problem("OTEL_EXPORTER_OTLP_ENDPOINT must be an http or https URL, got %q", cfg.Endpoint)
+		}
+	}
+	// Headers are comma separated key=value pairs, as the OTLP spec has them
+	for _, pair := range strings.Split(l.get("OTEL_EXPORTER_OTLP_HEADERS"), ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			l.problem("OTEL_EXPORTER_OTLP_HEADERS must be key=value pairs, got %q", pair)
+			continue
+		}
+		if cfg.Headers == nil {
+			cfg.Headers = map[string]string{}
+		}
+		cfg.Headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return cfg
+}
@@ -0,0 +1,438 @@
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/dbpool"
+	"github.com/dfodeker/terminus/internal/ratelimit"
+)
+
+func env(vars map[string]string) Lookup {
+	return func(key string) (string, bool) {
+		v, ok := vars[key]
+		return v, ok
+	}
+}
+
+var encryptionKey = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("e", 32)))
+
+// apiEnv is the least the api binary starts with
+func apiEnv(extra map[string]string) map[string]string {
+	vars := map[string]string{
+		"PLATFORM":       "dev",
+		"DB_URL":         "postgres://localhost/terminus",
+		"SIGNING_KEY":    "secret",
+		"ENCRYPTION_KEY": encryptionKey,
+	}
+	for k, v := range extra {
+		vars[k] = v
+	}
+	return vars
+}
+
+func problems(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var cfgErr *Error
+	if !errors.As(err, &cfgErr) {
+		t.Fatalf("error = %v, want *Error", err)
+	}
+	return cfgErr.Problems
+}
+
+func TestLoadAPIDefaults(t *testing.T) {
+	cfg, err := LoadAPI(env(apiEnv(nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Port != "8080" || cfg.BaseDomain != "storeos.org" || cfg.AppURL != "http://localhost:3000" {
+		t.Errorf("defaults = port %q, base domain %q, app URL %q", cfg.Port, cfg.BaseDomain, cfg.AppURL)
+	}
+	if cfg.APIURL != "http://localhost:8080" {
+		t.Errorf("APIURL = %q", cfg.APIURL)
+	}
+	if rl := cfg.RateLimit; rl.Requests != 5 || rl.Window != time.Second || rl.Store != "memory" {
+		t.Errorf("RateLimit = %+v", rl)
+	}
+	if cfg.RateLimit.Plans["free"] != (ratelimit.Quota{Requests: 600, Window: time.Minute}) || cfg.RateLimit.Plans["pro"] != (ratelimit.Quota{Requests: 6000, Window: time.Minute}) {
+		t.Errorf("RateLimit.Plans = %+v", cfg.RateLimit.Plans)
+	}
+	if cfg.ShutdownTimeout != 30*time.Second || cfg.MigrateOnStart {
+		t.Errorf("ShutdownTimeout = %v, MigrateOnStart = %v", cfg.ShutdownTimeout, cfg.MigrateOnStart)
+	}
+	if cfg.Storage.LocalSecret != "secret" || cfg.Storage.LocalDir != "./uploads" {
+		t.Errorf("Storage = %+v, want local storage signed with SIGNING_KEY", cfg.Storage)
+	}
+	if cfg.Tracing.Enabled() || cfg.Tracing.SampleRatio != 1 {
+		t.Errorf("Tracing = %+v, want it off by default", cfg.Tracing)
+	}
+	if cfg.Search.Index != "products" || cfg.TLS.ACME || cfg.TLS.Port != "443" {
+		t.Errorf("Search = %+v, TLS = %+v", cfg.Search, cfg.TLS)
+	}
+	if cfg.Metrics.MaxStoreLabels != 500 {
+		t.Errorf("Metrics = %+v", cfg.Metrics)
+	}
+	if db := cfg.DB; db.MaxConns != 25 || db.MinConns != 0 || db.MaxConnLifetime != 30*time.Minute || db.MaxConnIdleTime != 5*time.Minute || db.HealthCheckPeriod != time.Minute {
+		t.Errorf("DB = %+v", db)
+	}
+}
+
+func TestLoadAPI(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32)))
+
+	tests := []struct {
+		name         string
+		vars         map[string]string
+		wantProblems []string
+		check        func(t *testing.T, cfg *Config)
+	}{
+		{
+			name: "nothing set lists every missing value",
+			vars: map[string]string{},
+			wantProblems: []string{
+				"PLATFORM is required",
+				"DB_URL is required",
+				"JWT_PRIVATE_KEY_FILE or SIGNING_KEY is required",
+				"ENCRYPTION_KEY or ENCRYPTION_KMS_KEY_ID is required",
+				"STORAGE_LOCAL_SECRET or SIGNING_KEY is required for local storage",
+			},
+		},
+		{
+			name: "empty counts as unset",
+			vars: apiEnv(map[string]string{"DB_URL": ""}),
+			wantProblems: []string{
+				"DB_URL is required",
+			},
+		},
+		{
+			name:         "invalid values",
+			vars:         apiEnv(map[string]string{"MACHINE_ID": "2000", "JWT_TENANT_CLAIMS": "maybe", "RATE_LIMIT_REQUESTS": "0", "RATE_LIMIT_WINDOW": "soon"}),
+			wantProblems: []string{`MACHINE_ID must be between 0 and 1023, got "2000"`, `RATE_LIMIT_REQUESTS must be a positive integer, got "0"`, `RATE_LIMIT_WINDOW must be a positive duration such as 1s, got "soon"`, `JWT_TENANT_CLAIMS must be true or false, got "maybe"`},
+		},
+		{
+			name: "overrides",
+			vars: apiEnv(map[string]string{"API_PORT": "9000", "APP_URL": "https://app.example.com/", "API_URL": "https://api.example.com/", "MACHINE_ID": "12", "STOREFRONT_TOKENS_REQUIRED": "true", "MIGRATE_ON_START": "true", "INVENTORY_RESERVATION_TTL": "30m", "RATE_LIMIT_REQUESTS": "50", "RATE_LIMIT_WINDOW": "1m", "JWT_RETIRED_KEY_FILES": "a.pem, b.pem,"}),
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Port != "9000" || cfg.AppURL != "https://app.example.com" || cfg.APIURL != "https://api.example.com" || cfg.MachineID != 12 || !cfg.StorefrontTokensRequired || !cfg.MigrateOnStart || cfg.ReservationTTL != 30*time.Minute {
+					t.Errorf("cfg = %+v", cfg)
+				}
+				if rl := cfg.RateLimit; rl.Requests != 50 || rl.Window != time.Minute {
+					t.Errorf("RateLimit = %+v", rl)
+				}
+				if !slices.Equal(cfg.JWTKeys.RetiredKeyFiles, []string{"a.pem", "b.pem"}) {
+					t.Errorf("RetiredKeyFiles = %q", cfg.JWTKeys.RetiredKeyFiles)
+				}
+			},
+		},
+		{
+			name: "rate limit plans",
+			vars: apiEnv(map[string]string{"RATE_LIMIT_PLANS": "free=100/1m, pro=50/1s, enterprise=10000/1m", "RATE_LIMIT_STORE": "redis", "REDIS_URL": "redis://localhost:6379"}),
+			check: func(t *testing.T, cfg *Config) {
+				if len(cfg.RateLimit.Plans) != 3 || cfg.RateLimit.Plans["pro"] != (ratelimit.Quota{Requests: 50, Window: time.Second}) || cfg.RateLimit.Store != "redis" {
+					t.Errorf("RateLimit = %+v", cfg.RateLimit)
+				}
+			},
+		},
+		{
+			name:         "redis rate limits need a redis URL",
+			vars:         apiEnv(map[string]string{"RATE_LIMIT_STORE": "redis"}),
+			wantProblems: []string{"REDIS_URL is required for RATE_LIMIT_STORE=redis"},
+		},
+		{
+			name:         "invalid rate limit settings",
+			vars:         apiEnv(map[string]string{"RATE_LIMIT_PLANS": "pro=6000/1m", "RATE_LIMIT_STORE": "memcached"}),
+			wantProblems: []string{"RATE_LIMIT_PLANS: a quota for the free plan is required", `RATE_LIMIT_STORE must be memory or redis, got "memcached"`},
+		},
+		{
+			name: "acme",
+			vars: apiEnv(map[string]string{"TLS_ACME": "1", "ACME_EMAIL": "ops@example.com"}),
+			check: func(t *testing.T, cfg *Config) {
+				if !cfg.TLS.ACME || cfg.TLS.Certs.Email != "ops@example.com" {
+					t.Errorf("TLS = %+v", cfg.TLS)
+				}
+			},
+		},
+		{
+			name: "encryption keys",
+			vars: apiEnv(map[string]string{"ENCRYPTION_RETIRED_KEYS": key + ", " + key, "ENCRYPTION_KMS_KEY_ID": "alias/terminus", "KMS_REGION": "us-east-1", "KMS_ACCESS_KEY_ID": "AKID", "KMS_SECRET_ACCESS_KEY": "secret", "ENCRYPTION_RETIRED_KMS_KEY_IDS": "alias/old"}),
+			check: func(t *testing.T, cfg *Config) {
+				enc := cfg.Encryption
+				if enc.KMS.KeyID != "alias/terminus" || enc.KMS.Region != "us-east-1" || len(enc.Key) != 32 || len(enc.RetiredKeys) != 2 || !slices.Equal(enc.RetiredKMSKeyIDs, []string{"alias/old"}) {
+					t.Errorf("Encryption = %+v", enc)
+				}
+			},
+		},
+		{
+			name:         "invalid encryption settings",
+			vars:         apiEnv(map[string]string{"ENCRYPTION_KEY": base64.StdEncoding.EncodeToString([]byte("short")), "ENCRYPTION_KMS_KEY_ID": "alias/terminus"}),
+			wantProblems: []string{"ENCRYPTION_KEY must be 32 bytes, base64 encoded", "KMS_REGION is required for ENCRYPTION_KMS_KEY_ID", "KMS_ACCESS_KEY_ID is required for ENCRYPTION_KMS_KEY_ID", "KMS_SECRET_ACCESS_KEY is required for ENCRYPTION_KMS_KEY_ID"},
+		},
+		{
+			name: "captcha",
+			vars: apiEnv(map[string]string{"CAPTCHA_SECRET": "0x0000", "CAPTCHA_VERIFY_URL": "https://challenges.cloudflare.com/turnstile/v0/siteverify", "LOGIN_CAPTCHA_AFTER": "5"}),
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Captcha.Secret != "0x0000" || cfg.Captcha.After != 5 || cfg.Captcha.VerifyURL == "" {
+					t.Errorf("Captcha = %+v", cfg.Captcha)
+				}
+			},
+		},
+		{
+			name:         "invalid captcha settings",
+			vars:         apiEnv(map[string]string{"CAPTCHA_SECRET": "0x0000", "CAPTCHA_VERIFY_URL": "http://captcha.example.com"}),
+			wantProblems: []string{`CAPTCHA_VERIFY_URL must be an https URL, got "http://captcha.example.com"`},
+		},
+		{
+			name: "oauth providers",
+			vars: apiEnv(map[string]string{"OAUTH_REDIRECT_URL": "https://app.example.com/oauth/callback", "GOOGLE_CLIENT_ID": "g", "GOOGLE_CLIENT_SECRET": "gs", "OIDC_PROVIDER_NAME": "okta", "OIDC_ISSUER": "https://example.okta.com", "OIDC_CLIENT_ID": "o", "OIDC_CLIENT_SECRET": "os"}),
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.OAuth.Google.ClientSecret != "gs" || cfg.OAuth.OIDC.Name != "okta" || cfg.OAuth.OIDC.Issuer != "https://example.okta.com" || cfg.OAuth.GitHub.ClientID != "" {
+					t.Errorf("OAuth = %+v", cfg.OAuth)
+				}
+			},
+		},
+		{
+			name:         "invalid oauth settings",
+			vars:         apiEnv(map[string]string{"GITHUB_CLIENT_ID": "gh", "OIDC_PROVIDER_NAME": "google", "OIDC_ISSUER": "http://idp.example.com", "OIDC_CLIENT_ID": "o", "OIDC_CLIENT_SECRET": "os"}),
+			wantProblems: []string{"GITHUB_CLIENT_SECRET is required for GITHUB_CLIENT_ID", "OAUTH_REDIRECT_URL is required for GITHUB_CLIENT_ID", "OAUTH_REDIRECT_URL is required for OIDC_CLIENT_ID", `OIDC_PROVIDER_NAME can't be "google", which is taken`, `OIDC_ISSUER must be an https URL, got "http://idp.example.com"`},
+		},
+		{
+			name:         "s3 settings",
+			vars:         apiEnv(map[string]string{"STORAGE_DRIVER": "s3", "S3_BUCKET": "media"}),
+			wantProblems: []string{"S3_REGION is required for STORAGE_DRIVER=s3", "S3_ACCESS_KEY_ID is required for STORAGE_DRIVER=s3", "S3_SECRET_ACCESS_KEY is required for STORAGE_DRIVER=s3"},
+		},
+		{
+			name:         "unknown drivers",
+			vars:         apiEnv(map[string]string{"STORAGE_DRIVER": "floppy", "SEARCH_ENGINE": "grep"}),
+			wantProblems: []string{`STORAGE_DRIVER must be local, s3 or gcs, got "floppy"`, `SEARCH_ENGINE must be postgres, meilisearch or elasticsearch, got "grep"`},
+		},
+		{
+			name: "database pool",
+			vars: apiEnv(map[string]string{"DB_MAX_CONNS": "40", "DB_MIN_CONNS": "10", "DB_MAX_CONN_LIFETIME": "1h", "DB_MAX_CONN_IDLE_TIME": "1m", "DB_HEALTH_CHECK_PERIOD": "30s", "DB_CONNECT_TIMEOUT": "3s"}),
+			check: func(t *testing.T, cfg *Config) {
+				want := dbpool.Config{MaxConns: 40, MinConns: 10, MaxConnLifetime: time.Hour, MaxConnIdleTime: time.Minute, HealthCheckPeriod: 30 * time.Second, ConnectTimeout: 3 * time.Second}
+				if cfg.DB != want {
+					t.Errorf("DB = %+v, want %+v", cfg.DB, want)
+				}
+			},
+		},
+		{
+			name:         "invalid database pool",
+			vars:         apiEnv(map[string]string{"DB_MAX_CONNS": "5", "DB_MIN_CONNS": "10", "DB_MAX_CONN_LIFETIME": "forever"}),
+			wantProblems: []string{`DB_MAX_CONN_LIFETIME must be a positive duration such as 1s, got "forever"`, "DB_MIN_CONNS must be at most DB_MAX_CONNS (5), got 10"},
+		},
+		{
+			name: "read replica",
+			vars: apiEnv(map[string]string{"DB_REPLICA_URL": "postgres://replica/terminus", "DB_REPLICA_CHECK_INTERVAL": "30s"}),
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.DatabaseReplicaURL != "postgres://replica/terminus" || cfg.ReplicaCheckInterval != 30*time.Second {
+					t.Errorf("replica = %q every %s", cfg.DatabaseReplicaURL, cfg.ReplicaCheckInterval)
+				}
+			},
+		},
+		{
+			name: "tracing",
+			vars: apiEnv(map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://otel-collector:4318/", "OTEL_EXPORTER_OTLP_HEADERS": "x-honeycomb-team=abc, x-env = prod", "OTEL_TRACES_SAMPLER_ARG": "0.25"}),
+			check: func(t *testing.T, cfg *Config) {
+				tr := cfg.Tracing
+				if !tr.Enabled() || tr.Endpoint != "http://otel-collector:4318" || tr.ServiceName != "terminus-api" || tr.SampleRatio != 0.25 {
+					t.Errorf("Tracing = %+v", tr)
+				}
+				if tr.Headers["x-honeycomb-team"] != "abc" || tr.Headers["x-env"] != "prod" {
+					t.Errorf("Headers = %v", tr.Headers)
+				}
+			},
+		},
+		{
+			name:         "invalid tracing",
+			vars:         apiEnv(map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "otel-collector:4318", "OTEL_EXPORTER_OTLP_HEADERS": "token", "OTEL_TRACES_SAMPLER_ARG": "2"}),
+			wantProblems: []string{`OTEL_TRACES_SAMPLER_ARG must be a number between 0 and 1, got "2"`, `OTEL_EXPORTER_OTLP_ENDPOINT must be an http or https URL, got "otel-collector:4318"`, `OTEL_EXPORTER_OTLP_HEADERS must be key=value pairs, got "token"`},
+		},
+		{
+			name:         "label providers need webhook secrets",
+			vars:         apiEnv(map[string]string{"EASYPOST_API_KEY": "ep", "SHIPPO_API_TOKEN": "sh"}),
+			wantProblems: []string{"EASYPOST_WEBHOOK_SECRET is required for EASYPOST_API_KEY", "SHIPPO_WEBHOOK_TOKEN is required for SHIPPO_API_TOKEN"},
+		},
+		{
+			name: "label providers",
+			vars: apiEnv(map[string]string{"SHIPPO_API_TOKEN": "sh", "SHIPPO_WEBHOOK_TOKEN": "tok"}),
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Labels.Shippo.APIToken != "sh" || cfg.Labels.Shippo.WebhookToken != "tok" || cfg.Labels.EasyPost.APIKey != "" {
+					t.Errorf("Labels = %+v", cfg.Labels)
+				}
+			},
+		},
+		{
+			name:         "billing needs a webhook secret and prices",
+			vars:         apiEnv(map[string]string{"STRIPE_SECRET_KEY": "sk_test"}),
+			wantProblems: []string{"STRIPE_WEBHOOK_SECRET is required for STRIPE_SECRET_KEY", "STRIPE_PRICES is required for STRIPE_SECRET_KEY"},
+		},
+		{
+			name:         "billing prices for unknown plans",
+			vars:         apiEnv(map[string]string{"STRIPE_SECRET_KEY": "sk_test", "STRIPE_WEBHOOK_SECRET": "whsec", "STRIPE_PRICES": "gold=price_1"}),
+			wantProblems: []string{`STRIPE_PRICES: unknown plan "gold", plans are free, pro`},
+		},
+		{
+			name:         "billing overage for unknown metrics",
+			vars:         apiEnv(map[string]string{"STRIPE_SECRET_KEY": "sk_test", "STRIPE_WEBHOOK_SECRET": "whsec", "STRIPE_PRICES": "pro=price_1", "STRIPE_OVERAGE_PRICES": "seats=price_2"}),
+			wantProblems: []string{`STRIPE_OVERAGE_PRICES: unknown metric "seats", metrics are api_calls, storage_bytes, orders`},
+		},
+		{
+			name: "billing",
+			vars: apiEnv(map[string]string{"STRIPE_SECRET_KEY": "sk_test", "STRIPE_WEBHOOK_SECRET": "whsec", "STRIPE_PRICES": "pro=price_1", "STRIPE_OVERAGE_PRICES": "orders=price_2", "BILLING_GRACE_PERIOD": "72h"}),
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Billing.SecretKey != "sk_test" || cfg.Billing.WebhookSecret != "whsec" || cfg.Billing.Prices["pro"] != "price_1" || cfg.Billing.OveragePrices["orders"] != "price_2" || cfg.Billing.GracePeriod != 72*time.Hour {
+					t.Errorf("Billing = %+v", cfg.Billing)
+				}
+			},
+		},
+		{
+			name: "meilisearch",
+			vars: apiEnv(map[string]string{"SEARCH_ENGINE": "meilisearch", "MEILISEARCH_URL": "http://meili:7700", "SEARCH_INDEX": "catalog"}),
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Search.Meilisearch.URL != "http://meili:7700" || cfg.Search.Index != "catalog" {
+					t.Errorf("Search = %+v", cfg.Search)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadAPI(env(tt.vars))
+			if got := problems(t, err); !slices.Equal(got, tt.wantProblems) {
+				t.Fatalf("problems = %q, want %q", got, tt.wantProblems)
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
+
+func TestLoadWorker(t *testing.T) {
+	tests := []struct {
+		name         string
+		vars         map[string]string
+		wantProblems []string
+		check        func(t *testing.T, cfg *Config)
+	}{
+		{
+			name: "defaults",
+			vars: map[string]string{"DB_URL": "postgres://localhost/terminus", "SIGNING_KEY": "secret", "ENCRYPTION_KEY": encryptionKey},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Worker.Queue != "default" || cfg.Worker.Concurrency != 4 || len(cfg.Worker.ThumbnailWidths) == 0 || cfg.Worker.AuditRetention != 365*24*time.Hour || cfg.Worker.NotificationRetention != 90*24*time.Hour || cfg.Worker.DeletedProductRetention != 30*24*time.Hour || cfg.Worker.DeletedStoreRetention != 30*24*time.Hour {
+					t.Errorf("Worker = %+v", cfg.Worker)
+				}
+				if cfg.Mail.Driver != "" || cfg.Mail.From == "" {
+					t.Errorf("Mail = %+v", cfg.Mail)
+				}
+				if cfg.Worker.FX.RatesURL != "" || cfg.Worker.FX.Base != "USD" || cfg.Worker.FX.Interval != 6*time.Hour {
+					t.Errorf("FX = %+v", cfg.Worker.FX)
+				}
+				if cfg.Worker.SubscriptionRetryDelay != 24*time.Hour || cfg.Worker.SubscriptionMaxAttempts != 3 {
+					t.Errorf("Worker = %+v", cfg.Worker)
+				}
+				if cfg.Worker.CustomerGroupRefreshInterval != 6*time.Hour {
+					t.Errorf("CustomerGroupRefreshInterval = %s", cfg.Worker.CustomerGroupRefreshInterval)
+				}
+			},
+		},
+		{
+			name: "customer group refresh interval",
+			vars: map[string]string{"DB_URL": "postgres://localhost/terminus", "SIGNING_KEY": "secret", "ENCRYPTION_KEY": encryptionKey, "CUSTOMER_GROUP_REFRESH_INTERVAL": "30m"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Worker.CustomerGroupRefreshInterval != 30*time.Minute {
+					t.Errorf("CustomerGroupRefreshInterval = %s", cfg.Worker.CustomerGroupRefreshInterval)
+				}
+			},
+		},
+		{
+			name: "low stock check interval",
+			vars: map[string]string{"DB_URL": "postgres://localhost/terminus", "SIGNING_KEY": "secret", "ENCRYPTION_KEY": encryptionKey, "LOW_STOCK_CHECK_INTERVAL": "1m"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Worker.LowStockCheckInterval != time.Minute {
+					t.Errorf("LowStockCheckInterval = %s", cfg.Worker.LowStockCheckInterval)
+				}
+			},
+		},
+		{
+			name: "metrics settings",
+			vars: map[string]string{"DB_URL": "postgres://localhost/terminus", "SIGNING_KEY": "secret", "ENCRYPTION_KEY": encryptionKey, "WORKER_METRICS_PORT": "9090", "METRICS_MAX_STORES": "50"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Worker.MetricsPort != "9090" || cfg.Metrics.MaxStoreLabels != 50 {
+					t.Errorf("MetricsPort = %q, Metrics = %+v", cfg.Worker.MetricsPort, cfg.Metrics)
+				}
+			},
+		},
+		{
+			name:         "invalid metrics settings",
+			vars:         map[string]string{"DB_URL": "postgres://localhost/terminus", "SIGNING_KEY": "secret", "ENCRYPTION_KEY": encryptionKey, "METRICS_MAX_STORES": "0"},
+			wantProblems: []string{`METRICS_MAX_STORES must be a positive integer, got "0"`},
+		},
+		{
+			name: "subscription billing settings",
+			vars: map[string]string{"DB_URL": "postgres://localhost/terminus", "SIGNING_KEY": "secret", "ENCRYPTION_KEY": encryptionKey, "SUBSCRIPTION_RETRY_DELAY": "6h", "SUBSCRIPTION_MAX_ATTEMPTS": "5"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Worker.SubscriptionRetryDelay != 6*time.Hour || cfg.Worker.SubscriptionMaxAttempts != 5 {
+					t.Errorf("Worker = %+v", cfg.Worker)
+				}
+			},
+		},
+		{
+			name:         "does not need api settings",
+			vars:         map[string]string{"SIGNING_KEY": "secret", "ENCRYPTION_KEY": encryptionKey},
+			wantProblems: []string{"DB_URL is required"},
+		},
+		{
+			name:         "invalid worker settings",
+			vars:         map[string]string{"DB_URL": "postgres://localhost/terminus", "SIGNING_KEY": "secret", "ENCRYPTION_KEY": encryptionKey, "WORKER_CONCURRENCY": "-1", "MEDIA_THUMBNAIL_WIDTHS": "wide", "AUDIT_LOG_RETENTION": "forever"},
+			wantProblems: []string{`WORKER_CONCURRENCY must be a positive integer, got "-1"`, `MEDIA_THUMBNAIL_WIDTHS: invalid thumbnail width "wide"`, `AUDIT_LOG_RETENTION must be a positive duration such as 1s, got "forever"`},
+		},
+		{
+			name:         "invalid exchange rate settings",
+			vars:         map[string]string{"DB_URL": "postgres://localhost/terminus", "SIGNING_KEY": "secret", "ENCRYPTION_KEY": encryptionKey, "FX_RATES_URL": "rates.example.com", "FX_BASE_CURRENCY": "usd"},
+			wantProblems: []string{`FX_BASE_CURRENCY must be a three letter ISO 4217 code such as USD, got "usd"`, `FX_RATES_URL must be an http or https URL, got "rates.example.com"`},
+		},
+		{
+			name:         "mail driver settings",
+			vars:         map[string]string{"DB_URL": "postgres://localhost/terminus", "SIGNING_KEY": "secret", "ENCRYPTION_KEY": encryptionKey, "MAIL_DRIVER": "ses", "SES_REGION": "us-east-1"},
+			wantProblems: []string{"SES_SMTP_USERNAME is required for MAIL_DRIVER=ses", "SES_SMTP_PASSWORD is required for MAIL_DRIVER=ses"},
+		},
+		{
+			name: "smtp",
+			vars: map[string]string{"DB_URL": "postgres://localhost/terminus", "SIGNING_KEY": "secret", "ENCRYPTION_KEY": encryptionKey, "MAIL_DRIVER": "smtp", "SMTP_HOST": "mail.example.com", "SMTP_PORT": "2525"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Mail.SMTP.Host != "mail.example.com" || cfg.Mail.SMTP.Port != 2525 {
+					t.Errorf("SMTP = %+v", cfg.Mail.SMTP)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadWorker(env(tt.vars))
+			if got := problems(t, err); !slices.Equal(got, tt.wantProblems) {
+				t.Fatalf("problems = %q, want %q", got, tt.wantProblems)
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
+
+func TestErrorListsProblems(t *testing.T) {
+	err := &Error{Problems: []string{"PLATFORM is required", "DB_URL is required"}}
+	msg := err.Error()
+	if !strings.Contains(msg, "2 problems") || !strings.Contains(msg, "PLATFORM is required") || !strings.Contains(msg, "DB_URL is required") {
+		t.Errorf("Error() = %q", msg)
+	}
+}
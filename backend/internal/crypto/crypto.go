@@ -0,0 +1,275 @@
+// Package crypto encrypts personal data and secrets before they are written
+// to the database, with envelope encryption. Values are sealed with
+// AES-256-GCM under a data key. Data keys are stored wrapped by a key
+// encryption key (KEK) held in KMS or the environment, so the database
+// alone can open nothing.
+//
+// Rotating the KEK rewraps the data keys and leaves values alone. Rotating
+// the data key seals new values under a fresh one; values under a retired
+// key stay readable and are resealed in the background.
+//
+// Columns are sealed and opened as they are written and read through the
+// Text, NullText and NullJSON types, with the keyring given to SetDefault.
+package crypto
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// KeySize is the size of data keys, index keys and local KEKs
+const KeySize = 32
+
+// Sealed values are "enc:v1:<key id>:<base64 of nonce and ciphertext>"
+const sealedPrefix = "enc:v1:"
+
+const (
+	// refreshInterval is how soon a keyring picks up a data key rotated
+	// in another process
+	refreshInterval = time.Minute
+	reloadTimeout   = 5 * time.Second
+)
+
+var (
+	// ErrNoKeyring is returned by the column types before SetDefault
+	ErrNoKeyring = errors.New("crypto: no keyring configured")
+	// ErrUnknownKey is returned for a value sealed under a key the
+	// keyring doesn't hold
+	ErrUnknownKey = errors.New("crypto: unknown data key")
+	// ErrMalformed is returned for a sealed value that can't be opened
+	ErrMalformed = errors.New("crypto: malformed sealed value")
+)
+
+// Keys are the unwrapped keys a Keyring works with
+type Keys struct {
+	// Active is the ID of the data key new values are sealed under
+	Active string
+	// Data holds every data key by ID, retired ones included
+	Data map[string][]byte
+	// Index hashes blind indexes. It is never rotated, since every
+	// digest would have to be recomputed before lookups found anything.
+	Index []byte
+}
+
+// Source loads the keys, such as keystore.Store from the database
+type Source interface {
+	Keys(ctx context.Context) (Keys, error)
+}
+
+// Keyring seals and opens values with the keys from its source, which it
+// reloads every minute so a rotation made elsewhere is picked up
+type Keyring struct {
+	source Source
+	now    func() time.Time
+
+	mu       sync.RWMutex
+	active   string
+	aeads    map[string]cipher.AEAD
+	index    []byte
+	loadedAt time.Time
+}
+
+// NewKeyring loads the keys from source
+func NewKeyring(ctx context.Context, source Source) (*Keyring, error) {
+	k := &Keyring{source: source, now: time.Now}
+	if err := k.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Reload loads the keys again, such as right after rotating them
+func (k *Keyring) Reload(ctx context.Context) error {
+	keys, err := k.source.Keys(ctx)
+	if err != nil {
+		return fmt.Errorf("crypto: load keys: %w", err)
+	}
+	if _, ok := keys.Data[keys.Active]; !ok {
+		return fmt.Errorf("crypto: active data key %q is missing", keys.Active)
+	}
+	if len(keys.Index) != KeySize {
+		return fmt.Errorf("crypto: index key must be %d bytes", KeySize)
+	}
+	aeads := make(map[string]cipher.AEAD, len(keys.Data))
+	for id, key := range keys.Data {
+		aead, err := newAEAD(key)
+		if err != nil {
+			return fmt.Errorf("crypto: data key %q: %w", id, err)
+		}
+		aeads[id] = aead
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.active, k.aeads, k.index = keys.Active, aeads, keys.Index
+	k.loadedAt = k.now()
+	return nil
+}
+
+// Active returns the ID of the data key new values are sealed under
+func (k *Keyring) Active() string {
+	k.refresh()
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.active
+}
+
+// Seal encrypts plaintext under the active data key
+func (k *Keyring) Seal(plaintext []byte) (string, error) {
+	k.refresh()
+	k.mu.RLock()
+	id, aead := k.active, k.aeads[k.active]
+	k.mu.RUnlock()
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	// The key ID is authenticated so a value can't be relabelled
+	sealed := aead.Seal(nonce, nonce, plaintext, []byte(id))
+	return Prefix(id) + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a value from Seal
+func (k *Keyring) Open(value string) ([]byte, error) {
+	rest, ok := strings.CutPrefix(value, sealedPrefix)
+	if !ok {
+		return nil, ErrMalformed
+	}
+	id, encoded, ok := strings.Cut(rest, ":")
+	if !ok {
+		return nil, ErrMalformed
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	aead := k.aead(id)
+	if aead == nil {
+		// Sealed by another process under a key rotated in since the
+		// last reload
+		ctx, cancel := context.WithTimeout(context.Background(), reloadTimeout)
+		defer cancel()
+		if err := k.Reload(ctx); err != nil {
+			return nil, err
+		}
+		if aead = k.aead(id); aead == nil {
+			return nil, fmt.Errorf("%w %q", ErrUnknownKey, id)
+		}
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(id))
+	if err != nil {
+		return nil, fmt.Errorf("crypto: open value under key %q: %w", id, err)
+	}
+	return plaintext, nil
+}
+
+// Digest returns the blind index of value: a keyed hash that can be
+// stored and matched without revealing value. Case is ignored, as it is
+// in emails.
+func (k *Keyring) Digest(value string) string {
+	k.mu.RLock()
+	mac := hmac.New(sha256.New, k.index)
+	k.mu.RUnlock()
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(value))))
+	return digestPrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (k *Keyring) aead(id string) cipher.AEAD {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.aeads[id]
+}
+
+// refresh reloads the keys once they are a minute old. One caller reloads
+// while the rest carry on with the keys they have; a failed reload keeps
+// them until the next minute.
+func (k *Keyring) refresh() {
+	k.mu.Lock()
+	stale := k.now().Sub(k.loadedAt) >= refreshInterval
+	if stale {
+		k.loadedAt = k.now()
+	}
+	k.mu.Unlock()
+	if !stale {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), reloadTimeout)
+	defer cancel()
+	if err := k.Reload(ctx); err != nil {
+		slog.Warn("reloading encryption keys failed", "error", err)
+	}
+}
+
+// Prefix is how every value sealed under the data key id begins
+func Prefix(id string) string {
+	return sealedPrefix + id + ":"
+}
+
+// IsSealed reports whether value was sealed, rather than written before
+// encryption was turned on
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, sealedPrefix)
+}
+
+// NewKeyID returns a random ID for a data or index key
+func NewKeyID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// NewKey returns a random data or index key
+func NewKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+var defaultKeyring atomic.Pointer[Keyring]
+
+// SetDefault makes k the keyring the column types seal and open with. The
+// api and worker set it at startup, before touching the database.
+func SetDefault(k *Keyring) {
+	defaultKeyring.Store(k)
+}
+
+func keyring() (*Keyring, error) {
+	k := defaultKeyring.Load()
+	if k == nil {
+		return nil, ErrNoKeyring
+	}
+	return k, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
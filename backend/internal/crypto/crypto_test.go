@@ -0,0 +1,282 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// staticSource serves keys from memory, as the database would
+type staticSource struct {
+	keys Keys
+}
+
+func (s *staticSource) Keys(context.Context) (Keys, error) {
+	return s.keys, nil
+}
+
+func newTestKeyring(t *testing.T) (*Keyring, *staticSource) {
+	t.Helper()
+	source := &staticSource{keys: Keys{
+		Active: "k1",
+		Data:   map[string][]byte{"k1": bytes.Repeat([]byte{1}, KeySize)},
+		Index:  bytes.Repeat([]byte{9}, KeySize),
+	}}
+	k, err := NewKeyring(context.Background(), source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k, source
+}
+
+func TestSealOpen(t *testing.T) {
+	k, source := newTestKeyring(t)
+
+	sealed, err := k.Seal([]byte("ada@example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sealed, Prefix("k1")) || strings.Contains(sealed, "ada") {
+		t.Fatalf("Seal() = %q", sealed)
+	}
+
+	// A rotation made elsewhere is picked up when a value under the new
+	// key is opened, and values under the retired key stay readable
+	source.keys = Keys{
+		Active: "k2",
+		Data:   map[string][]byte{"k1": source.keys.Data["k1"], "k2": bytes.Repeat([]byte{2}, KeySize)},
+		Index:  source.keys.Index,
+	}
+	other, err := NewKeyring(context.Background(), source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := other.Seal([]byte("grace@example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr error
+	}{
+		{name: "retired key", value: sealed, want: "ada@example.com"},
+		{name: "key rotated in since loading", value: rotated, want: "grace@example.com"},
+		{name: "unknown key", value: Prefix("k3") + strings.TrimPrefix(sealed, Prefix("k1")), wantErr: ErrUnknownKey},
+		{name: "not sealed", value: "ada@example.com", wantErr: ErrMalformed},
+		{name: "bad encoding", value: Prefix("k1") + "!!", wantErr: ErrMalformed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := k.Open(tt.value)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Open() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Open() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Relabelling a value with another key ID fails authentication
+	relabelled := Prefix("k2") + strings.TrimPrefix(sealed, Prefix("k1"))
+	if _, err := k.Open(relabelled); err == nil {
+		t.Error("Open() of a relabelled value succeeded")
+	}
+}
+
+func TestColumnTypes(t *testing.T) {
+	k, _ := newTestKeyring(t)
+	SetDefault(k)
+	t.Cleanup(func() { SetDefault(nil) })
+
+	t.Run("text round trip", func(t *testing.T) {
+		v, err := Text("1 Main St").Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !IsSealed(v.(string)) {
+			t.Fatalf("Value() = %q, want it sealed", v)
+		}
+		var got Text
+		if err := got.Scan([]byte(v.(string))); err != nil {
+			t.Fatal(err)
+		}
+		if got != "1 Main St" {
+			t.Errorf("Scan() = %q", got)
+		}
+	})
+
+	t.Run("null text", func(t *testing.T) {
+		v, err := NullText{}.Value()
+		if err != nil || v != nil {
+			t.Fatalf("Value() = %v, %v, want NULL", v, err)
+		}
+		var got NullText
+		if err := got.Scan(nil); err != nil || got.Valid {
+			t.Fatalf("Scan(nil) = %+v, %v", got, err)
+		}
+		v, err = NullText{String: "555-0100", Valid: true}.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := got.Scan(v); err != nil || got != (NullText{String: "555-0100", Valid: true}) {
+			t.Fatalf("Scan() = %+v, %v", got, err)
+		}
+	})
+
+	t.Run("legacy plaintext passes through", func(t *testing.T) {
+		var text Text
+		if err := text.Scan("ada@example.com"); err != nil || text != "ada@example.com" {
+			t.Errorf("Text.Scan() = %q, %v", text, err)
+		}
+		var null NullText
+		if err := null.Scan([]byte("555-0100")); err != nil || null.String != "555-0100" || !null.Valid {
+			t.Errorf("NullText.Scan() = %+v, %v", null, err)
+		}
+		var doc NullJSON
+		if err := doc.Scan([]byte(`{"city":"Springfield"}`)); err != nil || string(doc.RawMessage) != `{"city":"Springfield"}` {
+			t.Errorf("NullJSON.Scan() = %s, %v", doc.RawMessage, err)
+		}
+	})
+
+	t.Run("json round trip", func(t *testing.T) {
+		v, err := NullJSON{RawMessage: json.RawMessage(`{"city":"Springfield"}`), Valid: true}.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Stored as a JSON string so the column stays valid JSON
+		var stored string
+		if err := json.Unmarshal(v.([]byte), &stored); err != nil || !IsSealed(stored) {
+			t.Fatalf("Value() = %s, %v", v, err)
+		}
+		var got NullJSON
+		if err := got.Scan(v); err != nil {
+			t.Fatal(err)
+		}
+		if !got.Valid || string(got.RawMessage) != `{"city":"Springfield"}` {
+			t.Errorf("Scan() = %+v", got)
+		}
+	})
+
+	t.Run("digest", func(t *testing.T) {
+		a, err := Digest("Ada@Example.com ").Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := Digest("ada@example.com").Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a != b || !strings.HasPrefix(a.(string), digestPrefix) {
+			t.Fatalf("Value() = %q and %q, want the same digest", a, b)
+		}
+		// A digest read back is written back unchanged
+		var stored Digest
+		if err := stored.Scan(a); err != nil {
+			t.Fatal(err)
+		}
+		if again, _ := stored.Value(); again != a {
+			t.Errorf("Value() of a stored digest = %q, want %q", again, a)
+		}
+		if empty, _ := Digest("").Value(); empty != "" {
+			t.Errorf("Value() of empty = %q", empty)
+		}
+	})
+}
+
+func TestColumnTypesWithoutKeyring(t *testing.T) {
+	SetDefault(nil)
+	if _, err := Text("secret").Value(); !errors.Is(err, ErrNoKeyring) {
+		t.Errorf("Value() error = %v, want ErrNoKeyring", err)
+	}
+	var text Text
+	if err := text.Scan(Prefix("k1") + "AAAA"); !errors.Is(err, ErrNoKeyring) {
+		t.Errorf("Scan() error = %v, want ErrNoKeyring", err)
+	}
+}
+
+func TestLocalKEK(t *testing.T) {
+	kek, err := NewLocalKEK(bytes.Repeat([]byte{7}, KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := bytes.Repeat([]byte{3}, KeySize)
+	wrapped, err := kek.Wrap(context.Background(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := kek.Unwrap(context.Background(), wrapped)
+	if err != nil || !bytes.Equal(got, key) {
+		t.Fatalf("Unwrap() = %x, %v", got, err)
+	}
+
+	other, _ := NewLocalKEK(bytes.Repeat([]byte{8}, KeySize))
+	if other.ID() == kek.ID() {
+		t.Error("different KEKs share an ID")
+	}
+	if _, err := other.Unwrap(context.Background(), wrapped); err == nil {
+		t.Error("Unwrap() with another KEK succeeded")
+	}
+	if _, err := NewLocalKEK([]byte("short")); err == nil {
+		t.Error("NewLocalKEK() accepted a short key")
+	}
+}
+
+func TestConfigKEKs(t *testing.T) {
+	current, retired := bytes.Repeat([]byte{1}, KeySize), bytes.Repeat([]byte{2}, KeySize)
+	currentKEK, _ := NewLocalKEK(current)
+	retiredKEK, _ := NewLocalKEK(retired)
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		want    []string
+		wantErr bool
+	}{
+		{name: "nothing configured", wantErr: true},
+		{name: "local", cfg: Config{Key: current, RetiredKeys: [][]byte{retired}}, want: []string{currentKEK.ID(), retiredKEK.ID()}},
+		{
+			name: "kms wraps, local only unwraps",
+			cfg: Config{
+				KMS:              KMSConfig{KeyID: "alias/new", Region: "us-east-1", AccessKeyID: "AKID", SecretAccessKey: "secret"},
+				RetiredKMSKeyIDs: []string{"alias/old"},
+				Key:              current,
+			},
+			want: []string{"kms:alias/new", "kms:alias/old", currentKEK.ID()},
+		},
+		{name: "kms without credentials", cfg: Config{KMS: KMSConfig{KeyID: "alias/new", Region: "us-east-1"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keks, err := tt.cfg.KEKs()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("KEKs() succeeded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, k := range keks {
+				got = append(got, k.ID())
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("KEKs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/sigv4"
+	"github.com/dfodeker/terminus/internal/tracing"
+)
+
+// KEK wraps and unwraps data keys. Its ID is stored with each key it
+// wraps, so a key wrapped by a retired KEK can be found and rewrapped.
+type KEK interface {
+	ID() string
+	Wrap(ctx context.Context, key []byte) ([]byte, error)
+	Unwrap(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// Config configures the KEKs. The first of KMS and Key that is set wraps
+// new data keys; the rest only unwrap keys until they are rewrapped.
+type Config struct {
+	// KMS is an AWS KMS key, used when its KeyID is set
+	KMS KMSConfig
+	// RetiredKMSKeyIDs are KMS keys used before KMS.KeyID, reached with
+	// the same credentials
+	RetiredKMSKeyIDs []string
+	// Key is a local KEK of KeySize bytes
+	Key []byte
+	// RetiredKeys are local KEKs used before Key
+	RetiredKeys [][]byte
+}
+
+// KEKs returns the configured KEKs, the one wrapping new keys first
+func (c Config) KEKs() ([]KEK, error) {
+	var keks []KEK
+	if c.KMS.KeyID != "" {
+		kms, err := NewKMS(c.KMS)
+		if err != nil {
+			return nil, err
+		}
+		keks = append(keks, kms)
+		for _, id := range c.RetiredKMSKeyIDs {
+			cfg := c.KMS
+			cfg.KeyID = id
+			retired, err := NewKMS(cfg)
+			if err != nil {
+				return nil, err
+			}
+			keks = append(keks, retired)
+		}
+	}
+	for _, key := range append([][]byte{c.Key}, c.RetiredKeys...) {
+		if key == nil {
+			continue
+		}
+		local, err := NewLocalKEK(key)
+		if err != nil {
+			return nil, err
+		}
+		keks = append(keks, local)
+	}
+	if len(keks) == 0 {
+		return nil, errors.New("crypto: no key encryption key configured")
+	}
+	return keks, nil
+}
+
+// LocalKEK is a KEK held in the environment. Its ID is derived from the
+// key, so it needs no name of its own.
+type LocalKEK struct {
+	id   string
+	aead cipher.AEAD
+}
+
+// NewLocalKEK returns a KEK for a KeySize key
+func NewLocalKEK(key []byte) (*LocalKEK, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, fmt.Errorf("crypto: local KEK: %w", err)
+	}
+	sum := sha256.Sum256(key)
+	return &LocalKEK{id: "local:" + hex.EncodeToString(sum[:8]), aead: aead}, nil
+}
+
+func (l *LocalKEK) ID() string { return l.id }
+
+func (l *LocalKEK) Wrap(_ context.Context, key []byte) ([]byte, error) {
+	nonce := make([]byte, l.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return l.aead.Seal(nonce, nonce, key, nil), nil
+}
+
+func (l *LocalKEK) Unwrap(_ context.Context, wrapped []byte) ([]byte, error) {
+	if len(wrapped) < l.aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := wrapped[:l.aead.NonceSize()], wrapped[l.aead.NonceSize():]
+	return l.aead.Open(nil, nonce, ciphertext, nil)
+}
+
+// KMSConfig configures an AWS KMS key
+type KMSConfig struct {
+	// KeyID is the key's ID, ARN or alias
+	KeyID  string
+	Region string
+	// Endpoint defaults to https://kms.<region>.amazonaws.com
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// kmsContext is bound to every key KMS wraps, so a wrapped key is only
+// unwrapped for this purpose
+var kmsContext = map[string]string{"purpose": "terminus-data-key"}
+
+// KMS is a KEK in AWS KMS. Keys are wrapped and unwrapped by KMS itself,
+// so the KEK never leaves it; KMS rotates its key material behind the same
+// key ID.
+type KMS struct {
+	keyID    string
+	endpoint string
+	signer   sigv4.Signer
+	client   *http.Client
+	now      func() time.Time
+}
+
+// NewKMS returns a KEK for a KMS key
+func NewKMS(cfg KMSConfig) (*KMS, error) {
+	if cfg.KeyID == "" || cfg.Region == "" {
+		return nil, errors.New("crypto: KMS key ID and region are required")
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, errors.New("crypto: KMS credentials are required")
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://kms." + cfg.Region + ".amazonaws.com"
+	}
+	return &KMS{
+		keyID:    cfg.KeyID,
+		endpoint: strings.TrimSuffix(cfg.Endpoint, "/") + "/",
+		signer:   sigv4.AWS("kms", cfg.Region, cfg.AccessKeyID, cfg.SecretAccessKey),
+		client:   &http.Client{Timeout: 10 * time.Second, Transport: tracing.Transport(nil)},
+		now:      time.Now,
+	}, nil
+}
+
+func (k *KMS) ID() string { return "kms:" + k.keyID }
+
+func (k *KMS) Wrap(ctx context.Context, key []byte) ([]byte, error) {
+	var out struct{ CiphertextBlob []byte }
+	err := k.call(ctx, "Encrypt", map[string]any{
+		"KeyId":             k.keyID,
+		"Plaintext":         key,
+		"EncryptionContext": kmsContext,
+	}, &out)
+	return out.CiphertextBlob, err
+}
+
+func (k *KMS) Unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	var out struct{ Plaintext []byte }
+	err := k.call(ctx, "Decrypt", map[string]any{
+		"KeyId":             k.keyID,
+		"CiphertextBlob":    wrapped,
+		"EncryptionContext": kmsContext,
+	}, &out)
+	return out.Plaintext, err
+}
+
+// call makes a KMS JSON API request. Byte slices travel base64 encoded,
+// which encoding/json does for them.
+func (k *KMS) call(ctx context.Context, action string, in map[string]any, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, k.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService."+action)
+	k.signer.SignBody(req, body, k.now())
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("crypto: KMS %s: %w", action, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var kmsErr struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		json.Unmarshal(b, &kmsErr)
+		return fmt.Errorf("crypto: KMS %s: %s: %s %s", action, resp.Status, kmsErr.Type, kmsErr.Message)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
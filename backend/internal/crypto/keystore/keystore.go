@@ -0,0 +1,219 @@
+// Package keystore keeps the keys of package crypto in the database,
+// wrapped by a KEK, and reseals stored values under the active data key
+package keystore
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dfodeker/terminus/internal/crypto"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/service"
+)
+
+// Key purposes
+const (
+	PurposeData  = "data"
+	PurposeIndex = "index"
+)
+
+// Queries are the database queries the store needs
+type Queries interface {
+	ListEncryptionKeys(ctx context.Context) ([]database.EncryptionKey, error)
+	CreateEncryptionKey(ctx context.Context, arg database.CreateEncryptionKeyParams) (database.EncryptionKey, error)
+	RewrapEncryptionKey(ctx context.Context, arg database.RewrapEncryptionKeyParams) error
+	RetireEncryptionKey(ctx context.Context, purpose string) error
+}
+
+var errRotated = service.Conflict("The data key was rotated by another request; list the keys to see the active one")
+
+// Store is a crypto.Source over the encryption_keys table. As keys are
+// loaded, a missing data or index key is created and keys wrapped by a
+// retired KEK are rewrapped by the current one.
+type Store struct {
+	q Queries
+	// keks wrap keys with the first and unwrap them with any
+	keks []crypto.KEK
+
+	// unwrapped caches keys by ID so KMS is only asked about new ones
+	mu        sync.Mutex
+	unwrapped map[string]unwrappedKey
+}
+
+type unwrappedKey struct {
+	wrapped []byte
+	key     []byte
+}
+
+// New returns a store over q. keks must hold at least one KEK.
+func New(q Queries, keks []crypto.KEK) *Store {
+	return &Store{q: q, keks: keks, unwrapped: make(map[string]unwrappedKey)}
+}
+
+// Open builds the KEKs from cfg and loads a keyring from a store over q
+func Open(ctx context.Context, q Queries, cfg crypto.Config) (*Store, *crypto.Keyring, error) {
+	keks, err := cfg.KEKs()
+	if err != nil {
+		return nil, nil, err
+	}
+	store := New(q, keks)
+	keyring, err := crypto.NewKeyring(ctx, store)
+	if err != nil {
+		return nil, nil, err
+	}
+	return store, keyring, nil
+}
+
+// Keys implements crypto.Source
+func (s *Store) Keys(ctx context.Context) (crypto.Keys, error) {
+	rows, err := s.ensureKeys(ctx)
+	if err != nil {
+		return crypto.Keys{}, err
+	}
+
+	keys := crypto.Keys{Data: make(map[string][]byte)}
+	for _, row := range rows {
+		key, err := s.unwrap(ctx, row)
+		if err != nil {
+			return crypto.Keys{}, err
+		}
+		if row.KekID != s.keks[0].ID() {
+			if err := s.rewrap(ctx, row, key); err != nil {
+				return crypto.Keys{}, err
+			}
+		}
+
+		active := !row.RetiredAt.Valid
+		switch row.Purpose {
+		case PurposeData:
+			keys.Data[row.ID] = key
+			if active {
+				keys.Active = row.ID
+			}
+		case PurposeIndex:
+			if active {
+				keys.Index = key
+			}
+		}
+	}
+	return keys, nil
+}
+
+// Rotate retires the active data key for a new one. Values under the
+// retired key stay readable until a Resealer reseals them. Run it in a
+// transaction, with q bound to it.
+func (s *Store) Rotate(ctx context.Context, q Queries) (database.EncryptionKey, error) {
+	if err := q.RetireEncryptionKey(ctx, PurposeData); err != nil {
+		return database.EncryptionKey{}, err
+	}
+	key, err := s.create(ctx, q, PurposeData)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.EncryptionKey{}, errRotated
+	}
+	return key, err
+}
+
+// ensureKeys lists the keys, first creating the active data and index keys
+// if they are missing, such as on first start. Processes starting together
+// race to create them; the losers use the winner's.
+func (s *Store) ensureKeys(ctx context.Context) ([]database.EncryptionKey, error) {
+	rows, err := s.q.ListEncryptionKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	created := false
+	for _, purpose := range []string{PurposeData, PurposeIndex} {
+		if hasActive(rows, purpose) {
+			continue
+		}
+		if _, err := s.create(ctx, s.q, purpose); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		created = true
+	}
+	if !created {
+		return rows, nil
+	}
+	return s.q.ListEncryptionKeys(ctx)
+}
+
+func (s *Store) create(ctx context.Context, q Queries, purpose string) (database.EncryptionKey, error) {
+	key, err := crypto.NewKey()
+	if err != nil {
+		return database.EncryptionKey{}, err
+	}
+	kek := s.keks[0]
+	wrapped, err := kek.Wrap(ctx, key)
+	if err != nil {
+		return database.EncryptionKey{}, fmt.Errorf("wrap %s key: %w", purpose, err)
+	}
+	return q.CreateEncryptionKey(ctx, database.CreateEncryptionKeyParams{
+		ID:         crypto.NewKeyID(),
+		Purpose:    purpose,
+		WrappedKey: wrapped,
+		KekID:      kek.ID(),
+	})
+}
+
+func (s *Store) unwrap(ctx context.Context, row database.EncryptionKey) ([]byte, error) {
+	s.mu.Lock()
+	cached, ok := s.unwrapped[row.ID]
+	s.mu.Unlock()
+	if ok && bytes.Equal(cached.wrapped, row.WrappedKey) {
+		return cached.key, nil
+	}
+
+	var kek crypto.KEK
+	for _, k := range s.keks {
+		if k.ID() == row.KekID {
+			kek = k
+		}
+	}
+	if kek == nil {
+		return nil, fmt.Errorf("key %q is wrapped by %s, which isn't configured", row.ID, row.KekID)
+	}
+	key, err := kek.Unwrap(ctx, row.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("unwrap key %q with %s: %w", row.ID, row.KekID, err)
+	}
+
+	s.mu.Lock()
+	s.unwrapped[row.ID] = unwrappedKey{wrapped: row.WrappedKey, key: key}
+	s.mu.Unlock()
+	return key, nil
+}
+
+// rewrap wraps a key with the current KEK, so the KEK it was wrapped with
+// can be retired
+func (s *Store) rewrap(ctx context.Context, row database.EncryptionKey, key []byte) error {
+	kek := s.keks[0]
+	wrapped, err := kek.Wrap(ctx, key)
+	if err != nil {
+		return fmt.Errorf("rewrap key %q: %w", row.ID, err)
+	}
+	if err := s.q.RewrapEncryptionKey(ctx, database.RewrapEncryptionKeyParams{
+		ID:         row.ID,
+		WrappedKey: wrapped,
+		KekID:      kek.ID(),
+	}); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.unwrapped[row.ID] = unwrappedKey{wrapped: wrapped, key: key}
+	s.mu.Unlock()
+	return nil
+}
+
+func hasActive(rows []database.EncryptionKey, purpose string) bool {
+	for _, row := range rows {
+		if row.Purpose == purpose && !row.RetiredAt.Valid {
+			return true
+		}
+	}
+	return false
+}
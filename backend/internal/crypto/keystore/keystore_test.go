@@ -0,0 +1,161 @@
+package keystore
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/crypto"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/service"
+)
+
+// fakeQueries keeps encryption_keys in memory, holding to its one active
+// key per purpose the way the unique index does
+type fakeQueries struct {
+	rows    []database.EncryptionKey
+	rewraps int
+	// raceCreate makes the next create lose to another process
+	raceCreate bool
+}
+
+func (f *fakeQueries) ListEncryptionKeys(context.Context) ([]database.EncryptionKey, error) {
+	return append([]database.EncryptionKey(nil), f.rows...), nil
+}
+
+func (f *fakeQueries) CreateEncryptionKey(_ context.Context, arg database.CreateEncryptionKeyParams) (database.EncryptionKey, error) {
+	if f.raceCreate {
+		f.raceCreate = false
+		return database.EncryptionKey{}, sql.ErrNoRows
+	}
+	for _, row := range f.rows {
+		if row.Purpose == arg.Purpose && !row.RetiredAt.Valid {
+			return database.EncryptionKey{}, sql.ErrNoRows
+		}
+	}
+	row := database.EncryptionKey{
+		ID:         arg.ID,
+		Purpose:    arg.Purpose,
+		WrappedKey: arg.WrappedKey,
+		KekID:      arg.KekID,
+		CreatedAt:  time.Now(),
+	}
+	f.rows = append(f.rows, row)
+	return row, nil
+}
+
+func (f *fakeQueries) RewrapEncryptionKey(_ context.Context, arg database.RewrapEncryptionKeyParams) error {
+	f.rewraps++
+	for i := range f.rows {
+		if f.rows[i].ID == arg.ID {
+			f.rows[i].WrappedKey, f.rows[i].KekID = arg.WrappedKey, arg.KekID
+		}
+	}
+	return nil
+}
+
+func (f *fakeQueries) RetireEncryptionKey(_ context.Context, purpose string) error {
+	for i := range f.rows {
+		if f.rows[i].Purpose == purpose && !f.rows[i].RetiredAt.Valid {
+			f.rows[i].RetiredAt = sql.NullTime{Time: time.Now(), Valid: true}
+		}
+	}
+	return nil
+}
+
+func localKEK(t *testing.T, b byte) crypto.KEK {
+	t.Helper()
+	kek, err := crypto.NewLocalKEK(bytes.Repeat([]byte{b}, crypto.KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return kek
+}
+
+func TestKeys(t *testing.T) {
+	q := &fakeQueries{}
+	oldKEK, newKEK := localKEK(t, 1), localKEK(t, 2)
+
+	first, err := New(q, []crypto.KEK{oldKEK}).Keys(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(q.rows) != 2 || first.Active == "" || len(first.Data[first.Active]) != crypto.KeySize || len(first.Index) != crypto.KeySize {
+		t.Fatalf("Keys() = %+v with rows %+v, want a data and an index key created", first, q.rows)
+	}
+	for _, row := range q.rows {
+		if bytes.Contains(row.WrappedKey, first.Index) || bytes.Contains(row.WrappedKey, first.Data[first.Active]) {
+			t.Fatalf("key %q is stored unwrapped", row.ID)
+		}
+	}
+
+	// Loading with a new KEK rewraps the keys and leaves them as they were
+	second, err := New(q, []crypto.KEK{newKEK, oldKEK}).Keys(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.rewraps != 2 || second.Active != first.Active || !bytes.Equal(second.Index, first.Index) || !bytes.Equal(second.Data[second.Active], first.Data[first.Active]) {
+		t.Fatalf("after a KEK change: rewraps = %d, keys = %+v, want %+v", q.rewraps, second, first)
+	}
+	for _, row := range q.rows {
+		if row.KekID != newKEK.ID() {
+			t.Errorf("key %q is wrapped by %s, want %s", row.ID, row.KekID, newKEK.ID())
+		}
+	}
+
+	// The old KEK can be dropped once everything is rewrapped
+	if _, err := New(q, []crypto.KEK{newKEK}).Keys(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(q, []crypto.KEK{localKEK(t, 3)}).Keys(context.Background()); err == nil {
+		t.Error("Keys() with none of the KEKs the keys are wrapped by succeeded")
+	}
+}
+
+func TestRotate(t *testing.T) {
+	tests := []struct {
+		name     string
+		race     bool
+		wantErr  bool
+		wantKeys int
+	}{
+		{name: "rotates the data key", wantKeys: 2},
+		{name: "another rotation won", race: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &fakeQueries{}
+			s := New(q, []crypto.KEK{localKEK(t, 1)})
+			before, err := s.Keys(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			q.raceCreate = tt.race
+			key, err := s.Rotate(context.Background(), q)
+			if tt.wantErr {
+				if !errors.Is(err, service.ErrConflict) {
+					t.Fatalf("Rotate() error = %v, want a conflict", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			after, err := s.Keys(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if after.Active != key.ID || after.Active == before.Active || len(after.Data) != tt.wantKeys {
+				t.Errorf("after rotating: %+v, want %s active alongside %s", after, key.ID, before.Active)
+			}
+			if !bytes.Equal(after.Index, before.Index) {
+				t.Error("rotating the data key changed the index key")
+			}
+		})
+	}
+}
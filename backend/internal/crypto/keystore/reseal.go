@@ -0,0 +1,276 @@
+package keystore
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/crypto"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/google/uuid"
+)
+
+// ResealArgs is the job that reseals right after a data key rotation,
+// rather than on the Resealer's next pass
+type ResealArgs struct{}
+
+func (ResealArgs) Kind() string { return "encryption.reseal" }
+
+// ResealQueries are the database queries a Resealer runs
+type ResealQueries interface {
+	ListCustomersToReseal(ctx context.Context, arg database.ListCustomersToResealParams) ([]database.Customer, error)
+	ResealCustomer(ctx context.Context, arg database.ResealCustomerParams) (int64, error)
+	ListCustomerAddressesToReseal(ctx context.Context, arg database.ListCustomerAddressesToResealParams) ([]database.CustomerAddress, error)
+	ResealCustomerAddress(ctx context.Context, arg database.ResealCustomerAddressParams) (int64, error)
+	ListOrdersToReseal(ctx context.Context, arg database.ListOrdersToResealParams) ([]database.Order, error)
+	ResealOrder(ctx context.Context, arg database.ResealOrderParams) (int64, error)
+	ListUserMFAToReseal(ctx context.Context, arg database.ListUserMFAToResealParams) ([]database.UserMfa, error)
+	ResealUserMFA(ctx context.Context, arg database.ResealUserMFAParams) (int64, error)
+	ListACMECacheEntriesToReseal(ctx context.Context, arg database.ListACMECacheEntriesToResealParams) ([]database.AcmeCache, error)
+	ResealACMECacheEntry(ctx context.Context, arg database.ResealACMECacheEntryParams) (int64, error)
+}
+
+// ResealerConfig configures a Resealer
+type ResealerConfig struct {
+	Interval time.Duration
+	// BatchSize bounds each read, so a large backlog is worked through a
+	// page at a time
+	BatchSize int
+	Logger    *slog.Logger
+}
+
+// Resealer reseals stored values under the active data key: those under a
+// retired key and those written before encryption was turned on. It also
+// fills in the blind indexes of rows written before they existed. A row
+// changed while it is being resealed is left for the next pass, which
+// finds it sealed already unless the change came from a process still on
+// the retired key. Running several at once is harmless.
+type Resealer struct {
+	q       ResealQueries
+	keyring *crypto.Keyring
+	cfg     ResealerConfig
+}
+
+// NewResealer creates a resealer over q
+func NewResealer(q ResealQueries, keyring *crypto.Keyring, cfg ResealerConfig) *Resealer {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 24 * time.Hour
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 500
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &Resealer{q: q, keyring: keyring, cfg: cfg}
+}
+
+// Run reseals once an interval until ctx is cancelled, starting right
+// away so a deploy that turns encryption on seals what is already stored
+func (r *Resealer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := r.Reseal(ctx)
+		if err != nil && ctx.Err() == nil {
+			r.cfg.Logger.Error("resealing encrypted data failed", "error", err)
+		} else if n > 0 {
+			r.cfg.Logger.Info("encrypted data resealed", "rows", n, "key_id", r.keyring.Active())
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reseal makes one pass over every sealed column and returns how many rows
+// it resealed
+func (r *Resealer) Reseal(ctx context.Context) (int64, error) {
+	prefix := crypto.Prefix(r.keyring.Active())
+	var total int64
+	for _, reseal := range []func(context.Context, string) (int64, error){
+		r.customers,
+		r.customerAddresses,
+		r.orders,
+		r.userMFA,
+		r.acmeCache,
+	} {
+		n, err := reseal(ctx, prefix)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+func (r *Resealer) customers(ctx context.Context, prefix string) (int64, error) {
+	var total int64
+	after := uuid.Nil
+	for {
+		rows, err := r.q.ListCustomersToReseal(ctx, database.ListCustomersToResealParams{
+			After:        after,
+			SealedPrefix: prefix,
+			RowLimit:     int32(r.cfg.BatchSize),
+		})
+		if err != nil {
+			return total, err
+		}
+		for _, c := range rows {
+			n, err := r.q.ResealCustomer(ctx, database.ResealCustomerParams{
+				Email:       c.Email,
+				EmailDigest: crypto.Digest(c.Email),
+				Phone:       c.Phone,
+				ID:          c.ID,
+				UpdatedAt:   c.UpdatedAt,
+			})
+			if err != nil {
+				return total, err
+			}
+			total += n
+			after = c.ID
+		}
+		if len(rows) < r.cfg.BatchSize {
+			return total, nil
+		}
+	}
+}
+
+func (r *Resealer) customerAddresses(ctx context.Context, prefix string) (int64, error) {
+	var total int64
+	after := uuid.Nil
+	for {
+		rows, err := r.q.ListCustomerAddressesToReseal(ctx, database.ListCustomerAddressesToResealParams{
+			After:        after,
+			SealedPrefix: prefix,
+			RowLimit:     int32(r.cfg.BatchSize),
+		})
+		if err != nil {
+			return total, err
+		}
+		for _, a := range rows {
+			n, err := r.q.ResealCustomerAddress(ctx, database.ResealCustomerAddressParams{
+				Address1:  a.Address1,
+				Address2:  a.Address2,
+				Phone:     a.Phone,
+				ID:        a.ID,
+				UpdatedAt: a.UpdatedAt,
+			})
+			if err != nil {
+				return total, err
+			}
+			total += n
+			after = a.ID
+		}
+		if len(rows) < r.cfg.BatchSize {
+			return total, nil
+		}
+	}
+}
+
+func (r *Resealer) orders(ctx context.Context, prefix string) (int64, error) {
+	var total int64
+	after := uuid.Nil
+	for {
+		rows, err := r.q.ListOrdersToReseal(ctx, database.ListOrdersToResealParams{
+			After:        after,
+			SealedPrefix: prefix,
+			RowLimit:     int32(r.cfg.BatchSize),
+		})
+		if err != nil {
+			return total, err
+		}
+		for _, o := range rows {
+			n, err := r.q.ResealOrder(ctx, database.ResealOrderParams{
+				Email:           o.Email,
+				EmailDigest:     crypto.Digest(o.Email.String),
+				ShippingAddress: o.ShippingAddress,
+				BillingAddress:  o.BillingAddress,
+				ID:              o.ID,
+				UpdatedAt:       o.UpdatedAt,
+			})
+			if err != nil {
+				return total, err
+			}
+			total += n
+			after = o.ID
+		}
+		if len(rows) < r.cfg.BatchSize {
+			return total, nil
+		}
+	}
+}
+
+func (r *Resealer) userMFA(ctx context.Context, prefix string) (int64, error) {
+	var total int64
+	after := uuid.Nil
+	for {
+		rows, err := r.q.ListUserMFAToReseal(ctx, database.ListUserMFAToResealParams{
+			After:        after,
+			SealedPrefix: prefix,
+			RowLimit:     int32(r.cfg.BatchSize),
+		})
+		if err != nil {
+			return total, err
+		}
+		for _, m := range rows {
+			n, err := r.q.ResealUserMFA(ctx, database.ResealUserMFAParams{
+				Secret:    m.Secret,
+				UserID:    m.UserID,
+				UpdatedAt: m.UpdatedAt,
+			})
+			if err != nil {
+				return total, err
+			}
+			total += n
+			after = m.UserID
+		}
+		if len(rows) < r.cfg.BatchSize {
+			return total, nil
+		}
+	}
+}
+
+// acmeCache reseals certificates and the ACME account key, which package
+// certs seals itself rather than through a column type
+func (r *Resealer) acmeCache(ctx context.Context, prefix string) (int64, error) {
+	var total int64
+	after := ""
+	for {
+		rows, err := r.q.ListACMECacheEntriesToReseal(ctx, database.ListACMECacheEntriesToResealParams{
+			After:        after,
+			SealedPrefix: prefix,
+			RowLimit:     int32(r.cfg.BatchSize),
+		})
+		if err != nil {
+			return total, err
+		}
+		for _, e := range rows {
+			plaintext, err := r.keyring.Open(e.Data)
+			if err != nil {
+				return total, err
+			}
+			data, err := r.keyring.Seal(plaintext)
+			if err != nil {
+				return total, err
+			}
+			n, err := r.q.ResealACMECacheEntry(ctx, database.ResealACMECacheEntryParams{
+				Data:      data,
+				Key:       e.Key,
+				UpdatedAt: e.UpdatedAt,
+			})
+			if err != nil {
+				return total, err
+			}
+			total += n
+			after = e.Key
+		}
+		if len(rows) < r.cfg.BatchSize {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package crypto
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// digestPrefix marks a stored digest, so writing back a Digest that was
+// read doesn't hash it again
+const digestPrefix = "hmac:v1:"
+
+// Text is a NOT NULL text column stored sealed. It reads back as the
+// plaintext; a value written before encryption was turned on reads back as
+// it is until it is resealed.
+type Text string
+
+// Value seals t
+func (t Text) Value() (driver.Value, error) {
+	k, err := keyring()
+	if err != nil {
+		return nil, err
+	}
+	return k.Seal([]byte(t))
+}
+
+// Scan opens a sealed value
+func (t *Text) Scan(src any) error {
+	s, err := openText(src)
+	if err != nil {
+		return err
+	}
+	*t = Text(s)
+	return nil
+}
+
+// NullText is a nullable Text, used like sql.NullString
+type NullText struct {
+	String string
+	Valid  bool
+}
+
+// Value seals the string, or writes NULL when n isn't valid
+func (n NullText) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return Text(n.String).Value()
+}
+
+// Scan opens a sealed value
+func (n *NullText) Scan(src any) error {
+	if src == nil {
+		*n = NullText{}
+		return nil
+	}
+	s, err := openText(src)
+	if err != nil {
+		return err
+	}
+	*n = NullText{String: s, Valid: true}
+	return nil
+}
+
+// NullJSON is a nullable json or jsonb column stored sealed, as a JSON
+// string holding the sealed document. It is used like
+// pqtype.NullRawMessage.
+type NullJSON struct {
+	RawMessage json.RawMessage
+	Valid      bool
+}
+
+// Value seals the document, or writes NULL when n isn't valid
+func (n NullJSON) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	k, err := keyring()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := k.Seal(n.RawMessage)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(sealed)
+}
+
+// Scan opens a sealed document. A document written before encryption was
+// turned on is read as it is.
+func (n *NullJSON) Scan(src any) error {
+	if src == nil {
+		*n = NullJSON{}
+		return nil
+	}
+	raw, err := srcBytes(src)
+	if err != nil {
+		return err
+	}
+	if !bytes.HasPrefix(raw, []byte(`"`+sealedPrefix)) {
+		*n = NullJSON{RawMessage: bytes.Clone(raw), Valid: true}
+		return nil
+	}
+	var sealed string
+	if err := json.Unmarshal(raw, &sealed); err != nil {
+		return ErrMalformed
+	}
+	k, err := keyring()
+	if err != nil {
+		return err
+	}
+	doc, err := k.Open(sealed)
+	if err != nil {
+		return err
+	}
+	*n = NullJSON{RawMessage: doc, Valid: true}
+	return nil
+}
+
+// Digest is a blind index column. Set it to the plaintext, which is hashed
+// when written; read back, it holds the stored hash, which is written back
+// unchanged. The empty string stays empty, for rows with nothing to index.
+type Digest string
+
+// Value hashes d
+func (d Digest) Value() (driver.Value, error) {
+	if d == "" || strings.HasPrefix(string(d), digestPrefix) {
+		return string(d), nil
+	}
+	k, err := keyring()
+	if err != nil {
+		return nil, err
+	}
+	return k.Digest(string(d)), nil
+}
+
+// Scan reads the stored hash
+func (d *Digest) Scan(src any) error {
+	b, err := srcBytes(src)
+	if err != nil {
+		return err
+	}
+	*d = Digest(b)
+	return nil
+}
+
+func openText(src any) (string, error) {
+	b, err := srcBytes(src)
+	if err != nil {
+		return "", err
+	}
+	s := string(b)
+	if !IsSealed(s) {
+		return s, nil
+	}
+	k, err := keyring()
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := k.Open(s)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func srcBytes(src any) ([]byte, error) {
+	switch v := src.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	}
+	return nil, fmt.Errorf("crypto: cannot scan %T", src)
+}
@@ -18,7 +18,7 @@ WHERE store_id = $1
 `
 
 func (q *Queries) CountCustomDomainsByStoreID(ctx context.Context, storeID uuid.UUID) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countCustomDomainsByStoreID, storeID)
+	row := q.db.QueryRow(ctx, countCustomDomainsByStoreID, storeID)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -40,7 +40,7 @@ type CreateCustomDomainParams struct {
 
 // Custom Domain Management
 func (q *Queries) CreateCustomDomain(ctx context.Context, arg CreateCustomDomainParams) (CustomDomain, error) {
-	row := q.db.QueryRowContext(ctx, createCustomDomain,
+	row := q.db.QueryRow(ctx, createCustomDomain,
 		arg.Gid,
 		arg.Domain,
 		arg.StoreID,
@@ -76,7 +76,7 @@ type DeleteCustomDomainParams struct {
 }
 
 func (q *Queries) DeleteCustomDomain(ctx context.Context, arg DeleteCustomDomainParams) error {
-	_, err := q.db.ExecContext(ctx, deleteCustomDomain, arg.ID, arg.StoreID)
+	_, err := q.db.Exec(ctx, deleteCustomDomain, arg.ID, arg.StoreID)
 	return err
 }
 
@@ -86,7 +86,7 @@ WHERE domain = $1
 `
 
 func (q *Queries) GetCustomDomainByDomain(ctx context.Context, domain string) (CustomDomain, error) {
-	row := q.db.QueryRowContext(ctx, getCustomDomainByDomain, domain)
+	row := q.db.QueryRow(ctx, getCustomDomainByDomain, domain)
 	var i CustomDomain
 	err := row.Scan(
 		&i.ID,
@@ -112,7 +112,7 @@ WHERE gid = $1
 `
 
 func (q *Queries) GetCustomDomainByGID(ctx context.Context, gid int64) (CustomDomain, error) {
-	row := q.db.QueryRowContext(ctx, getCustomDomainByGID, gid)
+	row := q.db.QueryRow(ctx, getCustomDomainByGID, gid)
 	var i CustomDomain
 	err := row.Scan(
 		&i.ID,
@@ -138,7 +138,7 @@ WHERE id = $1
 `
 
 func (q *Queries) GetCustomDomainByID(ctx context.Context, id uuid.UUID) (CustomDomain, error) {
-	row := q.db.QueryRowContext(ctx, getCustomDomainByID, id)
+	row := q.db.QueryRow(ctx, getCustomDomainByID, id)
 	var i CustomDomain
 	err := row.Scan(
 		&i.ID,
@@ -165,7 +165,7 @@ ORDER BY is_primary DESC, created_at ASC
 `
 
 func (q *Queries) GetCustomDomainsByStoreID(ctx context.Context, storeID uuid.UUID) ([]CustomDomain, error) {
-	rows, err := q.db.QueryContext(ctx, getCustomDomainsByStoreID, storeID)
+	rows, err := q.db.Query(ctx, getCustomDomainsByStoreID, storeID)
 	if err != nil {
 		return nil, err
 	}
@@ -192,9 +192,6 @@ func (q *Queries) GetCustomDomainsByStoreID(ctx context.Context, storeID uuid.UU
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -208,7 +205,7 @@ ORDER BY created_at DESC
 `
 
 func (q *Queries) GetCustomDomainsByTenantID(ctx context.Context, tenantID uuid.UUID) ([]CustomDomain, error) {
-	rows, err := q.db.QueryContext(ctx, getCustomDomainsByTenantID, tenantID)
+	rows, err := q.db.Query(ctx, getCustomDomainsByTenantID, tenantID)
 	if err != nil {
 		return nil, err
 	}
@@ -235,9 +232,6 @@ func (q *Queries) GetCustomDomainsByTenantID(ctx context.Context, tenantID uuid.
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -252,7 +246,7 @@ ORDER BY created_at ASC
 `
 
 func (q *Queries) GetPendingDomainVerifications(ctx context.Context) ([]CustomDomain, error) {
-	rows, err := q.db.QueryContext(ctx, getPendingDomainVerifications)
+	rows, err := q.db.Query(ctx, getPendingDomainVerifications)
 	if err != nil {
 		return nil, err
 	}
@@ -279,9 +273,6 @@ func (q *Queries) GetPendingDomainVerifications(ctx context.Context) ([]CustomDo
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -289,14 +280,17 @@ func (q *Queries) GetPendingDomainVerifications(ctx context.Context) ([]CustomDo
 }
 
 const getStoreByCustomDomain = `-- name: GetStoreByCustomDomain :one
-SELECT s.id, s.name, s.handle, s.address, s.status, s.default_currency, s.timezone, s.plan, s.created_at, s.updated_at, s.tenant_id, s.gid FROM stores s
+SELECT s.id, s.name, s.handle, s.address, s.status, s.default_currency, s.timezone, s.plan, s.created_at, s.updated_at, s.tenant_id, s.gid, s.deleted_at, s.settings, s.default_locale FROM stores s
 JOIN custom_domains cd ON s.id = cd.store_id
 WHERE cd.domain = $1
   AND cd.verification_status = 'verified'
+  AND s.deleted_at IS NULL
+  AND NOT EXISTS (SELECT 1 FROM tenants t WHERE t.id = s.tenant_id AND t.status = 'suspended')
 `
 
+// Stores of suspended tenants are not found, so they stop being served
 func (q *Queries) GetStoreByCustomDomain(ctx context.Context, domain string) (Store, error) {
-	row := q.db.QueryRowContext(ctx, getStoreByCustomDomain, domain)
+	row := q.db.QueryRow(ctx, getStoreByCustomDomain, domain)
 	var i Store
 	err := row.Scan(
 		&i.ID,
@@ -311,6 +305,9 @@ func (q *Queries) GetStoreByCustomDomain(ctx context.Context, domain string) (St
 		&i.UpdatedAt,
 		&i.TenantID,
 		&i.Gid,
+		&i.DeletedAt,
+		&i.Settings,
+		&i.DefaultLocale,
 	)
 	return i, err
 }
@@ -321,7 +318,7 @@ WHERE domain = $1 AND verification_status = 'verified'
 `
 
 func (q *Queries) GetVerifiedCustomDomainByDomain(ctx context.Context, domain string) (CustomDomain, error) {
-	row := q.db.QueryRowContext(ctx, getVerifiedCustomDomainByDomain, domain)
+	row := q.db.QueryRow(ctx, getVerifiedCustomDomainByDomain, domain)
 	var i CustomDomain
 	err := row.Scan(
 		&i.ID,
@@ -353,7 +350,7 @@ type SetPrimaryDomainParams struct {
 }
 
 func (q *Queries) SetPrimaryDomain(ctx context.Context, arg SetPrimaryDomainParams) error {
-	_, err := q.db.ExecContext(ctx, setPrimaryDomain, arg.StoreID, arg.ID)
+	_, err := q.db.Exec(ctx, setPrimaryDomain, arg.StoreID, arg.ID)
 	return err
 }
 
@@ -374,7 +371,7 @@ type UpdateCustomDomainSSLStatusParams struct {
 }
 
 func (q *Queries) UpdateCustomDomainSSLStatus(ctx context.Context, arg UpdateCustomDomainSSLStatusParams) (CustomDomain, error) {
-	row := q.db.QueryRowContext(ctx, updateCustomDomainSSLStatus, arg.ID, arg.SslStatus, arg.SslExpiresAt)
+	row := q.db.QueryRow(ctx, updateCustomDomainSSLStatus, arg.ID, arg.SslStatus, arg.SslExpiresAt)
 	var i CustomDomain
 	err := row.Scan(
 		&i.ID,
@@ -410,7 +407,7 @@ type UpdateCustomDomainVerificationStatusParams struct {
 }
 
 func (q *Queries) UpdateCustomDomainVerificationStatus(ctx context.Context, arg UpdateCustomDomainVerificationStatusParams) (CustomDomain, error) {
-	row := q.db.QueryRowContext(ctx, updateCustomDomainVerificationStatus, arg.ID, arg.VerificationStatus)
+	row := q.db.QueryRow(ctx, updateCustomDomainVerificationStatus, arg.ID, arg.VerificationStatus)
 	var i CustomDomain
 	err := row.Scan(
 		&i.ID,
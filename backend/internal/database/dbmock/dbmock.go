@@ -0,0 +1,18 @@
+// Package dbmock mocks the queries of package database, so code taking a
+// database.Querier, or an interface of some of its methods, can be unit
+// tested without a database. Set the funcs of the queries a test expects:
+//
+//	q := &dbmock.Querier{
+//		GetStoreByIDFunc: func(ctx context.Context, id uuid.UUID) (database.Store, error) {
+//			return database.Store{ID: id}, nil
+//		},
+//	}
+//
+// querier.go is generated from the Querier interface by cmd/querymock.
+package dbmock
+
+import "fmt"
+
+func unexpected(method string) string {
+	return fmt.Sprintf("dbmock: unexpected call to %s; set %sFunc", method, method)
+}
@@ -0,0 +1,31 @@
+package dbmock
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/google/uuid"
+)
+
+func TestQuerier(t *testing.T) {
+	id := uuid.New()
+	q := &Querier{
+		GetStoreByIDFunc: func(_ context.Context, got uuid.UUID) (database.Store, error) {
+			return database.Store{ID: got}, nil
+		},
+	}
+	store, err := q.GetStoreByID(context.Background(), id)
+	if err != nil || store.ID != id {
+		t.Errorf("GetStoreByID() = %v, %v, want the store %s", store.ID, err, id)
+	}
+
+	defer func() {
+		msg, _ := recover().(string)
+		if !strings.Contains(msg, "unexpected call to GetUserByEmail") {
+			t.Errorf("unset query panicked with %q", msg)
+		}
+	}()
+	q.GetUserByEmail(context.Background(), "user@example.com")
+}
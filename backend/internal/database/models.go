@@ -9,9 +9,148 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/dfodeker/terminus/internal/crypto"
 	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
 )
 
+type AcmeCache struct {
+	Key       string
+	Data      string
+	UpdatedAt time.Time
+}
+
+type AnalyticsDailyChannel struct {
+	StoreID   uuid.UUID
+	Day       time.Time
+	ChannelID uuid.UUID
+	Checkouts int32
+	Converted int32
+	Orders    int32
+}
+
+type AnalyticsDailyProduct struct {
+	StoreID      uuid.UUID
+	Day          time.Time
+	Currency     string
+	ProductID    uuid.UUID
+	Units        int64
+	Orders       int32
+	RevenueCents int64
+}
+
+type AnalyticsDailySale struct {
+	StoreID       uuid.UUID
+	Day           time.Time
+	Currency      string
+	Orders        int32
+	Units         int64
+	SubtotalCents int64
+	DiscountCents int64
+	ShippingCents int64
+	TaxCents      int64
+	TotalCents    int64
+	RefundedCents int64
+}
+
+type AnalyticsRollup struct {
+	StoreID       uuid.UUID
+	RolledThrough sql.NullTime
+	RolledAt      sql.NullTime
+}
+
+type ApiKey struct {
+	ID         uuid.UUID
+	Gid        sql.NullInt64
+	TenantID   uuid.UUID
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	CreatedBy  uuid.UUID
+	LastUsedAt sql.NullTime
+	ExpiresAt  sql.NullTime
+	RevokedAt  sql.NullTime
+	CreatedAt  time.Time
+}
+
+type AuditLog struct {
+	ID            uuid.UUID
+	TenantID      uuid.UUID
+	StoreID       uuid.NullUUID
+	ActorUserID   uuid.NullUUID
+	ActorApiKeyID uuid.NullUUID
+	Action        string
+	EntityType    sql.NullString
+	EntityID      uuid.NullUUID
+	EntityGid     sql.NullString
+	Method        string
+	Route         string
+	Path          string
+	StatusCode    int32
+	RequestID     sql.NullString
+	Ip            sql.NullString
+	Before        pqtype.NullRawMessage
+	After         pqtype.NullRawMessage
+	Changes       pqtype.NullRawMessage
+	CreatedAt     time.Time
+}
+
+type BulkOperation struct {
+	ID             uuid.UUID
+	TenantID       uuid.UUID
+	CreatedBy      uuid.NullUUID
+	Status         string
+	Mutations      json.RawMessage
+	Permissions    []string
+	TotalMutations int32
+	Error          sql.NullString
+	CreatedAt      time.Time
+	StartedAt      sql.NullTime
+	FinishedAt     sql.NullTime
+}
+
+type BulkOperationResult struct {
+	OperationID uuid.UUID
+	Position    int32
+	Op          string
+	StoreID     uuid.UUID
+	TargetID    uuid.UUID
+	Status      string
+	Errors      json.RawMessage
+	CreatedAt   time.Time
+}
+
+type BundleComponent struct {
+	BundleVariantID    uuid.UUID
+	ComponentVariantID uuid.UUID
+	StoreID            uuid.UUID
+	Quantity           int32
+	CreatedAt          time.Time
+}
+
+type Collection struct {
+	ID          uuid.UUID
+	Gid         sql.NullInt64
+	TenantID    uuid.UUID
+	StoreID     uuid.UUID
+	Handle      string
+	Title       string
+	Description sql.NullString
+	Kind        string
+	Rules       json.RawMessage
+	MatchAny    bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type CollectionProduct struct {
+	CollectionID uuid.UUID
+	ProductID    uuid.UUID
+	Position     int32
+	CreatedAt    time.Time
+}
+
 type CustomDomain struct {
 	ID                 uuid.UUID
 	Gid                int64
@@ -28,6 +167,460 @@ type CustomDomain struct {
 	UpdatedAt          time.Time
 }
 
+type Customer struct {
+	ID               uuid.UUID
+	Gid              sql.NullInt64
+	TenantID         uuid.UUID
+	StoreID          uuid.UUID
+	Email            crypto.Text
+	HashedPassword   sql.NullString
+	FirstName        sql.NullString
+	LastName         sql.NullString
+	Phone            crypto.NullText
+	AcceptsMarketing bool
+	Status           string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Tags             []string
+	ErasedAt         sql.NullTime
+	EmailDigest      crypto.Digest
+}
+
+type CustomerAddress struct {
+	ID                uuid.UUID
+	CustomerID        uuid.UUID
+	FirstName         sql.NullString
+	LastName          sql.NullString
+	Company           sql.NullString
+	Address1          crypto.Text
+	Address2          crypto.NullText
+	City              string
+	RegionCode        sql.NullString
+	PostalCode        sql.NullString
+	CountryCode       string
+	Phone             crypto.NullText
+	IsDefaultShipping bool
+	IsDefaultBilling  bool
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+type CustomerGroup struct {
+	ID                 uuid.UUID
+	Gid                sql.NullInt64
+	TenantID           uuid.UUID
+	StoreID            uuid.UUID
+	Name               string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	Kind               string
+	MinTotalSpentCents sql.NullInt64
+	MaxTotalSpentCents sql.NullInt64
+	MinOrderCount      sql.NullInt32
+	MaxOrderCount      sql.NullInt32
+	Tags               []string
+	RecalculatedAt     sql.NullTime
+}
+
+type CustomerGroupMember struct {
+	GroupID    uuid.UUID
+	CustomerID uuid.UUID
+	CreatedAt  time.Time
+}
+
+type CustomerRefreshToken struct {
+	TokenHash  string
+	CustomerID uuid.UUID
+	ExpiresAt  time.Time
+	RevokedAt  sql.NullTime
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type EmailVerificationToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    sql.NullTime
+	CreatedAt time.Time
+	NewEmail  sql.NullString
+}
+
+type EncryptionKey struct {
+	ID         string
+	Purpose    string
+	WrappedKey []byte
+	KekID      string
+	CreatedAt  time.Time
+	RetiredAt  sql.NullTime
+}
+
+type ExchangeRate struct {
+	Currency  string
+	Base      string
+	Rate      float64
+	UpdatedAt time.Time
+}
+
+type Export struct {
+	ID         uuid.UUID
+	StoreID    uuid.UUID
+	CreatedBy  uuid.NullUUID
+	Resource   string
+	Format     string
+	Columns    []string
+	Filters    string
+	Status     string
+	StorageKey sql.NullString
+	RowCount   sql.NullInt32
+	SizeBytes  sql.NullInt64
+	Error      sql.NullString
+	CreatedAt  time.Time
+	StartedAt  sql.NullTime
+	FinishedAt sql.NullTime
+}
+
+type Fulfillment struct {
+	ID                uuid.UUID
+	Gid               sql.NullInt64
+	StoreID           uuid.UUID
+	OrderID           uuid.UUID
+	Status            string
+	Carrier           sql.NullString
+	TrackingNumber    sql.NullString
+	TrackingUrl       sql.NullString
+	ShippedAt         sql.NullTime
+	DeliveredAt       sql.NullTime
+	CancelledAt       sql.NullTime
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	LabelProvider     sql.NullString
+	LabelShipmentRef  sql.NullString
+	LabelUrl          sql.NullString
+	LabelCostCents    sql.NullInt64
+	LabelCurrency     sql.NullString
+	LabelPurchasedAt  sql.NullTime
+	TrackingStatus    sql.NullString
+	TrackingUpdatedAt sql.NullTime
+}
+
+type FulfillmentLineItem struct {
+	FulfillmentID   uuid.UUID
+	OrderLineItemID uuid.UUID
+	Quantity        int32
+}
+
+type GiftCard struct {
+	ID                  uuid.UUID
+	Gid                 sql.NullInt64
+	TenantID            uuid.UUID
+	StoreID             uuid.UUID
+	CustomerID          uuid.NullUUID
+	CodeHash            string
+	LastCharacters      string
+	Currency            string
+	InitialBalanceCents int64
+	BalanceCents        int64
+	Status              string
+	Note                sql.NullString
+	ExpiresAt           sql.NullTime
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+type GiftCardTransaction struct {
+	ID                uuid.UUID
+	GiftCardID        uuid.UUID
+	StoreID           uuid.UUID
+	Kind              string
+	AmountCents       int64
+	BalanceAfterCents int64
+	OrderID           uuid.NullUUID
+	UserID            uuid.NullUUID
+	Note              sql.NullString
+	CreatedAt         time.Time
+}
+
+type IdempotencyKey struct {
+	ID              uuid.UUID
+	TenantID        uuid.UUID
+	Key             string
+	Method          string
+	Path            string
+	RequestHash     string
+	Status          string
+	ResponseStatus  sql.NullInt32
+	ResponseHeaders pqtype.NullRawMessage
+	ResponseBody    []byte
+	ExpiresAt       time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	Caller          string
+}
+
+type InventoryAlert struct {
+	ID           uuid.UUID
+	TenantID     uuid.UUID
+	StoreID      uuid.UUID
+	VariantID    uuid.UUID
+	LocationID   uuid.UUID
+	Kind         string
+	OnHand       int32
+	ReorderPoint sql.NullInt32
+	CreatedAt    time.Time
+}
+
+type InventoryCount struct {
+	ID          uuid.UUID
+	Gid         sql.NullInt64
+	TenantID    uuid.UUID
+	StoreID     uuid.UUID
+	LocationID  uuid.UUID
+	Name        string
+	Status      string
+	CreatedBy   uuid.NullUUID
+	CompletedBy uuid.NullUUID
+	CompletedAt sql.NullTime
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type InventoryCountLine struct {
+	CountID   uuid.UUID
+	VariantID uuid.UUID
+	Expected  int32
+	Counted   int32
+	CountedBy uuid.NullUUID
+	CountedAt time.Time
+}
+
+type InventoryItem struct {
+	ID        uuid.UUID
+	TenantID  uuid.UUID
+	StoreID   uuid.UUID
+	VariantID uuid.UUID
+	OnHand    int32
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Reserved  int32
+}
+
+type InventoryLevel struct {
+	ID              uuid.UUID
+	TenantID        uuid.UUID
+	InventoryItemID uuid.UUID
+	LocationID      uuid.UUID
+	OnHand          int32
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	ReorderPoint    sql.NullInt32
+	ReorderQuantity sql.NullInt32
+	LowStockSince   sql.NullTime
+}
+
+type InventoryLocation struct {
+	ID        uuid.UUID
+	TenantID  uuid.UUID
+	Name      string
+	Kind      string
+	Address   sql.NullString
+	IsDefault bool
+	Active    bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type InventoryMovement struct {
+	ID              uuid.UUID
+	TenantID        uuid.UUID
+	InventoryItemID uuid.UUID
+	Delta           int32
+	QuantityAfter   int32
+	Reason          string
+	Note            sql.NullString
+	CreatedBy       uuid.NullUUID
+	CreatedAt       time.Time
+	LocationID      uuid.NullUUID
+	TransferID      uuid.NullUUID
+}
+
+type InventoryReservation struct {
+	ID          uuid.UUID
+	Gid         sql.NullInt64
+	TenantID    uuid.UUID
+	StoreID     uuid.UUID
+	CustomerID  uuid.NullUUID
+	OrderID     uuid.NullUUID
+	Status      string
+	ExpiresAt   time.Time
+	CommittedAt sql.NullTime
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ChannelID   uuid.NullUUID
+}
+
+type InventoryReservationItem struct {
+	ReservationID   uuid.UUID
+	InventoryItemID uuid.UUID
+	VariantID       uuid.UUID
+	Quantity        int32
+	Held            int32
+	Backordered     int32
+}
+
+type Job struct {
+	ID          uuid.UUID
+	Queue       string
+	Kind        string
+	Payload     json.RawMessage
+	Status      string
+	Attempts    int32
+	MaxAttempts int32
+	RunAt       time.Time
+	LockedAt    sql.NullTime
+	LockedBy    sql.NullString
+	LastError   sql.NullString
+	CompletedAt sql.NullTime
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type LoginThrottle struct {
+	Key          crypto.Digest
+	Failures     int32
+	LockedUntil  sql.NullTime
+	LastFailedAt time.Time
+}
+
+type MagicLinkToken struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	TokenHash  string
+	DeviceHash string
+	ExpiresAt  time.Time
+	UsedAt     sql.NullTime
+	CreatedAt  time.Time
+}
+
+type MfaChallenge struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	TokenHash string
+	Attempts  int32
+	ExpiresAt time.Time
+	UsedAt    sql.NullTime
+	CreatedAt time.Time
+}
+
+type MfaRecoveryCode struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	CodeHash  string
+	UsedAt    sql.NullTime
+	CreatedAt time.Time
+}
+
+type Notification struct {
+	ID        uuid.UUID
+	TenantID  uuid.UUID
+	StoreID   uuid.NullUUID
+	Kind      string
+	Title     string
+	Body      string
+	Data      json.RawMessage
+	CreatedAt time.Time
+}
+
+type NotificationRead struct {
+	NotificationID uuid.UUID
+	UserID         uuid.UUID
+	ReadAt         time.Time
+}
+
+type OauthState struct {
+	StateHash    string
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+	UserID       uuid.NullUUID
+	CodeHash     sql.NullString
+}
+
+type Order struct {
+	ID                uuid.UUID
+	Gid               sql.NullInt64
+	TenantID          uuid.UUID
+	StoreID           uuid.UUID
+	CustomerID        uuid.NullUUID
+	OrderNumber       int64
+	Email             crypto.NullText
+	Status            string
+	FinancialStatus   string
+	FulfillmentStatus string
+	Currency          string
+	SubtotalCents     int64
+	ShippingCents     int64
+	TaxCents          int64
+	DiscountCents     int64
+	TotalCents        int64
+	PlacedAt          time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	ShippingAddress   crypto.NullJSON
+	BillingAddress    crypto.NullJSON
+	Tags              []string
+	ChannelID         uuid.NullUUID
+	EmailDigest       crypto.Digest
+}
+
+type OrderEvent struct {
+	ID        uuid.UUID
+	OrderID   uuid.UUID
+	StoreID   uuid.UUID
+	Kind      string
+	Message   string
+	Data      json.RawMessage
+	AuthorID  uuid.NullUUID
+	CreatedAt time.Time
+}
+
+type OrderLineItem struct {
+	ID               uuid.UUID
+	OrderID          uuid.UUID
+	ProductID        uuid.NullUUID
+	VariantID        uuid.NullUUID
+	Title            string
+	VariantTitle     sql.NullString
+	Sku              sql.NullString
+	Quantity         int32
+	PriceCents       int64
+	TotalCents       int64
+	CreatedAt        time.Time
+	ParentLineItemID uuid.NullUUID
+}
+
+type OutboxEvent struct {
+	ID          int64
+	Topic       string
+	AggregateID uuid.UUID
+	StoreID     uuid.UUID
+	EventType   string
+	CreatedAt   time.Time
+	ProcessedAt sql.NullTime
+}
+
+type PasswordResetToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    sql.NullTime
+	CreatedAt time.Time
+}
+
 type Permission struct {
 	ID          uuid.UUID
 	Key         string
@@ -37,6 +630,58 @@ type Permission struct {
 	Gid         sql.NullInt64
 }
 
+type PlatformAuditLog struct {
+	ID          uuid.UUID
+	ActorUserID uuid.NullUUID
+	ActorEmail  string
+	Action      string
+	TenantID    uuid.NullUUID
+	Reason      string
+	RequestID   sql.NullString
+	Ip          sql.NullString
+	CreatedAt   time.Time
+}
+
+type PriceList struct {
+	ID        uuid.UUID
+	Gid       sql.NullInt64
+	TenantID  uuid.UUID
+	StoreID   uuid.UUID
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type PriceListEntry struct {
+	PriceListID uuid.UUID
+	VariantID   uuid.UUID
+	PriceCents  sql.NullInt32
+	PercentOff  sql.NullInt32
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type PriceListGroup struct {
+	PriceListID uuid.UUID
+	GroupID     uuid.UUID
+}
+
+type PrivacyRequest struct {
+	ID          uuid.UUID
+	Kind        string
+	StoreID     uuid.NullUUID
+	CustomerID  uuid.NullUUID
+	UserID      uuid.NullUUID
+	RequestedBy uuid.NullUUID
+	Status      string
+	StorageKey  sql.NullString
+	SizeBytes   sql.NullInt64
+	Error       sql.NullString
+	CreatedAt   time.Time
+	StartedAt   sql.NullTime
+	FinishedAt  sql.NullTime
+}
+
 type Product struct {
 	ID               uuid.UUID
 	StoreID          uuid.UUID
@@ -50,6 +695,120 @@ type Product struct {
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
 	Gid              sql.NullInt64
+	DeletedAt        sql.NullTime
+	Version          int64
+	OversellPolicy   string
+}
+
+type ProductFeed struct {
+	ID             uuid.UUID
+	Gid            sql.NullInt64
+	TenantID       uuid.UUID
+	StoreID        uuid.UUID
+	ChannelID      uuid.UUID
+	Name           string
+	Format         string
+	LinkTemplate   string
+	RefreshMinutes int32
+	Token          string
+	NextRunAt      time.Time
+	StorageKey     sql.NullString
+	ItemCount      sql.NullInt32
+	SizeBytes      sql.NullInt64
+	GeneratedAt    sql.NullTime
+	LastError      sql.NullString
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type ProductImport struct {
+	ID         uuid.UUID
+	StoreID    uuid.UUID
+	CreatedBy  uuid.NullUUID
+	Filename   sql.NullString
+	Status     string
+	Data       []byte
+	TotalRows  int32
+	Error      sql.NullString
+	CreatedAt  time.Time
+	StartedAt  sql.NullTime
+	FinishedAt sql.NullTime
+}
+
+type ProductImportRow struct {
+	ImportID  uuid.UUID
+	RowNumber int32
+	Status    string
+	Handle    sql.NullString
+	Sku       sql.NullString
+	ProductID uuid.NullUUID
+	Errors    json.RawMessage
+	CreatedAt time.Time
+}
+
+type ProductMedium struct {
+	ID              uuid.UUID
+	Gid             sql.NullInt64
+	TenantID        uuid.UUID
+	StoreID         uuid.UUID
+	ProductID       uuid.UUID
+	VariantID       uuid.NullUUID
+	StorageKey      string
+	ContentType     string
+	SizeBytes       sql.NullInt64
+	Width           sql.NullInt32
+	Height          sql.NullInt32
+	AltText         sql.NullString
+	Position        int32
+	Status          string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	ThumbnailWidths []int32
+}
+
+type ProductOption struct {
+	ID        uuid.UUID
+	ProductID uuid.UUID
+	Name      string
+	Position  int32
+	Choices   []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type ProductPublication struct {
+	ChannelID   uuid.UUID
+	ProductID   uuid.UUID
+	PublishedAt time.Time
+}
+
+type ProductReview struct {
+	ID               uuid.UUID
+	Gid              sql.NullInt64
+	StoreID          uuid.UUID
+	ProductID        uuid.UUID
+	CustomerID       uuid.NullUUID
+	AuthorName       string
+	Rating           int32
+	Title            sql.NullString
+	Body             string
+	VerifiedPurchase bool
+	Status           string
+	ModeratedBy      uuid.NullUUID
+	ModeratedAt      sql.NullTime
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+type ProductTranslation struct {
+	ProductID   uuid.UUID
+	StoreID     uuid.UUID
+	Locale      string
+	Name        string
+	Description sql.NullString
+	Handle      sql.NullString
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
 type ProductVariant struct {
@@ -67,15 +826,44 @@ type ProductVariant struct {
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 	Gid            sql.NullInt64
+	WeightGrams    sql.NullInt32
+	DeletedAt      sql.NullTime
+	Version        int64
 }
 
 type RefreshToken struct {
-	Token     string
+	TokenHash string
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	UserID    uuid.UUID
 	ExpiresAt time.Time
 	RevokedAt sql.NullTime
+	SessionID uuid.UUID
+	RotatedAt sql.NullTime
+}
+
+type Refund struct {
+	ID                    uuid.UUID
+	Gid                   sql.NullInt64
+	StoreID               uuid.UUID
+	OrderID               uuid.UUID
+	AmountCents           int64
+	Currency              string
+	Reason                sql.NullString
+	Note                  sql.NullString
+	Provider              string
+	ProviderTransactionID sql.NullString
+	CreatedBy             uuid.NullUUID
+	CreatedAt             time.Time
+}
+
+type RefundLineItem struct {
+	RefundID        uuid.UUID
+	OrderLineItemID uuid.UUID
+	Quantity        int32
+	AmountCents     int64
+	Restocked       bool
+	LocationID      uuid.NullUUID
 }
 
 type Role struct {
@@ -86,6 +874,7 @@ type Role struct {
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	Gid         sql.NullInt64
+	IsSystem    bool
 }
 
 type RolePermission struct {
@@ -93,6 +882,137 @@ type RolePermission struct {
 	PermissionID uuid.UUID
 }
 
+type SalesChannel struct {
+	ID          uuid.UUID
+	Gid         sql.NullInt64
+	TenantID    uuid.UUID
+	StoreID     uuid.UUID
+	Kind        string
+	Name        string
+	AutoPublish bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type SecurityEvent struct {
+	ID         uuid.UUID
+	Kind       string
+	UserID     uuid.NullUUID
+	CustomerID uuid.NullUUID
+	StoreID    uuid.NullUUID
+	Email      crypto.NullText
+	Ip         sql.NullString
+	UserAgent  sql.NullString
+	CreatedAt  time.Time
+}
+
+type SellingPlan struct {
+	ID            uuid.UUID
+	Gid           sql.NullInt64
+	TenantID      uuid.UUID
+	StoreID       uuid.UUID
+	Name          string
+	IntervalUnit  string
+	IntervalCount int32
+	PercentOff    int32
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type SellingPlanProduct struct {
+	SellingPlanID uuid.UUID
+	ProductID     uuid.UUID
+}
+
+type Session struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	UserAgent  string
+	IpAddress  string
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	ExpiresAt  time.Time
+	RevokedAt  sql.NullTime
+}
+
+type ShippingRate struct {
+	ID         uuid.UUID
+	ZoneID     uuid.UUID
+	StoreID    uuid.UUID
+	Name       string
+	Kind       string
+	PriceCents int64
+	MinValue   sql.NullInt64
+	MaxValue   sql.NullInt64
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type ShippingZone struct {
+	ID        uuid.UUID
+	Gid       sql.NullInt64
+	TenantID  uuid.UUID
+	StoreID   uuid.UUID
+	Name      string
+	Regions   []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type ShopifyImport struct {
+	ID         uuid.UUID
+	TenantID   uuid.UUID
+	StoreID    uuid.UUID
+	CreatedBy  uuid.NullUUID
+	Entity     string
+	Format     string
+	Filename   sql.NullString
+	Status     string
+	Data       []byte
+	TotalItems int32
+	Error      sql.NullString
+	CreatedAt  time.Time
+	StartedAt  sql.NullTime
+	FinishedAt sql.NullTime
+}
+
+type ShopifyImportItem struct {
+	ImportID  uuid.UUID
+	Position  int32
+	Status    string
+	Reference sql.NullString
+	TargetID  uuid.NullUUID
+	Errors    json.RawMessage
+	CreatedAt time.Time
+}
+
+type SsoConnection struct {
+	ID               uuid.UUID
+	TenantID         uuid.UUID
+	Protocol         string
+	Enabled          bool
+	SamlMetadata     sql.NullString
+	SamlEntityID     sql.NullString
+	SamlSsoUrl       sql.NullString
+	OidcIssuer       sql.NullString
+	OidcClientID     sql.NullString
+	OidcClientSecret crypto.NullText
+	AttributeMapping json.RawMessage
+	DefaultRoleID    uuid.NullUUID
+	JitProvisioning  bool
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+type SsoDomain struct {
+	ID                uuid.UUID
+	TenantID          uuid.UUID
+	Domain            string
+	VerificationToken string
+	VerifiedAt        sql.NullTime
+	CreatedAt         time.Time
+}
+
 type Store struct {
 	ID              uuid.UUID
 	Name            string
@@ -106,6 +1026,25 @@ type Store struct {
 	UpdatedAt       time.Time
 	TenantID        uuid.NullUUID
 	Gid             sql.NullInt64
+	DeletedAt       sql.NullTime
+	Settings        json.RawMessage
+	DefaultLocale   string
+}
+
+type StoreCurrency struct {
+	StoreID   uuid.UUID
+	Currency  string
+	CreatedAt time.Time
+}
+
+type StoreEmailTemplate struct {
+	StoreID   uuid.UUID
+	Template  string
+	Subject   string
+	BodyText  string
+	BodyHtml  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 type StoreMembership struct {
@@ -117,13 +1056,105 @@ type StoreMembership struct {
 	UpdatedAt sql.NullTime
 }
 
+type StorefrontToken struct {
+	ID          uuid.UUID
+	Gid         sql.NullInt64
+	StoreID     uuid.UUID
+	Kind        string
+	Name        string
+	Prefix      string
+	TokenHash   string
+	Scopes      []string
+	CreatedBy   uuid.UUID
+	RotatedFrom uuid.NullUUID
+	LastUsedAt  sql.NullTime
+	LastUsedIp  sql.NullString
+	ExpiresAt   sql.NullTime
+	RevokedAt   sql.NullTime
+	CreatedAt   time.Time
+}
+
+type SubscriptionContract struct {
+	ID               uuid.UUID
+	Gid              sql.NullInt64
+	TenantID         uuid.UUID
+	StoreID          uuid.UUID
+	CustomerID       uuid.UUID
+	SellingPlanID    uuid.NullUUID
+	VariantID        uuid.NullUUID
+	Quantity         int32
+	PriceCents       int64
+	Currency         string
+	IntervalUnit     string
+	IntervalCount    int32
+	PaymentProvider  string
+	PaymentMethodRef string
+	Status           string
+	BillingAnchorAt  time.Time
+	NextCycle        int32
+	NextBillingAt    time.Time
+	FailedAttempts   int32
+	LastFailure      sql.NullString
+	PausedAt         sql.NullTime
+	CancelledAt      sql.NullTime
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+type SubscriptionOrder struct {
+	ContractID uuid.UUID
+	Cycle      int32
+	OrderID    uuid.UUID
+	CreatedAt  time.Time
+}
+
 type Tenant struct {
-	ID        uuid.UUID
-	Name      string
-	Status    string
-	CreatedAt time.Time
+	ID               uuid.UUID
+	Name             string
+	Status           string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Gid              sql.NullInt64
+	RequireMfa       bool
+	SuspendedAt      sql.NullTime
+	SuspensionReason sql.NullString
+	RequireSso       bool
+}
+
+type TenantBilling struct {
+	TenantID             uuid.UUID
+	StripeCustomerID     string
+	StripeSubscriptionID sql.NullString
+	Plan                 string
+	Status               string
+	CurrentPeriodEnd     sql.NullTime
+	CancelAtPeriodEnd    bool
+	GraceUntil           sql.NullTime
+	AppliedPlan          string
+	SyncedAt             sql.NullTime
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+type TenantInvitation struct {
+	ID         uuid.UUID
+	TenantID   uuid.UUID
+	Email      string
+	RoleID     uuid.NullUUID
+	TokenHash  string
+	InvitedBy  uuid.NullUUID
+	ExpiresAt  time.Time
+	AcceptedAt sql.NullTime
+	AcceptedBy uuid.NullUUID
+	RevokedAt  sql.NullTime
+	CreatedAt  time.Time
+}
+
+type TenantNotificationPreference struct {
+	TenantID  uuid.UUID
+	Kind      string
+	Enabled   bool
 	UpdatedAt time.Time
-	Gid       sql.NullInt64
 }
 
 type TenantUser struct {
@@ -140,11 +1171,66 @@ type TenantUserRole struct {
 	RoleID       uuid.UUID
 }
 
+type UsageOverageReport struct {
+	TenantID            uuid.UUID
+	Month               time.Time
+	Metric              string
+	Used                int64
+	Included            int64
+	Units               int64
+	StripeInvoiceItemID sql.NullString
+	CreatedAt           time.Time
+}
+
+type UsageRecord struct {
+	TenantID  uuid.UUID
+	Metric    string
+	Day       time.Time
+	Quantity  int64
+	UpdatedAt time.Time
+}
+
 type User struct {
-	ID             uuid.UUID
-	Email          string
+	ID                 uuid.UUID
+	Email              string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	HashedPassword     string
+	Gid                sql.NullInt64
+	VerifiedAt         sql.NullTime
+	PermissionsVersion int64
+	PlatformRole       sql.NullString
+	ErasedAt           sql.NullTime
+	TokenVersion       int64
+	DisplayName        sql.NullString
+	AvatarKey          sql.NullString
+}
+
+type UserIdentity struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Provider    string
+	Subject     string
+	Email       string
+	CreatedAt   time.Time
+	LastLoginAt sql.NullTime
+}
+
+type UserMfa struct {
+	UserID       uuid.UUID
+	Secret       crypto.Text
+	EnabledAt    sql.NullTime
+	LastUsedStep int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type VariantPrice struct {
+	VariantID      uuid.UUID
+	StoreID        uuid.UUID
+	Currency       string
+	PriceCents     int32
+	CompareAtCents sql.NullInt32
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
-	HashedPassword string
-	Gid            sql.NullInt64
 }
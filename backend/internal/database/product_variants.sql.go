@@ -17,11 +17,11 @@ import (
 
 const countProductVariantsByProductID = `-- name: CountProductVariantsByProductID :one
 SELECT COUNT(*) FROM product_variants
-WHERE product_id = $1
+WHERE product_id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) CountProductVariantsByProductID(ctx context.Context, productID uuid.UUID) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countProductVariantsByProductID, productID)
+	row := q.db.QueryRow(ctx, countProductVariantsByProductID, productID)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -30,12 +30,12 @@ func (q *Queries) CountProductVariantsByProductID(ctx context.Context, productID
 const createProductVariant = `-- name: CreateProductVariant :one
 INSERT INTO product_variants (
     id, gid, tenant_id, store_id, product_id, sku, barcode, title,
-    price_cents, compare_at_cents, option_values, status, created_at, updated_at
+    price_cents, compare_at_cents, option_values, status, weight_grams, created_at, updated_at
 )
 VALUES (
-    gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, now(), now()
+    gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, now(), now()
 )
-RETURNING id, tenant_id, store_id, product_id, sku, barcode, title, price_cents, compare_at_cents, option_values, status, created_at, updated_at, gid
+RETURNING id, tenant_id, store_id, product_id, sku, barcode, title, price_cents, compare_at_cents, option_values, status, created_at, updated_at, gid, weight_grams, deleted_at, version
 `
 
 type CreateProductVariantParams struct {
@@ -50,10 +50,11 @@ type CreateProductVariantParams struct {
 	CompareAtCents sql.NullInt32
 	OptionValues   json.RawMessage
 	Status         string
+	WeightGrams    sql.NullInt32
 }
 
 func (q *Queries) CreateProductVariant(ctx context.Context, arg CreateProductVariantParams) (ProductVariant, error) {
-	row := q.db.QueryRowContext(ctx, createProductVariant,
+	row := q.db.QueryRow(ctx, createProductVariant,
 		arg.Gid,
 		arg.TenantID,
 		arg.StoreID,
@@ -65,6 +66,7 @@ func (q *Queries) CreateProductVariant(ctx context.Context, arg CreateProductVar
 		arg.CompareAtCents,
 		arg.OptionValues,
 		arg.Status,
+		arg.WeightGrams,
 	)
 	var i ProductVariant
 	err := row.Scan(
@@ -82,38 +84,110 @@ func (q *Queries) CreateProductVariant(ctx context.Context, arg CreateProductVar
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Gid,
+		&i.WeightGrams,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
-const deleteProductVariant = `-- name: DeleteProductVariant :exec
+const deleteProductVariantsByProductID = `-- name: DeleteProductVariantsByProductID :exec
 DELETE FROM product_variants
-WHERE id = $1 AND product_id = $2
+WHERE product_id = $1
 `
 
-type DeleteProductVariantParams struct {
-	ID        uuid.UUID
-	ProductID uuid.UUID
+func (q *Queries) DeleteProductVariantsByProductID(ctx context.Context, productID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteProductVariantsByProductID, productID)
+	return err
 }
 
-func (q *Queries) DeleteProductVariant(ctx context.Context, arg DeleteProductVariantParams) error {
-	_, err := q.db.ExecContext(ctx, deleteProductVariant, arg.ID, arg.ProductID)
-	return err
+const getActiveVariantsForQuote = `-- name: GetActiveVariantsForQuote :many
+SELECT pv.id, pv.product_id, pv.price_cents, COALESCE(pv.weight_grams, 0)::integer AS weight_grams
+FROM product_variants pv
+JOIN products p ON p.id = pv.product_id
+WHERE pv.store_id = $1
+  AND pv.id = ANY($2::uuid[])
+  AND pv.status = 'active'
+  AND pv.deleted_at IS NULL
+  AND p.status = 'active'
+  AND p.deleted_at IS NULL
+`
+
+type GetActiveVariantsForQuoteParams struct {
+	StoreID    uuid.UUID
+	VariantIds []uuid.UUID
 }
 
-const deleteProductVariantsByProductID = `-- name: DeleteProductVariantsByProductID :exec
-DELETE FROM product_variants
-WHERE product_id = $1
+type GetActiveVariantsForQuoteRow struct {
+	ID          uuid.UUID
+	ProductID   uuid.UUID
+	PriceCents  int32
+	WeightGrams int32
+}
+
+func (q *Queries) GetActiveVariantsForQuote(ctx context.Context, arg GetActiveVariantsForQuoteParams) ([]GetActiveVariantsForQuoteRow, error) {
+	rows, err := q.db.Query(ctx, getActiveVariantsForQuote, arg.StoreID, arg.VariantIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetActiveVariantsForQuoteRow
+	for rows.Next() {
+		var i GetActiveVariantsForQuoteRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.PriceCents,
+			&i.WeightGrams,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDeletedProductVariant = `-- name: GetDeletedProductVariant :one
+SELECT id, tenant_id, store_id, product_id, sku, barcode, title, price_cents, compare_at_cents, option_values, status, created_at, updated_at, gid, weight_grams, deleted_at, version FROM product_variants
+WHERE id = $1 AND product_id = $2 AND deleted_at IS NOT NULL
 `
 
-func (q *Queries) DeleteProductVariantsByProductID(ctx context.Context, productID uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteProductVariantsByProductID, productID)
-	return err
+type GetDeletedProductVariantParams struct {
+	ID        uuid.UUID
+	ProductID uuid.UUID
+}
+
+func (q *Queries) GetDeletedProductVariant(ctx context.Context, arg GetDeletedProductVariantParams) (ProductVariant, error) {
+	row := q.db.QueryRow(ctx, getDeletedProductVariant, arg.ID, arg.ProductID)
+	var i ProductVariant
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.StoreID,
+		&i.ProductID,
+		&i.Sku,
+		&i.Barcode,
+		&i.Title,
+		&i.PriceCents,
+		&i.CompareAtCents,
+		&i.OptionValues,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Gid,
+		&i.WeightGrams,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
 }
 
 const getProductVariantByBarcode = `-- name: GetProductVariantByBarcode :one
-SELECT id, tenant_id, store_id, product_id, sku, barcode, title, price_cents, compare_at_cents, option_values, status, created_at, updated_at, gid FROM product_variants
-WHERE store_id = $1 AND barcode = $2
+SELECT id, tenant_id, store_id, product_id, sku, barcode, title, price_cents, compare_at_cents, option_values, status, created_at, updated_at, gid, weight_grams, deleted_at, version FROM product_variants
+WHERE store_id = $1 AND barcode = $2 AND deleted_at IS NULL
 `
 
 type GetProductVariantByBarcodeParams struct {
@@ -122,7 +196,7 @@ type GetProductVariantByBarcodeParams struct {
 }
 
 func (q *Queries) GetProductVariantByBarcode(ctx context.Context, arg GetProductVariantByBarcodeParams) (ProductVariant, error) {
-	row := q.db.QueryRowContext(ctx, getProductVariantByBarcode, arg.StoreID, arg.Barcode)
+	row := q.db.QueryRow(ctx, getProductVariantByBarcode, arg.StoreID, arg.Barcode)
 	var i ProductVariant
 	err := row.Scan(
 		&i.ID,
@@ -139,17 +213,20 @@ func (q *Queries) GetProductVariantByBarcode(ctx context.Context, arg GetProduct
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Gid,
+		&i.WeightGrams,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
 const getProductVariantByGID = `-- name: GetProductVariantByGID :one
-SELECT id, tenant_id, store_id, product_id, sku, barcode, title, price_cents, compare_at_cents, option_values, status, created_at, updated_at, gid FROM product_variants
-WHERE gid = $1
+SELECT id, tenant_id, store_id, product_id, sku, barcode, title, price_cents, compare_at_cents, option_values, status, created_at, updated_at, gid, weight_grams, deleted_at, version FROM product_variants
+WHERE gid = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetProductVariantByGID(ctx context.Context, gid sql.NullInt64) (ProductVariant, error) {
-	row := q.db.QueryRowContext(ctx, getProductVariantByGID, gid)
+	row := q.db.QueryRow(ctx, getProductVariantByGID, gid)
 	var i ProductVariant
 	err := row.Scan(
 		&i.ID,
@@ -166,17 +243,20 @@ func (q *Queries) GetProductVariantByGID(ctx context.Context, gid sql.NullInt64)
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Gid,
+		&i.WeightGrams,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
 const getProductVariantByID = `-- name: GetProductVariantByID :one
-SELECT id, tenant_id, store_id, product_id, sku, barcode, title, price_cents, compare_at_cents, option_values, status, created_at, updated_at, gid FROM product_variants
-WHERE id = $1
+SELECT id, tenant_id, store_id, product_id, sku, barcode, title, price_cents, compare_at_cents, option_values, status, created_at, updated_at, gid, weight_grams, deleted_at, version FROM product_variants
+WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetProductVariantByID(ctx context.Context, id uuid.UUID) (ProductVariant, error) {
-	row := q.db.QueryRowContext(ctx, getProductVariantByID, id)
+	row := q.db.QueryRow(ctx, getProductVariantByID, id)
 	var i ProductVariant
 	err := row.Scan(
 		&i.ID,
@@ -193,13 +273,16 @@ func (q *Queries) GetProductVariantByID(ctx context.Context, id uuid.UUID) (Prod
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Gid,
+		&i.WeightGrams,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
 const getProductVariantBySKU = `-- name: GetProductVariantBySKU :one
-SELECT id, tenant_id, store_id, product_id, sku, barcode, title, price_cents, compare_at_cents, option_values, status, created_at, updated_at, gid FROM product_variants
-WHERE store_id = $1 AND sku = $2
+SELECT id, tenant_id, store_id, product_id, sku, barcode, title, price_cents, compare_at_cents, option_values, status, created_at, updated_at, gid, weight_grams, deleted_at, version FROM product_variants
+WHERE store_id = $1 AND sku = $2 AND deleted_at IS NULL
 `
 
 type GetProductVariantBySKUParams struct {
@@ -208,7 +291,7 @@ type GetProductVariantBySKUParams struct {
 }
 
 func (q *Queries) GetProductVariantBySKU(ctx context.Context, arg GetProductVariantBySKUParams) (ProductVariant, error) {
-	row := q.db.QueryRowContext(ctx, getProductVariantBySKU, arg.StoreID, arg.Sku)
+	row := q.db.QueryRow(ctx, getProductVariantBySKU, arg.StoreID, arg.Sku)
 	var i ProductVariant
 	err := row.Scan(
 		&i.ID,
@@ -225,18 +308,21 @@ func (q *Queries) GetProductVariantBySKU(ctx context.Context, arg GetProductVari
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Gid,
+		&i.WeightGrams,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
 const getProductVariantsByProductID = `-- name: GetProductVariantsByProductID :many
-SELECT id, tenant_id, store_id, product_id, sku, barcode, title, price_cents, compare_at_cents, option_values, status, created_at, updated_at, gid FROM product_variants
-WHERE product_id = $1
+SELECT id, tenant_id, store_id, product_id, sku, barcode, title, price_cents, compare_at_cents, option_values, status, created_at, updated_at, gid, weight_grams, deleted_at, version FROM product_variants
+WHERE product_id = $1 AND deleted_at IS NULL
 ORDER BY created_at ASC
 `
 
 func (q *Queries) GetProductVariantsByProductID(ctx context.Context, productID uuid.UUID) ([]ProductVariant, error) {
-	rows, err := q.db.QueryContext(ctx, getProductVariantsByProductID, productID)
+	rows, err := q.db.Query(ctx, getProductVariantsByProductID, productID)
 	if err != nil {
 		return nil, err
 	}
@@ -259,14 +345,14 @@ func (q *Queries) GetProductVariantsByProductID(ctx context.Context, productID u
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Gid,
+			&i.WeightGrams,
+			&i.DeletedAt,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -275,9 +361,10 @@ func (q *Queries) GetProductVariantsByProductID(ctx context.Context, productID u
 
 const getProductVariantsByProductIDPaginated = `-- name: GetProductVariantsByProductIDPaginated :many
 SELECT id, gid, tenant_id, store_id, product_id, sku, barcode, title,
-       price_cents, compare_at_cents, option_values, status, created_at, updated_at
+       price_cents, compare_at_cents, option_values, status, weight_grams, created_at, updated_at, version
 FROM product_variants
 WHERE product_id = $1
+  AND deleted_at IS NULL
   AND (
     $2::boolean = false
     OR (created_at, id) < ($3::timestamptz, $4::uuid)
@@ -287,11 +374,11 @@ LIMIT $5
 `
 
 type GetProductVariantsByProductIDPaginatedParams struct {
-	ProductID uuid.UUID
-	Column2   bool
-	Column3   time.Time
-	Column4   uuid.UUID
-	Limit     int32
+	ProductID       uuid.UUID
+	HasCursor       bool
+	CursorCreatedAt time.Time
+	CursorID        uuid.UUID
+	RowLimit        int32
 }
 
 type GetProductVariantsByProductIDPaginatedRow struct {
@@ -307,17 +394,19 @@ type GetProductVariantsByProductIDPaginatedRow struct {
 	CompareAtCents sql.NullInt32
 	OptionValues   json.RawMessage
 	Status         string
+	WeightGrams    sql.NullInt32
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
+	Version        int64
 }
 
 func (q *Queries) GetProductVariantsByProductIDPaginated(ctx context.Context, arg GetProductVariantsByProductIDPaginatedParams) ([]GetProductVariantsByProductIDPaginatedRow, error) {
-	rows, err := q.db.QueryContext(ctx, getProductVariantsByProductIDPaginated,
+	rows, err := q.db.Query(ctx, getProductVariantsByProductIDPaginated,
 		arg.ProductID,
-		arg.Column2,
-		arg.Column3,
-		arg.Column4,
-		arg.Limit,
+		arg.HasCursor,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.RowLimit,
 	)
 	if err != nil {
 		return nil, err
@@ -339,16 +428,15 @@ func (q *Queries) GetProductVariantsByProductIDPaginated(ctx context.Context, ar
 			&i.CompareAtCents,
 			&i.OptionValues,
 			&i.Status,
+			&i.WeightGrams,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -356,13 +444,13 @@ func (q *Queries) GetProductVariantsByProductIDPaginated(ctx context.Context, ar
 }
 
 const getProductVariantsByStoreID = `-- name: GetProductVariantsByStoreID :many
-SELECT id, tenant_id, store_id, product_id, sku, barcode, title, price_cents, compare_at_cents, option_values, status, created_at, updated_at, gid FROM product_variants
-WHERE store_id = $1
+SELECT id, tenant_id, store_id, product_id, sku, barcode, title, price_cents, compare_at_cents, option_values, status, created_at, updated_at, gid, weight_grams, deleted_at, version FROM product_variants
+WHERE store_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
 `
 
 func (q *Queries) GetProductVariantsByStoreID(ctx context.Context, storeID uuid.UUID) ([]ProductVariant, error) {
-	rows, err := q.db.QueryContext(ctx, getProductVariantsByStoreID, storeID)
+	rows, err := q.db.Query(ctx, getProductVariantsByStoreID, storeID)
 	if err != nil {
 		return nil, err
 	}
@@ -385,14 +473,14 @@ func (q *Queries) GetProductVariantsByStoreID(ctx context.Context, storeID uuid.
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Gid,
+			&i.WeightGrams,
+			&i.DeletedAt,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -426,8 +514,8 @@ SELECT
     pv.created_at as variant_created_at,
     pv.updated_at as variant_updated_at
 FROM products p
-LEFT JOIN product_variants pv ON p.id = pv.product_id
-WHERE p.id = $1 AND p.store_id = $2
+LEFT JOIN product_variants pv ON p.id = pv.product_id AND pv.deleted_at IS NULL
+WHERE p.id = $1 AND p.store_id = $2 AND p.deleted_at IS NULL
 `
 
 type GetProductWithVariantsParams struct {
@@ -463,7 +551,7 @@ type GetProductWithVariantsRow struct {
 }
 
 func (q *Queries) GetProductWithVariants(ctx context.Context, arg GetProductWithVariantsParams) ([]GetProductWithVariantsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getProductWithVariants, arg.ID, arg.StoreID)
+	rows, err := q.db.Query(ctx, getProductWithVariants, arg.ID, arg.StoreID)
 	if err != nil {
 		return nil, err
 	}
@@ -501,16 +589,201 @@ func (q *Queries) GetProductWithVariants(ctx context.Context, arg GetProductWith
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+	return items, nil
+}
+
+const listDeletedProductVariants = `-- name: ListDeletedProductVariants :many
+SELECT id, tenant_id, store_id, product_id, sku, barcode, title, price_cents, compare_at_cents, option_values, status, created_at, updated_at, gid, weight_grams, deleted_at, version FROM product_variants
+WHERE product_id = $1
+  AND deleted_at IS NOT NULL
+  AND (
+    NOT $2::boolean
+    OR (deleted_at, id) < ($3::timestamptz, $4::uuid)
+  )
+ORDER BY deleted_at DESC, id DESC
+LIMIT $5
+`
+
+type ListDeletedProductVariantsParams struct {
+	ProductID       uuid.UUID
+	HasCursor       bool
+	CursorDeletedAt time.Time
+	CursorID        uuid.UUID
+	RowLimit        int32
+}
+
+// Most recently deleted first
+func (q *Queries) ListDeletedProductVariants(ctx context.Context, arg ListDeletedProductVariantsParams) ([]ProductVariant, error) {
+	rows, err := q.db.Query(ctx, listDeletedProductVariants,
+		arg.ProductID,
+		arg.HasCursor,
+		arg.CursorDeletedAt,
+		arg.CursorID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProductVariant
+	for rows.Next() {
+		var i ProductVariant
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.StoreID,
+			&i.ProductID,
+			&i.Sku,
+			&i.Barcode,
+			&i.Title,
+			&i.PriceCents,
+			&i.CompareAtCents,
+			&i.OptionValues,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Gid,
+			&i.WeightGrams,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 	return items, nil
 }
 
-const updateProductVariant = `-- name: UpdateProductVariant :one
+const lookupVariantByCode = `-- name: LookupVariantByCode :many
+SELECT
+    pv.id,
+    pv.product_id,
+    pv.sku,
+    pv.barcode,
+    pv.title,
+    pv.price_cents,
+    pv.compare_at_cents,
+    pv.status,
+    p.name AS product_name,
+    p.status AS product_status,
+    p.inventory_tracked,
+    s.default_currency AS currency,
+    (pv.barcode IS NOT NULL AND pv.barcode = $1::text)::boolean AS barcode_match,
+    COALESCE(ii.on_hand, 0)::integer AS on_hand,
+    COALESCE(ii.reserved, 0)::integer AS reserved,
+    il.on_hand AS location_on_hand
+FROM product_variants pv
+JOIN products p ON p.id = pv.product_id
+JOIN stores s ON s.id = pv.store_id
+LEFT JOIN inventory_items ii ON ii.variant_id = pv.id
+LEFT JOIN inventory_levels il ON il.inventory_item_id = ii.id AND il.location_id = $2::uuid
+WHERE pv.store_id = $3
+  AND pv.deleted_at IS NULL
+  AND p.deleted_at IS NULL
+  AND (
+    (pv.barcode = $1::text AND pv.barcode IS NOT NULL)
+    OR (lower(pv.sku) = lower($1::text) AND pv.sku IS NOT NULL)
+  )
+ORDER BY barcode_match DESC, pv.id
+LIMIT 2
+`
+
+type LookupVariantByCodeParams struct {
+	Code       string
+	LocationID uuid.NullUUID
+	StoreID    uuid.UUID
+}
+
+type LookupVariantByCodeRow struct {
+	ID               uuid.UUID
+	ProductID        uuid.UUID
+	Sku              sql.NullString
+	Barcode          sql.NullString
+	Title            string
+	PriceCents       int32
+	CompareAtCents   sql.NullInt32
+	Status           string
+	ProductName      string
+	ProductStatus    string
+	InventoryTracked bool
+	Currency         string
+	BarcodeMatch     bool
+	OnHand           int32
+	Reserved         int32
+	LocationOnHand   sql.NullInt32
+}
+
+// Variants whose barcode is code or whose SKU is code in any case, barcode
+// matches first, with their price and stock. location_id adds the stock at
+// that location.
+func (q *Queries) LookupVariantByCode(ctx context.Context, arg LookupVariantByCodeParams) ([]LookupVariantByCodeRow, error) {
+	rows, err := q.db.Query(ctx, lookupVariantByCode, arg.Code, arg.LocationID, arg.StoreID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LookupVariantByCodeRow
+	for rows.Next() {
+		var i LookupVariantByCodeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.Sku,
+			&i.Barcode,
+			&i.Title,
+			&i.PriceCents,
+			&i.CompareAtCents,
+			&i.Status,
+			&i.ProductName,
+			&i.ProductStatus,
+			&i.InventoryTracked,
+			&i.Currency,
+			&i.BarcodeMatch,
+			&i.OnHand,
+			&i.Reserved,
+			&i.LocationOnHand,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeDeletedProductVariants = `-- name: PurgeDeletedProductVariants :execrows
+DELETE FROM product_variants
+WHERE id IN (
+    SELECT id FROM product_variants
+    WHERE deleted_at < $1::timestamptz
+    ORDER BY deleted_at
+    LIMIT $2
+)
+`
+
+type PurgeDeletedProductVariantsParams struct {
+	Before   time.Time
+	RowLimit int32
+}
+
+// Permanently removes up to row_limit variants deleted before the cutoff
+func (q *Queries) PurgeDeletedProductVariants(ctx context.Context, arg PurgeDeletedProductVariantsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedProductVariants, arg.Before, arg.RowLimit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const replaceProductVariant = `-- name: ReplaceProductVariant :one
 UPDATE product_variants
 SET
     sku = $3,
@@ -520,12 +793,13 @@ SET
     compare_at_cents = $7,
     option_values = $8,
     status = $9,
+    weight_grams = $10,
     updated_at = now()
-WHERE id = $1 AND product_id = $2
-RETURNING id, tenant_id, store_id, product_id, sku, barcode, title, price_cents, compare_at_cents, option_values, status, created_at, updated_at, gid
+WHERE id = $1 AND product_id = $2 AND deleted_at IS NULL
+RETURNING id, tenant_id, store_id, product_id, sku, barcode, title, price_cents, compare_at_cents, option_values, status, created_at, updated_at, gid, weight_grams, deleted_at, version
 `
 
-type UpdateProductVariantParams struct {
+type ReplaceProductVariantParams struct {
 	ID             uuid.UUID
 	ProductID      uuid.UUID
 	Sku            sql.NullString
@@ -535,10 +809,13 @@ type UpdateProductVariantParams struct {
 	CompareAtCents sql.NullInt32
 	OptionValues   json.RawMessage
 	Status         string
+	WeightGrams    sql.NullInt32
 }
 
-func (q *Queries) UpdateProductVariant(ctx context.Context, arg UpdateProductVariantParams) (ProductVariant, error) {
-	row := q.db.QueryRowContext(ctx, updateProductVariant,
+// Overwrites every field, clearing those left null, for writers that hold
+// the whole variant such as imports
+func (q *Queries) ReplaceProductVariant(ctx context.Context, arg ReplaceProductVariantParams) (ProductVariant, error) {
+	row := q.db.QueryRow(ctx, replaceProductVariant,
 		arg.ID,
 		arg.ProductID,
 		arg.Sku,
@@ -548,6 +825,156 @@ func (q *Queries) UpdateProductVariant(ctx context.Context, arg UpdateProductVar
 		arg.CompareAtCents,
 		arg.OptionValues,
 		arg.Status,
+		arg.WeightGrams,
+	)
+	var i ProductVariant
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.StoreID,
+		&i.ProductID,
+		&i.Sku,
+		&i.Barcode,
+		&i.Title,
+		&i.PriceCents,
+		&i.CompareAtCents,
+		&i.OptionValues,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Gid,
+		&i.WeightGrams,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const restoreProductVariant = `-- name: RestoreProductVariant :one
+UPDATE product_variants
+SET deleted_at = NULL, updated_at = now()
+WHERE id = $1 AND product_id = $2 AND deleted_at IS NOT NULL
+  AND EXISTS (SELECT 1 FROM products p WHERE p.id = $2 AND p.deleted_at IS NULL)
+RETURNING id, tenant_id, store_id, product_id, sku, barcode, title, price_cents, compare_at_cents, option_values, status, created_at, updated_at, gid, weight_grams, deleted_at, version
+`
+
+type RestoreProductVariantParams struct {
+	ID        uuid.UUID
+	ProductID uuid.UUID
+}
+
+// A variant of a deleted product comes back with its product, not alone
+func (q *Queries) RestoreProductVariant(ctx context.Context, arg RestoreProductVariantParams) (ProductVariant, error) {
+	row := q.db.QueryRow(ctx, restoreProductVariant, arg.ID, arg.ProductID)
+	var i ProductVariant
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.StoreID,
+		&i.ProductID,
+		&i.Sku,
+		&i.Barcode,
+		&i.Title,
+		&i.PriceCents,
+		&i.CompareAtCents,
+		&i.OptionValues,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Gid,
+		&i.WeightGrams,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const softDeleteProductVariant = `-- name: SoftDeleteProductVariant :one
+UPDATE product_variants
+SET deleted_at = now()
+WHERE id = $1 AND product_id = $2 AND deleted_at IS NULL
+RETURNING id, tenant_id, store_id, product_id, sku, barcode, title, price_cents, compare_at_cents, option_values, status, created_at, updated_at, gid, weight_grams, deleted_at, version
+`
+
+type SoftDeleteProductVariantParams struct {
+	ID        uuid.UUID
+	ProductID uuid.UUID
+}
+
+func (q *Queries) SoftDeleteProductVariant(ctx context.Context, arg SoftDeleteProductVariantParams) (ProductVariant, error) {
+	row := q.db.QueryRow(ctx, softDeleteProductVariant, arg.ID, arg.ProductID)
+	var i ProductVariant
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.StoreID,
+		&i.ProductID,
+		&i.Sku,
+		&i.Barcode,
+		&i.Title,
+		&i.PriceCents,
+		&i.CompareAtCents,
+		&i.OptionValues,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Gid,
+		&i.WeightGrams,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const updateProductVariant = `-- name: UpdateProductVariant :one
+UPDATE product_variants
+SET
+    sku = COALESCE($1::text, sku),
+    barcode = COALESCE($2::text, barcode),
+    title = COALESCE($3::text, title),
+    price_cents = COALESCE($4::integer, price_cents),
+    compare_at_cents = COALESCE($5::integer, compare_at_cents),
+    option_values = COALESCE($6::jsonb, option_values),
+    status = COALESCE($7::text, status),
+    weight_grams = COALESCE($8::integer, weight_grams),
+    updated_at = now()
+WHERE id = $9 AND product_id = $10 AND store_id = $11
+  AND deleted_at IS NULL
+  AND ($12::bigint IS NULL OR version = $12::bigint)
+RETURNING id, tenant_id, store_id, product_id, sku, barcode, title, price_cents, compare_at_cents, option_values, status, created_at, updated_at, gid, weight_grams, deleted_at, version
+`
+
+type UpdateProductVariantParams struct {
+	Sku            sql.NullString
+	Barcode        sql.NullString
+	Title          sql.NullString
+	PriceCents     sql.NullInt32
+	CompareAtCents sql.NullInt32
+	OptionValues   pqtype.NullRawMessage
+	Status         sql.NullString
+	WeightGrams    sql.NullInt32
+	ID             uuid.UUID
+	ProductID      uuid.UUID
+	StoreID        uuid.UUID
+	Version        sql.NullInt64
+}
+
+// Sets only the fields given and keeps the rest, in one statement. With a
+// version it only applies while the variant still has that version.
+func (q *Queries) UpdateProductVariant(ctx context.Context, arg UpdateProductVariantParams) (ProductVariant, error) {
+	row := q.db.QueryRow(ctx, updateProductVariant,
+		arg.Sku,
+		arg.Barcode,
+		arg.Title,
+		arg.PriceCents,
+		arg.CompareAtCents,
+		arg.OptionValues,
+		arg.Status,
+		arg.WeightGrams,
+		arg.ID,
+		arg.ProductID,
+		arg.StoreID,
+		arg.Version,
 	)
 	var i ProductVariant
 	err := row.Scan(
@@ -565,6 +992,9 @@ func (q *Queries) UpdateProductVariant(ctx context.Context, arg UpdateProductVar
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Gid,
+		&i.WeightGrams,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -13,10 +13,276 @@ import (
 	"github.com/google/uuid"
 )
 
+const countFilteredProductsByAvailability = `-- name: CountFilteredProductsByAvailability :one
+SELECT
+    COUNT(*) FILTER (WHERE (
+    NOT p.inventory_tracked
+    OR EXISTS (
+        SELECT 1 FROM product_variants sv
+        JOIN inventory_items ii ON ii.variant_id = sv.id
+        WHERE sv.product_id = p.id AND sv.status = 'active' AND sv.deleted_at IS NULL AND ii.on_hand > 0
+    )
+))::bigint AS in_stock,
+    COUNT(*) FILTER (WHERE NOT (
+    NOT p.inventory_tracked
+    OR EXISTS (
+        SELECT 1 FROM product_variants sv
+        JOIN inventory_items ii ON ii.variant_id = sv.id
+        WHERE sv.product_id = p.id AND sv.status = 'active' AND sv.deleted_at IS NULL AND ii.on_hand > 0
+    )
+))::bigint AS out_of_stock
+FROM products p
+WHERE p.store_id = $1
+  AND p.deleted_at IS NULL
+  AND (COALESCE(cardinality($2::text[]), 0) = 0 OR p.status = ANY($2::text[]))
+  AND product_tags(p.tags) @> COALESCE($3::text[], '{}')
+  AND (
+    ($4::bigint IS NULL AND $5::bigint IS NULL)
+    OR EXISTS (
+        SELECT 1 FROM product_variants pv
+        WHERE pv.product_id = p.id AND pv.status = 'active' AND pv.deleted_at IS NULL
+          AND ($4::bigint IS NULL OR pv.price_cents >= $4::bigint)
+          AND ($5::bigint IS NULL OR pv.price_cents <= $5::bigint)
+    )
+  )
+  AND (
+    $6::boolean IS NULL
+    OR $6::boolean = (
+        NOT p.inventory_tracked
+        OR EXISTS (
+            SELECT 1 FROM product_variants sv
+            JOIN inventory_items ii ON ii.variant_id = sv.id
+            WHERE sv.product_id = p.id AND sv.status = 'active' AND sv.deleted_at IS NULL AND ii.on_hand > 0
+        )
+    )
+  )
+  AND ($7::timestamptz IS NULL OR p.created_at >= $7::timestamptz)
+  AND ($8::timestamptz IS NULL OR p.created_at < $8::timestamptz)
+  AND ($9::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM product_publications pub
+    WHERE pub.product_id = p.id AND pub.channel_id = $9::uuid
+  ))
+`
+
+type CountFilteredProductsByAvailabilityParams struct {
+	StoreID       uuid.UUID
+	Statuses      []string
+	Tags          []string
+	MinPriceCents sql.NullInt64
+	MaxPriceCents sql.NullInt64
+	InStock       sql.NullBool
+	CreatedAfter  sql.NullTime
+	CreatedBefore sql.NullTime
+	ChannelID     uuid.NullUUID
+}
+
+type CountFilteredProductsByAvailabilityRow struct {
+	InStock    int64
+	OutOfStock int64
+}
+
+func (q *Queries) CountFilteredProductsByAvailability(ctx context.Context, arg CountFilteredProductsByAvailabilityParams) (CountFilteredProductsByAvailabilityRow, error) {
+	row := q.db.QueryRow(ctx, countFilteredProductsByAvailability,
+		arg.StoreID,
+		arg.Statuses,
+		arg.Tags,
+		arg.MinPriceCents,
+		arg.MaxPriceCents,
+		arg.InStock,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.ChannelID,
+	)
+	var i CountFilteredProductsByAvailabilityRow
+	err := row.Scan(
+		&i.InStock,
+		&i.OutOfStock,
+	)
+	return i, err
+}
+
+const countFilteredProductsByStatus = `-- name: CountFilteredProductsByStatus :many
+SELECT p.status, COUNT(*) AS count
+FROM products p
+WHERE p.store_id = $1
+  AND p.deleted_at IS NULL
+  AND (COALESCE(cardinality($2::text[]), 0) = 0 OR p.status = ANY($2::text[]))
+  AND product_tags(p.tags) @> COALESCE($3::text[], '{}')
+  AND (
+    ($4::bigint IS NULL AND $5::bigint IS NULL)
+    OR EXISTS (
+        SELECT 1 FROM product_variants pv
+        WHERE pv.product_id = p.id AND pv.status = 'active' AND pv.deleted_at IS NULL
+          AND ($4::bigint IS NULL OR pv.price_cents >= $4::bigint)
+          AND ($5::bigint IS NULL OR pv.price_cents <= $5::bigint)
+    )
+  )
+  AND (
+    $6::boolean IS NULL
+    OR $6::boolean = (
+        NOT p.inventory_tracked
+        OR EXISTS (
+            SELECT 1 FROM product_variants sv
+            JOIN inventory_items ii ON ii.variant_id = sv.id
+            WHERE sv.product_id = p.id AND sv.status = 'active' AND sv.deleted_at IS NULL AND ii.on_hand > 0
+        )
+    )
+  )
+  AND ($7::timestamptz IS NULL OR p.created_at >= $7::timestamptz)
+  AND ($8::timestamptz IS NULL OR p.created_at < $8::timestamptz)
+  AND ($9::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM product_publications pub
+    WHERE pub.product_id = p.id AND pub.channel_id = $9::uuid
+  ))
+GROUP BY p.status
+ORDER BY p.status
+`
+
+type CountFilteredProductsByStatusParams struct {
+	StoreID       uuid.UUID
+	Statuses      []string
+	Tags          []string
+	MinPriceCents sql.NullInt64
+	MaxPriceCents sql.NullInt64
+	InStock       sql.NullBool
+	CreatedAfter  sql.NullTime
+	CreatedBefore sql.NullTime
+	ChannelID     uuid.NullUUID
+}
+
+type CountFilteredProductsByStatusRow struct {
+	Status string
+	Count  int64
+}
+
+func (q *Queries) CountFilteredProductsByStatus(ctx context.Context, arg CountFilteredProductsByStatusParams) ([]CountFilteredProductsByStatusRow, error) {
+	rows, err := q.db.Query(ctx, countFilteredProductsByStatus,
+		arg.StoreID,
+		arg.Statuses,
+		arg.Tags,
+		arg.MinPriceCents,
+		arg.MaxPriceCents,
+		arg.InStock,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.ChannelID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountFilteredProductsByStatusRow
+	for rows.Next() {
+		var i CountFilteredProductsByStatusRow
+		if err := rows.Scan(
+			&i.Status,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countFilteredProductsByTag = `-- name: CountFilteredProductsByTag :many
+SELECT tag::text AS tag, COUNT(*) AS count
+FROM products p
+CROSS JOIN LATERAL unnest(product_tags(p.tags)) AS tag
+WHERE p.store_id = $1
+  AND p.deleted_at IS NULL
+  AND (COALESCE(cardinality($2::text[]), 0) = 0 OR p.status = ANY($2::text[]))
+  AND product_tags(p.tags) @> COALESCE($3::text[], '{}')
+  AND (
+    ($4::bigint IS NULL AND $5::bigint IS NULL)
+    OR EXISTS (
+        SELECT 1 FROM product_variants pv
+        WHERE pv.product_id = p.id AND pv.status = 'active' AND pv.deleted_at IS NULL
+          AND ($4::bigint IS NULL OR pv.price_cents >= $4::bigint)
+          AND ($5::bigint IS NULL OR pv.price_cents <= $5::bigint)
+    )
+  )
+  AND (
+    $6::boolean IS NULL
+    OR $6::boolean = (
+        NOT p.inventory_tracked
+        OR EXISTS (
+            SELECT 1 FROM product_variants sv
+            JOIN inventory_items ii ON ii.variant_id = sv.id
+            WHERE sv.product_id = p.id AND sv.status = 'active' AND sv.deleted_at IS NULL AND ii.on_hand > 0
+        )
+    )
+  )
+  AND ($7::timestamptz IS NULL OR p.created_at >= $7::timestamptz)
+  AND ($8::timestamptz IS NULL OR p.created_at < $8::timestamptz)
+  AND ($9::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM product_publications pub
+    WHERE pub.product_id = p.id AND pub.channel_id = $9::uuid
+  ))
+GROUP BY tag
+ORDER BY count DESC, tag ASC
+LIMIT $10
+`
+
+type CountFilteredProductsByTagParams struct {
+	StoreID       uuid.UUID
+	Statuses      []string
+	Tags          []string
+	MinPriceCents sql.NullInt64
+	MaxPriceCents sql.NullInt64
+	InStock       sql.NullBool
+	CreatedAfter  sql.NullTime
+	CreatedBefore sql.NullTime
+	ChannelID     uuid.NullUUID
+	TagLimit      int32
+}
+
+type CountFilteredProductsByTagRow struct {
+	Tag   string
+	Count int64
+}
+
+func (q *Queries) CountFilteredProductsByTag(ctx context.Context, arg CountFilteredProductsByTagParams) ([]CountFilteredProductsByTagRow, error) {
+	rows, err := q.db.Query(ctx, countFilteredProductsByTag,
+		arg.StoreID,
+		arg.Statuses,
+		arg.Tags,
+		arg.MinPriceCents,
+		arg.MaxPriceCents,
+		arg.InStock,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.ChannelID,
+		arg.TagLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountFilteredProductsByTagRow
+	for rows.Next() {
+		var i CountFilteredProductsByTagRow
+		if err := rows.Scan(
+			&i.Tag,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createProduct = `-- name: CreateProduct :one
 INSERT INTO products (id, gid, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at)
 VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
-RETURNING id, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at, gid
+RETURNING id, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at, gid, deleted_at, version, oversell_policy
 `
 
 type CreateProductParams struct {
@@ -32,7 +298,7 @@ type CreateProductParams struct {
 }
 
 func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (Product, error) {
-	row := q.db.QueryRowContext(ctx, createProduct,
+	row := q.db.QueryRow(ctx, createProduct,
 		arg.Gid,
 		arg.StoreID,
 		arg.Handle,
@@ -57,23 +323,25 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (P
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Gid,
+		&i.DeletedAt,
+		&i.Version,
+		&i.OversellPolicy,
 	)
 	return i, err
 }
 
-const deleteProduct = `-- name: DeleteProduct :one
-DELETE FROM products
-WHERE id = $1 AND store_id = $2
-RETURNING id, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at, gid
+const getDeletedProduct = `-- name: GetDeletedProduct :one
+SELECT id, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at, gid, deleted_at, version, oversell_policy FROM products
+WHERE id = $1 AND store_id = $2 AND deleted_at IS NOT NULL
 `
 
-type DeleteProductParams struct {
+type GetDeletedProductParams struct {
 	ID      uuid.UUID
 	StoreID uuid.UUID
 }
 
-func (q *Queries) DeleteProduct(ctx context.Context, arg DeleteProductParams) (Product, error) {
-	row := q.db.QueryRowContext(ctx, deleteProduct, arg.ID, arg.StoreID)
+func (q *Queries) GetDeletedProduct(ctx context.Context, arg GetDeletedProductParams) (Product, error) {
+	row := q.db.QueryRow(ctx, getDeletedProduct, arg.ID, arg.StoreID)
 	var i Product
 	err := row.Scan(
 		&i.ID,
@@ -88,17 +356,99 @@ func (q *Queries) DeleteProduct(ctx context.Context, arg DeleteProductParams) (P
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Gid,
+		&i.DeletedAt,
+		&i.Version,
+		&i.OversellPolicy,
+	)
+	return i, err
+}
+
+const getFilteredProductsPriceRange = `-- name: GetFilteredProductsPriceRange :one
+SELECT
+    COALESCE(MIN(v.price_cents), 0)::bigint AS min_price_cents,
+    COALESCE(MAX(v.price_cents), 0)::bigint AS max_price_cents,
+    COUNT(v.id)::bigint AS variant_count
+FROM products p
+JOIN product_variants v ON v.product_id = p.id AND v.status = 'active' AND v.deleted_at IS NULL
+WHERE p.store_id = $1
+  AND p.deleted_at IS NULL
+  AND (COALESCE(cardinality($2::text[]), 0) = 0 OR p.status = ANY($2::text[]))
+  AND product_tags(p.tags) @> COALESCE($3::text[], '{}')
+  AND (
+    ($4::bigint IS NULL AND $5::bigint IS NULL)
+    OR EXISTS (
+        SELECT 1 FROM product_variants pv
+        WHERE pv.product_id = p.id AND pv.status = 'active' AND pv.deleted_at IS NULL
+          AND ($4::bigint IS NULL OR pv.price_cents >= $4::bigint)
+          AND ($5::bigint IS NULL OR pv.price_cents <= $5::bigint)
+    )
+  )
+  AND (
+    $6::boolean IS NULL
+    OR $6::boolean = (
+        NOT p.inventory_tracked
+        OR EXISTS (
+            SELECT 1 FROM product_variants sv
+            JOIN inventory_items ii ON ii.variant_id = sv.id
+            WHERE sv.product_id = p.id AND sv.status = 'active' AND sv.deleted_at IS NULL AND ii.on_hand > 0
+        )
+    )
+  )
+  AND ($7::timestamptz IS NULL OR p.created_at >= $7::timestamptz)
+  AND ($8::timestamptz IS NULL OR p.created_at < $8::timestamptz)
+  AND ($9::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM product_publications pub
+    WHERE pub.product_id = p.id AND pub.channel_id = $9::uuid
+  ))
+`
+
+type GetFilteredProductsPriceRangeParams struct {
+	StoreID       uuid.UUID
+	Statuses      []string
+	Tags          []string
+	MinPriceCents sql.NullInt64
+	MaxPriceCents sql.NullInt64
+	InStock       sql.NullBool
+	CreatedAfter  sql.NullTime
+	CreatedBefore sql.NullTime
+	ChannelID     uuid.NullUUID
+}
+
+type GetFilteredProductsPriceRangeRow struct {
+	MinPriceCents int64
+	MaxPriceCents int64
+	VariantCount  int64
+}
+
+// Active variant price bounds, both 0 when variant_count is 0
+func (q *Queries) GetFilteredProductsPriceRange(ctx context.Context, arg GetFilteredProductsPriceRangeParams) (GetFilteredProductsPriceRangeRow, error) {
+	row := q.db.QueryRow(ctx, getFilteredProductsPriceRange,
+		arg.StoreID,
+		arg.Statuses,
+		arg.Tags,
+		arg.MinPriceCents,
+		arg.MaxPriceCents,
+		arg.InStock,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.ChannelID,
+	)
+	var i GetFilteredProductsPriceRangeRow
+	err := row.Scan(
+		&i.MinPriceCents,
+		&i.MaxPriceCents,
+		&i.VariantCount,
 	)
 	return i, err
 }
 
 const getProductByGID = `-- name: GetProductByGID :one
-SELECT id, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at, gid FROM products
-WHERE gid = $1
+SELECT id, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at, gid, deleted_at, version, oversell_policy FROM products
+WHERE gid = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetProductByGID(ctx context.Context, gid sql.NullInt64) (Product, error) {
-	row := q.db.QueryRowContext(ctx, getProductByGID, gid)
+	row := q.db.QueryRow(ctx, getProductByGID, gid)
 	var i Product
 	err := row.Scan(
 		&i.ID,
@@ -113,13 +463,16 @@ func (q *Queries) GetProductByGID(ctx context.Context, gid sql.NullInt64) (Produ
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Gid,
+		&i.DeletedAt,
+		&i.Version,
+		&i.OversellPolicy,
 	)
 	return i, err
 }
 
 const getProductByHandle = `-- name: GetProductByHandle :one
-SELECT id, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at, gid FROM products
-WHERE store_id = $1 AND handle = $2
+SELECT id, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at, gid, deleted_at, version, oversell_policy FROM products
+WHERE store_id = $1 AND handle = $2 AND deleted_at IS NULL
 `
 
 type GetProductByHandleParams struct {
@@ -128,7 +481,7 @@ type GetProductByHandleParams struct {
 }
 
 func (q *Queries) GetProductByHandle(ctx context.Context, arg GetProductByHandleParams) (Product, error) {
-	row := q.db.QueryRowContext(ctx, getProductByHandle, arg.StoreID, arg.Handle)
+	row := q.db.QueryRow(ctx, getProductByHandle, arg.StoreID, arg.Handle)
 	var i Product
 	err := row.Scan(
 		&i.ID,
@@ -143,13 +496,16 @@ func (q *Queries) GetProductByHandle(ctx context.Context, arg GetProductByHandle
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Gid,
+		&i.DeletedAt,
+		&i.Version,
+		&i.OversellPolicy,
 	)
 	return i, err
 }
 
 const getProductByID = `-- name: GetProductByID :one
-SELECT id, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at, gid FROM products
-WHERE id = $1 AND store_id = $2
+SELECT id, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at, gid, deleted_at, version, oversell_policy FROM products
+WHERE id = $1 AND store_id = $2 AND deleted_at IS NULL
 `
 
 type GetProductByIDParams struct {
@@ -158,7 +514,7 @@ type GetProductByIDParams struct {
 }
 
 func (q *Queries) GetProductByID(ctx context.Context, arg GetProductByIDParams) (Product, error) {
-	row := q.db.QueryRowContext(ctx, getProductByID, arg.ID, arg.StoreID)
+	row := q.db.QueryRow(ctx, getProductByID, arg.ID, arg.StoreID)
 	var i Product
 	err := row.Scan(
 		&i.ID,
@@ -173,17 +529,20 @@ func (q *Queries) GetProductByID(ctx context.Context, arg GetProductByIDParams)
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Gid,
+		&i.DeletedAt,
+		&i.Version,
+		&i.OversellPolicy,
 	)
 	return i, err
 }
 
 const getProductByIDOnly = `-- name: GetProductByIDOnly :one
-SELECT id, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at, gid FROM products
-WHERE id = $1
+SELECT id, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at, gid, deleted_at, version, oversell_policy FROM products
+WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetProductByIDOnly(ctx context.Context, id uuid.UUID) (Product, error) {
-	row := q.db.QueryRowContext(ctx, getProductByIDOnly, id)
+	row := q.db.QueryRow(ctx, getProductByIDOnly, id)
 	var i Product
 	err := row.Scan(
 		&i.ID,
@@ -198,18 +557,174 @@ func (q *Queries) GetProductByIDOnly(ctx context.Context, id uuid.UUID) (Product
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Gid,
+		&i.DeletedAt,
+		&i.Version,
+		&i.OversellPolicy,
 	)
 	return i, err
 }
 
+const getProductBySKU = `-- name: GetProductBySKU :one
+SELECT id, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at, gid, deleted_at, version, oversell_policy FROM products
+WHERE store_id = $1 AND sku = $2 AND deleted_at IS NULL
+`
+
+type GetProductBySKUParams struct {
+	StoreID uuid.UUID
+	Sku     sql.NullString
+}
+
+func (q *Queries) GetProductBySKU(ctx context.Context, arg GetProductBySKUParams) (Product, error) {
+	row := q.db.QueryRow(ctx, getProductBySKU, arg.StoreID, arg.Sku)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Handle,
+		&i.Name,
+		&i.Description,
+		&i.InventoryTracked,
+		&i.Sku,
+		&i.Tags,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Gid,
+		&i.DeletedAt,
+		&i.Version,
+		&i.OversellPolicy,
+	)
+	return i, err
+}
+
+const getProductSearchDocuments = `-- name: GetProductSearchDocuments :many
+SELECT products.id, products.store_id, products.handle, products.name, products.description, products.inventory_tracked, products.sku, products.tags, products.status, products.created_at, products.updated_at, products.gid, products.deleted_at, products.version, products.oversell_policy,
+       COALESCE(array_agg(product_variants.sku) FILTER (WHERE product_variants.sku IS NOT NULL), '{}')::text[] AS variant_skus,
+       COUNT(product_variants.id) FILTER (WHERE product_variants.status = 'active')::bigint AS active_variant_count,
+       COALESCE(MIN(product_variants.price_cents) FILTER (WHERE product_variants.status = 'active'), 0)::bigint AS min_price_cents
+FROM products
+LEFT JOIN product_variants ON product_variants.product_id = products.id AND product_variants.deleted_at IS NULL
+WHERE products.id = ANY($1::uuid[]) AND products.deleted_at IS NULL
+GROUP BY products.id
+`
+
+type GetProductSearchDocumentsRow struct {
+	ID                 uuid.UUID
+	StoreID            uuid.UUID
+	Handle             string
+	Name               string
+	Description        sql.NullString
+	InventoryTracked   bool
+	Sku                sql.NullString
+	Tags               sql.NullString
+	Status             string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	Gid                sql.NullInt64
+	DeletedAt          sql.NullTime
+	Version            int64
+	OversellPolicy     string
+	VariantSkus        []string
+	ActiveVariantCount int64
+	MinPriceCents      int64
+}
+
+// Everything a search engine indexes about each product.
+// min_price_cents is only meaningful when active_variant_count > 0.
+// Deleted products are left out, which drops them from the index.
+func (q *Queries) GetProductSearchDocuments(ctx context.Context, ids []uuid.UUID) ([]GetProductSearchDocumentsRow, error) {
+	rows, err := q.db.Query(ctx, getProductSearchDocuments, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProductSearchDocumentsRow
+	for rows.Next() {
+		var i GetProductSearchDocumentsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.StoreID,
+			&i.Handle,
+			&i.Name,
+			&i.Description,
+			&i.InventoryTracked,
+			&i.Sku,
+			&i.Tags,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Gid,
+			&i.DeletedAt,
+			&i.Version,
+			&i.OversellPolicy,
+			&i.VariantSkus,
+			&i.ActiveVariantCount,
+			&i.MinPriceCents,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProductsByIDs = `-- name: GetProductsByIDs :many
+SELECT id, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at, gid, deleted_at, version, oversell_policy FROM products
+WHERE store_id = $1 AND id = ANY($2::uuid[]) AND deleted_at IS NULL
+`
+
+type GetProductsByIDsParams struct {
+	StoreID uuid.UUID
+	Ids     []uuid.UUID
+}
+
+func (q *Queries) GetProductsByIDs(ctx context.Context, arg GetProductsByIDsParams) ([]Product, error) {
+	rows, err := q.db.Query(ctx, getProductsByIDs, arg.StoreID, arg.Ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.StoreID,
+			&i.Handle,
+			&i.Name,
+			&i.Description,
+			&i.InventoryTracked,
+			&i.Sku,
+			&i.Tags,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Gid,
+			&i.DeletedAt,
+			&i.Version,
+			&i.OversellPolicy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getProductsByStore = `-- name: GetProductsByStore :many
-SELECT id, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at, gid FROM products
-WHERE store_id = $1
+SELECT id, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at, gid, deleted_at, version, oversell_policy FROM products
+WHERE store_id = $1 AND deleted_at IS NULL
 ORDER BY created_at ASC
 `
 
 func (q *Queries) GetProductsByStore(ctx context.Context, storeID uuid.UUID) ([]Product, error) {
-	rows, err := q.db.QueryContext(ctx, getProductsByStore, storeID)
+	rows, err := q.db.Query(ctx, getProductsByStore, storeID)
 	if err != nil {
 		return nil, err
 	}
@@ -230,14 +745,14 @@ func (q *Queries) GetProductsByStore(ctx context.Context, storeID uuid.UUID) ([]
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Gid,
+			&i.DeletedAt,
+			&i.Version,
+			&i.OversellPolicy,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -248,6 +763,7 @@ const getProductsByStorePaginated = `-- name: GetProductsByStorePaginated :many
 SELECT id, gid, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at
 FROM products
 WHERE store_id = $1
+  AND deleted_at IS NULL
   AND (
     $2::boolean = false
     OR (created_at, id) < ($3::timestamptz, $4::uuid)
@@ -257,11 +773,11 @@ LIMIT $5
 `
 
 type GetProductsByStorePaginatedParams struct {
-	StoreID uuid.UUID
-	Column2 bool
-	Column3 time.Time
-	Column4 uuid.UUID
-	Limit   int32
+	StoreID         uuid.UUID
+	HasCursor       bool
+	CursorCreatedAt time.Time
+	CursorID        uuid.UUID
+	RowLimit        int32
 }
 
 type GetProductsByStorePaginatedRow struct {
@@ -280,12 +796,12 @@ type GetProductsByStorePaginatedRow struct {
 }
 
 func (q *Queries) GetProductsByStorePaginated(ctx context.Context, arg GetProductsByStorePaginatedParams) ([]GetProductsByStorePaginatedRow, error) {
-	rows, err := q.db.QueryContext(ctx, getProductsByStorePaginated,
+	rows, err := q.db.Query(ctx, getProductsByStorePaginated,
 		arg.StoreID,
-		arg.Column2,
-		arg.Column3,
-		arg.Column4,
-		arg.Limit,
+		arg.HasCursor,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.RowLimit,
 	)
 	if err != nil {
 		return nil, err
@@ -312,46 +828,479 @@ func (q *Queries) GetProductsByStorePaginated(ctx context.Context, arg GetProduc
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+	return items, nil
+}
+
+const listDeletedProducts = `-- name: ListDeletedProducts :many
+SELECT id, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at, gid, deleted_at, version, oversell_policy FROM products
+WHERE store_id = $1
+  AND deleted_at IS NOT NULL
+  AND (
+    NOT $2::boolean
+    OR (deleted_at, id) < ($3::timestamptz, $4::uuid)
+  )
+ORDER BY deleted_at DESC, id DESC
+LIMIT $5
+`
+
+type ListDeletedProductsParams struct {
+	StoreID         uuid.UUID
+	HasCursor       bool
+	CursorDeletedAt time.Time
+	CursorID        uuid.UUID
+	RowLimit        int32
+}
+
+// Most recently deleted first
+func (q *Queries) ListDeletedProducts(ctx context.Context, arg ListDeletedProductsParams) ([]Product, error) {
+	rows, err := q.db.Query(ctx, listDeletedProducts,
+		arg.StoreID,
+		arg.HasCursor,
+		arg.CursorDeletedAt,
+		arg.CursorID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.StoreID,
+			&i.Handle,
+			&i.Name,
+			&i.Description,
+			&i.InventoryTracked,
+			&i.Sku,
+			&i.Tags,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Gid,
+			&i.DeletedAt,
+			&i.Version,
+			&i.OversellPolicy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFilteredProducts = `-- name: ListFilteredProducts :many
+SELECT p.id, p.store_id, p.handle, p.name, p.description, p.inventory_tracked, p.sku, p.tags, p.status, p.created_at, p.updated_at, p.gid, p.deleted_at, p.version, p.oversell_policy FROM products p
+WHERE p.store_id = $1
+  AND p.deleted_at IS NULL
+  AND (COALESCE(cardinality($2::text[]), 0) = 0 OR p.status = ANY($2::text[]))
+  AND product_tags(p.tags) @> COALESCE($3::text[], '{}')
+  AND (
+    ($4::bigint IS NULL AND $5::bigint IS NULL)
+    OR EXISTS (
+        SELECT 1 FROM product_variants pv
+        WHERE pv.product_id = p.id AND pv.status = 'active' AND pv.deleted_at IS NULL
+          AND ($4::bigint IS NULL OR pv.price_cents >= $4::bigint)
+          AND ($5::bigint IS NULL OR pv.price_cents <= $5::bigint)
+    )
+  )
+  AND (
+    $6::boolean IS NULL
+    OR $6::boolean = (
+        NOT p.inventory_tracked
+        OR EXISTS (
+            SELECT 1 FROM product_variants sv
+            JOIN inventory_items ii ON ii.variant_id = sv.id
+            WHERE sv.product_id = p.id AND sv.status = 'active' AND sv.deleted_at IS NULL AND ii.on_hand > 0
+        )
+    )
+  )
+  AND ($7::timestamptz IS NULL OR p.created_at >= $7::timestamptz)
+  AND ($8::timestamptz IS NULL OR p.created_at < $8::timestamptz)
+  AND ($9::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM product_publications pub
+    WHERE pub.product_id = p.id AND pub.channel_id = $9::uuid
+  ))
+  AND (
+    NOT $10::boolean
+    OR (p.created_at, p.id) < ($11::timestamptz, $12::uuid)
+  )
+ORDER BY p.created_at DESC, p.id DESC
+LIMIT $13
+`
+
+type ListFilteredProductsParams struct {
+	StoreID         uuid.UUID
+	Statuses        []string
+	Tags            []string
+	MinPriceCents   sql.NullInt64
+	MaxPriceCents   sql.NullInt64
+	InStock         sql.NullBool
+	CreatedAfter    sql.NullTime
+	CreatedBefore   sql.NullTime
+	ChannelID       uuid.NullUUID
+	HasCursor       bool
+	CursorCreatedAt time.Time
+	CursorID        uuid.UUID
+	RowLimit        int32
+}
+
+// Product listing with optional filters. A NULL or empty filter matches
+// everything. Products without inventory tracking are always in stock.
+// The same filter block is repeated in every facet query below.
+func (q *Queries) ListFilteredProducts(ctx context.Context, arg ListFilteredProductsParams) ([]Product, error) {
+	rows, err := q.db.Query(ctx, listFilteredProducts,
+		arg.StoreID,
+		arg.Statuses,
+		arg.Tags,
+		arg.MinPriceCents,
+		arg.MaxPriceCents,
+		arg.InStock,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.ChannelID,
+		arg.HasCursor,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.StoreID,
+			&i.Handle,
+			&i.Name,
+			&i.Description,
+			&i.InventoryTracked,
+			&i.Sku,
+			&i.Tags,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Gid,
+			&i.DeletedAt,
+			&i.Version,
+			&i.OversellPolicy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductHandlesWithPrefix = `-- name: ListProductHandlesWithPrefix :many
+SELECT handle FROM products
+WHERE store_id = $1
+  AND (handle = $2::text OR handle LIKE $2::text || '-%')
+`
+
+type ListProductHandlesWithPrefixParams struct {
+	StoreID uuid.UUID
+	Handle  string
+}
+
+// The handle and any taken with a numeric suffix, such as shirt-2, for
+// finding a free one. Deleted products keep their handles until purged.
+func (q *Queries) ListProductHandlesWithPrefix(ctx context.Context, arg ListProductHandlesWithPrefixParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, listProductHandlesWithPrefix, arg.StoreID, arg.Handle)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var handle string
+		if err := rows.Scan(&handle); err != nil {
+			return nil, err
+		}
+		items = append(items, handle)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeDeletedProducts = `-- name: PurgeDeletedProducts :execrows
+DELETE FROM products
+WHERE id IN (
+    SELECT id FROM products
+    WHERE deleted_at < $1::timestamptz
+    ORDER BY deleted_at
+    LIMIT $2
+)
+`
+
+type PurgeDeletedProductsParams struct {
+	Before   time.Time
+	RowLimit int32
+}
+
+// Permanently removes up to row_limit products deleted before the cutoff,
+// with their variants, media rows and inventory
+func (q *Queries) PurgeDeletedProducts(ctx context.Context, arg PurgeDeletedProductsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedProducts, arg.Before, arg.RowLimit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreProduct = `-- name: RestoreProduct :one
+UPDATE products
+SET deleted_at = NULL, updated_at = NOW()
+WHERE id = $1 AND store_id = $2 AND deleted_at IS NOT NULL
+RETURNING id, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at, gid, deleted_at, version, oversell_policy
+`
+
+type RestoreProductParams struct {
+	ID      uuid.UUID
+	StoreID uuid.UUID
+}
+
+func (q *Queries) RestoreProduct(ctx context.Context, arg RestoreProductParams) (Product, error) {
+	row := q.db.QueryRow(ctx, restoreProduct, arg.ID, arg.StoreID)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Handle,
+		&i.Name,
+		&i.Description,
+		&i.InventoryTracked,
+		&i.Sku,
+		&i.Tags,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Gid,
+		&i.DeletedAt,
+		&i.Version,
+		&i.OversellPolicy,
+	)
+	return i, err
+}
+
+const searchProductsByStore = `-- name: SearchProductsByStore :many
+SELECT p.id, p.store_id, p.handle, p.name, p.description, p.inventory_tracked, p.sku, p.tags, p.status, p.created_at, p.updated_at, p.gid, p.deleted_at, p.version, p.oversell_policy,
+    (
+        ts_rank(product_search_vector(p.name, p.description, p.tags, p.sku), websearch_to_tsquery('english', $1::text))
+        + CASE WHEN EXISTS (
+            SELECT 1 FROM product_variants v
+            WHERE v.product_id = p.id AND v.deleted_at IS NULL AND lower(v.sku) = lower($1::text)
+        ) THEN 1 ELSE 0 END
+    )::real AS rank,
+    ts_headline('english', p.name, websearch_to_tsquery('english', $1::text),
+        'StartSel=<mark>, StopSel=</mark>, HighlightAll=true')::text AS name_highlight,
+    ts_headline('english', coalesce(p.description, ''), websearch_to_tsquery('english', $1::text),
+        'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5')::text AS description_snippet
+FROM products p
+WHERE p.store_id = $2
+  AND p.deleted_at IS NULL
+  AND (
+    product_search_vector(p.name, p.description, p.tags, p.sku) @@ websearch_to_tsquery('english', $1::text)
+    OR EXISTS (
+        SELECT 1 FROM product_variants v
+        WHERE v.product_id = p.id AND v.deleted_at IS NULL AND lower(v.sku) = lower($1::text)
+    )
+  )
+  AND (
+    NOT $3::boolean
+    OR (
+        (
+            ts_rank(product_search_vector(p.name, p.description, p.tags, p.sku), websearch_to_tsquery('english', $1::text))
+            + CASE WHEN EXISTS (
+                SELECT 1 FROM product_variants v
+                WHERE v.product_id = p.id AND v.deleted_at IS NULL AND lower(v.sku) = lower($1::text)
+            ) THEN 1 ELSE 0 END
+        )::real,
+        p.created_at,
+        p.id
+    ) < ($4::real, $5::timestamptz, $6::uuid)
+  )
+ORDER BY rank DESC, p.created_at DESC, p.id DESC
+LIMIT $7
+`
+
+type SearchProductsByStoreParams struct {
+	Query           string
+	StoreID         uuid.UUID
+	HasCursor       bool
+	CursorRank      float32
+	CursorCreatedAt time.Time
+	CursorID        uuid.UUID
+	RowLimit        int32
+}
+
+type SearchProductsByStoreRow struct {
+	ID                 uuid.UUID
+	StoreID            uuid.UUID
+	Handle             string
+	Name               string
+	Description        sql.NullString
+	InventoryTracked   bool
+	Sku                sql.NullString
+	Tags               sql.NullString
+	Status             string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	Gid                sql.NullInt64
+	DeletedAt          sql.NullTime
+	Version            int64
+	OversellPolicy     string
+	Rank               float32
+	NameHighlight      string
+	DescriptionSnippet string
+}
+
+// Ranks products matching a web-style search query (quoted phrases, OR, -term).
+// An exact variant SKU match always ranks first. Pages are keyed on
+// (rank, created_at, id) so cursor pagination stays stable while searching.
+func (q *Queries) SearchProductsByStore(ctx context.Context, arg SearchProductsByStoreParams) ([]SearchProductsByStoreRow, error) {
+	rows, err := q.db.Query(ctx, searchProductsByStore,
+		arg.Query,
+		arg.StoreID,
+		arg.HasCursor,
+		arg.CursorRank,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchProductsByStoreRow
+	for rows.Next() {
+		var i SearchProductsByStoreRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.StoreID,
+			&i.Handle,
+			&i.Name,
+			&i.Description,
+			&i.InventoryTracked,
+			&i.Sku,
+			&i.Tags,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Gid,
+			&i.DeletedAt,
+			&i.Version,
+			&i.OversellPolicy,
+			&i.Rank,
+			&i.NameHighlight,
+			&i.DescriptionSnippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 	return items, nil
 }
 
+const softDeleteProduct = `-- name: SoftDeleteProduct :one
+UPDATE products
+SET deleted_at = NOW()
+WHERE id = $1 AND store_id = $2 AND deleted_at IS NULL
+RETURNING id, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at, gid, deleted_at, version, oversell_policy
+`
+
+type SoftDeleteProductParams struct {
+	ID      uuid.UUID
+	StoreID uuid.UUID
+}
+
+// Hides the product and, by trigger, its variants until it is restored or
+// purged
+func (q *Queries) SoftDeleteProduct(ctx context.Context, arg SoftDeleteProductParams) (Product, error) {
+	row := q.db.QueryRow(ctx, softDeleteProduct, arg.ID, arg.StoreID)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Handle,
+		&i.Name,
+		&i.Description,
+		&i.InventoryTracked,
+		&i.Sku,
+		&i.Tags,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Gid,
+		&i.DeletedAt,
+		&i.Version,
+		&i.OversellPolicy,
+	)
+	return i, err
+}
+
 const updateProduct = `-- name: UpdateProduct :one
 UPDATE products
-SET 
-    handle = $3,
-    name = $4,
-    description = $5,
-    inventory_tracked = $6,
-    sku = $7,
-    tags = $8,
-    status = $9,
+SET
+    handle = COALESCE($1::text, handle),
+    name = COALESCE($2::text, name),
+    description = COALESCE($3::text, description),
+    inventory_tracked = COALESCE($4::boolean, inventory_tracked),
+    sku = COALESCE($5::text, sku),
+    tags = COALESCE($6::text, tags),
+    status = COALESCE($7::text, status),
+    oversell_policy = COALESCE($8::text, oversell_policy),
     updated_at = NOW()
-WHERE id = $1 AND store_id = $2
-RETURNING id, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at, gid
+WHERE id = $9 AND store_id = $10 AND deleted_at IS NULL
+  AND ($11::bigint IS NULL OR version = $11::bigint)
+  AND (
+    $7::text IS NULL
+    OR status = $7::text
+    OR status = ANY($12::text[])
+  )
+RETURNING id, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at, gid, deleted_at, version, oversell_policy
 `
 
 type UpdateProductParams struct {
-	ID               uuid.UUID
-	StoreID          uuid.UUID
-	Handle           string
-	Name             string
+	Handle           sql.NullString
+	Name             sql.NullString
 	Description      sql.NullString
-	InventoryTracked bool
+	InventoryTracked sql.NullBool
 	Sku              sql.NullString
 	Tags             sql.NullString
-	Status           string
+	Status           sql.NullString
+	OversellPolicy   sql.NullString
+	ID               uuid.UUID
+	StoreID          uuid.UUID
+	Version          sql.NullInt64
+	FromStatuses     []string
 }
 
+// Sets only the fields given and keeps the rest, in one statement, so an
+// update never overwrites a field another request changed meanwhile. With a
+// version it only applies while the product still has that version, and
+// with a status only while the product is in it or one of from_statuses.
 func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (Product, error) {
-	row := q.db.QueryRowContext(ctx, updateProduct,
-		arg.ID,
-		arg.StoreID,
+	row := q.db.QueryRow(ctx, updateProduct,
 		arg.Handle,
 		arg.Name,
 		arg.Description,
@@ -359,6 +1308,56 @@ func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (P
 		arg.Sku,
 		arg.Tags,
 		arg.Status,
+		arg.OversellPolicy,
+		arg.ID,
+		arg.StoreID,
+		arg.Version,
+		arg.FromStatuses,
+	)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Handle,
+		&i.Name,
+		&i.Description,
+		&i.InventoryTracked,
+		&i.Sku,
+		&i.Tags,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Gid,
+		&i.DeletedAt,
+		&i.Version,
+		&i.OversellPolicy,
+	)
+	return i, err
+}
+
+const updateProductStatus = `-- name: UpdateProductStatus :one
+UPDATE products
+SET status = $1, updated_at = NOW()
+WHERE id = $2 AND store_id = $3 AND status = $4
+  AND deleted_at IS NULL
+RETURNING id, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at, gid, deleted_at, version, oversell_policy
+`
+
+type UpdateProductStatusParams struct {
+	Status     string
+	ID         uuid.UUID
+	StoreID    uuid.UUID
+	FromStatus string
+}
+
+// Only applies while the product still has from_status, so a status change
+// races with no other
+func (q *Queries) UpdateProductStatus(ctx context.Context, arg UpdateProductStatusParams) (Product, error) {
+	row := q.db.QueryRow(ctx, updateProductStatus,
+		arg.Status,
+		arg.ID,
+		arg.StoreID,
+		arg.FromStatus,
 	)
 	var i Product
 	err := row.Scan(
@@ -374,6 +1373,9 @@ func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (P
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Gid,
+		&i.DeletedAt,
+		&i.Version,
+		&i.OversellPolicy,
 	)
 	return i, err
 }
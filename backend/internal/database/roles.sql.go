@@ -11,7 +11,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/lib/pq"
 )
 
 const assignPermissionToRole = `-- name: AssignPermissionToRole :exec
@@ -28,7 +27,7 @@ type AssignPermissionToRoleParams struct {
 
 // Role-Permission Assignment
 func (q *Queries) AssignPermissionToRole(ctx context.Context, arg AssignPermissionToRoleParams) error {
-	_, err := q.db.ExecContext(ctx, assignPermissionToRole, arg.RoleID, arg.PermissionID)
+	_, err := q.db.Exec(ctx, assignPermissionToRole, arg.RoleID, arg.PermissionID)
 	return err
 }
 
@@ -46,7 +45,7 @@ type AssignRoleToTenantUserParams struct {
 
 // Tenant User Role Assignment
 func (q *Queries) AssignRoleToTenantUser(ctx context.Context, arg AssignRoleToTenantUserParams) error {
-	_, err := q.db.ExecContext(ctx, assignRoleToTenantUser, arg.TenantUserID, arg.RoleID)
+	_, err := q.db.Exec(ctx, assignRoleToTenantUser, arg.TenantUserID, arg.RoleID)
 	return err
 }
 
@@ -72,7 +71,7 @@ type CheckUserHasPermissionParams struct {
 
 // Permission Checking Queries
 func (q *Queries) CheckUserHasPermission(ctx context.Context, arg CheckUserHasPermissionParams) (bool, error) {
-	row := q.db.QueryRowContext(ctx, checkUserHasPermission, arg.TenantID, arg.UserID, arg.Key)
+	row := q.db.QueryRow(ctx, checkUserHasPermission, arg.TenantID, arg.UserID, arg.Key)
 	var has_permission bool
 	err := row.Scan(&has_permission)
 	return has_permission, err
@@ -93,7 +92,7 @@ type CreatePermissionParams struct {
 
 // Permission Management
 func (q *Queries) CreatePermission(ctx context.Context, arg CreatePermissionParams) (Permission, error) {
-	row := q.db.QueryRowContext(ctx, createPermission, arg.Gid, arg.Key, arg.Description)
+	row := q.db.QueryRow(ctx, createPermission, arg.Gid, arg.Key, arg.Description)
 	var i Permission
 	err := row.Scan(
 		&i.ID,
@@ -108,9 +107,9 @@ func (q *Queries) CreatePermission(ctx context.Context, arg CreatePermissionPara
 
 const createRole = `-- name: CreateRole :one
 
-INSERT INTO roles (id, gid, tenant_id, name, description, created_at, updated_at)
-VALUES (gen_random_uuid(), $1, $2, $3, $4, now(), now())
-RETURNING id, tenant_id, name, description, created_at, updated_at, gid
+INSERT INTO roles (id, gid, tenant_id, name, description, is_system, created_at, updated_at)
+VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, now(), now())
+RETURNING id, tenant_id, name, description, created_at, updated_at, gid, is_system
 `
 
 type CreateRoleParams struct {
@@ -118,15 +117,17 @@ type CreateRoleParams struct {
 	TenantID    uuid.UUID
 	Name        string
 	Description sql.NullString
+	IsSystem    bool
 }
 
 // Role Management
 func (q *Queries) CreateRole(ctx context.Context, arg CreateRoleParams) (Role, error) {
-	row := q.db.QueryRowContext(ctx, createRole,
+	row := q.db.QueryRow(ctx, createRole,
 		arg.Gid,
 		arg.TenantID,
 		arg.Name,
 		arg.Description,
+		arg.IsSystem,
 	)
 	var i Role
 	err := row.Scan(
@@ -137,6 +138,7 @@ func (q *Queries) CreateRole(ctx context.Context, arg CreateRoleParams) (Role, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Gid,
+		&i.IsSystem,
 	)
 	return i, err
 }
@@ -147,7 +149,7 @@ WHERE id = $1
 `
 
 func (q *Queries) DeleteRole(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteRole, id)
+	_, err := q.db.Exec(ctx, deleteRole, id)
 	return err
 }
 
@@ -157,7 +159,7 @@ ORDER BY key
 `
 
 func (q *Queries) GetAllPermissions(ctx context.Context) ([]Permission, error) {
-	rows, err := q.db.QueryContext(ctx, getAllPermissions)
+	rows, err := q.db.Query(ctx, getAllPermissions)
 	if err != nil {
 		return nil, err
 	}
@@ -177,9 +179,6 @@ func (q *Queries) GetAllPermissions(ctx context.Context) ([]Permission, error) {
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -192,7 +191,7 @@ WHERE gid = $1
 `
 
 func (q *Queries) GetPermissionByGID(ctx context.Context, gid sql.NullInt64) (Permission, error) {
-	row := q.db.QueryRowContext(ctx, getPermissionByGID, gid)
+	row := q.db.QueryRow(ctx, getPermissionByGID, gid)
 	var i Permission
 	err := row.Scan(
 		&i.ID,
@@ -211,7 +210,7 @@ WHERE id = $1
 `
 
 func (q *Queries) GetPermissionByID(ctx context.Context, id uuid.UUID) (Permission, error) {
-	row := q.db.QueryRowContext(ctx, getPermissionByID, id)
+	row := q.db.QueryRow(ctx, getPermissionByID, id)
 	var i Permission
 	err := row.Scan(
 		&i.ID,
@@ -230,7 +229,7 @@ WHERE key = $1
 `
 
 func (q *Queries) GetPermissionByKey(ctx context.Context, key string) (Permission, error) {
-	row := q.db.QueryRowContext(ctx, getPermissionByKey, key)
+	row := q.db.QueryRow(ctx, getPermissionByKey, key)
 	var i Permission
 	err := row.Scan(
 		&i.ID,
@@ -243,14 +242,51 @@ func (q *Queries) GetPermissionByKey(ctx context.Context, key string) (Permissio
 	return i, err
 }
 
+const getPermissionKeysByRoleIDs = `-- name: GetPermissionKeysByRoleIDs :many
+SELECT rp.role_id, array_agg(p.key ORDER BY p.key)::text[] AS keys
+FROM role_permissions rp
+JOIN permissions p ON p.id = rp.permission_id
+WHERE rp.role_id = ANY($1::uuid[])
+GROUP BY rp.role_id
+`
+
+type GetPermissionKeysByRoleIDsRow struct {
+	RoleID uuid.UUID
+	Keys   []string
+}
+
+// One row per role that grants anything, for listing roles in one query
+func (q *Queries) GetPermissionKeysByRoleIDs(ctx context.Context, roleIds []uuid.UUID) ([]GetPermissionKeysByRoleIDsRow, error) {
+	rows, err := q.db.Query(ctx, getPermissionKeysByRoleIDs, roleIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPermissionKeysByRoleIDsRow
+	for rows.Next() {
+		var i GetPermissionKeysByRoleIDsRow
+		if err := rows.Scan(
+			&i.RoleID,
+			&i.Keys,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPermissionsByKeys = `-- name: GetPermissionsByKeys :many
 SELECT id, key, description, created_at, updated_at, gid FROM permissions
 WHERE key = ANY($1::text[])
 ORDER BY key
 `
 
-func (q *Queries) GetPermissionsByKeys(ctx context.Context, dollar_1 []string) ([]Permission, error) {
-	rows, err := q.db.QueryContext(ctx, getPermissionsByKeys, pq.Array(dollar_1))
+func (q *Queries) GetPermissionsByKeys(ctx context.Context, keys []string) ([]Permission, error) {
+	rows, err := q.db.Query(ctx, getPermissionsByKeys, keys)
 	if err != nil {
 		return nil, err
 	}
@@ -270,9 +306,6 @@ func (q *Queries) GetPermissionsByKeys(ctx context.Context, dollar_1 []string) (
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -287,7 +320,7 @@ ORDER BY p.key
 `
 
 func (q *Queries) GetPermissionsByRoleID(ctx context.Context, roleID uuid.UUID) ([]Permission, error) {
-	rows, err := q.db.QueryContext(ctx, getPermissionsByRoleID, roleID)
+	rows, err := q.db.Query(ctx, getPermissionsByRoleID, roleID)
 	if err != nil {
 		return nil, err
 	}
@@ -307,9 +340,6 @@ func (q *Queries) GetPermissionsByRoleID(ctx context.Context, roleID uuid.UUID)
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -317,12 +347,12 @@ func (q *Queries) GetPermissionsByRoleID(ctx context.Context, roleID uuid.UUID)
 }
 
 const getRoleByGID = `-- name: GetRoleByGID :one
-SELECT id, tenant_id, name, description, created_at, updated_at, gid FROM roles
+SELECT id, tenant_id, name, description, created_at, updated_at, gid, is_system FROM roles
 WHERE gid = $1
 `
 
 func (q *Queries) GetRoleByGID(ctx context.Context, gid sql.NullInt64) (Role, error) {
-	row := q.db.QueryRowContext(ctx, getRoleByGID, gid)
+	row := q.db.QueryRow(ctx, getRoleByGID, gid)
 	var i Role
 	err := row.Scan(
 		&i.ID,
@@ -332,17 +362,18 @@ func (q *Queries) GetRoleByGID(ctx context.Context, gid sql.NullInt64) (Role, er
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Gid,
+		&i.IsSystem,
 	)
 	return i, err
 }
 
 const getRoleByID = `-- name: GetRoleByID :one
-SELECT id, tenant_id, name, description, created_at, updated_at, gid FROM roles
+SELECT id, tenant_id, name, description, created_at, updated_at, gid, is_system FROM roles
 WHERE id = $1
 `
 
 func (q *Queries) GetRoleByID(ctx context.Context, id uuid.UUID) (Role, error) {
-	row := q.db.QueryRowContext(ctx, getRoleByID, id)
+	row := q.db.QueryRow(ctx, getRoleByID, id)
 	var i Role
 	err := row.Scan(
 		&i.ID,
@@ -352,12 +383,13 @@ func (q *Queries) GetRoleByID(ctx context.Context, id uuid.UUID) (Role, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Gid,
+		&i.IsSystem,
 	)
 	return i, err
 }
 
 const getRoleByTenantAndID = `-- name: GetRoleByTenantAndID :one
-SELECT id, tenant_id, name, description, created_at, updated_at, gid FROM roles
+SELECT id, tenant_id, name, description, created_at, updated_at, gid, is_system FROM roles
 WHERE tenant_id = $1 AND id = $2
 `
 
@@ -367,7 +399,7 @@ type GetRoleByTenantAndIDParams struct {
 }
 
 func (q *Queries) GetRoleByTenantAndID(ctx context.Context, arg GetRoleByTenantAndIDParams) (Role, error) {
-	row := q.db.QueryRowContext(ctx, getRoleByTenantAndID, arg.TenantID, arg.ID)
+	row := q.db.QueryRow(ctx, getRoleByTenantAndID, arg.TenantID, arg.ID)
 	var i Role
 	err := row.Scan(
 		&i.ID,
@@ -377,12 +409,13 @@ func (q *Queries) GetRoleByTenantAndID(ctx context.Context, arg GetRoleByTenantA
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Gid,
+		&i.IsSystem,
 	)
 	return i, err
 }
 
 const getRoleByTenantAndName = `-- name: GetRoleByTenantAndName :one
-SELECT id, tenant_id, name, description, created_at, updated_at, gid FROM roles
+SELECT id, tenant_id, name, description, created_at, updated_at, gid, is_system FROM roles
 WHERE tenant_id = $1 AND name = $2
 `
 
@@ -392,7 +425,7 @@ type GetRoleByTenantAndNameParams struct {
 }
 
 func (q *Queries) GetRoleByTenantAndName(ctx context.Context, arg GetRoleByTenantAndNameParams) (Role, error) {
-	row := q.db.QueryRowContext(ctx, getRoleByTenantAndName, arg.TenantID, arg.Name)
+	row := q.db.QueryRow(ctx, getRoleByTenantAndName, arg.TenantID, arg.Name)
 	var i Role
 	err := row.Scan(
 		&i.ID,
@@ -402,18 +435,56 @@ func (q *Queries) GetRoleByTenantAndName(ctx context.Context, arg GetRoleByTenan
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Gid,
+		&i.IsSystem,
 	)
 	return i, err
 }
 
+const getRoleNamesByTenantUserIDs = `-- name: GetRoleNamesByTenantUserIDs :many
+SELECT tur.tenant_user_id, array_agg(r.name ORDER BY r.name)::text[] AS role_names
+FROM tenant_user_roles tur
+JOIN roles r ON r.id = tur.role_id
+WHERE tur.tenant_user_id = ANY($1::uuid[])
+GROUP BY tur.tenant_user_id
+`
+
+type GetRoleNamesByTenantUserIDsRow struct {
+	TenantUserID uuid.UUID
+	RoleNames    []string
+}
+
+// One row per member holding any role, for listing members in one query
+func (q *Queries) GetRoleNamesByTenantUserIDs(ctx context.Context, tenantUserIds []uuid.UUID) ([]GetRoleNamesByTenantUserIDsRow, error) {
+	rows, err := q.db.Query(ctx, getRoleNamesByTenantUserIDs, tenantUserIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRoleNamesByTenantUserIDsRow
+	for rows.Next() {
+		var i GetRoleNamesByTenantUserIDsRow
+		if err := rows.Scan(
+			&i.TenantUserID,
+			&i.RoleNames,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getRolesByTenantID = `-- name: GetRolesByTenantID :many
-SELECT id, tenant_id, name, description, created_at, updated_at, gid FROM roles
+SELECT id, tenant_id, name, description, created_at, updated_at, gid, is_system FROM roles
 WHERE tenant_id = $1
 ORDER BY name
 `
 
 func (q *Queries) GetRolesByTenantID(ctx context.Context, tenantID uuid.UUID) ([]Role, error) {
-	rows, err := q.db.QueryContext(ctx, getRolesByTenantID, tenantID)
+	rows, err := q.db.Query(ctx, getRolesByTenantID, tenantID)
 	if err != nil {
 		return nil, err
 	}
@@ -429,14 +500,12 @@ func (q *Queries) GetRolesByTenantID(ctx context.Context, tenantID uuid.UUID) ([
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Gid,
+			&i.IsSystem,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -444,7 +513,7 @@ func (q *Queries) GetRolesByTenantID(ctx context.Context, tenantID uuid.UUID) ([
 }
 
 const getRolesByTenantIDPaginated = `-- name: GetRolesByTenantIDPaginated :many
-SELECT id, gid, tenant_id, name, description, created_at, updated_at
+SELECT id, gid, tenant_id, name, description, is_system, created_at, updated_at
 FROM roles
 WHERE tenant_id = $1
   AND (
@@ -456,11 +525,11 @@ LIMIT $5
 `
 
 type GetRolesByTenantIDPaginatedParams struct {
-	TenantID uuid.UUID
-	Column2  bool
-	Column3  time.Time
-	Column4  uuid.UUID
-	Limit    int32
+	TenantID        uuid.UUID
+	HasCursor       bool
+	CursorCreatedAt time.Time
+	CursorID        uuid.UUID
+	RowLimit        int32
 }
 
 type GetRolesByTenantIDPaginatedRow struct {
@@ -469,17 +538,18 @@ type GetRolesByTenantIDPaginatedRow struct {
 	TenantID    uuid.UUID
 	Name        string
 	Description sql.NullString
+	IsSystem    bool
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
 
 func (q *Queries) GetRolesByTenantIDPaginated(ctx context.Context, arg GetRolesByTenantIDPaginatedParams) ([]GetRolesByTenantIDPaginatedRow, error) {
-	rows, err := q.db.QueryContext(ctx, getRolesByTenantIDPaginated,
+	rows, err := q.db.Query(ctx, getRolesByTenantIDPaginated,
 		arg.TenantID,
-		arg.Column2,
-		arg.Column3,
-		arg.Column4,
-		arg.Limit,
+		arg.HasCursor,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.RowLimit,
 	)
 	if err != nil {
 		return nil, err
@@ -494,6 +564,7 @@ func (q *Queries) GetRolesByTenantIDPaginated(ctx context.Context, arg GetRolesB
 			&i.TenantID,
 			&i.Name,
 			&i.Description,
+			&i.IsSystem,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -501,9 +572,6 @@ func (q *Queries) GetRolesByTenantIDPaginated(ctx context.Context, arg GetRolesB
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -511,14 +579,14 @@ func (q *Queries) GetRolesByTenantIDPaginated(ctx context.Context, arg GetRolesB
 }
 
 const getRolesByTenantUserID = `-- name: GetRolesByTenantUserID :many
-SELECT r.id, r.tenant_id, r.name, r.description, r.created_at, r.updated_at, r.gid FROM roles r
+SELECT r.id, r.tenant_id, r.name, r.description, r.created_at, r.updated_at, r.gid, r.is_system FROM roles r
 JOIN tenant_user_roles tur ON r.id = tur.role_id
 WHERE tur.tenant_user_id = $1
 ORDER BY r.name
 `
 
 func (q *Queries) GetRolesByTenantUserID(ctx context.Context, tenantUserID uuid.UUID) ([]Role, error) {
-	rows, err := q.db.QueryContext(ctx, getRolesByTenantUserID, tenantUserID)
+	rows, err := q.db.Query(ctx, getRolesByTenantUserID, tenantUserID)
 	if err != nil {
 		return nil, err
 	}
@@ -534,14 +602,12 @@ func (q *Queries) GetRolesByTenantUserID(ctx context.Context, tenantUserID uuid.
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Gid,
+			&i.IsSystem,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -563,7 +629,7 @@ type GetUserPermissionsInTenantParams struct {
 }
 
 func (q *Queries) GetUserPermissionsInTenant(ctx context.Context, arg GetUserPermissionsInTenantParams) ([]Permission, error) {
-	rows, err := q.db.QueryContext(ctx, getUserPermissionsInTenant, arg.TenantID, arg.UserID)
+	rows, err := q.db.Query(ctx, getUserPermissionsInTenant, arg.TenantID, arg.UserID)
 	if err != nil {
 		return nil, err
 	}
@@ -583,9 +649,6 @@ func (q *Queries) GetUserPermissionsInTenant(ctx context.Context, arg GetUserPer
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -593,7 +656,7 @@ func (q *Queries) GetUserPermissionsInTenant(ctx context.Context, arg GetUserPer
 }
 
 const getUserRolesInTenant = `-- name: GetUserRolesInTenant :many
-SELECT r.id, r.tenant_id, r.name, r.description, r.created_at, r.updated_at, r.gid FROM roles r
+SELECT r.id, r.tenant_id, r.name, r.description, r.created_at, r.updated_at, r.gid, r.is_system FROM roles r
 JOIN tenant_user_roles tur ON r.id = tur.role_id
 JOIN tenant_users tu ON tur.tenant_user_id = tu.id
 WHERE tu.tenant_id = $1 AND tu.user_id = $2 AND tu.status = 'active'
@@ -606,7 +669,7 @@ type GetUserRolesInTenantParams struct {
 }
 
 func (q *Queries) GetUserRolesInTenant(ctx context.Context, arg GetUserRolesInTenantParams) ([]Role, error) {
-	rows, err := q.db.QueryContext(ctx, getUserRolesInTenant, arg.TenantID, arg.UserID)
+	rows, err := q.db.Query(ctx, getUserRolesInTenant, arg.TenantID, arg.UserID)
 	if err != nil {
 		return nil, err
 	}
@@ -622,14 +685,12 @@ func (q *Queries) GetUserRolesInTenant(ctx context.Context, arg GetUserRolesInTe
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Gid,
+			&i.IsSystem,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -647,7 +708,7 @@ type RemovePermissionFromRoleParams struct {
 }
 
 func (q *Queries) RemovePermissionFromRole(ctx context.Context, arg RemovePermissionFromRoleParams) error {
-	_, err := q.db.ExecContext(ctx, removePermissionFromRole, arg.RoleID, arg.PermissionID)
+	_, err := q.db.Exec(ctx, removePermissionFromRole, arg.RoleID, arg.PermissionID)
 	return err
 }
 
@@ -662,7 +723,7 @@ type RemoveRoleFromTenantUserParams struct {
 }
 
 func (q *Queries) RemoveRoleFromTenantUser(ctx context.Context, arg RemoveRoleFromTenantUserParams) error {
-	_, err := q.db.ExecContext(ctx, removeRoleFromTenantUser, arg.TenantUserID, arg.RoleID)
+	_, err := q.db.Exec(ctx, removeRoleFromTenantUser, arg.TenantUserID, arg.RoleID)
 	return err
 }
 
@@ -670,7 +731,7 @@ const updateRole = `-- name: UpdateRole :one
 UPDATE roles
 SET name = $2, description = $3, updated_at = now()
 WHERE id = $1
-RETURNING id, tenant_id, name, description, created_at, updated_at, gid
+RETURNING id, tenant_id, name, description, created_at, updated_at, gid, is_system
 `
 
 type UpdateRoleParams struct {
@@ -680,7 +741,7 @@ type UpdateRoleParams struct {
 }
 
 func (q *Queries) UpdateRole(ctx context.Context, arg UpdateRoleParams) (Role, error) {
-	row := q.db.QueryRowContext(ctx, updateRole, arg.ID, arg.Name, arg.Description)
+	row := q.db.QueryRow(ctx, updateRole, arg.ID, arg.Name, arg.Description)
 	var i Role
 	err := row.Scan(
 		&i.ID,
@@ -690,6 +751,7 @@ func (q *Queries) UpdateRole(ctx context.Context, arg UpdateRoleParams) (Role, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Gid,
+		&i.IsSystem,
 	)
 	return i, err
 }
@@ -8,6 +8,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,7 +17,7 @@ import (
 const createStore = `-- name: CreateStore :one
 INSERT INTO stores (id, gid, name, handle, address, status, default_currency, timezone, plan, tenant_id, created_at, updated_at)
 VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8, $9, now(), now())
-RETURNING id, name, handle, address, status, default_currency, timezone, plan, created_at, updated_at, tenant_id, gid
+RETURNING id, name, handle, address, status, default_currency, timezone, plan, created_at, updated_at, tenant_id, gid, deleted_at, settings, default_locale
 `
 
 type CreateStoreParams struct {
@@ -32,7 +33,7 @@ type CreateStoreParams struct {
 }
 
 func (q *Queries) CreateStore(ctx context.Context, arg CreateStoreParams) (Store, error) {
-	row := q.db.QueryRowContext(ctx, createStore,
+	row := q.db.QueryRow(ctx, createStore,
 		arg.Gid,
 		arg.Name,
 		arg.Handle,
@@ -57,6 +58,9 @@ func (q *Queries) CreateStore(ctx context.Context, arg CreateStoreParams) (Store
 		&i.UpdatedAt,
 		&i.TenantID,
 		&i.Gid,
+		&i.DeletedAt,
+		&i.Settings,
+		&i.DefaultLocale,
 	)
 	return i, err
 }
@@ -65,7 +69,7 @@ const createStoreForTenant = `-- name: CreateStoreForTenant :one
 
 INSERT INTO stores (id, gid, name, handle, address, status, default_currency, timezone, plan, tenant_id, created_at, updated_at)
 VALUES (gen_random_uuid(), $1, $2, $3, '', 'active', 'USD', 'UTC', $4, $5, now(), now())
-RETURNING id, name, handle, address, status, default_currency, timezone, plan, created_at, updated_at, tenant_id, gid
+RETURNING id, name, handle, address, status, default_currency, timezone, plan, created_at, updated_at, tenant_id, gid, deleted_at, settings, default_locale
 `
 
 type CreateStoreForTenantParams struct {
@@ -78,7 +82,7 @@ type CreateStoreForTenantParams struct {
 
 // Tenant-scoped store queries
 func (q *Queries) CreateStoreForTenant(ctx context.Context, arg CreateStoreForTenantParams) (Store, error) {
-	row := q.db.QueryRowContext(ctx, createStoreForTenant,
+	row := q.db.QueryRow(ctx, createStoreForTenant,
 		arg.Gid,
 		arg.Name,
 		arg.Handle,
@@ -99,6 +103,9 @@ func (q *Queries) CreateStoreForTenant(ctx context.Context, arg CreateStoreForTe
 		&i.UpdatedAt,
 		&i.TenantID,
 		&i.Gid,
+		&i.DeletedAt,
+		&i.Settings,
+		&i.DefaultLocale,
 	)
 	return i, err
 }
@@ -108,32 +115,50 @@ DELETE FROM stores
 `
 
 func (q *Queries) DeleteAllStores(ctx context.Context) error {
-	_, err := q.db.ExecContext(ctx, deleteAllStores)
+	_, err := q.db.Exec(ctx, deleteAllStores)
 	return err
 }
 
-const deleteStoreForTenant = `-- name: DeleteStoreForTenant :exec
-DELETE FROM stores
-WHERE id = $1 AND tenant_id = $2
+const getDeletedStore = `-- name: GetDeletedStore :one
+SELECT id, name, handle, address, status, default_currency, timezone, plan, created_at, updated_at, tenant_id, gid, deleted_at, settings, default_locale FROM stores
+WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NOT NULL
 `
 
-type DeleteStoreForTenantParams struct {
+type GetDeletedStoreParams struct {
 	ID       uuid.UUID
 	TenantID uuid.NullUUID
 }
 
-func (q *Queries) DeleteStoreForTenant(ctx context.Context, arg DeleteStoreForTenantParams) error {
-	_, err := q.db.ExecContext(ctx, deleteStoreForTenant, arg.ID, arg.TenantID)
-	return err
+func (q *Queries) GetDeletedStore(ctx context.Context, arg GetDeletedStoreParams) (Store, error) {
+	row := q.db.QueryRow(ctx, getDeletedStore, arg.ID, arg.TenantID)
+	var i Store
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Handle,
+		&i.Address,
+		&i.Status,
+		&i.DefaultCurrency,
+		&i.Timezone,
+		&i.Plan,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TenantID,
+		&i.Gid,
+		&i.DeletedAt,
+		&i.Settings,
+		&i.DefaultLocale,
+	)
+	return i, err
 }
 
 const getStoreByGID = `-- name: GetStoreByGID :one
-SELECT id, name, handle, address, status, default_currency, timezone, plan, created_at, updated_at, tenant_id, gid FROM stores
+SELECT id, name, handle, address, status, default_currency, timezone, plan, created_at, updated_at, tenant_id, gid, deleted_at, settings, default_locale FROM stores
 WHERE gid = $1
 `
 
 func (q *Queries) GetStoreByGID(ctx context.Context, gid sql.NullInt64) (Store, error) {
-	row := q.db.QueryRowContext(ctx, getStoreByGID, gid)
+	row := q.db.QueryRow(ctx, getStoreByGID, gid)
 	var i Store
 	err := row.Scan(
 		&i.ID,
@@ -148,16 +173,50 @@ func (q *Queries) GetStoreByGID(ctx context.Context, gid sql.NullInt64) (Store,
 		&i.UpdatedAt,
 		&i.TenantID,
 		&i.Gid,
+		&i.DeletedAt,
+		&i.Settings,
+		&i.DefaultLocale,
 	)
 	return i, err
 }
 
 const getStoreByHandle = `-- name: GetStoreByHandle :one
-SELECT id, name, handle, address, status, default_currency, timezone, plan, created_at, updated_at, tenant_id, gid FROM stores WHERE handle = $1
+SELECT s.id, s.name, s.handle, s.address, s.status, s.default_currency, s.timezone, s.plan, s.created_at, s.updated_at, s.tenant_id, s.gid, s.deleted_at, s.settings, s.default_locale FROM stores s
+WHERE s.handle = $1 AND s.deleted_at IS NULL
+  AND NOT EXISTS (SELECT 1 FROM tenants t WHERE t.id = s.tenant_id AND t.status = 'suspended')
 `
 
+// Stores of suspended tenants are not found, so they stop being served
 func (q *Queries) GetStoreByHandle(ctx context.Context, handle string) (Store, error) {
-	row := q.db.QueryRowContext(ctx, getStoreByHandle, handle)
+	row := q.db.QueryRow(ctx, getStoreByHandle, handle)
+	var i Store
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Handle,
+		&i.Address,
+		&i.Status,
+		&i.DefaultCurrency,
+		&i.Timezone,
+		&i.Plan,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TenantID,
+		&i.Gid,
+		&i.DeletedAt,
+		&i.Settings,
+		&i.DefaultLocale,
+	)
+	return i, err
+}
+
+const getStoreByID = `-- name: GetStoreByID :one
+SELECT id, name, handle, address, status, default_currency, timezone, plan, created_at, updated_at, tenant_id, gid, deleted_at, settings, default_locale FROM stores
+WHERE id = $1
+`
+
+func (q *Queries) GetStoreByID(ctx context.Context, id uuid.UUID) (Store, error) {
+	row := q.db.QueryRow(ctx, getStoreByID, id)
 	var i Store
 	err := row.Scan(
 		&i.ID,
@@ -172,13 +231,16 @@ func (q *Queries) GetStoreByHandle(ctx context.Context, handle string) (Store, e
 		&i.UpdatedAt,
 		&i.TenantID,
 		&i.Gid,
+		&i.DeletedAt,
+		&i.Settings,
+		&i.DefaultLocale,
 	)
 	return i, err
 }
 
 const getStoreByTenantAndHandle = `-- name: GetStoreByTenantAndHandle :one
-SELECT id, name, handle, address, status, default_currency, timezone, plan, created_at, updated_at, tenant_id, gid FROM stores
-WHERE tenant_id = $1 AND handle = $2
+SELECT id, name, handle, address, status, default_currency, timezone, plan, created_at, updated_at, tenant_id, gid, deleted_at, settings, default_locale FROM stores
+WHERE tenant_id = $1 AND handle = $2 AND deleted_at IS NULL
 `
 
 type GetStoreByTenantAndHandleParams struct {
@@ -187,7 +249,7 @@ type GetStoreByTenantAndHandleParams struct {
 }
 
 func (q *Queries) GetStoreByTenantAndHandle(ctx context.Context, arg GetStoreByTenantAndHandleParams) (Store, error) {
-	row := q.db.QueryRowContext(ctx, getStoreByTenantAndHandle, arg.TenantID, arg.Handle)
+	row := q.db.QueryRow(ctx, getStoreByTenantAndHandle, arg.TenantID, arg.Handle)
 	var i Store
 	err := row.Scan(
 		&i.ID,
@@ -202,13 +264,16 @@ func (q *Queries) GetStoreByTenantAndHandle(ctx context.Context, arg GetStoreByT
 		&i.UpdatedAt,
 		&i.TenantID,
 		&i.Gid,
+		&i.DeletedAt,
+		&i.Settings,
+		&i.DefaultLocale,
 	)
 	return i, err
 }
 
 const getStoreByTenantAndID = `-- name: GetStoreByTenantAndID :one
-SELECT id, name, handle, address, status, default_currency, timezone, plan, created_at, updated_at, tenant_id, gid FROM stores
-WHERE tenant_id = $1 AND id = $2
+SELECT id, name, handle, address, status, default_currency, timezone, plan, created_at, updated_at, tenant_id, gid, deleted_at, settings, default_locale FROM stores
+WHERE tenant_id = $1 AND id = $2 AND deleted_at IS NULL
 `
 
 type GetStoreByTenantAndIDParams struct {
@@ -217,7 +282,7 @@ type GetStoreByTenantAndIDParams struct {
 }
 
 func (q *Queries) GetStoreByTenantAndID(ctx context.Context, arg GetStoreByTenantAndIDParams) (Store, error) {
-	row := q.db.QueryRowContext(ctx, getStoreByTenantAndID, arg.TenantID, arg.ID)
+	row := q.db.QueryRow(ctx, getStoreByTenantAndID, arg.TenantID, arg.ID)
 	var i Store
 	err := row.Scan(
 		&i.ID,
@@ -232,16 +297,38 @@ func (q *Queries) GetStoreByTenantAndID(ctx context.Context, arg GetStoreByTenan
 		&i.UpdatedAt,
 		&i.TenantID,
 		&i.Gid,
+		&i.DeletedAt,
+		&i.Settings,
+		&i.DefaultLocale,
 	)
 	return i, err
 }
 
-const getStores = `-- name: GetStores :many
-SELECT id, name, handle, address, status, default_currency, timezone, plan, created_at, updated_at, tenant_id, gid FROM stores ORDER BY created_at ASC
+const getStoreSettings = `-- name: GetStoreSettings :one
+SELECT settings FROM stores
+WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
 `
 
-func (q *Queries) GetStores(ctx context.Context) ([]Store, error) {
-	rows, err := q.db.QueryContext(ctx, getStores)
+type GetStoreSettingsParams struct {
+	ID       uuid.UUID
+	TenantID uuid.NullUUID
+}
+
+func (q *Queries) GetStoreSettings(ctx context.Context, arg GetStoreSettingsParams) (json.RawMessage, error) {
+	row := q.db.QueryRow(ctx, getStoreSettings, arg.ID, arg.TenantID)
+	var settings json.RawMessage
+	err := row.Scan(&settings)
+	return settings, err
+}
+
+const getStoresByTenantID = `-- name: GetStoresByTenantID :many
+SELECT id, name, handle, address, status, default_currency, timezone, plan, created_at, updated_at, tenant_id, gid, deleted_at, settings, default_locale FROM stores
+WHERE tenant_id = $1 AND deleted_at IS NULL
+ORDER BY created_at DESC
+`
+
+func (q *Queries) GetStoresByTenantID(ctx context.Context, tenantID uuid.NullUUID) ([]Store, error) {
+	rows, err := q.db.Query(ctx, getStoresByTenantID, tenantID)
 	if err != nil {
 		return nil, err
 	}
@@ -262,83 +349,131 @@ func (q *Queries) GetStores(ctx context.Context) ([]Store, error) {
 			&i.UpdatedAt,
 			&i.TenantID,
 			&i.Gid,
+			&i.DeletedAt,
+			&i.Settings,
+			&i.DefaultLocale,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 	return items, nil
 }
 
-const getStoresByTenantID = `-- name: GetStoresByTenantID :many
-SELECT id, name, handle, address, status, default_currency, timezone, plan, created_at, updated_at, tenant_id, gid FROM stores
+const getStoresByTenantIDPaginated = `-- name: GetStoresByTenantIDPaginated :many
+SELECT id, gid, name, handle, address, status, default_currency, default_locale, timezone, plan, tenant_id, created_at, updated_at
+FROM stores
 WHERE tenant_id = $1
-ORDER BY created_at DESC
+  AND deleted_at IS NULL
+  AND (
+    $2::boolean = false
+    OR (created_at, id) < ($3::timestamptz, $4::uuid)
+  )
+ORDER BY created_at DESC, id DESC
+LIMIT $5
 `
 
-func (q *Queries) GetStoresByTenantID(ctx context.Context, tenantID uuid.NullUUID) ([]Store, error) {
-	rows, err := q.db.QueryContext(ctx, getStoresByTenantID, tenantID)
+type GetStoresByTenantIDPaginatedParams struct {
+	TenantID        uuid.NullUUID
+	HasCursor       bool
+	CursorCreatedAt time.Time
+	CursorID        uuid.UUID
+	RowLimit        int32
+}
+
+type GetStoresByTenantIDPaginatedRow struct {
+	ID              uuid.UUID
+	Gid             sql.NullInt64
+	Name            string
+	Handle          string
+	Address         string
+	Status          string
+	DefaultCurrency string
+	DefaultLocale   string
+	Timezone        string
+	Plan            string
+	TenantID        uuid.NullUUID
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (q *Queries) GetStoresByTenantIDPaginated(ctx context.Context, arg GetStoresByTenantIDPaginatedParams) ([]GetStoresByTenantIDPaginatedRow, error) {
+	rows, err := q.db.Query(ctx, getStoresByTenantIDPaginated,
+		arg.TenantID,
+		arg.HasCursor,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.RowLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Store
+	var items []GetStoresByTenantIDPaginatedRow
 	for rows.Next() {
-		var i Store
+		var i GetStoresByTenantIDPaginatedRow
 		if err := rows.Scan(
 			&i.ID,
+			&i.Gid,
 			&i.Name,
 			&i.Handle,
 			&i.Address,
 			&i.Status,
 			&i.DefaultCurrency,
+			&i.DefaultLocale,
 			&i.Timezone,
 			&i.Plan,
+			&i.TenantID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.TenantID,
-			&i.Gid,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 	return items, nil
 }
 
-const getStoresByTenantIDPaginated = `-- name: GetStoresByTenantIDPaginated :many
-SELECT id, gid, name, handle, address, status, default_currency, timezone, plan, tenant_id, created_at, updated_at
-FROM stores
-WHERE tenant_id = $1
+const getStoresByUserIDPaginated = `-- name: GetStoresByUserIDPaginated :many
+SELECT s.id, s.gid, s.name, s.handle, s.address, s.status, s.default_currency, s.default_locale, s.timezone, s.plan, s.tenant_id, s.created_at, s.updated_at
+FROM stores s
+JOIN tenant_users tu ON tu.tenant_id = s.tenant_id
+WHERE tu.user_id = $1
+  AND tu.status = 'active'
+  AND EXISTS (
+    SELECT 1 FROM tenant_user_roles tur
+    JOIN role_permissions rp ON rp.role_id = tur.role_id
+    JOIN permissions p ON p.id = rp.permission_id
+    WHERE tur.tenant_user_id = tu.id
+      AND p.key = $2
+  )
+  AND s.deleted_at IS NULL
+  AND ($3::uuid IS NULL OR s.tenant_id = $3::uuid)
   AND (
-    $2::boolean = false
-    OR (created_at, id) < ($3::timestamptz, $4::uuid)
+    NOT $4::boolean
+    OR (s.created_at, s.id) < ($5::timestamptz, $6::uuid)
   )
-ORDER BY created_at DESC, id DESC
-LIMIT $5
+ORDER BY s.created_at DESC, s.id DESC
+LIMIT $7
 `
 
-type GetStoresByTenantIDPaginatedParams struct {
-	TenantID uuid.NullUUID
-	Column2  bool
-	Column3  time.Time
-	Column4  uuid.UUID
-	Limit    int32
+type GetStoresByUserIDPaginatedParams struct {
+	UserID          uuid.UUID
+	Permission      string
+	TenantID        uuid.NullUUID
+	HasCursor       bool
+	CursorCreatedAt time.Time
+	CursorID        uuid.UUID
+	RowLimit        int32
 }
 
-type GetStoresByTenantIDPaginatedRow struct {
+type GetStoresByUserIDPaginatedRow struct {
 	ID              uuid.UUID
 	Gid             sql.NullInt64
 	Name            string
@@ -346,6 +481,7 @@ type GetStoresByTenantIDPaginatedRow struct {
 	Address         string
 	Status          string
 	DefaultCurrency string
+	DefaultLocale   string
 	Timezone        string
 	Plan            string
 	TenantID        uuid.NullUUID
@@ -353,21 +489,25 @@ type GetStoresByTenantIDPaginatedRow struct {
 	UpdatedAt       time.Time
 }
 
-func (q *Queries) GetStoresByTenantIDPaginated(ctx context.Context, arg GetStoresByTenantIDPaginatedParams) ([]GetStoresByTenantIDPaginatedRow, error) {
-	rows, err := q.db.QueryContext(ctx, getStoresByTenantIDPaginated,
+// Stores in every tenant where one of the roles of the user, an active
+// member, grants the permission, optionally one
+func (q *Queries) GetStoresByUserIDPaginated(ctx context.Context, arg GetStoresByUserIDPaginatedParams) ([]GetStoresByUserIDPaginatedRow, error) {
+	rows, err := q.db.Query(ctx, getStoresByUserIDPaginated,
+		arg.UserID,
+		arg.Permission,
 		arg.TenantID,
-		arg.Column2,
-		arg.Column3,
-		arg.Column4,
-		arg.Limit,
+		arg.HasCursor,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.RowLimit,
 	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetStoresByTenantIDPaginatedRow
+	var items []GetStoresByUserIDPaginatedRow
 	for rows.Next() {
-		var i GetStoresByTenantIDPaginatedRow
+		var i GetStoresByUserIDPaginatedRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Gid,
@@ -376,6 +516,7 @@ func (q *Queries) GetStoresByTenantIDPaginated(ctx context.Context, arg GetStore
 			&i.Address,
 			&i.Status,
 			&i.DefaultCurrency,
+			&i.DefaultLocale,
 			&i.Timezone,
 			&i.Plan,
 			&i.TenantID,
@@ -386,15 +527,259 @@ func (q *Queries) GetStoresByTenantIDPaginated(ctx context.Context, arg GetStore
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+	return items, nil
+}
+
+const listDeletedStores = `-- name: ListDeletedStores :many
+SELECT id, name, handle, address, status, default_currency, timezone, plan, created_at, updated_at, tenant_id, gid, deleted_at, settings, default_locale FROM stores
+WHERE tenant_id = $1
+  AND deleted_at IS NOT NULL
+  AND (
+    NOT $2::boolean
+    OR (deleted_at, id) < ($3::timestamptz, $4::uuid)
+  )
+ORDER BY deleted_at DESC, id DESC
+LIMIT $5
+`
+
+type ListDeletedStoresParams struct {
+	TenantID        uuid.NullUUID
+	HasCursor       bool
+	CursorDeletedAt time.Time
+	CursorID        uuid.UUID
+	RowLimit        int32
+}
+
+// Most recently deleted first
+func (q *Queries) ListDeletedStores(ctx context.Context, arg ListDeletedStoresParams) ([]Store, error) {
+	rows, err := q.db.Query(ctx, listDeletedStores,
+		arg.TenantID,
+		arg.HasCursor,
+		arg.CursorDeletedAt,
+		arg.CursorID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Store
+	for rows.Next() {
+		var i Store
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Handle,
+			&i.Address,
+			&i.Status,
+			&i.DefaultCurrency,
+			&i.Timezone,
+			&i.Plan,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.TenantID,
+			&i.Gid,
+			&i.DeletedAt,
+			&i.Settings,
+			&i.DefaultLocale,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 	return items, nil
 }
 
+const listStoreHandlesWithPrefix = `-- name: ListStoreHandlesWithPrefix :many
+SELECT handle FROM stores
+WHERE tenant_id = $1
+  AND (handle = $2::text OR handle LIKE $2::text || '-%')
+`
+
+type ListStoreHandlesWithPrefixParams struct {
+	TenantID uuid.NullUUID
+	Handle   string
+}
+
+// The handle and any taken with a numeric suffix, such as shop-2, for
+// finding a free one
+func (q *Queries) ListStoreHandlesWithPrefix(ctx context.Context, arg ListStoreHandlesWithPrefixParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, listStoreHandlesWithPrefix, arg.TenantID, arg.Handle)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var handle string
+		if err := rows.Scan(&handle); err != nil {
+			return nil, err
+		}
+		items = append(items, handle)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTenantStorePlans = `-- name: ListTenantStorePlans :many
+SELECT DISTINCT plan FROM stores
+WHERE tenant_id = $1 AND deleted_at IS NULL
+`
+
+// Plans of the live stores in a tenant, for sizing its rate limit
+func (q *Queries) ListTenantStorePlans(ctx context.Context, tenantID uuid.NullUUID) ([]string, error) {
+	rows, err := q.db.Query(ctx, listTenantStorePlans, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var plan string
+		if err := rows.Scan(&plan); err != nil {
+			return nil, err
+		}
+		items = append(items, plan)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserStorePlans = `-- name: ListUserStorePlans :many
+SELECT DISTINCT s.plan FROM stores s
+JOIN tenant_users tu ON tu.tenant_id = s.tenant_id
+WHERE tu.user_id = $1
+  AND tu.status = 'active'
+  AND s.deleted_at IS NULL
+`
+
+// Plans of the live stores in every tenant the user is an active member of
+func (q *Queries) ListUserStorePlans(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	rows, err := q.db.Query(ctx, listUserStorePlans, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var plan string
+		if err := rows.Scan(&plan); err != nil {
+			return nil, err
+		}
+		items = append(items, plan)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeDeletedStores = `-- name: PurgeDeletedStores :execrows
+DELETE FROM stores
+WHERE id IN (
+    SELECT id FROM stores
+    WHERE deleted_at < $1::timestamptz
+    ORDER BY deleted_at
+    LIMIT $2
+)
+`
+
+type PurgeDeletedStoresParams struct {
+	Before   time.Time
+	RowLimit int32
+}
+
+// Permanently removes up to row_limit stores deleted before the cutoff,
+// with everything in them
+func (q *Queries) PurgeDeletedStores(ctx context.Context, arg PurgeDeletedStoresParams) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedStores, arg.Before, arg.RowLimit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreStore = `-- name: RestoreStore :one
+UPDATE stores
+SET deleted_at = NULL, updated_at = now()
+WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NOT NULL
+RETURNING id, name, handle, address, status, default_currency, timezone, plan, created_at, updated_at, tenant_id, gid, deleted_at, settings, default_locale
+`
+
+type RestoreStoreParams struct {
+	ID       uuid.UUID
+	TenantID uuid.NullUUID
+}
+
+func (q *Queries) RestoreStore(ctx context.Context, arg RestoreStoreParams) (Store, error) {
+	row := q.db.QueryRow(ctx, restoreStore, arg.ID, arg.TenantID)
+	var i Store
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Handle,
+		&i.Address,
+		&i.Status,
+		&i.DefaultCurrency,
+		&i.Timezone,
+		&i.Plan,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TenantID,
+		&i.Gid,
+		&i.DeletedAt,
+		&i.Settings,
+		&i.DefaultLocale,
+	)
+	return i, err
+}
+
+const softDeleteStore = `-- name: SoftDeleteStore :one
+UPDATE stores
+SET deleted_at = now()
+WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+RETURNING id, name, handle, address, status, default_currency, timezone, plan, created_at, updated_at, tenant_id, gid, deleted_at, settings, default_locale
+`
+
+type SoftDeleteStoreParams struct {
+	ID       uuid.UUID
+	TenantID uuid.NullUUID
+}
+
+// Hides the store until it is restored or purged
+func (q *Queries) SoftDeleteStore(ctx context.Context, arg SoftDeleteStoreParams) (Store, error) {
+	row := q.db.QueryRow(ctx, softDeleteStore, arg.ID, arg.TenantID)
+	var i Store
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Handle,
+		&i.Address,
+		&i.Status,
+		&i.DefaultCurrency,
+		&i.Timezone,
+		&i.Plan,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TenantID,
+		&i.Gid,
+		&i.DeletedAt,
+		&i.Settings,
+		&i.DefaultLocale,
+	)
+	return i, err
+}
+
 const updateStore = `-- name: UpdateStore :one
 UPDATE stores
 SET
@@ -402,7 +787,7 @@ SET
     handle = $3,
     updated_at = now()
 WHERE id = $1
-RETURNING id, name, handle, address, status, default_currency, timezone, plan, created_at, updated_at, tenant_id, gid
+RETURNING id, name, handle, address, status, default_currency, timezone, plan, created_at, updated_at, tenant_id, gid, deleted_at, settings, default_locale
 `
 
 type UpdateStoreParams struct {
@@ -412,7 +797,7 @@ type UpdateStoreParams struct {
 }
 
 func (q *Queries) UpdateStore(ctx context.Context, arg UpdateStoreParams) (Store, error) {
-	row := q.db.QueryRowContext(ctx, updateStore, arg.ID, arg.Name, arg.Handle)
+	row := q.db.QueryRow(ctx, updateStore, arg.ID, arg.Name, arg.Handle)
 	var i Store
 	err := row.Scan(
 		&i.ID,
@@ -427,6 +812,9 @@ func (q *Queries) UpdateStore(ctx context.Context, arg UpdateStoreParams) (Store
 		&i.UpdatedAt,
 		&i.TenantID,
 		&i.Gid,
+		&i.DeletedAt,
+		&i.Settings,
+		&i.DefaultLocale,
 	)
 	return i, err
 }
@@ -434,41 +822,117 @@ func (q *Queries) UpdateStore(ctx context.Context, arg UpdateStoreParams) (Store
 const updateStoreForTenant = `-- name: UpdateStoreForTenant :one
 UPDATE stores
 SET
-    name = $3,
-    handle = $4,
-    address = $5,
-    status = $6,
-    default_currency = $7,
-    timezone = $8,
-    plan = $9,
+    name = COALESCE($1::text, name),
+    address = COALESCE($2::text, address),
+    default_currency = COALESCE($3::text, default_currency),
+    timezone = COALESCE($4::text, timezone),
+    plan = COALESCE($5::text, plan),
+    default_locale = COALESCE($6::text, default_locale),
     updated_at = now()
-WHERE id = $1 AND tenant_id = $2
-RETURNING id, name, handle, address, status, default_currency, timezone, plan, created_at, updated_at, tenant_id, gid
+WHERE id = $7 AND tenant_id = $8 AND deleted_at IS NULL
+RETURNING id, name, handle, address, status, default_currency, timezone, plan, created_at, updated_at, tenant_id, gid, deleted_at, settings, default_locale
 `
 
 type UpdateStoreForTenantParams struct {
+	Name            sql.NullString
+	Address         sql.NullString
+	DefaultCurrency sql.NullString
+	Timezone        sql.NullString
+	Plan            sql.NullString
+	DefaultLocale   sql.NullString
 	ID              uuid.UUID
 	TenantID        uuid.NullUUID
-	Name            string
-	Handle          string
-	Address         string
-	Status          string
-	DefaultCurrency string
-	Timezone        string
-	Plan            string
 }
 
+// Only the fields given change
 func (q *Queries) UpdateStoreForTenant(ctx context.Context, arg UpdateStoreForTenantParams) (Store, error) {
-	row := q.db.QueryRowContext(ctx, updateStoreForTenant,
-		arg.ID,
-		arg.TenantID,
+	row := q.db.QueryRow(ctx, updateStoreForTenant,
 		arg.Name,
-		arg.Handle,
 		arg.Address,
-		arg.Status,
 		arg.DefaultCurrency,
 		arg.Timezone,
 		arg.Plan,
+		arg.DefaultLocale,
+		arg.ID,
+		arg.TenantID,
+	)
+	var i Store
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Handle,
+		&i.Address,
+		&i.Status,
+		&i.DefaultCurrency,
+		&i.Timezone,
+		&i.Plan,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TenantID,
+		&i.Gid,
+		&i.DeletedAt,
+		&i.Settings,
+		&i.DefaultLocale,
+	)
+	return i, err
+}
+
+const updateStoreSettings = `-- name: UpdateStoreSettings :one
+UPDATE stores
+SET settings = $1::jsonb, updated_at = now()
+WHERE id = $2
+  AND tenant_id = $3
+  AND deleted_at IS NULL
+  AND settings = $4::jsonb
+RETURNING settings
+`
+
+type UpdateStoreSettingsParams struct {
+	Settings json.RawMessage
+	ID       uuid.UUID
+	TenantID uuid.NullUUID
+	Previous json.RawMessage
+}
+
+// Conditional on the settings read, so two concurrent patches cannot lose
+// each other
+func (q *Queries) UpdateStoreSettings(ctx context.Context, arg UpdateStoreSettingsParams) (json.RawMessage, error) {
+	row := q.db.QueryRow(ctx, updateStoreSettings,
+		arg.Settings,
+		arg.ID,
+		arg.TenantID,
+		arg.Previous,
+	)
+	var settings json.RawMessage
+	err := row.Scan(&settings)
+	return settings, err
+}
+
+const updateStoreStatus = `-- name: UpdateStoreStatus :one
+UPDATE stores
+SET status = $1, updated_at = now()
+WHERE id = $2
+  AND tenant_id = $3
+  AND status = $4
+  AND deleted_at IS NULL
+RETURNING id, name, handle, address, status, default_currency, timezone, plan, created_at, updated_at, tenant_id, gid, deleted_at, settings, default_locale
+`
+
+type UpdateStoreStatusParams struct {
+	Status     string
+	ID         uuid.UUID
+	TenantID   uuid.NullUUID
+	FromStatus string
+}
+
+// Conditional on the status read, so concurrent changes cannot both pass
+// the transition check
+func (q *Queries) UpdateStoreStatus(ctx context.Context, arg UpdateStoreStatusParams) (Store, error) {
+	row := q.db.QueryRow(ctx, updateStoreStatus,
+		arg.Status,
+		arg.ID,
+		arg.TenantID,
+		arg.FromStatus,
 	)
 	var i Store
 	err := row.Scan(
@@ -484,6 +948,9 @@ func (q *Queries) UpdateStoreForTenant(ctx context.Context, arg UpdateStoreForTe
 		&i.UpdatedAt,
 		&i.TenantID,
 		&i.Gid,
+		&i.DeletedAt,
+		&i.Settings,
+		&i.DefaultLocale,
 	)
 	return i, err
 }
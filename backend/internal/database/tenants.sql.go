@@ -13,10 +13,33 @@ import (
 	"github.com/google/uuid"
 )
 
+const countActiveTenantOwners = `-- name: CountActiveTenantOwners :one
+SELECT count(DISTINCT tu.id)
+FROM tenant_users tu
+JOIN tenant_user_roles tur ON tur.tenant_user_id = tu.id
+JOIN roles r ON r.id = tur.role_id
+WHERE tu.tenant_id = $1
+  AND tu.status = 'active'
+  AND r.is_system
+  AND r.name = $2
+`
+
+type CountActiveTenantOwnersParams struct {
+	TenantID  uuid.UUID
+	OwnerRole string
+}
+
+func (q *Queries) CountActiveTenantOwners(ctx context.Context, arg CountActiveTenantOwnersParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countActiveTenantOwners, arg.TenantID, arg.OwnerRole)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createTenant = `-- name: CreateTenant :one
 INSERT INTO tenants (id, gid, name, status, created_at, updated_at)
 VALUES (gen_random_uuid(), $1, $2, 'active', now(), now())
-RETURNING id, name, status, created_at, updated_at, gid
+RETURNING id, name, status, created_at, updated_at, gid, require_mfa, suspended_at, suspension_reason, require_sso
 `
 
 type CreateTenantParams struct {
@@ -25,7 +48,7 @@ type CreateTenantParams struct {
 }
 
 func (q *Queries) CreateTenant(ctx context.Context, arg CreateTenantParams) (Tenant, error) {
-	row := q.db.QueryRowContext(ctx, createTenant, arg.Gid, arg.Name)
+	row := q.db.QueryRow(ctx, createTenant, arg.Gid, arg.Name)
 	var i Tenant
 	err := row.Scan(
 		&i.ID,
@@ -34,6 +57,10 @@ func (q *Queries) CreateTenant(ctx context.Context, arg CreateTenantParams) (Ten
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Gid,
+		&i.RequireMfa,
+		&i.SuspendedAt,
+		&i.SuspensionReason,
+		&i.RequireSso,
 	)
 	return i, err
 }
@@ -51,7 +78,33 @@ type CreateTenantUserParams struct {
 }
 
 func (q *Queries) CreateTenantUser(ctx context.Context, arg CreateTenantUserParams) (TenantUser, error) {
-	row := q.db.QueryRowContext(ctx, createTenantUser, arg.TenantID, arg.UserID, arg.Status)
+	row := q.db.QueryRow(ctx, createTenantUser, arg.TenantID, arg.UserID, arg.Status)
+	var i TenantUser
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.UserID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteTenantUser = `-- name: DeleteTenantUser :one
+DELETE FROM tenant_users
+WHERE id = $1 AND tenant_id = $2
+RETURNING id, tenant_id, user_id, status, created_at, updated_at
+`
+
+type DeleteTenantUserParams struct {
+	ID       uuid.UUID
+	TenantID uuid.UUID
+}
+
+// Role assignments go with the membership
+func (q *Queries) DeleteTenantUser(ctx context.Context, arg DeleteTenantUserParams) (TenantUser, error) {
+	row := q.db.QueryRow(ctx, deleteTenantUser, arg.ID, arg.TenantID)
 	var i TenantUser
 	err := row.Scan(
 		&i.ID,
@@ -65,12 +118,12 @@ func (q *Queries) CreateTenantUser(ctx context.Context, arg CreateTenantUserPara
 }
 
 const getTenantByGID = `-- name: GetTenantByGID :one
-SELECT id, name, status, created_at, updated_at, gid FROM tenants
+SELECT id, name, status, created_at, updated_at, gid, require_mfa, suspended_at, suspension_reason, require_sso FROM tenants
 WHERE gid = $1
 `
 
 func (q *Queries) GetTenantByGID(ctx context.Context, gid sql.NullInt64) (Tenant, error) {
-	row := q.db.QueryRowContext(ctx, getTenantByGID, gid)
+	row := q.db.QueryRow(ctx, getTenantByGID, gid)
 	var i Tenant
 	err := row.Scan(
 		&i.ID,
@@ -79,17 +132,21 @@ func (q *Queries) GetTenantByGID(ctx context.Context, gid sql.NullInt64) (Tenant
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Gid,
+		&i.RequireMfa,
+		&i.SuspendedAt,
+		&i.SuspensionReason,
+		&i.RequireSso,
 	)
 	return i, err
 }
 
 const getTenantByID = `-- name: GetTenantByID :one
-SELECT id, name, status, created_at, updated_at, gid FROM tenants
+SELECT id, name, status, created_at, updated_at, gid, require_mfa, suspended_at, suspension_reason, require_sso FROM tenants
 WHERE id = $1
 `
 
 func (q *Queries) GetTenantByID(ctx context.Context, id uuid.UUID) (Tenant, error) {
-	row := q.db.QueryRowContext(ctx, getTenantByID, id)
+	row := q.db.QueryRow(ctx, getTenantByID, id)
 	var i Tenant
 	err := row.Scan(
 		&i.ID,
@@ -98,17 +155,21 @@ func (q *Queries) GetTenantByID(ctx context.Context, id uuid.UUID) (Tenant, erro
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Gid,
+		&i.RequireMfa,
+		&i.SuspendedAt,
+		&i.SuspensionReason,
+		&i.RequireSso,
 	)
 	return i, err
 }
 
 const getTenantByName = `-- name: GetTenantByName :one
-SELECT id, name, status, created_at, updated_at, gid FROM tenants
+SELECT id, name, status, created_at, updated_at, gid, require_mfa, suspended_at, suspension_reason, require_sso FROM tenants
 WHERE name = $1
 `
 
 func (q *Queries) GetTenantByName(ctx context.Context, name string) (Tenant, error) {
-	row := q.db.QueryRowContext(ctx, getTenantByName, name)
+	row := q.db.QueryRow(ctx, getTenantByName, name)
 	var i Tenant
 	err := row.Scan(
 		&i.ID,
@@ -117,6 +178,10 @@ func (q *Queries) GetTenantByName(ctx context.Context, name string) (Tenant, err
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Gid,
+		&i.RequireMfa,
+		&i.SuspendedAt,
+		&i.SuspensionReason,
+		&i.RequireSso,
 	)
 	return i, err
 }
@@ -132,7 +197,7 @@ type GetTenantUserParams struct {
 }
 
 func (q *Queries) GetTenantUser(ctx context.Context, arg GetTenantUserParams) (TenantUser, error) {
-	row := q.db.QueryRowContext(ctx, getTenantUser, arg.TenantID, arg.UserID)
+	row := q.db.QueryRow(ctx, getTenantUser, arg.TenantID, arg.UserID)
 	var i TenantUser
 	err := row.Scan(
 		&i.ID,
@@ -151,7 +216,7 @@ WHERE id = $1
 `
 
 func (q *Queries) GetTenantUserByID(ctx context.Context, id uuid.UUID) (TenantUser, error) {
-	row := q.db.QueryRowContext(ctx, getTenantUserByID, id)
+	row := q.db.QueryRow(ctx, getTenantUserByID, id)
 	var i TenantUser
 	err := row.Scan(
 		&i.ID,
@@ -170,7 +235,7 @@ WHERE tenant_id = $1
 `
 
 func (q *Queries) GetTenantUsersByTenantID(ctx context.Context, tenantID uuid.UUID) ([]TenantUser, error) {
-	rows, err := q.db.QueryContext(ctx, getTenantUsersByTenantID, tenantID)
+	rows, err := q.db.Query(ctx, getTenantUsersByTenantID, tenantID)
 	if err != nil {
 		return nil, err
 	}
@@ -190,9 +255,6 @@ func (q *Queries) GetTenantUsersByTenantID(ctx context.Context, tenantID uuid.UU
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -216,7 +278,7 @@ type GetTenantUsersWithDetailsRow struct {
 }
 
 func (q *Queries) GetTenantUsersWithDetails(ctx context.Context, tenantID uuid.UUID) ([]GetTenantUsersWithDetailsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getTenantUsersWithDetails, tenantID)
+	rows, err := q.db.Query(ctx, getTenantUsersWithDetails, tenantID)
 	if err != nil {
 		return nil, err
 	}
@@ -237,9 +299,6 @@ func (q *Queries) GetTenantUsersWithDetails(ctx context.Context, tenantID uuid.U
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -247,43 +306,53 @@ func (q *Queries) GetTenantUsersWithDetails(ctx context.Context, tenantID uuid.U
 }
 
 const getTenantUsersWithDetailsPaginated = `-- name: GetTenantUsersWithDetailsPaginated :many
-SELECT tu.id, tu.tenant_id, tu.user_id, tu.status, tu.created_at, tu.updated_at, u.email
+SELECT tu.id, tu.tenant_id, tu.user_id, tu.status, tu.created_at, tu.updated_at, u.email, u.display_name
 FROM tenant_users tu
 JOIN users u ON tu.user_id = u.id
 WHERE tu.tenant_id = $1
   AND (
-    $2::boolean = false
-    OR (tu.created_at, tu.id) < ($3::timestamptz, $4::uuid)
+    $2::text IS NULL
+    OR strpos(lower(u.email), lower($2::text)) > 0
+    OR strpos(lower(coalesce(u.display_name, '')), lower($2::text)) > 0
+  )
+  AND (
+    $3::boolean = false
+    OR (tu.created_at, tu.id) < ($4::timestamptz, $5::uuid)
   )
 ORDER BY tu.created_at DESC, tu.id DESC
-LIMIT $5
+LIMIT $6
 `
 
 type GetTenantUsersWithDetailsPaginatedParams struct {
-	TenantID uuid.UUID
-	Column2  bool
-	Column3  time.Time
-	Column4  uuid.UUID
-	Limit    int32
+	TenantID        uuid.UUID
+	Query           sql.NullString
+	HasCursor       bool
+	CursorCreatedAt time.Time
+	CursorID        uuid.UUID
+	RowLimit        int32
 }
 
 type GetTenantUsersWithDetailsPaginatedRow struct {
-	ID        uuid.UUID
-	TenantID  uuid.UUID
-	UserID    uuid.UUID
-	Status    string
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Email     string
+	ID          uuid.UUID
+	TenantID    uuid.UUID
+	UserID      uuid.UUID
+	Status      string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Email       string
+	DisplayName sql.NullString
 }
 
+// query matches part of the email or display name, so members can find
+// each other without seeing anyone outside the tenant
 func (q *Queries) GetTenantUsersWithDetailsPaginated(ctx context.Context, arg GetTenantUsersWithDetailsPaginatedParams) ([]GetTenantUsersWithDetailsPaginatedRow, error) {
-	rows, err := q.db.QueryContext(ctx, getTenantUsersWithDetailsPaginated,
+	rows, err := q.db.Query(ctx, getTenantUsersWithDetailsPaginated,
 		arg.TenantID,
-		arg.Column2,
-		arg.Column3,
-		arg.Column4,
-		arg.Limit,
+		arg.Query,
+		arg.HasCursor,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.RowLimit,
 	)
 	if err != nil {
 		return nil, err
@@ -300,14 +369,12 @@ func (q *Queries) GetTenantUsersWithDetailsPaginated(ctx context.Context, arg Ge
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Email,
+			&i.DisplayName,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -315,13 +382,13 @@ func (q *Queries) GetTenantUsersWithDetailsPaginated(ctx context.Context, arg Ge
 }
 
 const getTenantsByUserID = `-- name: GetTenantsByUserID :many
-SELECT t.id, t.name, t.status, t.created_at, t.updated_at, t.gid FROM tenants t
+SELECT t.id, t.name, t.status, t.created_at, t.updated_at, t.gid, t.require_mfa, t.suspended_at, t.suspension_reason, t.require_sso FROM tenants t
 JOIN tenant_users tu ON t.id = tu.tenant_id
 WHERE tu.user_id = $1 AND tu.status = 'active'
 `
 
 func (q *Queries) GetTenantsByUserID(ctx context.Context, userID uuid.UUID) ([]Tenant, error) {
-	rows, err := q.db.QueryContext(ctx, getTenantsByUserID, userID)
+	rows, err := q.db.Query(ctx, getTenantsByUserID, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -336,14 +403,15 @@ func (q *Queries) GetTenantsByUserID(ctx context.Context, userID uuid.UUID) ([]T
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Gid,
+			&i.RequireMfa,
+			&i.SuspendedAt,
+			&i.SuspensionReason,
+			&i.RequireSso,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -365,11 +433,11 @@ LIMIT $5
 `
 
 type GetTenantsByUserIDPaginatedParams struct {
-	UserID  uuid.UUID
-	Column2 bool
-	Column3 time.Time
-	Column4 uuid.UUID
-	Limit   int32
+	UserID          uuid.UUID
+	HasCursor       bool
+	CursorCreatedAt time.Time
+	CursorID        uuid.UUID
+	RowLimit        int32
 }
 
 type GetTenantsByUserIDPaginatedRow struct {
@@ -382,12 +450,12 @@ type GetTenantsByUserIDPaginatedRow struct {
 }
 
 func (q *Queries) GetTenantsByUserIDPaginated(ctx context.Context, arg GetTenantsByUserIDPaginatedParams) ([]GetTenantsByUserIDPaginatedRow, error) {
-	rows, err := q.db.QueryContext(ctx, getTenantsByUserIDPaginated,
+	rows, err := q.db.Query(ctx, getTenantsByUserIDPaginated,
 		arg.UserID,
-		arg.Column2,
-		arg.Column3,
-		arg.Column4,
-		arg.Limit,
+		arg.HasCursor,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.RowLimit,
 	)
 	if err != nil {
 		return nil, err
@@ -408,20 +476,92 @@ func (q *Queries) GetTenantsByUserIDPaginated(ctx context.Context, arg GetTenant
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+	return items, nil
+}
+
+const isTenantUserOwner = `-- name: IsTenantUserOwner :one
+SELECT EXISTS (
+    SELECT 1 FROM tenant_user_roles tur
+    JOIN roles r ON r.id = tur.role_id
+    WHERE tur.tenant_user_id = $1
+      AND r.is_system
+      AND r.name = $2
+) AS is_owner
+`
+
+type IsTenantUserOwnerParams struct {
+	TenantUserID uuid.UUID
+	OwnerRole    string
+}
+
+func (q *Queries) IsTenantUserOwner(ctx context.Context, arg IsTenantUserOwnerParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isTenantUserOwner, arg.TenantUserID, arg.OwnerRole)
+	var is_owner bool
+	err := row.Scan(&is_owner)
+	return is_owner, err
+}
+
+const listActiveTenantIDsByUserID = `-- name: ListActiveTenantIDsByUserID :many
+SELECT tenant_id FROM tenant_users
+WHERE user_id = $1 AND status = 'active'
+ORDER BY tenant_id
+`
+
+func (q *Queries) ListActiveTenantIDsByUserID(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, listActiveTenantIDsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var tenant_id uuid.UUID
+		if err := rows.Scan(&tenant_id); err != nil {
+			return nil, err
+		}
+		items = append(items, tenant_id)
+	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 	return items, nil
 }
 
+const lockTenant = `-- name: LockTenant :one
+SELECT id FROM tenants
+WHERE id = $1
+FOR UPDATE
+`
+
+// Serializes membership changes that must leave the tenant an owner
+func (q *Queries) LockTenant(ctx context.Context, tenantID uuid.UUID) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, lockTenant, tenantID)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const scopeToTenant = `-- name: ScopeToTenant :exec
+SELECT set_config('app.tenant_id', $1::uuid::text, true),
+    set_config('app.bypass', 'off', true)
+`
+
+// Limits the rest of the transaction to the rows of the tenant, through the
+// row-level security policies of tenant-owned tables, even on a connection
+// that otherwise bypasses them
+func (q *Queries) ScopeToTenant(ctx context.Context, tenantID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, scopeToTenant, tenantID)
+	return err
+}
+
 const updateTenantStatus = `-- name: UpdateTenantStatus :one
 UPDATE tenants
 SET status = $2, updated_at = now()
 WHERE id = $1
-RETURNING id, name, status, created_at, updated_at, gid
+RETURNING id, name, status, created_at, updated_at, gid, require_mfa, suspended_at, suspension_reason, require_sso
 `
 
 type UpdateTenantStatusParams struct {
@@ -430,7 +570,7 @@ type UpdateTenantStatusParams struct {
 }
 
 func (q *Queries) UpdateTenantStatus(ctx context.Context, arg UpdateTenantStatusParams) (Tenant, error) {
-	row := q.db.QueryRowContext(ctx, updateTenantStatus, arg.ID, arg.Status)
+	row := q.db.QueryRow(ctx, updateTenantStatus, arg.ID, arg.Status)
 	var i Tenant
 	err := row.Scan(
 		&i.ID,
@@ -439,6 +579,10 @@ func (q *Queries) UpdateTenantStatus(ctx context.Context, arg UpdateTenantStatus
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Gid,
+		&i.RequireMfa,
+		&i.SuspendedAt,
+		&i.SuspensionReason,
+		&i.RequireSso,
 	)
 	return i, err
 }
@@ -456,7 +600,36 @@ type UpdateTenantUserStatusParams struct {
 }
 
 func (q *Queries) UpdateTenantUserStatus(ctx context.Context, arg UpdateTenantUserStatusParams) (TenantUser, error) {
-	row := q.db.QueryRowContext(ctx, updateTenantUserStatus, arg.ID, arg.Status)
+	row := q.db.QueryRow(ctx, updateTenantUserStatus, arg.ID, arg.Status)
+	var i TenantUser
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.UserID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertTenantUser = `-- name: UpsertTenantUser :one
+INSERT INTO tenant_users (id, tenant_id, user_id, status, created_at, updated_at)
+VALUES (gen_random_uuid(), $1, $2, $3, now(), now())
+ON CONFLICT (tenant_id, user_id) DO UPDATE
+SET status = EXCLUDED.status, updated_at = now()
+RETURNING id, tenant_id, user_id, status, created_at, updated_at
+`
+
+type UpsertTenantUserParams struct {
+	TenantID uuid.UUID
+	UserID   uuid.UUID
+	Status   string
+}
+
+// Re-invites and invitation acceptance reuse an existing membership row
+func (q *Queries) UpsertTenantUser(ctx context.Context, arg UpsertTenantUserParams) (TenantUser, error) {
+	row := q.db.QueryRow(ctx, upsertTenantUser, arg.TenantID, arg.UserID, arg.Status)
 	var i TenantUser
 	err := row.Scan(
 		&i.ID,
@@ -12,10 +12,55 @@ import (
 	"github.com/google/uuid"
 )
 
+const bumpUserTokenVersion = `-- name: BumpUserTokenVersion :exec
+UPDATE users
+SET token_version = token_version + 1, updated_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) BumpUserTokenVersion(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, bumpUserTokenVersion, id)
+	return err
+}
+
+const changeUserEmail = `-- name: ChangeUserEmail :one
+UPDATE users
+SET email = $2, verified_at = now(), updated_at = now()
+WHERE id = $1
+RETURNING id, email, created_at, updated_at, hashed_password, gid, verified_at, permissions_version, platform_role, erased_at, token_version, display_name, avatar_key
+`
+
+type ChangeUserEmailParams struct {
+	ID    uuid.UUID
+	Email string
+}
+
+// Following the link to the new address proved it, so it counts as verified
+func (q *Queries) ChangeUserEmail(ctx context.Context, arg ChangeUserEmailParams) (User, error) {
+	row := q.db.QueryRow(ctx, changeUserEmail, arg.ID, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HashedPassword,
+		&i.Gid,
+		&i.VerifiedAt,
+		&i.PermissionsVersion,
+		&i.PlatformRole,
+		&i.ErasedAt,
+		&i.TokenVersion,
+		&i.DisplayName,
+		&i.AvatarKey,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (id, gid, created_at, updated_at, email, hashed_password)
 VALUES (gen_random_uuid(), $1, now(), now(), $2, $3)
-RETURNING id, email, created_at, updated_at, hashed_password, gid
+RETURNING id, email, created_at, updated_at, hashed_password, gid, verified_at, permissions_version, platform_role, erased_at, token_version, display_name, avatar_key
 `
 
 type CreateUserParams struct {
@@ -25,7 +70,7 @@ type CreateUserParams struct {
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, createUser, arg.Gid, arg.Email, arg.HashedPassword)
+	row := q.db.QueryRow(ctx, createUser, arg.Gid, arg.Email, arg.HashedPassword)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -34,25 +79,23 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.UpdatedAt,
 		&i.HashedPassword,
 		&i.Gid,
+		&i.VerifiedAt,
+		&i.PermissionsVersion,
+		&i.PlatformRole,
+		&i.ErasedAt,
+		&i.TokenVersion,
+		&i.DisplayName,
+		&i.AvatarKey,
 	)
 	return i, err
 }
 
-const deleteAllUsers = `-- name: DeleteAllUsers :exec
-DELETE FROM users
-`
-
-func (q *Queries) DeleteAllUsers(ctx context.Context) error {
-	_, err := q.db.ExecContext(ctx, deleteAllUsers)
-	return err
-}
-
 const getAllUsers = `-- name: GetAllUsers :many
-SELECT id, email, created_at, updated_at, hashed_password, gid FROM users ORDER BY created_at ASC
+SELECT id, email, created_at, updated_at, hashed_password, gid, verified_at, permissions_version, platform_role, erased_at, token_version, display_name, avatar_key FROM users ORDER BY created_at ASC
 `
 
 func (q *Queries) GetAllUsers(ctx context.Context) ([]User, error) {
-	rows, err := q.db.QueryContext(ctx, getAllUsers)
+	rows, err := q.db.Query(ctx, getAllUsers)
 	if err != nil {
 		return nil, err
 	}
@@ -67,14 +110,18 @@ func (q *Queries) GetAllUsers(ctx context.Context) ([]User, error) {
 			&i.UpdatedAt,
 			&i.HashedPassword,
 			&i.Gid,
+			&i.VerifiedAt,
+			&i.PermissionsVersion,
+			&i.PlatformRole,
+			&i.ErasedAt,
+			&i.TokenVersion,
+			&i.DisplayName,
+			&i.AvatarKey,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -82,11 +129,11 @@ func (q *Queries) GetAllUsers(ctx context.Context) ([]User, error) {
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, email, created_at, updated_at, hashed_password, gid FROM users WHERE email = $1
+SELECT id, email, created_at, updated_at, hashed_password, gid, verified_at, permissions_version, platform_role, erased_at, token_version, display_name, avatar_key FROM users WHERE email = $1
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUserByEmail, email)
+	row := q.db.QueryRow(ctx, getUserByEmail, email)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -95,17 +142,49 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.UpdatedAt,
 		&i.HashedPassword,
 		&i.Gid,
+		&i.VerifiedAt,
+		&i.PermissionsVersion,
+		&i.PlatformRole,
+		&i.ErasedAt,
+		&i.TokenVersion,
+		&i.DisplayName,
+		&i.AvatarKey,
 	)
 	return i, err
 }
 
 const getUserByGID = `-- name: GetUserByGID :one
-SELECT id, email, created_at, updated_at, hashed_password, gid FROM users
+SELECT id, email, created_at, updated_at, hashed_password, gid, verified_at, permissions_version, platform_role, erased_at, token_version, display_name, avatar_key FROM users
 WHERE gid = $1
 `
 
 func (q *Queries) GetUserByGID(ctx context.Context, gid sql.NullInt64) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUserByGID, gid)
+	row := q.db.QueryRow(ctx, getUserByGID, gid)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HashedPassword,
+		&i.Gid,
+		&i.VerifiedAt,
+		&i.PermissionsVersion,
+		&i.PlatformRole,
+		&i.ErasedAt,
+		&i.TokenVersion,
+		&i.DisplayName,
+		&i.AvatarKey,
+	)
+	return i, err
+}
+
+const getUserByID = `-- name: GetUserByID :one
+SELECT id, email, created_at, updated_at, hashed_password, gid, verified_at, permissions_version, platform_role, erased_at, token_version, display_name, avatar_key FROM users WHERE id = $1
+`
+
+func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByID, id)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -114,6 +193,64 @@ func (q *Queries) GetUserByGID(ctx context.Context, gid sql.NullInt64) (User, er
 		&i.UpdatedAt,
 		&i.HashedPassword,
 		&i.Gid,
+		&i.VerifiedAt,
+		&i.PermissionsVersion,
+		&i.PlatformRole,
+		&i.ErasedAt,
+		&i.TokenVersion,
+		&i.DisplayName,
+		&i.AvatarKey,
+	)
+	return i, err
+}
+
+const getUserPermissionsVersion = `-- name: GetUserPermissionsVersion :one
+SELECT permissions_version FROM users WHERE id = $1
+`
+
+func (q *Queries) GetUserPermissionsVersion(ctx context.Context, id uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, getUserPermissionsVersion, id)
+	var permissions_version int64
+	err := row.Scan(&permissions_version)
+	return permissions_version, err
+}
+
+const getUserTokenVersion = `-- name: GetUserTokenVersion :one
+SELECT token_version FROM users WHERE id = $1
+`
+
+func (q *Queries) GetUserTokenVersion(ctx context.Context, id uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, getUserTokenVersion, id)
+	var token_version int64
+	err := row.Scan(&token_version)
+	return token_version, err
+}
+
+const markUserVerified = `-- name: MarkUserVerified :one
+UPDATE users
+SET verified_at = COALESCE(verified_at, now()), updated_at = now()
+WHERE id = $1
+RETURNING id, email, created_at, updated_at, hashed_password, gid, verified_at, permissions_version, platform_role, erased_at, token_version, display_name, avatar_key
+`
+
+// Keeps the first verification time if the user verifies twice
+func (q *Queries) MarkUserVerified(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, markUserVerified, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HashedPassword,
+		&i.Gid,
+		&i.VerifiedAt,
+		&i.PermissionsVersion,
+		&i.PlatformRole,
+		&i.ErasedAt,
+		&i.TokenVersion,
+		&i.DisplayName,
+		&i.AvatarKey,
 	)
 	return i, err
 }
@@ -124,7 +261,7 @@ SET
     email = $2,
     updated_at = now()
 WHERE id = $1
-RETURNING id, email, created_at, updated_at, hashed_password, gid
+RETURNING id, email, created_at, updated_at, hashed_password, gid, verified_at, permissions_version, platform_role, erased_at, token_version, display_name, avatar_key
 `
 
 type UpdateUserParams struct {
@@ -133,7 +270,106 @@ type UpdateUserParams struct {
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUser, arg.ID, arg.Email)
+	row := q.db.QueryRow(ctx, updateUser, arg.ID, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HashedPassword,
+		&i.Gid,
+		&i.VerifiedAt,
+		&i.PermissionsVersion,
+		&i.PlatformRole,
+		&i.ErasedAt,
+		&i.TokenVersion,
+		&i.DisplayName,
+		&i.AvatarKey,
+	)
+	return i, err
+}
+
+const updateUserAvatar = `-- name: UpdateUserAvatar :one
+UPDATE users
+SET avatar_key = $2, updated_at = now()
+WHERE id = $1
+RETURNING id, email, created_at, updated_at, hashed_password, gid, verified_at, permissions_version, platform_role, erased_at, token_version, display_name, avatar_key
+`
+
+type UpdateUserAvatarParams struct {
+	ID        uuid.UUID
+	AvatarKey sql.NullString
+}
+
+func (q *Queries) UpdateUserAvatar(ctx context.Context, arg UpdateUserAvatarParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserAvatar, arg.ID, arg.AvatarKey)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HashedPassword,
+		&i.Gid,
+		&i.VerifiedAt,
+		&i.PermissionsVersion,
+		&i.PlatformRole,
+		&i.ErasedAt,
+		&i.TokenVersion,
+		&i.DisplayName,
+		&i.AvatarKey,
+	)
+	return i, err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :one
+UPDATE users
+SET hashed_password = $2, updated_at = now()
+WHERE id = $1
+RETURNING id, email, created_at, updated_at, hashed_password, gid, verified_at, permissions_version, platform_role, erased_at, token_version, display_name, avatar_key
+`
+
+type UpdateUserPasswordParams struct {
+	ID             uuid.UUID
+	HashedPassword string
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserPassword, arg.ID, arg.HashedPassword)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HashedPassword,
+		&i.Gid,
+		&i.VerifiedAt,
+		&i.PermissionsVersion,
+		&i.PlatformRole,
+		&i.ErasedAt,
+		&i.TokenVersion,
+		&i.DisplayName,
+		&i.AvatarKey,
+	)
+	return i, err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+UPDATE users
+SET display_name = $2, updated_at = now()
+WHERE id = $1
+RETURNING id, email, created_at, updated_at, hashed_password, gid, verified_at, permissions_version, platform_role, erased_at, token_version, display_name, avatar_key
+`
+
+type UpdateUserProfileParams struct {
+	ID          uuid.UUID
+	DisplayName sql.NullString
+}
+
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserProfile, arg.ID, arg.DisplayName)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -142,6 +378,13 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.UpdatedAt,
 		&i.HashedPassword,
 		&i.Gid,
+		&i.VerifiedAt,
+		&i.PermissionsVersion,
+		&i.PlatformRole,
+		&i.ErasedAt,
+		&i.TokenVersion,
+		&i.DisplayName,
+		&i.AvatarKey,
 	)
 	return i, err
 }
@@ -0,0 +1,114 @@
+// Package dbpool opens the pool of database connections a binary shares
+// between its queries, transactions, migrations and health checks.
+package dbpool
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/tracing"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Defaults of the settings left unset
+const (
+	DefaultMaxConns          = 25
+	DefaultMaxConnLifetime   = 30 * time.Minute
+	DefaultMaxConnIdleTime   = 5 * time.Minute
+	DefaultHealthCheckPeriod = time.Minute
+	DefaultConnectTimeout    = 10 * time.Second
+)
+
+// Config sizes the pool. Every instance holds up to MaxConns, so the sum
+// over instances has to stay below the server's max_connections.
+type Config struct {
+	MaxConns int
+	// MinConns are kept open however idle the pool gets, so a quiet
+	// instance doesn't connect on its next request
+	MinConns int
+	// MaxConnLifetime closes connections this old once they are idle, so
+	// load spreads to database replicas added behind a proxy and server
+	// memory held by long-lived sessions is returned
+	MaxConnLifetime time.Duration
+	// MaxConnIdleTime closes connections idle this long
+	MaxConnIdleTime time.Duration
+	// HealthCheckPeriod is how often idle connections are checked, and
+	// those past their lifetime or idle time closed
+	HealthCheckPeriod time.Duration
+	// ConnectTimeout bounds the check that the database is reachable when
+	// the pool opens
+	ConnectTimeout time.Duration
+	// Bypass starts every connection past the tenant isolation policies,
+	// for binaries whose work crosses tenants. Without it a connection sees
+	// only the rows of the tenant it is scoped to, through WithTenant or
+	// ScopeToTenant, and none before.
+	Bypass bool
+}
+
+func (c Config) withDefaults() Config {
+	if c.MaxConns < 1 {
+		c.MaxConns = DefaultMaxConns
+	}
+	if c.MinConns < 0 || c.MinConns > c.MaxConns {
+		c.MinConns = 0
+	}
+	if c.MaxConnLifetime <= 0 {
+		c.MaxConnLifetime = DefaultMaxConnLifetime
+	}
+	if c.MaxConnIdleTime <= 0 {
+		c.MaxConnIdleTime = DefaultMaxConnIdleTime
+	}
+	if c.HealthCheckPeriod <= 0 {
+		c.HealthCheckPeriod = DefaultHealthCheckPeriod
+	}
+	if c.ConnectTimeout <= 0 {
+		c.ConnectTimeout = DefaultConnectTimeout
+	}
+	return c
+}
+
+// Open opens a pool of connections to the database at dsn and checks that
+// it can connect, so a wrong DB_URL fails at startup rather than on the
+// first request
+func Open(ctx context.Context, dsn string, cfg Config) (*pgxpool.Pool, error) {
+	cfg = cfg.withDefaults()
+	pool, err := Connect(dsn, cfg)
+	if err != nil {
+		return nil, err
+	}
+	pingCtx, cancel := context.WithTimeout(ctx, cfg.ConnectTimeout)
+	defer cancel()
+	if err := pool.Ping(pingCtx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("connect to the database: %w", err)
+	}
+	return pool, nil
+}
+
+// Connect is Open without the check, for a database the caller copes
+// without, such as a read replica. Connections trace their queries, so
+// every query is a span in the trace of the request that ran it.
+//
+// pgx prepares each statement once per connection and caches it. Behind a
+// pooler in transaction mode, which can't keep prepared statements, add
+// default_query_exec_mode=exec to dsn; its size is
+// statement_cache_capacity.
+func Connect(dsn string, cfg Config) (*pgxpool.Pool, error) {
+	cfg = cfg.withDefaults()
+	poolConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("parse database URL: %w", err)
+	}
+	if cfg.Bypass {
+		// A startup parameter, so RESET at the end of a scope returns to it
+		poolConfig.ConnConfig.RuntimeParams["app.bypass"] = "on"
+	}
+	poolConfig.ConnConfig.Tracer = tracing.QueryTracer{}
+	poolConfig.MaxConns = int32(cfg.MaxConns)
+	poolConfig.MinConns = int32(cfg.MinConns)
+	poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
+	poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	poolConfig.HealthCheckPeriod = cfg.HealthCheckPeriod
+	return pgxpool.NewWithConfig(context.Background(), poolConfig)
+}
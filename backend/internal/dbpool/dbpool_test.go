@@ -0,0 +1,109 @@
+package dbpool
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestWithDefaults(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want Config
+	}{
+		{
+			name: "unset",
+			want: Config{MaxConns: 25, MaxConnLifetime: 30 * time.Minute, MaxConnIdleTime: 5 * time.Minute, HealthCheckPeriod: time.Minute, ConnectTimeout: 10 * time.Second},
+		},
+		{
+			name: "set",
+			cfg:  Config{MaxConns: 10, MinConns: 4, MaxConnLifetime: time.Hour, MaxConnIdleTime: time.Minute, HealthCheckPeriod: 30 * time.Second, ConnectTimeout: time.Second},
+			want: Config{MaxConns: 10, MinConns: 4, MaxConnLifetime: time.Hour, MaxConnIdleTime: time.Minute, HealthCheckPeriod: 30 * time.Second, ConnectTimeout: time.Second},
+		},
+		{
+			name: "more kept open than allowed",
+			cfg:  Config{MaxConns: 5, MinConns: 8},
+			want: Config{MaxConns: 5, MaxConnLifetime: 30 * time.Minute, MaxConnIdleTime: 5 * time.Minute, HealthCheckPeriod: time.Minute, ConnectTimeout: 10 * time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.withDefaults(); got != tt.want {
+				t.Errorf("withDefaults() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenInvalidURL(t *testing.T) {
+	_, err := Open(context.Background(), "postgres://%zz", Config{})
+	if err == nil || !strings.Contains(err.Error(), "parse database URL") {
+		t.Errorf("Open() error = %v, want a parse error", err)
+	}
+}
+
+func TestReplicaDB(t *testing.T) {
+	primary, err := Connect("postgres://127.0.0.1:1/primary?sslmode=disable", Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer primary.Close()
+	replicaDB, err := Connect("postgres://127.0.0.1:1/replica?sslmode=disable", Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer replicaDB.Close()
+
+	if got := NewReplica(primary, nil, nil).DB(); got != primary {
+		t.Error("without a replica DB() isn't the primary")
+	}
+
+	r := NewReplica(primary, replicaDB, nil)
+	steps := []struct {
+		name string
+		err  error
+		want *pgxpool.Pool
+	}{
+		{name: "unchecked", want: primary},
+		{name: "answering", err: nil, want: replicaDB},
+		{name: "failing", err: errors.New("timeout"), want: primary},
+		{name: "answering again", err: nil, want: replicaDB},
+	}
+	for i, step := range steps {
+		if i > 0 {
+			r.setDown(step.err)
+		}
+		if got := r.DB(); got != step.want {
+			t.Errorf("%s: DB() is the wrong pool", step.name)
+		}
+	}
+}
+
+func TestReplicaQueryFallsBack(t *testing.T) {
+	primary, err := Connect("postgres://127.0.0.1:1/primary?sslmode=disable", Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer primary.Close()
+	replicaDB, err := Connect("postgres://127.0.0.1:1/replica?sslmode=disable", Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer replicaDB.Close()
+
+	r := NewReplica(primary, replicaDB, nil)
+	r.setDown(nil)
+	// Nothing listens on port 1, so the replica is unreachable and the
+	// query is retried on the primary, which isn't there either
+	if _, err := r.Query(context.Background(), "SELECT 1"); err == nil {
+		t.Fatal("Query() succeeded without a database")
+	}
+	if r.DB() != primary {
+		t.Error("an unreachable replica still takes reads")
+	}
+}
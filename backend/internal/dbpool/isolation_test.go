@@ -0,0 +1,137 @@
+package dbpool_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/dbpool"
+	"github.com/dfodeker/terminus/internal/migrate"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// TestTenantIsolation checks the row-level security policies against a
+// real database, migrated in the process. It runs when TEST_DATABASE_URL
+// names one the test may write to, as a role that is neither superuser nor
+// BYPASSRLS, since those skip the policies.
+func TestTenantIsolation(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+
+	db, err := dbpool.Open(ctx, dsn, dbpool.Config{Bypass: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var skips bool
+	err = db.QueryRow(ctx, "SELECT rolsuper OR rolbypassrls FROM pg_roles WHERE rolname = current_user").Scan(&skips)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skips {
+		t.Skip("TEST_DATABASE_URL connects as a role that skips row-level security")
+	}
+
+	if err := migrate.Up(ctx, db); err != nil {
+		t.Fatal(err)
+	}
+
+	q := database.New(dbpool.NewDB(db))
+	newStore := func(name string) (database.Tenant, database.Store) {
+		t.Helper()
+		tenant, err := q.CreateTenant(ctx, database.CreateTenantParams{Name: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		store, err := q.CreateStore(ctx, database.CreateStoreParams{
+			Name:            name,
+			Handle:          "isolation-" + uuid.NewString(),
+			Status:          "active",
+			DefaultCurrency: "USD",
+			Timezone:        "UTC",
+			Plan:            "free",
+			TenantID:        uuid.NullUUID{UUID: tenant.ID, Valid: true},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			db.Exec(ctx, "DELETE FROM stores WHERE id = $1", store.ID)
+			db.Exec(ctx, "DELETE FROM tenants WHERE id = $1", tenant.ID)
+		})
+		return tenant, store
+	}
+	tenantA, storeA := newStore("Tenant A")
+	_, storeB := newStore("Tenant B")
+
+	t.Run("scoped connection", func(t *testing.T) {
+		scoped, release, err := dbpool.WithTenant(ctx, db, tenantA.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer release()
+
+		if _, err := q.GetStoreByID(scoped, storeA.ID); err != nil {
+			t.Errorf("GetStoreByID(own store) error = %v", err)
+		}
+		if _, err := q.GetStoreByID(scoped, storeB.ID); !errors.Is(err, pgx.ErrNoRows) {
+			t.Errorf("GetStoreByID(other tenant's store) error = %v, want pgx.ErrNoRows", err)
+		}
+
+		tx, err := dbpool.BeginTx(scoped, db, pgx.TxOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Rollback(ctx)
+		if _, err := q.WithTx(tx).GetStoreByID(scoped, storeB.ID); !errors.Is(err, pgx.ErrNoRows) {
+			t.Errorf("GetStoreByID(other tenant's store) in a transaction error = %v, want pgx.ErrNoRows", err)
+		}
+	})
+
+	t.Run("scoped transaction", func(t *testing.T) {
+		tx, err := db.Begin(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Rollback(ctx)
+		qtx := q.WithTx(tx)
+		if err := qtx.ScopeToTenant(ctx, tenantA.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := qtx.GetStoreByID(ctx, storeB.ID); !errors.Is(err, pgx.ErrNoRows) {
+			t.Errorf("GetStoreByID(other tenant's store) error = %v, want pgx.ErrNoRows", err)
+		}
+	})
+
+	t.Run("released connection", func(t *testing.T) {
+		scoped, release, err := dbpool.WithTenant(ctx, db, tenantA.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+		if _, err := q.GetStoreByID(ctx, storeB.ID); err != nil {
+			t.Errorf("GetStoreByID() after release error = %v, want the bypass back", err)
+		}
+		if _, err := q.GetStoreByID(scoped, storeA.ID); !errors.Is(err, dbpool.ErrReleased) {
+			t.Errorf("GetStoreByID() on the released scope error = %v, want dbpool.ErrReleased", err)
+		}
+	})
+
+	t.Run("default deny", func(t *testing.T) {
+		unscoped, err := dbpool.Open(ctx, dsn, dbpool.Config{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer unscoped.Close()
+		if _, err := database.New(unscoped).GetStoreByID(ctx, storeA.ID); !errors.Is(err, pgx.ErrNoRows) {
+			t.Errorf("GetStoreByID() without bypass or scope error = %v, want pgx.ErrNoRows", err)
+		}
+	})
+}
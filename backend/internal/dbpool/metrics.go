@@ -0,0 +1,74 @@
+package dbpool
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Register exports the statistics of pool name, such as connections in use
+// and time spent waiting for one, to reg
+func Register(reg prometheus.Registerer, name string, pool *pgxpool.Pool) {
+	reg.MustRegister(newCollector(pool, name))
+}
+
+// collector reads a pool's statistics at each scrape
+type collector struct {
+	pool *pgxpool.Pool
+
+	maxConns         *prometheus.Desc
+	totalConns       *prometheus.Desc
+	acquiredConns    *prometheus.Desc
+	idleConns        *prometheus.Desc
+	acquires         *prometheus.Desc
+	acquireSeconds   *prometheus.Desc
+	emptyAcquires    *prometheus.Desc
+	canceledAcquires *prometheus.Desc
+	newConns         *prometheus.Desc
+	lifetimeDestroys *prometheus.Desc
+	idleTimeDestroys *prometheus.Desc
+}
+
+func newCollector(pool *pgxpool.Pool, name string) *collector {
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc("pgxpool_"+metric, help, nil, prometheus.Labels{"db_name": name})
+	}
+	return &collector{
+		pool:             pool,
+		maxConns:         desc("max_conns", "Maximum size of the pool."),
+		totalConns:       desc("total_conns", "Connections open, in use, idle or being opened."),
+		acquiredConns:    desc("acquired_conns", "Connections in use."),
+		idleConns:        desc("idle_conns", "Connections idle in the pool."),
+		acquires:         desc("acquires_total", "Connections taken from the pool."),
+		acquireSeconds:   desc("acquire_seconds_total", "Time spent taking connections from the pool."),
+		emptyAcquires:    desc("empty_acquires_total", "Connections taken that had to be waited for or opened."),
+		canceledAcquires: desc("canceled_acquires_total", "Waits for a connection cancelled by their context."),
+		newConns:         desc("new_conns_total", "Connections opened."),
+		lifetimeDestroys: desc("max_lifetime_destroys_total", "Connections closed for reaching MaxConnLifetime."),
+		idleTimeDestroys: desc("max_idle_destroys_total", "Connections closed for reaching MaxConnIdleTime."),
+	}
+}
+
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	s := c.pool.Stat()
+	gauge := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, v)
+	}
+	counter := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, v)
+	}
+	gauge(c.maxConns, float64(s.MaxConns()))
+	gauge(c.totalConns, float64(s.TotalConns()))
+	gauge(c.acquiredConns, float64(s.AcquiredConns()))
+	gauge(c.idleConns, float64(s.IdleConns()))
+	counter(c.acquires, float64(s.AcquireCount()))
+	counter(c.acquireSeconds, s.AcquireDuration().Seconds())
+	counter(c.emptyAcquires, float64(s.EmptyAcquireCount()))
+	counter(c.canceledAcquires, float64(s.CanceledAcquireCount()))
+	counter(c.newConns, float64(s.NewConnsCount()))
+	counter(c.lifetimeDestroys, float64(s.MaxLifetimeDestroyCount()))
+	counter(c.idleTimeDestroys, float64(s.MaxIdleDestroyCount()))
+}
@@ -0,0 +1,188 @@
+package dbpool
+
+import (
+	"context"
+	"errors"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgproto3"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// recorder is a Postgres server, speaking just enough of the protocol for
+// pgx, that answers every statement with no rows and records which
+// connection ran it
+type recorder struct {
+	mu         sync.Mutex
+	next       int
+	statements []statement
+	// failReset makes resetSQL fail
+	failReset bool
+	closed    []int
+}
+
+type statement struct {
+	conn  int
+	query string
+	args  []string
+}
+
+// ran lists the statements run, as the connection and query of each
+func (d *recorder) ran() []statement {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]statement(nil), d.statements...)
+}
+
+// closedConns lists the connections closed, waiting a little for those
+// closed as the test finished
+func (d *recorder) closedConns(want int) []int {
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(time.Millisecond) {
+		d.mu.Lock()
+		closed := append([]int(nil), d.closed...)
+		d.mu.Unlock()
+		if len(closed) >= want || time.Now().After(deadline) {
+			return closed
+		}
+	}
+}
+
+func (d *recorder) record(conn int, query string, args []string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.statements = append(d.statements, statement{conn: conn, query: query, args: args})
+	if query == resetSQL && d.failReset {
+		return errors.New("reset failed")
+	}
+	return nil
+}
+
+// dial opens a connection to the recorder, served until the client hangs up
+func (d *recorder) dial(context.Context, string, string) (net.Conn, error) {
+	client, server := net.Pipe()
+	d.mu.Lock()
+	d.next++
+	id := d.next
+	d.mu.Unlock()
+	go d.serve(id, server)
+	return client, nil
+}
+
+var placeholder = regexp.MustCompile(`\$(\d+)`)
+
+const textOID = 25
+
+func (d *recorder) serve(id int, conn net.Conn) {
+	defer func() {
+		conn.Close()
+		d.mu.Lock()
+		d.closed = append(d.closed, id)
+		d.mu.Unlock()
+	}()
+	be := pgproto3.NewBackend(conn, conn)
+	if _, err := be.ReceiveStartupMessage(); err != nil {
+		return
+	}
+	be.Send(&pgproto3.AuthenticationOk{})
+	be.Send(&pgproto3.BackendKeyData{ProcessID: uint32(id)})
+	txStatus := byte('I')
+	be.Send(&pgproto3.ReadyForQuery{TxStatus: txStatus})
+	if be.Flush() != nil {
+		return
+	}
+
+	// The statements prepared, by name, and the one being run
+	prepared := map[string]string{}
+	var query string
+	var args []string
+	failed := false
+	run := func(query string, args []string) {
+		if err := d.record(id, query, args); err != nil {
+			be.Send(&pgproto3.ErrorResponse{Severity: "ERROR", Code: "XX000", Message: err.Error()})
+			failed = true
+			return
+		}
+		command := strings.ToUpper(strings.Fields(query)[0])
+		switch command {
+		case "BEGIN":
+			txStatus = 'T'
+		case "COMMIT", "ROLLBACK":
+			txStatus = 'I'
+		}
+		be.Send(&pgproto3.CommandComplete{CommandTag: []byte(command)})
+	}
+	for {
+		msg, err := be.Receive()
+		if err != nil {
+			return
+		}
+		switch msg := msg.(type) {
+		case *pgproto3.Query:
+			run(msg.String, nil)
+			be.Send(&pgproto3.ReadyForQuery{TxStatus: txStatus})
+			failed = false
+		case *pgproto3.Parse:
+			query = msg.Query
+			prepared[msg.Name] = msg.Query
+			be.Send(&pgproto3.ParseComplete{})
+		case *pgproto3.Describe:
+			if msg.ObjectType == 'S' {
+				query = prepared[msg.Name]
+				n := 0
+				for _, m := range placeholder.FindAllStringSubmatch(query, -1) {
+					i, _ := strconv.Atoi(m[1])
+					n = max(n, i)
+				}
+				// Every parameter text, so pgx sends them as strings
+				oids := make([]uint32, n)
+				for i := range oids {
+					oids[i] = textOID
+				}
+				be.Send(&pgproto3.ParameterDescription{ParameterOIDs: oids})
+			}
+			be.Send(&pgproto3.NoData{})
+		case *pgproto3.Bind:
+			query = prepared[msg.PreparedStatement]
+			args = args[:0]
+			for _, p := range msg.Parameters {
+				args = append(args, string(p))
+			}
+			be.Send(&pgproto3.BindComplete{})
+		case *pgproto3.Execute:
+			if !failed {
+				run(query, append([]string(nil), args...))
+			}
+		case *pgproto3.Sync:
+			be.Send(&pgproto3.ReadyForQuery{TxStatus: txStatus})
+			failed = false
+		case *pgproto3.Terminate:
+			return
+		}
+		if be.Flush() != nil {
+			return
+		}
+	}
+}
+
+func newRecorder(t *testing.T) (*recorder, *pgxpool.Pool) {
+	t.Helper()
+	d := &recorder{}
+	cfg, err := pgxpool.ParseConfig("postgres://recorder/db?sslmode=disable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.ConnConfig.DialFunc = d.dial
+	cfg.ConnConfig.LookupFunc = func(context.Context, string) ([]string, error) { return []string{"127.0.0.1"}, nil }
+	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	return d, pool
+}
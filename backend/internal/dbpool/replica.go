@@ -0,0 +1,189 @@
+package dbpool
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DefaultReplicaCheckInterval is how often a replica is pinged when
+// DB_REPLICA_CHECK_INTERVAL is unset
+const DefaultReplicaCheckInterval = 5 * time.Second
+
+// Replica sends reads to a read replica while it answers, and to the
+// primary while it doesn't. It is a database.DBTX, so database.New over it
+// gives queries that follow it. Only reads that can be a little behind the
+// primary belong on it: replicas lag, so a request reading what it just
+// wrote has to use the primary.
+//
+// Without a replica every read goes to the primary. In a context from
+// WithTenant over the primary, reads run on the tenant's connections.
+type Replica struct {
+	primary *pgxpool.Pool
+	replica *pgxpool.Pool
+	logger  *slog.Logger
+	// down is set while the replica fails its checks, and until the first
+	// one; checked once there has been one
+	down    atomic.Bool
+	checked atomic.Bool
+}
+
+// NewReplica routes reads between primary and replica, which is nil when
+// there is no replica. Reads go to the primary until Run has checked the
+// replica. A nil logger logs to slog's default logger.
+func NewReplica(primary, replica *pgxpool.Pool, logger *slog.Logger) *Replica {
+	r := &Replica{primary: primary, replica: replica, logger: logger}
+	r.down.Store(true)
+	return r
+}
+
+// DB is the pool reads go to now
+func (r *Replica) DB() *pgxpool.Pool {
+	if r.replica == nil || r.down.Load() {
+		return r.primary
+	}
+	return r.replica
+}
+
+// Run pings the replica once an interval until ctx is done, sending reads
+// to the primary from the first failed ping until one succeeds
+func (r *Replica) Run(ctx context.Context, interval time.Duration) {
+	if r.replica == nil {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		pingCtx, cancel := context.WithTimeout(ctx, interval)
+		err := r.replica.Ping(pingCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		r.setDown(err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Replica) setDown(err error) {
+	if err != nil {
+		if !r.down.Swap(true) || !r.checked.Swap(true) {
+			r.log().Warn("read replica unavailable, reading from the primary", "error", err)
+		}
+		return
+	}
+	r.checked.Store(true)
+	if r.down.Swap(false) {
+		r.log().Info("reading from the read replica")
+	}
+}
+
+func (r *Replica) log() *slog.Logger {
+	if r.logger == nil {
+		return slog.Default()
+	}
+	return r.logger
+}
+
+// unreachable is whether err means the replica couldn't be reached, rather
+// than that the query failed
+func unreachable(err error) bool {
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+	return errors.As(err, &connectErr) || errors.As(err, &netErr)
+}
+
+// scoped is the connection of ctx's tenant scope to read from: the
+// replica's while it answers, else the primary's
+func (r *Replica) scoped(ctx context.Context) (querier, bool) {
+	s, ok := scopeFrom(ctx)
+	if !ok || s.primary != r.primary {
+		return nil, false
+	}
+	if pool := r.DB(); pool != r.primary {
+		c, err := s.conn(ctx, pool)
+		if err == nil {
+			return c, true
+		}
+		if ctx.Err() == nil && unreachable(err) {
+			r.setDown(err)
+		}
+	}
+	c, err := s.conn(ctx, r.primary)
+	if err != nil {
+		return failed{err}, true
+	}
+	return c, true
+}
+
+// Query runs the query on the replica, or on the primary when the replica
+// can't be reached
+func (r *Replica) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if c, ok := r.scoped(ctx); ok {
+		return c.Query(ctx, sql, args...)
+	}
+	pool := r.DB()
+	rows, err := pool.Query(ctx, sql, args...)
+	if err != nil && pool != r.primary && ctx.Err() == nil && unreachable(err) {
+		r.setDown(err)
+		return r.primary.Query(ctx, sql, args...)
+	}
+	return rows, err
+}
+
+// QueryRow runs the query on the replica, or on the primary when the
+// replica can't be reached. A row's error only comes with Scan, so that is
+// where it falls back.
+func (r *Replica) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	if c, ok := r.scoped(ctx); ok {
+		return c.QueryRow(ctx, sql, args...)
+	}
+	pool := r.DB()
+	if pool == r.primary {
+		return pool.QueryRow(ctx, sql, args...)
+	}
+	return &replicaRow{r: r, ctx: ctx, sql: sql, args: args, row: pool.QueryRow(ctx, sql, args...)}
+}
+
+// replicaRow is a row read from the replica, read again from the primary
+// if the replica turns out to be unreachable
+type replicaRow struct {
+	r    *Replica
+	ctx  context.Context
+	sql  string
+	args []any
+	row  pgx.Row
+}
+
+func (rr *replicaRow) Scan(dest ...any) error {
+	err := rr.row.Scan(dest...)
+	if err != nil && rr.ctx.Err() == nil && unreachable(err) {
+		rr.r.setDown(err)
+		return rr.r.primary.QueryRow(rr.ctx, rr.sql, rr.args...).Scan(dest...)
+	}
+	return err
+}
+
+// Exec runs statements on the primary; replicas are read only
+func (r *Replica) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	if s, ok := scopeFrom(ctx); ok && s.primary == r.primary {
+		c, err := s.conn(ctx, r.primary)
+		if err != nil {
+			return pgconn.CommandTag{}, err
+		}
+		return c.Exec(ctx, sql, args...)
+	}
+	return r.primary.Exec(ctx, sql, args...)
+}
@@ -0,0 +1,200 @@
+package dbpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// releaseTimeout bounds returning a scope's connections, which happens
+// after the request that took them may have been cancelled
+const releaseTimeout = 5 * time.Second
+
+// scopeSQL limits a connection to the rows of one tenant until resetSQL.
+// Session settings rather than transaction ones, so reads outside a
+// transaction are limited too.
+const (
+	scopeSQL = "SELECT set_config('app.tenant_id', $1, false), set_config('app.bypass', 'off', false)"
+	resetSQL = "RESET app.tenant_id; RESET app.bypass"
+)
+
+// ErrReleased is returned for queries in a tenant scope that has been
+// released
+var ErrReleased = errors.New("dbpool: tenant scope released")
+
+type scopeKey struct{}
+
+// scope holds the connections the queries of one tenant's work run on, one
+// per pool, each limited to the tenant's rows
+type scope struct {
+	tenantID uuid.UUID
+	primary  *pgxpool.Pool
+
+	mu       sync.Mutex
+	conns    map[*pgxpool.Pool]*pgxpool.Conn
+	released bool
+}
+
+// WithTenant takes a connection from pool limited to the rows of tenantID
+// and returns a context whose queries run on it: those of a DB over pool,
+// of a Replica over pool and of BeginTx. Reads of a replica take a second
+// connection, from the replica's pool, the first time there is one.
+//
+// release returns the connections to their pools and has to be called once
+// the work is done. The context's queries mustn't run concurrently, as
+// they share a connection.
+func WithTenant(ctx context.Context, pool *pgxpool.Pool, tenantID uuid.UUID) (context.Context, func(), error) {
+	s := &scope{tenantID: tenantID, primary: pool, conns: map[*pgxpool.Pool]*pgxpool.Conn{}}
+	if _, err := s.conn(ctx, pool); err != nil {
+		return nil, nil, err
+	}
+	return context.WithValue(ctx, scopeKey{}, s), s.release, nil
+}
+
+// WithScope returns ctx carrying the tenant scope of from, if it has one,
+// for work under one context that has to run on another's connection
+func WithScope(ctx, from context.Context) context.Context {
+	if s, ok := scopeFrom(from); ok {
+		return context.WithValue(ctx, scopeKey{}, s)
+	}
+	return ctx
+}
+
+func scopeFrom(ctx context.Context) (*scope, bool) {
+	s, ok := ctx.Value(scopeKey{}).(*scope)
+	return s, ok
+}
+
+// conn is the scope's connection from pool, taken and limited to the
+// tenant the first time
+func (s *scope) conn(ctx context.Context, pool *pgxpool.Pool) (*pgxpool.Conn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.released {
+		return nil, ErrReleased
+	}
+	if c, ok := s.conns[pool]; ok {
+		return c, nil
+	}
+	c, err := pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("take a connection for the tenant: %w", err)
+	}
+	if _, err := c.Exec(ctx, scopeSQL, s.tenantID.String()); err != nil {
+		discard(c)
+		return nil, fmt.Errorf("scope the connection to the tenant: %w", err)
+	}
+	s.conns[pool] = c
+	return c, nil
+}
+
+// release resets the scope's connections and returns them to their pools.
+// One that can't be reset is closed instead, so no later work inherits the
+// tenant.
+func (s *scope) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.released = true
+	for pool, c := range s.conns {
+		ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+		_, err := c.Exec(ctx, resetSQL)
+		cancel()
+		if err != nil {
+			slog.Warn("closing database connection that couldn't leave its tenant scope", "error", err)
+			discard(c)
+		} else {
+			c.Release()
+		}
+		delete(s.conns, pool)
+	}
+}
+
+// discard closes c rather than return it to its pool
+func discard(c *pgxpool.Conn) {
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+	c.Conn().Close(ctx)
+	c.Release()
+}
+
+// BeginTx starts a transaction on pool, or on the connection of the tenant
+// scope ctx carries
+func BeginTx(ctx context.Context, pool *pgxpool.Pool, opts pgx.TxOptions) (pgx.Tx, error) {
+	if s, ok := scopeFrom(ctx); ok && s.primary == pool {
+		c, err := s.conn(ctx, pool)
+		if err != nil {
+			return nil, err
+		}
+		return c.BeginTx(ctx, opts)
+	}
+	return pool.BeginTx(ctx, opts)
+}
+
+// querier is what a DB runs queries on: a pool or one of its connections
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// DB runs queries on a pool, or, in a context from WithTenant over the
+// pool, on the tenant's connection. It is a database.DBTX, so database.New
+// over it gives queries that follow the scope of the request.
+type DB struct {
+	pool *pgxpool.Pool
+}
+
+// NewDB runs queries on pool, following tenant scopes
+func NewDB(pool *pgxpool.Pool) *DB {
+	return &DB{pool: pool}
+}
+
+// on is the scoped connection of ctx over the pool, or the pool. Once the
+// scope is released its queries fail, rather than run on the pool past the
+// policies.
+func (d *DB) on(ctx context.Context) querier {
+	if s, ok := scopeFrom(ctx); ok && s.primary == d.pool {
+		c, err := s.conn(ctx, d.pool)
+		if err != nil {
+			return failed{err}
+		}
+		return c
+	}
+	return d.pool
+}
+
+func (d *DB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return d.on(ctx).Exec(ctx, sql, args...)
+}
+
+func (d *DB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return d.on(ctx).Query(ctx, sql, args...)
+}
+
+func (d *DB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return d.on(ctx).QueryRow(ctx, sql, args...)
+}
+
+// failed is a querier whose every query fails with err
+type failed struct{ err error }
+
+func (f failed) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, f.err
+}
+
+func (f failed) Query(context.Context, string, ...any) (pgx.Rows, error) { return nil, f.err }
+
+func (f failed) QueryRow(context.Context, string, ...any) pgx.Row { return failedRow(f) }
+
+// failedRow is a pgx.Row whose Scan fails with err
+type failedRow struct{ err error }
+
+func (r failedRow) Scan(...any) error { return r.err }
@@ -0,0 +1,161 @@
+package dbpool
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestWithTenant(t *testing.T) {
+	d, db := newRecorder(t)
+	tenantID := uuid.New()
+
+	ctx, release, err := WithTenant(context.Background(), db, tenantID)
+	if err != nil {
+		t.Fatalf("WithTenant() error = %v", err)
+	}
+	q := NewDB(db)
+	if _, err := q.Exec(ctx, "UPDATE a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.QueryRow(ctx, "SELECT b").Scan(new(any)); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("QueryRow() error = %v, want pgx.ErrNoRows", err)
+	}
+	tx, err := BeginTx(ctx, db, pgx.TxOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec(ctx, "UPDATE c"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+	release()
+
+	ran := d.ran()
+	want := []string{scopeSQL, "UPDATE a", "SELECT b", "begin", "UPDATE c", "commit", resetSQL}
+	if len(ran) != len(want) {
+		t.Fatalf("ran %d statements, want %d: %+v", len(ran), len(want), ran)
+	}
+	for i, s := range ran {
+		if s.query != want[i] {
+			t.Errorf("statement %d = %q, want %q", i, s.query, want[i])
+		}
+		if s.conn != ran[0].conn {
+			t.Errorf("statement %q ran on connection %d, want the scoped one %d", s.query, s.conn, ran[0].conn)
+		}
+	}
+	if args := ran[0].args; len(args) != 1 || args[0] != tenantID.String() {
+		t.Errorf("scope args = %+v, want the tenant ID", args)
+	}
+
+	// Once released the scope refuses queries rather than run them on the
+	// pool, past the policies
+	if _, err := q.Exec(ctx, "UPDATE d"); !errors.Is(err, ErrReleased) {
+		t.Errorf("Exec() after release error = %v, want ErrReleased", err)
+	}
+	if _, err := BeginTx(ctx, db, pgx.TxOptions{}); !errors.Is(err, ErrReleased) {
+		t.Errorf("BeginTx() after release error = %v, want ErrReleased", err)
+	}
+}
+
+func TestWithScope(t *testing.T) {
+	d, db := newRecorder(t)
+
+	scoped, release, err := WithTenant(context.Background(), db, uuid.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	type key struct{}
+	ctx := WithScope(context.WithValue(context.Background(), key{}, "kept"), scoped)
+	if ctx.Value(key{}) != "kept" {
+		t.Error("WithScope() dropped the values of ctx")
+	}
+	if _, err := NewDB(db).Exec(ctx, "UPDATE a"); err != nil {
+		t.Fatal(err)
+	}
+	if ran := d.ran(); len(ran) != 2 || ran[1].conn != ran[0].conn {
+		t.Errorf("ran %+v, want UPDATE a on the scoped connection", ran)
+	}
+
+	if unscoped := context.Background(); WithScope(unscoped, unscoped) != unscoped {
+		t.Error("WithScope() of an unscoped context changed ctx")
+	}
+}
+
+func TestDBUnscoped(t *testing.T) {
+	d, db := newRecorder(t)
+	_, other := newRecorder(t)
+
+	// A scope over another pool doesn't apply
+	ctx, release, err := WithTenant(context.Background(), other, uuid.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	if _, err := NewDB(db).Exec(ctx, "UPDATE a"); err != nil {
+		t.Fatal(err)
+	}
+	if ran := d.ran(); len(ran) != 1 || ran[0].query != "UPDATE a" {
+		t.Errorf("ran %+v, want only UPDATE a", ran)
+	}
+}
+
+func TestWithTenantResetFails(t *testing.T) {
+	d, db := newRecorder(t)
+	d.failReset = true
+
+	ctx, release, err := WithTenant(context.Background(), db, uuid.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewDB(db).Exec(ctx, "UPDATE a"); err != nil {
+		t.Fatal(err)
+	}
+	release()
+
+	// The connection still holds the tenant, so it mustn't go back to the
+	// pool
+	scoped := d.ran()[0].conn
+	closed := d.closedConns(1)
+	if len(closed) != 1 || closed[0] != scoped {
+		t.Errorf("closed connections %v, want the scoped one %d", closed, scoped)
+	}
+}
+
+func TestReplicaScoped(t *testing.T) {
+	d, primary := newRecorder(t)
+	rd, replicaDB := newRecorder(t)
+	replica := NewReplica(primary, replicaDB, nil)
+	replica.setDown(nil)
+
+	ctx, release, err := WithTenant(context.Background(), primary, uuid.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := replica.Query(ctx, "SELECT a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows.Close()
+	if _, err := replica.Exec(ctx, "UPDATE b"); err != nil {
+		t.Fatal(err)
+	}
+	release()
+
+	// Reads take a scoped connection of the replica; writes stay on the
+	// primary's
+	want := []string{scopeSQL, "SELECT a", resetSQL}
+	if ran := rd.ran(); len(ran) != len(want) || ran[0].query != want[0] || ran[1].query != want[1] || ran[2].query != want[2] {
+		t.Errorf("replica ran %+v, want %v", ran, want)
+	}
+	want = []string{scopeSQL, "UPDATE b", resetSQL}
+	if ran := d.ran(); len(ran) != len(want) || ran[0].query != want[0] || ran[1].query != want[1] || ran[2].query != want[2] {
+		t.Errorf("primary ran %+v, want %v", ran, want)
+	}
+}
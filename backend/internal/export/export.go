@@ -0,0 +1,284 @@
+// Package export writes a store's products, orders or customers out as CSV
+// or JSON Lines. Callers pick the columns by name; the worker feeds records in
+// pages so a file of any size is written in constant memory.
+package export
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/google/uuid"
+)
+
+// Resources that can be exported
+const (
+	ResourceProducts  = "products"
+	ResourceOrders    = "orders"
+	ResourceCustomers = "customers"
+)
+
+// Formats an export can be written in
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+// Export statuses
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+// ErrInvalid wraps every problem with an export's format or columns
+var ErrInvalid = errors.New("invalid export")
+
+// Args is the job that writes one export
+type Args struct {
+	ExportID uuid.UUID `json:"export_id"`
+}
+
+func (Args) Kind() string { return "exports.run" }
+
+// ContentType is the media type of a format
+func ContentType(format string) string {
+	if format == FormatJSONL {
+		return "application/x-ndjson"
+	}
+	return "text/csv"
+}
+
+// CheckFormat rejects formats other than csv and jsonl
+func CheckFormat(format string) error {
+	if format != FormatCSV && format != FormatJSONL {
+		return fmt.Errorf("%w: format must be %s or %s", ErrInvalid, FormatCSV, FormatJSONL)
+	}
+	return nil
+}
+
+// Column is one field of a record. Value returns nil for a field that is
+// unset, which is written as an empty cell or a JSON null.
+type Column[T any] struct {
+	Name  string
+	Value func(T) any
+}
+
+// ProductColumns are the fields a product export can include, in their
+// default order
+var ProductColumns = []Column[database.Product]{
+	{"id", func(p database.Product) any { return p.ID }},
+	{"handle", func(p database.Product) any { return p.Handle }},
+	{"name", func(p database.Product) any { return p.Name }},
+	{"description", func(p database.Product) any { return nullString(p.Description) }},
+	{"sku", func(p database.Product) any { return nullString(p.Sku) }},
+	{"tags", func(p database.Product) any { return nullString(p.Tags) }},
+	{"status", func(p database.Product) any { return p.Status }},
+	{"inventory_tracked", func(p database.Product) any { return p.InventoryTracked }},
+	{"created_at", func(p database.Product) any { return p.CreatedAt }},
+	{"updated_at", func(p database.Product) any { return p.UpdatedAt }},
+}
+
+// OrderColumns are the fields an order export can include, in their
+// default order
+var OrderColumns = []Column[database.Order]{
+	{"id", func(o database.Order) any { return o.ID }},
+	{"order_number", func(o database.Order) any { return o.OrderNumber }},
+	{"email", func(o database.Order) any { return nullString(sql.NullString(o.Email)) }},
+	{"customer_id", func(o database.Order) any {
+		if !o.CustomerID.Valid {
+			return nil
+		}
+		return o.CustomerID.UUID
+	}},
+	{"status", func(o database.Order) any { return o.Status }},
+	{"financial_status", func(o database.Order) any { return o.FinancialStatus }},
+	{"fulfillment_status", func(o database.Order) any { return o.FulfillmentStatus }},
+	{"currency", func(o database.Order) any { return o.Currency }},
+	{"subtotal_cents", func(o database.Order) any { return o.SubtotalCents }},
+	{"shipping_cents", func(o database.Order) any { return o.ShippingCents }},
+	{"tax_cents", func(o database.Order) any { return o.TaxCents }},
+	{"discount_cents", func(o database.Order) any { return o.DiscountCents }},
+	{"total_cents", func(o database.Order) any { return o.TotalCents }},
+	{"tags", func(o database.Order) any { return o.Tags }},
+	{"placed_at", func(o database.Order) any { return o.PlacedAt }},
+}
+
+// CustomerColumns are the fields a customer export can include, in their
+// default order
+var CustomerColumns = []Column[database.Customer]{
+	{"id", func(c database.Customer) any { return c.ID }},
+	{"email", func(c database.Customer) any { return string(c.Email) }},
+	{"first_name", func(c database.Customer) any { return nullString(c.FirstName) }},
+	{"last_name", func(c database.Customer) any { return nullString(c.LastName) }},
+	{"phone", func(c database.Customer) any { return nullString(sql.NullString(c.Phone)) }},
+	{"accepts_marketing", func(c database.Customer) any { return c.AcceptsMarketing }},
+	{"status", func(c database.Customer) any { return c.Status }},
+	{"tags", func(c database.Customer) any { return c.Tags }},
+	{"created_at", func(c database.Customer) any { return c.CreatedAt }},
+}
+
+// ColumnNames lists the names of columns in order
+func ColumnNames[T any](columns []Column[T]) []string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// Select returns the named columns in the order given, or every column
+// when names is empty
+func Select[T any](columns []Column[T], names []string) ([]Column[T], error) {
+	if len(names) == 0 {
+		return columns, nil
+	}
+	selected := make([]Column[T], 0, len(names))
+	seen := map[string]bool{}
+	for _, name := range names {
+		if seen[name] {
+			return nil, fmt.Errorf("%w: column %q is listed more than once", ErrInvalid, name)
+		}
+		seen[name] = true
+		i := indexOf(columns, name)
+		if i < 0 {
+			return nil, fmt.Errorf("%w: unknown column %q; columns are %s", ErrInvalid, name, strings.Join(ColumnNames(columns), ", "))
+		}
+		selected = append(selected, columns[i])
+	}
+	return selected, nil
+}
+
+func indexOf[T any](columns []Column[T], name string) int {
+	for i, c := range columns {
+		if c.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Writer writes records in one format. Call Flush once the last record is
+// written.
+type Writer[T any] struct {
+	columns []Column[T]
+	csv     *csv.Writer
+	jsonl   *bufio.Writer
+	record  []string
+	started bool
+}
+
+// NewWriter returns a Writer of format to w
+func NewWriter[T any](w io.Writer, format string, columns []Column[T]) (*Writer[T], error) {
+	if err := CheckFormat(format); err != nil {
+		return nil, err
+	}
+	ew := &Writer[T]{columns: columns}
+	if format == FormatCSV {
+		ew.csv = csv.NewWriter(w)
+		ew.record = make([]string, len(columns))
+	} else {
+		ew.jsonl = bufio.NewWriter(w)
+	}
+	return ew, nil
+}
+
+// Write adds one record, preceded in CSV by the header row
+func (ew *Writer[T]) Write(rec T) error {
+	if ew.csv != nil {
+		return ew.writeCSV(rec)
+	}
+	return ew.writeJSONL(rec)
+}
+
+func (ew *Writer[T]) writeCSV(rec T) error {
+	if !ew.started {
+		ew.started = true
+		if err := ew.csv.Write(ColumnNames(ew.columns)); err != nil {
+			return err
+		}
+	}
+	for i, c := range ew.columns {
+		ew.record[i] = cell(c.Value(rec))
+	}
+	return ew.csv.Write(ew.record)
+}
+
+// writeJSONL writes an object with its keys in column order, which
+// encoding/json would sort. Times are in UTC, as in CSV.
+func (ew *Writer[T]) writeJSONL(rec T) error {
+	ew.jsonl.WriteByte('{')
+	for i, c := range ew.columns {
+		if i > 0 {
+			ew.jsonl.WriteByte(',')
+		}
+		key, _ := json.Marshal(c.Name)
+		v := c.Value(rec)
+		if t, ok := v.(time.Time); ok {
+			v = t.UTC()
+		}
+		value, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("column %s: %w", c.Name, err)
+		}
+		ew.jsonl.Write(key)
+		ew.jsonl.WriteByte(':')
+		ew.jsonl.Write(value)
+	}
+	ew.jsonl.WriteString("}\n")
+	return nil
+}
+
+// Flush writes out anything buffered, and in CSV the header when there
+// were no records
+func (ew *Writer[T]) Flush() error {
+	if ew.csv != nil {
+		if !ew.started {
+			ew.started = true
+			if err := ew.csv.Write(ColumnNames(ew.columns)); err != nil {
+				return err
+			}
+		}
+		ew.csv.Flush()
+		return ew.csv.Error()
+	}
+	return ew.jsonl.Flush()
+}
+
+// cell formats a value for CSV
+func cell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case []string:
+		return strings.Join(v, ", ")
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func nullString(s sql.NullString) any {
+	if !s.Valid {
+		return nil
+	}
+	return s.String
+}
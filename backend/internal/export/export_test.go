@@ -0,0 +1,111 @@
+package export
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/crypto"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/google/uuid"
+)
+
+func TestSelect(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   []string
+		want    []string
+		wantErr string
+	}{
+		{name: "all by default", want: ColumnNames(ProductColumns)},
+		{name: "in the order asked", names: []string{"sku", "handle"}, want: []string{"sku", "handle"}},
+		{name: "unknown", names: []string{"handle", "price"}, wantErr: `unknown column "price"`},
+		{name: "repeated", names: []string{"handle", "handle"}, wantErr: "more than once"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Select(ProductColumns, tt.names)
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInvalid) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Select() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Select() error = %v", err)
+			}
+			if names := ColumnNames(got); !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("columns = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter(t *testing.T) {
+	id := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	placed := time.Date(2025, 3, 1, 12, 0, 0, 0, time.FixedZone("EST", -5*3600))
+	orders := []database.Order{
+		{ID: id, OrderNumber: 1001, Email: crypto.NullText{String: "a@example.com", Valid: true}, TotalCents: 2500, Tags: []string{"vip", "rush"}, PlacedAt: placed},
+		{ID: id, OrderNumber: 1002, TotalCents: 0, PlacedAt: placed},
+	}
+	columns, err := Select(OrderColumns, []string{"order_number", "email", "total_cents", "tags", "placed_at"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		format  string
+		records []database.Order
+		want    string
+	}{
+		{
+			name:    "csv",
+			format:  FormatCSV,
+			records: orders,
+			want: "order_number,email,total_cents,tags,placed_at\n" +
+				"1001,a@example.com,2500,\"vip, rush\",2025-03-01T17:00:00Z\n" +
+				"1002,,0,,2025-03-01T17:00:00Z\n",
+		},
+		{
+			name:   "csv without records keeps the header",
+			format: FormatCSV,
+			want:   "order_number,email,total_cents,tags,placed_at\n",
+		},
+		{
+			name:    "jsonl keeps column order",
+			format:  FormatJSONL,
+			records: orders,
+			want: `{"order_number":1001,"email":"a@example.com","total_cents":2500,"tags":["vip","rush"],"placed_at":"2025-03-01T17:00:00Z"}` + "\n" +
+				`{"order_number":1002,"email":null,"total_cents":0,"tags":null,"placed_at":"2025-03-01T17:00:00Z"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			w, err := NewWriter(&buf, tt.format, columns)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, rec := range tt.records {
+				if err := w.Write(rec); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("output =\n%s\nwant\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+
+	if _, err := NewWriter(&strings.Builder{}, "xlsx", columns); !errors.Is(err, ErrInvalid) {
+		t.Errorf("NewWriter(xlsx) error = %v, want ErrInvalid", err)
+	}
+}
@@ -0,0 +1,324 @@
+// Package feeds writes the product feeds marketplaces such as Google
+// Shopping and Facebook read a store's catalog from, and regenerates them
+// on a schedule.
+package feeds
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/dfodeker/terminus/internal/fx"
+)
+
+// Feed formats
+const (
+	FormatGoogleMerchantXML = "google_merchant_xml"
+	FormatFacebookCSV       = "facebook_csv"
+)
+
+const (
+	// MaxNameLength bounds a feed name
+	MaxNameLength = 100
+	// MaxLinkTemplateLength bounds a product link template
+	MaxLinkTemplateLength = 500
+	// MinRefreshMinutes, MaxRefreshMinutes and DefaultRefreshMinutes bound
+	// how often a feed is regenerated
+	MinRefreshMinutes     = 60
+	MaxRefreshMinutes     = 7 * 24 * 60
+	DefaultRefreshMinutes = 24 * 60
+)
+
+// HandlePlaceholder is replaced with the product handle in a link template
+const HandlePlaceholder = "{handle}"
+
+// Marketplaces cut longer values off or reject the item
+const (
+	maxTitleLength       = 150
+	maxDescriptionLength = 5000
+)
+
+// defaultVariantTitle is the title of the only variant of a product
+// without options, which adds nothing to the product name
+const defaultVariantTitle = "Default Title"
+
+// ErrInvalidLinkTemplate is returned for link templates that are not an
+// absolute http(s) URL with a HandlePlaceholder
+var ErrInvalidLinkTemplate = errors.New("invalid link template")
+
+// IsValidFormat reports whether format is a known feed format
+func IsValidFormat(format string) bool {
+	return format == FormatGoogleMerchantXML || format == FormatFacebookCSV
+}
+
+// ContentType is the media type a feed of format is served with
+func ContentType(format string) string {
+	if format == FormatFacebookCSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/xml; charset=utf-8"
+}
+
+// Extension is the file extension of a feed of format
+func Extension(format string) string {
+	if format == FormatFacebookCSV {
+		return ".csv"
+	}
+	return ".xml"
+}
+
+// ValidateLinkTemplate checks a product link template such as
+// https://shop.example.com/products/{handle}
+func ValidateLinkTemplate(template string) error {
+	if !strings.Contains(template, HandlePlaceholder) {
+		return fmt.Errorf("%w: must contain %s", ErrInvalidLinkTemplate, HandlePlaceholder)
+	}
+	u, err := url.Parse(strings.ReplaceAll(template, HandlePlaceholder, "handle"))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: must be an absolute http or https URL", ErrInvalidLinkTemplate)
+	}
+	return nil
+}
+
+// Link is the product page address of handle
+func Link(template, handle string) string {
+	return strings.ReplaceAll(template, HandlePlaceholder, url.PathEscape(handle))
+}
+
+// SiteLink is the origin of the product pages of a link template
+func SiteLink(template string) string {
+	u, err := url.Parse(strings.ReplaceAll(template, HandlePlaceholder, "handle"))
+	if err != nil {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// FormatPrice writes an amount in minor units the way both formats want
+// it, such as 12.50 USD
+func FormatPrice(amount int64, currency string) string {
+	digits := fx.MinorUnits(currency)
+	if digits == 0 {
+		return strconv.FormatInt(amount, 10) + " " + currency
+	}
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	scale := int64(1)
+	for range digits {
+		scale *= 10
+	}
+	return fmt.Sprintf("%s%d.%0*d %s", sign, amount/scale, digits, amount%scale, currency)
+}
+
+// Meta describes the store a feed is of
+type Meta struct {
+	Title       string
+	Link        string
+	Description string
+}
+
+// Item is one variant in a feed
+type Item struct {
+	ID          string
+	GroupID     string
+	Title       string
+	Description string
+	Link        string
+	ImageLink   string
+	// PriceCents is the regular price. SalePriceCents, when set, is what
+	// the item sells for now.
+	PriceCents     int64
+	SalePriceCents *int64
+	Currency       string
+	InStock        bool
+	Brand          string
+	GTIN           string
+	MPN            string
+}
+
+// ItemTitle is the title of a variant in a feed: the product name, with
+// the variant title when the product has options
+func ItemTitle(productName, variantTitle string) string {
+	if variantTitle == "" || variantTitle == defaultVariantTitle || variantTitle == productName {
+		return productName
+	}
+	return productName + " - " + variantTitle
+}
+
+// Writer writes the items of a feed
+type Writer interface {
+	Write(item Item) error
+	// Close finishes the feed. It does not close the underlying writer.
+	Close() error
+}
+
+// NewWriter starts a feed of format on w
+func NewWriter(w io.Writer, format string, meta Meta) (Writer, error) {
+	switch format {
+	case FormatGoogleMerchantXML:
+		return newGoogleWriter(w, meta)
+	case FormatFacebookCSV:
+		return newFacebookWriter(w)
+	default:
+		return nil, fmt.Errorf("unknown feed format %q", format)
+	}
+}
+
+// googleNamespace is the namespace of Google Merchant product attributes
+const googleNamespace = "http://base.google.com/ns/1.0"
+
+type googleItem struct {
+	XMLName          xml.Name `xml:"item"`
+	ID               string   `xml:"g:id"`
+	ItemGroupID      string   `xml:"g:item_group_id"`
+	Title            string   `xml:"g:title"`
+	Description      string   `xml:"g:description"`
+	Link             string   `xml:"g:link"`
+	ImageLink        string   `xml:"g:image_link"`
+	Availability     string   `xml:"g:availability"`
+	Price            string   `xml:"g:price"`
+	SalePrice        string   `xml:"g:sale_price,omitempty"`
+	Condition        string   `xml:"g:condition"`
+	Brand            string   `xml:"g:brand,omitempty"`
+	GTIN             string   `xml:"g:gtin,omitempty"`
+	MPN              string   `xml:"g:mpn,omitempty"`
+	IdentifierExists string   `xml:"g:identifier_exists,omitempty"`
+}
+
+// googleWriter writes an RSS 2.0 Google Merchant feed
+type googleWriter struct {
+	w   io.Writer
+	enc *xml.Encoder
+}
+
+func newGoogleWriter(w io.Writer, meta Meta) (*googleWriter, error) {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<rss version="2.0" xmlns:g="` + googleNamespace + `">` + "\n<channel>\n")
+	for _, el := range []struct{ name, value string }{
+		{"title", meta.Title},
+		{"link", meta.Link},
+		{"description", meta.Description},
+	} {
+		b.WriteString("<" + el.name + ">")
+		xml.EscapeText(&b, []byte(el.value))
+		b.WriteString("</" + el.name + ">\n")
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return nil, err
+	}
+	return &googleWriter{w: w, enc: xml.NewEncoder(w)}, nil
+}
+
+func (g *googleWriter) Write(item Item) error {
+	gi := googleItem{
+		ID:           item.ID,
+		ItemGroupID:  item.GroupID,
+		Title:        truncate(item.Title, maxTitleLength),
+		Description:  truncate(description(item), maxDescriptionLength),
+		Link:         item.Link,
+		ImageLink:    item.ImageLink,
+		Availability: "out_of_stock",
+		Price:        FormatPrice(item.PriceCents, item.Currency),
+		Condition:    "new",
+		Brand:        item.Brand,
+		GTIN:         item.GTIN,
+		MPN:          item.MPN,
+	}
+	if item.InStock {
+		gi.Availability = "in_stock"
+	}
+	if item.SalePriceCents != nil {
+		gi.SalePrice = FormatPrice(*item.SalePriceCents, item.Currency)
+	}
+	if item.GTIN == "" && item.MPN == "" {
+		gi.IdentifierExists = "no"
+	}
+	if err := g.enc.Encode(gi); err != nil {
+		return err
+	}
+	_, err := io.WriteString(g.w, "\n")
+	return err
+}
+
+func (g *googleWriter) Close() error {
+	if err := g.enc.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(g.w, "</channel>\n</rss>\n")
+	return err
+}
+
+// facebookColumns are the columns of a Facebook catalog feed, in order
+var facebookColumns = []string{
+	"id", "item_group_id", "title", "description", "availability", "condition",
+	"price", "sale_price", "link", "image_link", "brand", "gtin", "mpn",
+}
+
+// facebookWriter writes a Facebook catalog CSV feed
+type facebookWriter struct {
+	csv *csv.Writer
+}
+
+func newFacebookWriter(w io.Writer) (*facebookWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(facebookColumns); err != nil {
+		return nil, err
+	}
+	return &facebookWriter{csv: cw}, nil
+}
+
+func (f *facebookWriter) Write(item Item) error {
+	availability := "out of stock"
+	if item.InStock {
+		availability = "in stock"
+	}
+	var salePrice string
+	if item.SalePriceCents != nil {
+		salePrice = FormatPrice(*item.SalePriceCents, item.Currency)
+	}
+	return f.csv.Write([]string{
+		item.ID,
+		item.GroupID,
+		truncate(item.Title, maxTitleLength),
+		truncate(description(item), maxDescriptionLength),
+		availability,
+		"new",
+		FormatPrice(item.PriceCents, item.Currency),
+		salePrice,
+		item.Link,
+		item.ImageLink,
+		item.Brand,
+		item.GTIN,
+		item.MPN,
+	})
+}
+
+func (f *facebookWriter) Close() error {
+	f.csv.Flush()
+	return f.csv.Error()
+}
+
+// description falls back to the title, since both marketplaces require one
+func description(item Item) string {
+	if d := strings.TrimSpace(item.Description); d != "" {
+		return d
+	}
+	return item.Title
+}
+
+// truncate cuts s to at most n runes
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,176 @@
+package feeds
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateLinkTemplate(t *testing.T) {
+	tests := []struct {
+		template string
+		wantErr  bool
+	}{
+		{template: "https://shop.example.com/products/{handle}"},
+		{template: "http://localhost:3000/p/{handle}?ref=feed"},
+		{template: "https://shop.example.com/products", wantErr: true},
+		{template: "/products/{handle}", wantErr: true},
+		{template: "ftp://shop.example.com/{handle}", wantErr: true},
+	}
+	for _, tt := range tests {
+		err := ValidateLinkTemplate(tt.template)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateLinkTemplate(%q) error = %v, wantErr %v", tt.template, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidLinkTemplate) {
+			t.Errorf("ValidateLinkTemplate(%q) error = %v, want ErrInvalidLinkTemplate", tt.template, err)
+		}
+	}
+}
+
+func TestLink(t *testing.T) {
+	template := "https://shop.example.com/products/{handle}?ref=feed"
+	if got, want := Link(template, "linen shirt"), "https://shop.example.com/products/linen%20shirt?ref=feed"; got != want {
+		t.Errorf("Link() = %q, want %q", got, want)
+	}
+	if got, want := SiteLink(template), "https://shop.example.com"; got != want {
+		t.Errorf("SiteLink() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatPrice(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		currency string
+		want     string
+	}{
+		{amount: 1250, currency: "USD", want: "12.50 USD"},
+		{amount: 5, currency: "EUR", want: "0.05 EUR"},
+		{amount: 1500, currency: "JPY", want: "1500 JPY"},
+		{amount: 12345, currency: "KWD", want: "12.345 KWD"},
+	}
+	for _, tt := range tests {
+		if got := FormatPrice(tt.amount, tt.currency); got != tt.want {
+			t.Errorf("FormatPrice(%d, %s) = %q, want %q", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestItemTitle(t *testing.T) {
+	tests := []struct {
+		product, variant, want string
+	}{
+		{product: "Linen Shirt", variant: "Default Title", want: "Linen Shirt"},
+		{product: "Linen Shirt", variant: "", want: "Linen Shirt"},
+		{product: "Linen Shirt", variant: "Large / Blue", want: "Linen Shirt - Large / Blue"},
+	}
+	for _, tt := range tests {
+		if got := ItemTitle(tt.product, tt.variant); got != tt.want {
+			t.Errorf("ItemTitle(%q, %q) = %q, want %q", tt.product, tt.variant, got, tt.want)
+		}
+	}
+}
+
+func testItems() []Item {
+	sale := int64(1500)
+	return []Item{
+		{
+			ID: "v1", GroupID: "p1", Title: "Linen Shirt - Large", Description: "Cool & crisp <linen>",
+			Link: "https://shop.example.com/products/linen-shirt", ImageLink: "https://cdn.example.com/a.jpg",
+			PriceCents: 2000, SalePriceCents: &sale, Currency: "USD", InStock: true, Brand: "Acme", GTIN: "0123456789012",
+		},
+		{
+			ID: "v2", GroupID: "p2", Title: "Tote", Link: "https://shop.example.com/products/tote",
+			ImageLink: "https://cdn.example.com/b.jpg", PriceCents: 900, Currency: "USD", Brand: "Acme",
+		},
+	}
+}
+
+func TestGoogleWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, FormatGoogleMerchantXML, Meta{Title: "Acme & Co", Link: "https://shop.example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range testItems() {
+		if err := w.Write(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var feed struct {
+		Channel struct {
+			Title string `xml:"title"`
+			Items []struct {
+				ID               string `xml:"http://base.google.com/ns/1.0 id"`
+				Description      string `xml:"http://base.google.com/ns/1.0 description"`
+				Availability     string `xml:"http://base.google.com/ns/1.0 availability"`
+				Price            string `xml:"http://base.google.com/ns/1.0 price"`
+				SalePrice        string `xml:"http://base.google.com/ns/1.0 sale_price"`
+				IdentifierExists string `xml:"http://base.google.com/ns/1.0 identifier_exists"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &feed); err != nil {
+		t.Fatalf("feed is not valid XML: %v\n%s", err, buf.String())
+	}
+	if feed.Channel.Title != "Acme & Co" || len(feed.Channel.Items) != 2 {
+		t.Fatalf("channel = %+v", feed.Channel)
+	}
+	first, second := feed.Channel.Items[0], feed.Channel.Items[1]
+	if first.ID != "v1" || first.Availability != "in_stock" || first.Price != "20.00 USD" || first.SalePrice != "15.00 USD" {
+		t.Errorf("first item = %+v", first)
+	}
+	if first.Description != "Cool & crisp <linen>" || first.IdentifierExists != "" {
+		t.Errorf("first item = %+v", first)
+	}
+	if second.Availability != "out_of_stock" || second.Description != "Tote" || second.IdentifierExists != "no" {
+		t.Errorf("second item = %+v", second)
+	}
+}
+
+func TestFacebookWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, FormatFacebookCSV, Meta{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range testItems() {
+		if err := w.Write(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || strings.Join(records[0], ",") != strings.Join(facebookColumns, ",") {
+		t.Fatalf("records = %q", records)
+	}
+	row := map[string]string{}
+	for i, col := range records[0] {
+		row[col] = records[1][i]
+	}
+	if row["availability"] != "in stock" || row["price"] != "20.00 USD" || row["sale_price"] != "15.00 USD" || row["item_group_id"] != "p1" {
+		t.Errorf("row = %v", row)
+	}
+}
+
+func TestNewWriterUnknownFormat(t *testing.T) {
+	if _, err := NewWriter(&bytes.Buffer{}, "rss", Meta{}); err == nil {
+		t.Error("NewWriter() error = nil for an unknown format")
+	}
+	if IsValidFormat("rss") || !IsValidFormat(FormatFacebookCSV) {
+		t.Error("IsValidFormat() is wrong")
+	}
+}
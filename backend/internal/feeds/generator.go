@@ -0,0 +1,229 @@
+package feeds
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/storage"
+)
+
+// DefaultRetryDelay is how long a feed that failed to generate waits to be
+// tried again
+const DefaultRetryDelay = 15 * time.Minute
+
+// itemBatchSize is how many items are read from the database at once
+const itemBatchSize = 500
+
+// GeneratorConfig configures a Generator
+type GeneratorConfig struct {
+	Interval time.Duration
+	// RetryDelay is how long after a failed run a feed is tried again
+	RetryDelay time.Duration
+	// BatchSize bounds the feeds generated each interval
+	BatchSize int
+	Logger    *slog.Logger
+}
+
+// Generator regenerates feeds that are due and stores them. Each feed is
+// claimed before it is generated, so running several at once is harmless.
+type Generator struct {
+	db      *database.Queries
+	storage storage.Storage
+	cfg     GeneratorConfig
+}
+
+// NewGenerator creates a generator storing feeds in store
+func NewGenerator(db *database.Queries, store storage.Storage, cfg GeneratorConfig) *Generator {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = DefaultRetryDelay
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 10
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &Generator{db: db, storage: store, cfg: cfg}
+}
+
+// Run generates due feeds once an interval until ctx is cancelled
+func (g *Generator) Run(ctx context.Context) error {
+	ticker := time.NewTicker(g.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := g.GenerateDue(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			g.cfg.Logger.Error("product feed generation failed", "error", err)
+		} else if n > 0 {
+			g.cfg.Logger.Info("product feeds generated", "feeds", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// GenerateDue generates up to a batch of feeds due by now and returns how
+// many were handled, failed ones included. A feed that fails keeps its
+// previous file and is retried after the retry delay.
+func (g *Generator) GenerateDue(ctx context.Context, now time.Time) (int, error) {
+	var n int
+	for n < g.cfg.BatchSize && ctx.Err() == nil {
+		feed, err := g.db.ClaimDueProductFeed(ctx, now)
+		if errors.Is(err, sql.ErrNoRows) {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		n++
+
+		if err := g.generate(ctx, feed, now); err != nil {
+			g.cfg.Logger.Warn("product feed failed",
+				"feed_id", feed.ID,
+				"store_id", feed.StoreID,
+				"error", err,
+			)
+			if err := g.db.RecordProductFeedFailure(ctx, database.RecordProductFeedFailureParams{
+				ID:        feed.ID,
+				LastError: sql.NullString{String: err.Error(), Valid: true},
+				NextRunAt: now.Add(g.cfg.RetryDelay),
+			}); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// generate writes the feed to a temporary file and stores it under a new
+// key, then points the feed at it and removes the file it replaces. Keys
+// are never overwritten, since stored objects are cached as immutable.
+func (g *Generator) generate(ctx context.Context, feed database.ProductFeed, now time.Time) error {
+	store, err := g.db.GetStoreByID(ctx, feed.StoreID)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp("", "feed-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	count, err := g.write(ctx, file, feed, store)
+	if err != nil {
+		return err
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	key := fmt.Sprintf("%sstores/%s/feeds/%s/%d%s", storage.PrivatePrefix, feed.StoreID, feed.ID, now.UnixNano(), Extension(feed.Format))
+	if err := g.storage.Put(ctx, key, ContentType(feed.Format), file, size); err != nil {
+		return fmt.Errorf("store feed: %w", err)
+	}
+
+	if err := g.db.RecordProductFeedGenerated(ctx, database.RecordProductFeedGeneratedParams{
+		ID:         feed.ID,
+		StorageKey: sql.NullString{String: key, Valid: true},
+		ItemCount:  sql.NullInt32{Int32: int32(count), Valid: true},
+		SizeBytes:  sql.NullInt64{Int64: size, Valid: true},
+	}); err != nil {
+		return err
+	}
+	if feed.StorageKey.Valid {
+		if err := g.storage.Delete(ctx, feed.StorageKey.String); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			g.cfg.Logger.Warn("unable to delete replaced product feed", "feed_id", feed.ID, "key", feed.StorageKey.String, "error", err)
+		}
+	}
+
+	g.cfg.Logger.Info("product feed generated",
+		"feed_id", feed.ID,
+		"store_id", feed.StoreID,
+		"format", feed.Format,
+		"items", count,
+		"size_bytes", size,
+	)
+	return nil
+}
+
+// write writes every item of the feed to w a batch at a time
+func (g *Generator) write(ctx context.Context, w io.Writer, feed database.ProductFeed, store database.Store) (int, error) {
+	fw, err := NewWriter(w, feed.Format, Meta{
+		Title:       store.Name,
+		Link:        SiteLink(feed.LinkTemplate),
+		Description: feed.Name,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	params := database.ListProductFeedItemsParams{
+		ChannelID: feed.ChannelID,
+		StoreID:   feed.StoreID,
+		RowLimit:  itemBatchSize,
+	}
+	count := 0
+	for {
+		batch, err := g.db.ListProductFeedItems(ctx, params)
+		if err != nil {
+			return 0, err
+		}
+		for _, row := range batch {
+			if err := fw.Write(g.item(row, feed, store)); err != nil {
+				return 0, err
+			}
+		}
+		count += len(batch)
+		if len(batch) < itemBatchSize {
+			break
+		}
+		last := batch[len(batch)-1]
+		params.HasCursor, params.CursorProductID, params.CursorVariantID = true, last.ProductID, last.VariantID
+	}
+	return count, fw.Close()
+}
+
+func (g *Generator) item(row database.ListProductFeedItemsRow, feed database.ProductFeed, store database.Store) Item {
+	item := Item{
+		ID:          row.VariantID.String(),
+		GroupID:     row.ProductID.String(),
+		Title:       ItemTitle(row.Name, row.Title),
+		Description: row.Description.String,
+		Link:        Link(feed.LinkTemplate, row.Handle),
+		ImageLink:   g.storage.URL(row.ImageKey),
+		PriceCents:  int64(row.PriceCents),
+		Currency:    store.DefaultCurrency,
+		InStock:     row.InStock,
+		Brand:       store.Name,
+		GTIN:        row.Barcode.String,
+		MPN:         row.Sku.String,
+	}
+	// A compare-at price above the price means the item is on sale
+	if row.CompareAtCents.Valid && int64(row.CompareAtCents.Int32) > item.PriceCents {
+		sale := item.PriceCents
+		item.PriceCents = int64(row.CompareAtCents.Int32)
+		item.SalePriceCents = &sale
+	}
+	return item
+}
@@ -0,0 +1,398 @@
+package fixtures
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgproto3"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// DB is a Postgres server, speaking just enough of the protocol for pgx,
+// that answers each sqlc query with the rows set for it by name. Queries
+// without rows find none, and statements that aren't queries succeed, so a
+// handler run against it sees what a database holding only those rows
+// would return.
+//
+// Connect a pool to it by setting its DialFunc to Dial:
+//
+//	db := fixtures.NewDB(t)
+//	db.Set("GetStoreByID", database.Store{ID: fixtures.ID, Name: "Demo"})
+//	poolConfig.ConnConfig.DialFunc = db.Dial
+type DB struct {
+	t    testing.TB
+	next uint32
+
+	mu   sync.Mutex
+	rows map[string][]any
+	ran  []string
+}
+
+// NewDB starts a DB that logs the queries it answers to t
+func NewDB(t testing.TB) *DB {
+	return &DB{t: t, rows: map[string][]any{}}
+}
+
+// Set makes the query called name return rows, each the row struct sqlc
+// scans it into or, for a query of one column, that column's value
+func (d *DB) Set(name string, rows ...any) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.rows[name] = rows
+}
+
+// Ran lists the names of the queries run, in order
+func (d *DB) Ran() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.ran...)
+}
+
+// Dial opens a connection to the DB, served until the client hangs up
+func (d *DB) Dial(context.Context, string, string) (net.Conn, error) {
+	client, server := net.Pipe()
+	d.mu.Lock()
+	d.next++
+	id := d.next
+	d.mu.Unlock()
+	go d.serve(id, server)
+	return client, nil
+}
+
+// queryName is the sqlc name of query, or "" for SQL sqlc didn't generate
+func queryName(query string) string {
+	rest, ok := strings.CutPrefix(query, "-- name: ")
+	if !ok {
+		return ""
+	}
+	name, _, _ := strings.Cut(rest, " ")
+	return name
+}
+
+// returnsRows reports whether query is a sqlc query of rows, rather than
+// one run for its effect
+func returnsRows(query string) bool {
+	line, _, _ := strings.Cut(query, "\n")
+	return strings.HasSuffix(line, " :one") || strings.HasSuffix(line, " :many")
+}
+
+// command is the command tag of query: its first keyword, past the sqlc
+// name comment
+func command(query string) string {
+	if queryName(query) != "" {
+		_, query, _ = strings.Cut(query, "\n")
+	}
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToUpper(fields[0])
+}
+
+// rowsOf is what query returns, logged as run when run is set
+func (d *DB) rowsOf(query string, run bool) []any {
+	name := queryName(query)
+	if name == "" {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	rows, ok := d.rows[name]
+	if run {
+		d.ran = append(d.ran, name)
+		if !ok && returnsRows(query) {
+			d.t.Logf("fixtures: %s finds no rows", name)
+		}
+	}
+	return rows
+}
+
+// columns describes the columns of rows, all of one type
+func columns(rows []any) []pgproto3.FieldDescription {
+	if len(rows) == 0 {
+		return nil
+	}
+	var fields []pgproto3.FieldDescription
+	column := func(name string, t reflect.Type) {
+		fields = append(fields, pgproto3.FieldDescription{Name: []byte(name), DataTypeOID: oid(t)})
+	}
+	t := reflect.TypeOf(rows[0])
+	if columnType(t) {
+		column("value", t)
+		return fields
+	}
+	for i := range t.NumField() {
+		column(t.Field(i).Name, t.Field(i).Type)
+	}
+	return fields
+}
+
+// values is the columns of row
+func values(row any) []any {
+	v := reflect.ValueOf(row)
+	if columnType(v.Type()) {
+		return []any{plain(v)}
+	}
+	var vals []any
+	for i := range v.NumField() {
+		vals = append(vals, plain(v.Field(i)))
+	}
+	return vals
+}
+
+// columnType reports whether t is the value of one column rather than a
+// row struct of several
+func columnType(t reflect.Type) bool {
+	return t.Kind() != reflect.Struct || t == timeType || nullable(t)
+}
+
+// nullable reports whether t is a nullable column, like sql.NullString: a
+// struct of the value and whether it is set
+func nullable(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t.NumField() != 2 {
+		return false
+	}
+	valid, ok := t.FieldByName("Valid")
+	return ok && valid.Index[0] == 1 && valid.Type.Kind() == reflect.Bool
+}
+
+// plain is the value of a column as pgx encodes it. Nullable columns are
+// their value or nil, and types of a string, such as the sealed ones of
+// package crypto, their plaintext, so the row reads back as it was set.
+func plain(v reflect.Value) any {
+	t := v.Type()
+	switch {
+	case t == timeType || t == uuidType:
+		return v.Interface()
+	case nullable(t):
+		if !v.Field(1).Bool() {
+			return nil
+		}
+		return plain(v.Field(0))
+	case t.Kind() == reflect.String:
+		return v.String()
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return v.Bytes()
+	}
+	return v.Interface()
+}
+
+// oid is the type of the column a value of t is scanned from. Times are
+// timestamps without a zone, so they read back in UTC.
+func oid(t reflect.Type) uint32 {
+	if nullable(t) {
+		t = t.Field(0).Type
+	}
+	switch {
+	case t == timeType:
+		return pgtype.TimestampOID
+	case t == uuidType:
+		return pgtype.UUIDOID
+	case t == rawType:
+		return pgtype.JSONBOID
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return pgtype.TextOID
+	case reflect.Bool:
+		return pgtype.BoolOID
+	case reflect.Int16:
+		return pgtype.Int2OID
+	case reflect.Int32:
+		return pgtype.Int4OID
+	case reflect.Int, reflect.Int64:
+		return pgtype.Int8OID
+	case reflect.Float32, reflect.Float64:
+		return pgtype.Float8OID
+	case reflect.Slice:
+		switch elem := t.Elem(); {
+		case elem.Kind() == reflect.Uint8:
+			return pgtype.ByteaOID
+		case elem == uuidType:
+			return pgtype.UUIDArrayOID
+		case elem.Kind() == reflect.String:
+			return pgtype.TextArrayOID
+		case elem.Kind() == reflect.Int32:
+			return pgtype.Int4ArrayOID
+		case elem.Kind() == reflect.Int64:
+			return pgtype.Int8ArrayOID
+		}
+	}
+	panic(fmt.Sprintf("fixtures: no column type for %s", t))
+}
+
+// format is the format of column i the client bound formats for
+func format(formats []int16, i int) int16 {
+	switch {
+	case len(formats) == 1:
+		return formats[0]
+	case i < len(formats):
+		return formats[i]
+	}
+	return pgtype.TextFormatCode
+}
+
+// placeholder matches the parameters of a query, along with the type
+// they are cast to
+var placeholder = regexp.MustCompile(`\$(\d+)(?:::([a-z0-9]+)(\[\])?)?`)
+
+// typeNames are the names of the types pgtype knows by another
+var typeNames = map[string]string{
+	"bigint":  "int8",
+	"boolean": "bool",
+	"int":     "int4",
+	"integer": "int4",
+	"real":    "float4",
+}
+
+// paramOIDs is the types of the parameters of query
+func paramOIDs(types *pgtype.Map, query string) []uint32 {
+	var oids []uint32
+	for _, m := range placeholder.FindAllStringSubmatch(query, -1) {
+		i, _ := strconv.Atoi(m[1])
+		for len(oids) < i {
+			oids = append(oids, 0)
+		}
+		name := m[2]
+		if alias, ok := typeNames[name]; ok {
+			name = alias
+		}
+		if m[3] != "" {
+			name = "_" + name
+		}
+		if t, ok := types.TypeForName(name); ok && name != "" {
+			oids[i-1] = t.OID
+		}
+	}
+	return oids
+}
+
+func (d *DB) serve(id uint32, conn net.Conn) {
+	defer conn.Close()
+	be := pgproto3.NewBackend(conn, conn)
+	for {
+		msg, err := be.ReceiveStartupMessage()
+		if err != nil {
+			return
+		}
+		if _, ok := msg.(*pgproto3.StartupMessage); ok {
+			break
+		}
+		// Refuse TLS and GSS encryption, so the client speaks in the clear
+		if _, err := conn.Write([]byte("N")); err != nil {
+			return
+		}
+	}
+	be.Send(&pgproto3.AuthenticationOk{})
+	be.Send(&pgproto3.BackendKeyData{ProcessID: id})
+	txStatus := byte('I')
+	be.Send(&pgproto3.ReadyForQuery{TxStatus: txStatus})
+	if be.Flush() != nil {
+		return
+	}
+
+	types := pgtype.NewMap()
+	// The statements prepared, by name, and the portal bound
+	prepared := map[string]string{}
+	var (
+		query   string
+		formats []int16
+		failed  bool
+	)
+	complete := func(query string, rows int) {
+		tag := command(query)
+		switch tag {
+		case "BEGIN":
+			txStatus = 'T'
+		case "COMMIT", "ROLLBACK":
+			txStatus = 'I'
+		case "SELECT", "UPDATE", "DELETE":
+			tag = fmt.Sprintf("%s %d", tag, rows)
+		case "INSERT":
+			tag = fmt.Sprintf("INSERT 0 %d", rows)
+		}
+		be.Send(&pgproto3.CommandComplete{CommandTag: []byte(tag)})
+	}
+	fail := func(err error) {
+		be.Send(&pgproto3.ErrorResponse{Severity: "ERROR", Code: "XX000", Message: err.Error()})
+		failed = true
+	}
+	for {
+		msg, err := be.Receive()
+		if err != nil {
+			return
+		}
+		switch msg := msg.(type) {
+		case *pgproto3.Query:
+			complete(msg.String, 0)
+			be.Send(&pgproto3.ReadyForQuery{TxStatus: txStatus})
+		case *pgproto3.Parse:
+			prepared[msg.Name] = msg.Query
+			be.Send(&pgproto3.ParseComplete{})
+		case *pgproto3.Describe:
+			described := query
+			if msg.ObjectType == 'S' {
+				described = prepared[msg.Name]
+				// Parameters are of the type they are cast to, as sqlc
+				// writes them, or else unknown, which pgx encodes by their
+				// Go type
+				be.Send(&pgproto3.ParameterDescription{ParameterOIDs: paramOIDs(types, described)})
+			}
+			fields := columns(d.rowsOf(described, false))
+			if msg.ObjectType == 'P' {
+				for i := range fields {
+					fields[i].Format = format(formats, i)
+				}
+			}
+			if len(fields) == 0 {
+				be.Send(&pgproto3.NoData{})
+			} else {
+				be.Send(&pgproto3.RowDescription{Fields: fields})
+			}
+		case *pgproto3.Bind:
+			query = prepared[msg.PreparedStatement]
+			formats = msg.ResultFormatCodes
+			be.Send(&pgproto3.BindComplete{})
+		case *pgproto3.Execute:
+			if failed {
+				break
+			}
+			rows := d.rowsOf(query, true)
+			fields := columns(rows)
+			sent := 0
+			for _, row := range rows {
+				row := values(row)
+				encoded := make([][]byte, len(row))
+				for i, v := range row {
+					if encoded[i], err = types.Encode(fields[i].DataTypeOID, format(formats, i), v, nil); err != nil {
+						fail(fmt.Errorf("encode %s of %s: %w", fields[i].Name, queryName(query), err))
+						break
+					}
+				}
+				if failed {
+					break
+				}
+				be.Send(&pgproto3.DataRow{Values: encoded})
+				sent++
+			}
+			if !failed {
+				complete(query, sent)
+			}
+		case *pgproto3.Sync:
+			be.Send(&pgproto3.ReadyForQuery{TxStatus: txStatus})
+			failed = false
+		case *pgproto3.Terminate:
+			return
+		}
+		if be.Flush() != nil {
+			return
+		}
+	}
+}
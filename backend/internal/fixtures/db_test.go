@@ -0,0 +1,89 @@
+package fixtures
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type row struct {
+	ID      uuid.UUID
+	Name    string
+	Count   int32
+	Total   int64
+	Enabled bool
+	At      time.Time
+	Note    sql.NullString
+	Parent  uuid.NullUUID
+	Tags    []string
+	Doc     json.RawMessage
+}
+
+func newPool(t *testing.T, db *DB) *pgxpool.Pool {
+	t.Helper()
+	cfg, err := pgxpool.ParseConfig("postgres://fixtures/db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.ConnConfig.DialFunc = db.Dial
+	cfg.ConnConfig.LookupFunc = func(context.Context, string) ([]string, error) { return []string{"127.0.0.1"}, nil }
+	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func scanRow(r pgx.Row) (row, error) {
+	var i row
+	err := r.Scan(&i.ID, &i.Name, &i.Count, &i.Total, &i.Enabled, &i.At, &i.Note, &i.Parent, &i.Tags, &i.Doc)
+	return i, err
+}
+
+func TestDB(t *testing.T) {
+	db := NewDB(t)
+	want := Fill[row]()
+	db.Set("GetRow", want)
+	db.Set("CountRows", int64(2))
+	pool := newPool(t, db)
+	ctx := context.Background()
+
+	got, err := scanRow(pool.QueryRow(ctx, "-- name: GetRow :one\nSELECT * FROM rows WHERE id = $1", ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetRow = %+v, want %+v", got, want)
+	}
+
+	var count int64
+	if err := pool.QueryRow(ctx, "-- name: CountRows :one\nSELECT count(*) FROM rows").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("CountRows = %d, want 2", count)
+	}
+
+	_, err = scanRow(pool.QueryRow(ctx, "-- name: GetOther :one\nSELECT * FROM rows WHERE id = $1 AND name = $2", ID, "name"))
+	if !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("GetOther returned %v, want no rows", err)
+	}
+
+	if _, err := pool.Exec(ctx, "-- name: DeleteRow :exec\nDELETE FROM rows WHERE id = $1", ID); err != nil {
+		t.Fatal(err)
+	}
+
+	wantRan := []string{"GetRow", "CountRows", "GetOther", "DeleteRow"}
+	if got := db.Ran(); !reflect.DeepEqual(got, wantRan) {
+		t.Errorf("Ran() = %v, want %v", got, wantRan)
+	}
+}
@@ -0,0 +1,158 @@
+// Package fixtures builds the deterministic values handler tests render
+// responses from, serves them to handlers as the rows of a database, and
+// compares what they render with golden files.
+//
+// Golden files live under the testdata directory of the package under
+// test. Run its tests with -update to rewrite them after an intended
+// change to the API contract, and review the diff.
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files with what the tests render")
+
+var (
+	// ID is the UUID of every filled ID
+	ID = uuid.MustParse("00000000-0000-4000-8000-000000000001")
+	// Time is the time of every filled timestamp
+	Time = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+)
+
+var (
+	timeType = reflect.TypeFor[time.Time]()
+	uuidType = reflect.TypeFor[uuid.UUID]()
+	rawType  = reflect.TypeFor[json.RawMessage]()
+)
+
+// maxDepth stops Fill at types that contain themselves
+const maxDepth = 6
+
+// Fill returns a T with every field set, recursively: strings to their
+// JSON name, numbers to 1, booleans to true, IDs to ID, times to Time,
+// pointers to a filled value, and slices and maps to one filled element.
+// Responses rendered from a zero T and from Fill show which fields are
+// null, omitted or always present.
+func Fill[T any]() T {
+	var v T
+	fill(reflect.ValueOf(&v).Elem(), "value", 0)
+	return v
+}
+
+func fill(v reflect.Value, name string, depth int) {
+	if depth > maxDepth {
+		return
+	}
+	switch t := v.Type(); {
+	case t == timeType:
+		v.Set(reflect.ValueOf(Time))
+		return
+	case t == uuidType:
+		v.Set(reflect.ValueOf(ID))
+		return
+	case t == rawType:
+		v.SetBytes([]byte(`{"key":"value"}`))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(name)
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1.5)
+	case reflect.Pointer:
+		p := reflect.New(v.Type().Elem())
+		fill(p.Elem(), name, depth+1)
+		v.Set(p)
+	case reflect.Slice:
+		s := reflect.MakeSlice(v.Type(), 1, 1)
+		fill(s.Index(0), name, depth+1)
+		v.Set(s)
+	case reflect.Array:
+		for i := range v.Len() {
+			fill(v.Index(i), name, depth+1)
+		}
+	case reflect.Map:
+		m := reflect.MakeMapWithSize(v.Type(), 1)
+		key := reflect.New(v.Type().Key()).Elem()
+		fill(key, "key", depth+1)
+		elem := reflect.New(v.Type().Elem()).Elem()
+		fill(elem, name, depth+1)
+		m.SetMapIndex(key, elem)
+		v.Set(m)
+	case reflect.Interface:
+		if v.NumMethod() == 0 {
+			v.Set(reflect.ValueOf(name))
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldName := jsonName(field)
+			if fieldName == "-" {
+				continue
+			}
+			fill(v.Field(i), fieldName, depth+1)
+		}
+	}
+}
+
+// jsonName is the name a field is encoded under
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// Golden compares got, which must be JSON, with the golden file at path
+// under testdata, after indenting both the same way. With -update it
+// writes got there instead.
+func Golden(t *testing.T, path string, got []byte) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, got, "", "  "); err != nil {
+		t.Fatalf("%s: rendered invalid JSON: %v\n%s", path, err, got)
+	}
+	buf.WriteByte('\n')
+	got = buf.Bytes()
+
+	file := filepath.Join("testdata", path)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("%s: %v; run the tests with -update to create it", file, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s changed; if that is intended, run the tests with -update and review the diff\ngot:\n%s\nwant:\n%s", file, got, want)
+	}
+}
@@ -0,0 +1,64 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type nested struct {
+	Name string `json:"name"`
+}
+
+type filled struct {
+	Name     string            `json:"name"`
+	Count    int32             `json:"count"`
+	Ratio    float64           `json:"ratio"`
+	Enabled  bool              `json:"enabled"`
+	ID       uuid.UUID         `json:"id"`
+	At       time.Time         `json:"at"`
+	Note     *string           `json:"note,omitempty"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels"`
+	Raw      json.RawMessage   `json:"raw"`
+	Any      any               `json:"any"`
+	Child    *nested           `json:"child"`
+	Untagged string
+	Skipped  string `json:"-"`
+	private  string
+}
+
+func TestFill(t *testing.T) {
+	got, err := json.Marshal(Fill[filled]())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"name":"name","count":1,"ratio":1.5,"enabled":true,` +
+		`"id":"00000000-0000-4000-8000-000000000001","at":"2024-01-02T03:04:05Z",` +
+		`"note":"note","tags":["tags"],"labels":{"key":"labels"},"raw":{"key":"value"},` +
+		`"any":"any","child":{"name":"name"},"Untagged":"Untagged"}`
+	if string(got) != want {
+		t.Errorf("Fill() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+type recursive struct {
+	Next *recursive `json:"next"`
+}
+
+func TestFillRecursive(t *testing.T) {
+	v := Fill[recursive]()
+	depth := 0
+	for n := v.Next; n != nil; n = n.Next {
+		depth++
+	}
+	if depth == 0 || depth > maxDepth {
+		t.Errorf("depth = %d, want between 1 and %d", depth, maxDepth)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	Golden(t, "golden.json", []byte(`{"name":"name","tags":["a","b"]}`))
+}
@@ -0,0 +1,159 @@
+// Package fulfillment validates fulfillment status changes, checks that
+// fulfilled quantities fit an order, and rolls them up into the order's
+// fulfillment status.
+package fulfillment
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidTransition = errors.New("invalid fulfillment status transition")
+	ErrInvalidItems      = errors.New("invalid fulfillment items")
+)
+
+// Status is the lifecycle state of a fulfillment
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusShipped   Status = "shipped"
+	StatusDelivered Status = "delivered"
+	StatusCancelled Status = "cancelled"
+)
+
+// IsValid returns true if the status is known
+func (s Status) IsValid() bool {
+	switch s {
+	case StatusPending, StatusShipped, StatusDelivered, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+// transitions lists where each status may move. Delivered and cancelled
+// fulfillments are final.
+var transitions = map[Status][]Status{
+	StatusPending: {StatusShipped, StatusDelivered, StatusCancelled},
+	StatusShipped: {StatusDelivered, StatusCancelled},
+}
+
+// CanTransition checks a status change
+func CanTransition(from, to Status) error {
+	if !to.IsValid() {
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidTransition, to)
+	}
+	for _, next := range transitions[from] {
+		if next == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, from, to)
+}
+
+// Order fulfillment statuses, matching orders.fulfillment_status
+const (
+	OrderUnfulfilled = "unfulfilled"
+	OrderPartial     = "partial"
+	OrderFulfilled   = "fulfilled"
+)
+
+// Line is an order line item's ordered and already fulfilled quantity
+type Line struct {
+	ID        uuid.UUID
+	Ordered   int64
+	Fulfilled int64
+}
+
+// Item is a quantity of one line item to fulfill
+type Item struct {
+	LineItemID uuid.UUID
+	Quantity   int64
+}
+
+// Allocate resolves the items of a new fulfillment against the order's
+// lines. No items means everything still unfulfilled. Quantities for the
+// same line are merged.
+func Allocate(lines []Line, items []Item) ([]Item, error) {
+	if len(items) == 0 {
+		var all []Item
+		for _, line := range lines {
+			if remaining := line.Ordered - line.Fulfilled; remaining > 0 {
+				all = append(all, Item{LineItemID: line.ID, Quantity: remaining})
+			}
+		}
+		if len(all) == 0 {
+			return nil, fmt.Errorf("%w: the order is already fulfilled", ErrInvalidItems)
+		}
+		return all, nil
+	}
+
+	byID := make(map[uuid.UUID]Line, len(lines))
+	for _, line := range lines {
+		byID[line.ID] = line
+	}
+
+	var out []Item
+	index := map[uuid.UUID]int{}
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: quantities must be positive", ErrInvalidItems)
+		}
+		if _, ok := byID[item.LineItemID]; !ok {
+			return nil, fmt.Errorf("%w: line item %s is not on this order", ErrInvalidItems, item.LineItemID)
+		}
+		if i, ok := index[item.LineItemID]; ok {
+			out[i].Quantity += item.Quantity
+			continue
+		}
+		index[item.LineItemID] = len(out)
+		out = append(out, item)
+	}
+
+	for _, item := range out {
+		line := byID[item.LineItemID]
+		if remaining := line.Ordered - line.Fulfilled; item.Quantity > remaining {
+			return nil, fmt.Errorf("%w: only %d of line item %s left to fulfill", ErrInvalidItems, remaining, item.LineItemID)
+		}
+	}
+	return out, nil
+}
+
+// RollUp derives an order's fulfillment status from its lines
+func RollUp(lines []Line) string {
+	var ordered, fulfilled int64
+	for _, line := range lines {
+		ordered += line.Ordered
+		fulfilled += min(line.Fulfilled, line.Ordered)
+	}
+	switch {
+	case fulfilled == 0:
+		return OrderUnfulfilled
+	case fulfilled < ordered:
+		return OrderPartial
+	default:
+		return OrderFulfilled
+	}
+}
+
+// trackingURLs are tracking page templates for common carriers
+var trackingURLs = map[string]string{
+	"ups":   "https://www.ups.com/track?tracknum=%s",
+	"usps":  "https://tools.usps.com/go/TrackConfirmAction?tLabels=%s",
+	"fedex": "https://www.fedex.com/fedextrack/?trknbr=%s",
+	"dhl":   "https://www.dhl.com/en/express/tracking.html?AWB=%s",
+}
+
+// TrackingURL builds a tracking page link for known carriers, or returns ""
+func TrackingURL(carrier, number string) string {
+	tmpl, ok := trackingURLs[strings.ToLower(strings.TrimSpace(carrier))]
+	if !ok || number == "" {
+		return ""
+	}
+	return fmt.Sprintf(tmpl, url.QueryEscape(number))
+}
@@ -0,0 +1,102 @@
+package fulfillment
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to Status
+		wantErr  bool
+	}{
+		{StatusPending, StatusShipped, false},
+		{StatusPending, StatusCancelled, false},
+		{StatusShipped, StatusDelivered, false},
+		{StatusShipped, StatusPending, true},
+		{StatusDelivered, StatusCancelled, true},
+		{StatusCancelled, StatusShipped, true},
+		{StatusPending, "lost", true},
+	}
+	for _, tt := range tests {
+		err := CanTransition(tt.from, tt.to)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CanTransition(%s, %s) error = %v, wantErr %v", tt.from, tt.to, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidTransition) {
+			t.Errorf("error = %v, want ErrInvalidTransition", err)
+		}
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	lines := []Line{
+		{ID: a, Ordered: 3, Fulfilled: 1},
+		{ID: b, Ordered: 1, Fulfilled: 1},
+	}
+
+	tests := []struct {
+		name    string
+		lines   []Line
+		items   []Item
+		want    []Item
+		wantErr bool
+	}{
+		{name: "defaults to everything remaining", lines: lines, want: []Item{{LineItemID: a, Quantity: 2}}},
+		{name: "partial", lines: lines, items: []Item{{LineItemID: a, Quantity: 1}}, want: []Item{{LineItemID: a, Quantity: 1}}},
+		{name: "merges duplicates", lines: lines, items: []Item{{LineItemID: a, Quantity: 1}, {LineItemID: a, Quantity: 1}}, want: []Item{{LineItemID: a, Quantity: 2}}},
+		{name: "over remaining", lines: lines, items: []Item{{LineItemID: a, Quantity: 1}, {LineItemID: a, Quantity: 2}}, wantErr: true},
+		{name: "already fulfilled line", lines: lines, items: []Item{{LineItemID: b, Quantity: 1}}, wantErr: true},
+		{name: "unknown line", lines: lines, items: []Item{{LineItemID: uuid.New(), Quantity: 1}}, wantErr: true},
+		{name: "zero quantity", lines: lines, items: []Item{{LineItemID: a}}, wantErr: true},
+		{name: "nothing left", lines: []Line{{ID: b, Ordered: 1, Fulfilled: 1}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Allocate(tt.lines, tt.items)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Allocate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrInvalidItems) {
+					t.Errorf("error = %v, want ErrInvalidItems", err)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Allocate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRollUp(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []Line
+		want  string
+	}{
+		{name: "no lines", want: OrderUnfulfilled},
+		{name: "nothing fulfilled", lines: []Line{{Ordered: 2}}, want: OrderUnfulfilled},
+		{name: "some fulfilled", lines: []Line{{Ordered: 2, Fulfilled: 2}, {Ordered: 1}}, want: OrderPartial},
+		{name: "all fulfilled", lines: []Line{{Ordered: 2, Fulfilled: 2}, {Ordered: 1, Fulfilled: 1}}, want: OrderFulfilled},
+	}
+	for _, tt := range tests {
+		if got := RollUp(tt.lines); got != tt.want {
+			t.Errorf("%s: RollUp() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTrackingURL(t *testing.T) {
+	if got := TrackingURL("UPS", "1Z 999"); got != "https://www.ups.com/track?tracknum=1Z+999" {
+		t.Errorf("TrackingURL(UPS) = %q", got)
+	}
+	if got := TrackingURL("Pigeon Post", "123"); got != "" {
+		t.Errorf("TrackingURL(unknown carrier) = %q, want empty", got)
+	}
+}
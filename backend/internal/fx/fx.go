@@ -0,0 +1,53 @@
+// Package fx converts prices between currencies with exchange rates synced
+// from an external feed
+package fx
+
+import (
+	"math"
+	"regexp"
+)
+
+// Rates are units of each currency per one unit of a common base currency
+type Rates map[string]float64
+
+// currencyCode is an ISO 4217 alphabetic code
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// ValidCode reports whether code looks like an ISO 4217 code such as USD
+func ValidCode(code string) bool {
+	return currencyCode.MatchString(code)
+}
+
+// minorUnits lists the currencies without two decimal places
+var minorUnits = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// MinorUnits is how many decimal places amounts in currency have, so 2 for
+// USD cents and 0 for JPY
+func MinorUnits(currency string) int {
+	if n, ok := minorUnits[currency]; ok {
+		return n
+	}
+	return 2
+}
+
+// Convert converts amount, in minor units of from, to minor units of to,
+// rounding half away from zero. It is false when either rate is missing.
+func (r Rates) Convert(amount int64, from, to string) (int64, bool) {
+	if from == to {
+		return amount, true
+	}
+	fromRate, ok := r[from]
+	if !ok || fromRate <= 0 {
+		return 0, false
+	}
+	toRate, ok := r[to]
+	if !ok || toRate <= 0 {
+		return 0, false
+	}
+	scale := math.Pow10(MinorUnits(to) - MinorUnits(from))
+	return int64(math.Round(float64(amount) * toRate / fromRate * scale)), true
+}
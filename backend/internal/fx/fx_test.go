@@ -0,0 +1,95 @@
+package fx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	rates := Rates{"USD": 1, "EUR": 0.92, "JPY": 151.3, "KWD": 0.307}
+
+	tests := []struct {
+		name     string
+		amount   int64
+		from, to string
+		want     int64
+		wantOK   bool
+	}{
+		{name: "same currency", amount: 1999, from: "GBP", to: "GBP", want: 1999, wantOK: true},
+		{name: "from base", amount: 1000, from: "USD", to: "EUR", want: 920, wantOK: true},
+		{name: "to base", amount: 920, from: "EUR", to: "USD", want: 1000, wantOK: true},
+		{name: "cross rate", amount: 1000, from: "EUR", to: "JPY", want: 1645, wantOK: true},
+		{name: "zero decimal source", amount: 1513, from: "JPY", to: "USD", want: 1000, wantOK: true},
+		{name: "three decimal target", amount: 1000, from: "USD", to: "KWD", want: 3070, wantOK: true},
+		{name: "rounds to the nearest minor unit", amount: 1, from: "USD", to: "EUR", want: 1, wantOK: true},
+		{name: "missing target rate", amount: 1000, from: "USD", to: "GBP"},
+		{name: "missing source rate", amount: 1000, from: "GBP", to: "USD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := rates.Convert(tt.amount, tt.from, tt.to)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Convert(%d, %s, %s) = %d, %v, want %d, %v", tt.amount, tt.from, tt.to, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestFetch(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    Rates
+		wantErr string
+	}{
+		{
+			name:   "drops bad codes and rates",
+			status: http.StatusOK,
+			body:   `{"base":"USD","rates":{"EUR":0.92,"gbp":0.79,"XXXX":2,"JPY":0,"USD":1.5}}`,
+			want:   Rates{"USD": 1, "EUR": 0.92},
+		},
+		{
+			name:   "base left out",
+			status: http.StatusOK,
+			body:   `{"rates":{"EUR":0.92}}`,
+			want:   Rates{"USD": 1, "EUR": 0.92},
+		},
+		{name: "other base", status: http.StatusOK, body: `{"base":"EUR","rates":{"USD":1.08}}`, wantErr: "rates are against EUR"},
+		{name: "no rates", status: http.StatusOK, body: `{"base":"USD","rates":{}}`, wantErr: "no rates"},
+		{name: "not json", status: http.StatusOK, body: `<html>`, wantErr: "decode rates"},
+		{name: "error status", status: http.StatusTooManyRequests, body: `slow down`, wantErr: "status 429"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			s := NewSyncer(nil, Config{RatesURL: srv.URL}, nil)
+			got, err := s.fetch(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("fetch() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("fetch() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("fetch() = %v, want %v", got, tt.want)
+			}
+			for code, rate := range tt.want {
+				if got[code] != rate {
+					t.Errorf("fetch()[%s] = %v, want %v", code, got[code], rate)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/tracing"
+)
+
+// DefaultBase is the currency rates are fetched against when
+// FX_BASE_CURRENCY is unset
+const DefaultBase = "USD"
+
+// DefaultInterval is how often rates are synced when FX_SYNC_INTERVAL is
+// unset
+const DefaultInterval = 6 * time.Hour
+
+// Config configures rate syncing. An empty RatesURL turns it off.
+type Config struct {
+	// RatesURL returns JSON with a "rates" object of units per one unit
+	// of Base, such as https://api.frankfurter.app/latest?from=USD
+	RatesURL string
+	Base     string
+	Interval time.Duration
+}
+
+// Syncer keeps the exchange_rates table up to date from the feed at
+// RatesURL. Running several at once is harmless.
+type Syncer struct {
+	db     *database.Queries
+	cfg    Config
+	client *http.Client
+	logger *slog.Logger
+}
+
+// NewSyncer creates a syncer over db
+func NewSyncer(db *database.Queries, cfg Config, logger *slog.Logger) *Syncer {
+	if cfg.Base == "" {
+		cfg.Base = DefaultBase
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Syncer{
+		db:     db,
+		cfg:    cfg,
+		client: &http.Client{Timeout: 30 * time.Second, Transport: tracing.Transport(nil)},
+		logger: logger,
+	}
+}
+
+// Run syncs once an interval until ctx is cancelled. A failed sync keeps
+// the rates of the last good one.
+func (s *Syncer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := s.Sync(ctx)
+		if err != nil && ctx.Err() == nil {
+			s.logger.Error("exchange rate sync failed", "error", err)
+		} else if err == nil {
+			s.logger.Info("exchange rates synced", "base", s.cfg.Base, "currencies", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync fetches the rates once and stores them, returning how many
+// currencies were stored
+func (s *Syncer) Sync(ctx context.Context) (int, error) {
+	rates, err := s.fetch(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	codes := make([]string, 0, len(rates))
+	for code := range rates {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+	values := make([]float64, len(codes))
+	for i, code := range codes {
+		values[i] = rates[code]
+	}
+
+	if _, err := s.db.ReplaceExchangeRates(ctx, database.ReplaceExchangeRatesParams{
+		Currencies: codes,
+		Base:       s.cfg.Base,
+		Rates:      values,
+	}); err != nil {
+		return 0, err
+	}
+	return len(codes), nil
+}
+
+// fetch reads the feed. Codes that aren't ISO 4217 and rates that aren't
+// positive are dropped; the base is always present at 1.
+func (s *Syncer) fetch(ctx context.Context) (Rates, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.cfg.RatesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("GET %s: status %d: %s", s.cfg.RatesURL, resp.StatusCode, body)
+	}
+
+	var feed struct {
+		Base  string             `json:"base"`
+		Rates map[string]float64 `json:"rates"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("decode rates: %w", err)
+	}
+	if feed.Base != "" && feed.Base != s.cfg.Base {
+		return nil, fmt.Errorf("rates are against %s, want %s", feed.Base, s.cfg.Base)
+	}
+
+	rates := Rates{s.cfg.Base: 1}
+	for code, rate := range feed.Rates {
+		if ValidCode(code) && rate > 0 && code != s.cfg.Base {
+			rates[code] = rate
+		}
+	}
+	if len(rates) == 1 {
+		return nil, fmt.Errorf("no rates in the response from %s", s.cfg.RatesURL)
+	}
+	return rates, nil
+}
@@ -12,6 +12,18 @@ const (
 	EntityRole           EntityType = "Role"
 	EntityPermission     EntityType = "Permission"
 	EntityCustomDomain   EntityType = "CustomDomain"
+	EntityCustomer       EntityType = "Customer"
+	EntityOrder          EntityType = "Order"
+	EntityCollection     EntityType = "Collection"
+	EntityCustomerGroup  EntityType = "CustomerGroup"
+	EntityPriceList      EntityType = "PriceList"
+	EntitySellingPlan    EntityType = "SellingPlan"
+	EntitySubscription   EntityType = "SubscriptionContract"
+	EntitySalesChannel   EntityType = "SalesChannel"
+	EntityProductFeed    EntityType = "ProductFeed"
+	EntityProductReview  EntityType = "ProductReview"
+	EntityReservation    EntityType = "InventoryReservation"
+	EntityInventoryCount EntityType = "InventoryCount"
 )
 
 // ValidEntityTypes maps valid entity types for validation
@@ -24,6 +36,18 @@ var ValidEntityTypes = map[EntityType]bool{
 	EntityRole:           true,
 	EntityPermission:     true,
 	EntityCustomDomain:   true,
+	EntityCustomer:       true,
+	EntityOrder:          true,
+	EntityCollection:     true,
+	EntityCustomerGroup:  true,
+	EntityPriceList:      true,
+	EntitySellingPlan:    true,
+	EntitySubscription:   true,
+	EntitySalesChannel:   true,
+	EntityProductFeed:    true,
+	EntityProductReview:  true,
+	EntityReservation:    true,
+	EntityInventoryCount: true,
 }
 
 // IsValid checks if the entity type is valid
@@ -126,3 +126,63 @@ func PermissionGID(id uint64) GID {
 func CustomDomainGID(id uint64) GID {
 	return New(EntityCustomDomain, id)
 }
+
+// CustomerGID creates a Customer GID
+func CustomerGID(id uint64) GID {
+	return New(EntityCustomer, id)
+}
+
+// OrderGID creates an Order GID
+func OrderGID(id uint64) GID {
+	return New(EntityOrder, id)
+}
+
+// CollectionGID creates a Collection GID
+func CollectionGID(id uint64) GID {
+	return New(EntityCollection, id)
+}
+
+// CustomerGroupGID creates a CustomerGroup GID
+func CustomerGroupGID(id uint64) GID {
+	return New(EntityCustomerGroup, id)
+}
+
+// PriceListGID creates a PriceList GID
+func PriceListGID(id uint64) GID {
+	return New(EntityPriceList, id)
+}
+
+// SellingPlanGID creates a SellingPlan GID
+func SellingPlanGID(id uint64) GID {
+	return New(EntitySellingPlan, id)
+}
+
+// SubscriptionGID creates a SubscriptionContract GID
+func SubscriptionGID(id uint64) GID {
+	return New(EntitySubscription, id)
+}
+
+// SalesChannelGID creates a SalesChannel GID
+func SalesChannelGID(id uint64) GID {
+	return New(EntitySalesChannel, id)
+}
+
+// ProductFeedGID creates a ProductFeed GID
+func ProductFeedGID(id uint64) GID {
+	return New(EntityProductFeed, id)
+}
+
+// ProductReviewGID creates a ProductReview GID
+func ProductReviewGID(id uint64) GID {
+	return New(EntityProductReview, id)
+}
+
+// ReservationGID creates an InventoryReservation GID
+func ReservationGID(id uint64) GID {
+	return New(EntityReservation, id)
+}
+
+// InventoryCountGID creates an InventoryCount GID
+func InventoryCountGID(id uint64) GID {
+	return New(EntityInventoryCount, id)
+}
@@ -145,6 +145,18 @@ func TestGIDHelpers(t *testing.T) {
 		{RoleGID(6), EntityRole},
 		{PermissionGID(7), EntityPermission},
 		{CustomDomainGID(8), EntityCustomDomain},
+		{CustomerGID(9), EntityCustomer},
+		{OrderGID(10), EntityOrder},
+		{CollectionGID(11), EntityCollection},
+		{CustomerGroupGID(12), EntityCustomerGroup},
+		{PriceListGID(13), EntityPriceList},
+		{SellingPlanGID(14), EntitySellingPlan},
+		{SubscriptionGID(15), EntitySubscription},
+		{SalesChannelGID(16), EntitySalesChannel},
+		{ProductFeedGID(17), EntityProductFeed},
+		{ProductReviewGID(18), EntityProductReview},
+		{ReservationGID(19), EntityReservation},
+		{InventoryCountGID(20), EntityInventoryCount},
 	}
 
 	for _, tt := range tests {
@@ -158,7 +170,10 @@ func TestEntityTypeIsValid(t *testing.T) {
 	validTypes := []EntityType{
 		EntityProduct, EntityProductVariant, EntityStore,
 		EntityTenant, EntityUser, EntityRole, EntityPermission,
-		EntityCustomDomain,
+		EntityCustomDomain, EntityCustomer, EntityOrder, EntityCollection,
+		EntityCustomerGroup, EntityPriceList, EntitySellingPlan, EntitySubscription,
+		EntitySalesChannel, EntityProductFeed, EntityProductReview, EntityReservation,
+		EntityInventoryCount,
 	}
 
 	for _, et := range validTypes {
@@ -246,7 +261,10 @@ func TestParseAllEntityTypes(t *testing.T) {
 	entityTypes := []EntityType{
 		EntityProduct, EntityProductVariant, EntityStore,
 		EntityTenant, EntityUser, EntityRole, EntityPermission,
-		EntityCustomDomain,
+		EntityCustomDomain, EntityCustomer, EntityOrder, EntityCollection,
+		EntityCustomerGroup, EntityPriceList, EntitySellingPlan, EntitySubscription,
+		EntitySalesChannel, EntityProductFeed, EntityProductReview, EntityReservation,
+		EntityInventoryCount,
 	}
 
 	for _, et := range entityTypes {
@@ -0,0 +1,123 @@
+// Package giftcard generates, normalizes and hashes gift card codes and
+// works out how much of a card a redemption may draw.
+package giftcard
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// alphabet leaves out characters that are easy to misread (0/O, 1/I/L, U)
+const alphabet = "ABCDEFGHJKMNPQRSTVWXYZ23456789"
+
+const (
+	// CodeLength is the length of generated codes, excluding separators
+	CodeLength = 16
+	// MinCodeLength and MaxCodeLength bound merchant-supplied codes
+	MinCodeLength = 8
+	MaxCodeLength = 32
+	// lastCharacters is how much of a code is kept in the clear for display
+	lastCharacters = 4
+)
+
+var (
+	ErrInvalidCode   = errors.New("invalid gift card code")
+	ErrInvalidAmount = errors.New("invalid gift card amount")
+)
+
+// Status is the lifecycle state of a card
+type Status string
+
+const (
+	StatusActive   Status = "active"
+	StatusDisabled Status = "disabled"
+)
+
+// IsValid returns true if the status is known
+func (s Status) IsValid() bool {
+	return s == StatusActive || s == StatusDisabled
+}
+
+// GenerateCode returns a random code grouped in fours, e.g. ABCD-EFGH-JKMN-PQRS
+func GenerateCode() (string, error) {
+	max := big.NewInt(int64(len(alphabet)))
+	var b strings.Builder
+	for i := 0; i < CodeLength; i++ {
+		if i > 0 && i%4 == 0 {
+			b.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("generate gift card code: %w", err)
+		}
+		b.WriteByte(alphabet[n.Int64()])
+	}
+	return b.String(), nil
+}
+
+// Normalize uppercases a code and strips spaces and dashes so shoppers can
+// type it however it was printed
+func Normalize(code string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(code) {
+		if r == '-' || r == ' ' || r == '\t' {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Validate checks a merchant-supplied code after normalization
+func Validate(code string) error {
+	n := Normalize(code)
+	if len(n) < MinCodeLength || len(n) > MaxCodeLength {
+		return fmt.Errorf("%w: must be %d to %d characters", ErrInvalidCode, MinCodeLength, MaxCodeLength)
+	}
+	for _, r := range n {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return fmt.Errorf("%w: only letters and digits are allowed", ErrInvalidCode)
+		}
+	}
+	return nil
+}
+
+// Hash returns the lookup key stored in place of the code
+func Hash(code string) string {
+	sum := sha256.Sum256([]byte(Normalize(code)))
+	return hex.EncodeToString(sum[:])
+}
+
+// LastCharacters returns the tail of a code that is safe to display
+func LastCharacters(code string) string {
+	n := Normalize(code)
+	if len(n) <= lastCharacters {
+		return n
+	}
+	return n[len(n)-lastCharacters:]
+}
+
+// RedeemAmount works out how much to draw from a card toward an amount due.
+// requested of zero means as much as possible; a request above the balance
+// or the amount due is capped rather than rejected.
+func RedeemAmount(balance, due, requested int64) (int64, error) {
+	if requested < 0 {
+		return 0, fmt.Errorf("%w: amount must not be negative", ErrInvalidAmount)
+	}
+	if due <= 0 {
+		return 0, fmt.Errorf("%w: nothing is left to pay", ErrInvalidAmount)
+	}
+	if balance <= 0 {
+		return 0, fmt.Errorf("%w: the card has no balance", ErrInvalidAmount)
+	}
+	amount := min(balance, due)
+	if requested > 0 {
+		amount = min(amount, requested)
+	}
+	return amount, nil
+}
@@ -0,0 +1,92 @@
+package giftcard
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGenerateCode(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		code, err := GenerateCode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(code) != CodeLength+3 || strings.Count(code, "-") != 3 {
+			t.Fatalf("code %q is not four groups of four", code)
+		}
+		if err := Validate(code); err != nil {
+			t.Fatalf("generated code %q does not validate: %v", code, err)
+		}
+		if seen[code] {
+			t.Fatalf("duplicate code %q", code)
+		}
+		seen[code] = true
+	}
+}
+
+func TestNormalizeAndHash(t *testing.T) {
+	if got := Normalize(" abcd-efgh jkmn-pqrs "); got != "ABCDEFGHJKMNPQRS" {
+		t.Errorf("Normalize() = %q", got)
+	}
+	if Hash("abcd-efgh-jkmn-pqrs") != Hash("ABCDEFGHJKMNPQRS") {
+		t.Error("hash should not depend on formatting")
+	}
+	if Hash("ABCDEFGHJKMNPQRS") == Hash("ABCDEFGHJKMNPQRT") {
+		t.Error("different codes hashed the same")
+	}
+	if got := LastCharacters("abcd-efgh-jkmn-pqrs"); got != "PQRS" {
+		t.Errorf("LastCharacters() = %q", got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		code    string
+		wantErr bool
+	}{
+		{"HOLIDAY2025", false},
+		{"gift-card-01", false},
+		{"SHORT", true},
+		{"HAS_UNDERSCORE", true},
+		{strings.Repeat("A", MaxCodeLength+1), true},
+	}
+	for _, tt := range tests {
+		err := Validate(tt.code)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%q) error = %v, wantErr %v", tt.code, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidCode) {
+			t.Errorf("Validate(%q) error = %v, want ErrInvalidCode", tt.code, err)
+		}
+	}
+}
+
+func TestRedeemAmount(t *testing.T) {
+	tests := []struct {
+		name                    string
+		balance, due, requested int64
+		want                    int64
+		wantErr                 bool
+	}{
+		{name: "balance covers the order", balance: 5000, due: 3000, want: 3000},
+		{name: "partial redemption", balance: 2000, due: 3000, want: 2000},
+		{name: "requested amount", balance: 5000, due: 3000, requested: 1000, want: 1000},
+		{name: "request capped at amount due", balance: 5000, due: 3000, requested: 4000, want: 3000},
+		{name: "empty card", balance: 0, due: 3000, wantErr: true},
+		{name: "nothing due", balance: 5000, due: 0, wantErr: true},
+		{name: "negative request", balance: 5000, due: 3000, requested: -1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RedeemAmount(tt.balance, tt.due, tt.requested)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RedeemAmount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("RedeemAmount() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+// Package health runs the dependency checks behind the readiness probe and
+// reports them one by one, so a failing probe says what is down.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultTimeout bounds each check when the Checker is given none
+const DefaultTimeout = 2 * time.Second
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// CheckFunc checks one dependency. The detail, if any, is reported
+// alongside the status.
+type CheckFunc func(ctx context.Context) (detail any, err error)
+
+// Result is the outcome of one check
+type Result struct {
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	Detail     any    `json:"detail,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// Report is the outcome of every check. Status is up only when every
+// check is.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// Checker runs the registered checks concurrently
+type Checker struct {
+	timeout time.Duration
+	checks  []check
+}
+
+func New(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Checker{timeout: timeout}
+}
+
+// Add registers a check. Checks are added at startup, before Run is called.
+func (c *Checker) Add(name string, fn CheckFunc) {
+	c.checks = append(c.checks, check{name: name, fn: fn})
+}
+
+// Run runs every check, each bounded by the checker's timeout
+func (c *Checker) Run(ctx context.Context) Report {
+	report := Report{Status: StatusUp, Checks: make(map[string]Result, len(c.checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, chk := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := c.run(ctx, chk.fn)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[chk.name] = result
+			if result.Status != StatusUp {
+				report.Status = StatusDown
+			}
+		}()
+	}
+	wg.Wait()
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, fn CheckFunc) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	type outcome struct {
+		detail any
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		detail, err := fn(ctx)
+		done <- outcome{detail, err}
+	}()
+
+	var out outcome
+	select {
+	case out = <-done:
+	case <-ctx.Done():
+		out.err = fmt.Errorf("timed out after %s", c.timeout)
+	}
+
+	result := Result{Status: StatusUp, Detail: out.detail, DurationMs: time.Since(start).Milliseconds()}
+	if out.err != nil {
+		result.Status = StatusDown
+		result.Error = out.err.Error()
+	}
+	return result
+}
+
+// Database pings the database
+func Database(db *pgxpool.Pool) CheckFunc {
+	return func(ctx context.Context) (any, error) {
+		return nil, db.Ping(ctx)
+	}
+}
+
+// MigrationStatus compares the schema version recorded by goose with the
+// newest migration the binary was built with
+type MigrationStatus struct {
+	Current  int64 `json:"current"`
+	Expected int64 `json:"expected"`
+}
+
+// Migrations fails until every migration up to expected has been applied
+func Migrations(db *pgxpool.Pool, expected int64) CheckFunc {
+	return func(ctx context.Context) (any, error) {
+		status := MigrationStatus{Expected: expected}
+		err := db.QueryRow(ctx,
+			`SELECT COALESCE(MAX(version_id), 0) FROM goose_db_version WHERE is_applied`,
+		).Scan(&status.Current)
+		if err != nil {
+			return nil, err
+		}
+		if status.Current < expected {
+			return status, fmt.Errorf("schema is at version %d, want %d", status.Current, expected)
+		}
+		return status, nil
+	}
+}
+
+// LatestMigration returns the highest version among goose migration files,
+// which are named <version>_<name>.sql
+func LatestMigration(fsys fs.FS, dir string) (int64, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return 0, err
+	}
+	var latest int64
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || path.Ext(name) != ".sql" {
+			continue
+		}
+		prefix, _, ok := strings.Cut(name, "_")
+		if !ok {
+			continue
+		}
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			continue
+		}
+		latest = max(latest, version)
+	}
+	if latest == 0 {
+		return 0, errors.New("no migrations found")
+	}
+	return latest, nil
+}
+
+// JobQueue checks the job table can be read and reports how many jobs are
+// due
+func JobQueue(count func(ctx context.Context) (int64, error)) CheckFunc {
+	return func(ctx context.Context) (any, error) {
+		due, err := count(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]int64{"due": due}, nil
+	}
+}
+
+// Redis sends PING to the server at a redis:// or rediss:// URL,
+// authenticating first when the URL carries a password
+func Redis(rawURL string) CheckFunc {
+	return func(ctx context.Context) (any, error) {
+		opts, err := redis.ParseURL(rawURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis URL: %w", err)
+		}
+		// One connection for the one command, bounded by the check's
+		// deadline rather than the client's
+		opts.PoolSize = 1
+		opts.MaxRetries = -1
+		opts.ContextTimeoutEnabled = true
+		opts.Protocol = 2
+		opts.DisableIdentity = true
+		client := redis.NewClient(opts)
+		defer client.Close()
+		return nil, client.Ping(ctx).Err()
+	}
+}
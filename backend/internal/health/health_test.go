@@ -0,0 +1,219 @@
+package health
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestCheckerRun(t *testing.T) {
+	up := func(context.Context) (any, error) { return "fine", nil }
+	down := func(context.Context) (any, error) { return nil, errors.New("connection refused") }
+	slow := func(ctx context.Context) (any, error) {
+		<-ctx.Done()
+		time.Sleep(time.Second)
+		return nil, nil
+	}
+
+	tests := []struct {
+		name       string
+		checks     map[string]CheckFunc
+		wantStatus string
+		wantDown   []string
+	}{
+		{name: "no checks", checks: map[string]CheckFunc{}, wantStatus: StatusUp},
+		{name: "all up", checks: map[string]CheckFunc{"database": up, "jobs": up}, wantStatus: StatusUp},
+		{name: "one down", checks: map[string]CheckFunc{"database": up, "redis": down}, wantStatus: StatusDown, wantDown: []string{"redis"}},
+		{name: "timeout", checks: map[string]CheckFunc{"database": slow}, wantStatus: StatusDown, wantDown: []string{"database"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(20 * time.Millisecond)
+			for name, fn := range tt.checks {
+				c.Add(name, fn)
+			}
+
+			start := time.Now()
+			report := c.Run(context.Background())
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("Run() took %v, want it bounded by the timeout", elapsed)
+			}
+			if report.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", report.Status, tt.wantStatus)
+			}
+			if len(report.Checks) != len(tt.checks) {
+				t.Errorf("got %d results, want %d", len(report.Checks), len(tt.checks))
+			}
+			for name, result := range report.Checks {
+				wantDown := strings.Contains(strings.Join(tt.wantDown, ","), name)
+				if (result.Status == StatusDown) != wantDown {
+					t.Errorf("%s = %+v, want down %v", name, result, wantDown)
+				}
+				if wantDown && result.Error == "" {
+					t.Errorf("%s is down without an error", name)
+				}
+			}
+		})
+	}
+}
+
+func TestLatestMigration(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "highest version",
+			files: fstest.MapFS{
+				"schema/001_users.sql":          {},
+				"schema/041_acme_cache.sql":     {},
+				"schema/009_refresh_tokens.sql": {},
+			},
+			want: 41,
+		},
+		{
+			name: "ignores other files",
+			files: fstest.MapFS{
+				"schema/002_stores.sql": {},
+				"schema/README.md":      {},
+				"schema/notes.sql":      {},
+				"schema/999_draft.txt":  {},
+			},
+			want: 2,
+		},
+		{name: "empty", files: fstest.MapFS{"schema/README.md": {}}, wantErr: true},
+		{name: "missing dir", files: fstest.MapFS{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LatestMigration(tt.files, "schema")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LatestMigration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("LatestMigration() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJobQueue(t *testing.T) {
+	detail, err := JobQueue(func(context.Context) (int64, error) { return 3, nil })(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := detail.(map[string]int64)["due"]; got != 3 {
+		t.Errorf("due = %d, want 3", got)
+	}
+
+	if _, err := JobQueue(func(context.Context) (int64, error) { return 0, errors.New("relation does not exist") })(context.Background()); err == nil {
+		t.Error("JobQueue() hid the query error")
+	}
+}
+
+// fakeRedis answers AUTH with the given password and PING with PONG, and
+// any other command as one it doesn't know, the way Redis before 6 answers
+// HELLO
+func fakeRedis(t *testing.T, password string) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				rd := bufio.NewReader(conn)
+				authed := password == ""
+				for {
+					args, err := readCommand(rd)
+					if err != nil {
+						return
+					}
+					args[0] = strings.ToUpper(args[0])
+					switch {
+					case args[0] == "AUTH" && args[len(args)-1] == password:
+						authed = true
+						conn.Write([]byte("+OK\r\n"))
+					case args[0] == "AUTH":
+						conn.Write([]byte("-WRONGPASS invalid password\r\n"))
+					case !authed:
+						conn.Write([]byte("-NOAUTH Authentication required.\r\n"))
+					case args[0] == "PING":
+						conn.Write([]byte("+PONG\r\n"))
+					default:
+						conn.Write([]byte("-ERR unknown command '" + args[0] + "'\r\n"))
+					}
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func readCommand(rd *bufio.Reader) ([]string, error) {
+	header, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n := 0
+	for _, c := range strings.TrimSpace(header[1:]) {
+		n = n*10 + int(c-'0')
+	}
+	args := make([]string, 0, n)
+	for range n {
+		if _, err := rd.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := rd.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, strings.TrimSpace(arg))
+	}
+	return args, nil
+}
+
+func TestRedis(t *testing.T) {
+	open := fakeRedis(t, "")
+	secured := fakeRedis(t, "hunter2")
+
+	tests := []struct {
+		name    string
+		url     string
+		wantErr bool
+	}{
+		{name: "ping", url: "redis://" + open},
+		{name: "auth", url: "redis://:hunter2@" + secured},
+		{name: "auth with username", url: "redis://default:hunter2@" + secured},
+		{name: "wrong password", url: "redis://:nope@" + secured, wantErr: true},
+		{name: "missing password", url: "redis://" + secured, wantErr: true},
+		{name: "unsupported scheme", url: "http://" + open, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			if _, err := Redis(tt.url)(ctx); (err != nil) != tt.wantErr {
+				t.Errorf("Redis(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+		})
+	}
+}
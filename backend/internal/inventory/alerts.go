@@ -0,0 +1,205 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Alert kinds, matching inventory_alerts.kind
+const (
+	AlertLowStock  = "low_stock"
+	AlertRestocked = "restocked"
+)
+
+const (
+	// DefaultLowStockCheckInterval is how often stock levels are checked
+	// when LOW_STOCK_CHECK_INTERVAL is unset
+	DefaultLowStockCheckInterval = 5 * time.Minute
+	// MaxReorderPoint bounds reorder points and reorder quantities
+	MaxReorderPoint = 1000000
+)
+
+// IsLowStock returns true when onHand is at or below reorderPoint. A level
+// without a reorder point is never low.
+func IsLowStock(onHand int32, reorderPoint sql.NullInt32) bool {
+	return reorderPoint.Valid && onHand <= reorderPoint.Int32
+}
+
+// SuggestedReorder is how many to order for a low level: its reorder
+// quantity when set, otherwise enough to bring it back above the reorder
+// point
+func SuggestedReorder(onHand, reorderPoint int32, reorderQuantity sql.NullInt32) int32 {
+	if reorderQuantity.Valid {
+		return reorderQuantity.Int32
+	}
+	return max(reorderPoint-onHand+1, 1)
+}
+
+// LowStock is a variant that went low at a location, with what staff need
+// to reorder it
+type LowStock struct {
+	Alert        database.InventoryAlert
+	ProductName  string
+	VariantTitle string
+	SKU          string
+	LocationName string
+	// Reorder is the suggested quantity to order
+	Reorder int32
+}
+
+// NotifyFunc tells a store's staff about variants that went low
+type NotifyFunc func(ctx context.Context, tenantID, storeID uuid.UUID, items []LowStock) error
+
+// MonitorConfig configures a Monitor
+type MonitorConfig struct {
+	Interval time.Duration
+	// BatchSize bounds the crossings handled each interval
+	BatchSize int
+	// Notify is called once per store with the variants that went low
+	// during a check. It is optional.
+	Notify NotifyFunc
+	Logger *slog.Logger
+}
+
+// Monitor watches stock levels against their reorder points. Each time a
+// level goes to or below its point it records a low_stock alert and
+// notifies the store, once, until the level goes back above it and a
+// restocked alert is recorded.
+type Monitor struct {
+	pool *pgxpool.Pool
+	db   *database.Queries
+	cfg  MonitorConfig
+}
+
+// NewMonitor creates a monitor over pool
+func NewMonitor(pool *pgxpool.Pool, cfg MonitorConfig) *Monitor {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultLowStockCheckInterval
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 500
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &Monitor{pool: pool, db: database.New(pool), cfg: cfg}
+}
+
+// Run checks levels once an interval until ctx is cancelled
+func (m *Monitor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := m.Check(ctx)
+		if err != nil && ctx.Err() == nil {
+			m.cfg.Logger.Error("low stock check failed", "error", err)
+		} else if n > 0 {
+			m.cfg.Logger.Info("low stock alerts recorded", "alerts", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check records an alert for up to a batch of levels that crossed their
+// reorder point, notifies the stores with new low stock, and returns how
+// many alerts it recorded
+func (m *Monitor) Check(ctx context.Context) (int, error) {
+	type storeKey struct{ tenantID, storeID uuid.UUID }
+	low := map[storeKey][]LowStock{}
+
+	var n int
+	var err error
+	for n < m.cfg.BatchSize && ctx.Err() == nil {
+		var item *LowStock
+		var found bool
+		item, found, err = m.checkNext(ctx)
+		if err != nil || !found {
+			break
+		}
+		n++
+		if item != nil {
+			key := storeKey{item.Alert.TenantID, item.Alert.StoreID}
+			low[key] = append(low[key], *item)
+		}
+	}
+
+	if m.cfg.Notify != nil {
+		for key, items := range low {
+			if nerr := m.cfg.Notify(ctx, key.tenantID, key.storeID, items); nerr != nil {
+				m.cfg.Logger.Error("low stock notification failed", "store_id", key.storeID, "error", nerr)
+			}
+		}
+	}
+	return n, err
+}
+
+// checkNext records the alert for the next level that crossed its reorder
+// point, reporting false when none did. It returns the low stock when the
+// level went low.
+func (m *Monitor) checkNext(ctx context.Context) (*LowStock, bool, error) {
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback(ctx)
+	q := m.db.WithTx(tx)
+
+	level, err := q.ClaimInventoryLevelCrossing(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	kind, since := AlertRestocked, sql.NullTime{}
+	if IsLowStock(level.OnHand, level.ReorderPoint) {
+		kind, since = AlertLowStock, sql.NullTime{Time: time.Now(), Valid: true}
+	}
+	if err := q.SetInventoryLevelLowStockSince(ctx, database.SetInventoryLevelLowStockSinceParams{
+		LowStockSince: since,
+		ID:            level.ID,
+	}); err != nil {
+		return nil, false, err
+	}
+	alert, err := q.CreateInventoryAlert(ctx, database.CreateInventoryAlertParams{
+		TenantID:     level.TenantID,
+		StoreID:      level.StoreID,
+		VariantID:    level.VariantID,
+		LocationID:   level.LocationID,
+		Kind:         kind,
+		OnHand:       level.OnHand,
+		ReorderPoint: level.ReorderPoint,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, false, err
+	}
+
+	if kind != AlertLowStock {
+		return nil, true, nil
+	}
+	return &LowStock{
+		Alert:        alert,
+		ProductName:  level.ProductName,
+		VariantTitle: level.VariantTitle,
+		SKU:          level.Sku.String,
+		LocationName: level.LocationName,
+		Reorder:      SuggestedReorder(level.OnHand, level.ReorderPoint.Int32, level.ReorderQuantity),
+	}, true, nil
+}
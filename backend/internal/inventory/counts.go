@@ -0,0 +1,226 @@
+package inventory
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/google/uuid"
+)
+
+// Count statuses, matching inventory_counts.status. Only open counts take
+// new lines.
+const (
+	CountOpen      = "open"
+	CountCompleted = "completed"
+	CountCancelled = "cancelled"
+)
+
+const (
+	// MaxCountNameLength bounds a count's name
+	MaxCountNameLength = 100
+	// MaxCountEntries bounds the variants recorded in one request or file
+	MaxCountEntries = 5000
+	// MaxCountFileBytes bounds an uploaded count file
+	MaxCountFileBytes = 2 << 20
+)
+
+// CountColumns are the header names a count file may use. It needs a
+// counted column and one of the others to say which variant was counted.
+var CountColumns = []string{"variant_id", "sku", "barcode", "counted"}
+
+var (
+	// ErrInvalidCountFile wraps every problem that makes a count file
+	// unusable
+	ErrInvalidCountFile = errors.New("invalid count file")
+	// ErrCountEntry wraps entries that do not name exactly one variant
+	ErrCountEntry = errors.New("invalid count entry")
+	// ErrCountNotOpen is returned for changes to a completed or cancelled
+	// count
+	ErrCountNotOpen = errors.New("inventory count is not open")
+)
+
+// CountEntry is how many of a variant were counted. The variant is given
+// by VariantID, or by Code, a SKU or barcode, when it came from a file.
+type CountEntry struct {
+	VariantID uuid.UUID `json:"variant_id"`
+	Code      string    `json:"code,omitempty"`
+	Counted   int32     `json:"counted"`
+}
+
+// ParseCountFile reads a CSV of counted quantities, such as one exported
+// from a scanner. The first line names the columns, in any order:
+//
+//	sku,counted
+//
+// Rows are returned in file order. Recording them adds up rows for the
+// same variant, so a shelf counted in parts can be uploaded as scanned.
+func ParseCountFile(r io.Reader) ([]CountEntry, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: the file is empty", ErrInvalidCountFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCountFile, err)
+	}
+	columns := make([]string, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch {
+		case !slices.Contains(CountColumns, name):
+			return nil, fmt.Errorf("%w: unknown column %q; columns are %s", ErrInvalidCountFile, name, strings.Join(CountColumns, ", "))
+		case slices.Contains(columns[:i], name):
+			return nil, fmt.Errorf("%w: column %q appears more than once", ErrInvalidCountFile, name)
+		}
+		columns[i] = name
+	}
+	if !slices.Contains(columns, "counted") {
+		return nil, fmt.Errorf("%w: a counted column is required", ErrInvalidCountFile)
+	}
+	if !slices.ContainsFunc(columns, func(c string) bool { return c != "counted" }) {
+		return nil, fmt.Errorf("%w: a variant_id, sku or barcode column is required", ErrInvalidCountFile)
+	}
+
+	var entries []CountEntry
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCountFile, err)
+		}
+		line, _ := cr.FieldPos(0)
+		if len(record) != len(columns) {
+			return nil, fmt.Errorf("%w: line %d has %d cells, but the header has %d columns", ErrInvalidCountFile, line, len(record), len(columns))
+		}
+
+		var entry CountEntry
+		var counted bool
+		for i, column := range columns {
+			cell := strings.TrimSpace(record[i])
+			if cell == "" {
+				continue
+			}
+			switch column {
+			case "variant_id":
+				id, err := uuid.Parse(cell)
+				if err != nil {
+					return nil, fmt.Errorf("%w: line %d: variant_id must be a UUID", ErrInvalidCountFile, line)
+				}
+				entry.VariantID = id
+			case "sku", "barcode":
+				if entry.Code == "" {
+					entry.Code = cell
+				}
+			case "counted":
+				n, err := strconv.ParseInt(cell, 10, 32)
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("%w: line %d: counted must be a whole number of at least 0", ErrInvalidCountFile, line)
+				}
+				entry.Counted, counted = int32(n), true
+			}
+		}
+		if entry.VariantID == uuid.Nil && entry.Code == "" && !counted {
+			continue
+		}
+		if entry.VariantID == uuid.Nil && entry.Code == "" {
+			return nil, fmt.Errorf("%w: line %d does not say which variant was counted", ErrInvalidCountFile, line)
+		}
+		if !counted {
+			return nil, fmt.Errorf("%w: line %d: counted is required", ErrInvalidCountFile, line)
+		}
+		if len(entries) == MaxCountEntries {
+			return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidCountFile, MaxCountEntries)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: the file has no rows", ErrInvalidCountFile)
+	}
+	return entries, nil
+}
+
+// MatchCountEntries resolves entries against the store's variants that
+// have one of their IDs or codes, and adds up the counts of each variant.
+// A code matches a barcode exactly or a SKU in any case, barcodes first.
+func MatchCountEntries(entries []CountEntry, variants []database.GetCountableVariantsRow) (map[uuid.UUID]int32, error) {
+	ids := make(map[uuid.UUID]bool, len(variants))
+	barcodes := map[string][]uuid.UUID{}
+	skus := map[string][]uuid.UUID{}
+	for _, v := range variants {
+		ids[v.ID] = true
+		if v.Barcode.Valid {
+			barcodes[v.Barcode.String] = append(barcodes[v.Barcode.String], v.ID)
+		}
+		if v.Sku.Valid {
+			sku := strings.ToLower(v.Sku.String)
+			skus[sku] = append(skus[sku], v.ID)
+		}
+	}
+
+	counted := make(map[uuid.UUID]int32, len(entries))
+	for _, e := range entries {
+		id := e.VariantID
+		if id == uuid.Nil {
+			matches := barcodes[e.Code]
+			if len(matches) == 0 {
+				matches = skus[strings.ToLower(e.Code)]
+			}
+			switch len(matches) {
+			case 0:
+				return nil, fmt.Errorf("%w: no variant has the code %q", ErrCountEntry, e.Code)
+			case 1:
+				id = matches[0]
+			default:
+				return nil, fmt.Errorf("%w: more than one variant has the code %q", ErrCountEntry, e.Code)
+			}
+		} else if !ids[id] {
+			return nil, fmt.Errorf("%w: variant %s not found", ErrCountEntry, id)
+		}
+
+		total := int64(counted[id]) + int64(e.Counted)
+		if total > math.MaxInt32 {
+			return nil, fmt.Errorf("%w: the count of variant %s is too large", ErrCountEntry, id)
+		}
+		counted[id] = int32(total)
+	}
+	return counted, nil
+}
+
+// CountSummary totals the discrepancies of a count
+type CountSummary struct {
+	Lines int `json:"lines"`
+	// Discrepancies are the lines whose count differs from what was
+	// expected
+	Discrepancies int `json:"discrepancies"`
+	// Surplus and Shortage are the units found over and under what was
+	// expected
+	Surplus  int64 `json:"surplus"`
+	Shortage int64 `json:"shortage"`
+}
+
+// Add counts a line of expected and counted units into the summary
+func (s *CountSummary) Add(expected, counted int32) {
+	s.Lines++
+	switch d := int64(counted) - int64(expected); {
+	case d > 0:
+		s.Discrepancies++
+		s.Surplus += d
+	case d < 0:
+		s.Discrepancies++
+		s.Shortage -= d
+	}
+}
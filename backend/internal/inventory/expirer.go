@@ -0,0 +1,136 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Release ends an active reservation with status, released or expired, and
+// gives back the stock it held. It fails with ErrReservationNotActive once
+// the reservation was committed, released or expired. q should be in a
+// transaction so the stock comes back with the status change.
+func Release(ctx context.Context, q *database.Queries, reservationID uuid.UUID, status string) (database.InventoryReservation, error) {
+	reservation, err := q.CloseInventoryReservation(ctx, database.CloseInventoryReservationParams{
+		Status: status,
+		ID:     reservationID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.InventoryReservation{}, ErrReservationNotActive
+	}
+	if err != nil {
+		return database.InventoryReservation{}, err
+	}
+
+	items, err := q.GetInventoryReservationItems(ctx, reservation.ID)
+	if err != nil {
+		return database.InventoryReservation{}, err
+	}
+	for _, item := range items {
+		if item.Held == 0 {
+			continue
+		}
+		if err := q.UnreserveInventoryItem(ctx, database.UnreserveInventoryItemParams{
+			Held: item.Held,
+			ID:   item.InventoryItemID,
+		}); err != nil {
+			return database.InventoryReservation{}, err
+		}
+	}
+	return reservation, nil
+}
+
+// ExpirerConfig configures an Expirer
+type ExpirerConfig struct {
+	Interval time.Duration
+	// BatchSize bounds the reservations expired each interval
+	BatchSize int
+	Logger    *slog.Logger
+}
+
+// Expirer gives back the stock of reservations left past their expiry,
+// such as by shoppers who abandoned checkout. Each reservation is locked
+// while it is expired, so running several at once is harmless.
+type Expirer struct {
+	pool *pgxpool.Pool
+	db   *database.Queries
+	cfg  ExpirerConfig
+}
+
+// NewExpirer creates an expirer over pool
+func NewExpirer(pool *pgxpool.Pool, cfg ExpirerConfig) *Expirer {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 100
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &Expirer{pool: pool, db: database.New(pool), cfg: cfg}
+}
+
+// Run expires due reservations once an interval until ctx is cancelled
+func (e *Expirer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(e.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := e.ExpireDue(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			e.cfg.Logger.Error("inventory reservation expiry failed", "error", err)
+		} else if n > 0 {
+			e.cfg.Logger.Info("inventory reservations expired", "reservations", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExpireDue expires up to a batch of reservations past their expiry at now
+// and returns how many it expired
+func (e *Expirer) ExpireDue(ctx context.Context, now time.Time) (int, error) {
+	var n int
+	for n < e.cfg.BatchSize && ctx.Err() == nil {
+		expired, err := e.expireNext(ctx, now)
+		if err != nil || !expired {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// expireNext expires the next due reservation, reporting false when none
+// is due
+func (e *Expirer) expireNext(ctx context.Context, now time.Time) (bool, error) {
+	tx, err := e.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+	q := e.db.WithTx(tx)
+
+	id, err := q.ClaimExpiredInventoryReservation(ctx, now)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if _, err := Release(ctx, q, id, ReservationExpired); err != nil {
+		return false, err
+	}
+	return true, tx.Commit(ctx)
+}
@@ -0,0 +1,79 @@
+package inventory
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Reason explains why stock changed; it is stored on every movement
+type Reason string
+
+const (
+	ReasonReceived   Reason = "received"
+	ReasonSold       Reason = "sold"
+	ReasonDamaged    Reason = "damaged"
+	ReasonCorrection Reason = "correction"
+	// ReasonTransfer is recorded on both legs of a transfer between locations
+	ReasonTransfer Reason = "transfer"
+	// ReasonReturned is recorded when refunded items go back into stock
+	ReasonReturned Reason = "returned"
+	// ReasonCount is recorded when a completed count corrects the stock
+	ReasonCount Reason = "count"
+)
+
+// IsValid returns true if the reason is one of the known reason codes
+func (r Reason) IsValid() bool {
+	switch r {
+	case ReasonReceived, ReasonSold, ReasonDamaged, ReasonCorrection, ReasonTransfer, ReasonReturned, ReasonCount:
+		return true
+	}
+	return false
+}
+
+// ErrInsufficientStock is returned when an adjustment would take on-hand stock below zero
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+// ValidateAdjustment checks that the delta's sign fits the reason:
+// received and returned add stock, sold and damaged remove it, a correction
+// may go either way.
+func ValidateAdjustment(reason Reason, delta int32) error {
+	if !reason.IsValid() {
+		return fmt.Errorf("unknown reason %q", reason)
+	}
+	if reason == ReasonTransfer {
+		return errors.New("transfers must be made between two locations")
+	}
+	if reason == ReasonCount {
+		return errors.New("counts are applied by completing an inventory count")
+	}
+	if delta == 0 {
+		return errors.New("delta must not be zero")
+	}
+	switch reason {
+	case ReasonReceived, ReasonReturned:
+		if delta < 0 {
+			return fmt.Errorf("%s stock must have a positive delta", reason)
+		}
+	case ReasonSold, ReasonDamaged:
+		if delta > 0 {
+			return fmt.Errorf("%s stock must have a negative delta", reason)
+		}
+	}
+	return nil
+}
+
+// ValidateTransfer checks a stock transfer between two locations
+func ValidateTransfer(from, to uuid.UUID, quantity int32) error {
+	if from == uuid.Nil || to == uuid.Nil {
+		return errors.New("both from_location_id and to_location_id are required")
+	}
+	if from == to {
+		return errors.New("cannot transfer stock to the same location")
+	}
+	if quantity <= 0 {
+		return errors.New("quantity must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,321 @@
+package inventory
+
+import (
+	"database/sql"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/google/uuid"
+)
+
+func TestValidateAdjustment(t *testing.T) {
+	tests := []struct {
+		name    string
+		reason  Reason
+		delta   int32
+		wantErr bool
+	}{
+		{"received positive", ReasonReceived, 10, false},
+		{"received negative", ReasonReceived, -1, true},
+		{"returned positive", ReasonReturned, 2, false},
+		{"returned negative", ReasonReturned, -2, true},
+		{"sold negative", ReasonSold, -2, false},
+		{"sold positive", ReasonSold, 2, true},
+		{"damaged negative", ReasonDamaged, -1, false},
+		{"damaged positive", ReasonDamaged, 1, true},
+		{"correction up", ReasonCorrection, 5, false},
+		{"correction down", ReasonCorrection, -5, false},
+		{"zero delta", ReasonCorrection, 0, true},
+		{"transfer not adjustable", ReasonTransfer, 1, true},
+		{"count not adjustable", ReasonCount, 1, true},
+		{"unknown reason", Reason("stolen"), -1, true},
+		{"empty reason", Reason(""), 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAdjustment(tt.reason, tt.delta)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateAdjustment(%q, %d) error = %v, wantErr %v", tt.reason, tt.delta, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTransfer(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name     string
+		from, to uuid.UUID
+		quantity int32
+		wantErr  bool
+	}{
+		{"valid", a, b, 3, false},
+		{"same location", a, a, 3, true},
+		{"missing from", uuid.Nil, b, 3, true},
+		{"missing to", a, uuid.Nil, 3, true},
+		{"zero quantity", a, b, 0, true},
+		{"negative quantity", a, b, -2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTransfer(tt.from, tt.to, tt.quantity)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTransfer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOversellPolicyIsValid(t *testing.T) {
+	for _, p := range OversellPolicies {
+		if !OversellPolicy(p).IsValid() {
+			t.Errorf("IsValid(%q) = false", p)
+		}
+	}
+	if OversellPolicy("continue").IsValid() {
+		t.Error("IsValid() accepted an unknown policy")
+	}
+}
+
+func TestMergeLines(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+
+	got, err := MergeLines([]Line{{VariantID: a, Quantity: 2}, {VariantID: b, Quantity: 1}, {VariantID: a, Quantity: 3}})
+	if err != nil {
+		t.Fatalf("MergeLines() error = %v", err)
+	}
+	want := []Line{{VariantID: a, Quantity: 5}, {VariantID: b, Quantity: 1}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("MergeLines() = %v, want %v", got, want)
+	}
+
+	tooMany := make([]Line, MaxReservationItems+1)
+	for i := range tooMany {
+		tooMany[i] = Line{VariantID: uuid.New(), Quantity: 1}
+	}
+	bad := map[string][]Line{
+		"empty":            nil,
+		"too many":         tooMany,
+		"missing variant":  {{Quantity: 1}},
+		"zero quantity":    {{VariantID: a}},
+		"merged too large": {{VariantID: a, Quantity: MaxReservationQuantity}, {VariantID: a, Quantity: 1}},
+	}
+	for name, lines := range bad {
+		if _, err := MergeLines(lines); err == nil {
+			t.Errorf("MergeLines(%s) error = nil", name)
+		}
+	}
+}
+
+func TestHold(t *testing.T) {
+	tests := []struct {
+		name            string
+		policy          OversellPolicy
+		available, qty  int32
+		wantHeld, wantB int32
+		wantErr         bool
+	}{
+		{name: "in stock", policy: OversellDeny, available: 5, qty: 3, wantHeld: 3},
+		{name: "exactly in stock", policy: OversellDeny, available: 3, qty: 3, wantHeld: 3},
+		{name: "deny short", policy: OversellDeny, available: 2, qty: 3, wantErr: true},
+		{name: "allow short", policy: OversellAllow, available: 2, qty: 5, wantHeld: 2},
+		{name: "backorder short", policy: OversellBackorder, available: 2, qty: 5, wantHeld: 2, wantB: 3},
+		{name: "backorder over-reserved", policy: OversellBackorder, available: -1, qty: 2, wantB: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			held, backordered, err := Hold(tt.policy, tt.available, tt.qty)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Hold() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if held != tt.wantHeld || backordered != tt.wantB {
+				t.Errorf("Hold() = %d, %d, want %d, %d", held, backordered, tt.wantHeld, tt.wantB)
+			}
+		})
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	got, err := Allocate([]int32{2, 0, 5}, 4)
+	if err != nil {
+		t.Fatalf("Allocate() error = %v", err)
+	}
+	if want := []int32{2, 0, 2}; len(got) != 3 || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("Allocate() = %v, want %v", got, want)
+	}
+	if _, err := Allocate([]int32{1, 1}, 3); !errors.Is(err, ErrInsufficientStock) {
+		t.Errorf("Allocate() error = %v, want ErrInsufficientStock", err)
+	}
+}
+
+func TestIsLowStock(t *testing.T) {
+	tests := []struct {
+		name         string
+		onHand       int32
+		reorderPoint sql.NullInt32
+		want         bool
+	}{
+		{"no reorder point", 0, sql.NullInt32{}, false},
+		{"above", 6, sql.NullInt32{Int32: 5, Valid: true}, false},
+		{"at", 5, sql.NullInt32{Int32: 5, Valid: true}, true},
+		{"below", 2, sql.NullInt32{Int32: 5, Valid: true}, true},
+		{"zero point sold out", 0, sql.NullInt32{Int32: 0, Valid: true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsLowStock(tt.onHand, tt.reorderPoint); got != tt.want {
+				t.Errorf("IsLowStock(%d, %v) = %v, want %v", tt.onHand, tt.reorderPoint, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestedReorder(t *testing.T) {
+	tests := []struct {
+		name            string
+		onHand          int32
+		reorderPoint    int32
+		reorderQuantity sql.NullInt32
+		want            int32
+	}{
+		{"reorder quantity", 2, 5, sql.NullInt32{Int32: 24, Valid: true}, 24},
+		{"back above point", 2, 5, sql.NullInt32{}, 4},
+		{"at zero point", 0, 0, sql.NullInt32{}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SuggestedReorder(tt.onHand, tt.reorderPoint, tt.reorderQuantity); got != tt.want {
+				t.Errorf("SuggestedReorder() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCountFile(t *testing.T) {
+	variantID := uuid.MustParse("7b0e4cda-2f4e-4c1b-9a53-1d8c2f1f5a10")
+	tests := []struct {
+		name    string
+		file    string
+		want    []CountEntry
+		wantErr bool
+	}{
+		{
+			name: "sku and counted",
+			file: "\ufeffSKU,Counted\nSH-M,4\n\n sh-l , 0\n",
+			want: []CountEntry{{Code: "SH-M", Counted: 4}, {Code: "sh-l", Counted: 0}},
+		},
+		{
+			name: "variant id",
+			file: "counted,variant_id\n3," + variantID.String() + "\n",
+			want: []CountEntry{{VariantID: variantID, Counted: 3}},
+		},
+		{
+			name: "barcode when sku is blank",
+			file: "sku,barcode,counted\n,0123456789012,2\n",
+			want: []CountEntry{{Code: "0123456789012", Counted: 2}},
+		},
+		{name: "empty", file: "", wantErr: true},
+		{name: "no rows", file: "sku,counted\n", wantErr: true},
+		{name: "unknown column", file: "sku,qty\nA,1\n", wantErr: true},
+		{name: "no counted column", file: "sku\nA\n", wantErr: true},
+		{name: "no variant column", file: "counted\n1\n", wantErr: true},
+		{name: "negative count", file: "sku,counted\nA,-1\n", wantErr: true},
+		{name: "missing count", file: "sku,counted\nA,\n", wantErr: true},
+		{name: "bad variant id", file: "variant_id,counted\nabc,1\n", wantErr: true},
+		{name: "short row", file: "sku,counted\nA\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCountFile(strings.NewReader(tt.file))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCountFile) {
+					t.Fatalf("ParseCountFile() error = %v, want ErrInvalidCountFile", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCountFile() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ParseCountFile() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountSummary(t *testing.T) {
+	var s CountSummary
+	s.Add(5, 5)
+	s.Add(5, 7)
+	s.Add(10, 4)
+	want := CountSummary{Lines: 3, Discrepancies: 2, Surplus: 2, Shortage: 6}
+	if s != want {
+		t.Errorf("CountSummary = %+v, want %+v", s, want)
+	}
+}
+
+func TestMatchCountEntries(t *testing.T) {
+	shirt := uuid.MustParse("5d9f1c1e-8a4b-4c3e-9b1a-2f6a7c8d9e01")
+	mug := uuid.MustParse("5d9f1c1e-8a4b-4c3e-9b1a-2f6a7c8d9e02")
+	hat := uuid.MustParse("5d9f1c1e-8a4b-4c3e-9b1a-2f6a7c8d9e03")
+	variants := []database.GetCountableVariantsRow{
+		{ID: shirt, Sku: sql.NullString{String: "SH-M", Valid: true}, Barcode: sql.NullString{String: "111", Valid: true}},
+		{ID: mug, Sku: sql.NullString{String: "111", Valid: true}},
+		{ID: hat, Sku: sql.NullString{String: "CAP", Valid: true}, Barcode: sql.NullString{String: "222", Valid: true}},
+		{ID: uuid.New(), Barcode: sql.NullString{String: "222", Valid: true}},
+	}
+	tests := []struct {
+		name    string
+		entries []CountEntry
+		want    map[uuid.UUID]int32
+		wantErr bool
+	}{
+		{
+			name:    "by id and sku in any case",
+			entries: []CountEntry{{VariantID: mug, Counted: 2}, {Code: "sh-m", Counted: 3}},
+			want:    map[uuid.UUID]int32{mug: 2, shirt: 3},
+		},
+		{
+			name:    "barcode wins over sku",
+			entries: []CountEntry{{Code: "111", Counted: 1}},
+			want:    map[uuid.UUID]int32{shirt: 1},
+		},
+		{
+			name:    "rows for a variant are added up",
+			entries: []CountEntry{{Code: "SH-M", Counted: 3}, {VariantID: shirt, Counted: 4}},
+			want:    map[uuid.UUID]int32{shirt: 7},
+		},
+		{name: "unknown code", entries: []CountEntry{{Code: "nope", Counted: 1}}, wantErr: true},
+		{name: "unknown id", entries: []CountEntry{{VariantID: uuid.New(), Counted: 1}}, wantErr: true},
+		{name: "ambiguous barcode", entries: []CountEntry{{Code: "222", Counted: 1}}, wantErr: true},
+		{
+			name:    "overflow",
+			entries: []CountEntry{{VariantID: mug, Counted: 1<<31 - 1}, {VariantID: mug, Counted: 1}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MatchCountEntries(tt.entries, variants)
+			if tt.wantErr {
+				if !errors.Is(err, ErrCountEntry) {
+					t.Fatalf("MatchCountEntries() error = %v, want ErrCountEntry", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MatchCountEntries() error = %v", err)
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("MatchCountEntries() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
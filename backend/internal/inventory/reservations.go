@@ -0,0 +1,131 @@
+package inventory
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OversellPolicy says what happens when more of a product is wanted than
+// is available, matching products.oversell_policy
+type OversellPolicy string
+
+const (
+	// OversellDeny refuses to reserve more than is available
+	OversellDeny OversellPolicy = "deny"
+	// OversellAllow sells through the shortfall without recording it
+	OversellAllow OversellPolicy = "allow"
+	// OversellBackorder sells through the shortfall and records it as
+	// backordered, to be shipped once stock comes in
+	OversellBackorder OversellPolicy = "backorder"
+)
+
+// OversellPolicies are the known policies, for validation
+var OversellPolicies = []string{string(OversellDeny), string(OversellAllow), string(OversellBackorder)}
+
+// IsValid returns true if the policy is known
+func (p OversellPolicy) IsValid() bool {
+	switch p {
+	case OversellDeny, OversellAllow, OversellBackorder:
+		return true
+	}
+	return false
+}
+
+// Reservation statuses, matching inventory_reservations.status. Only
+// active reservations hold stock.
+const (
+	ReservationActive    = "active"
+	ReservationCommitted = "committed"
+	ReservationReleased  = "released"
+	ReservationExpired   = "expired"
+)
+
+const (
+	// DefaultReservationTTL is how long a reservation holds stock when
+	// INVENTORY_RESERVATION_TTL is unset
+	DefaultReservationTTL = 15 * time.Minute
+	// MaxReservationItems bounds the variants in one reservation
+	MaxReservationItems = 100
+	// MaxReservationQuantity bounds the units of one variant
+	MaxReservationQuantity = 10000
+)
+
+// ErrReservationNotActive is returned when a reservation was already
+// committed, released or expired
+var ErrReservationNotActive = errors.New("reservation is no longer active")
+
+// Line is a variant and how many of it to reserve
+type Line struct {
+	VariantID uuid.UUID `json:"variant_id"`
+	Quantity  int32     `json:"quantity"`
+}
+
+// MergeLines checks lines and adds up those for the same variant, keeping
+// the order each variant first appears in
+func MergeLines(lines []Line) ([]Line, error) {
+	if len(lines) == 0 {
+		return nil, errors.New("at least one item is required")
+	}
+	if len(lines) > MaxReservationItems {
+		return nil, fmt.Errorf("at most %d items are allowed", MaxReservationItems)
+	}
+	merged := make([]Line, 0, len(lines))
+	index := make(map[uuid.UUID]int, len(lines))
+	for i, l := range lines {
+		if l.VariantID == uuid.Nil {
+			return nil, fmt.Errorf("item %d: variant_id is required", i)
+		}
+		if l.Quantity < 1 || l.Quantity > MaxReservationQuantity {
+			return nil, fmt.Errorf("item %d: quantity must be between 1 and %d", i, MaxReservationQuantity)
+		}
+		j, ok := index[l.VariantID]
+		if !ok {
+			index[l.VariantID] = len(merged)
+			merged = append(merged, l)
+			continue
+		}
+		if merged[j].Quantity+l.Quantity > MaxReservationQuantity {
+			return nil, fmt.Errorf("item %d: quantity must be between 1 and %d", i, MaxReservationQuantity)
+		}
+		merged[j].Quantity += l.Quantity
+	}
+	return merged, nil
+}
+
+// Hold works out how much of quantity a reservation takes from available
+// stock under policy, and how much of the rest is backordered. Under deny
+// it fails with ErrInsufficientStock when available is short.
+func Hold(policy OversellPolicy, available, quantity int32) (held, backordered int32, err error) {
+	available = max(available, 0)
+	if quantity <= available {
+		return quantity, 0, nil
+	}
+	switch policy {
+	case OversellAllow:
+		return available, 0, nil
+	case OversellBackorder:
+		return available, quantity - available, nil
+	}
+	return 0, 0, ErrInsufficientStock
+}
+
+// Allocate takes quantity from stock in order, returning how much comes
+// from each. It fails with ErrInsufficientStock when the stock is short.
+func Allocate(stock []int32, quantity int32) ([]int32, error) {
+	taken := make([]int32, len(stock))
+	for i, s := range stock {
+		if quantity == 0 {
+			break
+		}
+		n := min(max(s, 0), quantity)
+		taken[i] = n
+		quantity -= n
+	}
+	if quantity > 0 {
+		return nil, ErrInsufficientStock
+	}
+	return taken, nil
+}
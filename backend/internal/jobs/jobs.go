@@ -0,0 +1,132 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/google/uuid"
+)
+
+// DefaultQueue is the queue used when a job does not name one
+const DefaultQueue = "default"
+
+// Job statuses as stored in the jobs table
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusDead      = "dead"
+)
+
+// Args is implemented by every typed job payload.
+// Kind must be stable across deploys since it is persisted with the job.
+type Args interface {
+	Kind() string
+}
+
+// Job is a claimed job handed to a handler
+type Job struct {
+	ID          uuid.UUID
+	Queue       string
+	Kind        string
+	Attempt     int
+	MaxAttempts int
+	CreatedAt   time.Time
+}
+
+// EnqueueOptions controls how a single job is scheduled
+type EnqueueOptions struct {
+	Queue       string
+	MaxAttempts int
+	RunAt       time.Time
+}
+
+// EnqueueOption mutates EnqueueOptions
+type EnqueueOption func(*EnqueueOptions)
+
+// WithQueue routes the job to a named queue
+func WithQueue(queue string) EnqueueOption {
+	return func(o *EnqueueOptions) { o.Queue = queue }
+}
+
+// WithMaxAttempts overrides the client's default attempt budget
+func WithMaxAttempts(n int) EnqueueOption {
+	return func(o *EnqueueOptions) { o.MaxAttempts = n }
+}
+
+// WithRunAt delays the job until the given time
+func WithRunAt(t time.Time) EnqueueOption {
+	return func(o *EnqueueOptions) { o.RunAt = t }
+}
+
+// WithDelay delays the job by the given duration
+func WithDelay(d time.Duration) EnqueueOption {
+	return func(o *EnqueueOptions) { o.RunAt = time.Now().Add(d) }
+}
+
+// Client enqueues jobs. It is safe for concurrent use.
+type Client struct {
+	db          *database.Queries
+	maxAttempts int
+}
+
+// NewClient creates a job client backed by the given queries
+func NewClient(db *database.Queries) *Client {
+	return &Client{db: db, maxAttempts: DefaultRetryPolicy.MaxAttempts}
+}
+
+// Enqueue persists a job for asynchronous processing.
+// Pass a transaction-bound Queries via EnqueueTx to make the job
+// visible only when the surrounding transaction commits.
+func (c *Client) Enqueue(ctx context.Context, args Args, opts ...EnqueueOption) (database.Job, error) {
+	return c.EnqueueTx(ctx, c.db, args, opts...)
+}
+
+// EnqueueTx is like Enqueue but writes through the provided queries
+func (c *Client) EnqueueTx(ctx context.Context, q *database.Queries, args Args, opts ...EnqueueOption) (database.Job, error) {
+	if args == nil || args.Kind() == "" {
+		return database.Job{}, errors.New("jobs: args must have a kind")
+	}
+
+	o := EnqueueOptions{
+		Queue:       DefaultQueue,
+		MaxAttempts: c.maxAttempts,
+		RunAt:       time.Now(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.MaxAttempts < 1 {
+		o.MaxAttempts = 1
+	}
+
+	payload, err := json.Marshal(args)
+	if err != nil {
+		return database.Job{}, fmt.Errorf("jobs: encode %s payload: %w", args.Kind(), err)
+	}
+
+	return q.EnqueueJob(ctx, database.EnqueueJobParams{
+		Queue:       o.Queue,
+		Kind:        args.Kind(),
+		Payload:     payload,
+		MaxAttempts: int32(o.MaxAttempts),
+		RunAt:       o.RunAt,
+	})
+}
+
+// RequeueDead moves a dead-lettered job back to pending with a fresh attempt budget
+func (c *Client) RequeueDead(ctx context.Context, id uuid.UUID) (database.Job, error) {
+	return c.db.RequeueDeadJob(ctx, id)
+}
+
+// DeadJobs lists the most recently dead-lettered jobs on a queue
+func (c *Client) DeadJobs(ctx context.Context, queue string, limit int) ([]database.Job, error) {
+	return c.db.GetDeadJobsByQueue(ctx, database.GetDeadJobsByQueueParams{
+		Queue: queue,
+		Limit: int32(limit),
+	})
+}
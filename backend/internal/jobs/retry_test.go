@@ -0,0 +1,65 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestBackoffExponential(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Minute}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, time.Second},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{6, 32 * time.Second},
+		{7, time.Minute},
+		{50, time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := p.Backoff(tt.attempt); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestBackoffJitterBounds(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 10 * time.Second, MaxDelay: time.Hour, Jitter: 0.5}
+	for i := 0; i < 1000; i++ {
+		got := p.Backoff(1)
+		if got < 5*time.Second || got > 15*time.Second {
+			t.Fatalf("Backoff with jitter out of bounds: %v", got)
+		}
+	}
+}
+
+func TestPermanent(t *testing.T) {
+	base := errors.New("boom")
+
+	if Permanent(nil) != nil {
+		t.Error("Permanent(nil) should be nil")
+	}
+	if IsPermanent(base) {
+		t.Error("plain error should not be permanent")
+	}
+
+	perm := Permanent(base)
+	if !IsPermanent(perm) {
+		t.Error("Permanent error should be detected")
+	}
+	if !errors.Is(perm, base) {
+		t.Error("Permanent should unwrap to the original error")
+	}
+
+	wrapped := fmt.Errorf("handler: %w", perm)
+	if !IsPermanent(wrapped) {
+		t.Error("wrapped Permanent error should still be detected")
+	}
+}
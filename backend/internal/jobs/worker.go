@@ -0,0 +1,349 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// HandlerFunc processes one job with its decoded payload
+type HandlerFunc[T Args] func(ctx context.Context, job Job, args T) error
+
+type registration struct {
+	policy RetryPolicy
+	run    func(ctx context.Context, job Job, payload json.RawMessage) error
+}
+
+// WorkerConfig configures a Worker
+type WorkerConfig struct {
+	Queue        string
+	Concurrency  int
+	PollInterval time.Duration
+	// JobTimeout bounds a single handler run
+	JobTimeout time.Duration
+	// StaleAfter releases running jobs locked longer than this (the worker
+	// crashed mid-job), or dead-letters them once out of attempts
+	StaleAfter time.Duration
+	// Retention is how long completed jobs are kept before being pruned
+	Retention time.Duration
+	// DrainTimeout is how long Run waits for in-flight jobs after ctx is
+	// cancelled before cancelling their contexts too
+	DrainTimeout time.Duration
+	Logger       *slog.Logger
+}
+
+// Worker claims due jobs with SELECT ... FOR UPDATE SKIP LOCKED and runs registered handlers
+type Worker struct {
+	db       database.Querier
+	cfg      WorkerConfig
+	id       string
+	mu       sync.RWMutex
+	handlers map[string]registration
+}
+
+// NewWorker creates a worker for a single queue
+func NewWorker(db database.Querier, cfg WorkerConfig) *Worker {
+	if cfg.Queue == "" {
+		cfg.Queue = DefaultQueue
+	}
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 4
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.JobTimeout <= 0 {
+		cfg.JobTimeout = 5 * time.Minute
+	}
+	if cfg.StaleAfter <= 0 {
+		cfg.StaleAfter = 2 * cfg.JobTimeout
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = 7 * 24 * time.Hour
+	}
+	if cfg.DrainTimeout <= 0 {
+		cfg.DrainTimeout = cfg.JobTimeout
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	host, _ := os.Hostname()
+	return &Worker{
+		db:       db,
+		cfg:      cfg,
+		id:       fmt.Sprintf("%s:%d", host, os.Getpid()),
+		handlers: map[string]registration{},
+	}
+}
+
+// Register binds a typed handler to the kind of T using the default retry policy
+func Register[T Args](w *Worker, fn HandlerFunc[T]) {
+	RegisterWithPolicy(w, DefaultRetryPolicy, fn)
+}
+
+// RegisterWithPolicy binds a typed handler with a custom retry policy
+func RegisterWithPolicy[T Args](w *Worker, policy RetryPolicy, fn HandlerFunc[T]) {
+	var zero T
+	kind := zero.Kind()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, exists := w.handlers[kind]; exists {
+		panic("jobs: handler already registered for kind " + kind)
+	}
+	w.handlers[kind] = registration{
+		policy: policy,
+		run: func(ctx context.Context, job Job, payload json.RawMessage) error {
+			var args T
+			if err := json.Unmarshal(payload, &args); err != nil {
+				return Permanent(fmt.Errorf("decode %s payload: %w", kind, err))
+			}
+			return fn(ctx, job, args)
+		},
+	}
+}
+
+// Kinds returns the job kinds this worker will claim
+func (w *Worker) Kinds() []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	kinds := make([]string, 0, len(w.handlers))
+	for k := range w.handlers {
+		kinds = append(kinds, k)
+	}
+	return kinds
+}
+
+// Run polls until ctx is cancelled, then waits up to DrainTimeout for
+// in-flight jobs to finish. Jobs still running after that have their
+// contexts cancelled and are retried like any other failure.
+func (w *Worker) Run(ctx context.Context) error {
+	kinds := w.Kinds()
+	if len(kinds) == 0 {
+		w.cfg.Logger.Warn("job worker has no handlers registered; only housekeeping will run", "queue", w.cfg.Queue)
+	}
+
+	w.cfg.Logger.Info("job worker started",
+		"worker_id", w.id,
+		"queue", w.cfg.Queue,
+		"concurrency", w.cfg.Concurrency,
+		"kinds", kinds,
+	)
+
+	sem := make(chan struct{}, w.cfg.Concurrency)
+	var wg sync.WaitGroup
+	// Jobs outlive ctx so a shutdown lets them finish; jobCtx stops them
+	// once the drain times out
+	jobCtx, cancelJobs := context.WithCancel(context.Background())
+	defer cancelJobs()
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+	var lastPrune time.Time
+
+	for {
+		w.releaseStale(ctx)
+		if time.Since(lastPrune) > time.Hour {
+			w.pruneCompleted(ctx)
+			lastPrune = time.Now()
+		}
+
+		free := w.cfg.Concurrency - len(sem)
+		if free > 0 && len(kinds) > 0 {
+			claimed, err := w.db.ClaimJobs(ctx, database.ClaimJobsParams{
+				LockedBy: sql.NullString{String: w.id, Valid: true},
+				Queue:    w.cfg.Queue,
+				Kinds:    kinds,
+				RowLimit: int32(free),
+			})
+			if err != nil && ctx.Err() == nil {
+				w.cfg.Logger.Error("job claim failed", "queue", w.cfg.Queue, "error", err)
+			}
+			for _, j := range claimed {
+				sem <- struct{}{}
+				wg.Add(1)
+				go func(j database.Job) {
+					defer wg.Done()
+					defer func() { <-sem }()
+					w.process(jobCtx, j)
+				}(j)
+			}
+			// A full batch suggests more work is waiting; poll again immediately
+			if len(claimed) == free && ctx.Err() == nil {
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			w.cfg.Logger.Info("job worker draining", "worker_id", w.id, "in_flight", len(sem), "timeout", w.cfg.DrainTimeout)
+			if !waitTimeout(&wg, w.cfg.DrainTimeout) {
+				w.cfg.Logger.Warn("job worker drain timed out, cancelling in-flight jobs", "worker_id", w.id, "in_flight", len(sem))
+				cancelJobs()
+				wg.Wait()
+			}
+			w.cfg.Logger.Info("job worker stopped", "worker_id", w.id)
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) releaseStale(ctx context.Context) {
+	n, err := w.db.ReleaseStaleJobs(ctx, sql.NullTime{Time: time.Now().Add(-w.cfg.StaleAfter), Valid: true})
+	if err != nil {
+		if ctx.Err() == nil {
+			w.cfg.Logger.Error("releasing stale jobs failed", "error", err)
+		}
+		return
+	}
+	if n > 0 {
+		// Those out of attempts are dead-lettered rather than released
+		w.cfg.Logger.Warn("released stale jobs", "count", n, "queue", w.cfg.Queue)
+	}
+}
+
+func (w *Worker) pruneCompleted(ctx context.Context) {
+	n, err := w.db.DeleteCompletedJobsBefore(ctx, sql.NullTime{Time: time.Now().Add(-w.cfg.Retention), Valid: true})
+	if err != nil {
+		if ctx.Err() == nil {
+			w.cfg.Logger.Error("pruning completed jobs failed", "error", err)
+		}
+		return
+	}
+	if n > 0 {
+		w.cfg.Logger.Info("pruned completed jobs", "count", n)
+	}
+}
+
+// waitTimeout waits for wg, reporting false if d passes first
+func waitTimeout(wg *sync.WaitGroup, d time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// process runs one claimed job. ctx is the jobs' context rather than the
+// claim loop's, so a shutdown signal lets the handler finish instead of
+// aborting mid-flight.
+func (w *Worker) process(ctx context.Context, j database.Job) {
+	w.mu.RLock()
+	reg, ok := w.handlers[j.Kind]
+	w.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, w.cfg.JobTimeout)
+	defer cancel()
+
+	job := Job{
+		ID:          j.ID,
+		Queue:       j.Queue,
+		Kind:        j.Kind,
+		Attempt:     int(j.Attempts),
+		MaxAttempts: int(j.MaxAttempts),
+		CreatedAt:   j.CreatedAt,
+	}
+
+	// Each run is a trace of its own, holding the handler's queries and
+	// outbound calls
+	ctx, span := tracing.Start(ctx, "job "+j.Kind,
+		attribute.String("job.id", j.ID.String()),
+		attribute.String("job.queue", j.Queue),
+		attribute.Int("job.attempt", int(j.Attempts)),
+	)
+	start := time.Now()
+	var err error
+	if !ok {
+		err = Permanent(fmt.Errorf("no handler registered for kind %q", j.Kind))
+	} else {
+		err = safeRun(ctx, reg, job, j.Payload)
+	}
+	tracing.End(span, err)
+
+	logger := w.cfg.Logger.With(
+		"job_id", j.ID,
+		"kind", j.Kind,
+		"queue", j.Queue,
+		"attempt", j.Attempts,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+
+	lockedBy := sql.NullString{String: w.id, Valid: true}
+	if err == nil {
+		n, cerr := w.db.CompleteJob(context.Background(), database.CompleteJobParams{
+			ID:       j.ID,
+			LockedBy: lockedBy,
+		})
+		if cerr != nil {
+			logger.Error("job completed but status update failed", "error", cerr)
+			return
+		}
+		if n == 0 {
+			logger.Warn("job completed after it was released as stale, result discarded")
+			return
+		}
+		logger.Info("job completed")
+		return
+	}
+
+	msg := sql.NullString{String: err.Error(), Valid: true}
+	if IsPermanent(err) || j.Attempts >= j.MaxAttempts {
+		n, derr := w.db.MarkJobDead(context.Background(), database.MarkJobDeadParams{
+			ID:        j.ID,
+			LockedBy:  lockedBy,
+			LastError: msg,
+		})
+		if derr != nil {
+			logger.Error("job dead-letter update failed", "error", derr)
+			return
+		}
+		if n == 0 {
+			logger.Warn("job failed after it was released as stale, result discarded", "error", err)
+			return
+		}
+		logger.Error("job moved to dead letter", "error", err)
+		return
+	}
+
+	next := time.Now().Add(reg.policy.Backoff(int(j.Attempts)))
+	n, rerr := w.db.RetryJob(context.Background(), database.RetryJobParams{
+		ID:        j.ID,
+		LockedBy:  lockedBy,
+		RunAt:     next,
+		LastError: msg,
+	})
+	if rerr != nil {
+		logger.Error("job retry scheduling failed", "error", rerr)
+		return
+	}
+	if n == 0 {
+		logger.Warn("job failed after it was released as stale, result discarded", "error", err)
+		return
+	}
+	logger.Warn("job failed, retry scheduled", "error", err, "next_run_at", next)
+}
+
+func safeRun(ctx context.Context, reg registration, job Job, payload json.RawMessage) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return reg.run(ctx, job, payload)
+}
@@ -0,0 +1,164 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/database/dbmock"
+	"github.com/google/uuid"
+)
+
+type testArgs struct {
+	Value string `json:"value"`
+}
+
+func (testArgs) Kind() string { return "test.args" }
+
+func TestRegisterDecodesPayload(t *testing.T) {
+	w := NewWorker(nil, WorkerConfig{})
+
+	var got string
+	Register(w, func(ctx context.Context, job Job, args testArgs) error {
+		got = args.Value
+		return nil
+	})
+
+	kinds := w.Kinds()
+	if len(kinds) != 1 || kinds[0] != "test.args" {
+		t.Fatalf("Kinds() = %v, want [test.args]", kinds)
+	}
+
+	reg := w.handlers["test.args"]
+	payload, _ := json.Marshal(testArgs{Value: "hello"})
+	if err := safeRun(context.Background(), reg, Job{Kind: "test.args"}, payload); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if got != "hello" {
+		t.Errorf("handler got %q, want %q", got, "hello")
+	}
+}
+
+func TestRegisterBadPayloadIsPermanent(t *testing.T) {
+	w := NewWorker(nil, WorkerConfig{})
+	Register(w, func(ctx context.Context, job Job, args testArgs) error { return nil })
+
+	err := safeRun(context.Background(), w.handlers["test.args"], Job{}, json.RawMessage(`{"value": 1}`))
+	if !IsPermanent(err) {
+		t.Errorf("expected permanent decode error, got %v", err)
+	}
+}
+
+func TestSafeRunRecoversPanic(t *testing.T) {
+	reg := registration{
+		run: func(ctx context.Context, job Job, payload json.RawMessage) error {
+			panic("kaboom")
+		},
+	}
+	err := safeRun(context.Background(), reg, Job{}, nil)
+	if err == nil {
+		t.Fatal("expected error from panicking handler")
+	}
+	if IsPermanent(err) {
+		t.Error("panics should be retried, not dead-lettered immediately")
+	}
+}
+
+func TestRegisterDuplicatePanics(t *testing.T) {
+	w := NewWorker(nil, WorkerConfig{})
+	Register(w, func(ctx context.Context, job Job, args testArgs) error { return nil })
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("registering the same kind twice should panic")
+		}
+	}()
+	Register(w, func(ctx context.Context, job Job, args testArgs) error { return errors.New("x") })
+}
+
+func TestWaitTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		jobTime time.Duration
+		timeout time.Duration
+		want    bool
+	}{
+		{name: "finishes in time", jobTime: time.Millisecond, timeout: time.Second, want: true},
+		{name: "times out", jobTime: time.Second, timeout: 10 * time.Millisecond, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				time.Sleep(tt.jobTime)
+			}()
+			if got := waitTimeout(&wg, tt.timeout); got != tt.want {
+				t.Errorf("waitTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewWorkerDrainTimeoutDefault(t *testing.T) {
+	w := NewWorker(nil, WorkerConfig{JobTimeout: time.Minute})
+	if w.cfg.DrainTimeout != time.Minute {
+		t.Errorf("DrainTimeout = %v, want the job timeout", w.cfg.DrainTimeout)
+	}
+}
+
+func TestProcessRecordsResultAsHolder(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		attempts int32
+		want     string
+	}{
+		{name: "succeeded", want: "completed"},
+		{name: "failed", err: errors.New("timeout"), attempts: 1, want: "retried"},
+		{name: "out of attempts", err: errors.New("timeout"), attempts: 3, want: "dead"},
+		{name: "permanent", err: Permanent(errors.New("bad input")), attempts: 1, want: "dead"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			var lockedBy sql.NullString
+			q := &dbmock.Querier{
+				CompleteJobFunc: func(ctx context.Context, arg database.CompleteJobParams) (int64, error) {
+					got, lockedBy = "completed", arg.LockedBy
+					return 1, nil
+				},
+				RetryJobFunc: func(ctx context.Context, arg database.RetryJobParams) (int64, error) {
+					got, lockedBy = "retried", arg.LockedBy
+					return 1, nil
+				},
+				MarkJobDeadFunc: func(ctx context.Context, arg database.MarkJobDeadParams) (int64, error) {
+					got, lockedBy = "dead", arg.LockedBy
+					return 1, nil
+				},
+			}
+			w := NewWorker(q, WorkerConfig{})
+			Register(w, func(ctx context.Context, job Job, args testArgs) error { return tt.err })
+
+			w.process(context.Background(), database.Job{
+				ID:          uuid.New(),
+				Kind:        "test.args",
+				Payload:     json.RawMessage(`{}`),
+				Attempts:    tt.attempts,
+				MaxAttempts: 3,
+			})
+			if got != tt.want {
+				t.Errorf("job %s, want %s", got, tt.want)
+			}
+			if lockedBy.String != w.id || !lockedBy.Valid {
+				t.Errorf("recorded as %q, want the worker %q", lockedBy.String, w.id)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package labels
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/tracing"
+)
+
+// ProviderEasyPost is the name EasyPost is configured and requested by
+const ProviderEasyPost = "easypost"
+
+// EasyPostConfig configures the EasyPost v2 API
+type EasyPostConfig struct {
+	APIKey string
+	// WebhookSecret signs the tracking webhooks EasyPost sends
+	WebhookSecret string
+	// BaseURL is overridden in tests
+	BaseURL string
+}
+
+type easyPost struct {
+	cfg    EasyPostConfig
+	client *http.Client
+}
+
+// NewEasyPost returns a provider that buys labels through EasyPost
+func NewEasyPost(cfg EasyPostConfig) Provider {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api.easypost.com"
+	}
+	return &easyPost{cfg: cfg, client: &http.Client{Timeout: 60 * time.Second, Transport: tracing.Transport(nil)}}
+}
+
+type easyPostAddress struct {
+	Name    string `json:"name,omitempty"`
+	Company string `json:"company,omitempty"`
+	Street1 string `json:"street1"`
+	Street2 string `json:"street2,omitempty"`
+	City    string `json:"city"`
+	State   string `json:"state,omitempty"`
+	Zip     string `json:"zip,omitempty"`
+	Country string `json:"country"`
+	Phone   string `json:"phone,omitempty"`
+	Email   string `json:"email,omitempty"`
+}
+
+func easyPostAddr(a Address) easyPostAddress {
+	return easyPostAddress{
+		Name:    a.Name,
+		Company: a.Company,
+		Street1: a.Address1,
+		Street2: a.Address2,
+		City:    a.City,
+		State:   a.RegionCode,
+		Zip:     a.PostalCode,
+		Country: a.CountryCode,
+		Phone:   a.Phone,
+		Email:   a.Email,
+	}
+}
+
+type easyPostRate struct {
+	ID       string `json:"id"`
+	Carrier  string `json:"carrier"`
+	Service  string `json:"service"`
+	Rate     string `json:"rate"`
+	Currency string `json:"currency"`
+}
+
+type easyPostShipment struct {
+	ID           string         `json:"id"`
+	Rates        []easyPostRate `json:"rates"`
+	TrackingCode string         `json:"tracking_code"`
+	PostageLabel *struct {
+		LabelURL string `json:"label_url"`
+	} `json:"postage_label"`
+	Tracker *struct {
+		PublicURL string `json:"public_url"`
+	} `json:"tracker"`
+}
+
+// EasyPost takes ounces and inches
+const (
+	gramsPerOunce    = 28.349523125
+	centimetresPerIn = 2.54
+)
+
+func (e *easyPost) Buy(ctx context.Context, req Request) (Label, error) {
+	// The API key is the basic auth username, with no password
+	headers := map[string]string{"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte(e.cfg.APIKey+":"))}
+
+	var shipment easyPostShipment
+	err := doJSON(ctx, e.client, http.MethodPost, e.cfg.BaseURL+"/v2/shipments", headers, map[string]any{
+		"shipment": map[string]any{
+			"from_address": easyPostAddr(req.From),
+			"to_address":   easyPostAddr(req.To),
+			"parcel": map[string]float64{
+				"weight": float64(req.Parcel.WeightGrams) / gramsPerOunce,
+				"length": req.Parcel.LengthCM / centimetresPerIn,
+				"width":  req.Parcel.WidthCM / centimetresPerIn,
+				"height": req.Parcel.HeightCM / centimetresPerIn,
+			},
+			"reference": req.Reference,
+		},
+	}, &shipment)
+	if err != nil {
+		return Label{}, fmt.Errorf("easypost shipment: %w", err)
+	}
+
+	rates := make([]rate, 0, len(shipment.Rates))
+	for _, r := range shipment.Rates {
+		cents, err := parseCents(r.Rate)
+		if err != nil {
+			return Label{}, fmt.Errorf("easypost rate %s: %w", r.ID, err)
+		}
+		rates = append(rates, rate{ID: r.ID, Carrier: r.Carrier, Service: r.Service, CostCents: cents, Currency: r.Currency})
+	}
+	chosen, err := cheapestRate(rates, req.Carrier, req.Service)
+	if err != nil {
+		return Label{}, err
+	}
+
+	var bought easyPostShipment
+	err = doJSON(ctx, e.client, http.MethodPost, e.cfg.BaseURL+"/v2/shipments/"+url.PathEscape(shipment.ID)+"/buy", headers, map[string]any{
+		"rate": map[string]string{"id": chosen.ID},
+	}, &bought)
+	if err != nil {
+		return Label{}, fmt.Errorf("easypost buy: %w", err)
+	}
+	if bought.PostageLabel == nil || bought.PostageLabel.LabelURL == "" {
+		return Label{}, fmt.Errorf("easypost buy: shipment %s has no label", shipment.ID)
+	}
+
+	label := Label{
+		ShipmentRef:    shipment.ID,
+		Carrier:        chosen.Carrier,
+		Service:        chosen.Service,
+		TrackingNumber: bought.TrackingCode,
+		URL:            bought.PostageLabel.LabelURL,
+		CostCents:      chosen.CostCents,
+		Currency:       chosen.Currency,
+	}
+	if bought.Tracker != nil {
+		label.TrackingURL = bought.Tracker.PublicURL
+	}
+	return label, nil
+}
+
+// easyPostStatuses maps EasyPost tracker statuses
+var easyPostStatuses = map[string]TrackingStatus{
+	"pre_transit":      TrackingPreTransit,
+	"in_transit":       TrackingInTransit,
+	"out_for_delivery": TrackingOutForDelivery,
+	"delivered":        TrackingDelivered,
+	"return_to_sender": TrackingReturned,
+	"failure":          TrackingFailure,
+	"error":            TrackingFailure,
+}
+
+// ParseWebhook checks the X-Hmac-Signature header, an HMAC-SHA256 of the
+// body keyed with the webhook secret, and reads tracker events
+func (e *easyPost) ParseWebhook(r *http.Request, body []byte) (TrackingEvent, bool, error) {
+	if e.cfg.WebhookSecret == "" {
+		return TrackingEvent{}, false, fmt.Errorf("%w: no webhook secret is configured", ErrInvalidSignature)
+	}
+	signature, ok := strings.CutPrefix(r.Header.Get("X-Hmac-Signature"), "hmac-sha256-hex=")
+	got, err := hex.DecodeString(signature)
+	if !ok || err != nil {
+		return TrackingEvent{}, false, ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, []byte(e.cfg.WebhookSecret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return TrackingEvent{}, false, ErrInvalidSignature
+	}
+
+	var event struct {
+		Description string `json:"description"`
+		Result      struct {
+			TrackingCode string    `json:"tracking_code"`
+			Carrier      string    `json:"carrier"`
+			Status       string    `json:"status"`
+			StatusDetail string    `json:"status_detail"`
+			UpdatedAt    time.Time `json:"updated_at"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return TrackingEvent{}, false, fmt.Errorf("easypost webhook: %w", err)
+	}
+	if event.Description != "tracker.created" && event.Description != "tracker.updated" {
+		return TrackingEvent{}, false, nil
+	}
+	if event.Result.TrackingCode == "" {
+		return TrackingEvent{}, false, errors.New("easypost webhook: tracker has no tracking code")
+	}
+
+	status, ok := easyPostStatuses[event.Result.Status]
+	if !ok {
+		status = TrackingUnknown
+	}
+	return TrackingEvent{
+		TrackingNumber: event.Result.TrackingCode,
+		Carrier:        event.Result.Carrier,
+		Status:         status,
+		Detail:         event.Result.StatusDetail,
+		OccurredAt:     event.Result.UpdatedAt,
+	}, true, nil
+}
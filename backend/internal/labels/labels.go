@@ -0,0 +1,249 @@
+// Package labels buys shipping labels from a label provider, such as
+// EasyPost or Shippo, and reads the tracking updates the provider sends
+// back as webhooks.
+package labels
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/fulfillment"
+)
+
+var (
+	// ErrRejected is returned when the provider refused to sell a label,
+	// such as for an address it cannot ship to. Anything else is a failure
+	// to reach the provider.
+	ErrRejected = errors.New("label rejected by provider")
+	// ErrInvalidSignature is returned for webhooks that did not come from
+	// the provider
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+)
+
+// MaxWebhookBytes bounds a tracking webhook body
+const MaxWebhookBytes = 1 << 20
+
+// Address is where a parcel ships from or to
+type Address struct {
+	Name        string `json:"name"`
+	Company     string `json:"company,omitempty"`
+	Address1    string `json:"address1"`
+	Address2    string `json:"address2,omitempty"`
+	City        string `json:"city"`
+	RegionCode  string `json:"region_code,omitempty"`
+	PostalCode  string `json:"postal_code,omitempty"`
+	CountryCode string `json:"country_code"`
+	Phone       string `json:"phone,omitempty"`
+	Email       string `json:"email,omitempty"`
+}
+
+// Parcel is the size of the box being shipped
+type Parcel struct {
+	WeightGrams int32   `json:"weight_grams"`
+	LengthCM    float64 `json:"length_cm"`
+	WidthCM     float64 `json:"width_cm"`
+	HeightCM    float64 `json:"height_cm"`
+}
+
+// Request asks for a label for one parcel. The cheapest rate is bought,
+// narrowed to Carrier and Service when they are given.
+type Request struct {
+	From    Address
+	To      Address
+	Parcel  Parcel
+	Carrier string
+	Service string
+	// Reference is printed on the label where the carrier allows it
+	Reference string
+}
+
+// Label is a label that was bought
+type Label struct {
+	// ShipmentRef is the provider's reference for the shipment
+	ShipmentRef    string
+	Carrier        string
+	Service        string
+	TrackingNumber string
+	// TrackingURL is the provider's tracking page, when it has one
+	TrackingURL string
+	// URL is where the printable label is downloaded from
+	URL       string
+	CostCents int64
+	Currency  string
+}
+
+// TrackingStatus is a provider's tracking status in common terms
+type TrackingStatus string
+
+const (
+	TrackingPreTransit     TrackingStatus = "pre_transit"
+	TrackingInTransit      TrackingStatus = "in_transit"
+	TrackingOutForDelivery TrackingStatus = "out_for_delivery"
+	TrackingDelivered      TrackingStatus = "delivered"
+	TrackingReturned       TrackingStatus = "returned"
+	TrackingFailure        TrackingStatus = "failure"
+	TrackingUnknown        TrackingStatus = "unknown"
+)
+
+// FulfillmentStatus is the status a fulfillment moves to once its parcel
+// reaches s, or "" when s does not move it
+func (s TrackingStatus) FulfillmentStatus() fulfillment.Status {
+	switch s {
+	case TrackingInTransit, TrackingOutForDelivery:
+		return fulfillment.StatusShipped
+	case TrackingDelivered:
+		return fulfillment.StatusDelivered
+	}
+	return ""
+}
+
+// TrackingEvent is a tracking update a provider sent
+type TrackingEvent struct {
+	TrackingNumber string
+	Carrier        string
+	Status         TrackingStatus
+	// Detail is the provider's description of the update, if any
+	Detail     string
+	OccurredAt time.Time
+}
+
+// Provider buys labels and reads tracking webhooks
+type Provider interface {
+	Buy(ctx context.Context, req Request) (Label, error)
+	// ParseWebhook checks a webhook came from the provider and returns
+	// its tracking update. It reports false for other kinds of event.
+	ParseWebhook(r *http.Request, body []byte) (TrackingEvent, bool, error)
+}
+
+// Config holds the credentials of each provider. A provider without
+// credentials is not offered.
+type Config struct {
+	EasyPost EasyPostConfig
+	Shippo   ShippoConfig
+}
+
+// Providers maps provider names to their provider
+type Providers map[string]Provider
+
+// New returns the providers cfg has credentials for
+func New(cfg Config) Providers {
+	providers := Providers{}
+	if cfg.EasyPost.APIKey != "" {
+		providers[ProviderEasyPost] = NewEasyPost(cfg.EasyPost)
+	}
+	if cfg.Shippo.APIToken != "" {
+		providers[ProviderShippo] = NewShippo(cfg.Shippo)
+	}
+	return providers
+}
+
+// Lookup returns the named provider
+func (p Providers) Lookup(name string) (Provider, bool) {
+	provider, ok := p[name]
+	return provider, ok
+}
+
+// Names lists the provider names in order, for error messages
+func (p Providers) Names() []string {
+	names := make([]string, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// rate is a price a provider quoted for a shipment
+type rate struct {
+	ID        string
+	Carrier   string
+	Service   string
+	CostCents int64
+	Currency  string
+}
+
+// cheapestRate picks the lowest of rates, narrowed to carrier and service
+// when they are given
+func cheapestRate(rates []rate, carrier, service string) (rate, error) {
+	var best rate
+	var found bool
+	for _, r := range rates {
+		if carrier != "" && !strings.EqualFold(r.Carrier, carrier) {
+			continue
+		}
+		if service != "" && !strings.EqualFold(r.Service, service) {
+			continue
+		}
+		if !found || r.CostCents < best.CostCents {
+			best, found = r, true
+		}
+	}
+	if !found {
+		if carrier != "" || service != "" {
+			return rate{}, fmt.Errorf("%w: no rate for carrier %q and service %q", ErrRejected, carrier, service)
+		}
+		return rate{}, fmt.Errorf("%w: no rates for this shipment", ErrRejected)
+	}
+	return best, nil
+}
+
+// parseCents reads a decimal amount such as "7.58" as cents
+func parseCents(amount string) (int64, error) {
+	whole, frac, _ := strings.Cut(strings.TrimSpace(amount), ".")
+	if whole == "" {
+		return 0, fmt.Errorf("invalid amount %q", amount)
+	}
+	if len(frac) > 2 {
+		frac = frac[:2]
+	}
+	frac += strings.Repeat("0", 2-len(frac))
+	cents, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil || cents < 0 {
+		return 0, fmt.Errorf("invalid amount %q", amount)
+	}
+	return cents, nil
+}
+
+// doJSON sends a provider API request and decodes the response into out.
+// 4xx responses other than 429 are rejections.
+func doJSON(ctx context.Context, client *http.Client, method, url string, headers map[string]string, body, out any) error {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(encoded))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		err = fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+		if resp.StatusCode/100 == 4 && resp.StatusCode != http.StatusTooManyRequests {
+			return fmt.Errorf("%w: %v", ErrRejected, err)
+		}
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
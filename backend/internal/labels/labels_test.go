@@ -0,0 +1,294 @@
+package labels
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dfodeker/terminus/internal/fulfillment"
+)
+
+var testRequest = Request{
+	From:      Address{Name: "Warehouse", Address1: "1 Dock Rd", City: "Oakland", RegionCode: "CA", PostalCode: "94607", CountryCode: "US"},
+	To:        Address{Name: "Ada", Address1: "2 Main St", City: "Portland", RegionCode: "OR", PostalCode: "97201", CountryCode: "US"},
+	Parcel:    Parcel{WeightGrams: 567, LengthCM: 25.4, WidthCM: 12.7, HeightCM: 5.08},
+	Reference: "#1001",
+}
+
+func TestCheapestRate(t *testing.T) {
+	rates := []rate{
+		{ID: "a", Carrier: "USPS", Service: "Priority", CostCents: 900},
+		{ID: "b", Carrier: "USPS", Service: "GroundAdvantage", CostCents: 550},
+		{ID: "c", Carrier: "UPS", Service: "Ground", CostCents: 700},
+	}
+	tests := []struct {
+		name             string
+		carrier, service string
+		wantID           string
+		wantErr          bool
+	}{
+		{name: "cheapest", wantID: "b"},
+		{name: "carrier in any case", carrier: "ups", wantID: "c"},
+		{name: "service", carrier: "USPS", service: "priority", wantID: "a"},
+		{name: "no match", carrier: "FedEx", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cheapestRate(rates, tt.carrier, tt.service)
+			if tt.wantErr {
+				if !errors.Is(err, ErrRejected) {
+					t.Fatalf("cheapestRate() error = %v, want ErrRejected", err)
+				}
+				return
+			}
+			if err != nil || got.ID != tt.wantID {
+				t.Errorf("cheapestRate() = %+v, %v, want %s", got, err, tt.wantID)
+			}
+		})
+	}
+	if _, err := cheapestRate(nil, "", ""); !errors.Is(err, ErrRejected) {
+		t.Errorf("cheapestRate(nil) error = %v, want ErrRejected", err)
+	}
+}
+
+func TestParseCents(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"7.58", 758, false},
+		{"12", 1200, false},
+		{"3.5", 350, false},
+		{"0.999", 99, false},
+		{"", 0, true},
+		{"-1.00", 0, true},
+		{"abc", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseCents(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseCents(%q) = %d, %v, want %d", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestTrackingStatusFulfillmentStatus(t *testing.T) {
+	tests := []struct {
+		status TrackingStatus
+		want   fulfillment.Status
+	}{
+		{TrackingPreTransit, ""},
+		{TrackingInTransit, fulfillment.StatusShipped},
+		{TrackingOutForDelivery, fulfillment.StatusShipped},
+		{TrackingDelivered, fulfillment.StatusDelivered},
+		{TrackingReturned, ""},
+		{TrackingUnknown, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.status.FulfillmentStatus(); got != tt.want {
+			t.Errorf("%s.FulfillmentStatus() = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	if got := New(Config{}).Names(); len(got) != 0 {
+		t.Errorf("New(empty) = %v, want no providers", got)
+	}
+	got := New(Config{EasyPost: EasyPostConfig{APIKey: "ep"}, Shippo: ShippoConfig{APIToken: "sh"}}).Names()
+	if len(got) != 2 || got[0] != ProviderEasyPost || got[1] != ProviderShippo {
+		t.Errorf("New() = %v", got)
+	}
+}
+
+func TestEasyPostBuy(t *testing.T) {
+	var shipment map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, _, _ := r.BasicAuth(); user != "key" {
+			t.Errorf("basic auth user = %q", user)
+		}
+		switch r.URL.Path {
+		case "/v2/shipments":
+			json.NewDecoder(r.Body).Decode(&shipment)
+			json.NewEncoder(w).Encode(map[string]any{
+				"id": "shp_1",
+				"rates": []map[string]string{
+					{"id": "rate_1", "carrier": "USPS", "service": "Priority", "rate": "9.10", "currency": "USD"},
+					{"id": "rate_2", "carrier": "USPS", "service": "GroundAdvantage", "rate": "5.25", "currency": "USD"},
+				},
+			})
+		case "/v2/shipments/shp_1/buy":
+			var body struct {
+				Rate struct {
+					ID string `json:"id"`
+				} `json:"rate"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body.Rate.ID != "rate_2" {
+				t.Errorf("bought rate %q, want the cheapest", body.Rate.ID)
+			}
+			json.NewEncoder(w).Encode(map[string]any{
+				"id":            "shp_1",
+				"tracking_code": "9400100000000000000000",
+				"postage_label": map[string]string{"label_url": "https://easypost.example/label.png"},
+				"tracker":       map[string]string{"public_url": "https://track.easypost.example/abc"},
+			})
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	label, err := NewEasyPost(EasyPostConfig{APIKey: "key", BaseURL: srv.URL}).Buy(context.Background(), testRequest)
+	if err != nil {
+		t.Fatalf("Buy() error = %v", err)
+	}
+	want := Label{
+		ShipmentRef:    "shp_1",
+		Carrier:        "USPS",
+		Service:        "GroundAdvantage",
+		TrackingNumber: "9400100000000000000000",
+		TrackingURL:    "https://track.easypost.example/abc",
+		URL:            "https://easypost.example/label.png",
+		CostCents:      525,
+		Currency:       "USD",
+	}
+	if label != want {
+		t.Errorf("Buy() = %+v, want %+v", label, want)
+	}
+
+	parcel := shipment["shipment"].(map[string]any)["parcel"].(map[string]any)
+	if parcel["length"] != float64(10) || parcel["weight"].(float64) < 19.99 || parcel["weight"].(float64) > 20.01 {
+		t.Errorf("parcel = %v, want inches and ounces", parcel)
+	}
+}
+
+func TestShippoBuy(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "ShippoToken token" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		switch r.URL.Path {
+		case "/shipments/":
+			json.NewEncoder(w).Encode(map[string]any{
+				"object_id": "shipment_1",
+				"rates": []map[string]any{
+					{"object_id": "rate_1", "provider": "USPS", "amount": "6.00", "currency": "USD", "servicelevel": map[string]string{"token": "usps_priority"}},
+					{"object_id": "rate_2", "provider": "UPS", "amount": "8.40", "currency": "USD", "servicelevel": map[string]string{"token": "ups_ground"}},
+				},
+			})
+		case "/transactions/":
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["rate"] != "rate_2" {
+				t.Errorf("bought rate %v, want the UPS one", body["rate"])
+			}
+			json.NewEncoder(w).Encode(map[string]any{
+				"object_id":             "txn_1",
+				"status":                "SUCCESS",
+				"tracking_number":       "1Z999",
+				"tracking_url_provider": "https://ups.example/1Z999",
+				"label_url":             "https://shippo.example/label.pdf",
+			})
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	req := testRequest
+	req.Carrier = "ups"
+	label, err := NewShippo(ShippoConfig{APIToken: "token", BaseURL: srv.URL}).Buy(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Buy() error = %v", err)
+	}
+	if label.ShipmentRef != "txn_1" || label.TrackingNumber != "1Z999" || label.URL != "https://shippo.example/label.pdf" || label.CostCents != 840 || label.Service != "ups_ground" {
+		t.Errorf("Buy() = %+v", label)
+	}
+}
+
+func TestShippoBuyFailedTransaction(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/shipments/":
+			json.NewEncoder(w).Encode(map[string]any{
+				"rates": []map[string]any{{"object_id": "rate_1", "provider": "USPS", "amount": "6.00", "currency": "USD"}},
+			})
+		case "/transactions/":
+			json.NewEncoder(w).Encode(map[string]any{
+				"status":   "ERROR",
+				"messages": []map[string]string{{"text": "Address not found"}},
+			})
+		}
+	}))
+	defer srv.Close()
+
+	_, err := NewShippo(ShippoConfig{APIToken: "token", BaseURL: srv.URL}).Buy(context.Background(), testRequest)
+	if !errors.Is(err, ErrRejected) {
+		t.Errorf("Buy() error = %v, want ErrRejected", err)
+	}
+}
+
+func TestEasyPostParseWebhook(t *testing.T) {
+	provider := NewEasyPost(EasyPostConfig{APIKey: "key", WebhookSecret: "whsec"})
+	sign := func(body string) string {
+		mac := hmac.New(sha256.New, []byte("whsec"))
+		mac.Write([]byte(body))
+		return "hmac-sha256-hex=" + hex.EncodeToString(mac.Sum(nil))
+	}
+	tracker := `{"description":"tracker.updated","result":{"tracking_code":"9400","carrier":"USPS","status":"delivered","status_detail":"arrived_at_destination","updated_at":"2026-10-18T12:00:00Z"}}`
+
+	tests := []struct {
+		name      string
+		body      string
+		signature string
+		wantOK    bool
+		wantErr   error
+	}{
+		{name: "tracker update", body: tracker, signature: sign(tracker), wantOK: true},
+		{name: "other event", body: `{"description":"batch.updated"}`, signature: sign(`{"description":"batch.updated"}`)},
+		{name: "bad signature", body: tracker, signature: sign("something else"), wantErr: ErrInvalidSignature},
+		{name: "no signature", body: tracker, wantErr: ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/webhooks/shipping/easypost", nil)
+			if tt.signature != "" {
+				r.Header.Set("X-Hmac-Signature", tt.signature)
+			}
+			event, ok, err := provider.ParseWebhook(r, []byte(tt.body))
+			if !errors.Is(err, tt.wantErr) || ok != tt.wantOK {
+				t.Fatalf("ParseWebhook() = %v, %v, want %v, %v", ok, err, tt.wantOK, tt.wantErr)
+			}
+			if ok && (event.TrackingNumber != "9400" || event.Status != TrackingDelivered || event.OccurredAt.IsZero()) {
+				t.Errorf("event = %+v", event)
+			}
+		})
+	}
+}
+
+func TestShippoParseWebhook(t *testing.T) {
+	provider := NewShippo(ShippoConfig{APIToken: "token", WebhookToken: "whtok"})
+	body := `{"event":"track_updated","data":{"carrier":"usps","tracking_number":"9205","tracking_status":{"status":"TRANSIT","status_details":"In transit","status_date":"2026-10-18T09:30:00Z"}}}`
+
+	r := httptest.NewRequest(http.MethodPost, "/webhooks/shipping/shippo?token=whtok", nil)
+	event, ok, err := provider.ParseWebhook(r, []byte(body))
+	if err != nil || !ok {
+		t.Fatalf("ParseWebhook() = %v, %v", ok, err)
+	}
+	if event.TrackingNumber != "9205" || event.Status != TrackingInTransit || event.Detail != "In transit" {
+		t.Errorf("event = %+v", event)
+	}
+
+	r = httptest.NewRequest(http.MethodPost, "/webhooks/shipping/shippo?token=wrong", nil)
+	if _, _, err := provider.ParseWebhook(r, []byte(body)); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("ParseWebhook() with a wrong token error = %v, want ErrInvalidSignature", err)
+	}
+}
@@ -0,0 +1,221 @@
+package labels
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/tracing"
+)
+
+// ProviderShippo is the name Shippo is configured and requested by
+const ProviderShippo = "shippo"
+
+// ShippoConfig configures the Shippo API
+type ShippoConfig struct {
+	APIToken string
+	// WebhookToken is added as ?token= to the webhook URL registered with
+	// Shippo, which does not sign its webhooks
+	WebhookToken string
+	// BaseURL is overridden in tests
+	BaseURL string
+}
+
+type shippo struct {
+	cfg    ShippoConfig
+	client *http.Client
+}
+
+// NewShippo returns a provider that buys labels through Shippo
+func NewShippo(cfg ShippoConfig) Provider {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api.goshippo.com"
+	}
+	return &shippo{cfg: cfg, client: &http.Client{Timeout: 60 * time.Second, Transport: tracing.Transport(nil)}}
+}
+
+type shippoAddress struct {
+	Name    string `json:"name"`
+	Company string `json:"company,omitempty"`
+	Street1 string `json:"street1"`
+	Street2 string `json:"street2,omitempty"`
+	City    string `json:"city"`
+	State   string `json:"state,omitempty"`
+	Zip     string `json:"zip,omitempty"`
+	Country string `json:"country"`
+	Phone   string `json:"phone,omitempty"`
+	Email   string `json:"email,omitempty"`
+}
+
+func shippoAddr(a Address) shippoAddress {
+	return shippoAddress{
+		Name:    a.Name,
+		Company: a.Company,
+		Street1: a.Address1,
+		Street2: a.Address2,
+		City:    a.City,
+		State:   a.RegionCode,
+		Zip:     a.PostalCode,
+		Country: a.CountryCode,
+		Phone:   a.Phone,
+		Email:   a.Email,
+	}
+}
+
+type shippoMessage struct {
+	Text string `json:"text"`
+}
+
+func shippoMessages(messages []shippoMessage) string {
+	texts := make([]string, 0, len(messages))
+	for _, m := range messages {
+		if m.Text != "" {
+			texts = append(texts, m.Text)
+		}
+	}
+	return strings.Join(texts, "; ")
+}
+
+func (s *shippo) Buy(ctx context.Context, req Request) (Label, error) {
+	headers := map[string]string{"Authorization": "ShippoToken " + s.cfg.APIToken}
+	decimal := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+
+	var shipment struct {
+		ObjectID string `json:"object_id"`
+		Rates    []struct {
+			ObjectID     string `json:"object_id"`
+			Provider     string `json:"provider"`
+			Amount       string `json:"amount"`
+			Currency     string `json:"currency"`
+			ServiceLevel struct {
+				Token string `json:"token"`
+			} `json:"servicelevel"`
+		} `json:"rates"`
+		Messages []shippoMessage `json:"messages"`
+	}
+	err := doJSON(ctx, s.client, http.MethodPost, s.cfg.BaseURL+"/shipments/", headers, map[string]any{
+		"address_from": shippoAddr(req.From),
+		"address_to":   shippoAddr(req.To),
+		"parcels": []map[string]string{{
+			"length":        decimal(req.Parcel.LengthCM),
+			"width":         decimal(req.Parcel.WidthCM),
+			"height":        decimal(req.Parcel.HeightCM),
+			"distance_unit": "cm",
+			"weight":        strconv.Itoa(int(req.Parcel.WeightGrams)),
+			"mass_unit":     "g",
+		}},
+		"metadata": req.Reference,
+		"async":    false,
+	}, &shipment)
+	if err != nil {
+		return Label{}, fmt.Errorf("shippo shipment: %w", err)
+	}
+
+	rates := make([]rate, 0, len(shipment.Rates))
+	for _, r := range shipment.Rates {
+		cents, err := parseCents(r.Amount)
+		if err != nil {
+			return Label{}, fmt.Errorf("shippo rate %s: %w", r.ObjectID, err)
+		}
+		rates = append(rates, rate{ID: r.ObjectID, Carrier: r.Provider, Service: r.ServiceLevel.Token, CostCents: cents, Currency: r.Currency})
+	}
+	chosen, err := cheapestRate(rates, req.Carrier, req.Service)
+	if err != nil {
+		if msg := shippoMessages(shipment.Messages); msg != "" {
+			err = fmt.Errorf("%w (%s)", err, msg)
+		}
+		return Label{}, err
+	}
+
+	var transaction struct {
+		ObjectID       string          `json:"object_id"`
+		Status         string          `json:"status"`
+		TrackingNumber string          `json:"tracking_number"`
+		TrackingURL    string          `json:"tracking_url_provider"`
+		LabelURL       string          `json:"label_url"`
+		Messages       []shippoMessage `json:"messages"`
+	}
+	err = doJSON(ctx, s.client, http.MethodPost, s.cfg.BaseURL+"/transactions/", headers, map[string]any{
+		"rate":            chosen.ID,
+		"label_file_type": "PDF",
+		"async":           false,
+	}, &transaction)
+	if err != nil {
+		return Label{}, fmt.Errorf("shippo transaction: %w", err)
+	}
+	if transaction.Status != "SUCCESS" {
+		return Label{}, fmt.Errorf("%w: %s", ErrRejected, shippoMessages(transaction.Messages))
+	}
+
+	return Label{
+		ShipmentRef:    transaction.ObjectID,
+		Carrier:        chosen.Carrier,
+		Service:        chosen.Service,
+		TrackingNumber: transaction.TrackingNumber,
+		TrackingURL:    transaction.TrackingURL,
+		URL:            transaction.LabelURL,
+		CostCents:      chosen.CostCents,
+		Currency:       chosen.Currency,
+	}, nil
+}
+
+// shippoStatuses maps Shippo tracking statuses
+var shippoStatuses = map[string]TrackingStatus{
+	"PRE_TRANSIT": TrackingPreTransit,
+	"TRANSIT":     TrackingInTransit,
+	"DELIVERED":   TrackingDelivered,
+	"RETURNED":    TrackingReturned,
+	"FAILURE":     TrackingFailure,
+}
+
+// ParseWebhook checks the ?token= of the webhook URL and reads
+// track_updated events
+func (s *shippo) ParseWebhook(r *http.Request, body []byte) (TrackingEvent, bool, error) {
+	if s.cfg.WebhookToken == "" {
+		return TrackingEvent{}, false, fmt.Errorf("%w: no webhook token is configured", ErrInvalidSignature)
+	}
+	token := r.URL.Query().Get("token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.cfg.WebhookToken)) != 1 {
+		return TrackingEvent{}, false, ErrInvalidSignature
+	}
+
+	var event struct {
+		Event string `json:"event"`
+		Data  struct {
+			TrackingNumber string `json:"tracking_number"`
+			Carrier        string `json:"carrier"`
+			TrackingStatus struct {
+				Status        string    `json:"status"`
+				StatusDetails string    `json:"status_details"`
+				StatusDate    time.Time `json:"status_date"`
+			} `json:"tracking_status"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return TrackingEvent{}, false, fmt.Errorf("shippo webhook: %w", err)
+	}
+	if event.Event != "track_updated" {
+		return TrackingEvent{}, false, nil
+	}
+	if event.Data.TrackingNumber == "" {
+		return TrackingEvent{}, false, errors.New("shippo webhook: update has no tracking number")
+	}
+
+	status, ok := shippoStatuses[event.Data.TrackingStatus.Status]
+	if !ok {
+		status = TrackingUnknown
+	}
+	return TrackingEvent{
+		TrackingNumber: event.Data.TrackingNumber,
+		Carrier:        event.Data.Carrier,
+		Status:         status,
+		Detail:         event.Data.TrackingStatus.StatusDetails,
+		OccurredAt:     event.Data.TrackingStatus.StatusDate,
+	}, true, nil
+}
@@ -0,0 +1,57 @@
+// Package locale parses BCP 47 locales and picks the one to serve a
+// shopper from those a store has content in
+package locale
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// MaxLength bounds locales; real tags such as zh-Hant-TW are far shorter
+const MaxLength = 35
+
+// Parse checks a BCP 47 locale such as fr or pt-BR and returns it in
+// canonical form, so fr-ca and fr-CA are the same translation
+func Parse(s string) (string, error) {
+	if s == "" || len(s) > MaxLength {
+		return "", fmt.Errorf("invalid locale %q", s)
+	}
+	tag, err := language.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid locale %q", s)
+	}
+	return tag.String(), nil
+}
+
+// Negotiate picks the locale to serve from fallback and available, which
+// are canonical. An explicit locale wins over the Accept-Language header;
+// with neither, or no match, it is fallback. A close match counts, so
+// fr-CA is served fr.
+func Negotiate(explicit, acceptLanguage, fallback string, available []string) string {
+	supported := make([]language.Tag, 0, len(available)+1)
+	names := make([]string, 0, len(available)+1)
+	for _, l := range append([]string{fallback}, available...) {
+		tag, err := language.Parse(l)
+		if err != nil {
+			continue
+		}
+		supported = append(supported, tag)
+		names = append(names, l)
+	}
+	if len(supported) == 0 {
+		return fallback
+	}
+
+	var queries []string
+	if explicit != "" {
+		queries = append(queries, explicit)
+	} else if acceptLanguage != "" {
+		queries = append(queries, acceptLanguage)
+	} else {
+		return fallback
+	}
+
+	_, index := language.MatchStrings(language.NewMatcher(supported), queries...)
+	return names[index]
+}
@@ -0,0 +1,55 @@
+package locale
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "fr", want: "fr"},
+		{in: "pt-br", want: "pt-BR"},
+		{in: "zh-hant-tw", want: "zh-Hant-TW"},
+		{in: "EN_us", want: "en-US"},
+		{in: "", wantErr: true},
+		{in: "not a locale", wantErr: true},
+		{in: "xx-yy-zz-123456789-123456789-123456789", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("Parse(%q) = %q, %v, want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	available := []string{"fr", "de", "pt-BR"}
+
+	tests := []struct {
+		name     string
+		explicit string
+		accept   string
+		want     string
+	}{
+		{name: "nothing asked", want: "en"},
+		{name: "explicit", explicit: "de", want: "de"},
+		{name: "explicit wins over header", explicit: "de", accept: "fr", want: "de"},
+		{name: "header", accept: "fr-FR,fr;q=0.9,en;q=0.8", want: "fr"},
+		{name: "header weights", accept: "it, de;q=0.5, fr;q=0.7", want: "fr"},
+		{name: "regional match", accept: "pt-BR", want: "pt-BR"},
+		{name: "default asked for", accept: "en-GB", want: "en"},
+		{name: "no match", accept: "ja", want: "en"},
+		{name: "malformed header", accept: ";;;", want: "en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Negotiate(tt.explicit, tt.accept, "en", available); got != tt.want {
+				t.Errorf("Negotiate(%q, %q) = %q, want %q", tt.explicit, tt.accept, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+// Package lockout slows down password guessing. Failed sign-ins are
+// counted per account and per client IP; once either passes the threshold
+// of its policy it is locked, for twice as long with each failure after,
+// up to a cap. Counts are kept in the database so every instance sees
+// them.
+package lockout
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/crypto"
+	"github.com/dfodeker/terminus/internal/database"
+)
+
+// Kinds of security events recorded around sign-in
+const (
+	EventLoginFailed   = "login.failed"
+	EventMFAFailed     = "login.mfa_failed"
+	EventLocked        = "login.locked"
+	EventCaptchaFailed = "login.captcha_failed"
+)
+
+// Policy is how a key is locked as its failures add up
+type Policy struct {
+	// Threshold is how many failures in a row lock the key
+	Threshold int
+	// Delay is the first lockout. Each failure after doubles it, up to
+	// MaxDelay.
+	Delay    time.Duration
+	MaxDelay time.Duration
+	// Reset is how long a key goes without a failure before its count
+	// starts over
+	Reset time.Duration
+}
+
+var (
+	// AccountPolicy guards each account, whether or not it exists, so a
+	// locked account can't be told from a missing one
+	AccountPolicy = Policy{Threshold: 5, Delay: time.Minute, MaxDelay: time.Hour, Reset: 24 * time.Hour}
+	// IPPolicy guards against one client trying many accounts. It allows
+	// more failures, as an office or carrier NAT puts many people behind
+	// one address.
+	IPPolicy = Policy{Threshold: 30, Delay: time.Minute, MaxDelay: time.Hour, Reset: time.Hour}
+)
+
+// LockFor returns how long a key with failures in a row is locked
+func (p Policy) LockFor(failures int) time.Duration {
+	if failures < p.Threshold {
+		return 0
+	}
+	d := p.Delay
+	for i := p.Threshold; i < failures && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	return min(d, p.MaxDelay)
+}
+
+// Queries are the database queries a Limiter runs
+type Queries interface {
+	GetLoginThrottle(ctx context.Context, key crypto.Digest) (database.LoginThrottle, error)
+	RecordLoginFailure(ctx context.Context, arg database.RecordLoginFailureParams) (database.LoginThrottle, error)
+	LockLoginThrottle(ctx context.Context, arg database.LockLoginThrottleParams) error
+	ClearLoginThrottle(ctx context.Context, key crypto.Digest) error
+}
+
+// Attempt is who is signing in
+type Attempt struct {
+	// Account names the account tried, such as "user:" and the email.
+	// Sign-ins to different kinds of account must not share names.
+	Account string
+	IP      string
+}
+
+// Status is where an account and IP stand
+type Status struct {
+	// Failures is how many times in a row signing in to the account
+	// failed
+	Failures int
+	// LockedFor is how long until the account and IP may try again
+	LockedFor time.Duration
+}
+
+// Locked reports whether the attempt must wait
+func (s Status) Locked() bool {
+	return s.LockedFor > 0
+}
+
+// Limiter counts failed sign-ins and locks what is failing
+type Limiter struct {
+	q   Queries
+	now func() time.Time
+}
+
+// New returns a limiter over q
+func New(q Queries) *Limiter {
+	return &Limiter{q: q, now: time.Now}
+}
+
+type throttledKey struct {
+	key     string
+	policy  Policy
+	account bool
+}
+
+func (a Attempt) keys() []throttledKey {
+	keys := []throttledKey{{key: a.Account, policy: AccountPolicy, account: true}}
+	if a.IP != "" {
+		keys = append(keys, throttledKey{key: "ip:" + a.IP, policy: IPPolicy})
+	}
+	return keys
+}
+
+// Check returns where an attempt stands before the password is checked
+func (l *Limiter) Check(ctx context.Context, a Attempt) (Status, error) {
+	now := l.now()
+	var s Status
+	for _, k := range a.keys() {
+		row, err := l.q.GetLoginThrottle(ctx, crypto.Digest(k.key))
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return Status{}, err
+		}
+		if row.LockedUntil.Valid && row.LockedUntil.Time.After(now) {
+			s.LockedFor = max(s.LockedFor, row.LockedUntil.Time.Sub(now))
+		}
+		if k.account && now.Sub(row.LastFailedAt) < k.policy.Reset {
+			s.Failures = int(row.Failures)
+		}
+	}
+	return s, nil
+}
+
+// Fail records a failed attempt and returns where it leaves the account
+// and IP. A Status that is Locked means this failure locked them.
+func (l *Limiter) Fail(ctx context.Context, a Attempt) (Status, error) {
+	now := l.now()
+	var s Status
+	for _, k := range a.keys() {
+		row, err := l.q.RecordLoginFailure(ctx, database.RecordLoginFailureParams{
+			Key:         crypto.Digest(k.key),
+			ResetBefore: now.Add(-k.policy.Reset),
+		})
+		if err != nil {
+			return Status{}, err
+		}
+		if k.account {
+			s.Failures = int(row.Failures)
+		}
+		lock := k.policy.LockFor(int(row.Failures))
+		if lock == 0 {
+			continue
+		}
+		if err := l.q.LockLoginThrottle(ctx, database.LockLoginThrottleParams{
+			Key:         crypto.Digest(k.key),
+			LockedUntil: sql.NullTime{Time: now.Add(lock), Valid: true},
+		}); err != nil {
+			return Status{}, err
+		}
+		s.LockedFor = max(s.LockedFor, lock)
+	}
+	return s, nil
+}
+
+// Succeed forgets the account's failures once it has fully signed in. The
+// IP's are kept, so one good password doesn't clear a client that has been
+// trying others.
+func (l *Limiter) Succeed(ctx context.Context, a Attempt) error {
+	return l.q.ClearLoginThrottle(ctx, crypto.Digest(a.Account))
+}
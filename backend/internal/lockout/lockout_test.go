@@ -0,0 +1,132 @@
+package lockout
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/crypto"
+	"github.com/dfodeker/terminus/internal/database"
+)
+
+// fakeQueries keeps login_throttles in memory, keyed the way the digest
+// column would be
+type fakeQueries struct {
+	now  func() time.Time
+	rows map[crypto.Digest]database.LoginThrottle
+}
+
+func (f *fakeQueries) GetLoginThrottle(_ context.Context, key crypto.Digest) (database.LoginThrottle, error) {
+	row, ok := f.rows[key]
+	if !ok {
+		return database.LoginThrottle{}, sql.ErrNoRows
+	}
+	return row, nil
+}
+
+func (f *fakeQueries) RecordLoginFailure(_ context.Context, arg database.RecordLoginFailureParams) (database.LoginThrottle, error) {
+	row, ok := f.rows[arg.Key]
+	if !ok || row.LastFailedAt.Before(arg.ResetBefore) {
+		row = database.LoginThrottle{Key: arg.Key, LockedUntil: row.LockedUntil}
+	}
+	row.Failures++
+	row.LastFailedAt = f.now()
+	f.rows[arg.Key] = row
+	return row, nil
+}
+
+func (f *fakeQueries) LockLoginThrottle(_ context.Context, arg database.LockLoginThrottleParams) error {
+	row := f.rows[arg.Key]
+	row.LockedUntil = arg.LockedUntil
+	f.rows[arg.Key] = row
+	return nil
+}
+
+func (f *fakeQueries) ClearLoginThrottle(_ context.Context, key crypto.Digest) error {
+	delete(f.rows, key)
+	return nil
+}
+
+func TestLockFor(t *testing.T) {
+	p := Policy{Threshold: 3, Delay: time.Minute, MaxDelay: 5 * time.Minute}
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{failures: 0, want: 0},
+		{failures: 2, want: 0},
+		{failures: 3, want: time.Minute},
+		{failures: 4, want: 2 * time.Minute},
+		{failures: 5, want: 4 * time.Minute},
+		{failures: 6, want: 5 * time.Minute},
+		{failures: 100, want: 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := p.LockFor(tt.failures); got != tt.want {
+			t.Errorf("LockFor(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	q := &fakeQueries{now: clock, rows: map[crypto.Digest]database.LoginThrottle{}}
+	l := New(q)
+	l.now = clock
+	a := Attempt{Account: "user:ada@example.com", IP: "203.0.113.7"}
+
+	for i := 1; i < AccountPolicy.Threshold; i++ {
+		s, err := l.Fail(ctx, a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Failures != i || s.Locked() {
+			t.Fatalf("after %d failures: %+v, want unlocked", i, s)
+		}
+	}
+	s, err := l.Fail(ctx, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.LockedFor != AccountPolicy.Delay {
+		t.Fatalf("after %d failures: %+v, want locked for %v", AccountPolicy.Threshold, s, AccountPolicy.Delay)
+	}
+
+	// The lock holds for another IP, and lifts once it has passed
+	other := Attempt{Account: a.Account, IP: "198.51.100.1"}
+	if s, _ := l.Check(ctx, other); !s.Locked() {
+		t.Errorf("Check() from another IP = %+v, want the account locked", s)
+	}
+	now = now.Add(AccountPolicy.Delay + time.Second)
+	if s, _ := l.Check(ctx, other); s.Locked() || s.Failures != AccountPolicy.Threshold {
+		t.Errorf("Check() after the lock = %+v, want unlocked with the failures kept", s)
+	}
+
+	// Signing in forgets the account's failures but not the IP's
+	if err := l.Succeed(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := l.Check(ctx, a); s.Failures != 0 {
+		t.Errorf("Check() after signing in = %+v, want no failures", s)
+	}
+	if _, ok := q.rows[crypto.Digest("ip:"+a.IP)]; !ok {
+		t.Error("signing in cleared the IP's failures")
+	}
+
+	// Failures older than the reset window start over
+	for range AccountPolicy.Threshold - 1 {
+		if _, err := l.Fail(ctx, other); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now = now.Add(AccountPolicy.Reset + time.Second)
+	if s, _ := l.Check(ctx, other); s.Failures != 0 {
+		t.Errorf("Check() after the reset window = %+v, want no failures", s)
+	}
+	if s, _ := l.Fail(ctx, other); s.Failures != 1 || s.Locked() {
+		t.Errorf("Fail() after the reset window = %+v, want the count started over", s)
+	}
+}
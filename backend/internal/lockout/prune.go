@@ -0,0 +1,77 @@
+package lockout
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+)
+
+// PrunerConfig configures a Pruner
+type PrunerConfig struct {
+	Interval time.Duration
+	// BatchSize bounds each delete, as a guessing run against made up
+	// emails can leave many keys behind
+	BatchSize int
+	Logger    *slog.Logger
+}
+
+// Pruner deletes the counts of keys that have gone the longest reset
+// window without a failure and aren't locked. Running several at once is
+// harmless.
+type Pruner struct {
+	db  *database.Queries
+	cfg PrunerConfig
+}
+
+// NewPruner creates a pruner over db
+func NewPruner(db *database.Queries, cfg PrunerConfig) *Pruner {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 1000
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &Pruner{db: db, cfg: cfg}
+}
+
+// Run prunes once an interval until ctx is cancelled
+func (p *Pruner) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := p.Prune(ctx, time.Now().Add(-max(AccountPolicy.Reset, IPPolicy.Reset)))
+		if err != nil && ctx.Err() == nil {
+			p.cfg.Logger.Error("login throttle prune failed", "error", err)
+		} else if n > 0 {
+			p.cfg.Logger.Info("login throttles pruned", "deleted", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Prune deletes the keys whose last failure was before cutoff, a batch at
+// a time, and returns how many were deleted
+func (p *Pruner) Prune(ctx context.Context, cutoff time.Time) (int64, error) {
+	var total int64
+	for {
+		n, err := p.db.PruneLoginThrottles(ctx, database.PruneLoginThrottlesParams{
+			Before:   cutoff,
+			RowLimit: int32(p.cfg.BatchSize),
+		})
+		total += n
+		if err != nil || n < int64(p.cfg.BatchSize) {
+			return total, err
+		}
+	}
+}
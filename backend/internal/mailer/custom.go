@@ -0,0 +1,179 @@
+package mailer
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Custom is a store's own wording for one of the OrderTemplates. An empty
+// subject keeps the built-in one. Setting either body replaces both
+// built-in bodies, so a store that only writes a text body sends text
+// only. Parts are plain text with {{variable}} placeholders rather than Go
+// templates, so a store can reach nothing the variables don't expose.
+type Custom struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
+
+// IsZero reports whether c changes nothing
+func (c Custom) IsZero() bool {
+	return c.Subject == "" && c.Text == "" && c.HTML == ""
+}
+
+// Custom part limits
+const (
+	MaxCustomSubjectLength = 200
+	MaxCustomBodyLength    = 64 << 10
+)
+
+// OrderVariables are the placeholders an order email can use. Amounts are
+// formatted with their currency.
+var OrderVariables = []string{
+	"store_name",
+	"customer_name",
+	"order_number",
+	"order_url",
+	"items",
+	"subtotal",
+	"discount",
+	"shipping",
+	"tax",
+	"total",
+	"amount",
+	"refund_reason",
+	"carrier",
+	"tracking_number",
+	"tracking_url",
+}
+
+var placeholder = regexp.MustCompile(`\{\{\s*([a-zA-Z0-9_]*)\s*\}\}`)
+
+// Variables are the values of OrderVariables for d
+func (d OrderData) Variables() map[string]string {
+	var items strings.Builder
+	for i, it := range d.Items {
+		if i > 0 {
+			items.WriteByte('\n')
+		}
+		fmt.Fprintf(&items, "%d x %s", it.Quantity, it.Title)
+		if it.VariantTitle != "" {
+			fmt.Fprintf(&items, " (%s)", it.VariantTitle)
+		}
+		fmt.Fprintf(&items, "  %s", formatMoney(it.TotalCents, d.Currency))
+	}
+
+	return map[string]string{
+		"store_name":      d.StoreName,
+		"customer_name":   d.CustomerName,
+		"order_number":    strconv.FormatInt(d.OrderNumber, 10),
+		"order_url":       d.OrderURL,
+		"items":           items.String(),
+		"subtotal":        formatMoney(d.SubtotalCents, d.Currency),
+		"discount":        formatMoney(d.DiscountCents, d.Currency),
+		"shipping":        formatMoney(d.ShippingCents, d.Currency),
+		"tax":             formatMoney(d.TaxCents, d.Currency),
+		"total":           formatMoney(d.TotalCents, d.Currency),
+		"amount":          formatMoney(d.AmountCents, d.Currency),
+		"refund_reason":   d.RefundReason,
+		"carrier":         d.Carrier,
+		"tracking_number": d.TrackingNumber,
+		"tracking_url":    d.TrackingURL,
+	}
+}
+
+// UnknownVariables lists the placeholders in s that are not
+// OrderVariables, in order and without repeats
+func UnknownVariables(s string) []string {
+	var unknown []string
+	for _, m := range placeholder.FindAllStringSubmatch(s, -1) {
+		if !slices.Contains(OrderVariables, m[1]) && !slices.Contains(unknown, m[1]) {
+			unknown = append(unknown, m[1])
+		}
+	}
+	return unknown
+}
+
+// Check reports placeholders in c that are not OrderVariables, naming the
+// part they are in
+func (c Custom) Check() error {
+	for _, part := range []struct{ name, s string }{{"subject", c.Subject}, {"text", c.Text}, {"html", c.HTML}} {
+		if unknown := UnknownVariables(part.s); len(unknown) > 0 {
+			return fmt.Errorf("%s uses unknown variables %s", part.name, strings.Join(unknown, ", "))
+		}
+	}
+	return nil
+}
+
+// RenderOrder renders one of the OrderTemplates, with custom's parts in
+// place of the built-in ones
+func RenderOrder(tmpl Template, data OrderData, custom Custom) (Message, error) {
+	msg, err := Render(tmpl, data)
+	if err != nil {
+		return Message{}, err
+	}
+	if custom.IsZero() {
+		return msg, nil
+	}
+	if err := custom.Check(); err != nil {
+		return Message{}, fmt.Errorf("render custom %s: %w", tmpl, err)
+	}
+
+	vars := data.Variables()
+	if custom.Subject != "" {
+		// A header cannot span lines, so multi-line values are joined
+		subject := interpolate(custom.Subject, vars, func(s string) string { return strings.Join(strings.Fields(s), " ") })
+		msg.Subject = strings.TrimSpace(subject)
+	}
+	if custom.Text != "" || custom.HTML != "" {
+		msg.Text, msg.HTML = "", ""
+		if custom.Text != "" {
+			msg.Text = strings.TrimSpace(interpolate(custom.Text, vars, nil)) + "\n"
+		}
+		if custom.HTML != "" {
+			escape := func(s string) string { return strings.ReplaceAll(html.EscapeString(s), "\n", "<br>\n") }
+			msg.HTML = strings.TrimSpace(interpolate(custom.HTML, vars, escape)) + "\n"
+		}
+	}
+	return msg, nil
+}
+
+// interpolate replaces the placeholders in s with vars, passing each value
+// through escape when it is given
+func interpolate(s string, vars map[string]string, escape func(string) string) string {
+	return placeholder.ReplaceAllStringFunc(s, func(m string) string {
+		value := vars[placeholder.FindStringSubmatch(m)[1]]
+		if escape != nil {
+			value = escape(value)
+		}
+		return value
+	})
+}
+
+// SampleOrderData is a made-up order for test sends and previews
+func SampleOrderData(storeName, currency string) OrderData {
+	return OrderData{
+		StoreName:    storeName,
+		CustomerName: "Ada Lovelace",
+		OrderNumber:  1001,
+		Items: []OrderItem{
+			{Title: "Linen shirt", VariantTitle: "M / Sand", Quantity: 2, TotalCents: 9000},
+			{Title: "Canvas tote", Quantity: 1, TotalCents: 2500},
+		},
+		Currency:       currency,
+		SubtotalCents:  11500,
+		DiscountCents:  1000,
+		ShippingCents:  599,
+		TaxCents:       840,
+		TotalCents:     11939,
+		AmountCents:    11939,
+		RefundReason:   "Damaged in transit",
+		Carrier:        "UPS",
+		TrackingNumber: "1Z999AA10123456784",
+		TrackingURL:    "https://www.ups.com/track?tracknum=1Z999AA10123456784",
+	}
+}
@@ -0,0 +1,203 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/tracing"
+)
+
+// postJSON sends a provider API request. 4xx responses other than 429 are
+// rejections; everything else that fails is worth retrying.
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body any) error {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(encoded))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 == 2 {
+		_, err = io.Copy(io.Discard, resp.Body)
+		return err
+	}
+
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	err = fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	if resp.StatusCode/100 == 4 && resp.StatusCode != http.StatusTooManyRequests {
+		return fmt.Errorf("%w: %v", ErrRejected, err)
+	}
+	return err
+}
+
+// SendGridConfig configures the SendGrid v3 mail send API
+type SendGridConfig struct {
+	APIKey string
+	From   string
+	// BaseURL is overridden in tests
+	BaseURL string
+}
+
+type sendGridSender struct {
+	cfg    SendGridConfig
+	client *http.Client
+}
+
+// NewSendGrid returns a sender that delivers through SendGrid
+func NewSendGrid(cfg SendGridConfig) (Sender, error) {
+	if cfg.APIKey == "" {
+		return nil, errors.New("SENDGRID_API_KEY must be set for the sendgrid mail driver")
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api.sendgrid.com"
+	}
+	return &sendGridSender{cfg: cfg, client: &http.Client{Timeout: 30 * time.Second, Transport: tracing.Transport(nil)}}, nil
+}
+
+func (s *sendGridSender) Name() string { return "sendgrid" }
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+func sendGridAddr(s string) (sendGridAddress, error) {
+	addr, err := mail.ParseAddress(s)
+	if err != nil {
+		return sendGridAddress{}, fmt.Errorf("%w: invalid address %q", ErrRejected, s)
+	}
+	return sendGridAddress{Email: addr.Address, Name: addr.Name}, nil
+}
+
+func (s *sendGridSender) Send(ctx context.Context, msg Message) error {
+	if msg.From == "" {
+		msg.From = s.cfg.From
+	}
+	from, err := sendGridAddr(msg.From)
+	if err != nil {
+		return err
+	}
+	to := make([]sendGridAddress, 0, len(msg.To))
+	for _, t := range msg.To {
+		addr, err := sendGridAddr(t)
+		if err != nil {
+			return err
+		}
+		to = append(to, addr)
+	}
+
+	type content struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+	body := map[string]any{
+		"personalizations": []map[string]any{{"to": to}},
+		"from":             from,
+		"subject":          msg.Subject,
+	}
+	// SendGrid requires text/plain before text/html
+	var contents []content
+	if msg.Text != "" {
+		contents = append(contents, content{Type: "text/plain", Value: msg.Text})
+	}
+	if msg.HTML != "" {
+		contents = append(contents, content{Type: "text/html", Value: msg.HTML})
+	}
+	body["content"] = contents
+	if msg.ReplyTo != "" {
+		replyTo, err := sendGridAddr(msg.ReplyTo)
+		if err != nil {
+			return err
+		}
+		body["reply_to"] = replyTo
+	}
+	if msg.Tag != "" {
+		body["categories"] = []string{msg.Tag}
+	}
+
+	return postJSON(ctx, s.client, s.cfg.BaseURL+"/v3/mail/send", map[string]string{
+		"Authorization": "Bearer " + s.cfg.APIKey,
+	}, body)
+}
+
+// PostmarkConfig configures the Postmark email API
+type PostmarkConfig struct {
+	ServerToken string
+	// MessageStream defaults to Postmark's transactional "outbound" stream
+	MessageStream string
+	From          string
+	// BaseURL is overridden in tests
+	BaseURL string
+}
+
+type postmarkSender struct {
+	cfg    PostmarkConfig
+	client *http.Client
+}
+
+// NewPostmark returns a sender that delivers through Postmark
+func NewPostmark(cfg PostmarkConfig) (Sender, error) {
+	if cfg.ServerToken == "" {
+		return nil, errors.New("POSTMARK_SERVER_TOKEN must be set for the postmark mail driver")
+	}
+	if cfg.MessageStream == "" {
+		cfg.MessageStream = "outbound"
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api.postmarkapp.com"
+	}
+	return &postmarkSender{cfg: cfg, client: &http.Client{Timeout: 30 * time.Second, Transport: tracing.Transport(nil)}}, nil
+}
+
+func (s *postmarkSender) Name() string { return "postmark" }
+
+func (s *postmarkSender) Send(ctx context.Context, msg Message) error {
+	if msg.From == "" {
+		msg.From = s.cfg.From
+	}
+
+	body := map[string]any{
+		"From":          msg.From,
+		"To":            strings.Join(msg.To, ", "),
+		"Subject":       msg.Subject,
+		"MessageStream": s.cfg.MessageStream,
+	}
+	if msg.Text != "" {
+		body["TextBody"] = msg.Text
+	}
+	if msg.HTML != "" {
+		body["HtmlBody"] = msg.HTML
+	}
+	if msg.ReplyTo != "" {
+		body["ReplyTo"] = msg.ReplyTo
+	}
+	if msg.Tag != "" {
+		body["Tag"] = msg.Tag
+	}
+
+	return postJSON(ctx, s.client, s.cfg.BaseURL+"/email", map[string]string{
+		"X-Postmark-Server-Token": s.cfg.ServerToken,
+	}, body)
+}
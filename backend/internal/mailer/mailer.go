@@ -0,0 +1,181 @@
+// Package mailer renders transactional emails and delivers them through
+// SMTP or a provider API. The API enqueues rendered messages as jobs and the
+// worker sends them, so a slow or failing provider never blocks a request.
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"strings"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/jobs"
+	"github.com/google/uuid"
+)
+
+// ErrRejected is returned when the provider refuses a message outright, such
+// as an invalid address or sender. Retrying will not help.
+var ErrRejected = errors.New("message rejected")
+
+// Message is a single rendered email
+type Message struct {
+	// From defaults to the sender's configured address when empty
+	From    string   `json:"from,omitempty"`
+	To      []string `json:"to"`
+	ReplyTo string   `json:"reply_to,omitempty"`
+	Subject string   `json:"subject"`
+	Text    string   `json:"text"`
+	HTML    string   `json:"html,omitempty"`
+	// Tag names the template for provider analytics
+	Tag string `json:"tag,omitempty"`
+}
+
+// Validate checks the addresses and that there is something to send
+func (m Message) Validate() error {
+	if len(m.To) == 0 {
+		return errors.New("at least one recipient is required")
+	}
+	for _, to := range m.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return fmt.Errorf("invalid recipient %q", to)
+		}
+	}
+	if m.From != "" {
+		if _, err := mail.ParseAddress(m.From); err != nil {
+			return fmt.Errorf("invalid sender %q", m.From)
+		}
+	}
+	if m.ReplyTo != "" {
+		if _, err := mail.ParseAddress(m.ReplyTo); err != nil {
+			return fmt.Errorf("invalid reply-to %q", m.ReplyTo)
+		}
+	}
+	if strings.TrimSpace(m.Subject) == "" {
+		return errors.New("subject is required")
+	}
+	if m.Text == "" && m.HTML == "" {
+		return errors.New("a text or HTML body is required")
+	}
+	return nil
+}
+
+// Sender is implemented by every delivery driver
+type Sender interface {
+	// Name identifies the driver in logs
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
+
+// DefaultFrom is the sender address used when none is configured
+const DefaultFrom = "Terminus <no-reply@localhost>"
+
+// Config selects and configures a delivery driver. From is copied into the
+// selected driver's config.
+type Config struct {
+	// Driver is log, smtp, ses, sendgrid or postmark. Empty means log.
+	Driver   string
+	From     string
+	SMTP     SMTPConfig
+	SES      SESConfig
+	SendGrid SendGridConfig
+	Postmark PostmarkConfig
+}
+
+// New builds the driver selected by cfg.Driver. The default, log, only
+// writes messages to the log and suits development.
+func New(cfg Config, logger *slog.Logger) (Sender, error) {
+	from := cfg.From
+	if from == "" {
+		from = DefaultFrom
+	}
+	if _, err := mail.ParseAddress(from); err != nil {
+		return nil, fmt.Errorf("invalid MAIL_FROM %q", from)
+	}
+
+	switch cfg.Driver {
+	case "", "log":
+		return NewLog(from, logger), nil
+	case "smtp":
+		cfg.SMTP.From = from
+		return NewSMTP(cfg.SMTP)
+	case "ses":
+		cfg.SES.From = from
+		return NewSES(cfg.SES)
+	case "sendgrid":
+		cfg.SendGrid.From = from
+		return NewSendGrid(cfg.SendGrid)
+	case "postmark":
+		cfg.Postmark.From = from
+		return NewPostmark(cfg.Postmark)
+	default:
+		return nil, fmt.Errorf("unknown MAIL_DRIVER %q", cfg.Driver)
+	}
+}
+
+// SendArgs is the job that delivers one message
+type SendArgs struct {
+	Message Message `json:"message"`
+}
+
+func (SendArgs) Kind() string { return "mail.send" }
+
+// OrderEmailArgs is the job that sends a customer one of the
+// OrderTemplates. The worker renders it from the order as it is when the
+// job runs, in the store's wording, and only if the store sends that email.
+type OrderEmailArgs struct {
+	Template Template  `json:"template"`
+	OrderID  uuid.UUID `json:"order_id"`
+	StoreID  uuid.UUID `json:"store_id"`
+	// FulfillmentID is the shipment of TemplateOrderShipped
+	FulfillmentID uuid.UUID `json:"fulfillment_id"`
+	// RefundID is the refund of TemplateOrderRefunded
+	RefundID uuid.UUID `json:"refund_id"`
+	// AmountCents is the payment of TemplateOrderPaid
+	AmountCents int64 `json:"amount_cents,omitempty"`
+}
+
+func (OrderEmailArgs) Kind() string { return "mail.order" }
+
+// Enqueue validates a message and queues it for the worker. Pass a
+// transaction-bound Queries so the email only goes out if the change that
+// triggered it commits.
+func Enqueue(ctx context.Context, client *jobs.Client, q *database.Queries, msg Message) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+	_, err := client.EnqueueTx(ctx, q, SendArgs{Message: msg})
+	return err
+}
+
+// logSender writes messages to the log instead of delivering them
+type logSender struct {
+	from   string
+	logger *slog.Logger
+}
+
+// NewLog returns a sender that only logs, for development
+func NewLog(from string, logger *slog.Logger) Sender {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &logSender{from: from, logger: logger}
+}
+
+func (s *logSender) Name() string { return "log" }
+
+func (s *logSender) Send(ctx context.Context, msg Message) error {
+	if msg.From == "" {
+		msg.From = s.from
+	}
+	s.logger.InfoContext(ctx, "email not delivered: log mail driver",
+		"from", msg.From,
+		"to", msg.To,
+		"subject", msg.Subject,
+		"tag", msg.Tag,
+		"text", msg.Text,
+	)
+	return nil
+}
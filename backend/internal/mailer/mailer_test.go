@@ -0,0 +1,355 @@
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMessageValidate(t *testing.T) {
+	valid := Message{To: []string{"Ada <ada@example.com>"}, Subject: "Hi", Text: "Hello"}
+
+	tests := []struct {
+		name    string
+		mutate  func(*Message)
+		wantErr bool
+	}{
+		{name: "valid", mutate: func(m *Message) {}},
+		{name: "no recipients", mutate: func(m *Message) { m.To = nil }, wantErr: true},
+		{name: "bad recipient", mutate: func(m *Message) { m.To = []string{"not an address"} }, wantErr: true},
+		{name: "bad sender", mutate: func(m *Message) { m.From = "nope" }, wantErr: true},
+		{name: "bad reply-to", mutate: func(m *Message) { m.ReplyTo = "nope" }, wantErr: true},
+		{name: "no subject", mutate: func(m *Message) { m.Subject = " " }, wantErr: true},
+		{name: "no body", mutate: func(m *Message) { m.Text = "" }, wantErr: true},
+		{name: "html only", mutate: func(m *Message) { m.Text = ""; m.HTML = "<p>Hello</p>" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := valid
+			tt.mutate(&m)
+			if err := m.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	msg, err := Render(TemplateOrderConfirmation, OrderData{
+		StoreName:     "Linen & Co",
+		CustomerName:  "Ada",
+		OrderNumber:   1042,
+		Items:         []OrderItem{{Title: "Shirt", VariantTitle: "M", Quantity: 2, TotalCents: 5000}},
+		Currency:      "USD",
+		SubtotalCents: 5000,
+		ShippingCents: 599,
+		TotalCents:    5599,
+		OrderURL:      "https://shop.example.com/orders/1?a=1&b=2",
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if msg.Subject != "Linen & Co order #1042 confirmed" {
+		t.Errorf("Subject = %q", msg.Subject)
+	}
+	if !strings.Contains(msg.Text, "2 x Shirt (M)  50.00 USD") || !strings.Contains(msg.Text, "Total: 55.99 USD") {
+		t.Errorf("Text = %q", msg.Text)
+	}
+	if strings.Contains(msg.Text, "Discount") {
+		t.Error("Text should omit a zero discount")
+	}
+	if !strings.Contains(msg.HTML, "a=1&amp;b=2") {
+		t.Errorf("HTML should escape the URL, got %q", msg.HTML)
+	}
+	if msg.Tag != "order_confirmation" {
+		t.Errorf("Tag = %q", msg.Tag)
+	}
+
+	invite, err := Render(TemplateInvite, InviteData{
+		InviterName: "Grace",
+		TenantName:  "<Acme>",
+		AcceptURL:   "https://app.example.com/invites/abc",
+		ExpiresAt:   time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if invite.Subject != "Grace invited you to join <Acme>" {
+		t.Errorf("Subject = %q, want plain text", invite.Subject)
+	}
+	if !strings.Contains(invite.HTML, "&lt;Acme&gt;") {
+		t.Errorf("HTML should escape the tenant name, got %q", invite.HTML)
+	}
+
+	low, err := Render(TemplateLowStock, LowStockData{
+		StoreName: "Linen & Co",
+		Items:     []LowStockItem{{Title: "Shirt", VariantTitle: "M", SKU: "SH-M", LocationName: "Warehouse", OnHand: 2, Reorder: 24}},
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if low.Subject != "Linen & Co: 1 variant is low on stock" {
+		t.Errorf("Subject = %q", low.Subject)
+	}
+	if !strings.Contains(low.Text, "Shirt (M) [SH-M] at Warehouse: 2 left, reorder 24") {
+		t.Errorf("Text = %q", low.Text)
+	}
+
+	shipped, err := Render(TemplateOrderShipped, OrderData{
+		StoreName:      "Linen & Co",
+		OrderNumber:    1042,
+		Carrier:        "UPS",
+		TrackingNumber: "1Z999",
+		TrackingURL:    "https://ups.example.com/track?n=1Z999&x=1",
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if shipped.Subject != "Linen & Co order #1042 is on its way" {
+		t.Errorf("Subject = %q", shipped.Subject)
+	}
+	if !strings.Contains(shipped.Text, "Carrier: UPS\nTracking number: 1Z999") {
+		t.Errorf("Text = %q", shipped.Text)
+	}
+	if !strings.Contains(shipped.HTML, "n=1Z999&amp;x=1") {
+		t.Errorf("HTML should escape the tracking URL, got %q", shipped.HTML)
+	}
+
+	refunded, err := Render(TemplateOrderRefunded, OrderData{StoreName: "Linen & Co", OrderNumber: 1042, Currency: "USD", AmountCents: 1250})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !strings.Contains(refunded.Text, "We've refunded 12.50 USD for order #1042.") || strings.Contains(refunded.Text, "Reason") {
+		t.Errorf("Text = %q", refunded.Text)
+	}
+
+	magic, err := Render(TemplateMagicLink, MagicLinkData{LoginURL: "https://app.example.com/magic-link?token=a&b", ExpiresInMinutes: 15})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !strings.Contains(magic.Text, "https://app.example.com/magic-link?token=a&b") || !strings.Contains(magic.Text, "15 minutes") {
+		t.Errorf("Text = %q", magic.Text)
+	}
+	if !strings.Contains(magic.HTML, "token=a&amp;b") {
+		t.Errorf("HTML should escape the link, got %q", magic.HTML)
+	}
+
+	if _, err := Render("welcome", nil); err == nil {
+		t.Error("expected an error for an unknown template")
+	}
+}
+
+func TestRenderOrder(t *testing.T) {
+	data := OrderData{
+		StoreName:    "Linen & Co",
+		CustomerName: "Ada <3",
+		OrderNumber:  1042,
+		Items: []OrderItem{
+			{Title: "Shirt", VariantTitle: "M", Quantity: 2, TotalCents: 5000},
+			{Title: "Tote", Quantity: 1, TotalCents: 1500},
+		},
+		Currency:   "USD",
+		TotalCents: 6500,
+	}
+
+	tests := []struct {
+		name        string
+		custom      Custom
+		wantSubject string
+		wantText    string
+		wantHTML    string
+		wantErr     bool
+	}{
+		{
+			name:        "built in",
+			wantSubject: "Linen & Co order #1042 confirmed",
+			wantText:    "Thanks for your order!",
+			wantHTML:    "<table>",
+		},
+		{
+			name:        "subject only keeps the built-in bodies",
+			custom:      Custom{Subject: "Thanks, {{ customer_name }}! Order {{order_number}}"},
+			wantSubject: "Thanks, Ada <3! Order 1042",
+			wantText:    "Thanks for your order!",
+			wantHTML:    "<table>",
+		},
+		{
+			name:        "text only drops the built-in html",
+			custom:      Custom{Text: "Total {{total}}\n{{items}}"},
+			wantSubject: "Linen & Co order #1042 confirmed",
+			wantText:    "Total 65.00 USD\n2 x Shirt (M)  50.00 USD\n1 x Tote  15.00 USD\n",
+		},
+		{
+			name:        "html values are escaped",
+			custom:      Custom{HTML: "<p>Hi {{customer_name}}</p><p>{{items}}</p>"},
+			wantSubject: "Linen & Co order #1042 confirmed",
+			wantHTML:    "<p>Hi Ada &lt;3</p><p>2 x Shirt (M)  50.00 USD<br>\n1 x Tote  15.00 USD</p>\n",
+		},
+		{
+			name:        "multi-line values are joined in the subject",
+			custom:      Custom{Subject: "{{items}}"},
+			wantSubject: "2 x Shirt (M) 50.00 USD 1 x Tote 15.00 USD",
+			wantText:    "Thanks for your order!",
+			wantHTML:    "<table>",
+		},
+		{name: "unknown variable", custom: Custom{Text: "{{password}}"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := RenderOrder(TemplateOrderConfirmation, data, tt.custom)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenderOrder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if msg.Subject != tt.wantSubject {
+				t.Errorf("Subject = %q, want %q", msg.Subject, tt.wantSubject)
+			}
+			if !strings.Contains(msg.Text, tt.wantText) || (tt.wantText == "") != (msg.Text == "") {
+				t.Errorf("Text = %q, want %q", msg.Text, tt.wantText)
+			}
+			if !strings.Contains(msg.HTML, tt.wantHTML) || (tt.wantHTML == "") != (msg.HTML == "") {
+				t.Errorf("HTML = %q, want %q", msg.HTML, tt.wantHTML)
+			}
+		})
+	}
+}
+
+func TestUnknownVariables(t *testing.T) {
+	got := UnknownVariables("{{order_number}} {{ secret }} {{secret}} {{}} {{Total}} {{ not a var }}")
+	want := []string{"secret", "", "Total"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("UnknownVariables() = %q, want %q", got, want)
+	}
+	if err := (Custom{Subject: "{{nope}}"}).Check(); err == nil || !strings.Contains(err.Error(), "subject") {
+		t.Errorf("Check() error = %v, want one naming the subject", err)
+	}
+}
+
+func TestBuildMIME(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	body, err := buildMIME(Message{
+		From:    "Shop <shop@example.com>",
+		To:      []string{"ada@example.com"},
+		Subject: "Café",
+		Text:    "Hello",
+		HTML:    "<p>Hello</p>",
+	}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := string(body)
+	for _, want := range []string{
+		"From: Shop <shop@example.com>\r\n",
+		"Subject: =?utf-8?q?Caf=C3=A9?=\r\n",
+		"Message-ID: <",
+		"@example.com>\r\n",
+		"Content-Type: multipart/alternative;",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Type: text/html; charset=utf-8",
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("message missing %q:\n%s", want, s)
+		}
+	}
+}
+
+func TestSendGridSend(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v3/mail/send" || r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("unexpected request %s %s", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	sender, err := NewSendGrid(SendGridConfig{APIKey: "key", From: "Shop <shop@example.com>", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sender.Send(context.Background(), Message{To: []string{"ada@example.com"}, Subject: "Hi", Text: "t", HTML: "<p>h</p>", Tag: "invite"})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	from := got["from"].(map[string]any)
+	if from["email"] != "shop@example.com" || from["name"] != "Shop" {
+		t.Errorf("from = %v", from)
+	}
+	content := got["content"].([]any)
+	if len(content) != 2 || content[0].(map[string]any)["type"] != "text/plain" {
+		t.Errorf("content = %v, want text before html", content)
+	}
+}
+
+func TestPostmarkSendErrors(t *testing.T) {
+	tests := []struct {
+		status       int
+		wantRejected bool
+	}{
+		{http.StatusUnprocessableEntity, true},
+		{http.StatusTooManyRequests, false},
+		{http.StatusServiceUnavailable, false},
+	}
+	for _, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Postmark-Server-Token") != "token" {
+				t.Error("missing server token")
+			}
+			w.WriteHeader(tt.status)
+		}))
+
+		sender, err := NewPostmark(PostmarkConfig{ServerToken: "token", From: "shop@example.com", BaseURL: srv.URL})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = sender.Send(context.Background(), Message{To: []string{"ada@example.com"}, Subject: "Hi", Text: "t"})
+		srv.Close()
+
+		if err == nil {
+			t.Fatalf("status %d: expected an error", tt.status)
+		}
+		if got := errors.Is(err, ErrRejected); got != tt.wantRejected {
+			t.Errorf("status %d: rejected = %v, want %v", tt.status, got, tt.wantRejected)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      Config
+		wantName string
+		wantErr  bool
+	}{
+		{name: "default", cfg: Config{}, wantName: "log"},
+		{name: "smtp", cfg: Config{Driver: "smtp", SMTP: SMTPConfig{Host: "mail.example.com"}}, wantName: "smtp"},
+		{name: "smtp without host", cfg: Config{Driver: "smtp"}, wantErr: true},
+		{name: "ses", cfg: Config{Driver: "ses", SES: SESConfig{Region: "us-east-1", Username: "u", Password: "p"}}, wantName: "ses"},
+		{name: "sendgrid", cfg: Config{Driver: "sendgrid", SendGrid: SendGridConfig{APIKey: "k"}}, wantName: "sendgrid"},
+		{name: "postmark without token", cfg: Config{Driver: "postmark"}, wantErr: true},
+		{name: "unknown driver", cfg: Config{Driver: "pigeon"}, wantErr: true},
+		{name: "invalid from", cfg: Config{From: "nope"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender, err := New(tt.cfg, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && sender.Name() != tt.wantName {
+				t.Errorf("Name() = %q, want %q", sender.Name(), tt.wantName)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPConfig configures the SMTP driver. STARTTLS is used whenever the
+// server offers it, and required when credentials are set.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+type smtpSender struct {
+	name string
+	cfg  SMTPConfig
+}
+
+// NewSMTP returns a sender that relays through an SMTP server
+func NewSMTP(cfg SMTPConfig) (Sender, error) {
+	if cfg.Host == "" {
+		return nil, errors.New("SMTP_HOST must be set for the smtp mail driver")
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 587
+	}
+	return &smtpSender{name: "smtp", cfg: cfg}, nil
+}
+
+// SESConfig configures Amazon SES through its SMTP interface, which takes
+// the SMTP credentials generated in the SES console
+type SESConfig struct {
+	Region   string
+	Username string
+	Password string
+	From     string
+}
+
+// NewSES returns a sender that delivers through Amazon SES
+func NewSES(cfg SESConfig) (Sender, error) {
+	if cfg.Region == "" || cfg.Username == "" || cfg.Password == "" {
+		return nil, errors.New("SES_REGION, SES_SMTP_USERNAME and SES_SMTP_PASSWORD must be set for the ses mail driver")
+	}
+	return &smtpSender{name: "ses", cfg: SMTPConfig{
+		Host:     "email-smtp." + cfg.Region + ".amazonaws.com",
+		Port:     587,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		From:     cfg.From,
+	}}, nil
+}
+
+func (s *smtpSender) Name() string { return s.name }
+
+func (s *smtpSender) Send(ctx context.Context, msg Message) error {
+	if msg.From == "" {
+		msg.From = s.cfg.From
+	}
+	from, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return fmt.Errorf("%w: invalid sender %q", ErrRejected, msg.From)
+	}
+	recipients := make([]string, 0, len(msg.To))
+	for _, to := range msg.To {
+		addr, err := mail.ParseAddress(to)
+		if err != nil {
+			return fmt.Errorf("%w: invalid recipient %q", ErrRejected, to)
+		}
+		recipients = append(recipients, addr.Address)
+	}
+
+	body, err := buildMIME(msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	dialer := net.Dialer{Timeout: 30 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port)))
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else {
+		conn.SetDeadline(time.Now().Add(2 * time.Minute))
+	}
+
+	c, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.cfg.Host, MinVersion: tls.VersionTLS12}); err != nil {
+			return err
+		}
+	}
+	if s.cfg.Username != "" {
+		// PlainAuth refuses to send credentials over an unencrypted connection
+		if err := c.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			return smtpError(err)
+		}
+	}
+
+	if err := c.Mail(from.Address); err != nil {
+		return smtpError(err)
+	}
+	for _, rcpt := range recipients {
+		if err := c.Rcpt(rcpt); err != nil {
+			return smtpError(err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return smtpError(err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return smtpError(err)
+	}
+	return c.Quit()
+}
+
+// smtpError marks permanent (5xx) replies as rejections
+func smtpError(err error) error {
+	var tpErr *textproto.Error
+	if errors.As(err, &tpErr) && tpErr.Code >= 500 {
+		return fmt.Errorf("%w: %v", ErrRejected, err)
+	}
+	return err
+}
+
+// buildMIME renders msg as an RFC 5322 message with quoted-printable text
+// and HTML alternatives
+func buildMIME(msg Message, now time.Time) ([]byte, error) {
+	var b bytes.Buffer
+
+	header := func(name, value string) {
+		b.WriteString(name + ": " + value + "\r\n")
+	}
+	header("From", msg.From)
+	header("To", strings.Join(msg.To, ", "))
+	if msg.ReplyTo != "" {
+		header("Reply-To", msg.ReplyTo)
+	}
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("Message-ID", "<"+randomToken()+"@"+messageIDDomain(msg.From)+">")
+	header("MIME-Version", "1.0")
+
+	if msg.HTML == "" {
+		header("Content-Type", "text/plain; charset=utf-8")
+		header("Content-Transfer-Encoding", "quoted-printable")
+		b.WriteString("\r\n")
+		return b.Bytes(), writeQP(&b, msg.Text)
+	}
+
+	boundary := "terminus-" + randomToken()
+	header("Content-Type", `multipart/alternative; boundary="`+boundary+`"`)
+	b.WriteString("\r\n")
+
+	parts := []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	}
+	for _, part := range parts {
+		if part.body == "" {
+			continue
+		}
+		b.WriteString("--" + boundary + "\r\n")
+		b.WriteString("Content-Type: " + part.contentType + "\r\n")
+		b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		if err := writeQP(&b, part.body); err != nil {
+			return nil, err
+		}
+		b.WriteString("\r\n")
+	}
+	b.WriteString("--" + boundary + "--\r\n")
+	return b.Bytes(), nil
+}
+
+func writeQP(b *bytes.Buffer, s string) error {
+	w := quotedprintable.NewWriter(b)
+	if _, err := w.Write([]byte(s)); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func randomToken() string {
+	buf := make([]byte, 12)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func messageIDDomain(from string) string {
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if i := strings.LastIndex(addr.Address, "@"); i >= 0 {
+			return addr.Address[i+1:]
+		}
+	}
+	return "localhost"
+}
@@ -0,0 +1,173 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// Template names an email layout in templates/
+type Template string
+
+const (
+	TemplateInvite            Template = "invite"
+	TemplatePasswordReset     Template = "password_reset"
+	TemplateOrderConfirmation Template = "order_confirmation"
+	TemplateVerifyEmail       Template = "verify_email"
+	TemplateMagicLink         Template = "magic_link"
+	TemplateLowStock          Template = "low_stock"
+	TemplateOrderPaid         Template = "order_paid"
+	TemplateOrderShipped      Template = "order_shipped"
+	TemplateOrderRefunded     Template = "order_refunded"
+)
+
+// OrderTemplates are the emails a customer gets as their order moves
+// along. Stores can reword them; see Custom.
+var OrderTemplates = []Template{TemplateOrderConfirmation, TemplateOrderPaid, TemplateOrderShipped, TemplateOrderRefunded}
+
+// InviteData fills TemplateInvite
+type InviteData struct {
+	InviterName string
+	TenantName  string
+	AcceptURL   string
+	ExpiresAt   time.Time
+}
+
+// PasswordResetData fills TemplatePasswordReset
+type PasswordResetData struct {
+	Name             string
+	ResetURL         string
+	ExpiresInMinutes int
+}
+
+// MagicLinkData fills TemplateMagicLink
+type MagicLinkData struct {
+	Name             string
+	LoginURL         string
+	ExpiresInMinutes int
+}
+
+// VerifyEmailData fills TemplateVerifyEmail
+type VerifyEmailData struct {
+	VerifyURL      string
+	ExpiresInHours int
+}
+
+// OrderData fills the OrderTemplates. Amounts are in the smallest unit of
+// Currency.
+type OrderData struct {
+	StoreName     string
+	CustomerName  string
+	OrderNumber   int64
+	Items         []OrderItem
+	Currency      string
+	SubtotalCents int64
+	DiscountCents int64
+	ShippingCents int64
+	TaxCents      int64
+	TotalCents    int64
+	OrderURL      string
+	// AmountCents is what was paid, for TemplateOrderPaid, or refunded, for
+	// TemplateOrderRefunded
+	AmountCents  int64
+	RefundReason string
+	// Carrier and tracking fill TemplateOrderShipped
+	Carrier        string
+	TrackingNumber string
+	TrackingURL    string
+}
+
+// OrderItem is one line of an order email
+type OrderItem struct {
+	Title        string
+	VariantTitle string
+	Quantity     int32
+	TotalCents   int64
+}
+
+// LowStockData fills TemplateLowStock
+type LowStockData struct {
+	StoreName string
+	Items     []LowStockItem
+}
+
+// LowStockItem is a variant that went low at a location
+type LowStockItem struct {
+	Title        string
+	VariantTitle string
+	SKU          string
+	LocationName string
+	OnHand       int32
+	// Reorder is the suggested quantity to order
+	Reorder int32
+}
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var funcs = map[string]any{
+	"money": formatMoney,
+	"date":  func(t time.Time) string { return t.UTC().Format("January 2, 2006 15:04 MST") },
+}
+
+type templateSet struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// templates holds one parsed set per file. Each file defines "subject",
+// "text" and "html", so files are parsed separately to keep those names
+// apart. The subject and text are plain text; the HTML part gets
+// contextual escaping.
+var templates = func() map[Template]templateSet {
+	sets := map[Template]templateSet{}
+	for _, tmpl := range append([]Template{TemplateInvite, TemplatePasswordReset, TemplateVerifyEmail, TemplateMagicLink, TemplateLowStock}, OrderTemplates...) {
+		path := "templates/" + string(tmpl) + ".tmpl"
+		sets[tmpl] = templateSet{
+			text: texttemplate.Must(texttemplate.New("").Funcs(funcs).ParseFS(templateFS, path)),
+			html: htmltemplate.Must(htmltemplate.New("").Funcs(funcs).ParseFS(templateFS, path)),
+		}
+	}
+	return sets
+}()
+
+// Render builds the subject and bodies of a templated email. The caller sets
+// the recipients.
+func Render(tmpl Template, data any) (Message, error) {
+	set, ok := templates[tmpl]
+	if !ok {
+		return Message{}, fmt.Errorf("unknown email template %q", tmpl)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := set.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, fmt.Errorf("render %s subject: %w", tmpl, err)
+	}
+	if err := set.text.ExecuteTemplate(&text, "text", data); err != nil {
+		return Message{}, fmt.Errorf("render %s text: %w", tmpl, err)
+	}
+	if err := set.html.ExecuteTemplate(&html, "html", data); err != nil {
+		return Message{}, fmt.Errorf("render %s html: %w", tmpl, err)
+	}
+
+	return Message{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		HTML:    strings.TrimSpace(html.String()) + "\n",
+		Tag:     string(tmpl),
+	}, nil
+}
+
+// formatMoney renders minor units as a decimal amount, e.g. 1999 USD → "19.99 USD"
+func formatMoney(cents int64, currency string) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d %s", sign, cents/100, cents%100, currency)
+}
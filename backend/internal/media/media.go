@@ -0,0 +1,125 @@
+// Package media generates resized thumbnails of product images and names
+// the storage keys they are served from.
+package media
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// DefaultThumbnailWidths are generated when MEDIA_THUMBNAIL_WIDTHS is unset
+var DefaultThumbnailWidths = []int{160, 320, 640, 1280}
+
+// MaxThumbnailWidth bounds configured widths; larger sizes defeat the purpose
+const MaxThumbnailWidth = 4096
+
+// MaxBytes is the largest original image accepted
+const MaxBytes = 20 << 20
+
+// ThumbnailArgs is the job that renders the thumbnails of one uploaded image
+type ThumbnailArgs struct {
+	MediaID uuid.UUID `json:"media_id"`
+}
+
+func (ThumbnailArgs) Kind() string { return "media.thumbnails" }
+
+// FetchArgs is the job that copies an image from elsewhere on the web, such
+// as another platform's CDN, into storage for a pending media row
+type FetchArgs struct {
+	MediaID   uuid.UUID `json:"media_id"`
+	SourceURL string    `json:"source_url"`
+}
+
+func (FetchArgs) Kind() string { return "media.fetch" }
+
+// Extensions maps the image types accepted for product media to the file
+// extension they are stored under
+var Extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+	"image/gif":  ".gif",
+}
+
+// OriginalKey is where the original of a product image is stored
+func OriginalKey(storeID, productID, mediaID uuid.UUID, contentType string) string {
+	return "stores/" + storeID.String() + "/products/" + productID.String() + "/" + mediaID.String() + Extensions[contentType]
+}
+
+// TypeByExtension returns the accepted image type a file extension such as
+// ".JPEG" names
+func TypeByExtension(ext string) (string, bool) {
+	ext = strings.ToLower(ext)
+	if ext == ".jpeg" {
+		ext = ".jpg"
+	}
+	for contentType, e := range Extensions {
+		if e == ext {
+			return contentType, true
+		}
+	}
+	return "", false
+}
+
+// ParseWidths parses a comma-separated list such as "160,320,640".
+// The result is sorted and de-duplicated; an empty string yields the defaults.
+func ParseWidths(s string) ([]int, error) {
+	if strings.TrimSpace(s) == "" {
+		return append([]int(nil), DefaultThumbnailWidths...), nil
+	}
+
+	seen := map[int]bool{}
+	widths := []int{}
+	for _, part := range strings.Split(s, ",") {
+		w, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || w < 1 || w > MaxThumbnailWidth {
+			return nil, fmt.Errorf("invalid thumbnail width %q", part)
+		}
+		if !seen[w] {
+			seen[w] = true
+			widths = append(widths, w)
+		}
+	}
+	sort.Ints(widths)
+	return widths, nil
+}
+
+// ErrUnsupportedFormat is returned for images the thumbnailer cannot decode
+var ErrUnsupportedFormat = errors.New("unsupported image format")
+
+// ThumbnailFormat returns the content type and file extension thumbnails of
+// an original are stored as. GIFs are flattened to their first frame and
+// stored as PNG; WebP cannot be decoded and is served as uploaded.
+func ThumbnailFormat(contentType string) (string, string, error) {
+	switch contentType {
+	case "image/jpeg":
+		return "image/jpeg", ".jpg", nil
+	case "image/png", "image/gif":
+		return "image/png", ".png", nil
+	}
+	return "", "", ErrUnsupportedFormat
+}
+
+// CanThumbnail reports whether thumbnails can be generated for a content type
+func CanThumbnail(contentType string) bool {
+	_, _, err := ThumbnailFormat(contentType)
+	return err == nil
+}
+
+// ThumbnailKey is the storage key of the thumbnail of an original at a width,
+// e.g. stores/.../abc.jpg at 320 becomes stores/.../abc_320w.jpg. Keys are
+// derived from the original so URLs can be built without extra lookups.
+func ThumbnailKey(originalKey, contentType string, width int) string {
+	_, ext, err := ThumbnailFormat(contentType)
+	if err != nil {
+		ext = path.Ext(originalKey)
+	}
+	base := strings.TrimSuffix(originalKey, path.Ext(originalKey))
+	return base + "_" + strconv.Itoa(width) + "w" + ext
+}
@@ -0,0 +1,126 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"reflect"
+	"testing"
+)
+
+func TestParseWidths(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []int
+		wantErr bool
+	}{
+		{"empty uses defaults", "", DefaultThumbnailWidths, false},
+		{"sorted and deduplicated", "640, 160,640,320", []int{160, 320, 640}, false},
+		{"zero", "0,320", nil, true},
+		{"too wide", "5000", nil, true},
+		{"not a number", "small", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseWidths(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWidths(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseWidths(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestThumbnailKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         string
+		contentType string
+		width       int
+		want        string
+	}{
+		{"jpeg", "stores/s/products/p/m.jpg", "image/jpeg", 320, "stores/s/products/p/m_320w.jpg"},
+		{"gif becomes png", "stores/s/products/p/m.gif", "image/gif", 160, "stores/s/products/p/m_160w.png"},
+		{"unsupported keeps extension", "stores/s/products/p/m.webp", "image/webp", 640, "stores/s/products/p/m_640w.webp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ThumbnailKey(tt.key, tt.contentType, tt.width); got != tt.want {
+				t.Errorf("ThumbnailKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResize(t *testing.T) {
+	// Left half black, right half white
+	src := image.NewNRGBA(image.Rect(10, 10, 410, 210))
+	for y := 10; y < 210; y++ {
+		for x := 10; x < 410; x++ {
+			if x >= 210 {
+				src.Set(x, y, color.White)
+			} else {
+				src.Set(x, y, color.Black)
+			}
+		}
+	}
+
+	dst := Resize(src, 4)
+	if got := dst.Bounds(); got.Dx() != 4 || got.Dy() != 2 {
+		t.Fatalf("Resize bounds = %v, want 4x2", got)
+	}
+	if got := dst.RGBAAt(0, 0); got != (color.RGBA{0, 0, 0, 255}) {
+		t.Errorf("left pixel = %v, want black", got)
+	}
+	if got := dst.RGBAAt(3, 1); got != (color.RGBA{255, 255, 255, 255}) {
+		t.Errorf("right pixel = %v, want white", got)
+	}
+
+	// A single output column averages both halves to mid grey
+	if got := Resize(src, 1).RGBAAt(0, 0); got.R < 127 || got.R > 128 {
+		t.Errorf("averaged pixel = %v, want mid grey", got)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 800, 600))
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Generate(buf.Bytes(), "image/png", []int{200, 400, 800, 1600})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if result.Width != 800 || result.Height != 600 {
+		t.Errorf("original = %dx%d, want 800x600", result.Width, result.Height)
+	}
+	if len(result.Thumbnails) != 2 {
+		t.Fatalf("got %d thumbnails, want 2 (no upscaling)", len(result.Thumbnails))
+	}
+	if thumb := result.Thumbnails[1]; thumb.Width != 400 || thumb.Height != 300 {
+		t.Errorf("thumbnail = %dx%d, want 400x300", thumb.Width, thumb.Height)
+	}
+	if _, err := png.Decode(bytes.NewReader(result.Thumbnails[0].Data)); err != nil {
+		t.Errorf("thumbnail is not a valid PNG: %v", err)
+	}
+
+	buf.Reset()
+	if err := jpeg.Encode(&buf, src, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Generate(buf.Bytes(), "image/webp", []int{200}); err != ErrUnsupportedFormat {
+		t.Errorf("Generate(webp) error = %v, want ErrUnsupportedFormat", err)
+	}
+	if _, err := Generate([]byte("not an image"), "image/jpeg", []int{200}); err == nil {
+		t.Error("Generate() should reject undecodable data")
+	}
+}
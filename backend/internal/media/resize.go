@@ -0,0 +1,152 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+)
+
+// MaxSourcePixels guards against decompression bombs: a small file can
+// declare enormous dimensions and exhaust memory when decoded
+const MaxSourcePixels = 50_000_000
+
+const jpegQuality = 85
+
+// Thumbnail is one encoded size variant of an image
+type Thumbnail struct {
+	Width  int
+	Height int
+	Data   []byte
+}
+
+// Result holds the original dimensions and the generated thumbnails
+type Result struct {
+	Width      int
+	Height     int
+	Thumbnails []Thumbnail
+}
+
+// Generate decodes an original and renders it at each width narrower than
+// the original. Images are never upscaled, so small originals may yield
+// fewer (or no) thumbnails.
+func Generate(data []byte, contentType string, widths []int) (*Result, error) {
+	outType, _, err := ThumbnailFormat(contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode image header: %w", err)
+	}
+	if config.Width < 1 || config.Height < 1 || config.Width*config.Height > MaxSourcePixels {
+		return nil, fmt.Errorf("image dimensions %dx%d are out of range", config.Width, config.Height)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %w", err)
+	}
+
+	result := &Result{Width: config.Width, Height: config.Height}
+	for _, width := range widths {
+		if width >= config.Width {
+			continue
+		}
+
+		thumb := Resize(src, width)
+
+		var buf bytes.Buffer
+		if err := encode(&buf, thumb, outType); err != nil {
+			return nil, fmt.Errorf("encode %dw thumbnail: %w", width, err)
+		}
+		result.Thumbnails = append(result.Thumbnails, Thumbnail{
+			Width:  thumb.Bounds().Dx(),
+			Height: thumb.Bounds().Dy(),
+			Data:   buf.Bytes(),
+		})
+	}
+	return result, nil
+}
+
+func encode(buf *bytes.Buffer, img image.Image, contentType string) error {
+	switch contentType {
+	case "image/jpeg":
+		return jpeg.Encode(buf, img, &jpeg.Options{Quality: jpegQuality})
+	case "image/png":
+		enc := png.Encoder{CompressionLevel: png.BestCompression}
+		return enc.Encode(buf, img)
+	}
+	return errors.New("no encoder for " + contentType)
+}
+
+// ScaledHeight is the height that keeps the aspect ratio of w x h at width
+func ScaledHeight(w, h, width int) int {
+	height := (h*width + w/2) / w
+	if height < 1 {
+		height = 1
+	}
+	return height
+}
+
+// Resize scales src to the given width, keeping its aspect ratio. It uses
+// a box filter, which averages every source pixel a destination pixel
+// covers; that is cheap and alias-free for the downscaling done here.
+func Resize(src image.Image, width int) *image.RGBA {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	height := ScaledHeight(sw, sh, width)
+
+	// Work on premultiplied RGBA so transparent pixels don't bleed colour
+	rgba, ok := src.(*image.RGBA)
+	if !ok || rgba.Rect.Min != (image.Point{}) {
+		rgba = image.NewRGBA(image.Rect(0, 0, sw, sh))
+		draw.Draw(rgba, rgba.Rect, src, b.Min, draw.Src)
+	}
+
+	// Separable filter: shrink rows first, then columns
+	tmp := image.NewRGBA(image.Rect(0, 0, width, sh))
+	for y := 0; y < sh; y++ {
+		in := rgba.Pix[y*rgba.Stride:]
+		out := tmp.Pix[y*tmp.Stride:]
+		boxFilter(in, 4, sw, out, 4, width)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		boxFilter(tmp.Pix[x*4:], tmp.Stride, sh, dst.Pix[x*4:], dst.Stride, height)
+	}
+	return dst
+}
+
+// boxFilter resamples one line of n RGBA pixels, spaced step bytes apart in
+// in, to m pixels spaced outStep bytes apart in out
+func boxFilter(in []byte, step, n int, out []byte, outStep, m int) {
+	for i := 0; i < m; i++ {
+		start := i * n / m
+		end := (i + 1) * n / m
+		if end <= start {
+			end = start + 1
+		}
+
+		var sum [4]uint32
+		for j := start; j < end; j++ {
+			p := in[j*step : j*step+4]
+			sum[0] += uint32(p[0])
+			sum[1] += uint32(p[1])
+			sum[2] += uint32(p[2])
+			sum[3] += uint32(p[3])
+		}
+
+		count := uint32(end - start)
+		q := out[i*outStep : i*outStep+4]
+		for c := range 4 {
+			q[c] = uint8((sum[c] + count/2) / count)
+		}
+	}
+}
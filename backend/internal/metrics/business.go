@@ -0,0 +1,168 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultMaxStoreLabels is how many stores get a label of their own when
+// METRICS_MAX_STORES is unset
+const DefaultMaxStoreLabels = 500
+
+const (
+	// OtherStores is the store label shared by stores past the cap
+	OtherStores = "other"
+	// UnknownStore labels what happened before a store could be told, such
+	// as a webhook with a bad signature
+	UnknownStore = "unknown"
+)
+
+// Config configures the business metrics
+type Config struct {
+	// MaxStoreLabels caps the stores labelled by ID in each process. Stores
+	// seen after the cap is reached share OtherStores, so one platform with
+	// many stores can't grow the series without bound.
+	MaxStoreLabels int
+}
+
+var (
+	OrdersCreatedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "orders_created_total",
+			Help: "Orders placed, not counting imported ones",
+		},
+		[]string{"store"},
+	)
+
+	OrderRevenueCentsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "order_revenue_cents_total",
+			Help: "Totals of placed orders in the minor unit of their currency",
+		},
+		[]string{"store", "currency"},
+	)
+
+	PaymentFailuresTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "payment_failures_total",
+			Help: "Charges declined or otherwise failed",
+		},
+		[]string{"store"},
+	)
+
+	WebhookFailuresTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "webhook_failures_total",
+			Help: "Incoming webhooks rejected or not applied",
+		},
+		[]string{"store", "provider"},
+	)
+
+	activeCartsDesc = prometheus.NewDesc(
+		"active_carts",
+		"Checkouts holding stock that are not committed, released or expired",
+		[]string{"store"}, nil,
+	)
+)
+
+// stores hands out store labels under the cap
+var stores = &storeLabels{max: DefaultMaxStoreLabels, seen: map[uuid.UUID]struct{}{}}
+
+type storeLabels struct {
+	mu   sync.Mutex
+	max  int
+	seen map[uuid.UUID]struct{}
+}
+
+func (s *storeLabels) label(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return UnknownStore
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.seen[id]; !ok {
+		if len(s.seen) >= s.max {
+			return OtherStores
+		}
+		s.seen[id] = struct{}{}
+	}
+	return id.String()
+}
+
+// Configure applies cfg. It is called once at startup, before any store
+// is labelled.
+func Configure(cfg Config) {
+	stores.mu.Lock()
+	defer stores.mu.Unlock()
+	if cfg.MaxStoreLabels > 0 {
+		stores.max = cfg.MaxStoreLabels
+	}
+}
+
+// StoreLabel is the store label of id: its ID while under the cap,
+// OtherStores past it, and UnknownStore for uuid.Nil
+func StoreLabel(id uuid.UUID) string {
+	return stores.label(id)
+}
+
+// OrderPlaced counts an order and its total
+func OrderPlaced(storeID uuid.UUID, currency string, totalCents int64) {
+	store := StoreLabel(storeID)
+	OrdersCreatedTotal.WithLabelValues(store).Inc()
+	OrderRevenueCentsTotal.WithLabelValues(store, currency).Add(float64(totalCents))
+}
+
+// PaymentFailed counts a failed charge
+func PaymentFailed(storeID uuid.UUID) {
+	PaymentFailuresTotal.WithLabelValues(StoreLabel(storeID)).Inc()
+}
+
+// WebhookFailed counts an incoming webhook from provider that was
+// rejected or could not be applied. storeID is uuid.Nil when the webhook
+// failed before its store was known.
+func WebhookFailed(storeID uuid.UUID, provider string) {
+	WebhookFailuresTotal.WithLabelValues(StoreLabel(storeID), provider).Inc()
+}
+
+// StoreCount is a count for one store
+type StoreCount struct {
+	StoreID uuid.UUID
+	Count   int64
+}
+
+// activeCarts reads the active cart gauge from the database at scrape
+// time, so every process reports the same value however carts come and go
+type activeCarts struct {
+	load func(ctx context.Context) ([]StoreCount, error)
+}
+
+// NewActiveCartsCollector reports the active_carts gauge from load
+func NewActiveCartsCollector(load func(ctx context.Context) ([]StoreCount, error)) prometheus.Collector {
+	return &activeCarts{load: load}
+}
+
+func (c *activeCarts) Describe(ch chan<- *prometheus.Desc) {
+	ch <- activeCartsDesc
+}
+
+func (c *activeCarts) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	counts, err := c.load(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(activeCartsDesc, err)
+		return
+	}
+	byLabel := map[string]float64{}
+	for _, sc := range counts {
+		byLabel[StoreLabel(sc.StoreID)] += float64(sc.Count)
+	}
+	for store, n := range byLabel {
+		ch <- prometheus.MustNewConstMetric(activeCartsDesc, prometheus.GaugeValue, n, store)
+	}
+}
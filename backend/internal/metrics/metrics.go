@@ -32,4 +32,5 @@ var (
 
 func Register(reg prometheus.Registerer) {
 	reg.MustRegister(HTTPRequestsTotal, HTTPDurationSeconds, HTTPResponseSizeBytes)
+	reg.MustRegister(OrdersCreatedTotal, OrderRevenueCentsTotal, PaymentFailuresTotal, WebhookFailuresTotal)
 }
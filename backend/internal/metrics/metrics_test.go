@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestStoreLabels(t *testing.T) {
+	s := &storeLabels{max: 2, seen: map[uuid.UUID]struct{}{}}
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		id   uuid.UUID
+		want string
+	}{
+		{id: a, want: a.String()},
+		{id: b, want: b.String()},
+		{id: c, want: OtherStores},
+		{id: a, want: a.String()},
+		{id: uuid.Nil, want: UnknownStore},
+		{id: c, want: OtherStores},
+	}
+	for i, tt := range tests {
+		if got := s.label(tt.id); got != tt.want {
+			t.Errorf("%d: label(%s) = %q, want %q", i, tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestActiveCartsCollector(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	stores = &storeLabels{max: 1, seen: map[uuid.UUID]struct{}{}}
+	t.Cleanup(func() { stores = &storeLabels{max: DefaultMaxStoreLabels, seen: map[uuid.UUID]struct{}{}} })
+
+	c := NewActiveCartsCollector(func(context.Context) ([]StoreCount, error) {
+		return []StoreCount{{StoreID: a, Count: 3}, {StoreID: b, Count: 2}, {StoreID: uuid.New(), Count: 1}}, nil
+	})
+	want := `
+# HELP active_carts Checkouts holding stock that are not committed, released or expired
+# TYPE active_carts gauge
+active_carts{store="` + a.String() + `"} 3
+active_carts{store="other"} 3
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+
+	failing := NewActiveCartsCollector(func(context.Context) ([]StoreCount, error) {
+		return nil, errors.New("database unavailable")
+	})
+	ch := make(chan prometheus.Metric, 1)
+	failing.Collect(ch)
+	if err := (<-ch).Write(&dto.Metric{}); err == nil {
+		t.Error("failing collector reported a value")
+	}
+}
@@ -0,0 +1,112 @@
+package mfa
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 key from RFC 6238 appendix B
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		// The RFC lists 8-digit codes; these are their last 6 digits
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("Code(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+
+	if _, err := Code("not base32!", 1); err != ErrInvalidSecret {
+		t.Errorf("expected ErrInvalidSecret, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Step(now)
+	code, _ := Code(rfcSecret, current)
+	previous, _ := Code(rfcSecret, current-1)
+	stale, _ := Code(rfcSecret, current-3)
+
+	tests := []struct {
+		name     string
+		code     string
+		lastStep int64
+		wantStep int64
+		wantErr  bool
+	}{
+		{"current", code, 0, current, false},
+		{"with spaces", code[:3] + " " + code[3:], 0, current, false},
+		{"previous step within skew", previous, 0, current - 1, false},
+		{"outside skew", stale, 0, 0, true},
+		{"replayed", code, current, 0, true},
+		{"wrong length", "12345", 0, 0, true},
+		{"wrong code", "000000", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, err := Validate(rfcSecret, tt.code, now, tt.lastStep)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if step != tt.wantStep {
+				t.Errorf("Validate() step = %d, want %d", step, tt.wantStep)
+			}
+		})
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("Terminus", "ada@example.com", "ABC")
+	if !strings.HasPrefix(uri, "otpauth://totp/Terminus:ada@example.com?") {
+		t.Errorf("unexpected URI %q", uri)
+	}
+	for _, want := range []string{"secret=ABC", "issuer=Terminus", "digits=6", "period=30"} {
+		if !strings.Contains(uri, want) {
+			t.Errorf("URI %q missing %q", uri, want)
+		}
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(RecoveryCodeCount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != RecoveryCodeCount {
+		t.Fatalf("got %d codes", len(codes))
+	}
+
+	seen := map[string]bool{}
+	for _, c := range codes {
+		if len(c) != 19 || strings.Count(c, "-") != 3 {
+			t.Errorf("unexpected code format %q", c)
+		}
+		if seen[c] {
+			t.Errorf("duplicate code %q", c)
+		}
+		seen[c] = true
+	}
+
+	if got := NormalizeRecoveryCode(" 7KQ2-mx4p 9d3a-HW6E"); got != "7kq2mx4p9d3ahw6e" {
+		t.Errorf("NormalizeRecoveryCode() = %q", got)
+	}
+}
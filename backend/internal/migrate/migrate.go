@@ -0,0 +1,74 @@
+// Package migrate applies the database migrations the binaries embed, with
+// goose, so a database migrated with the goose CLI carries on where it was.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dfodeker/terminus/sql/schema"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+)
+
+// lockID keys the advisory lock held while migrating, so instances started
+// together apply each migration once
+const lockID int64 = 0x7465726d696e7573
+
+func init() {
+	goose.SetBaseFS(schema.FS)
+	if err := goose.SetDialect("postgres"); err != nil {
+		panic(err)
+	}
+}
+
+// Up applies every pending migration in order of version, including any
+// older than the newest applied one, as merged branches can leave. It stops
+// at the first that fails.
+func Up(ctx context.Context, pool *pgxpool.Pool) error {
+	return locked(ctx, pool, func(db *sql.DB) error {
+		return goose.UpContext(ctx, db, ".", goose.WithAllowMissing())
+	})
+}
+
+// Down rolls back the newest applied migration
+func Down(ctx context.Context, pool *pgxpool.Pool) error {
+	return locked(ctx, pool, func(db *sql.DB) error {
+		return goose.DownContext(ctx, db, ".")
+	})
+}
+
+// Status logs every migration with whether it has been applied
+func Status(ctx context.Context, pool *pgxpool.Pool) error {
+	db := stdlib.OpenDBFromPool(pool)
+	defer db.Close()
+	return goose.StatusContext(ctx, db, ".")
+}
+
+// locked runs fn, with pool as goose takes it, holding the migration lock.
+// The lock is a session lock on a connection of its own, so pool needs to
+// allow another for goose.
+func locked(ctx context.Context, pool *pgxpool.Pool, fn func(db *sql.DB) error) error {
+	if pool.Config().MaxConns == 1 {
+		return errors.New("migrating needs a pool of more than one connection")
+	}
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, lockID); err != nil {
+		return fmt.Errorf("take the migration lock: %w", err)
+	}
+	// Unlocked even when ctx is done, so the connection goes back to the
+	// pool without the lock
+	defer conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, lockID)
+
+	db := stdlib.OpenDBFromPool(pool)
+	defer db.Close()
+	return fn(db)
+}
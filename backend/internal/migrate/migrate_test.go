@@ -0,0 +1,25 @@
+package migrate
+
+import (
+	"math"
+	"testing"
+
+	"github.com/pressly/goose/v3"
+)
+
+// TestCollect checks goose finds every embedded migration, each with a
+// version of its own
+func TestCollect(t *testing.T) {
+	migrations, err := goose.CollectMigrations(".", 0, math.MaxInt64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no migrations collected")
+	}
+	for i, m := range migrations {
+		if i > 0 && m.Version <= migrations[i-1].Version {
+			t.Errorf("%s is out of order after %s", m.Source, migrations[i-1].Source)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Package notifications records the in-app notifications staff see, such
+// as new orders and low stock. A notification belongs to a tenant and is
+// shown to each member holding the permission its kind needs; tenants can
+// turn kinds off.
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/google/uuid"
+)
+
+// Kind is what a notification is about
+type Kind string
+
+const (
+	KindNewOrder      Kind = "order.created"
+	KindLowStock      Kind = "inventory.low_stock"
+	KindWebhookFailed Kind = "webhook.failed"
+	KindNewReview     Kind = "review.created"
+)
+
+// Kinds lists every kind, in the order preferences are shown
+var Kinds = []Kind{KindNewOrder, KindLowStock, KindWebhookFailed, KindNewReview}
+
+// permissions are what a member needs to see each kind
+var permissions = map[Kind]string{
+	KindNewOrder:      "orders:view",
+	KindLowStock:      "inventory:view",
+	KindWebhookFailed: "orders:manage",
+	KindNewReview:     "reviews:moderate",
+}
+
+// Valid reports whether k is a known kind
+func (k Kind) Valid() bool {
+	return slices.Contains(Kinds, k)
+}
+
+// Permission is what a member needs to see notifications of kind k
+func (k Kind) Permission() string {
+	return permissions[k]
+}
+
+// Visible lists the kinds a member holding granted may see, as the
+// strings the notification queries filter on
+func Visible(granted []string) []string {
+	kinds := make([]string, 0, len(Kinds))
+	for _, k := range Kinds {
+		if slices.Contains(granted, k.Permission()) {
+			kinds = append(kinds, string(k))
+		}
+	}
+	return kinds
+}
+
+// Notification is one to record
+type Notification struct {
+	TenantID uuid.UUID
+	// StoreID is the store it is about, or uuid.Nil for the whole tenant
+	StoreID uuid.UUID
+	Kind    Kind
+	Title   string
+	Body    string
+	// Data carries IDs a client can link to
+	Data map[string]any
+}
+
+// Record stores n unless its tenant has turned its kind off, reporting
+// whether it did
+func Record(ctx context.Context, q *database.Queries, n Notification) (bool, error) {
+	data := n.Data
+	if data == nil {
+		data = map[string]any{}
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return false, err
+	}
+	rows, err := q.CreateNotification(ctx, database.CreateNotificationParams{
+		TenantID: n.TenantID,
+		StoreID:  uuid.NullUUID{UUID: n.StoreID, Valid: n.StoreID != uuid.Nil},
+		Kind:     string(n.Kind),
+		Title:    n.Title,
+		Body:     n.Body,
+		Data:     encoded,
+	})
+	return rows > 0, err
+}
+
+// Preferences resolves a tenant's stored preferences into whether each
+// kind is on. Kinds without a row are on.
+func Preferences(rows []database.TenantNotificationPreference) map[Kind]bool {
+	prefs := make(map[Kind]bool, len(Kinds))
+	for _, k := range Kinds {
+		prefs[k] = true
+	}
+	for _, row := range rows {
+		if Kind(row.Kind).Valid() {
+			prefs[Kind(row.Kind)] = row.Enabled
+		}
+	}
+	return prefs
+}
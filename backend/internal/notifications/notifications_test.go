@@ -0,0 +1,83 @@
+package notifications
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/dfodeker/terminus/internal/database"
+)
+
+func TestKindValid(t *testing.T) {
+	for _, k := range Kinds {
+		if !k.Valid() {
+			t.Errorf("%q.Valid() = false", k)
+		}
+		if k.Permission() == "" {
+			t.Errorf("%q has no permission", k)
+		}
+	}
+	if Kind("order.deleted").Valid() || Kind("").Valid() {
+		t.Error("Valid() accepted an unknown kind")
+	}
+}
+
+func TestVisible(t *testing.T) {
+	tests := []struct {
+		name    string
+		granted []string
+		want    []string
+	}{
+		{name: "nothing", granted: nil, want: []string{}},
+		{name: "orders view", granted: []string{"orders:view"}, want: []string{"order.created"}},
+		{
+			name:    "orders manage and inventory",
+			granted: []string{"inventory:view", "orders:manage", "orders:view"},
+			want:    []string{"order.created", "inventory.low_stock", "webhook.failed"},
+		},
+		{name: "unrelated", granted: []string{"products:view", "tenant:view"}, want: []string{}},
+		{name: "reviews", granted: []string{"reviews:moderate"}, want: []string{"review.created"}},
+	}
+	for _, tt := range tests {
+		if got := Visible(tt.granted); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: Visible(%v) = %v, want %v", tt.name, tt.granted, got, tt.want)
+		}
+	}
+}
+
+func TestPreferences(t *testing.T) {
+	tests := []struct {
+		name string
+		rows []database.TenantNotificationPreference
+		want map[Kind]bool
+	}{
+		{
+			name: "no rows",
+			want: map[Kind]bool{KindNewOrder: true, KindLowStock: true, KindWebhookFailed: true, KindNewReview: true},
+		},
+		{
+			name: "some off",
+			rows: []database.TenantNotificationPreference{
+				{Kind: "inventory.low_stock", Enabled: false},
+				{Kind: "review.created", Enabled: true},
+			},
+			want: map[Kind]bool{KindNewOrder: true, KindLowStock: false, KindWebhookFailed: true, KindNewReview: true},
+		},
+		{
+			name: "unknown kind ignored",
+			rows: []database.TenantNotificationPreference{{Kind: "order.deleted", Enabled: false}},
+			want: map[Kind]bool{KindNewOrder: true, KindLowStock: true, KindWebhookFailed: true, KindNewReview: true},
+		},
+	}
+	for _, tt := range tests {
+		got := Preferences(tt.rows)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: Preferences() = %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for k, want := range tt.want {
+			if got[k] != want {
+				t.Errorf("%s: Preferences()[%q] = %v, want %v", tt.name, k, got[k], want)
+			}
+		}
+	}
+}
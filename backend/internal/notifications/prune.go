@@ -0,0 +1,83 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+)
+
+// DefaultRetention is how long notifications are kept when
+// NOTIFICATION_RETENTION is unset
+const DefaultRetention = 90 * 24 * time.Hour
+
+// PrunerConfig configures a Pruner
+type PrunerConfig struct {
+	Retention time.Duration
+	Interval  time.Duration
+	// BatchSize bounds each delete
+	BatchSize int
+	Logger    *slog.Logger
+}
+
+// Pruner deletes notifications older than the retention window, along with
+// their read state. Running several at once is harmless.
+type Pruner struct {
+	db  *database.Queries
+	cfg PrunerConfig
+}
+
+// NewPruner creates a pruner over db
+func NewPruner(db *database.Queries, cfg PrunerConfig) *Pruner {
+	if cfg.Retention <= 0 {
+		cfg.Retention = DefaultRetention
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 1000
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &Pruner{db: db, cfg: cfg}
+}
+
+// Run prunes once an interval until ctx is cancelled
+func (p *Pruner) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := p.Prune(ctx, time.Now().Add(-p.cfg.Retention))
+		if err != nil && ctx.Err() == nil {
+			p.cfg.Logger.Error("notification prune failed", "error", err)
+		} else if n > 0 {
+			p.cfg.Logger.Info("notifications pruned", "deleted", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Prune deletes every notification created before cutoff, a batch at a time, and
+// returns how many were deleted
+func (p *Pruner) Prune(ctx context.Context, cutoff time.Time) (int64, error) {
+	var total int64
+	for {
+		n, err := p.db.PruneNotifications(ctx, database.PruneNotificationsParams{
+			Before:   cutoff,
+			RowLimit: int32(p.cfg.BatchSize),
+		})
+		total += n
+		if err != nil || n < int64(p.cfg.BatchSize) {
+			return total, err
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Package oauth signs users in through an outside account: Google, GitHub
+// or any OpenID Connect provider. Each sign-in is an authorization code
+// flow with PKCE; the provider sends the user back to the app with a code,
+// which is traded for who they are.
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/tracing"
+)
+
+// Provider names. A generic OIDC provider is named by its config.
+const (
+	ProviderGoogle = "google"
+	ProviderGitHub = "github"
+)
+
+// ErrRejected is returned when the provider refused the code or sent back
+// an identity that didn't check out. Anything else is a failure to reach
+// the provider.
+var ErrRejected = errors.New("sign-in rejected by provider")
+
+// Identity is who signed in at the provider
+type Identity struct {
+	// Subject is the provider's stable ID for the account; emails can
+	// change hands
+	Subject string
+	Email   string
+	// EmailVerified is whether the provider checked the user owns Email
+	EmailVerified bool
+	Name          string
+	// Claims are every claim in the ID token, for tenant SSO to map
+	// attributes from; GitHub has none
+	Claims map[string]any
+}
+
+// AuthRequest is what a sign-in sent to the provider is checked against
+// when it comes back
+type AuthRequest struct {
+	State string
+	// Nonce is bound into the ID token by OIDC providers
+	Nonce string
+	// Verifier is the PKCE code verifier; the provider is sent its
+	// challenge
+	Verifier string
+}
+
+// NewAuthRequest returns a fresh state, nonce and verifier
+func NewAuthRequest() (AuthRequest, error) {
+	var req AuthRequest
+	for _, s := range []*string{&req.State, &req.Nonce, &req.Verifier} {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return AuthRequest{}, err
+		}
+		*s = base64.RawURLEncoding.EncodeToString(b)
+	}
+	return req, nil
+}
+
+// Challenge is the S256 PKCE challenge for the verifier
+func (r AuthRequest) Challenge() string {
+	sum := sha256.Sum256([]byte(r.Verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// Provider signs users in
+type Provider interface {
+	// AuthURL is where the user is sent to sign in
+	AuthURL(ctx context.Context, req AuthRequest) (string, error)
+	// Exchange trades the code the provider sent back for the identity
+	// that signed in
+	Exchange(ctx context.Context, code string, req AuthRequest) (Identity, error)
+}
+
+// Config holds the credentials of each provider. A provider without
+// credentials is not offered.
+type Config struct {
+	// RedirectURL is the app's page providers send users back to. It has
+	// to be registered with each provider.
+	RedirectURL string
+	Google      ClientConfig
+	GitHub      ClientConfig
+	// OIDC is any other OpenID Connect provider, such as Okta or
+	// Microsoft Entra ID
+	OIDC OIDCConfig
+}
+
+// ClientConfig is the app's client at a provider
+type ClientConfig struct {
+	ClientID     string
+	ClientSecret string
+}
+
+// Providers maps provider names to their provider
+type Providers map[string]Provider
+
+// New returns the providers cfg has credentials for
+func New(cfg Config) Providers {
+	providers := Providers{}
+	if cfg.Google.ClientID != "" {
+		providers[ProviderGoogle] = NewOIDC(OIDCConfig{
+			Name:         ProviderGoogle,
+			Issuer:       "https://accounts.google.com",
+			ClientConfig: cfg.Google,
+		}, cfg.RedirectURL)
+	}
+	if cfg.GitHub.ClientID != "" {
+		providers[ProviderGitHub] = NewGitHub(GitHubConfig{ClientConfig: cfg.GitHub}, cfg.RedirectURL)
+	}
+	if cfg.OIDC.ClientID != "" {
+		providers[cfg.OIDC.Name] = NewOIDC(cfg.OIDC, cfg.RedirectURL)
+	}
+	return providers
+}
+
+// Lookup returns the named provider
+func (p Providers) Lookup(name string) (Provider, bool) {
+	provider, ok := p[name]
+	return provider, ok
+}
+
+// Names lists the provider names in order
+func (p Providers) Names() []string {
+	names := make([]string, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func newClient() *http.Client {
+	return &http.Client{Timeout: 15 * time.Second, Transport: tracing.Transport(nil)}
+}
@@ -0,0 +1,219 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// fakeOIDC is an OpenID Connect provider that accepts the code "good"
+// and answers it with the claims in idToken, signed by key
+type fakeOIDC struct {
+	t       *testing.T
+	srv     *httptest.Server
+	key     *rsa.PrivateKey
+	idToken jwt.MapClaims
+	// verifier is the PKCE verifier the token request must carry
+	verifier string
+}
+
+func newFakeOIDC(t *testing.T) *fakeOIDC {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeOIDC{t: t, key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 f.srv.URL,
+			"authorization_endpoint": f.srv.URL + "/authorize",
+			"token_endpoint":         f.srv.URL + "/token",
+			"jwks_uri":               f.srv.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		b64 := base64.RawURLEncoding.EncodeToString
+		json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "k1",
+			"use": "sig",
+			"n":   b64(key.N.Bytes()),
+			"e":   b64(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("code") != "good" || r.Form.Get("code_verifier") != f.verifier || r.Form.Get("client_secret") != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, f.idToken)
+		token.Header["kid"] = "k1"
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Error(err)
+		}
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "at", "id_token": signed})
+	})
+	f.srv = httptest.NewServer(mux)
+	t.Cleanup(f.srv.Close)
+	return f
+}
+
+func TestOIDC(t *testing.T) {
+	f := newFakeOIDC(t)
+	p := NewOIDC(OIDCConfig{Name: "okta", Issuer: f.srv.URL, ClientConfig: ClientConfig{ClientID: "app", ClientSecret: "secret"}}, "https://app.example.com/callback")
+
+	req, err := NewAuthRequest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.verifier = req.Verifier
+	authURL, err := p.AuthURL(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q := u.Query(); q.Get("state") != req.State || q.Get("nonce") != req.Nonce || q.Get("code_challenge") != req.Challenge() || q.Get("client_id") != "app" {
+		t.Errorf("AuthURL() = %s, missing the request", authURL)
+	}
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":            f.srv.URL,
+			"aud":            "app",
+			"sub":            "00u1",
+			"exp":            time.Now().Add(time.Hour).Unix(),
+			"nonce":          req.Nonce,
+			"email":          "ada@example.com",
+			"email_verified": true,
+			"name":           "Ada",
+		}
+	}
+	tests := []struct {
+		name    string
+		code    string
+		claims  func(jwt.MapClaims)
+		want    Identity
+		wantErr error
+	}{
+		{
+			name: "signs in",
+			code: "good",
+			want: Identity{Subject: "00u1", Email: "ada@example.com", EmailVerified: true, Name: "Ada"},
+		},
+		{
+			name:   "email verified as a string",
+			code:   "good",
+			claims: func(c jwt.MapClaims) { c["email_verified"] = "true" },
+			want:   Identity{Subject: "00u1", Email: "ada@example.com", EmailVerified: true, Name: "Ada"},
+		},
+		{
+			name:   "unverified email",
+			code:   "good",
+			claims: func(c jwt.MapClaims) { delete(c, "email_verified") },
+			want:   Identity{Subject: "00u1", Email: "ada@example.com", Name: "Ada"},
+		},
+		{name: "bad code", code: "bad", wantErr: ErrRejected},
+		{name: "replayed nonce", code: "good", claims: func(c jwt.MapClaims) { c["nonce"] = "other" }, wantErr: ErrRejected},
+		{name: "token for another app", code: "good", claims: func(c jwt.MapClaims) { c["aud"] = "other" }, wantErr: ErrRejected},
+		{name: "expired token", code: "good", claims: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }, wantErr: ErrRejected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.idToken = valid()
+			if tt.claims != nil {
+				tt.claims(f.idToken)
+			}
+			got, err := p.Exchange(context.Background(), tt.code, req)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Exchange() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Claims["sub"] != "00u1" || got.Claims["name"] != "Ada" {
+				t.Errorf("Exchange() claims = %v", got.Claims)
+			}
+			got.Claims = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Exchange() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitHub(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		// GitHub answers a bad code with a 200
+		if r.Form.Get("code") != "good" {
+			json.NewEncoder(w).Encode(map[string]string{"error": "bad_verification_code"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "gho_1"})
+	})
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer gho_1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"id": 42, "login": "ada", "email": "public@example.com"})
+	})
+	mux.HandleFunc("/user/emails", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]map[string]any{
+			{"email": "old@example.com", "primary": false, "verified": true},
+			{"email": "ada@example.com", "primary": true, "verified": true},
+		})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	p := NewGitHub(GitHubConfig{ClientConfig: ClientConfig{ClientID: "app", ClientSecret: "secret"}, BaseURL: srv.URL, APIURL: srv.URL}, "https://app.example.com/callback")
+
+	req, err := NewAuthRequest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	authURL, err := p.AuthURL(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(authURL, srv.URL+"/login/oauth/authorize?") || !strings.Contains(authURL, "state="+req.State) {
+		t.Errorf("AuthURL() = %s", authURL)
+	}
+
+	got, err := p.Exchange(context.Background(), "good", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Identity{Subject: "42", Email: "ada@example.com", EmailVerified: true, Name: "ada"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Exchange() = %+v, want %+v", got, want)
+	}
+	if _, err := p.Exchange(context.Background(), "bad", req); !errors.Is(err, ErrRejected) {
+		t.Errorf("Exchange() with a bad code error = %v, want ErrRejected", err)
+	}
+}
@@ -0,0 +1,321 @@
+package oauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// discoveryTTL is how long a provider's discovery document and keys are
+// trusted before they are fetched again
+const discoveryTTL = time.Hour
+
+// OIDCConfig configures an OpenID Connect provider
+type OIDCConfig struct {
+	// Name is the provider's name in the API
+	Name string
+	// Issuer is the provider's issuer URL; its discovery document is at
+	// Issuer/.well-known/openid-configuration
+	Issuer string
+	ClientConfig
+}
+
+type oidcProvider struct {
+	cfg         OIDCConfig
+	redirectURL string
+	client      *http.Client
+
+	mu           sync.Mutex
+	discovery    discovery
+	discoveredAt time.Time
+	keys         map[string]crypto.PublicKey
+	keysAt       time.Time
+}
+
+// discovery is the part of a discovery document sign-in needs
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// NewOIDC returns a provider that signs users in with OpenID Connect,
+// reading its endpoints from the issuer's discovery document
+func NewOIDC(cfg OIDCConfig, redirectURL string) Provider {
+	cfg.Issuer = strings.TrimSuffix(cfg.Issuer, "/")
+	return &oidcProvider{cfg: cfg, redirectURL: redirectURL, client: newClient()}
+}
+
+func (p *oidcProvider) AuthURL(ctx context.Context, req AuthRequest) (string, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.cfg.ClientID},
+		"redirect_uri":          {p.redirectURL},
+		"scope":                 {"openid email profile"},
+		"state":                 {req.State},
+		"nonce":                 {req.Nonce},
+		"code_challenge":        {req.Challenge()},
+		"code_challenge_method": {"S256"},
+	}
+	return withQuery(d.AuthorizationEndpoint, q), nil
+}
+
+// idClaims are the ID token claims sign-in reads
+type idClaims struct {
+	jwt.RegisteredClaims
+	Nonce string `json:"nonce"`
+	Email string `json:"email"`
+	// EmailVerified is a boolean, though some providers send a string
+	EmailVerified any    `json:"email_verified"`
+	Name          string `json:"name"`
+}
+
+func (p *oidcProvider) Exchange(ctx context.Context, code string, req AuthRequest) (Identity, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return Identity{}, err
+	}
+	tok, err := exchangeCode(ctx, p.client, d.TokenEndpoint, p.cfg.ClientConfig, p.redirectURL, code, req)
+	if err != nil {
+		return Identity{}, err
+	}
+	if tok.IDToken == "" {
+		return Identity{}, fmt.Errorf("%w: no ID token", ErrRejected)
+	}
+
+	var claims idClaims
+	_, err = jwt.ParseWithClaims(tok.IDToken, &claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(d.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: ID token: %v", ErrRejected, err)
+	}
+	if claims.Nonce != req.Nonce {
+		return Identity{}, fmt.Errorf("%w: ID token nonce does not match", ErrRejected)
+	}
+	if claims.Subject == "" {
+		return Identity{}, fmt.Errorf("%w: ID token has no subject", ErrRejected)
+	}
+	id := Identity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified == true || claims.EmailVerified == "true",
+		Name:          claims.Name,
+	}
+	// The token checked out, so its payload can be read as it is
+	if parts := strings.Split(tok.IDToken, "."); len(parts) == 3 {
+		if payload, err := base64.RawURLEncoding.DecodeString(parts[1]); err == nil {
+			json.Unmarshal(payload, &id.Claims)
+		}
+	}
+	return id, nil
+}
+
+// discover returns the issuer's discovery document, fetching it when the
+// cached one is stale
+func (p *oidcProvider) discover(ctx context.Context) (discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.discoveredAt.IsZero() && time.Since(p.discoveredAt) < discoveryTTL {
+		return p.discovery, nil
+	}
+
+	var d discovery
+	if err := getJSON(ctx, p.client, p.cfg.Issuer+"/.well-known/openid-configuration", "", &d); err != nil {
+		return discovery{}, fmt.Errorf("oauth: %s discovery: %w", p.cfg.Name, err)
+	}
+	if strings.TrimSuffix(d.Issuer, "/") != p.cfg.Issuer {
+		return discovery{}, fmt.Errorf("oauth: %s discovery: issuer is %q, want %q", p.cfg.Name, d.Issuer, p.cfg.Issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return discovery{}, fmt.Errorf("oauth: %s discovery: missing endpoints", p.cfg.Name)
+	}
+	p.discovery, p.discoveredAt = d, time.Now()
+	return d, nil
+}
+
+// key returns the provider's signing key named kid. Keys are fetched again
+// when kid is unknown, as providers rotate them, but no more than once a
+// minute so forged kids can't hammer the provider.
+func (p *oidcProvider) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if k, ok := p.keys[kid]; ok && time.Since(p.keysAt) < discoveryTTL {
+		return k, nil
+	}
+	if time.Since(p.keysAt) < time.Minute {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := getJSON(ctx, p.client, p.discovery.JWKSURI, "", &set); err != nil {
+		return nil, fmt.Errorf("oauth: %s keys: %w", p.cfg.Name, err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if pub, err := k.publicKey(); err == nil {
+			keys[k.Kid] = pub
+		}
+	}
+	p.keys, p.keysAt = keys, time.Now()
+	if k, ok := keys[kid]; ok {
+		return k, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// jwk is a public key in a provider's key set
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	b64 := base64.RawURLEncoding.DecodeString
+	switch k.Kty {
+	case "RSA":
+		n, err := b64(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := b64(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := b64(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := b64(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+// tokenResponse is a provider's answer to a code exchange
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// exchangeCode trades an authorization code for tokens at endpoint
+func exchangeCode(ctx context.Context, client *http.Client, endpoint string, cc ClientConfig, redirectURL, code string, req AuthRequest) (tokenResponse, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURL},
+		"client_id":     {cc.ClientID},
+		"client_secret": {cc.ClientSecret},
+		"code_verifier": {req.Verifier},
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return tokenResponse{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpReq.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return tokenResponse{}, fmt.Errorf("oauth: token: %w", err)
+	}
+	defer resp.Body.Close()
+	var tok tokenResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&tok); err != nil {
+		return tokenResponse{}, fmt.Errorf("oauth: token: %s: %w", resp.Status, err)
+	}
+	// GitHub answers a bad code with a 200 and an error
+	if tok.Error != "" {
+		return tokenResponse{}, fmt.Errorf("%w: %s: %s", ErrRejected, tok.Error, tok.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return tokenResponse{}, fmt.Errorf("oauth: token: %s", resp.Status)
+	}
+	return tok, nil
+}
+
+// getJSON fetches url into out, with the access token when one is given
+func getJSON(ctx context.Context, client *http.Client, url, accessToken string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.New(resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out)
+}
+
+// withQuery adds q to endpoint, which may already have a query
+func withQuery(endpoint string, q url.Values) string {
+	sep := "?"
+	if strings.Contains(endpoint, "?") {
+		sep = "&"
+	}
+	return endpoint + sep + q.Encode()
+}
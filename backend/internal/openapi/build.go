@@ -0,0 +1,487 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/go-chi/chi/v5"
+)
+
+// bearerScheme is the security scheme of operations behind Spec.Auth
+const bearerScheme = "bearerAuth"
+
+// IdempotencyKeyHeader is the header parameter of operations behind
+// Spec.Idempotent
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// Route is one method on one path of a router
+type Route struct {
+	Method  string
+	Pattern string
+	// Handler and Middlewares are function names, as funcName gives them
+	Handler     string
+	Middlewares []string
+}
+
+// behind reports whether any of middlewares is in front of the route
+func (r Route) behind(middlewares []string) bool {
+	return slices.ContainsFunc(r.Middlewares, func(m string) bool { return slices.Contains(middlewares, m) })
+}
+
+// Routes lists the routes of r. Handlers mounted under a wildcard, such
+// as file servers, aren't operations and are left out.
+//
+// chi.Walk isn't used because it drops the middlewares of a group a
+// subrouter is mounted in, and those are what say a route needs a token.
+func Routes(r chi.Routes) ([]Route, error) {
+	var routes []Route
+	walk(r, "", nil, &routes)
+	return routes, nil
+}
+
+func walk(r chi.Routes, prefix string, middlewares []string, routes *[]Route) {
+	middlewares = append(slices.Clone(middlewares), funcNames(r.Middlewares())...)
+	for _, route := range r.Routes() {
+		if route.SubRoutes != nil {
+			sub := middlewares
+			// The mount is the same handler for every method
+			for _, h := range route.Handlers {
+				if chain, ok := h.(*chi.ChainHandler); ok {
+					sub = append(slices.Clone(middlewares), funcNames(chain.Middlewares)...)
+				}
+				break
+			}
+			walk(route.SubRoutes, prefix+route.Pattern, sub, routes)
+			continue
+		}
+
+		pattern := strings.ReplaceAll(prefix+route.Pattern, "/*/", "/")
+		if strings.Contains(pattern, "*") {
+			continue
+		}
+		methods := make([]string, 0, len(route.Handlers))
+		for method := range route.Handlers {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			handler := route.Handlers[method]
+			found := Route{Method: method, Pattern: pattern, Middlewares: middlewares}
+			if chain, ok := handler.(*chi.ChainHandler); ok {
+				handler = chain.Endpoint
+				found.Middlewares = append(slices.Clone(middlewares), funcNames(chain.Middlewares)...)
+			}
+			found.Handler = funcName(handler)
+			*routes = append(*routes, found)
+		}
+	}
+}
+
+func funcNames(middlewares chi.Middlewares) []string {
+	names := make([]string, len(middlewares))
+	for i, m := range middlewares {
+		names[i] = funcName(m)
+	}
+	return names
+}
+
+// Annotation describes the route at Method and Path. Everything but the
+// route is optional; what is left out is worked out from the route.
+type Annotation struct {
+	Method string
+	// Path is as in the document, without regular expressions in its
+	// parameters or a trailing slash
+	Path string
+
+	ID          string
+	Summary     string
+	Description string
+	Tags        []string
+	// Query lists the query parameters the operation reads
+	Query []Parameter
+	// Request and Response are values of the types of the bodies, such as
+	// LoginResponse{}. A nil Response documents a response without a body.
+	Request  any
+	Response any
+	// Status is the status of a successful response, 200 if not set
+	Status int
+}
+
+func (op Annotation) key() string {
+	return op.Method + " " + op.Path
+}
+
+// Spec is what a document is built from besides the routes
+type Spec struct {
+	Info    Info
+	Servers []Server
+	// Prefix is left out of paths when picking their tag
+	Prefix string
+	// Scopes are the path segments other resources are nested under, such
+	// as tenants in /tenants/{tenantID}/products. A nested path is tagged
+	// with the resource rather than the scope.
+	Scopes []string
+	// Tags maps a resource to the tag it is listed under, when that isn't
+	// the resource itself
+	Tags map[string]string
+	// Auth names the middlewares that require a bearer token
+	Auth []string
+	// Idempotent names the middlewares that replay a POST or PUT retried
+	// with the same Idempotency-Key
+	Idempotent []string
+	// Annotations describe routes. Each has to match one.
+	Annotations []Annotation
+}
+
+// Build returns the document of routes. It fails if an annotation matches
+// no route, or if two operations end up with the same ID.
+func (s Spec) Build(routes []Route) (*Document, error) {
+	schemas := newSchemas()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    s.Info,
+		Servers: s.Servers,
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			SecuritySchemes: map[string]SecurityScheme{
+				bearerScheme: {
+					Type:         "http",
+					Scheme:       "bearer",
+					BearerFormat: "JWT",
+					Description:  "An access token, or an API key",
+				},
+			},
+		},
+	}
+	problemSchema := schemas.of(reflect.TypeOf(problem.Problem{}))
+
+	annotations := map[string]Annotation{}
+	for _, op := range s.Annotations {
+		if _, dup := annotations[op.key()]; dup {
+			return nil, fmt.Errorf("route %s is annotated twice", op.key())
+		}
+		annotations[op.key()] = op
+	}
+
+	var errs []error
+	ids := map[string]string{}
+	tags := map[string]bool{}
+	for _, route := range s.name(dedupe(routes)) {
+		path := DocumentPath(route.Pattern)
+		key := route.Method + " " + path
+		ann := annotations[key]
+		delete(annotations, key)
+
+		op := ann
+		if op.ID == "" {
+			op.ID = handlerID(route.name)
+		}
+		if op.Summary == "" {
+			op.Summary = summary(route.name)
+		}
+		if op.Tags == nil {
+			op.Tags = []string{s.tag(path)}
+		}
+		if op.Status == 0 {
+			op.Status = http.StatusOK
+		}
+		if other, dup := ids[op.ID]; dup {
+			errs = append(errs, fmt.Errorf("operation ID %s is used by both %s and %s", op.ID, other, key))
+		}
+		ids[op.ID] = key
+		for _, t := range op.Tags {
+			tags[t] = true
+		}
+
+		docOp := &Operation{
+			OperationID: op.ID,
+			Summary:     op.Summary,
+			Description: op.Description,
+			Tags:        op.Tags,
+			Parameters:  pathParameters(path),
+		}
+		for _, q := range op.Query {
+			q.In = "query"
+			if q.Schema == nil {
+				q.Schema = &Schema{Type: "string"}
+			}
+			docOp.Parameters = append(docOp.Parameters, q)
+		}
+		if op.Request != nil {
+			docOp.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{"application/json": {Schema: schemas.of(reflect.TypeOf(op.Request))}},
+			}
+		}
+		success := &Response{Description: http.StatusText(op.Status)}
+		if op.Response != nil {
+			success.Content = map[string]MediaType{"application/json": {Schema: schemas.of(reflect.TypeOf(op.Response))}}
+		}
+		docOp.Responses = map[string]*Response{
+			strconv.Itoa(op.Status): success,
+			"default": {
+				Description: "An error, as RFC 7807 problem details",
+				Content:     map[string]MediaType{problem.ContentType: {Schema: problemSchema}},
+			},
+		}
+		if route.behind(s.Auth) {
+			docOp.Security = []SecurityRequirement{{bearerScheme: {}}}
+		}
+		if route.behind(s.Idempotent) && (route.Method == http.MethodPost || route.Method == http.MethodPut) {
+			docOp.Parameters = append(docOp.Parameters, Parameter{
+				Name:        IdempotencyKeyHeader,
+				In:          "header",
+				Description: "Retries with the same key get the first response instead of repeating the request",
+				Schema:      &Schema{Type: "string"},
+			})
+		}
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = PathItem{}
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = docOp
+	}
+
+	stale := make([]string, 0, len(annotations))
+	for key := range annotations {
+		stale = append(stale, key)
+	}
+	sort.Strings(stale)
+	for _, key := range stale {
+		errs = append(errs, fmt.Errorf("annotation %s has no route", key))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	for t := range tags {
+		doc.Tags = append(doc.Tags, Tag{Name: t})
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+	doc.Components.Schemas = schemas.components
+	return doc, nil
+}
+
+// namedRoute is a route with the name of its operation, worked out from
+// its handler's name
+type namedRoute struct {
+	Route
+	name string
+}
+
+// dedupe drops routes registered twice, such as a path with and without
+// its trailing slash
+func dedupe(routes []Route) []Route {
+	seen := map[string]bool{}
+	var out []Route
+	for _, route := range routes {
+		key := route.Method + " " + DocumentPath(route.Pattern)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, route)
+	}
+	return out
+}
+
+// name names the operations of routes after their handlers. Routes that
+// share a handler, such as those built by one handler factory, are told
+// apart by the path segments they don't have in common, leaving out
+// scopes unless they are what differs, and then by method.
+func (s Spec) name(routes []Route) []namedRoute {
+	named := make([]namedRoute, len(routes))
+	byHandler := map[string][]*namedRoute{}
+	for i, route := range routes {
+		named[i] = namedRoute{Route: route, name: handlerName(route.Handler)}
+		byHandler[named[i].name] = append(byHandler[named[i].name], &named[i])
+	}
+
+	for _, group := range byHandler {
+		if len(group) == 1 {
+			continue
+		}
+		counts := map[string]int{}
+		for _, nr := range group {
+			for _, seg := range literalSegments(nr.Pattern) {
+				counts[seg]++
+			}
+		}
+		suffix := func(nr *namedRoute, scopes bool) string {
+			var out string
+			for _, seg := range literalSegments(nr.Pattern) {
+				if counts[seg] < len(group) && (scopes || !slices.Contains(s.Scopes, seg)) {
+					out += exportName(camel(seg))
+				}
+			}
+			return out
+		}
+
+		names := map[string]int{}
+		for _, nr := range group {
+			names[nr.name+suffix(nr, false)]++
+		}
+		full := map[string]int{}
+		for _, nr := range group {
+			if names[nr.name+suffix(nr, false)] > 1 {
+				nr.name += suffix(nr, true)
+			} else {
+				nr.name += suffix(nr, false)
+			}
+			full[nr.name]++
+		}
+		for _, nr := range group {
+			if full[nr.name] > 1 {
+				nr.name += exportName(strings.ToLower(nr.Method))
+			}
+		}
+	}
+	return named
+}
+
+// tag is the resource path is under: its first segment after the prefix,
+// or the one after a scope's parameter
+func (s Spec) tag(path string) string {
+	segs := strings.Split(strings.Trim(strings.TrimPrefix(path, s.Prefix), "/"), "/")
+	for i := 0; i < len(segs); i++ {
+		if slices.Contains(s.Scopes, segs[i]) && i+2 < len(segs) && isParam(segs[i+1]) {
+			i++
+			continue
+		}
+		if !isParam(segs[i]) && segs[i] != "" {
+			if tag, ok := s.Tags[segs[i]]; ok {
+				return tag
+			}
+			return segs[i]
+		}
+	}
+	return "default"
+}
+
+var paramPattern = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// DocumentPath turns a chi pattern into an OpenAPI path, dropping the
+// regular expressions of its parameters and any trailing slash
+func DocumentPath(pattern string) string {
+	path := paramPattern.ReplaceAllString(pattern, "{$1}")
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return path
+}
+
+func pathParameters(path string) []Parameter {
+	var params []Parameter
+	for _, m := range paramPattern.FindAllStringSubmatch(path, -1) {
+		params = append(params, Parameter{
+			Name:     m[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+	return params
+}
+
+func isParam(seg string) bool {
+	return strings.HasPrefix(seg, "{")
+}
+
+func literalSegments(pattern string) []string {
+	var segs []string
+	for _, seg := range strings.Split(DocumentPath(pattern), "/") {
+		if seg != "" && !isParam(seg) {
+			segs = append(segs, seg)
+		}
+	}
+	return segs
+}
+
+// funcClosure matches what the runtime appends to the names of closures
+// and method values
+var funcClosure = regexp.MustCompile(`(\.func\d+)?(\.\d+)*(-fm)?$`)
+
+// funcName is the name of the function f is, without its package or
+// receiver, such as handlerLogin for cfg.handlerLogin or for a closure
+// it returns. Values that aren't functions are named by their type.
+func funcName(f any) string {
+	v := reflect.ValueOf(f)
+	if v.Kind() != reflect.Func {
+		return reflect.TypeOf(f).String()
+	}
+	fn := runtime.FuncForPC(v.Pointer())
+	if fn == nil {
+		return ""
+	}
+	name := funcClosure.ReplaceAllString(fn.Name(), "")
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// handlerName drops the handler prefix or suffix from a handler's name:
+// handlerTenantsList becomes TenantsList
+func handlerName(name string) string {
+	name = strings.TrimPrefix(name, "handler")
+	name = strings.TrimSuffix(name, "Handler")
+	return exportName(name)
+}
+
+// handlerID turns an operation's name into its ID, lower-casing the first
+// word: TenantsList becomes tenantsList and JWKSRotate jwksRotate
+func handlerID(name string) string {
+	r := []rune(name)
+	for i := 0; i < len(r) && unicode.IsUpper(r[i]); i++ {
+		// The last capital of a leading acronym starts the next word
+		if i > 0 && i+1 < len(r) && unicode.IsLower(r[i+1]) {
+			break
+		}
+		r[i] = unicode.ToLower(r[i])
+	}
+	return string(r)
+}
+
+// camel joins the words of a path segment such as handle-availability
+func camel(seg string) string {
+	words := strings.FieldsFunc(seg, func(r rune) bool { return r == '-' || r == '_' || r == '.' })
+	for i := 1; i < len(words); i++ {
+		words[i] = exportName(words[i])
+	}
+	return strings.Join(words, "")
+}
+
+// summary reads an operation's name as a sentence: TenantProductsList
+// becomes "Tenant products list" and MFADisable "MFA disable"
+func summary(id string) string {
+	var words []string
+	r := []rune(id)
+	start := 0
+	for i := 1; i <= len(r); i++ {
+		if i < len(r) {
+			lowerToUpper := unicode.IsLower(r[i-1]) && unicode.IsUpper(r[i])
+			acronymEnd := i+1 < len(r) && unicode.IsUpper(r[i-1]) && unicode.IsUpper(r[i]) && unicode.IsLower(r[i+1])
+			if !lowerToUpper && !acronymEnd {
+				continue
+			}
+		}
+		word := string(r[start:i])
+		if strings.ToUpper(word) != word {
+			word = strings.ToLower(word)
+		}
+		words = append(words, word)
+		start = i
+	}
+	if len(words) == 0 {
+		return ""
+	}
+	words[0] = exportName(words[0])
+	return strings.Join(words, " ")
+}
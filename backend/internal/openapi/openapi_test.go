@@ -0,0 +1,291 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type testAddress struct {
+	City string `json:"city"`
+}
+
+type testPage[T any] struct {
+	Data []T `json:"data"`
+}
+
+type testUser struct {
+	ID        uuid.UUID         `json:"id"`
+	Name      *string           `json:"name"`
+	Nickname  string            `json:"nickname,omitempty"`
+	Internal  string            `json:"-"`
+	CreatedAt time.Time         `json:"created_at"`
+	Address   *testAddress      `json:"address"`
+	Labels    map[string]string `json:"labels"`
+	Count     int64             `json:"count,string"`
+	secret    string
+}
+
+type testMe struct {
+	testUser
+	Avatar string `json:"avatar"`
+}
+
+func TestSchemaOf(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  reflect.Type
+		want string
+	}{
+		{name: "string", typ: reflect.TypeOf(""), want: `{"type":"string"}`},
+		{name: "int32", typ: reflect.TypeOf(int32(0)), want: `{"type":"integer","format":"int32"}`},
+		{name: "time", typ: reflect.TypeOf(time.Time{}), want: `{"type":"string","format":"date-time"}`},
+		{name: "nullable uuid", typ: reflect.TypeOf(&uuid.UUID{}), want: `{"type":["string","null"],"format":"uuid"}`},
+		{name: "bytes", typ: reflect.TypeOf([]byte{}), want: `{"type":"string","format":"byte"}`},
+		{name: "slice", typ: reflect.TypeOf([]bool{}), want: `{"type":"array","items":{"type":"boolean"}}`},
+		{name: "any", typ: reflect.TypeOf(map[string]any{}), want: `{"type":"object","additionalProperties":{}}`},
+		{name: "named struct", typ: reflect.TypeOf(testAddress{}), want: `{"$ref":"#/components/schemas/TestAddress"}`},
+		{name: "nullable struct", typ: reflect.TypeOf(&testAddress{}), want: `{"anyOf":[{"$ref":"#/components/schemas/TestAddress"},{"type":"null"}]}`},
+		{name: "generic struct", typ: reflect.TypeOf(testPage[testAddress]{}), want: `{"$ref":"#/components/schemas/TestPageTestAddress"}`},
+		{name: "anonymous struct", typ: reflect.TypeOf(struct {
+			A string `json:"a,omitempty"`
+		}{}), want: `{"type":"object","properties":{"a":{"type":"string"}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(newSchemas().of(tt.typ))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("of(%v) = %s, want %s", tt.typ, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemaFields(t *testing.T) {
+	s := newSchemas()
+	s.of(reflect.TypeOf(testMe{}))
+	me := s.components["TestMe"]
+	if me == nil {
+		t.Fatalf("components = %v, want TestMe", s.components)
+	}
+
+	var names []string
+	for name := range me.Properties {
+		names = append(names, name)
+	}
+	for _, want := range []string{"id", "name", "nickname", "created_at", "address", "labels", "count", "avatar"} {
+		if me.Properties[want] == nil {
+			t.Errorf("properties %v are missing %s", names, want)
+		}
+	}
+	for _, unwanted := range []string{"Internal", "secret", "testUser"} {
+		if me.Properties[unwanted] != nil {
+			t.Errorf("properties %v include %s", names, unwanted)
+		}
+	}
+	if got := me.Properties["count"].Type; got != "string" {
+		t.Errorf("count type = %v, want string", got)
+	}
+	wantRequired := []string{"id", "name", "created_at", "address", "labels", "count", "avatar"}
+	if !reflect.DeepEqual(me.Required, wantRequired) {
+		t.Errorf("required = %v, want %v", me.Required, wantRequired)
+	}
+	if s.components["TestAddress"] == nil {
+		t.Error("nested struct wasn't registered as a component")
+	}
+}
+
+func testRequireAuth(next http.Handler) http.Handler { return next }
+
+func testIdempotency(next http.Handler) http.Handler { return next }
+
+func testHandlerHealth(w http.ResponseWriter, r *http.Request) {}
+
+func testHandlerWidgetsList(w http.ResponseWriter, r *http.Request) {}
+
+func testHandlerWidgetGet(w http.ResponseWriter, r *http.Request) {}
+
+func testHandlerSetState(state string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {}
+}
+
+func testRouter() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/health", testHandlerHealth)
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Use(testRequireAuth)
+			r.Use(testIdempotency)
+			r.Route("/tenants/{tenantID}/widgets", func(r chi.Router) {
+				r.Get("/", testHandlerWidgetsList)
+				r.Get("/{widgetID:[0-9]+}", testHandlerWidgetGet)
+				r.Post("/{widgetID}/enable", testHandlerSetState("on"))
+				r.Post("/{widgetID}/disable", testHandlerSetState("off"))
+			})
+		})
+		r.Mount("/files", http.FileServer(http.Dir(".")))
+	})
+	return r
+}
+
+func TestBuild(t *testing.T) {
+	routes, err := Routes(testRouter())
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := Spec{
+		Prefix:     "/api/v1",
+		Scopes:     []string{"tenants"},
+		Auth:       []string{"testRequireAuth"},
+		Idempotent: []string{"testIdempotency"},
+		Annotations: []Annotation{
+			{Method: http.MethodGet, Path: "/health", ID: "health", Summary: "Health"},
+			{Method: http.MethodGet, Path: "/api/v1/tenants/{tenantID}/widgets/{widgetID}", Response: testAddress{}},
+		},
+	}
+	doc, err := spec.Build(routes)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	tests := []struct {
+		method, path string
+		wantID       string
+		wantSummary  string
+		wantTag      string
+		wantSecured  bool
+		wantParams   int
+	}{
+		{method: "get", path: "/health", wantID: "health", wantSummary: "Health", wantTag: "health"},
+		{method: "get", path: "/api/v1/tenants/{tenantID}/widgets", wantID: "testHandlerWidgetsList", wantSummary: "Test handler widgets list", wantTag: "widgets", wantSecured: true, wantParams: 1},
+		{method: "get", path: "/api/v1/tenants/{tenantID}/widgets/{widgetID}", wantID: "testHandlerWidgetGet", wantSummary: "Test handler widget get", wantTag: "widgets", wantSecured: true, wantParams: 2},
+		{method: "post", path: "/api/v1/tenants/{tenantID}/widgets/{widgetID}/enable", wantID: "testHandlerSetStateEnable", wantSummary: "Test handler set state enable", wantTag: "widgets", wantSecured: true, wantParams: 3},
+		{method: "post", path: "/api/v1/tenants/{tenantID}/widgets/{widgetID}/disable", wantID: "testHandlerSetStateDisable", wantSummary: "Test handler set state disable", wantTag: "widgets", wantSecured: true, wantParams: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			op := doc.Paths[tt.path][tt.method]
+			if op == nil {
+				t.Fatalf("no operation; paths are %v", doc.Paths)
+			}
+			if op.OperationID != tt.wantID || op.Summary != tt.wantSummary {
+				t.Errorf("operation = %q %q, want %q %q", op.OperationID, op.Summary, tt.wantID, tt.wantSummary)
+			}
+			if len(op.Tags) != 1 || op.Tags[0] != tt.wantTag {
+				t.Errorf("tags = %v, want %s", op.Tags, tt.wantTag)
+			}
+			if secured := len(op.Security) > 0; secured != tt.wantSecured {
+				t.Errorf("secured = %v, want %v", secured, tt.wantSecured)
+			}
+			if len(op.Parameters) != tt.wantParams {
+				t.Errorf("parameters = %v, want %d", op.Parameters, tt.wantParams)
+			}
+			if op.Responses["default"] == nil {
+				t.Error("missing the problem response")
+			}
+		})
+	}
+
+	for path := range doc.Paths {
+		if strings.Contains(path, "files") {
+			t.Errorf("mounted file server documented at %s", path)
+		}
+	}
+	got := doc.Paths["/api/v1/tenants/{tenantID}/widgets/{widgetID}"]["get"].Responses["200"].Content["application/json"].Schema
+	if got.Ref != "#/components/schemas/TestAddress" {
+		t.Errorf("response schema = %+v, want a TestAddress ref", got)
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	routes, err := Routes(testRouter())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name        string
+		annotations []Annotation
+		want        string
+	}{
+		{
+			name:        "annotation without a route",
+			annotations: []Annotation{{Method: http.MethodGet, Path: "/gone"}},
+			want:        "annotation GET /gone has no route",
+		},
+		{
+			name: "annotated twice",
+			annotations: []Annotation{
+				{Method: http.MethodGet, Path: "/health"},
+				{Method: http.MethodGet, Path: "/health"},
+			},
+			want: "route GET /health is annotated twice",
+		},
+		{
+			name:        "duplicate operation ID",
+			annotations: []Annotation{{Method: http.MethodGet, Path: "/health", ID: "testHandlerWidgetGet"}},
+			want:        "operation ID testHandlerWidgetGet is used by both",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Spec{Annotations: tt.annotations}.Build(routes)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Build() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNames(t *testing.T) {
+	tests := []struct {
+		handler     string
+		wantID      string
+		wantSummary string
+	}{
+		{handler: "handlerTenantsList", wantID: "tenantsList", wantSummary: "Tenants list"},
+		{handler: "CreateUserHandler", wantID: "createUser", wantSummary: "Create user"},
+		{handler: "handlerMFADisable", wantID: "mfaDisable", wantSummary: "MFA disable"},
+		{handler: "handlerTenantSSOGet", wantID: "tenantSSOGet", wantSummary: "Tenant SSO get"},
+		{handler: "handlerJWKS", wantID: "jwks", wantSummary: "JWKS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.handler, func(t *testing.T) {
+			name := handlerName(tt.handler)
+			if got := handlerID(name); got != tt.wantID {
+				t.Errorf("handlerID(%q) = %q, want %q", name, got, tt.wantID)
+			}
+			if got := summary(name); got != tt.wantSummary {
+				t.Errorf("summary(%q) = %q, want %q", name, got, tt.wantSummary)
+			}
+		})
+	}
+}
+
+func TestDocumentPath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{pattern: "/", want: "/"},
+		{pattern: "/products/", want: "/products"},
+		{pattern: "/products/{productID:[0-9]+}/variants", want: "/products/{productID}/variants"},
+	}
+
+	for _, tt := range tests {
+		if got := DocumentPath(tt.pattern); got != tt.want {
+			t.Errorf("DocumentPath(%q) = %q, want %q", tt.pattern, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Package options models a product's options, such as Size and Color, and
+// the variants they imply: one per combination of values, so Size S/M and
+// Color Black/White make four.
+package options
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dfodeker/terminus/internal/validate"
+)
+
+// Limits on a product's options
+const (
+	MaxOptions = 3
+	MaxValues  = 100
+	// MaxCombinations bounds how many variants options may imply
+	MaxCombinations = 100
+	MaxNameLength   = 255
+	MaxValueLength  = 255
+)
+
+// Option is a named choice, such as Size, with its values in display order
+type Option struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// Validate checks names and values are present, unique and within limits,
+// and that the options imply at most MaxCombinations variants. Fields are
+// reported as options.0.name, options.1.values.2 and so on.
+func Validate(opts []Option) error {
+	var v validate.Validator
+	if !v.Check(len(opts) <= MaxOptions, "options", validate.CodeOutOfRange,
+		fmt.Sprintf("must have at most %d options", MaxOptions)) {
+		return v.Err()
+	}
+
+	names := map[string]bool{}
+	combinations := 1
+	for i, opt := range opts {
+		field := fmt.Sprintf("options.%d", i)
+		if v.Required(field+".name", opt.Name) && v.MaxLength(field+".name", opt.Name, MaxNameLength) {
+			key := strings.ToLower(strings.TrimSpace(opt.Name))
+			v.Check(!names[key], field+".name", validate.CodeInvalid, "is already the name of another option")
+			names[key] = true
+		}
+
+		if !v.Check(len(opt.Values) > 0, field+".values", validate.CodeRequired, "must have at least one value") ||
+			!v.Check(len(opt.Values) <= MaxValues, field+".values", validate.CodeOutOfRange,
+				fmt.Sprintf("must have at most %d values", MaxValues)) {
+			continue
+		}
+		values := map[string]bool{}
+		for j, value := range opt.Values {
+			vField := fmt.Sprintf("%s.values.%d", field, j)
+			if v.Required(vField, value) && v.MaxLength(vField, value, MaxValueLength) {
+				key := strings.ToLower(strings.TrimSpace(value))
+				v.Check(!values[key], vField, validate.CodeInvalid, "is already a value of this option")
+				values[key] = true
+			}
+		}
+		combinations *= len(opt.Values)
+	}
+
+	if v.Valid() {
+		v.Check(combinations <= MaxCombinations, "options", validate.CodeOutOfRange,
+			fmt.Sprintf("imply %d variants, more than the %d allowed", combinations, MaxCombinations))
+	}
+	return v.Err()
+}
+
+// Combination is one value of each option, in option order
+type Combination []string
+
+// Combinations returns every combination of the options' values, varying
+// the last option fastest: S/Black, S/White, M/Black, M/White. Without
+// options there is one empty combination, the product's default variant.
+func Combinations(opts []Option) []Combination {
+	combos := []Combination{{}}
+	for _, opt := range opts {
+		next := make([]Combination, 0, len(combos)*len(opt.Values))
+		for _, combo := range combos {
+			for _, value := range opt.Values {
+				c := make(Combination, len(combo), len(combo)+1)
+				copy(c, combo)
+				next = append(next, append(c, value))
+			}
+		}
+		combos = next
+	}
+	return combos
+}
+
+// Title names a variant by its values, such as "M / Black"
+func (c Combination) Title() string {
+	return strings.Join(c, " / ")
+}
+
+// Key identifies the combination among others of the same options
+func (c Combination) Key() string {
+	return strings.Join(c, "\x00")
+}
+
+// Values maps each option's name to the combination's value, the form
+// stored in a variant's option_values
+func (c Combination) Values(opts []Option) map[string]string {
+	values := make(map[string]string, len(opts))
+	for i, opt := range opts {
+		values[opt.Name] = c[i]
+	}
+	return values
+}
+
+// Match returns the combination of opts a variant's option_values
+// describe, or false when they don't name exactly one value of each option
+func Match(opts []Option, optionValues json.RawMessage) (Combination, bool) {
+	var values map[string]string
+	if err := json.Unmarshal(optionValues, &values); err != nil || len(values) != len(opts) {
+		return nil, false
+	}
+	combo := make(Combination, len(opts))
+	for i, opt := range opts {
+		value, ok := values[opt.Name]
+		if !ok || !slices.Contains(opt.Values, value) {
+			return nil, false
+		}
+		combo[i] = value
+	}
+	return combo, true
+}
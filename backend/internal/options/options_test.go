@@ -0,0 +1,118 @@
+package options
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dfodeker/terminus/internal/validate"
+)
+
+var shirt = []Option{
+	{Name: "Size", Values: []string{"S", "M"}},
+	{Name: "Color", Values: []string{"Black", "White"}},
+}
+
+func TestValidate(t *testing.T) {
+	many := make([]string, 11)
+	for i := range many {
+		many[i] = strings.Repeat("x", i+1)
+	}
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		wantFields []string
+	}{
+		{name: "valid", opts: shirt},
+		{name: "none", opts: nil},
+		{
+			name:       "too many options",
+			opts:       []Option{{Name: "A", Values: []string{"1"}}, {Name: "B", Values: []string{"1"}}, {Name: "C", Values: []string{"1"}}, {Name: "D", Values: []string{"1"}}},
+			wantFields: []string{"options"},
+		},
+		{
+			name:       "duplicate names and values",
+			opts:       []Option{{Name: "Size", Values: []string{"S", "s "}}, {Name: "size", Values: []string{"", "M"}}},
+			wantFields: []string{"options.0.values.1", "options.1.name", "options.1.values.0"},
+		},
+		{
+			name:       "no values",
+			opts:       []Option{{Name: "Size"}},
+			wantFields: []string{"options.0.values"},
+		},
+		{
+			name:       "too many combinations",
+			opts:       []Option{{Name: "A", Values: many}, {Name: "B", Values: many}},
+			wantFields: []string{"options"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.opts)
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			var invalid *validate.Error
+			if !errors.As(err, &invalid) {
+				t.Fatalf("Validate() = %v, want *validate.Error", err)
+			}
+			var got []string
+			for _, f := range invalid.Fields {
+				got = append(got, f.Field)
+			}
+			if !reflect.DeepEqual(got, tt.wantFields) {
+				t.Errorf("fields = %v, want %v", got, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestCombinations(t *testing.T) {
+	var titles []string
+	for _, c := range Combinations(shirt) {
+		titles = append(titles, c.Title())
+	}
+	want := []string{"S / Black", "S / White", "M / Black", "M / White"}
+	if !reflect.DeepEqual(titles, want) {
+		t.Errorf("titles = %v, want %v", titles, want)
+	}
+
+	if got := Combinations(nil); len(got) != 1 || len(got[0]) != 0 {
+		t.Errorf("Combinations(nil) = %v, want one empty combination", got)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	values, err := json.Marshal(Combination{"M", "White"}.Values(shirt))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		values string
+		want   Combination
+	}{
+		{name: "round trip", values: string(values), want: Combination{"M", "White"}},
+		{name: "unknown value", values: `{"Size":"XL","Color":"White"}`},
+		{name: "missing option", values: `{"Size":"M"}`},
+		{name: "extra option", values: `{"Size":"M","Color":"White","Fit":"Slim"}`},
+		{name: "default variant", values: `{}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Match(shirt, json.RawMessage(tt.values))
+			if ok != (tt.want != nil) || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Match() = %v, %v, want %v", got, ok, tt.want)
+			}
+		})
+	}
+}
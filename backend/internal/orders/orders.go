@@ -0,0 +1,155 @@
+// Package orders holds the merchant-side order rules: timeline event kinds,
+// staff notes, tags, and the filters of the store order listing.
+package orders
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidFilter = errors.New("invalid filter")
+	ErrInvalidTags   = errors.New("invalid tags")
+	ErrInvalidNote   = errors.New("invalid note")
+)
+
+// EventKind classifies an order timeline entry, matching order_events.kind
+type EventKind string
+
+const (
+	// EventPlaced and EventStatusChanged are written by a database trigger
+	EventPlaced        EventKind = "placed"
+	EventStatusChanged EventKind = "status_changed"
+	EventPayment       EventKind = "payment"
+	EventRefund        EventKind = "refund"
+	EventFulfillment   EventKind = "fulfillment"
+	EventNote          EventKind = "note"
+)
+
+// IsValid returns true if the kind is known
+func (k EventKind) IsValid() bool {
+	switch k {
+	case EventPlaced, EventStatusChanged, EventPayment, EventRefund, EventFulfillment, EventNote:
+		return true
+	}
+	return false
+}
+
+const (
+	MaxTags       = 50
+	MaxTagLength  = 40
+	MaxNoteLength = 5000
+	// MaxFilterValues bounds how many values one filter parameter may carry
+	MaxFilterValues = 20
+)
+
+// NormalizeTags trims and lowercases tags, dropping blanks and duplicates
+// while keeping the merchant's order
+func NormalizeTags(tags []string) ([]string, error) {
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || slices.Contains(out, tag) {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return nil, fmt.Errorf("%w: tags must be at most %d characters", ErrInvalidTags, MaxTagLength)
+		}
+		if strings.Contains(tag, ",") {
+			return nil, fmt.Errorf("%w: tags must not contain commas", ErrInvalidTags)
+		}
+		out = append(out, tag)
+	}
+	if len(out) > MaxTags {
+		return nil, fmt.Errorf("%w: at most %d tags are allowed", ErrInvalidTags, MaxTags)
+	}
+	return out, nil
+}
+
+// NormalizeNote trims a staff note and checks its length
+func NormalizeNote(note string) (string, error) {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return "", fmt.Errorf("%w: note must not be empty", ErrInvalidNote)
+	}
+	if utf8.RuneCountInString(note) > MaxNoteLength {
+		return "", fmt.Errorf("%w: note must be at most %d characters", ErrInvalidNote, MaxNoteLength)
+	}
+	return note, nil
+}
+
+var (
+	statuses            = []string{"open", "closed", "cancelled"}
+	financialStatuses   = []string{"pending", "authorized", "paid", "partially_refunded", "refunded", "voided"}
+	fulfillmentStatuses = []string{"unfulfilled", "partial", "fulfilled"}
+)
+
+// Filter narrows the store order listing. Zero values match everything.
+type Filter struct {
+	// Tags must all be present on an order
+	Tags                []string
+	Statuses            []string
+	FinancialStatuses   []string
+	FulfillmentStatuses []string
+}
+
+// ParseFilter reads filters from query parameters:
+//
+//	tag=vip&tag=wholesale            all of the tags (comma-separated also works)
+//	status=open                      any of the statuses
+//	financial_status=paid,refunded
+//	fulfillment_status=unfulfilled
+func ParseFilter(q url.Values) (Filter, error) {
+	var f Filter
+	var err error
+
+	if f.Tags, err = parseList(q, "tag", nil); err != nil {
+		return Filter{}, err
+	}
+	if f.Statuses, err = parseList(q, "status", statuses); err != nil {
+		return Filter{}, err
+	}
+	if f.FinancialStatuses, err = parseList(q, "financial_status", financialStatuses); err != nil {
+		return Filter{}, err
+	}
+	if f.FulfillmentStatuses, err = parseList(q, "fulfillment_status", fulfillmentStatuses); err != nil {
+		return Filter{}, err
+	}
+	return f, nil
+}
+
+// ParseEventKinds reads the kind filter of the order timeline:
+//
+//	kind=note,payment   any of the kinds
+func ParseEventKinds(q url.Values) ([]string, error) {
+	return parseList(q, "kind", []string{
+		string(EventPlaced), string(EventStatusChanged), string(EventPayment),
+		string(EventRefund), string(EventFulfillment), string(EventNote),
+	})
+}
+
+// parseList reads a repeated or comma-separated parameter, lowercased.
+// allowed, when set, lists the only accepted values.
+func parseList(q url.Values, key string, allowed []string) ([]string, error) {
+	var out []string
+	for _, raw := range q[key] {
+		for _, v := range strings.Split(raw, ",") {
+			v = strings.ToLower(strings.TrimSpace(v))
+			if v == "" || slices.Contains(out, v) {
+				continue
+			}
+			if allowed != nil && !slices.Contains(allowed, v) {
+				return nil, fmt.Errorf("%w: unknown %s %q", ErrInvalidFilter, key, v)
+			}
+			out = append(out, v)
+		}
+	}
+	if len(out) > MaxFilterValues {
+		return nil, fmt.Errorf("%w: at most %d %s values are allowed", ErrInvalidFilter, MaxFilterValues, key)
+	}
+	return out, nil
+}
@@ -0,0 +1,143 @@
+package orders
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tooMany := make([]string, MaxTags+1)
+	for i := range tooMany {
+		tooMany[i] = "tag" + strings.Repeat("x", i)
+	}
+
+	tests := []struct {
+		name    string
+		tags    []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "normalizes and dedupes", tags: []string{" VIP ", "vip", "", "Wholesale"}, want: []string{"vip", "wholesale"}},
+		{name: "empty", tags: nil, want: []string{}},
+		{name: "too long", tags: []string{strings.Repeat("a", MaxTagLength+1)}, wantErr: true},
+		{name: "comma", tags: []string{"a,b"}, wantErr: true},
+		{name: "too many", tags: tooMany, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeTags(tt.tags)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidTags) {
+					t.Fatalf("error = %v, want ErrInvalidTags", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NormalizeTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeNote(t *testing.T) {
+	tests := []struct {
+		note    string
+		want    string
+		wantErr bool
+	}{
+		{note: "  Called the customer  ", want: "Called the customer"},
+		{note: "   ", wantErr: true},
+		{note: strings.Repeat("a", MaxNoteLength+1), wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeNote(tt.note)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NormalizeNote(%q) error = %v, wantErr %v", tt.note, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NormalizeNote(%q) = %q, want %q", tt.note, got, tt.want)
+		}
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	var manyTags []string
+	for i := 0; i <= MaxFilterValues; i++ {
+		manyTags = append(manyTags, strings.Repeat("t", i+1))
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    Filter
+		wantErr bool
+	}{
+		{name: "empty", query: ""},
+		{
+			name:  "tags and statuses",
+			query: "tag=VIP&tag=wholesale,vip&status=open&financial_status=paid,refunded&fulfillment_status=unfulfilled",
+			want: Filter{
+				Tags:                []string{"vip", "wholesale"},
+				Statuses:            []string{"open"},
+				FinancialStatuses:   []string{"paid", "refunded"},
+				FulfillmentStatuses: []string{"unfulfilled"},
+			},
+		},
+		{name: "unknown status", query: "status=shipped", wantErr: true},
+		{name: "unknown financial status", query: "financial_status=owed", wantErr: true},
+		{name: "too many tags", query: "tag=" + strings.Join(manyTags, ","), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ParseFilter(q)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidFilter) {
+					t.Fatalf("error = %v, want ErrInvalidFilter", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFilter() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventKindIsValid(t *testing.T) {
+	for _, k := range []EventKind{EventPlaced, EventStatusChanged, EventPayment, EventRefund, EventFulfillment, EventNote} {
+		if !k.IsValid() {
+			t.Errorf("%q should be valid", k)
+		}
+	}
+	if EventKind("email").IsValid() {
+		t.Error("unknown kind should be invalid")
+	}
+}
+
+func TestParseEventKinds(t *testing.T) {
+	got, err := ParseEventKinds(url.Values{"kind": {"note, payment", "note"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"note", "payment"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseEventKinds() = %v, want %v", got, want)
+	}
+
+	if _, err := ParseEventKinds(url.Values{"kind": {"email"}}); !errors.Is(err, ErrInvalidFilter) {
+		t.Errorf("error = %v, want ErrInvalidFilter", err)
+	}
+}
@@ -0,0 +1,75 @@
+// Package payments charges a customer's saved payment method through the
+// provider that holds it
+package payments
+
+import (
+	"context"
+	"errors"
+	"sort"
+)
+
+// ProviderManual is the provider of payments the merchant collects outside
+// the platform, such as by invoice
+const ProviderManual = "manual"
+
+// ErrDeclined is returned when the provider refused the charge. Anything
+// else is a failure to reach the provider and worth retrying sooner.
+var ErrDeclined = errors.New("payment declined")
+
+// Charge asks a provider for an amount against a saved payment method
+type Charge struct {
+	// MethodRef is the provider's reference for the saved payment method
+	MethodRef   string
+	AmountCents int64
+	Currency    string
+	// IdempotencyKey is the same on every attempt at one payment, so a
+	// retry after a lost response never charges twice
+	IdempotencyKey string
+	Description    string
+}
+
+// Result is the provider's answer to a successful Charge
+type Result struct {
+	// TransactionID is the provider's reference for the payment, empty when
+	// nothing was taken
+	TransactionID string
+	// Pending is true when the money is still to be collected
+	Pending bool
+}
+
+// Gateway is a payment provider
+type Gateway interface {
+	Charge(ctx context.Context, c Charge) (Result, error)
+}
+
+// Manual takes nothing and leaves every payment pending for the merchant
+type Manual struct{}
+
+// Charge implements Gateway
+func (Manual) Charge(context.Context, Charge) (Result, error) {
+	return Result{Pending: true}, nil
+}
+
+// Gateways maps provider names to their gateway
+type Gateways map[string]Gateway
+
+// Default returns the gateways available without any configuration
+func Default() Gateways {
+	return Gateways{ProviderManual: Manual{}}
+}
+
+// Lookup returns the named provider's gateway
+func (g Gateways) Lookup(provider string) (Gateway, bool) {
+	gateway, ok := g[provider]
+	return gateway, ok
+}
+
+// Providers lists the provider names in order, for error messages
+func (g Gateways) Providers() []string {
+	names := make([]string, 0, len(g))
+	for name := range g {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,32 @@
+package payments
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestManualCharge(t *testing.T) {
+	got, err := Manual{}.Charge(context.Background(), Charge{MethodRef: "invoice", AmountCents: 1999, Currency: "USD"})
+	if err != nil {
+		t.Fatalf("Charge() error = %v", err)
+	}
+	if !got.Pending || got.TransactionID != "" {
+		t.Errorf("Charge() = %+v, want pending with no transaction", got)
+	}
+}
+
+func TestGatewaysLookup(t *testing.T) {
+	gateways := Default()
+	if _, ok := gateways.Lookup(ProviderManual); !ok {
+		t.Errorf("Lookup(%q) not found", ProviderManual)
+	}
+	if _, ok := gateways.Lookup("stripe"); ok {
+		t.Error("Lookup(stripe) found an unconfigured provider")
+	}
+
+	gateways["acme"] = Manual{}
+	if got, want := gateways.Providers(), []string{"acme", ProviderManual}; !slices.Equal(got, want) {
+		t.Errorf("Providers() = %v, want %v", got, want)
+	}
+}
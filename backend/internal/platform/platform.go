@@ -0,0 +1,120 @@
+// Package platform is what platform staff may do across tenants from the
+// admin API: look tenants and stores up, suspend abusive tenants and act in
+// a tenant for support. Staff hold a platform role on their user account,
+// granted by hand in the database.
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Role is a platform staff role
+type Role string
+
+const (
+	// RoleSupport may look tenants, stores and usage up
+	RoleSupport Role = "support"
+	// RoleAdmin may also suspend tenants and impersonate them
+	RoleAdmin Role = "admin"
+)
+
+// Allows reports whether a holder of r may do what need is required for
+func (r Role) Allows(need Role) bool {
+	switch r {
+	case RoleAdmin:
+		return need == RoleAdmin || need == RoleSupport
+	case RoleSupport:
+		return need == RoleSupport
+	}
+	return false
+}
+
+// ImpersonationTTL is how long an impersonation token lasts. It can't be
+// refreshed; staff start another impersonation, which is logged again.
+const ImpersonationTTL = 15 * time.Minute
+
+// OwnerPermission is the one permission impersonation doesn't grant, so
+// support can't spend a tenant's money or hand its ownership over
+const OwnerPermission = "tenant:owner"
+
+// ImpersonationAllows reports whether staff impersonating a tenant hold
+// permission in it
+func ImpersonationAllows(permission string) bool {
+	return permission != OwnerPermission
+}
+
+// Actions recorded in the platform audit log
+const (
+	ActionSuspend     = "tenant.suspend"
+	ActionUnsuspend   = "tenant.unsuspend"
+	ActionImpersonate = "tenant.impersonate"
+	ActionRotateKey   = "encryption_key.rotate"
+)
+
+// Tenant statuses
+const (
+	TenantActive    = "active"
+	TenantInactive  = "inactive"
+	TenantSuspended = "suspended"
+	TenantDeleted   = "deleted"
+)
+
+// TenantStatuses are the statuses a tenant can be in
+var TenantStatuses = []string{TenantActive, TenantInactive, TenantSuspended, TenantDeleted}
+
+// User statuses; an erased user is kept only as an anonymized record
+const (
+	UserActive = "active"
+	UserErased = "erased"
+)
+
+// UserStatuses are the statuses a user can be in
+var UserStatuses = []string{UserActive, UserErased}
+
+// MaxQueryLength bounds a search query
+const MaxQueryLength = 200
+
+// ErrInvalidFilter is returned for query parameters that can't be used
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// Filter narrows the tenants, stores or users listed
+type Filter struct {
+	// Query is matched against names, handles, domains and emails
+	Query    string
+	Status   string
+	TenantID uuid.UUID
+}
+
+// ParseFilter reads filters from query parameters, with statuses being
+// those of what is listed:
+//
+//	q=acme                  part of a name, handle or email, or a whole ID or domain
+//	status=suspended
+//	tenant_id=<tenant id>   stores and users only
+func ParseFilter(q url.Values, statuses []string) (Filter, error) {
+	f := Filter{
+		Query:  strings.TrimSpace(q.Get("q")),
+		Status: strings.ToLower(strings.TrimSpace(q.Get("status"))),
+	}
+	if len(f.Query) > MaxQueryLength {
+		return Filter{}, fmt.Errorf("%w: q is longer than %d characters", ErrInvalidFilter, MaxQueryLength)
+	}
+	if f.Status != "" && !slices.Contains(statuses, f.Status) {
+		return Filter{}, fmt.Errorf("%w: unknown status %q, statuses are %s", ErrInvalidFilter, f.Status, strings.Join(statuses, ", "))
+	}
+	if raw := strings.TrimSpace(q.Get("tenant_id")); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return Filter{}, fmt.Errorf("%w: tenant_id is not an ID", ErrInvalidFilter)
+		}
+		f.TenantID = id
+	}
+	return f, nil
+}
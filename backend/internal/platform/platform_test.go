@@ -0,0 +1,77 @@
+package platform
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestRoleAllows(t *testing.T) {
+	tests := []struct {
+		role Role
+		need Role
+		want bool
+	}{
+		{role: RoleAdmin, need: RoleAdmin, want: true},
+		{role: RoleAdmin, need: RoleSupport, want: true},
+		{role: RoleSupport, need: RoleSupport, want: true},
+		{role: RoleSupport, need: RoleAdmin, want: false},
+		{role: "", need: RoleSupport, want: false},
+		{role: "owner", need: RoleSupport, want: false},
+	}
+	for _, tt := range tests {
+		if got := tt.role.Allows(tt.need); got != tt.want {
+			t.Errorf("%q.Allows(%q) = %v, want %v", tt.role, tt.need, got, tt.want)
+		}
+	}
+}
+
+func TestImpersonationAllows(t *testing.T) {
+	for permission, want := range map[string]bool{
+		"products:view": true,
+		"tenant:manage": true,
+		"tenant:owner":  false,
+	} {
+		if got := ImpersonationAllows(permission); got != want {
+			t.Errorf("ImpersonationAllows(%q) = %v, want %v", permission, got, want)
+		}
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	tenantID := uuid.New()
+	long := make([]byte, MaxQueryLength+1)
+	for i := range long {
+		long[i] = 'a'
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    Filter
+		wantErr bool
+	}{
+		{name: "none", query: ""},
+		{name: "query trimmed", query: "q=+acme+", want: Filter{Query: "acme"}},
+		{name: "status", query: "status=Suspended", want: Filter{Status: TenantSuspended}},
+		{name: "tenant", query: "tenant_id=" + tenantID.String(), want: Filter{TenantID: tenantID}},
+		{name: "unknown status", query: "status=banned", wantErr: true},
+		{name: "bad tenant", query: "tenant_id=acme", wantErr: true},
+		{name: "query too long", query: "q=" + string(long), wantErr: true},
+	}
+	for _, tt := range tests {
+		q, _ := url.ParseQuery(tt.query)
+		got, err := ParseFilter(q, TenantStatuses)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidFilter) {
+				t.Errorf("%s: ParseFilter() error = %v, want ErrInvalidFilter", tt.name, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: ParseFilter() = %+v, %v, want %+v", tt.name, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Package pricelists works out what a customer pays for a variant from the
+// price lists assigned to their customer groups
+package pricelists
+
+import "github.com/google/uuid"
+
+// MaxNameLength bounds price list and customer group names
+const MaxNameLength = 255
+
+// Entry is a variant's price on one list: a fixed price in the store
+// currency when PriceCents is set, otherwise PercentOff its base price
+type Entry struct {
+	VariantID  uuid.UUID
+	PriceCents *int64
+	PercentOff int64
+}
+
+// Apply returns the variant's price on the entry given its base price. A
+// percentage rounds to the nearest minor unit, halves up.
+func (e Entry) Apply(base int64) int64 {
+	if e.PriceCents != nil {
+		return *e.PriceCents
+	}
+	return base - (base*e.PercentOff+50)/100
+}
+
+// Prices maps a variant to what the customer pays for it
+type Prices map[uuid.UUID]int64
+
+// Resolve prices each variant with the cheapest of its entries. A list
+// never makes a variant dearer than its base price, so a customer in a
+// group is never charged more than a guest.
+func Resolve(base map[uuid.UUID]int64, entries []Entry) Prices {
+	prices := make(Prices, len(entries))
+	for _, e := range entries {
+		b, ok := base[e.VariantID]
+		if !ok {
+			continue
+		}
+		price := min(e.Apply(b), b)
+		if lowest, seen := prices[e.VariantID]; !seen || price < lowest {
+			prices[e.VariantID] = price
+		}
+	}
+	return prices
+}
+
+// Price returns what the customer pays for the variant, base when no list
+// prices it
+func (p Prices) Price(variantID uuid.UUID, base int64) int64 {
+	if price, ok := p[variantID]; ok {
+		return price
+	}
+	return base
+}
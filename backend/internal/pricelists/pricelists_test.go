@@ -0,0 +1,80 @@
+package pricelists
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func cents(n int64) *int64 { return &n }
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry Entry
+		base  int64
+		want  int64
+	}{
+		{name: "fixed price", entry: Entry{PriceCents: cents(1500)}, base: 2000, want: 1500},
+		{name: "fixed price above base", entry: Entry{PriceCents: cents(2500)}, base: 2000, want: 2500},
+		{name: "percentage off", entry: Entry{PercentOff: 25}, base: 2000, want: 1500},
+		{name: "rounds halves up", entry: Entry{PercentOff: 10}, base: 1995, want: 1795},
+		{name: "rounds down below a half", entry: Entry{PercentOff: 33}, base: 1001, want: 671},
+		{name: "all off", entry: Entry{PercentOff: 100}, base: 999, want: 0},
+		{name: "free base", entry: Entry{PercentOff: 50}, base: 0, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.entry.Apply(tt.base); got != tt.want {
+				t.Errorf("Apply(%d) = %d, want %d", tt.base, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	shirt, mug, hat := uuid.New(), uuid.New(), uuid.New()
+	base := map[uuid.UUID]int64{shirt: 2000, mug: 1000, hat: 1500}
+
+	tests := []struct {
+		name    string
+		entries []Entry
+		want    map[uuid.UUID]int64
+	}{
+		{name: "no entries", want: map[uuid.UUID]int64{shirt: 2000, mug: 1000, hat: 1500}},
+		{
+			name:    "one list",
+			entries: []Entry{{VariantID: shirt, PercentOff: 10}, {VariantID: mug, PriceCents: cents(800)}},
+			want:    map[uuid.UUID]int64{shirt: 1800, mug: 800, hat: 1500},
+		},
+		{
+			name: "cheapest list wins",
+			entries: []Entry{
+				{VariantID: shirt, PercentOff: 10},
+				{VariantID: shirt, PriceCents: cents(1700)},
+				{VariantID: shirt, PercentOff: 5},
+			},
+			want: map[uuid.UUID]int64{shirt: 1700, mug: 1000, hat: 1500},
+		},
+		{
+			name:    "never above base",
+			entries: []Entry{{VariantID: mug, PriceCents: cents(1200)}},
+			want:    map[uuid.UUID]int64{shirt: 2000, mug: 1000, hat: 1500},
+		},
+		{
+			name:    "unpriced variant ignored",
+			entries: []Entry{{VariantID: uuid.New(), PriceCents: cents(1)}},
+			want:    map[uuid.UUID]int64{shirt: 2000, mug: 1000, hat: 1500},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prices := Resolve(base, tt.entries)
+			for id, want := range tt.want {
+				if got := prices.Price(id, base[id]); got != want {
+					t.Errorf("Price(%v) = %d, want %d", id, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package privacy
+
+import (
+	"context"
+
+	"github.com/dfodeker/terminus/internal/crypto"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/service/roles"
+	"github.com/google/uuid"
+)
+
+var (
+	errCustomerErased  = service.Conflict("The customer has already been erased")
+	errUserErased      = service.Conflict("The account has already been erased")
+	errOrdersToFulfill = service.Conflict("The customer has open orders still to fulfill; fulfill or cancel them before erasing the customer")
+	errSoleOwner       = &service.Error{
+		Kind:    service.ErrConflict,
+		Code:    problem.CodeLastOwner,
+		Message: "You are the only owner of a tenant; give another member the Owner role or delete the tenant first",
+	}
+)
+
+// CheckCustomerErasable refuses to erase a customer with orders still to
+// ship, since the store needs their address to do it
+func CheckCustomerErasable(ctx context.Context, q Queries, c database.Customer) error {
+	_, err := erasableOrders(ctx, q, c)
+	return err
+}
+
+// EraseCustomer anonymizes a customer and everything pointing at them. The
+// customer is left disabled with an undeliverable email. Orders keep their
+// totals and line items but lose the email and addresses, reviews keep
+// their text under ErasedAuthorName, subscriptions are cancelled, and audit
+// log entries lose their snapshots. Saved addresses, sign-ins, security
+// events and group memberships are deleted. Run it in a transaction.
+func EraseCustomer(ctx context.Context, q Queries, c database.Customer) error {
+	orderIDs, err := erasableOrders(ctx, q, c)
+	if err != nil {
+		return err
+	}
+	addresses, err := q.GetCustomerAddresses(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := q.EraseOrdersContact(ctx, orderIDs); err != nil {
+		return err
+	}
+	if err := q.DeleteCustomerAddresses(ctx, c.ID); err != nil {
+		return err
+	}
+	if err := q.DeleteCustomerRefreshTokens(ctx, c.ID); err != nil {
+		return err
+	}
+	if err := q.DeleteCustomerGroupMemberships(ctx, c.ID); err != nil {
+		return err
+	}
+	if err := q.DeleteCustomerSecurityEvents(ctx, c.ID); err != nil {
+		return err
+	}
+	if err := q.EraseCustomerReviews(ctx, database.EraseCustomerReviewsParams{
+		AuthorName: ErasedAuthorName,
+		CustomerID: uuid.NullUUID{UUID: c.ID, Valid: true},
+	}); err != nil {
+		return err
+	}
+	if err := q.CancelCustomerSubscriptions(ctx, c.ID); err != nil {
+		return err
+	}
+
+	entities := append([]uuid.UUID{c.ID}, orderIDs...)
+	for _, a := range addresses {
+		entities = append(entities, a.ID)
+	}
+	if err := q.EraseAuditLogEntities(ctx, database.EraseAuditLogEntitiesParams{
+		TenantID:  c.TenantID,
+		EntityIds: entities,
+	}); err != nil {
+		return err
+	}
+
+	_, err = q.EraseCustomer(ctx, database.EraseCustomerParams{
+		Email:       crypto.Text(ErasedEmail(c.ID)),
+		EmailDigest: crypto.Digest(ErasedEmail(c.ID)),
+		ID:          c.ID,
+	})
+	return err
+}
+
+// CheckUserErasable refuses to erase a user who is the only owner of a
+// tenant, which would be left without one
+func CheckUserErasable(ctx context.Context, q Queries, u database.User) error {
+	if u.ErasedAt.Valid {
+		return errUserErased
+	}
+	owned, err := q.ListTenantsSolelyOwnedByUser(ctx, database.ListTenantsSolelyOwnedByUserParams{
+		OwnerRole: roles.OwnerTemplate.Name,
+		UserID:    u.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if len(owned) > 0 {
+		return errSoleOwner
+	}
+	return nil
+}
+
+// EraseUser anonymizes a platform user. They leave every tenant, every
+// sign-in, token, authenticator, linked account and security event is
+// deleted, and the email is replaced wherever it was kept: the account,
+// invitations sent to it and the platform audit log. Audit log entries about the user lose their
+// snapshots; entries naming them as the actor keep only their ID. Run it in
+// a transaction.
+func EraseUser(ctx context.Context, q Queries, u database.User) error {
+	if err := CheckUserErasable(ctx, q, u); err != nil {
+		return err
+	}
+	erased := ErasedEmail(u.ID)
+
+	// Before the memberships go, since entries about them are found
+	// through them
+	if err := q.EraseUserAuditLogEntities(ctx, uuid.NullUUID{UUID: u.ID, Valid: true}); err != nil {
+		return err
+	}
+	for _, del := range []func(context.Context, uuid.UUID) error{
+		q.DeleteUserMemberships,
+		q.DeleteUserSessions,
+		q.DeleteUserPasswordResetTokens,
+		q.DeleteUserMagicLinkTokens,
+		q.DeleteUserEmailVerificationTokens,
+		q.DeleteUserMFAChallenges,
+		q.DeleteMFARecoveryCodes,
+		q.DeleteUserMFA,
+		q.DeleteUserSecurityEvents,
+		q.DeleteUserIdentities,
+	} {
+		if err := del(ctx, u.ID); err != nil {
+			return err
+		}
+	}
+	if err := q.EraseInvitationEmails(ctx, database.EraseInvitationEmailsParams{
+		ErasedEmail: erased,
+		Email:       u.Email,
+	}); err != nil {
+		return err
+	}
+	if err := q.ErasePlatformAuditLogActor(ctx, database.ErasePlatformAuditLogActorParams{
+		ErasedEmail: erased,
+		ActorUserID: uuid.NullUUID{UUID: u.ID, Valid: true},
+	}); err != nil {
+		return err
+	}
+
+	_, err := q.EraseUser(ctx, database.EraseUserParams{
+		Email: erased,
+		ID:    u.ID,
+	})
+	return err
+}
+
+// erasableOrders returns the IDs of the customer's orders, refusing if any
+// is still to be fulfilled
+func erasableOrders(ctx context.Context, q Queries, c database.Customer) ([]uuid.UUID, error) {
+	if c.ErasedAt.Valid {
+		return nil, errCustomerErased
+	}
+	orders, err := customerOrders(ctx, q, c)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, 0, len(orders))
+	for _, o := range orders {
+		ids = append(ids, o.ID)
+	}
+	open, err := q.CountUnfulfilledOrders(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if open > 0 {
+		return nil, errOrdersToFulfill
+	}
+	return ids, nil
+}
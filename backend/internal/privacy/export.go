@@ -0,0 +1,432 @@
+package privacy
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/crypto"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/google/uuid"
+)
+
+// Customer is a customer's own record in an export
+type Customer struct {
+	ID               uuid.UUID  `json:"id"`
+	StoreID          uuid.UUID  `json:"store_id"`
+	Email            string     `json:"email"`
+	FirstName        *string    `json:"first_name"`
+	LastName         *string    `json:"last_name"`
+	Phone            *string    `json:"phone"`
+	AcceptsMarketing bool       `json:"accepts_marketing"`
+	Status           string     `json:"status"`
+	Tags             []string   `json:"tags"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	ErasedAt         *time.Time `json:"erased_at,omitempty"`
+}
+
+// Address is one of a customer's saved addresses
+type Address struct {
+	ID                uuid.UUID `json:"id"`
+	FirstName         *string   `json:"first_name"`
+	LastName          *string   `json:"last_name"`
+	Company           *string   `json:"company"`
+	Address1          string    `json:"address1"`
+	Address2          *string   `json:"address2"`
+	City              string    `json:"city"`
+	RegionCode        *string   `json:"region_code"`
+	PostalCode        *string   `json:"postal_code"`
+	CountryCode       string    `json:"country_code"`
+	Phone             *string   `json:"phone"`
+	IsDefaultShipping bool      `json:"is_default_shipping"`
+	IsDefaultBilling  bool      `json:"is_default_billing"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// Order is an order the customer placed, signed in or as a guest
+type Order struct {
+	ID                uuid.UUID       `json:"id"`
+	OrderNumber       int64           `json:"order_number"`
+	Email             *string         `json:"email"`
+	Status            string          `json:"status"`
+	FinancialStatus   string          `json:"financial_status"`
+	FulfillmentStatus string          `json:"fulfillment_status"`
+	Currency          string          `json:"currency"`
+	SubtotalCents     int64           `json:"subtotal_cents"`
+	ShippingCents     int64           `json:"shipping_cents"`
+	TaxCents          int64           `json:"tax_cents"`
+	DiscountCents     int64           `json:"discount_cents"`
+	TotalCents        int64           `json:"total_cents"`
+	ShippingAddress   json.RawMessage `json:"shipping_address"`
+	BillingAddress    json.RawMessage `json:"billing_address"`
+	LineItems         []LineItem      `json:"line_items"`
+	PlacedAt          time.Time       `json:"placed_at"`
+}
+
+// LineItem is what an order bought
+type LineItem struct {
+	Title        string  `json:"title"`
+	VariantTitle *string `json:"variant_title"`
+	Sku          *string `json:"sku"`
+	Quantity     int32   `json:"quantity"`
+	PriceCents   int64   `json:"price_cents"`
+	TotalCents   int64   `json:"total_cents"`
+}
+
+// Review is a review the customer wrote
+type Review struct {
+	ID               uuid.UUID `json:"id"`
+	ProductID        uuid.UUID `json:"product_id"`
+	AuthorName       string    `json:"author_name"`
+	Rating           int32     `json:"rating"`
+	Title            *string   `json:"title"`
+	Body             string    `json:"body"`
+	VerifiedPurchase bool      `json:"verified_purchase"`
+	Status           string    `json:"status"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// Subscription is one of the customer's subscriptions. The payment method
+// reference is left out; it only means something to the payment provider.
+type Subscription struct {
+	ID            uuid.UUID  `json:"id"`
+	VariantID     *uuid.UUID `json:"variant_id"`
+	Quantity      int32      `json:"quantity"`
+	PriceCents    int64      `json:"price_cents"`
+	Currency      string     `json:"currency"`
+	IntervalUnit  string     `json:"interval_unit"`
+	IntervalCount int32      `json:"interval_count"`
+	Status        string     `json:"status"`
+	NextBillingAt time.Time  `json:"next_billing_at"`
+	CancelledAt   *time.Time `json:"cancelled_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// GiftCard is a gift card issued to the customer, without its code
+type GiftCard struct {
+	ID             uuid.UUID  `json:"id"`
+	LastCharacters string     `json:"last_characters"`
+	Currency       string     `json:"currency"`
+	BalanceCents   int64      `json:"balance_cents"`
+	Status         string     `json:"status"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// User is a platform user's own record in an export
+type User struct {
+	ID          uuid.UUID  `json:"id"`
+	Email       string     `json:"email"`
+	DisplayName *string    `json:"display_name"`
+	VerifiedAt  *time.Time `json:"verified_at"`
+	MFAEnabled  bool       `json:"mfa_enabled"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Session is a sign-in of the user's
+type Session struct {
+	ID         uuid.UUID  `json:"id"`
+	UserAgent  string     `json:"user_agent"`
+	IPAddress  string     `json:"ip_address"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt time.Time  `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+// Membership is a tenant the user belongs to
+type Membership struct {
+	TenantID   uuid.UUID `json:"tenant_id"`
+	TenantName string    `json:"tenant_name"`
+	Status     string    `json:"status"`
+	Roles      []string  `json:"roles"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Identity is a Google, GitHub or OIDC account the user signs in with
+type Identity struct {
+	Provider    string     `json:"provider"`
+	Email       string     `json:"email"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+}
+
+// ExportCustomer writes everything held about a customer to a: their
+// record, addresses, orders, reviews, subscriptions and gift cards
+func ExportCustomer(ctx context.Context, q Queries, a *Archive, c database.Customer) error {
+	tags := c.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	if err := a.Add("customer.json", Customer{
+		ID:               c.ID,
+		StoreID:          c.StoreID,
+		Email:            string(c.Email),
+		FirstName:        nullString(c.FirstName),
+		LastName:         nullString(c.LastName),
+		Phone:            nullString(sql.NullString(c.Phone)),
+		AcceptsMarketing: c.AcceptsMarketing,
+		Status:           c.Status,
+		Tags:             tags,
+		CreatedAt:        c.CreatedAt,
+		UpdatedAt:        c.UpdatedAt,
+		ErasedAt:         nullTime(c.ErasedAt),
+	}); err != nil {
+		return err
+	}
+
+	addresses, err := q.GetCustomerAddresses(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	addressOut := make([]Address, 0, len(addresses))
+	for _, ad := range addresses {
+		addressOut = append(addressOut, Address{
+			ID:                ad.ID,
+			FirstName:         nullString(ad.FirstName),
+			LastName:          nullString(ad.LastName),
+			Company:           nullString(ad.Company),
+			Address1:          string(ad.Address1),
+			Address2:          nullString(sql.NullString(ad.Address2)),
+			City:              ad.City,
+			RegionCode:        nullString(ad.RegionCode),
+			PostalCode:        nullString(ad.PostalCode),
+			CountryCode:       ad.CountryCode,
+			Phone:             nullString(sql.NullString(ad.Phone)),
+			IsDefaultShipping: ad.IsDefaultShipping,
+			IsDefaultBilling:  ad.IsDefaultBilling,
+			CreatedAt:         ad.CreatedAt,
+		})
+	}
+	if err := a.Add("addresses.json", addressOut); err != nil {
+		return err
+	}
+
+	orders, err := customerOrders(ctx, q, c)
+	if err != nil {
+		return err
+	}
+	ids := make([]uuid.UUID, 0, len(orders))
+	for _, o := range orders {
+		ids = append(ids, o.ID)
+	}
+	items, err := q.ListOrdersLineItems(ctx, ids)
+	if err != nil {
+		return err
+	}
+	byOrder := make(map[uuid.UUID][]LineItem, len(orders))
+	for _, li := range items {
+		byOrder[li.OrderID] = append(byOrder[li.OrderID], LineItem{
+			Title:        li.Title,
+			VariantTitle: nullString(li.VariantTitle),
+			Sku:          nullString(li.Sku),
+			Quantity:     li.Quantity,
+			PriceCents:   li.PriceCents,
+			TotalCents:   li.TotalCents,
+		})
+	}
+	orderOut := make([]Order, 0, len(orders))
+	for _, o := range orders {
+		lineItems := byOrder[o.ID]
+		if lineItems == nil {
+			lineItems = []LineItem{}
+		}
+		order := Order{
+			ID:                o.ID,
+			OrderNumber:       o.OrderNumber,
+			Email:             nullString(sql.NullString(o.Email)),
+			Status:            o.Status,
+			FinancialStatus:   o.FinancialStatus,
+			FulfillmentStatus: o.FulfillmentStatus,
+			Currency:          o.Currency,
+			SubtotalCents:     o.SubtotalCents,
+			ShippingCents:     o.ShippingCents,
+			TaxCents:          o.TaxCents,
+			DiscountCents:     o.DiscountCents,
+			TotalCents:        o.TotalCents,
+			LineItems:         lineItems,
+			PlacedAt:          o.PlacedAt,
+		}
+		if o.ShippingAddress.Valid {
+			order.ShippingAddress = o.ShippingAddress.RawMessage
+		}
+		if o.BillingAddress.Valid {
+			order.BillingAddress = o.BillingAddress.RawMessage
+		}
+		orderOut = append(orderOut, order)
+	}
+	if err := a.Add("orders.json", orderOut); err != nil {
+		return err
+	}
+
+	customerID := uuid.NullUUID{UUID: c.ID, Valid: true}
+	reviews, err := q.ListCustomerReviews(ctx, customerID)
+	if err != nil {
+		return err
+	}
+	reviewOut := make([]Review, 0, len(reviews))
+	for _, r := range reviews {
+		reviewOut = append(reviewOut, Review{
+			ID:               r.ID,
+			ProductID:        r.ProductID,
+			AuthorName:       r.AuthorName,
+			Rating:           r.Rating,
+			Title:            nullString(r.Title),
+			Body:             r.Body,
+			VerifiedPurchase: r.VerifiedPurchase,
+			Status:           r.Status,
+			CreatedAt:        r.CreatedAt,
+		})
+	}
+	if err := a.Add("reviews.json", reviewOut); err != nil {
+		return err
+	}
+
+	subscriptions, err := q.ListCustomerSubscriptionContracts(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	subscriptionOut := make([]Subscription, 0, len(subscriptions))
+	for _, s := range subscriptions {
+		sub := Subscription{
+			ID:            s.ID,
+			Quantity:      s.Quantity,
+			PriceCents:    s.PriceCents,
+			Currency:      s.Currency,
+			IntervalUnit:  s.IntervalUnit,
+			IntervalCount: s.IntervalCount,
+			Status:        s.Status,
+			NextBillingAt: s.NextBillingAt,
+			CancelledAt:   nullTime(s.CancelledAt),
+			CreatedAt:     s.CreatedAt,
+		}
+		if s.VariantID.Valid {
+			sub.VariantID = &s.VariantID.UUID
+		}
+		subscriptionOut = append(subscriptionOut, sub)
+	}
+	if err := a.Add("subscriptions.json", subscriptionOut); err != nil {
+		return err
+	}
+
+	cards, err := q.ListCustomerGiftCards(ctx, customerID)
+	if err != nil {
+		return err
+	}
+	cardOut := make([]GiftCard, 0, len(cards))
+	for _, g := range cards {
+		cardOut = append(cardOut, GiftCard{
+			ID:             g.ID,
+			LastCharacters: g.LastCharacters,
+			Currency:       g.Currency,
+			BalanceCents:   g.BalanceCents,
+			Status:         g.Status,
+			ExpiresAt:      nullTime(g.ExpiresAt),
+			CreatedAt:      g.CreatedAt,
+		})
+	}
+	return a.Add("gift_cards.json", cardOut)
+}
+
+// ExportUser writes everything held about a platform user to a: their
+// account, sign-ins, linked accounts and tenant memberships
+func ExportUser(ctx context.Context, q Queries, a *Archive, u database.User) error {
+	mfa, err := q.GetUserMFA(ctx, u.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if err := a.Add("user.json", User{
+		ID:          u.ID,
+		Email:       u.Email,
+		DisplayName: nullString(u.DisplayName),
+		VerifiedAt:  nullTime(u.VerifiedAt),
+		MFAEnabled:  mfa.EnabledAt.Valid,
+		CreatedAt:   u.CreatedAt,
+		UpdatedAt:   u.UpdatedAt,
+	}); err != nil {
+		return err
+	}
+
+	sessions, err := q.ListAllUserSessions(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	sessionOut := make([]Session, 0, len(sessions))
+	for _, s := range sessions {
+		sessionOut = append(sessionOut, Session{
+			ID:         s.ID,
+			UserAgent:  s.UserAgent,
+			IPAddress:  s.IpAddress,
+			CreatedAt:  s.CreatedAt,
+			LastUsedAt: s.LastUsedAt,
+			RevokedAt:  nullTime(s.RevokedAt),
+		})
+	}
+	if err := a.Add("sessions.json", sessionOut); err != nil {
+		return err
+	}
+
+	identities, err := q.ListUserIdentities(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	identityOut := make([]Identity, 0, len(identities))
+	for _, i := range identities {
+		identityOut = append(identityOut, Identity{
+			Provider:    i.Provider,
+			Email:       i.Email,
+			CreatedAt:   i.CreatedAt,
+			LastLoginAt: nullTime(i.LastLoginAt),
+		})
+	}
+	if err := a.Add("identities.json", identityOut); err != nil {
+		return err
+	}
+
+	memberships, err := q.ListUserMemberships(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	membershipOut := make([]Membership, 0, len(memberships))
+	for _, m := range memberships {
+		if m.Roles == nil {
+			m.Roles = []string{}
+		}
+		membershipOut = append(membershipOut, Membership{
+			TenantID:   m.TenantID,
+			TenantName: m.TenantName,
+			Status:     m.Status,
+			Roles:      m.Roles,
+			CreatedAt:  m.CreatedAt,
+		})
+	}
+	return a.Add("memberships.json", membershipOut)
+}
+
+// customerOrders are the orders the customer placed signed in, and those
+// placed as a guest with their email
+func customerOrders(ctx context.Context, q Queries, c database.Customer) ([]database.Order, error) {
+	return q.ListCustomerPrivacyOrders(ctx, database.ListCustomerPrivacyOrdersParams{
+		StoreID:     c.StoreID,
+		CustomerID:  uuid.NullUUID{UUID: c.ID, Valid: true},
+		EmailDigest: crypto.Digest(c.Email),
+		Email:       string(c.Email),
+	})
+}
+
+func nullString(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,124 @@
+// Package privacy answers data subject requests: a copy of everything held
+// about a store customer or a platform user, as a zip of JSON files, or its
+// erasure. Erasure anonymizes the subject in place rather than deleting it,
+// so orders, gift cards and the audit log keep pointing at a record.
+package privacy
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/google/uuid"
+)
+
+// Request kinds
+const (
+	KindExport  = "export"
+	KindErasure = "erasure"
+)
+
+// Request statuses
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+// ContentType is the media type of an export archive
+const ContentType = "application/zip"
+
+// ErasedAuthorName replaces the name on an erased customer's reviews
+const ErasedAuthorName = "Former customer"
+
+// Args is the job that carries out one request
+type Args struct {
+	RequestID uuid.UUID `json:"request_id"`
+}
+
+func (Args) Kind() string { return "privacy.run" }
+
+// ErasedEmail is the address an erased subject is left with. It is unique
+// per subject, as emails must be, and can never be delivered to.
+func ErasedEmail(id uuid.UUID) string {
+	return fmt.Sprintf("erased-%s@erased.invalid", id)
+}
+
+// Queries is the slice of the database exports and erasures use
+type Queries interface {
+	GetCustomerAddresses(ctx context.Context, customerID uuid.UUID) ([]database.CustomerAddress, error)
+	ListCustomerPrivacyOrders(ctx context.Context, arg database.ListCustomerPrivacyOrdersParams) ([]database.Order, error)
+	ListOrdersLineItems(ctx context.Context, orderIds []uuid.UUID) ([]database.OrderLineItem, error)
+	ListCustomerReviews(ctx context.Context, customerID uuid.NullUUID) ([]database.ProductReview, error)
+	ListCustomerSubscriptionContracts(ctx context.Context, customerID uuid.UUID) ([]database.SubscriptionContract, error)
+	ListCustomerGiftCards(ctx context.Context, customerID uuid.NullUUID) ([]database.GiftCard, error)
+	ListAllUserSessions(ctx context.Context, userID uuid.UUID) ([]database.Session, error)
+	ListUserMemberships(ctx context.Context, userID uuid.UUID) ([]database.ListUserMembershipsRow, error)
+	ListUserIdentities(ctx context.Context, userID uuid.UUID) ([]database.UserIdentity, error)
+	GetUserMFA(ctx context.Context, userID uuid.UUID) (database.UserMfa, error)
+
+	CountUnfulfilledOrders(ctx context.Context, orderIds []uuid.UUID) (int64, error)
+	EraseOrdersContact(ctx context.Context, orderIds []uuid.UUID) error
+	DeleteCustomerAddresses(ctx context.Context, customerID uuid.UUID) error
+	DeleteCustomerRefreshTokens(ctx context.Context, customerID uuid.UUID) error
+	DeleteCustomerGroupMemberships(ctx context.Context, customerID uuid.UUID) error
+	DeleteCustomerSecurityEvents(ctx context.Context, customerID uuid.UUID) error
+	EraseCustomerReviews(ctx context.Context, arg database.EraseCustomerReviewsParams) error
+	CancelCustomerSubscriptions(ctx context.Context, customerID uuid.UUID) error
+	EraseAuditLogEntities(ctx context.Context, arg database.EraseAuditLogEntitiesParams) error
+	EraseCustomer(ctx context.Context, arg database.EraseCustomerParams) (database.Customer, error)
+
+	ListTenantsSolelyOwnedByUser(ctx context.Context, arg database.ListTenantsSolelyOwnedByUserParams) ([]uuid.UUID, error)
+	EraseUserAuditLogEntities(ctx context.Context, entityID uuid.NullUUID) error
+	DeleteUserMemberships(ctx context.Context, userID uuid.UUID) error
+	DeleteUserSessions(ctx context.Context, userID uuid.UUID) error
+	DeleteUserPasswordResetTokens(ctx context.Context, userID uuid.UUID) error
+	DeleteUserMagicLinkTokens(ctx context.Context, userID uuid.UUID) error
+	DeleteUserEmailVerificationTokens(ctx context.Context, userID uuid.UUID) error
+	DeleteUserMFAChallenges(ctx context.Context, userID uuid.UUID) error
+	DeleteUserMFA(ctx context.Context, userID uuid.UUID) error
+	DeleteMFARecoveryCodes(ctx context.Context, userID uuid.UUID) error
+	DeleteUserSecurityEvents(ctx context.Context, userID uuid.UUID) error
+	DeleteUserIdentities(ctx context.Context, userID uuid.UUID) error
+	EraseInvitationEmails(ctx context.Context, arg database.EraseInvitationEmailsParams) error
+	ErasePlatformAuditLogActor(ctx context.Context, arg database.ErasePlatformAuditLogActorParams) error
+	EraseUser(ctx context.Context, arg database.EraseUserParams) (database.User, error)
+}
+
+// Archive writes an export as a zip of JSON files
+type Archive struct {
+	zw *zip.Writer
+}
+
+// NewArchive starts an archive written to w
+func NewArchive(w io.Writer) *Archive {
+	return &Archive{zw: zip.NewWriter(w)}
+}
+
+// Add writes v to the archive as the indented JSON file name
+func (a *Archive) Add(name string, v any) error {
+	f, err := a.zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// Close finishes the archive. It doesn't close the underlying writer.
+func (a *Archive) Close() error {
+	return a.zw.Close()
+}
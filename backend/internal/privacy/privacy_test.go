@@ -0,0 +1,364 @@
+package privacy
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/crypto"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/google/uuid"
+)
+
+// fakeQueries records the writes made through it. Methods a test doesn't
+// expect panic through the nil embedded interface.
+type fakeQueries struct {
+	Queries
+
+	addresses   []database.CustomerAddress
+	orders      []database.Order
+	lineItems   []database.OrderLineItem
+	giftCards   []database.GiftCard
+	unfulfilled int64
+	soleOwned   []uuid.UUID
+
+	calls         []string
+	auditEntities []uuid.UUID
+	erasedEmail   string
+}
+
+func (f *fakeQueries) record(call string) error {
+	f.calls = append(f.calls, call)
+	return nil
+}
+
+func (f *fakeQueries) GetCustomerAddresses(context.Context, uuid.UUID) ([]database.CustomerAddress, error) {
+	return f.addresses, nil
+}
+
+func (f *fakeQueries) ListCustomerPrivacyOrders(context.Context, database.ListCustomerPrivacyOrdersParams) ([]database.Order, error) {
+	return f.orders, nil
+}
+
+func (f *fakeQueries) ListOrdersLineItems(context.Context, []uuid.UUID) ([]database.OrderLineItem, error) {
+	return f.lineItems, nil
+}
+
+func (f *fakeQueries) ListCustomerReviews(context.Context, uuid.NullUUID) ([]database.ProductReview, error) {
+	return nil, nil
+}
+
+func (f *fakeQueries) ListCustomerSubscriptionContracts(context.Context, uuid.UUID) ([]database.SubscriptionContract, error) {
+	return nil, nil
+}
+
+func (f *fakeQueries) ListCustomerGiftCards(context.Context, uuid.NullUUID) ([]database.GiftCard, error) {
+	return f.giftCards, nil
+}
+
+func (f *fakeQueries) CountUnfulfilledOrders(context.Context, []uuid.UUID) (int64, error) {
+	return f.unfulfilled, nil
+}
+
+func (f *fakeQueries) EraseOrdersContact(context.Context, []uuid.UUID) error {
+	return f.record("EraseOrdersContact")
+}
+
+func (f *fakeQueries) DeleteCustomerAddresses(context.Context, uuid.UUID) error {
+	return f.record("DeleteCustomerAddresses")
+}
+
+func (f *fakeQueries) DeleteCustomerRefreshTokens(context.Context, uuid.UUID) error {
+	return f.record("DeleteCustomerRefreshTokens")
+}
+
+func (f *fakeQueries) DeleteCustomerGroupMemberships(context.Context, uuid.UUID) error {
+	return f.record("DeleteCustomerGroupMemberships")
+}
+
+func (f *fakeQueries) DeleteCustomerSecurityEvents(context.Context, uuid.UUID) error {
+	return f.record("DeleteCustomerSecurityEvents")
+}
+
+func (f *fakeQueries) EraseCustomerReviews(context.Context, database.EraseCustomerReviewsParams) error {
+	return f.record("EraseCustomerReviews")
+}
+
+func (f *fakeQueries) CancelCustomerSubscriptions(context.Context, uuid.UUID) error {
+	return f.record("CancelCustomerSubscriptions")
+}
+
+func (f *fakeQueries) EraseAuditLogEntities(_ context.Context, arg database.EraseAuditLogEntitiesParams) error {
+	f.auditEntities = arg.EntityIds
+	return f.record("EraseAuditLogEntities")
+}
+
+func (f *fakeQueries) EraseCustomer(_ context.Context, arg database.EraseCustomerParams) (database.Customer, error) {
+	f.erasedEmail = string(arg.Email)
+	return database.Customer{}, f.record("EraseCustomer")
+}
+
+func (f *fakeQueries) ListTenantsSolelyOwnedByUser(context.Context, database.ListTenantsSolelyOwnedByUserParams) ([]uuid.UUID, error) {
+	return f.soleOwned, nil
+}
+
+func (f *fakeQueries) EraseUserAuditLogEntities(context.Context, uuid.NullUUID) error {
+	return f.record("EraseUserAuditLogEntities")
+}
+
+func (f *fakeQueries) DeleteUserMemberships(context.Context, uuid.UUID) error {
+	return f.record("DeleteUserMemberships")
+}
+
+func (f *fakeQueries) DeleteUserSessions(context.Context, uuid.UUID) error {
+	return f.record("DeleteUserSessions")
+}
+
+func (f *fakeQueries) DeleteUserPasswordResetTokens(context.Context, uuid.UUID) error {
+	return f.record("DeleteUserPasswordResetTokens")
+}
+
+func (f *fakeQueries) DeleteUserMagicLinkTokens(context.Context, uuid.UUID) error {
+	return f.record("DeleteUserMagicLinkTokens")
+}
+
+func (f *fakeQueries) DeleteUserEmailVerificationTokens(context.Context, uuid.UUID) error {
+	return f.record("DeleteUserEmailVerificationTokens")
+}
+
+func (f *fakeQueries) DeleteUserMFAChallenges(context.Context, uuid.UUID) error {
+	return f.record("DeleteUserMFAChallenges")
+}
+
+func (f *fakeQueries) DeleteMFARecoveryCodes(context.Context, uuid.UUID) error {
+	return f.record("DeleteMFARecoveryCodes")
+}
+
+func (f *fakeQueries) DeleteUserMFA(context.Context, uuid.UUID) error {
+	return f.record("DeleteUserMFA")
+}
+
+func (f *fakeQueries) DeleteUserSecurityEvents(context.Context, uuid.UUID) error {
+	return f.record("DeleteUserSecurityEvents")
+}
+
+func (f *fakeQueries) DeleteUserIdentities(context.Context, uuid.UUID) error {
+	return f.record("DeleteUserIdentities")
+}
+
+func (f *fakeQueries) EraseInvitationEmails(context.Context, database.EraseInvitationEmailsParams) error {
+	return f.record("EraseInvitationEmails")
+}
+
+func (f *fakeQueries) ErasePlatformAuditLogActor(context.Context, database.ErasePlatformAuditLogActorParams) error {
+	return f.record("ErasePlatformAuditLogActor")
+}
+
+func (f *fakeQueries) EraseUser(_ context.Context, arg database.EraseUserParams) (database.User, error) {
+	f.erasedEmail = string(arg.Email)
+	return database.User{}, f.record("EraseUser")
+}
+
+func TestErasedEmail(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	if ErasedEmail(a) == ErasedEmail(b) {
+		t.Errorf("ErasedEmail() gave two subjects the same address %q", ErasedEmail(a))
+	}
+	if got := ErasedEmail(a); !strings.HasSuffix(got, ".invalid") || !strings.Contains(got, a.String()) {
+		t.Errorf("ErasedEmail() = %q, want an address at a .invalid domain naming the subject", got)
+	}
+}
+
+func TestExportCustomer(t *testing.T) {
+	customer := database.Customer{
+		ID:        uuid.New(),
+		StoreID:   uuid.New(),
+		Email:     "ada@example.com",
+		FirstName: sql.NullString{String: "Ada", Valid: true},
+	}
+	first, second := uuid.New(), uuid.New()
+	q := &fakeQueries{
+		addresses: []database.CustomerAddress{{ID: uuid.New(), Address1: "1 Main St", City: "Springfield", CountryCode: "US"}},
+		orders: []database.Order{
+			{ID: first, OrderNumber: 1001, ShippingAddress: crypto.NullJSON{RawMessage: json.RawMessage(`{"city":"Springfield"}`), Valid: true}},
+			{ID: second, OrderNumber: 1002},
+		},
+		lineItems: []database.OrderLineItem{
+			{OrderID: first, Title: "Mug", Quantity: 2},
+			{OrderID: first, Title: "Spoon", Quantity: 1},
+		},
+		giftCards: []database.GiftCard{{ID: uuid.New(), CodeHash: "secret-hash", LastCharacters: "WXYZ"}},
+	}
+
+	var buf bytes.Buffer
+	a := NewArchive(&buf)
+	if err := ExportCustomer(context.Background(), q, a, customer); err != nil {
+		t.Fatalf("ExportCustomer() error = %v", err)
+	}
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	files := readArchive(t, buf.Bytes())
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	want := []string{"addresses.json", "customer.json", "gift_cards.json", "orders.json", "reviews.json", "subscriptions.json"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("files = %v, want %v", names, want)
+	}
+
+	var orders []Order
+	if err := json.Unmarshal(files["orders.json"], &orders); err != nil {
+		t.Fatal(err)
+	}
+	if len(orders) != 2 || len(orders[0].LineItems) != 2 || len(orders[1].LineItems) != 0 {
+		t.Fatalf("orders = %+v, want the two line items under the first order only", orders)
+	}
+	if orders[1].LineItems == nil {
+		t.Error("an order without line items has null line_items, want []")
+	}
+	var shipping map[string]string
+	if err := json.Unmarshal(orders[0].ShippingAddress, &shipping); err != nil || shipping["city"] != "Springfield" {
+		t.Errorf("shipping_address = %s, want the address as stored", orders[0].ShippingAddress)
+	}
+
+	var reviews []Review
+	if err := json.Unmarshal(files["reviews.json"], &reviews); err != nil || reviews == nil {
+		t.Errorf("reviews.json = %s, want an empty list", files["reviews.json"])
+	}
+	if bytes.Contains(files["gift_cards.json"], []byte("secret-hash")) {
+		t.Error("gift_cards.json holds the code hash")
+	}
+}
+
+func TestEraseCustomer(t *testing.T) {
+	order, address := uuid.New(), uuid.New()
+	tests := []struct {
+		name        string
+		erased      bool
+		unfulfilled int64
+		wantErr     string
+	}{
+		{name: "erased"},
+		{name: "already erased", erased: true, wantErr: "already been erased"},
+		{name: "orders to fulfill", unfulfilled: 1, wantErr: "open orders"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			customer := database.Customer{ID: uuid.New(), TenantID: uuid.New(), Email: "ada@example.com"}
+			if tt.erased {
+				customer.ErasedAt = sql.NullTime{Time: time.Now(), Valid: true}
+			}
+			q := &fakeQueries{
+				orders:      []database.Order{{ID: order}},
+				addresses:   []database.CustomerAddress{{ID: address}},
+				unfulfilled: tt.unfulfilled,
+			}
+
+			err := EraseCustomer(context.Background(), q, customer)
+			if tt.wantErr != "" {
+				if !errors.Is(err, service.ErrConflict) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("EraseCustomer() error = %v, want a conflict containing %q", err, tt.wantErr)
+				}
+				if len(q.calls) != 0 {
+					t.Errorf("writes = %v, want none", q.calls)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("EraseCustomer() error = %v", err)
+			}
+			if q.erasedEmail != ErasedEmail(customer.ID) {
+				t.Errorf("email = %q, want %q", q.erasedEmail, ErasedEmail(customer.ID))
+			}
+			if want := []uuid.UUID{customer.ID, order, address}; !reflect.DeepEqual(q.auditEntities, want) {
+				t.Errorf("audit log entities = %v, want %v", q.auditEntities, want)
+			}
+			if last := q.calls[len(q.calls)-1]; last != "EraseCustomer" {
+				t.Errorf("last write = %s, want the customer erased after what points at it", last)
+			}
+		})
+	}
+}
+
+func TestEraseUser(t *testing.T) {
+	tests := []struct {
+		name      string
+		erased    bool
+		soleOwned []uuid.UUID
+		wantCode  string
+		wantErr   bool
+	}{
+		{name: "erased"},
+		{name: "only owner of a tenant", soleOwned: []uuid.UUID{uuid.New()}, wantErr: true, wantCode: problem.CodeLastOwner},
+		{name: "already erased", erased: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := database.User{ID: uuid.New(), Email: "staff@example.com"}
+			if tt.erased {
+				user.ErasedAt = sql.NullTime{Time: time.Now(), Valid: true}
+			}
+			q := &fakeQueries{soleOwned: tt.soleOwned}
+
+			err := EraseUser(context.Background(), q, user)
+			if tt.wantErr {
+				var svcErr *service.Error
+				if !errors.As(err, &svcErr) || !errors.Is(err, service.ErrConflict) || svcErr.Code != tt.wantCode {
+					t.Fatalf("EraseUser() error = %v, want a conflict with code %q", err, tt.wantCode)
+				}
+				if len(q.calls) != 0 {
+					t.Errorf("writes = %v, want none", q.calls)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("EraseUser() error = %v", err)
+			}
+			if q.calls[0] != "EraseUserAuditLogEntities" || !slices.Contains(q.calls, "DeleteUserMemberships") {
+				t.Errorf("writes = %v, want the audit log scrubbed before the memberships go", q.calls)
+			}
+			if q.erasedEmail != ErasedEmail(user.ID) {
+				t.Errorf("email = %q, want %q", q.erasedEmail, ErasedEmail(user.ID))
+			}
+		})
+	}
+}
+
+func readArchive(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("archive is not a zip: %v", err)
+	}
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = b
+	}
+	return files
+}
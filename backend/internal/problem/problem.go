@@ -0,0 +1,204 @@
+// Package problem writes RFC 7807 problem details, the body of every API
+// error response. Code is the stable, machine-readable part that clients
+// branch on; Detail is written for people and may change.
+package problem
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// ContentType is the media type of a problem response
+const ContentType = "application/problem+json"
+
+// typePrefix namespaces Type. Problems are identified by code rather than
+// by a dereferenceable URL.
+const typePrefix = "urn:terminus:problem:"
+
+// requestIDHeader is set on every response by middleware.RequestID
+const requestIDHeader = "X-Request-Id"
+
+// Codes for when the status alone says enough
+const (
+	CodeBadRequest       = "bad_request"
+	CodeUnauthorized     = "unauthorized"
+	CodeForbidden        = "forbidden"
+	CodeNotFound         = "not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeConflict         = "conflict"
+	CodePayloadTooLarge  = "payload_too_large"
+	CodeUnprocessable    = "unprocessable"
+	CodeRateLimited      = "rate_limited"
+	CodeInternal         = "internal_error"
+	CodeUnavailable      = "unavailable"
+)
+
+// Codes for failures a client may want to tell apart from others with the
+// same status
+const (
+	// CodeValidationFailed comes with the failing fields in Errors
+	CodeValidationFailed = "validation_failed"
+
+	CodePermissionDenied   = "permission_denied"
+	CodeInsufficientScope  = "insufficient_scope"
+	CodeNotTenantMember    = "not_tenant_member"
+	CodeMembershipInactive = "membership_inactive"
+	CodeMFARequired        = "mfa_required"
+	CodeEmailNotVerified   = "email_not_verified"
+	CodeAccountDisabled    = "account_disabled"
+	// CodeAccountLocked means too many sign-ins failed; Retry-After says
+	// when to try again
+	CodeAccountLocked = "account_locked"
+	// CodeCaptchaRequired means the sign-in has to come with a solved
+	// CAPTCHA in captcha_token
+	CodeCaptchaRequired = "captcha_required"
+	// CodeSSORequired means a tenant the user belongs to only lets its
+	// members sign in through a provider
+	CodeSSORequired = "sso_required"
+
+	CodeHandleTaken   = "handle_taken"
+	CodeEmailTaken    = "email_taken"
+	CodeAlreadyExists = "already_exists"
+
+	CodeInvalidStatusTransition  = "invalid_status_transition"
+	CodeInsufficientStock        = "insufficient_stock"
+	CodeIdempotencyKeyReused     = "idempotency_key_reused"
+	CodeIdempotencyKeyInProgress = "idempotency_key_in_progress"
+	// CodeVersionConflict means the resource changed since the version the
+	// client sent; it should fetch it again and reapply its change
+	CodeVersionConflict = "version_conflict"
+	// CodeSystemRole means the role is built in and can't be changed; a
+	// clone of it can
+	CodeSystemRole = "system_role"
+	// CodeLastOwner means the change would leave a tenant with no active
+	// owner
+	CodeLastOwner = "last_owner"
+	// CodeUpgradeRequired means the plan's limit on what was being created
+	// is reached; a bigger plan allows more
+	CodeUpgradeRequired = "upgrade_required"
+	// CodeTenantSuspended means platform staff suspended the tenant; its
+	// members can't reach it until they lift the suspension
+	CodeTenantSuspended = "tenant_suspended"
+)
+
+var titles = map[string]string{
+	CodeValidationFailed:         "Validation failed",
+	CodePermissionDenied:         "Permission denied",
+	CodeInsufficientScope:        "Insufficient scope",
+	CodeNotTenantMember:          "Not a tenant member",
+	CodeMembershipInactive:       "Membership inactive",
+	CodeMFARequired:              "Two-factor authentication required",
+	CodeEmailNotVerified:         "Email not verified",
+	CodeAccountDisabled:          "Account disabled",
+	CodeAccountLocked:            "Account locked",
+	CodeCaptchaRequired:          "CAPTCHA required",
+	CodeSSORequired:              "Single sign-on required",
+	CodeHandleTaken:              "Handle already taken",
+	CodeEmailTaken:               "Email already taken",
+	CodeAlreadyExists:            "Already exists",
+	CodeInvalidStatusTransition:  "Invalid status transition",
+	CodeInsufficientStock:        "Insufficient stock",
+	CodeIdempotencyKeyReused:     "Idempotency key reused",
+	CodeIdempotencyKeyInProgress: "Idempotency key in progress",
+	CodeVersionConflict:          "Version conflict",
+	CodeSystemRole:               "System role",
+	CodeLastOwner:                "Last owner",
+	CodeUpgradeRequired:          "Upgrade required",
+	CodeTenantSuspended:          "Tenant suspended",
+}
+
+// Problem is an RFC 7807 problem detail, extended with the code, the
+// request ID to quote to support, and per-field errors
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Code      string       `json:"code"`
+	Detail    string       `json:"detail,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	// Suggestion is a value that would be accepted in place of the
+	// rejected one, such as a free handle when the requested one is taken
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// FieldError is one rejected field of a request body
+type FieldError struct {
+	// Field is the JSON name, dotted for nested fields, such as
+	// variants.0.sku
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// New returns a problem with the given code. An empty code becomes the
+// generic one for status.
+func New(status int, code, detail string) *Problem {
+	if code == "" {
+		code = CodeFor(status)
+	}
+	title, ok := titles[code]
+	if !ok {
+		title = http.StatusText(status)
+	}
+	return &Problem{
+		Type:   typePrefix + code,
+		Title:  title,
+		Status: status,
+		Code:   code,
+		Detail: detail,
+	}
+}
+
+// CodeFor is the generic code for status
+func CodeFor(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return CodePayloadTooLarge
+	case http.StatusUnprocessableEntity:
+		return CodeUnprocessable
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusServiceUnavailable:
+		return CodeUnavailable
+	}
+	if status >= 500 {
+		return CodeInternal
+	}
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
+
+// Write sends p, filling in the request ID from the response headers
+func Write(w http.ResponseWriter, p *Problem) {
+	if p.RequestID == "" {
+		p.RequestID = w.Header().Get(requestIDHeader)
+	}
+	dat, err := json.Marshal(p)
+	if err != nil {
+		log.Printf("Error marshalling problem: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	w.Write(dat)
+}
+
+// Error writes a problem with the given status, code and detail
+func Error(w http.ResponseWriter, status int, code, detail string) {
+	Write(w, New(status, code, detail))
+}
@@ -0,0 +1,58 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		code      string
+		wantCode  string
+		wantTitle string
+	}{
+		{name: "generic code from status", status: http.StatusNotFound, wantCode: CodeNotFound, wantTitle: "Not Found"},
+		{name: "server errors share a code", status: http.StatusBadGateway, wantCode: CodeInternal, wantTitle: "Bad Gateway"},
+		{name: "unlisted status", status: http.StatusGone, wantCode: "gone", wantTitle: "Gone"},
+		{name: "specific code", status: http.StatusConflict, code: CodeHandleTaken, wantCode: CodeHandleTaken, wantTitle: "Handle already taken"},
+		{name: "specific code without a title", status: http.StatusBadRequest, code: "invalid_cursor", wantCode: "invalid_cursor", wantTitle: "Bad Request"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(tt.status, tt.code, "detail")
+			if p.Code != tt.wantCode || p.Title != tt.wantTitle || p.Status != tt.status {
+				t.Errorf("New() = %+v, want code %q and title %q", p, tt.wantCode, tt.wantTitle)
+			}
+			if p.Type != "urn:terminus:problem:"+tt.wantCode {
+				t.Errorf("Type = %q", p.Type)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rec.Header().Set("X-Request-Id", "req-123")
+	p := New(http.StatusUnprocessableEntity, CodeValidationFailed, "The request has invalid fields")
+	p.Errors = []FieldError{{Field: "handle", Code: "format", Message: "must be lowercase letters, digits and dashes"}}
+	Write(rec, p)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status = %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != ContentType {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	var got Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.RequestID != "req-123" || got.Code != CodeValidationFailed || len(got.Errors) != 1 || got.Errors[0].Field != "handle" {
+		t.Errorf("body = %+v", got)
+	}
+}
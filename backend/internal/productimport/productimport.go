@@ -0,0 +1,366 @@
+// Package productimport reads product CSVs and applies them to a store's
+// catalog row by row: a row whose handle or SKU matches a product updates
+// it, and any other row creates one.
+//
+// The file's first line names its columns, in any order:
+//
+//	handle,name,description,sku,tags,status,inventory_tracked
+//
+// Only the columns present are written, and a blank cell leaves the
+// product's value as it was, so a file of handle and status alone
+// publishes or archives products without touching anything else.
+package productimport
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/service/products"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/google/uuid"
+)
+
+// Limits on an uploaded file
+const (
+	MaxBytes = 10 << 20
+	MaxRows  = 5000
+)
+
+// Import statuses
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+// Row outcomes
+const (
+	RowCreated = "created"
+	RowUpdated = "updated"
+	RowFailed  = "failed"
+)
+
+// Columns lists the header names a file may use
+var Columns = []string{"handle", "name", "description", "sku", "tags", "status", "inventory_tracked"}
+
+// ErrInvalidFile wraps every problem that makes a whole file unusable
+var ErrInvalidFile = errors.New("invalid product file")
+
+// Args is the job that applies one uploaded import
+type Args struct {
+	ImportID uuid.UUID `json:"import_id"`
+}
+
+func (Args) Kind() string { return "products.import" }
+
+// Row is one line of a file. Fields are nil when their column is missing
+// or the cell is blank.
+type Row struct {
+	// Line is where the row starts in the file, counting the header as 1
+	Line             int
+	Handle           *string
+	Name             *string
+	Description      *string
+	SKU              *string
+	Tags             *string
+	Status           *string
+	InventoryTracked *bool
+	// Errors are problems with the cells themselves, found while parsing
+	Errors []problem.FieldError
+}
+
+// Parse reads a whole file. Rows with bad cells are returned with Errors
+// so they can be reported alongside the rest; the error is for files that
+// can't be read at all, such as one with an unknown column.
+func Parse(r io.Reader) ([]Row, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: the file is empty", ErrInvalidFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+	columns, err := parseHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []Row
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+		}
+		line, _ := cr.FieldPos(0)
+		if blank(record) {
+			continue
+		}
+		if len(rows) == MaxRows {
+			return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidFile, MaxRows)
+		}
+		rows = append(rows, parseRow(line, columns, record))
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: the file has no rows", ErrInvalidFile)
+	}
+	return rows, nil
+}
+
+// parseHeader returns the column names in file order
+func parseHeader(header []string) ([]string, error) {
+	columns := make([]string, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch {
+		case !slices.Contains(Columns, name):
+			return nil, fmt.Errorf("%w: unknown column %q; columns are %s", ErrInvalidFile, name, strings.Join(Columns, ", "))
+		case slices.Contains(columns[:i], name):
+			return nil, fmt.Errorf("%w: column %q appears more than once", ErrInvalidFile, name)
+		}
+		columns[i] = name
+	}
+	if !slices.ContainsFunc(columns, func(c string) bool { return c == "handle" || c == "sku" || c == "name" }) {
+		return nil, fmt.Errorf("%w: a handle, sku or name column is required", ErrInvalidFile)
+	}
+	return columns, nil
+}
+
+func parseRow(line int, columns, record []string) Row {
+	row := Row{Line: line}
+	if len(record) != len(columns) {
+		row.Errors = append(row.Errors, problem.FieldError{
+			Field:   "row",
+			Code:    validate.CodeInvalid,
+			Message: fmt.Sprintf("has %d cells, but the header has %d columns", len(record), len(columns)),
+		})
+		return row
+	}
+
+	for i, column := range columns {
+		cell := strings.TrimSpace(record[i])
+		if cell == "" {
+			continue
+		}
+		switch column {
+		case "handle":
+			row.Handle = &cell
+		case "name":
+			row.Name = &cell
+		case "description":
+			row.Description = &cell
+		case "sku":
+			row.SKU = &cell
+		case "tags":
+			row.Tags = &cell
+		case "status":
+			status := strings.ToLower(cell)
+			row.Status = &status
+		case "inventory_tracked":
+			tracked, err := strconv.ParseBool(strings.ToLower(cell))
+			if err != nil {
+				row.Errors = append(row.Errors, problem.FieldError{
+					Field:   column,
+					Code:    validate.CodeInvalid,
+					Message: "must be true or false",
+				})
+				continue
+			}
+			row.InventoryTracked = &tracked
+		}
+	}
+	return row
+}
+
+func blank(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Catalog is what an import needs of the products service
+type Catalog interface {
+	Create(ctx context.Context, in products.CreateInput) (database.Product, error)
+	Update(ctx context.Context, in products.UpdateInput) (database.Product, error)
+}
+
+// Lookup finds the product a row refers to
+type Lookup interface {
+	GetProductByHandle(ctx context.Context, arg database.GetProductByHandleParams) (database.Product, error)
+	GetProductBySKU(ctx context.Context, arg database.GetProductBySKUParams) (database.Product, error)
+}
+
+// Importer applies rows to one store's catalog
+type Importer struct {
+	catalog Catalog
+	lookup  Lookup
+	storeID uuid.UUID
+}
+
+func NewImporter(catalog Catalog, lookup Lookup, storeID uuid.UUID) *Importer {
+	return &Importer{catalog: catalog, lookup: lookup, storeID: storeID}
+}
+
+// Result is the outcome of one row
+type Result struct {
+	Line   int
+	Status string
+	Handle string
+	SKU    string
+	// ProductID is the product created or updated; uuid.Nil when the row
+	// failed
+	ProductID uuid.UUID
+	Errors    []problem.FieldError
+}
+
+// Apply creates or updates the product a row describes. The handle is
+// matched first, then the SKU. Rows that break a rule come back as failed
+// Results; the error is for failures worth retrying, such as a lost
+// database connection.
+func (im *Importer) Apply(ctx context.Context, row Row) (Result, error) {
+	result := Result{Line: row.Line, Handle: deref(row.Handle), SKU: deref(row.SKU)}
+	if len(row.Errors) > 0 {
+		return failed(result, row.Errors), nil
+	}
+
+	existing, found, err := im.find(ctx, row)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var product database.Product
+	if found {
+		result.Status = RowUpdated
+		product, err = im.catalog.Update(ctx, products.UpdateInput{
+			StoreID:          im.storeID,
+			ID:               existing.ID,
+			Name:             row.Name,
+			Handle:           row.Handle,
+			Description:      row.Description,
+			InventoryTracked: row.InventoryTracked,
+			SKU:              row.SKU,
+			Tags:             row.Tags,
+			Status:           row.Status,
+		})
+	} else {
+		result.Status = RowCreated
+		tracked := true
+		if row.InventoryTracked != nil {
+			tracked = *row.InventoryTracked
+		}
+		product, err = im.catalog.Create(ctx, products.CreateInput{
+			StoreID:          im.storeID,
+			Name:             deref(row.Name),
+			Handle:           deref(row.Handle),
+			Description:      row.Description,
+			InventoryTracked: tracked,
+			SKU:              row.SKU,
+			Tags:             row.Tags,
+			Status:           deref(row.Status),
+		})
+	}
+	if err != nil {
+		if fields, ok := rowErrors(err); ok {
+			return failed(result, fields), nil
+		}
+		return Result{}, err
+	}
+
+	result.ProductID = product.ID
+	result.Handle = product.Handle
+	if product.Sku.Valid {
+		result.SKU = product.Sku.String
+	}
+	return result, nil
+}
+
+// find returns the product matching the row's handle, or failing that its
+// SKU
+func (im *Importer) find(ctx context.Context, row Row) (database.Product, bool, error) {
+	if row.Handle != nil {
+		product, err := im.lookup.GetProductByHandle(ctx, database.GetProductByHandleParams{
+			StoreID: im.storeID,
+			Handle:  *row.Handle,
+		})
+		if err == nil || !errors.Is(err, sql.ErrNoRows) {
+			return product, err == nil, err
+		}
+	}
+	if row.SKU != nil {
+		product, err := im.lookup.GetProductBySKU(ctx, database.GetProductBySKUParams{
+			StoreID: im.storeID,
+			Sku:     sql.NullString{String: *row.SKU, Valid: true},
+		})
+		if err == nil || !errors.Is(err, sql.ErrNoRows) {
+			return product, err == nil, err
+		}
+	}
+	return database.Product{}, false, nil
+}
+
+// rowErrors turns a rejection by the products service into field errors,
+// or reports false for any other failure
+func rowErrors(err error) ([]problem.FieldError, bool) {
+	var invalid *validate.Error
+	if errors.As(err, &invalid) {
+		return invalid.Fields, true
+	}
+	var svcErr *service.Error
+	if !errors.As(err, &svcErr) {
+		return nil, false
+	}
+
+	field, code, message := "row", svcErr.Code, svcErr.Message
+	switch {
+	case code == problem.CodeHandleTaken:
+		field = "handle"
+		if svcErr.Suggestion != "" {
+			message = fmt.Sprintf("%s; %s is free", message, svcErr.Suggestion)
+		}
+	case code == problem.CodeInvalidStatusTransition:
+		field = "status"
+	case errors.Is(err, service.ErrConflict):
+		// The only other clash a product write reports is its SKU
+		field, code = "sku", problem.CodeConflict
+	case code == "":
+		code = validate.CodeInvalid
+	}
+	return []problem.FieldError{{Field: field, Code: code, Message: message}}, true
+}
+
+func failed(result Result, errs []problem.FieldError) Result {
+	result.Status = RowFailed
+	result.ProductID = uuid.Nil
+	result.Errors = errs
+	return result
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,216 @@
+package productimport
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/service/products"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/google/uuid"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		check   func(t *testing.T, rows []Row)
+		wantErr string
+	}{
+		{
+			name: "columns in any order",
+			csv:  "\ufeffSKU, Name ,status,inventory_tracked\nTEE-1,Tee,Active,false\n\nMUG-1,Mug,,\n",
+			check: func(t *testing.T, rows []Row) {
+				if len(rows) != 2 {
+					t.Fatalf("got %d rows, want 2", len(rows))
+				}
+				tee, mug := rows[0], rows[1]
+				if tee.Line != 2 || *tee.SKU != "TEE-1" || *tee.Name != "Tee" || *tee.Status != "active" || *tee.InventoryTracked {
+					t.Errorf("tee = %+v", tee)
+				}
+				if mug.Line != 4 || mug.Status != nil || mug.InventoryTracked != nil || mug.Handle != nil {
+					t.Errorf("mug = %+v", mug)
+				}
+			},
+		},
+		{
+			name: "bad cells are reported on the row",
+			csv:  "handle,inventory_tracked\nshirt,maybe\nmug\n",
+			check: func(t *testing.T, rows []Row) {
+				if got := fields(rows[0].Errors); !reflect.DeepEqual(got, []string{"inventory_tracked"}) {
+					t.Errorf("row 1 errors = %v", got)
+				}
+				if got := fields(rows[1].Errors); !reflect.DeepEqual(got, []string{"row"}) {
+					t.Errorf("row 2 errors = %v", got)
+				}
+			},
+		},
+		{name: "empty", csv: "", wantErr: "empty"},
+		{name: "header only", csv: "handle,name\n", wantErr: "no rows"},
+		{name: "unknown column", csv: "handle,price\nshirt,10\n", wantErr: `unknown column "price"`},
+		{name: "duplicate column", csv: "handle,Handle\na,b\n", wantErr: "more than once"},
+		{name: "no identifying column", csv: "tags,status\nsale,active\n", wantErr: "handle, sku or name"},
+		{name: "malformed", csv: "handle,name\n\"shirt,Shirt\n", wantErr: "invalid product file"},
+		{name: "too many rows", csv: "handle\n" + strings.Repeat("x\n", MaxRows+1), wantErr: "more than"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := Parse(strings.NewReader(tt.csv))
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInvalidFile) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			tt.check(t, rows)
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	storeID := uuid.New()
+	shirt := database.Product{ID: uuid.New(), StoreID: storeID, Handle: "shirt", Status: "draft",
+		Sku: sql.NullString{String: "SHIRT-1", Valid: true}}
+
+	tests := []struct {
+		name       string
+		row        Row
+		catalogErr error
+		wantStatus string
+		wantFields []string
+		wantCreate bool
+	}{
+		{name: "matches handle", row: Row{Handle: ptr("shirt"), Status: ptr("active")}, wantStatus: RowUpdated},
+		{name: "matches sku", row: Row{Handle: ptr("new-shirt"), SKU: ptr("SHIRT-1")}, wantStatus: RowUpdated},
+		{name: "creates", row: Row{Name: ptr("Mug"), SKU: ptr("MUG-1")}, wantStatus: RowCreated, wantCreate: true},
+		{name: "bad cells skip the catalog", row: Row{Errors: []problem.FieldError{{Field: "row"}}}, wantStatus: RowFailed, wantFields: []string{"row"}},
+		{
+			name:       "validation",
+			row:        Row{Handle: ptr("mug")},
+			catalogErr: &validate.Error{Fields: []problem.FieldError{{Field: "name", Code: validate.CodeRequired}}},
+			wantStatus: RowFailed,
+			wantFields: []string{"name"},
+			wantCreate: true,
+		},
+		{
+			name:       "handle taken",
+			row:        Row{Handle: ptr("shirt"), SKU: ptr("OTHER")},
+			catalogErr: service.HandleTaken("taken", "shirt-2"),
+			wantStatus: RowFailed,
+			wantFields: []string{"handle"},
+		},
+		{
+			name:       "sku taken",
+			row:        Row{Name: ptr("Mug"), SKU: ptr("MUG-1")},
+			catalogErr: service.Conflict("A product with this SKU already exists"),
+			wantStatus: RowFailed,
+			wantFields: []string{"sku"},
+			wantCreate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalog := &fakeCatalog{err: tt.catalogErr}
+			im := NewImporter(catalog, fakeLookup{shirt}, storeID)
+
+			got, err := im.Apply(context.Background(), tt.row)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if got.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", got.Status, tt.wantStatus)
+			}
+			if f := fields(got.Errors); !reflect.DeepEqual(f, tt.wantFields) {
+				t.Errorf("error fields = %v, want %v", f, tt.wantFields)
+			}
+			if catalog.created != tt.wantCreate {
+				t.Errorf("created = %v, want %v", catalog.created, tt.wantCreate)
+			}
+			if tt.wantStatus == RowUpdated && catalog.updated != shirt.ID {
+				t.Errorf("updated %s, want %s", catalog.updated, shirt.ID)
+			}
+		})
+	}
+}
+
+func TestApplyRetriesOtherErrors(t *testing.T) {
+	im := NewImporter(&fakeCatalog{err: errors.New("connection reset")}, fakeLookup{}, uuid.New())
+	if _, err := im.Apply(context.Background(), Row{Name: ptr("Mug")}); err == nil {
+		t.Fatal("Apply() error = nil, want the catalog error")
+	}
+}
+
+func TestCreateDefaultsToTrackedInventory(t *testing.T) {
+	catalog := &fakeCatalog{}
+	im := NewImporter(catalog, fakeLookup{}, uuid.New())
+	if _, err := im.Apply(context.Background(), Row{Name: ptr("Mug")}); err != nil {
+		t.Fatal(err)
+	}
+	if !catalog.createInput.InventoryTracked {
+		t.Error("InventoryTracked = false, want true when the column is absent")
+	}
+}
+
+type fakeCatalog struct {
+	err         error
+	created     bool
+	createInput products.CreateInput
+	updated     uuid.UUID
+}
+
+func (f *fakeCatalog) Create(_ context.Context, in products.CreateInput) (database.Product, error) {
+	f.created, f.createInput = true, in
+	if f.err != nil {
+		return database.Product{}, f.err
+	}
+	return database.Product{ID: uuid.New(), StoreID: in.StoreID, Handle: in.Handle}, nil
+}
+
+func (f *fakeCatalog) Update(_ context.Context, in products.UpdateInput) (database.Product, error) {
+	f.updated = in.ID
+	if f.err != nil {
+		return database.Product{}, f.err
+	}
+	return database.Product{ID: in.ID, StoreID: in.StoreID}, nil
+}
+
+type fakeLookup []database.Product
+
+func (f fakeLookup) GetProductByHandle(_ context.Context, arg database.GetProductByHandleParams) (database.Product, error) {
+	for _, p := range f {
+		if p.StoreID == arg.StoreID && p.Handle == arg.Handle {
+			return p, nil
+		}
+	}
+	return database.Product{}, sql.ErrNoRows
+}
+
+func (f fakeLookup) GetProductBySKU(_ context.Context, arg database.GetProductBySKUParams) (database.Product, error) {
+	for _, p := range f {
+		if p.StoreID == arg.StoreID && p.Sku == arg.Sku {
+			return p, nil
+		}
+	}
+	return database.Product{}, sql.ErrNoRows
+}
+
+func fields(errs []problem.FieldError) []string {
+	var out []string
+	for _, e := range errs {
+		out = append(out, e.Field)
+	}
+	return out
+}
+
+func ptr(s string) *string { return &s }
@@ -0,0 +1,50 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps counters in the process. Each instance counts on its
+// own, so limits are per instance when more than one is running.
+type MemoryStore struct {
+	mu       sync.Mutex
+	counters map[string]memoryCounter
+	swept    time.Time
+}
+
+type memoryCounter struct {
+	windowStart time.Time
+	count       int64
+	expires     time.Time
+}
+
+// NewMemoryStore returns an empty in-process store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{counters: map[string]memoryCounter{}}
+}
+
+func (s *MemoryStore) Increment(_ context.Context, key string, windowStart time.Time, window time.Duration) (int64, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Counters of past windows are dropped at most once a second
+	if now.Sub(s.swept) >= time.Second {
+		for k, c := range s.counters {
+			if !now.Before(c.expires) {
+				delete(s.counters, k)
+			}
+		}
+		s.swept = now
+	}
+
+	c := s.counters[key]
+	if !c.windowStart.Equal(windowStart) {
+		c = memoryCounter{windowStart: windowStart, expires: windowStart.Add(window)}
+	}
+	c.count++
+	s.counters[key] = c
+	return c.count, nil
+}
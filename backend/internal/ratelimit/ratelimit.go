@@ -0,0 +1,189 @@
+// Package ratelimit counts requests in fixed windows per key and decides
+// whether a request fits its quota. Counters live in memory for a single
+// instance or in Redis when several API instances share the limits.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPlan is the plan whose quota applies when a caller has no store
+// or only stores on plans without a quota of their own
+const DefaultPlan = "free"
+
+// DefaultPlans are the quotas used when RATE_LIMIT_PLANS is unset
+const DefaultPlans = "free=600/1m,pro=6000/1m"
+
+// Quota is how many requests fit in each window
+type Quota struct {
+	Requests int
+	Window   time.Duration
+}
+
+// rate is the quota in requests per second, for comparing quotas with
+// different windows
+func (q Quota) rate() float64 {
+	return float64(q.Requests) / q.Window.Seconds()
+}
+
+// Plans maps store plan names to their quotas
+type Plans map[string]Quota
+
+// ParsePlans reads a list such as "free=600/1m,pro=6000/1m". The default
+// plan must be among them.
+func ParsePlans(s string) (Plans, error) {
+	plans := Plans{}
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, spec, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%q is not plan=requests/window", entry)
+		}
+		requests, window, ok := strings.Cut(strings.TrimSpace(spec), "/")
+		n, err := strconv.Atoi(requests)
+		if !ok || err != nil || n <= 0 {
+			return nil, fmt.Errorf("%q needs a positive request count and a window, such as %s=600/1m", entry, name)
+		}
+		d, err := time.ParseDuration(window)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("%q has an invalid window", entry)
+		}
+		if _, dup := plans[name]; dup {
+			return nil, fmt.Errorf("plan %q is listed twice", name)
+		}
+		plans[name] = Quota{Requests: n, Window: d}
+	}
+	if _, ok := plans[DefaultPlan]; !ok {
+		return nil, fmt.Errorf("a quota for the %s plan is required", DefaultPlan)
+	}
+	return plans, nil
+}
+
+// Best is the most generous quota among the named plans. Names without a
+// quota are ignored, and the default plan's quota applies when none has one.
+func (p Plans) Best(names []string) Quota {
+	best := p[DefaultPlan]
+	for _, name := range names {
+		if q, ok := p[name]; ok && q.rate() > best.rate() {
+			best = q
+		}
+	}
+	return best
+}
+
+// Store keeps the counters
+type Store interface {
+	// Increment adds one to the counter of key for the window starting at
+	// windowStart and returns the new count. The counter may be dropped
+	// once the window has passed.
+	Increment(ctx context.Context, key string, windowStart time.Time, window time.Duration) (int64, error)
+}
+
+// Result is the outcome of counting one request
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAt is when the window ends and the count starts over
+	ResetAt time.Time
+}
+
+// SetHeaders writes the X-RateLimit-* headers, and Retry-After when the
+// request was turned away
+func (r Result) SetHeaders(h http.Header, now time.Time) {
+	h.Set("X-RateLimit-Limit", strconv.Itoa(r.Limit))
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(r.Remaining))
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(r.ResetAt.Unix(), 10))
+	if !r.Allowed {
+		retry := int(math.Ceil(r.ResetAt.Sub(now).Seconds()))
+		h.Set("Retry-After", strconv.Itoa(max(retry, 1)))
+	}
+}
+
+// Limiter counts requests against their quotas
+type Limiter struct {
+	store Store
+	now   func() time.Time
+}
+
+// New returns a limiter counting in store
+func New(store Store) *Limiter {
+	return &Limiter{store: store, now: time.Now}
+}
+
+// Allow counts a request under key and reports whether it fits q. Windows
+// are aligned to the clock so every instance agrees on where they start.
+func (l *Limiter) Allow(ctx context.Context, key string, q Quota) (Result, error) {
+	start := l.now().Truncate(q.Window)
+	count, err := l.store.Increment(ctx, key, start, q.Window)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{
+		Allowed:   count <= int64(q.Requests),
+		Limit:     q.Requests,
+		Remaining: int(max(int64(q.Requests)-count, 0)),
+		ResetAt:   start.Add(q.Window),
+	}, nil
+}
+
+// QuotaCache remembers quotas for a while, so working out a caller's plan
+// doesn't cost a query on every request
+type QuotaCache struct {
+	ttl     time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[string]cachedQuota
+	swept   time.Time
+}
+
+type cachedQuota struct {
+	quota   Quota
+	expires time.Time
+}
+
+// NewQuotaCache returns a cache keeping each quota for ttl
+func NewQuotaCache(ttl time.Duration) *QuotaCache {
+	return &QuotaCache{ttl: ttl, now: time.Now, entries: map[string]cachedQuota{}}
+}
+
+// Get returns the cached quota of key, calling load when there is none or
+// it has expired. Load errors are not cached.
+func (c *QuotaCache) Get(ctx context.Context, key string, load func(ctx context.Context) (Quota, error)) (Quota, error) {
+	now := c.now()
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(e.expires) {
+		return e.quota, nil
+	}
+
+	q, err := load(ctx)
+	if err != nil {
+		return Quota{}, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Expired entries are dropped at most once per ttl
+	if now.Sub(c.swept) >= c.ttl {
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		c.swept = now
+	}
+	c.entries[key] = cachedQuota{quota: q, expires: now.Add(c.ttl)}
+	return q, nil
+}
@@ -0,0 +1,283 @@
+package ratelimit
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParsePlans(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Plans
+		wantErr bool
+	}{
+		{in: DefaultPlans, want: Plans{"free": {600, time.Minute}, "pro": {6000, time.Minute}}},
+		{in: " free = 10/1s , pro=100/1s,", want: Plans{"free": {10, time.Second}, "pro": {100, time.Second}}},
+		{in: "free=5/1h", want: Plans{"free": {5, time.Hour}}},
+		{in: "pro=100/1m", wantErr: true},
+		{in: "free", wantErr: true},
+		{in: "free=0/1m", wantErr: true},
+		{in: "free=10", wantErr: true},
+		{in: "free=10/soon", wantErr: true},
+		{in: "free=10/-1m", wantErr: true},
+		{in: "free=10/1m,free=20/1m", wantErr: true},
+		{in: "=10/1m,free=20/1m", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePlans(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePlans(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("ParsePlans(%q) = %v, want %v", tt.in, got, tt.want)
+			continue
+		}
+		for name, q := range tt.want {
+			if got[name] != q {
+				t.Errorf("ParsePlans(%q)[%s] = %v, want %v", tt.in, name, got[name], q)
+			}
+		}
+	}
+}
+
+func TestPlansBest(t *testing.T) {
+	plans := Plans{
+		"free":       {Requests: 600, Window: time.Minute},
+		"pro":        {Requests: 6000, Window: time.Minute},
+		"burst":      {Requests: 50, Window: time.Second},
+		"enterprise": {Requests: 10000, Window: time.Hour},
+	}
+	tests := []struct {
+		names []string
+		want  Quota
+	}{
+		{names: nil, want: plans["free"]},
+		{names: []string{"free"}, want: plans["free"]},
+		{names: []string{"free", "pro"}, want: plans["pro"]},
+		{names: []string{"legacy"}, want: plans["free"]},
+		{names: []string{"pro", "burst"}, want: plans["pro"]},
+		{names: []string{"free", "enterprise"}, want: plans["free"]},
+	}
+	for _, tt := range tests {
+		if got := plans.Best(tt.names); got != tt.want {
+			t.Errorf("Best(%q) = %v, want %v", tt.names, got, tt.want)
+		}
+	}
+}
+
+func TestLimiterAllow(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 10, 0, time.UTC)
+	l := New(NewMemoryStore())
+	l.now = func() time.Time { return now }
+	q := Quota{Requests: 2, Window: time.Minute}
+
+	steps := []struct {
+		key       string
+		advance   time.Duration
+		allowed   bool
+		remaining int
+	}{
+		{key: "a", allowed: true, remaining: 1},
+		{key: "a", allowed: true, remaining: 0},
+		{key: "a", allowed: false, remaining: 0},
+		{key: "b", allowed: true, remaining: 1},
+		// The next window starts at the top of the minute
+		{key: "a", advance: 50 * time.Second, allowed: true, remaining: 1},
+	}
+	for i, s := range steps {
+		now = now.Add(s.advance)
+		res, err := l.Allow(context.Background(), s.key, q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Allowed != s.allowed || res.Remaining != s.remaining || res.Limit != 2 {
+			t.Errorf("step %d: Allow(%s) = %+v, want allowed %v with %d remaining", i, s.key, res, s.allowed, s.remaining)
+		}
+		if want := now.Truncate(time.Minute).Add(time.Minute); !res.ResetAt.Equal(want) {
+			t.Errorf("step %d: ResetAt = %v, want %v", i, res.ResetAt, want)
+		}
+	}
+}
+
+func TestResultSetHeaders(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 10, 500, time.UTC)
+	reset := time.Date(2026, 10, 18, 12, 1, 0, 0, time.UTC)
+
+	h := http.Header{}
+	Result{Allowed: true, Limit: 600, Remaining: 599, ResetAt: reset}.SetHeaders(h, now)
+	if h.Get("X-RateLimit-Limit") != "600" || h.Get("X-RateLimit-Remaining") != "599" || h.Get("X-RateLimit-Reset") != strconv.FormatInt(reset.Unix(), 10) {
+		t.Errorf("headers = %v", h)
+	}
+	if h.Get("Retry-After") != "" {
+		t.Errorf("Retry-After = %q on an allowed request", h.Get("Retry-After"))
+	}
+
+	h = http.Header{}
+	Result{Limit: 600, ResetAt: reset}.SetHeaders(h, now)
+	if h.Get("X-RateLimit-Remaining") != "0" || h.Get("Retry-After") != "50" {
+		t.Errorf("headers = %v", h)
+	}
+}
+
+func TestQuotaCache(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	c := NewQuotaCache(time.Minute)
+	c.now = func() time.Time { return now }
+
+	loads := 0
+	quota := Quota{Requests: 1, Window: time.Second}
+	var loadErr error
+	load := func(context.Context) (Quota, error) {
+		loads++
+		return quota, loadErr
+	}
+	get := func() Quota {
+		t.Helper()
+		q, err := c.Get(context.Background(), "tenant:1", load)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return q
+	}
+
+	get()
+	quota = Quota{Requests: 2, Window: time.Second}
+	if q := get(); q.Requests != 1 || loads != 1 {
+		t.Errorf("cached Get() = %v after %d loads", q, loads)
+	}
+	now = now.Add(time.Minute)
+	if q := get(); q.Requests != 2 || loads != 2 {
+		t.Errorf("expired Get() = %v after %d loads", q, loads)
+	}
+
+	now = now.Add(time.Minute)
+	loadErr = errors.New("database unavailable")
+	if _, err := c.Get(context.Background(), "tenant:1", load); err == nil {
+		t.Error("Get() hid the load error")
+	}
+	loadErr = nil
+	get()
+	if loads != 4 {
+		t.Errorf("loads = %d, want 4 as errors aren't cached", loads)
+	}
+}
+
+// fakeRedis answers INCR, PEXPIRE and AUTH like Redis, keeping counts in
+// a map, and any other command as one it doesn't know, the way Redis
+// before 6 answers HELLO
+func fakeRedis(t *testing.T, password string) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("no loopback listener")
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	var mu sync.Mutex
+	counts := map[string]int64{}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				rd := bufio.NewReader(conn)
+				authed := password == ""
+				for {
+					args, err := readCommand(rd)
+					if err != nil {
+						return
+					}
+					args[0] = strings.ToUpper(args[0])
+					switch {
+					case args[0] == "AUTH" && args[len(args)-1] == password:
+						authed = true
+						fmt.Fprint(conn, "+OK\r\n")
+					case args[0] == "AUTH":
+						fmt.Fprint(conn, "-WRONGPASS invalid password\r\n")
+					case !authed:
+						fmt.Fprint(conn, "-NOAUTH Authentication required\r\n")
+					case args[0] == "INCR":
+						mu.Lock()
+						counts[args[1]]++
+						n := counts[args[1]]
+						mu.Unlock()
+						fmt.Fprintf(conn, ":%d\r\n", n)
+					case args[0] == "PEXPIRE":
+						fmt.Fprint(conn, ":1\r\n")
+					default:
+						fmt.Fprintf(conn, "-ERR unknown command '%s'\r\n", args[0])
+					}
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func readCommand(rd *bufio.Reader) ([]string, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+	args := make([]string, n)
+	for i := range args {
+		if _, err := rd.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := rd.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimRight(arg, "\r\n")
+	}
+	return args, nil
+}
+
+func TestRedisStore(t *testing.T) {
+	addr := fakeRedis(t, "hunter2")
+	start := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	s, err := NewRedisStore("redis://:hunter2@"+addr, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	for want := int64(1); want <= 3; want++ {
+		got, err := s.Increment(context.Background(), "tenant:1", start, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("Increment() = %d, want %d", got, want)
+		}
+	}
+	if got, err := s.Increment(context.Background(), "tenant:1", start.Add(time.Minute), time.Minute); err != nil || got != 1 {
+		t.Errorf("Increment() in the next window = %d, %v, want 1", got, err)
+	}
+
+	bad, err := NewRedisStore("redis://:wrong@"+addr, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bad.Increment(context.Background(), "tenant:1", start, time.Minute); err == nil || !strings.Contains(err.Error(), "WRONGPASS") {
+		t.Errorf("Increment() with a wrong password error = %v", err)
+	}
+
+	if _, err := NewRedisStore("http://"+addr, 2); err == nil {
+		t.Error("NewRedisStore() accepted an http URL")
+	}
+}
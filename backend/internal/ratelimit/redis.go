@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisTimeout bounds a round trip, so a slow Redis can't hold requests up
+const redisTimeout = 500 * time.Millisecond
+
+// RedisStore keeps counters in Redis so every API instance shares them.
+// Each window of a key is its own Redis key, counted with INCR and expired
+// with PEXPIRE in one round trip.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore returns a store for a redis:// or rediss:// URL.
+// Connections are dialled as needed and up to poolSize are kept open.
+func NewRedisStore(rawURL string, poolSize int) (*RedisStore, error) {
+	opts, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+	opts.PoolSize = poolSize
+	opts.DialTimeout = redisTimeout
+	opts.ReadTimeout = redisTimeout
+	opts.WriteTimeout = redisTimeout
+	// A retried INCR may have counted already
+	opts.MaxRetries = -1
+	// Counting needs neither RESP3 nor the client name Redis is told by
+	// default, so connecting is only AUTH
+	opts.Protocol = 2
+	opts.DisableIdentity = true
+	return &RedisStore{client: redis.NewClient(opts)}, nil
+}
+
+func (s *RedisStore) Increment(ctx context.Context, key string, windowStart time.Time, window time.Duration) (int64, error) {
+	// The key outlives its window by a second so clock skew between
+	// instances can't reset a count early
+	k := "ratelimit:" + key + ":" + strconv.FormatInt(windowStart.UnixMilli(), 10)
+	var count *redis.IntCmd
+	_, err := s.client.Pipelined(ctx, func(p redis.Pipeliner) error {
+		count = p.Incr(ctx, k)
+		p.PExpire(ctx, k, window+time.Second)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count.Val(), nil
+}
+
+// Close closes the connections
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
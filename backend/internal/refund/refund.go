@@ -0,0 +1,168 @@
+// Package refund works out what a refund covers: which line items and
+// quantities go back to the shopper, how much money is returned, and the
+// order's financial status afterwards.
+package refund
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidItems      = errors.New("invalid refund items")
+	ErrInvalidAmount     = errors.New("invalid refund amount")
+	ErrNothingRefundable = errors.New("nothing left to refund")
+)
+
+// Order financial statuses set by refunds, matching orders.financial_status
+const (
+	StatusPartiallyRefunded = "partially_refunded"
+	StatusRefunded          = "refunded"
+)
+
+// Refundable reports whether an order in this financial status can be refunded
+func Refundable(financialStatus string) bool {
+	return financialStatus == "paid" || financialStatus == StatusPartiallyRefunded
+}
+
+// Line is an order line item and what has already been refunded against it
+type Line struct {
+	ID               uuid.UUID
+	Quantity         int64
+	TotalCents       int64
+	RefundedQuantity int64
+	RefundedCents    int64
+}
+
+// Item is a quantity of one line item to refund
+type Item struct {
+	LineItemID uuid.UUID
+	Quantity   int64
+	Restock    bool
+}
+
+// PlannedItem is a refunded line item with its share of the refund
+type PlannedItem struct {
+	Item
+	AmountCents int64
+}
+
+// Plan is a validated refund ready to record
+type Plan struct {
+	Items       []PlannedItem
+	AmountCents int64
+}
+
+// Order is the order-level state a refund is checked against
+type Order struct {
+	TotalCents    int64
+	RefundedCents int64
+}
+
+// Remaining is how much of the order can still be refunded
+func (o Order) Remaining() int64 {
+	return max(o.TotalCents-o.RefundedCents, 0)
+}
+
+// Build validates a refund request and works out its amount.
+//
+// With neither items nor an amount the refund is full: every line item not
+// yet refunded, and everything left on the order including shipping and tax.
+// Items alone are refunded at their share of the line total. An amount
+// overrides the computed one, so merchants can refund shipping, keep a
+// restocking fee, or return items without refunding money (amount 0).
+func Build(order Order, lines []Line, items []Item, amountCents *int64, restockAll bool) (Plan, error) {
+	remaining := order.Remaining()
+
+	full := len(items) == 0 && amountCents == nil
+	if full {
+		for _, line := range lines {
+			if left := line.Quantity - line.RefundedQuantity; left > 0 {
+				items = append(items, Item{LineItemID: line.ID, Quantity: left, Restock: restockAll})
+			}
+		}
+		if len(items) == 0 && remaining == 0 {
+			return Plan{}, ErrNothingRefundable
+		}
+	}
+
+	planned, err := allocate(lines, items)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	var amount int64
+	if full {
+		amount = remaining
+	} else if amountCents != nil {
+		amount = *amountCents
+		if amount < 0 {
+			return Plan{}, fmt.Errorf("%w: amount must not be negative", ErrInvalidAmount)
+		}
+		if amount == 0 && len(planned) == 0 {
+			return Plan{}, fmt.Errorf("%w: amount must be positive when no items are refunded", ErrInvalidAmount)
+		}
+	} else {
+		for _, item := range planned {
+			amount += item.AmountCents
+		}
+	}
+
+	if amount > remaining {
+		return Plan{}, fmt.Errorf("%w: only %d cents left to refund", ErrInvalidAmount, remaining)
+	}
+	return Plan{Items: planned, AmountCents: amount}, nil
+}
+
+// allocate checks items against the order's lines, merging repeated lines
+// and pricing each at its share of the line total. The last units of a line
+// take whatever is left of it so rounding never loses or adds a cent.
+func allocate(lines []Line, items []Item) ([]PlannedItem, error) {
+	byID := make(map[uuid.UUID]Line, len(lines))
+	for _, line := range lines {
+		byID[line.ID] = line
+	}
+
+	var out []PlannedItem
+	index := map[uuid.UUID]int{}
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: quantities must be positive", ErrInvalidItems)
+		}
+		if _, ok := byID[item.LineItemID]; !ok {
+			return nil, fmt.Errorf("%w: line item %s is not on this order", ErrInvalidItems, item.LineItemID)
+		}
+		if i, ok := index[item.LineItemID]; ok {
+			out[i].Quantity += item.Quantity
+			out[i].Restock = out[i].Restock || item.Restock
+			continue
+		}
+		index[item.LineItemID] = len(out)
+		out = append(out, PlannedItem{Item: item})
+	}
+
+	for i, item := range out {
+		line := byID[item.LineItemID]
+		left := line.Quantity - line.RefundedQuantity
+		if item.Quantity > left {
+			return nil, fmt.Errorf("%w: only %d of line item %s left to refund", ErrInvalidItems, left, item.LineItemID)
+		}
+		if item.Quantity == left {
+			out[i].AmountCents = max(line.TotalCents-line.RefundedCents, 0)
+		} else {
+			out[i].AmountCents = line.TotalCents * item.Quantity / line.Quantity
+		}
+	}
+	return out, nil
+}
+
+// FinancialStatus is the order's financial status once refunded cents have
+// been returned
+func FinancialStatus(order Order) string {
+	if order.RefundedCents >= order.TotalCents {
+		return StatusRefunded
+	}
+	return StatusPartiallyRefunded
+}
@@ -0,0 +1,146 @@
+package refund
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestBuild(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	lines := []Line{
+		{ID: a, Quantity: 3, TotalCents: 1000},
+		{ID: b, Quantity: 1, TotalCents: 500, RefundedQuantity: 1, RefundedCents: 500},
+	}
+	order := Order{TotalCents: 2000, RefundedCents: 500}
+	amount := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		name       string
+		order      Order
+		lines      []Line
+		items      []Item
+		amount     *int64
+		restockAll bool
+		wantAmount int64
+		wantItems  []PlannedItem
+		wantErr    error
+	}{
+		{
+			name:       "full refund covers remaining items and order total",
+			order:      order,
+			restockAll: true,
+			wantAmount: 1500,
+			wantItems:  []PlannedItem{{Item: Item{LineItemID: a, Quantity: 3, Restock: true}, AmountCents: 1000}},
+		},
+		{
+			name:       "partial quantity rounds down",
+			order:      order,
+			items:      []Item{{LineItemID: a, Quantity: 1}},
+			wantAmount: 333,
+			wantItems:  []PlannedItem{{Item: Item{LineItemID: a, Quantity: 1}, AmountCents: 333}},
+		},
+		{
+			name:       "merged quantities take the rest of the line",
+			order:      order,
+			items:      []Item{{LineItemID: a, Quantity: 1}, {LineItemID: a, Quantity: 2, Restock: true}},
+			wantAmount: 1000,
+			wantItems:  []PlannedItem{{Item: Item{LineItemID: a, Quantity: 3, Restock: true}, AmountCents: 1000}},
+		},
+		{
+			name:       "amount overrides item total",
+			order:      order,
+			items:      []Item{{LineItemID: a, Quantity: 1}},
+			amount:     amount(250),
+			wantAmount: 250,
+			wantItems:  []PlannedItem{{Item: Item{LineItemID: a, Quantity: 1}, AmountCents: 333}},
+		},
+		{
+			name:       "amount only",
+			order:      order,
+			amount:     amount(100),
+			wantAmount: 100,
+		},
+		{
+			name:       "return without money",
+			order:      order,
+			items:      []Item{{LineItemID: a, Quantity: 1, Restock: true}},
+			amount:     amount(0),
+			wantAmount: 0,
+			wantItems:  []PlannedItem{{Item: Item{LineItemID: a, Quantity: 1, Restock: true}, AmountCents: 333}},
+		},
+		{name: "zero amount alone", order: order, amount: amount(0), wantErr: ErrInvalidAmount},
+		{name: "negative amount", order: order, amount: amount(-1), wantErr: ErrInvalidAmount},
+		{name: "amount over remaining", order: order, amount: amount(1501), wantErr: ErrInvalidAmount},
+		{name: "already refunded line", order: order, items: []Item{{LineItemID: b, Quantity: 1}}, wantErr: ErrInvalidItems},
+		{name: "over quantity", order: order, items: []Item{{LineItemID: a, Quantity: 4}}, wantErr: ErrInvalidItems},
+		{name: "unknown line", order: order, items: []Item{{LineItemID: uuid.New(), Quantity: 1}}, wantErr: ErrInvalidItems},
+		{name: "zero quantity", order: order, items: []Item{{LineItemID: a}}, wantErr: ErrInvalidItems},
+		{
+			name:    "fully refunded order",
+			order:   Order{TotalCents: 500, RefundedCents: 500},
+			lines:   lines[1:],
+			wantErr: ErrNothingRefundable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := tt.lines
+			if l == nil {
+				l = lines
+			}
+			got, err := Build(tt.order, l, tt.items, tt.amount, tt.restockAll)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.AmountCents != tt.wantAmount {
+				t.Errorf("AmountCents = %d, want %d", got.AmountCents, tt.wantAmount)
+			}
+			if len(got.Items) != len(tt.wantItems) {
+				t.Fatalf("Items = %+v, want %+v", got.Items, tt.wantItems)
+			}
+			for i := range got.Items {
+				if got.Items[i] != tt.wantItems[i] {
+					t.Errorf("Items[%d] = %+v, want %+v", i, got.Items[i], tt.wantItems[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFinancialStatus(t *testing.T) {
+	tests := []struct {
+		order Order
+		want  string
+	}{
+		{Order{TotalCents: 1000, RefundedCents: 1}, StatusPartiallyRefunded},
+		{Order{TotalCents: 1000, RefundedCents: 1000}, StatusRefunded},
+		{Order{TotalCents: 0, RefundedCents: 0}, StatusRefunded},
+	}
+	for _, tt := range tests {
+		if got := FinancialStatus(tt.order); got != tt.want {
+			t.Errorf("FinancialStatus(%+v) = %q, want %q", tt.order, got, tt.want)
+		}
+	}
+}
+
+func TestRefundable(t *testing.T) {
+	for status, want := range map[string]bool{
+		"paid":               true,
+		"partially_refunded": true,
+		"pending":            false,
+		"refunded":           false,
+		"voided":             false,
+	} {
+		if got := Refundable(status); got != want {
+			t.Errorf("Refundable(%q) = %v, want %v", status, got, want)
+		}
+	}
+}
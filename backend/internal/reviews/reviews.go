@@ -0,0 +1,74 @@
+// Package reviews holds the rules of product reviews: their moderation
+// statuses, the limits on what a customer may write, how the author is
+// shown to shoppers, and the rating summary of a product.
+package reviews
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// Review statuses, matching product_reviews.status. Only approved reviews
+// are shown on the storefront or count towards a product's rating.
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+	StatusSpam     = "spam"
+)
+
+const (
+	MinRating = 1
+	MaxRating = 5
+
+	MaxTitleLength      = 150
+	MaxBodyLength       = 5000
+	MaxAuthorNameLength = 100
+)
+
+// anonymousAuthor is shown for customers without a name on their account
+const anonymousAuthor = "Anonymous"
+
+// IsValidStatus reports whether status is a known review status
+func IsValidStatus(status string) bool {
+	switch status {
+	case StatusPending, StatusApproved, StatusRejected, StatusSpam:
+		return true
+	}
+	return false
+}
+
+// AuthorName is how a review's author is shown when they do not pick a
+// name: their first name and last initial, so a full name is never
+// published without the customer choosing to
+func AuthorName(firstName, lastName string) string {
+	firstName, lastName = strings.TrimSpace(firstName), strings.TrimSpace(lastName)
+	if firstName == "" {
+		return anonymousAuthor
+	}
+	if lastName == "" {
+		return firstName
+	}
+	initial, _ := utf8.DecodeRuneInString(lastName)
+	return firstName + " " + strings.ToUpper(string(initial)) + "."
+}
+
+// Summary is the rating of a product over its approved reviews
+type Summary struct {
+	ReviewCount int64 `json:"review_count"`
+	// Average is rounded to one decimal place, and zero without reviews
+	Average float64 `json:"average"`
+}
+
+// Summarize builds the summary of count reviews whose ratings add up to
+// total
+func Summarize(count, total int64) Summary {
+	if count <= 0 {
+		return Summary{}
+	}
+	return Summary{
+		ReviewCount: count,
+		Average:     math.Round(float64(total)/float64(count)*10) / 10,
+	}
+}
@@ -0,0 +1,49 @@
+package reviews
+
+import "testing"
+
+func TestIsValidStatus(t *testing.T) {
+	for _, status := range []string{StatusPending, StatusApproved, StatusRejected, StatusSpam} {
+		if !IsValidStatus(status) {
+			t.Errorf("IsValidStatus(%q) = false", status)
+		}
+	}
+	if IsValidStatus("deleted") || IsValidStatus("") {
+		t.Error("IsValidStatus() accepted an unknown status")
+	}
+}
+
+func TestAuthorName(t *testing.T) {
+	tests := []struct {
+		first, last, want string
+	}{
+		{first: "Jane", last: "doe", want: "Jane D."},
+		{first: " Jane ", last: "", want: "Jane"},
+		{first: "Zoë", last: "Østergaard", want: "Zoë Ø."},
+		{first: "", last: "Doe", want: "Anonymous"},
+		{first: "", last: "", want: "Anonymous"},
+	}
+	for _, tt := range tests {
+		if got := AuthorName(tt.first, tt.last); got != tt.want {
+			t.Errorf("AuthorName(%q, %q) = %q, want %q", tt.first, tt.last, got, tt.want)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		count, total int64
+		want         Summary
+	}{
+		{count: 0, total: 0, want: Summary{}},
+		{count: 1, total: 5, want: Summary{ReviewCount: 1, Average: 5}},
+		{count: 3, total: 13, want: Summary{ReviewCount: 3, Average: 4.3}},
+		{count: 2, total: 9, want: Summary{ReviewCount: 2, Average: 4.5}},
+		{count: 6, total: 20, want: Summary{ReviewCount: 6, Average: 3.3}},
+	}
+	for _, tt := range tests {
+		if got := Summarize(tt.count, tt.total); got != tt.want {
+			t.Errorf("Summarize(%d, %d) = %+v, want %+v", tt.count, tt.total, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,362 @@
+// Package saml is the service provider side of SAML 2.0 single sign-on:
+// it reads an identity provider's metadata, sends users there with an
+// AuthnRequest over the HTTP-Redirect binding, and checks the signed
+// Response they come back with over the HTTP-POST binding.
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Bindings, formats and statuses the service provider uses
+const (
+	BindingHTTPRedirect = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+	BindingHTTPPost     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+
+	nameIDEmail   = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+	statusSuccess = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	methodBearer  = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+)
+
+// clockSkew is how far the identity provider's clock may be off
+const clockSkew = 2 * time.Minute
+
+// ErrInvalid wraps the reasons a response or metadata is turned away
+var ErrInvalid = errors.New("saml: invalid")
+
+// IdP is an identity provider as described by its metadata
+type IdP struct {
+	EntityID string
+	// SSOURL is where AuthnRequests are sent, over HTTP-Redirect
+	SSOURL string
+	// Certificates are the keys the provider signs with
+	Certificates []*x509.Certificate
+}
+
+// ParseMetadata reads an identity provider's metadata document, which is
+// either an EntityDescriptor or an EntitiesDescriptor holding one
+func ParseMetadata(data []byte) (IdP, error) {
+	root, err := parseXML(data)
+	if err != nil {
+		return IdP{}, fmt.Errorf("%w: metadata: %v", ErrInvalid, err)
+	}
+
+	var entity, idp *element
+	root.walk(func(el *element) {
+		if idp == nil && el.is(nsMetadata, "EntityDescriptor") {
+			if d := el.child(nsMetadata, "IDPSSODescriptor"); d != nil {
+				entity, idp = el, d
+			}
+		}
+	})
+	if idp == nil {
+		return IdP{}, fmt.Errorf("%w: metadata has no identity provider", ErrInvalid)
+	}
+
+	out := IdP{EntityID: entity.attr("entityID")}
+	if out.EntityID == "" {
+		return IdP{}, fmt.Errorf("%w: metadata has no entityID", ErrInvalid)
+	}
+	for _, sso := range idp.childrenNamed(nsMetadata, "SingleSignOnService") {
+		if sso.attr("Binding") == BindingHTTPRedirect {
+			out.SSOURL = sso.attr("Location")
+			break
+		}
+	}
+	if u, err := url.Parse(out.SSOURL); err != nil || u.Scheme != "https" || u.Host == "" {
+		return IdP{}, fmt.Errorf("%w: metadata has no https HTTP-Redirect sign-on service", ErrInvalid)
+	}
+	for _, kd := range idp.childrenNamed(nsMetadata, "KeyDescriptor") {
+		if use := kd.attr("use"); use != "" && use != "signing" {
+			continue
+		}
+		kd.walk(func(el *element) {
+			if !el.is(nsDSig, "X509Certificate") {
+				return
+			}
+			if cert, err := parseCertificate(el.text()); err == nil {
+				out.Certificates = append(out.Certificates, cert)
+			}
+		})
+	}
+	if len(out.Certificates) == 0 {
+		return IdP{}, fmt.Errorf("%w: metadata has no signing certificate", ErrInvalid)
+	}
+	return out, nil
+}
+
+// SP is this service as a service provider to one identity provider
+type SP struct {
+	// EntityID names the service provider to the identity provider
+	EntityID string
+	// ACSURL is the assertion consumer service responses are posted to
+	ACSURL string
+	IdP    IdP
+	// Now is overridden in tests
+	Now func() time.Time
+}
+
+func (sp *SP) now() time.Time {
+	if sp.Now != nil {
+		return sp.Now()
+	}
+	return time.Now()
+}
+
+// Metadata is the service provider's metadata, for the identity
+// provider's administrator to set the connection up with
+func (sp *SP) Metadata() []byte {
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	fmt.Fprintf(&b, `<md:EntityDescriptor xmlns:md="%s" entityID="%s">`, nsMetadata, escapeAttr(sp.EntityID))
+	fmt.Fprintf(&b, `<md:SPSSODescriptor AuthnRequestsSigned="false" WantAssertionsSigned="true" protocolSupportEnumeration="%s">`, nsProtocol)
+	fmt.Fprintf(&b, `<md:NameIDFormat>%s</md:NameIDFormat>`, nameIDEmail)
+	fmt.Fprintf(&b, `<md:AssertionConsumerService Binding="%s" Location="%s" index="0" isDefault="true"/>`, BindingHTTPPost, escapeAttr(sp.ACSURL))
+	b.WriteString(`</md:SPSSODescriptor></md:EntityDescriptor>` + "\n")
+	return b.Bytes()
+}
+
+// AuthnRequest returns the URL that sends the user to the identity
+// provider to sign in, and the request's ID, which the response has to
+// answer. relayState comes back with the response untouched.
+func (sp *SP) AuthnRequest(relayState string) (redirectURL, id string, err error) {
+	raw := make([]byte, 20)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	// IDs are xs:ID, which can't start with a digit
+	id = "_" + hex.EncodeToString(raw)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<samlp:AuthnRequest xmlns:samlp="%s" xmlns:saml="%s" ID="%s" Version="2.0" IssueInstant="%s" Destination="%s" AssertionConsumerServiceURL="%s" ProtocolBinding="%s">`,
+		nsProtocol, nsAssertion, id, sp.now().UTC().Format(time.RFC3339), escapeAttr(sp.IdP.SSOURL), escapeAttr(sp.ACSURL), BindingHTTPPost)
+	fmt.Fprintf(&b, `<saml:Issuer>%s</saml:Issuer>`, escapeText(sp.EntityID))
+	b.WriteString(`<samlp:NameIDPolicy AllowCreate="true"/></samlp:AuthnRequest>`)
+
+	var deflated bytes.Buffer
+	w, err := flate.NewWriter(&deflated, flate.BestCompression)
+	if err != nil {
+		return "", "", err
+	}
+	w.Write(b.Bytes())
+	if err := w.Close(); err != nil {
+		return "", "", err
+	}
+
+	q := url.Values{"SAMLRequest": {base64.StdEncoding.EncodeToString(deflated.Bytes())}}
+	if relayState != "" {
+		q.Set("RelayState", relayState)
+	}
+	sep := "?"
+	if strings.Contains(sp.IdP.SSOURL, "?") {
+		sep = "&"
+	}
+	return sp.IdP.SSOURL + sep + q.Encode(), id, nil
+}
+
+// Assertion is what the identity provider vouches for about the user
+type Assertion struct {
+	NameID       string
+	NameIDFormat string
+	// Attributes are keyed by Name, and by FriendlyName too when one is
+	// given
+	Attributes map[string][]string
+}
+
+// ParseResponse checks a base64 SAMLResponse posted to the assertion
+// consumer service answers the AuthnRequest requestID, and returns its
+// assertion. Either the response or the assertion has to be signed by
+// the identity provider.
+func (sp *SP) ParseResponse(encoded, requestID string) (Assertion, error) {
+	a, err := sp.parseResponse(encoded, requestID)
+	if err != nil {
+		return Assertion{}, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return a, nil
+}
+
+func (sp *SP) parseResponse(encoded, requestID string) (Assertion, error) {
+	data, err := decodeBase64(encoded)
+	if err != nil {
+		return Assertion{}, fmt.Errorf("response encoding: %w", err)
+	}
+	resp, err := parseXML(data)
+	if err != nil {
+		return Assertion{}, err
+	}
+	if !resp.is(nsProtocol, "Response") {
+		return Assertion{}, errors.New("not a Response")
+	}
+
+	// Signature wrapping attacks rely on a second element with the signed
+	// element's ID
+	ids := map[string]bool{}
+	var dup bool
+	resp.walk(func(el *element) {
+		if id := el.attr("ID"); id != "" {
+			dup = dup || ids[id]
+			ids[id] = true
+		}
+	})
+	if dup {
+		return Assertion{}, errors.New("duplicate IDs")
+	}
+
+	if d := resp.attr("Destination"); d != "" && d != sp.ACSURL {
+		return Assertion{}, fmt.Errorf("destination is %q", d)
+	}
+	if irt := resp.attr("InResponseTo"); irt != requestID {
+		return Assertion{}, errors.New("response does not answer the request")
+	}
+	if iss := resp.child(nsAssertion, "Issuer"); iss != nil && iss.text() != sp.IdP.EntityID {
+		return Assertion{}, fmt.Errorf("response issuer is %q", iss.text())
+	}
+	status := resp.child(nsProtocol, "Status")
+	if status == nil {
+		return Assertion{}, errors.New("no status")
+	}
+	if code := status.child(nsProtocol, "StatusCode"); code == nil || code.attr("Value") != statusSuccess {
+		msg := "sign-in failed"
+		if m := status.child(nsProtocol, "StatusMessage"); m != nil {
+			msg += ": " + m.text()
+		}
+		return Assertion{}, errors.New(msg)
+	}
+	if resp.child(nsAssertion, "EncryptedAssertion") != nil {
+		return Assertion{}, errors.New("encrypted assertions are not supported")
+	}
+	assertions := resp.childrenNamed(nsAssertion, "Assertion")
+	if len(assertions) != 1 {
+		return Assertion{}, errors.New("response must have exactly one assertion")
+	}
+	assertion := assertions[0]
+
+	// A signed response covers the assertion in it. Only what a signature
+	// covers is read, from the copy verifySignature returns.
+	now := sp.now()
+	signedResp, respErr := verifySignature(resp, sp.IdP.Certificates, now)
+	if respErr != nil && !errors.Is(respErr, errUnsigned) {
+		return Assertion{}, fmt.Errorf("response signature: %w", respErr)
+	}
+	if respErr == nil {
+		assertion = signedResp.child(nsAssertion, "Assertion")
+	}
+	signedAssertion, assertErr := verifySignature(assertion, sp.IdP.Certificates, now)
+	if assertErr != nil && !errors.Is(assertErr, errUnsigned) {
+		return Assertion{}, fmt.Errorf("assertion signature: %w", assertErr)
+	}
+	if respErr != nil && assertErr != nil {
+		return Assertion{}, errors.New("neither the response nor the assertion is signed")
+	}
+	if assertErr == nil {
+		assertion = signedAssertion
+	}
+
+	return sp.readAssertion(assertion, requestID)
+}
+
+// readAssertion checks the assertion is for this service provider, now,
+// and reads it
+func (sp *SP) readAssertion(a *element, requestID string) (Assertion, error) {
+	now := sp.now()
+
+	if iss := a.child(nsAssertion, "Issuer"); iss == nil || iss.text() != sp.IdP.EntityID {
+		return Assertion{}, errors.New("assertion is not from the identity provider")
+	}
+
+	subject := a.child(nsAssertion, "Subject")
+	if subject == nil {
+		return Assertion{}, errors.New("assertion has no subject")
+	}
+	nameID := subject.child(nsAssertion, "NameID")
+	if nameID == nil || nameID.text() == "" {
+		return Assertion{}, errors.New("assertion has no NameID")
+	}
+	var confirmed bool
+	for _, sc := range subject.childrenNamed(nsAssertion, "SubjectConfirmation") {
+		if sc.attr("Method") != methodBearer {
+			continue
+		}
+		data := sc.child(nsAssertion, "SubjectConfirmationData")
+		if data == nil || data.attr("Recipient") != sp.ACSURL {
+			continue
+		}
+		if irt := data.attr("InResponseTo"); irt != "" && irt != requestID {
+			continue
+		}
+		notOnOrAfter, err := parseTime(data.attr("NotOnOrAfter"))
+		if err != nil || !now.Before(notOnOrAfter.Add(clockSkew)) {
+			continue
+		}
+		confirmed = true
+		break
+	}
+	if !confirmed {
+		return Assertion{}, errors.New("assertion has no valid bearer confirmation")
+	}
+
+	conditions := a.child(nsAssertion, "Conditions")
+	if conditions == nil {
+		return Assertion{}, errors.New("assertion has no conditions")
+	}
+	if v := conditions.attr("NotBefore"); v != "" {
+		t, err := parseTime(v)
+		if err != nil || now.Add(clockSkew).Before(t) {
+			return Assertion{}, errors.New("assertion is not valid yet")
+		}
+	}
+	if v := conditions.attr("NotOnOrAfter"); v != "" {
+		t, err := parseTime(v)
+		if err != nil || !now.Before(t.Add(clockSkew)) {
+			return Assertion{}, errors.New("assertion has expired")
+		}
+	}
+	// Every audience restriction has to include this service provider
+	for _, ar := range conditions.childrenNamed(nsAssertion, "AudienceRestriction") {
+		var ok bool
+		for _, aud := range ar.childrenNamed(nsAssertion, "Audience") {
+			ok = ok || aud.text() == sp.EntityID
+		}
+		if !ok {
+			return Assertion{}, errors.New("assertion is for another audience")
+		}
+	}
+	if len(conditions.childrenNamed(nsAssertion, "AudienceRestriction")) == 0 {
+		return Assertion{}, errors.New("assertion has no audience")
+	}
+
+	out := Assertion{
+		NameID:       nameID.text(),
+		NameIDFormat: nameID.attr("Format"),
+		Attributes:   map[string][]string{},
+	}
+	for _, st := range a.childrenNamed(nsAssertion, "AttributeStatement") {
+		for _, attr := range st.childrenNamed(nsAssertion, "Attribute") {
+			var values []string
+			for _, v := range attr.childrenNamed(nsAssertion, "AttributeValue") {
+				values = append(values, v.text())
+			}
+			if name := attr.attr("Name"); name != "" {
+				out.Attributes[name] = append(out.Attributes[name], values...)
+			}
+			if name := attr.attr("FriendlyName"); name != "" && name != attr.attr("Name") {
+				out.Attributes[name] = append(out.Attributes[name], values...)
+			}
+		}
+	}
+	return out, nil
+}
+
+func parseTime(s string) (time.Time, error) {
+	return time.Parse(time.RFC3339, s)
+}
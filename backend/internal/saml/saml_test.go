@@ -0,0 +1,349 @@
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"errors"
+	"io"
+	"math/big"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/russellhaering/goxmldsig/etreeutils"
+)
+
+const (
+	testIdP  = "https://idp.example.com/metadata"
+	testSP   = "https://api.example.com/sso/saml/1/metadata"
+	testACS  = "https://api.example.com/sso/saml/1/acs"
+	testReq  = "_req1"
+	testTime = "2026-03-01T12:00:00Z"
+)
+
+// testKey is an identity provider's signing key and certificate
+type testKey struct {
+	key  *rsa.PrivateKey
+	cert *x509.Certificate
+}
+
+func newTestKey(t *testing.T) testKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Valid when the tests' responses are
+	issued, _ := time.Parse(time.RFC3339, testTime)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "idp"},
+		NotBefore:    issued.Add(-time.Hour),
+		NotAfter:     issued.Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testKey{key: key, cert: cert}
+}
+
+// sign returns doc with an enveloped signature over the element with ID
+// id, placed where the "<!--sig:id-->" marker is
+func (k testKey) sign(t *testing.T, doc, id string) string {
+	t.Helper()
+	return k.signWith(t, doc, id, algRSASHA256)
+}
+
+// signWith is sign with the signature algorithm method
+func (k testKey) signWith(t *testing.T, doc, id, method string) string {
+	t.Helper()
+	marker := "<!--sig:" + id + "-->"
+	d := etree.NewDocument()
+	if err := d.ReadFromString(strings.Replace(doc, marker, "", 1)); err != nil {
+		t.Fatal(err)
+	}
+	el := d.FindElement("//[@ID='" + id + "']")
+	if el == nil {
+		t.Fatalf("no element with ID %s", id)
+	}
+	// Signed on its own, as it is checked, with the namespaces it uses
+	nsCtx, err := etreeutils.NSBuildParentContext(el)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if el, err = etreeutils.NSDetatch(nsCtx, el); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := dsig.NewDefaultSigningContext(dsig.TLSCertKeyStore(tls.Certificate{Certificate: [][]byte{k.cert.Raw}, PrivateKey: k.key}))
+	ctx.Canonicalizer = dsig.MakeC14N10ExclusiveCanonicalizerWithPrefixList("")
+	if err := ctx.SetSignatureMethod(method); err != nil {
+		t.Fatal(err)
+	}
+	sig, err := ctx.ConstructSignature(el, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := etree.NewDocument()
+	out.SetRoot(sig)
+	signature, err := out.WriteToString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Replace(doc, marker, signature, 1)
+}
+
+// testAssertion is an assertion for testSP, with a signature marker
+func testAssertion(id, email string) string {
+	return `<saml:Assertion xmlns:saml="` + nsAssertion + `" ID="` + id + `" Version="2.0" IssueInstant="` + testTime + `">` +
+		`<saml:Issuer>` + testIdP + `</saml:Issuer><!--sig:` + id + `-->` +
+		`<saml:Subject><saml:NameID Format="` + nameIDEmail + `">` + email + `</saml:NameID>` +
+		`<saml:SubjectConfirmation Method="` + methodBearer + `"><saml:SubjectConfirmationData InResponseTo="` + testReq + `" Recipient="` + testACS + `" NotOnOrAfter="2026-03-01T12:05:00Z"/></saml:SubjectConfirmation></saml:Subject>` +
+		`<saml:Conditions NotBefore="2026-03-01T11:59:00Z" NotOnOrAfter="2026-03-01T12:05:00Z"><saml:AudienceRestriction><saml:Audience>` + testSP + `</saml:Audience></saml:AudienceRestriction></saml:Conditions>` +
+		`<saml:AttributeStatement>` +
+		`<saml:Attribute Name="http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress" FriendlyName="mail"><saml:AttributeValue>` + email + `</saml:AttributeValue></saml:Attribute>` +
+		`<saml:Attribute Name="groups"><saml:AttributeValue>staff</saml:AttributeValue><saml:AttributeValue>admins</saml:AttributeValue></saml:Attribute>` +
+		`</saml:AttributeStatement></saml:Assertion>`
+}
+
+func testResponse(assertions ...string) string {
+	return `<samlp:Response xmlns:samlp="` + nsProtocol + `" xmlns:saml="` + nsAssertion + `" ID="_resp1" Version="2.0" IssueInstant="` + testTime + `" Destination="` + testACS + `" InResponseTo="` + testReq + `">` +
+		`<saml:Issuer>` + testIdP + `</saml:Issuer><!--sig:_resp1-->` +
+		`<samlp:Status><samlp:StatusCode Value="` + statusSuccess + `"/></samlp:Status>` +
+		strings.Join(assertions, "") +
+		`</samlp:Response>`
+}
+
+func TestParseResponse(t *testing.T) {
+	idp, other := newTestKey(t), newTestKey(t)
+	now, _ := time.Parse(time.RFC3339, testTime)
+	sp := &SP{
+		EntityID: testSP,
+		ACSURL:   testACS,
+		IdP:      IdP{EntityID: testIdP, SSOURL: "https://idp.example.com/sso", Certificates: []*x509.Certificate{idp.cert}},
+		Now:      func() time.Time { return now },
+	}
+
+	want := Assertion{
+		NameID:       "ada@example.com",
+		NameIDFormat: nameIDEmail,
+		Attributes: map[string][]string{
+			"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress": {"ada@example.com"},
+			"mail":   {"ada@example.com"},
+			"groups": {"staff", "admins"},
+		},
+	}
+	signedAssertion := idp.sign(t, testAssertion("_a1", "ada@example.com"), "_a1")
+
+	tests := []struct {
+		name      string
+		doc       string
+		requestID string
+		now       time.Time
+		wantErr   bool
+	}{
+		{
+			name: "signed assertion",
+			doc:  testResponse(signedAssertion),
+		},
+		{
+			name: "signed response",
+			doc:  idp.sign(t, testResponse(testAssertion("_a1", "ada@example.com")), "_resp1"),
+		},
+		{
+			name: "assertion using the response's namespace declarations",
+			doc:  idp.sign(t, testResponse(strings.Replace(testAssertion("_a1", "ada@example.com"), ` xmlns:saml="`+nsAssertion+`"`, "", 1)), "_a1"),
+		},
+		{
+			name:    "SHA-1",
+			doc:     testResponse(idp.signWith(t, testAssertion("_a1", "ada@example.com"), "_a1", "http://www.w3.org/2000/09/xmldsig#rsa-sha1")),
+			wantErr: true,
+		},
+		{
+			name:    "unsigned",
+			doc:     testResponse(testAssertion("_a1", "ada@example.com")),
+			wantErr: true,
+		},
+		{
+			name:    "signed by another key",
+			doc:     testResponse(other.sign(t, testAssertion("_a1", "ada@example.com"), "_a1")),
+			wantErr: true,
+		},
+		{
+			name:    "tampered after signing",
+			doc:     testResponse(strings.Replace(signedAssertion, "<saml:NameID Format=\""+nameIDEmail+"\">ada@", "<saml:NameID Format=\""+nameIDEmail+"\">eve@", 1)),
+			wantErr: true,
+		},
+		{
+			name:    "wrapped with a second assertion",
+			doc:     testResponse(signedAssertion, strings.Replace(testAssertion("_a2", "eve@example.com"), "<!--sig:_a2-->", "", 1)),
+			wantErr: true,
+		},
+		{
+			name:    "copied with the same ID",
+			doc:     testResponse(strings.Replace(testAssertion("_a1", "eve@example.com"), "<!--sig:_a1-->", "<saml:Advice>"+signedAssertion+"</saml:Advice>", 1)),
+			wantErr: true,
+		},
+		{
+			name:      "answers another request",
+			doc:       testResponse(signedAssertion),
+			requestID: "_req2",
+			wantErr:   true,
+		},
+		{
+			name:    "expired",
+			doc:     testResponse(signedAssertion),
+			now:     now.Add(10 * time.Minute),
+			wantErr: true,
+		},
+		{
+			name:    "for another audience",
+			doc:     testResponse(idp.sign(t, strings.Replace(testAssertion("_a1", "ada@example.com"), testSP, "https://other.example.com", 1), "_a1")),
+			wantErr: true,
+		},
+		{
+			name:    "DTD",
+			doc:     `<!DOCTYPE x [<!ENTITY e "x">]>` + testResponse(signedAssertion),
+			wantErr: true,
+		},
+		{
+			name:    "failed status",
+			doc:     strings.Replace(testResponse(signedAssertion), statusSuccess, "urn:oasis:names:tc:SAML:2.0:status:Responder", 1),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp.Now = func() time.Time { return now }
+			if !tt.now.IsZero() {
+				sp.Now = func() time.Time { return tt.now }
+			}
+			requestID := tt.requestID
+			if requestID == "" {
+				requestID = testReq
+			}
+			got, err := sp.ParseResponse(base64.StdEncoding.EncodeToString([]byte(tt.doc)), requestID)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalid) {
+					t.Fatalf("ParseResponse() error = %v, want ErrInvalid", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ParseResponse() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+// TestCanonicalize pins what signatures are checked over, the exclusive
+// canonical form goxmldsig writes
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{
+			name: "drops unused namespaces and sorts attributes",
+			doc:  `<a:x xmlns:a="urn:a" xmlns:b="urn:b" z="1" a:y="2" b="3"><!-- c --><a:e/></a:x>`,
+			want: `<a:x xmlns:a="urn:a" b="3" z="1" a:y="2"><a:e></a:e></a:x>`,
+		},
+		{
+			name: "escapes text and attributes",
+			doc:  `<x a='"&lt;&#10;'>&amp;&gt;"</x>`,
+			want: `<x a="&quot;&lt;&#xA;">&amp;&gt;"</x>`,
+		},
+		{
+			name: "undeclares the default namespace",
+			doc:  `<x xmlns="urn:x"><y xmlns=""/></x>`,
+			want: `<x xmlns="urn:x"><y xmlns=""></y></x>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			el, err := parseXML([]byte(tt.doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := dsig.MakeC14N10ExclusiveCanonicalizerWithPrefixList("").Canonicalize(el.Element)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Canonicalize() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetadataAndAuthnRequest(t *testing.T) {
+	k := newTestKey(t)
+	metadata := `<md:EntityDescriptor xmlns:md="` + nsMetadata + `" xmlns:ds="` + nsDSig + `" entityID="` + testIdP + `">` +
+		`<md:IDPSSODescriptor protocolSupportEnumeration="` + nsProtocol + `">` +
+		`<md:KeyDescriptor use="signing"><ds:KeyInfo><ds:X509Data><ds:X509Certificate>` + "\n" + base64.StdEncoding.EncodeToString(k.cert.Raw) + "\n" + `</ds:X509Certificate></ds:X509Data></ds:KeyInfo></md:KeyDescriptor>` +
+		`<md:SingleSignOnService Binding="` + BindingHTTPPost + `" Location="https://idp.example.com/post"/>` +
+		`<md:SingleSignOnService Binding="` + BindingHTTPRedirect + `" Location="https://idp.example.com/sso?app=1"/>` +
+		`</md:IDPSSODescriptor></md:EntityDescriptor>`
+
+	idp, err := ParseMetadata([]byte(metadata))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idp.EntityID != testIdP || idp.SSOURL != "https://idp.example.com/sso?app=1" || len(idp.Certificates) != 1 || !idp.Certificates[0].Equal(k.cert) {
+		t.Errorf("ParseMetadata() = %+v", idp)
+	}
+	if _, err := ParseMetadata([]byte(strings.Replace(metadata, "https://idp.example.com/sso", "http://idp.example.com/sso", 1))); !errors.Is(err, ErrInvalid) {
+		t.Errorf("ParseMetadata() with an http sign-on URL error = %v, want ErrInvalid", err)
+	}
+
+	sp := &SP{EntityID: testSP, ACSURL: testACS, IdP: idp}
+	redirect, id, err := sp.AuthnRequest("state1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(redirect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Query().Get("app") != "1" || u.Query().Get("RelayState") != "state1" {
+		t.Errorf("AuthnRequest() = %s", redirect)
+	}
+	deflated, err := base64.StdEncoding.DecodeString(u.Query().Get("SAMLRequest"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := io.ReadAll(flate.NewReader(bytes.NewReader(deflated)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := parseXML(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !req.is(nsProtocol, "AuthnRequest") || req.attr("ID") != id || req.attr("AssertionConsumerServiceURL") != testACS {
+		t.Errorf("AuthnRequest() sent %s", raw)
+	}
+
+	if _, err := parseXML(sp.Metadata()); err != nil {
+		t.Errorf("Metadata() is not well-formed: %v", err)
+	}
+}
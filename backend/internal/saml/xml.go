@@ -0,0 +1,151 @@
+package saml
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/beevik/etree"
+)
+
+// Namespaces of the elements read
+const (
+	nsDSig      = "http://www.w3.org/2000/09/xmldsig#"
+	nsExcC14N   = "http://www.w3.org/2001/10/xml-exc-c14n#"
+	nsAssertion = "urn:oasis:names:tc:SAML:2.0:assertion"
+	nsProtocol  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	nsMetadata  = "urn:oasis:names:tc:SAML:2.0:metadata"
+)
+
+const (
+	// maxDocumentBytes bounds a response or metadata document
+	maxDocumentBytes = 1 << 20
+	// MaxPostBytes bounds the form a response is posted to the assertion
+	// consumer service in, base64 and all
+	MaxPostBytes = 2 << 20
+)
+
+// element is a node of a parsed document. etree keeps prefixes and
+// namespace declarations as written, which goxmldsig needs to
+// canonicalize the document the way it was signed.
+type element struct {
+	*etree.Element
+}
+
+// parseXML parses a document. DTDs are refused outright.
+func parseXML(data []byte) (*element, error) {
+	if len(data) > maxDocumentBytes {
+		return nil, errors.New("document is too large")
+	}
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(data); err != nil {
+		return nil, err
+	}
+
+	var root *etree.Element
+	for _, t := range doc.Child {
+		switch t := t.(type) {
+		case *etree.Element:
+			if root != nil {
+				return nil, errors.New("more than one root element")
+			}
+			root = t
+		case *etree.Directive:
+			return nil, errors.New("DTDs are not allowed")
+		}
+	}
+	if root == nil {
+		return nil, errors.New("incomplete document")
+	}
+	if err := checkTokens(root); err != nil {
+		return nil, err
+	}
+	return &element{root}, nil
+}
+
+// checkTokens refuses directives and prefixes bound to no namespace
+// anywhere below el
+func checkTokens(el *etree.Element) error {
+	if el.Space != "" && el.NamespaceURI() == "" {
+		return fmt.Errorf("unbound prefix %q", el.Space)
+	}
+	for i := range el.Attr {
+		a := &el.Attr[i]
+		if a.Space != "" && a.Space != "xmlns" && a.Space != "xml" && a.NamespaceURI() == "" {
+			return fmt.Errorf("unbound prefix %q", a.Space)
+		}
+	}
+	for _, t := range el.Child {
+		switch t := t.(type) {
+		case *etree.Element:
+			if err := checkTokens(t); err != nil {
+				return err
+			}
+		case *etree.Directive:
+			return errors.New("DTDs are not allowed")
+		}
+	}
+	return nil
+}
+
+func (el *element) is(space, local string) bool {
+	return el.Tag == local && el.NamespaceURI() == space
+}
+
+// attr returns the value of the unqualified attribute name
+func (el *element) attr(name string) string {
+	for _, a := range el.Attr {
+		if a.Space == "" && a.Key == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// child returns the first child element named space and local
+func (el *element) child(space, local string) *element {
+	for _, c := range el.ChildElements() {
+		if e := (&element{c}); e.is(space, local) {
+			return e
+		}
+	}
+	return nil
+}
+
+// childrenNamed returns the child elements named space and local
+func (el *element) childrenNamed(space, local string) []*element {
+	var out []*element
+	for _, c := range el.ChildElements() {
+		if e := (&element{c}); e.is(space, local) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// text is the element's character data, trimmed
+func (el *element) text() string {
+	var b strings.Builder
+	for _, c := range el.Child {
+		if s, ok := c.(*etree.CharData); ok {
+			b.WriteString(s.Data)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// walk calls fn on el and every element below it
+func (el *element) walk(fn func(*element)) {
+	fn(el)
+	for _, c := range el.ChildElements() {
+		(&element{c}).walk(fn)
+	}
+}
+
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+)
+
+func escapeText(s string) string { return textEscaper.Replace(s) }
+func escapeAttr(s string) string { return attrEscaper.Replace(s) }
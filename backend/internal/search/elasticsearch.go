@@ -0,0 +1,245 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ElasticsearchConfig points at an Elasticsearch (or OpenSearch) cluster.
+// Authenticate with either an API key or a username and password.
+type ElasticsearchConfig struct {
+	URL      string
+	APIKey   string
+	Username string
+	Password string
+	Index    string
+}
+
+// Elasticsearch indexes products in one index shared by all stores; every
+// query is filtered to one store
+type Elasticsearch struct {
+	http  httpBackend
+	index string
+}
+
+// elasticsearchCursor holds the sort values of the last hit (search_after)
+type elasticsearchCursor struct {
+	After []any `json:"after"`
+}
+
+// NewElasticsearch returns an Elasticsearch engine
+func NewElasticsearch(cfg ElasticsearchConfig) (*Elasticsearch, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("ELASTICSEARCH_URL is required")
+	}
+	backend, err := newHTTPBackend(cfg.URL, func(r *http.Request) {
+		switch {
+		case cfg.APIKey != "":
+			r.Header.Set("Authorization", "ApiKey "+cfg.APIKey)
+		case cfg.Username != "":
+			r.SetBasicAuth(cfg.Username, cfg.Password)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Elasticsearch{http: backend, index: cfg.Index}, nil
+}
+
+func (e *Elasticsearch) Name() string { return "elasticsearch" }
+
+func (e *Elasticsearch) indexPath() string {
+	return "/" + url.PathEscape(e.index)
+}
+
+// EnsureIndex creates the index with explicit mappings if it is missing
+func (e *Elasticsearch) EnsureIndex(ctx context.Context) error {
+	err := e.http.do(ctx, http.MethodHead, e.indexPath(), "", nil, nil)
+	if err == nil {
+		return nil
+	}
+	var se *statusError
+	if !errors.As(err, &se) || se.Code != http.StatusNotFound {
+		return err
+	}
+
+	keywordText := map[string]any{
+		"type":   "text",
+		"fields": map[string]any{"keyword": map[string]string{"type": "keyword"}},
+	}
+	return e.http.do(ctx, http.MethodPut, e.indexPath(), "application/json", map[string]any{
+		"mappings": map[string]any{
+			"properties": map[string]any{
+				"id":              map[string]string{"type": "keyword"},
+				"store_id":        map[string]string{"type": "keyword"},
+				"handle":          map[string]string{"type": "keyword"},
+				"name":            map[string]string{"type": "text"},
+				"description":     map[string]string{"type": "text"},
+				"tags":            keywordText,
+				"skus":            keywordText,
+				"status":          map[string]string{"type": "keyword"},
+				"min_price_cents": map[string]string{"type": "long"},
+				"created_at":      map[string]string{"type": "date"},
+			},
+		},
+	}, nil)
+}
+
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		ID     string `json:"_id"`
+		Status int    `json:"status"`
+		Error  *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// bulk sends NDJSON actions and fails if any item failed. Deleting a
+// missing document (404) is not a failure.
+func (e *Elasticsearch) bulk(ctx context.Context, body []byte) error {
+	var resp bulkResponse
+	if err := e.http.do(ctx, http.MethodPost, "/_bulk", "application/x-ndjson", body, &resp); err != nil {
+		return err
+	}
+	if !resp.Errors {
+		return nil
+	}
+	for _, item := range resp.Items {
+		for action, result := range item {
+			if result.Error == nil || (action == "delete" && result.Status == http.StatusNotFound) {
+				continue
+			}
+			return fmt.Errorf("bulk %s %s: %s: %s", action, result.ID, result.Error.Type, result.Error.Reason)
+		}
+	}
+	return nil
+}
+
+func (e *Elasticsearch) Index(ctx context.Context, docs []Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, doc := range docs {
+		action := map[string]any{"index": map[string]string{"_index": e.index, "_id": doc.ID.String()}}
+		if err := enc.Encode(action); err != nil {
+			return err
+		}
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+	}
+	return e.bulk(ctx, buf.Bytes())
+}
+
+func (e *Elasticsearch) Delete(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, id := range ids {
+		action := map[string]any{"delete": map[string]string{"_index": e.index, "_id": id.String()}}
+		if err := enc.Encode(action); err != nil {
+			return err
+		}
+	}
+	return e.bulk(ctx, buf.Bytes())
+}
+
+type elasticsearchResponse struct {
+	Hits struct {
+		Hits []struct {
+			ID        string              `json:"_id"`
+			Score     float64             `json:"_score"`
+			Sort      []any               `json:"sort"`
+			Highlight map[string][]string `json:"highlight"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+func (e *Elasticsearch) Search(ctx context.Context, q Query) (*Result, error) {
+	body := map[string]any{
+		"size": q.Limit + 1,
+		"query": map[string]any{
+			"bool": map[string]any{
+				"must": map[string]any{
+					"multi_match": map[string]any{
+						"query":  q.Text,
+						"fields": []string{"name^3", "skus.keyword^4", "skus^3", "tags^2", "description"},
+					},
+				},
+				"filter": []any{
+					map[string]any{"term": map[string]string{"store_id": q.StoreID.String()}},
+				},
+			},
+		},
+		// Stable ordering so search_after pages never skip or repeat hits
+		"sort": []any{"_score", map[string]string{"created_at": "desc"}, map[string]string{"id": "desc"}},
+		"highlight": map[string]any{
+			"pre_tags":  []string{highlightPreTag},
+			"post_tags": []string{highlightPostTag},
+			"fields": map[string]any{
+				"name":        map[string]int{"number_of_fragments": 0},
+				"description": map[string]int{"fragment_size": 150, "number_of_fragments": 2},
+			},
+		},
+		"_source": false,
+	}
+
+	if q.Cursor != "" {
+		var cursor elasticsearchCursor
+		if err := decodeCursor(q.Cursor, &cursor); err != nil {
+			return nil, err
+		}
+		if len(cursor.After) != 3 {
+			return nil, ErrInvalidCursor
+		}
+		body["search_after"] = cursor.After
+	}
+
+	var resp elasticsearchResponse
+	if err := e.http.do(ctx, http.MethodPost, e.indexPath()+"/_search", "application/json", body, &resp); err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	hits := resp.Hits.Hits
+	if len(hits) > q.Limit {
+		hits = hits[:q.Limit]
+		var err error
+		result.NextCursor, err = encodeCursor(elasticsearchCursor{After: hits[len(hits)-1].Sort})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result.Hits = make([]Hit, 0, len(hits))
+	for _, h := range hits {
+		id, err := uuid.Parse(h.ID)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected document id %q", h.ID)
+		}
+		result.Hits = append(result.Hits, Hit{
+			ProductID:          id,
+			Score:              h.Score,
+			NameHighlight:      strings.Join(h.Highlight["name"], ""),
+			DescriptionSnippet: strings.Join(h.Highlight["description"], " ... "),
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,117 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestMeilisearchSearch(t *testing.T) {
+	storeID := uuid.New()
+	hitIDs := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/indexes/products/search" || r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("unexpected request %s %s", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+
+		hits := []map[string]any{}
+		for _, id := range hitIDs {
+			hits = append(hits, map[string]any{
+				"id":            id,
+				"_rankingScore": 0.9,
+				"_formatted":    map[string]string{"name": "<mark>Linen</mark> Shirt"},
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"hits": hits})
+	}))
+	defer srv.Close()
+
+	engine, err := NewMeilisearch(MeilisearchConfig{URL: srv.URL, APIKey: "secret", Index: "products"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := engine.Search(context.Background(), Query{StoreID: storeID, Text: "linen", Limit: 2})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	if got["filter"] != `store_id = "`+storeID.String()+`"` {
+		t.Errorf("filter = %v, want the store filter", got["filter"])
+	}
+	if got["limit"] != float64(3) {
+		t.Errorf("limit = %v, want one extra to detect more pages", got["limit"])
+	}
+	if len(result.Hits) != 2 || result.Hits[0].ProductID != hitIDs[0] || result.Hits[0].NameHighlight != "<mark>Linen</mark> Shirt" {
+		t.Errorf("hits = %+v", result.Hits)
+	}
+	if result.NextCursor == "" {
+		t.Fatal("expected a next cursor")
+	}
+
+	if _, err := engine.Search(context.Background(), Query{StoreID: storeID, Text: "linen", Limit: 2, Cursor: result.NextCursor}); err != nil {
+		t.Fatal(err)
+	}
+	if got["offset"] != float64(2) {
+		t.Errorf("offset = %v, want 2 on the second page", got["offset"])
+	}
+}
+
+func TestElasticsearchBulk(t *testing.T) {
+	deleted := uuid.New()
+
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_bulk" || r.Header.Get("Content-Type") != "application/x-ndjson" {
+			t.Errorf("unexpected request %s %s", r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		if user, pass, ok := r.BasicAuth(); !ok || user != "elastic" || pass != "changeme" {
+			t.Error("missing basic auth")
+		}
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+
+		// A missing document is not a failure when deleting
+		w.Write([]byte(`{"errors":true,"items":[{"delete":{"_id":"` + deleted.String() + `","status":404,"error":{"type":"not_found","reason":"missing"}}}]}`))
+	}))
+	defer srv.Close()
+
+	engine, err := NewElasticsearch(ElasticsearchConfig{URL: srv.URL, Username: "elastic", Password: "changeme", Index: "products"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := engine.Delete(context.Background(), []uuid.UUID{deleted}); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	want := `{"delete":{"_id":"` + deleted.String() + `","_index":"products"}}` + "\n"
+	if body != want {
+		t.Errorf("bulk body = %q, want %q", body, want)
+	}
+}
+
+func TestElasticsearchBulkFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"errors":true,"items":[{"index":{"_id":"x","status":400,"error":{"type":"mapper_parsing_exception","reason":"bad field"}}}]}`))
+	}))
+	defer srv.Close()
+
+	engine, err := NewElasticsearch(ElasticsearchConfig{URL: srv.URL, Index: "products"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = engine.Index(context.Background(), []Document{{ID: uuid.New(), Tags: []string{}, SKUs: []string{}}})
+	if err == nil || !strings.Contains(err.Error(), "mapper_parsing_exception") {
+		t.Errorf("Index() error = %v, want the item failure", err)
+	}
+}
@@ -0,0 +1,91 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/tracing"
+)
+
+// httpBackend is the JSON-over-HTTP plumbing shared by external engines
+type httpBackend struct {
+	base      *url.URL
+	authorize func(*http.Request)
+	client    *http.Client
+}
+
+func newHTTPBackend(rawURL string, authorize func(*http.Request)) (httpBackend, error) {
+	u, err := url.Parse(strings.TrimSuffix(rawURL, "/"))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return httpBackend{}, fmt.Errorf("invalid search engine URL %q", rawURL)
+	}
+	return httpBackend{
+		base:      u,
+		authorize: authorize,
+		client:    &http.Client{Timeout: 30 * time.Second, Transport: tracing.Transport(nil)},
+	}, nil
+}
+
+// statusError carries a non-2xx response so callers can branch on the code
+type statusError struct {
+	Method string
+	Path   string
+	Code   int
+	Body   string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%s %s: status %d: %s", e.Method, e.Path, e.Code, e.Body)
+}
+
+// do sends body (JSON-encoded unless it is already a []byte) and decodes a
+// JSON response into out when out is non-nil
+func (b httpBackend) do(ctx context.Context, method, path, contentType string, body any, out any) error {
+	var reader io.Reader
+	switch v := body.(type) {
+	case nil:
+	case []byte:
+		reader = bytes.NewReader(v)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, b.base.String()+path, reader)
+	if err != nil {
+		return err
+	}
+	if reader != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+	if b.authorize != nil {
+		b.authorize(req)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &statusError{Method: method, Path: path, Code: resp.StatusCode, Body: strings.TrimSpace(string(msg))}
+	}
+	if out == nil {
+		_, err = io.Copy(io.Discard, resp.Body)
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,185 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ProductTopic is the outbox topic product and variant writes are recorded under
+const ProductTopic = "product"
+
+// IndexerConfig configures an Indexer
+type IndexerConfig struct {
+	BatchSize    int
+	PollInterval time.Duration
+	// Retention is how long processed outbox events are kept before being pruned
+	Retention time.Duration
+	Logger    *slog.Logger
+}
+
+// Indexer relays product outbox events to a search engine. Several indexers
+// may run at once; each claims its batch with SKIP LOCKED.
+type Indexer struct {
+	pool   *pgxpool.Pool
+	db     *database.Queries
+	engine Engine
+	cfg    IndexerConfig
+}
+
+// NewIndexer creates an indexer feeding engine
+func NewIndexer(pool *pgxpool.Pool, engine Engine, cfg IndexerConfig) *Indexer {
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 100
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 2 * time.Second
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = 24 * time.Hour
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &Indexer{
+		pool:   pool,
+		db:     database.New(pool),
+		engine: engine,
+		cfg:    cfg,
+	}
+}
+
+// Run relays events until ctx is cancelled
+func (ix *Indexer) Run(ctx context.Context) error {
+	if err := ix.engine.EnsureIndex(ctx); err != nil {
+		return fmt.Errorf("ensure %s index: %w", ix.engine.Name(), err)
+	}
+
+	ix.cfg.Logger.Info("search indexer started", "engine", ix.engine.Name())
+
+	ticker := time.NewTicker(ix.cfg.PollInterval)
+	defer ticker.Stop()
+	var lastPrune time.Time
+
+	for {
+		if time.Since(lastPrune) > time.Hour {
+			ix.prune(ctx)
+			lastPrune = time.Now()
+		}
+
+		n, err := ix.ProcessBatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			ix.cfg.Logger.Error("search indexing batch failed", "engine", ix.engine.Name(), "error", err)
+		}
+
+		// A full batch means there is likely more waiting; don't sleep
+		if err == nil && n == ix.cfg.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			ix.cfg.Logger.Info("search indexer stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessBatch syncs one batch of pending events and returns how many were
+// handled. Events stay pending if the engine call fails and are retried.
+func (ix *Indexer) ProcessBatch(ctx context.Context) (int, error) {
+	tx, err := ix.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+	qtx := ix.db.WithTx(tx)
+
+	events, err := qtx.ClaimOutboxEvents(ctx, database.ClaimOutboxEventsParams{
+		Topic: ProductTopic,
+		Limit: int32(ix.cfg.BatchSize),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	eventIDs, productIDs := batchIDs(events)
+
+	rows, err := qtx.GetProductSearchDocuments(ctx, productIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	// Products that no longer exist were deleted; drop them from the index
+	docs := make([]Document, 0, len(rows))
+	found := make(map[uuid.UUID]bool, len(rows))
+	for _, row := range rows {
+		docs = append(docs, DocumentFromRow(row))
+		found[row.ID] = true
+	}
+	var deleted []uuid.UUID
+	for _, id := range productIDs {
+		if !found[id] {
+			deleted = append(deleted, id)
+		}
+	}
+
+	if err := ix.engine.Index(ctx, docs); err != nil {
+		return 0, fmt.Errorf("index %d documents: %w", len(docs), err)
+	}
+	if err := ix.engine.Delete(ctx, deleted); err != nil {
+		return 0, fmt.Errorf("delete %d documents: %w", len(deleted), err)
+	}
+
+	if err := qtx.MarkOutboxEventsProcessed(ctx, eventIDs); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	ix.cfg.Logger.Debug("search index batch synced",
+		"engine", ix.engine.Name(),
+		"events", len(events),
+		"indexed", len(docs),
+		"deleted", len(deleted),
+	)
+	return len(events), nil
+}
+
+// batchIDs returns the event IDs and the distinct products they touch.
+// A product written many times in a batch is only indexed once.
+func batchIDs(events []database.OutboxEvent) ([]int64, []uuid.UUID) {
+	eventIDs := make([]int64, 0, len(events))
+	productIDs := make([]uuid.UUID, 0, len(events))
+	seen := make(map[uuid.UUID]bool, len(events))
+	for _, event := range events {
+		eventIDs = append(eventIDs, event.ID)
+		if !seen[event.AggregateID] {
+			seen[event.AggregateID] = true
+			productIDs = append(productIDs, event.AggregateID)
+		}
+	}
+	return eventIDs, productIDs
+}
+
+func (ix *Indexer) prune(ctx context.Context) {
+	n, err := ix.db.PruneOutboxEvents(ctx, sql.NullTime{Time: time.Now().Add(-ix.cfg.Retention), Valid: true})
+	if err != nil {
+		ix.cfg.Logger.Warn("outbox prune failed", "error", err)
+		return
+	}
+	if n > 0 {
+		ix.cfg.Logger.Info("pruned processed outbox events", "count", n)
+	}
+}
@@ -0,0 +1,144 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+)
+
+// MeilisearchConfig points at a Meilisearch instance
+type MeilisearchConfig struct {
+	URL    string
+	APIKey string
+	Index  string
+}
+
+// Meilisearch indexes products in a Meilisearch index shared by all stores;
+// every query is filtered to one store
+type Meilisearch struct {
+	http  httpBackend
+	index string
+}
+
+// meilisearchCursor is the offset of the next page
+type meilisearchCursor struct {
+	Offset int `json:"offset"`
+}
+
+// NewMeilisearch returns a Meilisearch engine
+func NewMeilisearch(cfg MeilisearchConfig) (*Meilisearch, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("MEILISEARCH_URL is required")
+	}
+	backend, err := newHTTPBackend(cfg.URL, func(r *http.Request) {
+		if cfg.APIKey != "" {
+			r.Header.Set("Authorization", "Bearer "+cfg.APIKey)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Meilisearch{http: backend, index: cfg.Index}, nil
+}
+
+func (m *Meilisearch) Name() string { return "meilisearch" }
+
+func (m *Meilisearch) indexPath() string {
+	return "/indexes/" + url.PathEscape(m.index)
+}
+
+// EnsureIndex creates the index and applies its settings. Meilisearch
+// processes both asynchronously and treats repeats as no-ops.
+func (m *Meilisearch) EnsureIndex(ctx context.Context) error {
+	err := m.http.do(ctx, http.MethodPost, "/indexes", "application/json", map[string]string{
+		"uid":        m.index,
+		"primaryKey": "id",
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	return m.http.do(ctx, http.MethodPatch, m.indexPath()+"/settings", "application/json", map[string]any{
+		"searchableAttributes": []string{"name", "skus", "tags", "description"},
+		"filterableAttributes": []string{"store_id", "status", "tags", "min_price_cents"},
+	}, nil)
+}
+
+func (m *Meilisearch) Index(ctx context.Context, docs []Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	return m.http.do(ctx, http.MethodPost, m.indexPath()+"/documents?primaryKey=id", "application/json", docs, nil)
+}
+
+func (m *Meilisearch) Delete(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return m.http.do(ctx, http.MethodPost, m.indexPath()+"/documents/delete-batch", "application/json", ids, nil)
+}
+
+type meilisearchResponse struct {
+	Hits []struct {
+		ID           uuid.UUID `json:"id"`
+		RankingScore float64   `json:"_rankingScore"`
+		Formatted    struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+		} `json:"_formatted"`
+	} `json:"hits"`
+}
+
+func (m *Meilisearch) Search(ctx context.Context, q Query) (*Result, error) {
+	var cursor meilisearchCursor
+	if q.Cursor != "" {
+		if err := decodeCursor(q.Cursor, &cursor); err != nil {
+			return nil, err
+		}
+		if cursor.Offset < 0 {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	var resp meilisearchResponse
+	err := m.http.do(ctx, http.MethodPost, m.indexPath()+"/search", "application/json", map[string]any{
+		"q":                     q.Text,
+		"filter":                `store_id = "` + q.StoreID.String() + `"`,
+		"offset":                cursor.Offset,
+		"limit":                 q.Limit + 1,
+		"attributesToRetrieve":  []string{"id", "name", "description"},
+		"attributesToHighlight": []string{"name", "description"},
+		"attributesToCrop":      []string{"description"},
+		"cropLength":            20,
+		"highlightPreTag":       highlightPreTag,
+		"highlightPostTag":      highlightPostTag,
+		"showRankingScore":      true,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	hits := resp.Hits
+	if len(hits) > q.Limit {
+		hits = hits[:q.Limit]
+		result.NextCursor, err = encodeCursor(meilisearchCursor{Offset: cursor.Offset + q.Limit})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result.Hits = make([]Hit, 0, len(hits))
+	for _, h := range hits {
+		result.Hits = append(result.Hits, Hit{
+			ProductID:          h.ID,
+			Score:              h.RankingScore,
+			NameHighlight:      h.Formatted.Name,
+			DescriptionSnippet: h.Formatted.Description,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,88 @@
+package search
+
+import (
+	"context"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/google/uuid"
+)
+
+// Postgres searches the products table through its full-text expression
+// index. Postgres maintains that index itself, so Index and Delete are no-ops.
+type Postgres struct {
+	db *database.Queries
+}
+
+// NewPostgres returns the default engine
+func NewPostgres(db *database.Queries) *Postgres {
+	return &Postgres{db: db}
+}
+
+// postgresCursor pages through results in (rank, created_at, id) order
+type postgresCursor struct {
+	Rank      float32   `json:"rank"`
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+}
+
+func (p *Postgres) Name() string { return "postgres" }
+
+func (p *Postgres) EnsureIndex(ctx context.Context) error { return nil }
+
+func (p *Postgres) Index(ctx context.Context, docs []Document) error { return nil }
+
+func (p *Postgres) Delete(ctx context.Context, ids []uuid.UUID) error { return nil }
+
+func (p *Postgres) Search(ctx context.Context, q Query) (*Result, error) {
+	var cursor postgresCursor
+	if q.Cursor != "" {
+		if err := decodeCursor(q.Cursor, &cursor); err != nil {
+			return nil, err
+		}
+		if cursor.CreatedAt.IsZero() || cursor.ID == uuid.Nil {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	rows, err := p.db.SearchProductsByStore(ctx, database.SearchProductsByStoreParams{
+		Query:           q.Text,
+		StoreID:         q.StoreID,
+		HasCursor:       q.Cursor != "",
+		CursorRank:      cursor.Rank,
+		CursorCreatedAt: cursor.CreatedAt,
+		CursorID:        cursor.ID,
+		RowLimit:        int32(q.Limit + 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	if len(rows) > q.Limit {
+		rows = rows[:q.Limit]
+		last := rows[len(rows)-1]
+		result.NextCursor, err = encodeCursor(postgresCursor{
+			Rank:      last.Rank,
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result.Hits = make([]Hit, 0, len(rows))
+	for _, row := range rows {
+		hit := Hit{
+			ProductID:     row.ID,
+			Score:         float64(row.Rank),
+			NameHighlight: row.NameHighlight,
+		}
+		if row.Description.Valid {
+			hit.DescriptionSnippet = row.DescriptionSnippet
+		}
+		result.Hits = append(result.Hits, hit)
+	}
+	return result, nil
+}
@@ -0,0 +1,158 @@
+// Package search indexes products and answers storefront and admin search
+// queries. Postgres full-text search is the default engine; Meilisearch and
+// Elasticsearch can be plugged in and are kept in sync from the outbox.
+package search
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/collections"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCursor is returned when a page cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid search cursor")
+
+// Engine is implemented by every search backend
+type Engine interface {
+	// Name identifies the engine in logs
+	Name() string
+	// EnsureIndex creates the index and its settings if needed
+	EnsureIndex(ctx context.Context) error
+	// Index adds or replaces documents
+	Index(ctx context.Context, docs []Document) error
+	// Delete removes documents; unknown IDs are ignored
+	Delete(ctx context.Context, ids []uuid.UUID) error
+	Search(ctx context.Context, q Query) (*Result, error)
+}
+
+// Document is the searchable projection of a product
+type Document struct {
+	ID          uuid.UUID `json:"id"`
+	StoreID     uuid.UUID `json:"store_id"`
+	Handle      string    `json:"handle"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Tags        []string  `json:"tags"`
+	SKUs        []string  `json:"skus"`
+	Status      string    `json:"status"`
+	// MinPriceCents is nil when the product has no active variants
+	MinPriceCents *int64    `json:"min_price_cents"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// DocumentFromRow builds the indexed document of a product
+func DocumentFromRow(row database.GetProductSearchDocumentsRow) Document {
+	doc := Document{
+		ID:          row.ID,
+		StoreID:     row.StoreID,
+		Handle:      row.Handle,
+		Name:        row.Name,
+		Description: row.Description.String,
+		Tags:        collections.ParseTags(row.Tags.String),
+		SKUs:        row.VariantSkus,
+		Status:      row.Status,
+		CreatedAt:   row.CreatedAt,
+	}
+	if row.Sku.Valid && row.Sku.String != "" {
+		doc.SKUs = append([]string{row.Sku.String}, doc.SKUs...)
+	}
+	if doc.Tags == nil {
+		doc.Tags = []string{}
+	}
+	if doc.SKUs == nil {
+		doc.SKUs = []string{}
+	}
+	if row.ActiveVariantCount > 0 {
+		price := row.MinPriceCents
+		doc.MinPriceCents = &price
+	}
+	return doc
+}
+
+// Query is a search within one store
+type Query struct {
+	StoreID uuid.UUID
+	Text    string
+	Limit   int
+	// Cursor is the NextCursor of the previous page, or empty for the first
+	Cursor string
+}
+
+// Hit is one matching product. Highlights wrap matched terms in <mark> tags.
+type Hit struct {
+	ProductID          uuid.UUID
+	Score              float64
+	NameHighlight      string
+	DescriptionSnippet string
+}
+
+// Result is one page of hits in relevance order
+type Result struct {
+	Hits []Hit
+	// NextCursor is empty on the last page
+	NextCursor string
+}
+
+const (
+	highlightPreTag  = "<mark>"
+	highlightPostTag = "</mark>"
+)
+
+// DefaultIndex is the index name used when none is configured
+const DefaultIndex = "products"
+
+// Config selects and configures an engine. Index is copied into the
+// selected engine's config.
+type Config struct {
+	// Engine is postgres, meilisearch or elasticsearch. Empty means postgres.
+	Engine        string
+	Index         string
+	Meilisearch   MeilisearchConfig
+	Elasticsearch ElasticsearchConfig
+}
+
+// New builds the engine selected by cfg.Engine
+func New(db *database.Queries, cfg Config) (Engine, error) {
+	if cfg.Index == "" {
+		cfg.Index = DefaultIndex
+	}
+	switch cfg.Engine {
+	case "", "postgres":
+		return NewPostgres(db), nil
+	case "meilisearch":
+		cfg.Meilisearch.Index = cfg.Index
+		return NewMeilisearch(cfg.Meilisearch)
+	case "elasticsearch":
+		cfg.Elasticsearch.Index = cfg.Index
+		return NewElasticsearch(cfg.Elasticsearch)
+	default:
+		return nil, fmt.Errorf("unknown SEARCH_ENGINE %q", cfg.Engine)
+	}
+}
+
+// encodeCursor and decodeCursor make engine-specific page state opaque
+func encodeCursor(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeCursor(s string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
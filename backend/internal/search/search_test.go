@@ -0,0 +1,113 @@
+package search
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/google/uuid"
+)
+
+func TestDocumentFromRow(t *testing.T) {
+	id := uuid.New()
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		row  database.GetProductSearchDocumentsRow
+		want Document
+	}{
+		{
+			name: "product and variant skus",
+			row: database.GetProductSearchDocumentsRow{
+				ID:                 id,
+				Name:               "Linen Shirt",
+				Description:        sql.NullString{String: "Breathable", Valid: true},
+				Sku:                sql.NullString{String: "SHIRT", Valid: true},
+				Tags:               sql.NullString{String: "summer, linen", Valid: true},
+				Status:             "active",
+				VariantSkus:        []string{"SHIRT-S", "SHIRT-M"},
+				ActiveVariantCount: 2,
+				MinPriceCents:      4500,
+				CreatedAt:          created,
+			},
+			want: Document{
+				ID:            id,
+				Name:          "Linen Shirt",
+				Description:   "Breathable",
+				Tags:          []string{"summer", "linen"},
+				SKUs:          []string{"SHIRT", "SHIRT-S", "SHIRT-M"},
+				Status:        "active",
+				MinPriceCents: ptr(int64(4500)),
+				CreatedAt:     created,
+			},
+		},
+		{
+			name: "no variants",
+			row: database.GetProductSearchDocumentsRow{
+				ID:        id,
+				Name:      "Draft",
+				Status:    "draft",
+				CreatedAt: created,
+			},
+			want: Document{
+				ID:        id,
+				Name:      "Draft",
+				Tags:      []string{},
+				SKUs:      []string{},
+				Status:    "draft",
+				CreatedAt: created,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DocumentFromRow(tt.row); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DocumentFromRow() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	want := postgresCursor{Rank: 0.0607927, CreatedAt: time.Now().UTC().Truncate(time.Microsecond), ID: uuid.New()}
+
+	s, err := encodeCursor(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got postgresCursor
+	if err := decodeCursor(s, &got); err != nil {
+		t.Fatalf("decodeCursor() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("decodeCursor() = %+v, want %+v", got, want)
+	}
+
+	if err := decodeCursor("not base64!", &got); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("decodeCursor(garbage) error = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestBatchIDs(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	events := []database.OutboxEvent{
+		{ID: 1, AggregateID: a},
+		{ID: 2, AggregateID: b},
+		{ID: 3, AggregateID: a},
+	}
+
+	eventIDs, productIDs := batchIDs(events)
+	if !reflect.DeepEqual(eventIDs, []int64{1, 2, 3}) {
+		t.Errorf("event IDs = %v", eventIDs)
+	}
+	if !reflect.DeepEqual(productIDs, []uuid.UUID{a, b}) {
+		t.Errorf("product IDs = %v, want each product once in first-seen order", productIDs)
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
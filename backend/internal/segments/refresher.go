@@ -0,0 +1,95 @@
+package segments
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+)
+
+// DefaultRefreshInterval is how long the members of a rule group may go
+// without a recalculation when CUSTOMER_GROUP_REFRESH_INTERVAL is unset
+const DefaultRefreshInterval = 6 * time.Hour
+
+// RefresherConfig configures a Refresher
+type RefresherConfig struct {
+	// StaleAfter is how long after its last recalculation a rule group is
+	// recalculated again
+	StaleAfter time.Duration
+	Interval   time.Duration
+	// BatchSize bounds the groups recalculated each interval
+	BatchSize int
+	Logger    *slog.Logger
+}
+
+// Refresher keeps rule groups current as customers place orders and are
+// tagged. Each group is claimed before it is recalculated, so running
+// several at once is harmless.
+type Refresher struct {
+	db  *database.Queries
+	cfg RefresherConfig
+}
+
+// NewRefresher creates a refresher over db
+func NewRefresher(db *database.Queries, cfg RefresherConfig) *Refresher {
+	if cfg.StaleAfter <= 0 {
+		cfg.StaleAfter = DefaultRefreshInterval
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 5 * time.Minute
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 20
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &Refresher{db: db, cfg: cfg}
+}
+
+// Run recalculates stale groups once an interval until ctx is cancelled
+func (r *Refresher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := r.RefreshStale(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			r.cfg.Logger.Error("customer group refresh failed", "error", err)
+		} else if n > 0 {
+			r.cfg.Logger.Info("customer groups recalculated", "groups", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RefreshStale recalculates up to a batch of rule groups last recalculated
+// more than StaleAfter before now and returns how many it recalculated
+func (r *Refresher) RefreshStale(ctx context.Context, now time.Time) (int, error) {
+	var n int
+	for n < r.cfg.BatchSize && ctx.Err() == nil {
+		id, err := r.db.ClaimStaleCustomerGroup(ctx, database.ClaimStaleCustomerGroupParams{
+			Now:         now,
+			StaleBefore: now.Add(-r.cfg.StaleAfter),
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		if _, err := r.db.RecalculateCustomerGroup(ctx, id); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
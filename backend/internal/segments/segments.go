@@ -0,0 +1,143 @@
+// Package segments holds the rules of customer groups, which are either
+// picked by hand or recalculated in the background from what customers
+// spent, how many orders they placed and how they are tagged, and the
+// filters of the store customer listing and its exports.
+package segments
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/dfodeker/terminus/internal/orders"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidRules  = errors.New("invalid rules")
+	ErrInvalidFilter = errors.New("invalid filter")
+)
+
+// Kind says how the members of a customer group are chosen, matching
+// customer_groups.kind
+type Kind string
+
+const (
+	// KindManual groups have the customers staff put in them
+	KindManual Kind = "manual"
+	// KindRule groups have the customers matching their rules
+	KindRule Kind = "rule"
+)
+
+// IsValid returns true if the kind is known
+func (k Kind) IsValid() bool {
+	return k == KindManual || k == KindRule
+}
+
+// Rules select the customers of a rule group. A customer is in the group
+// when they match every rule that is set. Spend is in minor units of the
+// store currency, net of refunds.
+type Rules struct {
+	MinTotalSpentCents *int64 `json:"min_total_spent_cents,omitempty"`
+	MaxTotalSpentCents *int64 `json:"max_total_spent_cents,omitempty"`
+	MinOrderCount      *int32 `json:"min_order_count,omitempty"`
+	MaxOrderCount      *int32 `json:"max_order_count,omitempty"`
+	// Tags must all be on a customer
+	Tags []string `json:"tags,omitempty"`
+}
+
+// IsZero reports whether no rule is set
+func (r Rules) IsZero() bool {
+	return r.MinTotalSpentCents == nil && r.MaxTotalSpentCents == nil &&
+		r.MinOrderCount == nil && r.MaxOrderCount == nil && len(r.Tags) == 0
+}
+
+// Normalize checks the rules and returns them with their tags normalized
+func (r Rules) Normalize() (Rules, error) {
+	tags, err := NormalizeTags(r.Tags)
+	if err != nil {
+		return Rules{}, fmt.Errorf("%w: %w", ErrInvalidRules, err)
+	}
+	r.Tags = tags
+	if r.IsZero() {
+		return Rules{}, fmt.Errorf("%w: set at least one of spend, order count or tags", ErrInvalidRules)
+	}
+	for _, v := range []*int64{r.MinTotalSpentCents, r.MaxTotalSpentCents} {
+		if v != nil && *v < 0 {
+			return Rules{}, fmt.Errorf("%w: spend must not be negative", ErrInvalidRules)
+		}
+	}
+	for _, v := range []*int32{r.MinOrderCount, r.MaxOrderCount} {
+		if v != nil && *v < 0 {
+			return Rules{}, fmt.Errorf("%w: order count must not be negative", ErrInvalidRules)
+		}
+	}
+	if r.MinTotalSpentCents != nil && r.MaxTotalSpentCents != nil && *r.MinTotalSpentCents > *r.MaxTotalSpentCents {
+		return Rules{}, fmt.Errorf("%w: min_total_spent_cents must not be above max_total_spent_cents", ErrInvalidRules)
+	}
+	if r.MinOrderCount != nil && r.MaxOrderCount != nil && *r.MinOrderCount > *r.MaxOrderCount {
+		return Rules{}, fmt.Errorf("%w: min_order_count must not be above max_order_count", ErrInvalidRules)
+	}
+	return r, nil
+}
+
+// NormalizeTags applies the rules of order tags to customer tags
+func NormalizeTags(tags []string) ([]string, error) {
+	return orders.NormalizeTags(tags)
+}
+
+// RecalculateArgs is the job that recalculates the members of one rule
+// group
+type RecalculateArgs struct {
+	GroupID uuid.UUID `json:"group_id"`
+}
+
+func (RecalculateArgs) Kind() string { return "customer_groups.recalculate" }
+
+// Filter narrows the store customer listing. Zero values match everything.
+type Filter struct {
+	// Tags must all be present on a customer
+	Tags             []string
+	AcceptsMarketing *bool
+	GroupID          *uuid.UUID
+}
+
+// ParseFilter reads filters from query parameters:
+//
+//	tag=vip&tag=wholesale     all of the tags (comma-separated also works)
+//	accepts_marketing=true    customers who opted in to marketing
+//	group_id=<uuid>           members of a customer group
+func ParseFilter(q url.Values) (Filter, error) {
+	var f Filter
+
+	var raw []string
+	for _, v := range q["tag"] {
+		raw = append(raw, strings.Split(v, ",")...)
+	}
+	tags, err := NormalizeTags(raw)
+	if err != nil {
+		return Filter{}, fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+	}
+	if len(tags) > orders.MaxFilterValues {
+		return Filter{}, fmt.Errorf("%w: at most %d tag values are allowed", ErrInvalidFilter, orders.MaxFilterValues)
+	}
+	f.Tags = tags
+
+	if v := q.Get("accepts_marketing"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return Filter{}, fmt.Errorf("%w: accepts_marketing must be true or false", ErrInvalidFilter)
+		}
+		f.AcceptsMarketing = &b
+	}
+	if v := q.Get("group_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return Filter{}, fmt.Errorf("%w: group_id must be a UUID", ErrInvalidFilter)
+		}
+		f.GroupID = &id
+	}
+	return f, nil
+}
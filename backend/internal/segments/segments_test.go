@@ -0,0 +1,113 @@
+package segments
+
+import (
+	"errors"
+	"net/url"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func cents(n int64) *int64 { return &n }
+func count(n int32) *int32 { return &n }
+
+func TestRulesNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    Rules
+		wantTags []string
+		wantErr  bool
+	}{
+		{name: "spend only", rules: Rules{MinTotalSpentCents: cents(50000)}, wantTags: []string{}},
+		{name: "tags normalized", rules: Rules{Tags: []string{" VIP ", "vip", "Wholesale"}}, wantTags: []string{"vip", "wholesale"}},
+		{name: "range", rules: Rules{MinOrderCount: count(2), MaxOrderCount: count(2)}, wantTags: []string{}},
+		{name: "no rules", rules: Rules{}, wantErr: true},
+		{name: "blank tags only", rules: Rules{Tags: []string{" ", ""}}, wantErr: true},
+		{name: "negative spend", rules: Rules{MaxTotalSpentCents: cents(-1)}, wantErr: true},
+		{name: "negative order count", rules: Rules{MinOrderCount: count(-1)}, wantErr: true},
+		{name: "spend range inverted", rules: Rules{MinTotalSpentCents: cents(200), MaxTotalSpentCents: cents(100)}, wantErr: true},
+		{name: "order range inverted", rules: Rules{MinOrderCount: count(5), MaxOrderCount: count(1)}, wantErr: true},
+		{name: "tag with comma", rules: Rules{Tags: []string{"a,b"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.rules.Normalize()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Normalize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrInvalidRules) {
+					t.Errorf("Normalize() error = %v, want ErrInvalidRules", err)
+				}
+				return
+			}
+			if !slices.Equal(got.Tags, tt.wantTags) {
+				t.Errorf("Normalize() tags = %q, want %q", got.Tags, tt.wantTags)
+			}
+		})
+	}
+}
+
+func TestKindIsValid(t *testing.T) {
+	if !KindManual.IsValid() || !KindRule.IsValid() || Kind("smart").IsValid() {
+		t.Error("IsValid() is wrong")
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	groupID := uuid.New()
+	tests := []struct {
+		name    string
+		query   string
+		check   func(t *testing.T, f Filter)
+		wantErr bool
+	}{
+		{
+			name:  "empty",
+			query: "",
+			check: func(t *testing.T, f Filter) {
+				if len(f.Tags) != 0 || f.AcceptsMarketing != nil || f.GroupID != nil {
+					t.Errorf("Filter = %+v", f)
+				}
+			},
+		},
+		{
+			name:  "all filters",
+			query: "tag=VIP,wholesale&tag=vip&accepts_marketing=true&group_id=" + groupID.String(),
+			check: func(t *testing.T, f Filter) {
+				if !slices.Equal(f.Tags, []string{"vip", "wholesale"}) {
+					t.Errorf("Tags = %q", f.Tags)
+				}
+				if f.AcceptsMarketing == nil || !*f.AcceptsMarketing {
+					t.Errorf("AcceptsMarketing = %v", f.AcceptsMarketing)
+				}
+				if f.GroupID == nil || *f.GroupID != groupID {
+					t.Errorf("GroupID = %v", f.GroupID)
+				}
+			},
+		},
+		{name: "bad accepts_marketing", query: "accepts_marketing=maybe", wantErr: true},
+		{name: "bad group_id", query: "group_id=wholesale", wantErr: true},
+		{name: "too many tags", query: "tag=a,b,c,d,e,f,g,h,i,j,k,l,m,n,o,p,q,r,s,t,u", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f, err := ParseFilter(q)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrInvalidFilter) {
+					t.Errorf("ParseFilter() error = %v, want ErrInvalidFilter", err)
+				}
+				return
+			}
+			tt.check(t, f)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+// Package entitlements decides what each plan allows: how many stores a
+// tenant may open, products a store may hold and staff seats a tenant may
+// fill, and the API rate its callers get. A store's plan sets its own
+// product limit. A tenant's other limits come from the best plan among its
+// live stores, so upgrading any store lifts them. Once billing is on, every
+// store of a tenant is on the plan the tenant pays for.
+//
+// Limits are checked before creating, not enforced by the database, so two
+// concurrent requests may together pass a limit by one.
+package entitlements
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/ratelimit"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/google/uuid"
+)
+
+// Plans in ascending order. A plan not listed here has the limits of the
+// first.
+var Plans = []string{"free", "pro"}
+
+// Limits are what a plan allows. Zero means unlimited.
+type Limits struct {
+	Stores           int `json:"stores"`
+	ProductsPerStore int `json:"products_per_store"`
+	StaffSeats       int `json:"staff_seats"`
+}
+
+// DefaultLimits are the limits of each plan
+var DefaultLimits = map[string]Limits{
+	"free": {Stores: 1, ProductsPerStore: 100, StaffSeats: 2},
+	"pro":  {Stores: 10, ProductsPerStore: 10000, StaffSeats: 25},
+}
+
+// Plan is a plan's name with everything it allows
+type Plan struct {
+	Name   string
+	Limits Limits
+	// APIRate is the plan's rate limit quota, from RATE_LIMIT_PLANS
+	APIRate ratelimit.Quota
+}
+
+// Catalog looks plans up by name
+type Catalog struct {
+	limits map[string]Limits
+	rates  ratelimit.Plans
+}
+
+// NewCatalog returns the catalog of the default limits, with API rates
+// from rates. Rates may be nil where API rates don't matter.
+func NewCatalog(rates ratelimit.Plans) Catalog {
+	return Catalog{limits: DefaultLimits, rates: rates}
+}
+
+// Plan returns the named plan, or the first plan when name is unknown
+func (c Catalog) Plan(name string) Plan {
+	if !slices.Contains(Plans, name) {
+		name = Plans[0]
+	}
+	return Plan{Name: name, Limits: c.limits[name], APIRate: c.rates.Best([]string{name})}
+}
+
+// Best returns the highest of the named plans, or the first plan when none
+// is known
+func (c Catalog) Best(names []string) Plan {
+	best := 0
+	for _, name := range names {
+		best = max(best, slices.Index(Plans, name))
+	}
+	return c.Plan(Plans[best])
+}
+
+// Queries is the slice of the database the service uses
+type Queries interface {
+	ListTenantStorePlans(ctx context.Context, tenantID uuid.NullUUID) ([]string, error)
+	GetStoreByID(ctx context.Context, id uuid.UUID) (database.Store, error)
+	CountTenantStores(ctx context.Context, tenantID uuid.UUID) (int64, error)
+	CountStoreProducts(ctx context.Context, storeID uuid.UUID) (int64, error)
+	CountTenantSeats(ctx context.Context, arg database.CountTenantSeatsParams) (int64, error)
+}
+
+type Service struct {
+	q       Queries
+	catalog Catalog
+}
+
+func New(q Queries, catalog Catalog) *Service {
+	return &Service{q: q, catalog: catalog}
+}
+
+// TenantPlan is the best plan among a tenant's live stores
+func (s *Service) TenantPlan(ctx context.Context, tenantID uuid.UUID) (Plan, error) {
+	names, err := s.q.ListTenantStorePlans(ctx, uuid.NullUUID{UUID: tenantID, Valid: true})
+	if err != nil {
+		return Plan{}, err
+	}
+	return s.catalog.Best(names), nil
+}
+
+// CheckStores returns an upgrade-required error when the tenant can't
+// open, or restore, another store
+func (s *Service) CheckStores(ctx context.Context, tenantID uuid.UUID) error {
+	plan, err := s.TenantPlan(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	count, err := s.q.CountTenantStores(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	return check(plan, plan.Limits.Stores, count, "store", "stores")
+}
+
+// CheckProducts returns an upgrade-required error when the store can't
+// hold another product
+func (s *Service) CheckProducts(ctx context.Context, storeID uuid.UUID) error {
+	store, err := s.q.GetStoreByID(ctx, storeID)
+	if err != nil {
+		return service.NotFoundAs(err, "Store not found")
+	}
+	plan := s.catalog.Plan(store.Plan)
+	count, err := s.q.CountStoreProducts(ctx, storeID)
+	if err != nil {
+		return err
+	}
+	return check(plan, plan.Limits.ProductsPerStore, count, "product per store", "products per store")
+}
+
+// CheckSeats returns an upgrade-required error when inviting email would
+// take the tenant past its staff seats. An open invitation to email
+// already holds its seat.
+func (s *Service) CheckSeats(ctx context.Context, tenantID uuid.UUID, email string) error {
+	plan, err := s.TenantPlan(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	count, err := s.q.CountTenantSeats(ctx, database.CountTenantSeatsParams{TenantID: tenantID, ExceptEmail: email})
+	if err != nil {
+		return err
+	}
+	return check(plan, plan.Limits.StaffSeats, count, "staff seat", "staff seats")
+}
+
+// Usage is a tenant's plan and how much of it is in use
+type Usage struct {
+	Plan       Plan
+	Stores     int64
+	StaffSeats int64
+}
+
+// Usage reports the tenant's plan with its store and seat counts
+func (s *Service) Usage(ctx context.Context, tenantID uuid.UUID) (Usage, error) {
+	plan, err := s.TenantPlan(ctx, tenantID)
+	if err != nil {
+		return Usage{}, err
+	}
+	stores, err := s.q.CountTenantStores(ctx, tenantID)
+	if err != nil {
+		return Usage{}, err
+	}
+	seats, err := s.q.CountTenantSeats(ctx, database.CountTenantSeatsParams{TenantID: tenantID})
+	if err != nil {
+		return Usage{}, err
+	}
+	return Usage{Plan: plan, Stores: stores, StaffSeats: seats}, nil
+}
+
+// check fails when count has reached limit
+func check(plan Plan, limit int, count int64, one, many string) error {
+	if limit == 0 || count < int64(limit) {
+		return nil
+	}
+	noun := many
+	if limit == 1 {
+		noun = one
+	}
+	return service.UpgradeRequired(fmt.Sprintf("The %s plan allows %d %s. Upgrade to add more.", plan.Name, limit, noun))
+}
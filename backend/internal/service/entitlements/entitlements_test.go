@@ -0,0 +1,138 @@
+package entitlements
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/ratelimit"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/google/uuid"
+)
+
+type fakeQueries struct {
+	plans    []string
+	stores   map[uuid.UUID]database.Store
+	count    int64
+	seats    int64
+	seatsArg database.CountTenantSeatsParams
+}
+
+func (f *fakeQueries) ListTenantStorePlans(context.Context, uuid.NullUUID) ([]string, error) {
+	return f.plans, nil
+}
+
+func (f *fakeQueries) GetStoreByID(_ context.Context, id uuid.UUID) (database.Store, error) {
+	store, ok := f.stores[id]
+	if !ok {
+		return database.Store{}, sql.ErrNoRows
+	}
+	return store, nil
+}
+
+func (f *fakeQueries) CountTenantStores(context.Context, uuid.UUID) (int64, error) {
+	return f.count, nil
+}
+
+func (f *fakeQueries) CountStoreProducts(context.Context, uuid.UUID) (int64, error) {
+	return f.count, nil
+}
+
+func (f *fakeQueries) CountTenantSeats(_ context.Context, arg database.CountTenantSeatsParams) (int64, error) {
+	f.seatsArg = arg
+	return f.seats, nil
+}
+
+func TestCatalog(t *testing.T) {
+	rates := ratelimit.Plans{
+		"free": {Requests: 600, Window: time.Minute},
+		"pro":  {Requests: 6000, Window: time.Minute},
+	}
+	c := NewCatalog(rates)
+
+	tests := []struct {
+		names []string
+		want  string
+	}{
+		{names: nil, want: "free"},
+		{names: []string{"free"}, want: "free"},
+		{names: []string{"free", "pro"}, want: "pro"},
+		{names: []string{"pro", "free"}, want: "pro"},
+		{names: []string{"legacy"}, want: "free"},
+		{names: []string{"legacy", "pro"}, want: "pro"},
+	}
+	for _, tt := range tests {
+		got := c.Best(tt.names)
+		if got.Name != tt.want || got.Limits != DefaultLimits[tt.want] || got.APIRate != rates[tt.want] {
+			t.Errorf("Best(%q) = %+v, want the %s plan", tt.names, got, tt.want)
+		}
+	}
+	if got := c.Plan("legacy"); got.Name != "free" {
+		t.Errorf("Plan(legacy) = %+v, want the free plan", got)
+	}
+	if got := NewCatalog(nil).Plan("pro"); got.Limits != DefaultLimits["pro"] {
+		t.Errorf("Plan(pro) without rates = %+v", got)
+	}
+}
+
+func TestChecks(t *testing.T) {
+	free, pro := uuid.New(), uuid.New()
+	stores := map[uuid.UUID]database.Store{
+		free: {ID: free, Plan: "free"},
+		pro:  {ID: pro, Plan: "pro"},
+	}
+	freeLimits, proLimits := DefaultLimits["free"], DefaultLimits["pro"]
+
+	tests := []struct {
+		name    string
+		plans   []string
+		count   int64
+		check   func(s *Service) error
+		wantErr error
+	}{
+		{name: "first store", count: 0, check: func(s *Service) error { return s.CheckStores(context.Background(), uuid.New()) }},
+		{name: "free tenant with a store", plans: []string{"free"}, count: int64(freeLimits.Stores), check: func(s *Service) error { return s.CheckStores(context.Background(), uuid.New()) }, wantErr: service.ErrForbidden},
+		{name: "pro tenant under its stores", plans: []string{"free", "pro"}, count: int64(freeLimits.Stores), check: func(s *Service) error { return s.CheckStores(context.Background(), uuid.New()) }},
+		{name: "pro tenant at its stores", plans: []string{"pro"}, count: int64(proLimits.Stores), check: func(s *Service) error { return s.CheckStores(context.Background(), uuid.New()) }, wantErr: service.ErrForbidden},
+		{name: "free store under its products", count: int64(freeLimits.ProductsPerStore) - 1, check: func(s *Service) error { return s.CheckProducts(context.Background(), free) }},
+		{name: "free store at its products", count: int64(freeLimits.ProductsPerStore), check: func(s *Service) error { return s.CheckProducts(context.Background(), free) }, wantErr: service.ErrForbidden},
+		// A pro tenant's free store keeps the free product limit
+		{name: "free store of a pro tenant", plans: []string{"pro"}, count: int64(freeLimits.ProductsPerStore), check: func(s *Service) error { return s.CheckProducts(context.Background(), free) }, wantErr: service.ErrForbidden},
+		{name: "pro store", count: int64(freeLimits.ProductsPerStore), check: func(s *Service) error { return s.CheckProducts(context.Background(), pro) }},
+		{name: "unknown store", check: func(s *Service) error { return s.CheckProducts(context.Background(), uuid.New()) }, wantErr: service.ErrNotFound},
+	}
+	for _, tt := range tests {
+		s := New(&fakeQueries{plans: tt.plans, stores: stores, count: tt.count}, NewCatalog(nil))
+		err := tt.check(s)
+		if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		var svcErr *service.Error
+		if tt.wantErr == service.ErrForbidden && (!errors.As(err, &svcErr) || svcErr.Code != problem.CodeUpgradeRequired) {
+			t.Errorf("%s: error = %#v, want code %s", tt.name, err, problem.CodeUpgradeRequired)
+		}
+	}
+}
+
+func TestCheckSeats(t *testing.T) {
+	seats := int64(DefaultLimits["free"].StaffSeats)
+	q := &fakeQueries{seats: seats - 1}
+	s := New(q, NewCatalog(nil))
+
+	if err := s.CheckSeats(context.Background(), uuid.New(), "new@example.com"); err != nil {
+		t.Errorf("CheckSeats() under the limit = %v", err)
+	}
+	if q.seatsArg.ExceptEmail != "new@example.com" {
+		t.Errorf("CheckSeats() counted seats except %q", q.seatsArg.ExceptEmail)
+	}
+	q.seats = seats
+	err := s.CheckSeats(context.Background(), uuid.New(), "new@example.com")
+	if err == nil || err.Error() != "The free plan allows 2 staff seats. Upgrade to add more." {
+		t.Errorf("CheckSeats() at the limit = %v", err)
+	}
+}
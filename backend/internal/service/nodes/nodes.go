@@ -0,0 +1,126 @@
+// Package nodes resolves a GID to the record it names and the tenant that
+// owns it, for lookups that only have the GID to go on
+package nodes
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/google/uuid"
+)
+
+// Queries is the slice of the database the service uses
+type Queries interface {
+	GetTenantByGID(ctx context.Context, gid sql.NullInt64) (database.Tenant, error)
+	GetStoreByGID(ctx context.Context, gid sql.NullInt64) (database.Store, error)
+	GetStoreByID(ctx context.Context, id uuid.UUID) (database.Store, error)
+	GetProductByGID(ctx context.Context, gid sql.NullInt64) (database.Product, error)
+	GetProductVariantByGID(ctx context.Context, gid sql.NullInt64) (database.ProductVariant, error)
+	GetRoleByGID(ctx context.Context, gid sql.NullInt64) (database.Role, error)
+	GetCustomerByGID(ctx context.Context, gid sql.NullInt64) (database.Customer, error)
+	GetOrderByGID(ctx context.Context, gid sql.NullInt64) (database.Order, error)
+	GetCollectionByGID(ctx context.Context, gid sql.NullInt64) (database.Collection, error)
+}
+
+type Service struct {
+	q Queries
+}
+
+func New(q Queries) *Service {
+	return &Service{q: q}
+}
+
+// Node is a looked-up record. Value is a database.Tenant, database.Store,
+// database.Product, database.ProductVariant, database.Role,
+// database.Customer, database.Order or database.Collection, matching
+// GID.Type.
+type Node struct {
+	GID      gid.GID
+	TenantID uuid.UUID
+	Value    any
+}
+
+const notFound = "Node not found"
+
+// Lookup fetches the record g names. Callers must still check the caller
+// may see TenantID before handing the node out.
+func (s *Service) Lookup(ctx context.Context, g gid.GID) (Node, error) {
+	id := sql.NullInt64{Int64: int64(g.ID), Valid: true}
+	node := Node{GID: g}
+
+	switch g.Type {
+	case gid.EntityTenant:
+		tenant, err := s.q.GetTenantByGID(ctx, id)
+		if err != nil {
+			return Node{}, service.NotFoundAs(err, notFound)
+		}
+		node.TenantID, node.Value = tenant.ID, tenant
+
+	case gid.EntityStore:
+		store, err := s.q.GetStoreByGID(ctx, id)
+		if err != nil {
+			return Node{}, service.NotFoundAs(err, notFound)
+		}
+		// Stores from before tenants existed have no owner to check against
+		if !store.TenantID.Valid {
+			return Node{}, service.NotFound(notFound)
+		}
+		node.TenantID, node.Value = store.TenantID.UUID, store
+
+	case gid.EntityProduct:
+		product, err := s.q.GetProductByGID(ctx, id)
+		if err != nil {
+			return Node{}, service.NotFoundAs(err, notFound)
+		}
+		store, err := s.q.GetStoreByID(ctx, product.StoreID)
+		if err != nil {
+			return Node{}, service.NotFoundAs(err, notFound)
+		}
+		if !store.TenantID.Valid {
+			return Node{}, service.NotFound(notFound)
+		}
+		node.TenantID, node.Value = store.TenantID.UUID, product
+
+	case gid.EntityProductVariant:
+		variant, err := s.q.GetProductVariantByGID(ctx, id)
+		if err != nil {
+			return Node{}, service.NotFoundAs(err, notFound)
+		}
+		node.TenantID, node.Value = variant.TenantID, variant
+
+	case gid.EntityRole:
+		role, err := s.q.GetRoleByGID(ctx, id)
+		if err != nil {
+			return Node{}, service.NotFoundAs(err, notFound)
+		}
+		node.TenantID, node.Value = role.TenantID, role
+
+	case gid.EntityCustomer:
+		customer, err := s.q.GetCustomerByGID(ctx, id)
+		if err != nil {
+			return Node{}, service.NotFoundAs(err, notFound)
+		}
+		node.TenantID, node.Value = customer.TenantID, customer
+
+	case gid.EntityOrder:
+		order, err := s.q.GetOrderByGID(ctx, id)
+		if err != nil {
+			return Node{}, service.NotFoundAs(err, notFound)
+		}
+		node.TenantID, node.Value = order.TenantID, order
+
+	case gid.EntityCollection:
+		collection, err := s.q.GetCollectionByGID(ctx, id)
+		if err != nil {
+			return Node{}, service.NotFoundAs(err, notFound)
+		}
+		node.TenantID, node.Value = collection.TenantID, collection
+
+	default:
+		return Node{}, service.Invalid("Nodes of type " + string(g.Type) + " can't be looked up")
+	}
+	return node, nil
+}
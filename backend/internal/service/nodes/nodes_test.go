@@ -0,0 +1,129 @@
+package nodes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/google/uuid"
+)
+
+type fakeQueries struct {
+	tenants     map[int64]database.Tenant
+	stores      map[int64]database.Store
+	products    map[int64]database.Product
+	variants    map[int64]database.ProductVariant
+	roles       map[int64]database.Role
+	customers   map[int64]database.Customer
+	orders      map[int64]database.Order
+	collections map[int64]database.Collection
+}
+
+func lookup[T any](m map[int64]T, id sql.NullInt64) (T, error) {
+	v, ok := m[id.Int64]
+	if !ok {
+		return v, sql.ErrNoRows
+	}
+	return v, nil
+}
+
+func (f *fakeQueries) GetTenantByGID(_ context.Context, id sql.NullInt64) (database.Tenant, error) {
+	return lookup(f.tenants, id)
+}
+
+func (f *fakeQueries) GetStoreByGID(_ context.Context, id sql.NullInt64) (database.Store, error) {
+	return lookup(f.stores, id)
+}
+
+func (f *fakeQueries) GetStoreByID(_ context.Context, id uuid.UUID) (database.Store, error) {
+	for _, store := range f.stores {
+		if store.ID == id {
+			return store, nil
+		}
+	}
+	return database.Store{}, sql.ErrNoRows
+}
+
+func (f *fakeQueries) GetProductByGID(_ context.Context, id sql.NullInt64) (database.Product, error) {
+	return lookup(f.products, id)
+}
+
+func (f *fakeQueries) GetProductVariantByGID(_ context.Context, id sql.NullInt64) (database.ProductVariant, error) {
+	return lookup(f.variants, id)
+}
+
+func (f *fakeQueries) GetRoleByGID(_ context.Context, id sql.NullInt64) (database.Role, error) {
+	return lookup(f.roles, id)
+}
+
+func (f *fakeQueries) GetCustomerByGID(_ context.Context, id sql.NullInt64) (database.Customer, error) {
+	return lookup(f.customers, id)
+}
+
+func (f *fakeQueries) GetOrderByGID(_ context.Context, id sql.NullInt64) (database.Order, error) {
+	return lookup(f.orders, id)
+}
+
+func (f *fakeQueries) GetCollectionByGID(_ context.Context, id sql.NullInt64) (database.Collection, error) {
+	return lookup(f.collections, id)
+}
+
+func TestLookup(t *testing.T) {
+	tenantID := uuid.New()
+	store := database.Store{ID: uuid.New(), TenantID: uuid.NullUUID{UUID: tenantID, Valid: true}}
+	legacyStore := database.Store{ID: uuid.New()}
+	q := &fakeQueries{
+		tenants:     map[int64]database.Tenant{1: {ID: tenantID}},
+		stores:      map[int64]database.Store{2: store, 3: legacyStore},
+		products:    map[int64]database.Product{4: {ID: uuid.New(), StoreID: store.ID}, 5: {ID: uuid.New(), StoreID: legacyStore.ID}},
+		variants:    map[int64]database.ProductVariant{6: {ID: uuid.New(), TenantID: tenantID}},
+		roles:       map[int64]database.Role{7: {ID: uuid.New(), TenantID: tenantID}},
+		customers:   map[int64]database.Customer{8: {ID: uuid.New(), TenantID: tenantID, StoreID: store.ID}},
+		orders:      map[int64]database.Order{9: {ID: uuid.New(), TenantID: tenantID, StoreID: store.ID}},
+		collections: map[int64]database.Collection{10: {ID: uuid.New(), TenantID: tenantID, StoreID: store.ID}},
+	}
+	svc := New(q)
+
+	tests := []struct {
+		name    string
+		gid     gid.GID
+		wantErr error
+	}{
+		{name: "tenant", gid: gid.TenantGID(1)},
+		{name: "store", gid: gid.StoreGID(2)},
+		{name: "store without tenant", gid: gid.StoreGID(3), wantErr: service.ErrNotFound},
+		{name: "product", gid: gid.ProductGID(4)},
+		{name: "product in store without tenant", gid: gid.ProductGID(5), wantErr: service.ErrNotFound},
+		{name: "variant", gid: gid.ProductVariantGID(6)},
+		{name: "role", gid: gid.RoleGID(7)},
+		{name: "customer", gid: gid.CustomerGID(8)},
+		{name: "order", gid: gid.OrderGID(9)},
+		{name: "collection", gid: gid.CollectionGID(10)},
+		{name: "missing order", gid: gid.OrderGID(99), wantErr: service.ErrNotFound},
+		{name: "missing", gid: gid.ProductGID(99), wantErr: service.ErrNotFound},
+		{name: "wrong type for id", gid: gid.RoleGID(1), wantErr: service.ErrNotFound},
+		{name: "unsupported type", gid: gid.UserGID(1), wantErr: service.ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := svc.Lookup(context.Background(), tt.gid)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Lookup() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if node.TenantID != tenantID {
+				t.Errorf("TenantID = %v, want %v", node.TenantID, tenantID)
+			}
+			if node.GID != tt.gid || node.Value == nil {
+				t.Errorf("Lookup() = %+v", node)
+			}
+		})
+	}
+}
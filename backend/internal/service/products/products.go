@@ -0,0 +1,400 @@
+// Package products manages a store's catalog products
+package products
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/inventory"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/slug"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/google/uuid"
+)
+
+// Product statuses. Only active products are shown to shoppers or sold;
+// drafts are being prepared and archived products are retired.
+const (
+	StatusDraft    = "draft"
+	StatusActive   = "active"
+	StatusArchived = "archived"
+)
+
+// DefaultStatus is given to products created without one
+const DefaultStatus = StatusActive
+
+// Statuses are the states a product can be in
+var Statuses = []string{StatusActive, StatusDraft, StatusArchived}
+
+// transitions lists where each status may move. An archived product goes
+// back to draft before it can be published again.
+var transitions = map[string][]string{
+	StatusDraft:    {StatusActive, StatusArchived},
+	StatusActive:   {StatusDraft, StatusArchived},
+	StatusArchived: {StatusDraft},
+}
+
+// transitionsTo lists the statuses a product may move to status from
+func transitionsTo(status string) []string {
+	var from []string
+	for _, s := range Statuses {
+		if slices.Contains(transitions[s], status) {
+			from = append(from, s)
+		}
+	}
+	return from
+}
+
+// CanTransition reports whether a product may move from one status to
+// another. Staying in the same status is always allowed.
+func CanTransition(from, to string) bool {
+	return from == to || slices.Contains(transitions[from], to)
+}
+
+// Field limits, matching the columns they're stored in
+const (
+	MaxNameLength   = 255
+	MaxHandleLength = 100
+	MaxSKULength    = 100
+)
+
+// Unique constraints on products
+const (
+	handleConstraint = "uq_products_store_handle"
+	skuConstraint    = "uq_products_store_sku"
+)
+
+const (
+	duplicateHandle = "A product with this handle already exists"
+	duplicateSKU    = "A product with this SKU already exists"
+)
+
+var versionConflict = service.VersionConflict("The product was changed by another request, please reload it and retry")
+
+// Queries is the slice of the database the service uses
+type Queries interface {
+	CreateProduct(ctx context.Context, arg database.CreateProductParams) (database.Product, error)
+	GetProductByID(ctx context.Context, arg database.GetProductByIDParams) (database.Product, error)
+	UpdateProduct(ctx context.Context, arg database.UpdateProductParams) (database.Product, error)
+	UpdateProductStatus(ctx context.Context, arg database.UpdateProductStatusParams) (database.Product, error)
+	SoftDeleteProduct(ctx context.Context, arg database.SoftDeleteProductParams) (database.Product, error)
+	RestoreProduct(ctx context.Context, arg database.RestoreProductParams) (database.Product, error)
+	ListProductHandlesWithPrefix(ctx context.Context, arg database.ListProductHandlesWithPrefixParams) ([]string, error)
+	ListProductTranslations(ctx context.Context, productID uuid.UUID) ([]database.ProductTranslation, error)
+	UpsertProductTranslation(ctx context.Context, arg database.UpsertProductTranslationParams) (database.ProductTranslation, error)
+	DeleteProductTranslation(ctx context.Context, arg database.DeleteProductTranslationParams) (database.ProductTranslation, error)
+}
+
+type Service struct {
+	q    Queries
+	gids service.GIDGenerator
+}
+
+func New(q Queries, gids service.GIDGenerator) *Service {
+	return &Service{q: q, gids: gids}
+}
+
+// CreateInput describes a new product. Optional fields are nil when unset.
+type CreateInput struct {
+	StoreID uuid.UUID
+	Name    string
+	// Handle is generated from Name when empty, with a numeric suffix if
+	// need be to keep it unique in the store
+	Handle           string
+	Description      *string
+	InventoryTracked bool
+	SKU              *string
+	Tags             *string
+	Status           string
+}
+
+// Create adds a product to a store
+func (s *Service) Create(ctx context.Context, in CreateInput) (database.Product, error) {
+	var v validate.Validator
+	nameOK := v.Required("name", in.Name) && v.MaxLength("name", in.Name, MaxNameLength)
+	handle, generated := in.Handle, in.Handle == ""
+	if !generated {
+		validateHandle(&v, handle)
+	} else if nameOK {
+		handle = slug.Make(in.Name, MaxHandleLength)
+		v.Check(handle != "", "handle", validate.CodeRequired, "is required when the name has no letters or digits")
+	}
+	validateOptional(&v, in.SKU, in.Status)
+	v.Check(in.Status != StatusArchived, "status", validate.CodeInvalid, "cannot be archived on create")
+	if err := v.Err(); err != nil {
+		return database.Product{}, err
+	}
+
+	status := in.Status
+	if status == "" {
+		status = DefaultStatus
+	}
+
+	if generated {
+		var err error
+		if handle, err = s.freeHandle(ctx, in.StoreID, handle); err != nil {
+			return database.Product{}, err
+		}
+	}
+
+	product, err := s.q.CreateProduct(ctx, database.CreateProductParams{
+		Gid:              service.NewGID(s.gids),
+		StoreID:          in.StoreID,
+		Handle:           handle,
+		Name:             in.Name,
+		Description:      nullString(in.Description),
+		InventoryTracked: in.InventoryTracked,
+		Sku:              nullString(in.SKU),
+		Tags:             nullString(in.Tags),
+		Status:           status,
+	})
+	return product, s.conflict(ctx, err, in.StoreID, handle)
+}
+
+// Get returns one of a store's products
+func (s *Service) Get(ctx context.Context, storeID, productID uuid.UUID) (database.Product, error) {
+	product, err := s.q.GetProductByID(ctx, database.GetProductByIDParams{
+		ID:      productID,
+		StoreID: storeID,
+	})
+	return product, service.NotFoundAs(err, "Product not found")
+}
+
+// UpdateInput changes the non-nil fields of a product and leaves the rest
+type UpdateInput struct {
+	StoreID          uuid.UUID
+	ID               uuid.UUID
+	Name             *string
+	Handle           *string
+	Description      *string
+	InventoryTracked *bool
+	SKU              *string
+	Tags             *string
+	Status           *string
+	OversellPolicy   *string
+	// Version, when set, is the version the caller last read; the update
+	// is refused with a version conflict if the product has changed since
+	Version *int64
+}
+
+// Update applies a partial update to a product. It is made conditional on
+// the version it merges into, so a concurrent update is never lost.
+func (s *Service) Update(ctx context.Context, in UpdateInput) (database.Product, error) {
+	var v validate.Validator
+	if in.Name != nil && v.Required("name", *in.Name) {
+		v.MaxLength("name", *in.Name, MaxNameLength)
+	}
+	if in.Handle != nil {
+		validateHandle(&v, *in.Handle)
+	}
+	status := ""
+	if in.Status != nil {
+		status = *in.Status
+		v.Required("status", status)
+	}
+	validateOptional(&v, in.SKU, status)
+	if in.OversellPolicy != nil {
+		v.OneOf("oversell_policy", *in.OversellPolicy, inventory.OversellPolicies...)
+	}
+	if err := v.Err(); err != nil {
+		return database.Product{}, err
+	}
+
+	// One statement sets the given fields, so nothing is read first
+	arg := database.UpdateProductParams{
+		ID:             in.ID,
+		StoreID:        in.StoreID,
+		Handle:         nullString(in.Handle),
+		Name:           nullString(in.Name),
+		Description:    nullString(in.Description),
+		Sku:            nullString(in.SKU),
+		Tags:           nullString(in.Tags),
+		Status:         nullString(in.Status),
+		OversellPolicy: nullString(in.OversellPolicy),
+	}
+	if in.InventoryTracked != nil {
+		arg.InventoryTracked = sql.NullBool{Bool: *in.InventoryTracked, Valid: true}
+	}
+	if in.Version != nil {
+		arg.Version = sql.NullInt64{Int64: *in.Version, Valid: true}
+	}
+	if in.Status != nil {
+		arg.FromStatuses = transitionsTo(*in.Status)
+	}
+
+	product, err := s.q.UpdateProduct(ctx, arg)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.Product{}, s.updateRefused(ctx, in)
+	}
+	return product, s.conflict(ctx, err, in.StoreID, arg.Handle.String)
+}
+
+// updateRefused explains why an update matched no product: it is gone, it
+// has moved past the version the caller pinned, or its status can't move
+// to the one asked for
+func (s *Service) updateRefused(ctx context.Context, in UpdateInput) error {
+	existing, err := s.Get(ctx, in.StoreID, in.ID)
+	if err != nil {
+		return err
+	}
+	if in.Version != nil && *in.Version != existing.Version {
+		return versionConflict
+	}
+	if in.Status != nil {
+		if err := checkTransition(existing.Status, *in.Status); err != nil {
+			return err
+		}
+	}
+	// It matched when read just now, so it changed in between
+	return versionConflict
+}
+
+// HandleAvailability says whether a handle is free in a store, and if not,
+// one that is
+type HandleAvailability struct {
+	Handle     string
+	Available  bool
+	Suggestion string
+}
+
+// CheckHandle reports whether handle is free in a store. With an empty
+// handle it checks the one Create would generate from name.
+func (s *Service) CheckHandle(ctx context.Context, storeID uuid.UUID, handle, name string) (HandleAvailability, error) {
+	var v validate.Validator
+	if handle != "" {
+		validateHandle(&v, handle)
+	} else if v.Required("name", name) {
+		handle = slug.Make(name, MaxHandleLength)
+		v.Check(handle != "", "name", validate.CodeFormat, "has no letters or digits to make a handle from")
+	}
+	if err := v.Err(); err != nil {
+		return HandleAvailability{}, err
+	}
+
+	free, err := s.freeHandle(ctx, storeID, handle)
+	if err != nil {
+		return HandleAvailability{}, err
+	}
+	if free == handle {
+		return HandleAvailability{Handle: handle, Available: true}, nil
+	}
+	return HandleAvailability{Handle: handle, Suggestion: free}, nil
+}
+
+// SetStatus moves a product to status, as publishing, unpublishing and
+// archiving do. Moving to the status a product already has changes
+// nothing.
+func (s *Service) SetStatus(ctx context.Context, storeID, productID uuid.UUID, status string) (database.Product, error) {
+	existing, err := s.Get(ctx, storeID, productID)
+	if err != nil {
+		return database.Product{}, err
+	}
+	if existing.Status == status {
+		return existing, nil
+	}
+	if err := checkTransition(existing.Status, status); err != nil {
+		return database.Product{}, err
+	}
+
+	// Conditional on the status just read, so two concurrent changes
+	// can't both pass the transition check
+	product, err := s.q.UpdateProductStatus(ctx, database.UpdateProductStatusParams{
+		ID:         existing.ID,
+		StoreID:    existing.StoreID,
+		Status:     status,
+		FromStatus: existing.Status,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.Product{}, service.Conflict("The product was changed by another request, please retry")
+	}
+	return product, err
+}
+
+// Delete hides a product along with its variants. It can be restored
+// until the worker purges it.
+func (s *Service) Delete(ctx context.Context, storeID, productID uuid.UUID) (database.Product, error) {
+	product, err := s.q.SoftDeleteProduct(ctx, database.SoftDeleteProductParams{
+		ID:      productID,
+		StoreID: storeID,
+	})
+	return product, service.NotFoundAs(err, "Product not found")
+}
+
+// Restore brings back a deleted product with the variants deleted along
+// with it
+func (s *Service) Restore(ctx context.Context, storeID, productID uuid.UUID) (database.Product, error) {
+	product, err := s.q.RestoreProduct(ctx, database.RestoreProductParams{
+		ID:      productID,
+		StoreID: storeID,
+	})
+	return product, service.NotFoundAs(err, "Deleted product not found")
+}
+
+// validateOptional checks the fields create and update treat alike: the
+// SKU when given and the status when not empty
+func validateOptional(v *validate.Validator, sku *string, status string) {
+	if sku != nil {
+		v.MaxLength("sku", *sku, MaxSKULength)
+	}
+	if status != "" {
+		v.OneOf("status", status, Statuses...)
+	}
+}
+
+// freeHandle returns handle if no product in the store has it, or else the
+// first free one with a numeric suffix
+func (s *Service) freeHandle(ctx context.Context, storeID uuid.UUID, handle string) (string, error) {
+	rows, err := s.q.ListProductHandlesWithPrefix(ctx, database.ListProductHandlesWithPrefixParams{
+		StoreID: storeID,
+		Handle:  handle,
+	})
+	if err != nil {
+		return "", err
+	}
+	return slug.Next(handle, MaxHandleLength, func(h string) bool { return slices.Contains(rows, h) }), nil
+}
+
+// conflict converts a unique violation from writing a product into a
+// Conflict. A taken handle comes with a free one to suggest.
+func (s *Service) conflict(ctx context.Context, err error, storeID uuid.UUID, handle string) error {
+	switch {
+	case service.UniqueViolation(err, handleConstraint):
+		// Best effort: the conflict stands without a suggestion
+		suggestion, _ := s.freeHandle(ctx, storeID, handle)
+		return service.HandleTaken(duplicateHandle, suggestion)
+	case service.UniqueViolation(err, skuConstraint):
+		return service.Conflict(duplicateSKU)
+	}
+	return err
+}
+
+// validateHandle checks a handle given by the caller
+func validateHandle(v *validate.Validator, handle string) {
+	if v.Required("handle", handle) && v.MaxLength("handle", handle, MaxHandleLength) {
+		v.Handle("handle", handle)
+	}
+}
+
+// checkTransition rejects a status change the lifecycle doesn't allow
+func checkTransition(from, to string) error {
+	if CanTransition(from, to) {
+		return nil
+	}
+	return &service.Error{
+		Kind:    service.ErrConflict,
+		Code:    problem.CodeInvalidStatusTransition,
+		Message: fmt.Sprintf("A product cannot move from %s to %s", from, to),
+	}
+}
+
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
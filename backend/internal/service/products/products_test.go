@@ -0,0 +1,673 @@
+package products
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/database/dbmock"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type fakeGIDs struct{ next uint64 }
+
+func (g *fakeGIDs) Generate() uint64 {
+	g.next++
+	return g.next
+}
+
+type fakeQueries struct {
+	products map[uuid.UUID]database.Product
+	// beforeUpdate, when set, runs as UpdateProduct starts, standing in for
+	// a request that writes just before it
+	beforeUpdate func()
+	translations []database.ProductTranslation
+}
+
+func newFakeQueries() *fakeQueries {
+	return &fakeQueries{products: map[uuid.UUID]database.Product{}}
+}
+
+// handleTaken mimics the unique constraint on a store's handles
+func (f *fakeQueries) handleTaken(storeID, except uuid.UUID, handle string) error {
+	for _, p := range f.products {
+		if p.StoreID == storeID && p.ID != except && p.Handle == handle {
+			return &pgconn.PgError{Code: "23505", ConstraintName: handleConstraint}
+		}
+	}
+	return nil
+}
+
+func (f *fakeQueries) CreateProduct(_ context.Context, arg database.CreateProductParams) (database.Product, error) {
+	if err := f.handleTaken(arg.StoreID, uuid.Nil, arg.Handle); err != nil {
+		return database.Product{}, err
+	}
+	p := database.Product{
+		ID:               uuid.New(),
+		Gid:              arg.Gid,
+		StoreID:          arg.StoreID,
+		Handle:           arg.Handle,
+		Name:             arg.Name,
+		Description:      arg.Description,
+		InventoryTracked: arg.InventoryTracked,
+		Sku:              arg.Sku,
+		Tags:             arg.Tags,
+		Status:           arg.Status,
+		Version:          1,
+	}
+	f.products[p.ID] = p
+	return p, nil
+}
+
+func (f *fakeQueries) GetProductByID(_ context.Context, arg database.GetProductByIDParams) (database.Product, error) {
+	p, ok := f.products[arg.ID]
+	if !ok || p.StoreID != arg.StoreID || p.DeletedAt.Valid {
+		return database.Product{}, sql.ErrNoRows
+	}
+	return p, nil
+}
+
+func (f *fakeQueries) UpdateProduct(_ context.Context, arg database.UpdateProductParams) (database.Product, error) {
+	if f.beforeUpdate != nil {
+		f.beforeUpdate()
+	}
+	p, ok := f.products[arg.ID]
+	if !ok || p.StoreID != arg.StoreID || p.DeletedAt.Valid {
+		return database.Product{}, sql.ErrNoRows
+	}
+	if arg.Version.Valid && p.Version != arg.Version.Int64 {
+		return database.Product{}, sql.ErrNoRows
+	}
+	if arg.Status.Valid && p.Status != arg.Status.String && !slices.Contains(arg.FromStatuses, p.Status) {
+		return database.Product{}, sql.ErrNoRows
+	}
+	if arg.Handle.Valid {
+		if err := f.handleTaken(arg.StoreID, arg.ID, arg.Handle.String); err != nil {
+			return database.Product{}, err
+		}
+		p.Handle = arg.Handle.String
+	}
+	if arg.Name.Valid {
+		p.Name = arg.Name.String
+	}
+	if arg.Description.Valid {
+		p.Description = arg.Description
+	}
+	if arg.InventoryTracked.Valid {
+		p.InventoryTracked = arg.InventoryTracked.Bool
+	}
+	if arg.Sku.Valid {
+		p.Sku = arg.Sku
+	}
+	if arg.Tags.Valid {
+		p.Tags = arg.Tags
+	}
+	if arg.Status.Valid {
+		p.Status = arg.Status.String
+	}
+	if arg.OversellPolicy.Valid {
+		p.OversellPolicy = arg.OversellPolicy.String
+	}
+	p.Version++
+	f.products[p.ID] = p
+	return p, nil
+}
+
+func (f *fakeQueries) UpdateProductStatus(_ context.Context, arg database.UpdateProductStatusParams) (database.Product, error) {
+	p, ok := f.products[arg.ID]
+	if !ok || p.StoreID != arg.StoreID || p.Status != arg.FromStatus {
+		return database.Product{}, sql.ErrNoRows
+	}
+	p.Status = arg.Status
+	p.Version++
+	f.products[p.ID] = p
+	return p, nil
+}
+
+func (f *fakeQueries) SoftDeleteProduct(_ context.Context, arg database.SoftDeleteProductParams) (database.Product, error) {
+	p, ok := f.products[arg.ID]
+	if !ok || p.StoreID != arg.StoreID || p.DeletedAt.Valid {
+		return database.Product{}, sql.ErrNoRows
+	}
+	p.DeletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	f.products[p.ID] = p
+	return p, nil
+}
+
+func (f *fakeQueries) RestoreProduct(_ context.Context, arg database.RestoreProductParams) (database.Product, error) {
+	p, ok := f.products[arg.ID]
+	if !ok || p.StoreID != arg.StoreID || !p.DeletedAt.Valid {
+		return database.Product{}, sql.ErrNoRows
+	}
+	p.DeletedAt = sql.NullTime{}
+	f.products[p.ID] = p
+	return p, nil
+}
+
+func (f *fakeQueries) ListProductHandlesWithPrefix(_ context.Context, arg database.ListProductHandlesWithPrefixParams) ([]string, error) {
+	var handles []string
+	for _, p := range f.products {
+		if p.StoreID == arg.StoreID && (p.Handle == arg.Handle || strings.HasPrefix(p.Handle, arg.Handle+"-")) {
+			handles = append(handles, p.Handle)
+		}
+	}
+	return handles, nil
+}
+
+func (f *fakeQueries) ListProductTranslations(_ context.Context, productID uuid.UUID) ([]database.ProductTranslation, error) {
+	var rows []database.ProductTranslation
+	for _, t := range f.translations {
+		if t.ProductID == productID {
+			rows = append(rows, t)
+		}
+	}
+	return rows, nil
+}
+
+func (f *fakeQueries) UpsertProductTranslation(_ context.Context, arg database.UpsertProductTranslationParams) (database.ProductTranslation, error) {
+	translation := database.ProductTranslation{
+		ProductID:   arg.ProductID,
+		StoreID:     arg.StoreID,
+		Locale:      arg.Locale,
+		Name:        arg.Name,
+		Description: arg.Description,
+		Handle:      arg.Handle,
+	}
+	for i, t := range f.translations {
+		if t.ProductID == arg.ProductID && t.Locale == arg.Locale {
+			f.translations[i] = translation
+			return translation, nil
+		}
+		if t.StoreID == arg.StoreID && t.Locale == arg.Locale && arg.Handle.Valid && t.Handle == arg.Handle {
+			return database.ProductTranslation{}, &pgconn.PgError{Code: "23505", ConstraintName: "uq_product_translations_store_locale_handle"}
+		}
+	}
+	f.translations = append(f.translations, translation)
+	return translation, nil
+}
+
+func (f *fakeQueries) DeleteProductTranslation(_ context.Context, arg database.DeleteProductTranslationParams) (database.ProductTranslation, error) {
+	for i, t := range f.translations {
+		if t.ProductID == arg.ProductID && t.Locale == arg.Locale {
+			f.translations = slices.Delete(f.translations, i, i+1)
+			return t, nil
+		}
+	}
+	return database.ProductTranslation{}, sql.ErrNoRows
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestCreate(t *testing.T) {
+	storeID := uuid.New()
+
+	tests := []struct {
+		name       string
+		in         CreateInput
+		wantErr    error
+		wantStatus string
+	}{
+		{
+			name:       "defaults status",
+			in:         CreateInput{StoreID: storeID, Name: "Shirt", Handle: "shirt", SKU: ptr("SH-1")},
+			wantStatus: DefaultStatus,
+		},
+		{
+			name:       "keeps status",
+			in:         CreateInput{StoreID: storeID, Name: "Shirt", Handle: "shirt", Status: "draft"},
+			wantStatus: "draft",
+		},
+		{name: "missing name", in: CreateInput{StoreID: storeID, Handle: "shirt"}, wantErr: service.ErrInvalid},
+		{name: "blank handle", in: CreateInput{StoreID: storeID, Name: "Shirt", Handle: " "}, wantErr: service.ErrInvalid},
+		{name: "unknown status", in: CreateInput{StoreID: storeID, Name: "Shirt", Handle: "shirt", Status: "live"}, wantErr: service.ErrInvalid},
+		{name: "handle from name", in: CreateInput{StoreID: storeID, Name: "Linen Shirt"}, wantStatus: DefaultStatus},
+		{name: "malformed handle", in: CreateInput{StoreID: storeID, Name: "Shirt", Handle: "Linen Shirt"}, wantErr: service.ErrInvalid},
+		{name: "archived on create", in: CreateInput{StoreID: storeID, Name: "Shirt", Handle: "shirt", Status: StatusArchived}, wantErr: service.ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := New(newFakeQueries(), &fakeGIDs{})
+			got, err := svc.Create(context.Background(), tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Create() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", got.Status, tt.wantStatus)
+			}
+			if !got.Gid.Valid {
+				t.Error("Create() should assign a GID")
+			}
+			if (tt.in.SKU != nil) != got.Sku.Valid {
+				t.Errorf("Sku = %+v for input %v", got.Sku, tt.in.SKU)
+			}
+		})
+	}
+}
+
+func TestCreateHandles(t *testing.T) {
+	ctx := context.Background()
+	svc := New(newFakeQueries(), &fakeGIDs{})
+	storeID := uuid.New()
+
+	// Each product is created in the same store as those before it
+	steps := []struct {
+		in             CreateInput
+		wantHandle     string
+		wantErr        error
+		wantSuggestion string
+	}{
+		{in: CreateInput{Name: "Linen Shirt"}, wantHandle: "linen-shirt"},
+		{in: CreateInput{Name: "Linen shirt!"}, wantHandle: "linen-shirt-2"},
+		{in: CreateInput{Name: "Shirt", Handle: "linen-shirt"}, wantErr: service.ErrConflict, wantSuggestion: "linen-shirt-3"},
+		{in: CreateInput{Name: "Shirt", Handle: "linen-shirt-3"}, wantHandle: "linen-shirt-3"},
+		{in: CreateInput{Name: "LINEN  SHIRT"}, wantHandle: "linen-shirt-4"},
+	}
+
+	for i, step := range steps {
+		step.in.StoreID = storeID
+		got, err := svc.Create(ctx, step.in)
+		if !errors.Is(err, step.wantErr) {
+			t.Fatalf("step %d: Create() error = %v, want %v", i, err, step.wantErr)
+		}
+		if err != nil {
+			var svcErr *service.Error
+			if !errors.As(err, &svcErr) || svcErr.Suggestion != step.wantSuggestion {
+				t.Errorf("step %d: error = %#v, want suggestion %q", i, err, step.wantSuggestion)
+			}
+			continue
+		}
+		if got.Handle != step.wantHandle {
+			t.Errorf("step %d: Handle = %q, want %q", i, got.Handle, step.wantHandle)
+		}
+	}
+
+	other, err := svc.Create(ctx, CreateInput{StoreID: uuid.New(), Name: "Linen Shirt"})
+	if err != nil || other.Handle != "linen-shirt" {
+		t.Errorf("Create() in another store = %q, %v, want linen-shirt", other.Handle, err)
+	}
+
+	availability, err := svc.CheckHandle(ctx, storeID, "", "Linen Shirt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (HandleAvailability{Handle: "linen-shirt", Suggestion: "linen-shirt-5"}); availability != want {
+		t.Errorf("CheckHandle() = %+v, want %+v", availability, want)
+	}
+}
+
+func TestCreateReportsEveryInvalidField(t *testing.T) {
+	svc := New(newFakeQueries(), &fakeGIDs{})
+	_, err := svc.Create(context.Background(), CreateInput{
+		StoreID: uuid.New(),
+		Handle:  "Summer Shirt",
+		SKU:     ptr(strings.Repeat("x", MaxSKULength+1)),
+		Status:  "live",
+	})
+
+	var invalid *validate.Error
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Create() error = %v, want *validate.Error", err)
+	}
+	got := map[string]string{}
+	for _, f := range invalid.Fields {
+		got[f.Field] = f.Code
+	}
+	want := map[string]string{
+		"name":   validate.CodeRequired,
+		"handle": validate.CodeFormat,
+		"sku":    validate.CodeTooLong,
+		"status": validate.CodeOneOf,
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("fields = %v, want %v", got, want)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	ctx := context.Background()
+	q := newFakeQueries()
+	svc := New(q, &fakeGIDs{})
+	storeID := uuid.New()
+	product, err := svc.Create(ctx, CreateInput{StoreID: storeID, Name: "Shirt", Handle: "shirt", Tags: ptr("summer")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		in      UpdateInput
+		wantErr error
+		check   func(t *testing.T, p database.Product)
+	}{
+		{
+			name: "changes only given fields",
+			in:   UpdateInput{StoreID: storeID, ID: product.ID, Name: ptr("Linen shirt"), InventoryTracked: ptr(true)},
+			check: func(t *testing.T, p database.Product) {
+				if p.Name != "Linen shirt" || !p.InventoryTracked {
+					t.Errorf("fields not updated: %+v", p)
+				}
+				if p.Handle != "shirt" || p.Tags.String != "summer" {
+					t.Errorf("untouched fields changed: %+v", p)
+				}
+			},
+		},
+		{
+			name:    "other store",
+			in:      UpdateInput{StoreID: uuid.New(), ID: product.ID, Name: ptr("x")},
+			wantErr: service.ErrNotFound,
+		},
+		{
+			name:    "empty name",
+			in:      UpdateInput{StoreID: storeID, ID: product.ID, Name: ptr("")},
+			wantErr: service.ErrInvalid,
+		},
+		{
+			name: "allowed status change",
+			in:   UpdateInput{StoreID: storeID, ID: product.ID, Status: ptr(StatusArchived)},
+			check: func(t *testing.T, p database.Product) {
+				if p.Status != StatusArchived {
+					t.Errorf("Status = %q, want archived", p.Status)
+				}
+			},
+		},
+		{
+			name: "oversell policy",
+			in:   UpdateInput{StoreID: storeID, ID: product.ID, OversellPolicy: ptr("backorder")},
+			check: func(t *testing.T, p database.Product) {
+				if p.OversellPolicy != "backorder" {
+					t.Errorf("OversellPolicy = %q, want backorder", p.OversellPolicy)
+				}
+			},
+		},
+		{
+			name:    "unknown oversell policy",
+			in:      UpdateInput{StoreID: storeID, ID: product.ID, OversellPolicy: ptr("continue")},
+			wantErr: service.ErrInvalid,
+		},
+		{
+			name:    "archived straight to active",
+			in:      UpdateInput{StoreID: storeID, ID: product.ID, Status: ptr(StatusActive)},
+			wantErr: service.ErrConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.Update(ctx, tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Update() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, got)
+			}
+		})
+	}
+}
+
+func TestUpdateVersion(t *testing.T) {
+	ctx := context.Background()
+	q := newFakeQueries()
+	svc := New(q, &fakeGIDs{})
+	storeID := uuid.New()
+	product, err := svc.Create(ctx, CreateInput{StoreID: storeID, Name: "Shirt", Handle: "shirt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := svc.Update(ctx, UpdateInput{StoreID: storeID, ID: product.ID, Name: ptr("Tee"), Version: ptr(product.Version)})
+	if err != nil {
+		t.Fatalf("Update() at the current version error = %v", err)
+	}
+	if updated.Version != product.Version+1 {
+		t.Errorf("Version = %d, want %d", updated.Version, product.Version+1)
+	}
+
+	// A second editor still holding the first version
+	_, err = svc.Update(ctx, UpdateInput{StoreID: storeID, ID: product.ID, Name: ptr("Shirt"), Version: ptr(product.Version)})
+	if code := errorCode(err); code != problem.CodeVersionConflict {
+		t.Errorf("Update() at a stale version error = %v, want a version conflict", err)
+	}
+
+	// Without a version, an update racing another keeps the fields that
+	// one wrote
+	q.beforeUpdate = func() {
+		p := q.products[product.ID]
+		p.Tags = sql.NullString{String: "summer", Valid: true}
+		p.Version++
+		q.products[product.ID] = p
+	}
+	updated, err = svc.Update(ctx, UpdateInput{StoreID: storeID, ID: product.ID, Name: ptr("Linen tee")})
+	if err != nil {
+		t.Fatalf("Update() racing another write error = %v", err)
+	}
+	if updated.Name != "Linen tee" || updated.Tags.String != "summer" {
+		t.Errorf("Update() racing another write = %+v, want both writes kept", updated)
+	}
+}
+
+func errorCode(err error) string {
+	var svcErr *service.Error
+	if !errors.As(err, &svcErr) {
+		return ""
+	}
+	return svcErr.Code
+}
+
+func TestSetStatus(t *testing.T) {
+	ctx := context.Background()
+	svc := New(newFakeQueries(), &fakeGIDs{})
+	storeID := uuid.New()
+	product, err := svc.Create(ctx, CreateInput{StoreID: storeID, Name: "Shirt", Handle: "shirt", Status: StatusDraft})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each step runs against the status the previous one left
+	steps := []struct {
+		to      string
+		wantErr error
+	}{
+		{to: StatusActive},
+		{to: StatusActive},
+		{to: StatusDraft},
+		{to: StatusArchived},
+		{to: StatusActive, wantErr: service.ErrConflict},
+		{to: StatusDraft},
+		{to: "live", wantErr: service.ErrConflict},
+	}
+
+	for i, step := range steps {
+		got, err := svc.SetStatus(ctx, storeID, product.ID, step.to)
+		if !errors.Is(err, step.wantErr) {
+			t.Fatalf("step %d: SetStatus(%q) error = %v, want %v", i, step.to, err, step.wantErr)
+		}
+		if err == nil && got.Status != step.to {
+			t.Errorf("step %d: Status = %q, want %q", i, got.Status, step.to)
+		}
+	}
+
+	if _, err := svc.SetStatus(ctx, uuid.New(), product.ID, StatusActive); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("SetStatus() in another store error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	ctx := context.Background()
+	svc := New(newFakeQueries(), &fakeGIDs{})
+	storeID := uuid.New()
+	product, _ := svc.Create(ctx, CreateInput{StoreID: storeID, Name: "Shirt", Handle: "shirt"})
+
+	if _, err := svc.Delete(ctx, uuid.New(), product.ID); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Delete() from another store error = %v, want ErrNotFound", err)
+	}
+	if _, err := svc.Delete(ctx, storeID, product.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := svc.Get(ctx, storeID, product.ID); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Get() after delete error = %v, want ErrNotFound", err)
+	}
+	if _, err := svc.Delete(ctx, storeID, product.ID); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Delete() twice error = %v, want ErrNotFound", err)
+	}
+
+	// A deleted product keeps its handle until it is purged
+	if _, err := svc.Create(ctx, CreateInput{StoreID: storeID, Name: "Shirt", Handle: "shirt"}); !errors.Is(err, service.ErrConflict) {
+		t.Errorf("Create() with a deleted product's handle error = %v, want ErrConflict", err)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	ctx := context.Background()
+	svc := New(newFakeQueries(), &fakeGIDs{})
+	storeID := uuid.New()
+	product, _ := svc.Create(ctx, CreateInput{StoreID: storeID, Name: "Shirt", Handle: "shirt"})
+
+	if _, err := svc.Restore(ctx, storeID, product.ID); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Restore() of a live product error = %v, want ErrNotFound", err)
+	}
+	if _, err := svc.Delete(ctx, storeID, product.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := svc.Restore(ctx, uuid.New(), product.ID); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Restore() from another store error = %v, want ErrNotFound", err)
+	}
+	restored, err := svc.Restore(ctx, storeID, product.ID)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if restored.DeletedAt.Valid {
+		t.Errorf("DeletedAt = %v, want unset", restored.DeletedAt.Time)
+	}
+	if _, err := svc.Get(ctx, storeID, product.ID); err != nil {
+		t.Errorf("Get() after restore error = %v", err)
+	}
+}
+
+func TestTranslations(t *testing.T) {
+	ctx := context.Background()
+	storeID := uuid.New()
+	q := newFakeQueries()
+	svc := New(q, &fakeGIDs{})
+	shirt, err := svc.Create(ctx, CreateInput{StoreID: storeID, Name: "Shirt", Handle: "shirt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hat, err := svc.Create(ctx, CreateInput{StoreID: storeID, Name: "Hat", Handle: "hat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.SetTranslation(ctx, TranslationInput{StoreID: storeID, ProductID: hat.ID, Locale: "fr", Name: "Chapeau", Handle: ptr("chapeau")}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		in         TranslationInput
+		wantLocale string
+		wantErr    error
+		wantField  string
+	}{
+		{name: "canonical locale", in: TranslationInput{Locale: "pt-br", Name: "Camisa", Description: ptr("De algodão")}, wantLocale: "pt-BR"},
+		{name: "replaces", in: TranslationInput{Locale: "pt-BR", Name: "Camiseta", Handle: ptr("camiseta")}, wantLocale: "pt-BR"},
+		{name: "handle used in another locale", in: TranslationInput{Locale: "es", Name: "Sombrero", Handle: ptr("chapeau")}, wantLocale: "es"},
+		{name: "handle taken in locale", in: TranslationInput{Locale: "fr", Name: "Chemise", Handle: ptr("chapeau")}, wantErr: service.ErrConflict},
+		{name: "bad locale", in: TranslationInput{Locale: "french", Name: "Chemise"}, wantErr: service.ErrInvalid, wantField: "locale"},
+		{name: "no name", in: TranslationInput{Locale: "fr"}, wantErr: service.ErrInvalid, wantField: "name"},
+		{name: "bad handle", in: TranslationInput{Locale: "fr", Name: "Chemise", Handle: ptr("Chemise Blanche")}, wantErr: service.ErrInvalid, wantField: "handle"},
+		{name: "another store", in: TranslationInput{StoreID: uuid.New(), Locale: "fr", Name: "Chemise"}, wantErr: service.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := tt.in
+			in.ProductID = shirt.ID
+			if in.StoreID == uuid.Nil {
+				in.StoreID = storeID
+			}
+			got, err := svc.SetTranslation(ctx, in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetTranslation() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantField != "" && !strings.Contains(err.Error(), tt.wantField+":") {
+				t.Errorf("SetTranslation() error = %v, want it to name %s", err, tt.wantField)
+			}
+			if err == nil && (got.Locale != tt.wantLocale || got.Name != in.Name) {
+				t.Errorf("SetTranslation() = %+v, want locale %s", got, tt.wantLocale)
+			}
+		})
+	}
+
+	list, err := svc.Translations(ctx, storeID, shirt.ID)
+	if err != nil || len(list) != 2 {
+		t.Fatalf("Translations() = %+v, %v, want pt-BR and es", list, err)
+	}
+	if _, err := svc.DeleteTranslation(ctx, storeID, shirt.ID, "PT-br"); err != nil {
+		t.Errorf("DeleteTranslation() error = %v", err)
+	}
+	if _, err := svc.DeleteTranslation(ctx, storeID, shirt.ID, "pt-BR"); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("DeleteTranslation() twice error = %v, want ErrNotFound", err)
+	}
+	if _, err := svc.Translations(ctx, uuid.New(), shirt.ID); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Translations() from another store error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	cutoff := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		// batches are what each delete of products and of variants removes
+		productBatches []int64
+		variantBatches []int64
+		productErr     error
+		wantProducts   int64
+		wantVariants   int64
+		wantErr        bool
+	}{
+		{name: "nothing deleted", productBatches: []int64{0}, variantBatches: []int64{0}},
+		{name: "partial batches", productBatches: []int64{1}, variantBatches: []int64{2}, wantProducts: 1, wantVariants: 2},
+		{name: "full batches repeat", productBatches: []int64{3, 3, 1}, variantBatches: []int64{3, 0}, wantProducts: 7, wantVariants: 3},
+		{name: "products fail", productBatches: []int64{0}, productErr: errors.New("boom"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var productCalls, variantCalls int
+			q := &dbmock.Querier{
+				PurgeDeletedProductsFunc: func(_ context.Context, arg database.PurgeDeletedProductsParams) (int64, error) {
+					if !arg.Before.Equal(cutoff) || arg.RowLimit != 3 {
+						t.Errorf("PurgeDeletedProducts(%+v)", arg)
+					}
+					productCalls++
+					return tt.productBatches[productCalls-1], tt.productErr
+				},
+				PurgeDeletedProductVariantsFunc: func(_ context.Context, arg database.PurgeDeletedProductVariantsParams) (int64, error) {
+					variantCalls++
+					return tt.variantBatches[variantCalls-1], nil
+				},
+			}
+			products, variants, err := NewPurger(q, PurgerConfig{BatchSize: 3}).Purge(context.Background(), cutoff)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Purge() error = %v, want error %v", err, tt.wantErr)
+			}
+			if products != tt.wantProducts || variants != tt.wantVariants {
+				t.Errorf("Purge() = %d, %d, want %d, %d", products, variants, tt.wantProducts, tt.wantVariants)
+			}
+			if productCalls != len(tt.productBatches) || variantCalls != len(tt.variantBatches) {
+				t.Errorf("deletes = %d, %d, want %d, %d", productCalls, variantCalls, len(tt.productBatches), len(tt.variantBatches))
+			}
+		})
+	}
+}
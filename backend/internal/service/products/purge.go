@@ -0,0 +1,102 @@
+package products
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+)
+
+// DefaultRetention is how long deleted products and variants can be
+// restored when DELETED_PRODUCT_RETENTION is unset
+const DefaultRetention = 30 * 24 * time.Hour
+
+// PurgerConfig configures a Purger
+type PurgerConfig struct {
+	Retention time.Duration
+	Interval  time.Duration
+	// BatchSize bounds each delete; purging a product cascades to its
+	// variants, media and inventory, so batches are kept small
+	BatchSize int
+	Logger    *slog.Logger
+}
+
+// Purger permanently removes products and variants that have been deleted
+// for longer than the retention window. Running several at once is
+// harmless.
+type Purger struct {
+	db  database.Querier
+	cfg PurgerConfig
+}
+
+// NewPurger creates a purger over db
+func NewPurger(db database.Querier, cfg PurgerConfig) *Purger {
+	if cfg.Retention <= 0 {
+		cfg.Retention = DefaultRetention
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 100
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &Purger{db: db, cfg: cfg}
+}
+
+// Run purges once an interval until ctx is cancelled
+func (p *Purger) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		products, variants, err := p.Purge(ctx, time.Now().Add(-p.cfg.Retention))
+		if err != nil && ctx.Err() == nil {
+			p.cfg.Logger.Error("deleted product purge failed", "error", err)
+		} else if products > 0 || variants > 0 {
+			p.cfg.Logger.Info("deleted products purged", "products", products, "variants", variants)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge removes every product, then every variant, deleted before cutoff,
+// a batch at a time, and returns how many of each were removed. Variants
+// purged with their product are not counted.
+func (p *Purger) Purge(ctx context.Context, cutoff time.Time) (products, variants int64, err error) {
+	products, err = p.batches(ctx, func() (int64, error) {
+		return p.db.PurgeDeletedProducts(ctx, database.PurgeDeletedProductsParams{
+			Before:   cutoff,
+			RowLimit: int32(p.cfg.BatchSize),
+		})
+	})
+	if err != nil {
+		return products, 0, err
+	}
+	variants, err = p.batches(ctx, func() (int64, error) {
+		return p.db.PurgeDeletedProductVariants(ctx, database.PurgeDeletedProductVariantsParams{
+			Before:   cutoff,
+			RowLimit: int32(p.cfg.BatchSize),
+		})
+	})
+	return products, variants, err
+}
+
+func (p *Purger) batches(ctx context.Context, purge func() (int64, error)) (int64, error) {
+	var total int64
+	for {
+		n, err := purge()
+		total += n
+		if err != nil || n < int64(p.cfg.BatchSize) || ctx.Err() != nil {
+			return total, err
+		}
+	}
+}
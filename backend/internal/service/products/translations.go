@@ -0,0 +1,79 @@
+package products
+
+import (
+	"context"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/locale"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/google/uuid"
+)
+
+// TranslationInput is a product's content in one locale. A nil Description
+// or Handle falls back to the product's own.
+type TranslationInput struct {
+	StoreID     uuid.UUID
+	ProductID   uuid.UUID
+	Locale      string
+	Name        string
+	Description *string
+	Handle      *string
+}
+
+// Translations lists a product's translations by locale
+func (s *Service) Translations(ctx context.Context, storeID, productID uuid.UUID) ([]database.ProductTranslation, error) {
+	if _, err := s.Get(ctx, storeID, productID); err != nil {
+		return nil, err
+	}
+	return s.q.ListProductTranslations(ctx, productID)
+}
+
+// SetTranslation creates or replaces a product's translation in a locale,
+// which is stored in canonical form
+func (s *Service) SetTranslation(ctx context.Context, in TranslationInput) (database.ProductTranslation, error) {
+	var v validate.Validator
+	canonical, err := locale.Parse(in.Locale)
+	v.Check(err == nil, "locale", validate.CodeFormat, "must be a BCP 47 locale such as fr or pt-BR")
+	if v.Required("name", in.Name) {
+		v.MaxLength("name", in.Name, MaxNameLength)
+	}
+	if in.Handle != nil {
+		validateHandle(&v, *in.Handle)
+	}
+	if err := v.Err(); err != nil {
+		return database.ProductTranslation{}, err
+	}
+
+	if _, err := s.Get(ctx, in.StoreID, in.ProductID); err != nil {
+		return database.ProductTranslation{}, err
+	}
+
+	translation, err := s.q.UpsertProductTranslation(ctx, database.UpsertProductTranslationParams{
+		ProductID:   in.ProductID,
+		StoreID:     in.StoreID,
+		Locale:      canonical,
+		Name:        in.Name,
+		Description: nullString(in.Description),
+		Handle:      nullString(in.Handle),
+	})
+	return translation, service.ConflictCodeAs(err, problem.CodeHandleTaken, "A product already has this handle in "+canonical)
+}
+
+// DeleteTranslation removes a product's translation in a locale and
+// returns it
+func (s *Service) DeleteTranslation(ctx context.Context, storeID, productID uuid.UUID, loc string) (database.ProductTranslation, error) {
+	canonical, err := locale.Parse(loc)
+	if err != nil {
+		return database.ProductTranslation{}, service.NotFound("Translation not found")
+	}
+	if _, err := s.Get(ctx, storeID, productID); err != nil {
+		return database.ProductTranslation{}, err
+	}
+	translation, err := s.q.DeleteProductTranslation(ctx, database.DeleteProductTranslationParams{
+		ProductID: productID,
+		Locale:    canonical,
+	})
+	return translation, service.NotFoundAs(err, "Translation not found")
+}
@@ -0,0 +1,269 @@
+// Package roles manages a tenant's roles and the permissions they grant
+package roles
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/google/uuid"
+)
+
+// Queries is the slice of the database the service uses
+type Queries interface {
+	CreateRole(ctx context.Context, arg database.CreateRoleParams) (database.Role, error)
+	GetRoleByTenantAndID(ctx context.Context, arg database.GetRoleByTenantAndIDParams) (database.Role, error)
+	GetRoleByTenantAndName(ctx context.Context, arg database.GetRoleByTenantAndNameParams) (database.Role, error)
+	GetRolesByTenantIDPaginated(ctx context.Context, arg database.GetRolesByTenantIDPaginatedParams) ([]database.GetRolesByTenantIDPaginatedRow, error)
+	GetPermissionByKey(ctx context.Context, key string) (database.Permission, error)
+	GetPermissionsByKeys(ctx context.Context, keys []string) ([]database.Permission, error)
+	GetPermissionsByRoleID(ctx context.Context, roleID uuid.UUID) ([]database.Permission, error)
+	GetPermissionKeysByRoleIDs(ctx context.Context, roleIDs []uuid.UUID) ([]database.GetPermissionKeysByRoleIDsRow, error)
+	AssignPermissionToRole(ctx context.Context, arg database.AssignPermissionToRoleParams) error
+	RemovePermissionFromRole(ctx context.Context, arg database.RemovePermissionFromRoleParams) error
+}
+
+type Service struct {
+	q    Queries
+	tx   service.Tx[Queries]
+	gids service.GIDGenerator
+}
+
+func New(q Queries, tx service.Tx[Queries], gids service.GIDGenerator) *Service {
+	return &Service{q: q, tx: tx, gids: gids}
+}
+
+// errSystemRole refuses changes to a role made from a template
+var errSystemRole = &service.Error{
+	Kind:    service.ErrConflict,
+	Code:    problem.CodeSystemRole,
+	Message: "System roles cannot be changed, clone the role to make one that can",
+}
+
+// Role is a role with the keys of the permissions it grants
+type Role struct {
+	database.Role
+	Permissions []string
+}
+
+// CreateInput describes a new role. Permissions are permission keys.
+type CreateInput struct {
+	TenantID    uuid.UUID
+	Name        string
+	Description string
+	Permissions []string
+}
+
+// Create adds a role to a tenant with its permissions, all or nothing
+func (s *Service) Create(ctx context.Context, in CreateInput) (Role, error) {
+	if strings.TrimSpace(in.Name) == "" {
+		return Role{}, service.Invalid("Role name is required")
+	}
+
+	var created Role
+	err := s.tx(ctx, func(q Queries) error {
+		_, err := q.GetRoleByTenantAndName(ctx, database.GetRoleByTenantAndNameParams{
+			TenantID: in.TenantID,
+			Name:     in.Name,
+		})
+		if err == nil {
+			return service.Conflict("A role with this name already exists")
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		permissions, err := lookupPermissions(ctx, q, in.Permissions)
+		if err != nil {
+			return err
+		}
+
+		role, err := q.CreateRole(ctx, database.CreateRoleParams{
+			Gid:         service.NewGID(s.gids),
+			TenantID:    in.TenantID,
+			Name:        in.Name,
+			Description: sql.NullString{String: in.Description, Valid: in.Description != ""},
+		})
+		if err != nil {
+			return err
+		}
+
+		created = Role{Role: role, Permissions: make([]string, 0, len(permissions))}
+		for _, perm := range permissions {
+			if err := q.AssignPermissionToRole(ctx, database.AssignPermissionToRoleParams{
+				RoleID:       role.ID,
+				PermissionID: perm.ID,
+			}); err != nil {
+				return err
+			}
+			created.Permissions = append(created.Permissions, perm.Key)
+		}
+		return nil
+	})
+	return created, err
+}
+
+// lookupPermissions resolves permission keys, rejecting any that don't exist
+func lookupPermissions(ctx context.Context, q Queries, keys []string) ([]database.Permission, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	permissions, err := q.GetPermissionsByKeys(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		if !slices.ContainsFunc(permissions, func(p database.Permission) bool { return p.Key == key }) {
+			return nil, service.Invalid("Unknown permission: " + key)
+		}
+	}
+	return permissions, nil
+}
+
+// List pages through a tenant's roles, newest first
+func (s *Service) List(ctx context.Context, tenantID uuid.UUID, page service.PageRequest) (service.Page[Role], error) {
+	hasCursor, createdAt, id, limit := page.QueryArgs()
+	rows, err := s.q.GetRolesByTenantIDPaginated(ctx, database.GetRolesByTenantIDPaginatedParams{
+		TenantID:        tenantID,
+		HasCursor:       hasCursor,
+		CursorCreatedAt: createdAt,
+		CursorID:        id,
+		RowLimit:        limit,
+	})
+	if err != nil {
+		return service.Page[Role]{}, err
+	}
+
+	rowPage := service.NewPage(rows, page.Limit)
+	ids := make([]uuid.UUID, 0, len(rowPage.Items))
+	for _, row := range rowPage.Items {
+		ids = append(ids, row.ID)
+	}
+	// One query for the whole page rather than one per role
+	granted, err := s.q.GetPermissionKeysByRoleIDs(ctx, ids)
+	if err != nil {
+		return service.Page[Role]{}, err
+	}
+	keysByRole := make(map[uuid.UUID][]string, len(granted))
+	for _, g := range granted {
+		keysByRole[g.RoleID] = g.Keys
+	}
+
+	result := service.Page[Role]{Items: make([]Role, 0, len(rowPage.Items)), HasMore: rowPage.HasMore}
+	for _, row := range rowPage.Items {
+		keys := keysByRole[row.ID]
+		if keys == nil {
+			keys = []string{}
+		}
+		result.Items = append(result.Items, Role{
+			Role: database.Role{
+				ID:          row.ID,
+				Gid:         row.Gid,
+				TenantID:    row.TenantID,
+				Name:        row.Name,
+				Description: row.Description,
+				IsSystem:    row.IsSystem,
+				CreatedAt:   row.CreatedAt,
+				UpdatedAt:   row.UpdatedAt,
+			},
+			Permissions: keys,
+		})
+	}
+	return result, nil
+}
+
+// CloneInput names the copy of a role
+type CloneInput struct {
+	TenantID    uuid.UUID
+	RoleID      uuid.UUID
+	Name        string
+	Description string
+}
+
+// Clone copies one of the tenant's roles, system or not, into a new role
+// with the same permissions that can then be changed
+func (s *Service) Clone(ctx context.Context, in CloneInput) (Role, error) {
+	source, err := s.q.GetRoleByTenantAndID(ctx, database.GetRoleByTenantAndIDParams{
+		TenantID: in.TenantID,
+		ID:       in.RoleID,
+	})
+	if err != nil {
+		return Role{}, service.NotFoundAs(err, "Role not found in this tenant")
+	}
+	permissions, err := s.q.GetPermissionsByRoleID(ctx, source.ID)
+	if err != nil {
+		return Role{}, err
+	}
+
+	description := in.Description
+	if description == "" {
+		description = source.Description.String
+	}
+	keys := make([]string, 0, len(permissions))
+	for _, perm := range permissions {
+		keys = append(keys, perm.Key)
+	}
+	return s.Create(ctx, CreateInput{
+		TenantID:    in.TenantID,
+		Name:        in.Name,
+		Description: description,
+		Permissions: keys,
+	})
+}
+
+// AddPermission grants a permission through one of the tenant's roles
+func (s *Service) AddPermission(ctx context.Context, tenantID, roleID uuid.UUID, key string) (database.Role, database.Permission, error) {
+	if key == "" {
+		return database.Role{}, database.Permission{}, service.Invalid("Permission key is required")
+	}
+	role, permission, err := s.rolePermission(ctx, tenantID, roleID, key)
+	if err != nil {
+		return database.Role{}, database.Permission{}, err
+	}
+	if role.IsSystem {
+		return database.Role{}, database.Permission{}, errSystemRole
+	}
+	err = s.q.AssignPermissionToRole(ctx, database.AssignPermissionToRoleParams{
+		RoleID:       role.ID,
+		PermissionID: permission.ID,
+	})
+	return role, permission, err
+}
+
+// RemovePermission stops one of the tenant's roles granting a permission
+func (s *Service) RemovePermission(ctx context.Context, tenantID, roleID uuid.UUID, key string) (database.Role, database.Permission, error) {
+	role, permission, err := s.rolePermission(ctx, tenantID, roleID, key)
+	if err != nil {
+		return database.Role{}, database.Permission{}, err
+	}
+	if role.IsSystem {
+		return database.Role{}, database.Permission{}, errSystemRole
+	}
+	err = s.q.RemovePermissionFromRole(ctx, database.RemovePermissionFromRoleParams{
+		RoleID:       role.ID,
+		PermissionID: permission.ID,
+	})
+	return role, permission, err
+}
+
+// rolePermission loads a role, checking it belongs to the tenant, and a
+// permission by key
+func (s *Service) rolePermission(ctx context.Context, tenantID, roleID uuid.UUID, key string) (database.Role, database.Permission, error) {
+	role, err := s.q.GetRoleByTenantAndID(ctx, database.GetRoleByTenantAndIDParams{
+		TenantID: tenantID,
+		ID:       roleID,
+	})
+	if err != nil {
+		return database.Role{}, database.Permission{}, service.NotFoundAs(err, "Role not found in this tenant")
+	}
+	permission, err := s.q.GetPermissionByKey(ctx, key)
+	if err != nil {
+		return database.Role{}, database.Permission{}, service.NotFoundAs(err, "Permission not found")
+	}
+	return role, permission, nil
+}
@@ -0,0 +1,263 @@
+package roles
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/google/uuid"
+)
+
+type fakeGIDs struct{}
+
+func (fakeGIDs) Generate() uint64 { return 7 }
+
+type fakeQueries struct {
+	roles       map[uuid.UUID]database.Role
+	permissions []database.Permission
+	grants      map[uuid.UUID][]uuid.UUID
+}
+
+func newFakeQueries(keys ...string) *fakeQueries {
+	f := &fakeQueries{roles: map[uuid.UUID]database.Role{}, grants: map[uuid.UUID][]uuid.UUID{}}
+	for _, key := range keys {
+		f.permissions = append(f.permissions, database.Permission{ID: uuid.New(), Key: key})
+	}
+	return f
+}
+
+func (f *fakeQueries) CreateRole(_ context.Context, arg database.CreateRoleParams) (database.Role, error) {
+	role := database.Role{ID: uuid.New(), Gid: arg.Gid, TenantID: arg.TenantID, Name: arg.Name, Description: arg.Description}
+	f.roles[role.ID] = role
+	return role, nil
+}
+
+func (f *fakeQueries) GetRoleByTenantAndID(_ context.Context, arg database.GetRoleByTenantAndIDParams) (database.Role, error) {
+	role, ok := f.roles[arg.ID]
+	if !ok || role.TenantID != arg.TenantID {
+		return database.Role{}, sql.ErrNoRows
+	}
+	return role, nil
+}
+
+func (f *fakeQueries) GetRoleByTenantAndName(_ context.Context, arg database.GetRoleByTenantAndNameParams) (database.Role, error) {
+	for _, role := range f.roles {
+		if role.TenantID == arg.TenantID && role.Name == arg.Name {
+			return role, nil
+		}
+	}
+	return database.Role{}, sql.ErrNoRows
+}
+
+func (f *fakeQueries) GetRolesByTenantIDPaginated(_ context.Context, arg database.GetRolesByTenantIDPaginatedParams) ([]database.GetRolesByTenantIDPaginatedRow, error) {
+	var rows []database.GetRolesByTenantIDPaginatedRow
+	for _, role := range f.roles {
+		if role.TenantID == arg.TenantID {
+			rows = append(rows, database.GetRolesByTenantIDPaginatedRow{ID: role.ID, TenantID: role.TenantID, Name: role.Name})
+		}
+	}
+	return rows, nil
+}
+
+func (f *fakeQueries) GetPermissionByKey(_ context.Context, key string) (database.Permission, error) {
+	for _, perm := range f.permissions {
+		if perm.Key == key {
+			return perm, nil
+		}
+	}
+	return database.Permission{}, sql.ErrNoRows
+}
+
+func (f *fakeQueries) GetPermissionsByKeys(_ context.Context, keys []string) ([]database.Permission, error) {
+	var found []database.Permission
+	for _, perm := range f.permissions {
+		if slices.Contains(keys, perm.Key) {
+			found = append(found, perm)
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeQueries) GetPermissionsByRoleID(_ context.Context, roleID uuid.UUID) ([]database.Permission, error) {
+	var found []database.Permission
+	for _, perm := range f.permissions {
+		if slices.Contains(f.grants[roleID], perm.ID) {
+			found = append(found, perm)
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeQueries) GetPermissionKeysByRoleIDs(ctx context.Context, roleIDs []uuid.UUID) ([]database.GetPermissionKeysByRoleIDsRow, error) {
+	var rows []database.GetPermissionKeysByRoleIDsRow
+	for _, id := range roleIDs {
+		permissions, _ := f.GetPermissionsByRoleID(ctx, id)
+		if len(permissions) == 0 {
+			continue
+		}
+		row := database.GetPermissionKeysByRoleIDsRow{RoleID: id}
+		for _, perm := range permissions {
+			row.Keys = append(row.Keys, perm.Key)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (f *fakeQueries) AssignPermissionToRole(_ context.Context, arg database.AssignPermissionToRoleParams) error {
+	f.grants[arg.RoleID] = append(f.grants[arg.RoleID], arg.PermissionID)
+	return nil
+}
+
+func (f *fakeQueries) RemovePermissionFromRole(_ context.Context, arg database.RemovePermissionFromRoleParams) error {
+	f.grants[arg.RoleID] = slices.DeleteFunc(f.grants[arg.RoleID], func(id uuid.UUID) bool { return id == arg.PermissionID })
+	return nil
+}
+
+func newService(q *fakeQueries) *Service {
+	tx := func(ctx context.Context, fn func(q Queries) error) error { return fn(q) }
+	return New(q, tx, fakeGIDs{})
+}
+
+func TestCreate(t *testing.T) {
+	tenantID := uuid.New()
+
+	tests := []struct {
+		name    string
+		in      CreateInput
+		wantErr error
+	}{
+		{name: "with permissions", in: CreateInput{TenantID: tenantID, Name: "Editor", Permissions: []string{"products:read", "products:write"}}},
+		{name: "no permissions", in: CreateInput{TenantID: tenantID, Name: "Viewer"}},
+		{name: "missing name", in: CreateInput{TenantID: tenantID}, wantErr: service.ErrInvalid},
+		{name: "duplicate name", in: CreateInput{TenantID: tenantID, Name: "Existing"}, wantErr: service.ErrConflict},
+		{name: "unknown permission", in: CreateInput{TenantID: tenantID, Name: "Editor", Permissions: []string{"nope"}}, wantErr: service.ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newFakeQueries("products:read", "products:write")
+			q.roles[uuid.New()] = database.Role{TenantID: tenantID, Name: "Existing"}
+			svc := newService(q)
+
+			role, err := svc.Create(context.Background(), tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Create() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if len(q.roles) != 1 {
+					t.Error("Create() wrote a role for rejected input")
+				}
+				return
+			}
+			if !slices.Equal(role.Permissions, tt.in.Permissions) && len(tt.in.Permissions) > 0 {
+				t.Errorf("Permissions = %v, want %v", role.Permissions, tt.in.Permissions)
+			}
+			if len(q.grants[role.ID]) != len(tt.in.Permissions) {
+				t.Errorf("granted %d permissions, want %d", len(q.grants[role.ID]), len(tt.in.Permissions))
+			}
+		})
+	}
+}
+
+func TestPermissionChanges(t *testing.T) {
+	ctx := context.Background()
+	q := newFakeQueries("products:read")
+	svc := newService(q)
+	tenantID := uuid.New()
+	role, err := svc.Create(ctx, CreateInput{TenantID: tenantID, Name: "Viewer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := svc.AddPermission(ctx, uuid.New(), role.ID, "products:read"); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("AddPermission() on another tenant's role error = %v, want ErrNotFound", err)
+	}
+	if _, _, err := svc.AddPermission(ctx, tenantID, role.ID, "nope"); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("AddPermission() with unknown key error = %v, want ErrNotFound", err)
+	}
+	if _, _, err := svc.AddPermission(ctx, tenantID, role.ID, "products:read"); err != nil {
+		t.Fatalf("AddPermission() error = %v", err)
+	}
+
+	page, err := svc.List(ctx, tenantID, service.PageRequest{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 1 || !slices.Equal(page.Items[0].Permissions, []string{"products:read"}) {
+		t.Errorf("List() = %+v", page.Items)
+	}
+
+	if _, _, err := svc.RemovePermission(ctx, uuid.New(), role.ID, "products:read"); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("RemovePermission() on another tenant's role error = %v, want ErrNotFound", err)
+	}
+	if _, _, err := svc.RemovePermission(ctx, tenantID, role.ID, "products:read"); err != nil {
+		t.Fatalf("RemovePermission() error = %v", err)
+	}
+	if len(q.grants[role.ID]) != 0 {
+		t.Errorf("grants after remove = %v", q.grants[role.ID])
+	}
+
+	page, err = svc.List(ctx, tenantID, service.PageRequest{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 1 || page.Items[0].Permissions == nil || len(page.Items[0].Permissions) != 0 {
+		t.Errorf("List() after remove = %+v, want no permissions", page.Items)
+	}
+}
+
+func TestSystemRoles(t *testing.T) {
+	ctx := context.Background()
+	q := newFakeQueries("products:view", "products:edit")
+	svc := newService(q)
+	tenantID := uuid.New()
+	owner := database.Role{ID: uuid.New(), TenantID: tenantID, Name: "Owner", IsSystem: true}
+	q.roles[owner.ID] = owner
+	q.grants[owner.ID] = []uuid.UUID{q.permissions[0].ID, q.permissions[1].ID}
+
+	if _, _, err := svc.AddPermission(ctx, tenantID, owner.ID, "products:view"); !errors.Is(err, service.ErrConflict) {
+		t.Errorf("AddPermission() on a system role error = %v, want ErrConflict", err)
+	}
+	if _, _, err := svc.RemovePermission(ctx, tenantID, owner.ID, "products:edit"); !errors.Is(err, service.ErrConflict) {
+		t.Errorf("RemovePermission() on a system role error = %v, want ErrConflict", err)
+	}
+	if len(q.grants[owner.ID]) != 2 {
+		t.Errorf("system role grants changed to %v", q.grants[owner.ID])
+	}
+
+	tests := []struct {
+		name    string
+		in      CloneInput
+		wantErr error
+	}{
+		{name: "clones", in: CloneInput{TenantID: tenantID, RoleID: owner.ID, Name: "Co-owner"}},
+		{name: "name taken", in: CloneInput{TenantID: tenantID, RoleID: owner.ID, Name: "Owner"}, wantErr: service.ErrConflict},
+		{name: "other tenant", in: CloneInput{TenantID: uuid.New(), RoleID: owner.ID, Name: "Mine"}, wantErr: service.ErrNotFound},
+		{name: "missing name", in: CloneInput{TenantID: tenantID, RoleID: owner.ID}, wantErr: service.ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clone, err := svc.Clone(ctx, tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Clone() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if clone.IsSystem {
+				t.Error("Clone() made a system role")
+			}
+			if len(clone.Permissions) != 2 {
+				t.Errorf("Clone() permissions = %v, want both of the source's", clone.Permissions)
+			}
+			if _, _, err := svc.RemovePermission(ctx, tenantID, clone.ID, "products:edit"); err != nil {
+				t.Errorf("RemovePermission() on the clone error = %v", err)
+			}
+		})
+	}
+}
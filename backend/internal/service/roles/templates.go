@@ -0,0 +1,61 @@
+package roles
+
+import (
+	"slices"
+	"strings"
+)
+
+// Template is a built-in role every tenant is given when it is created.
+// The roles made from templates are system roles: they can be cloned but
+// not changed.
+type Template struct {
+	Name        string
+	Description string
+	// Grants picks the template's permissions out of all that exist when
+	// the tenant is created
+	Grants func(key string) bool
+}
+
+var (
+	OwnerTemplate = Template{
+		Name:        "Owner",
+		Description: "Full access to all tenant resources, stores, and settings",
+		Grants:      func(string) bool { return true },
+	}
+	AdminTemplate = Template{
+		Name:        "Admin",
+		Description: "Manages the tenant, its members, and every store, short of ownership",
+		Grants:      func(key string) bool { return key != "tenant:owner" },
+	}
+	StaffTemplate = Template{
+		Name:        "Staff",
+		Description: "Runs stores day to day: products, inventory, orders, and customers",
+		Grants: func(key string) bool {
+			return slices.Contains(staffPermissions, key) || isView(key)
+		},
+	}
+	ViewerTemplate = Template{
+		Name:        "Viewer",
+		Description: "Read-only access to the tenant and its stores",
+		Grants:      isView,
+	}
+)
+
+// Templates are the roles every new tenant starts with, most access first
+var Templates = []Template{OwnerTemplate, AdminTemplate, StaffTemplate, ViewerTemplate}
+
+// staffPermissions are what Staff may change, on top of what they may view
+var staffPermissions = []string{
+	"products:create", "products:edit",
+	"inventory:manage",
+	"orders:manage",
+	"customers:manage",
+	"gift_cards:manage",
+	"reviews:moderate",
+}
+
+// isView reports whether key only grants a look. The audit log is left to
+// those who manage the tenant.
+func isView(key string) bool {
+	return strings.HasSuffix(key, ":view") && key != "audit_log:view"
+}
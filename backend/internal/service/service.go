@@ -0,0 +1,213 @@
+// Package service holds what the domain services beneath it share: the
+// errors they return, how they run work in a transaction, and keyset
+// pagination. Services take typed inputs, enforce business rules and talk
+// to the database; request decoding, status codes and logging stay with
+// their callers, whether that's an HTTP handler, a worker or the CLI.
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/dbpool"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Error kinds. Match them with errors.Is; the Error's message is written
+// for the end user. ErrInvalid is shared with package validate, so
+// field-level validation failures match it too.
+var (
+	ErrNotFound  = errors.New("not found")
+	ErrConflict  = errors.New("conflict")
+	ErrForbidden = errors.New("forbidden")
+	ErrInvalid   = validate.ErrInvalid
+)
+
+// Error is a failure a caller can act on, such as a missing record or a
+// rejected input
+type Error struct {
+	Kind    error
+	Message string
+	// Code, if set, is the problem code reported to API clients in place
+	// of the generic one for Kind
+	Code string
+	// Suggestion, if set, is a value the caller could retry with, such as
+	// a free handle
+	Suggestion string
+}
+
+func (e *Error) Error() string { return e.Message }
+
+func (e *Error) Unwrap() error { return e.Kind }
+
+// NotFound reports that what was asked for doesn't exist, or isn't visible
+// to the caller
+func NotFound(message string) error {
+	return &Error{Kind: ErrNotFound, Message: message}
+}
+
+// Conflict reports a clash with existing data, such as a duplicate name
+func Conflict(message string) error {
+	return &Error{Kind: ErrConflict, Message: message}
+}
+
+// Forbidden reports that the caller may not do this, whatever permissions
+// let them reach it
+func Forbidden(message string) error {
+	return &Error{Kind: ErrForbidden, Message: message, Code: problem.CodePermissionDenied}
+}
+
+// UpgradeRequired reports that the caller's plan doesn't allow what was
+// asked, such as one more store than its limit
+func UpgradeRequired(message string) error {
+	return &Error{Kind: ErrForbidden, Message: message, Code: problem.CodeUpgradeRequired}
+}
+
+// VersionConflict reports that a resource changed since it was read,
+// either by the caller or while the update was being made
+func VersionConflict(message string) error {
+	return &Error{Kind: ErrConflict, Message: message, Code: problem.CodeVersionConflict}
+}
+
+// Invalid reports an input that breaks a rule
+func Invalid(message string) error {
+	return &Error{Kind: ErrInvalid, Message: message}
+}
+
+// NotFoundAs converts sql.ErrNoRows into a NotFound error with message,
+// passing anything else through
+func NotFoundAs(err error, message string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return NotFound(message)
+	}
+	return err
+}
+
+// ConflictAs converts a unique constraint violation into a Conflict error
+// with message, passing anything else through
+func ConflictAs(err error, message string) error {
+	return ConflictCodeAs(err, "", message)
+}
+
+// ConflictCodeAs is ConflictAs for a conflict with its own problem code,
+// such as problem.CodeHandleTaken
+func ConflictCodeAs(err error, code, message string) error {
+	if UniqueViolation(err, "") {
+		return &Error{Kind: ErrConflict, Message: message, Code: code}
+	}
+	return err
+}
+
+// UniqueViolation reports whether err is a violation of the named unique
+// constraint, or of any unique constraint when constraint is empty
+func UniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" &&
+		(constraint == "" || pgErr.ConstraintName == constraint)
+}
+
+// HandleTaken reports that a handle is already in use, suggesting a free
+// one unless suggestion is empty
+func HandleTaken(message, suggestion string) error {
+	return &Error{Kind: ErrConflict, Message: message, Code: problem.CodeHandleTaken, Suggestion: suggestion}
+}
+
+// GIDGenerator hands out the snowflake IDs stored in gid columns;
+// *gid.Generator is the real one
+type GIDGenerator interface {
+	Generate() uint64
+}
+
+// NewGID returns the next GID from g as the nullable column value
+func NewGID(g GIDGenerator) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(g.Generate()), Valid: true}
+}
+
+// Tx runs fn with queries bound to a single transaction, committing only if
+// fn returns nil
+type Tx[Q any] func(ctx context.Context, fn func(q Q) error) error
+
+// SQLTx is the Tx for a real database. Q is a service's query interface,
+// which *database.Queries satisfies. In a context from dbpool.WithTenant
+// the transaction runs on the tenant's connection.
+func SQLTx[Q any](pool *pgxpool.Pool, queries *database.Queries) Tx[Q] {
+	return sqlTx[Q](pool, queries, uuid.Nil)
+}
+
+// TenantTx is SQLTx scoped to one tenant: row-level security hides the
+// rows of every other tenant from the transaction and rejects writes to
+// them, so a query missing its tenant filter can't reach across tenants
+func TenantTx[Q any](pool *pgxpool.Pool, queries *database.Queries, tenantID uuid.UUID) Tx[Q] {
+	return sqlTx[Q](pool, queries, tenantID)
+}
+
+func sqlTx[Q any](pool *pgxpool.Pool, queries *database.Queries, tenantID uuid.UUID) Tx[Q] {
+	return func(ctx context.Context, fn func(q Q) error) error {
+		tx, err := dbpool.BeginTx(ctx, pool, pgx.TxOptions{})
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
+
+		qtx := queries.WithTx(tx)
+		if tenantID != uuid.Nil {
+			if err := qtx.ScopeToTenant(ctx, tenantID); err != nil {
+				return fmt.Errorf("scope transaction to tenant: %w", err)
+			}
+		}
+		q, ok := any(qtx).(Q)
+		if !ok {
+			return fmt.Errorf("service: %T does not implement the service's queries", queries)
+		}
+		if err := fn(q); err != nil {
+			return err
+		}
+		return tx.Commit(ctx)
+	}
+}
+
+// Cursor is a keyset position in a list ordered newest first
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// PageRequest asks for up to Limit items after an optional cursor
+type PageRequest struct {
+	After *Cursor
+	Limit int
+}
+
+// Page is one page of a list
+type Page[T any] struct {
+	Items   []T
+	HasMore bool
+}
+
+// QueryArgs returns what the paginated queries take for p: whether there's
+// a cursor, its position, and a limit one past p.Limit so the extra row
+// shows whether more follow
+func (p PageRequest) QueryArgs() (hasCursor bool, createdAt time.Time, id uuid.UUID, limit int32) {
+	if p.After == nil {
+		return false, time.Time{}, uuid.Nil, int32(p.Limit + 1)
+	}
+	return true, p.After.CreatedAt, p.After.ID, int32(p.Limit + 1)
+}
+
+// NewPage trims the extra row QueryArgs asked for and reports whether it
+// was there
+func NewPage[T any](rows []T, limit int) Page[T] {
+	if len(rows) > limit {
+		return Page[T]{Items: rows[:limit], HasMore: true}
+	}
+	return Page[T]{Items: rows}
+}
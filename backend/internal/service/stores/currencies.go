@@ -0,0 +1,89 @@
+package stores
+
+import (
+	"context"
+	"slices"
+	"strconv"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/fx"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/google/uuid"
+)
+
+// MaxCurrencies bounds how many presentment currencies a store can enable
+const MaxCurrencies = 20
+
+// Currencies are what a store prices in: Default, which base prices are
+// in, and the presentment currencies shoppers can also pick
+type Currencies struct {
+	Default string   `json:"default"`
+	Enabled []string `json:"enabled"`
+}
+
+// Currencies returns a store's default and presentment currencies
+func (s *Service) Currencies(ctx context.Context, tenantID, storeID uuid.UUID) (Currencies, error) {
+	store, err := s.q.GetStoreByTenantAndID(ctx, database.GetStoreByTenantAndIDParams{
+		TenantID: uuid.NullUUID{UUID: tenantID, Valid: true},
+		ID:       storeID,
+	})
+	if err != nil {
+		return Currencies{}, service.NotFoundAs(err, "Store not found")
+	}
+	return s.currencies(ctx, store)
+}
+
+// SetCurrencies replaces a store's presentment currencies. The default
+// currency is always enabled, so listing it is allowed and changes nothing.
+// Variant price overrides in a currency that is dropped are kept for when
+// it comes back.
+func (s *Service) SetCurrencies(ctx context.Context, tenantID, storeID uuid.UUID, codes []string) (Currencies, error) {
+	store, err := s.q.GetStoreByTenantAndID(ctx, database.GetStoreByTenantAndIDParams{
+		TenantID: uuid.NullUUID{UUID: tenantID, Valid: true},
+		ID:       storeID,
+	})
+	if err != nil {
+		return Currencies{}, service.NotFoundAs(err, "Store not found")
+	}
+
+	var v validate.Validator
+	enabled := make([]string, 0, len(codes))
+	for i, code := range codes {
+		field := "currencies." + strconv.Itoa(i)
+		switch {
+		case !fx.ValidCode(code):
+			v.Fail(field, validate.CodeFormat, "must be a three letter ISO 4217 code such as EUR")
+		case slices.Contains(enabled, code):
+			v.Fail(field, validate.CodeInvalid, "is listed more than once")
+		case code != store.DefaultCurrency:
+			enabled = append(enabled, code)
+		}
+	}
+	v.Check(len(enabled) <= MaxCurrencies, "currencies", validate.CodeOutOfRange, "must list at most "+strconv.Itoa(MaxCurrencies)+" currencies")
+	if err := v.Err(); err != nil {
+		return Currencies{}, err
+	}
+
+	if err := s.q.ReplaceStoreCurrencies(ctx, database.ReplaceStoreCurrenciesParams{
+		StoreID:    store.ID,
+		Currencies: enabled,
+	}); err != nil {
+		return Currencies{}, err
+	}
+	return s.currencies(ctx, store)
+}
+
+// currencies reads the enabled currencies of store. One matching the
+// default, left from before the default changed, is not reported.
+func (s *Service) currencies(ctx context.Context, store database.Store) (Currencies, error) {
+	codes, err := s.q.ListStoreCurrencies(ctx, store.ID)
+	if err != nil {
+		return Currencies{}, err
+	}
+	enabled := slices.DeleteFunc(codes, func(code string) bool { return code == store.DefaultCurrency })
+	if enabled == nil {
+		enabled = []string{}
+	}
+	return Currencies{Default: store.DefaultCurrency, Enabled: enabled}, nil
+}
@@ -0,0 +1,84 @@
+package stores
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+)
+
+// DefaultRetention is how long a deleted store can be restored when
+// DELETED_STORE_RETENTION is unset
+const DefaultRetention = 30 * 24 * time.Hour
+
+// PurgerConfig configures a Purger
+type PurgerConfig struct {
+	Retention time.Duration
+	Interval  time.Duration
+	// BatchSize bounds each delete; purging a store cascades to all its
+	// products, orders and customers, so batches are kept very small
+	BatchSize int
+	Logger    *slog.Logger
+}
+
+// Purger permanently removes stores that have been deleted for longer than
+// the retention window. Running several at once is harmless.
+type Purger struct {
+	db  database.Querier
+	cfg PurgerConfig
+}
+
+// NewPurger creates a purger over db
+func NewPurger(db database.Querier, cfg PurgerConfig) *Purger {
+	if cfg.Retention <= 0 {
+		cfg.Retention = DefaultRetention
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 5
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &Purger{db: db, cfg: cfg}
+}
+
+// Run purges once an interval until ctx is cancelled
+func (p *Purger) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := p.Purge(ctx, time.Now().Add(-p.cfg.Retention))
+		if err != nil && ctx.Err() == nil {
+			p.cfg.Logger.Error("deleted store purge failed", "error", err)
+		} else if n > 0 {
+			p.cfg.Logger.Info("deleted stores purged", "stores", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge removes every store deleted before cutoff, a batch at a time, and
+// returns how many were removed
+func (p *Purger) Purge(ctx context.Context, cutoff time.Time) (int64, error) {
+	var total int64
+	for {
+		n, err := p.db.PurgeDeletedStores(ctx, database.PurgeDeletedStoresParams{
+			Before:   cutoff,
+			RowLimit: int32(p.cfg.BatchSize),
+		})
+		total += n
+		if err != nil || n < int64(p.cfg.BatchSize) || ctx.Err() != nil {
+			return total, err
+		}
+	}
+}
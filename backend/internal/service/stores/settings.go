@@ -0,0 +1,207 @@
+package stores
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"regexp"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/google/uuid"
+)
+
+// Settings is a store's settings document. A store keeps only what it has
+// changed; everything else reads as DefaultSettings.
+type Settings struct {
+	// WeightUnit is how weights are shown and entered. They are stored in
+	// grams whatever it is.
+	WeightUnit string `json:"weight_unit"`
+	// OrderNumberPrefix is shown before order numbers, as in #1001
+	OrderNumberPrefix string               `json:"order_number_prefix"`
+	Checkout          CheckoutSettings     `json:"checkout"`
+	Notifications     NotificationSettings `json:"notifications"`
+	Branding          BrandingSettings     `json:"branding"`
+}
+
+// CheckoutSettings are the options shoppers see at checkout
+type CheckoutSettings struct {
+	GuestCheckout bool   `json:"guest_checkout"`
+	RequirePhone  bool   `json:"require_phone"`
+	OrderNotes    bool   `json:"order_notes"`
+	TermsURL      string `json:"terms_url"`
+}
+
+// NotificationSettings pick the emails a store sends
+type NotificationSettings struct {
+	OrderConfirmation bool `json:"order_confirmation"`
+	PaymentReceipts   bool `json:"payment_receipts"`
+	ShippingUpdates   bool `json:"shipping_updates"`
+	RefundReceipts    bool `json:"refund_receipts"`
+	// StaffOrderAlerts emails StaffEmail about each new order
+	StaffOrderAlerts bool `json:"staff_order_alerts"`
+	// LowStockAlerts emails StaffEmail when variants go to or below their
+	// reorder point
+	LowStockAlerts bool   `json:"low_stock_alerts"`
+	StaffEmail     string `json:"staff_email"`
+}
+
+// BrandingSettings style the storefront and emails. Empty values leave it
+// to the theme.
+type BrandingSettings struct {
+	LogoURL      string `json:"logo_url"`
+	PrimaryColor string `json:"primary_color"`
+	AccentColor  string `json:"accent_color"`
+}
+
+// WeightUnits are the units a store can show weights in
+var WeightUnits = []string{"g", "kg", "oz", "lb"}
+
+// MaxOrderNumberPrefixLength bounds the order number prefix
+const MaxOrderNumberPrefixLength = 10
+
+var hexColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// DefaultSettings are what a store has until it changes them
+func DefaultSettings() Settings {
+	return Settings{
+		WeightUnit:        "kg",
+		OrderNumberPrefix: "#",
+		Checkout: CheckoutSettings{
+			GuestCheckout: true,
+			OrderNotes:    true,
+		},
+		Notifications: NotificationSettings{
+			OrderConfirmation: true,
+			PaymentReceipts:   true,
+			ShippingUpdates:   true,
+			RefundReceipts:    true,
+		},
+	}
+}
+
+// Settings returns a store's settings with defaults filled in
+func (s *Service) Settings(ctx context.Context, tenantID, storeID uuid.UUID) (Settings, error) {
+	stored, err := s.q.GetStoreSettings(ctx, database.GetStoreSettingsParams{
+		ID:       storeID,
+		TenantID: uuid.NullUUID{UUID: tenantID, Valid: true},
+	})
+	if err != nil {
+		return Settings{}, service.NotFoundAs(err, "Store not found")
+	}
+	return resolveSettings(stored)
+}
+
+// UpdateSettings applies a JSON merge patch (RFC 7396) to a store's
+// settings. Setting a field to null puts it back to its default.
+func (s *Service) UpdateSettings(ctx context.Context, tenantID, storeID uuid.UUID, patch json.RawMessage) (Settings, error) {
+	var changes map[string]any
+	if err := json.Unmarshal(patch, &changes); err != nil || changes == nil {
+		return Settings{}, service.Invalid("Settings must be patched with a JSON object")
+	}
+
+	tenant := uuid.NullUUID{UUID: tenantID, Valid: true}
+	stored, err := s.q.GetStoreSettings(ctx, database.GetStoreSettingsParams{ID: storeID, TenantID: tenant})
+	if err != nil {
+		return Settings{}, service.NotFoundAs(err, "Store not found")
+	}
+	var current map[string]any
+	if err := json.Unmarshal(stored, &current); err != nil {
+		return Settings{}, err
+	}
+
+	merged, err := json.Marshal(mergePatch(current, changes))
+	if err != nil {
+		return Settings{}, err
+	}
+	settings, err := resolveSettings(merged)
+	if err != nil {
+		return Settings{}, service.Invalid("Invalid settings: " + err.Error())
+	}
+	if err := settings.validate(); err != nil {
+		return Settings{}, err
+	}
+
+	_, err = s.q.UpdateStoreSettings(ctx, database.UpdateStoreSettingsParams{
+		ID:       storeID,
+		TenantID: tenant,
+		Settings: merged,
+		Previous: stored,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return Settings{}, service.VersionConflict("The settings were changed by another request, please retry")
+	}
+	return settings, err
+}
+
+// resolveSettings reads a stored document over the defaults. Unknown
+// fields and wrong types are errors.
+func resolveSettings(stored json.RawMessage) (Settings, error) {
+	settings := DefaultSettings()
+	dec := json.NewDecoder(bytes.NewReader(stored))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&settings); err != nil {
+		return Settings{}, err
+	}
+	return settings, nil
+}
+
+func (st Settings) validate() error {
+	var v validate.Validator
+	v.OneOf("weight_unit", st.WeightUnit, WeightUnits...)
+	v.MaxLength("order_number_prefix", st.OrderNumberPrefix, MaxOrderNumberPrefixLength)
+	if st.Checkout.TermsURL != "" {
+		v.Check(webURL(st.Checkout.TermsURL), "checkout.terms_url", validate.CodeFormat, "must be an http or https URL")
+	}
+	if st.Notifications.StaffOrderAlerts {
+		v.Check(st.Notifications.StaffEmail != "", "notifications.staff_email", validate.CodeRequired, "is required for staff order alerts")
+	}
+	if st.Notifications.LowStockAlerts {
+		v.Check(st.Notifications.StaffEmail != "", "notifications.staff_email", validate.CodeRequired, "is required for low stock alerts")
+	}
+	if st.Notifications.StaffEmail != "" {
+		v.Email("notifications.staff_email", st.Notifications.StaffEmail)
+	}
+	if st.Branding.LogoURL != "" {
+		v.Check(webURL(st.Branding.LogoURL), "branding.logo_url", validate.CodeFormat, "must be an http or https URL")
+	}
+	checkColor(&v, "branding.primary_color", st.Branding.PrimaryColor)
+	checkColor(&v, "branding.accent_color", st.Branding.AccentColor)
+	return v.Err()
+}
+
+func checkColor(v *validate.Validator, field, color string) {
+	if color != "" {
+		v.Check(hexColor.MatchString(color), field, validate.CodeFormat, "must be a hex color such as #1a2b3c")
+	}
+}
+
+// mergePatch applies patch to target as RFC 7396 describes: objects merge
+// key by key, null removes a key and anything else replaces it
+func mergePatch(target, patch any) any {
+	changes, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	doc, ok := target.(map[string]any)
+	if !ok {
+		doc = map[string]any{}
+	}
+	for key, value := range changes {
+		if value == nil {
+			delete(doc, key)
+			continue
+		}
+		doc[key] = mergePatch(doc[key], value)
+	}
+	return doc
+}
+
+func webURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,357 @@
+// Package stores manages the stores that belong to a tenant
+package stores
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+	// Timezones are checked against the embedded database so validation
+	// doesn't depend on what the host has installed
+	_ "time/tzdata"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/fx"
+	"github.com/dfodeker/terminus/internal/locale"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/slug"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/google/uuid"
+)
+
+// DefaultPlan is given to stores created without one
+const DefaultPlan = "free"
+
+// Store statuses. Only active stores are served to shoppers; paused ones
+// are expected back and closed ones are not.
+const (
+	StatusActive = "active"
+	StatusPaused = "paused"
+	StatusClosed = "closed"
+)
+
+// Statuses are the states a store can be in
+var Statuses = []string{StatusActive, StatusPaused, StatusClosed}
+
+// transitions lists where each status may move. A closed store reopens
+// paused, so it isn't served again before it has been checked over.
+var transitions = map[string][]string{
+	StatusActive: {StatusPaused, StatusClosed},
+	StatusPaused: {StatusActive, StatusClosed},
+	StatusClosed: {StatusPaused},
+}
+
+// MaxPlanLength bounds plan names
+const MaxPlanLength = 50
+
+// MaxHandleLength keeps a store handle usable as a DNS label, since it
+// names the store's subdomain
+const MaxHandleLength = 63
+
+// MaxNameLength bounds store names
+const MaxNameLength = 255
+
+// handleConstraint keeps store handles unique within a tenant
+const handleConstraint = "stores_tenant_handle_unique"
+
+const duplicateHandle = "A store with this handle already exists"
+
+// Queries is the slice of the database the service uses
+type Queries interface {
+	CreateStoreForTenant(ctx context.Context, arg database.CreateStoreForTenantParams) (database.Store, error)
+	GetStoresByTenantIDPaginated(ctx context.Context, arg database.GetStoresByTenantIDPaginatedParams) ([]database.GetStoresByTenantIDPaginatedRow, error)
+	GetStoresByUserIDPaginated(ctx context.Context, arg database.GetStoresByUserIDPaginatedParams) ([]database.GetStoresByUserIDPaginatedRow, error)
+	GetStoreByTenantAndID(ctx context.Context, arg database.GetStoreByTenantAndIDParams) (database.Store, error)
+	UpdateStoreForTenant(ctx context.Context, arg database.UpdateStoreForTenantParams) (database.Store, error)
+	UpdateStoreStatus(ctx context.Context, arg database.UpdateStoreStatusParams) (database.Store, error)
+	SoftDeleteStore(ctx context.Context, arg database.SoftDeleteStoreParams) (database.Store, error)
+	RestoreStore(ctx context.Context, arg database.RestoreStoreParams) (database.Store, error)
+	GetStoreSettings(ctx context.Context, arg database.GetStoreSettingsParams) (json.RawMessage, error)
+	UpdateStoreSettings(ctx context.Context, arg database.UpdateStoreSettingsParams) (json.RawMessage, error)
+	ListStoreHandlesWithPrefix(ctx context.Context, arg database.ListStoreHandlesWithPrefixParams) ([]string, error)
+	ListStoreCurrencies(ctx context.Context, storeID uuid.UUID) ([]string, error)
+	ReplaceStoreCurrencies(ctx context.Context, arg database.ReplaceStoreCurrenciesParams) error
+}
+
+type Service struct {
+	q    Queries
+	gids service.GIDGenerator
+}
+
+func New(q Queries, gids service.GIDGenerator) *Service {
+	return &Service{q: q, gids: gids}
+}
+
+// CreateInput describes a new store
+type CreateInput struct {
+	TenantID uuid.UUID
+	Name     string
+	// Handle is generated from Name when empty, with a numeric suffix if
+	// need be to keep it unique in the tenant
+	Handle string
+	Plan   string
+}
+
+// Create opens a store under a tenant
+func (s *Service) Create(ctx context.Context, in CreateInput) (database.Store, error) {
+	var v validate.Validator
+	nameOK := v.Required("name", in.Name) && v.MaxLength("name", in.Name, MaxNameLength)
+	handle, generated := in.Handle, in.Handle == ""
+	if !generated {
+		validateHandle(&v, handle)
+	} else if nameOK {
+		handle = slug.Make(in.Name, MaxHandleLength)
+		v.Check(handle != "", "handle", validate.CodeRequired, "is required when the name has no letters or digits")
+	}
+	if err := v.Err(); err != nil {
+		return database.Store{}, err
+	}
+
+	plan := in.Plan
+	if plan == "" {
+		plan = DefaultPlan
+	}
+
+	if generated {
+		var err error
+		if handle, err = s.freeHandle(ctx, in.TenantID, handle); err != nil {
+			return database.Store{}, err
+		}
+	}
+
+	store, err := s.q.CreateStoreForTenant(ctx, database.CreateStoreForTenantParams{
+		Gid:      service.NewGID(s.gids),
+		Name:     in.Name,
+		Handle:   handle,
+		Plan:     plan,
+		TenantID: uuid.NullUUID{UUID: in.TenantID, Valid: true},
+	})
+	if service.UniqueViolation(err, handleConstraint) {
+		// Best effort: the conflict stands without a suggestion
+		suggestion, _ := s.freeHandle(ctx, in.TenantID, handle)
+		return database.Store{}, service.HandleTaken(duplicateHandle, suggestion)
+	}
+	return store, err
+}
+
+// HandleAvailability says whether a handle is free in a tenant, and if
+// not, one that is
+type HandleAvailability struct {
+	Handle     string
+	Available  bool
+	Suggestion string
+}
+
+// CheckHandle reports whether handle is free in a tenant. With an empty
+// handle it checks the one Create would generate from name.
+func (s *Service) CheckHandle(ctx context.Context, tenantID uuid.UUID, handle, name string) (HandleAvailability, error) {
+	var v validate.Validator
+	if handle != "" {
+		validateHandle(&v, handle)
+	} else if v.Required("name", name) {
+		handle = slug.Make(name, MaxHandleLength)
+		v.Check(handle != "", "name", validate.CodeFormat, "has no letters or digits to make a handle from")
+	}
+	if err := v.Err(); err != nil {
+		return HandleAvailability{}, err
+	}
+
+	free, err := s.freeHandle(ctx, tenantID, handle)
+	if err != nil {
+		return HandleAvailability{}, err
+	}
+	if free == handle {
+		return HandleAvailability{Handle: handle, Available: true}, nil
+	}
+	return HandleAvailability{Handle: handle, Suggestion: free}, nil
+}
+
+// freeHandle returns handle if no store in the tenant has it, or else the
+// first free one with a numeric suffix
+func (s *Service) freeHandle(ctx context.Context, tenantID uuid.UUID, handle string) (string, error) {
+	rows, err := s.q.ListStoreHandlesWithPrefix(ctx, database.ListStoreHandlesWithPrefixParams{
+		TenantID: uuid.NullUUID{UUID: tenantID, Valid: true},
+		Handle:   handle,
+	})
+	if err != nil {
+		return "", err
+	}
+	return slug.Next(handle, MaxHandleLength, func(h string) bool { return slices.Contains(rows, h) }), nil
+}
+
+// validateHandle checks a handle given by the caller
+func validateHandle(v *validate.Validator, handle string) {
+	if v.Required("handle", handle) && v.MaxLength("handle", handle, MaxHandleLength) {
+		v.Handle("handle", handle)
+	}
+}
+
+// List pages through a tenant's stores, newest first
+func (s *Service) List(ctx context.Context, tenantID uuid.UUID, page service.PageRequest) (service.Page[database.GetStoresByTenantIDPaginatedRow], error) {
+	hasCursor, createdAt, id, limit := page.QueryArgs()
+	rows, err := s.q.GetStoresByTenantIDPaginated(ctx, database.GetStoresByTenantIDPaginatedParams{
+		TenantID:        uuid.NullUUID{UUID: tenantID, Valid: true},
+		HasCursor:       hasCursor,
+		CursorCreatedAt: createdAt,
+		CursorID:        id,
+		RowLimit:        limit,
+	})
+	if err != nil {
+		return service.Page[database.GetStoresByTenantIDPaginatedRow]{}, err
+	}
+	return service.NewPage(rows, page.Limit), nil
+}
+
+// ListForUser pages through the stores of every tenant the user is an
+// active member of and holds permission in through a role, newest first. A
+// valid tenantID narrows it to that tenant, which yields nothing if the
+// user isn't a member or lacks the permission there.
+func (s *Service) ListForUser(ctx context.Context, userID uuid.UUID, permission string, tenantID uuid.NullUUID, page service.PageRequest) (service.Page[database.GetStoresByUserIDPaginatedRow], error) {
+	hasCursor, createdAt, id, limit := page.QueryArgs()
+	rows, err := s.q.GetStoresByUserIDPaginated(ctx, database.GetStoresByUserIDPaginatedParams{
+		UserID:          userID,
+		TenantID:        tenantID,
+		Permission:      permission,
+		HasCursor:       hasCursor,
+		CursorCreatedAt: createdAt,
+		CursorID:        id,
+		RowLimit:        limit,
+	})
+	if err != nil {
+		return service.Page[database.GetStoresByUserIDPaginatedRow]{}, err
+	}
+	return service.NewPage(rows, page.Limit), nil
+}
+
+// UpdateInput changes a store's settings. Nil fields are left as they are.
+type UpdateInput struct {
+	TenantID        uuid.UUID
+	ID              uuid.UUID
+	Name            *string
+	Address         *string
+	DefaultCurrency *string
+	// DefaultLocale is the locale the store's own product content is in
+	DefaultLocale *string
+	Timezone      *string
+	Plan          *string
+}
+
+// Update changes the settings of a store that hasn't been deleted
+func (s *Service) Update(ctx context.Context, in UpdateInput) (database.Store, error) {
+	var v validate.Validator
+	if in.Name != nil && v.Required("name", *in.Name) {
+		v.MaxLength("name", *in.Name, MaxNameLength)
+	}
+	if in.DefaultCurrency != nil {
+		v.Check(fx.ValidCode(*in.DefaultCurrency), "default_currency", validate.CodeFormat, "must be a three letter ISO 4217 code such as USD")
+	}
+	if in.DefaultLocale != nil {
+		canonical, err := locale.Parse(*in.DefaultLocale)
+		if v.Check(err == nil, "default_locale", validate.CodeFormat, "must be a BCP 47 locale such as en or pt-BR") {
+			in.DefaultLocale = &canonical
+		}
+	}
+	if in.Timezone != nil {
+		v.Check(validTimezone(*in.Timezone), "timezone", validate.CodeFormat, "must be an IANA timezone such as Europe/Paris")
+	}
+	if in.Plan != nil && v.Required("plan", *in.Plan) {
+		v.MaxLength("plan", *in.Plan, MaxPlanLength)
+	}
+	if err := v.Err(); err != nil {
+		return database.Store{}, err
+	}
+
+	store, err := s.q.UpdateStoreForTenant(ctx, database.UpdateStoreForTenantParams{
+		ID:              in.ID,
+		TenantID:        uuid.NullUUID{UUID: in.TenantID, Valid: true},
+		Name:            nullString(in.Name),
+		Address:         nullString(in.Address),
+		DefaultCurrency: nullString(in.DefaultCurrency),
+		Timezone:        nullString(in.Timezone),
+		Plan:            nullString(in.Plan),
+		DefaultLocale:   nullString(in.DefaultLocale),
+	})
+	return store, service.NotFoundAs(err, "Store not found")
+}
+
+// SetStatus moves a store to status. Moving to the status a store already
+// has changes nothing.
+func (s *Service) SetStatus(ctx context.Context, tenantID, storeID uuid.UUID, status string) (database.Store, error) {
+	var v validate.Validator
+	if v.Required("status", status) {
+		v.OneOf("status", status, Statuses...)
+	}
+	if err := v.Err(); err != nil {
+		return database.Store{}, err
+	}
+
+	tenant := uuid.NullUUID{UUID: tenantID, Valid: true}
+	existing, err := s.q.GetStoreByTenantAndID(ctx, database.GetStoreByTenantAndIDParams{
+		TenantID: tenant,
+		ID:       storeID,
+	})
+	if err != nil {
+		return database.Store{}, service.NotFoundAs(err, "Store not found")
+	}
+	if existing.Status == status {
+		return existing, nil
+	}
+	if !slices.Contains(transitions[existing.Status], status) {
+		return database.Store{}, &service.Error{
+			Kind:    service.ErrConflict,
+			Code:    problem.CodeInvalidStatusTransition,
+			Message: fmt.Sprintf("A store cannot move from %s to %s", existing.Status, status),
+		}
+	}
+
+	store, err := s.q.UpdateStoreStatus(ctx, database.UpdateStoreStatusParams{
+		ID:         storeID,
+		TenantID:   tenant,
+		Status:     status,
+		FromStatus: existing.Status,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.Store{}, service.Conflict("The store was changed by another request, please retry")
+	}
+	return store, err
+}
+
+// Delete hides a store and everything in it. It can be restored until the
+// worker purges it.
+func (s *Service) Delete(ctx context.Context, tenantID, storeID uuid.UUID) (database.Store, error) {
+	store, err := s.q.SoftDeleteStore(ctx, database.SoftDeleteStoreParams{
+		ID:       storeID,
+		TenantID: uuid.NullUUID{UUID: tenantID, Valid: true},
+	})
+	return store, service.NotFoundAs(err, "Store not found")
+}
+
+// Restore brings back a deleted store as it was
+func (s *Service) Restore(ctx context.Context, tenantID, storeID uuid.UUID) (database.Store, error) {
+	store, err := s.q.RestoreStore(ctx, database.RestoreStoreParams{
+		ID:       storeID,
+		TenantID: uuid.NullUUID{UUID: tenantID, Valid: true},
+	})
+	return store, service.NotFoundAs(err, "Deleted store not found")
+}
+
+// validTimezone reports whether tz names an IANA timezone. Local is the
+// server's, so it isn't one.
+func validTimezone(tz string) bool {
+	if tz == "" || tz == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(tz)
+	return err == nil
+}
+
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
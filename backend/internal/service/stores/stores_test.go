@@ -0,0 +1,633 @@
+package stores
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type fakeGIDs struct{}
+
+func (fakeGIDs) Generate() uint64 { return 42 }
+
+type fakeQueries struct {
+	created  []database.CreateStoreForTenantParams
+	listArgs database.GetStoresByTenantIDPaginatedParams
+	rows     []database.GetStoresByTenantIDPaginatedRow
+	userArgs database.GetStoresByUserIDPaginatedParams
+	userRows []database.GetStoresByUserIDPaginatedRow
+	// granted are the permissions the user's roles grant in each tenant
+	granted map[uuid.NullUUID][]string
+	// taken are the handles of the tenant's existing stores
+	taken []string
+	// stores backs the lifecycle methods
+	stores map[uuid.UUID]database.Store
+	// settings are the stored settings documents by store
+	settings map[uuid.UUID]json.RawMessage
+	// currencies are the enabled presentment currencies by store
+	currencies map[uuid.UUID][]string
+}
+
+// live finds a store that hasn't been deleted, as the queries do
+func (f *fakeQueries) live(id uuid.UUID, tenantID uuid.NullUUID) (database.Store, bool) {
+	store, ok := f.stores[id]
+	return store, ok && store.TenantID == tenantID && !store.DeletedAt.Valid
+}
+
+func (f *fakeQueries) CreateStoreForTenant(_ context.Context, arg database.CreateStoreForTenantParams) (database.Store, error) {
+	if slices.Contains(f.taken, arg.Handle) {
+		return database.Store{}, &pgconn.PgError{Code: "23505", ConstraintName: handleConstraint}
+	}
+	f.created = append(f.created, arg)
+	return database.Store{ID: uuid.New(), Name: arg.Name, Handle: arg.Handle, Plan: arg.Plan, TenantID: arg.TenantID, Gid: arg.Gid}, nil
+}
+
+func (f *fakeQueries) GetStoresByTenantIDPaginated(_ context.Context, arg database.GetStoresByTenantIDPaginatedParams) ([]database.GetStoresByTenantIDPaginatedRow, error) {
+	f.listArgs = arg
+	return f.rows[:min(len(f.rows), int(arg.RowLimit))], nil
+}
+
+func (f *fakeQueries) GetStoresByUserIDPaginated(_ context.Context, arg database.GetStoresByUserIDPaginatedParams) ([]database.GetStoresByUserIDPaginatedRow, error) {
+	f.userArgs = arg
+	var rows []database.GetStoresByUserIDPaginatedRow
+	for _, row := range f.userRows {
+		if (!arg.TenantID.Valid || row.TenantID == arg.TenantID) && slices.Contains(f.granted[row.TenantID], arg.Permission) {
+			rows = append(rows, row)
+		}
+	}
+	return rows[:min(len(rows), int(arg.RowLimit))], nil
+}
+
+func (f *fakeQueries) GetStoreByTenantAndID(_ context.Context, arg database.GetStoreByTenantAndIDParams) (database.Store, error) {
+	store, ok := f.live(arg.ID, arg.TenantID)
+	if !ok {
+		return database.Store{}, sql.ErrNoRows
+	}
+	return store, nil
+}
+
+func (f *fakeQueries) UpdateStoreForTenant(_ context.Context, arg database.UpdateStoreForTenantParams) (database.Store, error) {
+	store, ok := f.live(arg.ID, arg.TenantID)
+	if !ok {
+		return database.Store{}, sql.ErrNoRows
+	}
+	coalesce := func(field *string, v sql.NullString) {
+		if v.Valid {
+			*field = v.String
+		}
+	}
+	coalesce(&store.Name, arg.Name)
+	coalesce(&store.Address, arg.Address)
+	coalesce(&store.DefaultCurrency, arg.DefaultCurrency)
+	coalesce(&store.Timezone, arg.Timezone)
+	coalesce(&store.Plan, arg.Plan)
+	coalesce(&store.DefaultLocale, arg.DefaultLocale)
+	f.stores[store.ID] = store
+	return store, nil
+}
+
+func (f *fakeQueries) UpdateStoreStatus(_ context.Context, arg database.UpdateStoreStatusParams) (database.Store, error) {
+	store, ok := f.live(arg.ID, arg.TenantID)
+	if !ok || store.Status != arg.FromStatus {
+		return database.Store{}, sql.ErrNoRows
+	}
+	store.Status = arg.Status
+	f.stores[store.ID] = store
+	return store, nil
+}
+
+func (f *fakeQueries) SoftDeleteStore(_ context.Context, arg database.SoftDeleteStoreParams) (database.Store, error) {
+	store, ok := f.live(arg.ID, arg.TenantID)
+	if !ok {
+		return database.Store{}, sql.ErrNoRows
+	}
+	store.DeletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	f.stores[store.ID] = store
+	return store, nil
+}
+
+func (f *fakeQueries) RestoreStore(_ context.Context, arg database.RestoreStoreParams) (database.Store, error) {
+	store, ok := f.stores[arg.ID]
+	if !ok || store.TenantID != arg.TenantID || !store.DeletedAt.Valid {
+		return database.Store{}, sql.ErrNoRows
+	}
+	store.DeletedAt = sql.NullTime{}
+	f.stores[store.ID] = store
+	return store, nil
+}
+
+func (f *fakeQueries) GetStoreSettings(_ context.Context, arg database.GetStoreSettingsParams) (json.RawMessage, error) {
+	if _, ok := f.live(arg.ID, arg.TenantID); !ok {
+		return nil, sql.ErrNoRows
+	}
+	if doc, ok := f.settings[arg.ID]; ok {
+		return doc, nil
+	}
+	return json.RawMessage(`{}`), nil
+}
+
+func (f *fakeQueries) UpdateStoreSettings(ctx context.Context, arg database.UpdateStoreSettingsParams) (json.RawMessage, error) {
+	current, err := f.GetStoreSettings(ctx, database.GetStoreSettingsParams{ID: arg.ID, TenantID: arg.TenantID})
+	if err != nil || !bytes.Equal(current, arg.Previous) {
+		return nil, sql.ErrNoRows
+	}
+	if f.settings == nil {
+		f.settings = map[uuid.UUID]json.RawMessage{}
+	}
+	f.settings[arg.ID] = arg.Settings
+	return arg.Settings, nil
+}
+
+func (f *fakeQueries) ListStoreCurrencies(_ context.Context, storeID uuid.UUID) ([]string, error) {
+	return slices.Sorted(slices.Values(f.currencies[storeID])), nil
+}
+
+func (f *fakeQueries) ReplaceStoreCurrencies(_ context.Context, arg database.ReplaceStoreCurrenciesParams) error {
+	if f.currencies == nil {
+		f.currencies = map[uuid.UUID][]string{}
+	}
+	f.currencies[arg.StoreID] = slices.Clone(arg.Currencies)
+	return nil
+}
+
+func (f *fakeQueries) ListStoreHandlesWithPrefix(_ context.Context, arg database.ListStoreHandlesWithPrefixParams) ([]string, error) {
+	var handles []string
+	for _, h := range f.taken {
+		if h == arg.Handle || strings.HasPrefix(h, arg.Handle+"-") {
+			handles = append(handles, h)
+		}
+	}
+	return handles, nil
+}
+
+func TestCreate(t *testing.T) {
+	tenantID := uuid.New()
+
+	tests := []struct {
+		name           string
+		in             CreateInput
+		taken          []string
+		wantErr        error
+		wantPlan       string
+		wantHandle     string
+		wantSuggestion string
+	}{
+		{name: "default plan", in: CreateInput{TenantID: tenantID, Name: "Shop", Handle: "shop"}, wantPlan: DefaultPlan, wantHandle: "shop"},
+		{name: "given plan", in: CreateInput{TenantID: tenantID, Name: "Shop", Handle: "shop", Plan: "pro"}, wantPlan: "pro", wantHandle: "shop"},
+		{name: "missing name", in: CreateInput{TenantID: tenantID, Handle: "shop"}, wantErr: service.ErrInvalid},
+		{name: "handle from name", in: CreateInput{TenantID: tenantID, Name: "Corner Café"}, wantPlan: DefaultPlan, wantHandle: "corner-cafe"},
+		{
+			name:       "generated handle skips taken ones",
+			in:         CreateInput{TenantID: tenantID, Name: "Corner Café"},
+			taken:      []string{"corner-cafe", "corner-cafe-2"},
+			wantPlan:   DefaultPlan,
+			wantHandle: "corner-cafe-3",
+		},
+		{name: "name with nothing to make a handle from", in: CreateInput{TenantID: tenantID, Name: "!!!"}, wantErr: service.ErrInvalid},
+		{
+			name:           "given handle taken",
+			in:             CreateInput{TenantID: tenantID, Name: "Shop", Handle: "shop"},
+			taken:          []string{"shop"},
+			wantErr:        service.ErrConflict,
+			wantSuggestion: "shop-2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &fakeQueries{taken: tt.taken}
+			store, err := New(q, fakeGIDs{}).Create(context.Background(), tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Create() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if len(q.created) != 0 {
+					t.Error("Create() wrote a store for invalid input")
+				}
+				var svcErr *service.Error
+				if errors.As(err, &svcErr) && svcErr.Suggestion != tt.wantSuggestion {
+					t.Errorf("Suggestion = %q, want %q", svcErr.Suggestion, tt.wantSuggestion)
+				}
+				return
+			}
+			if store.Plan != tt.wantPlan || store.Handle != tt.wantHandle || store.TenantID.UUID != tenantID || store.Gid.Int64 != 42 {
+				t.Errorf("Create() = %+v, want handle %q", store, tt.wantHandle)
+			}
+		})
+	}
+}
+
+func TestCheckHandle(t *testing.T) {
+	q := &fakeQueries{taken: []string{"shop", "shop-2"}}
+	svc := New(q, fakeGIDs{})
+
+	tests := []struct {
+		name    string
+		handle  string
+		from    string
+		want    HandleAvailability
+		wantErr error
+	}{
+		{name: "free", handle: "outlet", want: HandleAvailability{Handle: "outlet", Available: true}},
+		{name: "taken", handle: "shop", want: HandleAvailability{Handle: "shop", Suggestion: "shop-3"}},
+		{name: "from name", from: "Shop", want: HandleAvailability{Handle: "shop", Suggestion: "shop-3"}},
+		{name: "malformed", handle: "Shop!", wantErr: service.ErrInvalid},
+		{name: "neither", wantErr: service.ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.CheckHandle(context.Background(), uuid.New(), tt.handle, tt.from)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CheckHandle() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("CheckHandle() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	now := time.Now()
+	q := &fakeQueries{}
+	for i := range 3 {
+		q.rows = append(q.rows, database.GetStoresByTenantIDPaginatedRow{ID: uuid.New(), CreatedAt: now.Add(-time.Duration(i) * time.Minute)})
+	}
+	svc := New(q, fakeGIDs{})
+
+	page, err := svc.List(context.Background(), uuid.New(), service.PageRequest{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 2 || !page.HasMore {
+		t.Errorf("first page = %d items, has_more %v", len(page.Items), page.HasMore)
+	}
+	if q.listArgs.HasCursor || q.listArgs.RowLimit != 3 {
+		t.Errorf("query args = %+v, want no cursor and limit 3", q.listArgs)
+	}
+
+	after := service.Cursor{CreatedAt: page.Items[1].CreatedAt, ID: page.Items[1].ID}
+	q.rows = q.rows[2:]
+	page, err = svc.List(context.Background(), uuid.New(), service.PageRequest{After: &after, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 1 || page.HasMore {
+		t.Errorf("last page = %d items, has_more %v", len(page.Items), page.HasMore)
+	}
+	if !q.listArgs.HasCursor || q.listArgs.CursorID != after.ID {
+		t.Errorf("query args = %+v, want the cursor", q.listArgs)
+	}
+}
+
+func TestListForUser(t *testing.T) {
+	userID := uuid.New()
+	acme := uuid.NullUUID{UUID: uuid.New(), Valid: true}
+	globex := uuid.NullUUID{UUID: uuid.New(), Valid: true}
+	// The user's role in initech doesn't grant stores:view
+	initech := uuid.NullUUID{UUID: uuid.New(), Valid: true}
+	q := &fakeQueries{
+		userRows: []database.GetStoresByUserIDPaginatedRow{
+			{ID: uuid.New(), TenantID: acme},
+			{ID: uuid.New(), TenantID: globex},
+			{ID: uuid.New(), TenantID: initech},
+			{ID: uuid.New(), TenantID: acme},
+		},
+		granted: map[uuid.NullUUID][]string{
+			acme:    {"stores:view", "stores:edit"},
+			globex:  {"stores:view"},
+			initech: {"orders:view"},
+		},
+	}
+	svc := New(q, fakeGIDs{})
+
+	tests := []struct {
+		name      string
+		tenantID  uuid.NullUUID
+		limit     int
+		wantItems int
+		wantMore  bool
+	}{
+		{name: "all tenants", limit: 10, wantItems: 3},
+		{name: "one tenant", tenantID: acme, limit: 10, wantItems: 2},
+		{name: "tenant without the permission", tenantID: initech, limit: 10, wantItems: 0},
+		{name: "paged", limit: 2, wantItems: 2, wantMore: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := svc.ListForUser(context.Background(), userID, "stores:view", tt.tenantID, service.PageRequest{Limit: tt.limit})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(page.Items) != tt.wantItems || page.HasMore != tt.wantMore {
+				t.Errorf("page = %d items, has_more %v", len(page.Items), page.HasMore)
+			}
+			for _, store := range page.Items {
+				if store.TenantID == initech {
+					t.Errorf("store %s from a tenant the user can't view stores in", store.ID)
+				}
+				if tt.tenantID.Valid && store.TenantID != tt.tenantID {
+					t.Errorf("store %s from tenant %v", store.ID, store.TenantID)
+				}
+			}
+			if q.userArgs.UserID != userID || q.userArgs.Permission != "stores:view" || q.userArgs.TenantID != tt.tenantID || q.userArgs.RowLimit != int32(tt.limit+1) {
+				t.Errorf("query args = %+v", q.userArgs)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	tenantID := uuid.New()
+	ptr := func(s string) *string { return &s }
+
+	tests := []struct {
+		name      string
+		in        UpdateInput
+		wantErr   error
+		wantField string
+		// wantLocale is the default locale stored, in canonical form
+		wantLocale string
+	}{
+		{name: "settings", in: UpdateInput{Name: ptr("Shop"), DefaultCurrency: ptr("EUR"), Timezone: ptr("Europe/Paris"), Plan: ptr("pro")}},
+		{name: "locale", in: UpdateInput{DefaultLocale: ptr("pt-br")}, wantLocale: "pt-BR"},
+		{name: "bad locale", in: UpdateInput{DefaultLocale: ptr("portuguese please")}, wantErr: service.ErrInvalid, wantField: "default_locale"},
+		{name: "nothing", in: UpdateInput{}},
+		{name: "empty name", in: UpdateInput{Name: ptr("")}, wantErr: service.ErrInvalid, wantField: "name"},
+		{name: "lowercase currency", in: UpdateInput{DefaultCurrency: ptr("eur")}, wantErr: service.ErrInvalid, wantField: "default_currency"},
+		{name: "unknown timezone", in: UpdateInput{Timezone: ptr("Mars/Olympus")}, wantErr: service.ErrInvalid, wantField: "timezone"},
+		{name: "server timezone", in: UpdateInput{Timezone: ptr("Local")}, wantErr: service.ErrInvalid, wantField: "timezone"},
+		{name: "another tenant", in: UpdateInput{TenantID: uuid.New(), Name: ptr("Shop")}, wantErr: service.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := database.Store{
+				ID: uuid.New(), TenantID: uuid.NullUUID{UUID: tenantID, Valid: true},
+				Name: "Old", DefaultCurrency: "USD", DefaultLocale: "en", Timezone: "UTC", Plan: DefaultPlan, Status: StatusActive,
+			}
+			q := &fakeQueries{stores: map[uuid.UUID]database.Store{existing.ID: existing}}
+			in := tt.in
+			in.ID = existing.ID
+			if in.TenantID == uuid.Nil {
+				in.TenantID = tenantID
+			}
+
+			got, err := New(q, fakeGIDs{}).Update(context.Background(), in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Update() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantField != "" && !strings.Contains(err.Error(), tt.wantField) {
+				t.Errorf("Update() error = %v, want it to name %s", err, tt.wantField)
+			}
+			if err != nil {
+				return
+			}
+			want := existing
+			if in.Name != nil {
+				want.Name, want.DefaultCurrency, want.Timezone, want.Plan = *in.Name, *in.DefaultCurrency, *in.Timezone, *in.Plan
+			}
+			if tt.wantLocale != "" {
+				want.DefaultLocale = tt.wantLocale
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Update() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestSetStatus(t *testing.T) {
+	tests := []struct {
+		from, to string
+		wantErr  error
+		wantCode string
+	}{
+		{from: StatusActive, to: StatusPaused},
+		{from: StatusActive, to: StatusClosed},
+		{from: StatusPaused, to: StatusActive},
+		{from: StatusActive, to: StatusActive},
+		{from: StatusClosed, to: StatusPaused},
+		{from: StatusClosed, to: StatusActive, wantErr: service.ErrConflict, wantCode: problem.CodeInvalidStatusTransition},
+		{from: StatusActive, to: "deleted", wantErr: service.ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			tenantID := uuid.New()
+			store := database.Store{ID: uuid.New(), TenantID: uuid.NullUUID{UUID: tenantID, Valid: true}, Status: tt.from}
+			q := &fakeQueries{stores: map[uuid.UUID]database.Store{store.ID: store}}
+
+			got, err := New(q, fakeGIDs{}).SetStatus(context.Background(), tenantID, store.ID, tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetStatus() error = %v, want %v", err, tt.wantErr)
+			}
+			var svcErr *service.Error
+			if tt.wantCode != "" && (!errors.As(err, &svcErr) || svcErr.Code != tt.wantCode) {
+				t.Errorf("SetStatus() error = %v, want code %s", err, tt.wantCode)
+			}
+			if err == nil && got.Status != tt.to {
+				t.Errorf("status = %s, want %s", got.Status, tt.to)
+			}
+		})
+	}
+}
+
+func TestDeleteRestore(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	store := database.Store{ID: uuid.New(), TenantID: uuid.NullUUID{UUID: tenantID, Valid: true}, Status: StatusActive}
+	q := &fakeQueries{stores: map[uuid.UUID]database.Store{store.ID: store}}
+	svc := New(q, fakeGIDs{})
+
+	if _, err := svc.Restore(ctx, tenantID, store.ID); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Restore() of a live store error = %v, want ErrNotFound", err)
+	}
+	if _, err := svc.Delete(ctx, uuid.New(), store.ID); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Delete() from another tenant error = %v, want ErrNotFound", err)
+	}
+	deleted, err := svc.Delete(ctx, tenantID, store.ID)
+	if err != nil || !deleted.DeletedAt.Valid {
+		t.Fatalf("Delete() = %+v, %v", deleted, err)
+	}
+	if _, err := svc.SetStatus(ctx, tenantID, store.ID, StatusPaused); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("SetStatus() on a deleted store error = %v, want ErrNotFound", err)
+	}
+	restored, err := svc.Restore(ctx, tenantID, store.ID)
+	if err != nil || restored.DeletedAt.Valid {
+		t.Fatalf("Restore() = %+v, %v", restored, err)
+	}
+}
+
+func TestUpdateSettings(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	store := database.Store{ID: uuid.New(), TenantID: uuid.NullUUID{UUID: tenantID, Valid: true}, Status: StatusActive}
+	stored := `{"weight_unit":"lb","checkout":{"require_phone":true,"terms_url":"https://shop.example/terms"}}`
+
+	tests := []struct {
+		name    string
+		patch   string
+		want    func(*Settings)
+		wantErr error
+	}{
+		{
+			name:  "merges over stored values and defaults",
+			patch: `{"order_number_prefix":"SO-","checkout":{"guest_checkout":false}}`,
+			want: func(s *Settings) {
+				s.WeightUnit = "lb"
+				s.OrderNumberPrefix = "SO-"
+				s.Checkout.GuestCheckout = false
+				s.Checkout.RequirePhone = true
+				s.Checkout.TermsURL = "https://shop.example/terms"
+			},
+		},
+		{
+			name:  "null resets to the default",
+			patch: `{"weight_unit":null,"checkout":{"terms_url":null}}`,
+			want: func(s *Settings) {
+				s.Checkout.RequirePhone = true
+			},
+		},
+		{
+			name:  "staff alerts with an email",
+			patch: `{"notifications":{"staff_order_alerts":true,"staff_email":"orders@shop.example"}}`,
+			want: func(s *Settings) {
+				s.WeightUnit = "lb"
+				s.Checkout.RequirePhone = true
+				s.Checkout.TermsURL = "https://shop.example/terms"
+				s.Notifications.StaffOrderAlerts = true
+				s.Notifications.StaffEmail = "orders@shop.example"
+			},
+		},
+		{name: "unknown field", patch: `{"checkout":{"gift_wrap":true}}`, wantErr: service.ErrInvalid},
+		{name: "wrong type", patch: `{"checkout":{"guest_checkout":"yes"}}`, wantErr: service.ErrInvalid},
+		{name: "not an object", patch: `["weight_unit"]`, wantErr: service.ErrInvalid},
+		{name: "null patch", patch: `null`, wantErr: service.ErrInvalid},
+		{name: "bad weight unit", patch: `{"weight_unit":"stone"}`, wantErr: service.ErrInvalid},
+		{name: "long prefix", patch: `{"order_number_prefix":"ORDER-NUMBER-"}`, wantErr: service.ErrInvalid},
+		{name: "bad color", patch: `{"branding":{"primary_color":"red"}}`, wantErr: service.ErrInvalid},
+		{name: "bad logo url", patch: `{"branding":{"logo_url":"javascript:alert(1)"}}`, wantErr: service.ErrInvalid},
+		{name: "staff alerts without an email", patch: `{"notifications":{"staff_order_alerts":true}}`, wantErr: service.ErrInvalid},
+		{name: "low stock alerts without an email", patch: `{"notifications":{"low_stock_alerts":true}}`, wantErr: service.ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &fakeQueries{
+				stores:   map[uuid.UUID]database.Store{store.ID: store},
+				settings: map[uuid.UUID]json.RawMessage{store.ID: json.RawMessage(stored)},
+			}
+			svc := New(q, fakeGIDs{})
+
+			got, err := svc.UpdateSettings(ctx, tenantID, store.ID, json.RawMessage(tt.patch))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("UpdateSettings() error = %v, want %v", err, tt.wantErr)
+				}
+				if string(q.settings[store.ID]) != stored {
+					t.Errorf("stored settings changed on error: %s", q.settings[store.ID])
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateSettings() error = %v", err)
+			}
+			want := DefaultSettings()
+			tt.want(&want)
+			if got != want {
+				t.Errorf("UpdateSettings() = %+v, want %+v", got, want)
+			}
+			if read, err := svc.Settings(ctx, tenantID, store.ID); err != nil || read != want {
+				t.Errorf("Settings() = %+v, %v, want %+v", read, err, want)
+			}
+		})
+	}
+}
+
+func TestUpdateSettingsScope(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	store := database.Store{ID: uuid.New(), TenantID: uuid.NullUUID{UUID: tenantID, Valid: true}}
+	q := &fakeQueries{stores: map[uuid.UUID]database.Store{store.ID: store}}
+	svc := New(q, fakeGIDs{})
+
+	if _, err := svc.Settings(ctx, uuid.New(), store.ID); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Settings() from another tenant error = %v, want ErrNotFound", err)
+	}
+	if _, err := svc.UpdateSettings(ctx, uuid.New(), store.ID, json.RawMessage(`{}`)); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("UpdateSettings() from another tenant error = %v, want ErrNotFound", err)
+	}
+	if got, err := svc.Settings(ctx, tenantID, store.ID); err != nil || got != DefaultSettings() {
+		t.Errorf("Settings() of a new store = %+v, %v, want the defaults", got, err)
+	}
+}
+
+func TestSetCurrencies(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	store := database.Store{ID: uuid.New(), TenantID: uuid.NullUUID{UUID: tenantID, Valid: true}, DefaultCurrency: "USD"}
+	tooMany := make([]string, 0, MaxCurrencies+1)
+	for i := range MaxCurrencies + 1 {
+		tooMany = append(tooMany, "A"+string(rune('A'+i/26))+string(rune('A'+i%26)))
+	}
+
+	tests := []struct {
+		name      string
+		tenantID  uuid.UUID
+		codes     []string
+		want      []string
+		wantErr   error
+		wantField string
+	}{
+		{name: "sorted", tenantID: tenantID, codes: []string{"GBP", "EUR"}, want: []string{"EUR", "GBP"}},
+		{name: "default left out", tenantID: tenantID, codes: []string{"USD", "CAD"}, want: []string{"CAD"}},
+		{name: "cleared", tenantID: tenantID, codes: nil, want: []string{}},
+		{name: "bad code", tenantID: tenantID, codes: []string{"EUR", "euro"}, wantErr: service.ErrInvalid, wantField: "currencies.1"},
+		{name: "listed twice", tenantID: tenantID, codes: []string{"EUR", "EUR"}, wantErr: service.ErrInvalid, wantField: "currencies.1"},
+		{name: "too many", tenantID: tenantID, codes: tooMany, wantErr: service.ErrInvalid, wantField: "currencies"},
+		{name: "another tenant", tenantID: uuid.New(), codes: []string{"EUR"}, wantErr: service.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &fakeQueries{
+				stores:     map[uuid.UUID]database.Store{store.ID: store},
+				currencies: map[uuid.UUID][]string{store.ID: {"JPY"}},
+			}
+			svc := New(q, fakeGIDs{})
+
+			got, err := svc.SetCurrencies(ctx, tt.tenantID, store.ID, tt.codes)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("SetCurrencies() error = %v, want %v", err, tt.wantErr)
+				}
+				if tt.wantField != "" && !strings.Contains(err.Error(), tt.wantField+":") {
+					t.Errorf("SetCurrencies() error = %v, want it to name %s", err, tt.wantField)
+				}
+				if !slices.Equal(q.currencies[store.ID], []string{"JPY"}) {
+					t.Errorf("currencies changed on error: %v", q.currencies[store.ID])
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetCurrencies() error = %v", err)
+			}
+			if got.Default != "USD" || !slices.Equal(got.Enabled, tt.want) {
+				t.Errorf("SetCurrencies() = %+v, want USD and %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package tenants
+
+import (
+	"context"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/service/roles"
+	"github.com/google/uuid"
+)
+
+// Membership statuses. Only active members can reach the tenant.
+const (
+	MemberActive    = "active"
+	MemberInvited   = "invited"
+	MemberSuspended = "suspended"
+)
+
+var (
+	errMemberNotFound = service.NotFound("Member not found in this tenant")
+	errLastOwner      = &service.Error{
+		Kind:    service.ErrConflict,
+		Code:    problem.CodeLastOwner,
+		Message: "A tenant needs an active owner, give another member the Owner role first",
+	}
+	errOwnerOnly = service.Forbidden("Only an owner can remove or suspend another owner")
+)
+
+// MemberChange names a member of a tenant and who is changing them
+type MemberChange struct {
+	TenantID uuid.UUID
+	MemberID uuid.UUID
+	ActorID  uuid.UUID
+}
+
+// RemoveMember takes a member, and their roles, out of a tenant. Owners
+// can only be removed by another owner, and never the last active one.
+func (s *Service) RemoveMember(ctx context.Context, in MemberChange) (database.TenantUser, error) {
+	var removed database.TenantUser
+	err := s.tx(ctx, func(q Queries) error {
+		member, err := s.guardOwner(ctx, q, in)
+		if err != nil {
+			return err
+		}
+		removed, err = q.DeleteTenantUser(ctx, database.DeleteTenantUserParams{
+			ID:       member.ID,
+			TenantID: in.TenantID,
+		})
+		return err
+	})
+	return removed, err
+}
+
+// SuspendMember stops an active member reaching the tenant while keeping
+// their roles. Owners are protected as for RemoveMember.
+func (s *Service) SuspendMember(ctx context.Context, in MemberChange) (database.TenantUser, error) {
+	var suspended database.TenantUser
+	err := s.tx(ctx, func(q Queries) error {
+		member, err := s.guardOwner(ctx, q, in)
+		if err != nil {
+			return err
+		}
+		if member.Status != MemberActive {
+			return statusConflict("Only active members can be suspended")
+		}
+		suspended, err = q.UpdateTenantUserStatus(ctx, database.UpdateTenantUserStatusParams{
+			ID:     member.ID,
+			Status: MemberSuspended,
+		})
+		return err
+	})
+	return suspended, err
+}
+
+// ReactivateMember gives a suspended member their access back
+func (s *Service) ReactivateMember(ctx context.Context, in MemberChange) (database.TenantUser, error) {
+	member, err := s.member(ctx, s.q, in)
+	if err != nil {
+		return database.TenantUser{}, err
+	}
+	if member.Status != MemberSuspended {
+		return database.TenantUser{}, statusConflict("Only suspended members can be reactivated")
+	}
+	return s.q.UpdateTenantUserStatus(ctx, database.UpdateTenantUserStatusParams{
+		ID:     member.ID,
+		Status: MemberActive,
+	})
+}
+
+// Leave takes a user out of a tenant of their own accord. The last active
+// owner can't leave.
+func (s *Service) Leave(ctx context.Context, tenantID, userID uuid.UUID) (database.TenantUser, error) {
+	membership, err := s.q.GetTenantUser(ctx, database.GetTenantUserParams{
+		TenantID: tenantID,
+		UserID:   userID,
+	})
+	if err != nil {
+		return database.TenantUser{}, service.NotFoundAs(err, "You are not a member of this tenant")
+	}
+	return s.RemoveMember(ctx, MemberChange{TenantID: tenantID, MemberID: membership.ID, ActorID: userID})
+}
+
+// guardOwner loads the member being changed, locking the tenant, and
+// refuses if they are an owner and the actor isn't one, or they are the
+// last active owner
+func (s *Service) guardOwner(ctx context.Context, q Queries, in MemberChange) (database.TenantUser, error) {
+	// Two owners removing each other at once must not both succeed
+	if _, err := q.LockTenant(ctx, in.TenantID); err != nil {
+		return database.TenantUser{}, service.NotFoundAs(err, "Tenant not found")
+	}
+	member, err := s.member(ctx, q, in)
+	if err != nil {
+		return database.TenantUser{}, err
+	}
+
+	isOwner, err := q.IsTenantUserOwner(ctx, database.IsTenantUserOwnerParams{
+		TenantUserID: member.ID,
+		OwnerRole:    roles.OwnerTemplate.Name,
+	})
+	if err != nil || !isOwner {
+		return member, err
+	}
+
+	if member.UserID != in.ActorID {
+		actor, err := q.GetTenantUser(ctx, database.GetTenantUserParams{
+			TenantID: in.TenantID,
+			UserID:   in.ActorID,
+		})
+		if err != nil {
+			return database.TenantUser{}, service.NotFoundAs(err, "You are not a member of this tenant")
+		}
+		actorIsOwner, err := q.IsTenantUserOwner(ctx, database.IsTenantUserOwnerParams{
+			TenantUserID: actor.ID,
+			OwnerRole:    roles.OwnerTemplate.Name,
+		})
+		if err != nil {
+			return database.TenantUser{}, err
+		}
+		if !actorIsOwner {
+			return database.TenantUser{}, errOwnerOnly
+		}
+	}
+
+	if member.Status != MemberActive {
+		return member, nil
+	}
+	owners, err := q.CountActiveTenantOwners(ctx, database.CountActiveTenantOwnersParams{
+		TenantID:  in.TenantID,
+		OwnerRole: roles.OwnerTemplate.Name,
+	})
+	if err != nil {
+		return database.TenantUser{}, err
+	}
+	if owners <= 1 {
+		return database.TenantUser{}, errLastOwner
+	}
+	return member, nil
+}
+
+// member loads a membership, checking it belongs to the tenant
+func (s *Service) member(ctx context.Context, q Queries, in MemberChange) (database.TenantUser, error) {
+	member, err := q.GetTenantUserByID(ctx, in.MemberID)
+	if err != nil {
+		return database.TenantUser{}, service.NotFoundAs(err, errMemberNotFound.Error())
+	}
+	if member.TenantID != in.TenantID {
+		return database.TenantUser{}, errMemberNotFound
+	}
+	return member, nil
+}
+
+func statusConflict(message string) error {
+	return &service.Error{Kind: service.ErrConflict, Code: problem.CodeInvalidStatusTransition, Message: message}
+}
@@ -0,0 +1,154 @@
+// Package tenants creates tenants, lists the ones a user belongs to, and
+// manages their members
+package tenants
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/service/roles"
+	"github.com/google/uuid"
+)
+
+// Queries is the slice of the database the service uses
+type Queries interface {
+	CreateTenant(ctx context.Context, arg database.CreateTenantParams) (database.Tenant, error)
+	CreateTenantUser(ctx context.Context, arg database.CreateTenantUserParams) (database.TenantUser, error)
+	CreateRole(ctx context.Context, arg database.CreateRoleParams) (database.Role, error)
+	GetAllPermissions(ctx context.Context) ([]database.Permission, error)
+	AssignPermissionToRole(ctx context.Context, arg database.AssignPermissionToRoleParams) error
+	AssignRoleToTenantUser(ctx context.Context, arg database.AssignRoleToTenantUserParams) error
+	GetTenantsByUserIDPaginated(ctx context.Context, arg database.GetTenantsByUserIDPaginatedParams) ([]database.GetTenantsByUserIDPaginatedRow, error)
+	LockTenant(ctx context.Context, tenantID uuid.UUID) (uuid.UUID, error)
+	GetTenantUser(ctx context.Context, arg database.GetTenantUserParams) (database.TenantUser, error)
+	GetTenantUserByID(ctx context.Context, id uuid.UUID) (database.TenantUser, error)
+	IsTenantUserOwner(ctx context.Context, arg database.IsTenantUserOwnerParams) (bool, error)
+	CountActiveTenantOwners(ctx context.Context, arg database.CountActiveTenantOwnersParams) (int64, error)
+	UpdateTenantUserStatus(ctx context.Context, arg database.UpdateTenantUserStatusParams) (database.TenantUser, error)
+	DeleteTenantUser(ctx context.Context, arg database.DeleteTenantUserParams) (database.TenantUser, error)
+}
+
+type Service struct {
+	q    Queries
+	tx   service.Tx[Queries]
+	gids service.GIDGenerator
+}
+
+func New(q Queries, tx service.Tx[Queries], gids service.GIDGenerator) *Service {
+	return &Service{q: q, tx: tx, gids: gids}
+}
+
+// CreateInput describes a new tenant and the user who owns it
+type CreateInput struct {
+	Name    string
+	OwnerID uuid.UUID
+}
+
+// Created is everything Create sets up
+type Created struct {
+	Tenant     database.Tenant
+	Membership database.TenantUser
+	// Roles are the system roles, one per roles.Templates entry and in
+	// the same order
+	Roles     []database.Role
+	OwnerRole database.Role
+}
+
+// Create sets up a tenant with a system role for each of roles.Templates
+// and its owner as an active member holding the Owner role, all or nothing
+func (s *Service) Create(ctx context.Context, in CreateInput) (Created, error) {
+	if strings.TrimSpace(in.Name) == "" {
+		return Created{}, service.Invalid("Tenant name is required")
+	}
+
+	var created Created
+	err := s.tx(ctx, func(q Queries) error {
+		tenant, err := q.CreateTenant(ctx, database.CreateTenantParams{
+			Gid:  service.NewGID(s.gids),
+			Name: in.Name,
+		})
+		if err != nil {
+			return err
+		}
+
+		membership, err := q.CreateTenantUser(ctx, database.CreateTenantUserParams{
+			TenantID: tenant.ID,
+			UserID:   in.OwnerID,
+			Status:   "active",
+		})
+		if err != nil {
+			return err
+		}
+
+		permissions, err := q.GetAllPermissions(ctx)
+		if err != nil {
+			return err
+		}
+		created = Created{Tenant: tenant, Membership: membership}
+		for _, template := range roles.Templates {
+			role, err := s.createSystemRole(ctx, q, tenant.ID, template, permissions)
+			if err != nil {
+				return err
+			}
+			created.Roles = append(created.Roles, role)
+			if template.Name == roles.OwnerTemplate.Name {
+				created.OwnerRole = role
+			}
+		}
+
+		return q.AssignRoleToTenantUser(ctx, database.AssignRoleToTenantUserParams{
+			TenantUserID: membership.ID,
+			RoleID:       created.OwnerRole.ID,
+		})
+	})
+	if err != nil {
+		return Created{}, err
+	}
+	return created, nil
+}
+
+// createSystemRole makes a tenant's role from template, granting the
+// permissions it picks
+func (s *Service) createSystemRole(ctx context.Context, q Queries, tenantID uuid.UUID, template roles.Template, permissions []database.Permission) (database.Role, error) {
+	role, err := q.CreateRole(ctx, database.CreateRoleParams{
+		Gid:         service.NewGID(s.gids),
+		TenantID:    tenantID,
+		Name:        template.Name,
+		Description: sql.NullString{String: template.Description, Valid: true},
+		IsSystem:    true,
+	})
+	if err != nil {
+		return database.Role{}, err
+	}
+	for _, perm := range permissions {
+		if !template.Grants(perm.Key) {
+			continue
+		}
+		if err := q.AssignPermissionToRole(ctx, database.AssignPermissionToRoleParams{
+			RoleID:       role.ID,
+			PermissionID: perm.ID,
+		}); err != nil {
+			return database.Role{}, err
+		}
+	}
+	return role, nil
+}
+
+// ListForUser pages through the tenants a user belongs to, newest first
+func (s *Service) ListForUser(ctx context.Context, userID uuid.UUID, page service.PageRequest) (service.Page[database.GetTenantsByUserIDPaginatedRow], error) {
+	hasCursor, createdAt, id, limit := page.QueryArgs()
+	rows, err := s.q.GetTenantsByUserIDPaginated(ctx, database.GetTenantsByUserIDPaginatedParams{
+		UserID:          userID,
+		HasCursor:       hasCursor,
+		CursorCreatedAt: createdAt,
+		CursorID:        id,
+		RowLimit:        limit,
+	})
+	if err != nil {
+		return service.Page[database.GetTenantsByUserIDPaginatedRow]{}, err
+	}
+	return service.NewPage(rows, page.Limit), nil
+}
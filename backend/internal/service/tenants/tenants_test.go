@@ -0,0 +1,316 @@
+package tenants
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/service/roles"
+	"github.com/google/uuid"
+)
+
+type fakeGIDs struct{ next uint64 }
+
+func (g *fakeGIDs) Generate() uint64 {
+	g.next++
+	return g.next
+}
+
+type fakeQueries struct {
+	permissions []database.Permission
+	failAt      string
+
+	tenants     []database.Tenant
+	memberships []database.TenantUser
+	roles       []database.Role
+	grants      []database.AssignPermissionToRoleParams
+	assigned    []database.AssignRoleToTenantUserParams
+	listArgs    database.GetTenantsByUserIDPaginatedParams
+	// owners are the memberships holding the Owner role
+	owners map[uuid.UUID]bool
+}
+
+var errBoom = errors.New("boom")
+
+func (f *fakeQueries) fail(step string) error {
+	if f.failAt == step {
+		return errBoom
+	}
+	return nil
+}
+
+func (f *fakeQueries) CreateTenant(_ context.Context, arg database.CreateTenantParams) (database.Tenant, error) {
+	t := database.Tenant{ID: uuid.New(), Gid: arg.Gid, Name: arg.Name, Status: "active"}
+	f.tenants = append(f.tenants, t)
+	return t, f.fail("tenant")
+}
+
+func (f *fakeQueries) CreateTenantUser(_ context.Context, arg database.CreateTenantUserParams) (database.TenantUser, error) {
+	m := database.TenantUser{ID: uuid.New(), TenantID: arg.TenantID, UserID: arg.UserID, Status: arg.Status}
+	f.memberships = append(f.memberships, m)
+	return m, f.fail("membership")
+}
+
+func (f *fakeQueries) CreateRole(_ context.Context, arg database.CreateRoleParams) (database.Role, error) {
+	r := database.Role{ID: uuid.New(), Gid: arg.Gid, TenantID: arg.TenantID, Name: arg.Name, Description: arg.Description, IsSystem: arg.IsSystem}
+	f.roles = append(f.roles, r)
+	return r, f.fail("role")
+}
+
+func (f *fakeQueries) GetAllPermissions(context.Context) ([]database.Permission, error) {
+	return f.permissions, f.fail("permissions")
+}
+
+func (f *fakeQueries) AssignPermissionToRole(_ context.Context, arg database.AssignPermissionToRoleParams) error {
+	f.grants = append(f.grants, arg)
+	return f.fail("grant")
+}
+
+func (f *fakeQueries) AssignRoleToTenantUser(_ context.Context, arg database.AssignRoleToTenantUserParams) error {
+	f.assigned = append(f.assigned, arg)
+	return f.fail("assign")
+}
+
+func (f *fakeQueries) GetTenantsByUserIDPaginated(_ context.Context, arg database.GetTenantsByUserIDPaginatedParams) ([]database.GetTenantsByUserIDPaginatedRow, error) {
+	f.listArgs = arg
+	return make([]database.GetTenantsByUserIDPaginatedRow, arg.RowLimit), nil
+}
+
+func (f *fakeQueries) LockTenant(_ context.Context, tenantID uuid.UUID) (uuid.UUID, error) {
+	return tenantID, nil
+}
+
+func (f *fakeQueries) GetTenantUser(_ context.Context, arg database.GetTenantUserParams) (database.TenantUser, error) {
+	for _, m := range f.memberships {
+		if m.TenantID == arg.TenantID && m.UserID == arg.UserID {
+			return m, nil
+		}
+	}
+	return database.TenantUser{}, sql.ErrNoRows
+}
+
+func (f *fakeQueries) GetTenantUserByID(_ context.Context, id uuid.UUID) (database.TenantUser, error) {
+	for _, m := range f.memberships {
+		if m.ID == id {
+			return m, nil
+		}
+	}
+	return database.TenantUser{}, sql.ErrNoRows
+}
+
+func (f *fakeQueries) IsTenantUserOwner(_ context.Context, arg database.IsTenantUserOwnerParams) (bool, error) {
+	return f.owners[arg.TenantUserID], nil
+}
+
+func (f *fakeQueries) CountActiveTenantOwners(_ context.Context, arg database.CountActiveTenantOwnersParams) (int64, error) {
+	var n int64
+	for _, m := range f.memberships {
+		if m.TenantID == arg.TenantID && m.Status == MemberActive && f.owners[m.ID] {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeQueries) UpdateTenantUserStatus(_ context.Context, arg database.UpdateTenantUserStatusParams) (database.TenantUser, error) {
+	for i, m := range f.memberships {
+		if m.ID == arg.ID {
+			f.memberships[i].Status = arg.Status
+			return f.memberships[i], nil
+		}
+	}
+	return database.TenantUser{}, sql.ErrNoRows
+}
+
+func (f *fakeQueries) DeleteTenantUser(_ context.Context, arg database.DeleteTenantUserParams) (database.TenantUser, error) {
+	for i, m := range f.memberships {
+		if m.ID == arg.ID && m.TenantID == arg.TenantID {
+			f.memberships = append(f.memberships[:i], f.memberships[i+1:]...)
+			return m, nil
+		}
+	}
+	return database.TenantUser{}, sql.ErrNoRows
+}
+
+func TestCreate(t *testing.T) {
+	ownerID := uuid.New()
+	perms := []database.Permission{
+		{ID: uuid.New(), Key: "tenant:owner"},
+		{ID: uuid.New(), Key: "audit_log:view"},
+		{ID: uuid.New(), Key: "products:edit"},
+		{ID: uuid.New(), Key: "products:view"},
+	}
+	// How many of perms each template grants
+	wantGrants := map[string]int{"Owner": 4, "Admin": 3, "Staff": 2, "Viewer": 1}
+
+	tests := []struct {
+		name    string
+		in      CreateInput
+		failAt  string
+		wantErr error
+	}{
+		{name: "sets up owner", in: CreateInput{Name: "Acme", OwnerID: ownerID}},
+		{name: "missing name", in: CreateInput{Name: "  ", OwnerID: ownerID}, wantErr: service.ErrInvalid},
+		{name: "membership fails", in: CreateInput{Name: "Acme", OwnerID: ownerID}, failAt: "membership", wantErr: errBoom},
+		{name: "grant fails", in: CreateInput{Name: "Acme", OwnerID: ownerID}, failAt: "grant", wantErr: errBoom},
+		{name: "role assignment fails", in: CreateInput{Name: "Acme", OwnerID: ownerID}, failAt: "assign", wantErr: errBoom},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &fakeQueries{permissions: perms, failAt: tt.failAt}
+			calls := 0
+			tx := func(ctx context.Context, fn func(q Queries) error) error {
+				calls++
+				return fn(q)
+			}
+
+			got, err := New(q, tx, &fakeGIDs{}).Create(context.Background(), tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Create() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if got.Tenant.ID != uuid.Nil {
+					t.Errorf("Create() returned %+v alongside an error", got)
+				}
+				return
+			}
+			if calls != 1 {
+				t.Errorf("Create() ran %d transactions, want 1", calls)
+			}
+			if got.Membership.UserID != ownerID || got.Membership.Status != "active" {
+				t.Errorf("Membership = %+v", got.Membership)
+			}
+			if got.OwnerRole.Name != roles.OwnerTemplate.Name || got.OwnerRole.TenantID != got.Tenant.ID {
+				t.Errorf("OwnerRole = %+v", got.OwnerRole)
+			}
+			if len(got.Roles) != len(roles.Templates) {
+				t.Fatalf("created %d roles, want %d", len(got.Roles), len(roles.Templates))
+			}
+			for _, role := range got.Roles {
+				if !role.IsSystem {
+					t.Errorf("role %s is not a system role", role.Name)
+				}
+				granted := 0
+				for _, g := range q.grants {
+					if g.RoleID == role.ID {
+						granted++
+					}
+				}
+				if granted != wantGrants[role.Name] {
+					t.Errorf("role %s granted %d permissions, want %d", role.Name, granted, wantGrants[role.Name])
+				}
+			}
+			if len(q.assigned) != 1 || q.assigned[0].TenantUserID != got.Membership.ID || q.assigned[0].RoleID != got.OwnerRole.ID {
+				t.Errorf("role assignments = %+v", q.assigned)
+			}
+			if got.Tenant.Gid == got.OwnerRole.Gid {
+				t.Error("tenant and role share a GID")
+			}
+		})
+	}
+}
+
+func TestListForUser(t *testing.T) {
+	q := &fakeQueries{}
+	userID := uuid.New()
+	page, err := New(q, nil, &fakeGIDs{}).ListForUser(context.Background(), userID, service.PageRequest{Limit: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 5 || !page.HasMore {
+		t.Errorf("page = %d items, has_more %v", len(page.Items), page.HasMore)
+	}
+	if q.listArgs.UserID != userID || q.listArgs.RowLimit != 6 {
+		t.Errorf("query args = %+v", q.listArgs)
+	}
+}
+
+func TestMemberChanges(t *testing.T) {
+	tenantID := uuid.New()
+	member := func(status string) database.TenantUser {
+		return database.TenantUser{ID: uuid.New(), TenantID: tenantID, UserID: uuid.New(), Status: status}
+	}
+	owner, coOwner, staff := member(MemberActive), member(MemberActive), member(MemberActive)
+	suspended, foreign := member(MemberSuspended), member(MemberActive)
+	foreign.TenantID = uuid.New()
+
+	remove := func(s *Service, in MemberChange) (database.TenantUser, error) {
+		return s.RemoveMember(context.Background(), in)
+	}
+	suspend := func(s *Service, in MemberChange) (database.TenantUser, error) {
+		return s.SuspendMember(context.Background(), in)
+	}
+	reactivate := func(s *Service, in MemberChange) (database.TenantUser, error) {
+		return s.ReactivateMember(context.Background(), in)
+	}
+	leave := func(s *Service, in MemberChange) (database.TenantUser, error) {
+		return s.Leave(context.Background(), in.TenantID, in.ActorID)
+	}
+
+	tests := []struct {
+		name       string
+		change     func(*Service, MemberChange) (database.TenantUser, error)
+		target     database.TenantUser
+		actor      database.TenantUser
+		soleOwner  bool
+		wantStatus string
+		wantGone   bool
+		wantErr    error
+		wantCode   string
+	}{
+		{name: "owner removes staff", change: remove, target: staff, actor: owner, wantGone: true},
+		{name: "staff cannot remove owner", change: remove, target: owner, actor: staff, wantErr: service.ErrForbidden},
+		{name: "owner removes co-owner", change: remove, target: coOwner, actor: owner, wantGone: true},
+		{name: "last owner kept", change: remove, target: owner, actor: owner, soleOwner: true, wantErr: service.ErrConflict, wantCode: problem.CodeLastOwner},
+		{name: "member of another tenant", change: remove, target: foreign, actor: owner, wantErr: service.ErrNotFound},
+		{name: "suspend staff", change: suspend, target: staff, actor: owner, wantStatus: MemberSuspended},
+		{name: "staff cannot suspend owner", change: suspend, target: coOwner, actor: staff, wantErr: service.ErrForbidden},
+		{name: "last owner not suspended", change: suspend, target: owner, actor: owner, soleOwner: true, wantErr: service.ErrConflict, wantCode: problem.CodeLastOwner},
+		{name: "suspend twice", change: suspend, target: suspended, actor: owner, wantErr: service.ErrConflict, wantCode: problem.CodeInvalidStatusTransition},
+		{name: "reactivate", change: reactivate, target: suspended, actor: owner, wantStatus: MemberActive},
+		{name: "reactivate active", change: reactivate, target: staff, actor: owner, wantErr: service.ErrConflict, wantCode: problem.CodeInvalidStatusTransition},
+		{name: "staff leaves", change: leave, target: staff, actor: staff, wantGone: true},
+		{name: "owner leaves co-owner behind", change: leave, target: owner, actor: owner, wantGone: true},
+		{name: "last owner cannot leave", change: leave, target: owner, actor: owner, soleOwner: true, wantErr: service.ErrConflict, wantCode: problem.CodeLastOwner},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &fakeQueries{
+				memberships: []database.TenantUser{owner, coOwner, staff, suspended, foreign},
+				owners:      map[uuid.UUID]bool{owner.ID: true, coOwner.ID: !tt.soleOwner},
+			}
+			tx := func(ctx context.Context, fn func(q Queries) error) error { return fn(q) }
+			s := New(q, tx, &fakeGIDs{})
+
+			got, err := tt.change(s, MemberChange{TenantID: tenantID, MemberID: tt.target.ID, ActorID: tt.actor.UserID})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantCode != "" {
+				var serr *service.Error
+				if !errors.As(err, &serr) || serr.Code != tt.wantCode {
+					t.Errorf("error = %v, want code %s", err, tt.wantCode)
+				}
+			}
+			if err != nil {
+				return
+			}
+			if got.ID != tt.target.ID {
+				t.Errorf("changed member %s, want %s", got.ID, tt.target.ID)
+			}
+			_, lookupErr := q.GetTenantUserByID(context.Background(), tt.target.ID)
+			if gone := errors.Is(lookupErr, sql.ErrNoRows); gone != tt.wantGone {
+				t.Errorf("member removed = %v, want %v", gone, tt.wantGone)
+			}
+			if tt.wantStatus != "" && got.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", got.Status, tt.wantStatus)
+			}
+		})
+	}
+}
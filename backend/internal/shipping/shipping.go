@@ -0,0 +1,216 @@
+// Package shipping matches destinations to shipping zones and prices the
+// rates of a zone for a cart.
+package shipping
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dfodeker/terminus/internal/address"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidRegion = errors.New("invalid shipping region")
+	ErrInvalidRate   = errors.New("invalid shipping rate")
+)
+
+// MaxZoneRegions bounds how many regions one zone may list
+const MaxZoneRegions = 250
+
+// Kind selects what a rate's range is measured against
+type Kind string
+
+const (
+	// KindFlat always applies
+	KindFlat Kind = "flat"
+	// KindWeight applies when the cart weight in grams is within range
+	KindWeight Kind = "weight"
+	// KindPrice applies when the cart subtotal in cents is within range
+	KindPrice Kind = "price"
+)
+
+// IsValid returns true if the kind is known
+func (k Kind) IsValid() bool {
+	return k == KindFlat || k == KindWeight || k == KindPrice
+}
+
+// Zone is a named set of regions with its own rates
+type Zone struct {
+	ID      uuid.UUID
+	Name    string
+	Regions []string
+}
+
+// Rate prices shipping within a zone. Min is inclusive and Max exclusive;
+// either may be nil for an open-ended range.
+type Rate struct {
+	ID         uuid.UUID
+	ZoneID     uuid.UUID
+	Name       string
+	Kind       Kind
+	PriceCents int64
+	Min        *int64
+	Max        *int64
+}
+
+// Destination is where a cart ships to
+type Destination struct {
+	Country string
+	Region  string
+}
+
+// Cart is what the rates are measured against
+type Cart struct {
+	SubtotalCents int64
+	WeightGrams   int64
+}
+
+// Option is a rate that applies to a cart
+type Option struct {
+	RateID     uuid.UUID
+	ZoneID     uuid.UUID
+	Name       string
+	PriceCents int64
+}
+
+// NormalizeRegions validates and de-duplicates zone regions, accepting
+// country codes (US) and subdivisions (US-CA) in any case
+func NormalizeRegions(regions []string) ([]string, error) {
+	if len(regions) == 0 {
+		return nil, fmt.Errorf("%w: at least one region is required", ErrInvalidRegion)
+	}
+	if len(regions) > MaxZoneRegions {
+		return nil, fmt.Errorf("%w: at most %d regions are allowed", ErrInvalidRegion, MaxZoneRegions)
+	}
+
+	out := make([]string, 0, len(regions))
+	for _, raw := range regions {
+		country, region, _ := strings.Cut(strings.TrimSpace(raw), "-")
+		country, err := address.NormalizeCountry(country)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRegion, err)
+		}
+		code := country
+		if region != "" {
+			region, err = address.NormalizeRegion(country, region)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidRegion, err)
+			}
+			code = country + "-" + region
+		}
+		if !slices.Contains(out, code) {
+			out = append(out, code)
+		}
+	}
+	return out, nil
+}
+
+// MatchZone picks the zone covering a destination. A zone that lists the
+// destination's subdivision beats one that only lists its country; ties go
+// to the first zone.
+func MatchZone(zones []Zone, dest Destination) (Zone, bool) {
+	country := strings.ToUpper(dest.Country)
+	subdivision := ""
+	if dest.Region != "" {
+		subdivision = country + "-" + strings.TrimPrefix(strings.ToUpper(dest.Region), country+"-")
+	}
+
+	var match Zone
+	best := 0
+	for _, zone := range zones {
+		for _, code := range zone.Regions {
+			score := 0
+			switch {
+			case subdivision != "" && code == subdivision:
+				score = 2
+			case code == country:
+				score = 1
+			}
+			if score > best {
+				match, best = zone, score
+			}
+		}
+	}
+	return match, best > 0
+}
+
+// ValidateRate checks a rate's kind and range
+func ValidateRate(r Rate) error {
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidRate)
+	}
+	if !r.Kind.IsValid() {
+		return fmt.Errorf("%w: kind must be 'flat', 'weight' or 'price'", ErrInvalidRate)
+	}
+	if r.PriceCents < 0 {
+		return fmt.Errorf("%w: price must be non-negative", ErrInvalidRate)
+	}
+	if r.Kind == KindFlat {
+		if r.Min != nil || r.Max != nil {
+			return fmt.Errorf("%w: flat rates take no range", ErrInvalidRate)
+		}
+		return nil
+	}
+	if r.Min == nil && r.Max == nil {
+		return fmt.Errorf("%w: %s rates need a min or max", ErrInvalidRate, r.Kind)
+	}
+	if r.Min != nil && *r.Min < 0 {
+		return fmt.Errorf("%w: min must be non-negative", ErrInvalidRate)
+	}
+	if r.Max != nil && *r.Max <= 0 {
+		return fmt.Errorf("%w: max must be positive", ErrInvalidRate)
+	}
+	if r.Min != nil && r.Max != nil && *r.Min >= *r.Max {
+		return fmt.Errorf("%w: min must be below max", ErrInvalidRate)
+	}
+	return nil
+}
+
+// Applies reports whether a rate covers the cart
+func (r Rate) Applies(cart Cart) bool {
+	var v int64
+	switch r.Kind {
+	case KindFlat:
+		return true
+	case KindWeight:
+		v = cart.WeightGrams
+	case KindPrice:
+		v = cart.SubtotalCents
+	default:
+		return false
+	}
+	if r.Min != nil && v < *r.Min {
+		return false
+	}
+	if r.Max != nil && v >= *r.Max {
+		return false
+	}
+	return true
+}
+
+// Quote returns the rates that apply to the cart, cheapest first
+func Quote(rates []Rate, cart Cart) []Option {
+	options := []Option{}
+	for _, rate := range rates {
+		if !rate.Applies(cart) {
+			continue
+		}
+		options = append(options, Option{
+			RateID:     rate.ID,
+			ZoneID:     rate.ZoneID,
+			Name:       rate.Name,
+			PriceCents: rate.PriceCents,
+		})
+	}
+	slices.SortStableFunc(options, func(a, b Option) int {
+		if c := cmp.Compare(a.PriceCents, b.PriceCents); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return options
+}
@@ -0,0 +1,134 @@
+package shipping
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func ptr(v int64) *int64 { return &v }
+
+func TestNormalizeRegions(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "countries and subdivisions", in: []string{"us-ca", " GB ", "US-CA", "fr"}, want: []string{"US-CA", "GB", "FR"}},
+		{name: "country without subdivision list", in: []string{"FR-75"}, want: []string{"FR-75"}},
+		{name: "empty", in: nil, wantErr: true},
+		{name: "unknown country", in: []string{"XX"}, wantErr: true},
+		{name: "unknown subdivision", in: []string{"US-ZZ"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeRegions(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeRegions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrInvalidRegion) {
+					t.Errorf("error = %v, want ErrInvalidRegion", err)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NormalizeRegions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchZone(t *testing.T) {
+	domestic := Zone{ID: uuid.New(), Name: "Domestic", Regions: []string{"US"}}
+	westCoast := Zone{ID: uuid.New(), Name: "West Coast", Regions: []string{"US-CA", "US-OR", "US-WA"}}
+	europe := Zone{ID: uuid.New(), Name: "Europe", Regions: []string{"FR", "DE"}}
+	zones := []Zone{domestic, westCoast, europe}
+
+	tests := []struct {
+		name   string
+		dest   Destination
+		want   string
+		wantOK bool
+	}{
+		{name: "subdivision beats country", dest: Destination{Country: "US", Region: "CA"}, want: "West Coast", wantOK: true},
+		{name: "prefixed region", dest: Destination{Country: "us", Region: "US-OR"}, want: "West Coast", wantOK: true},
+		{name: "falls back to country", dest: Destination{Country: "US", Region: "NY"}, want: "Domestic", wantOK: true},
+		{name: "no region", dest: Destination{Country: "DE"}, want: "Europe", wantOK: true},
+		{name: "not covered", dest: Destination{Country: "JP"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := MatchZone(zones, tt.dest)
+			if ok != tt.wantOK || got.Name != tt.want {
+				t.Errorf("MatchZone() = %q, %v, want %q, %v", got.Name, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestValidateRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rate    Rate
+		wantErr bool
+	}{
+		{name: "flat", rate: Rate{Name: "Standard", Kind: KindFlat, PriceCents: 500}},
+		{name: "weight band", rate: Rate{Name: "Light", Kind: KindWeight, Max: ptr(1000)}},
+		{name: "free over", rate: Rate{Name: "Free", Kind: KindPrice, Min: ptr(5000)}},
+		{name: "flat with range", rate: Rate{Name: "Standard", Kind: KindFlat, Min: ptr(1)}, wantErr: true},
+		{name: "missing range", rate: Rate{Name: "Light", Kind: KindWeight}, wantErr: true},
+		{name: "inverted range", rate: Rate{Name: "Light", Kind: KindWeight, Min: ptr(10), Max: ptr(10)}, wantErr: true},
+		{name: "unknown kind", rate: Rate{Name: "X", Kind: "zone"}, wantErr: true},
+		{name: "negative price", rate: Rate{Name: "X", Kind: KindFlat, PriceCents: -1}, wantErr: true},
+		{name: "missing name", rate: Rate{Kind: KindFlat}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRate(tt.rate)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateRate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidRate) {
+				t.Errorf("error = %v, want ErrInvalidRate", err)
+			}
+		})
+	}
+}
+
+func TestQuote(t *testing.T) {
+	rates := []Rate{
+		{Name: "Express", Kind: KindFlat, PriceCents: 2500},
+		{Name: "Light parcel", Kind: KindWeight, PriceCents: 600, Max: ptr(1000)},
+		{Name: "Heavy parcel", Kind: KindWeight, PriceCents: 1500, Min: ptr(1000)},
+		{Name: "Free shipping", Kind: KindPrice, PriceCents: 0, Min: ptr(10000)},
+	}
+
+	names := func(opts []Option) []string {
+		out := []string{}
+		for _, o := range opts {
+			out = append(out, o.Name)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name string
+		cart Cart
+		want []string
+	}{
+		{name: "light cart", cart: Cart{SubtotalCents: 3000, WeightGrams: 400}, want: []string{"Light parcel", "Express"}},
+		{name: "max is exclusive", cart: Cart{SubtotalCents: 3000, WeightGrams: 1000}, want: []string{"Heavy parcel", "Express"}},
+		{name: "free shipping threshold", cart: Cart{SubtotalCents: 10000, WeightGrams: 400}, want: []string{"Free shipping", "Light parcel", "Express"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := names(Quote(rates, tt.cart)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Quote() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package shopify
+
+import (
+	"strings"
+
+	"github.com/dfodeker/terminus/internal/problem"
+)
+
+// Customer is one Shopify customer
+type Customer struct {
+	// Position is the line the customer is on in a CSV, or their place in a
+	// JSON list counting from 1
+	Position         int
+	Email            string
+	FirstName        string
+	LastName         string
+	Phone            string
+	AcceptsMarketing bool
+	// Address is the customer's default address, when they have one
+	Address *Address
+	Errors  []problem.FieldError
+}
+
+// Address is a postal address. RegionCode is the province or state code
+// without its country, as Shopify writes it.
+type Address struct {
+	FirstName   string
+	LastName    string
+	Company     string
+	Address1    string
+	Address2    string
+	City        string
+	RegionCode  string
+	PostalCode  string
+	CountryCode string
+	Phone       string
+}
+
+// ParseCustomers reads a customers_export CSV or an Admin API customers
+// response
+func ParseCustomers(format string, data []byte) ([]Customer, error) {
+	if err := checkFormat(format); err != nil {
+		return nil, err
+	}
+	var customers []Customer
+	var err error
+	if format == FormatCSV {
+		customers, err = parseCustomersCSV(data)
+	} else {
+		customers, err = parseCustomersJSON(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCount(len(customers)); err != nil {
+		return nil, err
+	}
+	for i := range customers {
+		if customers[i].Email == "" {
+			// Shopify allows customers known only by phone; terminus
+			// identifies them by email
+			var errs fieldErrors
+			errs.add("email", "is required")
+			customers[i].Errors = append(customers[i].Errors, errs...)
+		}
+	}
+	return customers, nil
+}
+
+// parseCustomersCSV reads both the current export, whose address columns
+// start "Default Address", and the older one without the prefix
+func parseCustomersCSV(data []byte) ([]Customer, error) {
+	t, err := readTable(data, "email")
+	if err != nil {
+		return nil, err
+	}
+
+	customers := make([]Customer, 0, len(t.records))
+	for _, rec := range t.records {
+		c := Customer{
+			Position:         rec.line,
+			Email:            strings.ToLower(t.get(rec, "email")),
+			FirstName:        t.get(rec, "first name"),
+			LastName:         t.get(rec, "last name"),
+			Phone:            t.get(rec, "phone"),
+			AcceptsMarketing: parseBool(t.get(rec, "accepts email marketing", "accepts marketing")),
+		}
+		addr := Address{
+			FirstName:   c.FirstName,
+			LastName:    c.LastName,
+			Company:     t.get(rec, "default address company", "company"),
+			Address1:    t.get(rec, "default address address1", "address1"),
+			Address2:    t.get(rec, "default address address2", "address2"),
+			City:        t.get(rec, "default address city", "city"),
+			RegionCode:  t.get(rec, "default address province code", "province code"),
+			PostalCode:  t.get(rec, "default address zip", "zip"),
+			CountryCode: strings.ToUpper(t.get(rec, "default address country code", "country code")),
+			Phone:       t.get(rec, "default address phone"),
+		}
+		c.Address = completeAddress(addr)
+		customers = append(customers, c)
+	}
+	return customers, nil
+}
+
+// customerJSON is a customer as the Admin API returns it
+type customerJSON struct {
+	Email            *string `json:"email"`
+	FirstName        *string `json:"first_name"`
+	LastName         *string `json:"last_name"`
+	Phone            *string `json:"phone"`
+	AcceptsMarketing *bool   `json:"accepts_marketing"`
+	// Newer API versions replace accepts_marketing with a consent record
+	EmailMarketingConsent *struct {
+		State string `json:"state"`
+	} `json:"email_marketing_consent"`
+	DefaultAddress *addressJSON `json:"default_address"`
+}
+
+type addressJSON struct {
+	FirstName    *string `json:"first_name"`
+	LastName     *string `json:"last_name"`
+	Company      *string `json:"company"`
+	Address1     *string `json:"address1"`
+	Address2     *string `json:"address2"`
+	City         *string `json:"city"`
+	ProvinceCode *string `json:"province_code"`
+	Zip          *string `json:"zip"`
+	CountryCode  *string `json:"country_code"`
+	Phone        *string `json:"phone"`
+}
+
+func parseCustomersJSON(data []byte) ([]Customer, error) {
+	items, err := decodeList[customerJSON](data, "customers", "customer")
+	if err != nil {
+		return nil, err
+	}
+	customers := make([]Customer, len(items))
+	for i, item := range items {
+		c := Customer{
+			Position:  i + 1,
+			Email:     strings.ToLower(deref(item.Email)),
+			FirstName: deref(item.FirstName),
+			LastName:  deref(item.LastName),
+			Phone:     deref(item.Phone),
+		}
+		if item.AcceptsMarketing != nil {
+			c.AcceptsMarketing = *item.AcceptsMarketing
+		} else if item.EmailMarketingConsent != nil {
+			c.AcceptsMarketing = item.EmailMarketingConsent.State == "subscribed"
+		}
+		if a := item.DefaultAddress; a != nil {
+			c.Address = completeAddress(Address{
+				FirstName:   deref(a.FirstName),
+				LastName:    deref(a.LastName),
+				Company:     deref(a.Company),
+				Address1:    deref(a.Address1),
+				Address2:    deref(a.Address2),
+				City:        deref(a.City),
+				RegionCode:  deref(a.ProvinceCode),
+				PostalCode:  deref(a.Zip),
+				CountryCode: strings.ToUpper(deref(a.CountryCode)),
+				Phone:       deref(a.Phone),
+			})
+		}
+		customers[i] = c
+	}
+	return customers, nil
+}
+
+// completeAddress returns the address when it has the street, city and
+// country an address needs, and nil otherwise; Shopify keeps partial ones
+// that terminus has no place for
+func completeAddress(a Address) *Address {
+	if a.Address1 == "" || a.City == "" || len(a.CountryCode) != 2 {
+		return nil
+	}
+	return &a
+}
@@ -0,0 +1,301 @@
+package shopify
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/dfodeker/terminus/internal/orders"
+	"github.com/dfodeker/terminus/internal/problem"
+)
+
+// Order is one Shopify order. Statuses are already mapped to terminus's.
+type Order struct {
+	// Position is the line the order starts on in a CSV, or its place in a
+	// JSON list counting from 1
+	Position int
+	// Name is what Shopify shows, such as #1001; Number is its digits
+	Name              string
+	Number            int64
+	Email             string
+	Status            string
+	FinancialStatus   string
+	FulfillmentStatus string
+	Currency          string
+	SubtotalCents     int64
+	ShippingCents     int64
+	TaxCents          int64
+	DiscountCents     int64
+	TotalCents        int64
+	Tags              []string
+	PlacedAt          time.Time
+	LineItems         []LineItem
+	Errors            []problem.FieldError
+}
+
+// LineItem is one line of an order as it was bought
+type LineItem struct {
+	Title        string
+	VariantTitle string
+	SKU          string
+	Quantity     int32
+	PriceCents   int64
+}
+
+// financialStatuses maps Shopify's payment states onto terminus's, which
+// have no partial payment or expiry
+var financialStatuses = map[string]string{
+	"":                   "pending",
+	"pending":            "pending",
+	"partially_paid":     "pending",
+	"authorized":         "authorized",
+	"paid":               "paid",
+	"partially_refunded": "partially_refunded",
+	"refunded":           "refunded",
+	"voided":             "voided",
+	"expired":            "voided",
+}
+
+// fulfillmentStatuses maps Shopify's fulfillment states onto terminus's;
+// restocked orders have nothing left fulfilled
+var fulfillmentStatuses = map[string]string{
+	"":            "unfulfilled",
+	"unfulfilled": "unfulfilled",
+	"restocked":   "unfulfilled",
+	"partial":     "partial",
+	"fulfilled":   "fulfilled",
+}
+
+// ParseOrders reads an orders_export CSV or an Admin API orders response
+func ParseOrders(format string, data []byte) ([]Order, error) {
+	if err := checkFormat(format); err != nil {
+		return nil, err
+	}
+	var out []Order
+	var err error
+	if format == FormatCSV {
+		out, err = parseOrdersCSV(data)
+	} else {
+		out, err = parseOrdersJSON(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCount(len(out)); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// orderBuilder collects an order and the problems found reading it
+type orderBuilder struct {
+	order Order
+	errs  fieldErrors
+}
+
+func (b *orderBuilder) number(name string, number int64) {
+	b.order.Name = name
+	if number > 0 {
+		b.order.Number = number
+		return
+	}
+	// Names are a store's prefix and suffix around the number, #1001 by
+	// default
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, name)
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || n <= 0 {
+		b.errs.add("name", "must contain the order number")
+		return
+	}
+	b.order.Number = n
+}
+
+func (b *orderBuilder) statuses(financial, fulfillment string, cancelled, closed bool) {
+	var ok bool
+	if b.order.FinancialStatus, ok = financialStatuses[strings.ToLower(financial)]; !ok {
+		b.errs.add("financial_status", fmt.Sprintf("%q is not a Shopify financial status", financial))
+	}
+	if b.order.FulfillmentStatus, ok = fulfillmentStatuses[strings.ToLower(fulfillment)]; !ok {
+		b.errs.add("fulfillment_status", fmt.Sprintf("%q is not a Shopify fulfillment status", fulfillment))
+	}
+	switch {
+	case cancelled:
+		b.order.Status = "cancelled"
+	case closed:
+		b.order.Status = "closed"
+	default:
+		b.order.Status = "open"
+	}
+}
+
+func (b *orderBuilder) tags(s string) {
+	tags, err := orders.NormalizeTags(strings.Split(s, ","))
+	if err != nil {
+		b.errs.add("tags", strings.TrimPrefix(err.Error(), orders.ErrInvalidTags.Error()+": "))
+		return
+	}
+	b.order.Tags = tags
+}
+
+func (b *orderBuilder) placedAt(s string) {
+	if s == "" {
+		b.errs.add("created_at", "is required")
+		return
+	}
+	t, err := parseTime(s)
+	if err != nil {
+		b.errs.add("created_at", err.Error())
+		return
+	}
+	b.order.PlacedAt = t
+}
+
+func (b *orderBuilder) lineItem(title, variantTitle, sku, quantity, price string) {
+	field := fmt.Sprintf("line_items.%d", len(b.order.LineItems))
+	item := LineItem{Title: title, VariantTitle: variantTitle, SKU: sku}
+	if title == "" {
+		b.errs.add(field+".title", "is required")
+	}
+	n, err := strconv.ParseInt(quantity, 10, 32)
+	if err != nil || n <= 0 {
+		b.errs.add(field+".quantity", "must be a whole number above zero")
+	}
+	item.Quantity = int32(n)
+	b.errs.cents(field+".price", price, &item.PriceCents)
+	b.order.LineItems = append(b.order.LineItems, item)
+}
+
+func (b *orderBuilder) build() Order {
+	o := b.order
+	if o.Currency == "" {
+		o.Currency = "USD"
+	}
+	if len(o.LineItems) == 0 {
+		b.errs.add("line_items", "must have at least one line item")
+	}
+	o.Errors = b.errs
+	return o
+}
+
+// parseOrdersCSV groups the rows of an orders export by name. The first
+// row of an order carries its totals and statuses; every row is one line
+// item.
+func parseOrdersCSV(data []byte) ([]Order, error) {
+	t, err := readTable(data, "name", "lineitem name")
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*orderBuilder
+	byName := map[string]*orderBuilder{}
+	for _, rec := range t.records {
+		name := t.get(rec, "name")
+		b, ok := byName[name]
+		if !ok {
+			b = &orderBuilder{order: Order{
+				Position: rec.line,
+				Email:    strings.ToLower(t.get(rec, "email")),
+				Currency: strings.ToUpper(t.get(rec, "currency")),
+			}}
+			b.number(name, 0)
+			b.statuses(t.get(rec, "financial status"), t.get(rec, "fulfillment status"), t.get(rec, "cancelled at") != "", false)
+			b.errs.cents("subtotal", t.get(rec, "subtotal"), &b.order.SubtotalCents)
+			b.errs.cents("shipping", t.get(rec, "shipping"), &b.order.ShippingCents)
+			b.errs.cents("taxes", t.get(rec, "taxes"), &b.order.TaxCents)
+			b.errs.cents("discount_amount", t.get(rec, "discount amount"), &b.order.DiscountCents)
+			b.errs.cents("total", t.get(rec, "total"), &b.order.TotalCents)
+			b.tags(t.get(rec, "tags"))
+			b.placedAt(t.get(rec, "created at"))
+			byName[name] = b
+			out = append(out, b)
+		}
+		b.lineItem(t.get(rec, "lineitem name"), "", t.get(rec, "lineitem sku"),
+			t.get(rec, "lineitem quantity"), t.get(rec, "lineitem price"))
+	}
+
+	result := make([]Order, len(out))
+	for i, b := range out {
+		result[i] = b.build()
+	}
+	return result, nil
+}
+
+// orderJSON is an order as the Admin API returns it
+type orderJSON struct {
+	Name              string  `json:"name"`
+	OrderNumber       int64   `json:"order_number"`
+	Email             *string `json:"email"`
+	FinancialStatus   *string `json:"financial_status"`
+	FulfillmentStatus *string `json:"fulfillment_status"`
+	Currency          string  `json:"currency"`
+	SubtotalPrice     amount  `json:"subtotal_price"`
+	TotalTax          amount  `json:"total_tax"`
+	TotalDiscounts    amount  `json:"total_discounts"`
+	TotalPrice        amount  `json:"total_price"`
+	ShippingLines     []struct {
+		Price amount `json:"price"`
+	} `json:"shipping_lines"`
+	Tags        string  `json:"tags"`
+	CreatedAt   string  `json:"created_at"`
+	ProcessedAt *string `json:"processed_at"`
+	CancelledAt *string `json:"cancelled_at"`
+	ClosedAt    *string `json:"closed_at"`
+	LineItems   []struct {
+		Title        string  `json:"title"`
+		VariantTitle *string `json:"variant_title"`
+		SKU          *string `json:"sku"`
+		Quantity     int64   `json:"quantity"`
+		Price        amount  `json:"price"`
+	} `json:"line_items"`
+}
+
+func parseOrdersJSON(data []byte) ([]Order, error) {
+	items, err := decodeList[orderJSON](data, "orders", "order")
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Order, len(items))
+	for i, item := range items {
+		out[i] = item.order(i + 1)
+	}
+	return out, nil
+}
+
+func (oj orderJSON) order(position int) Order {
+	b := &orderBuilder{order: Order{
+		Position: position,
+		Email:    strings.ToLower(deref(oj.Email)),
+		Currency: strings.ToUpper(oj.Currency),
+	}}
+	b.number(oj.Name, oj.OrderNumber)
+	b.statuses(deref(oj.FinancialStatus), deref(oj.FulfillmentStatus), oj.CancelledAt != nil, oj.ClosedAt != nil)
+	b.errs.cents("subtotal_price", string(oj.SubtotalPrice), &b.order.SubtotalCents)
+	b.errs.cents("total_tax", string(oj.TotalTax), &b.order.TaxCents)
+	b.errs.cents("total_discounts", string(oj.TotalDiscounts), &b.order.DiscountCents)
+	b.errs.cents("total_price", string(oj.TotalPrice), &b.order.TotalCents)
+	for i, line := range oj.ShippingLines {
+		var n int64
+		b.errs.cents(fmt.Sprintf("shipping_lines.%d.price", i), string(line.Price), &n)
+		b.order.ShippingCents += n
+	}
+	b.tags(oj.Tags)
+	// processed_at is when the order was placed; created_at differs for
+	// orders imported into Shopify from elsewhere
+	placed := oj.CreatedAt
+	if oj.ProcessedAt != nil && *oj.ProcessedAt != "" {
+		placed = *oj.ProcessedAt
+	}
+	b.placedAt(placed)
+	for _, line := range oj.LineItems {
+		b.lineItem(line.Title, deref(line.VariantTitle), deref(line.SKU),
+			strconv.FormatInt(line.Quantity, 10), string(line.Price))
+	}
+	return b.build()
+}
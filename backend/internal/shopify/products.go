@@ -0,0 +1,405 @@
+package shopify
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/dfodeker/terminus/internal/media"
+	"github.com/dfodeker/terminus/internal/options"
+	"github.com/dfodeker/terminus/internal/problem"
+)
+
+// Product is one Shopify product with its variants and images
+type Product struct {
+	// Position is the line the product starts on in a CSV, or its place in
+	// a JSON list counting from 1
+	Position    int
+	Handle      string
+	Title       string
+	Description string
+	Tags        string
+	// Status is active, draft or archived
+	Status           string
+	InventoryTracked bool
+	Options          []options.Option
+	Variants         []Variant
+	// Images are in display order
+	Images []Image
+	Errors []problem.FieldError
+}
+
+// Variant is one purchasable version of a product. Values holds one value
+// per option, in option order.
+type Variant struct {
+	SKU               string
+	Barcode           string
+	Values            options.Combination
+	PriceCents        int32
+	CompareAtCents    *int32
+	WeightGrams       *int32
+	InventoryQuantity int32
+	// ImageSrc is the URL of the product image shown for this variant
+	ImageSrc string
+}
+
+// Image is a product image, to be fetched from Shopify's CDN
+type Image struct {
+	Src string
+	Alt string
+}
+
+// ContentType is the image type the URL's extension names, or false for
+// one product media doesn't accept
+func (img Image) ContentType() (string, bool) {
+	u, err := url.Parse(img.Src)
+	if err != nil {
+		return "", false
+	}
+	return media.TypeByExtension(path.Ext(u.Path))
+}
+
+// Shopify gives a product without options a single option with one value
+const (
+	defaultOptionName  = "Title"
+	defaultOptionValue = "Default Title"
+)
+
+// ParseProducts reads a products_export CSV or an Admin API products
+// response
+func ParseProducts(format string, data []byte) ([]Product, error) {
+	if err := checkFormat(format); err != nil {
+		return nil, err
+	}
+	var products []Product
+	var err error
+	if format == FormatCSV {
+		products, err = parseProductsCSV(data)
+	} else {
+		products, err = parseProductsJSON(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCount(len(products)); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// parseProductsCSV groups the rows of a products export by handle. The
+// first row of a product carries its details and first variant; later
+// rows add a variant, an image, or both.
+func parseProductsCSV(data []byte) ([]Product, error) {
+	t, err := readTable(data, "handle")
+	if err != nil {
+		return nil, err
+	}
+
+	var products []*productBuilder
+	byHandle := map[string]*productBuilder{}
+	for _, rec := range t.records {
+		handle := t.get(rec, "handle")
+		b, ok := byHandle[handle]
+		if !ok {
+			b = newProductBuilder(t, rec)
+			byHandle[handle] = b
+			products = append(products, b)
+		}
+		b.add(t, rec)
+	}
+
+	out := make([]Product, len(products))
+	for i, b := range products {
+		out[i] = b.build()
+	}
+	return out, nil
+}
+
+type productBuilder struct {
+	product Product
+	names   []string
+	tracked bool
+	images  []sortedImage
+	errs    fieldErrors
+}
+
+type sortedImage struct {
+	Image
+	position int64
+}
+
+func newProductBuilder(t *table, rec record) *productBuilder {
+	b := &productBuilder{product: Product{
+		Position:    rec.line,
+		Handle:      t.get(rec, "handle"),
+		Title:       t.get(rec, "title"),
+		Description: t.get(rec, "body (html)"),
+		Tags:        t.get(rec, "tags"),
+	}}
+	if b.product.Handle == "" {
+		b.errs.add("handle", "is required")
+	}
+
+	// Exports from before product statuses only say whether it's published
+	b.product.Status = strings.ToLower(t.get(rec, "status"))
+	if !t.has("status") {
+		b.product.Status = "draft"
+		if parseBool(t.get(rec, "published")) {
+			b.product.Status = "active"
+		}
+	}
+
+	for n := 1; n <= options.MaxOptions; n++ {
+		name := t.get(rec, fmt.Sprintf("option%d name", n))
+		if name == "" {
+			break
+		}
+		b.names = append(b.names, name)
+	}
+	b.product.Options = make([]options.Option, len(b.names))
+	for i, name := range b.names {
+		b.product.Options[i].Name = name
+	}
+	// Without the column every variant is taken as tracked
+	b.tracked = !t.has("variant inventory tracker")
+	return b
+}
+
+// add takes the variant and image a row describes, if any
+func (b *productBuilder) add(t *table, rec record) {
+	if src := t.get(rec, "image src"); src != "" && !b.hasImage(src) {
+		img := sortedImage{Image: Image{Src: src, Alt: t.get(rec, "image alt text")}, position: math.MaxInt64}
+		b.errs.int(fmt.Sprintf("images.%d.position", len(b.images)), t.get(rec, "image position"), &img.position)
+		b.images = append(b.images, img)
+	}
+
+	values := make([]string, len(b.names))
+	hasValue := false
+	for i := range b.names {
+		values[i] = t.get(rec, fmt.Sprintf("option%d value", i+1))
+		hasValue = hasValue || values[i] != ""
+	}
+	if !hasValue && t.get(rec, "variant sku") == "" && t.get(rec, "variant price") == "" {
+		// An image-only row
+		return
+	}
+
+	field := fmt.Sprintf("variants.%d", len(b.product.Variants))
+	v := Variant{
+		SKU:      t.get(rec, "variant sku"),
+		Barcode:  t.get(rec, "variant barcode"),
+		Values:   values,
+		ImageSrc: t.get(rec, "variant image"),
+	}
+	var price, compareAt, grams, qty int64
+	b.errs.cents(field+".price", t.get(rec, "variant price"), &price)
+	v.PriceCents = b.int32(field+".price", price)
+	if s := t.get(rec, "variant compare at price"); s != "" {
+		b.errs.cents(field+".compare_at_price", s, &compareAt)
+		if compareAt > 0 {
+			c := b.int32(field+".compare_at_price", compareAt)
+			v.CompareAtCents = &c
+		}
+	}
+	if s := t.get(rec, "variant grams"); s != "" {
+		b.errs.int(field+".grams", s, &grams)
+		g := b.int32(field+".grams", grams)
+		v.WeightGrams = &g
+	}
+	b.errs.int(field+".inventory_quantity", t.get(rec, "variant inventory qty"), &qty)
+	v.InventoryQuantity = max(b.int32(field+".inventory_quantity", qty), 0)
+	if strings.EqualFold(t.get(rec, "variant inventory tracker"), "shopify") {
+		b.tracked = true
+	}
+	b.addVariant(field, v)
+}
+
+func (b *productBuilder) hasImage(src string) bool {
+	return slices.ContainsFunc(b.images, func(img sortedImage) bool { return img.Src == src })
+}
+
+// addVariant records a variant and the option values it uses
+func (b *productBuilder) addVariant(field string, v Variant) {
+	if len(v.Values) != len(b.names) {
+		b.errs.add(field+".options", fmt.Sprintf("must have a value for each of the %d options", len(b.names)))
+		return
+	}
+	for i, value := range v.Values {
+		if value == "" {
+			b.errs.add(fmt.Sprintf("%s.option%d", field, i+1), "is required")
+			continue
+		}
+		opt := &b.product.Options[i]
+		if !slices.Contains(opt.Values, value) {
+			opt.Values = append(opt.Values, value)
+		}
+	}
+	b.product.Variants = append(b.product.Variants, v)
+}
+
+func (b *productBuilder) int32(field string, n int64) int32 {
+	if n > math.MaxInt32 || n < math.MinInt32 {
+		b.errs.add(field, "is too large")
+		return 0
+	}
+	return int32(n)
+}
+
+func (b *productBuilder) build() Product {
+	p := b.product
+	p.InventoryTracked = b.tracked
+	// Shopify's statuses are the same three as terminus's
+	if !slices.Contains([]string{"active", "draft", "archived"}, p.Status) {
+		b.errs.add("status", "must be active, draft or archived")
+	}
+
+	// A lone Title: Default Title option is how Shopify writes a product
+	// with no options
+	if len(p.Options) == 1 && p.Options[0].Name == defaultOptionName &&
+		slices.Equal(p.Options[0].Values, []string{defaultOptionValue}) {
+		p.Options = nil
+		for i := range p.Variants {
+			p.Variants[i].Values = nil
+		}
+	}
+
+	slices.SortStableFunc(b.images, func(a, b sortedImage) int {
+		return cmp.Compare(a.position, b.position)
+	})
+	for _, img := range b.images {
+		p.Images = append(p.Images, img.Image)
+	}
+	p.Errors = b.errs
+	return p
+}
+
+// productJSON is a product as the Admin API returns it
+type productJSON struct {
+	Handle      string  `json:"handle"`
+	Title       string  `json:"title"`
+	BodyHTML    *string `json:"body_html"`
+	Tags        string  `json:"tags"`
+	Status      string  `json:"status"`
+	PublishedAt *string `json:"published_at"`
+	Options     []struct {
+		Name   string   `json:"name"`
+		Values []string `json:"values"`
+	} `json:"options"`
+	Variants []struct {
+		SKU                 *string `json:"sku"`
+		Barcode             *string `json:"barcode"`
+		Price               amount  `json:"price"`
+		CompareAtPrice      amount  `json:"compare_at_price"`
+		Option1             *string `json:"option1"`
+		Option2             *string `json:"option2"`
+		Option3             *string `json:"option3"`
+		Grams               *int64  `json:"grams"`
+		InventoryQuantity   int64   `json:"inventory_quantity"`
+		InventoryManagement *string `json:"inventory_management"`
+		ImageID             *int64  `json:"image_id"`
+	} `json:"variants"`
+	Images []struct {
+		ID       int64   `json:"id"`
+		Src      string  `json:"src"`
+		Alt      *string `json:"alt"`
+		Position int64   `json:"position"`
+	} `json:"images"`
+}
+
+func parseProductsJSON(data []byte) ([]Product, error) {
+	items, err := decodeList[productJSON](data, "products", "product")
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Product, len(items))
+	for i, item := range items {
+		out[i] = item.product(i + 1)
+	}
+	return out, nil
+}
+
+func (pj productJSON) product(position int) Product {
+	b := &productBuilder{product: Product{
+		Position:    position,
+		Handle:      pj.Handle,
+		Title:       pj.Title,
+		Description: deref(pj.BodyHTML),
+		Tags:        pj.Tags,
+		Status:      strings.ToLower(pj.Status),
+	}}
+	if b.product.Handle == "" {
+		b.errs.add("handle", "is required")
+	}
+	if b.product.Status == "" {
+		b.product.Status = "draft"
+		if pj.PublishedAt != nil {
+			b.product.Status = "active"
+		}
+	}
+	for _, opt := range pj.Options {
+		b.names = append(b.names, opt.Name)
+		b.product.Options = append(b.product.Options, options.Option{Name: opt.Name, Values: slices.Clone(opt.Values)})
+	}
+
+	srcByID := map[int64]string{}
+	for i, img := range pj.Images {
+		srcByID[img.ID] = img.Src
+		position := img.Position
+		if position == 0 {
+			position = math.MaxInt64
+		}
+		if img.Src == "" {
+			b.errs.add(fmt.Sprintf("images.%d.src", i), "is required")
+			continue
+		}
+		if !b.hasImage(img.Src) {
+			b.images = append(b.images, sortedImage{Image: Image{Src: img.Src, Alt: deref(img.Alt)}, position: position})
+		}
+	}
+
+	for i, vj := range pj.Variants {
+		field := fmt.Sprintf("variants.%d", i)
+		v := Variant{SKU: deref(vj.SKU), Barcode: deref(vj.Barcode)}
+		for _, value := range []*string{vj.Option1, vj.Option2, vj.Option3}[:min(len(b.names), options.MaxOptions)] {
+			v.Values = append(v.Values, deref(value))
+		}
+		var price, compareAt int64
+		b.errs.cents(field+".price", string(vj.Price), &price)
+		v.PriceCents = b.int32(field+".price", price)
+		if vj.CompareAtPrice != "" {
+			b.errs.cents(field+".compare_at_price", string(vj.CompareAtPrice), &compareAt)
+			if compareAt > 0 {
+				c := b.int32(field+".compare_at_price", compareAt)
+				v.CompareAtCents = &c
+			}
+		}
+		if vj.Grams != nil {
+			g := b.int32(field+".grams", *vj.Grams)
+			v.WeightGrams = &g
+		}
+		v.InventoryQuantity = max(b.int32(field+".inventory_quantity", vj.InventoryQuantity), 0)
+		if vj.InventoryManagement != nil && *vj.InventoryManagement != "" {
+			b.tracked = true
+		}
+		if vj.ImageID != nil {
+			v.ImageSrc = srcByID[*vj.ImageID]
+		}
+		b.addVariant(field, v)
+	}
+	if len(b.names) > options.MaxOptions {
+		b.errs.add("options", fmt.Sprintf("must have at most %d options", options.MaxOptions))
+	}
+	return b.build()
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return strings.TrimSpace(*s)
+}
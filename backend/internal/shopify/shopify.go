@@ -0,0 +1,349 @@
+// Package shopify reads the files Shopify exports, products, customers and
+// orders as either admin CSV or Admin API JSON, into types that map onto
+// terminus's own. Parsing never touches the database; the worker applies
+// what it returns.
+//
+// Problems confined to one product, customer or order, such as a price
+// that isn't an amount, are reported on that item so the rest can still be
+// imported. An error from a Parse function means the file as a whole can't
+// be read.
+package shopify
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/google/uuid"
+)
+
+// Limits on an uploaded file
+const (
+	MaxBytes = 25 << 20
+	MaxItems = 5000
+)
+
+// Entities a file can hold
+const (
+	EntityProducts  = "products"
+	EntityCustomers = "customers"
+	EntityOrders    = "orders"
+)
+
+// Formats a file can be in
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// Import statuses
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+// Item outcomes. Orders already in the store are skipped rather than
+// updated, since an order is a record of what happened.
+const (
+	ItemCreated = "created"
+	ItemUpdated = "updated"
+	ItemSkipped = "skipped"
+	ItemFailed  = "failed"
+)
+
+// ErrInvalidFile wraps every problem that makes a whole file unusable
+var ErrInvalidFile = errors.New("invalid Shopify file")
+
+// Args is the job that applies one uploaded import
+type Args struct {
+	ImportID uuid.UUID `json:"import_id"`
+}
+
+func (Args) Kind() string { return "shopify.import" }
+
+// DetectFormat works out whether an upload is CSV or JSON from its file
+// name, then its content type, then its first character
+func DetectFormat(filename, contentType string, data []byte) string {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".json":
+		return FormatJSON
+	case ".csv":
+		return FormatCSV
+	}
+	if strings.Contains(contentType, "json") {
+		return FormatJSON
+	}
+	if strings.Contains(contentType, "csv") {
+		return FormatCSV
+	}
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\ufeff")), " \t\r\n")
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return FormatJSON
+	}
+	return FormatCSV
+}
+
+// Count parses a file only to check it and count its items
+func Count(entity, format string, data []byte) (int, error) {
+	switch entity {
+	case EntityProducts:
+		items, err := ParseProducts(format, data)
+		return len(items), err
+	case EntityCustomers:
+		items, err := ParseCustomers(format, data)
+		return len(items), err
+	case EntityOrders:
+		items, err := ParseOrders(format, data)
+		return len(items), err
+	}
+	return 0, fmt.Errorf("%w: unknown entity %q", ErrInvalidFile, entity)
+}
+
+// table is a CSV file read whole, with its columns looked up by name
+type table struct {
+	columns map[string]int
+	records []record
+}
+
+// record is one CSV row and the line it starts on
+type record struct {
+	line   int
+	fields []string
+}
+
+func readTable(data []byte, required ...string) (*table, error) {
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: the file is empty", ErrInvalidFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+	t := &table{columns: make(map[string]int, len(header))}
+	for i, name := range header {
+		if i == 0 {
+			// Excel and others lead with a byte order mark
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		t.columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range required {
+		if _, ok := t.columns[name]; !ok {
+			return nil, fmt.Errorf("%w: the header has no %q column; is this a Shopify export?", ErrInvalidFile, name)
+		}
+	}
+
+	for {
+		fields, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+		}
+		if blank(fields) {
+			continue
+		}
+		line, _ := cr.FieldPos(0)
+		t.records = append(t.records, record{line: line, fields: fields})
+	}
+	return t, nil
+}
+
+// get returns the trimmed cell of the first named column the file has, or
+// "" when it has none of them
+func (t *table) get(rec record, names ...string) string {
+	for _, name := range names {
+		if i, ok := t.columns[name]; ok {
+			if i < len(rec.fields) {
+				return strings.TrimSpace(rec.fields[i])
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+func (t *table) has(name string) bool {
+	_, ok := t.columns[name]
+	return ok
+}
+
+func blank(fields []string) bool {
+	for _, cell := range fields {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// decodeList reads a JSON file as Admin API responses have it: an object
+// holding a list under plural, an object holding one item under singular,
+// or a bare list
+func decodeList[T any](data []byte, plural, singular string) ([]T, error) {
+	data = bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\ufeff")))
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: the file is empty", ErrInvalidFile)
+	}
+	var items []T
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+		}
+		return items, nil
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+	if raw, ok := doc[plural]; ok {
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidFile, plural, err)
+		}
+		return items, nil
+	}
+	if raw, ok := doc[singular]; ok {
+		var item T
+		if err := json.Unmarshal(raw, &item); err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidFile, singular, err)
+		}
+		return []T{item}, nil
+	}
+	return nil, fmt.Errorf("%w: expected a %q list", ErrInvalidFile, plural)
+}
+
+// checkCount rejects files with no items or too many
+func checkCount(n int) error {
+	if n == 0 {
+		return fmt.Errorf("%w: the file has nothing to import", ErrInvalidFile)
+	}
+	if n > MaxItems {
+		return fmt.Errorf("%w: more than %d items", ErrInvalidFile, MaxItems)
+	}
+	return nil
+}
+
+func checkFormat(format string) error {
+	if format != FormatCSV && format != FormatJSON {
+		return fmt.Errorf("%w: format must be %s or %s", ErrInvalidFile, FormatCSV, FormatJSON)
+	}
+	return nil
+}
+
+// amount is a money value, which the Admin API writes as a string such as
+// "19.99" and some tools write as a number
+type amount string
+
+func (a *amount) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*a = ""
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*a = amount(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("amount must be a string or number: %w", err)
+	}
+	*a = amount(n)
+	return nil
+}
+
+// cents turns an amount such as "19.99" into 1999. Blank is zero.
+func cents(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return 0, errAmount
+	}
+	// Exports pad to more places than currencies use; the extra are zeros
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > 2 || strings.ContainsAny(s, "+-") {
+		return 0, errAmount
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	for len(frac) < 2 {
+		frac += "0"
+	}
+	n, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil || n < 0 {
+		return 0, errAmount
+	}
+	return n, nil
+}
+
+var errAmount = errors.New("must be an amount such as 19.99")
+
+// csvTimeLayout is how admin CSV exports write times
+const csvTimeLayout = "2006-01-02 15:04:05 -0700"
+
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, csvTimeLayout} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("must be a time such as 2024-01-15 10:30:00 -0500")
+}
+
+// parseBool reads the TRUE/FALSE, yes/no of admin exports
+func parseBool(s string) bool {
+	switch strings.ToLower(s) {
+	case "true", "yes", "y", "1":
+		return true
+	}
+	return false
+}
+
+// fieldErrors collects problems with one item
+type fieldErrors []problem.FieldError
+
+func (e *fieldErrors) add(field, message string) {
+	*e = append(*e, problem.FieldError{Field: field, Code: validate.CodeInvalid, Message: message})
+}
+
+// cents parses an amount into dst, noting the field when it isn't one
+func (e *fieldErrors) cents(field, s string, dst *int64) {
+	n, err := cents(s)
+	if err != nil {
+		e.add(field, err.Error())
+		return
+	}
+	*dst = n
+}
+
+func (e *fieldErrors) int(field, s string, dst *int64) {
+	if s == "" {
+		return
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		e.add(field, "must be a whole number")
+		return
+	}
+	*dst = n
+}
@@ -0,0 +1,344 @@
+package shopify
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/options"
+	"github.com/dfodeker/terminus/internal/problem"
+)
+
+func int32p(n int32) *int32 { return &n }
+
+func fields(errs []problem.FieldError) []string {
+	var out []string
+	for _, e := range errs {
+		out = append(out, e.Field)
+	}
+	return out
+}
+
+const productsCSV = "\ufeffHandle,Title,Body (HTML),Tags,Published,Option1 Name,Option1 Value,Option2 Name,Option2 Value,Variant SKU,Variant Grams,Variant Inventory Tracker,Variant Inventory Qty,Variant Price,Variant Compare At Price,Variant Barcode,Image Src,Image Position,Image Alt Text,Variant Image,Status\n" +
+	"tee,Tee,<p>Soft</p>,\"summer, cotton\",TRUE,Size,S,Color,Black,TEE-S-BLK,200,shopify,5,19.99,25.00,111,https://cdn.shopify.com/tee-back.png?v=1,2,Back,https://cdn.shopify.com/tee-front.jpg?v=1,active\n" +
+	"tee,,,,,,M,,Black,TEE-M-BLK,210,shopify,-2,19.99,,,https://cdn.shopify.com/tee-front.jpg?v=1,1,Front,,\n" +
+	"tee,,,,,,,,,,,,,,,,https://cdn.shopify.com/tee-side.webp,3,,,\n" +
+	"mug,Mug,,,FALSE,Title,Default Title,,,MUG,,,0,8,,,,,,,draft\n" +
+	"bad,Bad,,,,Title,Default Title,,,BAD,,,,ten,,,,,,,sold\n"
+
+func TestParseProductsCSV(t *testing.T) {
+	got, err := ParseProducts(FormatCSV, []byte(productsCSV))
+	if err != nil {
+		t.Fatalf("ParseProducts() error = %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d products, want 3", len(got))
+	}
+
+	tee := got[0]
+	wantTee := Product{
+		Position:         2,
+		Handle:           "tee",
+		Title:            "Tee",
+		Description:      "<p>Soft</p>",
+		Tags:             "summer, cotton",
+		Status:           "active",
+		InventoryTracked: true,
+		Options: []options.Option{
+			{Name: "Size", Values: []string{"S", "M"}},
+			{Name: "Color", Values: []string{"Black"}},
+		},
+		Variants: []Variant{
+			{SKU: "TEE-S-BLK", Barcode: "111", Values: options.Combination{"S", "Black"}, PriceCents: 1999, CompareAtCents: int32p(2500), WeightGrams: int32p(200), InventoryQuantity: 5, ImageSrc: "https://cdn.shopify.com/tee-front.jpg?v=1"},
+			{SKU: "TEE-M-BLK", Values: options.Combination{"M", "Black"}, PriceCents: 1999, WeightGrams: int32p(210)},
+		},
+		Images: []Image{
+			{Src: "https://cdn.shopify.com/tee-front.jpg?v=1", Alt: "Front"},
+			{Src: "https://cdn.shopify.com/tee-back.png?v=1", Alt: "Back"},
+			{Src: "https://cdn.shopify.com/tee-side.webp"},
+		},
+	}
+	if !reflect.DeepEqual(tee, wantTee) {
+		t.Errorf("tee =\n%+v\nwant\n%+v", tee, wantTee)
+	}
+
+	mug := got[1]
+	if mug.Options != nil || len(mug.Variants) != 1 || mug.Variants[0].Values != nil {
+		t.Errorf("mug should have no options and one plain variant, got %+v", mug)
+	}
+	if mug.Status != "draft" || mug.InventoryTracked || mug.Variants[0].PriceCents != 800 {
+		t.Errorf("mug = %+v", mug)
+	}
+
+	if want := []string{"variants.0.price", "status"}; !reflect.DeepEqual(fields(got[2].Errors), want) {
+		t.Errorf("bad product errors = %v, want %v", fields(got[2].Errors), want)
+	}
+}
+
+func TestParseProductsJSON(t *testing.T) {
+	doc := `{"products":[{
+		"handle":"tee","title":"Tee","body_html":null,"tags":"summer","status":"ACTIVE",
+		"options":[{"name":"Size","values":["M","S"]}],
+		"variants":[
+			{"sku":"TEE-S","price":"19.99","compare_at_price":null,"option1":"S","grams":200,"inventory_quantity":3,"inventory_management":"shopify","image_id":2},
+			{"sku":"TEE-M","price":21,"option1":"M","inventory_quantity":0,"inventory_management":null}
+		],
+		"images":[{"id":2,"src":"https://cdn/b.jpg","position":2},{"id":1,"src":"https://cdn/a.jpg","position":1,"alt":"A"}]
+	}]}`
+
+	got, err := ParseProducts(FormatJSON, []byte(doc))
+	if err != nil {
+		t.Fatalf("ParseProducts() error = %v", err)
+	}
+	want := []Product{{
+		Position:         1,
+		Handle:           "tee",
+		Title:            "Tee",
+		Tags:             "summer",
+		Status:           "active",
+		InventoryTracked: true,
+		Options:          []options.Option{{Name: "Size", Values: []string{"M", "S"}}},
+		Variants: []Variant{
+			{SKU: "TEE-S", Values: options.Combination{"S"}, PriceCents: 1999, WeightGrams: int32p(200), InventoryQuantity: 3, ImageSrc: "https://cdn/b.jpg"},
+			{SKU: "TEE-M", Values: options.Combination{"M"}, PriceCents: 2100},
+		},
+		Images: []Image{{Src: "https://cdn/a.jpg", Alt: "A"}, {Src: "https://cdn/b.jpg"}},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseProducts() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// A single product response is accepted too
+	single, err := ParseProducts(FormatJSON, []byte(`{"product":{"handle":"mug","title":"Mug","published_at":"2024-01-01T00:00:00Z"}}`))
+	if err != nil || len(single) != 1 || single[0].Status != "active" {
+		t.Errorf("ParseProducts(single) = %+v, %v", single, err)
+	}
+}
+
+func TestParseInvalidFiles(t *testing.T) {
+	tests := []struct {
+		name   string
+		entity string
+		format string
+		data   string
+	}{
+		{name: "empty csv", entity: EntityProducts, format: FormatCSV, data: ""},
+		{name: "not a products export", entity: EntityProducts, format: FormatCSV, data: "name,price\nTee,10\n"},
+		{name: "header only", entity: EntityCustomers, format: FormatCSV, data: "Email\n"},
+		{name: "not json", entity: EntityOrders, format: FormatJSON, data: "{"},
+		{name: "wrong list", entity: EntityOrders, format: FormatJSON, data: `{"products":[]}`},
+		{name: "unknown format", entity: EntityOrders, format: "xml", data: "<orders/>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Count(tt.entity, tt.format, []byte(tt.data)); !errors.Is(err, ErrInvalidFile) {
+				t.Errorf("Count() error = %v, want ErrInvalidFile", err)
+			}
+		})
+	}
+}
+
+func TestParseCustomers(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		data   string
+		want   []Customer
+	}{
+		{
+			name:   "current csv",
+			format: FormatCSV,
+			data: "First Name,Last Name,Email,Accepts Email Marketing,Default Address Address1,Default Address City,Default Address Province Code,Default Address Country Code,Default Address Zip,Phone\n" +
+				"Ada,Lovelace,ADA@example.com,yes,1 Main St,Springfield,IL,us,62701,+15550100\n" +
+				"No,Address,no@example.com,no,,,,,,\n",
+			want: []Customer{
+				{Position: 2, Email: "ada@example.com", FirstName: "Ada", LastName: "Lovelace", Phone: "+15550100", AcceptsMarketing: true,
+					Address: &Address{FirstName: "Ada", LastName: "Lovelace", Address1: "1 Main St", City: "Springfield", RegionCode: "IL", PostalCode: "62701", CountryCode: "US"}},
+				{Position: 3, Email: "no@example.com", FirstName: "No", LastName: "Address"},
+			},
+		},
+		{
+			name:   "older csv",
+			format: FormatCSV,
+			data:   "Email,Accepts Marketing,Address1,City,Country Code\nbo@example.com,TRUE,2 Side St,Leeds,GB\n",
+			want: []Customer{
+				{Position: 2, Email: "bo@example.com", AcceptsMarketing: true, Address: &Address{Address1: "2 Side St", City: "Leeds", CountryCode: "GB"}},
+			},
+		},
+		{
+			name:   "json",
+			format: FormatJSON,
+			data:   `{"customers":[{"email":"cy@example.com","first_name":"Cy","email_marketing_consent":{"state":"subscribed"},"default_address":{"address1":"3 Road","city":"Paris","country_code":"FR","zip":"75001"}},{"email":null,"phone":"+15550199"}]}`,
+			want: []Customer{
+				{Position: 1, Email: "cy@example.com", FirstName: "Cy", AcceptsMarketing: true, Address: &Address{Address1: "3 Road", City: "Paris", PostalCode: "75001", CountryCode: "FR"}},
+				{Position: 2, Phone: "+15550199", Errors: []problem.FieldError{{Field: "email", Code: "invalid", Message: "is required"}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCustomers(tt.format, []byte(tt.data))
+			if err != nil {
+				t.Fatalf("ParseCustomers() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCustomers() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOrders(t *testing.T) {
+	placed := time.Date(2024, 1, 15, 10, 30, 0, 0, time.FixedZone("", -5*3600))
+
+	t.Run("csv", func(t *testing.T) {
+		data := "Name,Email,Financial Status,Fulfillment Status,Currency,Subtotal,Shipping,Taxes,Total,Discount Amount,Created at,Lineitem quantity,Lineitem name,Lineitem price,Lineitem sku,Cancelled at,Tags\n" +
+			"#1001,A@example.com,paid,fulfilled,usd,30.00,5.00,2.40,32.40,5.00,2024-01-15 10:30:00 -0500,1,Tee - M / Black,20.00,TEE-M-BLK,,\"VIP, Rush\"\n" +
+			"#1001,,,,,,,,,,,2,Sticker,5.00,,,\n" +
+			"#1002,b@example.com,expired,,EUR,0,0,0,0,0,2024-01-15 10:30:00 -0500,1,Gift,0,,2024-01-16 09:00:00 -0500,\n" +
+			"#1003,c@example.com,owed,,USD,abc,0,0,0,0,,0,,0,,,\n"
+
+		got, err := ParseOrders(FormatCSV, []byte(data))
+		if err != nil {
+			t.Fatalf("ParseOrders() error = %v", err)
+		}
+		if len(got) != 3 {
+			t.Fatalf("got %d orders, want 3", len(got))
+		}
+		want := Order{
+			Position: 2, Name: "#1001", Number: 1001, Email: "a@example.com",
+			Status: "open", FinancialStatus: "paid", FulfillmentStatus: "fulfilled", Currency: "USD",
+			SubtotalCents: 3000, ShippingCents: 500, TaxCents: 240, DiscountCents: 500, TotalCents: 3240,
+			Tags: []string{"vip", "rush"}, PlacedAt: placed,
+			LineItems: []LineItem{
+				{Title: "Tee - M / Black", SKU: "TEE-M-BLK", Quantity: 1, PriceCents: 2000},
+				{Title: "Sticker", Quantity: 2, PriceCents: 500},
+			},
+		}
+		if !reflect.DeepEqual(got[0], want) {
+			t.Errorf("order =\n%+v\nwant\n%+v", got[0], want)
+		}
+		if got[1].Status != "cancelled" || got[1].FinancialStatus != "voided" || got[1].FulfillmentStatus != "unfulfilled" {
+			t.Errorf("cancelled order = %+v", got[1])
+		}
+		wantErrs := []string{"financial_status", "subtotal", "created_at", "line_items.0.title", "line_items.0.quantity"}
+		if !reflect.DeepEqual(fields(got[2].Errors), wantErrs) {
+			t.Errorf("bad order errors = %v, want %v", fields(got[2].Errors), wantErrs)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		data := `{"orders":[{"name":"#2001","order_number":2001,"email":"d@example.com","financial_status":"partially_refunded",
+			"fulfillment_status":null,"currency":"CAD","subtotal_price":"10.00","total_tax":"1.30","total_discounts":"0.00",
+			"total_price":"16.30","shipping_lines":[{"price":"5.00"}],"tags":"","created_at":"2024-02-01T00:00:00Z",
+			"processed_at":"2024-01-15T10:30:00-05:00","closed_at":"2024-03-01T00:00:00Z",
+			"line_items":[{"title":"Tee","variant_title":"M","sku":"TEE-M","quantity":1,"price":"10.00"}]}]}`
+
+		got, err := ParseOrders(FormatJSON, []byte(data))
+		if err != nil {
+			t.Fatalf("ParseOrders() error = %v", err)
+		}
+		want := []Order{{
+			Position: 1, Name: "#2001", Number: 2001, Email: "d@example.com",
+			Status: "closed", FinancialStatus: "partially_refunded", FulfillmentStatus: "unfulfilled", Currency: "CAD",
+			SubtotalCents: 1000, ShippingCents: 500, TaxCents: 130, TotalCents: 1630,
+			Tags: []string{}, PlacedAt: placed,
+			LineItems: []LineItem{{Title: "Tee", VariantTitle: "M", SKU: "TEE-M", Quantity: 1, PriceCents: 1000}},
+		}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ParseOrders() =\n%+v\nwant\n%+v", got, want)
+		}
+	})
+}
+
+func TestCents(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{in: "19.99", want: 1999},
+		{in: "19.9", want: 1990},
+		{in: "20", want: 2000},
+		{in: ".5", want: 50},
+		{in: "3.500", want: 350},
+		{in: "", want: 0},
+		{in: "1.999", wantErr: true},
+		{in: "-1.00", wantErr: true},
+		{in: "ten", wantErr: true},
+		{in: ".", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := cents(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("cents(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("cents(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name        string
+		filename    string
+		contentType string
+		data        string
+		want        string
+	}{
+		{name: "json extension", filename: "products.JSON", data: "Handle", want: FormatJSON},
+		{name: "csv extension", filename: "products_export_1.csv", data: "{", want: FormatCSV},
+		{name: "content type", contentType: "application/json", want: FormatJSON},
+		{name: "sniffed object", data: "\ufeff\n  {\"products\":[]}", want: FormatJSON},
+		{name: "sniffed list", data: "[]", want: FormatJSON},
+		{name: "csv by default", data: "Handle,Title", want: FormatCSV},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFormat(tt.filename, tt.contentType, []byte(tt.data)); got != tt.want {
+				t.Errorf("DetectFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImageContentType(t *testing.T) {
+	tests := []struct {
+		src    string
+		want   string
+		wantOK bool
+	}{
+		{src: "https://cdn.shopify.com/s/files/1/tee.JPEG?v=1700000000", want: "image/jpeg", wantOK: true},
+		{src: "https://cdn.shopify.com/tee.webp", want: "image/webp", wantOK: true},
+		{src: "https://cdn.shopify.com/tee.svg", wantOK: false},
+		{src: "https://cdn.shopify.com/tee", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			got, ok := Image{Src: tt.src}.ContentType()
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ContentType() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestCountMatchesParse(t *testing.T) {
+	n, err := Count(EntityProducts, FormatCSV, []byte(productsCSV))
+	if err != nil || n != 3 {
+		t.Errorf("Count() = %d, %v, want 3", n, err)
+	}
+	if _, err := Count("collections", FormatCSV, []byte(productsCSV)); err == nil || !strings.Contains(err.Error(), "unknown entity") {
+		t.Errorf("Count(collections) error = %v", err)
+	}
+}
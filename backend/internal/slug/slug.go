@@ -0,0 +1,72 @@
+// Package slug turns names into handles: the lowercase, dash-separated,
+// URL-safe identifiers products and stores are addressed by, such as
+// summer-sale-2025 for "Summer Sale 2025!"
+package slug
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Make returns the handle for name, at most max characters long. Accents
+// are dropped, so Café becomes cafe; any other run of characters that
+// aren't ASCII letters or digits becomes one dash. Make returns "" when
+// name has nothing usable.
+func Make(name string, max int) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range norm.NFKD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Combining accent left over from decomposing a letter
+			continue
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+		case r >= 'A' && r <= 'Z':
+			r = unicode.ToLower(r)
+		default:
+			dash = b.Len() > 0
+			continue
+		}
+		if dash {
+			b.WriteByte('-')
+			dash = false
+		}
+		b.WriteRune(r)
+	}
+	return trim(b.String(), max)
+}
+
+// WithSuffix returns handle with -n appended, shortening handle if need be
+// so the result is at most max characters long
+func WithSuffix(handle string, n, max int) string {
+	suffix := "-" + strconv.Itoa(n)
+	return trim(handle, max-len(suffix)) + suffix
+}
+
+// Next returns base if it isn't taken, or else the first of base-2,
+// base-3, ... that isn't
+func Next(base string, max int, taken func(string) bool) string {
+	if !taken(base) {
+		return base
+	}
+	for n := 2; ; n++ {
+		if h := WithSuffix(base, n, max); !taken(h) {
+			return h
+		}
+	}
+}
+
+// trim cuts a handle to max characters without leaving a dash at the end.
+// Handles are ASCII, so bytes and characters agree.
+func trim(handle string, max int) string {
+	if max < 0 {
+		max = 0
+	}
+	if len(handle) > max {
+		handle = handle[:max]
+	}
+	return strings.TrimRight(handle, "-")
+}
@@ -0,0 +1,56 @@
+package slug
+
+import "testing"
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		max  int
+		want string
+	}{
+		{name: "words and punctuation", in: "Summer Sale 2025!", max: 100, want: "summer-sale-2025"},
+		{name: "accents", in: "Crème Brûlée Café", max: 100, want: "creme-brulee-cafe"},
+		{name: "runs collapse", in: "  --Linen__shirt  (blue) ", max: 100, want: "linen-shirt-blue"},
+		{name: "nothing usable", in: "日本 !!", max: 100, want: ""},
+		{name: "cut without trailing dash", in: "linen shirt", max: 6, want: "linen"},
+		{name: "compatibility forms", in: "ﬁne Ｔea", max: 100, want: "fine-tea"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Make(tt.in, tt.max); got != tt.want {
+				t.Errorf("Make(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		name  string
+		base  string
+		max   int
+		taken []string
+		want  string
+	}{
+		{name: "free", base: "shirt", max: 100, want: "shirt"},
+		{name: "taken", base: "shirt", max: 100, taken: []string{"shirt"}, want: "shirt-2"},
+		{name: "skips taken suffixes", base: "shirt", max: 100, taken: []string{"shirt", "shirt-2", "shirt-3"}, want: "shirt-4"},
+		{name: "shortens to fit", base: "linen-shirt", max: 11, taken: []string{"linen-shirt"}, want: "linen-shi-2"},
+		{name: "drops a dash left at the cut", base: "linen-shirt", max: 8, taken: []string{"linen-shirt"}, want: "linen-2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taken := map[string]bool{}
+			for _, h := range tt.taken {
+				taken[h] = true
+			}
+			got := Next(tt.base, tt.max, func(h string) bool { return taken[h] })
+			if got != tt.want {
+				t.Errorf("Next(%q) = %q, want %q", tt.base, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+// Package sso holds what tenant single sign-on needs beyond the protocols
+// themselves: reading who signed in out of what a tenant's identity
+// provider sends, and proving the tenant owns the email domains its
+// provider vouches for.
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/dfodeker/terminus/internal/saml"
+)
+
+// Protocols a connection can speak
+const (
+	ProtocolSAML = "saml"
+	ProtocolOIDC = "oidc"
+)
+
+// ProviderPrefix starts the provider name of a tenant connection, in
+// sign-in states and linked identities: "sso:<connection ID>"
+const ProviderPrefix = "sso:"
+
+// Mapping names where the email and roles are in what the identity
+// provider sends: SAML attributes or OpenID Connect claims
+type Mapping struct {
+	// Email names the attribute holding the user's email. When empty, the
+	// usual names are tried, then the SAML NameID.
+	Email string `json:"email,omitempty"`
+	// Role names the attribute whose values are matched against the
+	// tenant's role names
+	Role string `json:"role,omitempty"`
+}
+
+// emailAttributes are tried in order when the mapping names none
+var emailAttributes = []string{
+	"email",
+	"mail",
+	"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress",
+	"urn:oid:0.9.2342.19200300.100.1.3",
+}
+
+// Profile is who signed in, as far as the tenant cares
+type Profile struct {
+	// Subject is the provider's stable ID for the user
+	Subject string
+	Email   string
+	// Roles are the values of the mapped role attribute
+	Roles []string
+}
+
+// FromSAML reads a profile out of an assertion
+func FromSAML(a saml.Assertion, m Mapping) Profile {
+	p := Profile{Subject: a.NameID, Email: pickEmail(a.Attributes, m)}
+	if p.Email == "" && strings.Contains(a.NameID, "@") {
+		p.Email = a.NameID
+	}
+	if m.Role != "" {
+		p.Roles = a.Attributes[m.Role]
+	}
+	p.Email = strings.ToLower(strings.TrimSpace(p.Email))
+	return p
+}
+
+// FromClaims reads a profile out of an ID token's claims
+func FromClaims(subject string, claims map[string]any, m Mapping) Profile {
+	attrs := make(map[string][]string, len(claims))
+	for k, v := range claims {
+		attrs[k] = claimStrings(v)
+	}
+	p := Profile{Subject: subject, Email: pickEmail(attrs, m)}
+	if m.Role != "" {
+		p.Roles = attrs[m.Role]
+	}
+	p.Email = strings.ToLower(strings.TrimSpace(p.Email))
+	return p
+}
+
+func pickEmail(attrs map[string][]string, m Mapping) string {
+	names := emailAttributes
+	if m.Email != "" {
+		names = []string{m.Email}
+	}
+	for _, name := range names {
+		if v := attrs[name]; len(v) > 0 && v[0] != "" {
+			return v[0]
+		}
+	}
+	return ""
+}
+
+// claimStrings reads a claim that is a string or a list of them
+func claimStrings(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// ErrNotVerified is returned when a domain's TXT records don't carry its
+// verification token
+var ErrNotVerified = errors.New("sso: domain verification record not found")
+
+// recordPrefix starts the TXT record that proves a domain
+const recordPrefix = "terminus-verification="
+
+// NormalizeDomain lowercases a domain name and checks it is one
+func NormalizeDomain(s string) (string, error) {
+	d := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(s)), ".")
+	if len(d) > 253 || !strings.Contains(d, ".") {
+		return "", fmt.Errorf("%q is not a domain name", s)
+	}
+	for _, label := range strings.Split(d, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return "", fmt.Errorf("%q is not a domain name", s)
+		}
+		for _, c := range label {
+			if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+				return "", fmt.Errorf("%q is not a domain name", s)
+			}
+		}
+	}
+	return d, nil
+}
+
+// EmailDomain is the lowercased domain of an email address, or "" when
+// there is none
+func EmailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(email[at+1:])
+}
+
+// NewVerificationToken returns a token for a tenant to publish in DNS
+func NewVerificationToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// TXTRecord is the TXT record that proves ownership with token
+func TXTRecord(token string) string {
+	return recordPrefix + token
+}
+
+// Resolver looks up TXT records; net.DefaultResolver is one
+type Resolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// VerifyDomain checks the domain publishes the TXT record for token
+func VerifyDomain(ctx context.Context, r Resolver, domain, token string) error {
+	records, err := r.LookupTXT(ctx, domain)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return ErrNotVerified
+		}
+		return fmt.Errorf("sso: looking up %s: %w", domain, err)
+	}
+	for _, rec := range records {
+		if strings.TrimSpace(rec) == TXTRecord(token) {
+			return nil
+		}
+	}
+	return ErrNotVerified
+}
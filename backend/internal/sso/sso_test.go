@@ -0,0 +1,133 @@
+package sso
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/dfodeker/terminus/internal/saml"
+)
+
+func TestProfiles(t *testing.T) {
+	assertion := saml.Assertion{
+		NameID: "00u1",
+		Attributes: map[string][]string{
+			"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress": {"Ada@Example.com"},
+			"work_email": {"ada@corp.example.com"},
+			"groups":     {"Staff", "Admins"},
+		},
+	}
+	claims := map[string]any{
+		"sub":    "00u1",
+		"email":  "ada@example.com",
+		"groups": []any{"Staff", 7},
+		"dept":   "ops",
+	}
+
+	tests := []struct {
+		name string
+		got  Profile
+		want Profile
+	}{
+		{
+			name: "saml with the usual attributes",
+			got:  FromSAML(assertion, Mapping{}),
+			want: Profile{Subject: "00u1", Email: "ada@example.com"},
+		},
+		{
+			name: "saml with a mapping",
+			got:  FromSAML(assertion, Mapping{Email: "work_email", Role: "groups"}),
+			want: Profile{Subject: "00u1", Email: "ada@corp.example.com", Roles: []string{"Staff", "Admins"}},
+		},
+		{
+			name: "saml falls back to the NameID",
+			got:  FromSAML(saml.Assertion{NameID: "ada@example.com"}, Mapping{}),
+			want: Profile{Subject: "ada@example.com", Email: "ada@example.com"},
+		},
+		{
+			name: "saml with no email",
+			got:  FromSAML(saml.Assertion{NameID: "00u1"}, Mapping{}),
+			want: Profile{Subject: "00u1"},
+		},
+		{
+			name: "oidc list claim",
+			got:  FromClaims("00u1", claims, Mapping{Role: "groups"}),
+			want: Profile{Subject: "00u1", Email: "ada@example.com", Roles: []string{"Staff"}},
+		},
+		{
+			name: "oidc string claim",
+			got:  FromClaims("00u1", claims, Mapping{Role: "dept"}),
+			want: Profile{Subject: "00u1", Email: "ada@example.com", Roles: []string{"ops"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("profile = %+v, want %+v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeDomain(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: " Example.COM. ", want: "example.com"},
+		{in: "mail.corp-1.example.co.uk", want: "mail.corp-1.example.co.uk"},
+		{in: "localhost", wantErr: true},
+		{in: "-bad.example.com", wantErr: true},
+		{in: "a..example.com", wantErr: true},
+		{in: "ada@example.com", wantErr: true},
+		{in: "exa mple.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := NormalizeDomain(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeDomain() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeDomain() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeResolver answers TXT lookups from a map
+type fakeResolver map[string][]string
+
+func (f fakeResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	records, ok := f[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return records, nil
+}
+
+func TestVerifyDomain(t *testing.T) {
+	r := fakeResolver{
+		"example.com": {"v=spf1 -all", "terminus-verification=abc123"},
+		"other.com":   {"terminus-verification=zzz"},
+	}
+	tests := []struct {
+		domain  string
+		wantErr error
+	}{
+		{domain: "example.com"},
+		{domain: "other.com", wantErr: ErrNotVerified},
+		{domain: "missing.com", wantErr: ErrNotVerified},
+	}
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			err := VerifyDomain(context.Background(), r, tt.domain, "abc123")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyDomain() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/sigv4"
+)
+
+// uploadPrefix and downloadPrefix are the paths under the disk handler that
+// accept signed uploads and serve signed downloads. Generated keys never
+// start with an underscore.
+const (
+	uploadPrefix   = "/_upload/"
+	downloadPrefix = "/_download/"
+)
+
+// MaxDiskUploadBytes caps a single upload through the disk handler
+const MaxDiskUploadBytes = 50 << 20
+
+// Disk stores objects under a local directory. It is meant for development
+// and single-node installs; mount Handler at the public URL's path to serve
+// files and accept presigned uploads.
+type Disk struct {
+	root      string
+	publicURL string
+	secret    []byte
+	now       func() time.Time
+}
+
+// NewDisk returns a Storage rooted at dir, served from publicURL
+func NewDisk(dir, publicURL, secret string) (*Disk, error) {
+	if secret == "" {
+		return nil, errors.New("disk storage needs a signing secret")
+	}
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+	return &Disk{
+		root:      root,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+		secret:    []byte(secret),
+		now:       time.Now,
+	}, nil
+}
+
+func (d *Disk) path(key string) (string, error) {
+	if err := ValidateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(d.root, filepath.FromSlash(key)), nil
+}
+
+func (d *Disk) URL(key string) string {
+	return d.publicURL + "/" + sigv4.URIEscape(key, false)
+}
+
+func (d *Disk) PresignPut(ctx context.Context, key, contentType string, ttl time.Duration) (*PresignedRequest, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, err
+	}
+
+	expires := d.now().Add(ttl)
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	query.Set("signature", d.uploadSignature(key, contentType, expires.Unix()))
+
+	return &PresignedRequest{
+		Method:    http.MethodPut,
+		URL:       d.publicURL + uploadPrefix + sigv4.URIEscape(key, false) + "?" + query.Encode(),
+		Headers:   map[string]string{"Content-Type": contentType},
+		ExpiresAt: expires,
+	}, nil
+}
+
+func (d *Disk) uploadSignature(key, contentType string, expires int64) string {
+	mac := hmac.New(sha256.New, d.secret)
+	fmt.Fprintf(mac, "PUT\n%s\n%s\n%d", key, contentType, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (d *Disk) PresignGet(ctx context.Context, key string, ttl time.Duration) (*PresignedRequest, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, err
+	}
+
+	expires := d.now().Add(ttl)
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	query.Set("signature", d.downloadSignature(key, expires.Unix()))
+
+	return &PresignedRequest{
+		Method:    http.MethodGet,
+		URL:       d.publicURL + downloadPrefix + sigv4.URIEscape(key, false) + "?" + query.Encode(),
+		Headers:   map[string]string{},
+		ExpiresAt: expires,
+	}, nil
+}
+
+func (d *Disk) downloadSignature(key string, expires int64) string {
+	mac := hmac.New(sha256.New, d.secret)
+	fmt.Fprintf(mac, "GET\n%s\n%d", key, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (d *Disk) Put(ctx context.Context, key, contentType string, body io.Reader, size int64) error {
+	path, err := d.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temp file first so readers never see a partial object
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (d *Disk) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := d.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (d *Disk) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	path, err := d.path(key)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ObjectInfo{
+		Size:        fi.Size(),
+		ContentType: mime.TypeByExtension(filepath.Ext(path)),
+	}, nil
+}
+
+func (d *Disk) Delete(ctx context.Context, key string) error {
+	path, err := d.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Handler serves stored files, except private ones, and accepts presigned
+// uploads and downloads. Mount it with the public URL's path prefix
+// stripped.
+func (d *Disk) Handler() http.Handler {
+	files := http.FileServer(http.Dir(d.root))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, uploadPrefix) {
+			d.handleUpload(w, r, strings.TrimPrefix(r.URL.Path, uploadPrefix))
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, downloadPrefix) {
+			d.handleDownload(w, r, strings.TrimPrefix(r.URL.Path, downloadPrefix))
+			return
+		}
+		// Don't expose directory listings or private objects
+		if strings.HasSuffix(r.URL.Path, "/") || strings.HasPrefix(r.URL.Path, "/"+PrivatePrefix) {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Cache-Control", CacheControl)
+		files.ServeHTTP(w, r)
+	})
+}
+
+func (d *Disk) handleUpload(w http.ResponseWriter, r *http.Request, key string) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	expires, err := strconv.ParseInt(r.URL.Query().Get("expires"), 10, 64)
+	if err != nil || d.now().Unix() > expires {
+		http.Error(w, "upload URL expired", http.StatusForbidden)
+		return
+	}
+
+	want := d.uploadSignature(key, r.Header.Get("Content-Type"), expires)
+	if !hmac.Equal([]byte(want), []byte(r.URL.Query().Get("signature"))) {
+		http.Error(w, "invalid upload signature", http.StatusForbidden)
+		return
+	}
+
+	body := http.MaxBytesReader(w, r.Body, MaxDiskUploadBytes)
+	if err := d.Put(r.Context(), key, r.Header.Get("Content-Type"), body, r.ContentLength); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "upload too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "upload failed", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (d *Disk) handleDownload(w http.ResponseWriter, r *http.Request, key string) {
+	expires, err := strconv.ParseInt(r.URL.Query().Get("expires"), 10, 64)
+	if err != nil || d.now().Unix() > expires {
+		http.Error(w, "download URL expired", http.StatusForbidden)
+		return
+	}
+
+	want := d.downloadSignature(key, expires)
+	if !hmac.Equal([]byte(want), []byte(r.URL.Query().Get("signature"))) {
+		http.Error(w, "invalid download signature", http.StatusForbidden)
+		return
+	}
+
+	path, err := d.path(key)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Cache-Control", "private, no-store")
+	http.ServeFile(w, r, path)
+}
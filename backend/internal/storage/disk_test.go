@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		wantErr bool
+	}{
+		{"stores/1/products/2/image.png", false},
+		{"", true},
+		{"/absolute.png", true},
+		{"a/../b.png", true},
+		{"a//b.png", true},
+		{"a\\b.png", true},
+		{strings.Repeat("a", 1025), true},
+	}
+	for _, tt := range tests {
+		if err := ValidateKey(tt.key); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateKey(%q) error = %v, wantErr %v", tt.key, err, tt.wantErr)
+		}
+	}
+}
+
+func TestDiskRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	d, err := NewDisk(t.TempDir(), "http://localhost/media", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const key = "stores/s/products/p/img.png"
+	if err := d.Put(ctx, key, "image/png", strings.NewReader("png-bytes"), 9); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	info, err := d.Stat(ctx, key)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.Size != 9 || info.ContentType != "image/png" {
+		t.Errorf("Stat = %+v", info)
+	}
+
+	rc, err := d.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(data) != "png-bytes" {
+		t.Errorf("Get = %q", data)
+	}
+
+	if err := d.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := d.Stat(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Stat after delete error = %v, want ErrNotFound", err)
+	}
+	if err := d.Delete(ctx, key); err != nil {
+		t.Errorf("second Delete: %v", err)
+	}
+}
+
+func TestDiskPresignedUpload(t *testing.T) {
+	d, err := NewDisk(t.TempDir(), "http://localhost/media", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.StripPrefix("/media", d.Handler()))
+	defer srv.Close()
+
+	const key = "stores/s/products/p/img.jpg"
+	presigned, err := d.PresignPut(context.Background(), key, "image/jpeg", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := strings.Replace(presigned.URL, "http://localhost", srv.URL, 1)
+
+	upload := func(url, contentType string) int {
+		req, _ := http.NewRequest(presigned.Method, url, strings.NewReader("jpeg"))
+		req.Header.Set("Content-Type", contentType)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := upload(target, "image/png"); code != http.StatusForbidden {
+		t.Errorf("upload with wrong content type = %d, want 403", code)
+	}
+	if code := upload(strings.Replace(target, "img.jpg", "other.jpg", 1), "image/jpeg"); code != http.StatusForbidden {
+		t.Errorf("upload to other key = %d, want 403", code)
+	}
+	if code := upload(target, "image/jpeg"); code != http.StatusOK {
+		t.Fatalf("upload = %d, want 200", code)
+	}
+
+	resp, err := http.Get(srv.URL + "/media/" + key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "jpeg" {
+		t.Errorf("GET = %d %q", resp.StatusCode, body)
+	}
+
+	d.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if code := upload(target, "image/jpeg"); code != http.StatusForbidden {
+		t.Errorf("expired upload = %d, want 403", code)
+	}
+}
+
+func TestDiskPresignedDownload(t *testing.T) {
+	d, err := NewDisk(t.TempDir(), "http://localhost/media", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.StripPrefix("/media", d.Handler()))
+	defer srv.Close()
+
+	const key = PrivatePrefix + "stores/s/exports/e.csv"
+	if err := d.Put(context.Background(), key, "text/csv", strings.NewReader("a,b"), 3); err != nil {
+		t.Fatal(err)
+	}
+	presigned, err := d.PresignGet(context.Background(), key, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := strings.Replace(presigned.URL, "http://localhost", srv.URL, 1)
+
+	get := func(url string) (int, string) {
+		resp, err := http.Get(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	if code, _ := get(srv.URL + "/media/" + key); code != http.StatusNotFound {
+		t.Errorf("public GET of private key = %d, want 404", code)
+	}
+	if code, _ := get(strings.Replace(target, "e.csv", "f.csv", 1)); code != http.StatusForbidden {
+		t.Errorf("GET of other key = %d, want 403", code)
+	}
+	if code, body := get(target); code != http.StatusOK || body != "a,b" {
+		t.Errorf("signed GET = %d %q", code, body)
+	}
+
+	d.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if code, _ := get(target); code != http.StatusForbidden {
+		t.Errorf("expired GET = %d, want 403", code)
+	}
+}
@@ -0,0 +1,246 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/sigv4"
+	"github.com/dfodeker/terminus/internal/tracing"
+)
+
+// S3Config configures an S3 or S3-compatible (MinIO, R2, ...) bucket
+type S3Config struct {
+	Bucket string
+	Region string
+	// Endpoint defaults to https://s3.<region>.amazonaws.com
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle addresses objects as <endpoint>/<bucket>/<key>, which most
+	// S3-compatible servers need, instead of <bucket>.<host>/<key>
+	PathStyle bool
+	// PublicURL is an optional CDN or custom domain objects are served from
+	PublicURL string
+}
+
+// GCSConfig configures a Google Cloud Storage bucket through its
+// S3-compatible XML API using HMAC keys
+type GCSConfig struct {
+	Bucket     string
+	HMACKeyID  string
+	HMACSecret string
+	PublicURL  string
+}
+
+// objectStore talks to S3-style object storage over plain HTTP
+type objectStore struct {
+	endpoint  *url.URL
+	bucket    string
+	pathStyle bool
+	publicURL string
+	signer    sigv4.Signer
+	client    *http.Client
+	now       func() time.Time
+}
+
+// NewS3 returns a Storage backed by an S3 bucket
+func NewS3(cfg S3Config) (Storage, error) {
+	if cfg.Bucket == "" || cfg.Region == "" {
+		return nil, errors.New("S3 bucket and region are required")
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, errors.New("S3 credentials are required")
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	return newObjectStore(cfg.Endpoint, cfg.Bucket, cfg.PathStyle, cfg.PublicURL,
+		sigv4.AWS("s3", cfg.Region, cfg.AccessKeyID, cfg.SecretAccessKey))
+}
+
+// NewGCS returns a Storage backed by a GCS bucket
+func NewGCS(cfg GCSConfig) (Storage, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("GCS bucket is required")
+	}
+	if cfg.HMACKeyID == "" || cfg.HMACSecret == "" {
+		return nil, errors.New("GCS HMAC key is required")
+	}
+	return newObjectStore("https://storage.googleapis.com", cfg.Bucket, true, cfg.PublicURL,
+		sigv4.GCS(cfg.HMACKeyID, cfg.HMACSecret))
+}
+
+func newObjectStore(endpoint, bucket string, pathStyle bool, publicURL string, signer sigv4.Signer) (*objectStore, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid storage endpoint %q", endpoint)
+	}
+	return &objectStore{
+		endpoint:  u,
+		bucket:    bucket,
+		pathStyle: pathStyle,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+		signer:    signer,
+		client:    &http.Client{Timeout: 5 * time.Minute, Transport: tracing.Transport(nil)},
+		now:       time.Now,
+	}, nil
+}
+
+func (s *objectStore) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	base := strings.TrimSuffix(u.Path, "/")
+	if s.pathStyle {
+		u.Path = base + "/" + s.bucket + "/" + key
+	} else {
+		u.Host = s.bucket + "." + u.Host
+		u.Path = base + "/" + key
+	}
+	u.RawPath = sigv4.URIEscape(u.Path, false)
+	return &u
+}
+
+func (s *objectStore) URL(key string) string {
+	if s.publicURL != "" {
+		return s.publicURL + "/" + sigv4.URIEscape(key, false)
+	}
+	return s.objectURL(key).String()
+}
+
+func (s *objectStore) PresignPut(ctx context.Context, key, contentType string, ttl time.Duration) (*PresignedRequest, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	headers := http.Header{}
+	headers.Set("Content-Type", contentType)
+	headers.Set("Cache-Control", CacheControl)
+
+	signed := s.signer.Presign(http.MethodPut, s.objectURL(key), headers, now, ttl)
+	return &PresignedRequest{
+		Method:    http.MethodPut,
+		URL:       signed.String(),
+		Headers:   map[string]string{"Content-Type": contentType, "Cache-Control": CacheControl},
+		ExpiresAt: now.Add(ttl),
+	}, nil
+}
+
+func (s *objectStore) PresignGet(ctx context.Context, key string, ttl time.Duration) (*PresignedRequest, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	signed := s.signer.Presign(http.MethodGet, s.objectURL(key), http.Header{}, now, ttl)
+	return &PresignedRequest{
+		Method:    http.MethodGet,
+		URL:       signed.String(),
+		Headers:   map[string]string{},
+		ExpiresAt: now.Add(ttl),
+	}, nil
+}
+
+func (s *objectStore) Put(ctx context.Context, key, contentType string, body io.Reader, size int64) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key).String(), body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Cache-Control", CacheControl)
+
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return statusError("put", key, resp)
+	}
+	return nil
+}
+
+func (s *objectStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(key).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		return nil, statusError("get", key, resp)
+	}
+	return resp.Body, nil
+}
+
+func (s *objectStore) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, s.objectURL(key).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return nil, statusError("stat", key, resp)
+	}
+	return &ObjectInfo{
+		Size:        resp.ContentLength,
+		ContentType: resp.Header.Get("Content-Type"),
+	}, nil
+}
+
+func (s *objectStore) Delete(ctx context.Context, key string) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key).String(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Deleting a missing object is not an error
+	if resp.StatusCode/100 != 2 && resp.StatusCode != http.StatusNotFound {
+		return statusError("delete", key, resp)
+	}
+	return nil
+}
+
+func (s *objectStore) do(req *http.Request) (*http.Response, error) {
+	s.signer.Sign(req, s.now())
+	return s.client.Do(req)
+}
@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/dfodeker/terminus/internal/sigv4"
+)
+
+func TestObjectURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoint  string
+		pathStyle bool
+		publicURL string
+		key       string
+		want      string
+	}{
+		{"virtual hosted", "https://s3.us-east-1.amazonaws.com", false, "", "a/b c.png",
+			"https://media.s3.us-east-1.amazonaws.com/a/b%20c.png"},
+		{"path style", "http://localhost:9000", true, "", "a/b.png",
+			"http://localhost:9000/media/a/b.png"},
+		{"public url", "http://localhost:9000", true, "https://cdn.example.com/", "a/b.png",
+			"https://cdn.example.com/a/b.png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := newObjectStore(tt.endpoint, "media", tt.pathStyle, tt.publicURL, sigv4.AWS("s3", "us-east-1", "k", "s"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.URL(tt.key); got != tt.want {
+				t.Errorf("URL(%q) = %s, want %s", tt.key, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Package storage stores binary objects such as product images in S3, GCS or
+// on local disk behind one interface.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned when an object does not exist
+var ErrNotFound = errors.New("object not found")
+
+// PrivatePrefix starts the keys of objects only reachable through
+// PresignGet, such as exports. Disk storage refuses to serve them publicly;
+// an object store's bucket policy should do the same.
+const PrivatePrefix = "private/"
+
+// CacheControl is attached to every stored object. Keys are never rewritten
+// with different content, so browsers and CDNs may cache them indefinitely.
+const CacheControl = "public, max-age=31536000, immutable"
+
+// Storage is implemented by every backend
+type Storage interface {
+	// PresignPut returns a request the client can use to upload directly to
+	// the backend without proxying the bytes through the API
+	PresignPut(ctx context.Context, key, contentType string, ttl time.Duration) (*PresignedRequest, error)
+	// PresignGet returns a request that downloads the object until ttl
+	// passes, private or not
+	PresignGet(ctx context.Context, key string, ttl time.Duration) (*PresignedRequest, error)
+	Put(ctx context.Context, key, contentType string, body io.Reader, size int64) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	Delete(ctx context.Context, key string) error
+	// URL is the public address the object is served from
+	URL(key string) string
+}
+
+// PresignedRequest describes an upload or download the client performs
+// itself
+type PresignedRequest struct {
+	Method    string            `json:"method"`
+	URL       string            `json:"url"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// ObjectInfo is the metadata of a stored object
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+}
+
+// ValidateKey rejects keys that could escape a bucket prefix or a disk root
+func ValidateKey(key string) error {
+	if key == "" || len(key) > 1024 {
+		return fmt.Errorf("invalid storage key length %d", len(key))
+	}
+	if strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("invalid storage key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid storage key %q", key)
+		}
+	}
+	return nil
+}
+
+// Config selects and configures a backend
+type Config struct {
+	// Driver is local, s3 or gcs. Empty means local.
+	Driver string
+	// LocalDir, LocalPublicURL and LocalSecret configure local disk storage
+	LocalDir       string
+	LocalPublicURL string
+	LocalSecret    string
+	S3             S3Config
+	GCS            GCSConfig
+}
+
+// New builds the backend selected by cfg.Driver
+func New(cfg Config) (Storage, error) {
+	switch cfg.Driver {
+	case "", "local":
+		if cfg.LocalSecret == "" {
+			return nil, errors.New("STORAGE_LOCAL_SECRET or SIGNING_KEY must be set for local storage")
+		}
+		return NewDisk(cfg.LocalDir, cfg.LocalPublicURL, cfg.LocalSecret)
+	case "s3":
+		return NewS3(cfg.S3)
+	case "gcs":
+		return NewGCS(cfg.GCS)
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_DRIVER %q", cfg.Driver)
+	}
+}
+
+func statusError(op, key string, resp *http.Response) error {
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("storage %s %q: %s: %s", op, key, resp.Status, strings.TrimSpace(string(body)))
+}
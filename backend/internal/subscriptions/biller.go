@@ -0,0 +1,337 @@
+package subscriptions
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/crypto"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/metrics"
+	"github.com/dfodeker/terminus/internal/orders"
+	"github.com/dfodeker/terminus/internal/payments"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// OrderTag marks the orders a renewal placed
+const OrderTag = "subscription"
+
+const (
+	// DefaultRetryDelay is how long a declined renewal waits to be tried
+	// again when SUBSCRIPTION_RETRY_DELAY is unset
+	DefaultRetryDelay = 24 * time.Hour
+	// DefaultMaxAttempts is how many declines in a row fail a contract when
+	// SUBSCRIPTION_MAX_ATTEMPTS is unset
+	DefaultMaxAttempts = 3
+)
+
+// BillerConfig configures a Biller
+type BillerConfig struct {
+	Interval time.Duration
+	// RetryDelay is how long after a declined renewal it is tried again
+	RetryDelay time.Duration
+	// MaxAttempts is how many declines in a row fail the contract
+	MaxAttempts int
+	// BatchSize bounds the renewals billed each interval
+	BatchSize int
+	// OnOrder, if set, is called in the renewal's transaction once its
+	// order is placed, such as to queue the customer's emails
+	OnOrder func(ctx context.Context, q *database.Queries, order database.Order) error
+	Logger  *slog.Logger
+}
+
+// Biller places and charges the renewal orders of contracts that are due.
+// Each contract is locked while it is billed, so running several at once
+// is harmless.
+type Biller struct {
+	pool     *pgxpool.Pool
+	db       *database.Queries
+	gateways payments.Gateways
+	gids     service.GIDGenerator
+	cfg      BillerConfig
+}
+
+// NewBiller creates a biller over pool charging through gateways
+func NewBiller(pool *pgxpool.Pool, gateways payments.Gateways, gids service.GIDGenerator, cfg BillerConfig) *Biller {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = DefaultRetryDelay
+	}
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = DefaultMaxAttempts
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 100
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &Biller{
+		pool:     pool,
+		db:       database.New(pool),
+		gateways: gateways,
+		gids:     gids,
+		cfg:      cfg,
+	}
+}
+
+// Run bills due renewals once an interval until ctx is cancelled
+func (b *Biller) Run(ctx context.Context) error {
+	ticker := time.NewTicker(b.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := b.BillDue(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			b.cfg.Logger.Error("subscription billing failed", "error", err)
+		} else if n > 0 {
+			b.cfg.Logger.Info("subscriptions billed", "renewals", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// BillDue bills up to a batch of renewals due by now and returns how many
+// were handled, declined ones included
+func (b *Biller) BillDue(ctx context.Context, now time.Time) (int, error) {
+	var n int
+	for n < b.cfg.BatchSize && ctx.Err() == nil {
+		billed, err := b.billNext(ctx, now)
+		if err != nil || !billed {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// billNext bills the next due renewal, reporting false when none is due.
+// The charge is made with the contract locked and the order is only written
+// once it succeeds. A charge whose outcome is unknown rolls everything back
+// to be retried with the same idempotency key.
+func (b *Biller) billNext(ctx context.Context, now time.Time) (bool, error) {
+	tx, err := b.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+	q := b.db.WithTx(tx)
+
+	c, err := q.ClaimDueSubscriptionContract(ctx, now)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	fail := func(status Status, retryAt time.Time, reason string) (bool, error) {
+		if err := q.RecordSubscriptionFailure(ctx, database.RecordSubscriptionFailureParams{
+			ID:            c.ID,
+			Status:        string(status),
+			NextBillingAt: retryAt,
+			LastFailure:   sql.NullString{String: reason, Valid: true},
+		}); err != nil {
+			return false, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return false, err
+		}
+		b.cfg.Logger.Warn("subscription renewal failed",
+			"contract_id", c.ID,
+			"store_id", c.StoreID,
+			"cycle", c.NextCycle,
+			"status", status,
+			"reason", reason,
+		)
+		return true, nil
+	}
+
+	if !c.VariantID.Valid {
+		return fail(StatusFailed, c.NextBillingAt, "variant is no longer available")
+	}
+	variant, err := q.GetSubscriptionVariant(ctx, c.VariantID.UUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fail(StatusFailed, c.NextBillingAt, "variant is no longer available")
+	}
+	if err != nil {
+		return false, err
+	}
+	gateway, ok := b.gateways.Lookup(c.PaymentProvider)
+	if !ok {
+		return fail(StatusFailed, c.NextBillingAt, fmt.Sprintf("payment provider %q is not configured", c.PaymentProvider))
+	}
+	customer, err := q.GetCustomerByID(ctx, database.GetCustomerByIDParams{ID: c.CustomerID, StoreID: c.StoreID})
+	if err != nil {
+		return false, err
+	}
+
+	total := c.PriceCents * int64(c.Quantity)
+	result, err := gateway.Charge(ctx, payments.Charge{
+		MethodRef:      c.PaymentMethodRef,
+		AmountCents:    total,
+		Currency:       c.Currency,
+		IdempotencyKey: fmt.Sprintf("subscription:%s:%d", c.ID, c.NextCycle),
+		Description:    fmt.Sprintf("%s renewal %d", variant.ProductName, c.NextCycle+1),
+	})
+	if err != nil {
+		metrics.PaymentFailed(c.StoreID)
+	}
+	if errors.Is(err, payments.ErrDeclined) {
+		if int(c.FailedAttempts)+1 >= b.cfg.MaxAttempts {
+			return fail(StatusFailed, c.NextBillingAt, err.Error())
+		}
+		return fail(StatusActive, now.Add(b.cfg.RetryDelay), err.Error())
+	}
+	if err != nil {
+		return false, fmt.Errorf("charge subscription %s: %w", c.ID, err)
+	}
+
+	order, err := b.placeOrder(ctx, q, c, customer, variant, result, now)
+	if err != nil {
+		return false, err
+	}
+	if b.cfg.OnOrder != nil {
+		if err := b.cfg.OnOrder(ctx, q, order); err != nil {
+			return false, err
+		}
+	}
+
+	interval := Interval{Unit: Unit(c.IntervalUnit), Count: int(c.IntervalCount)}
+	next := interval.NextCycle(c.BillingAnchorAt, int(c.NextCycle)+1, now)
+	if err := q.RecordSubscriptionBilled(ctx, database.RecordSubscriptionBilledParams{
+		ID:            c.ID,
+		NextCycle:     int32(next),
+		NextBillingAt: interval.At(c.BillingAnchorAt, next),
+	}); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	metrics.OrderPlaced(order.StoreID, order.Currency, order.TotalCents)
+
+	b.cfg.Logger.Info("subscription renewed",
+		"contract_id", c.ID,
+		"store_id", c.StoreID,
+		"order_id", order.ID,
+		"order_number", order.OrderNumber,
+		"cycle", c.NextCycle,
+		"financial_status", order.FinancialStatus,
+	)
+	return true, nil
+}
+
+// placeOrder writes the renewal order, its line and the timeline entries
+// for it being placed and paid
+func (b *Biller) placeOrder(ctx context.Context, q *database.Queries, c database.SubscriptionContract, customer database.Customer, variant database.GetSubscriptionVariantRow, result payments.Result, now time.Time) (database.Order, error) {
+	if err := q.LockStoreOrderNumbers(ctx, c.StoreID); err != nil {
+		return database.Order{}, err
+	}
+	number, err := q.GetNextOrderNumber(ctx, c.StoreID)
+	if err != nil {
+		return database.Order{}, err
+	}
+
+	financialStatus := "paid"
+	if result.Pending {
+		financialStatus = "pending"
+	}
+	total := c.PriceCents * int64(c.Quantity)
+	order, err := q.CreateOrder(ctx, database.CreateOrderParams{
+		Gid:               service.NewGID(b.gids),
+		TenantID:          c.TenantID,
+		StoreID:           c.StoreID,
+		CustomerID:        uuid.NullUUID{UUID: c.CustomerID, Valid: true},
+		OrderNumber:       number,
+		Email:             crypto.NullText{String: string(customer.Email), Valid: customer.Email != ""},
+		EmailDigest:       crypto.Digest(customer.Email),
+		Status:            "open",
+		FinancialStatus:   financialStatus,
+		FulfillmentStatus: "unfulfilled",
+		Currency:          c.Currency,
+		SubtotalCents:     total,
+		TotalCents:        total,
+		Tags:              []string{OrderTag},
+		PlacedAt:          now,
+	})
+	if err != nil {
+		return database.Order{}, err
+	}
+
+	item, err := q.CreateOrderLineItem(ctx, database.CreateOrderLineItemParams{
+		OrderID:      order.ID,
+		ProductID:    uuid.NullUUID{UUID: variant.ProductID, Valid: true},
+		VariantID:    uuid.NullUUID{UUID: variant.ID, Valid: true},
+		Title:        variant.ProductName,
+		VariantTitle: sql.NullString{String: variant.Title, Valid: variant.Title != ""},
+		Sku:          variant.Sku,
+		Quantity:     c.Quantity,
+		PriceCents:   c.PriceCents,
+		TotalCents:   total,
+	})
+	if err != nil {
+		return database.Order{}, err
+	}
+	// A bundle's components get lines of their own for fulfillment
+	if _, err := q.CreateBundleComponentLineItems(ctx, item.ID); err != nil {
+		return database.Order{}, err
+	}
+	if err := q.CreateSubscriptionOrder(ctx, database.CreateSubscriptionOrderParams{
+		ContractID: c.ID,
+		Cycle:      c.NextCycle,
+		OrderID:    order.ID,
+	}); err != nil {
+		return database.Order{}, err
+	}
+
+	placed, err := json.Marshal(map[string]any{"subscription_contract_id": c.ID, "cycle": c.NextCycle})
+	if err != nil {
+		return database.Order{}, err
+	}
+	if _, err := q.CreateOrderEvent(ctx, database.CreateOrderEventParams{
+		OrderID: order.ID,
+		StoreID: order.StoreID,
+		Kind:    string(orders.EventPlaced),
+		Message: fmt.Sprintf("Subscription renewal %d placed", c.NextCycle+1),
+		Data:    placed,
+	}); err != nil {
+		return database.Order{}, err
+	}
+
+	payment := map[string]any{"provider": c.PaymentProvider, "amount_cents": total}
+	message := fmt.Sprintf("Payment to be collected via %s", c.PaymentProvider)
+	if !result.Pending {
+		message = fmt.Sprintf("Payment captured via %s", c.PaymentProvider)
+	}
+	if result.TransactionID != "" {
+		payment["provider_transaction_id"] = result.TransactionID
+	}
+	paid, err := json.Marshal(payment)
+	if err != nil {
+		return database.Order{}, err
+	}
+	if _, err := q.CreateOrderEvent(ctx, database.CreateOrderEventParams{
+		OrderID: order.ID,
+		StoreID: order.StoreID,
+		Kind:    string(orders.EventPayment),
+		Message: message,
+		Data:    paid,
+	}); err != nil {
+		return database.Order{}, err
+	}
+	return order, nil
+}
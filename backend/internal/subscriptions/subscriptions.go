@@ -0,0 +1,123 @@
+// Package subscriptions schedules and bills the recurring orders customers
+// take out on a selling plan
+package subscriptions
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// MaxNameLength bounds selling plan names
+	MaxNameLength = 255
+	// MaxIntervalCount bounds how many units apart renewals can be
+	MaxIntervalCount = 365
+	// MaxQuantity bounds the units of a variant each renewal orders
+	MaxQuantity = 1000
+)
+
+// Unit is what a selling plan interval is counted in
+type Unit string
+
+const (
+	UnitDay   Unit = "day"
+	UnitWeek  Unit = "week"
+	UnitMonth Unit = "month"
+	UnitYear  Unit = "year"
+)
+
+// IsValid reports whether u is a known unit
+func (u Unit) IsValid() bool {
+	switch u {
+	case UnitDay, UnitWeek, UnitMonth, UnitYear:
+		return true
+	}
+	return false
+}
+
+// Status is where a contract is in its life, matching
+// subscription_contracts.status
+type Status string
+
+const (
+	StatusActive    Status = "active"
+	StatusPaused    Status = "paused"
+	StatusCancelled Status = "cancelled"
+	// StatusFailed stops billing after too many declined renewals, until
+	// the contract is resumed
+	StatusFailed Status = "failed"
+)
+
+// IsValid reports whether s is a known status
+func (s Status) IsValid() bool {
+	switch s {
+	case StatusActive, StatusPaused, StatusCancelled, StatusFailed:
+		return true
+	}
+	return false
+}
+
+// ErrInvalidInterval is returned for an interval renewals cannot be
+// scheduled on
+var ErrInvalidInterval = errors.New("invalid interval")
+
+// Interval is how far apart renewals are, such as every 2 weeks
+type Interval struct {
+	Unit  Unit
+	Count int
+}
+
+// Validate checks the interval can be scheduled
+func (i Interval) Validate() error {
+	if !i.Unit.IsValid() {
+		return fmt.Errorf("%w: unit must be one of day, week, month or year", ErrInvalidInterval)
+	}
+	if i.Count < 1 || i.Count > MaxIntervalCount {
+		return fmt.Errorf("%w: count must be between 1 and %d", ErrInvalidInterval, MaxIntervalCount)
+	}
+	return nil
+}
+
+// At returns when renewal n falls due for a contract anchored at anchor.
+// Renewal 0 is the anchor itself. Months are counted from the anchor, not
+// the previous renewal, so a contract taken out on the 31st renews on the
+// last day of shorter months and goes back to the 31st after them.
+func (i Interval) At(anchor time.Time, n int) time.Time {
+	steps := n * i.Count
+	switch i.Unit {
+	case UnitDay:
+		return anchor.AddDate(0, 0, steps)
+	case UnitWeek:
+		return anchor.AddDate(0, 0, 7*steps)
+	case UnitYear:
+		return addMonths(anchor, 12*steps)
+	default:
+		return addMonths(anchor, steps)
+	}
+}
+
+// NextCycle returns the first renewal from cycle on that is not yet in the
+// past at now. Renewals missed while a contract was paused are skipped, not
+// billed all at once.
+func (i Interval) NextCycle(anchor time.Time, cycle int, now time.Time) int {
+	for i.At(anchor, cycle).Before(now) {
+		cycle++
+	}
+	return cycle
+}
+
+// addMonths moves t by months, keeping its day of month where the target
+// month has it and using the last day otherwise
+func addMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	first := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(day, last)-1)
+}
+
+// Price is what each unit costs on a plan percentOff its base price,
+// rounded to the nearest minor unit with halves up
+func Price(base int64, percentOff int) int64 {
+	return base - (base*int64(percentOff)+50)/100
+}
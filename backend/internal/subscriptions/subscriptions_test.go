@@ -0,0 +1,115 @@
+package subscriptions
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+}
+
+func TestIntervalValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval Interval
+		wantErr  bool
+	}{
+		{name: "monthly", interval: Interval{Unit: UnitMonth, Count: 1}},
+		{name: "every 365 days", interval: Interval{Unit: UnitDay, Count: MaxIntervalCount}},
+		{name: "unknown unit", interval: Interval{Unit: "fortnight", Count: 1}, wantErr: true},
+		{name: "zero count", interval: Interval{Unit: UnitWeek, Count: 0}, wantErr: true},
+		{name: "count too large", interval: Interval{Unit: UnitDay, Count: MaxIntervalCount + 1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.interval.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidInterval) {
+				t.Errorf("Validate() error = %v, want ErrInvalidInterval", err)
+			}
+		})
+	}
+}
+
+func TestIntervalAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval Interval
+		anchor   time.Time
+		n        int
+		want     time.Time
+	}{
+		{name: "first renewal is the anchor", interval: Interval{Unit: UnitMonth, Count: 1}, anchor: date(2026, 1, 15), n: 0, want: date(2026, 1, 15)},
+		{name: "days", interval: Interval{Unit: UnitDay, Count: 10}, anchor: date(2026, 1, 25), n: 1, want: date(2026, 2, 4)},
+		{name: "weeks", interval: Interval{Unit: UnitWeek, Count: 2}, anchor: date(2026, 1, 1), n: 3, want: date(2026, 2, 12)},
+		{name: "months", interval: Interval{Unit: UnitMonth, Count: 3}, anchor: date(2026, 1, 15), n: 2, want: date(2026, 7, 15)},
+		{name: "month end clamps", interval: Interval{Unit: UnitMonth, Count: 1}, anchor: date(2026, 1, 31), n: 1, want: date(2026, 2, 28)},
+		{name: "month end recovers", interval: Interval{Unit: UnitMonth, Count: 1}, anchor: date(2026, 1, 31), n: 2, want: date(2026, 3, 31)},
+		{name: "leap day yearly", interval: Interval{Unit: UnitYear, Count: 1}, anchor: date(2028, 2, 29), n: 1, want: date(2029, 2, 28)},
+		{name: "leap day every four years", interval: Interval{Unit: UnitYear, Count: 4}, anchor: date(2028, 2, 29), n: 1, want: date(2032, 2, 29)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.interval.At(tt.anchor, tt.n); !got.Equal(tt.want) {
+				t.Errorf("At(%v, %d) = %v, want %v", tt.anchor, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntervalNextCycle(t *testing.T) {
+	monthly := Interval{Unit: UnitMonth, Count: 1}
+	anchor := date(2026, 1, 10)
+
+	tests := []struct {
+		name  string
+		cycle int
+		now   time.Time
+		want  int
+	}{
+		{name: "still ahead", cycle: 1, now: date(2026, 1, 20), want: 1},
+		{name: "due right now", cycle: 1, now: date(2026, 2, 10), want: 1},
+		{name: "skips missed renewals", cycle: 1, now: date(2026, 5, 1), want: 4},
+		{name: "never goes back", cycle: 6, now: date(2026, 2, 1), want: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := monthly.NextCycle(anchor, tt.cycle, tt.now); got != tt.want {
+				t.Errorf("NextCycle(%d, %v) = %d, want %d", tt.cycle, tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrice(t *testing.T) {
+	tests := []struct {
+		base       int64
+		percentOff int
+		want       int64
+	}{
+		{base: 2000, percentOff: 0, want: 2000},
+		{base: 2000, percentOff: 15, want: 1700},
+		{base: 1995, percentOff: 10, want: 1795},
+		{base: 999, percentOff: 100, want: 0},
+	}
+	for _, tt := range tests {
+		if got := Price(tt.base, tt.percentOff); got != tt.want {
+			t.Errorf("Price(%d, %d) = %d, want %d", tt.base, tt.percentOff, got, tt.want)
+		}
+	}
+}
+
+func TestStatusIsValid(t *testing.T) {
+	for _, s := range []Status{StatusActive, StatusPaused, StatusCancelled, StatusFailed} {
+		if !s.IsValid() {
+			t.Errorf("%q.IsValid() = false", s)
+		}
+	}
+	if Status("expired").IsValid() {
+		t.Error(`"expired".IsValid() = true`)
+	}
+}
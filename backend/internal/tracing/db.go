@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// QueryTracer is a pgx tracer that runs every query, inside a transaction
+// or not, in a client span. sqlc starts each query with a
+// "-- name: GetUser :one" comment, and the span takes that name, so
+// repeated queries from one request line up in the trace. Queries outside
+// any trace, such as the worker polling for jobs, are not traced, or each
+// poll would start a trace of its own.
+//
+//	poolConfig, err := pgxpool.ParseConfig(dsn)
+//	poolConfig.ConnConfig.Tracer = tracing.QueryTracer{}
+type QueryTracer struct{}
+
+type querySpanKey struct{}
+
+func (QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	name := queryName(data.SQL)
+	ctx, span := Tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNamePostgreSQL,
+			semconv.DBOperationName(name),
+			semconv.DBQueryText(data.SQL),
+		),
+	)
+	return context.WithValue(ctx, querySpanKey{}, span)
+}
+
+func (QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	if span, ok := ctx.Value(querySpanKey{}).(trace.Span); ok {
+		End(span, data.Err)
+	}
+}
+
+// queryName is the sqlc query name from the leading "-- name:" comment, or
+// the statement's first keyword for hand-written SQL
+func queryName(query string) string {
+	query = strings.TrimSpace(query)
+	if rest, ok := strings.CutPrefix(query, "-- name:"); ok {
+		if fields := strings.Fields(rest); len(fields) > 0 {
+			return fields[0]
+		}
+	}
+	for strings.HasPrefix(query, "--") {
+		_, query, _ = strings.Cut(query, "\n")
+		query = strings.TrimSpace(query)
+	}
+	if fields := strings.Fields(query); len(fields) > 0 {
+		return strings.ToUpper(fields[0])
+	}
+	return "query"
+}
@@ -0,0 +1,107 @@
+// Package tracing exports OpenTelemetry traces over OTLP/HTTP and holds the
+// instrumentation the rest of the code shares: spans for SQL queries,
+// outbound HTTP and anything a caller wants timed. With no endpoint
+// configured spans are still created, against a no-op provider, so callers
+// never check whether tracing is on.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentation names the tracer every span here comes from
+const instrumentation = "github.com/dfodeker/terminus"
+
+// Config configures the exporter
+type Config struct {
+	// Endpoint is the collector's OTLP/HTTP base URL, such as
+	// http://otel-collector:4318. Traces are sent to its /v1/traces path.
+	// Tracing is off without one.
+	Endpoint string
+	// Headers are sent with every export, typically for authentication
+	Headers     map[string]string
+	ServiceName string
+	// SampleRatio is the fraction of new traces recorded, from 0 to 1.
+	// Requests arriving with a sampled trace parent are always recorded.
+	SampleRatio float64
+}
+
+// Enabled reports whether traces are exported
+func (c Config) Enabled() bool {
+	return c.Endpoint != ""
+}
+
+// Setup installs the global tracer provider and the W3C trace context
+// propagator. The returned shutdown flushes buffered spans and must be
+// called before the process exits.
+func Setup(ctx context.Context, cfg Config) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	if !cfg.Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx,
+		otlptracehttp.WithEndpointURL(strings.TrimSuffix(cfg.Endpoint, "/")+"/v1/traces"),
+		otlptracehttp.WithHeaders(cfg.Headers),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create OTLP exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(cfg.ServiceName),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer of the global provider
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentation)
+}
+
+// Start starts an internal span as a child of any span in ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on span, if there is one, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Transport wraps base, or http.DefaultTransport when nil, so each
+// outbound request runs in a client span and carries the trace context to
+// the server it calls
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base)
+}
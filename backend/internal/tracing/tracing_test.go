@@ -0,0 +1,122 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// record installs a tracer provider that keeps every ended span
+func record(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	return recorder
+}
+
+func TestQueryName(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: "-- name: GetUserByEmail :one\nSELECT * FROM users WHERE email = $1", want: "GetUserByEmail"},
+		{query: "\n  -- name: DeleteJob :exec\nDELETE FROM jobs", want: "DeleteJob"},
+		{query: "SELECT COALESCE(MAX(version_id), 0) FROM goose_db_version", want: "SELECT"},
+		{query: "-- refresh the view\nrefresh materialized view product_search", want: "REFRESH"},
+		{query: "", want: "query"},
+	}
+
+	for _, tt := range tests {
+		if got := queryName(tt.query); got != tt.want {
+			t.Errorf("queryName(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestQueryTracer(t *testing.T) {
+	recorder := record(t)
+	var tracer QueryTracer
+	query := func(ctx context.Context, sql string, err error) {
+		ctx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: sql})
+		tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: err})
+	}
+
+	// Not part of a trace, so not traced
+	query(context.Background(), "-- name: ClaimJobs :many\nUPDATE jobs SET locked_at = now()", nil)
+
+	ctx, parent := Start(context.Background(), "request")
+	query(ctx, "-- name: DeleteJob :exec\nDELETE FROM jobs WHERE id = $1", nil)
+	query(ctx, "-- name: ListJobs :many\nSELECT id FROM jobs", nil)
+	query(ctx, "-- name: GetMissing :one\nSELECT id FROM missing", errors.New(`relation "missing" does not exist`))
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 4 {
+		t.Fatalf("got %d spans, want 3 queries and the parent", len(spans))
+	}
+	for i, want := range []string{"DeleteJob", "ListJobs", "GetMissing"} {
+		span := spans[i]
+		if span.Name() != want {
+			t.Errorf("span %d = %q, want %q", i, span.Name(), want)
+		}
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("%s is not a child of the request span", span.Name())
+		}
+	}
+	if spans[1].Status().Code == codes.Error {
+		t.Errorf("ListJobs status = %v, want ok", spans[1].Status())
+	}
+	if spans[2].Status().Code != codes.Error {
+		t.Errorf("GetMissing status = %v, want error", spans[2].Status())
+	}
+}
+
+func TestTransport(t *testing.T) {
+	recorder := record(t)
+
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer srv.Close()
+
+	ctx, parent := Start(context.Background(), "job")
+	client := &http.Client{Transport: Transport(nil)}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL+"/hooks", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	parent.End()
+
+	if traceparent == "" {
+		t.Error("outbound request carried no traceparent header")
+	}
+	spans := recorder.Ended()
+	if len(spans) != 2 || spans[0].Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("got %d spans, want a client span under the job span", len(spans))
+	}
+}
+
+func TestSetupDisabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Config{ServiceName: "terminus-api", SampleRatio: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown() error = %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package usage
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/google/uuid"
+)
+
+// MeterStore is where a Meter flushes its counts
+type MeterStore interface {
+	AddUsage(ctx context.Context, arg database.AddUsageParams) error
+}
+
+// MeterConfig configures a Meter
+type MeterConfig struct {
+	// Interval is how often counts are flushed
+	Interval time.Duration
+	Logger   *slog.Logger
+}
+
+type meterKey struct {
+	tenantID uuid.UUID
+	metric   Metric
+	day      time.Time
+}
+
+// Meter counts usage in memory and flushes it to the database once an
+// interval, so metering a request costs no query. Counts not yet flushed
+// are lost if the process dies, which undercounts rather than bills twice.
+type Meter struct {
+	store MeterStore
+	cfg   MeterConfig
+	now   func() time.Time
+
+	mu     sync.Mutex
+	counts map[meterKey]int64
+}
+
+// NewMeter creates a meter flushing to store
+func NewMeter(store MeterStore, cfg MeterConfig) *Meter {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30 * time.Second
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &Meter{store: store, cfg: cfg, now: time.Now, counts: map[meterKey]int64{}}
+}
+
+// Add counts n of metric against the tenant today
+func (m *Meter) Add(tenantID uuid.UUID, metric Metric, n int64) {
+	key := meterKey{tenantID: tenantID, metric: metric, day: Day(m.now())}
+	m.mu.Lock()
+	m.counts[key] += n
+	m.mu.Unlock()
+}
+
+// Run flushes once an interval until ctx is cancelled, then flushes what
+// is left
+func (m *Meter) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := m.Flush(flushCtx); err != nil {
+				m.cfg.Logger.Error("usage flush on shutdown failed", "error", err)
+			}
+			return nil
+		case <-ticker.C:
+			if err := m.Flush(ctx); err != nil && ctx.Err() == nil {
+				m.cfg.Logger.Error("usage flush failed", "error", err)
+			}
+		}
+	}
+}
+
+// Flush adds the counts so far to the database. Counts it couldn't write
+// are kept for the next flush.
+func (m *Meter) Flush(ctx context.Context) error {
+	m.mu.Lock()
+	counts := m.counts
+	m.counts = map[meterKey]int64{}
+	m.mu.Unlock()
+
+	var firstErr error
+	for key, n := range counts {
+		if firstErr == nil {
+			firstErr = m.store.AddUsage(ctx, database.AddUsageParams{
+				TenantID: key.tenantID,
+				Metric:   string(key.metric),
+				Day:      key.day,
+				Quantity: n,
+			})
+			if firstErr == nil {
+				continue
+			}
+		}
+		m.mu.Lock()
+		m.counts[key] += n
+		m.mu.Unlock()
+	}
+	return firstErr
+}
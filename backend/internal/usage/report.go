@@ -0,0 +1,157 @@
+package usage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/billing"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Invoicer adds overage to a customer's next invoice. *billing.Client is
+// one.
+type Invoicer interface {
+	OveragePrice(metric string) (string, bool)
+	CreateInvoiceItem(ctx context.Context, item billing.InvoiceItem) (string, error)
+}
+
+// ReporterConfig configures a Reporter
+type ReporterConfig struct {
+	Interval time.Duration
+	Logger   *slog.Logger
+}
+
+// Reporter bills each subscribed tenant's use over its allowance once a
+// month ends, as items on its next Stripe invoice. Allowances are those of
+// the plan the tenant is on when the month is billed. Each month's
+// overage of a metric is claimed before it is billed and Stripe is asked
+// with an idempotency key, so running several at once is harmless.
+type Reporter struct {
+	pool     *pgxpool.Pool
+	db       *database.Queries
+	invoicer Invoicer
+	cfg      ReporterConfig
+}
+
+// NewReporter creates a reporter over pool billing through invoicer
+func NewReporter(pool *pgxpool.Pool, invoicer Invoicer, cfg ReporterConfig) *Reporter {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &Reporter{pool: pool, db: database.New(pool), invoicer: invoicer, cfg: cfg}
+}
+
+// Run bills the month before the current one once an interval until ctx is
+// cancelled. Months already billed are skipped.
+func (r *Reporter) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		thisMonth, _ := Month(time.Now())
+		n, err := r.ReportMonth(ctx, thisMonth.AddDate(0, -1, 0))
+		if err != nil && ctx.Err() == nil {
+			r.cfg.Logger.Error("usage overage report failed", "error", err)
+		} else if n > 0 {
+			r.cfg.Logger.Info("usage overage billed", "items", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// ReportMonth bills the overage of the month starting at month and
+// returns how many invoice items it added
+func (r *Reporter) ReportMonth(ctx context.Context, month time.Time) (int, error) {
+	month, next := Month(month)
+	rows, err := r.db.ListBillableUsage(ctx, database.ListBillableUsageParams{FromDay: month, ToDay: next})
+	if err != nil {
+		return 0, err
+	}
+
+	var n int
+	for _, row := range rows {
+		m := Metric(row.Metric)
+		if !m.Valid() {
+			continue
+		}
+		price, ok := r.invoicer.OveragePrice(string(m))
+		if !ok {
+			continue
+		}
+		used := m.Used(row.Total, row.Peak)
+		included := AllowanceFor(row.AppliedPlan)[m]
+		units := OverageUnits(m, used, included)
+		if units == 0 {
+			continue
+		}
+		billed, err := r.bill(ctx, row, month, m, used, included, units, price)
+		if err != nil {
+			return n, err
+		}
+		if billed {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// bill claims a month's overage of m for the tenant and adds it to the
+// tenant's invoice, reporting false when it was already billed. The claim
+// is rolled back if Stripe can't be reached, so the next run tries again.
+func (r *Reporter) bill(ctx context.Context, row database.ListBillableUsageRow, month time.Time, m Metric, used, included, units int64, price string) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+	q := r.db.WithTx(tx)
+
+	_, err = q.ClaimOverageReport(ctx, database.ClaimOverageReportParams{
+		TenantID: row.TenantID,
+		Month:    month,
+		Metric:   string(m),
+		Used:     used,
+		Included: included,
+		Units:    units,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	period := month.Format("2006-01")
+	itemID, err := r.invoicer.CreateInvoiceItem(ctx, billing.InvoiceItem{
+		CustomerID:     row.StripeCustomerID,
+		PriceID:        price,
+		Quantity:       units,
+		Description:    fmt.Sprintf("%s overage for %s: %d used, %d included", m, period, used, included),
+		IdempotencyKey: fmt.Sprintf("overage-%s-%s-%s", row.TenantID, period, m),
+	})
+	if err != nil {
+		return false, err
+	}
+	if err := q.SetOverageReportInvoiceItem(ctx, database.SetOverageReportInvoiceItemParams{
+		TenantID:            row.TenantID,
+		Month:               month,
+		Metric:              string(m),
+		StripeInvoiceItemID: sql.NullString{String: itemID, Valid: true},
+	}); err != nil {
+		return false, err
+	}
+	return true, tx.Commit(ctx)
+}
@@ -0,0 +1,70 @@
+package usage
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+)
+
+// TallierConfig configures a Tallier
+type TallierConfig struct {
+	Interval time.Duration
+	Logger   *slog.Logger
+}
+
+// Tallier records the usage the database already holds: it recounts each
+// tenant's orders and measures its stored media. Yesterday's orders are
+// recounted too, so orders placed just before midnight are not missed.
+// Each tally overwrites the last, so running several at once is harmless.
+type Tallier struct {
+	db  *database.Queries
+	cfg TallierConfig
+}
+
+// NewTallier creates a tallier over db
+func NewTallier(db *database.Queries, cfg TallierConfig) *Tallier {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 15 * time.Minute
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &Tallier{db: db, cfg: cfg}
+}
+
+// Run tallies once an interval until ctx is cancelled
+func (t *Tallier) Run(ctx context.Context) error {
+	ticker := time.NewTicker(t.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := t.Tally(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			t.cfg.Logger.Error("usage tally failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tally records the orders of yesterday and today and the storage in use
+// at now
+func (t *Tallier) Tally(ctx context.Context, now time.Time) error {
+	today := Day(now)
+	for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+		if _, err := t.db.TallyOrderUsage(ctx, database.TallyOrderUsageParams{
+			Day:      day,
+			FromTime: day,
+			ToTime:   day.AddDate(0, 0, 1),
+		}); err != nil {
+			return err
+		}
+	}
+	_, err := t.db.TallyStorageUsage(ctx, today)
+	return err
+}
@@ -0,0 +1,127 @@
+// Package usage meters what each tenant uses, its API calls, the bytes of
+// media it stores and the orders it takes, into daily records. Each plan
+// includes a monthly allowance of every metric, and use over it is billed
+// as overage on the tenant's next Stripe invoice.
+package usage
+
+import (
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+)
+
+// Metric is something tenants are metered for
+type Metric string
+
+const (
+	MetricAPICalls     Metric = "api_calls"
+	MetricStorageBytes Metric = "storage_bytes"
+	MetricOrders       Metric = "orders"
+)
+
+// Metrics are every metric, in the order they are reported
+var Metrics = []Metric{MetricAPICalls, MetricStorageBytes, MetricOrders}
+
+// Valid reports whether m is a known metric
+func (m Metric) Valid() bool {
+	for _, known := range Metrics {
+		if m == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Unit is how much of m one unit of overage is: a thousand API calls, a
+// gibibyte of storage or a single order
+func (m Metric) Unit() int64 {
+	switch m {
+	case MetricAPICalls:
+		return 1000
+	case MetricStorageBytes:
+		return 1 << 30
+	}
+	return 1
+}
+
+// Used is a month's use of m from the total and peak of its days. Storage
+// is a level, so a month uses its highest day; the others are counts added
+// up over the month.
+func (m Metric) Used(total, peak int64) int64 {
+	if m == MetricStorageBytes {
+		return peak
+	}
+	return total
+}
+
+// Allowance is how much of each metric a plan includes a month
+type Allowance map[Metric]int64
+
+// DefaultAllowances are the allowances of each plan
+var DefaultAllowances = map[string]Allowance{
+	"free": {MetricAPICalls: 100_000, MetricStorageBytes: 1 << 30, MetricOrders: 100},
+	"pro":  {MetricAPICalls: 5_000_000, MetricStorageBytes: 50 << 30, MetricOrders: 10_000},
+}
+
+// AllowanceFor returns the allowance of plan, or the free plan's when plan
+// is unknown
+func AllowanceFor(plan string) Allowance {
+	if a, ok := DefaultAllowances[plan]; ok {
+		return a
+	}
+	return DefaultAllowances["free"]
+}
+
+// OverageUnits is how many units of m are billed for using used when
+// included is allowed. A part unit is billed whole.
+func OverageUnits(m Metric, used, included int64) int64 {
+	if used <= included {
+		return 0
+	}
+	unit := m.Unit()
+	return (used - included + unit - 1) / unit
+}
+
+// Day returns the UTC day t falls on
+func Day(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// Month returns the first UTC day of t's month and of the month after
+func Month(t time.Time) (time.Time, time.Time) {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// Total is a month's use of a metric against the allowance of a plan
+type Total struct {
+	Metric       Metric
+	Used         int64
+	Included     int64
+	OverageUnits int64
+}
+
+// Totals sums a month of daily rows into a total per metric, against the
+// allowance of plan
+func Totals(rows []database.ListTenantUsageRow, plan string) []Total {
+	sums := map[Metric]int64{}
+	peaks := map[Metric]int64{}
+	for _, row := range rows {
+		m := Metric(row.Metric)
+		sums[m] += row.Quantity
+		peaks[m] = max(peaks[m], row.Quantity)
+	}
+	allowance := AllowanceFor(plan)
+	totals := make([]Total, 0, len(Metrics))
+	for _, m := range Metrics {
+		used := m.Used(sums[m], peaks[m])
+		totals = append(totals, Total{
+			Metric:       m,
+			Used:         used,
+			Included:     allowance[m],
+			OverageUnits: OverageUnits(m, used, allowance[m]),
+		})
+	}
+	return totals
+}
@@ -0,0 +1,177 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/google/uuid"
+)
+
+func TestOverageUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		metric   Metric
+		used     int64
+		included int64
+		want     int64
+	}{
+		{name: "under allowance", metric: MetricOrders, used: 90, included: 100, want: 0},
+		{name: "at allowance", metric: MetricOrders, used: 100, included: 100, want: 0},
+		{name: "orders over", metric: MetricOrders, used: 103, included: 100, want: 3},
+		{name: "api calls part unit", metric: MetricAPICalls, used: 100_001, included: 100_000, want: 1},
+		{name: "api calls whole units", metric: MetricAPICalls, used: 102_000, included: 100_000, want: 2},
+		{name: "storage", metric: MetricStorageBytes, used: 3<<30 + 1, included: 1 << 30, want: 3},
+	}
+	for _, tt := range tests {
+		if got := OverageUnits(tt.metric, tt.used, tt.included); got != tt.want {
+			t.Errorf("%s: OverageUnits() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMonth(t *testing.T) {
+	tests := []struct {
+		in        time.Time
+		wantStart time.Time
+		wantNext  time.Time
+	}{
+		{
+			in:        time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+			wantStart: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+			wantNext:  time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			in:        time.Date(2026, 12, 31, 23, 59, 0, 0, time.UTC),
+			wantStart: time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC),
+			wantNext:  time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			// still the 31st of December in UTC
+			in:        time.Date(2027, 1, 1, 1, 0, 0, 0, time.FixedZone("CET", 2*60*60)),
+			wantStart: time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC),
+			wantNext:  time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		start, next := Month(tt.in)
+		if !start.Equal(tt.wantStart) || !next.Equal(tt.wantNext) {
+			t.Errorf("Month(%v) = %v, %v, want %v, %v", tt.in, start, next, tt.wantStart, tt.wantNext)
+		}
+	}
+}
+
+func TestTotals(t *testing.T) {
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	rows := []database.ListTenantUsageRow{
+		{Metric: string(MetricOrders), Day: day, Quantity: 60},
+		{Metric: string(MetricOrders), Day: day.AddDate(0, 0, 1), Quantity: 45},
+		{Metric: string(MetricStorageBytes), Day: day, Quantity: 2 << 30},
+		{Metric: string(MetricStorageBytes), Day: day.AddDate(0, 0, 1), Quantity: 1 << 30},
+	}
+
+	tests := []struct {
+		plan string
+		want []Total
+	}{
+		{
+			plan: "free",
+			want: []Total{
+				{Metric: MetricAPICalls, Included: 100_000},
+				{Metric: MetricStorageBytes, Used: 2 << 30, Included: 1 << 30, OverageUnits: 1},
+				{Metric: MetricOrders, Used: 105, Included: 100, OverageUnits: 5},
+			},
+		},
+		{
+			plan: "pro",
+			want: []Total{
+				{Metric: MetricAPICalls, Included: 5_000_000},
+				{Metric: MetricStorageBytes, Used: 2 << 30, Included: 50 << 30},
+				{Metric: MetricOrders, Used: 105, Included: 10_000},
+			},
+		},
+		{
+			plan: "unknown",
+			want: []Total{
+				{Metric: MetricAPICalls, Included: 100_000},
+				{Metric: MetricStorageBytes, Used: 2 << 30, Included: 1 << 30, OverageUnits: 1},
+				{Metric: MetricOrders, Used: 105, Included: 100, OverageUnits: 5},
+			},
+		},
+	}
+	for _, tt := range tests {
+		got := Totals(rows, tt.plan)
+		if len(got) != len(tt.want) {
+			t.Fatalf("%s: Totals() = %+v, want %+v", tt.plan, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: Totals()[%d] = %+v, want %+v", tt.plan, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+type fakeMeterStore struct {
+	err   error
+	added map[meterKey]int64
+}
+
+func (s *fakeMeterStore) AddUsage(ctx context.Context, arg database.AddUsageParams) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.added[meterKey{tenantID: arg.TenantID, metric: Metric(arg.Metric), day: arg.Day}] += arg.Quantity
+	return nil
+}
+
+func TestMeterFlush(t *testing.T) {
+	now := time.Date(2026, 10, 18, 23, 59, 0, 0, time.UTC)
+	today := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	tenantA, tenantB := uuid.New(), uuid.New()
+
+	store := &fakeMeterStore{added: map[meterKey]int64{}}
+	m := NewMeter(store, MeterConfig{})
+	m.now = func() time.Time { return now }
+
+	m.Add(tenantA, MetricAPICalls, 1)
+	m.Add(tenantA, MetricAPICalls, 1)
+	m.Add(tenantB, MetricAPICalls, 1)
+
+	// a failed flush keeps its counts for the next
+	store.err = errors.New("connection refused")
+	if err := m.Flush(context.Background()); err == nil {
+		t.Fatal("Flush() succeeded with a failing store")
+	}
+	now = now.Add(2 * time.Minute)
+	m.Add(tenantA, MetricAPICalls, 1)
+
+	store.err = nil
+	if err := m.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	want := map[meterKey]int64{
+		{tenantID: tenantA, metric: MetricAPICalls, day: today}:                  2,
+		{tenantID: tenantB, metric: MetricAPICalls, day: today}:                  1,
+		{tenantID: tenantA, metric: MetricAPICalls, day: today.AddDate(0, 0, 1)}: 1,
+	}
+	if len(store.added) != len(want) {
+		t.Fatalf("added %v, want %v", store.added, want)
+	}
+	for key, n := range want {
+		if store.added[key] != n {
+			t.Errorf("added %v = %d, want %d", key, store.added[key], n)
+		}
+	}
+
+	// nothing is left to add twice
+	store.added = map[meterKey]int64{}
+	if err := m.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if len(store.added) != 0 {
+		t.Errorf("second Flush() added %v", store.added)
+	}
+}
@@ -0,0 +1,151 @@
+// Package validate checks request inputs and collects every failure, so a
+// client learns about all of a request's problems from one response
+// instead of fixing them one round trip at a time.
+//
+//	var v validate.Validator
+//	v.Required("name", in.Name)
+//	if v.Required("handle", in.Handle) {
+//		v.Handle("handle", in.Handle)
+//	}
+//	v.OneOf("status", in.Status, "active", "draft", "archived")
+//	if err := v.Err(); err != nil {
+//		return err
+//	}
+//
+// Each check returns whether it passed, so dependent checks can be skipped
+// and a field reports its first failure rather than a pile of them.
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dfodeker/terminus/internal/problem"
+)
+
+// ErrInvalid is matched by every *Error with errors.Is
+var ErrInvalid = errors.New("invalid input")
+
+// Field error codes
+const (
+	CodeRequired   = "required"
+	CodeTooShort   = "too_short"
+	CodeTooLong    = "too_long"
+	CodeFormat     = "format"
+	CodeOneOf      = "one_of"
+	CodeOutOfRange = "out_of_range"
+	CodeInvalid    = "invalid"
+)
+
+// MaxHandleLength bounds handles, which appear in URLs
+const MaxHandleLength = 255
+
+var handlePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Error lists the fields that failed validation
+type Error struct {
+	Fields []problem.FieldError
+}
+
+func (e *Error) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Field + ": " + f.Message
+	}
+	return "invalid input: " + strings.Join(msgs, "; ")
+}
+
+func (e *Error) Unwrap() error { return ErrInvalid }
+
+// Validator collects field errors. The zero value is ready to use.
+type Validator struct {
+	fields []problem.FieldError
+	failed map[string]bool
+}
+
+// Fail records a failure of field. Only the first failure of a field is
+// kept.
+func (v *Validator) Fail(field, code, message string) {
+	if v.failed[field] {
+		return
+	}
+	if v.failed == nil {
+		v.failed = map[string]bool{}
+	}
+	v.failed[field] = true
+	v.fields = append(v.fields, problem.FieldError{Field: field, Code: code, Message: message})
+}
+
+// Check records a failure with code and message unless ok
+func (v *Validator) Check(ok bool, field, code, message string) bool {
+	if !ok {
+		v.Fail(field, code, message)
+	}
+	return ok
+}
+
+// Required fails on an empty or all-whitespace value
+func (v *Validator) Required(field, value string) bool {
+	return v.Check(strings.TrimSpace(value) != "", field, CodeRequired, "is required")
+}
+
+// MaxLength fails on a value of more than max characters
+func (v *Validator) MaxLength(field, value string, max int) bool {
+	return v.Check(utf8.RuneCountInString(value) <= max, field, CodeTooLong,
+		fmt.Sprintf("must be at most %d characters", max))
+}
+
+// Handle fails unless value is lowercase letters and digits in
+// dash-separated words, such as summer-sale-2025, of at most
+// MaxHandleLength characters
+func (v *Validator) Handle(field, value string) bool {
+	return v.MaxLength(field, value, MaxHandleLength) &&
+		v.Check(handlePattern.MatchString(value), field, CodeFormat,
+			"must be lowercase letters, digits and single dashes, such as summer-sale")
+}
+
+// Email fails unless value is a bare email address
+func (v *Validator) Email(field, value string) bool {
+	addr, err := mail.ParseAddress(value)
+	return v.Check(err == nil && addr.Address == strings.TrimSpace(value), field, CodeFormat,
+		"must be a valid email address")
+}
+
+// OneOf fails unless value is one of allowed
+func (v *Validator) OneOf(field, value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	v.Fail(field, CodeOneOf, "must be one of "+strings.Join(allowed, ", "))
+	return false
+}
+
+// Between fails unless min <= value <= max
+func (v *Validator) Between(field string, value, min, max int64) bool {
+	return v.Check(value >= min && value <= max, field, CodeOutOfRange,
+		fmt.Sprintf("must be between %d and %d", min, max))
+}
+
+// Min fails unless value is at least min
+func (v *Validator) Min(field string, value, min int64) bool {
+	return v.Check(value >= min, field, CodeOutOfRange, fmt.Sprintf("must be at least %d", min))
+}
+
+// Valid reports whether every check so far has passed
+func (v *Validator) Valid() bool {
+	return len(v.fields) == 0
+}
+
+// Err returns an *Error listing every failure, or nil
+func (v *Validator) Err() error {
+	if v.Valid() {
+		return nil
+	}
+	return &Error{Fields: v.fields}
+}
@@ -0,0 +1,102 @@
+package validate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidator(t *testing.T) {
+	tests := []struct {
+		name  string
+		check func(v *Validator)
+		want  map[string]string
+	}{
+		{
+			name: "all pass",
+			check: func(v *Validator) {
+				v.Required("name", "Shirt")
+				v.Handle("handle", "summer-sale-2025")
+				v.Email("email", "ada@example.com")
+				v.OneOf("status", "draft", "active", "draft", "archived")
+				v.Between("price_cents", 0, 0, 100)
+				v.MaxLength("sku", "ÄÖÜ", 3)
+			},
+			want: map[string]string{},
+		},
+		{
+			name: "every failure is reported",
+			check: func(v *Validator) {
+				v.Required("name", "  ")
+				v.Handle("handle", "Summer Sale")
+				v.Email("email", "ada@")
+				v.OneOf("status", "live", "active", "draft")
+				v.Min("price_cents", -1, 0)
+				v.MaxLength("sku", strings.Repeat("x", 101), 100)
+			},
+			want: map[string]string{
+				"name":        CodeRequired,
+				"handle":      CodeFormat,
+				"email":       CodeFormat,
+				"status":      CodeOneOf,
+				"price_cents": CodeOutOfRange,
+				"sku":         CodeTooLong,
+			},
+		},
+		{
+			name: "first failure per field wins",
+			check: func(v *Validator) {
+				v.Required("handle", "")
+				v.Handle("handle", "")
+			},
+			want: map[string]string{"handle": CodeRequired},
+		},
+		{
+			name: "handle rules",
+			check: func(v *Validator) {
+				v.Handle("double_dash", "a--b")
+				v.Handle("trailing_dash", "shirt-")
+				v.Handle("too_long", strings.Repeat("a", MaxHandleLength+1))
+			},
+			want: map[string]string{"double_dash": CodeFormat, "trailing_dash": CodeFormat, "too_long": CodeTooLong},
+		},
+		{
+			name: "email with a display name",
+			check: func(v *Validator) {
+				v.Email("email", "Ada <ada@example.com>")
+			},
+			want: map[string]string{"email": CodeFormat},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Validator
+			tt.check(&v)
+
+			err := v.Err()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Err() = %v, want nil", err)
+				}
+				return
+			}
+			var vErr *Error
+			if !errors.As(err, &vErr) || !errors.Is(err, ErrInvalid) {
+				t.Fatalf("Err() = %v, want *Error matching ErrInvalid", err)
+			}
+			got := map[string]string{}
+			for _, f := range vErr.Fields {
+				got[f.Field] = f.Code
+			}
+			if len(got) != len(vErr.Fields) || len(got) != len(tt.want) {
+				t.Fatalf("fields = %+v, want %v", vErr.Fields, tt.want)
+			}
+			for field, code := range tt.want {
+				if got[field] != code {
+					t.Errorf("%s = %q, want %q", field, got[field], code)
+				}
+			}
+		})
+	}
+}
@@ -2,24 +2,49 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 
 	"log"
 	"net/http"
+
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/validate"
 )
 
+// respondWithError writes an RFC 7807 problem with the generic code for
+// the status and msg as the detail. err, if any, is logged.
 func respondWithError(w http.ResponseWriter, code int, msg string, err error) {
+	respondWithErrorCode(w, code, "", msg, err)
+}
+
+// respondWithErrorCode is respondWithError with a specific problem code,
+// for failures clients need to tell apart from others with the same status
+func respondWithErrorCode(w http.ResponseWriter, status int, code, msg string, err error) {
+	respondWithProblem(w, problem.New(status, code, msg), err)
+}
+
+// respondWithValidationError answers a request that failed validation,
+// err being a *validate.Error, with a 422 listing every field at fault
+func respondWithValidationError(w http.ResponseWriter, err error) {
+	var invalid *validate.Error
+	if !errors.As(err, &invalid) {
+		respondWithError(w, http.StatusBadRequest, "The request is invalid", err)
+		return
+	}
+	p := problem.New(http.StatusUnprocessableEntity, problem.CodeValidationFailed, "The request has invalid fields")
+	p.Errors = invalid.Fields
+	respondWithProblem(w, p, nil)
+}
+
+// respondWithProblem writes p, logging err if there is one
+func respondWithProblem(w http.ResponseWriter, p *problem.Problem, err error) {
 	if err != nil {
 		log.Println(err)
 	}
-	if code > 499 {
-		log.Printf("Responding with 5XX error: %s", msg)
-	}
-	type errorResponse struct {
-		Error string `json:"error"`
+	if p.Status > 499 {
+		log.Printf("Responding with 5XX error: %s", p.Detail)
 	}
-	respondWithJSON(w, code, errorResponse{
-		Error: msg,
-	})
+	problem.Write(w, p)
 }
 
 func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/dfodeker/terminus/internal/crypto"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/lockout"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/google/uuid"
+)
+
+// loginSubject is who a sign-in was for, as its security events record it.
+// The IDs are left nil when the email matched no account.
+type loginSubject struct {
+	UserID     uuid.UUID
+	CustomerID uuid.UUID
+	StoreID    uuid.UUID
+	Email      string
+}
+
+// userLoginAttempt is a staff sign-in to email from the client of r
+func userLoginAttempt(r *http.Request, email string) lockout.Attempt {
+	return lockout.Attempt{
+		Account: "user:" + normalizeEmail(email),
+		IP:      middleware.ClientIP(r),
+	}
+}
+
+// customerLoginAttempt is a storefront sign-in to email at a store. Each
+// store's customers are separate accounts, so each is counted on its own.
+func customerLoginAttempt(r *http.Request, storeID uuid.UUID, email string) lockout.Attempt {
+	return lockout.Attempt{
+		Account: "customer:" + storeID.String() + ":" + normalizeEmail(email),
+		IP:      middleware.ClientIP(r),
+	}
+}
+
+// checkLoginLock turns away an attempt on a locked account or from a
+// locked IP. It returns false, having written the response, when the
+// attempt must stop.
+func (cfg *apiConfig) checkLoginLock(w http.ResponseWriter, r *http.Request, a lockout.Attempt) (lockout.Status, bool) {
+	status, err := cfg.lockout.Check(r.Context(), a)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to sign in", err)
+		return lockout.Status{}, false
+	}
+	if status.Locked() {
+		respondLocked(w, status)
+		return status, false
+	}
+	return status, true
+}
+
+// checkLoginCaptcha asks for a solved CAPTCHA once an account has failed
+// enough sign-ins in a row. Nothing is asked when no CAPTCHA is set up.
+func (cfg *apiConfig) checkLoginCaptcha(w http.ResponseWriter, r *http.Request, status lockout.Status, token string, who loginSubject) bool {
+	if cfg.captcha == nil || status.Failures < cfg.config.Captcha.After {
+		return true
+	}
+	ok, err := cfg.captcha.Verify(r.Context(), token, middleware.ClientIP(r))
+	if err != nil {
+		respondWithError(w, http.StatusServiceUnavailable, "Unable to check the CAPTCHA, please try again", err)
+		return false
+	}
+	if !ok {
+		if token != "" {
+			cfg.recordSecurityEvent(r, lockout.EventCaptchaFailed, who)
+		}
+		respondWithErrorCode(w, http.StatusUnauthorized, problem.CodeCaptchaRequired,
+			"Solve the CAPTCHA and send its response as captcha_token", nil)
+		return false
+	}
+	return true
+}
+
+// failLogin counts a failed sign-in and records it, along with the lockout
+// it caused if it was the last straw. The caller still answers the request
+// as it would have.
+func (cfg *apiConfig) failLogin(r *http.Request, a lockout.Attempt, kind string, who loginSubject) {
+	cfg.recordSecurityEvent(r, kind, who)
+	status, err := cfg.lockout.Fail(r.Context(), a)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to count failed sign-in",
+			"request_id", middleware.GetRequestID(r.Context()),
+			"error", err,
+		)
+		return
+	}
+	if status.Locked() {
+		cfg.recordSecurityEvent(r, lockout.EventLocked, who)
+		slog.WarnContext(r.Context(), "sign-in locked after repeated failures",
+			"request_id", middleware.GetRequestID(r.Context()),
+			"user_id", who.UserID,
+			"customer_id", who.CustomerID,
+			"failures", status.Failures,
+			"locked_for", status.LockedFor,
+		)
+	}
+}
+
+// succeedLogin forgets an account's failures once it has fully signed in
+func (cfg *apiConfig) succeedLogin(r *http.Request, a lockout.Attempt) {
+	if err := cfg.lockout.Succeed(r.Context(), a); err != nil {
+		slog.ErrorContext(r.Context(), "failed to clear failed sign-ins",
+			"request_id", middleware.GetRequestID(r.Context()),
+			"error", err,
+		)
+	}
+}
+
+// recordSecurityEvent stores a sign-in event for platform staff to review.
+// A failure to store it is logged rather than failing the sign-in.
+func (cfg *apiConfig) recordSecurityEvent(r *http.Request, kind string, who loginSubject) {
+	meta := sessionMetaFromRequest(r)
+	err := cfg.db.CreateSecurityEvent(r.Context(), database.CreateSecurityEventParams{
+		Kind:       kind,
+		UserID:     uuid.NullUUID{UUID: who.UserID, Valid: who.UserID != uuid.Nil},
+		CustomerID: uuid.NullUUID{UUID: who.CustomerID, Valid: who.CustomerID != uuid.Nil},
+		StoreID:    uuid.NullUUID{UUID: who.StoreID, Valid: who.StoreID != uuid.Nil},
+		Email:      crypto.NullText{String: who.Email, Valid: who.Email != ""},
+		Ip:         sql.NullString{String: meta.IPAddress, Valid: meta.IPAddress != ""},
+		UserAgent:  sql.NullString{String: meta.UserAgent, Valid: meta.UserAgent != ""},
+	})
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to record security event",
+			"request_id", middleware.GetRequestID(r.Context()),
+			"kind", kind,
+			"error", err,
+		)
+	}
+}
+
+// respondLocked answers a sign-in that must wait out a lockout
+func respondLocked(w http.ResponseWriter, status lockout.Status) {
+	seconds := int(math.Ceil(status.LockedFor.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+	respondWithErrorCode(w, http.StatusTooManyRequests, problem.CodeAccountLocked,
+		fmt.Sprintf("Too many failed sign-ins, try again in %d minutes", max((seconds+59)/60, 1)), nil)
+}
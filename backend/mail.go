@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/mailer"
+)
+
+// enqueueEmail renders a templated email and queues it for the worker.
+// Pass the transaction's queries so nothing is sent if it rolls back.
+func (cfg *apiConfig) enqueueEmail(ctx context.Context, q *database.Queries, to string, tmpl mailer.Template, data any) error {
+	msg, err := mailer.Render(tmpl, data)
+	if err != nil {
+		return err
+	}
+	msg.To = []string{to}
+	return mailer.Enqueue(ctx, cfg.jobs, q, msg)
+}
+
+// queueOrderEmail queues one of the customer's order emails. The worker
+// renders it, so pass the transaction's queries and it goes out with the
+// change that prompted it.
+func (cfg *apiConfig) queueOrderEmail(ctx context.Context, q *database.Queries, args mailer.OrderEmailArgs) error {
+	_, err := cfg.jobs.EnqueueTx(ctx, q, args)
+	return err
+}
@@ -2,104 +2,250 @@ package main
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"log"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
-	"strconv"
+	"os/signal"
+	"sync"
 	"sync/atomic"
+	"syscall"
 	"time"
 
+	"github.com/dfodeker/terminus/graph"
+	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/billing"
+	"github.com/dfodeker/terminus/internal/captcha"
+	"github.com/dfodeker/terminus/internal/certs"
+	"github.com/dfodeker/terminus/internal/config"
+	"github.com/dfodeker/terminus/internal/crypto"
+	"github.com/dfodeker/terminus/internal/crypto/keystore"
 	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/dbpool"
 	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/health"
+	"github.com/dfodeker/terminus/internal/jobs"
+	"github.com/dfodeker/terminus/internal/labels"
+	"github.com/dfodeker/terminus/internal/lockout"
 	"github.com/dfodeker/terminus/internal/metrics"
+	"github.com/dfodeker/terminus/internal/oauth"
+	"github.com/dfodeker/terminus/internal/payments"
+	"github.com/dfodeker/terminus/internal/ratelimit"
+	"github.com/dfodeker/terminus/internal/search"
+	"github.com/dfodeker/terminus/internal/sso"
+	"github.com/dfodeker/terminus/internal/storage"
+	"github.com/dfodeker/terminus/internal/tracing"
+	"github.com/dfodeker/terminus/internal/usage"
 	mw "github.com/dfodeker/terminus/middleware"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/httprate"
-	"github.com/joho/godotenv"
-	_ "github.com/lib/pq"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"golang.org/x/crypto/acme/autocert"
 )
 
 type apiConfig struct {
 	fileServerHits atomic.Int32
-	platform       string
-	db             *database.Queries
-	port           string
-	signingKey     string
-	sqlDB          *sql.DB
-	gidGen         *gid.Generator
-	baseDomain     string
+	// config is the validated settings the server started with
+	config *config.Config
+	db     *database.Queries
+	// reads runs listings and reports on the read replica while it
+	// answers, and on the primary otherwise. Replicas lag, so a request
+	// reading what it just wrote uses db.
+	reads *database.Queries
+	// jwtKeys signs and verifies access tokens
+	jwtKeys  *auth.KeySet
+	pool     *pgxpool.Pool
+	gidGen   *gid.Generator
+	jobs     *jobs.Client
+	storage  storage.Storage
+	search   search.Engine
+	services services
+	// payments are the providers subscriptions can be charged through
+	payments payments.Gateways
+	// labels are the providers shipping labels can be bought from
+	labels labels.Providers
+	// billing sells plans through Stripe, or is nil when Stripe isn't
+	// configured and stores keep the plan staff give them
+	billing *billing.Client
+	// health runs the dependency checks behind /health/ready
+	health *health.Checker
+	// limiter counts requests against their rate limits, and quotas
+	// caches the plan quota of each caller
+	limiter *ratelimit.Limiter
+	quotas  *ratelimit.QuotaCache
+	// meter counts each tenant's API calls
+	meter *usage.Meter
+	// keys holds the data keys personal data is sealed under, and keyring
+	// seals and opens with them
+	keys    *keystore.Store
+	keyring *crypto.Keyring
+	// lockout counts failed sign-ins, and captcha checks the CAPTCHA an
+	// account that keeps failing must solve, or is nil when none is set up
+	lockout *lockout.Limiter
+	captcha captcha.Verifier
+	// oauth are the providers users can sign in through
+	oauth oauth.Providers
+	// resolver looks up the TXT records tenants prove their SSO domains
+	// with
+	resolver sso.Resolver
+	// openAPI is the encoded OpenAPI document of the API host
+	openAPI []byte
+	// graphQL executes GraphQL Admin API requests
+	graphQL http.Handler
 }
 
 func main() {
-	godotenv.Load()
-
-	port := os.Getenv("API_PORT")
-	if port == "" {
-		port = "8080"
+	if len(os.Args) > 1 && os.Args[1] == "openapi" {
+		if err := runOpenAPI(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
 
-	platform := os.Getenv("PLATFORM")
-	if platform == "" {
-		log.Fatal("PLATFORM MUST BE SET")
+	lookup, err := config.Env()
+	if err != nil {
+		log.Fatal(err)
+	}
+	cfg, err := config.LoadAPI(lookup)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	signingKey := os.Getenv("SIGNING_KEY")
-	if signingKey == "" {
-		log.Fatal("SIGNING_KEY must be set")
+	jwtKeys, err := auth.LoadKeySet(cfg.JWTKeys)
+	if err != nil {
+		log.Fatalf("Unable to load JWT keys: %v", err)
+	}
+	if cfg.JWTKeys.PrivateKeyFile == "" {
+		log.Println("JWT_PRIVATE_KEY_FILE is not set, signing tokens with a key derived from SIGNING_KEY")
 	}
 
-	dbURL := os.Getenv("DB_URL")
-	if dbURL == "" {
-		log.Fatal("DB_Url Must BE SET")
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		log.Fatalf("Failed to configure tracing: %s", err)
 	}
 
-	db, err := sql.Open("postgres", dbURL)
+	// One pool serves the queries, transactions and readiness checks. Its
+	// connections bypass tenant isolation for sign-in, store resolution and
+	// the storefront, which work before or across tenants; tenant routes
+	// take a connection scoped to their tenant in requireTenantMember, and
+	// GraphQL resolvers one for each tenant they read, and dbQueries
+	// follows it.
+	cfg.DB.Bypass = true
+	db, err := dbpool.Open(context.Background(), cfg.DatabaseURL, cfg.DB)
 	if err != nil {
 		log.Fatalf("Error Loading DB, %s", err)
 	}
 	defer db.Close()
-	dbQueries := database.New(db)
-	sqlDB, err := sql.Open("postgres", dbURL)
-	if err != nil {
-		log.Fatalf("Error Loading DBConn, %s", err)
-	}
+	dbQueries := database.New(dbpool.NewDB(db))
 
-	// Initialize GID generator with machine ID from environment
-	machineIDStr := os.Getenv("MACHINE_ID")
-	machineID := uint16(0)
-	if machineIDStr != "" {
-		id, err := strconv.ParseUint(machineIDStr, 10, 16)
+	// Without a replica, or while it doesn't answer, reads go to db. An
+	// unreachable replica doesn't stop the API from starting.
+	var replicaDB *pgxpool.Pool
+	if cfg.DatabaseReplicaURL != "" {
+		replicaDB, err = dbpool.Connect(cfg.DatabaseReplicaURL, cfg.DB)
 		if err != nil {
-			log.Fatalf("Invalid MACHINE_ID: %s", err)
+			log.Fatalf("Error Loading read replica, %s", err)
 		}
-		machineID = uint16(id)
+		defer replicaDB.Close()
 	}
-	gidGen, err := gid.NewGenerator(machineID)
+	replica := dbpool.NewReplica(db, replicaDB, nil)
+
+	// Before anything reads the schema. Instances started together take
+	// turns, so each migration is applied once.
+	if cfg.MigrateOnStart {
+		if err := migrateOnStart(context.Background(), db); err != nil {
+			log.Fatalf("Failed to migrate the database: %s", err)
+		}
+	}
+
+	// Personal data and MFA secrets are sealed as they are written, so the
+	// keyring has to be loaded before anything touches them
+	keyStore, keyring, err := keystore.Open(context.Background(), dbQueries, cfg.Encryption)
+	if err != nil {
+		log.Fatalf("Failed to load encryption keys: %s", err)
+	}
+	crypto.SetDefault(keyring)
+
+	gidGen, err := gid.NewGenerator(cfg.MachineID)
 	if err != nil {
 		log.Fatalf("Failed to create GID generator: %s", err)
 	}
 
-	baseDomain := os.Getenv("BASE_DOMAIN")
-	if baseDomain == "" {
-		baseDomain = "storeos.org"
+	var certManager *autocert.Manager
+	if cfg.TLS.ACME {
+		certManager = certs.NewManager(dbQueries, keyring, cfg.TLS.Certs)
+	}
+
+	mediaStorage, err := storage.New(cfg.Storage)
+	if err != nil {
+		log.Fatalf("Failed to configure storage: %s", err)
+	}
+
+	searchEngine, err := search.New(dbQueries, cfg.Search)
+	if err != nil {
+		log.Fatalf("Failed to configure search: %s", err)
+	}
+
+	readiness, err := newReadinessChecker(cfg, db, dbQueries)
+	if err != nil {
+		log.Fatalf("Failed to configure readiness checks: %s", err)
+	}
+
+	rateLimitStore, err := newRateLimitStore(cfg)
+	if err != nil {
+		log.Fatalf("Failed to configure rate limits: %s", err)
+	}
+
+	var billingClient *billing.Client
+	if cfg.Billing.SecretKey != "" {
+		billingClient = billing.New(cfg.Billing)
+	}
+
+	var captchaVerifier captcha.Verifier
+	if cfg.Captcha.Secret != "" {
+		captchaVerifier = captcha.New(cfg.Captcha)
 	}
 
 	apiCfg := apiConfig{
-		db:         dbQueries,
-		platform:   platform,
-		port:       port,
-		sqlDB:      sqlDB,
-		signingKey: signingKey,
-		gidGen:     gidGen,
-		baseDomain: baseDomain,
+		config:   cfg,
+		db:       dbQueries,
+		reads:    database.New(replica),
+		pool:     db,
+		jwtKeys:  jwtKeys,
+		gidGen:   gidGen,
+		jobs:     jobs.NewClient(dbQueries),
+		storage:  mediaStorage,
+		search:   searchEngine,
+		services: newServices(db, dbQueries, gidGen, cfg.RateLimit.Plans),
+		payments: payments.Default(),
+		labels:   labels.New(cfg.Labels),
+		billing:  billingClient,
+		health:   readiness,
+		limiter:  ratelimit.New(rateLimitStore),
+		quotas:   ratelimit.NewQuotaCache(planQuotaTTL),
+		meter:    usage.NewMeter(dbQueries, usage.MeterConfig{}),
+		keys:     keyStore,
+		keyring:  keyring,
+		lockout:  lockout.New(dbQueries),
+		captcha:  captchaVerifier,
+		oauth:    oauth.New(cfg.OAuth),
+		resolver: net.DefaultResolver,
+	}
+	apiCfg.graphQL = graph.NewHandler(apiCfg.graphConfig())
+	apiCfg.openAPI, err = apiCfg.openAPIDocument()
+	if err != nil {
+		log.Fatalf("Failed to build the OpenAPI document: %s", err)
 	}
+	metrics.Configure(cfg.Metrics)
 	metrics.Register(prometheus.DefaultRegisterer)
+	dbpool.Register(prometheus.DefaultRegisterer, "terminus", db)
+	if replicaDB != nil {
+		dbpool.Register(prometheus.DefaultRegisterer, "terminus-replica", replicaDB)
+	}
+	prometheus.MustRegister(metrics.NewActiveCartsCollector(apiCfg.activeCarts))
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
 	}))
@@ -108,172 +254,119 @@ func main() {
 	// r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer) // Recover from panics and log them
 	r.Use(mw.RequestID)
+	r.Use(mw.Tracing)
 	r.Use(mw.Metrics)
-	if apiCfg.platform == "dev" {
+	if apiCfg.config.Platform == "dev" {
 		r.Use(middleware.Logger) // colored, pretty
 	} else {
 		r.Use(mw.RequestLogger(logger)) // structured for prod
 	}
-	r.Use(httprate.Limit(
-		5,             // requests
-		1*time.Second, // per duration
-		httprate.WithKeyFuncs(httprate.KeyByIP, httprate.KeyByEndpoint),
-	))
-
-	// Subdomain and store resolution middleware
-	r.Use(mw.Subdomain(mw.SubdomainConfig{
-		BaseDomain:     apiCfg.baseDomain,
-		APISubdomain:   "api",
-		AdminSubdomain: "admin",
-	}))
-	r.Use(mw.StoreResolver(mw.StoreResolverConfig{
-		DB: dbQueries,
-	}))
-
-	r.Mount("/debug", middleware.Profiler())
-	r.Get("/", homeHandler)
-	r.Get("/health", apiCfg.healthHandler)
-	r.Get("/metrics", promhttp.Handler().ServeHTTP)
-
-	r.Route("/api/v1", func(r chi.Router) {
-
-		r.Post("/users", apiCfg.CreateUserHandler)
-		r.Get("/users", apiCfg.handlerGetUsers)
-
-		r.Group(func(r chi.Router) {
-			r.Use(apiCfg.requireAuth)
-
-			r.Route("/products", func(r chi.Router) {
-				r.Post("/", apiCfg.handlerTenantProductCreate)
-				r.Get("/", apiCfg.handlerTenantProductsList)
-
-				r.Route("/{productID}", func(r chi.Router) {
-					r.Get("/", apiCfg.handlerTenantProductGet)
-					r.Put("/", apiCfg.handlerTenantProductUpdate)
-					r.Delete("/", apiCfg.handlerTenantProductDelete)
-					r.Route("/variants", func(r chi.Router) {
-						r.Post("/", apiCfg.handlerTenantVariantCreate)
-						r.Get("/", apiCfg.handlerTenantVariantsList)
-						r.Route("/{variantID}", func(r chi.Router) {
-							r.Put("/", apiCfg.handlerTenantVariantUpdate)
-							r.Delete("/", apiCfg.handlerTenantVariantDelete)
-						})
-					})
-				})
-
-			})
-			r.Route("/variants", func(r chi.Router) {
-				r.Route("/{variantID}", func(r chi.Router) {
-					r.Get("/", apiCfg.handlerVariantGet)
-				})
-				r.Get("/", apiCfg.handlerTenantVariantsList)
-			})
-			r.Route("/stores", func(r chi.Router) {
-				r.Post("/", apiCfg.handlerCreateStore)
-				r.Get("/", apiCfg.handlerGetStores)
-				r.Route("/{store}", func(r chi.Router) {
-					r.Post("/products", apiCfg.handlerCreateProducts)
-					r.Get("/products", apiCfg.handlerListProducts)
-				})
-			})
-
-			r.Route("/tenants", func(r chi.Router) {
-				r.Post("/", apiCfg.handlerTenantsCreate)
-				r.Get("/", apiCfg.handlerTenantsList)
-
-				r.Route("/{tenantID}", func(r chi.Router) {
-					// Stores under tenant
-					r.Route("/stores", func(r chi.Router) {
-						r.Post("/", apiCfg.handlerTenantStoresCreate)
-						r.Get("/", apiCfg.handlerTenantStoresList)
-
-						r.Route("/{storeID}", func(r chi.Router) {
-							// Products
-							r.Route("/products", func(r chi.Router) {
-								r.Post("/", apiCfg.handlerTenantProductCreate)
-								r.Get("/", apiCfg.handlerTenantProductsList)
-
-								r.Route("/{productID}", func(r chi.Router) {
-									r.Get("/", apiCfg.handlerTenantProductGet)
-									r.Put("/", apiCfg.handlerTenantProductUpdate)
-									r.Delete("/", apiCfg.handlerTenantProductDelete)
-
-									// Variants
-									r.Route("/variants", func(r chi.Router) {
-										r.Post("/", apiCfg.handlerTenantVariantCreate)
-
-										r.Route("/{variantID}", func(r chi.Router) {
-											r.Put("/", apiCfg.handlerTenantVariantUpdate)
-											r.Delete("/", apiCfg.handlerTenantVariantDelete)
-										})
-									})
-								})
-							})
-						})
-					})
-
-					// Members management
-					r.Route("/members", func(r chi.Router) {
-						r.Get("/", apiCfg.handlerTenantMembersList)
-						r.Post("/invite", apiCfg.handlerTenantMembersInvite)
-
-						r.Route("/{memberID}/roles", func(r chi.Router) {
-							r.Post("/", apiCfg.handlerTenantMemberAssignRole)
-							r.Delete("/{roleID}", apiCfg.handlerTenantMemberRemoveRole)
-						})
-					})
-
-					// Roles management
-					r.Route("/roles", func(r chi.Router) {
-						r.Post("/", apiCfg.handlerTenantRolesCreate)
-						r.Get("/", apiCfg.handlerTenantRolesList)
-
-						r.Route("/{roleID}/permissions", func(r chi.Router) {
-							r.Post("/", apiCfg.handlerTenantRoleAddPermission)
-							r.Delete("/{permissionKey}", apiCfg.handlerTenantRoleRemovePermission)
-						})
-					})
-				})
-			})
-
-			// Global permissions list (available to all authenticated users)
-			r.Get("/permissions", apiCfg.handlerPermissionsList)
-
-			// Add more protected routes here and they all get auth automatically.
-		})
-	})
-
-	r.Post("/login", apiCfg.handlerLoginUsers)
-	r.Post("/refresh", apiCfg.handlerRefresh)
-	r.Post("/revoke", apiCfg.handlerRevoke)
-
-	r.Post("/admin/reset", apiCfg.handlerReset)
 
+	r.Mount("/", apiCfg.hostHandler())
 	srv := &http.Server{
-		Addr:              ":" + apiCfg.port,
+		Addr:              ":" + apiCfg.config.Port,
 		Handler:           r,
 		ReadHeaderTimeout: 5 * time.Second,
 	}
 
-	log.Printf("api listening on :%s", apiCfg.port)
-	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatal(fmt.Errorf("listen: %w", err))
+	servers := []*http.Server{srv}
+	if certManager != nil {
+		// Custom domains are served over TLS with certificates picked by SNI.
+		// The plain listener keeps serving the API and answers HTTP-01
+		// challenges, so it must be reachable on port 80.
+		tlsSrv := &http.Server{
+			Addr:              ":" + cfg.TLS.Port,
+			Handler:           r,
+			TLSConfig:         certManager.TLSConfig(),
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		srv.Handler = certManager.HTTPHandler(r)
+		servers = append(servers, tlsSrv)
 	}
-}
 
-func homeHandler(w http.ResponseWriter, r *http.Request) {
-	w.Write([]byte("You've hit our application"))
-}
-func (cfg *apiConfig) healthHandler(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(r.Context(), 1*time.Second)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go replica.Run(ctx, cfg.ReplicaCheckInterval)
+
+	// The meter outlives the servers so the calls they drain are counted
+	meterCtx, stopMeter := context.WithCancel(context.Background())
+	defer stopMeter()
+	meterDone := make(chan struct{})
+	go func() {
+		defer close(meterDone)
+		apiCfg.meter.Run(meterCtx)
+	}()
+
+	serveErr := make(chan error, len(servers))
+	for _, s := range servers {
+		go func() {
+			var err error
+			if s.TLSConfig != nil {
+				log.Printf("tls listening on %s", s.Addr)
+				err = s.ListenAndServeTLS("", "")
+			} else {
+				log.Printf("api listening on %s", s.Addr)
+				err = s.ListenAndServe()
+			}
+			if err != nil && err != http.ErrServerClosed {
+				serveErr <- fmt.Errorf("listen %s: %w", s.Addr, err)
+			}
+		}()
+	}
+
+	select {
+	case err := <-serveErr:
+		log.Fatal(err)
+	case <-ctx.Done():
+	}
+	// A second signal kills the process without waiting
+	stop()
+
+	// Stop accepting connections and let in-flight requests, such as a
+	// checkout mid-transaction, finish before the database goes away. The
+	// deferred Close calls run once every server has returned.
+	slog.Info("shutting down", "drain_timeout", cfg.ShutdownTimeout.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 	defer cancel()
 
-	if err := cfg.sqlDB.PingContext(ctx); err != nil {
-		// 503 signals “unhealthy”
-		http.Error(w, "db: unavailable", http.StatusServiceUnavailable)
-		return
+	var wg sync.WaitGroup
+	for _, s := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.Shutdown(shutdownCtx); err != nil {
+				slog.Error("server did not drain in time", "addr", s.Addr, "error", err)
+			}
+		}()
 	}
+	wg.Wait()
+	stopMeter()
+	<-meterDone
+
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFlush()
+	if err := shutdownTracing(flushCtx); err != nil {
+		slog.Error("failed to flush traces", "error", err)
+	}
+	slog.Info("server stopped")
+}
+
+// hostRouter builds the router for one kind of host from route groups
+func hostRouter(groups ...func(chi.Router)) chi.Router {
+	r := chi.NewRouter()
+	// Set before the groups so the subrouters they mount inherit them
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		respondWithError(w, http.StatusNotFound, "No route matches "+r.URL.Path, nil)
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		respondWithError(w, http.StatusMethodNotAllowed, r.Method+" is not allowed on "+r.URL.Path, nil)
+	})
+	for _, group := range groups {
+		group(r)
+	}
+	return r
+}
 
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("ok"))
+func homeHandler(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("You've hit our application"))
 }
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"context"
+
+	"github.com/dfodeker/terminus/internal/metrics"
+)
+
+// activeCarts counts the checkouts holding stock in each store, for the
+// active_carts gauge
+func (cfg *apiConfig) activeCarts(ctx context.Context) ([]metrics.StoreCount, error) {
+	rows, err := cfg.db.CountActiveReservationsByStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts := make([]metrics.StoreCount, 0, len(rows))
+	for _, row := range rows {
+		counts = append(counts, metrics.StoreCount{StoreID: row.StoreID, Count: row.Active})
+	}
+	return counts, nil
+}
@@ -0,0 +1,248 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
+)
+
+const (
+	HeaderIdempotencyKey      = "Idempotency-Key"
+	HeaderIdempotentReplayed  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	defaultIdempotencyTTL     = 24 * time.Hour
+	defaultIdempotencyLockTTL = time.Minute
+	// The body is read whole to hash it before the handler runs, so it is
+	// capped above the largest upload a tenant route takes, a Shopify export
+	defaultIdempotencyMaxBody = 32 << 20
+)
+
+// replayedHeaders are the response headers stored and sent back on replay
+var replayedHeaders = []string{"Content-Type", "Location"}
+
+// IdempotencyConfig configures the idempotency middleware
+type IdempotencyConfig struct {
+	DB database.Querier
+	// TTL is how long a stored response can be replayed
+	TTL time.Duration
+	// LockTTL is how long an in-flight request holds its key before a retry may take it over
+	LockTTL time.Duration
+	// TenantID scopes keys; requests without a tenant pass through untouched.
+	// Defaults to the {tenantID} route param, then the store-resolved tenant.
+	TenantID func(r *http.Request) (uuid.UUID, bool)
+	// Caller identifies who sent the request, such as a user or an API
+	// key; keys are scoped to it, so nobody can replay another caller's
+	// response. Requests without a caller pass through untouched.
+	Caller func(r *http.Request) (string, bool)
+	// MaxBodyBytes caps the request bodies read to hash them
+	MaxBodyBytes int64
+}
+
+// Idempotency stores the first response to a POST/PUT carrying an
+// Idempotency-Key header, keyed by (tenant, caller, key, method, path), and replays
+// it for retries of the same request. Reusing a key with a different body
+// is rejected, as is a retry that arrives while the original is in flight.
+func Idempotency(cfg IdempotencyConfig) func(http.Handler) http.Handler {
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultIdempotencyTTL
+	}
+	if cfg.LockTTL <= 0 {
+		cfg.LockTTL = defaultIdempotencyLockTTL
+	}
+	if cfg.TenantID == nil {
+		cfg.TenantID = tenantFromRequest
+	}
+	if cfg.Caller == nil {
+		panic("middleware: IdempotencyConfig.Caller is required")
+	}
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = defaultIdempotencyMaxBody
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(HeaderIdempotencyKey)
+			if key == "" || (r.Method != http.MethodPost && r.Method != http.MethodPut) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				problem.Error(w, http.StatusBadRequest, "", "Idempotency-Key must be at most 255 characters")
+				return
+			}
+
+			tenantID, ok := cfg.TenantID(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			caller, ok := cfg.Caller(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			reqID := GetRequestID(ctx)
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, cfg.MaxBodyBytes))
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					problem.Error(w, http.StatusRequestEntityTooLarge, "", "Request body is too large")
+					return
+				}
+				problem.Error(w, http.StatusBadRequest, "", "Couldn't read request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			sum := sha256.Sum256(body)
+			hash := hex.EncodeToString(sum[:])
+
+			record, err := cfg.DB.ClaimIdempotencyKey(ctx, database.ClaimIdempotencyKeyParams{
+				TenantID:     tenantID,
+				Caller:       caller,
+				Key:          key,
+				Method:       r.Method,
+				Path:         r.URL.Path,
+				RequestHash:  hash,
+				ExpiresAt:    time.Now().Add(cfg.TTL),
+				LockedBefore: time.Now().Add(-cfg.LockTTL),
+			})
+			if errors.Is(err, sql.ErrNoRows) {
+				// Someone already holds this key; replay or reject
+				existing, err := cfg.DB.GetIdempotencyKey(ctx, database.GetIdempotencyKeyParams{
+					TenantID: tenantID,
+					Caller:   caller,
+					Key:      key,
+					Method:   r.Method,
+					Path:     r.URL.Path,
+				})
+				if err != nil {
+					slog.ErrorContext(ctx, "idempotency lookup failed", "request_id", reqID, "error", err)
+					problem.Error(w, http.StatusInternalServerError, "", "Couldn't process idempotency key")
+					return
+				}
+				replayIdempotent(w, existing, hash)
+				return
+			}
+			if err != nil {
+				slog.ErrorContext(ctx, "idempotency claim failed", "request_id", reqID, "error", err)
+				problem.Error(w, http.StatusInternalServerError, "", "Couldn't process idempotency key")
+				return
+			}
+
+			// Bookkeeping must survive the client hanging up mid-request
+			storeCtx := context.WithoutCancel(ctx)
+			rec := &captureRecorder{ResponseWriter: w}
+			completed := false
+			defer func() {
+				// A panic or server error releases the key so the client can retry
+				if !completed {
+					if err := cfg.DB.DeleteIdempotencyKey(storeCtx, record.ID); err != nil {
+						slog.ErrorContext(ctx, "idempotency release failed", "request_id", reqID, "error", err)
+					}
+				}
+			}()
+
+			next.ServeHTTP(rec, r)
+
+			status := rec.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if status >= http.StatusInternalServerError {
+				return
+			}
+
+			headers := map[string]string{}
+			for _, h := range replayedHeaders {
+				if v := rec.Header().Get(h); v != "" {
+					headers[h] = v
+				}
+			}
+			rawHeaders, _ := json.Marshal(headers)
+
+			if err := cfg.DB.CompleteIdempotencyKey(storeCtx, database.CompleteIdempotencyKeyParams{
+				ID:              record.ID,
+				ResponseStatus:  sql.NullInt32{Int32: int32(status), Valid: true},
+				ResponseHeaders: pqtype.NullRawMessage{RawMessage: rawHeaders, Valid: true},
+				ResponseBody:    rec.body.Bytes(),
+			}); err != nil {
+				slog.ErrorContext(ctx, "idempotency store failed", "request_id", reqID, "error", err)
+				return
+			}
+			completed = true
+		})
+	}
+}
+
+func replayIdempotent(w http.ResponseWriter, record database.IdempotencyKey, hash string) {
+	if record.RequestHash != hash {
+		problem.Error(w, http.StatusUnprocessableEntity, problem.CodeIdempotencyKeyReused, "Idempotency-Key was already used with a different request body")
+		return
+	}
+	if record.Status != "completed" || !record.ResponseStatus.Valid {
+		w.Header().Set("Retry-After", "1")
+		problem.Error(w, http.StatusConflict, problem.CodeIdempotencyKeyInProgress, "A request with this Idempotency-Key is already in progress")
+		return
+	}
+
+	if record.ResponseHeaders.Valid {
+		headers := map[string]string{}
+		if err := json.Unmarshal(record.ResponseHeaders.RawMessage, &headers); err == nil {
+			for k, v := range headers {
+				w.Header().Set(k, v)
+			}
+		}
+	}
+	w.Header().Set(HeaderIdempotentReplayed, "true")
+	w.Header().Set("Content-Length", strconv.Itoa(len(record.ResponseBody)))
+	w.WriteHeader(int(record.ResponseStatus.Int32))
+	w.Write(record.ResponseBody)
+}
+
+func tenantFromRequest(r *http.Request) (uuid.UUID, bool) {
+	if raw := chi.URLParam(r, "tenantID"); raw != "" {
+		id, err := uuid.Parse(raw)
+		return id, err == nil
+	}
+	return GetResolvedTenantID(r.Context())
+}
+
+// captureRecorder passes the response through while keeping a copy of it
+type captureRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *captureRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *captureRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
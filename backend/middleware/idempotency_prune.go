@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+)
+
+// IdempotencyPrunerConfig configures an IdempotencyPruner
+type IdempotencyPrunerConfig struct {
+	Interval time.Duration
+	// BatchSize bounds each delete, as stored responses can be large
+	BatchSize int
+	Logger    *slog.Logger
+}
+
+// IdempotencyPruner deletes stored idempotency keys once they can no
+// longer be replayed. Running several at once is harmless.
+type IdempotencyPruner struct {
+	db  database.Querier
+	cfg IdempotencyPrunerConfig
+}
+
+// NewIdempotencyPruner creates a pruner over db
+func NewIdempotencyPruner(db database.Querier, cfg IdempotencyPrunerConfig) *IdempotencyPruner {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 500
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &IdempotencyPruner{db: db, cfg: cfg}
+}
+
+// Run prunes once an interval until ctx is cancelled
+func (p *IdempotencyPruner) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := p.Prune(ctx)
+		if err != nil && ctx.Err() == nil {
+			p.cfg.Logger.Error("idempotency key prune failed", "error", err)
+		} else if n > 0 {
+			p.cfg.Logger.Info("idempotency keys pruned", "deleted", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Prune deletes the expired keys, a batch at a time, and returns how many
+// were deleted
+func (p *IdempotencyPruner) Prune(ctx context.Context) (int64, error) {
+	var total int64
+	for {
+		n, err := p.db.DeleteExpiredIdempotencyKeys(ctx, int32(p.cfg.BatchSize))
+		total += n
+		if err != nil || n < int64(p.cfg.BatchSize) {
+			return total, err
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/database/dbmock"
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
+)
+
+func TestIdempotency(t *testing.T) {
+	const body = `{"name":"Linen shirt"}`
+	sum := sha256.Sum256([]byte(body))
+	hash := hex.EncodeToString(sum[:])
+	tenantID := uuid.New()
+	completed := database.IdempotencyKey{
+		RequestHash:     hash,
+		Status:          "completed",
+		ResponseStatus:  sql.NullInt32{Int32: http.StatusCreated, Valid: true},
+		ResponseHeaders: pqtype.NullRawMessage{RawMessage: []byte(`{"Content-Type":"application/json"}`), Valid: true},
+		ResponseBody:    []byte(`{"id":"stored"}`),
+	}
+
+	tests := []struct {
+		name string
+		body string
+		// existing is the key another request holds, if the claim loses
+		existing      *database.IdempotencyKey
+		handlerStatus int
+		wantStatus    int
+		wantBody      string
+		wantHandler   bool
+		wantStored    bool
+		wantReleased  bool
+	}{
+		{
+			name:          "claimed",
+			body:          body,
+			handlerStatus: http.StatusCreated,
+			wantStatus:    http.StatusCreated,
+			wantBody:      `{"id":"new"}`,
+			wantHandler:   true,
+			wantStored:    true,
+		},
+		{
+			name:       "replayed",
+			body:       body,
+			existing:   &completed,
+			wantStatus: http.StatusCreated,
+			wantBody:   `{"id":"stored"}`,
+		},
+		{
+			name:       "different body",
+			body:       `{"name":"Wool coat"}`,
+			existing:   &completed,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "in progress",
+			body:       body,
+			existing:   &database.IdempotencyKey{RequestHash: hash, Status: "in_progress"},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:          "server error releases the key",
+			body:          body,
+			handlerStatus: http.StatusInternalServerError,
+			wantStatus:    http.StatusInternalServerError,
+			wantHandler:   true,
+			wantReleased:  true,
+		},
+		{
+			name:       "body too large",
+			body:       strings.Repeat("x", 65),
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var claimed database.ClaimIdempotencyKeyParams
+			var stored, released bool
+			q := &dbmock.Querier{
+				ClaimIdempotencyKeyFunc: func(ctx context.Context, arg database.ClaimIdempotencyKeyParams) (database.IdempotencyKey, error) {
+					claimed = arg
+					if tt.existing != nil {
+						return database.IdempotencyKey{}, sql.ErrNoRows
+					}
+					return database.IdempotencyKey{ID: uuid.New()}, nil
+				},
+				GetIdempotencyKeyFunc: func(ctx context.Context, arg database.GetIdempotencyKeyParams) (database.IdempotencyKey, error) {
+					if arg.Caller != claimed.Caller {
+						t.Errorf("looked up caller %q, claimed as %q", arg.Caller, claimed.Caller)
+					}
+					return *tt.existing, nil
+				},
+				CompleteIdempotencyKeyFunc: func(ctx context.Context, arg database.CompleteIdempotencyKeyParams) error {
+					stored = true
+					return nil
+				},
+				DeleteIdempotencyKeyFunc: func(ctx context.Context, id uuid.UUID) error {
+					released = true
+					return nil
+				},
+			}
+			handled := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handled = true
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.handlerStatus)
+				w.Write([]byte(`{"id":"new"}`))
+			})
+			h := Idempotency(IdempotencyConfig{
+				DB:           q,
+				TenantID:     func(*http.Request) (uuid.UUID, bool) { return tenantID, true },
+				Caller:       func(*http.Request) (string, bool) { return "user:1", true },
+				MaxBodyBytes: 64,
+			})(next)
+
+			req := httptest.NewRequest(http.MethodPost, "/tenants/t/customers", strings.NewReader(tt.body))
+			req.Header.Set(HeaderIdempotencyKey, "key-1")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %s, want %s", rec.Body, tt.wantBody)
+			}
+			if handled != tt.wantHandler {
+				t.Errorf("handler ran = %v, want %v", handled, tt.wantHandler)
+			}
+			if stored != tt.wantStored || released != tt.wantReleased {
+				t.Errorf("stored = %v, released = %v, want %v and %v", stored, released, tt.wantStored, tt.wantReleased)
+			}
+			if tt.wantStatus != http.StatusRequestEntityTooLarge && (claimed.Caller != "user:1" || claimed.TenantID != tenantID || claimed.RequestHash == "") {
+				t.Errorf("claimed %+v, want the caller and tenant", claimed)
+			}
+			if tt.existing == &completed && tt.wantStatus == http.StatusCreated && rec.Header().Get(HeaderIdempotentReplayed) != "true" {
+				t.Error("replay isn't marked Idempotent-Replayed")
+			}
+		})
+	}
+}
+
+func TestIdempotencyPassesThroughWithoutCaller(t *testing.T) {
+	h := Idempotency(IdempotencyConfig{
+		DB:       &dbmock.Querier{},
+		TenantID: func(*http.Request) (uuid.UUID, bool) { return uuid.New(), true },
+		Caller:   func(*http.Request) (string, bool) { return "", false },
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{}"))
+	req.Header.Set(HeaderIdempotencyKey, "key-1")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("status = %d, want the handler's %d", rec.Code, http.StatusAccepted)
+	}
+}
+
+func TestIdempotencyPrunerDeletesInBatches(t *testing.T) {
+	batches := []int64{2, 2, 1}
+	calls := 0
+	p := NewIdempotencyPruner(&dbmock.Querier{
+		DeleteExpiredIdempotencyKeysFunc: func(ctx context.Context, rowLimit int32) (int64, error) {
+			n := batches[calls]
+			calls++
+			return n, nil
+		},
+	}, IdempotencyPrunerConfig{BatchSize: 2})
+	n, err := p.Prune(context.Background())
+	if err != nil || n != 5 || calls != 3 {
+		t.Errorf("Prune() = %d, %v after %d deletes, want 5 after 3", n, err, calls)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 type statusRecorder struct {
@@ -40,7 +42,7 @@ func RequestLogger(logger *slog.Logger) func(http.Handler) http.Handler {
 			reqID := GetRequestID(r.Context())
 
 			// Optional fields (consider privacy + volume):
-			remoteIP := clientIP(r)
+			remoteIP := ClientIP(r)
 			ua := r.UserAgent()
 			durStr := dur.Round(time.Microsecond).String()
 
@@ -60,6 +62,10 @@ func RequestLogger(logger *slog.Logger) func(http.Handler) http.Handler {
 			if ua != "" {
 				attrs = append(attrs, slog.String("user_agent", ua))
 			}
+			// Links the log line to the request's trace when Tracing ran first
+			if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+				attrs = append(attrs, slog.String("trace_id", sc.TraceID().String()))
+			}
 
 			// Level choice: warn on 5xx, info otherwise (tweak as you like)
 			if rec.status >= 500 {
@@ -71,7 +77,9 @@ func RequestLogger(logger *slog.Logger) func(http.Handler) http.Handler {
 	}
 }
 
-func clientIP(r *http.Request) string {
+// ClientIP is the address a request came from, preferring the first
+// X-Forwarded-For entry
+func ClientIP(r *http.Request) string {
 	// If you're behind a trusted proxy, you might use X-Forwarded-For.
 	// If you are NOT behind a trusted proxy, do NOT trust these headers.
 	xff := strings.TrimSpace(r.Header.Get("X-Forwarded-For"))
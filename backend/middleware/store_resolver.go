@@ -4,11 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"log"
 	"net/http"
-	"strings"
 
 	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/problem"
 	"github.com/google/uuid"
 )
 
@@ -22,6 +21,10 @@ type ResolvedStore struct {
 	Handle   string
 	Name     string
 	TenantID uuid.NullUUID
+	// DefaultCurrency is the currency of the store's base prices
+	DefaultCurrency string
+	// DefaultLocale is the locale of the store's own product content
+	DefaultLocale string
 }
 
 // GetResolvedStore retrieves the resolved store from the context
@@ -59,14 +62,6 @@ func StoreResolver(cfg StoreResolverConfig) func(http.Handler) http.Handler {
 				return
 			}
 
-			// Skip localhost/development requests - they shouldn't trigger store resolution
-			host := strings.Split(info.FullHost, ":")[0] // strip port
-			if host == "localhost" || host == "127.0.0.1" || host == "0.0.0.0" {
-				log.Println("local host")
-				next.ServeHTTP(w, r)
-				return
-			}
-
 			var store database.Store
 			var err error
 
@@ -80,19 +75,28 @@ func StoreResolver(cfg StoreResolverConfig) func(http.Handler) http.Handler {
 
 			if err != nil {
 				if errors.Is(err, sql.ErrNoRows) {
-					http.Error(w, "Store not found", http.StatusNotFound)
+					problem.Error(w, http.StatusNotFound, "", "Store not found")
 					return
 				}
-				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				problem.Error(w, http.StatusInternalServerError, "", "Internal server error")
+				return
+			}
+
+			// Paused and closed stores are still managed in the admin API
+			// but not served to shoppers
+			if store.Status != "active" {
+				problem.Error(w, http.StatusServiceUnavailable, problem.CodeUnavailable, "This store is not open right now")
 				return
 			}
 
 			resolved := ResolvedStore{
-				ID:       store.ID,
-				GID:      store.Gid,
-				Handle:   store.Handle,
-				Name:     store.Name,
-				TenantID: store.TenantID,
+				ID:              store.ID,
+				GID:             store.Gid,
+				Handle:          store.Handle,
+				Name:            store.Name,
+				TenantID:        store.TenantID,
+				DefaultCurrency: store.DefaultCurrency,
+				DefaultLocale:   store.DefaultLocale,
 			}
 
 			ctx := context.WithValue(r.Context(), storeCtxKey{}, resolved)
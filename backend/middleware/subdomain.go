@@ -2,8 +2,11 @@ package middleware
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"strings"
+
+	"github.com/dfodeker/terminus/internal/problem"
 )
 
 type subdomainKey struct{}
@@ -22,6 +25,8 @@ const (
 	DomainTypeCustom
 	// DomainTypeRoot is for the root domain mystoreos.org
 	DomainTypeRoot
+	// DomainTypeLocal is for localhost and loopback addresses in development
+	DomainTypeLocal
 )
 
 // SubdomainInfo contains information extracted from the request host
@@ -85,6 +90,9 @@ func Subdomain(cfg SubdomainConfig) func(http.Handler) http.Handler {
 			} else if strings.EqualFold(host, cfg.BaseDomain) {
 				// Root domain
 				info.DomainType = DomainTypeRoot
+			} else if isLocalHost(host) {
+				// Development, or probes from the same machine
+				info.DomainType = DomainTypeLocal
 			} else {
 				// Custom domain
 				info.DomainType = DomainTypeCustom
@@ -97,6 +105,34 @@ func Subdomain(cfg SubdomainConfig) func(http.Handler) http.Handler {
 	}
 }
 
+// HostRouter sends each request to the handler for its domain type, as
+// classified by Subdomain. Hosts without a handler get a 404.
+func HostRouter(routes map[DomainType]http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info, ok := GetSubdomainInfo(r.Context())
+		if !ok {
+			problem.Error(w, http.StatusNotFound, "", "Nothing is served on this host")
+			return
+		}
+		handler, ok := routes[info.DomainType]
+		if !ok {
+			problem.Error(w, http.StatusNotFound, "", "Nothing is served on this host")
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// isLocalHost reports whether host is localhost or a loopback or
+// unspecified address
+func isLocalHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && (ip.IsLoopback() || ip.IsUnspecified())
+}
+
 // String returns a human-readable string representation of the DomainType
 func (d DomainType) String() string {
 	switch d {
@@ -110,6 +146,8 @@ func (d DomainType) String() string {
 		return "custom"
 	case DomainTypeRoot:
 		return "root"
+	case DomainTypeLocal:
+		return "local"
 	default:
 		return "unknown"
 	}
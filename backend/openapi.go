@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/dfodeker/terminus/internal/config"
+	"github.com/dfodeker/terminus/internal/openapi"
+)
+
+// openAPIFile is where the document is kept in the repository, so changes
+// to the API show up in review. CI fails when it falls behind the routes.
+const openAPIFile = "openapi.json"
+
+// apiSpec describes the API host. Storefront and admin hosts aren't in
+// the document.
+var apiSpec = openapi.Spec{
+	Info: openapi.Info{
+		Title:       "Terminus API",
+		Version:     "1",
+		Description: "Errors are RFC 7807 problem details; clients branch on their code.",
+	},
+	Prefix: "/api/v1",
+	Scopes: []string{"tenants", "stores"},
+	Tags: map[string]string{
+		".well-known":  "auth",
+		"login":        "auth",
+		"refresh":      "auth",
+		"revoke":       "auth",
+		"logout":       "auth",
+		"password":     "auth",
+		"email":        "auth",
+		"openapi.json": "docs",
+		"docs":         "docs",
+		"admin":        "graphql",
+		"leave":        "members",
+		"status":       "stores",
+		"settings":     "stores",
+		"lookup":       "variants",
+		"mfa-policy":   "tenants",
+		"sso-policy":   "tenants",
+	},
+	Auth: []string{"requireAuth"},
+	// Routes without an annotation are still documented, with a summary
+	// from their handler's name and no body schemas
+	Annotations: []openapi.Annotation{
+		{Method: http.MethodGet, Path: "/health", ID: "health", Summary: "Readiness of the API and its dependencies"},
+		{Method: http.MethodGet, Path: "/health/ready", ID: "healthReady", Summary: "Readiness of the API and its dependencies"},
+		{Method: http.MethodGet, Path: "/health/live", Summary: "Liveness of the API"},
+		{Method: http.MethodGet, Path: "/.well-known/jwks.json", Summary: "Public keys access tokens are signed with"},
+		{Method: http.MethodGet, Path: "/api/v1/openapi.json", Summary: "This document"},
+		{
+			Method: http.MethodPost, Path: "/admin/graphql", Summary: "GraphQL Admin API",
+			Description: "Takes a JSON body of query, operationName and variables. The schema is graph/schema.graphqls.",
+		},
+
+		{Method: http.MethodPost, Path: "/api/v1/users", Summary: "Sign up", Response: User{}, Status: http.StatusCreated},
+		{
+			Method: http.MethodGet, Path: "/api/v1/users", Summary: "List every user",
+			Description: "For platform staff only",
+			Query:       append(platformFilterQuery, pageQuery...),
+			Response:    PageResponse[adminUserResponse]{},
+		},
+
+		{Method: http.MethodPost, Path: "/login", Summary: "Sign in with email and password", Response: loginResult{}},
+		{Method: http.MethodPost, Path: "/login/mfa", Summary: "Finish signing in with a second factor", Response: LoginResponse{}},
+		{Method: http.MethodPost, Path: "/login/magic-link", Summary: "Email a sign-in link", Response: MagicLinkResponse{}, Status: http.StatusAccepted},
+		{Method: http.MethodPost, Path: "/login/magic-link/consume", Summary: "Sign in with an emailed link", Response: LoginResponse{}},
+		{Method: http.MethodGet, Path: "/login/oauth", Summary: "List the OAuth providers users can sign in with"},
+		{Method: http.MethodPost, Path: "/login/oauth/{provider}", Summary: "Start signing in with an OAuth provider"},
+		{Method: http.MethodPost, Path: "/login/oauth/callback", Summary: "Finish signing in with an OAuth provider", Response: LoginResponse{}},
+		{Method: http.MethodGet, Path: "/sso/saml/{connectionID}/metadata", Summary: "SAML service provider metadata"},
+		{Method: http.MethodPost, Path: "/sso/saml/{connectionID}/acs", Summary: "SAML assertion consumer service"},
+		{Method: http.MethodPost, Path: "/revoke", Summary: "Revoke a refresh token", Status: http.StatusNoContent},
+		{Method: http.MethodPost, Path: "/logout", Summary: "Sign out", Status: http.StatusNoContent},
+		{Method: http.MethodPost, Path: "/password/forgot", Summary: "Email a password reset link", Status: http.StatusAccepted},
+		{Method: http.MethodPost, Path: "/email/verify", Summary: "Verify an email address", Response: User{}},
+
+		{Method: http.MethodGet, Path: "/api/v1/tenants", Summary: "List the tenants the user belongs to", Query: pageQuery, Response: PageResponse[TenantResponse]{}},
+		{Method: http.MethodGet, Path: "/api/v1/tenants/{tenantID}/stores", Summary: "List stores", Query: pageQuery, Response: PageResponse[TenantStoreResponse]{}},
+		{
+			Method: http.MethodGet, Path: "/api/v1/tenants/{tenantID}/members", Summary: "List members",
+			Query:    append([]openapi.Parameter{{Name: "q", Description: "Text to search names and emails for"}}, pageQuery...),
+			Response: PageResponse[TenantMemberResponse]{},
+		},
+		{Method: http.MethodGet, Path: "/api/v1/tenants/{tenantID}/audit-log", Summary: "List audit log entries", Query: pageQuery, Response: PageResponse[AuditLogEntryResponse]{}},
+		{
+			Method: http.MethodGet, Path: "/api/v1/tenants/{tenantID}/notifications", Summary: "List notifications",
+			Query: append([]openapi.Parameter{
+				{Name: "store_id", Schema: &openapi.Schema{Type: "string", Format: "uuid"}},
+				{Name: "unread", Schema: &openapi.Schema{Type: "boolean"}},
+			}, pageQuery...),
+			Response: PageResponse[NotificationResponse]{},
+		},
+		{Method: http.MethodGet, Path: "/api/v1/tenants/{tenantID}/stores/{storeID}/orders", Summary: "List orders", Query: pageQuery, Response: PageResponse[TenantOrderResponse]{}},
+		{Method: http.MethodGet, Path: "/api/v1/tenants/{tenantID}/stores/{storeID}/customers", Summary: "List customers", Query: pageQuery, Response: PageResponse[TenantCustomerResponse]{}},
+		{Method: http.MethodGet, Path: "/api/v1/tenants/{tenantID}/stores/{storeID}/gift-cards", Summary: "List gift cards", Query: pageQuery, Response: PageResponse[GiftCardResponse]{}},
+		{
+			Method: http.MethodGet, Path: "/api/v1/tenants/{tenantID}/stores/{storeID}/subscriptions", Summary: "List subscriptions",
+			Query:    append([]openapi.Parameter{{Name: "status"}}, pageQuery...),
+			Response: PageResponse[SubscriptionResponse]{},
+		},
+
+		{Method: http.MethodGet, Path: "/api/v1/me", Summary: "The signed-in user", Response: MeResponse{}},
+		{Method: http.MethodPatch, Path: "/api/v1/me", Summary: "Update the signed-in user", Response: MeResponse{}},
+		{Method: http.MethodPut, Path: "/api/v1/me/avatar", Summary: "Upload a profile picture", Response: MeResponse{}},
+		{Method: http.MethodDelete, Path: "/api/v1/me/avatar", Summary: "Remove the profile picture", Status: http.StatusNoContent},
+		{Method: http.MethodGet, Path: "/api/v1/me/mfa", Summary: "Two-factor authentication status", Response: MFAStatusResponse{}},
+		{Method: http.MethodPost, Path: "/api/v1/me/revoke-all", Summary: "Sign out everywhere", Status: http.StatusNoContent},
+		{Method: http.MethodDelete, Path: "/api/v1/me/sessions", Summary: "Sign out everywhere", Status: http.StatusNoContent},
+		{Method: http.MethodDelete, Path: "/api/v1/me/sessions/{sessionID}", Summary: "End a session", Status: http.StatusNoContent},
+	},
+}
+
+// loginResult documents what POST /login answers with: the tokens, or for
+// users with MFA on only the challenge to finish with POST /login/mfa
+type loginResult struct {
+	LoginResponse
+	MFARequired bool   `json:"mfa_required,omitempty"`
+	MFAToken    string `json:"mfa_token,omitempty"`
+}
+
+// pageQuery are the query parameters of cursor-paginated lists
+var pageQuery = []openapi.Parameter{
+	{Name: "limit", Description: "Results per page", Schema: &openapi.Schema{Type: "integer"}},
+	{Name: "cursor", Description: "The next_cursor of the previous page"},
+}
+
+// platformFilterQuery are the query parameters platform.ParseFilter reads
+var platformFilterQuery = []openapi.Parameter{
+	{Name: "q", Description: "Text to search for"},
+	{Name: "status"},
+	{Name: "tenant_id", Schema: &openapi.Schema{Type: "string", Format: "uuid"}},
+}
+
+// openAPIDocument builds the document from the API host's routes, encoded
+// the way it is served and kept in openapi.json
+func (cfg *apiConfig) openAPIDocument() ([]byte, error) {
+	g := cfg.routes()
+	routes, err := openapi.Routes(hostRouter(g.shared, g.api))
+	if err != nil {
+		return nil, err
+	}
+	doc, err := apiSpec.Build(routes)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// runOpenAPI is the openapi command. It writes the document to -o, or to
+// stdout for -o -; with -check it instead fails if the file is out of
+// date.
+func runOpenAPI(args []string) error {
+	fs := flag.NewFlagSet("openapi", flag.ExitOnError)
+	out := fs.String("o", openAPIFile, "file to write the document to, or - for stdout")
+	check := fs.Bool("check", false, "fail if the file isn't the current document")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// Building the routes looks nothing up, so no settings are needed
+	cfg := &apiConfig{config: &config.Config{}}
+	data, err := cfg.openAPIDocument()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case *check:
+		existing, err := os.ReadFile(*out)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(existing, data) {
+			return fmt.Errorf("%s is out of date with the routes; regenerate it with go run . openapi", *out)
+		}
+		return nil
+	case *out == "-":
+		_, err := os.Stdout.Write(data)
+		return err
+	default:
+		return os.WriteFile(*out, data, 0o644)
+	}
+}
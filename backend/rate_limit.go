@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/config"
+	"github.com/dfodeker/terminus/internal/ratelimit"
+	mw "github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/httprate"
+	"github.com/google/uuid"
+)
+
+// planQuotaTTL is how long a caller's plan quota is cached, so a plan
+// change takes effect within a minute
+const planQuotaTTL = time.Minute
+
+// redisRateLimitConns is how many Redis connections each instance keeps
+// open for counting
+const redisRateLimitConns = 32
+
+// newRateLimitStore returns the store RATE_LIMIT_STORE names
+func newRateLimitStore(cfg *config.Config) (ratelimit.Store, error) {
+	if cfg.RateLimit.Store == "redis" {
+		return ratelimit.NewRedisStore(cfg.RedisURL, redisRateLimitConns)
+	}
+	return ratelimit.NewMemoryStore(), nil
+}
+
+// rateLimitAnonymous limits requests by client IP and endpoint. Requests
+// to the admin API carrying credentials are left to rateLimitCaller and
+// rateLimitTenant, which count them once the credentials are checked, or
+// to rateLimitPublic on routes that never check them.
+func (cfg *apiConfig) rateLimitAnonymous(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if carriesAdminCredentials(r) || cfg.limitIP(w, r) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// rateLimitPublic limits requests to the admin API routes that take no
+// credentials, such as sign-in, by client IP when rateLimitAnonymous left
+// them alone for carrying an Authorization header. Nothing checks the
+// header on these routes, so any would otherwise get past every limit.
+func (cfg *apiConfig) rateLimitPublic(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !carriesAdminCredentials(r) || cfg.limitIP(w, r) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// limitIP counts the request against the anonymous quota of its client IP
+// and endpoint, and reports whether it may go on
+func (cfg *apiConfig) limitIP(w http.ResponseWriter, r *http.Request) bool {
+	quota := ratelimit.Quota{Requests: cfg.config.RateLimit.Requests, Window: cfg.config.RateLimit.Window}
+	ip, _ := httprate.KeyByIP(r)
+	return cfg.limit(w, r, "ip:"+ip+":"+r.URL.Path, quota)
+}
+
+// carriesAdminCredentials reports whether the request is to the admin API
+// with an Authorization header, which rateLimitAnonymous doesn't count
+func carriesAdminCredentials(r *http.Request) bool {
+	return r.Header.Get("Authorization") != "" && servesAdminAPI(r)
+}
+
+// servesAdminAPI reports whether the request host serves the admin API,
+// whose credentials are staff tokens and API keys rather than customer
+// tokens
+func servesAdminAPI(r *http.Request) bool {
+	info, ok := mw.GetSubdomainInfo(r.Context())
+	return ok && (info.DomainType == mw.DomainTypeAPI || info.DomainType == mw.DomainTypeLocal)
+}
+
+// rateLimitCaller limits an authenticated request by its API key, with the
+// quota of the key's tenant, or by its user, with the best quota among the
+// tenants the user belongs to
+func (cfg *apiConfig) rateLimitCaller(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var key string
+		var quota ratelimit.Quota
+		if apiKey, ok := apiKeyFromContext(r.Context()); ok {
+			key = "api_key:" + apiKey.ID.String()
+			quota = cfg.tenantQuota(r.Context(), apiKey.TenantID)
+		} else if user, ok := userFromContext(r.Context()); ok {
+			key = "user:" + user.String()
+			quota = cfg.planQuota(r.Context(), key, func(ctx context.Context) ([]string, error) {
+				return cfg.db.ListUserStorePlans(ctx, user)
+			})
+		} else {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if cfg.limit(w, r, key, quota) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// rateLimitTenant limits user requests under a tenant by the tenant, so its
+// staff share the quota of its plan between them. API keys are left to
+// rateLimitCaller, which already counts each against its tenant's quota.
+func (cfg *apiConfig) rateLimitTenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := apiKeyFromContext(r.Context()); ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		tenantID := tenantContextFrom(r).Tenant.ID
+		if cfg.limit(w, r, "tenant:"+tenantID.String(), cfg.tenantQuota(r.Context(), tenantID)) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+func (cfg *apiConfig) tenantQuota(ctx context.Context, tenantID uuid.UUID) ratelimit.Quota {
+	return cfg.planQuota(ctx, "tenant:"+tenantID.String(), func(ctx context.Context) ([]string, error) {
+		return cfg.db.ListTenantStorePlans(ctx, uuid.NullUUID{UUID: tenantID, Valid: true})
+	})
+}
+
+// planQuota is the cached quota of the best plan load returns. The default
+// plan's quota stands in when the plans can't be read.
+func (cfg *apiConfig) planQuota(ctx context.Context, key string, load func(ctx context.Context) ([]string, error)) ratelimit.Quota {
+	plans := cfg.config.RateLimit.Plans
+	q, err := cfg.quotas.Get(ctx, key, func(ctx context.Context) (ratelimit.Quota, error) {
+		names, err := load(ctx)
+		if err != nil {
+			return ratelimit.Quota{}, err
+		}
+		return plans.Best(names), nil
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "unable to load plans for rate limit", "key", key, "error", err)
+		return plans[ratelimit.DefaultPlan]
+	}
+	return q
+}
+
+// limit counts the request under key and answers 429 when it is over
+// quota, reporting whether the request may go on. A failure to count lets
+// the request through, so a Redis outage doesn't take the API down with it.
+func (cfg *apiConfig) limit(w http.ResponseWriter, r *http.Request, key string, quota ratelimit.Quota) bool {
+	res, err := cfg.limiter.Allow(r.Context(), key, quota)
+	if err != nil {
+		slog.WarnContext(r.Context(), "unable to count request for rate limit", "key", key, "error", err)
+		return true
+	}
+	res.SetHeaders(w.Header(), time.Now())
+	if !res.Allowed {
+		respondWithError(w, http.StatusTooManyRequests, "Too many requests, please slow down", nil)
+		return false
+	}
+	return true
+}
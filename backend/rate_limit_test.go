@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	mw "github.com/dfodeker/terminus/middleware"
+)
+
+func TestRateLimitPublicRoutes(t *testing.T) {
+	cfg := newRouteTestConfig(t)
+	cfg.config.RateLimit.Requests = 2
+
+	// Stacked the way main stacks them, less the store resolution
+	g := cfg.routes()
+	handler := mw.Subdomain(mw.SubdomainConfig{BaseDomain: "example.com", APISubdomain: "api", AdminSubdomain: "admin"})(
+		cfg.rateLimitAnonymous(hostRouter(g.shared, g.api)),
+	)
+
+	tests := []struct {
+		name          string
+		authorization string
+	}{
+		{name: "no credentials"},
+		// Nothing on the route checks the header, so it mustn't get past
+		// the IP limit
+		{name: "unchecked credentials", authorization: "Bearer not-a-token"},
+		{name: "unchecked api key", authorization: "ApiKey not-a-key"},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var codes []int
+			for range 3 {
+				req := httptest.NewRequest(http.MethodPost, "http://api.example.com/login", strings.NewReader("{}"))
+				// A client IP per case, so each starts with a fresh quota
+				req.RemoteAddr = fmt.Sprintf("192.0.2.%d:1234", i+1)
+				if tt.authorization != "" {
+					req.Header.Set("Authorization", tt.authorization)
+				}
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				codes = append(codes, rec.Code)
+			}
+			if codes[0] == http.StatusTooManyRequests || codes[2] != http.StatusTooManyRequests {
+				t.Errorf("statuses = %v, want the third request limited", codes)
+			}
+		})
+	}
+}
@@ -2,39 +2,187 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"log"
+	"log/slog"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/platform"
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
 type ctxKey int
 
-const userKey ctxKey = iota
+const (
+	userKey ctxKey = iota
+	customerKey
+	apiKeyKey
+	tenantClaimsKey
+	impersonationKey
+)
 
 func userFromContext(ctx context.Context) (uuid.UUID, bool) {
 	u, ok := ctx.Value(userKey).(uuid.UUID)
 	return u, ok
 }
 
+// apiKeyFromContext returns the API key a request authenticated with, if it
+// used one instead of a user token
+func apiKeyFromContext(ctx context.Context) (database.ApiKey, bool) {
+	k, ok := ctx.Value(apiKeyKey).(database.ApiKey)
+	return k, ok
+}
+
+// callerFromRequest identifies who sent a request: the API key it
+// authenticated with, or else its user
+func callerFromRequest(r *http.Request) (string, bool) {
+	if key, ok := apiKeyFromContext(r.Context()); ok {
+		return "api_key:" + key.ID.String(), true
+	}
+	if user, ok := userFromContext(r.Context()); ok {
+		return "user:" + user.String(), true
+	}
+	return "", false
+}
+
+// impersonatedTenantFromContext returns the tenant platform staff are
+// acting in, if the request carries an impersonation token
+func impersonatedTenantFromContext(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(impersonationKey).(uuid.UUID)
+	return id, ok
+}
+
 // we'll need to add to this later
 func (cfg *apiConfig) requireAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.Header.Get("Authorization"), "ApiKey ") {
+			cfg.authenticateAPIKey(w, r, next)
+			return
+		}
+
 		bearerToken, err := auth.GetBearerToken(r.Header)
 		log.Printf("value here : %s", bearerToken)
 		if err != nil {
 			respondWithError(w, http.StatusUnauthorized, "Authentication credentials are missing or invalid", err)
 			return
 		}
-		user, err := auth.ValidateJWT(bearerToken, cfg.signingKey)
+		tok, err := auth.ParseAccessToken(bearerToken, cfg.jwtKeys)
 		if err != nil {
-			respondWithError(w, http.StatusUnauthorized, "Authentication credentials are invalid.", err)
+			cfg.authenticateImpersonation(w, r, next, bearerToken, err)
 			return
 		}
-		log.Printf("valid User: %s", user)
+		log.Printf("valid User: %s", tok.UserID)
+
+		if cfg.config.TokenVersions {
+			version, err := cfg.db.GetUserTokenVersion(r.Context(), tok.UserID)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				respondWithError(w, http.StatusInternalServerError, "Unable to verify access token", err)
+				return
+			}
+			// Tokens issued before the user signed out everywhere, or
+			// before versions were turned on, are revoked
+			if err != nil || version != tok.Version {
+				respondWithError(w, http.StatusUnauthorized, "Access token has been revoked", nil)
+				return
+			}
+		}
 
-		ctx := context.WithValue(r.Context(), userKey, user)
+		ctx := context.WithValue(r.Context(), userKey, tok.UserID)
+		if tok.Tenants != nil {
+			ctx = context.WithValue(ctx, tenantClaimsKey, tok.Tenants)
+		}
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// authenticateAPIKey serves a request made with an "Authorization: ApiKey"
+// header. The request acts as the user who created the key, limited to the
+// key's tenant and scopes.
+func (cfg *apiConfig) authenticateAPIKey(w http.ResponseWriter, r *http.Request, next http.Handler) {
+	raw, err := auth.GetAPIKey(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication credentials are missing or invalid", err)
+		return
+	}
+
+	key, err := cfg.db.GetActiveAPIKeyByHash(r.Context(), auth.HashToken(raw))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusUnauthorized, "Authentication credentials are invalid.", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Unable to verify API key", err)
+		return
+	}
+
+	if err := cfg.db.TouchAPIKey(r.Context(), key.ID); err != nil {
+		// Losing a last-used timestamp isn't worth failing the request
+		slog.WarnContext(r.Context(), "unable to record api key use", "api_key_id", key.ID, "error", err)
+	}
+
+	ctx := context.WithValue(r.Context(), userKey, key.CreatedBy)
+	ctx = context.WithValue(ctx, apiKeyKey, key)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// authenticateImpersonation serves a bearer token that isn't a staff
+// access token if it is an impersonation token. The request acts as the
+// platform staff member, in the one tenant the token names, and
+// requireTenantMember keeps it there. accessErr is why the token isn't an
+// access token.
+func (cfg *apiConfig) authenticateImpersonation(w http.ResponseWriter, r *http.Request, next http.Handler, token string, accessErr error) {
+	user, tenantID, err := auth.ParseImpersonationJWT(token, cfg.jwtKeys)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication credentials are invalid.", accessErr)
+		return
+	}
+	ctx := context.WithValue(r.Context(), userKey, user)
+	ctx = context.WithValue(ctx, impersonationKey, tenantID)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// requireUserToken keeps API keys and impersonation tokens away from
+// account-level routes such as the user's own sessions and MFA settings
+func (cfg *apiConfig) requireUserToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := checkUserToken(r.Context()); err != nil {
+			respondWithAccessError(w, err)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireAPIKeyTenant stops an API key from being used on any tenant but
+// its own; see checkAPIKeyTenant
+func (cfg *apiConfig) requireAPIKeyTenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// An unparsable tenant is no key's own
+		tenantID, _ := uuid.Parse(chi.URLParam(r, "tenantID"))
+		if err := checkAPIKeyTenant(r.Context(), tenantID); err != nil {
+			respondWithAccessError(w, err)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// checkPermission reports whether the request may use a tenant permission.
+// Users need it through one of their roles; API keys need it among their
+// scopes, for their own tenant only. Impersonating staff hold every
+// permission but ownership in the tenant they act in.
+func (cfg *apiConfig) checkPermission(ctx context.Context, arg database.CheckUserHasPermissionParams) (bool, error) {
+	if key, ok := apiKeyFromContext(ctx); ok {
+		return key.TenantID == arg.TenantID && slices.Contains(key.Scopes, arg.Key), nil
+	}
+	if tenantID, ok := impersonatedTenantFromContext(ctx); ok {
+		return tenantID == arg.TenantID && platform.ImpersonationAllows(arg.Key), nil
+	}
+	return cfg.db.CheckUserHasPermission(ctx, arg)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/google/uuid"
+)
+
+func customerFromContext(ctx context.Context) (uuid.UUID, bool) {
+	c, ok := ctx.Value(customerKey).(uuid.UUID)
+	return c, ok
+}
+
+// requireStore rejects storefront requests that did not resolve to a store from the host
+func (cfg *apiConfig) requireStore(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		store, ok := middleware.GetResolvedStore(r.Context())
+		if !ok || !store.TenantID.Valid {
+			respondWithError(w, http.StatusNotFound, "Store not found", nil)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireCustomer authenticates a shopper token issued for the resolved store
+func (cfg *apiConfig) requireCustomer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		store, ok := middleware.GetResolvedStore(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusNotFound, "Store not found", nil)
+			return
+		}
+
+		bearerToken, err := auth.GetBearerToken(r.Header)
+		if err != nil {
+			respondWithError(w, http.StatusUnauthorized, "Authentication credentials are missing or invalid", err)
+			return
+		}
+		customer, err := auth.ValidateCustomerJWT(bearerToken, cfg.jwtKeys, store.ID)
+		if err != nil {
+			respondWithError(w, http.StatusUnauthorized, "Authentication credentials are invalid.", err)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), customerKey, customer)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// identifyCustomer lets guests through but, when the request carries a
+// shopper token, authenticates it like requireCustomer so prices can be
+// worked out for that customer
+func (cfg *apiConfig) identifyCustomer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// What a response shows depends on who asked
+		w.Header().Add("Vary", "Authorization")
+		if r.Header.Get("Authorization") == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cfg.requireCustomer(next).ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/tracing"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// tenantAccess is what requirePermission established for a request
+type tenantAccess struct {
+	UserID   uuid.UUID
+	TenantID uuid.UUID
+	// StoreID is only set on routes with a {storeID}, once the store is
+	// known to belong to the tenant
+	StoreID    uuid.UUID
+	Permission string
+}
+
+type tenantAccessKey struct{}
+
+// tenantAccessFrom returns the access requirePermission stored for the
+// request. Handlers behind requirePermission can rely on it; reaching one
+// without the middleware is a routing bug, so it panics rather than let the
+// handler run unauthorized.
+func tenantAccessFrom(r *http.Request) tenantAccess {
+	access, ok := r.Context().Value(tenantAccessKey{}).(tenantAccess)
+	if !ok {
+		panic("tenant handler " + r.URL.Path + " is not behind requirePermission")
+	}
+	return access
+}
+
+// requirePermission guards a tenant route behind requireTenantMember: it
+// checks the member holds permission in the tenant, applies the tenant's
+// MFA policy, resolves the store when the route has one, and stores the
+// result for the handler. It must wrap the endpoint itself (r.With) so
+// every URL parameter has been routed.
+func (cfg *apiConfig) requirePermission(permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// A span of its own so slow checks stand out from the handler
+			checkCtx, span := tracing.Start(r.Context(), "requirePermission", attribute.String("permission", permission))
+			access, ok := cfg.resolveTenantAccess(w, r.WithContext(checkCtx), permission)
+			span.SetAttributes(attribute.Bool("permission.granted", ok))
+			span.End()
+			if !ok {
+				return
+			}
+			ctx := context.WithValue(r.Context(), tenantAccessKey{}, access)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// resolveTenantAccess does the checks behind requirePermission. It writes
+// the error response and returns ok=false when the request is denied.
+func (cfg *apiConfig) resolveTenantAccess(w http.ResponseWriter, r *http.Request, permission string) (tenantAccess, bool) {
+	tc := tenantContextFrom(r)
+	user, tenantID := tc.Membership.UserID, tc.Tenant.ID
+
+	var (
+		storeID uuid.UUID
+		err     error
+	)
+	if storeParam := chi.URLParam(r, "storeID"); storeParam != "" {
+		storeID, err = uuid.Parse(storeParam)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid store ID format", err)
+			return tenantAccess{}, false
+		}
+	}
+
+	if err := cfg.checkTenantPermission(r.Context(), tc, permission); err != nil {
+		respondWithAccessError(w, err)
+		return tenantAccess{}, false
+	}
+
+	if storeID != uuid.Nil {
+		// Verify store belongs to tenant
+		_, err = cfg.db.GetStoreByTenantAndID(r.Context(), database.GetStoreByTenantAndIDParams{
+			TenantID: uuid.NullUUID{UUID: tenantID, Valid: true},
+			ID:       storeID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				respondWithError(w, http.StatusNotFound, "Store not found in this tenant", nil)
+				return tenantAccess{}, false
+			}
+			respondWithError(w, http.StatusInternalServerError, "Unable to verify store", err)
+			return tenantAccess{}, false
+		}
+	}
+
+	return tenantAccess{
+		UserID:     user,
+		TenantID:   tenantID,
+		StoreID:    storeID,
+		Permission: permission,
+	}, true
+}
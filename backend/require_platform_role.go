@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/platform"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/middleware"
+	"github.com/google/uuid"
+)
+
+// platformStaff is the platform staff member requirePlatformRole let
+// through
+type platformStaff struct {
+	UserID uuid.UUID
+	Email  string
+	Role   platform.Role
+}
+
+type platformStaffKey struct{}
+
+// platformStaffFrom returns the staff member requirePlatformRole stored.
+// Like tenantAccessFrom, a missing one is a routing bug.
+func platformStaffFrom(r *http.Request) platformStaff {
+	staff, ok := r.Context().Value(platformStaffKey{}).(platformStaff)
+	if !ok {
+		panic("admin handler " + r.URL.Path + " is not behind requirePlatformRole")
+	}
+	return staff
+}
+
+// requirePlatformRole lets through users whose platform role allows need.
+// The role is read on every request, so taking it away takes effect at
+// once. It goes behind requireAuth and requireUserToken.
+func (cfg *apiConfig) requirePlatformRole(need platform.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := userFromContext(r.Context())
+			if !ok {
+				respondWithError(w, http.StatusUnauthorized, "Authentication required", nil)
+				return
+			}
+
+			row, err := cfg.db.GetUserPlatformRole(r.Context(), user)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				respondWithError(w, http.StatusInternalServerError, "Unable to verify platform role", err)
+				return
+			}
+			role := platform.Role(row.PlatformRole.String)
+			if !role.Allows(need) {
+				slog.WarnContext(r.Context(), "admin request denied: platform role too low",
+					"request_id", middleware.GetRequestID(r.Context()),
+					"user_id", user,
+					"role", role,
+					"need", need,
+				)
+				respondWithErrorCode(w, http.StatusForbidden, problem.CodePermissionDenied, "You do not have permission to perform this action", nil)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), platformStaffKey{}, platformStaff{UserID: user, Email: row.Email, Role: role})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// recordPlatformAction adds what staff did to a tenant to the platform
+// audit log, or to the platform itself when tenantID is uuid.Nil. q lets it
+// join the transaction making the change.
+func (cfg *apiConfig) recordPlatformAction(r *http.Request, q *database.Queries, action string, tenantID uuid.UUID, reason string) error {
+	staff := platformStaffFrom(r)
+	reqID, ip := middleware.GetRequestID(r.Context()), middleware.ClientIP(r)
+	return q.CreatePlatformAuditLogEntry(r.Context(), database.CreatePlatformAuditLogEntryParams{
+		ActorUserID: uuid.NullUUID{UUID: staff.UserID, Valid: true},
+		ActorEmail:  staff.Email,
+		Action:      action,
+		TenantID:    uuid.NullUUID{UUID: tenantID, Valid: tenantID != uuid.Nil},
+		Reason:      reason,
+		RequestID:   sql.NullString{String: reqID, Valid: reqID != ""},
+		Ip:          sql.NullString{String: ip, Valid: ip != ""},
+	})
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/middleware"
+)
+
+// storefrontTokenHeader carries a storefront access token. Authorization
+// is left for the shopper's own token.
+const storefrontTokenHeader = "X-Storefront-Access-Token"
+
+type storefrontTokenKey struct{}
+
+func storefrontTokenFromContext(ctx context.Context) (database.StorefrontToken, bool) {
+	t, ok := ctx.Value(storefrontTokenKey{}).(database.StorefrontToken)
+	return t, ok
+}
+
+// requireStorefrontToken authenticates the storefront access token sent for
+// the resolved store. Until storefront tokens are required, requests
+// without one still pass, but a token that is sent must be valid.
+func (cfg *apiConfig) requireStorefrontToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw := r.Header.Get(storefrontTokenHeader)
+		if raw == "" {
+			if cfg.config.StorefrontTokensRequired {
+				respondWithError(w, http.StatusUnauthorized, "A storefront access token is required", nil)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, err := cfg.db.GetActiveStorefrontTokenByHash(r.Context(), auth.HashToken(raw))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				respondWithError(w, http.StatusUnauthorized, "Storefront access token is invalid", nil)
+				return
+			}
+			respondWithError(w, http.StatusInternalServerError, "Unable to verify storefront access token", err)
+			return
+		}
+
+		store, _ := middleware.GetResolvedStore(r.Context())
+		if token.StoreID != store.ID {
+			respondWithError(w, http.StatusUnauthorized, "Storefront access token is invalid", nil)
+			return
+		}
+
+		if err := cfg.db.TouchStorefrontToken(r.Context(), database.TouchStorefrontTokenParams{
+			ID:         token.ID,
+			LastUsedIp: sql.NullString{String: middleware.ClientIP(r), Valid: true},
+		}); err != nil {
+			slog.WarnContext(r.Context(), "unable to record storefront token use", "storefront_token_id", token.ID, "error", err)
+		}
+
+		ctx := context.WithValue(r.Context(), storefrontTokenKey{}, token)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// requireStorefrontScope checks the request's storefront token carries
+// scope. Requests without a token only get here while tokens are optional.
+func (cfg *apiConfig) requireStorefrontScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token, ok := storefrontTokenFromContext(r.Context()); ok && !slices.Contains(token.Scopes, scope) {
+				respondWithErrorCode(w, http.StatusForbidden, problem.CodeInsufficientScope, "This storefront access token lacks the "+scope+" scope", nil)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
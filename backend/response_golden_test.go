@@ -0,0 +1,327 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dfodeker/terminus/internal/fixtures"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/go-chi/chi/v5"
+)
+
+// responseCase renders a response type empty and filled
+type responseCase struct {
+	empty, filled any
+}
+
+func response[T any]() responseCase {
+	var empty T
+	return responseCase{empty: empty, filled: fixtures.Fill[T]()}
+}
+
+// responseTypes are the response types of the API by name. Every struct
+// named *Response must be listed, which TestResponseTypesListed checks.
+var responseTypes = map[string]responseCase{
+	"APIKeyResponse":                       response[APIKeyResponse](),
+	"AuditLogEntryResponse":                response[AuditLogEntryResponse](),
+	"BulkOperationResponse":                response[BulkOperationResponse](),
+	"BulkOperationReportResponse":          response[BulkOperationReportResponse](),
+	"BulkOperationResultResponse":          response[BulkOperationResultResponse](),
+	"BundleComponentResponse":              response[BundleComponentResponse](),
+	"ChannelConversionResponse":            response[ChannelConversionResponse](),
+	"CollectionResponse":                   response[CollectionResponse](),
+	"CreateAPIKeyResponse":                 response[CreateAPIKeyResponse](),
+	"CreateStorefrontTokenResponse":        response[CreateStorefrontTokenResponse](),
+	"CreateTenantResponse":                 response[CreateTenantResponse](),
+	"CustomerAddressResponse":              response[CustomerAddressResponse](),
+	"CustomerGroupResponse":                response[CustomerGroupResponse](),
+	"CustomerResponse":                     response[CustomerResponse](),
+	"EmailPreviewResponse":                 response[EmailPreviewResponse](),
+	"EmailTemplateResponse":                response[EmailTemplateResponse](),
+	"ExportResponse":                       response[ExportResponse](),
+	"FulfillmentLabelResponse":             response[FulfillmentLabelResponse](),
+	"FulfillmentLineItemResponse":          response[FulfillmentLineItemResponse](),
+	"FulfillmentResponse":                  response[FulfillmentResponse](),
+	"GiftCardBalanceResponse":              response[GiftCardBalanceResponse](),
+	"GiftCardResponse":                     response[GiftCardResponse](),
+	"GiftCardTransactionResponse":          response[GiftCardTransactionResponse](),
+	"HandleAvailabilityResponse":           response[HandleAvailabilityResponse](),
+	"InventoryAlertResponse":               response[InventoryAlertResponse](),
+	"InventoryCountLineResponse":           response[InventoryCountLineResponse](),
+	"InventoryCountResponse":               response[InventoryCountResponse](),
+	"InventoryItemResponse":                response[InventoryItemResponse](),
+	"InventoryLevelResponse":               response[InventoryLevelResponse](),
+	"InventoryMovementResponse":            response[InventoryMovementResponse](),
+	"InvitationPreviewResponse":            response[InvitationPreviewResponse](),
+	"LocationResponse":                     response[LocationResponse](),
+	"LoginResponse":                        response[LoginResponse](),
+	"LowStockResponse":                     response[LowStockResponse](),
+	"MFAChallengeResponse":                 response[MFAChallengeResponse](),
+	"MFAEnrollResponse":                    response[MFAEnrollResponse](),
+	"MFARecoveryCodesResponse":             response[MFARecoveryCodesResponse](),
+	"MFAStatusResponse":                    response[MFAStatusResponse](),
+	"MagicLinkResponse":                    response[MagicLinkResponse](),
+	"MeResponse":                           response[MeResponse](),
+	"NodeResponse":                         response[NodeResponse](),
+	"NotificationPreferenceResponse":       response[NotificationPreferenceResponse](),
+	"NotificationResponse":                 response[NotificationResponse](),
+	"OrderEventResponse":                   response[OrderEventResponse](),
+	"OrderLineItemResponse":                response[OrderLineItemResponse](),
+	"OrderResponse":                        response[OrderResponse](),
+	"PageResponse":                         response[PageResponse[TenantResponse]](),
+	"PermissionResponse":                   response[PermissionResponse](),
+	"PriceListEntryResponse":               response[PriceListEntryResponse](),
+	"PriceListResponse":                    response[PriceListResponse](),
+	"PrivacyRequestResponse":               response[PrivacyRequestResponse](),
+	"ProductFacetsResponse":                response[ProductFacetsResponse](),
+	"ProductFeedResponse":                  response[ProductFeedResponse](),
+	"ProductImportResponse":                response[ProductImportResponse](),
+	"ProductImportReportResponse":          response[ProductImportReportResponse](),
+	"ProductImportRowResponse":             response[ProductImportRowResponse](),
+	"ProductInventoryResponse":             response[ProductInventoryResponse](),
+	"ProductMediaResponse":                 response[ProductMediaResponse](),
+	"ProductPublicationResponse":           response[ProductPublicationResponse](),
+	"ProductResponse":                      response[ProductResponse](),
+	"ProductReviewResponse":                response[ProductReviewResponse](),
+	"ProductTranslationResponse":           response[ProductTranslationResponse](),
+	"RefundLineItemResponse":               response[RefundLineItemResponse](),
+	"RefundResponse":                       response[RefundResponse](),
+	"ReservationItemResponse":              response[ReservationItemResponse](),
+	"ReservationResponse":                  response[ReservationResponse](),
+	"RoleResponse":                         response[RoleResponse](),
+	"SSOConnectionResponse":                response[SSOConnectionResponse](),
+	"SSODomainResponse":                    response[SSODomainResponse](),
+	"SalesChannelResponse":                 response[SalesChannelResponse](),
+	"SellingPlanResponse":                  response[SellingPlanResponse](),
+	"SessionResponse":                      response[SessionResponse](),
+	"ShippingOptionResponse":               response[ShippingOptionResponse](),
+	"ShippingQuoteResponse":                response[ShippingQuoteResponse](),
+	"ShippingRateResponse":                 response[ShippingRateResponse](),
+	"ShippingZoneResponse":                 response[ShippingZoneResponse](),
+	"ShopifyImportItemResponse":            response[ShopifyImportItemResponse](),
+	"ShopifyImportResponse":                response[ShopifyImportResponse](),
+	"ShopifyImportReportResponse":          response[ShopifyImportReportResponse](),
+	"StorefrontCollectionProductsResponse": response[StorefrontCollectionProductsResponse](),
+	"StorefrontProductResponse":            response[StorefrontProductResponse](),
+	"StorefrontReviewPageResponse":         response[StorefrontReviewPageResponse](),
+	"StorefrontReviewResponse":             response[StorefrontReviewResponse](),
+	"StorefrontSellingPlanResponse":        response[StorefrontSellingPlanResponse](),
+	"StorefrontTokenResponse":              response[StorefrontTokenResponse](),
+	"SubscriptionResponse":                 response[SubscriptionResponse](),
+	"TenantCustomerResponse":               response[TenantCustomerResponse](),
+	"TenantInvitationResponse":             response[TenantInvitationResponse](),
+	"TenantMeResponse":                     response[TenantMeResponse](),
+	"TenantMemberResponse":                 response[TenantMemberResponse](),
+	"TenantOrderResponse":                  response[TenantOrderResponse](),
+	"TenantProductResponse":                response[TenantProductResponse](),
+	"TenantResponse":                       response[TenantResponse](),
+	"TenantStoreResponse":                  response[TenantStoreResponse](),
+	"TenantUserResponse":                   response[TenantUserResponse](),
+	"TopProductResponse":                   response[TopProductResponse](),
+	"VariantLookupResponse":                response[VariantLookupResponse](),
+	"VariantPriceResponse":                 response[VariantPriceResponse](),
+	"VariantResponse":                      response[VariantResponse](),
+	"adminAuditLogEntryResponse":           response[adminAuditLogEntryResponse](),
+	"adminEncryptionKeyResponse":           response[adminEncryptionKeyResponse](),
+	"adminImpersonationResponse":           response[adminImpersonationResponse](),
+	"adminSecurityEventResponse":           response[adminSecurityEventResponse](),
+	"adminStoreResponse":                   response[adminStoreResponse](),
+	"adminTenantDetailResponse":            response[adminTenantDetailResponse](),
+	"adminTenantResponse":                  response[adminTenantResponse](),
+	"adminUserResponse":                    response[adminUserResponse](),
+	"apiRateResponse":                      response[apiRateResponse](),
+	"billingResponse":                      response[billingResponse](),
+	"billingSessionResponse":               response[billingSessionResponse](),
+	"billingSubscriptionResponse":          response[billingSubscriptionResponse](),
+	"customerAuthResponse":                 response[customerAuthResponse](),
+	"entitlementsResponse":                 response[entitlementsResponse](),
+	"entitlementsUsageResponse":            response[entitlementsUsageResponse](),
+	"usageDayResponse":                     response[usageDayResponse](),
+	"usageResponse":                        response[usageResponse](),
+	"usageTotalResponse":                   response[usageTotalResponse](),
+}
+
+func TestResponseGolden(t *testing.T) {
+	for name, tc := range responseTypes {
+		t.Run(name, func(t *testing.T) {
+			rendered := map[string]any{"empty": tc.empty, "filled": tc.filled}
+			got, err := json.Marshal(rendered)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fixtures.Golden(t, "responses/"+name+".json", got)
+		})
+	}
+}
+
+func TestResponseTypesListed(t *testing.T) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range pkgs["main"].Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			spec, ok := n.(*ast.TypeSpec)
+			if !ok || !strings.HasSuffix(spec.Name.Name, "Response") {
+				return true
+			}
+			if _, isStruct := spec.Type.(*ast.StructType); !isStruct {
+				return true
+			}
+			if _, listed := responseTypes[spec.Name.Name]; !listed {
+				t.Errorf("%s is not in responseTypes; add it so its JSON is checked", spec.Name.Name)
+			}
+			return true
+		})
+	}
+}
+
+// TestPageEnvelopesTyped keeps list handlers on PageResponse and the other
+// typed pages, whose JSON TestResponseGolden pins, rather than a page
+// built from maps the golden files can't see
+func TestPageEnvelopesTyped(t *testing.T) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range pkgs["main"].Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) != 3 {
+				return true
+			}
+			if fn, ok := call.Fun.(*ast.Ident); !ok || fn.Name != "respondWithJSON" {
+				return true
+			}
+			lit, ok := call.Args[2].(*ast.CompositeLit)
+			if !ok {
+				return true
+			}
+			if _, isMap := lit.Type.(*ast.MapType); !isMap {
+				return true
+			}
+			for _, elt := range lit.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				if key, ok := kv.Key.(*ast.BasicLit); ok && key.Value == `"page"` {
+					t.Errorf("%s: page envelope built from a map; respond with PageResponse or a typed page", fset.Position(lit.Pos()))
+				}
+			}
+			return true
+		})
+	}
+}
+
+func TestPageEnvelopeGolden(t *testing.T) {
+	tests := []struct {
+		name string
+		page PageResponse[TenantResponse]
+	}{
+		{
+			name: "last_page",
+			page: PageResponse[TenantResponse]{
+				Data: []TenantResponse{fixtures.Fill[TenantResponse]()},
+				Page: PageInfo{Limit: 20},
+			},
+		},
+		{
+			name: "more",
+			page: PageResponse[TenantResponse]{
+				Data: []TenantResponse{fixtures.Fill[TenantResponse]()},
+				Page: PageInfo{Limit: 1, NextCursor: "cursor", HasMore: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			respondWithJSON(w, http.StatusOK, tt.page)
+			if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", ct)
+			}
+			fixtures.Golden(t, "envelopes/page_"+tt.name+".json", w.Body.Bytes())
+		})
+	}
+}
+
+func TestProblemGolden(t *testing.T) {
+	router := hostRouter(func(r chi.Router) {
+		r.Get("/thing", func(w http.ResponseWriter, r *http.Request) {})
+	})
+	route := func(method, path string) func(w http.ResponseWriter) {
+		return func(w http.ResponseWriter) {
+			router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		}
+	}
+
+	tests := []struct {
+		name       string
+		respond    func(w http.ResponseWriter)
+		wantStatus int
+	}{
+		{name: "not_found", respond: route(http.MethodGet, "/nowhere"), wantStatus: http.StatusNotFound},
+		{name: "method_not_allowed", respond: route(http.MethodDelete, "/thing"), wantStatus: http.StatusMethodNotAllowed},
+		{
+			name: "error",
+			respond: func(w http.ResponseWriter) {
+				respondWithError(w, http.StatusBadRequest, "Couldn't decode parameters", nil)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "validation",
+			respond: func(w http.ResponseWriter) {
+				var v validate.Validator
+				v.Required("name", "")
+				v.Handle("handle", "Not A Handle")
+				respondWithValidationError(w, v.Err())
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "service_conflict",
+			respond: func(w http.ResponseWriter) {
+				respondWithServiceError(w, service.HandleTaken("The handle is taken", "demo-2"), "Couldn't create store")
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name: "service_unexpected",
+			respond: func(w http.ResponseWriter) {
+				respondWithServiceError(w, errors.New("connection refused"), "Couldn't create store")
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.respond(w)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != problem.ContentType {
+				t.Errorf("Content-Type = %q, want %q", ct, problem.ContentType)
+			}
+			fixtures.Golden(t, "problems/"+tt.name+".json", w.Body.Bytes())
+		})
+	}
+}
@@ -0,0 +1,800 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/export"
+	"github.com/dfodeker/terminus/internal/platform"
+	"github.com/dfodeker/terminus/internal/reviews"
+	"github.com/dfodeker/terminus/internal/service/products"
+	"github.com/dfodeker/terminus/internal/shopify"
+	"github.com/dfodeker/terminus/internal/storage"
+	mw "github.com/dfodeker/terminus/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// routeGroups are the routes served on each kind of host
+type routeGroups struct {
+	shared     func(chi.Router)
+	root       func(chi.Router)
+	admin      func(chi.Router)
+	storefront func(chi.Router)
+	api        func(chi.Router)
+}
+
+// routes builds the route groups. Nothing is looked up while they are
+// built, so the OpenAPI document can be generated from them without a
+// database.
+func (cfg *apiConfig) routes() routeGroups {
+	// Routes are grouped by the host they are served on. Subdomain classifies
+	// the host and HostRouter picks the group, so one binary serves api.*,
+	// admin.*, store subdomains, custom domains and the root domain.
+	sharedRoutes := func(r chi.Router) {
+		r.Get("/health", cfg.handlerHealthReady)
+		r.Get("/health/live", cfg.handlerHealthLive)
+		r.Get("/health/ready", cfg.handlerHealthReady)
+
+		// Local disk storage serves its own files and presigned uploads
+		if disk, ok := cfg.storage.(*storage.Disk); ok {
+			r.Mount("/media", http.StripPrefix("/media", disk.Handler()))
+		}
+	}
+
+	// Marketing site on the root domain
+	rootRoutes := func(r chi.Router) {
+		r.Get("/", homeHandler)
+	}
+
+	// Operator-only endpoints on admin.*
+	adminRoutes := func(r chi.Router) {
+		r.Mount("/debug", middleware.Profiler())
+		r.Get("/metrics", promhttp.Handler().ServeHTTP)
+
+		// The platform admin API, across every tenant, for staff with a
+		// platform role. Support may look; admins also suspend tenants, act
+		// in them and rotate the data key.
+		r.Route("/api/v1/admin", func(r chi.Router) {
+			r.Use(cfg.requireAuth)
+			r.Use(cfg.requireUserToken)
+			r.Use(cfg.requirePlatformRole(platform.RoleSupport))
+
+			r.Get("/tenants", cfg.handlerAdminTenantsList)
+			r.Route("/tenants/{tenantID}", func(r chi.Router) {
+				r.Get("/", cfg.handlerAdminTenantGet)
+				r.Get("/usage", cfg.handlerAdminTenantUsage)
+				r.Group(func(r chi.Router) {
+					r.Use(cfg.requirePlatformRole(platform.RoleAdmin))
+					r.Post("/suspend", cfg.handlerAdminTenantSuspend)
+					r.Post("/unsuspend", cfg.handlerAdminTenantUnsuspend)
+					r.Post("/impersonate", cfg.handlerAdminTenantImpersonate)
+				})
+			})
+			r.Get("/stores", cfg.handlerAdminStoresList)
+			r.Get("/users", cfg.handlerAdminUsersList)
+			r.Get("/audit-log", cfg.handlerAdminAuditLogList)
+			r.Get("/security-events", cfg.handlerAdminSecurityEventsList)
+			r.Get("/encryption-keys", cfg.handlerAdminEncryptionKeysList)
+			r.With(cfg.requirePlatformRole(platform.RoleAdmin)).Post("/encryption-keys/rotate", cfg.handlerAdminEncryptionKeyRotate)
+		})
+	}
+
+	// Storefront (shopper-facing) routes on store subdomains and custom
+	// domains, scoped to the store resolved from the request host
+	storefrontRoutes := func(r chi.Router) {
+		r.Route("/api/v1/storefront", func(r chi.Router) {
+			r.Use(cfg.requireStore)
+			r.Use(cfg.requireStorefrontToken)
+
+			r.Group(func(r chi.Router) {
+				r.Use(cfg.requireStorefrontScope(auth.ScopeReadProducts))
+				r.Use(cfg.identifyCustomer)
+				r.Get("/products", cfg.handlerStorefrontProductsList)
+				r.Get("/products/facets", cfg.handlerStorefrontProductFacets)
+				r.Get("/collections", cfg.handlerStorefrontCollectionsList)
+				r.Get("/collections/{handle}/products", cfg.handlerStorefrontCollectionProductsList)
+				r.Get("/products/{productID}/selling-plans", cfg.handlerStorefrontProductSellingPlans)
+				r.Get("/products/{productID}/reviews", cfg.handlerStorefrontProductReviewsList)
+			})
+			r.With(cfg.requireStorefrontScope(auth.ScopeManageCheckout), cfg.requireCustomer).Post("/products/{productID}/reviews", cfg.handlerStorefrontProductReviewCreate)
+			r.With(cfg.requireStorefrontScope(auth.ScopeManageCheckout)).Post("/gift-cards/balance", cfg.handlerStorefrontGiftCardBalance)
+			r.With(cfg.requireStorefrontScope(auth.ScopeManageCheckout), cfg.identifyCustomer).Post("/shipping/quote", cfg.handlerStorefrontShippingQuote)
+
+			r.Route("/reservations", func(r chi.Router) {
+				r.Use(cfg.requireStorefrontScope(auth.ScopeManageCheckout))
+				r.Use(cfg.identifyCustomer)
+				r.Post("/", cfg.handlerStorefrontReservationCreate)
+				r.Get("/{reservationID}", cfg.handlerStorefrontReservationGet)
+				r.Delete("/{reservationID}", cfg.handlerStorefrontReservationRelease)
+			})
+
+			r.Route("/customers", func(r chi.Router) {
+				r.Use(cfg.requireStorefrontScope(auth.ScopeManageCheckout))
+				r.Post("/", cfg.handlerStorefrontCustomerRegister)
+				r.Post("/login", cfg.handlerStorefrontCustomerLogin)
+				r.Post("/refresh", cfg.handlerStorefrontCustomerRefresh)
+				r.Post("/revoke", cfg.handlerStorefrontCustomerRevoke)
+
+				r.Group(func(r chi.Router) {
+					r.Use(cfg.requireCustomer)
+
+					r.Get("/me", cfg.handlerStorefrontCustomerMe)
+					r.Put("/me", cfg.handlerStorefrontCustomerUpdate)
+					r.Get("/me/orders", cfg.handlerStorefrontCustomerOrdersList)
+					r.Get("/me/orders/{orderID}", cfg.handlerStorefrontCustomerOrderGet)
+					r.Post("/me/orders/{orderID}/gift-cards", cfg.handlerStorefrontOrderGiftCardRedeem)
+
+					r.Route("/me/subscriptions", func(r chi.Router) {
+						r.Get("/", cfg.handlerStorefrontSubscriptionsList)
+						r.Post("/", cfg.handlerStorefrontSubscriptionCreate)
+
+						r.Route("/{subscriptionID}", func(r chi.Router) {
+							r.Get("/", cfg.handlerStorefrontSubscriptionGet)
+							r.Post("/pause", cfg.handlerStorefrontSubscriptionPause)
+							r.Post("/resume", cfg.handlerStorefrontSubscriptionResume)
+							r.Post("/cancel", cfg.handlerStorefrontSubscriptionCancel)
+						})
+					})
+
+					r.Route("/me/addresses", func(r chi.Router) {
+						r.Get("/", cfg.handlerStorefrontCustomerAddressesList)
+						r.Post("/", cfg.handlerStorefrontCustomerAddressCreate)
+
+						r.Route("/{addressID}", func(r chi.Router) {
+							r.Get("/", cfg.handlerStorefrontCustomerAddressGet)
+							r.Put("/", cfg.handlerStorefrontCustomerAddressUpdate)
+							r.Delete("/", cfg.handlerStorefrontCustomerAddressDelete)
+						})
+					})
+				})
+			})
+		})
+	}
+
+	// Admin API on api.*
+	apiRoutes := func(r chi.Router) {
+		r.With(cfg.rateLimitPublic).Get("/.well-known/jwks.json", cfg.handlerJWKS)
+		// The GraphQL Admin API resolves each field with the checks of its
+		// REST route
+		r.With(cfg.requireAuth, cfg.rateLimitCaller, cfg.meterCaller).Post("/admin/graphql", cfg.handlerAdminGraphQL)
+
+		r.Route("/api/v1", func(r chi.Router) {
+			r.With(cfg.rateLimitPublic).Get("/openapi.json", cfg.handlerOpenAPI)
+			if cfg.config.Platform == "dev" {
+				r.With(cfg.rateLimitPublic).Get("/docs", cfg.handlerAPIDocs)
+			}
+
+			r.With(cfg.rateLimitPublic).Post("/users", cfg.CreateUserHandler)
+			// Every user on the platform, so only for platform staff; the
+			// same list is on the admin API
+			r.With(cfg.requireAuth, cfg.rateLimitCaller, cfg.requireUserToken, cfg.requirePlatformRole(platform.RoleSupport)).Get("/users", cfg.handlerAdminUsersList)
+
+			r.Group(func(r chi.Router) {
+				r.Use(cfg.requireAuth)
+				r.Use(cfg.rateLimitCaller)
+				r.Use(cfg.meterCaller)
+
+				r.Route("/stores", func(r chi.Router) {
+					// Checks membership rather than permissions, so not for API keys
+					r.Use(cfg.requireUserToken)
+					r.Get("/", cfg.handlerGetStores)
+				})
+
+				r.Route("/tenants", func(r chi.Router) {
+					r.With(cfg.requireUserToken).Post("/", cfg.handlerTenantsCreate)
+					r.With(cfg.requireUserToken).Get("/", cfg.handlerTenantsList)
+
+					r.Route("/{tenantID}", func(r chi.Router) {
+						r.Use(cfg.requireAPIKeyTenant)
+						r.Use(cfg.requireClaimedTenant)
+						r.Use(cfg.requireTenantMember)
+						r.Use(cfg.rateLimitTenant)
+						r.Use(cfg.meterTenant)
+						// Replays the stored response for retried POST/PUT requests carrying an Idempotency-Key
+						r.Use(mw.Idempotency(mw.IdempotencyConfig{DB: cfg.db, Caller: callerFromRequest}))
+						// Records every successful mutation under the tenant
+						r.Use(cfg.auditLog)
+
+						// Stores under tenant
+						r.Route("/stores", func(r chi.Router) {
+							r.With(cfg.requirePermission("stores:create")).Post("/", cfg.handlerTenantStoresCreate)
+							r.With(cfg.requirePermission("stores:view")).Get("/", cfg.handlerTenantStoresList)
+							r.With(cfg.requirePermission("stores:create")).Get("/handle-availability", cfg.handlerTenantStoreHandleAvailability)
+							r.With(cfg.requirePermission("stores:view")).Get("/deleted", cfg.handlerTenantStoresDeletedList)
+							r.With(cfg.requirePermission("stores:delete")).Post("/deleted/{deletedStoreID}/restore", cfg.handlerTenantStoreRestore)
+
+							r.Route("/{storeID}", func(r chi.Router) {
+								r.With(cfg.requirePermission("stores:edit")).Put("/", cfg.handlerTenantStoreUpdate)
+								r.With(cfg.requirePermission("stores:edit")).Post("/status", cfg.handlerTenantStoreStatus)
+								r.With(cfg.requirePermission("stores:delete")).Delete("/", cfg.handlerTenantStoreDelete)
+								r.With(cfg.requirePermission("stores:view")).Get("/settings", cfg.handlerTenantStoreSettingsGet)
+								r.With(cfg.requirePermission("stores:edit")).Patch("/settings", cfg.handlerTenantStoreSettingsUpdate)
+								r.With(cfg.requirePermission("stores:view")).Get("/currencies", cfg.handlerTenantStoreCurrenciesGet)
+								r.With(cfg.requirePermission("stores:edit")).Put("/currencies", cfg.handlerTenantStoreCurrenciesUpdate)
+
+								// Customer order emails
+								r.Route("/email-templates", func(r chi.Router) {
+									r.With(cfg.requirePermission("stores:view")).Get("/", cfg.handlerTenantEmailTemplatesList)
+
+									r.Route("/{template}", func(r chi.Router) {
+										r.With(cfg.requirePermission("stores:view")).Get("/", cfg.handlerTenantEmailTemplateGet)
+										r.With(cfg.requirePermission("stores:edit")).Put("/", cfg.handlerTenantEmailTemplateUpdate)
+										r.With(cfg.requirePermission("stores:edit")).Delete("/", cfg.handlerTenantEmailTemplateDelete)
+										r.With(cfg.requirePermission("stores:edit")).Post("/test", cfg.handlerTenantEmailTemplateTest)
+									})
+								})
+
+								// Customers
+								r.Route("/customers", func(r chi.Router) {
+									r.With(cfg.requirePermission("customers:view")).Get("/", cfg.handlerTenantCustomersList)
+									r.With(cfg.requirePermission("customers:manage")).Post("/imports/shopify", cfg.handlerTenantShopifyImportCreate(shopify.EntityCustomers))
+									r.With(cfg.requirePermission("customers:view")).Get("/imports/shopify/{importID}", cfg.handlerTenantShopifyImportGet(shopify.EntityCustomers))
+									r.With(cfg.requirePermission("customers:view")).Get("/tags", cfg.handlerTenantCustomerTagsList)
+									r.With(cfg.requirePermission("customers:view")).Post("/exports", cfg.handlerTenantExportCreate(export.ResourceCustomers))
+									r.With(cfg.requirePermission("customers:view")).Get("/exports/{exportID}", cfg.handlerTenantExportGet(export.ResourceCustomers))
+
+									r.Route("/{customerID}", func(r chi.Router) {
+										r.With(cfg.requirePermission("customers:view")).Get("/", cfg.handlerTenantCustomerGet)
+										r.With(cfg.requirePermission("customers:manage")).Put("/", cfg.handlerTenantCustomerUpdate)
+										r.With(cfg.requirePermission("customers:manage")).Put("/tags", cfg.handlerTenantCustomerTagsUpdate)
+										r.With(cfg.requirePermission("customers:view")).Get("/orders", cfg.handlerTenantCustomerOrdersList)
+
+										// Data export and erasure on the customer's behalf
+										r.Route("/privacy-requests", func(r chi.Router) {
+											r.With(cfg.requirePermission("customers:manage")).Post("/", cfg.handlerTenantCustomerPrivacyRequestCreate)
+											r.With(cfg.requirePermission("customers:manage")).Get("/{requestID}", cfg.handlerTenantCustomerPrivacyRequestGet)
+										})
+
+										r.Route("/addresses", func(r chi.Router) {
+											r.With(cfg.requirePermission("customers:view")).Get("/", cfg.handlerTenantCustomerAddressesList)
+											r.With(cfg.requirePermission("customers:manage")).Post("/", cfg.handlerTenantCustomerAddressCreate)
+
+											r.Route("/{addressID}", func(r chi.Router) {
+												r.With(cfg.requirePermission("customers:manage")).Put("/", cfg.handlerTenantCustomerAddressUpdate)
+												r.With(cfg.requirePermission("customers:manage")).Delete("/", cfg.handlerTenantCustomerAddressDelete)
+											})
+										})
+									})
+								})
+
+								// Customer groups
+								r.Route("/customer-groups", func(r chi.Router) {
+									r.With(cfg.requirePermission("customers:view")).Get("/", cfg.handlerTenantCustomerGroupsList)
+									r.With(cfg.requirePermission("customers:manage")).Post("/", cfg.handlerTenantCustomerGroupsCreate)
+
+									r.Route("/{groupID}", func(r chi.Router) {
+										r.With(cfg.requirePermission("customers:view")).Get("/", cfg.handlerTenantCustomerGroupGet)
+										r.With(cfg.requirePermission("customers:manage")).Put("/", cfg.handlerTenantCustomerGroupUpdate)
+										r.With(cfg.requirePermission("customers:manage")).Delete("/", cfg.handlerTenantCustomerGroupDelete)
+										r.With(cfg.requirePermission("customers:manage")).Post("/recalculate", cfg.handlerTenantCustomerGroupRecalculate)
+										r.With(cfg.requirePermission("customers:view")).Get("/customers", cfg.handlerTenantCustomerGroupMembersList)
+										r.With(cfg.requirePermission("customers:manage")).Put("/customers/{customerID}", cfg.handlerTenantCustomerGroupMemberAdd)
+										r.With(cfg.requirePermission("customers:manage")).Delete("/customers/{customerID}", cfg.handlerTenantCustomerGroupMemberRemove)
+									})
+								})
+
+								// Product reviews
+								r.Route("/reviews", func(r chi.Router) {
+									r.With(cfg.requirePermission("reviews:moderate")).Get("/", cfg.handlerTenantProductReviewsList)
+
+									r.Route("/{reviewID}", func(r chi.Router) {
+										r.With(cfg.requirePermission("reviews:moderate")).Get("/", cfg.handlerTenantProductReviewGet)
+										r.With(cfg.requirePermission("reviews:moderate")).Delete("/", cfg.handlerTenantProductReviewDelete)
+										r.With(cfg.requirePermission("reviews:moderate")).Post("/approve", cfg.handlerTenantProductReviewModerate(reviews.StatusApproved))
+										r.With(cfg.requirePermission("reviews:moderate")).Post("/reject", cfg.handlerTenantProductReviewModerate(reviews.StatusRejected))
+										r.With(cfg.requirePermission("reviews:moderate")).Post("/spam", cfg.handlerTenantProductReviewModerate(reviews.StatusSpam))
+									})
+								})
+
+								// Price lists
+								r.Route("/price-lists", func(r chi.Router) {
+									r.With(cfg.requirePermission("products:view")).Get("/", cfg.handlerTenantPriceListsList)
+									r.With(cfg.requirePermission("products:edit")).Post("/", cfg.handlerTenantPriceListsCreate)
+
+									r.Route("/{priceListID}", func(r chi.Router) {
+										r.With(cfg.requirePermission("products:view")).Get("/", cfg.handlerTenantPriceListGet)
+										r.With(cfg.requirePermission("products:edit")).Put("/", cfg.handlerTenantPriceListUpdate)
+										r.With(cfg.requirePermission("products:edit")).Delete("/", cfg.handlerTenantPriceListDelete)
+										r.With(cfg.requirePermission("products:view")).Get("/entries", cfg.handlerTenantPriceListEntriesList)
+										r.With(cfg.requirePermission("products:edit")).Put("/entries/{variantID}", cfg.handlerTenantPriceListEntrySet)
+										r.With(cfg.requirePermission("products:edit")).Delete("/entries/{variantID}", cfg.handlerTenantPriceListEntryDelete)
+									})
+								})
+
+								// Sales channels products are published to
+								r.Route("/channels", func(r chi.Router) {
+									r.With(cfg.requirePermission("products:view")).Get("/", cfg.handlerTenantSalesChannelsList)
+									r.With(cfg.requirePermission("products:edit")).Post("/", cfg.handlerTenantSalesChannelsCreate)
+
+									r.Route("/{channelID}", func(r chi.Router) {
+										r.With(cfg.requirePermission("products:view")).Get("/", cfg.handlerTenantSalesChannelGet)
+										r.With(cfg.requirePermission("products:edit")).Put("/", cfg.handlerTenantSalesChannelUpdate)
+										r.With(cfg.requirePermission("products:edit")).Delete("/", cfg.handlerTenantSalesChannelDelete)
+									})
+								})
+
+								// Marketplace product feeds
+								r.Route("/feeds", func(r chi.Router) {
+									r.With(cfg.requirePermission("products:view")).Get("/", cfg.handlerTenantProductFeedsList)
+									r.With(cfg.requirePermission("products:edit")).Post("/", cfg.handlerTenantProductFeedsCreate)
+
+									r.Route("/{feedID}", func(r chi.Router) {
+										r.With(cfg.requirePermission("products:view")).Get("/", cfg.handlerTenantProductFeedGet)
+										r.With(cfg.requirePermission("products:edit")).Put("/", cfg.handlerTenantProductFeedUpdate)
+										r.With(cfg.requirePermission("products:edit")).Delete("/", cfg.handlerTenantProductFeedDelete)
+										r.With(cfg.requirePermission("products:edit")).Post("/refresh", cfg.handlerTenantProductFeedRefresh)
+										r.With(cfg.requirePermission("products:edit")).Post("/rotate-token", cfg.handlerTenantProductFeedRotateToken)
+									})
+								})
+
+								// Selling plans
+								r.Route("/selling-plans", func(r chi.Router) {
+									r.With(cfg.requirePermission("products:view")).Get("/", cfg.handlerTenantSellingPlansList)
+									r.With(cfg.requirePermission("products:edit")).Post("/", cfg.handlerTenantSellingPlansCreate)
+
+									r.Route("/{sellingPlanID}", func(r chi.Router) {
+										r.With(cfg.requirePermission("products:view")).Get("/", cfg.handlerTenantSellingPlanGet)
+										r.With(cfg.requirePermission("products:edit")).Put("/", cfg.handlerTenantSellingPlanUpdate)
+										r.With(cfg.requirePermission("products:edit")).Delete("/", cfg.handlerTenantSellingPlanDelete)
+									})
+								})
+
+								// Subscriptions
+								r.Route("/subscriptions", func(r chi.Router) {
+									r.With(cfg.requirePermission("orders:view")).Get("/", cfg.handlerTenantSubscriptionsList)
+
+									r.Route("/{subscriptionID}", func(r chi.Router) {
+										r.With(cfg.requirePermission("orders:view")).Get("/", cfg.handlerTenantSubscriptionGet)
+										r.With(cfg.requirePermission("orders:view")).Get("/orders", cfg.handlerTenantSubscriptionOrdersList)
+										r.With(cfg.requirePermission("orders:manage")).Post("/pause", cfg.handlerTenantSubscriptionPause)
+										r.With(cfg.requirePermission("orders:manage")).Post("/resume", cfg.handlerTenantSubscriptionResume)
+										r.With(cfg.requirePermission("orders:manage")).Post("/cancel", cfg.handlerTenantSubscriptionCancel)
+									})
+								})
+
+								// Collections
+								r.Route("/collections", func(r chi.Router) {
+									r.With(cfg.requirePermission("products:edit")).Post("/", cfg.handlerTenantCollectionsCreate)
+									r.With(cfg.requirePermission("products:view")).Get("/", cfg.handlerTenantCollectionsList)
+
+									r.Route("/{collectionID}", func(r chi.Router) {
+										r.With(cfg.requirePermission("products:view")).Get("/", cfg.handlerTenantCollectionGet)
+										r.With(cfg.requirePermission("products:edit")).Put("/", cfg.handlerTenantCollectionUpdate)
+										r.With(cfg.requirePermission("products:edit")).Delete("/", cfg.handlerTenantCollectionDelete)
+
+										r.Route("/products", func(r chi.Router) {
+											r.With(cfg.requirePermission("products:view")).Get("/", cfg.handlerTenantCollectionProductsList)
+											r.With(cfg.requirePermission("products:edit")).Post("/", cfg.handlerTenantCollectionProductsAdd)
+											r.With(cfg.requirePermission("products:edit")).Put("/order", cfg.handlerTenantCollectionProductsReorder)
+											r.With(cfg.requirePermission("products:edit")).Delete("/{productID}", cfg.handlerTenantCollectionProductRemove)
+										})
+									})
+								})
+
+								// Gift cards
+								r.Route("/gift-cards", func(r chi.Router) {
+									r.With(cfg.requirePermission("gift_cards:manage")).Post("/", cfg.handlerTenantGiftCardsCreate)
+									r.With(cfg.requirePermission("gift_cards:view")).Get("/", cfg.handlerTenantGiftCardsList)
+
+									r.Route("/{giftCardID}", func(r chi.Router) {
+										r.With(cfg.requirePermission("gift_cards:view")).Get("/", cfg.handlerTenantGiftCardGet)
+										r.With(cfg.requirePermission("gift_cards:manage")).Put("/", cfg.handlerTenantGiftCardUpdate)
+										r.With(cfg.requirePermission("gift_cards:view")).Get("/transactions", cfg.handlerTenantGiftCardTransactionsList)
+										r.With(cfg.requirePermission("gift_cards:manage")).Post("/adjustments", cfg.handlerTenantGiftCardAdjust)
+									})
+								})
+
+								// Reports, from the rollups the worker keeps nightly
+								r.Route("/analytics", func(r chi.Router) {
+									r.With(cfg.requirePermission("analytics:view")).Get("/sales", cfg.handlerTenantAnalyticsSales)
+									r.With(cfg.requirePermission("analytics:view")).Get("/products", cfg.handlerTenantAnalyticsProducts)
+									r.With(cfg.requirePermission("analytics:view")).Get("/channels", cfg.handlerTenantAnalyticsChannels)
+								})
+
+								// Orders
+								r.With(cfg.requirePermission("orders:view")).Get("/orders", cfg.handlerTenantOrdersList)
+								r.With(cfg.requirePermission("orders:view")).Get("/orders/tags", cfg.handlerTenantOrderTagsList)
+								r.With(cfg.requirePermission("orders:view")).Post("/orders/exports", cfg.handlerTenantExportCreate(export.ResourceOrders))
+								r.With(cfg.requirePermission("orders:view")).Get("/orders/exports/{exportID}", cfg.handlerTenantExportGet(export.ResourceOrders))
+								r.With(cfg.requirePermission("orders:manage")).Post("/orders/imports/shopify", cfg.handlerTenantShopifyImportCreate(shopify.EntityOrders))
+								r.With(cfg.requirePermission("orders:view")).Get("/orders/imports/shopify/{importID}", cfg.handlerTenantShopifyImportGet(shopify.EntityOrders))
+								r.Route("/orders/{orderID}", func(r chi.Router) {
+									r.With(cfg.requirePermission("orders:view")).Get("/", cfg.handlerTenantOrderGet)
+									r.With(cfg.requirePermission("orders:manage")).Put("/tags", cfg.handlerTenantOrderTagsUpdate)
+									r.With(cfg.requirePermission("orders:view")).Get("/events", cfg.handlerTenantOrderEventsList)
+									r.With(cfg.requirePermission("orders:manage")).Post("/notes", cfg.handlerTenantOrderNoteCreate)
+
+									r.Route("/fulfillments", func(r chi.Router) {
+										r.With(cfg.requirePermission("orders:manage")).Post("/", cfg.handlerTenantFulfillmentsCreate)
+										r.With(cfg.requirePermission("orders:view")).Get("/", cfg.handlerTenantFulfillmentsList)
+
+										r.Route("/{fulfillmentID}", func(r chi.Router) {
+											r.With(cfg.requirePermission("orders:view")).Get("/", cfg.handlerTenantFulfillmentGet)
+											r.With(cfg.requirePermission("orders:manage")).Put("/", cfg.handlerTenantFulfillmentUpdate)
+											r.With(cfg.requirePermission("orders:manage")).Post("/status", cfg.handlerTenantFulfillmentStatusUpdate)
+											r.With(cfg.requirePermission("orders:manage")).Post("/label", cfg.handlerTenantFulfillmentLabelCreate)
+										})
+									})
+
+									r.Route("/refunds", func(r chi.Router) {
+										r.With(cfg.requirePermission("orders:manage")).Post("/", cfg.handlerTenantRefundsCreate)
+										r.With(cfg.requirePermission("orders:view")).Get("/", cfg.handlerTenantRefundsList)
+									})
+								})
+
+								// Shipping
+								r.Route("/shipping-zones", func(r chi.Router) {
+									r.With(cfg.requirePermission("stores:edit")).Post("/", cfg.handlerTenantShippingZonesCreate)
+									r.With(cfg.requirePermission("stores:view")).Get("/", cfg.handlerTenantShippingZonesList)
+
+									r.Route("/{zoneID}", func(r chi.Router) {
+										r.With(cfg.requirePermission("stores:view")).Get("/", cfg.handlerTenantShippingZoneGet)
+										r.With(cfg.requirePermission("stores:edit")).Put("/", cfg.handlerTenantShippingZoneUpdate)
+										r.With(cfg.requirePermission("stores:edit")).Delete("/", cfg.handlerTenantShippingZoneDelete)
+
+										r.Route("/rates", func(r chi.Router) {
+											r.With(cfg.requirePermission("stores:edit")).Post("/", cfg.handlerTenantShippingRatesCreate)
+											r.With(cfg.requirePermission("stores:view")).Get("/", cfg.handlerTenantShippingRatesList)
+											r.With(cfg.requirePermission("stores:edit")).Put("/{rateID}", cfg.handlerTenantShippingRateUpdate)
+											r.With(cfg.requirePermission("stores:edit")).Delete("/{rateID}", cfg.handlerTenantShippingRateDelete)
+										})
+									})
+								})
+
+								// Storefront access tokens
+								r.Route("/storefront-tokens", func(r chi.Router) {
+									r.With(cfg.requirePermission("stores:edit")).Post("/", cfg.handlerTenantStorefrontTokensCreate)
+									r.With(cfg.requirePermission("stores:view")).Get("/", cfg.handlerTenantStorefrontTokensList)
+									r.With(cfg.requirePermission("stores:edit")).Post("/{tokenID}/rotate", cfg.handlerTenantStorefrontTokenRotate)
+									r.With(cfg.requirePermission("stores:edit")).Delete("/{tokenID}", cfg.handlerTenantStorefrontTokenRevoke)
+								})
+
+								// Search
+								r.With(cfg.requirePermission("products:edit")).Post("/search/reindex", cfg.handlerTenantSearchReindex)
+
+								// Barcode and SKU lookup for point of sale scanning
+								r.With(cfg.requirePermission("products:view")).Get("/lookup", cfg.handlerTenantVariantLookup)
+
+								// Inventory
+								r.Route("/inventory", func(r chi.Router) {
+									r.With(cfg.requirePermission("inventory:view")).Get("/", cfg.handlerTenantInventoryList)
+									r.With(cfg.requirePermission("inventory:view")).Get("/low-stock", cfg.handlerTenantInventoryLowStockReport)
+									r.With(cfg.requirePermission("inventory:view")).Get("/alerts", cfg.handlerTenantInventoryAlertsList)
+
+									// Stock counts
+									r.Route("/counts", func(r chi.Router) {
+										r.With(cfg.requirePermission("inventory:manage")).Post("/", cfg.handlerTenantInventoryCountCreate)
+										r.With(cfg.requirePermission("inventory:view")).Get("/", cfg.handlerTenantInventoryCountsList)
+
+										r.Route("/{countID}", func(r chi.Router) {
+											r.With(cfg.requirePermission("inventory:view")).Get("/", cfg.handlerTenantInventoryCountGet)
+											r.With(cfg.requirePermission("inventory:manage")).Put("/lines", cfg.handlerTenantInventoryCountLinesRecord)
+											r.With(cfg.requirePermission("inventory:manage")).Post("/lines/import", cfg.handlerTenantInventoryCountLinesImport)
+											r.With(cfg.requirePermission("inventory:manage")).Delete("/lines/{variantID}", cfg.handlerTenantInventoryCountLineDelete)
+											r.With(cfg.requirePermission("inventory:manage")).Post("/complete", cfg.handlerTenantInventoryCountComplete)
+											r.With(cfg.requirePermission("inventory:manage")).Post("/cancel", cfg.handlerTenantInventoryCountCancel)
+										})
+									})
+
+									r.Route("/{variantID}", func(r chi.Router) {
+										r.With(cfg.requirePermission("inventory:view")).Get("/", cfg.handlerTenantInventoryGet)
+										r.With(cfg.requirePermission("inventory:manage")).Post("/adjustments", cfg.handlerTenantInventoryAdjust)
+										r.With(cfg.requirePermission("inventory:manage")).Post("/transfers", cfg.handlerTenantInventoryTransfer)
+										r.With(cfg.requirePermission("inventory:manage")).Put("/reorder-point", cfg.handlerTenantInventoryReorderPointSet)
+										r.With(cfg.requirePermission("inventory:view")).Get("/movements", cfg.handlerTenantInventoryMovementsList)
+									})
+								})
+
+								// Inventory reservations
+								r.Route("/reservations", func(r chi.Router) {
+									r.With(cfg.requirePermission("inventory:view")).Get("/", cfg.handlerTenantReservationsList)
+
+									r.Route("/{reservationID}", func(r chi.Router) {
+										r.With(cfg.requirePermission("inventory:view")).Get("/", cfg.handlerTenantReservationGet)
+										r.With(cfg.requirePermission("inventory:manage")).Post("/commit", cfg.handlerTenantReservationCommit)
+										r.With(cfg.requirePermission("inventory:manage")).Post("/release", cfg.handlerTenantReservationRelease)
+									})
+								})
+
+								// Products
+								r.Route("/products", func(r chi.Router) {
+									r.With(cfg.requirePermission("products:create")).Post("/", cfg.handlerTenantProductCreate)
+									r.With(cfg.requirePermission("products:view")).Get("/", cfg.handlerTenantProductsList)
+									r.With(cfg.requirePermission("products:view")).Get("/facets", cfg.handlerTenantProductFacets)
+									r.With(cfg.requirePermission("products:view")).Get("/handle-availability", cfg.handlerTenantProductHandleAvailability)
+									// Deleted products stay restorable until the worker purges them
+									r.With(cfg.requirePermission("products:view")).Get("/deleted", cfg.handlerTenantProductsDeletedList)
+									r.With(cfg.requirePermission("products:create")).Post("/imports", cfg.handlerTenantProductImportCreate)
+									r.With(cfg.requirePermission("products:view")).Get("/imports/{importID}", cfg.handlerTenantProductImportGet)
+									r.With(cfg.requirePermission("products:create")).Post("/imports/shopify", cfg.handlerTenantShopifyImportCreate(shopify.EntityProducts))
+									r.With(cfg.requirePermission("products:view")).Get("/imports/shopify/{importID}", cfg.handlerTenantShopifyImportGet(shopify.EntityProducts))
+									r.With(cfg.requirePermission("products:view")).Post("/exports", cfg.handlerTenantExportCreate(export.ResourceProducts))
+									r.With(cfg.requirePermission("products:view")).Get("/exports/{exportID}", cfg.handlerTenantExportGet(export.ResourceProducts))
+
+									r.Route("/{productID}", func(r chi.Router) {
+										r.With(cfg.requirePermission("products:view")).Get("/", cfg.handlerTenantProductGet)
+										r.With(cfg.requirePermission("products:edit")).Put("/", cfg.handlerTenantProductUpdate)
+										r.With(cfg.requirePermission("products:delete")).Delete("/", cfg.handlerTenantProductDelete)
+										r.With(cfg.requirePermission("products:delete")).Post("/restore", cfg.handlerTenantProductRestore)
+
+										// Status lifecycle: draft, active, archived
+										r.With(cfg.requirePermission("products:edit")).Post("/publish", cfg.handlerTenantProductSetStatus(products.StatusActive))
+										r.With(cfg.requirePermission("products:edit")).Post("/unpublish", cfg.handlerTenantProductSetStatus(products.StatusDraft))
+										r.With(cfg.requirePermission("products:edit")).Post("/archive", cfg.handlerTenantProductSetStatus(products.StatusArchived))
+
+										// Media (images)
+										r.Route("/media", func(r chi.Router) {
+											r.With(cfg.requirePermission("products:view")).Get("/", cfg.handlerTenantProductMediaList)
+											r.With(cfg.requirePermission("products:edit")).Post("/uploads", cfg.handlerTenantProductMediaCreateUpload)
+											r.With(cfg.requirePermission("products:edit")).Put("/order", cfg.handlerTenantProductMediaReorder)
+
+											r.Route("/{mediaID}", func(r chi.Router) {
+												r.With(cfg.requirePermission("products:edit")).Put("/", cfg.handlerTenantProductMediaUpdate)
+												r.With(cfg.requirePermission("products:edit")).Delete("/", cfg.handlerTenantProductMediaDelete)
+												r.With(cfg.requirePermission("products:edit")).Post("/complete", cfg.handlerTenantProductMediaComplete)
+											})
+										})
+
+										// Sales channels the product is sold on
+										r.With(cfg.requirePermission("products:view")).Get("/publications", cfg.handlerTenantProductPublicationsList)
+										r.With(cfg.requirePermission("products:edit")).Put("/publications", cfg.handlerTenantProductPublicationsSet)
+
+										// Content in other locales
+										r.With(cfg.requirePermission("products:view")).Get("/translations", cfg.handlerTenantProductTranslationsList)
+										r.With(cfg.requirePermission("products:edit")).Put("/translations/{locale}", cfg.handlerTenantProductTranslationSet)
+										r.With(cfg.requirePermission("products:edit")).Delete("/translations/{locale}", cfg.handlerTenantProductTranslationDelete)
+
+										// Options (Size, Color, ...) and the variants they imply
+										r.With(cfg.requirePermission("products:view")).Get("/options", cfg.handlerTenantProductOptionsGet)
+										r.With(cfg.requirePermission("products:edit")).Put("/options", cfg.handlerTenantProductOptionsPut)
+
+										// Variants
+										r.Route("/variants", func(r chi.Router) {
+											r.With(cfg.requirePermission("products:create")).Post("/", cfg.handlerTenantVariantCreate)
+											r.With(cfg.requirePermission("products:create")).Post("/generate", cfg.handlerTenantVariantsGenerate)
+											r.With(cfg.requirePermission("products:view")).Get("/deleted", cfg.handlerTenantVariantsDeletedList)
+
+											r.Route("/{variantID}", func(r chi.Router) {
+												r.With(cfg.requirePermission("products:edit")).Put("/", cfg.handlerTenantVariantUpdate)
+												r.With(cfg.requirePermission("products:delete")).Delete("/", cfg.handlerTenantVariantDelete)
+												r.With(cfg.requirePermission("products:delete")).Post("/restore", cfg.handlerTenantVariantRestore)
+
+												// Prices in presentment currencies
+												r.With(cfg.requirePermission("products:view")).Get("/prices", cfg.handlerTenantVariantPricesList)
+												r.With(cfg.requirePermission("products:edit")).Put("/prices/{currency}", cfg.handlerTenantVariantPriceSet)
+												r.With(cfg.requirePermission("products:edit")).Delete("/prices/{currency}", cfg.handlerTenantVariantPriceDelete)
+
+												// Bundle components
+												r.With(cfg.requirePermission("products:view")).Get("/components", cfg.handlerTenantBundleComponentsGet)
+												r.With(cfg.requirePermission("products:edit")).Put("/components", cfg.handlerTenantBundleComponentsPut)
+											})
+										})
+									})
+								})
+							})
+						})
+
+						// Inventory locations (warehouses, retail stores)
+						r.Route("/locations", func(r chi.Router) {
+							r.With(cfg.requirePermission("inventory:manage")).Post("/", cfg.handlerTenantLocationsCreate)
+							r.With(cfg.requirePermission("inventory:view")).Get("/", cfg.handlerTenantLocationsList)
+
+							r.Route("/{locationID}", func(r chi.Router) {
+								r.With(cfg.requirePermission("inventory:manage")).Put("/", cfg.handlerTenantLocationsUpdate)
+								r.With(cfg.requirePermission("inventory:manage")).Delete("/", cfg.handlerTenantLocationsDelete)
+							})
+						})
+
+						r.With(cfg.requirePermission("tenant:manage")).Put("/mfa-policy", cfg.handlerTenantMFAPolicyUpdate)
+						r.With(cfg.requirePermission("tenant:manage")).Put("/sso-policy", cfg.handlerTenantSSOPolicyUpdate)
+
+						// The tenant's own identity provider, and the email domains
+						// it signs in
+						r.Route("/sso", func(r chi.Router) {
+							r.With(cfg.requirePermission("tenant:manage")).Get("/", cfg.handlerTenantSSOGet)
+							r.With(cfg.requirePermission("tenant:manage")).Put("/", cfg.handlerTenantSSOUpdate)
+							r.With(cfg.requirePermission("tenant:manage")).Delete("/", cfg.handlerTenantSSODelete)
+							r.With(cfg.requirePermission("tenant:manage")).Get("/domains", cfg.handlerTenantSSODomainsList)
+							r.With(cfg.requirePermission("tenant:manage")).Post("/domains", cfg.handlerTenantSSODomainCreate)
+							r.With(cfg.requirePermission("tenant:manage")).Post("/domains/{domainID}/verify", cfg.handlerTenantSSODomainVerify)
+							r.With(cfg.requirePermission("tenant:manage")).Delete("/domains/{domainID}", cfg.handlerTenantSSODomainDelete)
+						})
+
+						// Batches of changes across the tenant's stores, applied by the
+						// worker; each mutation is checked against its own permission
+						r.Route("/bulk", func(r chi.Router) {
+							r.Post("/", cfg.handlerTenantBulkCreate)
+							r.Get("/{operationID}", cfg.handlerTenantBulkGet)
+						})
+
+						r.With(cfg.requirePermission("audit_log:view")).Get("/audit-log", cfg.handlerTenantAuditLogList)
+
+						// API keys for machine access
+						r.Route("/api-keys", func(r chi.Router) {
+							r.With(cfg.requirePermission("tenant:manage")).Post("/", cfg.handlerTenantAPIKeysCreate)
+							r.With(cfg.requirePermission("tenant:manage")).Get("/", cfg.handlerTenantAPIKeysList)
+							r.With(cfg.requirePermission("tenant:manage")).Delete("/{keyID}", cfg.handlerTenantAPIKeyRevoke)
+						})
+
+						// What the caller may do here, for hiding controls in a UI
+						r.Get("/me", cfg.handlerTenantMe)
+						// The tenant's plan, its limits and how much of them is used
+						r.With(cfg.requirePermission("tenant:view")).Get("/entitlements", cfg.handlerTenantEntitlements)
+
+						// The tenant's subscription. Only owners spend its money.
+						r.Route("/billing", func(r chi.Router) {
+							r.With(cfg.requirePermission("tenant:view")).Get("/", cfg.handlerTenantBillingGet)
+							r.With(cfg.requirePermission("tenant:owner")).Post("/checkout", cfg.handlerTenantBillingCheckout)
+							r.With(cfg.requirePermission("tenant:owner")).Post("/portal", cfg.handlerTenantBillingPortal)
+						})
+						// What the tenant used in a month against its plan's allowance
+						r.With(cfg.requirePermission("tenant:view")).Get("/usage", cfg.handlerTenantUsage)
+
+						// Staff notifications, each kind shown to members with its permission
+						r.Route("/notifications", func(r chi.Router) {
+							r.Get("/", cfg.handlerTenantNotificationsList)
+							r.Get("/unread-count", cfg.handlerTenantNotificationsUnreadCount)
+							r.Post("/read-all", cfg.handlerTenantNotificationsReadAll)
+							r.With(cfg.requirePermission("tenant:view")).Get("/preferences", cfg.handlerTenantNotificationPreferencesGet)
+							r.With(cfg.requirePermission("tenant:manage")).Put("/preferences", cfg.handlerTenantNotificationPreferencesUpdate)
+							r.Post("/{notificationID}/read", cfg.handlerTenantNotificationRead)
+							r.Delete("/{notificationID}/read", cfg.handlerTenantNotificationUnread)
+						})
+						// Membership checks rather than permissions, so not for API keys
+						r.With(cfg.requireUserToken).Post("/leave", cfg.handlerTenantLeave)
+
+						// Members management
+						r.Route("/members", func(r chi.Router) {
+							r.Get("/", cfg.handlerTenantMembersList)
+							r.With(cfg.requirePermission("tenant:invite_users")).Post("/invite", cfg.handlerTenantMembersInvite)
+							r.With(cfg.requirePermission("tenant:remove_users")).Delete("/{memberID}", cfg.handlerTenantMemberRemove)
+							r.With(cfg.requirePermission("tenant:manage_users")).Post("/{memberID}/suspend", cfg.handlerTenantMemberSuspend)
+							r.With(cfg.requirePermission("tenant:manage_users")).Post("/{memberID}/reactivate", cfg.handlerTenantMemberReactivate)
+
+							r.Route("/{memberID}/roles", func(r chi.Router) {
+								r.With(cfg.requirePermission("tenant:manage_users")).Post("/", cfg.handlerTenantMemberAssignRole)
+								r.With(cfg.requirePermission("tenant:manage_users")).Delete("/{roleID}", cfg.handlerTenantMemberRemoveRole)
+							})
+						})
+
+						// Roles management
+						r.Route("/roles", func(r chi.Router) {
+							r.With(cfg.requirePermission("tenant:manage_users")).Post("/", cfg.handlerTenantRolesCreate)
+							r.Get("/", cfg.handlerTenantRolesList)
+							r.With(cfg.requirePermission("tenant:manage_users")).Post("/{roleID}/clone", cfg.handlerTenantRoleClone)
+
+							r.Route("/{roleID}/permissions", func(r chi.Router) {
+								r.With(cfg.requirePermission("tenant:manage_users")).Post("/", cfg.handlerTenantRoleAddPermission)
+								r.With(cfg.requirePermission("tenant:manage_users")).Delete("/{permissionKey}", cfg.handlerTenantRoleRemovePermission)
+							})
+						})
+					})
+				})
+
+				// The signed-in user's own account
+				r.With(cfg.requireUserToken).Get("/me", cfg.handlerMeGet)
+				r.With(cfg.requireUserToken).Patch("/me", cfg.handlerMeUpdate)
+				r.With(cfg.requireUserToken).Put("/me/avatar", cfg.handlerMeAvatarUpload)
+				r.With(cfg.requireUserToken).Delete("/me/avatar", cfg.handlerMeAvatarDelete)
+
+				// Sessions of the signed-in user
+				r.Route("/me/sessions", func(r chi.Router) {
+					r.Use(cfg.requireUserToken)
+					r.Get("/", cfg.handlerSessionsList)
+					r.Delete("/", cfg.handlerSessionsRevokeAll)
+					r.Delete("/{sessionID}", cfg.handlerSessionRevoke)
+				})
+				r.With(cfg.requireUserToken).Post("/me/revoke-all", cfg.handlerSessionsRevokeAll)
+
+				// Data export and account erasure for the signed-in user
+				r.Route("/me/privacy-requests", func(r chi.Router) {
+					r.Use(cfg.requireUserToken)
+					r.Post("/", cfg.handlerPrivacyRequestCreate)
+					r.Get("/{requestID}", cfg.handlerPrivacyRequestGet)
+				})
+
+				// Google, GitHub and OIDC accounts linked to the signed-in user
+				r.With(cfg.requireUserToken).Get("/me/identities", cfg.handlerUserIdentitiesList)
+
+				// Two-factor authentication for the signed-in user
+				r.Route("/me/mfa", func(r chi.Router) {
+					r.Use(cfg.requireUserToken)
+					r.Get("/", cfg.handlerMFAStatus)
+					r.Post("/enroll", cfg.handlerMFAEnroll)
+					r.Post("/confirm", cfg.handlerMFAConfirm)
+					r.Post("/recovery-codes", cfg.handlerMFARecoveryCodesRegenerate)
+					r.Post("/disable", cfg.handlerMFADisable)
+				})
+
+				// Any record by its GID, checked against the tenant that owns it
+				r.Get("/nodes/{gid}", cfg.handlerNodeGet)
+
+				// Global permissions list (available to all authenticated users)
+				r.Get("/permissions", cfg.handlerPermissionsList)
+
+				// Add more protected routes here and they all get auth automatically.
+			})
+		})
+
+		r.With(cfg.requireAuth, cfg.rateLimitCaller, cfg.requireUserToken).Post("/email/verify/resend", cfg.handlerEmailVerifyResend)
+		r.With(cfg.requireAuth, cfg.rateLimitCaller, cfg.requireUserToken).Post("/invitations/accept", cfg.handlerInvitationAccept)
+
+		// Routes that take no credentials are limited by IP whatever
+		// Authorization header they carry
+		r.Group(func(r chi.Router) {
+			r.Use(cfg.rateLimitPublic)
+
+			r.Post("/login", cfg.handlerLoginUsers)
+			r.Post("/login/mfa", cfg.handlerLoginMFA)
+			r.Get("/login/oauth", cfg.handlerOAuthProviders)
+			r.Post("/login/oauth/callback", cfg.handlerOAuthCallback)
+			r.Post("/login/oauth/{provider}", cfg.handlerOAuthStart)
+			r.Post("/login/sso", cfg.handlerSSOStart)
+			r.Post("/login/magic-link", cfg.handlerMagicLinkRequest)
+			r.Post("/login/magic-link/consume", cfg.handlerMagicLinkConsume)
+			r.Post("/refresh", cfg.handlerRefresh)
+			r.Post("/revoke", cfg.handlerRevoke)
+			r.Post("/logout", cfg.handlerLogout)
+			r.Post("/password/forgot", cfg.handlerPasswordForgot)
+			r.Post("/password/reset", cfg.handlerPasswordReset)
+			r.Post("/email/verify", cfg.handlerEmailVerify)
+			r.Get("/invitations", cfg.handlerInvitationPreview)
+			r.Post("/invitations/signup", cfg.handlerInvitationSignup)
+
+			// Marketplaces fetch product feeds with the token as their only
+			// credential
+			r.Get("/feeds/{token}", cfg.handlerProductFeedDownload)
+
+			// Label providers sign their tracking webhooks rather than
+			// authenticating
+			r.Post("/webhooks/shipping/{provider}", cfg.handlerShippingTrackingWebhook)
+			// Stripe signs its subscription webhooks the same way
+			r.Post("/webhooks/billing/stripe", cfg.handlerBillingWebhook)
+
+			// Tenants' SAML identity providers read the service provider
+			// metadata and post signed responses through the user's browser
+			r.Get("/sso/saml/{connectionID}/metadata", cfg.handlerSAMLMetadata)
+			r.Post("/sso/saml/{connectionID}/acs", cfg.handlerSAMLACS)
+		})
+	}
+
+	return routeGroups{
+		shared:     sharedRoutes,
+		root:       rootRoutes,
+		admin:      adminRoutes,
+		storefront: storefrontRoutes,
+		api:        apiRoutes,
+	}
+}
+
+// hostHandler serves the routes of the host each request is for, with the
+// store of a storefront host resolved first
+func (cfg *apiConfig) hostHandler() http.Handler {
+	return chi.Chain(
+		mw.Subdomain(mw.SubdomainConfig{
+			BaseDomain:     cfg.config.BaseDomain,
+			APISubdomain:   "api",
+			AdminSubdomain: "admin",
+		}),
+		// Limits anonymous requests by IP before resolving the store costs a
+		// query. Authenticated ones are limited once their credentials are
+		// checked, by API key, user and tenant.
+		cfg.rateLimitAnonymous,
+		mw.StoreResolver(mw.StoreResolverConfig{DB: cfg.db}),
+	).Handler(cfg.routes().handler())
+}
+
+// handler serves each host the routes of its kind
+func (g routeGroups) handler() http.Handler {
+	return mw.HostRouter(map[mw.DomainType]http.Handler{
+		mw.DomainTypeAPI:    hostRouter(g.shared, g.api),
+		mw.DomainTypeAdmin:  hostRouter(g.shared, g.admin),
+		mw.DomainTypeStore:  hostRouter(g.shared, g.storefront),
+		mw.DomainTypeCustom: hostRouter(g.shared, g.storefront),
+		mw.DomainTypeRoot:   hostRouter(g.shared, g.root),
+		// Development on localhost gets everything
+		mw.DomainTypeLocal: hostRouter(g.shared, g.root, g.admin, g.storefront, g.api),
+	})
+}
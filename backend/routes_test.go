@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dfodeker/terminus/graph"
+	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/config"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/dbpool"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/jobs"
+	"github.com/dfodeker/terminus/internal/lockout"
+	"github.com/dfodeker/terminus/internal/payments"
+	"github.com/dfodeker/terminus/internal/ratelimit"
+	"github.com/dfodeker/terminus/internal/storage"
+	"github.com/dfodeker/terminus/internal/usage"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// errNoDatabase is what every query of the route tests fails with
+var errNoDatabase = errors.New("no database in route tests")
+
+// newRouteTestConfig is an apiConfig as main builds it, over a database
+// that fails every query
+func newRouteTestConfig(t *testing.T) *apiConfig {
+	t.Helper()
+	return newTestConfig(t, func(context.Context, string, string) (net.Conn, error) {
+		return nil, errNoDatabase
+	})
+}
+
+// newTestConfig is an apiConfig as main builds it, over the database dial
+// connects to
+func newTestConfig(t *testing.T, dial pgconn.DialFunc) *apiConfig {
+	t.Helper()
+	vars := map[string]string{
+		"PLATFORM":       "dev",
+		"DB_URL":         "postgres://localhost/terminus",
+		"SIGNING_KEY":    "secret",
+		"ENCRYPTION_KEY": base64.StdEncoding.EncodeToString([]byte(strings.Repeat("e", 32))),
+
+		"STORAGE_LOCAL_DIR":  t.TempDir(),
+		"STORAGE_PUBLIC_URL": "https://media.example.com",
+	}
+	cfg, err := config.LoadAPI(func(key string) (string, bool) {
+		v, ok := vars[key]
+		return v, ok
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwtKeys, err := auth.LoadKeySet(cfg.JWTKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	poolConfig, err := pgxpool.ParseConfig(cfg.DatabaseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	poolConfig.ConnConfig.DialFunc = dial
+	db, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(db.Close)
+	queries := database.New(dbpool.NewDB(db))
+	readiness, err := newReadinessChecker(cfg, db, queries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gidGen, err := gid.NewGenerator(cfg.MachineID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mediaStorage, err := storage.New(cfg.Storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiCfg := &apiConfig{
+		config:   cfg,
+		db:       queries,
+		reads:    queries,
+		pool:     db,
+		jwtKeys:  jwtKeys,
+		gidGen:   gidGen,
+		jobs:     jobs.NewClient(queries),
+		storage:  mediaStorage,
+		services: newServices(db, queries, gidGen, cfg.RateLimit.Plans),
+		payments: payments.Default(),
+		health:   readiness,
+		limiter:  ratelimit.New(ratelimit.NewMemoryStore()),
+		quotas:   ratelimit.NewQuotaCache(planQuotaTTL),
+		meter:    usage.NewMeter(queries, usage.MeterConfig{}),
+		lockout:  lockout.New(queries),
+	}
+	apiCfg.graphQL = graph.NewHandler(apiCfg.graphConfig())
+	return apiCfg
+}
+
+// routeParam matches the URL parameters of a chi route pattern
+var routeParam = regexp.MustCompile(`\{[^}]+\}`)
+
+// TestRoutesServe sends one signed-in request to every route of every host
+// and fails on any that panics, such as a route checking permissions
+// without the tenant membership the check reads
+func TestRoutesServe(t *testing.T) {
+	cfg := newRouteTestConfig(t)
+	token, err := auth.MakeJWT(uuid.New(), cfg.jwtKeys, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := cfg.routes()
+	hosts := map[string]chi.Router{
+		"api":        hostRouter(g.shared, g.api),
+		"admin":      hostRouter(g.shared, g.admin),
+		"storefront": hostRouter(g.shared, g.storefront),
+		"root":       hostRouter(g.shared, g.root),
+	}
+	for host, router := range hosts {
+		err := chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+			// The profiler records for seconds before it answers
+			if strings.HasPrefix(route, "/debug/") {
+				return nil
+			}
+			path := routeParam.ReplaceAllString(route, uuid.NewString())
+			path = strings.TrimSuffix(strings.ReplaceAll(path, "/*", "/x"), "/")
+			if path == "" {
+				path = "/"
+			}
+			name := fmt.Sprintf("%s %s %s", host, method, route)
+			t.Run(name, func(t *testing.T) {
+				req := httptest.NewRequest(method, path, strings.NewReader("{}"))
+				req.Header.Set("Authorization", "Bearer "+token)
+				req.Header.Set("Content-Type", "application/json")
+				defer func() {
+					if p := recover(); p != nil {
+						t.Errorf("%s panicked: %v", name, p)
+					}
+				}()
+				router.ServeHTTP(httptest.NewRecorder(), req)
+			})
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
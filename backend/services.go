@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/gid"
+	"github.com/dfodeker/terminus/internal/problem"
+	"github.com/dfodeker/terminus/internal/ratelimit"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/dfodeker/terminus/internal/service/entitlements"
+	"github.com/dfodeker/terminus/internal/service/nodes"
+	"github.com/dfodeker/terminus/internal/service/products"
+	"github.com/dfodeker/terminus/internal/service/roles"
+	"github.com/dfodeker/terminus/internal/service/stores"
+	"github.com/dfodeker/terminus/internal/service/tenants"
+	"github.com/dfodeker/terminus/internal/validate"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// services is the domain logic the handlers share with other callers
+type services struct {
+	products *products.Service
+	stores   *stores.Service
+	tenants  *tenants.Service
+	roles    *roles.Service
+	nodes    *nodes.Service
+	// entitlements are checked before creating what a plan limits
+	entitlements *entitlements.Service
+}
+
+func newServices(db *pgxpool.Pool, q *database.Queries, gids *gid.Generator, rates ratelimit.Plans) services {
+	return services{
+		products:     products.New(q, gids),
+		stores:       stores.New(q, gids),
+		tenants:      tenants.New(q, service.SQLTx[tenants.Queries](db, q), gids),
+		roles:        roles.New(q, service.SQLTx[roles.Queries](db, q), gids),
+		nodes:        nodes.New(q),
+		entitlements: entitlements.New(q, entitlements.NewCatalog(rates)),
+	}
+}
+
+// respondWithServiceError maps an error from a service to a response.
+// Validation failures are a 422 listing the fields at fault. Other errors
+// the caller can act on carry their own message and, sometimes, a problem
+// code; anything else is a 500 with fallback.
+func respondWithServiceError(w http.ResponseWriter, err error, fallback string) {
+	if errors.As(err, new(*validate.Error)) {
+		respondWithValidationError(w, err)
+		return
+	}
+	var svcErr *service.Error
+	if !errors.As(err, &svcErr) {
+		respondWithError(w, http.StatusInternalServerError, fallback, err)
+		return
+	}
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, service.ErrConflict):
+		status = http.StatusConflict
+	case errors.Is(err, service.ErrForbidden):
+		status = http.StatusForbidden
+	}
+	p := problem.New(status, svcErr.Code, svcErr.Message)
+	p.Suggestion = svcErr.Suggestion
+	respondWithProblem(w, p, nil)
+}
+
+// pageRequest builds a service page request from a decoded list cursor
+func pageRequest(limit int, createdAt time.Time, id uuid.UUID, hasCursor bool) service.PageRequest {
+	page := service.PageRequest{Limit: limit}
+	if hasCursor {
+		page.After = &service.Cursor{CreatedAt: createdAt, ID: id}
+	}
+	return page
+}
@@ -0,0 +1,13 @@
+-- name: GetACMECacheEntry :one
+SELECT data FROM acme_cache
+WHERE key = $1;
+
+-- name: PutACMECacheEntry :exec
+INSERT INTO acme_cache (key, data, updated_at)
+VALUES ($1, $2, now())
+ON CONFLICT (key) DO UPDATE
+SET data = EXCLUDED.data, updated_at = now();
+
+-- name: DeleteACMECacheEntry :exec
+DELETE FROM acme_cache
+WHERE key = $1;
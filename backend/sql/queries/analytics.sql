@@ -0,0 +1,143 @@
+-- name: EnsureAnalyticsRollups :exec
+-- Starts tracking stores created since the last run
+INSERT INTO analytics_rollups (store_id)
+SELECT id FROM stores
+WHERE deleted_at IS NULL
+ON CONFLICT DO NOTHING;
+
+-- name: ClaimAnalyticsRollup :one
+-- Takes a store with a finished day not rolled up yet, if no worker took
+-- it since retry_before, and marks it taken up front so no other worker
+-- takes it too
+UPDATE analytics_rollups
+SET rolled_at = sqlc.arg(now)::timestamptz
+WHERE analytics_rollups.store_id = (
+    SELECT ar.store_id FROM analytics_rollups ar
+    JOIN stores s ON s.id = ar.store_id
+    WHERE s.deleted_at IS NULL
+      AND (ar.rolled_through IS NULL OR ar.rolled_through < (sqlc.arg(now)::timestamptz AT TIME ZONE s.timezone)::date - 1)
+      AND (ar.rolled_at IS NULL OR ar.rolled_at < sqlc.arg(retry_before)::timestamptz)
+    ORDER BY ar.rolled_through ASC NULLS FIRST
+    LIMIT 1
+    FOR UPDATE OF ar SKIP LOCKED
+)
+RETURNING *;
+
+-- name: SetAnalyticsRolledThrough :exec
+UPDATE analytics_rollups
+SET rolled_through = sqlc.arg(rolled_through)::date
+WHERE store_id = sqlc.arg(store_id);
+
+-- name: GetAnalyticsRollup :one
+SELECT * FROM analytics_rollups
+WHERE store_id = $1;
+
+-- name: GetFirstOrderPlacedAt :one
+-- When the first order of a store was placed, or now without orders
+SELECT COALESCE(MIN(placed_at), now())::timestamptz FROM orders
+WHERE store_id = $1;
+
+-- name: DeleteAnalyticsDailySales :exec
+DELETE FROM analytics_daily_sales
+WHERE store_id = sqlc.arg(store_id) AND day BETWEEN sqlc.arg(from_day)::date AND sqlc.arg(to_day)::date;
+
+-- name: DeleteAnalyticsDailyProducts :exec
+DELETE FROM analytics_daily_products
+WHERE store_id = sqlc.arg(store_id) AND day BETWEEN sqlc.arg(from_day)::date AND sqlc.arg(to_day)::date;
+
+-- name: DeleteAnalyticsDailyChannels :exec
+DELETE FROM analytics_daily_channels
+WHERE store_id = sqlc.arg(store_id) AND day BETWEEN sqlc.arg(from_day)::date AND sqlc.arg(to_day)::date;
+
+-- name: RollupAnalyticsDailySales :exec
+-- Orders count on the day they were placed. Cancelled and voided orders
+-- are left out, and bundle components are counted as their bundle.
+INSERT INTO analytics_daily_sales (store_id, day, currency, orders, units, subtotal_cents, discount_cents, shipping_cents, tax_cents, total_cents)
+SELECT o.store_id, (o.placed_at AT TIME ZONE sqlc.arg(timezone)::text)::date, o.currency,
+    COUNT(*), COALESCE(SUM(li.units), 0)::bigint,
+    SUM(o.subtotal_cents)::bigint, SUM(o.discount_cents)::bigint, SUM(o.shipping_cents)::bigint,
+    SUM(o.tax_cents)::bigint, SUM(o.total_cents)::bigint
+FROM orders o
+LEFT JOIN (
+    SELECT l.order_id, SUM(l.quantity) AS units FROM order_line_items l
+    JOIN orders lo ON lo.id = l.order_id
+    WHERE lo.store_id = sqlc.arg(store_id) AND lo.placed_at >= sqlc.arg(start_at)::timestamptz AND lo.placed_at < sqlc.arg(end_at)::timestamptz
+      AND l.parent_line_item_id IS NULL
+    GROUP BY l.order_id
+) li ON li.order_id = o.id
+WHERE o.store_id = sqlc.arg(store_id) AND o.placed_at >= sqlc.arg(start_at)::timestamptz AND o.placed_at < sqlc.arg(end_at)::timestamptz
+  AND o.status <> 'cancelled' AND o.financial_status <> 'voided'
+GROUP BY o.store_id, 2, o.currency;
+
+-- name: RollupAnalyticsDailyRefunds :exec
+-- Refunds count on the day they were made
+INSERT INTO analytics_daily_sales (store_id, day, currency, refunded_cents)
+SELECT rf.store_id, (rf.created_at AT TIME ZONE sqlc.arg(timezone)::text)::date, rf.currency, SUM(rf.amount_cents)::bigint
+FROM refunds rf
+WHERE rf.store_id = sqlc.arg(store_id) AND rf.created_at >= sqlc.arg(start_at)::timestamptz AND rf.created_at < sqlc.arg(end_at)::timestamptz
+GROUP BY rf.store_id, 2, rf.currency
+ON CONFLICT (store_id, day, currency) DO UPDATE SET refunded_cents = EXCLUDED.refunded_cents;
+
+-- name: RollupAnalyticsDailyProducts :exec
+INSERT INTO analytics_daily_products (store_id, day, currency, product_id, units, orders, revenue_cents)
+SELECT o.store_id, (o.placed_at AT TIME ZONE sqlc.arg(timezone)::text)::date, o.currency, li.product_id,
+    SUM(li.quantity)::bigint, COUNT(DISTINCT o.id), SUM(li.total_cents)::bigint
+FROM order_line_items li
+JOIN orders o ON o.id = li.order_id
+WHERE o.store_id = sqlc.arg(store_id) AND o.placed_at >= sqlc.arg(start_at)::timestamptz AND o.placed_at < sqlc.arg(end_at)::timestamptz
+  AND o.status <> 'cancelled' AND o.financial_status <> 'voided'
+  AND li.product_id IS NOT NULL AND li.parent_line_item_id IS NULL
+GROUP BY o.store_id, 2, o.currency, li.product_id;
+
+-- name: RollupAnalyticsDailyCheckouts :exec
+INSERT INTO analytics_daily_channels (store_id, day, channel_id, checkouts, converted)
+SELECT r.store_id, (r.created_at AT TIME ZONE sqlc.arg(timezone)::text)::date, r.channel_id,
+    COUNT(*), COUNT(*) FILTER (WHERE r.status = 'committed')
+FROM inventory_reservations r
+WHERE r.store_id = sqlc.arg(store_id) AND r.created_at >= sqlc.arg(start_at)::timestamptz AND r.created_at < sqlc.arg(end_at)::timestamptz
+  AND r.channel_id IS NOT NULL
+GROUP BY r.store_id, 2, r.channel_id;
+
+-- name: RollupAnalyticsDailyChannelOrders :exec
+INSERT INTO analytics_daily_channels (store_id, day, channel_id, orders)
+SELECT o.store_id, (o.placed_at AT TIME ZONE sqlc.arg(timezone)::text)::date, o.channel_id, COUNT(*)
+FROM orders o
+WHERE o.store_id = sqlc.arg(store_id) AND o.placed_at >= sqlc.arg(start_at)::timestamptz AND o.placed_at < sqlc.arg(end_at)::timestamptz
+  AND o.status <> 'cancelled' AND o.financial_status <> 'voided'
+  AND o.channel_id IS NOT NULL
+GROUP BY o.store_id, 2, o.channel_id
+ON CONFLICT (store_id, day, channel_id) DO UPDATE SET orders = EXCLUDED.orders;
+
+-- name: GetAnalyticsDailySales :many
+SELECT * FROM analytics_daily_sales
+WHERE store_id = sqlc.arg(store_id) AND currency = sqlc.arg(currency)
+  AND day BETWEEN sqlc.arg(from_day)::date AND sqlc.arg(to_day)::date
+ORDER BY day;
+
+-- name: GetAnalyticsSalesCurrencies :many
+-- The currencies a store sold in over a range, most orders first
+SELECT currency FROM analytics_daily_sales
+WHERE store_id = sqlc.arg(store_id) AND day BETWEEN sqlc.arg(from_day)::date AND sqlc.arg(to_day)::date
+GROUP BY currency
+ORDER BY SUM(orders) DESC, currency;
+
+-- name: GetAnalyticsTopProducts :many
+-- Best sellers over a range by revenue, or by units when by_units
+SELECT p.id AS product_id, p.name AS product_name,
+    SUM(ap.units)::bigint AS units, SUM(ap.orders)::bigint AS orders, SUM(ap.revenue_cents)::bigint AS revenue_cents
+FROM analytics_daily_products ap
+JOIN products p ON p.id = ap.product_id
+WHERE ap.store_id = sqlc.arg(store_id) AND ap.currency = sqlc.arg(currency)
+  AND ap.day BETWEEN sqlc.arg(from_day)::date AND sqlc.arg(to_day)::date
+GROUP BY p.id, p.name
+ORDER BY CASE WHEN sqlc.arg(by_units)::boolean THEN SUM(ap.units) ELSE SUM(ap.revenue_cents) END DESC, p.name, p.id
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetAnalyticsDailyChannels :many
+SELECT ac.day, ac.channel_id, sc.name AS channel_name, sc.kind AS channel_kind,
+    ac.checkouts, ac.converted, ac.orders
+FROM analytics_daily_channels ac
+JOIN sales_channels sc ON sc.id = ac.channel_id
+WHERE ac.store_id = sqlc.arg(store_id)
+  AND ac.day BETWEEN sqlc.arg(from_day)::date AND sqlc.arg(to_day)::date
+ORDER BY sc.kind, sc.name, ac.channel_id, ac.day;
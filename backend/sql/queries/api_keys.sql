@@ -0,0 +1,28 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (id, gid, tenant_id, name, prefix, key_hash, scopes, created_by, expires_at, created_at)
+VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8, now())
+RETURNING *;
+
+-- name: GetActiveAPIKeyByHash :one
+SELECT * FROM api_keys
+WHERE key_hash = $1
+  AND revoked_at IS NULL
+  AND (expires_at IS NULL OR expires_at > now());
+
+-- name: TouchAPIKey :exec
+-- Throttled to one write a minute per key
+UPDATE api_keys
+SET last_used_at = now()
+WHERE id = $1
+  AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute');
+
+-- name: ListTenantAPIKeys :many
+SELECT * FROM api_keys
+WHERE tenant_id = $1 AND revoked_at IS NULL
+ORDER BY created_at DESC, id DESC;
+
+-- name: RevokeAPIKey :one
+UPDATE api_keys
+SET revoked_at = now()
+WHERE id = $1 AND tenant_id = $2 AND revoked_at IS NULL
+RETURNING *;
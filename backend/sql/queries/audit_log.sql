@@ -0,0 +1,33 @@
+-- name: CreateAuditLogEntry :exec
+INSERT INTO audit_log (
+    tenant_id, store_id, actor_user_id, actor_api_key_id, action, entity_type, entity_id, entity_gid,
+    method, route, path, status_code, request_id, ip, before, after, changes
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17);
+
+-- name: ListAuditLog :many
+-- Newest first. Every filter is optional.
+SELECT * FROM audit_log
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(actor_user_id)::uuid IS NULL OR actor_user_id = sqlc.narg(actor_user_id)::uuid)
+  AND (sqlc.narg(action)::text IS NULL OR action = sqlc.narg(action)::text)
+  AND (sqlc.narg(entity_type)::text IS NULL OR entity_type = sqlc.narg(entity_type)::text)
+  AND (sqlc.narg(entity_id)::uuid IS NULL OR entity_id = sqlc.narg(entity_id)::uuid)
+  AND (sqlc.narg(entity_gid)::text IS NULL OR entity_gid = sqlc.narg(entity_gid)::text)
+  AND (sqlc.narg(store_id)::uuid IS NULL OR store_id = sqlc.narg(store_id)::uuid)
+  AND (sqlc.narg(since)::timestamptz IS NULL OR created_at >= sqlc.narg(since)::timestamptz)
+  AND (sqlc.narg(until)::timestamptz IS NULL OR created_at < sqlc.narg(until)::timestamptz)
+  AND (
+    sqlc.arg(has_cursor)::boolean = false
+    OR (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: PruneAuditLog :execrows
+DELETE FROM audit_log
+WHERE id IN (
+    SELECT id FROM audit_log
+    WHERE created_at < sqlc.arg(before)
+    LIMIT sqlc.arg(row_limit)
+);
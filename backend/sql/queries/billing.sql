@@ -0,0 +1,49 @@
+-- name: GetTenantBilling :one
+SELECT * FROM tenant_billing WHERE tenant_id = $1;
+
+-- name: CreateTenantBilling :one
+-- A second customer made for the tenant by a concurrent checkout is left
+-- unused, and the first returned.
+INSERT INTO tenant_billing (tenant_id, stripe_customer_id)
+VALUES ($1, $2)
+ON CONFLICT (tenant_id) DO UPDATE SET tenant_id = tenant_billing.tenant_id
+RETURNING *;
+
+-- name: LockTenantBillingByCustomer :one
+SELECT * FROM tenant_billing WHERE stripe_customer_id = $1 FOR UPDATE;
+
+-- name: UpdateTenantBillingSubscription :one
+UPDATE tenant_billing
+SET stripe_subscription_id = sqlc.arg(stripe_subscription_id),
+    plan = sqlc.arg(plan),
+    status = sqlc.arg(status),
+    current_period_end = sqlc.narg(current_period_end),
+    cancel_at_period_end = sqlc.arg(cancel_at_period_end),
+    grace_until = sqlc.narg(grace_until),
+    applied_plan = sqlc.arg(applied_plan),
+    synced_at = sqlc.arg(synced_at),
+    updated_at = now()
+WHERE tenant_id = sqlc.arg(tenant_id)
+RETURNING *;
+
+-- name: LapseTenantBilling :many
+-- Tenants whose grace period ran out while still on a paid plan, claimed
+-- for their stores to be moved to the free plan
+UPDATE tenant_billing
+SET applied_plan = sqlc.arg(free_plan)::text, updated_at = now()
+WHERE tenant_id IN (
+    SELECT tb.tenant_id FROM tenant_billing tb
+    WHERE tb.grace_until <= sqlc.arg(now)::timestamptz
+      AND tb.applied_plan <> sqlc.arg(free_plan)::text
+    ORDER BY tb.grace_until
+    LIMIT sqlc.arg(row_limit)::int
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING tenant_id;
+
+-- name: SetTenantStorePlans :execrows
+-- Deleted stores follow too, so restoring one cannot bring back a plan
+-- that lapsed
+UPDATE stores
+SET plan = sqlc.arg(plan)::text, updated_at = now()
+WHERE tenant_id = sqlc.arg(tenant_id)::uuid AND plan IS DISTINCT FROM sqlc.arg(plan)::text;
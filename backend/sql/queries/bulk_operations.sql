@@ -0,0 +1,48 @@
+-- name: CreateBulkOperation :one
+INSERT INTO bulk_operations (tenant_id, created_by, mutations, permissions, total_mutations)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, tenant_id, created_by, status, permissions, total_mutations, error, created_at, started_at, finished_at;
+
+-- name: GetBulkOperation :one
+-- Leaves out the mutations, which only the worker needs
+SELECT id, tenant_id, created_by, status, permissions, total_mutations, error, created_at, started_at, finished_at
+FROM bulk_operations
+WHERE id = $1 AND tenant_id = $2;
+
+-- name: GetBulkOperationForProcessing :one
+SELECT * FROM bulk_operations
+WHERE id = $1;
+
+-- name: StartBulkOperation :exec
+UPDATE bulk_operations
+SET status = 'running', started_at = COALESCE(started_at, now())
+WHERE id = $1;
+
+-- name: FinishBulkOperation :exec
+UPDATE bulk_operations
+SET status = $2, error = $3, finished_at = now()
+WHERE id = $1;
+
+-- name: CreateBulkOperationResult :exec
+INSERT INTO bulk_operation_results (operation_id, position, op, store_id, target_id, status, errors)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (operation_id, position) DO NOTHING;
+
+-- name: ListBulkOperationResultPositions :many
+SELECT position FROM bulk_operation_results
+WHERE operation_id = $1;
+
+-- name: ListBulkOperationResults :many
+SELECT * FROM bulk_operation_results
+WHERE operation_id = sqlc.arg(operation_id)
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+  AND position > sqlc.arg(after_position)
+ORDER BY position
+LIMIT sqlc.arg(row_limit);
+
+-- name: CountBulkOperationResults :one
+SELECT
+    COUNT(*) FILTER (WHERE status = 'succeeded')::int AS succeeded,
+    COUNT(*) FILTER (WHERE status = 'failed')::int AS failed
+FROM bulk_operation_results
+WHERE operation_id = $1;
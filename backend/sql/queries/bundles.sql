@@ -0,0 +1,48 @@
+-- name: ListBundleComponents :many
+-- The components of a bundle variant with what is needed to show and stock them
+SELECT
+    bc.component_variant_id,
+    bc.quantity,
+    pv.product_id,
+    pv.title,
+    pv.sku,
+    p.inventory_tracked,
+    p.oversell_policy
+FROM bundle_components bc
+JOIN product_variants pv ON pv.id = bc.component_variant_id
+JOIN products p ON p.id = pv.product_id
+WHERE bc.bundle_variant_id = $1
+ORDER BY bc.created_at ASC, bc.component_variant_id ASC;
+
+-- name: ClearBundleComponents :exec
+DELETE FROM bundle_components
+WHERE bundle_variant_id = $1;
+
+-- name: AddBundleComponent :exec
+INSERT INTO bundle_components (bundle_variant_id, component_variant_id, store_id, quantity)
+VALUES ($1, $2, $3, $4);
+
+-- name: GetBundleVariantIDs :many
+-- Which of the given variants are bundles
+SELECT DISTINCT bundle_variant_id FROM bundle_components
+WHERE bundle_variant_id = ANY (sqlc.arg(variant_ids)::uuid[]);
+
+-- name: IsBundleComponent :one
+SELECT EXISTS (
+    SELECT 1 FROM bundle_components WHERE component_variant_id = $1
+)::boolean AS is_component;
+
+-- name: CreateBundleComponentLineItems :many
+-- Expands a bundle line into a line per component under it. They carry no
+-- price since the bundle line does.
+INSERT INTO order_line_items (
+    order_id, parent_line_item_id, product_id, variant_id, title, variant_title, sku, quantity, price_cents, total_cents
+)
+SELECT
+    li.order_id, li.id, pv.product_id, pv.id, p.name, pv.title, pv.sku, li.quantity * bc.quantity, 0, 0
+FROM order_line_items li
+JOIN bundle_components bc ON bc.bundle_variant_id = li.variant_id
+JOIN product_variants pv ON pv.id = bc.component_variant_id
+JOIN products p ON p.id = pv.product_id
+WHERE li.id = $1
+RETURNING *;
@@ -0,0 +1,110 @@
+-- name: CreateCollection :one
+INSERT INTO collections (
+    id, gid, tenant_id, store_id, handle, title, description, kind, rules, match_any, created_at, updated_at
+)
+VALUES (
+    gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8, $9, now(), now()
+)
+RETURNING *;
+
+-- name: GetCollectionByID :one
+SELECT * FROM collections
+WHERE id = $1 AND store_id = $2;
+
+-- name: GetCollectionByGID :one
+SELECT * FROM collections
+WHERE gid = $1;
+
+-- name: GetCollectionByHandle :one
+SELECT * FROM collections
+WHERE store_id = $1 AND handle = $2;
+
+-- name: GetCollectionsByStorePaginated :many
+SELECT * FROM collections
+WHERE store_id = sqlc.arg(store_id)
+  AND (
+    sqlc.arg(has_cursor)::boolean = false
+    OR (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetAutomatedCollectionsByStore :many
+SELECT * FROM collections
+WHERE store_id = $1 AND kind = 'automated';
+
+-- name: UpdateCollection :one
+UPDATE collections
+SET handle = $3,
+    title = $4,
+    description = $5,
+    rules = $6,
+    match_any = $7,
+    updated_at = now()
+WHERE id = $1 AND store_id = $2
+RETURNING *;
+
+-- name: DeleteCollection :exec
+DELETE FROM collections
+WHERE id = $1 AND store_id = $2;
+
+-- name: AddProductToCollection :exec
+INSERT INTO collection_products (collection_id, product_id, position, created_at)
+VALUES (
+    $1, $2,
+    (SELECT COALESCE(MAX(position), 0) + 1 FROM collection_products WHERE collection_id = $1),
+    now()
+)
+ON CONFLICT (collection_id, product_id) DO NOTHING;
+
+-- name: RemoveProductFromCollection :execrows
+DELETE FROM collection_products
+WHERE collection_id = $1 AND product_id = $2;
+
+-- name: ClearCollectionProducts :exec
+DELETE FROM collection_products
+WHERE collection_id = $1;
+
+-- name: GetCollectionProductIDs :many
+SELECT product_id FROM collection_products
+WHERE collection_id = $1
+ORDER BY position ASC, product_id ASC;
+
+-- name: SetCollectionProductPosition :exec
+UPDATE collection_products
+SET position = $3
+WHERE collection_id = $1 AND product_id = $2;
+
+-- name: GetCollectionProductsPaginated :many
+SELECT products.*, collection_products.position
+FROM collection_products
+JOIN products ON products.id = collection_products.product_id
+WHERE collection_products.collection_id = $1
+  AND products.deleted_at IS NULL
+  AND (sqlc.arg(active_only)::boolean = false OR products.status = 'active')
+  AND (sqlc.narg(channel_id)::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM product_publications pub
+    WHERE pub.product_id = products.id AND pub.channel_id = sqlc.narg(channel_id)::uuid
+  ))
+  AND (
+    sqlc.arg(has_cursor)::boolean = false
+    OR (collection_products.position, products.id) > (sqlc.arg(cursor_position)::integer, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY collection_products.position ASC, products.id ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetCollectionMatchCandidates :many
+-- Rule inputs for every product in the store, or a single product when filtered.
+-- min_price_cents is only meaningful when active_variant_count > 0.
+SELECT products.id, products.tags, products.status,
+       COUNT(product_variants.id)::bigint AS active_variant_count,
+       COALESCE(MIN(product_variants.price_cents), 0)::bigint AS min_price_cents
+FROM products
+LEFT JOIN product_variants
+  ON product_variants.product_id = products.id AND product_variants.status = 'active'
+  AND product_variants.deleted_at IS NULL
+WHERE products.store_id = sqlc.arg(store_id)
+  AND products.deleted_at IS NULL
+  AND (sqlc.narg(product_id)::uuid IS NULL OR products.id = sqlc.narg(product_id)::uuid)
+GROUP BY products.id, products.tags, products.status, products.created_at
+ORDER BY products.created_at ASC, products.id ASC;
@@ -0,0 +1,60 @@
+-- name: ListStoreCurrencies :many
+SELECT currency FROM store_currencies
+WHERE store_id = $1
+ORDER BY currency;
+
+-- name: ReplaceStoreCurrencies :exec
+-- Sets the store currencies to exactly the given list in one statement
+WITH removed AS (
+    DELETE FROM store_currencies
+    WHERE store_id = sqlc.arg(store_id)
+      AND currency <> ALL (sqlc.arg(currencies)::text[])
+)
+INSERT INTO store_currencies (store_id, currency)
+SELECT sqlc.arg(store_id), unnest(sqlc.arg(currencies)::text[])
+ON CONFLICT (store_id, currency) DO NOTHING;
+
+-- name: ListVariantPrices :many
+SELECT * FROM variant_prices
+WHERE variant_id = $1
+ORDER BY currency;
+
+-- name: UpsertVariantPrice :one
+INSERT INTO variant_prices (variant_id, store_id, currency, price_cents, compare_at_cents)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (variant_id, currency) DO UPDATE
+SET price_cents = EXCLUDED.price_cents,
+    compare_at_cents = EXCLUDED.compare_at_cents,
+    updated_at = now()
+RETURNING *;
+
+-- name: DeleteVariantPrice :execrows
+DELETE FROM variant_prices
+WHERE variant_id = $1 AND currency = $2;
+
+-- name: GetStorefrontVariantPrices :many
+-- Base prices of the active variants of the given products, with any
+-- override in the presentment currency
+SELECT pv.id, pv.product_id, pv.price_cents, vp.price_cents AS override_cents
+FROM product_variants pv
+LEFT JOIN variant_prices vp ON vp.variant_id = pv.id AND vp.currency = sqlc.arg(currency)::text
+WHERE pv.store_id = sqlc.arg(store_id)
+  AND pv.product_id = ANY (sqlc.arg(product_ids)::uuid[])
+  AND pv.status = 'active'
+  AND pv.deleted_at IS NULL;
+
+-- name: ListExchangeRates :many
+SELECT currency, rate FROM exchange_rates
+WHERE currency = ANY (sqlc.arg(currencies)::text[]);
+
+-- name: ReplaceExchangeRates :execrows
+-- Writes the rates of one sync and drops currencies it no longer has
+WITH upserted AS (
+    INSERT INTO exchange_rates (currency, base, rate)
+    SELECT unnest(sqlc.arg(currencies)::text[]), sqlc.arg(base)::text, unnest(sqlc.arg(rates)::float8[])
+    ON CONFLICT (currency) DO UPDATE
+    SET base = EXCLUDED.base, rate = EXCLUDED.rate, updated_at = now()
+    RETURNING currency
+)
+DELETE FROM exchange_rates
+WHERE currency NOT IN (SELECT currency FROM upserted);
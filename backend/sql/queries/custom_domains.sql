@@ -59,10 +59,13 @@ DELETE FROM custom_domains
 WHERE id = $1 AND store_id = $2;
 
 -- name: GetStoreByCustomDomain :one
+-- Stores of suspended tenants are not found, so they stop being served
 SELECT s.* FROM stores s
 JOIN custom_domains cd ON s.id = cd.store_id
 WHERE cd.domain = $1
-  AND cd.verification_status = 'verified';
+  AND cd.verification_status = 'verified'
+  AND s.deleted_at IS NULL
+  AND NOT EXISTS (SELECT 1 FROM tenants t WHERE t.id = s.tenant_id AND t.status = 'suspended');
 
 -- name: GetPendingDomainVerifications :many
 SELECT * FROM custom_domains
@@ -0,0 +1,55 @@
+-- name: CreateCustomerAddress :one
+INSERT INTO customer_addresses (
+    id, customer_id, first_name, last_name, company, address1, address2, city,
+    region_code, postal_code, country_code, phone, is_default_shipping, is_default_billing,
+    created_at, updated_at
+)
+VALUES (
+    gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, now(), now()
+)
+RETURNING *;
+
+-- name: GetCustomerAddressByID :one
+SELECT * FROM customer_addresses
+WHERE id = $1 AND customer_id = $2;
+
+-- name: GetCustomerAddresses :many
+SELECT * FROM customer_addresses
+WHERE customer_id = $1
+ORDER BY is_default_shipping DESC, is_default_billing DESC, created_at ASC, id ASC;
+
+-- name: CountCustomerAddresses :one
+SELECT COUNT(*) FROM customer_addresses
+WHERE customer_id = $1;
+
+-- name: UpdateCustomerAddress :one
+UPDATE customer_addresses
+SET first_name = $3,
+    last_name = $4,
+    company = $5,
+    address1 = $6,
+    address2 = $7,
+    city = $8,
+    region_code = $9,
+    postal_code = $10,
+    country_code = $11,
+    phone = $12,
+    is_default_shipping = $13,
+    is_default_billing = $14,
+    updated_at = now()
+WHERE id = $1 AND customer_id = $2
+RETURNING *;
+
+-- name: ClearDefaultShippingAddress :exec
+UPDATE customer_addresses
+SET is_default_shipping = FALSE, updated_at = now()
+WHERE customer_id = $1 AND is_default_shipping;
+
+-- name: ClearDefaultBillingAddress :exec
+UPDATE customer_addresses
+SET is_default_billing = FALSE, updated_at = now()
+WHERE customer_id = $1 AND is_default_billing;
+
+-- name: DeleteCustomerAddress :exec
+DELETE FROM customer_addresses
+WHERE id = $1 AND customer_id = $2;
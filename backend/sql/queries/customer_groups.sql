@@ -0,0 +1,129 @@
+-- name: CreateCustomerGroup :one
+INSERT INTO customer_groups (
+    gid, tenant_id, store_id, name, kind,
+    min_total_spent_cents, max_total_spent_cents, min_order_count, max_order_count, tags
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: GetCustomerGroupByID :one
+SELECT * FROM customer_groups
+WHERE id = $1 AND store_id = $2;
+
+-- name: ListCustomerGroups :many
+SELECT customer_groups.*,
+    (SELECT COUNT(*) FROM customer_group_members WHERE group_id = customer_groups.id)::bigint AS customer_count
+FROM customer_groups
+WHERE store_id = $1
+ORDER BY name ASC, id ASC;
+
+-- name: GetCustomerGroupIDsInStore :many
+-- Which of the given groups belong to the store
+SELECT id FROM customer_groups
+WHERE store_id = sqlc.arg(store_id)
+  AND id = ANY (sqlc.arg(ids)::uuid[]);
+
+-- name: UpdateCustomerGroup :one
+UPDATE customer_groups
+SET name = $3,
+    updated_at = now()
+WHERE id = $1 AND store_id = $2
+RETURNING *;
+
+-- name: UpdateCustomerGroupRules :one
+-- Changes the rules of a rule group. Its members are stale until it is
+-- recalculated.
+UPDATE customer_groups
+SET min_total_spent_cents = $3,
+    max_total_spent_cents = $4,
+    min_order_count = $5,
+    max_order_count = $6,
+    tags = $7,
+    recalculated_at = NULL,
+    updated_at = now()
+WHERE id = $1 AND store_id = $2 AND kind = 'rule'
+RETURNING *;
+
+-- name: RecalculateCustomerGroup :execrows
+-- Replaces the members of a rule group with the customers matching every
+-- rule it sets. No row is affected for a manual group. Orders count once paid and
+-- not cancelled. Spend is net of refunds, in the store currency only.
+WITH grp AS (
+    SELECT cg.* FROM customer_groups cg
+    WHERE cg.id = sqlc.arg(id) AND cg.kind = 'rule'
+),
+matching AS (
+    SELECT c.id FROM customers c
+    JOIN grp ON grp.store_id = c.store_id
+    JOIN stores s ON s.id = c.store_id
+    CROSS JOIN LATERAL (
+        SELECT COUNT(*) AS order_count,
+               COALESCE(SUM(
+                   o.total_cents - COALESCE((SELECT SUM(rf.amount_cents) FROM refunds rf WHERE rf.order_id = o.id), 0)
+               ) FILTER (WHERE o.currency = s.default_currency), 0) AS total_spent
+        FROM orders o
+        WHERE o.customer_id = c.id
+          AND o.status <> 'cancelled'
+          AND o.financial_status IN ('paid', 'partially_refunded')
+    ) stats
+    WHERE c.tags @> grp.tags
+      AND (grp.min_total_spent_cents IS NULL OR stats.total_spent >= grp.min_total_spent_cents)
+      AND (grp.max_total_spent_cents IS NULL OR stats.total_spent <= grp.max_total_spent_cents)
+      AND (grp.min_order_count IS NULL OR stats.order_count >= grp.min_order_count)
+      AND (grp.max_order_count IS NULL OR stats.order_count <= grp.max_order_count)
+),
+removed AS (
+    DELETE FROM customer_group_members m
+    USING grp
+    WHERE m.group_id = grp.id
+      AND m.customer_id NOT IN (SELECT id FROM matching)
+),
+added AS (
+    INSERT INTO customer_group_members (group_id, customer_id)
+    SELECT grp.id, matching.id FROM grp CROSS JOIN matching
+    ON CONFLICT (group_id, customer_id) DO NOTHING
+)
+UPDATE customer_groups
+SET recalculated_at = now()
+FROM grp
+WHERE customer_groups.id = grp.id;
+
+-- name: ClaimStaleCustomerGroup :one
+-- Takes the rule group recalculated longest ago, if before stale_before,
+-- and marks it recalculated up front so no other worker takes it too
+UPDATE customer_groups
+SET recalculated_at = sqlc.arg(now)::timestamptz
+WHERE customer_groups.id = (
+    SELECT cg.id FROM customer_groups cg
+    JOIN stores s ON s.id = cg.store_id
+    WHERE cg.kind = 'rule' AND s.deleted_at IS NULL
+      AND (cg.recalculated_at IS NULL OR cg.recalculated_at < sqlc.arg(stale_before)::timestamptz)
+    ORDER BY cg.recalculated_at ASC NULLS FIRST
+    LIMIT 1
+    FOR UPDATE OF cg SKIP LOCKED
+)
+RETURNING id;
+
+-- name: DeleteCustomerGroup :execrows
+DELETE FROM customer_groups
+WHERE id = $1 AND store_id = $2;
+
+-- name: AddCustomerToGroup :exec
+INSERT INTO customer_group_members (group_id, customer_id)
+VALUES ($1, $2)
+ON CONFLICT (group_id, customer_id) DO NOTHING;
+
+-- name: RemoveCustomerFromGroup :execrows
+DELETE FROM customer_group_members
+WHERE group_id = $1 AND customer_id = $2;
+
+-- name: GetCustomerGroupMembersPaginated :many
+SELECT customers.* FROM customers
+JOIN customer_group_members ON customer_group_members.customer_id = customers.id
+WHERE customer_group_members.group_id = sqlc.arg(group_id)
+  AND (
+    sqlc.arg(has_cursor)::boolean = false
+    OR (customers.created_at, customers.id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY customers.created_at DESC, customers.id DESC
+LIMIT sqlc.arg(row_limit);
@@ -0,0 +1,110 @@
+-- name: CreateCustomer :one
+-- The blind index keeps emails unique among resealed customers. A customer
+-- not yet resealed still holds their email in the clear, so creating
+-- another with it returns no row.
+INSERT INTO customers (
+    id, gid, tenant_id, store_id, email, email_digest, hashed_password,
+    first_name, last_name, phone, accepts_marketing, status, created_at, updated_at
+)
+SELECT
+    gen_random_uuid(), sqlc.arg(gid), sqlc.arg(tenant_id), sqlc.arg(store_id), sqlc.arg(email),
+    sqlc.arg(email_digest), sqlc.arg(hashed_password), sqlc.arg(first_name), sqlc.arg(last_name),
+    sqlc.arg(phone), sqlc.arg(accepts_marketing), 'enabled', now(), now()
+WHERE NOT EXISTS (
+    SELECT 1 FROM customers c
+    WHERE c.store_id = sqlc.arg(store_id)
+      AND c.email_digest = ''
+      AND c.email = sqlc.arg(plain_email)::text
+)
+RETURNING *;
+
+-- name: GetCustomerByID :one
+SELECT * FROM customers
+WHERE id = $1 AND store_id = $2;
+
+-- name: GetCustomerByGID :one
+SELECT * FROM customers
+WHERE gid = $1;
+
+-- name: GetCustomerByEmail :one
+-- Customers not yet resealed are matched on the plain email
+SELECT * FROM customers
+WHERE store_id = sqlc.arg(store_id)
+  AND (
+    email_digest = sqlc.arg(email_digest)
+    OR (email_digest = '' AND email = sqlc.arg(email)::text)
+  );
+
+-- name: UpdateCustomerProfile :one
+UPDATE customers
+SET
+    first_name = $3,
+    last_name = $4,
+    phone = $5,
+    accepts_marketing = $6,
+    updated_at = now()
+WHERE id = $1 AND store_id = $2
+RETURNING *;
+
+-- name: UpdateCustomerTags :one
+UPDATE customers
+SET
+    tags = $3,
+    updated_at = now()
+WHERE id = $1 AND store_id = $2
+RETURNING *;
+
+-- name: ListFilteredCustomers :many
+-- Store customer listing, newest first. A NULL or empty filter matches
+-- everything. Customers must carry every requested tag.
+SELECT customers.* FROM customers
+WHERE store_id = sqlc.arg(store_id)
+  AND tags @> COALESCE(sqlc.arg(tags)::text[], '{}')
+  AND (sqlc.narg(accepts_marketing)::boolean IS NULL OR accepts_marketing = sqlc.narg(accepts_marketing)::boolean)
+  AND (
+    sqlc.narg(group_id)::uuid IS NULL
+    OR EXISTS (
+        SELECT 1 FROM customer_group_members m
+        WHERE m.customer_id = customers.id AND m.group_id = sqlc.narg(group_id)::uuid
+    )
+  )
+  AND (
+    NOT sqlc.arg(has_cursor)::boolean
+    OR (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetCustomerTagsByStore :many
+SELECT tag::text AS tag, COUNT(*) AS count
+FROM customers
+CROSS JOIN LATERAL unnest(tags) AS tag
+WHERE store_id = $1
+GROUP BY tag
+ORDER BY count DESC, tag ASC;
+
+-- name: UpdateCustomerStatus :one
+UPDATE customers
+SET
+    status = $3,
+    updated_at = now()
+WHERE id = $1 AND store_id = $2
+RETURNING *;
+
+-- name: CreateCustomerRefreshToken :one
+INSERT INTO customer_refresh_tokens (token_hash, customer_id, expires_at, created_at, updated_at)
+VALUES ($1, $2, $3, now(), now())
+RETURNING *;
+
+-- name: GetCustomerFromRefreshToken :one
+SELECT customers.* FROM customers
+JOIN customer_refresh_tokens ON customers.id = customer_refresh_tokens.customer_id
+WHERE customer_refresh_tokens.token_hash = $1
+  AND customers.store_id = $2
+  AND customer_refresh_tokens.revoked_at IS NULL
+  AND customer_refresh_tokens.expires_at > now();
+
+-- name: RevokeCustomerRefreshToken :exec
+UPDATE customer_refresh_tokens
+SET revoked_at = now(), updated_at = now()
+WHERE token_hash = $1;
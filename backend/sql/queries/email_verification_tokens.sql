@@ -0,0 +1,32 @@
+-- name: CreateEmailVerificationToken :one
+INSERT INTO email_verification_tokens (id, user_id, token_hash, expires_at, created_at)
+VALUES (gen_random_uuid(), $1, $2, $3, now())
+RETURNING *;
+
+-- name: GetActiveEmailVerificationToken :one
+SELECT * FROM email_verification_tokens
+WHERE token_hash = $1
+  AND used_at IS NULL
+  AND expires_at > now()
+FOR UPDATE;
+
+-- name: CreateEmailChangeToken :one
+INSERT INTO email_verification_tokens (id, user_id, token_hash, new_email, expires_at, created_at)
+VALUES (gen_random_uuid(), $1, $2, $3, $4, now())
+RETURNING *;
+
+-- name: GetPendingEmailChange :one
+-- The address the user is moving to, if they have asked to
+SELECT new_email FROM email_verification_tokens
+WHERE user_id = $1
+  AND new_email IS NOT NULL
+  AND used_at IS NULL
+  AND expires_at > now()
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: InvalidateEmailVerificationTokens :exec
+-- Marks every outstanding token of a user as used
+UPDATE email_verification_tokens
+SET used_at = now()
+WHERE user_id = $1 AND used_at IS NULL;
@@ -0,0 +1,101 @@
+-- name: ListEncryptionKeys :many
+SELECT * FROM encryption_keys
+ORDER BY created_at ASC, id ASC;
+
+-- name: CreateEncryptionKey :one
+-- Returns no row when another process created the active key of the
+-- purpose first
+INSERT INTO encryption_keys (id, purpose, wrapped_key, kek_id)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT DO NOTHING
+RETURNING *;
+
+-- name: RewrapEncryptionKey :exec
+UPDATE encryption_keys
+SET wrapped_key = $2, kek_id = $3
+WHERE id = $1;
+
+-- name: RetireEncryptionKey :exec
+UPDATE encryption_keys
+SET retired_at = now()
+WHERE purpose = $1 AND retired_at IS NULL;
+
+-- name: ListCustomersToReseal :many
+-- Customers after the cursor holding a value not sealed under the active
+-- data key, or written before the blind index
+SELECT * FROM customers
+WHERE id > sqlc.arg(after)::uuid
+  AND (
+    email NOT LIKE sqlc.arg(sealed_prefix)::text || '%'
+    OR phone NOT LIKE sqlc.arg(sealed_prefix)::text || '%'
+    OR email_digest = ''
+  )
+ORDER BY id ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ResealCustomer :execrows
+-- A customer changed since it was read is left for the next pass
+UPDATE customers
+SET email = sqlc.arg(email), email_digest = sqlc.arg(email_digest), phone = sqlc.arg(phone)
+WHERE id = sqlc.arg(id) AND updated_at = sqlc.arg(updated_at);
+
+-- name: ListCustomerAddressesToReseal :many
+SELECT * FROM customer_addresses
+WHERE id > sqlc.arg(after)::uuid
+  AND (
+    address1 NOT LIKE sqlc.arg(sealed_prefix)::text || '%'
+    OR address2 NOT LIKE sqlc.arg(sealed_prefix)::text || '%'
+    OR phone NOT LIKE sqlc.arg(sealed_prefix)::text || '%'
+  )
+ORDER BY id ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ResealCustomerAddress :execrows
+UPDATE customer_addresses
+SET address1 = sqlc.arg(address1), address2 = sqlc.arg(address2), phone = sqlc.arg(phone)
+WHERE id = sqlc.arg(id) AND updated_at = sqlc.arg(updated_at);
+
+-- name: ListOrdersToReseal :many
+-- Sealed addresses are stored as a JSON string holding the sealed value
+SELECT * FROM orders
+WHERE id > sqlc.arg(after)::uuid
+  AND (
+    email NOT LIKE sqlc.arg(sealed_prefix)::text || '%'
+    OR (email IS NOT NULL AND email_digest = '')
+    OR shipping_address::text NOT LIKE '"' || sqlc.arg(sealed_prefix)::text || '%'
+    OR billing_address::text NOT LIKE '"' || sqlc.arg(sealed_prefix)::text || '%'
+  )
+ORDER BY id ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ResealOrder :execrows
+UPDATE orders
+SET email = sqlc.arg(email),
+    email_digest = sqlc.arg(email_digest),
+    shipping_address = sqlc.arg(shipping_address),
+    billing_address = sqlc.arg(billing_address)
+WHERE id = sqlc.arg(id) AND updated_at = sqlc.arg(updated_at);
+
+-- name: ListUserMFAToReseal :many
+SELECT * FROM user_mfa
+WHERE user_id > sqlc.arg(after)::uuid
+  AND secret NOT LIKE sqlc.arg(sealed_prefix)::text || '%'
+ORDER BY user_id ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ResealUserMFA :execrows
+UPDATE user_mfa
+SET secret = sqlc.arg(secret)
+WHERE user_id = sqlc.arg(user_id) AND updated_at = sqlc.arg(updated_at);
+
+-- name: ListACMECacheEntriesToReseal :many
+SELECT * FROM acme_cache
+WHERE key > sqlc.arg(after)::text
+  AND data NOT LIKE sqlc.arg(sealed_prefix)::text || '%'
+ORDER BY key ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ResealACMECacheEntry :execrows
+UPDATE acme_cache
+SET data = sqlc.arg(data)
+WHERE key = sqlc.arg(key) AND updated_at = sqlc.arg(updated_at);
@@ -0,0 +1,23 @@
+-- name: CountTenantStores :one
+-- Live stores in a tenant. Deleted ones free their slot until restored.
+SELECT count(*) FROM stores
+WHERE tenant_id = sqlc.arg(tenant_id)::uuid AND deleted_at IS NULL;
+
+-- name: CountStoreProducts :one
+SELECT count(*) FROM products
+WHERE store_id = sqlc.arg(store_id) AND deleted_at IS NULL;
+
+-- name: CountTenantSeats :one
+-- Active members plus open invitations, which hold a seat until they are
+-- accepted, revoked or expire. An invitation to except_email is left out,
+-- so inviting an address again counts it once.
+SELECT (
+    (SELECT count(*) FROM tenant_users tu
+     WHERE tu.tenant_id = sqlc.arg(tenant_id) AND tu.status = 'active')
+  + (SELECT count(*) FROM tenant_invitations ti
+     WHERE ti.tenant_id = sqlc.arg(tenant_id)
+       AND ti.accepted_at IS NULL
+       AND ti.revoked_at IS NULL
+       AND ti.expires_at > now()
+       AND ti.email <> sqlc.arg(except_email)::text)
+)::bigint AS seats;
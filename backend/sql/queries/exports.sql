@@ -0,0 +1,27 @@
+-- name: CreateExport :one
+INSERT INTO exports (store_id, created_by, resource, format, columns, filters)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetExport :one
+SELECT * FROM exports
+WHERE id = $1 AND store_id = $2 AND resource = $3;
+
+-- name: GetExportForProcessing :one
+SELECT * FROM exports
+WHERE id = $1;
+
+-- name: StartExport :exec
+UPDATE exports
+SET status = 'running', started_at = COALESCE(started_at, now())
+WHERE id = $1;
+
+-- name: CompleteExport :exec
+UPDATE exports
+SET status = 'completed', storage_key = $2, row_count = $3, size_bytes = $4, finished_at = now()
+WHERE id = $1;
+
+-- name: FailExport :exec
+UPDATE exports
+SET status = 'failed', error = $2, finished_at = now()
+WHERE id = $1;
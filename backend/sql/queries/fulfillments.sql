@@ -0,0 +1,97 @@
+-- name: CreateFulfillment :one
+INSERT INTO fulfillments (
+    id, gid, store_id, order_id, status, carrier, tracking_number, tracking_url,
+    shipped_at, created_at, updated_at
+)
+VALUES (
+    gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7,
+    CASE WHEN $4 = 'shipped' THEN now() END, now(), now()
+)
+RETURNING *;
+
+-- name: CreateFulfillmentLineItem :exec
+INSERT INTO fulfillment_line_items (fulfillment_id, order_line_item_id, quantity)
+VALUES ($1, $2, $3);
+
+-- name: GetFulfillmentByID :one
+SELECT * FROM fulfillments
+WHERE id = $1 AND order_id = $2;
+
+-- name: GetFulfillmentsByOrder :many
+SELECT * FROM fulfillments
+WHERE order_id = $1
+ORDER BY created_at ASC, id ASC;
+
+-- name: GetFulfillmentLineItemsByOrder :many
+SELECT fli.fulfillment_id, fli.order_line_item_id, fli.quantity
+FROM fulfillment_line_items fli
+JOIN fulfillments f ON f.id = fli.fulfillment_id
+WHERE f.order_id = $1;
+
+-- name: GetOrderLineItemFulfillment :many
+-- Ordered and fulfilled quantity per line item, ignoring cancelled
+-- fulfillments. A bundle line is fulfilled through its component lines.
+SELECT
+    li.id,
+    li.quantity,
+    COALESCE(SUM(fli.quantity) FILTER (WHERE f.status <> 'cancelled'), 0)::bigint AS fulfilled_quantity
+FROM order_line_items li
+LEFT JOIN fulfillment_line_items fli ON fli.order_line_item_id = li.id
+LEFT JOIN fulfillments f ON f.id = fli.fulfillment_id
+WHERE li.order_id = $1
+  AND NOT EXISTS (SELECT 1 FROM order_line_items c WHERE c.parent_line_item_id = li.id)
+GROUP BY li.id, li.quantity
+ORDER BY li.id;
+
+-- name: UpdateFulfillmentTracking :one
+UPDATE fulfillments
+SET carrier = $3, tracking_number = $4, tracking_url = $5, updated_at = now()
+WHERE id = $1 AND order_id = $2
+RETURNING *;
+
+-- name: UpdateFulfillmentStatus :one
+UPDATE fulfillments
+SET
+    status = sqlc.arg(status),
+    shipped_at = CASE WHEN sqlc.arg(status) = 'shipped' THEN COALESCE(shipped_at, now()) ELSE shipped_at END,
+    delivered_at = CASE WHEN sqlc.arg(status) = 'delivered' THEN now() ELSE delivered_at END,
+    cancelled_at = CASE WHEN sqlc.arg(status) = 'cancelled' THEN now() ELSE cancelled_at END,
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND order_id = sqlc.arg(order_id)
+RETURNING *;
+
+-- name: SetFulfillmentLabel :one
+-- Records a bought label. Returns no row when the fulfillment already has
+-- one.
+UPDATE fulfillments
+SET
+    label_provider = sqlc.arg(label_provider),
+    label_shipment_ref = sqlc.arg(label_shipment_ref),
+    label_url = sqlc.arg(label_url),
+    label_cost_cents = sqlc.arg(label_cost_cents),
+    label_currency = sqlc.arg(label_currency),
+    label_purchased_at = now(),
+    carrier = sqlc.arg(carrier),
+    tracking_number = sqlc.arg(tracking_number),
+    tracking_url = sqlc.narg(tracking_url),
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND order_id = sqlc.arg(order_id) AND label_provider IS NULL
+RETURNING *;
+
+-- name: GetFulfillmentsByLabelTracking :many
+-- Fulfillments with a label from provider for tracking_number
+SELECT * FROM fulfillments
+WHERE label_provider = sqlc.arg(label_provider) AND tracking_number = sqlc.arg(tracking_number)
+ORDER BY created_at ASC, id ASC;
+
+-- name: SetFulfillmentTrackingStatus :one
+-- Records a carrier update. Returns no row for an update older than the
+-- last one, since providers may deliver them out of order.
+UPDATE fulfillments
+SET
+    tracking_status = sqlc.arg(tracking_status),
+    tracking_updated_at = sqlc.arg(tracking_updated_at)::timestamptz,
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND order_id = sqlc.arg(order_id)
+  AND (tracking_updated_at IS NULL OR tracking_updated_at <= sqlc.arg(tracking_updated_at)::timestamptz)
+RETURNING *;
@@ -0,0 +1,65 @@
+-- name: CreateGiftCard :one
+INSERT INTO gift_cards (
+    id, gid, tenant_id, store_id, customer_id, code_hash, last_characters, currency,
+    initial_balance_cents, balance_cents, status, note, expires_at, created_at, updated_at
+)
+VALUES (
+    gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8, $8, 'active', $9, $10, now(), now()
+)
+RETURNING *;
+
+-- name: GetGiftCardByID :one
+SELECT * FROM gift_cards
+WHERE id = $1 AND store_id = $2;
+
+-- name: GetGiftCardByCodeHash :one
+SELECT * FROM gift_cards
+WHERE store_id = $1 AND code_hash = $2;
+
+-- name: GetGiftCardsByStorePaginated :many
+SELECT * FROM gift_cards
+WHERE store_id = sqlc.arg(store_id)
+  AND (
+    sqlc.arg(has_cursor)::boolean = false
+    OR (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: UpdateGiftCardStatus :one
+UPDATE gift_cards
+SET status = $3, updated_at = now()
+WHERE id = $1 AND store_id = $2
+RETURNING *;
+
+-- name: ChangeGiftCardBalance :one
+-- Applies a signed change, refusing to take the balance below zero
+UPDATE gift_cards
+SET balance_cents = balance_cents + sqlc.arg(amount_cents)::bigint, updated_at = now()
+WHERE id = sqlc.arg(id) AND store_id = sqlc.arg(store_id)
+  AND balance_cents + sqlc.arg(amount_cents)::bigint >= 0
+RETURNING *;
+
+-- name: CreateGiftCardTransaction :one
+INSERT INTO gift_card_transactions (
+    id, gift_card_id, store_id, kind, amount_cents, balance_after_cents, order_id, user_id, note, created_at
+)
+VALUES (
+    gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8, now()
+)
+RETURNING *;
+
+-- name: GetGiftCardTransactionsPaginated :many
+SELECT * FROM gift_card_transactions
+WHERE gift_card_id = sqlc.arg(gift_card_id)
+  AND (
+    sqlc.arg(has_cursor)::boolean = false
+    OR (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: SumOrderGiftCardRedemptions :one
+SELECT COALESCE(-SUM(amount_cents), 0)::bigint AS redeemed_cents
+FROM gift_card_transactions
+WHERE order_id = $1 AND kind = 'redeem';
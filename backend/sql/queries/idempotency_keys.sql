@@ -0,0 +1,42 @@
+-- name: ClaimIdempotencyKey :one
+INSERT INTO idempotency_keys (id, tenant_id, caller, key, method, path, request_hash, status, expires_at, created_at, updated_at)
+VALUES (gen_random_uuid(), sqlc.arg(tenant_id), sqlc.arg(caller), sqlc.arg(key), sqlc.arg(method), sqlc.arg(path), sqlc.arg(request_hash), 'in_progress', sqlc.arg(expires_at), now(), now())
+ON CONFLICT (tenant_id, caller, key, method, path) DO UPDATE
+SET
+    request_hash = EXCLUDED.request_hash,
+    status = 'in_progress',
+    response_status = NULL,
+    response_headers = NULL,
+    response_body = NULL,
+    expires_at = EXCLUDED.expires_at,
+    created_at = now(),
+    updated_at = now()
+WHERE idempotency_keys.expires_at < now()
+   OR (idempotency_keys.status = 'in_progress' AND idempotency_keys.updated_at < sqlc.arg(locked_before))
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE tenant_id = $1 AND caller = $2 AND key = $3 AND method = $4 AND path = $5;
+
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys
+SET
+    status = 'completed',
+    response_status = $2,
+    response_headers = $3,
+    response_body = $4,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE id = $1;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE id IN (
+    SELECT id FROM idempotency_keys
+    WHERE expires_at < now()
+    LIMIT sqlc.arg(row_limit)
+);
@@ -0,0 +1,47 @@
+-- name: EnsureInventoryItem :one
+INSERT INTO inventory_items (id, tenant_id, store_id, variant_id, on_hand, created_at, updated_at)
+VALUES (gen_random_uuid(), $1, $2, $3, 0, now(), now())
+ON CONFLICT (variant_id) DO UPDATE
+SET variant_id = EXCLUDED.variant_id
+RETURNING *;
+
+-- name: GetInventoryItemByVariantID :one
+SELECT * FROM inventory_items
+WHERE variant_id = $1 AND store_id = $2;
+
+-- name: GetInventoryItemsByStorePaginated :many
+SELECT inventory_items.*, product_variants.product_id, product_variants.sku, product_variants.title
+FROM inventory_items
+JOIN product_variants ON product_variants.id = inventory_items.variant_id
+WHERE inventory_items.store_id = sqlc.arg(store_id)
+  AND product_variants.deleted_at IS NULL
+  AND (
+    sqlc.arg(has_cursor)::boolean = false
+    OR (inventory_items.created_at, inventory_items.id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY inventory_items.created_at DESC, inventory_items.id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: AdjustInventoryItem :one
+-- Returns no row when the adjustment would take stock below zero
+UPDATE inventory_items
+SET
+    on_hand = on_hand + sqlc.arg(delta)::integer,
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND on_hand + sqlc.arg(delta)::integer >= 0
+RETURNING *;
+
+-- name: CreateInventoryMovement :one
+INSERT INTO inventory_movements (id, tenant_id, inventory_item_id, location_id, transfer_id, delta, quantity_after, reason, note, created_by, created_at)
+VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8, $9, now())
+RETURNING *;
+
+-- name: GetInventoryMovementsByItemPaginated :many
+SELECT * FROM inventory_movements
+WHERE inventory_item_id = sqlc.arg(inventory_item_id)
+  AND (
+    sqlc.arg(has_cursor)::boolean = false
+    OR (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
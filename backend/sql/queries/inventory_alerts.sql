@@ -0,0 +1,96 @@
+-- name: SetInventoryLevelReorderPoint :one
+UPDATE inventory_levels
+SET
+    reorder_point = sqlc.narg(reorder_point)::integer,
+    reorder_quantity = sqlc.narg(reorder_quantity)::integer,
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ClaimInventoryLevelCrossing :one
+-- A level that went to or below its reorder point and was not alerted on,
+-- or went back above it since, skipping any another monitor has locked
+SELECT
+    inventory_levels.id,
+    inventory_levels.tenant_id,
+    inventory_items.store_id,
+    inventory_items.variant_id,
+    inventory_levels.location_id,
+    inventory_levels.on_hand,
+    inventory_levels.reorder_point,
+    inventory_levels.reorder_quantity,
+    inventory_levels.low_stock_since,
+    products.name AS product_name,
+    product_variants.title AS variant_title,
+    product_variants.sku,
+    inventory_locations.name AS location_name
+FROM inventory_levels
+JOIN inventory_items ON inventory_items.id = inventory_levels.inventory_item_id
+JOIN product_variants ON product_variants.id = inventory_items.variant_id
+JOIN products ON products.id = product_variants.product_id
+JOIN inventory_locations ON inventory_locations.id = inventory_levels.location_id
+WHERE (
+    inventory_levels.low_stock_since IS NULL
+    AND inventory_levels.on_hand <= inventory_levels.reorder_point
+    AND product_variants.deleted_at IS NULL
+  ) OR (
+    inventory_levels.low_stock_since IS NOT NULL
+    AND (inventory_levels.reorder_point IS NULL OR inventory_levels.on_hand > inventory_levels.reorder_point)
+  )
+LIMIT 1
+FOR UPDATE OF inventory_levels SKIP LOCKED;
+
+-- name: SetInventoryLevelLowStockSince :exec
+UPDATE inventory_levels
+SET low_stock_since = sqlc.narg(low_stock_since)::timestamptz
+WHERE id = sqlc.arg(id);
+
+-- name: CreateInventoryAlert :one
+INSERT INTO inventory_alerts (tenant_id, store_id, variant_id, location_id, kind, on_hand, reorder_point)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: ListInventoryAlertsPaginated :many
+-- Newest first. A NULL kind matches every alert.
+SELECT * FROM inventory_alerts
+WHERE store_id = sqlc.arg(store_id)
+  AND (sqlc.narg(kind)::text IS NULL OR kind = sqlc.narg(kind)::text)
+  AND (
+    NOT sqlc.arg(has_cursor)::boolean
+    OR (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListLowStockLevelsPaginated :many
+-- Levels at or below their reorder point right now, by product and
+-- variant. A NULL location matches every location.
+SELECT
+    inventory_levels.id,
+    inventory_items.variant_id,
+    product_variants.product_id,
+    inventory_levels.location_id,
+    inventory_levels.on_hand,
+    inventory_levels.reorder_point,
+    inventory_levels.reorder_quantity,
+    inventory_levels.low_stock_since,
+    products.name AS product_name,
+    product_variants.title AS variant_title,
+    product_variants.sku,
+    inventory_locations.name AS location_name
+FROM inventory_levels
+JOIN inventory_items ON inventory_items.id = inventory_levels.inventory_item_id
+JOIN product_variants ON product_variants.id = inventory_items.variant_id
+JOIN products ON products.id = product_variants.product_id
+JOIN inventory_locations ON inventory_locations.id = inventory_levels.location_id
+WHERE inventory_items.store_id = sqlc.arg(store_id)
+  AND inventory_levels.on_hand <= inventory_levels.reorder_point
+  AND product_variants.deleted_at IS NULL
+  AND products.deleted_at IS NULL
+  AND (sqlc.narg(location_id)::uuid IS NULL OR inventory_levels.location_id = sqlc.narg(location_id)::uuid)
+  AND (
+    NOT sqlc.arg(has_cursor)::boolean
+    OR (products.name, inventory_levels.id) > (sqlc.arg(cursor_name)::text, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY products.name, inventory_levels.id
+LIMIT sqlc.arg(row_limit);
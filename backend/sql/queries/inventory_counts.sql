@@ -0,0 +1,93 @@
+-- name: CreateInventoryCount :one
+INSERT INTO inventory_counts (gid, tenant_id, store_id, location_id, name, created_by)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetInventoryCountByID :one
+SELECT * FROM inventory_counts
+WHERE id = $1 AND store_id = $2;
+
+-- name: ListInventoryCountsPaginated :many
+-- Newest first. A NULL status matches every count.
+SELECT * FROM inventory_counts
+WHERE store_id = sqlc.arg(store_id)
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+  AND (
+    NOT sqlc.arg(has_cursor)::boolean
+    OR (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: CloseInventoryCount :one
+-- Completes or cancels an open count. Returns no row once it is closed.
+UPDATE inventory_counts
+SET
+    status = sqlc.arg(status),
+    completed_by = sqlc.narg(completed_by),
+    completed_at = CASE WHEN sqlc.arg(status) = 'completed' THEN now() ELSE NULL END,
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND status = 'open'
+RETURNING *;
+
+-- name: TouchInventoryCount :execrows
+-- Locks an open count while its lines change. Affects no row once it is
+-- closed.
+UPDATE inventory_counts SET updated_at = now()
+WHERE id = $1 AND status = 'open';
+
+-- name: GetCountableVariants :many
+-- Variants of the store with one of ids, a barcode in codes, or a SKU in
+-- skus, which are given in lower case
+SELECT id, sku, barcode
+FROM product_variants
+WHERE store_id = sqlc.arg(store_id)
+  AND deleted_at IS NULL
+  AND (
+    id = ANY(sqlc.arg(ids)::uuid[])
+    OR barcode = ANY(sqlc.arg(codes)::text[])
+    OR lower(sku) = ANY(sqlc.arg(skus)::text[])
+  );
+
+-- name: GetStockAtLocation :many
+SELECT inventory_items.variant_id, inventory_levels.on_hand
+FROM inventory_items
+JOIN inventory_levels ON inventory_levels.inventory_item_id = inventory_items.id
+WHERE inventory_items.variant_id = ANY(sqlc.arg(variant_ids)::uuid[])
+  AND inventory_levels.location_id = sqlc.arg(location_id);
+
+-- name: UpsertInventoryCountLine :exec
+-- Counting a variant again replaces its count and what was expected
+INSERT INTO inventory_count_lines (count_id, variant_id, expected, counted, counted_by)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (count_id, variant_id) DO UPDATE
+SET expected = EXCLUDED.expected,
+    counted = EXCLUDED.counted,
+    counted_by = EXCLUDED.counted_by,
+    counted_at = now();
+
+-- name: DeleteInventoryCountLine :execrows
+DELETE FROM inventory_count_lines
+WHERE count_id = $1 AND variant_id = $2;
+
+-- name: GetInventoryCountLines :many
+-- Lines by product and variant, with the stock at the location now
+SELECT
+    inventory_count_lines.variant_id,
+    inventory_count_lines.expected,
+    inventory_count_lines.counted,
+    inventory_count_lines.counted_by,
+    inventory_count_lines.counted_at,
+    products.name AS product_name,
+    product_variants.title AS variant_title,
+    product_variants.sku,
+    COALESCE(inventory_levels.on_hand, 0)::integer AS on_hand
+FROM inventory_count_lines
+JOIN inventory_counts ON inventory_counts.id = inventory_count_lines.count_id
+JOIN product_variants ON product_variants.id = inventory_count_lines.variant_id
+JOIN products ON products.id = product_variants.product_id
+LEFT JOIN inventory_items ON inventory_items.variant_id = inventory_count_lines.variant_id
+LEFT JOIN inventory_levels ON inventory_levels.inventory_item_id = inventory_items.id
+    AND inventory_levels.location_id = inventory_counts.location_id
+WHERE inventory_count_lines.count_id = $1
+ORDER BY products.name, product_variants.title, inventory_count_lines.variant_id;
@@ -0,0 +1,77 @@
+-- name: CreateInventoryLocation :one
+INSERT INTO inventory_locations (id, tenant_id, name, kind, address, is_default, active, created_at, updated_at)
+VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, TRUE, now(), now())
+RETURNING *;
+
+-- name: EnsureDefaultInventoryLocation :one
+INSERT INTO inventory_locations (id, tenant_id, name, kind, is_default, active, created_at, updated_at)
+VALUES (gen_random_uuid(), $1, 'Default location', 'warehouse', TRUE, TRUE, now(), now())
+ON CONFLICT (tenant_id) WHERE is_default DO UPDATE
+SET is_default = TRUE
+RETURNING *;
+
+-- name: GetInventoryLocationByID :one
+SELECT * FROM inventory_locations
+WHERE id = $1 AND tenant_id = $2;
+
+-- name: GetInventoryLocationsByTenant :many
+SELECT * FROM inventory_locations
+WHERE tenant_id = $1
+ORDER BY is_default DESC, name ASC;
+
+-- name: UpdateInventoryLocation :one
+UPDATE inventory_locations
+SET
+    name = $3,
+    kind = $4,
+    address = $5,
+    active = $6,
+    updated_at = now()
+WHERE id = $1 AND tenant_id = $2
+RETURNING *;
+
+-- name: DeleteInventoryLocation :exec
+DELETE FROM inventory_locations
+WHERE id = $1 AND tenant_id = $2 AND is_default = FALSE;
+
+-- name: CountStockAtLocation :one
+SELECT COUNT(*) FROM inventory_levels
+WHERE location_id = $1 AND on_hand > 0;
+
+-- name: EnsureInventoryLevel :one
+INSERT INTO inventory_levels (id, tenant_id, inventory_item_id, location_id, on_hand, created_at, updated_at)
+VALUES (gen_random_uuid(), $1, $2, $3, 0, now(), now())
+ON CONFLICT (inventory_item_id, location_id) DO UPDATE
+SET location_id = EXCLUDED.location_id
+RETURNING *;
+
+-- name: AdjustInventoryLevel :one
+-- Returns no row when the adjustment would take stock at the location below zero
+UPDATE inventory_levels
+SET
+    on_hand = on_hand + sqlc.arg(delta)::integer,
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND on_hand + sqlc.arg(delta)::integer >= 0
+RETURNING *;
+
+-- name: GetInventoryLevelsByItem :many
+SELECT inventory_levels.*, inventory_locations.name AS location_name
+FROM inventory_levels
+JOIN inventory_locations ON inventory_locations.id = inventory_levels.location_id
+WHERE inventory_levels.inventory_item_id = $1
+ORDER BY inventory_locations.is_default DESC, inventory_locations.name ASC;
+
+-- name: GetProductAvailabilityByLocation :many
+SELECT
+    product_variants.product_id,
+    inventory_locations.id AS location_id,
+    inventory_locations.name AS location_name,
+    SUM(inventory_levels.on_hand)::bigint AS on_hand
+FROM inventory_levels
+JOIN inventory_items ON inventory_items.id = inventory_levels.inventory_item_id
+JOIN product_variants ON product_variants.id = inventory_items.variant_id
+JOIN inventory_locations ON inventory_locations.id = inventory_levels.location_id
+WHERE product_variants.product_id = ANY(sqlc.arg(product_ids)::uuid[])
+  AND product_variants.deleted_at IS NULL
+GROUP BY product_variants.product_id, inventory_locations.id, inventory_locations.name
+ORDER BY product_variants.product_id, inventory_locations.name;
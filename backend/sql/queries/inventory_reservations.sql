@@ -0,0 +1,113 @@
+-- name: GetReservableVariants :many
+-- Active variants of active products, with how their stock is sold
+SELECT pv.id, pv.product_id, p.inventory_tracked, p.oversell_policy
+FROM product_variants pv
+JOIN products p ON p.id = pv.product_id
+WHERE pv.store_id = sqlc.arg(store_id)
+  AND pv.id = ANY(sqlc.arg(variant_ids)::uuid[])
+  AND pv.status = 'active'
+  AND pv.deleted_at IS NULL
+  AND p.status = 'active'
+  AND p.deleted_at IS NULL;
+
+-- name: LockInventoryItem :one
+-- Holds the row until the transaction ends, so what is available cannot
+-- change between reading it and reserving it
+SELECT * FROM inventory_items
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ReserveInventoryItem :one
+-- Returns no row when fewer than held units are available
+UPDATE inventory_items
+SET
+    reserved = reserved + sqlc.arg(held)::integer,
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND on_hand - reserved >= sqlc.arg(held)::integer
+RETURNING *;
+
+-- name: UnreserveInventoryItem :exec
+UPDATE inventory_items
+SET
+    reserved = GREATEST(reserved - sqlc.arg(held)::integer, 0),
+    updated_at = now()
+WHERE id = sqlc.arg(id);
+
+-- name: CreateInventoryReservation :one
+INSERT INTO inventory_reservations (gid, tenant_id, store_id, customer_id, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: CreateInventoryReservationItem :one
+INSERT INTO inventory_reservation_items (reservation_id, inventory_item_id, variant_id, quantity, held, backordered)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetInventoryReservationByID :one
+SELECT * FROM inventory_reservations
+WHERE id = $1 AND store_id = $2;
+
+-- name: GetInventoryReservationItems :many
+SELECT * FROM inventory_reservation_items
+WHERE reservation_id = $1
+ORDER BY variant_id;
+
+-- name: ListInventoryReservationsPaginated :many
+-- Newest first. A NULL status matches every reservation.
+SELECT * FROM inventory_reservations
+WHERE store_id = sqlc.arg(store_id)
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+  AND (
+    NOT sqlc.arg(has_cursor)::boolean
+    OR (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: CloseInventoryReservation :one
+-- Moves an active reservation to status. Returns no row once it is no
+-- longer active, so it is committed, released or expired only once.
+UPDATE inventory_reservations
+SET
+    status = sqlc.arg(status),
+    order_id = COALESCE(sqlc.narg(order_id)::uuid, order_id),
+    committed_at = CASE WHEN sqlc.arg(status) = 'committed' THEN now() ELSE committed_at END,
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND status = 'active'
+RETURNING *;
+
+-- name: ClaimExpiredInventoryReservation :one
+-- An active reservation past its expiry, skipping any another sweeper has
+-- locked
+SELECT id FROM inventory_reservations
+WHERE status = 'active' AND expires_at <= sqlc.arg(now)::timestamptz
+ORDER BY expires_at
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
+
+-- name: GetSellableInventoryLevels :many
+-- Stock of an item at active locations, the default first, locked for the
+-- rest of the transaction
+SELECT inventory_levels.*
+FROM inventory_levels
+JOIN inventory_locations ON inventory_locations.id = inventory_levels.location_id
+WHERE inventory_levels.inventory_item_id = $1
+  AND inventory_locations.active
+  AND inventory_levels.on_hand > 0
+ORDER BY inventory_locations.is_default DESC, inventory_levels.on_hand DESC, inventory_levels.id
+FOR UPDATE OF inventory_levels;
+
+-- name: GetReservedStockByProducts :many
+SELECT product_variants.product_id, SUM(inventory_items.reserved)::bigint AS reserved
+FROM inventory_items
+JOIN product_variants ON product_variants.id = inventory_items.variant_id
+WHERE product_variants.product_id = ANY(sqlc.arg(product_ids)::uuid[])
+  AND product_variants.deleted_at IS NULL
+GROUP BY product_variants.product_id;
+
+-- name: CountActiveReservationsByStore :many
+-- Unexpired active reservations per store, the carts metric
+SELECT store_id, COUNT(*) AS active
+FROM inventory_reservations
+WHERE status = 'active' AND expires_at > now()
+GROUP BY store_id;
@@ -0,0 +1,102 @@
+-- name: EnqueueJob :one
+INSERT INTO jobs (id, queue, kind, payload, status, attempts, max_attempts, run_at, created_at, updated_at)
+VALUES (gen_random_uuid(), $1, $2, $3, 'pending', 0, $4, $5, now(), now())
+RETURNING *;
+
+-- name: ClaimJobs :many
+UPDATE jobs
+SET
+    status = 'running',
+    attempts = attempts + 1,
+    locked_at = now(),
+    locked_by = sqlc.arg(locked_by),
+    updated_at = now()
+WHERE id IN (
+    SELECT j.id FROM jobs j
+    WHERE j.queue = sqlc.arg(queue)
+      AND j.kind = ANY(sqlc.arg(kinds)::text[])
+      AND j.status = 'pending'
+      AND j.run_at <= now()
+    ORDER BY j.run_at ASC
+    LIMIT sqlc.arg(row_limit)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteJob :execrows
+-- Only the worker holding the job can finish it. Once the job was released
+-- as stale and claimed again, only the new holder records the result.
+UPDATE jobs
+SET
+    status = 'completed',
+    locked_at = NULL,
+    locked_by = NULL,
+    last_error = NULL,
+    completed_at = now(),
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND status = 'running' AND locked_by = sqlc.arg(locked_by);
+
+-- name: RetryJob :execrows
+UPDATE jobs
+SET
+    status = 'pending',
+    run_at = sqlc.arg(run_at),
+    last_error = sqlc.arg(last_error),
+    locked_at = NULL,
+    locked_by = NULL,
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND status = 'running' AND locked_by = sqlc.arg(locked_by);
+
+-- name: MarkJobDead :execrows
+UPDATE jobs
+SET
+    status = 'dead',
+    last_error = sqlc.arg(last_error),
+    locked_at = NULL,
+    locked_by = NULL,
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND status = 'running' AND locked_by = sqlc.arg(locked_by);
+
+-- name: ReleaseStaleJobs :execrows
+-- Jobs whose worker stopped mid-run go back to pending, unless they have
+-- used their attempts: a job that kills or hangs its worker every time is
+-- dead-lettered rather than retried forever
+UPDATE jobs
+SET
+    status = CASE WHEN attempts >= max_attempts THEN 'dead' ELSE 'pending' END,
+    last_error = CASE WHEN attempts >= max_attempts THEN 'worker stopped before the job finished' ELSE last_error END,
+    locked_at = NULL,
+    locked_by = NULL,
+    updated_at = now()
+WHERE status = 'running'
+  AND locked_at < sqlc.arg(locked_before);
+
+-- name: GetJobByID :one
+SELECT * FROM jobs
+WHERE id = $1;
+
+-- name: GetDeadJobsByQueue :many
+SELECT * FROM jobs
+WHERE queue = $1 AND status = 'dead'
+ORDER BY updated_at DESC
+LIMIT $2;
+
+-- name: RequeueDeadJob :one
+UPDATE jobs
+SET
+    status = 'pending',
+    attempts = 0,
+    run_at = now(),
+    last_error = NULL,
+    updated_at = now()
+WHERE id = $1 AND status = 'dead'
+RETURNING *;
+
+-- name: DeleteCompletedJobsBefore :execrows
+DELETE FROM jobs
+WHERE status = 'completed'
+  AND completed_at < $1;
+
+-- name: CountDueJobs :one
+SELECT count(*) FROM jobs
+WHERE status = 'pending' AND run_at <= now();
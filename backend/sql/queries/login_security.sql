@@ -0,0 +1,59 @@
+-- name: GetLoginThrottle :one
+SELECT * FROM login_throttles WHERE key = $1;
+
+-- name: RecordLoginFailure :one
+-- A key that has gone the reset window without a failure starts again
+INSERT INTO login_throttles (key, failures, last_failed_at)
+VALUES (sqlc.arg(key), 1, now())
+ON CONFLICT (key) DO UPDATE
+SET failures = CASE
+        WHEN login_throttles.last_failed_at < sqlc.arg(reset_before)::timestamptz THEN 1
+        ELSE login_throttles.failures + 1
+    END,
+    last_failed_at = now()
+RETURNING *;
+
+-- name: LockLoginThrottle :exec
+UPDATE login_throttles SET locked_until = sqlc.arg(locked_until) WHERE key = sqlc.arg(key);
+
+-- name: ClearLoginThrottle :exec
+DELETE FROM login_throttles WHERE key = $1;
+
+-- name: PruneLoginThrottles :execrows
+DELETE FROM login_throttles
+WHERE key IN (
+    SELECT key FROM login_throttles
+    WHERE last_failed_at < sqlc.arg(before)
+      AND (locked_until IS NULL OR locked_until < now())
+    LIMIT sqlc.arg(row_limit)
+);
+
+-- name: CreateSecurityEvent :exec
+INSERT INTO security_events (id, kind, user_id, customer_id, store_id, email, ip, user_agent)
+VALUES (gen_random_uuid(), sqlc.arg(kind), sqlc.narg(user_id), sqlc.narg(customer_id),
+        sqlc.narg(store_id), sqlc.narg(email), sqlc.narg(ip), sqlc.narg(user_agent));
+
+-- name: ListSecurityEvents :many
+-- Newest first, of one user when user_id is given
+SELECT * FROM security_events
+WHERE (sqlc.narg(user_id)::uuid IS NULL OR user_id = sqlc.narg(user_id)::uuid)
+  AND (
+    sqlc.arg(has_cursor)::boolean = false
+    OR (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: PruneSecurityEvents :execrows
+DELETE FROM security_events
+WHERE id IN (
+    SELECT id FROM security_events
+    WHERE created_at < sqlc.arg(before)
+    LIMIT sqlc.arg(row_limit)
+);
+
+-- name: DeleteUserSecurityEvents :exec
+DELETE FROM security_events WHERE user_id = sqlc.arg(user_id)::uuid;
+
+-- name: DeleteCustomerSecurityEvents :exec
+DELETE FROM security_events WHERE customer_id = sqlc.arg(customer_id)::uuid;
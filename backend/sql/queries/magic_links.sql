@@ -0,0 +1,18 @@
+-- name: CreateMagicLinkToken :one
+INSERT INTO magic_link_tokens (id, user_id, token_hash, device_hash, expires_at, created_at)
+VALUES (gen_random_uuid(), $1, $2, $3, $4, now())
+RETURNING *;
+
+-- name: GetActiveMagicLinkToken :one
+-- Locks the token so two concurrent sign-ins cannot both use it
+SELECT * FROM magic_link_tokens
+WHERE token_hash = $1
+  AND used_at IS NULL
+  AND expires_at > now()
+FOR UPDATE;
+
+-- name: InvalidateMagicLinkTokens :exec
+-- Marks every outstanding link of a user as used
+UPDATE magic_link_tokens
+SET used_at = now()
+WHERE user_id = $1 AND used_at IS NULL;
@@ -0,0 +1,84 @@
+-- name: UpsertPendingUserMFA :one
+-- Starting enrollment again replaces a secret that was never confirmed
+INSERT INTO user_mfa (user_id, secret, created_at, updated_at)
+VALUES ($1, $2, now(), now())
+ON CONFLICT (user_id) DO UPDATE
+SET secret = EXCLUDED.secret, last_used_step = 0, updated_at = now()
+WHERE user_mfa.enabled_at IS NULL
+RETURNING *;
+
+-- name: GetUserMFA :one
+SELECT * FROM user_mfa WHERE user_id = $1;
+
+-- name: GetUserMFAForUpdate :one
+SELECT * FROM user_mfa WHERE user_id = $1 FOR UPDATE;
+
+-- name: EnableUserMFA :one
+UPDATE user_mfa
+SET enabled_at = now(), last_used_step = $2, updated_at = now()
+WHERE user_id = $1
+RETURNING *;
+
+-- name: UpdateUserMFALastStep :exec
+UPDATE user_mfa
+SET last_used_step = $2, updated_at = now()
+WHERE user_id = $1;
+
+-- name: DeleteUserMFA :exec
+DELETE FROM user_mfa WHERE user_id = $1;
+
+-- name: CreateMFARecoveryCode :exec
+INSERT INTO mfa_recovery_codes (id, user_id, code_hash, created_at)
+VALUES (gen_random_uuid(), $1, $2, now());
+
+-- name: DeleteMFARecoveryCodes :exec
+DELETE FROM mfa_recovery_codes WHERE user_id = $1;
+
+-- name: UseMFARecoveryCode :execrows
+UPDATE mfa_recovery_codes
+SET used_at = now()
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+
+-- name: CountUnusedMFARecoveryCodes :one
+SELECT COUNT(*) FROM mfa_recovery_codes
+WHERE user_id = $1 AND used_at IS NULL;
+
+-- name: CreateMFAChallenge :one
+INSERT INTO mfa_challenges (id, user_id, token_hash, expires_at, created_at)
+VALUES (gen_random_uuid(), $1, $2, $3, now())
+RETURNING *;
+
+-- name: GetActiveMFAChallenge :one
+SELECT * FROM mfa_challenges
+WHERE token_hash = $1
+  AND used_at IS NULL
+  AND expires_at > now()
+FOR UPDATE;
+
+-- name: IncrementMFAChallengeAttempts :one
+UPDATE mfa_challenges
+SET attempts = attempts + 1
+WHERE id = $1
+RETURNING *;
+
+-- name: MarkMFAChallengeUsed :exec
+UPDATE mfa_challenges
+SET used_at = now()
+WHERE id = $1;
+
+-- name: GetTenantMFAPolicyStatus :one
+-- Whether the tenant requires MFA and whether the user has it turned on
+SELECT
+    t.require_mfa,
+    EXISTS (
+        SELECT 1 FROM user_mfa m
+        WHERE m.user_id = sqlc.arg(user_id) AND m.enabled_at IS NOT NULL
+    )::boolean AS mfa_enabled
+FROM tenants t
+WHERE t.id = sqlc.arg(tenant_id);
+
+-- name: UpdateTenantRequireMFA :one
+UPDATE tenants
+SET require_mfa = $2, updated_at = now()
+WHERE id = $1
+RETURNING *;
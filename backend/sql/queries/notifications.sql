@@ -0,0 +1,79 @@
+-- name: CreateNotification :execrows
+-- Records nothing when the tenant has turned the kind off
+INSERT INTO notifications (tenant_id, store_id, kind, title, body, data)
+SELECT sqlc.arg(tenant_id)::uuid, sqlc.narg(store_id)::uuid, sqlc.arg(kind)::text,
+    sqlc.arg(title)::text, sqlc.arg(body)::text, sqlc.arg(data)::jsonb
+WHERE NOT EXISTS (
+    SELECT 1 FROM tenant_notification_preferences p
+    WHERE p.tenant_id = sqlc.arg(tenant_id)::uuid
+      AND p.kind = sqlc.arg(kind)::text
+      AND NOT p.enabled
+);
+
+-- name: ListNotifications :many
+-- Newest first, with the read time of user_id
+SELECT notifications.*, r.read_at
+FROM notifications
+LEFT JOIN notification_reads r ON r.notification_id = notifications.id AND r.user_id = sqlc.arg(user_id)
+WHERE notifications.tenant_id = sqlc.arg(tenant_id)
+  AND notifications.kind = ANY(sqlc.arg(kinds)::text[])
+  AND (sqlc.arg(unread_only)::boolean = false OR r.read_at IS NULL)
+  AND (sqlc.narg(store_id)::uuid IS NULL OR notifications.store_id = sqlc.narg(store_id)::uuid)
+  AND (
+    sqlc.arg(has_cursor)::boolean = false
+    OR (notifications.created_at, notifications.id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY notifications.created_at DESC, notifications.id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: CountUnreadNotifications :one
+SELECT count(*) FROM notifications
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND kind = ANY(sqlc.arg(kinds)::text[])
+  AND NOT EXISTS (
+    SELECT 1 FROM notification_reads r
+    WHERE r.notification_id = notifications.id AND r.user_id = sqlc.arg(user_id)
+  );
+
+-- name: GetNotification :one
+SELECT notifications.*, r.read_at
+FROM notifications
+LEFT JOIN notification_reads r ON r.notification_id = notifications.id AND r.user_id = sqlc.arg(user_id)
+WHERE notifications.id = sqlc.arg(id) AND notifications.tenant_id = sqlc.arg(tenant_id);
+
+-- name: MarkNotificationRead :exec
+INSERT INTO notification_reads (notification_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: MarkNotificationUnread :exec
+DELETE FROM notification_reads
+WHERE notification_id = $1 AND user_id = $2;
+
+-- name: MarkAllNotificationsRead :execrows
+INSERT INTO notification_reads (notification_id, user_id)
+SELECT notifications.id, sqlc.arg(user_id)::uuid
+FROM notifications
+WHERE notifications.tenant_id = sqlc.arg(tenant_id)
+  AND notifications.kind = ANY(sqlc.arg(kinds)::text[])
+ON CONFLICT DO NOTHING;
+
+-- name: GetNotificationPreferences :many
+SELECT * FROM tenant_notification_preferences
+WHERE tenant_id = $1
+ORDER BY kind;
+
+-- name: UpsertNotificationPreference :exec
+INSERT INTO tenant_notification_preferences (tenant_id, kind, enabled)
+VALUES ($1, $2, $3)
+ON CONFLICT (tenant_id, kind) DO UPDATE
+SET enabled = EXCLUDED.enabled,
+    updated_at = now();
+
+-- name: PruneNotifications :execrows
+DELETE FROM notifications
+WHERE id IN (
+    SELECT id FROM notifications
+    WHERE created_at < sqlc.arg(before)
+    LIMIT sqlc.arg(row_limit)
+);
@@ -0,0 +1,56 @@
+-- name: CreateOAuthState :exec
+INSERT INTO oauth_states (state_hash, provider, nonce, code_verifier, expires_at)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ConsumeOAuthState :one
+-- A state is good for one callback
+DELETE FROM oauth_states
+WHERE state_hash = $1 AND expires_at > now()
+RETURNING *;
+
+-- name: DeleteExpiredOAuthStates :exec
+DELETE FROM oauth_states WHERE expires_at <= now();
+
+-- name: GetUserIdentity :one
+SELECT * FROM user_identities
+WHERE provider = $1 AND subject = $2;
+
+-- name: CreateUserIdentity :one
+INSERT INTO user_identities (id, user_id, provider, subject, email, last_login_at)
+VALUES (gen_random_uuid(), $1, $2, $3, $4, now())
+RETURNING *;
+
+-- name: TouchUserIdentity :exec
+UPDATE user_identities SET last_login_at = now() WHERE id = $1;
+
+-- name: ListUserIdentities :many
+SELECT * FROM user_identities
+WHERE user_id = $1
+ORDER BY created_at, id;
+
+-- name: DeleteUserIdentities :exec
+DELETE FROM user_identities WHERE user_id = $1;
+
+-- name: CreateSSOUser :one
+-- The provider verified the email, and the account has no password until
+-- one is set through a reset
+INSERT INTO users (id, gid, created_at, updated_at, email, verified_at)
+VALUES (gen_random_uuid(), $1, now(), now(), $2, now())
+RETURNING *;
+
+-- name: UserRequiresSSO :one
+-- Whether any tenant the user is an active member of requires SSO
+SELECT EXISTS (
+    SELECT 1 FROM tenant_users tu
+    JOIN tenants t ON t.id = tu.tenant_id
+    WHERE tu.user_id = $1 AND tu.status = 'active' AND t.require_sso
+)::boolean;
+
+-- name: UpdateTenantRequireSSO :one
+UPDATE tenants
+SET require_sso = $2, updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteUserIdentitiesByProvider :exec
+DELETE FROM user_identities WHERE provider = $1;
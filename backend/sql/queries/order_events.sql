@@ -0,0 +1,16 @@
+-- name: CreateOrderEvent :one
+INSERT INTO order_events (id, order_id, store_id, kind, message, data, author_id, created_at)
+VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, now())
+RETURNING *;
+
+-- name: GetOrderEventsPaginated :many
+-- Newest first. An empty kinds filter returns every event.
+SELECT * FROM order_events
+WHERE order_id = sqlc.arg(order_id)
+  AND (COALESCE(cardinality(sqlc.arg(kinds)::text[]), 0) = 0 OR kind = ANY(sqlc.arg(kinds)::text[]))
+  AND (
+    NOT sqlc.arg(has_cursor)::boolean
+    OR (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
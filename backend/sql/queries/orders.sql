@@ -0,0 +1,91 @@
+-- name: GetOrderByID :one
+SELECT * FROM orders
+WHERE id = $1 AND store_id = $2;
+
+-- name: GetOrderByGID :one
+SELECT * FROM orders
+WHERE gid = $1;
+
+-- name: GetOrdersByCustomerPaginated :many
+SELECT * FROM orders
+WHERE customer_id = sqlc.arg(customer_id)
+  AND (
+    sqlc.arg(has_cursor)::boolean = false
+    OR (placed_at, id) < (sqlc.arg(cursor_placed_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY placed_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetOrderLineItems :many
+SELECT * FROM order_line_items
+WHERE order_id = $1
+ORDER BY created_at ASC, id ASC;
+
+-- name: UpdateOrderFinancialStatus :one
+UPDATE orders
+SET financial_status = $3, updated_at = now()
+WHERE id = $1 AND store_id = $2
+RETURNING *;
+
+-- name: LockOrderForUpdate :one
+SELECT * FROM orders
+WHERE id = $1 AND store_id = $2
+FOR UPDATE;
+
+-- name: UpdateOrderFulfillmentStatus :one
+UPDATE orders
+SET fulfillment_status = $3, updated_at = now()
+WHERE id = $1 AND store_id = $2
+RETURNING *;
+
+-- name: UpdateOrderTags :one
+UPDATE orders
+SET tags = $3, updated_at = now()
+WHERE id = $1 AND store_id = $2
+RETURNING *;
+
+-- name: ListFilteredOrders :many
+-- Store order listing, newest first. A NULL or empty filter matches
+-- everything. Orders must carry every requested tag.
+SELECT * FROM orders
+WHERE store_id = sqlc.arg(store_id)
+  AND tags @> COALESCE(sqlc.arg(tags)::text[], '{}')
+  AND (COALESCE(cardinality(sqlc.arg(statuses)::text[]), 0) = 0 OR status = ANY(sqlc.arg(statuses)::text[]))
+  AND (COALESCE(cardinality(sqlc.arg(financial_statuses)::text[]), 0) = 0 OR financial_status = ANY(sqlc.arg(financial_statuses)::text[]))
+  AND (COALESCE(cardinality(sqlc.arg(fulfillment_statuses)::text[]), 0) = 0 OR fulfillment_status = ANY(sqlc.arg(fulfillment_statuses)::text[]))
+  AND (
+    NOT sqlc.arg(has_cursor)::boolean
+    OR (placed_at, id) < (sqlc.arg(cursor_placed_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY placed_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetOrderTagsByStore :many
+SELECT tag::text AS tag, COUNT(*) AS count
+FROM orders
+CROSS JOIN LATERAL unnest(tags) AS tag
+WHERE store_id = $1
+GROUP BY tag
+ORDER BY count DESC, tag ASC;
+
+-- name: GetOrderByNumber :one
+SELECT * FROM orders
+WHERE store_id = $1 AND order_number = $2;
+
+-- name: CreateOrder :one
+-- Records an order as it was placed, with its totals already worked out.
+-- Orders brought over from another platform keep their number and date.
+INSERT INTO orders (
+    gid, tenant_id, store_id, customer_id, order_number, email, email_digest, status,
+    financial_status, fulfillment_status, currency, subtotal_cents, shipping_cents, tax_cents,
+    discount_cents, total_cents, tags, placed_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+RETURNING *;
+
+-- name: CreateOrderLineItem :one
+INSERT INTO order_line_items (
+    order_id, product_id, variant_id, title, variant_title, sku, quantity, price_cents, total_cents
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
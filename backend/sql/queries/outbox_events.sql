@@ -0,0 +1,24 @@
+-- name: ClaimOutboxEvents :many
+-- Locks the oldest unprocessed events of a topic. Run inside a transaction
+-- and mark the batch processed before committing.
+SELECT * FROM outbox_events
+WHERE topic = $1 AND processed_at IS NULL
+ORDER BY id ASC
+LIMIT $2
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkOutboxEventsProcessed :exec
+UPDATE outbox_events
+SET processed_at = now()
+WHERE id = ANY(sqlc.arg(ids)::bigint[]);
+
+-- name: PruneOutboxEvents :execrows
+DELETE FROM outbox_events
+WHERE processed_at IS NOT NULL AND processed_at < $1;
+
+-- name: EnqueueStoreProductReindex :execrows
+-- Queues every product of a store for reindexing, e.g. after switching engines
+INSERT INTO outbox_events (topic, aggregate_id, store_id, event_type)
+SELECT 'product', products.id, products.store_id, 'product.reindex'
+FROM products
+WHERE products.store_id = $1;
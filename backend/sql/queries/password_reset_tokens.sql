@@ -0,0 +1,18 @@
+-- name: CreatePasswordResetToken :one
+INSERT INTO password_reset_tokens (id, user_id, token_hash, expires_at, created_at)
+VALUES (gen_random_uuid(), $1, $2, $3, now())
+RETURNING *;
+
+-- name: GetActivePasswordResetToken :one
+-- Locks the token so two concurrent resets cannot both use it
+SELECT * FROM password_reset_tokens
+WHERE token_hash = $1
+  AND used_at IS NULL
+  AND expires_at > now()
+FOR UPDATE;
+
+-- name: InvalidatePasswordResetTokens :exec
+-- Marks every outstanding token of a user as used
+UPDATE password_reset_tokens
+SET used_at = now()
+WHERE user_id = $1 AND used_at IS NULL;
@@ -0,0 +1,136 @@
+-- name: GetUserPlatformRole :one
+SELECT email, platform_role FROM users WHERE id = $1;
+
+-- name: ListPlatformTenants :many
+-- Newest first. query matches the tenant name or ID, the name, handle or
+-- custom domain of one of its stores, or the email of one of its members.
+SELECT
+    t.id, t.name, t.status, t.suspended_at, t.suspension_reason, t.created_at,
+    (SELECT count(*) FROM stores s
+     WHERE s.tenant_id = t.id AND s.deleted_at IS NULL)::bigint AS store_count,
+    (SELECT count(*) FROM tenant_users tu
+     WHERE tu.tenant_id = t.id AND tu.status = 'active')::bigint AS member_count
+FROM tenants t
+WHERE (sqlc.narg(status)::text IS NULL OR t.status = sqlc.narg(status)::text)
+  AND (
+    sqlc.narg(query)::text IS NULL
+    OR strpos(lower(t.name), lower(sqlc.narg(query)::text)) > 0
+    OR t.id::text = lower(sqlc.narg(query)::text)
+    OR EXISTS (
+        SELECT 1 FROM stores s
+        LEFT JOIN custom_domains cd ON cd.store_id = s.id
+        WHERE s.tenant_id = t.id
+          AND (strpos(lower(s.name), lower(sqlc.narg(query)::text)) > 0
+               OR strpos(s.handle, lower(sqlc.narg(query)::text)) > 0
+               OR cd.domain = lower(sqlc.narg(query)::text))
+    )
+    OR EXISTS (
+        SELECT 1 FROM tenant_users tu
+        JOIN users u ON u.id = tu.user_id
+        WHERE tu.tenant_id = t.id AND strpos(lower(u.email), lower(sqlc.narg(query)::text)) > 0
+    )
+  )
+  AND (
+    sqlc.arg(has_cursor)::boolean = false
+    OR (t.created_at, t.id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY t.created_at DESC, t.id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetPlatformTenant :one
+SELECT
+    t.id, t.name, t.status, t.suspended_at, t.suspension_reason, t.created_at,
+    (SELECT count(*) FROM stores s
+     WHERE s.tenant_id = t.id AND s.deleted_at IS NULL)::bigint AS store_count,
+    (SELECT count(*) FROM tenant_users tu
+     WHERE tu.tenant_id = t.id AND tu.status = 'active')::bigint AS member_count
+FROM tenants t
+WHERE t.id = $1;
+
+-- name: ListPlatformStores :many
+-- By handle. query matches the store name or handle, or one of its custom
+-- domains. Deleted stores are listed too, as support may be asked about
+-- them.
+SELECT
+    s.id, s.tenant_id, s.name, s.handle, s.status, s.plan, s.deleted_at, s.created_at,
+    t.name AS tenant_name, t.status AS tenant_status
+FROM stores s
+LEFT JOIN tenants t ON t.id = s.tenant_id
+WHERE (sqlc.narg(tenant_id)::uuid IS NULL OR s.tenant_id = sqlc.narg(tenant_id)::uuid)
+  AND (sqlc.narg(status)::text IS NULL OR s.status = sqlc.narg(status)::text)
+  AND (
+    sqlc.narg(query)::text IS NULL
+    OR strpos(lower(s.name), lower(sqlc.narg(query)::text)) > 0
+    OR strpos(s.handle, lower(sqlc.narg(query)::text)) > 0
+    OR EXISTS (
+        SELECT 1 FROM custom_domains cd
+        WHERE cd.store_id = s.id AND cd.domain = lower(sqlc.narg(query)::text)
+    )
+  )
+  AND (sqlc.arg(has_cursor)::boolean = false OR s.handle > sqlc.arg(cursor_handle)::text)
+ORDER BY s.handle
+LIMIT sqlc.arg(row_limit);
+
+-- name: SuspendTenant :one
+-- Suspending again only updates the reason
+UPDATE tenants
+SET status = 'suspended',
+    suspended_at = COALESCE(suspended_at, now()),
+    suspension_reason = sqlc.arg(reason),
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND status IN ('active', 'inactive', 'suspended')
+RETURNING *;
+
+-- name: UnsuspendTenant :one
+UPDATE tenants
+SET status = 'active', suspended_at = NULL, suspension_reason = NULL, updated_at = now()
+WHERE id = $1 AND status = 'suspended'
+RETURNING *;
+
+-- name: CreatePlatformAuditLogEntry :exec
+INSERT INTO platform_audit_log (id, actor_user_id, actor_email, action, tenant_id, reason, request_id, ip)
+VALUES (gen_random_uuid(), sqlc.arg(actor_user_id), sqlc.arg(actor_email), sqlc.arg(action),
+        sqlc.narg(tenant_id), sqlc.arg(reason), sqlc.narg(request_id), sqlc.narg(ip));
+
+-- name: ListPlatformAuditLog :many
+-- Newest first, of one tenant when tenant_id is given
+SELECT * FROM platform_audit_log
+WHERE (sqlc.narg(tenant_id)::uuid IS NULL OR tenant_id = sqlc.narg(tenant_id)::uuid)
+  AND (
+    sqlc.arg(has_cursor)::boolean = false
+    OR (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListPlatformUsers :many
+-- Newest first. query matches part of the email or display name, or the
+-- whole ID. tenant_id keeps members of that tenant, in any status.
+SELECT
+    u.id, u.email, u.display_name, u.verified_at, u.platform_role, u.erased_at, u.created_at,
+    (SELECT count(*) FROM tenant_users tu
+     WHERE tu.user_id = u.id AND tu.status = 'active')::bigint AS tenant_count
+FROM users u
+WHERE (
+    sqlc.narg(status)::text IS NULL
+    OR (sqlc.narg(status)::text = 'erased') = (u.erased_at IS NOT NULL)
+  )
+  AND (
+    sqlc.narg(query)::text IS NULL
+    OR strpos(lower(u.email), lower(sqlc.narg(query)::text)) > 0
+    OR strpos(lower(coalesce(u.display_name, '')), lower(sqlc.narg(query)::text)) > 0
+    OR u.id::text = lower(sqlc.narg(query)::text)
+  )
+  AND (
+    sqlc.narg(tenant_id)::uuid IS NULL
+    OR EXISTS (
+        SELECT 1 FROM tenant_users tu
+        WHERE tu.user_id = u.id AND tu.tenant_id = sqlc.narg(tenant_id)::uuid
+    )
+  )
+  AND (
+    sqlc.arg(has_cursor)::boolean = false
+    OR (u.created_at, u.id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY u.created_at DESC, u.id DESC
+LIMIT sqlc.arg(row_limit);
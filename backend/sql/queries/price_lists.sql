@@ -0,0 +1,71 @@
+-- name: CreatePriceList :one
+INSERT INTO price_lists (gid, tenant_id, store_id, name)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetPriceListByID :one
+SELECT * FROM price_lists
+WHERE id = $1 AND store_id = $2;
+
+-- name: ListPriceLists :many
+SELECT * FROM price_lists
+WHERE store_id = $1
+ORDER BY name ASC, id ASC;
+
+-- name: UpdatePriceList :one
+UPDATE price_lists
+SET name = $3,
+    updated_at = now()
+WHERE id = $1 AND store_id = $2
+RETURNING *;
+
+-- name: DeletePriceList :execrows
+DELETE FROM price_lists
+WHERE id = $1 AND store_id = $2;
+
+-- name: ListPriceListGroupsByStore :many
+SELECT price_list_groups.price_list_id, price_list_groups.group_id
+FROM price_list_groups
+JOIN price_lists ON price_lists.id = price_list_groups.price_list_id
+WHERE price_lists.store_id = $1
+ORDER BY price_list_groups.group_id;
+
+-- name: ClearPriceListGroups :exec
+DELETE FROM price_list_groups
+WHERE price_list_id = $1;
+
+-- name: AddPriceListGroup :exec
+INSERT INTO price_list_groups (price_list_id, group_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: ListPriceListEntries :many
+SELECT * FROM price_list_entries
+WHERE price_list_id = $1
+ORDER BY created_at ASC, variant_id ASC;
+
+-- name: UpsertPriceListEntry :one
+INSERT INTO price_list_entries (price_list_id, variant_id, price_cents, percent_off)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (price_list_id, variant_id) DO UPDATE
+SET price_cents = EXCLUDED.price_cents,
+    percent_off = EXCLUDED.percent_off,
+    updated_at = now()
+RETURNING *;
+
+-- name: DeletePriceListEntry :one
+DELETE FROM price_list_entries
+WHERE price_list_id = $1 AND variant_id = $2
+RETURNING *;
+
+-- name: GetCustomerPriceListEntries :many
+-- The entries for the given variants on every price list assigned to a
+-- group the customer is in
+SELECT DISTINCT e.price_list_id, e.variant_id, e.price_cents, e.percent_off
+FROM price_list_entries e
+JOIN price_lists pl ON pl.id = e.price_list_id
+JOIN price_list_groups plg ON plg.price_list_id = e.price_list_id
+JOIN customer_group_members m ON m.group_id = plg.group_id
+WHERE pl.store_id = sqlc.arg(store_id)
+  AND m.customer_id = sqlc.arg(customer_id)
+  AND e.variant_id = ANY (sqlc.arg(variant_ids)::uuid[]);
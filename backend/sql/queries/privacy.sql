@@ -0,0 +1,234 @@
+-- name: CreatePrivacyRequest :one
+INSERT INTO privacy_requests (kind, store_id, customer_id, user_id, requested_by)
+VALUES (
+    sqlc.arg(kind),
+    sqlc.narg(store_id),
+    sqlc.narg(customer_id),
+    sqlc.narg(user_id),
+    sqlc.narg(requested_by)
+)
+RETURNING *;
+
+-- name: GetCustomerPrivacyRequest :one
+SELECT * FROM privacy_requests
+WHERE id = $1 AND store_id = $2 AND customer_id = $3;
+
+-- name: GetUserPrivacyRequest :one
+SELECT * FROM privacy_requests
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetPrivacyRequestForProcessing :one
+SELECT * FROM privacy_requests
+WHERE id = $1;
+
+-- name: StartPrivacyRequest :exec
+UPDATE privacy_requests
+SET status = 'running', started_at = COALESCE(started_at, now())
+WHERE id = $1;
+
+-- name: CompletePrivacyRequest :exec
+UPDATE privacy_requests
+SET status = 'completed',
+    storage_key = sqlc.narg(storage_key),
+    size_bytes = sqlc.narg(size_bytes),
+    finished_at = now()
+WHERE id = sqlc.arg(id);
+
+-- name: FailPrivacyRequest :exec
+UPDATE privacy_requests
+SET status = 'failed', error = $2, finished_at = now()
+WHERE id = $1;
+
+-- name: ListPrivacyExportKeys :many
+-- Archives already written for a subject, which erasure deletes
+SELECT id, storage_key::text AS storage_key FROM privacy_requests
+WHERE kind = 'export'
+  AND storage_key IS NOT NULL
+  AND (customer_id = sqlc.narg(customer_id) OR user_id = sqlc.narg(user_id));
+
+-- name: ClearPrivacyExportKey :exec
+UPDATE privacy_requests
+SET storage_key = NULL
+WHERE id = $1;
+
+-- name: ListCustomerPrivacyOrders :many
+-- A customer places orders signed in or as a guest with the same email.
+-- Guest orders not yet resealed are matched on the plain email.
+SELECT * FROM orders
+WHERE store_id = sqlc.arg(store_id)
+  AND (
+    customer_id = sqlc.arg(customer_id)
+    OR (customer_id IS NULL AND email_digest = sqlc.arg(email_digest))
+    OR (customer_id IS NULL AND email_digest = '' AND lower(email) = lower(sqlc.arg(email)::text))
+  )
+ORDER BY placed_at ASC, id ASC;
+
+-- name: CountUnfulfilledOrders :one
+SELECT count(*) FROM orders
+WHERE id = ANY(sqlc.arg(order_ids)::uuid[])
+  AND status = 'open'
+  AND fulfillment_status <> 'fulfilled';
+
+-- name: ListOrdersLineItems :many
+SELECT * FROM order_line_items
+WHERE order_id = ANY(sqlc.arg(order_ids)::uuid[])
+ORDER BY order_id, created_at ASC, id ASC;
+
+-- name: ListCustomerReviews :many
+SELECT * FROM product_reviews
+WHERE customer_id = $1
+ORDER BY created_at ASC, id ASC;
+
+-- name: ListCustomerGiftCards :many
+SELECT * FROM gift_cards
+WHERE customer_id = $1
+ORDER BY created_at ASC, id ASC;
+
+-- name: EraseCustomer :one
+UPDATE customers
+SET email = sqlc.arg(email),
+    email_digest = sqlc.arg(email_digest),
+    hashed_password = NULL,
+    first_name = NULL,
+    last_name = NULL,
+    phone = NULL,
+    accepts_marketing = false,
+    status = 'disabled',
+    tags = '{}',
+    erased_at = now(),
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteCustomerAddresses :exec
+DELETE FROM customer_addresses
+WHERE customer_id = $1;
+
+-- name: DeleteCustomerRefreshTokens :exec
+DELETE FROM customer_refresh_tokens
+WHERE customer_id = $1;
+
+-- name: DeleteCustomerGroupMemberships :exec
+DELETE FROM customer_group_members
+WHERE customer_id = $1;
+
+-- name: EraseOrdersContact :exec
+-- Orders stay for the books, without who placed them or where they went
+UPDATE orders
+SET email = NULL, email_digest = '', shipping_address = NULL, billing_address = NULL, updated_at = now()
+WHERE id = ANY(sqlc.arg(order_ids)::uuid[]);
+
+-- name: EraseCustomerReviews :exec
+UPDATE product_reviews
+SET author_name = sqlc.arg(author_name), updated_at = now()
+WHERE customer_id = sqlc.arg(customer_id);
+
+-- name: CancelCustomerSubscriptions :exec
+-- The payment method is forgotten too, so nothing is billed again
+UPDATE subscription_contracts
+SET status = 'cancelled',
+    cancelled_at = COALESCE(cancelled_at, now()),
+    payment_method_ref = '',
+    updated_at = now()
+WHERE customer_id = $1;
+
+-- name: EraseAuditLogEntities :exec
+-- Entries stay but lose the snapshots of the entities, which hold the data
+UPDATE audit_log
+SET before = NULL, after = NULL, changes = NULL
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND entity_id = ANY(sqlc.arg(entity_ids)::uuid[]);
+
+-- name: EraseUserAuditLogEntities :exec
+-- The user and their memberships, across every tenant
+UPDATE audit_log
+SET before = NULL, after = NULL, changes = NULL
+WHERE entity_id = $1
+   OR entity_id IN (SELECT id FROM tenant_users WHERE user_id = $1);
+
+-- name: ListAllUserSessions :many
+SELECT * FROM sessions
+WHERE user_id = $1
+ORDER BY created_at ASC, id ASC;
+
+-- name: ListUserMemberships :many
+SELECT
+    tu.tenant_id,
+    t.name AS tenant_name,
+    tu.status,
+    tu.created_at,
+    COALESCE(array_agg(r.name ORDER BY r.name) FILTER (WHERE r.id IS NOT NULL), '{}')::text[] AS roles
+FROM tenant_users tu
+JOIN tenants t ON t.id = tu.tenant_id
+LEFT JOIN tenant_user_roles tur ON tur.tenant_user_id = tu.id
+LEFT JOIN roles r ON r.id = tur.role_id
+WHERE tu.user_id = $1
+GROUP BY tu.id, t.name
+ORDER BY tu.created_at ASC, tu.id ASC;
+
+-- name: ListTenantsSolelyOwnedByUser :many
+-- Tenants the user is the only active owner of, which erasing them would
+-- leave without one
+SELECT tu.tenant_id FROM tenant_users tu
+JOIN tenant_user_roles tur ON tur.tenant_user_id = tu.id
+JOIN roles r ON r.id = tur.role_id AND r.is_system AND r.name = sqlc.arg(owner_role)
+WHERE tu.user_id = sqlc.arg(user_id)
+  AND tu.status = 'active'
+  AND NOT EXISTS (
+    SELECT 1 FROM tenant_users other
+    JOIN tenant_user_roles otur ON otur.tenant_user_id = other.id
+    JOIN roles orole ON orole.id = otur.role_id AND orole.is_system AND orole.name = sqlc.arg(owner_role)
+    WHERE other.tenant_id = tu.tenant_id
+      AND other.user_id <> tu.user_id
+      AND other.status = 'active'
+  );
+
+-- name: EraseUser :one
+-- No password can match the one left behind
+UPDATE users
+SET email = sqlc.arg(email),
+    hashed_password = 'erased',
+    display_name = NULL,
+    avatar_key = NULL,
+    verified_at = NULL,
+    platform_role = NULL,
+    erased_at = now(),
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteUserMemberships :exec
+DELETE FROM tenant_users
+WHERE user_id = $1;
+
+-- name: DeleteUserSessions :exec
+-- Their refresh tokens go with them
+DELETE FROM sessions
+WHERE user_id = $1;
+
+-- name: DeleteUserPasswordResetTokens :exec
+DELETE FROM password_reset_tokens
+WHERE user_id = $1;
+
+-- name: DeleteUserMagicLinkTokens :exec
+DELETE FROM magic_link_tokens
+WHERE user_id = $1;
+
+-- name: DeleteUserEmailVerificationTokens :exec
+DELETE FROM email_verification_tokens
+WHERE user_id = $1;
+
+-- name: DeleteUserMFAChallenges :exec
+DELETE FROM mfa_challenges
+WHERE user_id = $1;
+
+-- name: EraseInvitationEmails :exec
+UPDATE tenant_invitations
+SET email = sqlc.arg(erased_email),
+    revoked_at = COALESCE(revoked_at, CASE WHEN accepted_at IS NULL THEN now() END)
+WHERE lower(email) = lower(sqlc.arg(email)::text);
+
+-- name: ErasePlatformAuditLogActor :exec
+UPDATE platform_audit_log
+SET actor_email = sqlc.arg(erased_email)
+WHERE actor_user_id = sqlc.arg(actor_user_id);
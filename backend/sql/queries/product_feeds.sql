@@ -0,0 +1,113 @@
+-- name: CreateProductFeed :one
+INSERT INTO product_feeds (gid, tenant_id, store_id, channel_id, name, format, link_template, refresh_minutes, token)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: GetProductFeedByID :one
+SELECT * FROM product_feeds
+WHERE id = $1 AND store_id = $2;
+
+-- name: GetProductFeedByToken :one
+-- Feeds of deleted stores stop being served
+SELECT product_feeds.* FROM product_feeds
+JOIN stores ON stores.id = product_feeds.store_id
+WHERE product_feeds.token = $1 AND stores.deleted_at IS NULL;
+
+-- name: ListProductFeeds :many
+SELECT * FROM product_feeds
+WHERE store_id = $1
+ORDER BY name ASC, id ASC;
+
+-- name: UpdateProductFeed :one
+UPDATE product_feeds
+SET name = $3,
+    link_template = $4,
+    refresh_minutes = $5,
+    updated_at = now()
+WHERE id = $1 AND store_id = $2
+RETURNING *;
+
+-- name: RotateProductFeedToken :one
+UPDATE product_feeds
+SET token = $3,
+    updated_at = now()
+WHERE id = $1 AND store_id = $2
+RETURNING *;
+
+-- name: RefreshProductFeed :one
+-- Regenerates the feed on the next worker pass
+UPDATE product_feeds
+SET next_run_at = now(),
+    updated_at = now()
+WHERE id = $1 AND store_id = $2
+RETURNING *;
+
+-- name: DeleteProductFeed :execrows
+DELETE FROM product_feeds
+WHERE id = $1 AND store_id = $2;
+
+-- name: ClaimDueProductFeed :one
+-- Takes the feed due longest and schedules its next run up front, so a
+-- worker that dies mid run only delays it
+UPDATE product_feeds
+SET next_run_at = sqlc.arg(now)::timestamptz + make_interval(mins => product_feeds.refresh_minutes)
+WHERE product_feeds.id = (
+    SELECT pf.id FROM product_feeds pf
+    JOIN stores s ON s.id = pf.store_id
+    WHERE pf.next_run_at <= sqlc.arg(now)::timestamptz AND s.deleted_at IS NULL
+    ORDER BY pf.next_run_at ASC
+    LIMIT 1
+    FOR UPDATE OF pf SKIP LOCKED
+)
+RETURNING *;
+
+-- name: RecordProductFeedGenerated :exec
+UPDATE product_feeds
+SET storage_key = $2,
+    item_count = $3,
+    size_bytes = $4,
+    generated_at = now(),
+    last_error = NULL
+WHERE id = $1;
+
+-- name: RecordProductFeedFailure :exec
+UPDATE product_feeds
+SET last_error = $2,
+    next_run_at = $3
+WHERE id = $1;
+
+-- name: ListProductFeedItems :many
+-- One row per active variant with a price and an image, of active
+-- products published to the channel, in product then variant order. The
+-- image is the first one of the variant, else the first of the product.
+SELECT p.id AS product_id, p.handle, p.name, p.description,
+       v.id AS variant_id, v.title, v.sku, v.barcode, v.price_cents, v.compare_at_cents,
+       (
+           NOT p.inventory_tracked
+           OR EXISTS (SELECT 1 FROM inventory_items ii WHERE ii.variant_id = v.id AND ii.on_hand > 0)
+       )::boolean AS in_stock,
+       (
+           SELECT pm.storage_key FROM product_media pm
+           WHERE pm.product_id = p.id AND pm.status = 'ready'
+             AND (pm.variant_id IS NULL OR pm.variant_id = v.id)
+           ORDER BY (pm.variant_id IS NULL) ASC, pm.position ASC, pm.id ASC
+           LIMIT 1
+       )::text AS image_key
+FROM products p
+JOIN product_publications pub ON pub.product_id = p.id AND pub.channel_id = sqlc.arg(channel_id)
+JOIN product_variants v ON v.product_id = p.id AND v.status = 'active' AND v.deleted_at IS NULL
+WHERE p.store_id = sqlc.arg(store_id)
+  AND p.status = 'active'
+  AND p.deleted_at IS NULL
+  AND v.price_cents > 0
+  AND EXISTS (
+    SELECT 1 FROM product_media img
+    WHERE img.product_id = p.id AND img.status = 'ready'
+      AND (img.variant_id IS NULL OR img.variant_id = v.id)
+  )
+  AND (
+    NOT sqlc.arg(has_cursor)::boolean
+    OR (p.id, v.id) > (sqlc.arg(cursor_product_id)::uuid, sqlc.arg(cursor_variant_id)::uuid)
+  )
+ORDER BY p.id ASC, v.id ASC
+LIMIT sqlc.arg(row_limit);
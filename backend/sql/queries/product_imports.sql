@@ -0,0 +1,49 @@
+-- name: CreateProductImport :one
+INSERT INTO product_imports (store_id, created_by, filename, data, total_rows)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, store_id, created_by, filename, status, total_rows, error, created_at, started_at, finished_at;
+
+-- name: GetProductImport :one
+-- Leaves out the uploaded file, which only the worker needs
+SELECT id, store_id, created_by, filename, status, total_rows, error, created_at, started_at, finished_at
+FROM product_imports
+WHERE id = $1 AND store_id = $2;
+
+-- name: GetProductImportForProcessing :one
+SELECT * FROM product_imports
+WHERE id = $1;
+
+-- name: StartProductImport :exec
+UPDATE product_imports
+SET status = 'running', started_at = COALESCE(started_at, now())
+WHERE id = $1;
+
+-- name: FinishProductImport :exec
+UPDATE product_imports
+SET status = $2, error = $3, finished_at = now()
+WHERE id = $1;
+
+-- name: CreateProductImportRow :exec
+INSERT INTO product_import_rows (import_id, row_number, status, handle, sku, product_id, errors)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (import_id, row_number) DO NOTHING;
+
+-- name: ListProductImportRowNumbers :many
+SELECT row_number FROM product_import_rows
+WHERE import_id = $1;
+
+-- name: ListProductImportRows :many
+SELECT * FROM product_import_rows
+WHERE import_id = sqlc.arg(import_id)
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+  AND row_number > sqlc.arg(after_row)
+ORDER BY row_number
+LIMIT sqlc.arg(row_limit);
+
+-- name: CountProductImportRows :one
+SELECT
+    COUNT(*) FILTER (WHERE status = 'created')::int AS created,
+    COUNT(*) FILTER (WHERE status = 'updated')::int AS updated,
+    COUNT(*) FILTER (WHERE status = 'failed')::int AS failed
+FROM product_import_rows
+WHERE import_id = $1;
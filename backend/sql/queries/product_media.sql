@@ -0,0 +1,62 @@
+-- name: CreateProductMedia :one
+INSERT INTO product_media (
+    id, gid, tenant_id, store_id, product_id, variant_id, storage_key, content_type,
+    alt_text, position, status, created_at, updated_at
+)
+VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9,
+    (SELECT COALESCE(MAX(position), 0) + 1 FROM product_media WHERE product_id = $5),
+    'pending', now(), now()
+)
+RETURNING *;
+
+-- name: GetProductMediaByID :one
+SELECT * FROM product_media
+WHERE id = $1 AND product_id = $2;
+
+-- name: GetProductMediaByProduct :many
+SELECT * FROM product_media
+WHERE product_id = $1
+ORDER BY position ASC, id ASC;
+
+-- name: GetReadyMediaByProducts :many
+SELECT * FROM product_media
+WHERE product_id = ANY(sqlc.arg(product_ids)::uuid[]) AND status = 'ready'
+ORDER BY product_id, position ASC, id ASC;
+
+-- name: MarkProductMediaReady :one
+UPDATE product_media
+SET status = 'ready',
+    size_bytes = $3,
+    updated_at = now()
+WHERE id = $1 AND product_id = $2
+RETURNING *;
+
+-- name: UpdateProductMedia :one
+UPDATE product_media
+SET alt_text = $3,
+    variant_id = $4,
+    updated_at = now()
+WHERE id = $1 AND product_id = $2
+RETURNING *;
+
+-- name: SetProductMediaPosition :exec
+UPDATE product_media
+SET position = $3, updated_at = now()
+WHERE id = $1 AND product_id = $2;
+
+-- name: DeleteProductMedia :exec
+DELETE FROM product_media
+WHERE id = $1 AND product_id = $2;
+
+-- name: GetProductMediaForProcessing :one
+SELECT * FROM product_media
+WHERE id = $1;
+
+-- name: SetProductMediaThumbnails :exec
+UPDATE product_media
+SET width = $2,
+    height = $3,
+    thumbnail_widths = $4,
+    updated_at = now()
+WHERE id = $1;
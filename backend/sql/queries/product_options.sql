@@ -0,0 +1,13 @@
+-- name: ListProductOptions :many
+SELECT * FROM product_options
+WHERE product_id = $1
+ORDER BY position;
+
+-- name: DeleteProductOptions :exec
+DELETE FROM product_options
+WHERE product_id = $1;
+
+-- name: CreateProductOption :one
+INSERT INTO product_options (product_id, name, position, choices)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
@@ -0,0 +1,64 @@
+-- name: CreateProductReview :one
+INSERT INTO product_reviews (gid, store_id, product_id, customer_id, author_name, rating, title, body, verified_purchase)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: GetProductReviewByID :one
+SELECT * FROM product_reviews
+WHERE id = $1 AND store_id = $2;
+
+-- name: ListProductReviewsPaginated :many
+-- Moderation queue, newest first. A NULL filter matches everything.
+SELECT * FROM product_reviews
+WHERE store_id = sqlc.arg(store_id)
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+  AND (sqlc.narg(product_id)::uuid IS NULL OR product_id = sqlc.narg(product_id)::uuid)
+  AND (
+    NOT sqlc.arg(has_cursor)::boolean
+    OR (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListApprovedProductReviewsPaginated :many
+SELECT * FROM product_reviews
+WHERE product_id = sqlc.arg(product_id)
+  AND status = 'approved'
+  AND (
+    NOT sqlc.arg(has_cursor)::boolean
+    OR (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ModerateProductReview :one
+UPDATE product_reviews
+SET status = $3,
+    moderated_by = $4,
+    moderated_at = now(),
+    updated_at = now()
+WHERE id = $1 AND store_id = $2
+RETURNING *;
+
+-- name: DeleteProductReview :execrows
+DELETE FROM product_reviews
+WHERE id = $1 AND store_id = $2;
+
+-- name: GetProductRatings :many
+-- Approved review count and rating total of each product with any
+SELECT product_id, COUNT(*)::bigint AS review_count, SUM(rating)::bigint AS rating_total
+FROM product_reviews
+WHERE product_id = ANY (sqlc.arg(product_ids)::uuid[])
+  AND status = 'approved'
+GROUP BY product_id;
+
+-- name: CustomerPurchasedProduct :one
+-- Whether the customer has a paid order, not cancelled, with the product
+SELECT EXISTS (
+    SELECT 1 FROM orders o
+    JOIN order_line_items li ON li.order_id = o.id
+    WHERE o.customer_id = sqlc.arg(customer_id)::uuid
+      AND li.product_id = sqlc.arg(product_id)::uuid
+      AND o.status <> 'cancelled'
+      AND o.financial_status IN ('paid', 'partially_refunded')
+)::boolean AS purchased;
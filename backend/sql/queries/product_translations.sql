@@ -0,0 +1,31 @@
+-- name: ListProductTranslations :many
+SELECT * FROM product_translations
+WHERE product_id = $1
+ORDER BY locale;
+
+-- name: UpsertProductTranslation :one
+INSERT INTO product_translations (product_id, store_id, locale, name, description, handle)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (product_id, locale) DO UPDATE
+SET name = EXCLUDED.name,
+    description = EXCLUDED.description,
+    handle = EXCLUDED.handle,
+    updated_at = now()
+RETURNING *;
+
+-- name: DeleteProductTranslation :one
+DELETE FROM product_translations
+WHERE product_id = $1 AND locale = $2
+RETURNING *;
+
+-- name: ListStoreTranslationLocales :many
+-- The locales a store has any product content in
+SELECT DISTINCT locale FROM product_translations
+WHERE store_id = $1
+ORDER BY locale;
+
+-- name: GetProductTranslationsForLocale :many
+SELECT * FROM product_translations
+WHERE store_id = sqlc.arg(store_id)
+  AND locale = sqlc.arg(locale)
+  AND product_id = ANY (sqlc.arg(product_ids)::uuid[]);
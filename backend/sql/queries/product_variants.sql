@@ -1,52 +1,74 @@
 -- name: CreateProductVariant :one
 INSERT INTO product_variants (
     id, gid, tenant_id, store_id, product_id, sku, barcode, title,
-    price_cents, compare_at_cents, option_values, status, created_at, updated_at
+    price_cents, compare_at_cents, option_values, status, weight_grams, created_at, updated_at
 )
 VALUES (
-    gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, now(), now()
+    gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, now(), now()
 )
 RETURNING *;
 
 -- name: GetProductVariantByGID :one
 SELECT * FROM product_variants
-WHERE gid = $1;
+WHERE gid = $1 AND deleted_at IS NULL;
 
 -- name: GetProductVariantByID :one
 SELECT * FROM product_variants
-WHERE id = $1;
+WHERE id = $1 AND deleted_at IS NULL;
 
 -- name: GetProductVariantsByProductID :many
 SELECT * FROM product_variants
-WHERE product_id = $1
+WHERE product_id = $1 AND deleted_at IS NULL
 ORDER BY created_at ASC;
 
 -- name: GetProductVariantsByStoreID :many
 SELECT * FROM product_variants
-WHERE store_id = $1
+WHERE store_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC;
 
 -- name: GetProductVariantsByProductIDPaginated :many
 SELECT id, gid, tenant_id, store_id, product_id, sku, barcode, title,
-       price_cents, compare_at_cents, option_values, status, created_at, updated_at
+       price_cents, compare_at_cents, option_values, status, weight_grams, created_at, updated_at, version
 FROM product_variants
-WHERE product_id = $1
+WHERE product_id = sqlc.arg(product_id)
+  AND deleted_at IS NULL
   AND (
-    $2::boolean = false
-    OR (created_at, id) < ($3::timestamptz, $4::uuid)
+    sqlc.arg(has_cursor)::boolean = false
+    OR (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
   )
 ORDER BY created_at DESC, id DESC
-LIMIT $5;
+LIMIT sqlc.arg(row_limit);
 
 -- name: GetProductVariantBySKU :one
 SELECT * FROM product_variants
-WHERE store_id = $1 AND sku = $2;
+WHERE store_id = $1 AND sku = $2 AND deleted_at IS NULL;
 
 -- name: GetProductVariantByBarcode :one
 SELECT * FROM product_variants
-WHERE store_id = $1 AND barcode = $2;
+WHERE store_id = $1 AND barcode = $2 AND deleted_at IS NULL;
 
 -- name: UpdateProductVariant :one
+-- Sets only the fields given and keeps the rest, in one statement. With a
+-- version it only applies while the variant still has that version.
+UPDATE product_variants
+SET
+    sku = COALESCE(sqlc.narg(sku)::text, sku),
+    barcode = COALESCE(sqlc.narg(barcode)::text, barcode),
+    title = COALESCE(sqlc.narg(title)::text, title),
+    price_cents = COALESCE(sqlc.narg(price_cents)::integer, price_cents),
+    compare_at_cents = COALESCE(sqlc.narg(compare_at_cents)::integer, compare_at_cents),
+    option_values = COALESCE(sqlc.narg(option_values)::jsonb, option_values),
+    status = COALESCE(sqlc.narg(status)::text, status),
+    weight_grams = COALESCE(sqlc.narg(weight_grams)::integer, weight_grams),
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND product_id = sqlc.arg(product_id) AND store_id = sqlc.arg(store_id)
+  AND deleted_at IS NULL
+  AND (sqlc.narg(version)::bigint IS NULL OR version = sqlc.narg(version)::bigint)
+RETURNING *;
+
+-- name: ReplaceProductVariant :one
+-- Overwrites every field, clearing those left null, for writers that hold
+-- the whole variant such as imports
 UPDATE product_variants
 SET
     sku = $3,
@@ -56,13 +78,50 @@ SET
     compare_at_cents = $7,
     option_values = $8,
     status = $9,
+    weight_grams = $10,
     updated_at = now()
-WHERE id = $1 AND product_id = $2
+WHERE id = $1 AND product_id = $2 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SoftDeleteProductVariant :one
+UPDATE product_variants
+SET deleted_at = now()
+WHERE id = $1 AND product_id = $2 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RestoreProductVariant :one
+-- A variant of a deleted product comes back with its product, not alone
+UPDATE product_variants
+SET deleted_at = NULL, updated_at = now()
+WHERE id = $1 AND product_id = $2 AND deleted_at IS NOT NULL
+  AND EXISTS (SELECT 1 FROM products p WHERE p.id = $2 AND p.deleted_at IS NULL)
 RETURNING *;
 
--- name: DeleteProductVariant :exec
+-- name: GetDeletedProductVariant :one
+SELECT * FROM product_variants
+WHERE id = $1 AND product_id = $2 AND deleted_at IS NOT NULL;
+
+-- name: ListDeletedProductVariants :many
+-- Most recently deleted first
+SELECT * FROM product_variants
+WHERE product_id = sqlc.arg(product_id)
+  AND deleted_at IS NOT NULL
+  AND (
+    NOT sqlc.arg(has_cursor)::boolean
+    OR (deleted_at, id) < (sqlc.arg(cursor_deleted_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY deleted_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: PurgeDeletedProductVariants :execrows
+-- Permanently removes up to row_limit variants deleted before the cutoff
 DELETE FROM product_variants
-WHERE id = $1 AND product_id = $2;
+WHERE id IN (
+    SELECT id FROM product_variants
+    WHERE deleted_at < sqlc.arg(before)::timestamptz
+    ORDER BY deleted_at
+    LIMIT sqlc.arg(row_limit)
+);
 
 -- name: DeleteProductVariantsByProductID :exec
 DELETE FROM product_variants
@@ -95,9 +154,56 @@ SELECT
     pv.created_at as variant_created_at,
     pv.updated_at as variant_updated_at
 FROM products p
-LEFT JOIN product_variants pv ON p.id = pv.product_id
-WHERE p.id = $1 AND p.store_id = $2;
+LEFT JOIN product_variants pv ON p.id = pv.product_id AND pv.deleted_at IS NULL
+WHERE p.id = $1 AND p.store_id = $2 AND p.deleted_at IS NULL;
 
 -- name: CountProductVariantsByProductID :one
 SELECT COUNT(*) FROM product_variants
-WHERE product_id = $1;
+WHERE product_id = $1 AND deleted_at IS NULL;
+
+-- name: GetActiveVariantsForQuote :many
+SELECT pv.id, pv.product_id, pv.price_cents, COALESCE(pv.weight_grams, 0)::integer AS weight_grams
+FROM product_variants pv
+JOIN products p ON p.id = pv.product_id
+WHERE pv.store_id = sqlc.arg(store_id)
+  AND pv.id = ANY(sqlc.arg(variant_ids)::uuid[])
+  AND pv.status = 'active'
+  AND pv.deleted_at IS NULL
+  AND p.status = 'active'
+  AND p.deleted_at IS NULL;
+
+-- name: LookupVariantByCode :many
+-- Variants whose barcode is code or whose SKU is code in any case, barcode
+-- matches first, with their price and stock. location_id adds the stock at
+-- that location.
+SELECT
+    pv.id,
+    pv.product_id,
+    pv.sku,
+    pv.barcode,
+    pv.title,
+    pv.price_cents,
+    pv.compare_at_cents,
+    pv.status,
+    p.name AS product_name,
+    p.status AS product_status,
+    p.inventory_tracked,
+    s.default_currency AS currency,
+    (pv.barcode IS NOT NULL AND pv.barcode = sqlc.arg(code)::text)::boolean AS barcode_match,
+    COALESCE(ii.on_hand, 0)::integer AS on_hand,
+    COALESCE(ii.reserved, 0)::integer AS reserved,
+    il.on_hand AS location_on_hand
+FROM product_variants pv
+JOIN products p ON p.id = pv.product_id
+JOIN stores s ON s.id = pv.store_id
+LEFT JOIN inventory_items ii ON ii.variant_id = pv.id
+LEFT JOIN inventory_levels il ON il.inventory_item_id = ii.id AND il.location_id = sqlc.narg(location_id)::uuid
+WHERE pv.store_id = sqlc.arg(store_id)
+  AND pv.deleted_at IS NULL
+  AND p.deleted_at IS NULL
+  AND (
+    (pv.barcode = sqlc.arg(code)::text AND pv.barcode IS NOT NULL)
+    OR (lower(pv.sku) = lower(sqlc.arg(code)::text) AND pv.sku IS NOT NULL)
+  )
+ORDER BY barcode_match DESC, pv.id
+LIMIT 2;
@@ -5,12 +5,12 @@ RETURNING *;
 
 -- name: GetProductByGID :one
 SELECT * FROM products
-WHERE gid = $1;
+WHERE gid = $1 AND deleted_at IS NULL;
 
 
 -- name: GetProductsByStore :many
 SELECT * FROM products
-WHERE store_id = $1
+WHERE store_id = $1 AND deleted_at IS NULL
 ORDER BY created_at ASC;
 
 
@@ -19,13 +19,14 @@ ORDER BY created_at ASC;
 -- name: GetProductsByStorePaginated :many
 SELECT id, gid, store_id, handle, name, description, inventory_tracked, sku, tags, status, created_at, updated_at
 FROM products
-WHERE store_id = $1
+WHERE store_id = sqlc.arg(store_id)
+  AND deleted_at IS NULL
   AND (
-    $2::boolean = false
-    OR (created_at, id) < ($3::timestamptz, $4::uuid)
+    sqlc.arg(has_cursor)::boolean = false
+    OR (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
   )
 ORDER BY created_at DESC, id DESC
-LIMIT $5;
+LIMIT sqlc.arg(row_limit);
 
 
 
@@ -33,35 +34,367 @@ LIMIT $5;
 
 -- name: GetProductByHandle :one
 SELECT * FROM products
-WHERE store_id = $1 AND handle = $2;
+WHERE store_id = $1 AND handle = $2 AND deleted_at IS NULL;
+
+-- name: GetProductBySKU :one
+SELECT * FROM products
+WHERE store_id = $1 AND sku = $2 AND deleted_at IS NULL;
 
 
 -- name: UpdateProduct :one
+-- Sets only the fields given and keeps the rest, in one statement, so an
+-- update never overwrites a field another request changed meanwhile. With a
+-- version it only applies while the product still has that version, and
+-- with a status only while the product is in it or one of from_statuses.
 UPDATE products
-SET 
-    handle = $3,
-    name = $4,
-    description = $5,
-    inventory_tracked = $6,
-    sku = $7,
-    tags = $8,
-    status = $9,
+SET
+    handle = COALESCE(sqlc.narg(handle)::text, handle),
+    name = COALESCE(sqlc.narg(name)::text, name),
+    description = COALESCE(sqlc.narg(description)::text, description),
+    inventory_tracked = COALESCE(sqlc.narg(inventory_tracked)::boolean, inventory_tracked),
+    sku = COALESCE(sqlc.narg(sku)::text, sku),
+    tags = COALESCE(sqlc.narg(tags)::text, tags),
+    status = COALESCE(sqlc.narg(status)::text, status),
+    oversell_policy = COALESCE(sqlc.narg(oversell_policy)::text, oversell_policy),
     updated_at = NOW()
-WHERE id = $1 AND store_id = $2
-RETURNING *;    
+WHERE id = sqlc.arg(id) AND store_id = sqlc.arg(store_id) AND deleted_at IS NULL
+  AND (sqlc.narg(version)::bigint IS NULL OR version = sqlc.narg(version)::bigint)
+  AND (
+    sqlc.narg(status)::text IS NULL
+    OR status = sqlc.narg(status)::text
+    OR status = ANY(sqlc.arg(from_statuses)::text[])
+  )
+RETURNING *;
 
 
--- name: DeleteProduct :one
-DELETE FROM products
-WHERE id = $1 AND store_id = $2
+-- name: UpdateProductStatus :one
+-- Only applies while the product still has from_status, so a status change
+-- races with no other
+UPDATE products
+SET status = sqlc.arg(status), updated_at = NOW()
+WHERE id = sqlc.arg(id) AND store_id = sqlc.arg(store_id) AND status = sqlc.arg(from_status)
+  AND deleted_at IS NULL
 RETURNING *;
 
+-- name: ListProductHandlesWithPrefix :many
+-- The handle and any taken with a numeric suffix, such as shirt-2, for
+-- finding a free one. Deleted products keep their handles until purged.
+SELECT handle FROM products
+WHERE store_id = sqlc.arg(store_id)
+  AND (handle = sqlc.arg(handle)::text OR handle LIKE sqlc.arg(handle)::text || '-%');
+
+-- name: SoftDeleteProduct :one
+-- Hides the product and, by trigger, its variants until it is restored or
+-- purged
+UPDATE products
+SET deleted_at = NOW()
+WHERE id = $1 AND store_id = $2 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RestoreProduct :one
+UPDATE products
+SET deleted_at = NULL, updated_at = NOW()
+WHERE id = $1 AND store_id = $2 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: GetDeletedProduct :one
+SELECT * FROM products
+WHERE id = $1 AND store_id = $2 AND deleted_at IS NOT NULL;
+
+-- name: ListDeletedProducts :many
+-- Most recently deleted first
+SELECT * FROM products
+WHERE store_id = sqlc.arg(store_id)
+  AND deleted_at IS NOT NULL
+  AND (
+    NOT sqlc.arg(has_cursor)::boolean
+    OR (deleted_at, id) < (sqlc.arg(cursor_deleted_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY deleted_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: PurgeDeletedProducts :execrows
+-- Permanently removes up to row_limit products deleted before the cutoff,
+-- with their variants, media rows and inventory
+DELETE FROM products
+WHERE id IN (
+    SELECT id FROM products
+    WHERE deleted_at < sqlc.arg(before)::timestamptz
+    ORDER BY deleted_at
+    LIMIT sqlc.arg(row_limit)
+);
+
 -- name: GetProductByID :one
 SELECT * FROM products
-WHERE id = $1 AND store_id = $2;
+WHERE id = $1 AND store_id = $2 AND deleted_at IS NULL;
 
 -- name: GetProductByIDOnly :one
 SELECT * FROM products
-WHERE id = $1;
+WHERE id = $1 AND deleted_at IS NULL;
+
+
+
+-- name: SearchProductsByStore :many
+-- Ranks products matching a web-style search query (quoted phrases, OR, -term).
+-- An exact variant SKU match always ranks first. Pages are keyed on
+-- (rank, created_at, id) so cursor pagination stays stable while searching.
+SELECT p.*,
+    (
+        ts_rank(product_search_vector(p.name, p.description, p.tags, p.sku), websearch_to_tsquery('english', sqlc.arg(query)::text))
+        + CASE WHEN EXISTS (
+            SELECT 1 FROM product_variants v
+            WHERE v.product_id = p.id AND v.deleted_at IS NULL AND lower(v.sku) = lower(sqlc.arg(query)::text)
+        ) THEN 1 ELSE 0 END
+    )::real AS rank,
+    ts_headline('english', p.name, websearch_to_tsquery('english', sqlc.arg(query)::text),
+        'StartSel=<mark>, StopSel=</mark>, HighlightAll=true')::text AS name_highlight,
+    ts_headline('english', coalesce(p.description, ''), websearch_to_tsquery('english', sqlc.arg(query)::text),
+        'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5')::text AS description_snippet
+FROM products p
+WHERE p.store_id = sqlc.arg(store_id)
+  AND p.deleted_at IS NULL
+  AND (
+    product_search_vector(p.name, p.description, p.tags, p.sku) @@ websearch_to_tsquery('english', sqlc.arg(query)::text)
+    OR EXISTS (
+        SELECT 1 FROM product_variants v
+        WHERE v.product_id = p.id AND v.deleted_at IS NULL AND lower(v.sku) = lower(sqlc.arg(query)::text)
+    )
+  )
+  AND (
+    NOT sqlc.arg(has_cursor)::boolean
+    OR (
+        (
+            ts_rank(product_search_vector(p.name, p.description, p.tags, p.sku), websearch_to_tsquery('english', sqlc.arg(query)::text))
+            + CASE WHEN EXISTS (
+                SELECT 1 FROM product_variants v
+                WHERE v.product_id = p.id AND v.deleted_at IS NULL AND lower(v.sku) = lower(sqlc.arg(query)::text)
+            ) THEN 1 ELSE 0 END
+        )::real,
+        p.created_at,
+        p.id
+    ) < (sqlc.arg(cursor_rank)::real, sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY rank DESC, p.created_at DESC, p.id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetProductsByIDs :many
+SELECT * FROM products
+WHERE store_id = sqlc.arg(store_id) AND id = ANY(sqlc.arg(ids)::uuid[]) AND deleted_at IS NULL;
+
+-- name: GetProductSearchDocuments :many
+-- Everything a search engine indexes about each product.
+-- min_price_cents is only meaningful when active_variant_count > 0.
+-- Deleted products are left out, which drops them from the index.
+SELECT products.*,
+       COALESCE(array_agg(product_variants.sku) FILTER (WHERE product_variants.sku IS NOT NULL), '{}')::text[] AS variant_skus,
+       COUNT(product_variants.id) FILTER (WHERE product_variants.status = 'active')::bigint AS active_variant_count,
+       COALESCE(MIN(product_variants.price_cents) FILTER (WHERE product_variants.status = 'active'), 0)::bigint AS min_price_cents
+FROM products
+LEFT JOIN product_variants ON product_variants.product_id = products.id AND product_variants.deleted_at IS NULL
+WHERE products.id = ANY(sqlc.arg(ids)::uuid[]) AND products.deleted_at IS NULL
+GROUP BY products.id;
 
+-- name: ListFilteredProducts :many
+-- Product listing with optional filters. A NULL or empty filter matches
+-- everything. Products without inventory tracking are always in stock.
+-- The same filter block is repeated in every facet query below.
+SELECT p.* FROM products p
+WHERE p.store_id = sqlc.arg(store_id)
+  AND p.deleted_at IS NULL
+  AND (COALESCE(cardinality(sqlc.arg(statuses)::text[]), 0) = 0 OR p.status = ANY(sqlc.arg(statuses)::text[]))
+  AND product_tags(p.tags) @> COALESCE(sqlc.arg(tags)::text[], '{}')
+  AND (
+    (sqlc.narg(min_price_cents)::bigint IS NULL AND sqlc.narg(max_price_cents)::bigint IS NULL)
+    OR EXISTS (
+        SELECT 1 FROM product_variants pv
+        WHERE pv.product_id = p.id AND pv.status = 'active' AND pv.deleted_at IS NULL
+          AND (sqlc.narg(min_price_cents)::bigint IS NULL OR pv.price_cents >= sqlc.narg(min_price_cents)::bigint)
+          AND (sqlc.narg(max_price_cents)::bigint IS NULL OR pv.price_cents <= sqlc.narg(max_price_cents)::bigint)
+    )
+  )
+  AND (
+    sqlc.narg(in_stock)::boolean IS NULL
+    OR sqlc.narg(in_stock)::boolean = (
+        NOT p.inventory_tracked
+        OR EXISTS (
+            SELECT 1 FROM product_variants sv
+            JOIN inventory_items ii ON ii.variant_id = sv.id
+            WHERE sv.product_id = p.id AND sv.status = 'active' AND sv.deleted_at IS NULL AND ii.on_hand > 0
+        )
+    )
+  )
+  AND (sqlc.narg(created_after)::timestamptz IS NULL OR p.created_at >= sqlc.narg(created_after)::timestamptz)
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR p.created_at < sqlc.narg(created_before)::timestamptz)
+  AND (sqlc.narg(channel_id)::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM product_publications pub
+    WHERE pub.product_id = p.id AND pub.channel_id = sqlc.narg(channel_id)::uuid
+  ))
+  AND (
+    NOT sqlc.arg(has_cursor)::boolean
+    OR (p.created_at, p.id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY p.created_at DESC, p.id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: CountFilteredProductsByStatus :many
+SELECT p.status, COUNT(*) AS count
+FROM products p
+WHERE p.store_id = sqlc.arg(store_id)
+  AND p.deleted_at IS NULL
+  AND (COALESCE(cardinality(sqlc.arg(statuses)::text[]), 0) = 0 OR p.status = ANY(sqlc.arg(statuses)::text[]))
+  AND product_tags(p.tags) @> COALESCE(sqlc.arg(tags)::text[], '{}')
+  AND (
+    (sqlc.narg(min_price_cents)::bigint IS NULL AND sqlc.narg(max_price_cents)::bigint IS NULL)
+    OR EXISTS (
+        SELECT 1 FROM product_variants pv
+        WHERE pv.product_id = p.id AND pv.status = 'active' AND pv.deleted_at IS NULL
+          AND (sqlc.narg(min_price_cents)::bigint IS NULL OR pv.price_cents >= sqlc.narg(min_price_cents)::bigint)
+          AND (sqlc.narg(max_price_cents)::bigint IS NULL OR pv.price_cents <= sqlc.narg(max_price_cents)::bigint)
+    )
+  )
+  AND (
+    sqlc.narg(in_stock)::boolean IS NULL
+    OR sqlc.narg(in_stock)::boolean = (
+        NOT p.inventory_tracked
+        OR EXISTS (
+            SELECT 1 FROM product_variants sv
+            JOIN inventory_items ii ON ii.variant_id = sv.id
+            WHERE sv.product_id = p.id AND sv.status = 'active' AND sv.deleted_at IS NULL AND ii.on_hand > 0
+        )
+    )
+  )
+  AND (sqlc.narg(created_after)::timestamptz IS NULL OR p.created_at >= sqlc.narg(created_after)::timestamptz)
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR p.created_at < sqlc.narg(created_before)::timestamptz)
+  AND (sqlc.narg(channel_id)::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM product_publications pub
+    WHERE pub.product_id = p.id AND pub.channel_id = sqlc.narg(channel_id)::uuid
+  ))
+GROUP BY p.status
+ORDER BY p.status;
 
+-- name: CountFilteredProductsByTag :many
+SELECT tag::text AS tag, COUNT(*) AS count
+FROM products p
+CROSS JOIN LATERAL unnest(product_tags(p.tags)) AS tag
+WHERE p.store_id = sqlc.arg(store_id)
+  AND p.deleted_at IS NULL
+  AND (COALESCE(cardinality(sqlc.arg(statuses)::text[]), 0) = 0 OR p.status = ANY(sqlc.arg(statuses)::text[]))
+  AND product_tags(p.tags) @> COALESCE(sqlc.arg(tags)::text[], '{}')
+  AND (
+    (sqlc.narg(min_price_cents)::bigint IS NULL AND sqlc.narg(max_price_cents)::bigint IS NULL)
+    OR EXISTS (
+        SELECT 1 FROM product_variants pv
+        WHERE pv.product_id = p.id AND pv.status = 'active' AND pv.deleted_at IS NULL
+          AND (sqlc.narg(min_price_cents)::bigint IS NULL OR pv.price_cents >= sqlc.narg(min_price_cents)::bigint)
+          AND (sqlc.narg(max_price_cents)::bigint IS NULL OR pv.price_cents <= sqlc.narg(max_price_cents)::bigint)
+    )
+  )
+  AND (
+    sqlc.narg(in_stock)::boolean IS NULL
+    OR sqlc.narg(in_stock)::boolean = (
+        NOT p.inventory_tracked
+        OR EXISTS (
+            SELECT 1 FROM product_variants sv
+            JOIN inventory_items ii ON ii.variant_id = sv.id
+            WHERE sv.product_id = p.id AND sv.status = 'active' AND sv.deleted_at IS NULL AND ii.on_hand > 0
+        )
+    )
+  )
+  AND (sqlc.narg(created_after)::timestamptz IS NULL OR p.created_at >= sqlc.narg(created_after)::timestamptz)
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR p.created_at < sqlc.narg(created_before)::timestamptz)
+  AND (sqlc.narg(channel_id)::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM product_publications pub
+    WHERE pub.product_id = p.id AND pub.channel_id = sqlc.narg(channel_id)::uuid
+  ))
+GROUP BY tag
+ORDER BY count DESC, tag ASC
+LIMIT sqlc.arg(tag_limit);
+
+-- name: CountFilteredProductsByAvailability :one
+SELECT
+    COUNT(*) FILTER (WHERE (
+    NOT p.inventory_tracked
+    OR EXISTS (
+        SELECT 1 FROM product_variants sv
+        JOIN inventory_items ii ON ii.variant_id = sv.id
+        WHERE sv.product_id = p.id AND sv.status = 'active' AND sv.deleted_at IS NULL AND ii.on_hand > 0
+    )
+))::bigint AS in_stock,
+    COUNT(*) FILTER (WHERE NOT (
+    NOT p.inventory_tracked
+    OR EXISTS (
+        SELECT 1 FROM product_variants sv
+        JOIN inventory_items ii ON ii.variant_id = sv.id
+        WHERE sv.product_id = p.id AND sv.status = 'active' AND sv.deleted_at IS NULL AND ii.on_hand > 0
+    )
+))::bigint AS out_of_stock
+FROM products p
+WHERE p.store_id = sqlc.arg(store_id)
+  AND p.deleted_at IS NULL
+  AND (COALESCE(cardinality(sqlc.arg(statuses)::text[]), 0) = 0 OR p.status = ANY(sqlc.arg(statuses)::text[]))
+  AND product_tags(p.tags) @> COALESCE(sqlc.arg(tags)::text[], '{}')
+  AND (
+    (sqlc.narg(min_price_cents)::bigint IS NULL AND sqlc.narg(max_price_cents)::bigint IS NULL)
+    OR EXISTS (
+        SELECT 1 FROM product_variants pv
+        WHERE pv.product_id = p.id AND pv.status = 'active' AND pv.deleted_at IS NULL
+          AND (sqlc.narg(min_price_cents)::bigint IS NULL OR pv.price_cents >= sqlc.narg(min_price_cents)::bigint)
+          AND (sqlc.narg(max_price_cents)::bigint IS NULL OR pv.price_cents <= sqlc.narg(max_price_cents)::bigint)
+    )
+  )
+  AND (
+    sqlc.narg(in_stock)::boolean IS NULL
+    OR sqlc.narg(in_stock)::boolean = (
+        NOT p.inventory_tracked
+        OR EXISTS (
+            SELECT 1 FROM product_variants sv
+            JOIN inventory_items ii ON ii.variant_id = sv.id
+            WHERE sv.product_id = p.id AND sv.status = 'active' AND sv.deleted_at IS NULL AND ii.on_hand > 0
+        )
+    )
+  )
+  AND (sqlc.narg(created_after)::timestamptz IS NULL OR p.created_at >= sqlc.narg(created_after)::timestamptz)
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR p.created_at < sqlc.narg(created_before)::timestamptz)
+  AND (sqlc.narg(channel_id)::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM product_publications pub
+    WHERE pub.product_id = p.id AND pub.channel_id = sqlc.narg(channel_id)::uuid
+  ));
+
+-- name: GetFilteredProductsPriceRange :one
+-- Active variant price bounds, both 0 when variant_count is 0
+SELECT
+    COALESCE(MIN(v.price_cents), 0)::bigint AS min_price_cents,
+    COALESCE(MAX(v.price_cents), 0)::bigint AS max_price_cents,
+    COUNT(v.id)::bigint AS variant_count
+FROM products p
+JOIN product_variants v ON v.product_id = p.id AND v.status = 'active' AND v.deleted_at IS NULL
+WHERE p.store_id = sqlc.arg(store_id)
+  AND p.deleted_at IS NULL
+  AND (COALESCE(cardinality(sqlc.arg(statuses)::text[]), 0) = 0 OR p.status = ANY(sqlc.arg(statuses)::text[]))
+  AND product_tags(p.tags) @> COALESCE(sqlc.arg(tags)::text[], '{}')
+  AND (
+    (sqlc.narg(min_price_cents)::bigint IS NULL AND sqlc.narg(max_price_cents)::bigint IS NULL)
+    OR EXISTS (
+        SELECT 1 FROM product_variants pv
+        WHERE pv.product_id = p.id AND pv.status = 'active' AND pv.deleted_at IS NULL
+          AND (sqlc.narg(min_price_cents)::bigint IS NULL OR pv.price_cents >= sqlc.narg(min_price_cents)::bigint)
+          AND (sqlc.narg(max_price_cents)::bigint IS NULL OR pv.price_cents <= sqlc.narg(max_price_cents)::bigint)
+    )
+  )
+  AND (
+    sqlc.narg(in_stock)::boolean IS NULL
+    OR sqlc.narg(in_stock)::boolean = (
+        NOT p.inventory_tracked
+        OR EXISTS (
+            SELECT 1 FROM product_variants sv
+            JOIN inventory_items ii ON ii.variant_id = sv.id
+            WHERE sv.product_id = p.id AND sv.status = 'active' AND sv.deleted_at IS NULL AND ii.on_hand > 0
+        )
+    )
+  )
+  AND (sqlc.narg(created_after)::timestamptz IS NULL OR p.created_at >= sqlc.narg(created_after)::timestamptz)
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR p.created_at < sqlc.narg(created_before)::timestamptz)
+  AND (sqlc.narg(channel_id)::uuid IS NULL OR EXISTS (
+    SELECT 1 FROM product_publications pub
+    WHERE pub.product_id = p.id AND pub.channel_id = sqlc.narg(channel_id)::uuid
+  ));
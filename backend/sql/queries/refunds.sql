@@ -0,0 +1,59 @@
+-- name: CreateRefund :one
+INSERT INTO refunds (
+    id, gid, store_id, order_id, amount_cents, currency, reason, note,
+    provider, provider_transaction_id, created_by, created_at
+)
+VALUES (
+    gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, now()
+)
+RETURNING *;
+
+-- name: CreateRefundLineItem :exec
+INSERT INTO refund_line_items (refund_id, order_line_item_id, quantity, amount_cents, restocked, location_id)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: GetRefundByProviderTransaction :one
+SELECT * FROM refunds
+WHERE store_id = $1 AND provider = $2 AND provider_transaction_id = $3;
+
+-- name: GetRefundByID :one
+SELECT * FROM refunds
+WHERE id = $1 AND order_id = $2;
+
+-- name: GetRefundsByOrder :many
+SELECT * FROM refunds
+WHERE order_id = $1
+ORDER BY created_at ASC, id ASC;
+
+-- name: GetRefundLineItemsByOrder :many
+SELECT rli.*
+FROM refund_line_items rli
+JOIN refunds r ON r.id = rli.refund_id
+WHERE r.order_id = $1;
+
+-- name: SumOrderRefunds :one
+SELECT COALESCE(SUM(amount_cents), 0)::bigint AS refunded_cents
+FROM refunds
+WHERE order_id = $1;
+
+-- name: GetOrderLineItemsForRefund :many
+-- Line items with what has already been refunded and whether stock is tracked
+SELECT
+    li.id,
+    li.parent_line_item_id,
+    li.variant_id,
+    li.quantity,
+    li.total_cents,
+    COALESCE(p.inventory_tracked, false)::boolean AS inventory_tracked,
+    COALESCE((
+        SELECT SUM(rli.quantity) FROM refund_line_items rli
+        WHERE rli.order_line_item_id = li.id
+    ), 0)::bigint AS refunded_quantity,
+    COALESCE((
+        SELECT SUM(rli.amount_cents) FROM refund_line_items rli
+        WHERE rli.order_line_item_id = li.id
+    ), 0)::bigint AS refunded_cents
+FROM order_line_items li
+LEFT JOIN products p ON p.id = li.product_id
+WHERE li.order_id = $1
+ORDER BY li.created_at ASC, li.id ASC;
@@ -1,8 +1,8 @@
 -- Role Management
 
 -- name: CreateRole :one
-INSERT INTO roles (id, gid, tenant_id, name, description, created_at, updated_at)
-VALUES (gen_random_uuid(), $1, $2, $3, $4, now(), now())
+INSERT INTO roles (id, gid, tenant_id, name, description, is_system, created_at, updated_at)
+VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, now(), now())
 RETURNING *;
 
 -- name: GetRoleByGID :one
@@ -72,6 +72,14 @@ JOIN role_permissions rp ON p.id = rp.permission_id
 WHERE rp.role_id = $1
 ORDER BY p.key;
 
+-- name: GetPermissionKeysByRoleIDs :many
+-- One row per role that grants anything, for listing roles in one query
+SELECT rp.role_id, array_agg(p.key ORDER BY p.key)::text[] AS keys
+FROM role_permissions rp
+JOIN permissions p ON p.id = rp.permission_id
+WHERE rp.role_id = ANY(sqlc.arg(role_ids)::uuid[])
+GROUP BY rp.role_id;
+
 -- Tenant User Role Assignment
 
 -- name: AssignRoleToTenantUser :exec
@@ -89,6 +97,14 @@ JOIN tenant_user_roles tur ON r.id = tur.role_id
 WHERE tur.tenant_user_id = $1
 ORDER BY r.name;
 
+-- name: GetRoleNamesByTenantUserIDs :many
+-- One row per member holding any role, for listing members in one query
+SELECT tur.tenant_user_id, array_agg(r.name ORDER BY r.name)::text[] AS role_names
+FROM tenant_user_roles tur
+JOIN roles r ON r.id = tur.role_id
+WHERE tur.tenant_user_id = ANY(sqlc.arg(tenant_user_ids)::uuid[])
+GROUP BY tur.tenant_user_id;
+
 -- Permission Checking Queries
 
 -- name: CheckUserHasPermission :one
@@ -123,19 +139,19 @@ SELECT * FROM roles
 WHERE tenant_id = $1 AND id = $2;
 
 -- name: GetRolesByTenantIDPaginated :many
-SELECT id, gid, tenant_id, name, description, created_at, updated_at
+SELECT id, gid, tenant_id, name, description, is_system, created_at, updated_at
 FROM roles
-WHERE tenant_id = $1
+WHERE tenant_id = sqlc.arg(tenant_id)
   AND (
-    $2::boolean = false
-    OR (created_at, id) < ($3::timestamptz, $4::uuid)
+    sqlc.arg(has_cursor)::boolean = false
+    OR (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
   )
 ORDER BY created_at DESC, id DESC
-LIMIT $5;
+LIMIT sqlc.arg(row_limit);
 
 -- name: GetPermissionsByKeys :many
 SELECT * FROM permissions
-WHERE key = ANY($1::text[])
+WHERE key = ANY(sqlc.arg(keys)::text[])
 ORDER BY key;
 
 
@@ -0,0 +1,59 @@
+-- name: CreateSalesChannel :one
+INSERT INTO sales_channels (gid, tenant_id, store_id, kind, name, auto_publish)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetSalesChannelByID :one
+SELECT * FROM sales_channels
+WHERE id = $1 AND store_id = $2;
+
+-- name: GetOnlineStoreChannel :one
+SELECT * FROM sales_channels
+WHERE store_id = $1 AND kind = 'online_store';
+
+-- name: ListSalesChannels :many
+SELECT sales_channels.*,
+       (SELECT COUNT(*) FROM product_publications
+        WHERE product_publications.channel_id = sales_channels.id)::bigint AS product_count
+FROM sales_channels
+WHERE store_id = $1
+ORDER BY created_at ASC, id ASC;
+
+-- name: UpdateSalesChannel :one
+UPDATE sales_channels
+SET name = $3,
+    auto_publish = $4,
+    updated_at = now()
+WHERE id = $1 AND store_id = $2
+RETURNING *;
+
+-- name: DeleteSalesChannel :execrows
+-- The online store cannot be deleted
+DELETE FROM sales_channels
+WHERE id = $1 AND store_id = $2 AND kind <> 'online_store';
+
+-- name: ListProductPublications :many
+SELECT sales_channels.id AS channel_id, sales_channels.kind, sales_channels.name, product_publications.published_at
+FROM product_publications
+JOIN sales_channels ON sales_channels.id = product_publications.channel_id
+WHERE product_publications.product_id = $1
+ORDER BY sales_channels.created_at ASC, sales_channels.id ASC;
+
+-- name: UnpublishProductExcept :exec
+-- Removes every publication of a product except those to channel_ids
+DELETE FROM product_publications
+WHERE product_id = sqlc.arg(product_id)
+  AND NOT (channel_id = ANY(sqlc.arg(channel_ids)::uuid[]));
+
+-- name: PublishProduct :exec
+-- Keeps the original published_at of a product already on the channel
+INSERT INTO product_publications (channel_id, product_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: IsProductOnOnlineStore :one
+SELECT EXISTS (
+    SELECT 1 FROM product_publications
+    JOIN sales_channels ON sales_channels.id = product_publications.channel_id
+    WHERE product_publications.product_id = $1 AND sales_channels.kind = 'online_store'
+)::boolean AS published;
@@ -0,0 +1,31 @@
+-- name: CreateSession :one
+INSERT INTO sessions (id, user_id, user_agent, ip_address, created_at, last_used_at, expires_at)
+VALUES (gen_random_uuid(), $1, $2, $3, now(), now(), $4)
+RETURNING *;
+
+-- name: GetSessionForUpdate :one
+SELECT * FROM sessions WHERE id = $1 FOR UPDATE;
+
+-- name: TouchSession :exec
+-- Records where the session was last used and extends it
+UPDATE sessions
+SET last_used_at = now(), user_agent = $2, ip_address = $3, expires_at = $4
+WHERE id = $1;
+
+-- name: ListActiveUserSessions :many
+SELECT * FROM sessions
+WHERE user_id = $1
+  AND revoked_at IS NULL
+  AND expires_at > now()
+ORDER BY last_used_at DESC, id DESC;
+
+-- name: RevokeUserSession :one
+UPDATE sessions
+SET revoked_at = now()
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+RETURNING *;
+
+-- name: RevokeAllUserSessions :exec
+UPDATE sessions
+SET revoked_at = now()
+WHERE user_id = $1 AND revoked_at IS NULL;
@@ -0,0 +1,52 @@
+-- name: CreateShippingZone :one
+INSERT INTO shipping_zones (id, gid, tenant_id, store_id, name, regions, created_at, updated_at)
+VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, now(), now())
+RETURNING *;
+
+-- name: GetShippingZoneByID :one
+SELECT * FROM shipping_zones
+WHERE id = $1 AND store_id = $2;
+
+-- name: GetShippingZonesByStore :many
+SELECT * FROM shipping_zones
+WHERE store_id = $1
+ORDER BY created_at ASC, id ASC;
+
+-- name: UpdateShippingZone :one
+UPDATE shipping_zones
+SET name = $3, regions = $4, updated_at = now()
+WHERE id = $1 AND store_id = $2
+RETURNING *;
+
+-- name: DeleteShippingZone :execrows
+DELETE FROM shipping_zones
+WHERE id = $1 AND store_id = $2;
+
+-- name: CreateShippingRate :one
+INSERT INTO shipping_rates (id, zone_id, store_id, name, kind, price_cents, min_value, max_value, created_at, updated_at)
+VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, now(), now())
+RETURNING *;
+
+-- name: GetShippingRateByID :one
+SELECT * FROM shipping_rates
+WHERE id = $1 AND zone_id = $2;
+
+-- name: GetShippingRatesByZone :many
+SELECT * FROM shipping_rates
+WHERE zone_id = $1
+ORDER BY price_cents ASC, id ASC;
+
+-- name: GetShippingRatesByStore :many
+SELECT * FROM shipping_rates
+WHERE store_id = $1
+ORDER BY price_cents ASC, id ASC;
+
+-- name: UpdateShippingRate :one
+UPDATE shipping_rates
+SET name = $3, kind = $4, price_cents = $5, min_value = $6, max_value = $7, updated_at = now()
+WHERE id = $1 AND zone_id = $2
+RETURNING *;
+
+-- name: DeleteShippingRate :execrows
+DELETE FROM shipping_rates
+WHERE id = $1 AND zone_id = $2;
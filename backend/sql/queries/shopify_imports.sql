@@ -0,0 +1,50 @@
+-- name: CreateShopifyImport :one
+INSERT INTO shopify_imports (tenant_id, store_id, created_by, entity, format, filename, data, total_items)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, tenant_id, store_id, created_by, entity, format, filename, status, total_items, error, created_at, started_at, finished_at;
+
+-- name: GetShopifyImport :one
+-- Leaves out the uploaded file, which only the worker needs
+SELECT id, tenant_id, store_id, created_by, entity, format, filename, status, total_items, error, created_at, started_at, finished_at
+FROM shopify_imports
+WHERE id = $1 AND store_id = $2 AND entity = $3;
+
+-- name: GetShopifyImportForProcessing :one
+SELECT * FROM shopify_imports
+WHERE id = $1;
+
+-- name: StartShopifyImport :exec
+UPDATE shopify_imports
+SET status = 'running', started_at = COALESCE(started_at, now())
+WHERE id = $1;
+
+-- name: FinishShopifyImport :exec
+UPDATE shopify_imports
+SET status = $2, error = $3, finished_at = now()
+WHERE id = $1;
+
+-- name: CreateShopifyImportItem :exec
+INSERT INTO shopify_import_items (import_id, position, status, reference, target_id, errors)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (import_id, position) DO NOTHING;
+
+-- name: ListShopifyImportItemPositions :many
+SELECT position FROM shopify_import_items
+WHERE import_id = $1;
+
+-- name: ListShopifyImportItems :many
+SELECT * FROM shopify_import_items
+WHERE import_id = sqlc.arg(import_id)
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+  AND position > sqlc.arg(after_position)
+ORDER BY position
+LIMIT sqlc.arg(row_limit);
+
+-- name: CountShopifyImportItems :one
+SELECT
+    COUNT(*) FILTER (WHERE status = 'created')::int AS created,
+    COUNT(*) FILTER (WHERE status = 'updated')::int AS updated,
+    COUNT(*) FILTER (WHERE status = 'skipped')::int AS skipped,
+    COUNT(*) FILTER (WHERE status = 'failed')::int AS failed
+FROM shopify_import_items
+WHERE import_id = $1;
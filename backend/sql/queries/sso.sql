@@ -0,0 +1,88 @@
+-- name: GetSSOConnectionByTenant :one
+SELECT * FROM sso_connections WHERE tenant_id = $1;
+
+-- name: GetSSOConnectionByID :one
+SELECT * FROM sso_connections WHERE id = $1;
+
+-- name: UpsertSSOConnection :one
+INSERT INTO sso_connections (
+    id, tenant_id, protocol, enabled, saml_metadata, saml_entity_id, saml_sso_url,
+    oidc_issuer, oidc_client_id, oidc_client_secret, attribute_mapping,
+    default_role_id, jit_provisioning
+)
+VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+ON CONFLICT (tenant_id) DO UPDATE
+SET protocol = EXCLUDED.protocol,
+    enabled = EXCLUDED.enabled,
+    saml_metadata = EXCLUDED.saml_metadata,
+    saml_entity_id = EXCLUDED.saml_entity_id,
+    saml_sso_url = EXCLUDED.saml_sso_url,
+    oidc_issuer = EXCLUDED.oidc_issuer,
+    oidc_client_id = EXCLUDED.oidc_client_id,
+    oidc_client_secret = EXCLUDED.oidc_client_secret,
+    attribute_mapping = EXCLUDED.attribute_mapping,
+    default_role_id = EXCLUDED.default_role_id,
+    jit_provisioning = EXCLUDED.jit_provisioning,
+    updated_at = now()
+RETURNING *;
+
+-- name: DeleteSSOConnection :execrows
+DELETE FROM sso_connections WHERE tenant_id = $1;
+
+-- name: GetSSOConnectionByDomain :one
+-- The enabled connection of the tenant that verified the domain
+SELECT c.* FROM sso_connections c
+JOIN sso_domains d ON d.tenant_id = c.tenant_id
+WHERE d.domain = $1 AND d.verified_at IS NOT NULL AND c.enabled;
+
+-- name: IsSSODomainVerified :one
+SELECT EXISTS (
+    SELECT 1 FROM sso_domains
+    WHERE tenant_id = $1 AND domain = $2 AND verified_at IS NOT NULL
+)::boolean;
+
+-- name: ListSSODomains :many
+SELECT * FROM sso_domains
+WHERE tenant_id = $1
+ORDER BY domain;
+
+-- name: CreateSSODomain :one
+INSERT INTO sso_domains (id, tenant_id, domain, verification_token)
+VALUES (gen_random_uuid(), $1, $2, $3)
+RETURNING *;
+
+-- name: GetSSODomain :one
+SELECT * FROM sso_domains
+WHERE tenant_id = $1 AND id = $2;
+
+-- name: MarkSSODomainVerified :one
+UPDATE sso_domains
+SET verified_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteSSODomain :execrows
+DELETE FROM sso_domains
+WHERE tenant_id = $1 AND id = $2;
+
+-- name: UserRequiresTenantSSO :one
+-- Whether the user is an active member of a tenant that requires SSO and
+-- has its own identity provider to sign in through
+SELECT EXISTS (
+    SELECT 1 FROM tenant_users tu
+    JOIN tenants t ON t.id = tu.tenant_id
+    JOIN sso_connections c ON c.tenant_id = t.id
+    WHERE tu.user_id = $1 AND tu.status = 'active' AND t.require_sso AND c.enabled
+)::boolean;
+
+-- name: GetPendingOAuthState :one
+-- A sign-in still waiting on its identity provider
+SELECT * FROM oauth_states
+WHERE state_hash = $1 AND user_id IS NULL AND expires_at > now();
+
+-- name: CompleteOAuthState :execrows
+-- Records who signed in at a SAML provider, once, for the callback to
+-- finish with the code
+UPDATE oauth_states
+SET user_id = $2, code_hash = $3
+WHERE state_hash = $1 AND user_id IS NULL AND expires_at > now();
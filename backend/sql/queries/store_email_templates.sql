@@ -0,0 +1,22 @@
+-- name: GetStoreEmailTemplates :many
+SELECT * FROM store_email_templates
+WHERE store_id = $1
+ORDER BY template;
+
+-- name: GetStoreEmailTemplate :one
+SELECT * FROM store_email_templates
+WHERE store_id = $1 AND template = $2;
+
+-- name: UpsertStoreEmailTemplate :one
+INSERT INTO store_email_templates (store_id, template, subject, body_text, body_html)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (store_id, template) DO UPDATE
+SET subject = EXCLUDED.subject,
+    body_text = EXCLUDED.body_text,
+    body_html = EXCLUDED.body_html,
+    updated_at = now()
+RETURNING *;
+
+-- name: DeleteStoreEmailTemplate :execrows
+DELETE FROM store_email_templates
+WHERE store_id = $1 AND template = $2;
@@ -0,0 +1,40 @@
+-- name: CreateStorefrontToken :one
+INSERT INTO storefront_tokens (id, gid, store_id, kind, name, prefix, token_hash, scopes, created_by, rotated_from, created_at)
+VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8, $9, now())
+RETURNING *;
+
+-- name: GetActiveStorefrontTokenByHash :one
+SELECT * FROM storefront_tokens
+WHERE token_hash = $1
+  AND revoked_at IS NULL
+  AND (expires_at IS NULL OR expires_at > now());
+
+-- name: TouchStorefrontToken :exec
+-- Throttled to one write a minute per token
+UPDATE storefront_tokens
+SET last_used_at = now(), last_used_ip = $2
+WHERE id = $1
+  AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute');
+
+-- name: ListStoreStorefrontTokens :many
+SELECT * FROM storefront_tokens
+WHERE store_id = $1
+  AND revoked_at IS NULL
+  AND (expires_at IS NULL OR expires_at > now())
+ORDER BY created_at DESC, id DESC;
+
+-- name: ExpireStorefrontToken :one
+-- Ends a token at expires_at, unless it already ends sooner
+UPDATE storefront_tokens
+SET expires_at = sqlc.arg(expires_at)
+WHERE id = sqlc.arg(id)
+  AND store_id = sqlc.arg(store_id)
+  AND revoked_at IS NULL
+  AND (expires_at IS NULL OR expires_at > sqlc.arg(expires_at))
+RETURNING *;
+
+-- name: RevokeStorefrontToken :one
+UPDATE storefront_tokens
+SET revoked_at = now()
+WHERE id = $1 AND store_id = $2 AND revoked_at IS NULL
+RETURNING *;
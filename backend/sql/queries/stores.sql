@@ -7,14 +7,18 @@ RETURNING *;
 SELECT * FROM stores
 WHERE gid = $1;
 
+-- name: GetStoreByID :one
+SELECT * FROM stores
+WHERE id = $1;
+
 -- name: DeleteAllStores :exec
 DELETE FROM stores;
 
--- name: GetStores :many
-SELECT * FROM stores ORDER BY created_at ASC;
-
 -- name: GetStoreByHandle :one
-SELECT * FROM stores WHERE handle = $1;
+-- Stores of suspended tenants are not found, so they stop being served
+SELECT s.* FROM stores s
+WHERE s.handle = $1 AND s.deleted_at IS NULL
+  AND NOT EXISTS (SELECT 1 FROM tenants t WHERE t.id = s.tenant_id AND t.status = 'suspended');
 
 -- name: UpdateStore :one
 UPDATE stores
@@ -34,42 +38,149 @@ RETURNING *;
 
 -- name: GetStoresByTenantID :many
 SELECT * FROM stores
-WHERE tenant_id = $1
+WHERE tenant_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC;
 
 -- name: GetStoresByTenantIDPaginated :many
-SELECT id, gid, name, handle, address, status, default_currency, timezone, plan, tenant_id, created_at, updated_at
+SELECT id, gid, name, handle, address, status, default_currency, default_locale, timezone, plan, tenant_id, created_at, updated_at
 FROM stores
-WHERE tenant_id = $1
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND deleted_at IS NULL
   AND (
-    $2::boolean = false
-    OR (created_at, id) < ($3::timestamptz, $4::uuid)
+    sqlc.arg(has_cursor)::boolean = false
+    OR (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
   )
 ORDER BY created_at DESC, id DESC
-LIMIT $5;
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetStoresByUserIDPaginated :many
+-- Stores in every tenant where one of the roles of the user, an active
+-- member, grants the permission, optionally one
+SELECT s.id, s.gid, s.name, s.handle, s.address, s.status, s.default_currency, s.default_locale, s.timezone, s.plan, s.tenant_id, s.created_at, s.updated_at
+FROM stores s
+JOIN tenant_users tu ON tu.tenant_id = s.tenant_id
+WHERE tu.user_id = sqlc.arg(user_id)
+  AND tu.status = 'active'
+  AND EXISTS (
+    SELECT 1 FROM tenant_user_roles tur
+    JOIN role_permissions rp ON rp.role_id = tur.role_id
+    JOIN permissions p ON p.id = rp.permission_id
+    WHERE tur.tenant_user_id = tu.id
+      AND p.key = sqlc.arg(permission)
+  )
+  AND s.deleted_at IS NULL
+  AND (sqlc.narg(tenant_id)::uuid IS NULL OR s.tenant_id = sqlc.narg(tenant_id)::uuid)
+  AND (
+    NOT sqlc.arg(has_cursor)::boolean
+    OR (s.created_at, s.id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY s.created_at DESC, s.id DESC
+LIMIT sqlc.arg(row_limit);
 
 -- name: GetStoreByTenantAndHandle :one
 SELECT * FROM stores
-WHERE tenant_id = $1 AND handle = $2;
+WHERE tenant_id = $1 AND handle = $2 AND deleted_at IS NULL;
 
 -- name: GetStoreByTenantAndID :one
 SELECT * FROM stores
-WHERE tenant_id = $1 AND id = $2;
+WHERE tenant_id = $1 AND id = $2 AND deleted_at IS NULL;
 
 -- name: UpdateStoreForTenant :one
+-- Only the fields given change
 UPDATE stores
 SET
-    name = $3,
-    handle = $4,
-    address = $5,
-    status = $6,
-    default_currency = $7,
-    timezone = $8,
-    plan = $9,
+    name = COALESCE(sqlc.narg(name)::text, name),
+    address = COALESCE(sqlc.narg(address)::text, address),
+    default_currency = COALESCE(sqlc.narg(default_currency)::text, default_currency),
+    timezone = COALESCE(sqlc.narg(timezone)::text, timezone),
+    plan = COALESCE(sqlc.narg(plan)::text, plan),
+    default_locale = COALESCE(sqlc.narg(default_locale)::text, default_locale),
     updated_at = now()
-WHERE id = $1 AND tenant_id = $2
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id) AND deleted_at IS NULL
+RETURNING *;
+
+-- name: UpdateStoreStatus :one
+-- Conditional on the status read, so concurrent changes cannot both pass
+-- the transition check
+UPDATE stores
+SET status = sqlc.arg(status), updated_at = now()
+WHERE id = sqlc.arg(id)
+  AND tenant_id = sqlc.arg(tenant_id)
+  AND status = sqlc.arg(from_status)
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SoftDeleteStore :one
+-- Hides the store until it is restored or purged
+UPDATE stores
+SET deleted_at = now()
+WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RestoreStore :one
+UPDATE stores
+SET deleted_at = NULL, updated_at = now()
+WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NOT NULL
 RETURNING *;
 
--- name: DeleteStoreForTenant :exec
+-- name: GetDeletedStore :one
+SELECT * FROM stores
+WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NOT NULL;
+
+-- name: ListDeletedStores :many
+-- Most recently deleted first
+SELECT * FROM stores
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND deleted_at IS NOT NULL
+  AND (
+    NOT sqlc.arg(has_cursor)::boolean
+    OR (deleted_at, id) < (sqlc.arg(cursor_deleted_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY deleted_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: PurgeDeletedStores :execrows
+-- Permanently removes up to row_limit stores deleted before the cutoff,
+-- with everything in them
 DELETE FROM stores
-WHERE id = $1 AND tenant_id = $2;
+WHERE id IN (
+    SELECT id FROM stores
+    WHERE deleted_at < sqlc.arg(before)::timestamptz
+    ORDER BY deleted_at
+    LIMIT sqlc.arg(row_limit)
+);
+
+-- name: ListStoreHandlesWithPrefix :many
+-- The handle and any taken with a numeric suffix, such as shop-2, for
+-- finding a free one
+SELECT handle FROM stores
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND (handle = sqlc.arg(handle)::text OR handle LIKE sqlc.arg(handle)::text || '-%');
+
+-- name: GetStoreSettings :one
+SELECT settings FROM stores
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id) AND deleted_at IS NULL;
+
+-- name: UpdateStoreSettings :one
+-- Conditional on the settings read, so two concurrent patches cannot lose
+-- each other
+UPDATE stores
+SET settings = sqlc.arg(settings)::jsonb, updated_at = now()
+WHERE id = sqlc.arg(id)
+  AND tenant_id = sqlc.arg(tenant_id)
+  AND deleted_at IS NULL
+  AND settings = sqlc.arg(previous)::jsonb
+RETURNING settings;
+
+-- name: ListTenantStorePlans :many
+-- Plans of the live stores in a tenant, for sizing its rate limit
+SELECT DISTINCT plan FROM stores
+WHERE tenant_id = sqlc.arg(tenant_id) AND deleted_at IS NULL;
+
+-- name: ListUserStorePlans :many
+-- Plans of the live stores in every tenant the user is an active member of
+SELECT DISTINCT s.plan FROM stores s
+JOIN tenant_users tu ON tu.tenant_id = s.tenant_id
+WHERE tu.user_id = sqlc.arg(user_id)
+  AND tu.status = 'active'
+  AND s.deleted_at IS NULL;
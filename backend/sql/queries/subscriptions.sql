@@ -0,0 +1,172 @@
+-- name: CreateSellingPlan :one
+INSERT INTO selling_plans (gid, tenant_id, store_id, name, interval_unit, interval_count, percent_off)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetSellingPlanByID :one
+SELECT * FROM selling_plans
+WHERE id = $1 AND store_id = $2;
+
+-- name: ListSellingPlans :many
+SELECT * FROM selling_plans
+WHERE store_id = $1
+ORDER BY name ASC, id ASC;
+
+-- name: UpdateSellingPlan :one
+UPDATE selling_plans
+SET name = $3,
+    interval_unit = $4,
+    interval_count = $5,
+    percent_off = $6,
+    updated_at = now()
+WHERE id = $1 AND store_id = $2
+RETURNING *;
+
+-- name: DeleteSellingPlan :execrows
+DELETE FROM selling_plans
+WHERE id = $1 AND store_id = $2;
+
+-- name: ListSellingPlanProductsByStore :many
+SELECT selling_plan_products.selling_plan_id, selling_plan_products.product_id
+FROM selling_plan_products
+JOIN selling_plans ON selling_plans.id = selling_plan_products.selling_plan_id
+WHERE selling_plans.store_id = $1
+ORDER BY selling_plan_products.product_id;
+
+-- name: ClearSellingPlanProducts :exec
+DELETE FROM selling_plan_products
+WHERE selling_plan_id = $1;
+
+-- name: AddSellingPlanProduct :exec
+INSERT INTO selling_plan_products (selling_plan_id, product_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: ListProductSellingPlans :many
+-- Plans a product can be subscribed to
+SELECT selling_plans.* FROM selling_plans
+JOIN selling_plan_products ON selling_plan_products.selling_plan_id = selling_plans.id
+WHERE selling_plan_products.product_id = $1
+ORDER BY selling_plans.name ASC, selling_plans.id ASC;
+
+-- name: CreateSubscriptionContract :one
+INSERT INTO subscription_contracts (
+    gid, tenant_id, store_id, customer_id, selling_plan_id, variant_id, quantity, price_cents,
+    currency, interval_unit, interval_count, payment_provider, payment_method_ref,
+    billing_anchor_at, next_billing_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $14)
+RETURNING *;
+
+-- name: GetSubscriptionContractByID :one
+SELECT * FROM subscription_contracts
+WHERE id = $1 AND store_id = $2;
+
+-- name: GetCustomerSubscriptionContract :one
+SELECT * FROM subscription_contracts
+WHERE id = $1 AND customer_id = $2;
+
+-- name: GetSubscriptionContractsPaginated :many
+-- Newest first. An empty status returns every contract.
+SELECT * FROM subscription_contracts
+WHERE store_id = sqlc.arg(store_id)
+  AND (sqlc.arg(status)::text = '' OR status = sqlc.arg(status)::text)
+  AND (
+    sqlc.arg(has_cursor)::boolean = false
+    OR (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListCustomerSubscriptionContracts :many
+SELECT * FROM subscription_contracts
+WHERE customer_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: PauseSubscriptionContract :one
+UPDATE subscription_contracts
+SET status = 'paused',
+    paused_at = now(),
+    updated_at = now()
+WHERE id = $1 AND status = 'active'
+RETURNING *;
+
+-- name: ResumeSubscriptionContract :one
+-- Renewals missed while paused or failed are skipped rather than billed
+-- all at once, so the caller passes the next cycle still ahead.
+UPDATE subscription_contracts
+SET status = 'active',
+    next_cycle = $2,
+    next_billing_at = $3,
+    failed_attempts = 0,
+    last_failure = NULL,
+    paused_at = NULL,
+    updated_at = now()
+WHERE id = $1 AND status IN ('paused', 'failed')
+RETURNING *;
+
+-- name: CancelSubscriptionContract :one
+UPDATE subscription_contracts
+SET status = 'cancelled',
+    cancelled_at = now(),
+    updated_at = now()
+WHERE id = $1 AND status <> 'cancelled'
+RETURNING *;
+
+-- name: ClaimDueSubscriptionContract :one
+-- Locks the next active contract that is due. Contracts another biller
+-- has locked are skipped, so several can run at once.
+SELECT sc.* FROM subscription_contracts sc
+JOIN stores s ON s.id = sc.store_id
+WHERE sc.status = 'active'
+  AND sc.next_billing_at <= $1
+  AND s.deleted_at IS NULL
+ORDER BY sc.next_billing_at ASC
+LIMIT 1
+FOR UPDATE OF sc SKIP LOCKED;
+
+-- name: GetSubscriptionVariant :one
+-- The variant a renewal orders, unless it or its product has been deleted
+SELECT pv.id, pv.product_id, pv.title, pv.sku, p.name AS product_name
+FROM product_variants pv
+JOIN products p ON p.id = pv.product_id
+WHERE pv.id = $1 AND pv.deleted_at IS NULL AND p.deleted_at IS NULL;
+
+-- name: RecordSubscriptionBilled :exec
+UPDATE subscription_contracts
+SET next_cycle = $2,
+    next_billing_at = $3,
+    failed_attempts = 0,
+    last_failure = NULL,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: RecordSubscriptionFailure :exec
+-- Retries the same cycle at next_billing_at, or stops billing when status
+-- is failed
+UPDATE subscription_contracts
+SET status = $2,
+    next_billing_at = $3,
+    failed_attempts = failed_attempts + 1,
+    last_failure = $4,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: LockStoreOrderNumbers :exec
+-- Held until the transaction ends so two orders never take the same number
+SELECT pg_advisory_xact_lock(hashtext('order_number:' || sqlc.arg(store_id)::uuid::text));
+
+-- name: GetNextOrderNumber :one
+SELECT (COALESCE(MAX(order_number), 1000) + 1)::bigint AS order_number
+FROM orders
+WHERE store_id = $1;
+
+-- name: CreateSubscriptionOrder :exec
+INSERT INTO subscription_orders (contract_id, cycle, order_id)
+VALUES ($1, $2, $3);
+
+-- name: ListSubscriptionOrders :many
+SELECT o.* FROM orders o
+JOIN subscription_orders so ON so.order_id = o.id
+WHERE so.contract_id = $1
+ORDER BY so.cycle DESC;
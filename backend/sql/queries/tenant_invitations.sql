@@ -0,0 +1,25 @@
+-- name: CreateTenantInvitation :one
+INSERT INTO tenant_invitations (id, tenant_id, email, role_id, token_hash, invited_by, expires_at, created_at)
+VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, now())
+RETURNING *;
+
+-- name: RevokeOpenTenantInvitations :exec
+-- Closes earlier invitations to the same address so only the newest link works
+UPDATE tenant_invitations
+SET revoked_at = now()
+WHERE tenant_id = $1 AND email = $2
+  AND accepted_at IS NULL AND revoked_at IS NULL;
+
+-- name: GetActiveTenantInvitation :one
+SELECT * FROM tenant_invitations
+WHERE token_hash = $1
+  AND accepted_at IS NULL
+  AND revoked_at IS NULL
+  AND expires_at > now()
+FOR UPDATE;
+
+-- name: MarkTenantInvitationAccepted :one
+UPDATE tenant_invitations
+SET accepted_at = now(), accepted_by = $2
+WHERE id = $1
+RETURNING *;
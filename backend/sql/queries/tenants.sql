@@ -54,27 +54,85 @@ WHERE tu.tenant_id = $1;
 SELECT t.id, t.gid, t.name, t.status, t.created_at, t.updated_at
 FROM tenants t
 JOIN tenant_users tu ON t.id = tu.tenant_id
-WHERE tu.user_id = $1
+WHERE tu.user_id = sqlc.arg(user_id)
   AND tu.status = 'active'
   AND (
-    $2::boolean = false
-    OR (t.created_at, t.id) < ($3::timestamptz, $4::uuid)
+    sqlc.arg(has_cursor)::boolean = false
+    OR (t.created_at, t.id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
   )
 ORDER BY t.created_at DESC, t.id DESC
-LIMIT $5;
+LIMIT sqlc.arg(row_limit);
 
 -- name: GetTenantUsersWithDetailsPaginated :many
-SELECT tu.id, tu.tenant_id, tu.user_id, tu.status, tu.created_at, tu.updated_at, u.email
+-- query matches part of the email or display name, so members can find
+-- each other without seeing anyone outside the tenant
+SELECT tu.id, tu.tenant_id, tu.user_id, tu.status, tu.created_at, tu.updated_at, u.email, u.display_name
 FROM tenant_users tu
 JOIN users u ON tu.user_id = u.id
-WHERE tu.tenant_id = $1
+WHERE tu.tenant_id = sqlc.arg(tenant_id)
   AND (
-    $2::boolean = false
-    OR (tu.created_at, tu.id) < ($3::timestamptz, $4::uuid)
+    sqlc.narg(query)::text IS NULL
+    OR strpos(lower(u.email), lower(sqlc.narg(query)::text)) > 0
+    OR strpos(lower(coalesce(u.display_name, '')), lower(sqlc.narg(query)::text)) > 0
+  )
+  AND (
+    sqlc.arg(has_cursor)::boolean = false
+    OR (tu.created_at, tu.id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
   )
 ORDER BY tu.created_at DESC, tu.id DESC
-LIMIT $5;
+LIMIT sqlc.arg(row_limit);
 
 -- name: GetTenantUserByID :one
 SELECT * FROM tenant_users
 WHERE id = $1;
+
+-- name: UpsertTenantUser :one
+-- Re-invites and invitation acceptance reuse an existing membership row
+INSERT INTO tenant_users (id, tenant_id, user_id, status, created_at, updated_at)
+VALUES (gen_random_uuid(), $1, $2, $3, now(), now())
+ON CONFLICT (tenant_id, user_id) DO UPDATE
+SET status = EXCLUDED.status, updated_at = now()
+RETURNING *;
+
+-- name: ListActiveTenantIDsByUserID :many
+SELECT tenant_id FROM tenant_users
+WHERE user_id = $1 AND status = 'active'
+ORDER BY tenant_id;
+
+-- name: LockTenant :one
+-- Serializes membership changes that must leave the tenant an owner
+SELECT id FROM tenants
+WHERE id = sqlc.arg(tenant_id)
+FOR UPDATE;
+
+-- name: CountActiveTenantOwners :one
+SELECT count(DISTINCT tu.id)
+FROM tenant_users tu
+JOIN tenant_user_roles tur ON tur.tenant_user_id = tu.id
+JOIN roles r ON r.id = tur.role_id
+WHERE tu.tenant_id = sqlc.arg(tenant_id)
+  AND tu.status = 'active'
+  AND r.is_system
+  AND r.name = sqlc.arg(owner_role);
+
+-- name: IsTenantUserOwner :one
+SELECT EXISTS (
+    SELECT 1 FROM tenant_user_roles tur
+    JOIN roles r ON r.id = tur.role_id
+    WHERE tur.tenant_user_id = sqlc.arg(tenant_user_id)
+      AND r.is_system
+      AND r.name = sqlc.arg(owner_role)
+) AS is_owner;
+
+-- name: DeleteTenantUser :one
+-- Role assignments go with the membership
+DELETE FROM tenant_users
+WHERE id = $1 AND tenant_id = $2
+RETURNING *;
+
+-- name: ScopeToTenant :exec
+-- Limits the rest of the transaction to the rows of the tenant, through the
+-- row-level security policies of tenant-owned tables, even on a connection
+-- that otherwise bypasses them
+SELECT set_config('app.tenant_id', sqlc.arg(tenant_id)::uuid::text, true),
+    set_config('app.bypass', 'off', true);
@@ -0,0 +1,61 @@
+-- name: AddUsage :exec
+INSERT INTO usage_records (tenant_id, metric, day, quantity)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (tenant_id, metric, day) DO UPDATE
+SET quantity = usage_records.quantity + EXCLUDED.quantity, updated_at = now();
+
+-- name: TallyOrderUsage :execrows
+-- Recounts the orders each tenant placed on day, between from_time and
+-- to_time
+INSERT INTO usage_records (tenant_id, metric, day, quantity)
+SELECT o.tenant_id, 'orders', sqlc.arg(day)::date, count(*)
+FROM orders o
+WHERE o.created_at >= sqlc.arg(from_time)::timestamptz
+  AND o.created_at < sqlc.arg(to_time)::timestamptz
+GROUP BY o.tenant_id
+ON CONFLICT (tenant_id, metric, day) DO UPDATE
+SET quantity = EXCLUDED.quantity, updated_at = now();
+
+-- name: TallyStorageUsage :execrows
+-- Bytes of uploaded media each tenant stores now, kept as the peak of day
+INSERT INTO usage_records (tenant_id, metric, day, quantity)
+SELECT pm.tenant_id, 'storage_bytes', sqlc.arg(day)::date, coalesce(sum(pm.size_bytes), 0)::bigint
+FROM product_media pm
+WHERE pm.status = 'ready'
+GROUP BY pm.tenant_id
+ON CONFLICT (tenant_id, metric, day) DO UPDATE
+SET quantity = GREATEST(usage_records.quantity, EXCLUDED.quantity), updated_at = now();
+
+-- name: ListTenantUsage :many
+SELECT metric, day, quantity FROM usage_records
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND day >= sqlc.arg(from_day)::date
+  AND day < sqlc.arg(to_day)::date
+ORDER BY day, metric;
+
+-- name: ListBillableUsage :many
+-- Usage between from_day and to_day of tenants with a live subscription,
+-- totalled and peaked per metric
+SELECT tb.tenant_id, tb.stripe_customer_id, tb.applied_plan, ur.metric,
+       sum(ur.quantity)::bigint AS total,
+       max(ur.quantity)::bigint AS peak
+FROM tenant_billing tb
+JOIN usage_records ur ON ur.tenant_id = tb.tenant_id
+WHERE tb.stripe_subscription_id IS NOT NULL
+  AND tb.status NOT IN ('canceled', 'incomplete_expired')
+  AND ur.day >= sqlc.arg(from_day)::date
+  AND ur.day < sqlc.arg(to_day)::date
+GROUP BY tb.tenant_id, tb.stripe_customer_id, tb.applied_plan, ur.metric
+ORDER BY tb.tenant_id, ur.metric;
+
+-- name: ClaimOverageReport :one
+-- Returns no row when the month was already billed
+INSERT INTO usage_overage_reports (tenant_id, month, metric, used, included, units)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (tenant_id, month, metric) DO NOTHING
+RETURNING *;
+
+-- name: SetOverageReportInvoiceItem :exec
+UPDATE usage_overage_reports
+SET stripe_invoice_item_id = $4
+WHERE tenant_id = $1 AND month = $2 AND metric = $3;
@@ -9,9 +9,6 @@ WHERE gid = $1;
 
 
 
--- name: DeleteAllUsers :exec
-DELETE FROM users;
-
 
 -- name: GetAllUsers :many
 SELECT * FROM users ORDER BY created_at ASC;
@@ -29,3 +26,49 @@ SET
     updated_at = now()
 WHERE id = $1
 RETURNING *;
+
+-- name: UpdateUserPassword :one
+UPDATE users
+SET hashed_password = $2, updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: GetUserByID :one
+SELECT * FROM users WHERE id = $1;
+
+-- name: MarkUserVerified :one
+-- Keeps the first verification time if the user verifies twice
+UPDATE users
+SET verified_at = COALESCE(verified_at, now()), updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: GetUserPermissionsVersion :one
+SELECT permissions_version FROM users WHERE id = $1;
+
+-- name: GetUserTokenVersion :one
+SELECT token_version FROM users WHERE id = $1;
+
+-- name: BumpUserTokenVersion :exec
+UPDATE users
+SET token_version = token_version + 1, updated_at = now()
+WHERE id = $1;
+
+-- name: UpdateUserProfile :one
+UPDATE users
+SET display_name = $2, updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateUserAvatar :one
+UPDATE users
+SET avatar_key = $2, updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ChangeUserEmail :one
+-- Following the link to the new address proved it, so it counts as verified
+UPDATE users
+SET email = $2, verified_at = now(), updated_at = now()
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,72 @@
+-- +goose Up
+
+CREATE TABLE inventory_locations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    kind TEXT NOT NULL DEFAULT 'warehouse' CHECK (kind IN ('warehouse', 'retail')),
+    address TEXT,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (tenant_id, name)
+);
+
+-- At most one default location per tenant; adjustments without a location land here
+CREATE UNIQUE INDEX IF NOT EXISTS idx_inventory_locations_default ON inventory_locations(tenant_id) WHERE is_default;
+
+-- Stock per variant per location. inventory_items.on_hand is kept as the sum across locations.
+CREATE TABLE inventory_levels (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    inventory_item_id UUID NOT NULL REFERENCES inventory_items(id) ON DELETE CASCADE,
+    location_id UUID NOT NULL REFERENCES inventory_locations(id) ON DELETE CASCADE,
+    on_hand INTEGER NOT NULL DEFAULT 0 CHECK (on_hand >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (inventory_item_id, location_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_levels_location ON inventory_levels(location_id);
+
+ALTER TABLE inventory_movements
+    ADD COLUMN location_id UUID REFERENCES inventory_locations(id) ON DELETE SET NULL,
+    ADD COLUMN transfer_id UUID;
+
+ALTER TABLE inventory_movements DROP CONSTRAINT IF EXISTS inventory_movements_reason_check;
+ALTER TABLE inventory_movements
+    ADD CONSTRAINT inventory_movements_reason_check
+    CHECK (reason IN ('received', 'sold', 'damaged', 'correction', 'transfer'));
+
+CREATE INDEX IF NOT EXISTS idx_inventory_movements_transfer ON inventory_movements(transfer_id) WHERE transfer_id IS NOT NULL;
+
+-- Existing single-location stock moves into a default location per tenant
+INSERT INTO inventory_locations (id, tenant_id, name, kind, is_default, created_at, updated_at)
+SELECT gen_random_uuid(), t.tenant_id, 'Default location', 'warehouse', TRUE, now(), now()
+FROM (SELECT DISTINCT tenant_id FROM inventory_items) t;
+
+INSERT INTO inventory_levels (id, tenant_id, inventory_item_id, location_id, on_hand, created_at, updated_at)
+SELECT gen_random_uuid(), i.tenant_id, i.id, l.id, i.on_hand, now(), now()
+FROM inventory_items i
+JOIN inventory_locations l ON l.tenant_id = i.tenant_id AND l.is_default;
+
+UPDATE inventory_movements m
+SET location_id = l.id
+FROM inventory_locations l
+WHERE l.tenant_id = m.tenant_id AND l.is_default;
+
+-- +goose Down
+DELETE FROM inventory_movements WHERE reason = 'transfer';
+ALTER TABLE inventory_movements DROP CONSTRAINT IF EXISTS inventory_movements_reason_check;
+ALTER TABLE inventory_movements
+    ADD CONSTRAINT inventory_movements_reason_check
+    CHECK (reason IN ('received', 'sold', 'damaged', 'correction'));
+DROP INDEX IF EXISTS idx_inventory_movements_transfer;
+ALTER TABLE inventory_movements
+    DROP COLUMN IF EXISTS transfer_id,
+    DROP COLUMN IF EXISTS location_id;
+DROP INDEX IF EXISTS idx_inventory_levels_location;
+DROP TABLE IF EXISTS inventory_levels;
+DROP INDEX IF EXISTS idx_inventory_locations_default;
+DROP TABLE IF EXISTS inventory_locations;
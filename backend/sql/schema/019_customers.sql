@@ -0,0 +1,52 @@
+-- +goose Up
+
+-- Shoppers are scoped to a single store and never share identity with platform users
+CREATE TABLE customers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    gid BIGINT UNIQUE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    email TEXT NOT NULL,
+    -- NULL for customers created by staff or checkout who never set a password
+    hashed_password TEXT,
+    first_name TEXT,
+    last_name TEXT,
+    phone TEXT,
+    accepts_marketing BOOLEAN NOT NULL DEFAULT FALSE,
+    status TEXT NOT NULL DEFAULT 'enabled' CHECK (status IN ('enabled', 'disabled')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (store_id, email)
+);
+
+CREATE INDEX IF NOT EXISTS idx_customers_store ON customers(store_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_customers_gid ON customers(gid) WHERE gid IS NOT NULL;
+
+CREATE TABLE customer_refresh_tokens (
+    token TEXT PRIMARY KEY,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO permissions (id, key, description, created_at, updated_at) VALUES
+    (gen_random_uuid(), 'customers:view', 'View customer accounts and their orders', now(), now()),
+    (gen_random_uuid(), 'customers:manage', 'Create, edit, and disable customer accounts', now(), now());
+
+-- Owner roles hold every permission; grant the new ones to existing owners
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT DISTINCT rp.role_id, p.id
+FROM role_permissions rp
+JOIN permissions owner ON owner.id = rp.permission_id AND owner.key = 'tenant:owner'
+CROSS JOIN permissions p
+WHERE p.key IN ('customers:view', 'customers:manage')
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM permissions WHERE key IN ('customers:view', 'customers:manage');
+DROP TABLE IF EXISTS customer_refresh_tokens;
+DROP INDEX IF EXISTS idx_customers_gid;
+DROP INDEX IF EXISTS idx_customers_store;
+DROP TABLE IF EXISTS customers;
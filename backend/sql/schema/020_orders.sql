@@ -0,0 +1,55 @@
+-- +goose Up
+
+CREATE TABLE orders (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    gid BIGINT UNIQUE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    customer_id UUID REFERENCES customers(id) ON DELETE SET NULL,
+    order_number BIGINT NOT NULL,
+    email TEXT,
+    status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed', 'cancelled')),
+    financial_status TEXT NOT NULL DEFAULT 'pending'
+        CHECK (financial_status IN ('pending', 'authorized', 'paid', 'partially_refunded', 'refunded', 'voided')),
+    fulfillment_status TEXT NOT NULL DEFAULT 'unfulfilled'
+        CHECK (fulfillment_status IN ('unfulfilled', 'partial', 'fulfilled')),
+    currency TEXT NOT NULL DEFAULT 'USD',
+    subtotal_cents BIGINT NOT NULL DEFAULT 0 CHECK (subtotal_cents >= 0),
+    shipping_cents BIGINT NOT NULL DEFAULT 0 CHECK (shipping_cents >= 0),
+    tax_cents BIGINT NOT NULL DEFAULT 0 CHECK (tax_cents >= 0),
+    discount_cents BIGINT NOT NULL DEFAULT 0 CHECK (discount_cents >= 0),
+    total_cents BIGINT NOT NULL DEFAULT 0 CHECK (total_cents >= 0),
+    placed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (store_id, order_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_orders_store ON orders(store_id, placed_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_orders_customer ON orders(customer_id, placed_at DESC, id DESC) WHERE customer_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_orders_gid ON orders(gid) WHERE gid IS NOT NULL;
+
+-- Line items snapshot title/sku/price at purchase time so later catalog edits don't rewrite history
+CREATE TABLE order_line_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    product_id UUID REFERENCES products(id) ON DELETE SET NULL,
+    variant_id UUID REFERENCES product_variants(id) ON DELETE SET NULL,
+    title TEXT NOT NULL,
+    variant_title TEXT,
+    sku TEXT,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    price_cents BIGINT NOT NULL CHECK (price_cents >= 0),
+    total_cents BIGINT NOT NULL CHECK (total_cents >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_line_items_order ON order_line_items(order_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_order_line_items_order;
+DROP TABLE IF EXISTS order_line_items;
+DROP INDEX IF EXISTS idx_orders_gid;
+DROP INDEX IF EXISTS idx_orders_customer;
+DROP INDEX IF EXISTS idx_orders_store;
+DROP TABLE IF EXISTS orders;
@@ -0,0 +1,44 @@
+-- +goose Up
+
+CREATE TABLE customer_addresses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    first_name TEXT,
+    last_name TEXT,
+    company TEXT,
+    address1 TEXT NOT NULL,
+    address2 TEXT,
+    city TEXT NOT NULL,
+    -- ISO 3166-2 subdivision without the country prefix (e.g. 'CA' for US-CA)
+    region_code TEXT,
+    postal_code TEXT,
+    -- ISO 3166-1 alpha-2
+    country_code TEXT NOT NULL CHECK (char_length(country_code) = 2),
+    phone TEXT,
+    is_default_shipping BOOLEAN NOT NULL DEFAULT FALSE,
+    is_default_billing BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_addresses_customer ON customer_addresses(customer_id);
+
+-- At most one default shipping and one default billing address per customer
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customer_addresses_default_shipping
+    ON customer_addresses(customer_id) WHERE is_default_shipping;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customer_addresses_default_billing
+    ON customer_addresses(customer_id) WHERE is_default_billing;
+
+-- Orders keep a snapshot of the addresses used at checkout so later address book edits don't rewrite history
+ALTER TABLE orders
+    ADD COLUMN shipping_address JSONB,
+    ADD COLUMN billing_address JSONB;
+
+-- +goose Down
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS billing_address,
+    DROP COLUMN IF EXISTS shipping_address;
+DROP INDEX IF EXISTS idx_customer_addresses_default_billing;
+DROP INDEX IF EXISTS idx_customer_addresses_default_shipping;
+DROP INDEX IF EXISTS idx_customer_addresses_customer;
+DROP TABLE IF EXISTS customer_addresses;
@@ -0,0 +1,43 @@
+-- +goose Up
+
+CREATE TABLE collections (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    gid BIGINT UNIQUE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    handle TEXT NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT,
+    kind TEXT NOT NULL DEFAULT 'manual' CHECK (kind IN ('manual', 'automated')),
+    -- Automated collections only: [{"field":"tag","relation":"equals","value":"summer"}, ...]
+    rules JSONB NOT NULL DEFAULT '[]'::jsonb,
+    -- When true a product needs to match any rule rather than all of them
+    match_any BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (store_id, handle)
+);
+
+CREATE INDEX IF NOT EXISTS idx_collections_store ON collections(store_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_collections_gid ON collections(gid) WHERE gid IS NOT NULL;
+
+-- Membership for both kinds. Automated collections are re-materialized when
+-- their rules change or a product they could match is edited.
+CREATE TABLE collection_products (
+    collection_id UUID NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (collection_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_collection_products_position ON collection_products(collection_id, position, product_id);
+CREATE INDEX IF NOT EXISTS idx_collection_products_product ON collection_products(product_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_collection_products_product;
+DROP INDEX IF EXISTS idx_collection_products_position;
+DROP TABLE IF EXISTS collection_products;
+DROP INDEX IF EXISTS idx_collections_gid;
+DROP INDEX IF EXISTS idx_collections_store;
+DROP TABLE IF EXISTS collections;
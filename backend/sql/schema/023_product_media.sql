@@ -0,0 +1,32 @@
+-- +goose Up
+
+CREATE TABLE product_media (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    gid BIGINT UNIQUE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    -- Optionally pins the image to one variant (e.g. the red colourway)
+    variant_id UUID REFERENCES product_variants(id) ON DELETE SET NULL,
+    storage_key TEXT NOT NULL UNIQUE,
+    content_type TEXT NOT NULL,
+    size_bytes BIGINT,
+    width INTEGER,
+    height INTEGER,
+    alt_text TEXT,
+    position INTEGER NOT NULL,
+    -- pending until the client confirms the presigned upload finished
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'ready')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_media_product ON product_media(product_id, position, id);
+CREATE INDEX IF NOT EXISTS idx_product_media_variant ON product_media(variant_id) WHERE variant_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_product_media_gid ON product_media(gid) WHERE gid IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_product_media_gid;
+DROP INDEX IF EXISTS idx_product_media_variant;
+DROP INDEX IF EXISTS idx_product_media_product;
+DROP TABLE IF EXISTS product_media;
@@ -0,0 +1,8 @@
+-- +goose Up
+
+-- Widths the thumbnail job rendered; each is stored next to the original
+-- under a key derived from storage_key, so only the widths need recording
+ALTER TABLE product_media ADD COLUMN thumbnail_widths INTEGER[] NOT NULL DEFAULT '{}';
+
+-- +goose Down
+ALTER TABLE product_media DROP COLUMN IF EXISTS thumbnail_widths;
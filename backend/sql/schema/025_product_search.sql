@@ -0,0 +1,27 @@
+-- +goose Up
+
+-- Builds the full-text document of a product. Names and SKUs weigh most,
+-- then tags, then the description. SKUs and tags use the simple config so
+-- codes like AB-1234 are not stemmed. The function is IMMUTABLE so it can
+-- back an expression index; queries must call it with the same arguments.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION product_search_vector(name TEXT, description TEXT, tags TEXT, sku TEXT)
+RETURNS tsvector AS $$
+    SELECT setweight(to_tsvector('english'::regconfig, coalesce(name, '')), 'A')
+        || setweight(to_tsvector('simple'::regconfig, coalesce(sku, '')), 'A')
+        || setweight(to_tsvector('simple'::regconfig, replace(coalesce(tags, ''), ',', ' ')), 'B')
+        || setweight(to_tsvector('english'::regconfig, coalesce(description, '')), 'C');
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+-- +goose StatementEnd
+
+CREATE INDEX IF NOT EXISTS idx_products_search
+    ON products USING GIN (product_search_vector(name, description, tags, sku));
+
+-- Variant SKUs are matched exactly (case-insensitively) rather than tokenized
+CREATE INDEX IF NOT EXISTS idx_product_variants_sku_lower
+    ON product_variants (lower(sku)) WHERE sku IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_product_variants_sku_lower;
+DROP INDEX IF EXISTS idx_products_search;
+DROP FUNCTION IF EXISTS product_search_vector(TEXT, TEXT, TEXT, TEXT);
@@ -0,0 +1,62 @@
+-- +goose Up
+
+-- Transactional outbox. Rows are written by triggers in the same transaction
+-- as the change they describe and relayed by the worker to consumers such as
+-- an external search index. Consumers re-read current state, so events only
+-- need to say what changed, not how.
+CREATE TABLE outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    topic TEXT NOT NULL,
+    aggregate_id UUID NOT NULL,
+    store_id UUID NOT NULL,
+    event_type TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    processed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events(topic, id) WHERE processed_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_processed ON outbox_events(processed_at) WHERE processed_at IS NOT NULL;
+
+-- Any product or variant write marks the product for reindexing
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION outbox_product_event()
+RETURNS TRIGGER AS $$
+DECLARE
+    row_data RECORD;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        row_data := OLD;
+    ELSE
+        row_data := NEW;
+    END IF;
+
+    IF TG_TABLE_NAME = 'products' THEN
+        INSERT INTO outbox_events (topic, aggregate_id, store_id, event_type)
+        VALUES ('product', row_data.id, row_data.store_id, 'product.' || lower(TG_OP));
+    ELSE
+        INSERT INTO outbox_events (topic, aggregate_id, store_id, event_type)
+        VALUES ('product', row_data.product_id, row_data.store_id, 'variant.' || lower(TG_OP));
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trigger_outbox_products
+    AFTER INSERT OR UPDATE OR DELETE ON products
+    FOR EACH ROW
+    EXECUTE FUNCTION outbox_product_event();
+
+CREATE TRIGGER trigger_outbox_product_variants
+    AFTER INSERT OR UPDATE OR DELETE ON product_variants
+    FOR EACH ROW
+    EXECUTE FUNCTION outbox_product_event();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trigger_outbox_product_variants ON product_variants;
+DROP TRIGGER IF EXISTS trigger_outbox_products ON products;
+DROP FUNCTION IF EXISTS outbox_product_event();
+DROP INDEX IF EXISTS idx_outbox_events_processed;
+DROP INDEX IF EXISTS idx_outbox_events_pending;
+DROP TABLE IF EXISTS outbox_events;
@@ -0,0 +1,21 @@
+-- +goose Up
+
+-- Normalizes the comma-separated tags column into a lowercase array so
+-- listings can filter with @> and facet counts can unnest it
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION product_tags(tags TEXT)
+RETURNS TEXT[] AS $$
+    SELECT COALESCE(array_agg(DISTINCT trim(tag)) FILTER (WHERE trim(tag) <> ''), '{}')
+    FROM unnest(string_to_array(lower(coalesce(tags, '')), ',')) AS tag;
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+-- +goose StatementEnd
+
+CREATE INDEX IF NOT EXISTS idx_products_tags ON products USING GIN (product_tags(tags));
+CREATE INDEX IF NOT EXISTS idx_products_store_created ON products(store_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_product_variants_product_price ON product_variants(product_id, price_cents) WHERE status = 'active';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_product_variants_product_price;
+DROP INDEX IF EXISTS idx_products_store_created;
+DROP INDEX IF EXISTS idx_products_tags;
+DROP FUNCTION IF EXISTS product_tags(TEXT);
@@ -0,0 +1,63 @@
+-- +goose Up
+
+-- Only a hash of the code is stored; the full code is shown once at issuance
+CREATE TABLE gift_cards (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    gid BIGINT UNIQUE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    customer_id UUID REFERENCES customers(id) ON DELETE SET NULL,
+    code_hash TEXT NOT NULL,
+    last_characters TEXT NOT NULL,
+    currency TEXT NOT NULL DEFAULT 'USD',
+    initial_balance_cents BIGINT NOT NULL CHECK (initial_balance_cents > 0),
+    balance_cents BIGINT NOT NULL CHECK (balance_cents >= 0),
+    status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'disabled')),
+    note TEXT,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (store_id, code_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_gift_cards_store ON gift_cards(store_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_gift_cards_gid ON gift_cards(gid) WHERE gid IS NOT NULL;
+
+-- Every balance change is recorded; amount_cents is negative for redemptions
+CREATE TABLE gift_card_transactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    gift_card_id UUID NOT NULL REFERENCES gift_cards(id) ON DELETE CASCADE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('issue', 'redeem', 'adjust')),
+    amount_cents BIGINT NOT NULL CHECK (amount_cents <> 0),
+    balance_after_cents BIGINT NOT NULL CHECK (balance_after_cents >= 0),
+    order_id UUID REFERENCES orders(id) ON DELETE SET NULL,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    note TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_gift_card_transactions_card ON gift_card_transactions(gift_card_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_gift_card_transactions_order ON gift_card_transactions(order_id) WHERE order_id IS NOT NULL;
+
+INSERT INTO permissions (id, key, description, created_at, updated_at) VALUES
+    (gen_random_uuid(), 'gift_cards:view', 'View gift cards and their balances', now(), now()),
+    (gen_random_uuid(), 'gift_cards:manage', 'Issue, adjust, and disable gift cards', now(), now());
+
+-- Owner roles hold every permission; grant the new ones to existing owners
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT DISTINCT rp.role_id, p.id
+FROM role_permissions rp
+JOIN permissions owner ON owner.id = rp.permission_id AND owner.key = 'tenant:owner'
+CROSS JOIN permissions p
+WHERE p.key IN ('gift_cards:view', 'gift_cards:manage')
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM permissions WHERE key IN ('gift_cards:view', 'gift_cards:manage');
+DROP INDEX IF EXISTS idx_gift_card_transactions_order;
+DROP INDEX IF EXISTS idx_gift_card_transactions_card;
+DROP TABLE IF EXISTS gift_card_transactions;
+DROP INDEX IF EXISTS idx_gift_cards_gid;
+DROP INDEX IF EXISTS idx_gift_cards_store;
+DROP TABLE IF EXISTS gift_cards;
@@ -0,0 +1,47 @@
+-- +goose Up
+
+-- Shipping weight per unit; weight-based rates treat a missing weight as zero
+ALTER TABLE product_variants
+    ADD COLUMN IF NOT EXISTS weight_grams INTEGER CHECK (weight_grams >= 0);
+
+-- Regions are ISO 3166-1 country codes (US) or ISO 3166-2 subdivisions (US-CA)
+CREATE TABLE shipping_zones (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    gid BIGINT UNIQUE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    regions TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (store_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shipping_zones_store ON shipping_zones(store_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_shipping_zones_gid ON shipping_zones(gid) WHERE gid IS NOT NULL;
+
+-- min_value is inclusive and max_value exclusive; both are grams for weight
+-- rates and cents of cart subtotal for price rates, and unused for flat rates
+CREATE TABLE shipping_rates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    zone_id UUID NOT NULL REFERENCES shipping_zones(id) ON DELETE CASCADE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('flat', 'weight', 'price')),
+    price_cents BIGINT NOT NULL CHECK (price_cents >= 0),
+    min_value BIGINT CHECK (min_value >= 0),
+    max_value BIGINT CHECK (max_value > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (min_value IS NULL OR max_value IS NULL OR min_value < max_value)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shipping_rates_zone ON shipping_rates(zone_id, price_cents, id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_shipping_rates_zone;
+DROP TABLE IF EXISTS shipping_rates;
+DROP INDEX IF EXISTS idx_shipping_zones_gid;
+DROP INDEX IF EXISTS idx_shipping_zones_store;
+DROP TABLE IF EXISTS shipping_zones;
+ALTER TABLE product_variants DROP COLUMN IF EXISTS weight_grams;
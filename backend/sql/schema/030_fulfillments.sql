@@ -0,0 +1,39 @@
+-- +goose Up
+
+-- A fulfillment ships some or all of an order's line items. Cancelled
+-- fulfillments release their items back to the order.
+CREATE TABLE fulfillments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    gid BIGINT UNIQUE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'shipped', 'delivered', 'cancelled')),
+    carrier TEXT,
+    tracking_number TEXT,
+    tracking_url TEXT,
+    shipped_at TIMESTAMPTZ,
+    delivered_at TIMESTAMPTZ,
+    cancelled_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_fulfillments_order ON fulfillments(order_id, created_at ASC);
+CREATE INDEX IF NOT EXISTS idx_fulfillments_gid ON fulfillments(gid) WHERE gid IS NOT NULL;
+
+CREATE TABLE fulfillment_line_items (
+    fulfillment_id UUID NOT NULL REFERENCES fulfillments(id) ON DELETE CASCADE,
+    order_line_item_id UUID NOT NULL REFERENCES order_line_items(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (fulfillment_id, order_line_item_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fulfillment_line_items_line ON fulfillment_line_items(order_line_item_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_fulfillment_line_items_line;
+DROP TABLE IF EXISTS fulfillment_line_items;
+DROP INDEX IF EXISTS idx_fulfillments_gid;
+DROP INDEX IF EXISTS idx_fulfillments_order;
+DROP TABLE IF EXISTS fulfillments;
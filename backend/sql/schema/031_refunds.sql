@@ -0,0 +1,54 @@
+-- +goose Up
+
+-- Returned items put back into stock by a refund
+ALTER TABLE inventory_movements DROP CONSTRAINT IF EXISTS inventory_movements_reason_check;
+ALTER TABLE inventory_movements
+    ADD CONSTRAINT inventory_movements_reason_check
+    CHECK (reason IN ('received', 'sold', 'damaged', 'correction', 'transfer', 'returned'));
+
+-- provider and provider_transaction_id tie a refund to the payment
+-- provider record so merchants can reconcile payouts
+CREATE TABLE refunds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    gid BIGINT UNIQUE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    amount_cents BIGINT NOT NULL CHECK (amount_cents >= 0),
+    currency TEXT NOT NULL,
+    reason TEXT,
+    note TEXT,
+    provider TEXT NOT NULL DEFAULT 'manual',
+    provider_transaction_id TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_refunds_order ON refunds(order_id, created_at ASC);
+CREATE INDEX IF NOT EXISTS idx_refunds_gid ON refunds(gid) WHERE gid IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refunds_provider_transaction
+    ON refunds(store_id, provider, provider_transaction_id) WHERE provider_transaction_id IS NOT NULL;
+
+CREATE TABLE refund_line_items (
+    refund_id UUID NOT NULL REFERENCES refunds(id) ON DELETE CASCADE,
+    order_line_item_id UUID NOT NULL REFERENCES order_line_items(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    amount_cents BIGINT NOT NULL CHECK (amount_cents >= 0),
+    restocked BOOLEAN NOT NULL DEFAULT FALSE,
+    location_id UUID REFERENCES inventory_locations(id) ON DELETE SET NULL,
+    PRIMARY KEY (refund_id, order_line_item_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_refund_line_items_line ON refund_line_items(order_line_item_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_refund_line_items_line;
+DROP TABLE IF EXISTS refund_line_items;
+DROP INDEX IF EXISTS idx_refunds_provider_transaction;
+DROP INDEX IF EXISTS idx_refunds_gid;
+DROP INDEX IF EXISTS idx_refunds_order;
+DROP TABLE IF EXISTS refunds;
+DELETE FROM inventory_movements WHERE reason = 'returned';
+ALTER TABLE inventory_movements DROP CONSTRAINT IF EXISTS inventory_movements_reason_check;
+ALTER TABLE inventory_movements
+    ADD CONSTRAINT inventory_movements_reason_check
+    CHECK (reason IN ('received', 'sold', 'damaged', 'correction', 'transfer'));
@@ -0,0 +1,72 @@
+-- +goose Up
+
+-- Merchant-only labels for sorting and filtering orders
+ALTER TABLE orders ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+CREATE INDEX IF NOT EXISTS idx_orders_tags ON orders USING GIN (tags);
+
+-- Order timeline. Placement and status changes are written by a trigger so
+-- they are captured however the order row is changed. Payments, refunds,
+-- fulfillments and staff notes are written by the handlers that make them.
+CREATE TABLE order_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL
+        CHECK (kind IN ('placed', 'status_changed', 'payment', 'refund', 'fulfillment', 'note')),
+    message TEXT NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_events_order ON order_events(order_id, created_at DESC, id DESC);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION order_status_event()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO order_events (order_id, store_id, kind, message, created_at)
+        VALUES (NEW.id, NEW.store_id, 'placed', 'Order placed', NEW.placed_at);
+        RETURN NULL;
+    END IF;
+
+    IF NEW.status IS DISTINCT FROM OLD.status THEN
+        INSERT INTO order_events (order_id, store_id, kind, message, data)
+        VALUES (NEW.id, NEW.store_id, 'status_changed',
+            'Order status changed from ' || OLD.status || ' to ' || NEW.status,
+            jsonb_build_object('field', 'status', 'from', OLD.status, 'to', NEW.status));
+    END IF;
+    IF NEW.financial_status IS DISTINCT FROM OLD.financial_status THEN
+        INSERT INTO order_events (order_id, store_id, kind, message, data)
+        VALUES (NEW.id, NEW.store_id, 'status_changed',
+            'Financial status changed from ' || OLD.financial_status || ' to ' || NEW.financial_status,
+            jsonb_build_object('field', 'financial_status', 'from', OLD.financial_status, 'to', NEW.financial_status));
+    END IF;
+    IF NEW.fulfillment_status IS DISTINCT FROM OLD.fulfillment_status THEN
+        INSERT INTO order_events (order_id, store_id, kind, message, data)
+        VALUES (NEW.id, NEW.store_id, 'status_changed',
+            'Fulfillment status changed from ' || OLD.fulfillment_status || ' to ' || NEW.fulfillment_status,
+            jsonb_build_object('field', 'fulfillment_status', 'from', OLD.fulfillment_status, 'to', NEW.fulfillment_status));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trigger_order_status_events
+    AFTER INSERT OR UPDATE ON orders
+    FOR EACH ROW
+    EXECUTE FUNCTION order_status_event();
+
+-- Existing orders start their timeline when they were placed
+INSERT INTO order_events (order_id, store_id, kind, message, created_at)
+SELECT id, store_id, 'placed', 'Order placed', placed_at FROM orders;
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trigger_order_status_events ON orders;
+DROP FUNCTION IF EXISTS order_status_event();
+DROP INDEX IF EXISTS idx_order_events_order;
+DROP TABLE IF EXISTS order_events;
+DROP INDEX IF EXISTS idx_orders_tags;
+ALTER TABLE orders DROP COLUMN IF EXISTS tags;
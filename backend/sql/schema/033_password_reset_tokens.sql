@@ -0,0 +1,17 @@
+-- +goose Up
+
+-- Reset links carry the token; only its SHA-256 digest is stored
+CREATE TABLE password_reset_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user ON password_reset_tokens(user_id) WHERE used_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_password_reset_tokens_user;
+DROP TABLE IF EXISTS password_reset_tokens;
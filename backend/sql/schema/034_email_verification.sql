@@ -0,0 +1,23 @@
+-- +goose Up
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS verified_at TIMESTAMPTZ;
+
+-- Accounts made before verification existed are trusted as they are
+UPDATE users SET verified_at = created_at WHERE verified_at IS NULL;
+
+-- Like password reset links, only the SHA-256 digest of the token is stored
+CREATE TABLE email_verification_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_verification_tokens_user ON email_verification_tokens(user_id) WHERE used_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_email_verification_tokens_user;
+DROP TABLE IF EXISTS email_verification_tokens;
+ALTER TABLE users DROP COLUMN IF EXISTS verified_at;
@@ -0,0 +1,26 @@
+-- +goose Up
+
+-- Invitations are addressed to an email, which may not have an account yet.
+-- The emailed link carries the token; only its SHA-256 digest is stored.
+CREATE TABLE tenant_invitations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    email TEXT NOT NULL,
+    role_id UUID REFERENCES roles(id) ON DELETE SET NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    accepted_at TIMESTAMPTZ,
+    accepted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- One open invitation per address and tenant
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_invitations_open
+    ON tenant_invitations(tenant_id, email)
+    WHERE accepted_at IS NULL AND revoked_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_tenant_invitations_open;
+DROP TABLE IF EXISTS tenant_invitations;
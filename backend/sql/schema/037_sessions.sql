@@ -0,0 +1,41 @@
+-- +goose Up
+
+-- A session is one sign-in on one device. Its refresh token is rotated on
+-- every use, so a session owns a chain of refresh tokens.
+CREATE TABLE sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip_address TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_used_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_active ON sessions(user_id, last_used_at DESC) WHERE revoked_at IS NULL;
+
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS session_id UUID;
+-- Set when the token is exchanged for a new one. Presenting a rotated token
+-- again means it leaked, and the whole session is revoked.
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS rotated_at TIMESTAMPTZ;
+
+-- Every existing refresh token becomes its own session
+UPDATE refresh_tokens SET session_id = gen_random_uuid() WHERE session_id IS NULL;
+INSERT INTO sessions (id, user_id, created_at, last_used_at, expires_at, revoked_at)
+SELECT session_id, user_id, created_at, updated_at, expires_at, revoked_at
+FROM refresh_tokens;
+
+ALTER TABLE refresh_tokens ALTER COLUMN session_id SET NOT NULL;
+ALTER TABLE refresh_tokens
+    ADD CONSTRAINT refresh_tokens_session_id_fkey
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session ON refresh_tokens(session_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_refresh_tokens_session;
+ALTER TABLE refresh_tokens DROP CONSTRAINT IF EXISTS refresh_tokens_session_id_fkey;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS rotated_at;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS session_id;
+DROP INDEX IF EXISTS idx_sessions_user_active;
+DROP TABLE IF EXISTS sessions;
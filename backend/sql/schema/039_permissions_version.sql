@@ -0,0 +1,81 @@
+-- +goose Up
+-- permissions_version is stamped into access tokens that carry tenant
+-- claims. Any change to a user's memberships, role assignments or the
+-- permissions of one of their roles bumps it, which marks those claims stale.
+ALTER TABLE users ADD COLUMN permissions_version BIGINT NOT NULL DEFAULT 1;
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION bump_permissions_version_tenant_user()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE users SET permissions_version = permissions_version + 1 WHERE id = OLD.user_id;
+    ELSIF TG_OP = 'INSERT' OR NEW.status IS DISTINCT FROM OLD.status THEN
+        UPDATE users SET permissions_version = permissions_version + 1 WHERE id = NEW.user_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION bump_permissions_version_user_role()
+RETURNS TRIGGER AS $$
+DECLARE
+    tu_id uuid;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        tu_id := OLD.tenant_user_id;
+    ELSE
+        tu_id := NEW.tenant_user_id;
+    END IF;
+    UPDATE users SET permissions_version = permissions_version + 1
+    WHERE id = (SELECT user_id FROM tenant_users WHERE id = tu_id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION bump_permissions_version_role_permission()
+RETURNS TRIGGER AS $$
+DECLARE
+    changed_role uuid;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed_role := OLD.role_id;
+    ELSE
+        changed_role := NEW.role_id;
+    END IF;
+    UPDATE users SET permissions_version = permissions_version + 1
+    WHERE id IN (
+        SELECT tu.user_id
+        FROM tenant_user_roles tur
+        JOIN tenant_users tu ON tu.id = tur.tenant_user_id
+        WHERE tur.role_id = changed_role
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER tenant_users_permissions_version
+AFTER INSERT OR UPDATE OF status OR DELETE ON tenant_users
+FOR EACH ROW EXECUTE FUNCTION bump_permissions_version_tenant_user();
+
+CREATE TRIGGER tenant_user_roles_permissions_version
+AFTER INSERT OR DELETE ON tenant_user_roles
+FOR EACH ROW EXECUTE FUNCTION bump_permissions_version_user_role();
+
+CREATE TRIGGER role_permissions_permissions_version
+AFTER INSERT OR DELETE ON role_permissions
+FOR EACH ROW EXECUTE FUNCTION bump_permissions_version_role_permission();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS role_permissions_permissions_version ON role_permissions;
+DROP TRIGGER IF EXISTS tenant_user_roles_permissions_version ON tenant_user_roles;
+DROP TRIGGER IF EXISTS tenant_users_permissions_version ON tenant_users;
+DROP FUNCTION IF EXISTS bump_permissions_version_role_permission();
+DROP FUNCTION IF EXISTS bump_permissions_version_user_role();
+DROP FUNCTION IF EXISTS bump_permissions_version_tenant_user();
+ALTER TABLE users DROP COLUMN IF EXISTS permissions_version;
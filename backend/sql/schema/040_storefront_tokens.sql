@@ -0,0 +1,29 @@
+-- +goose Up
+
+-- Per-store tokens that gate the storefront API. Publishable tokens are
+-- meant to be embedded in web pages; secret tokens stay on the merchant's
+-- servers. Like API keys only the SHA-256 digest is stored. Rotating a
+-- token issues a replacement and lets the old one run out.
+CREATE TABLE storefront_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    gid BIGINT UNIQUE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('publishable', 'secret')),
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL UNIQUE,
+    token_hash TEXT NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rotated_from UUID REFERENCES storefront_tokens(id) ON DELETE SET NULL,
+    last_used_at TIMESTAMPTZ,
+    last_used_ip TEXT,
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_storefront_tokens_store ON storefront_tokens(store_id, created_at DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_storefront_tokens_store;
+DROP TABLE IF EXISTS storefront_tokens;
@@ -0,0 +1,13 @@
+-- +goose Up
+
+-- Certificates and the ACME account key for custom domains, as autocert
+-- caches them. data is AES-GCM sealed with CERT_ENCRYPTION_KEY, so a
+-- database dump alone does not leak private keys.
+CREATE TABLE acme_cache (
+    key TEXT PRIMARY KEY,
+    data BYTEA NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS acme_cache;
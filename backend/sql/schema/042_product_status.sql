@@ -0,0 +1,13 @@
+-- +goose Up
+
+-- Product status was free-form. Anything outside the lifecycle becomes a
+-- draft, hidden from shoppers until a merchant looks at it.
+UPDATE products
+SET status = 'draft', updated_at = now()
+WHERE status NOT IN ('active', 'draft', 'archived');
+
+ALTER TABLE products
+  ADD CONSTRAINT products_status_check CHECK (status IN ('active', 'draft', 'archived'));
+
+-- +goose Down
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_status_check;
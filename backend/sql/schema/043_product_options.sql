@@ -0,0 +1,18 @@
+-- +goose Up
+
+-- A product's options, such as Size and Color, in display order. Variants
+-- name one value of each in their option_values.
+CREATE TABLE product_options (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    choices TEXT[] NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (product_id, name),
+    UNIQUE (product_id, position)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS product_options;
@@ -0,0 +1,38 @@
+-- +goose Up
+
+-- A CSV of products uploaded for the worker to create or update. The file
+-- is kept with the import so the job needs nothing but its ID.
+CREATE TABLE product_imports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    filename TEXT,
+    status TEXT NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    data BYTEA NOT NULL,
+    total_rows INTEGER NOT NULL,
+    error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_product_imports_store ON product_imports (store_id, created_at DESC);
+
+-- The outcome of each row, numbered by the line of the file it starts on.
+-- A rerun of the job skips rows already recorded.
+CREATE TABLE product_import_rows (
+    import_id UUID NOT NULL REFERENCES product_imports(id) ON DELETE CASCADE,
+    row_number INTEGER NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('created', 'updated', 'failed')),
+    handle TEXT,
+    sku TEXT,
+    product_id UUID REFERENCES products(id) ON DELETE SET NULL,
+    errors JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (import_id, row_number)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS product_import_rows;
+DROP TABLE IF EXISTS product_imports;
@@ -0,0 +1,28 @@
+-- +goose Up
+
+-- A file of products or orders written by the worker. filters is the list
+-- endpoint query string the export was made with, and columns the fields
+-- to write, in order.
+CREATE TABLE exports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resource TEXT NOT NULL CHECK (resource IN ('products', 'orders')),
+    format TEXT NOT NULL CHECK (format IN ('csv', 'jsonl')),
+    columns TEXT[] NOT NULL,
+    filters TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    storage_key TEXT,
+    row_count INTEGER,
+    size_bytes BIGINT,
+    error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_exports_store ON exports (store_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS exports;
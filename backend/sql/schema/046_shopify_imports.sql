@@ -0,0 +1,42 @@
+-- +goose Up
+
+-- A Shopify export of products, customers or orders uploaded for the
+-- worker to bring into a store. Like product_imports, the file is kept
+-- with the import so the job needs nothing but its ID.
+CREATE TABLE shopify_imports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    entity TEXT NOT NULL CHECK (entity IN ('products', 'customers', 'orders')),
+    format TEXT NOT NULL CHECK (format IN ('csv', 'json')),
+    filename TEXT,
+    status TEXT NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    data BYTEA NOT NULL,
+    total_items INTEGER NOT NULL,
+    error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_shopify_imports_store ON shopify_imports (store_id, created_at DESC);
+
+-- The outcome of each product, customer or order, numbered by where it
+-- starts in the file. reference is what Shopify calls it, a handle, email
+-- or order name, and target_id the terminus record it became.
+CREATE TABLE shopify_import_items (
+    import_id UUID NOT NULL REFERENCES shopify_imports(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('created', 'updated', 'skipped', 'failed')),
+    reference TEXT,
+    target_id UUID,
+    errors JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (import_id, position)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS shopify_import_items;
+DROP TABLE IF EXISTS shopify_imports;
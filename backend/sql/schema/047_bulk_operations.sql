@@ -0,0 +1,38 @@
+-- +goose Up
+
+-- A batch of product, variant and order changes submitted in one request
+-- and applied by the worker. permissions are what submitting it took, so
+-- only members who could have made the changes can read the results.
+CREATE TABLE bulk_operations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    status TEXT NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    mutations JSONB NOT NULL,
+    permissions TEXT[] NOT NULL,
+    total_mutations INTEGER NOT NULL,
+    error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_bulk_operations_tenant ON bulk_operations (tenant_id, created_at DESC);
+
+-- The outcome of each mutation, numbered by its place in the batch from 0
+CREATE TABLE bulk_operation_results (
+    operation_id UUID NOT NULL REFERENCES bulk_operations(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    op TEXT NOT NULL,
+    store_id UUID NOT NULL,
+    target_id UUID NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('succeeded', 'failed')),
+    errors JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (operation_id, position)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS bulk_operation_results;
+DROP TABLE IF EXISTS bulk_operations;
@@ -0,0 +1,48 @@
+-- +goose Up
+
+-- Who changed what in a tenant. Entries outlive the users, keys and
+-- entities they name, so none of those are foreign keys. before and after
+-- are the entity as the API returned it, with secrets removed, and changes
+-- the top-level fields that differ between them.
+CREATE TABLE audit_log (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    store_id UUID,
+    actor_user_id UUID,
+    actor_api_key_id UUID,
+    action TEXT NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+    entity_type TEXT,
+    entity_id UUID,
+    entity_gid TEXT,
+    method TEXT NOT NULL,
+    route TEXT NOT NULL,
+    path TEXT NOT NULL,
+    status_code INTEGER NOT NULL,
+    request_id TEXT,
+    ip TEXT,
+    before JSONB,
+    after JSONB,
+    changes JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_audit_log_tenant ON audit_log (tenant_id, created_at DESC, id DESC);
+CREATE INDEX idx_audit_log_entity ON audit_log (tenant_id, entity_id) WHERE entity_id IS NOT NULL;
+CREATE INDEX idx_audit_log_actor ON audit_log (tenant_id, actor_user_id);
+CREATE INDEX idx_audit_log_created ON audit_log (created_at);
+
+INSERT INTO permissions (id, key, description, created_at, updated_at) VALUES
+    (gen_random_uuid(), 'audit_log:view', 'View the record of changes made in the tenant', now(), now());
+
+-- Owner roles hold every permission; grant the new one to existing owners
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT DISTINCT rp.role_id, p.id
+FROM role_permissions rp
+JOIN permissions owner ON owner.id = rp.permission_id AND owner.key = 'tenant:owner'
+CROSS JOIN permissions p
+WHERE p.key = 'audit_log:view'
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM permissions WHERE key = 'audit_log:view';
+DROP TABLE IF EXISTS audit_log;
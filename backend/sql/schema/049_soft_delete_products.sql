@@ -0,0 +1,40 @@
+-- +goose Up
+-- Deleted products and variants are kept, hidden, until the worker purges
+-- them after the retention window. They hold on to their handle and SKUs
+-- until then, so restoring one never collides with a newer product.
+ALTER TABLE products ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE product_variants ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX idx_products_deleted ON products (store_id, deleted_at DESC, id DESC) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_product_variants_deleted ON product_variants (product_id, deleted_at DESC, id DESC) WHERE deleted_at IS NOT NULL;
+
+-- Deleting a product deletes its live variants at the same moment;
+-- restoring it brings back exactly those, not variants deleted on their own
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION cascade_product_soft_delete()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.deleted_at IS NOT NULL AND OLD.deleted_at IS NULL THEN
+        UPDATE product_variants SET deleted_at = NEW.deleted_at
+        WHERE product_id = NEW.id AND deleted_at IS NULL;
+    ELSIF NEW.deleted_at IS NULL AND OLD.deleted_at IS NOT NULL THEN
+        UPDATE product_variants SET deleted_at = NULL
+        WHERE product_id = NEW.id AND deleted_at = OLD.deleted_at;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trigger_cascade_product_soft_delete
+    AFTER UPDATE OF deleted_at ON products
+    FOR EACH ROW
+    EXECUTE FUNCTION cascade_product_soft_delete();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trigger_cascade_product_soft_delete ON products;
+DROP FUNCTION IF EXISTS cascade_product_soft_delete();
+DELETE FROM product_variants WHERE deleted_at IS NOT NULL;
+DELETE FROM products WHERE deleted_at IS NOT NULL;
+ALTER TABLE product_variants DROP COLUMN deleted_at;
+ALTER TABLE products DROP COLUMN deleted_at;
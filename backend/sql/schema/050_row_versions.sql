@@ -0,0 +1,35 @@
+-- +goose Up
+-- version counts the writes to a row. Updates made from a read are
+-- conditional on the version read, so two staff members saving the same
+-- product at once can't silently overwrite each other; the second gets a
+-- conflict instead.
+ALTER TABLE products ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE product_variants ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+-- Every update bumps it, whichever query makes it
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION bump_row_version()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.version := OLD.version + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trigger_products_version
+    BEFORE UPDATE ON products
+    FOR EACH ROW
+    EXECUTE FUNCTION bump_row_version();
+
+CREATE TRIGGER trigger_product_variants_version
+    BEFORE UPDATE ON product_variants
+    FOR EACH ROW
+    EXECUTE FUNCTION bump_row_version();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trigger_product_variants_version ON product_variants;
+DROP TRIGGER IF EXISTS trigger_products_version ON products;
+DROP FUNCTION IF EXISTS bump_row_version();
+ALTER TABLE product_variants DROP COLUMN version;
+ALTER TABLE products DROP COLUMN version;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- System roles are the built-in roles every tenant is given when it is
+-- created. They can be cloned into an ordinary role but not changed.
+ALTER TABLE roles ADD COLUMN is_system BOOLEAN NOT NULL DEFAULT false;
+
+-- Until now tenants were only given an Owner role, made the same way
+UPDATE roles SET is_system = true WHERE name = 'Owner';
+
+-- +goose Down
+ALTER TABLE roles DROP COLUMN is_system;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- A suspended member keeps their roles but loses access until reactivated
+ALTER TABLE tenant_users DROP CONSTRAINT tenant_users_status_check;
+ALTER TABLE tenant_users ADD CONSTRAINT tenant_users_status_check
+    CHECK (status IN ('active', 'invited', 'suspended', 'removed'));
+
+-- +goose Down
+UPDATE tenant_users SET status = 'removed' WHERE status = 'suspended';
+ALTER TABLE tenant_users DROP CONSTRAINT tenant_users_status_check;
+ALTER TABLE tenant_users ADD CONSTRAINT tenant_users_status_check
+    CHECK (status IN ('active', 'invited', 'removed'));
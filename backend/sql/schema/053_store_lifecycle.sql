@@ -0,0 +1,17 @@
+-- +goose Up
+-- Only active stores are served to shoppers. Paused and closed stores stay
+-- manageable in the admin API.
+UPDATE stores SET status = 'active' WHERE status NOT IN ('active', 'paused', 'closed');
+ALTER TABLE stores ADD CONSTRAINT stores_status_check
+    CHECK (status IN ('active', 'paused', 'closed'));
+
+-- Deleted stores are hidden everywhere and can be restored until the worker
+-- purges them, with everything in them, after the retention window
+ALTER TABLE stores ADD COLUMN deleted_at TIMESTAMPTZ;
+CREATE INDEX idx_stores_deleted ON stores (tenant_id, deleted_at DESC, id DESC) WHERE deleted_at IS NOT NULL;
+
+-- +goose Down
+DELETE FROM stores WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_stores_deleted;
+ALTER TABLE stores DROP COLUMN deleted_at;
+ALTER TABLE stores DROP CONSTRAINT stores_status_check;
@@ -0,0 +1,8 @@
+-- +goose Up
+-- Per-store settings, such as checkout options and branding. Only the
+-- settings a store has changed are kept; the rest take the defaults in
+-- code, so changing a default reaches every store that never set it.
+ALTER TABLE stores ADD COLUMN settings JSONB NOT NULL DEFAULT '{}';
+
+-- +goose Down
+ALTER TABLE stores DROP COLUMN settings;
@@ -0,0 +1,35 @@
+-- +goose Up
+-- Currencies a store shows prices in besides its default currency
+CREATE TABLE store_currencies (
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    currency TEXT NOT NULL CHECK (currency ~ '^[A-Z]{3}$'),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (store_id, currency)
+);
+
+-- A variant's price in one presentment currency, set by the merchant
+-- instead of converting its base price
+CREATE TABLE variant_prices (
+    variant_id UUID NOT NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    currency TEXT NOT NULL CHECK (currency ~ '^[A-Z]{3}$'),
+    price_cents INTEGER NOT NULL CHECK (price_cents >= 0),
+    compare_at_cents INTEGER CHECK (compare_at_cents IS NULL OR compare_at_cents >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (variant_id, currency)
+);
+
+-- Units of each currency per one unit of base, as of the last sync. Every
+-- row comes from the same sync, so any two give a cross rate.
+CREATE TABLE exchange_rates (
+    currency TEXT PRIMARY KEY CHECK (currency ~ '^[A-Z]{3}$'),
+    base TEXT NOT NULL,
+    rate DOUBLE PRECISION NOT NULL CHECK (rate > 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- +goose Down
+DROP TABLE exchange_rates;
+DROP TABLE variant_prices;
+DROP TABLE store_currencies;
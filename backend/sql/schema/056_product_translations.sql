@@ -0,0 +1,22 @@
+-- +goose Up
+-- The locale a store's own product content is written in
+ALTER TABLE stores ADD COLUMN default_locale TEXT NOT NULL DEFAULT 'en';
+
+-- Product content in another locale. A missing description or handle
+-- falls back to the product itself.
+CREATE TABLE product_translations (
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    locale TEXT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT,
+    handle TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (product_id, locale),
+    CONSTRAINT uq_product_translations_store_locale_handle UNIQUE (store_id, locale, handle)
+);
+
+-- +goose Down
+DROP TABLE product_translations;
+ALTER TABLE stores DROP COLUMN default_locale;
@@ -0,0 +1,67 @@
+-- +goose Up
+-- Customers a merchant prices together, such as wholesale accounts
+CREATE TABLE customer_groups (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    gid BIGINT UNIQUE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (store_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_groups_gid ON customer_groups(gid) WHERE gid IS NOT NULL;
+
+CREATE TABLE customer_group_members (
+    group_id UUID NOT NULL REFERENCES customer_groups(id) ON DELETE CASCADE,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (group_id, customer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_group_members_customer ON customer_group_members(customer_id);
+
+-- Prices the customers of the assigned groups pay instead of the base price
+CREATE TABLE price_lists (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    gid BIGINT UNIQUE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (store_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_lists_gid ON price_lists(gid) WHERE gid IS NOT NULL;
+
+-- A variant on a price list is either a fixed price in the store currency
+-- or a percentage off its base price
+CREATE TABLE price_list_entries (
+    price_list_id UUID NOT NULL REFERENCES price_lists(id) ON DELETE CASCADE,
+    variant_id UUID NOT NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+    price_cents INTEGER CHECK (price_cents IS NULL OR price_cents >= 0),
+    percent_off INTEGER CHECK (percent_off IS NULL OR percent_off BETWEEN 1 AND 100),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (price_list_id, variant_id),
+    CHECK ((price_cents IS NULL) <> (percent_off IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_list_entries_variant ON price_list_entries(variant_id);
+
+CREATE TABLE price_list_groups (
+    price_list_id UUID NOT NULL REFERENCES price_lists(id) ON DELETE CASCADE,
+    group_id UUID NOT NULL REFERENCES customer_groups(id) ON DELETE CASCADE,
+    PRIMARY KEY (price_list_id, group_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_list_groups_group ON price_list_groups(group_id);
+
+-- +goose Down
+DROP TABLE price_list_groups;
+DROP TABLE price_list_entries;
+DROP TABLE price_lists;
+DROP TABLE customer_group_members;
+DROP TABLE customer_groups;
@@ -0,0 +1,26 @@
+-- +goose Up
+-- A variant with components is a bundle: it has no stock of its own and
+-- selling one takes quantity of each component instead
+CREATE TABLE bundle_components (
+    bundle_variant_id UUID NOT NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+    component_variant_id UUID NOT NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (bundle_variant_id, component_variant_id),
+    CHECK (bundle_variant_id <> component_variant_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bundle_components_component ON bundle_components(component_variant_id);
+
+-- Components of a bundle sold on an order are lines of their own under the
+-- bundle line, so they can be picked and fulfilled
+ALTER TABLE order_line_items
+    ADD COLUMN parent_line_item_id UUID REFERENCES order_line_items(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_order_line_items_parent ON order_line_items(parent_line_item_id) WHERE parent_line_item_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_order_line_items_parent;
+ALTER TABLE order_line_items DROP COLUMN parent_line_item_id;
+DROP TABLE bundle_components;
@@ -0,0 +1,79 @@
+-- +goose Up
+-- How often a subscription renews and what it saves on each order
+CREATE TABLE selling_plans (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    gid BIGINT UNIQUE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    interval_unit TEXT NOT NULL CHECK (interval_unit IN ('day', 'week', 'month', 'year')),
+    interval_count INTEGER NOT NULL CHECK (interval_count > 0),
+    percent_off INTEGER NOT NULL DEFAULT 0 CHECK (percent_off BETWEEN 0 AND 100),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (store_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_selling_plans_gid ON selling_plans(gid) WHERE gid IS NOT NULL;
+
+-- Products a plan can be bought on
+CREATE TABLE selling_plan_products (
+    selling_plan_id UUID NOT NULL REFERENCES selling_plans(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    PRIMARY KEY (selling_plan_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_selling_plan_products_product ON selling_plan_products(product_id);
+
+-- A customer's standing order for a variant. The interval and unit price
+-- are copied from the plan when it is taken out, so editing a plan never
+-- changes what existing subscribers pay. Renewal n falls due at
+-- billing_anchor_at plus n intervals.
+CREATE TABLE subscription_contracts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    gid BIGINT UNIQUE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    selling_plan_id UUID REFERENCES selling_plans(id) ON DELETE SET NULL,
+    variant_id UUID REFERENCES product_variants(id) ON DELETE SET NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    price_cents BIGINT NOT NULL CHECK (price_cents >= 0),
+    currency TEXT NOT NULL,
+    interval_unit TEXT NOT NULL CHECK (interval_unit IN ('day', 'week', 'month', 'year')),
+    interval_count INTEGER NOT NULL CHECK (interval_count > 0),
+    payment_provider TEXT NOT NULL,
+    payment_method_ref TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'paused', 'cancelled', 'failed')),
+    billing_anchor_at TIMESTAMPTZ NOT NULL,
+    next_cycle INTEGER NOT NULL DEFAULT 0 CHECK (next_cycle >= 0),
+    next_billing_at TIMESTAMPTZ NOT NULL,
+    failed_attempts INTEGER NOT NULL DEFAULT 0,
+    last_failure TEXT,
+    paused_at TIMESTAMPTZ,
+    cancelled_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_contracts_store ON subscription_contracts(store_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_subscription_contracts_customer ON subscription_contracts(customer_id);
+CREATE INDEX IF NOT EXISTS idx_subscription_contracts_due ON subscription_contracts(next_billing_at) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_subscription_contracts_gid ON subscription_contracts(gid) WHERE gid IS NOT NULL;
+
+-- The order each renewal produced. A cycle is billed at most once.
+CREATE TABLE subscription_orders (
+    contract_id UUID NOT NULL REFERENCES subscription_contracts(id) ON DELETE CASCADE,
+    cycle INTEGER NOT NULL,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (contract_id, cycle)
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_orders_order ON subscription_orders(order_id);
+
+-- +goose Down
+DROP TABLE subscription_orders;
+DROP TABLE subscription_contracts;
+DROP TABLE selling_plan_products;
+DROP TABLE selling_plans;
@@ -0,0 +1,91 @@
+-- +goose Up
+-- Places a store sells through. Every store has exactly one online store,
+-- its own storefront, and may add points of sale and marketplace feeds.
+CREATE TABLE sales_channels (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    gid BIGINT UNIQUE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('online_store', 'pos', 'marketplace_feed')),
+    name TEXT NOT NULL,
+    -- New products are published to the channel as they are created
+    auto_publish BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (store_id, name)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sales_channels_online_store ON sales_channels(store_id) WHERE kind = 'online_store';
+CREATE INDEX IF NOT EXISTS idx_sales_channels_gid ON sales_channels(gid) WHERE gid IS NOT NULL;
+
+-- A product is sold on a channel while it is published there
+CREATE TABLE product_publications (
+    channel_id UUID NOT NULL REFERENCES sales_channels(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    published_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (channel_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_publications_product ON product_publications(product_id);
+
+-- Stores get their online store as they are created, whichever path
+-- creates them
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION create_online_store_channel()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.tenant_id IS NOT NULL THEN
+        INSERT INTO sales_channels (tenant_id, store_id, kind, name, auto_publish)
+        VALUES (NEW.tenant_id, NEW.id, 'online_store', 'Online Store', true)
+        ON CONFLICT DO NOTHING;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trigger_create_online_store_channel
+    AFTER INSERT ON stores
+    FOR EACH ROW
+    EXECUTE FUNCTION create_online_store_channel();
+
+-- Products are published to the auto_publish channels of their store as
+-- they are created, by the API, imports or bulk operations alike
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION auto_publish_product()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO product_publications (channel_id, product_id)
+    SELECT id, NEW.id FROM sales_channels
+    WHERE store_id = NEW.store_id AND auto_publish
+    ON CONFLICT DO NOTHING;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trigger_auto_publish_product
+    AFTER INSERT ON products
+    FOR EACH ROW
+    EXECUTE FUNCTION auto_publish_product();
+
+-- Existing stores keep selling everything they sold before
+INSERT INTO sales_channels (tenant_id, store_id, kind, name, auto_publish)
+SELECT tenant_id, id, 'online_store', 'Online Store', true
+FROM stores
+WHERE tenant_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+INSERT INTO product_publications (channel_id, product_id)
+SELECT sales_channels.id, products.id
+FROM products
+JOIN sales_channels ON sales_channels.store_id = products.store_id AND sales_channels.kind = 'online_store'
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trigger_auto_publish_product ON products;
+DROP FUNCTION IF EXISTS auto_publish_product();
+DROP TRIGGER IF EXISTS trigger_create_online_store_channel ON stores;
+DROP FUNCTION IF EXISTS create_online_store_channel();
+DROP TABLE product_publications;
+DROP TABLE sales_channels;
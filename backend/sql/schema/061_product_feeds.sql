@@ -0,0 +1,36 @@
+-- +goose Up
+-- Product feeds read by marketplaces such as Google Shopping. The worker
+-- regenerates each feed every refresh_minutes from the products published
+-- to its channel. Marketplaces fetch it from a stable URL carrying token,
+-- which redirects to the latest file. The feed only ever holds what the
+-- storefront already shows, so the token is stored as is and can be shown
+-- again.
+CREATE TABLE product_feeds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    gid BIGINT UNIQUE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    channel_id UUID NOT NULL REFERENCES sales_channels(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    format TEXT NOT NULL CHECK (format IN ('google_merchant_xml', 'facebook_csv')),
+    -- Product page address with {handle} standing in for the product handle
+    link_template TEXT NOT NULL,
+    refresh_minutes INTEGER NOT NULL DEFAULT 1440 CHECK (refresh_minutes BETWEEN 60 AND 10080),
+    token TEXT NOT NULL UNIQUE,
+    next_run_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    -- The latest generated file, replaced by a new key every run
+    storage_key TEXT,
+    item_count INTEGER,
+    size_bytes BIGINT,
+    generated_at TIMESTAMPTZ,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (store_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_feeds_due ON product_feeds(next_run_at);
+CREATE INDEX IF NOT EXISTS idx_product_feeds_gid ON product_feeds(gid) WHERE gid IS NOT NULL;
+
+-- +goose Down
+DROP TABLE product_feeds;
@@ -0,0 +1,52 @@
+-- +goose Up
+-- Labels staff put on customers, such as vip or wholesale
+ALTER TABLE customers ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_customers_tags ON customers USING GIN (tags);
+
+-- A manual group has the customers staff put in it. A rule group has the
+-- customers matching every rule it sets, recalculated in the background.
+-- Spend is net of refunds and counts orders in the store currency only.
+ALTER TABLE customer_groups
+    ADD COLUMN kind TEXT NOT NULL DEFAULT 'manual' CHECK (kind IN ('manual', 'rule')),
+    ADD COLUMN min_total_spent_cents BIGINT CHECK (min_total_spent_cents >= 0),
+    ADD COLUMN max_total_spent_cents BIGINT CHECK (max_total_spent_cents >= 0),
+    ADD COLUMN min_order_count INTEGER CHECK (min_order_count >= 0),
+    ADD COLUMN max_order_count INTEGER CHECK (max_order_count >= 0),
+    ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN recalculated_at TIMESTAMPTZ,
+    ADD CONSTRAINT customer_groups_rules_check CHECK (
+        CASE kind
+            WHEN 'manual' THEN min_total_spent_cents IS NULL AND max_total_spent_cents IS NULL
+                AND min_order_count IS NULL AND max_order_count IS NULL AND cardinality(tags) = 0
+            ELSE min_total_spent_cents IS NOT NULL OR max_total_spent_cents IS NOT NULL
+                OR min_order_count IS NOT NULL OR max_order_count IS NOT NULL OR cardinality(tags) > 0
+        END
+    );
+
+CREATE INDEX IF NOT EXISTS idx_customer_groups_recalculated ON customer_groups(recalculated_at ASC NULLS FIRST) WHERE kind = 'rule';
+
+-- Customers can be exported for marketing
+ALTER TABLE exports DROP CONSTRAINT IF EXISTS exports_resource_check;
+ALTER TABLE exports
+    ADD CONSTRAINT exports_resource_check CHECK (resource IN ('products', 'orders', 'customers'));
+
+-- +goose Down
+DELETE FROM exports WHERE resource = 'customers';
+ALTER TABLE exports DROP CONSTRAINT IF EXISTS exports_resource_check;
+ALTER TABLE exports
+    ADD CONSTRAINT exports_resource_check CHECK (resource IN ('products', 'orders'));
+
+DROP INDEX IF EXISTS idx_customer_groups_recalculated;
+ALTER TABLE customer_groups
+    DROP CONSTRAINT customer_groups_rules_check,
+    DROP COLUMN recalculated_at,
+    DROP COLUMN tags,
+    DROP COLUMN max_order_count,
+    DROP COLUMN min_order_count,
+    DROP COLUMN max_total_spent_cents,
+    DROP COLUMN min_total_spent_cents,
+    DROP COLUMN kind;
+
+DROP INDEX IF EXISTS idx_customers_tags;
+ALTER TABLE customers DROP COLUMN tags;
@@ -0,0 +1,46 @@
+-- +goose Up
+-- Reviews customers leave on products. A review is only shown once staff
+-- approve it. verified_purchase records whether the customer had a paid
+-- order with the product when the review was written.
+CREATE TABLE product_reviews (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    gid BIGINT UNIQUE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    customer_id UUID REFERENCES customers(id) ON DELETE SET NULL,
+    author_name TEXT NOT NULL,
+    rating INTEGER NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    title TEXT,
+    body TEXT NOT NULL,
+    verified_purchase BOOLEAN NOT NULL DEFAULT false,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected', 'spam')),
+    moderated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    moderated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (product_id, customer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_reviews_gid ON product_reviews(gid) WHERE gid IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_product_reviews_approved
+    ON product_reviews(product_id, created_at DESC, id DESC) WHERE status = 'approved';
+CREATE INDEX IF NOT EXISTS idx_product_reviews_queue ON product_reviews(store_id, status, created_at DESC, id DESC);
+
+INSERT INTO permissions (id, key, description, created_at, updated_at) VALUES
+    (gen_random_uuid(), 'reviews:moderate', 'Approve, reject, and mark product reviews as spam', now(), now());
+
+-- Owner roles hold every permission; grant the new one to existing owners
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT DISTINCT rp.role_id, p.id
+FROM role_permissions rp
+JOIN permissions owner ON owner.id = rp.permission_id AND owner.key = 'tenant:owner'
+CROSS JOIN permissions p
+WHERE p.key = 'reviews:moderate'
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM permissions WHERE key = 'reviews:moderate';
+DROP INDEX IF EXISTS idx_product_reviews_queue;
+DROP INDEX IF EXISTS idx_product_reviews_approved;
+DROP INDEX IF EXISTS idx_product_reviews_gid;
+DROP TABLE IF EXISTS product_reviews;
@@ -0,0 +1,57 @@
+-- +goose Up
+-- What happens when a shopper wants more of a product than is available.
+-- deny turns them away, allow sells through the shortfall, and backorder
+-- sells it but records the units as backordered.
+ALTER TABLE products
+    ADD COLUMN oversell_policy TEXT NOT NULL DEFAULT 'deny'
+        CHECK (oversell_policy IN ('deny', 'allow', 'backorder'));
+
+-- Units held by active reservations. Available stock is on_hand less
+-- reserved, which an allow or backorder policy never takes below zero.
+ALTER TABLE inventory_items
+    ADD COLUMN reserved INTEGER NOT NULL DEFAULT 0 CHECK (reserved >= 0);
+
+-- Stock held for a checkout until it is committed as sold, released, or
+-- expires. Only active reservations hold stock.
+CREATE TABLE inventory_reservations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    gid BIGINT UNIQUE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    customer_id UUID REFERENCES customers(id) ON DELETE SET NULL,
+    order_id UUID REFERENCES orders(id) ON DELETE SET NULL,
+    status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'committed', 'released', 'expired')),
+    expires_at TIMESTAMPTZ NOT NULL,
+    committed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_reservations_gid ON inventory_reservations(gid) WHERE gid IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_inventory_reservations_store ON inventory_reservations(store_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_inventory_reservations_expiry ON inventory_reservations(expires_at) WHERE status = 'active';
+
+-- held is what the line takes from stock. backordered is the rest of the
+-- quantity under a backorder policy, and allow sells it through unrecorded.
+CREATE TABLE inventory_reservation_items (
+    reservation_id UUID NOT NULL REFERENCES inventory_reservations(id) ON DELETE CASCADE,
+    inventory_item_id UUID NOT NULL REFERENCES inventory_items(id) ON DELETE CASCADE,
+    variant_id UUID NOT NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    held INTEGER NOT NULL CHECK (held >= 0),
+    backordered INTEGER NOT NULL DEFAULT 0 CHECK (backordered >= 0),
+    PRIMARY KEY (reservation_id, inventory_item_id),
+    CHECK (held + backordered <= quantity)
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_reservation_items_item ON inventory_reservation_items(inventory_item_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_inventory_reservation_items_item;
+DROP TABLE IF EXISTS inventory_reservation_items;
+DROP INDEX IF EXISTS idx_inventory_reservations_expiry;
+DROP INDEX IF EXISTS idx_inventory_reservations_store;
+DROP INDEX IF EXISTS idx_inventory_reservations_gid;
+DROP TABLE IF EXISTS inventory_reservations;
+ALTER TABLE inventory_items DROP COLUMN IF EXISTS reserved;
+ALTER TABLE products DROP COLUMN IF EXISTS oversell_policy;
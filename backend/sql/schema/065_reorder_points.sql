@@ -0,0 +1,36 @@
+-- +goose Up
+-- reorder_point is the stock at a location at or below which a variant is
+-- low, and reorder_quantity how many to order when it is. low_stock_since
+-- is set by the worker once it has alerted on the crossing, so each one is
+-- alerted on once.
+ALTER TABLE inventory_levels
+    ADD COLUMN reorder_point INTEGER CHECK (reorder_point >= 0),
+    ADD COLUMN reorder_quantity INTEGER CHECK (reorder_quantity > 0),
+    ADD COLUMN low_stock_since TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_inventory_levels_reorder ON inventory_levels(inventory_item_id)
+    WHERE reorder_point IS NOT NULL OR low_stock_since IS NOT NULL;
+
+-- A level going to or below its reorder point, or back above it
+CREATE TABLE inventory_alerts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    variant_id UUID NOT NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+    location_id UUID NOT NULL REFERENCES inventory_locations(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('low_stock', 'restocked')),
+    on_hand INTEGER NOT NULL,
+    reorder_point INTEGER,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_alerts_store ON inventory_alerts(store_id, created_at DESC, id DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_inventory_alerts_store;
+DROP TABLE IF EXISTS inventory_alerts;
+DROP INDEX IF EXISTS idx_inventory_levels_reorder;
+ALTER TABLE inventory_levels
+    DROP COLUMN IF EXISTS low_stock_since,
+    DROP COLUMN IF EXISTS reorder_quantity,
+    DROP COLUMN IF EXISTS reorder_point;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Point of sale scans resolve a barcode or SKU to a variant, matching the
+-- barcode exactly and the SKU case-insensitively
+CREATE INDEX IF NOT EXISTS idx_product_variants_store_barcode
+    ON product_variants (store_id, barcode) WHERE barcode IS NOT NULL AND deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_product_variants_store_sku_lower
+    ON product_variants (store_id, lower(sku)) WHERE sku IS NOT NULL AND deleted_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_product_variants_store_sku_lower;
+DROP INDEX IF EXISTS idx_product_variants_store_barcode;
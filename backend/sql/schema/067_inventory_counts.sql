@@ -0,0 +1,53 @@
+-- +goose Up
+
+-- Stock corrected by completing a count
+ALTER TABLE inventory_movements DROP CONSTRAINT IF EXISTS inventory_movements_reason_check;
+ALTER TABLE inventory_movements
+    ADD CONSTRAINT inventory_movements_reason_check
+    CHECK (reason IN ('received', 'sold', 'damaged', 'correction', 'transfer', 'returned', 'count'));
+
+-- A count of the stock at one location. Staff record what they count while
+-- it is open, and completing it corrects the stock by the discrepancies.
+CREATE TABLE inventory_counts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    gid BIGINT UNIQUE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    location_id UUID NOT NULL REFERENCES inventory_locations(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'completed', 'cancelled')),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    completed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_counts_gid ON inventory_counts(gid) WHERE gid IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_inventory_counts_store ON inventory_counts(store_id, created_at DESC, id DESC);
+-- A location is counted by one open count at a time
+CREATE UNIQUE INDEX IF NOT EXISTS uq_inventory_counts_open_location ON inventory_counts(location_id) WHERE status = 'open';
+
+-- expected is what the location had when the variant was counted, so stock
+-- sold or received since is kept when the count is applied
+CREATE TABLE inventory_count_lines (
+    count_id UUID NOT NULL REFERENCES inventory_counts(id) ON DELETE CASCADE,
+    variant_id UUID NOT NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+    expected INTEGER NOT NULL,
+    counted INTEGER NOT NULL CHECK (counted >= 0),
+    counted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    counted_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (count_id, variant_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS inventory_count_lines;
+DROP INDEX IF EXISTS uq_inventory_counts_open_location;
+DROP INDEX IF EXISTS idx_inventory_counts_store;
+DROP INDEX IF EXISTS idx_inventory_counts_gid;
+DROP TABLE IF EXISTS inventory_counts;
+DELETE FROM inventory_movements WHERE reason = 'count';
+ALTER TABLE inventory_movements DROP CONSTRAINT IF EXISTS inventory_movements_reason_check;
+ALTER TABLE inventory_movements
+    ADD CONSTRAINT inventory_movements_reason_check
+    CHECK (reason IN ('received', 'sold', 'damaged', 'correction', 'transfer', 'returned'));
@@ -0,0 +1,30 @@
+-- +goose Up
+
+-- A label bought from a shipping label provider for a fulfillment. Its
+-- carrier and tracking number go in the existing columns, and the
+-- provider's tracking webhooks find the fulfillment by them.
+ALTER TABLE fulfillments
+    ADD COLUMN label_provider TEXT,
+    ADD COLUMN label_shipment_ref TEXT,
+    ADD COLUMN label_url TEXT,
+    ADD COLUMN label_cost_cents BIGINT,
+    ADD COLUMN label_currency TEXT,
+    ADD COLUMN label_purchased_at TIMESTAMPTZ,
+    -- The latest status the carrier reported, in the provider's terms
+    ADD COLUMN tracking_status TEXT,
+    ADD COLUMN tracking_updated_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_fulfillments_label_tracking
+    ON fulfillments(label_provider, tracking_number) WHERE label_provider IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_fulfillments_label_tracking;
+ALTER TABLE fulfillments
+    DROP COLUMN IF EXISTS tracking_updated_at,
+    DROP COLUMN IF EXISTS tracking_status,
+    DROP COLUMN IF EXISTS label_purchased_at,
+    DROP COLUMN IF EXISTS label_currency,
+    DROP COLUMN IF EXISTS label_cost_cents,
+    DROP COLUMN IF EXISTS label_url,
+    DROP COLUMN IF EXISTS label_shipment_ref,
+    DROP COLUMN IF EXISTS label_provider;
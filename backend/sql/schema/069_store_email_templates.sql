@@ -0,0 +1,17 @@
+-- +goose Up
+
+-- A store's own wording for a customer order email. Empty parts fall back
+-- to the built-in template.
+CREATE TABLE IF NOT EXISTS store_email_templates (
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    template TEXT NOT NULL,
+    subject TEXT NOT NULL DEFAULT '',
+    body_text TEXT NOT NULL DEFAULT '',
+    body_html TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (store_id, template)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS store_email_templates;
@@ -0,0 +1,99 @@
+-- +goose Up
+-- The channel an order was placed or a checkout started through. Rows
+-- created without one are put on the online store of their store.
+ALTER TABLE orders
+    ADD COLUMN channel_id UUID REFERENCES sales_channels(id) ON DELETE SET NULL;
+ALTER TABLE inventory_reservations
+    ADD COLUMN channel_id UUID REFERENCES sales_channels(id) ON DELETE SET NULL;
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION default_online_store_channel()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.channel_id IS NULL THEN
+        SELECT id INTO NEW.channel_id FROM sales_channels
+        WHERE store_id = NEW.store_id AND kind = 'online_store';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trigger_order_channel
+    BEFORE INSERT ON orders
+    FOR EACH ROW
+    EXECUTE FUNCTION default_online_store_channel();
+
+CREATE TRIGGER trigger_inventory_reservation_channel
+    BEFORE INSERT ON inventory_reservations
+    FOR EACH ROW
+    EXECUTE FUNCTION default_online_store_channel();
+
+UPDATE orders SET channel_id = sales_channels.id
+FROM sales_channels
+WHERE sales_channels.store_id = orders.store_id AND sales_channels.kind = 'online_store';
+
+UPDATE inventory_reservations SET channel_id = sales_channels.id
+FROM sales_channels
+WHERE sales_channels.store_id = inventory_reservations.store_id AND sales_channels.kind = 'online_store';
+
+-- Daily rollups behind the analytics reports, one day per store in the
+-- store timezone. The worker rebuilds each day once it has ended, and
+-- again for a while after so late refunds and cancellations are counted.
+CREATE TABLE analytics_daily_sales (
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    currency TEXT NOT NULL,
+    orders INTEGER NOT NULL DEFAULT 0,
+    units BIGINT NOT NULL DEFAULT 0,
+    subtotal_cents BIGINT NOT NULL DEFAULT 0,
+    discount_cents BIGINT NOT NULL DEFAULT 0,
+    shipping_cents BIGINT NOT NULL DEFAULT 0,
+    tax_cents BIGINT NOT NULL DEFAULT 0,
+    total_cents BIGINT NOT NULL DEFAULT 0,
+    -- Refunds count on the day they were made, not the day of their order
+    refunded_cents BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (store_id, day, currency)
+);
+
+CREATE TABLE analytics_daily_products (
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    currency TEXT NOT NULL,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    units BIGINT NOT NULL DEFAULT 0,
+    orders INTEGER NOT NULL DEFAULT 0,
+    revenue_cents BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (store_id, day, currency, product_id)
+);
+
+-- checkouts are reservations started on the day and converted those of
+-- them committed to an order
+CREATE TABLE analytics_daily_channels (
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    channel_id UUID NOT NULL REFERENCES sales_channels(id) ON DELETE CASCADE,
+    checkouts INTEGER NOT NULL DEFAULT 0,
+    converted INTEGER NOT NULL DEFAULT 0,
+    orders INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (store_id, day, channel_id)
+);
+
+-- How far each store has been rolled up. rolled_at is when a worker last
+-- took the store, so a failing one is not retried straight away.
+CREATE TABLE analytics_rollups (
+    store_id UUID PRIMARY KEY REFERENCES stores(id) ON DELETE CASCADE,
+    rolled_through DATE,
+    rolled_at TIMESTAMPTZ
+);
+
+-- +goose Down
+DROP TABLE analytics_rollups;
+DROP TABLE analytics_daily_channels;
+DROP TABLE analytics_daily_products;
+DROP TABLE analytics_daily_sales;
+DROP TRIGGER IF EXISTS trigger_inventory_reservation_channel ON inventory_reservations;
+DROP TRIGGER IF EXISTS trigger_order_channel ON orders;
+DROP FUNCTION IF EXISTS default_online_store_channel();
+ALTER TABLE inventory_reservations DROP COLUMN channel_id;
+ALTER TABLE orders DROP COLUMN channel_id;
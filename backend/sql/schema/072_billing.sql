@@ -0,0 +1,27 @@
+-- +goose Up
+
+-- A tenant's Stripe customer and the subscription it pays for its plan.
+-- The subscription is kept in step by Stripe webhooks. applied_plan is
+-- the plan the tenant's stores are on, which lapses to free once a
+-- subscription ends or stays unpaid past grace_until.
+CREATE TABLE IF NOT EXISTS tenant_billing (
+    tenant_id UUID PRIMARY KEY REFERENCES tenants(id) ON DELETE CASCADE,
+    stripe_customer_id TEXT NOT NULL UNIQUE,
+    stripe_subscription_id TEXT,
+    plan TEXT NOT NULL DEFAULT 'free',
+    status TEXT NOT NULL DEFAULT 'none',
+    current_period_end TIMESTAMPTZ,
+    cancel_at_period_end BOOLEAN NOT NULL DEFAULT false,
+    grace_until TIMESTAMPTZ,
+    applied_plan TEXT NOT NULL DEFAULT 'free',
+    -- When the last webhook applied was sent, so older ones arriving late
+    -- are ignored
+    synced_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_tenant_billing_grace ON tenant_billing(grace_until) WHERE grace_until IS NOT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS tenant_billing;
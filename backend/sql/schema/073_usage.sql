@@ -0,0 +1,33 @@
+-- +goose Up
+
+-- Metered usage per tenant, one row per metric a day (UTC). API calls are
+-- added up as they are flushed from each API instance. Orders are
+-- recounted and storage bytes keep the peak of the day.
+CREATE TABLE IF NOT EXISTS usage_records (
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    metric TEXT NOT NULL,
+    day DATE NOT NULL,
+    quantity BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (tenant_id, metric, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_usage_records_day ON usage_records(day);
+
+-- Overage billed for a month, one row per metric, so a month is never
+-- billed twice
+CREATE TABLE IF NOT EXISTS usage_overage_reports (
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    month DATE NOT NULL,
+    metric TEXT NOT NULL,
+    used BIGINT NOT NULL,
+    included BIGINT NOT NULL,
+    units BIGINT NOT NULL,
+    stripe_invoice_item_id TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (tenant_id, month, metric)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS usage_overage_reports;
+DROP TABLE IF EXISTS usage_records;
@@ -0,0 +1,34 @@
+-- +goose Up
+
+-- Platform staff run the admin API on admin.* across every tenant.
+-- support may look, admin may also suspend tenants and act as one.
+-- Roles are granted by hand in SQL, never through the API.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS platform_role TEXT
+    CHECK (platform_role IN ('support', 'admin'));
+
+-- Why and when a tenant was suspended. Its status is suspended meanwhile.
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMPTZ;
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS suspension_reason TEXT;
+
+-- What platform staff did to tenants. The email is kept so entries outlive
+-- the staff account.
+CREATE TABLE IF NOT EXISTS platform_audit_log (
+    id UUID PRIMARY KEY,
+    actor_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    actor_email TEXT NOT NULL,
+    action TEXT NOT NULL,
+    tenant_id UUID REFERENCES tenants(id) ON DELETE SET NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    request_id TEXT,
+    ip TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_platform_audit_log_created ON platform_audit_log(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_platform_audit_log_tenant ON platform_audit_log(tenant_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS platform_audit_log;
+ALTER TABLE tenants DROP COLUMN IF EXISTS suspension_reason;
+ALTER TABLE tenants DROP COLUMN IF EXISTS suspended_at;
+ALTER TABLE users DROP COLUMN IF EXISTS platform_role;
@@ -0,0 +1,44 @@
+-- +goose Up
+
+-- A request from a data subject for a copy of their personal data, or for
+-- it to be erased. The subject is either a store customer, asked for by
+-- store staff on their behalf, or a platform user asking for themselves.
+-- Erasure anonymizes the subject in place so orders and the other records
+-- pointing at it stay whole; erased_at marks what was erased.
+CREATE TABLE IF NOT EXISTS privacy_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind TEXT NOT NULL CHECK (kind IN ('export', 'erasure')),
+    store_id UUID REFERENCES stores(id) ON DELETE CASCADE,
+    customer_id UUID REFERENCES customers(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    requested_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    status TEXT NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    storage_key TEXT,
+    size_bytes BIGINT,
+    error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    CHECK ((customer_id IS NULL) <> (user_id IS NULL)),
+    CHECK ((customer_id IS NULL) = (store_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_privacy_requests_customer
+    ON privacy_requests (customer_id, created_at DESC) WHERE customer_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_privacy_requests_user
+    ON privacy_requests (user_id, created_at DESC) WHERE user_id IS NOT NULL;
+
+-- One open request of each kind per subject
+CREATE UNIQUE INDEX IF NOT EXISTS idx_privacy_requests_open_customer
+    ON privacy_requests (customer_id, kind) WHERE status IN ('pending', 'running') AND customer_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_privacy_requests_open_user
+    ON privacy_requests (user_id, kind) WHERE status IN ('pending', 'running') AND user_id IS NOT NULL;
+
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS erased_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS erased_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS erased_at;
+ALTER TABLE customers DROP COLUMN IF EXISTS erased_at;
+DROP TABLE IF EXISTS privacy_requests;
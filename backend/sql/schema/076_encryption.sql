@@ -0,0 +1,39 @@
+-- +goose Up
+
+-- Data keys for encrypting personal data and secrets at rest. Each is kept
+-- wrapped by a key encryption key (KEK) held in KMS or the environment,
+-- named by kek_id, so the database alone can decrypt nothing. A data key
+-- seals values; the index key hashes blind indexes for lookups. One of
+-- each purpose is active; retired data keys stay to open older values
+-- until they are resealed.
+CREATE TABLE IF NOT EXISTS encryption_keys (
+    id TEXT PRIMARY KEY,
+    purpose TEXT NOT NULL CHECK (purpose IN ('data', 'index')),
+    wrapped_key BYTEA NOT NULL,
+    kek_id TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    retired_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_encryption_keys_active
+    ON encryption_keys (purpose) WHERE retired_at IS NULL;
+
+-- Emails are sealed, so they are looked up and kept unique through a
+-- blind index of the lowercased email instead. Rows written before it
+-- existed have an empty one until they are resealed.
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS email_digest TEXT NOT NULL DEFAULT '';
+ALTER TABLE customers DROP CONSTRAINT IF EXISTS customers_store_id_email_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customers_email_digest
+    ON customers (store_id, email_digest) WHERE email_digest <> '';
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS email_digest TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_orders_email_digest
+    ON orders (store_id, email_digest) WHERE email_digest <> '';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_orders_email_digest;
+ALTER TABLE orders DROP COLUMN IF EXISTS email_digest;
+DROP INDEX IF EXISTS idx_customers_email_digest;
+ALTER TABLE customers DROP COLUMN IF EXISTS email_digest;
+ALTER TABLE customers ADD CONSTRAINT customers_store_id_email_key UNIQUE (store_id, email);
+DROP TABLE IF EXISTS encryption_keys;
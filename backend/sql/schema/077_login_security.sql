@@ -0,0 +1,37 @@
+-- +goose Up
+
+-- Failed sign-ins counted per account and per client IP. Keys are blind
+-- indexes, so neither emails nor IPs are stored in the clear. A key is
+-- locked for longer with each failure past its threshold, and forgotten
+-- once it has gone a while without one.
+CREATE TABLE IF NOT EXISTS login_throttles (
+    key TEXT PRIMARY KEY,
+    failures INTEGER NOT NULL DEFAULT 0,
+    locked_until TIMESTAMPTZ,
+    last_failed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_throttles_last_failed ON login_throttles(last_failed_at);
+
+-- Failed sign-ins, lockouts and rejected CAPTCHAs, for platform staff
+-- looking into an attack. The email is the one tried, sealed, so attempts
+-- on accounts that don't exist are kept too.
+CREATE TABLE IF NOT EXISTS security_events (
+    id UUID PRIMARY KEY,
+    kind TEXT NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    customer_id UUID REFERENCES customers(id) ON DELETE SET NULL,
+    store_id UUID REFERENCES stores(id) ON DELETE CASCADE,
+    email TEXT,
+    ip TEXT,
+    user_agent TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_security_events_created ON security_events(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_security_events_user ON security_events(user_id, created_at DESC) WHERE user_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_security_events_customer ON security_events(customer_id) WHERE customer_id IS NOT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS security_events;
+DROP TABLE IF EXISTS login_throttles;
@@ -0,0 +1,39 @@
+-- +goose Up
+
+-- Accounts at Google, GitHub or an OIDC provider that sign in as a user.
+-- subject is the provider's stable ID for the account; the email is the
+-- one the provider gave when it was linked.
+CREATE TABLE IF NOT EXISTS user_identities (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    email TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_login_at TIMESTAMPTZ,
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user ON user_identities(user_id);
+
+-- Sign-ins sent to a provider and not yet back. The state is stored
+-- hashed; the nonce and PKCE verifier are checked against what comes back.
+CREATE TABLE IF NOT EXISTS oauth_states (
+    state_hash TEXT PRIMARY KEY,
+    provider TEXT NOT NULL,
+    nonce TEXT NOT NULL,
+    code_verifier TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_oauth_states_expires ON oauth_states(expires_at);
+
+-- Tenants whose members must sign in through a provider rather than with
+-- a password
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS require_sso BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose Down
+ALTER TABLE tenants DROP COLUMN IF EXISTS require_sso;
+DROP TABLE IF EXISTS oauth_states;
+DROP TABLE IF EXISTS user_identities;
@@ -0,0 +1,52 @@
+-- +goose Up
+
+-- A tenant's own identity provider, over SAML or OpenID Connect. Members
+-- whose email is in one of the tenant's verified domains sign in through
+-- it, and with jit_provisioning on, people new to the tenant are made
+-- members on their first sign-in. attribute_mapping names where the email
+-- and roles are in what the provider sends.
+CREATE TABLE IF NOT EXISTS sso_connections (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL UNIQUE REFERENCES tenants(id) ON DELETE CASCADE,
+    protocol TEXT NOT NULL CHECK (protocol IN ('saml', 'oidc')),
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    saml_metadata TEXT,
+    saml_entity_id TEXT,
+    saml_sso_url TEXT,
+    oidc_issuer TEXT,
+    oidc_client_id TEXT,
+    oidc_client_secret TEXT,
+    attribute_mapping JSONB NOT NULL DEFAULT '{}',
+    default_role_id UUID REFERENCES roles(id) ON DELETE SET NULL,
+    jit_provisioning BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (protocol <> 'saml' OR saml_metadata IS NOT NULL),
+    CHECK (protocol <> 'oidc' OR (oidc_issuer IS NOT NULL AND oidc_client_id IS NOT NULL AND oidc_client_secret IS NOT NULL))
+);
+
+-- Email domains a tenant claims for its identity provider. A domain counts
+-- once the tenant publishes its token in a DNS TXT record, and only one
+-- tenant can hold a domain verified.
+CREATE TABLE IF NOT EXISTS sso_domains (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    domain TEXT NOT NULL,
+    verification_token TEXT NOT NULL,
+    verified_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (tenant_id, domain)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sso_domains_verified ON sso_domains(domain) WHERE verified_at IS NOT NULL;
+
+-- A SAML sign-in comes back to the API rather than the app, which then
+-- hands the app a one-time code to finish it with at the OAuth callback
+ALTER TABLE oauth_states ADD COLUMN IF NOT EXISTS user_id UUID REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE oauth_states ADD COLUMN IF NOT EXISTS code_hash TEXT;
+
+-- +goose Down
+ALTER TABLE oauth_states DROP COLUMN IF EXISTS code_hash;
+ALTER TABLE oauth_states DROP COLUMN IF EXISTS user_id;
+DROP TABLE IF EXISTS sso_domains;
+DROP TABLE IF EXISTS sso_connections;
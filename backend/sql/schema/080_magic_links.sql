@@ -0,0 +1,20 @@
+-- +goose Up
+
+-- Sign-in links carry the token; only its SHA-256 digest is stored. The
+-- device hash is the digest of a second token handed to the browser that
+-- asked for the link, so a link only works where it was requested.
+CREATE TABLE magic_link_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    device_hash TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_magic_link_tokens_user ON magic_link_tokens(user_id) WHERE used_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_magic_link_tokens_user;
+DROP TABLE IF EXISTS magic_link_tokens;
@@ -0,0 +1,8 @@
+-- +goose Up
+-- token_version is stamped into access tokens when JWT_TOKEN_VERSION is on.
+-- Signing a user out everywhere bumps it, which ends the access tokens
+-- already issued along with the refresh tokens.
+ALTER TABLE users ADD COLUMN token_version BIGINT NOT NULL DEFAULT 1;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS token_version;
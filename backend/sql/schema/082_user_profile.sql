@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN display_name TEXT;
+-- avatar_key is the storage key of the user's picture
+ALTER TABLE users ADD COLUMN avatar_key TEXT;
+
+-- A token with new_email changes the account to that address once it is
+-- followed; one without confirms the current address
+ALTER TABLE email_verification_tokens ADD COLUMN new_email TEXT;
+
+-- +goose Down
+ALTER TABLE email_verification_tokens DROP COLUMN IF EXISTS new_email;
+ALTER TABLE users DROP COLUMN IF EXISTS avatar_key;
+ALTER TABLE users DROP COLUMN IF EXISTS display_name;
@@ -0,0 +1,105 @@
+-- +goose Up
+
+-- app_tenant_id is the tenant the transaction is scoped to with
+-- ScopeToTenant, or NULL outside a scoped transaction. set_config leaves
+-- the setting empty rather than unset once the transaction ends, so empty
+-- is NULL too.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION app_tenant_id() RETURNS uuid AS $$
+    SELECT NULLIF(current_setting('app.tenant_id', true), '')::uuid;
+$$ LANGUAGE sql STABLE PARALLEL SAFE;
+-- +goose StatementEnd
+
+-- In a scoped transaction, rows of tenant-owned tables that belong to
+-- another tenant can't be read or written, whatever a query's WHERE clause
+-- says. Tables keyed by store belong to the tenant of the store; rows of
+-- tables keyed by a parent row, such as order_line_items, are reached
+-- through it. Unscoped transactions see every row, as the worker, sign-in
+-- and store resolution work across tenants.
+--
+-- FORCE applies the policies to the table owner, which the API connects
+-- as. Superusers and roles with BYPASSRLS skip them regardless.
+-- +goose StatementBegin
+DO $$
+DECLARE
+    t text;
+BEGIN
+    ALTER TABLE tenants ENABLE ROW LEVEL SECURITY;
+    ALTER TABLE tenants FORCE ROW LEVEL SECURITY;
+    CREATE POLICY tenant_isolation ON tenants
+        USING (app_tenant_id() IS NULL OR id = app_tenant_id());
+
+    FOREACH t IN ARRAY ARRAY[
+        'api_keys', 'audit_log', 'bulk_operations', 'collections',
+        'custom_domains', 'customer_groups', 'customers', 'gift_cards',
+        'idempotency_keys', 'inventory_alerts', 'inventory_counts',
+        'inventory_items', 'inventory_levels', 'inventory_locations',
+        'inventory_movements', 'inventory_reservations', 'notifications',
+        'orders', 'price_lists', 'product_feeds', 'product_media',
+        'product_variants', 'roles', 'sales_channels', 'selling_plans',
+        'shipping_zones', 'shopify_imports', 'sso_connections', 'sso_domains',
+        'stores', 'subscription_contracts', 'tenant_billing',
+        'tenant_invitations', 'tenant_notification_preferences',
+        'tenant_users', 'usage_overage_reports', 'usage_records'
+    ] LOOP
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', t);
+        EXECUTE format('CREATE POLICY tenant_isolation ON %I
+            USING (app_tenant_id() IS NULL OR tenant_id = app_tenant_id())', t);
+    END LOOP;
+
+    FOREACH t IN ARRAY ARRAY[
+        'analytics_daily_channels', 'analytics_daily_products',
+        'analytics_daily_sales', 'analytics_rollups', 'bulk_operation_results',
+        'bundle_components', 'exports', 'fulfillments',
+        'gift_card_transactions', 'order_events', 'outbox_events',
+        'privacy_requests', 'product_imports', 'product_reviews',
+        'product_translations', 'products', 'refunds', 'security_events',
+        'shipping_rates', 'store_currencies', 'store_email_templates',
+        'store_memberships', 'storefront_tokens', 'variant_prices'
+    ] LOOP
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', t);
+        EXECUTE format('CREATE POLICY tenant_isolation ON %I
+            USING (app_tenant_id() IS NULL OR store_id IN (
+                SELECT id FROM stores WHERE tenant_id = app_tenant_id()))', t);
+    END LOOP;
+END $$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DO $$
+DECLARE
+    t text;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'tenants',
+        'api_keys', 'audit_log', 'bulk_operations', 'collections',
+        'custom_domains', 'customer_groups', 'customers', 'gift_cards',
+        'idempotency_keys', 'inventory_alerts', 'inventory_counts',
+        'inventory_items', 'inventory_levels', 'inventory_locations',
+        'inventory_movements', 'inventory_reservations', 'notifications',
+        'orders', 'price_lists', 'product_feeds', 'product_media',
+        'product_variants', 'roles', 'sales_channels', 'selling_plans',
+        'shipping_zones', 'shopify_imports', 'sso_connections', 'sso_domains',
+        'stores', 'subscription_contracts', 'tenant_billing',
+        'tenant_invitations', 'tenant_notification_preferences',
+        'tenant_users', 'usage_overage_reports', 'usage_records',
+        'analytics_daily_channels', 'analytics_daily_products',
+        'analytics_daily_sales', 'analytics_rollups', 'bulk_operation_results',
+        'bundle_components', 'exports', 'fulfillments',
+        'gift_card_transactions', 'order_events', 'outbox_events',
+        'privacy_requests', 'product_imports', 'product_reviews',
+        'product_translations', 'products', 'refunds', 'security_events',
+        'shipping_rates', 'store_currencies', 'store_email_templates',
+        'store_memberships', 'storefront_tokens', 'variant_prices'
+    ] LOOP
+        EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', t);
+        EXECUTE format('ALTER TABLE %I NO FORCE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I DISABLE ROW LEVEL SECURITY', t);
+    END LOOP;
+END $$;
+-- +goose StatementEnd
+
+DROP FUNCTION IF EXISTS app_tenant_id();
@@ -0,0 +1,13 @@
+-- +goose Up
+-- Keys belong to the caller that sent them, a user or an API key, so one
+-- member can't have another's stored response replayed by resending its key
+-- and body
+ALTER TABLE idempotency_keys ADD COLUMN caller TEXT NOT NULL DEFAULT '';
+ALTER TABLE idempotency_keys DROP CONSTRAINT IF EXISTS idempotency_keys_tenant_id_key_method_path_key;
+ALTER TABLE idempotency_keys ADD CONSTRAINT idempotency_keys_caller_key UNIQUE (tenant_id, caller, key, method, path);
+
+-- +goose Down
+ALTER TABLE idempotency_keys DROP CONSTRAINT IF EXISTS idempotency_keys_caller_key;
+DELETE FROM idempotency_keys;
+ALTER TABLE idempotency_keys ADD CONSTRAINT idempotency_keys_tenant_id_key_method_path_key UNIQUE (tenant_id, key, method, path);
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS caller;
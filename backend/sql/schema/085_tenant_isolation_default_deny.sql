@@ -0,0 +1,101 @@
+-- +goose Up
+
+-- app_bypass is whether the session works across tenants. Connections of
+-- the API, the worker and the CLI turn it on at startup for sign-in, store
+-- resolution, the storefront and background jobs; a scope taken with
+-- ScopeToTenant or for a tenant route turns it off again.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION app_bypass() RETURNS boolean AS $$
+    SELECT coalesce(current_setting('app.bypass', true), '') = 'on';
+$$ LANGUAGE sql STABLE PARALLEL SAFE;
+-- +goose StatementEnd
+
+-- Rows are hidden unless the session bypasses the policies or is scoped to
+-- their tenant, so a connection that never said which tenant it works for
+-- sees nothing rather than everything.
+-- +goose StatementBegin
+DO $$
+DECLARE
+    t text;
+BEGIN
+    ALTER POLICY tenant_isolation ON tenants
+        USING (app_bypass() OR id = app_tenant_id());
+
+    FOREACH t IN ARRAY ARRAY[
+        'api_keys', 'audit_log', 'bulk_operations', 'collections',
+        'custom_domains', 'customer_groups', 'customers', 'gift_cards',
+        'idempotency_keys', 'inventory_alerts', 'inventory_counts',
+        'inventory_items', 'inventory_levels', 'inventory_locations',
+        'inventory_movements', 'inventory_reservations', 'notifications',
+        'orders', 'price_lists', 'product_feeds', 'product_media',
+        'product_variants', 'roles', 'sales_channels', 'selling_plans',
+        'shipping_zones', 'shopify_imports', 'sso_connections', 'sso_domains',
+        'stores', 'subscription_contracts', 'tenant_billing',
+        'tenant_invitations', 'tenant_notification_preferences',
+        'tenant_users', 'usage_overage_reports', 'usage_records'
+    ] LOOP
+        EXECUTE format('ALTER POLICY tenant_isolation ON %I
+            USING (app_bypass() OR tenant_id = app_tenant_id())', t);
+    END LOOP;
+
+    FOREACH t IN ARRAY ARRAY[
+        'analytics_daily_channels', 'analytics_daily_products',
+        'analytics_daily_sales', 'analytics_rollups', 'bulk_operation_results',
+        'bundle_components', 'exports', 'fulfillments',
+        'gift_card_transactions', 'order_events', 'outbox_events',
+        'privacy_requests', 'product_imports', 'product_reviews',
+        'product_translations', 'products', 'refunds', 'security_events',
+        'shipping_rates', 'store_currencies', 'store_email_templates',
+        'store_memberships', 'storefront_tokens', 'variant_prices'
+    ] LOOP
+        EXECUTE format('ALTER POLICY tenant_isolation ON %I
+            USING (app_bypass() OR store_id IN (
+                SELECT id FROM stores WHERE tenant_id = app_tenant_id()))', t);
+    END LOOP;
+END $$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DO $$
+DECLARE
+    t text;
+BEGIN
+    ALTER POLICY tenant_isolation ON tenants
+        USING (app_tenant_id() IS NULL OR id = app_tenant_id());
+
+    FOREACH t IN ARRAY ARRAY[
+        'api_keys', 'audit_log', 'bulk_operations', 'collections',
+        'custom_domains', 'customer_groups', 'customers', 'gift_cards',
+        'idempotency_keys', 'inventory_alerts', 'inventory_counts',
+        'inventory_items', 'inventory_levels', 'inventory_locations',
+        'inventory_movements', 'inventory_reservations', 'notifications',
+        'orders', 'price_lists', 'product_feeds', 'product_media',
+        'product_variants', 'roles', 'sales_channels', 'selling_plans',
+        'shipping_zones', 'shopify_imports', 'sso_connections', 'sso_domains',
+        'stores', 'subscription_contracts', 'tenant_billing',
+        'tenant_invitations', 'tenant_notification_preferences',
+        'tenant_users', 'usage_overage_reports', 'usage_records'
+    ] LOOP
+        EXECUTE format('ALTER POLICY tenant_isolation ON %I
+            USING (app_tenant_id() IS NULL OR tenant_id = app_tenant_id())', t);
+    END LOOP;
+
+    FOREACH t IN ARRAY ARRAY[
+        'analytics_daily_channels', 'analytics_daily_products',
+        'analytics_daily_sales', 'analytics_rollups', 'bulk_operation_results',
+        'bundle_components', 'exports', 'fulfillments',
+        'gift_card_transactions', 'order_events', 'outbox_events',
+        'privacy_requests', 'product_imports', 'product_reviews',
+        'product_translations', 'products', 'refunds', 'security_events',
+        'shipping_rates', 'store_currencies', 'store_email_templates',
+        'store_memberships', 'storefront_tokens', 'variant_prices'
+    ] LOOP
+        EXECUTE format('ALTER POLICY tenant_isolation ON %I
+            USING (app_tenant_id() IS NULL OR store_id IN (
+                SELECT id FROM stores WHERE tenant_id = app_tenant_id()))', t);
+    END LOOP;
+END $$;
+-- +goose StatementEnd
+
+DROP FUNCTION IF EXISTS app_bypass();
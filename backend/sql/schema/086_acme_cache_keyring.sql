@@ -0,0 +1,12 @@
+-- +goose Up
+
+-- ACME cache entries are sealed with the data keys of encryption_keys, as
+-- other secrets are, instead of CERT_ENCRYPTION_KEY. Entries sealed under
+-- that key can't be opened any more, so they are dropped, and certificates
+-- are issued again as domains are next served.
+DELETE FROM acme_cache;
+ALTER TABLE acme_cache ALTER COLUMN data TYPE TEXT USING '';
+
+-- +goose Down
+DELETE FROM acme_cache;
+ALTER TABLE acme_cache ALTER COLUMN data TYPE BYTEA USING ''::bytea;
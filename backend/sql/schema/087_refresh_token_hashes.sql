@@ -0,0 +1,19 @@
+-- +goose Up
+
+-- Refresh tokens are kept as their SHA-256, as reset and invitation tokens
+-- are, so a database dump can't be used to sign in. Hashing the existing
+-- ones in place keeps everyone signed in.
+ALTER TABLE refresh_tokens RENAME COLUMN token TO token_hash;
+UPDATE refresh_tokens SET token_hash = encode(sha256(convert_to(token_hash, 'UTF8')), 'hex');
+
+ALTER TABLE customer_refresh_tokens RENAME COLUMN token TO token_hash;
+UPDATE customer_refresh_tokens SET token_hash = encode(sha256(convert_to(token_hash, 'UTF8')), 'hex');
+
+-- +goose Down
+
+-- A hash can't be turned back into its token, so the sessions are ended
+DELETE FROM refresh_tokens;
+ALTER TABLE refresh_tokens RENAME COLUMN token_hash TO token;
+
+DELETE FROM customer_refresh_tokens;
+ALTER TABLE customer_refresh_tokens RENAME COLUMN token_hash TO token;
@@ -0,0 +1,10 @@
+// Package schema holds the database migrations, embedded so the binaries
+// can apply them and tell whether the database has caught up
+package schema
+
+import "embed"
+
+// FS holds the migrations at its root
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/dfodeker/terminus/internal/auth"
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// accessTokenTTL is how long a staff access token stays valid
+const accessTokenTTL = time.Hour
+
+func tenantClaimsFromContext(ctx context.Context) (*auth.TenantClaims, bool) {
+	tc, ok := ctx.Value(tenantClaimsKey).(*auth.TenantClaims)
+	return tc, ok && tc != nil
+}
+
+// makeAccessToken issues a staff access token for userID. With tenant
+// claims enabled the token also lists the user's active tenants, stamped
+// with their current permissions version; with token versions enabled it
+// carries the user's token version.
+func (cfg *apiConfig) makeAccessToken(ctx context.Context, q *database.Queries, userID uuid.UUID) (string, error) {
+	tok := auth.AccessToken{UserID: userID}
+	if cfg.config.TokenVersions {
+		version, err := q.GetUserTokenVersion(ctx, userID)
+		if err != nil {
+			return "", err
+		}
+		tok.Version = version
+	}
+	if !cfg.config.TenantClaims {
+		return auth.SignAccessToken(tok, cfg.jwtKeys, accessTokenTTL)
+	}
+
+	version, err := q.GetUserPermissionsVersion(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	tenantIDs, err := q.ListActiveTenantIDsByUserID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	tok.Tenants = &auth.TenantClaims{
+		TenantIDs:          tenantIDs,
+		PermissionsVersion: version,
+	}
+	return auth.SignAccessToken(tok, cfg.jwtKeys, accessTokenTTL)
+}
+
+// requireClaimedTenant turns away a request for a tenant missing from the
+// token's tenant claims; see checkClaimedTenant
+func (cfg *apiConfig) requireClaimedTenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenantID, err := uuid.Parse(chi.URLParam(r, "tenantID"))
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if err := cfg.checkClaimedTenant(r.Context(), tenantID); err != nil {
+			respondWithAccessError(w, err)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/dbpool"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// TenantContext is the tenant a /tenants/{tenantID} request targets along
+// with the caller's membership and roles there. requireTenantMember loads
+// it once per request.
+type TenantContext struct {
+	Tenant     database.Tenant
+	Membership database.TenantUser
+	Roles      []database.Role
+}
+
+type tenantContextKey struct{}
+
+// tenantContextFrom returns the TenantContext requireTenantMember stored.
+// Like tenantAccessFrom, a missing context is a routing bug.
+func tenantContextFrom(r *http.Request) TenantContext {
+	tc, ok := r.Context().Value(tenantContextKey{}).(TenantContext)
+	if !ok {
+		panic("tenant handler " + r.URL.Path + " is not behind requireTenantMember")
+	}
+	return tc
+}
+
+// requireTenantMember parses the tenant in the URL, checks the caller is an
+// active member of it and stores a TenantContext for what follows, whose
+// queries only see the tenant's rows. See tenantMember for who gets in.
+func (cfg *apiConfig) requireTenantMember(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := userFromContext(r.Context()); !ok {
+			respondWithAccessError(w, errUnauthenticated)
+			return
+		}
+
+		tenantID, err := uuid.Parse(chi.URLParam(r, "tenantID"))
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid tenant ID format", err)
+			return
+		}
+
+		tc, err := cfg.tenantMember(r.Context(), tenantID)
+		if err != nil {
+			respondWithAccessError(w, err)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), tenantContextKey{}, tc)
+		cfg.serveInTenant(w, r.WithContext(ctx), next, tenantID)
+	})
+}
+
+// serveInTenant serves next with its queries scoped to tenantID: they run
+// on a connection that sees only the tenant's rows, so a query missing its
+// tenant filter finds nothing rather than another tenant's data
+func (cfg *apiConfig) serveInTenant(w http.ResponseWriter, r *http.Request, next http.Handler, tenantID uuid.UUID) {
+	ctx, release, err := dbpool.WithTenant(r.Context(), cfg.pool, tenantID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to open a tenant connection", err)
+		return
+	}
+	defer release()
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/dfodeker/terminus/internal/database"
+	"github.com/dfodeker/terminus/internal/service"
+	"github.com/google/uuid"
+)
+
+// withTx runs fn with queries bound to a single transaction, committing
+// only if fn returns nil. Handlers that make several writes go through it
+// so a failure part way leaves nothing half done.
+func (cfg *apiConfig) withTx(ctx context.Context, fn func(q *database.Queries) error) error {
+	return service.SQLTx[*database.Queries](cfg.pool, cfg.db)(ctx, fn)
+}
+
+// withTenantTx is withTx for a tenant's request: only the tenant's rows of
+// tenant-owned tables are visible to fn, so a query that forgets its
+// tenant filter can't touch another tenant's data
+func (cfg *apiConfig) withTenantTx(ctx context.Context, tenantID uuid.UUID, fn func(q *database.Queries) error) error {
+	return service.TenantTx[*database.Queries](cfg.pool, cfg.db, tenantID)(ctx, fn)
+}
+
+// txError ends a withTx function early with a particular error response.
+// The transaction rolls back and respondWithTxError writes the response.
+type txError struct {
+	status int
+	code   string
+	msg    string
+	err    error
+}
+
+func (e *txError) Error() string { return e.msg }
+
+func (e *txError) Unwrap() error { return e.err }
+
+// abortTx is respondWithError for inside a withTx function: it returns the
+// error that rolls the transaction back and is answered with status and
+// msg, logging err if there is one
+func abortTx(status int, msg string, err error) error {
+	return &txError{status: status, msg: msg, err: err}
+}
+
+// abortTxCode is abortTx with a specific problem code
+func abortTxCode(status int, code, msg string, err error) error {
+	return &txError{status: status, code: code, msg: msg, err: err}
+}
+
+// respondWithTxError answers a request whose withTx function failed: with
+// the response an abortTx asked for, as respondWithServiceError would for
+// a service error, or else a 500 with fallback
+func respondWithTxError(w http.ResponseWriter, err error, fallback string) {
+	var txErr *txError
+	if errors.As(err, &txErr) {
+		respondWithErrorCode(w, txErr.status, txErr.code, txErr.msg, txErr.err)
+		return
+	}
+	respondWithServiceError(w, err, fallback)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/dfodeker/terminus/internal/usage"
+)
+
+// meterCaller counts a request made with an API key as an API call of the
+// key's tenant. It goes after rateLimitCaller so refused requests are free.
+func (cfg *apiConfig) meterCaller(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if apiKey, ok := apiKeyFromContext(r.Context()); ok {
+			cfg.meter.Add(apiKey.TenantID, usage.MetricAPICalls, 1)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// meterTenant counts a user request under a tenant as an API call of the
+// tenant. API keys are left to meterCaller, which already counted them, and
+// platform staff impersonating the tenant aren't billed to it.
+func (cfg *apiConfig) meterTenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, isAPIKey := apiKeyFromContext(r.Context())
+		_, impersonating := impersonatedTenantFromContext(r.Context())
+		if !isAPIKey && !impersonating {
+			cfg.meter.Add(tenantContextFrom(r).Tenant.ID, usage.MetricAPICalls, 1)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
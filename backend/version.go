@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Products and variants carry a version that every write bumps. Reads
+// return it in the body and as the ETag; an update may pin itself to it
+// with an If-Match header or a version field, and is refused with a 409
+// version_conflict if the row has moved on.
+
+// setVersionETag sends version as the response's ETag
+func setVersionETag(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+// requestVersion returns the version an update is pinned to, from the
+// body's version field or else the If-Match header, or nil for none. When
+// both are sent they must agree.
+func requestVersion(r *http.Request, body *int64) (*int64, error) {
+	header, err := ifMatchVersion(r)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil && header != nil && *body != *header {
+		return nil, errors.New("The version field and If-Match header disagree")
+	}
+	if body != nil {
+		return body, nil
+	}
+	return header, nil
+}
+
+// ifMatchVersion parses an If-Match header holding one ETag, weak or
+// strong, as set by setVersionETag. "*" matches any version.
+func ifMatchVersion(r *http.Request) (*int64, error) {
+	raw := strings.TrimSpace(r.Header.Get("If-Match"))
+	if raw == "" || raw == "*" {
+		return nil, nil
+	}
+	tag := strings.TrimPrefix(raw, "W/")
+	unquoted, err := strconv.Unquote(tag)
+	if err != nil {
+		return nil, errors.New("If-Match must be a single ETag from a previous response")
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version < 1 {
+		return nil, errors.New("If-Match must be a single ETag from a previous response")
+	}
+	return &version, nil
+}
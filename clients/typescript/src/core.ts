@@ -0,0 +1,207 @@
+// The transport under the generated Client: authentication, retries,
+// idempotency keys, errors and pagination.
+
+/** Where a page sits in a list */
+export interface PageInfo {
+  limit: number;
+  next_cursor?: string;
+  has_more: boolean;
+}
+
+/** One rejected field of a request body */
+export interface FieldError {
+  field: string;
+  code: string;
+  message: string;
+}
+
+/** How often and how long to retry */
+export interface RetryPolicy {
+  /** Counts the first try; 1 never retries */
+  maxAttempts: number;
+  minBackoffMs: number;
+  maxBackoffMs: number;
+}
+
+export const defaultRetryPolicy: RetryPolicy = {
+  maxAttempts: 4,
+  minBackoffMs: 250,
+  maxBackoffMs: 10_000,
+};
+
+export interface ClientOptions {
+  /** Such as https://api.example.com */
+  baseURL: string;
+  /** An access token or API key */
+  token?: string;
+  retry?: RetryPolicy;
+  fetch?: typeof fetch;
+}
+
+export interface RequestOptions {
+  signal?: AbortSignal;
+  /**
+   * Sent as the Idempotency-Key of a write that takes one, in place of a
+   * random key. Reusing the key of an earlier call gets that call's
+   * response instead of repeating it.
+   */
+  idempotencyKey?: string;
+}
+
+/** An API error, from the RFC 7807 problem details in its body */
+export class ApiError extends Error {
+  readonly status: number;
+  readonly code: string;
+  readonly title: string;
+  readonly detail: string;
+  readonly requestId: string;
+  readonly errors: FieldError[];
+
+  constructor(status: number, problem: Record<string, unknown>) {
+    const code = typeof problem.code === "string" ? problem.code : `http_${status}`;
+    const detail = typeof problem.detail === "string" ? problem.detail : "";
+    super(`terminus: ${status} ${code}${detail ? `: ${detail}` : ""}`);
+    this.name = "ApiError";
+    this.status = status;
+    this.code = code;
+    this.title = typeof problem.title === "string" ? problem.title : "";
+    this.detail = detail;
+    this.requestId = typeof problem.request_id === "string" ? problem.request_id : "";
+    this.errors = Array.isArray(problem.errors) ? (problem.errors as FieldError[]) : [];
+  }
+}
+
+/** One call, as the generated methods describe it */
+export interface ApiRequest {
+  method: string;
+  path: string;
+  query?: object;
+  body?: unknown;
+  /** Set for writes that take an Idempotency-Key */
+  idempotent?: boolean;
+}
+
+const retryableStatuses = new Set([502, 503, 504]);
+const safeMethods = new Set(["GET", "HEAD", "PUT", "DELETE"]);
+
+/**
+ * Requests the server can safely see twice are retried: reads, and writes
+ * that take an Idempotency-Key. The client sends a key with those writes
+ * itself, the same one on every attempt.
+ */
+export class BaseClient {
+  private readonly baseURL: string;
+  private readonly token?: string;
+  private readonly retry: RetryPolicy;
+  private readonly fetch: typeof fetch;
+
+  constructor(options: ClientOptions) {
+    this.baseURL = options.baseURL.replace(/\/$/, "");
+    this.token = options.token;
+    this.retry = options.retry ?? defaultRetryPolicy;
+    this.fetch = options.fetch ?? globalThis.fetch.bind(globalThis);
+  }
+
+  protected async request<T>(req: ApiRequest, options: RequestOptions = {}): Promise<T> {
+    let url = this.baseURL + req.path;
+    const query = new URLSearchParams();
+    for (const [key, value] of Object.entries(req.query ?? {})) {
+      if (value !== undefined && value !== null && value !== "") {
+        query.set(key, String(value));
+      }
+    }
+    if ([...query].length > 0) {
+      url += `?${query}`;
+    }
+
+    const headers: Record<string, string> = { Accept: "application/json" };
+    if (req.body !== undefined) {
+      headers["Content-Type"] = "application/json";
+    }
+    if (this.token) {
+      headers.Authorization = `Bearer ${this.token}`;
+    }
+    if (req.idempotent) {
+      headers["Idempotency-Key"] = options.idempotencyKey ?? crypto.randomUUID();
+    }
+    const retryable = req.idempotent || safeMethods.has(req.method);
+    const body = req.body === undefined ? undefined : JSON.stringify(req.body);
+
+    for (let attempt = 1; ; attempt++) {
+      let resp: Response;
+      try {
+        resp = await this.fetch(url, { method: req.method, headers, body, signal: options.signal });
+      } catch (err) {
+        // The request may have reached the server, so only what is safe to
+        // repeat is
+        if (!retryable || attempt >= this.retry.maxAttempts || options.signal?.aborted) {
+          throw err;
+        }
+        await this.wait(attempt, 0, options.signal);
+        continue;
+      }
+
+      if (resp.ok) {
+        const text = await resp.text();
+        return (text ? JSON.parse(text) : undefined) as T;
+      }
+
+      const error = await toApiError(resp);
+      // A rate-limited request was turned away before it was handled, so
+      // any request may be retried
+      const again = resp.status === 429 || (retryable && retryableStatuses.has(resp.status));
+      if (!again || attempt >= this.retry.maxAttempts) {
+        throw error;
+      }
+      await this.wait(attempt, retryAfterMs(resp), options.signal);
+    }
+  }
+
+  /** Yields every item of a list from the page at cursor on */
+  protected async *paginate<T>(
+    cursor: string | undefined,
+    fetchPage: (cursor: string | undefined) => Promise<{ data: T[]; page: PageInfo }>,
+  ): AsyncGenerator<T> {
+    for (;;) {
+      const { data, page } = await fetchPage(cursor);
+      yield* data;
+      if (!page.has_more || !page.next_cursor) {
+        return;
+      }
+      cursor = page.next_cursor;
+    }
+  }
+
+  private wait(attempt: number, afterMs: number, signal?: AbortSignal): Promise<void> {
+    let backoff = Math.min(this.retry.minBackoffMs * 2 ** (attempt - 1), this.retry.maxBackoffMs);
+    // Jitter keeps many clients from retrying in step
+    backoff = backoff / 2 + Math.random() * backoff;
+    const ms = Math.max(backoff, afterMs);
+    return new Promise((resolve, reject) => {
+      const timer = setTimeout(resolve, ms);
+      signal?.addEventListener("abort", () => {
+        clearTimeout(timer);
+        reject(signal.reason);
+      });
+    });
+  }
+}
+
+async function toApiError(resp: Response): Promise<ApiError> {
+  const text = await resp.text();
+  try {
+    const problem = JSON.parse(text);
+    if (problem && typeof problem === "object") {
+      return new ApiError(resp.status, problem);
+    }
+  } catch {
+    // Not problem details
+  }
+  return new ApiError(resp.status, { detail: text.trim() });
+}
+
+/** Reads a Retry-After header given in seconds */
+function retryAfterMs(resp: Response): number {
+  const seconds = Number.parseInt(resp.headers.get("Retry-After") ?? "", 10);
+  return Number.isFinite(seconds) && seconds > 0 ? seconds * 1000 : 0;
+}